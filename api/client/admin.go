@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// AdminListProjects lists all projects of the instance
+func (c *Client) AdminListProjects(
+	ctx context.Context,
+	req *types.AdminListRequest,
+) (*types.AdminListProjectsResponse, error) {
+	resp := &types.AdminListProjectsResponse{}
+
+	err := c.getRequest(
+		"/admin/projects",
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// AdminListUsers lists all users of the instance
+func (c *Client) AdminListUsers(
+	ctx context.Context,
+	req *types.AdminListRequest,
+) (*types.AdminListUsersResponse, error) {
+	resp := &types.AdminListUsersResponse{}
+
+	err := c.getRequest(
+		"/admin/users",
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// AdminUpdateUser updates the instance admin role of a user
+func (c *Client) AdminUpdateUser(
+	ctx context.Context,
+	userID uint,
+	req *types.AdminUpdateUserRequest,
+) (*types.AdminUser, error) {
+	resp := &types.AdminUser{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/admin/users/%d",
+			userID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// AdminGetQuota retrieves the quotas of the instance
+func (c *Client) AdminGetQuota(
+	ctx context.Context,
+) (*types.InstanceQuota, error) {
+	resp := &types.InstanceQuota{}
+
+	err := c.getRequest(
+		"/admin/quotas",
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// AdminUpdateQuota updates the quotas of the instance
+func (c *Client) AdminUpdateQuota(
+	ctx context.Context,
+	req *types.UpdateInstanceQuotaRequest,
+) (*types.InstanceQuota, error) {
+	resp := &types.InstanceQuota{}
+
+	err := c.postRequest(
+		"/admin/quotas",
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// AdminListWhitelistedUsers lists the ids of the users that do not count toward usage limits
+func (c *Client) AdminListWhitelistedUsers(
+	ctx context.Context,
+) (types.AdminListWhitelistedUsersResponse, error) {
+	resp := types.AdminListWhitelistedUsersResponse{}
+
+	err := c.getRequest(
+		"/admin/whitelisted_users",
+		nil,
+		&resp,
+	)
+
+	return resp, err
+}
+
+// AdminWhitelistUser adds a user to the whitelisted users
+func (c *Client) AdminWhitelistUser(
+	ctx context.Context,
+	req *types.AdminWhitelistUserRequest,
+) error {
+	return c.postRequest(
+		"/admin/whitelisted_users",
+		req,
+		nil,
+	)
+}
+
+// AdminRemoveWhitelistedUser removes a user from the whitelisted users
+func (c *Client) AdminRemoveWhitelistedUser(
+	ctx context.Context,
+	userID uint,
+) error {
+	return c.deleteRequest(
+		fmt.Sprintf(
+			"/admin/whitelisted_users/%d",
+			userID,
+		),
+		nil,
+		nil,
+	)
+}
+
+// AdminListAuditLogs lists the audit logs of instance admins
+func (c *Client) AdminListAuditLogs(
+	ctx context.Context,
+	req *types.AdminListRequest,
+) (*types.AdminListAuditLogsResponse, error) {
+	resp := &types.AdminListAuditLogsResponse{}
+
+	err := c.getRequest(
+		"/admin/audit_logs",
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// AdminListSettings lists the settings of the server
+func (c *Client) AdminListSettings(
+	ctx context.Context,
+) (types.ListServerSettingsResponse, error) {
+	resp := types.ListServerSettingsResponse{}
+
+	err := c.getRequest(
+		"/admin/settings",
+		nil,
+		&resp,
+	)
+
+	return resp, err
+}
+
+// AdminUpdateSetting overrides the value of a setting of the server
+func (c *Client) AdminUpdateSetting(
+	ctx context.Context,
+	key types.ServerSettingKey,
+	req *types.UpdateServerSettingRequest,
+) (types.ListServerSettingsResponse, error) {
+	resp := types.ListServerSettingsResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/admin/settings/%s",
+			key,
+		),
+		req,
+		&resp,
+	)
+
+	return resp, err
+}
+
+// AdminResetSetting removes the override of a setting of the server
+func (c *Client) AdminResetSetting(
+	ctx context.Context,
+	key types.ServerSettingKey,
+) (types.ListServerSettingsResponse, error) {
+	resp := types.ListServerSettingsResponse{}
+
+	err := c.deleteRequest(
+		fmt.Sprintf(
+			"/admin/settings/%s",
+			key,
+		),
+		nil,
+		&resp,
+	)
+
+	return resp, err
+}
@@ -3,7 +3,9 @@ package client
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"github.com/gorilla/schema"
+	"github.com/gorilla/websocket"
 	"github.com/porter-dev/porter/api/types"
 	"k8s.io/client-go/util/homedir"
 )
@@ -24,6 +27,10 @@ type Client struct {
 	Cookie         *http.Cookie
 	CookieFilePath string
 	Token          string
+
+	// FreezeOverrideReason is sent with every request if set, to override active deploy
+	// freezes of the project
+	FreezeOverrideReason string
 }
 
 // NewClient constructs a new client based on a set of options
@@ -60,6 +67,42 @@ func NewClientWithToken(baseURL, token string) *Client {
 	return client
 }
 
+// APIError is returned for requests that the server rejected, so that callers can
+// handle well-known errors by their error code
+type APIError struct {
+	ErrorCode types.ErrorCode
+	Message   string
+}
+
+func newAPIError(httpErr *types.ExternalError) error {
+	return &APIError{
+		ErrorCode: httpErr.ErrorCode,
+		Message:   httpErr.Error,
+	}
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// StatusError is returned for error responses without an error body, such as requests
+// to endpoints that do not exist on the server
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unknown error, status code: %d", e.StatusCode)
+}
+
+// isNotFoundError returns true if the endpoint of a request does not exist on the server,
+// which is the case for endpoints that were added after the version of the server
+func isNotFoundError(err error) bool {
+	var statusErr *StatusError
+
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
 func (c *Client) getRequest(relPath string, data interface{}, response interface{}) error {
 	vals := make(map[string][]string)
 	err := schema.NewEncoder().Encode(data, vals)
@@ -88,7 +131,7 @@ func (c *Client) getRequest(relPath string, data interface{}, response interface
 
 	if httpErr, err := c.sendRequest(req, response, true); httpErr != nil || err != nil {
 		if httpErr != nil {
-			return fmt.Errorf("%v", httpErr.Error)
+			return newAPIError(httpErr)
 		}
 
 		return err
@@ -136,6 +179,12 @@ func (c *Client) postRequest(relPath string, data interface{}, response interfac
 			return nil
 		}
 
+		// upgrades are not retried while the pre-deploy command of the release is running,
+		// as they would be rejected until it finishes
+		if httpErr != nil && httpErr.ErrorCode == types.ErrorCodePreDeployRunning {
+			break
+		}
+
 		if i != int(retryCount)-1 {
 			if httpErr != nil {
 				fmt.Printf("Error: %s (status code %d), retrying request...\n", httpErr.Error, httpErr.Code)
@@ -146,7 +195,7 @@ func (c *Client) postRequest(relPath string, data interface{}, response interfac
 	}
 
 	if httpErr != nil {
-		return fmt.Errorf("%v", httpErr.Error)
+		return newAPIError(httpErr)
 	}
 
 	return err
@@ -171,7 +220,7 @@ func (c *Client) deleteRequest(relPath string, data interface{}, response interf
 
 	if httpErr, err := c.sendRequest(req, response, true); httpErr != nil || err != nil {
 		if httpErr != nil {
-			return fmt.Errorf("%v", httpErr.Error)
+			return newAPIError(httpErr)
 		}
 
 		return err
@@ -180,9 +229,91 @@ func (c *Client) deleteRequest(relPath string, data interface{}, response interf
 	return nil
 }
 
+// websocketRequest opens a websocket connection to the endpoint and calls onMessage for
+// every JSON message received, until the server closes the connection or onMessage
+// returns true or an error
+func (c *Client) websocketRequest(
+	relPath string,
+	data interface{},
+	newMessage func() interface{},
+	onMessage func(msg interface{}) (bool, error),
+) error {
+	vals := make(map[string][]string)
+
+	if err := schema.NewEncoder().Encode(data, vals); err != nil {
+		return err
+	}
+
+	baseURL, err := url.Parse(c.BaseURL)
+
+	if err != nil {
+		return err
+	}
+
+	wsURL := *baseURL
+	wsURL.Path = baseURL.Path + relPath
+	wsURL.RawQuery = url.Values(vals).Encode()
+
+	if baseURL.Scheme == "https" {
+		wsURL.Scheme = "wss"
+	} else {
+		wsURL.Scheme = "ws"
+	}
+
+	header := http.Header{}
+
+	// the server only accepts websocket connections that originate from the server URL
+	header.Set("Origin", fmt.Sprintf("%s://%s", baseURL.Scheme, baseURL.Host))
+
+	if c.Token != "" {
+		header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	} else if cookie, _ := c.getCookie(); cookie != nil {
+		header.Set("Cookie", cookie.String())
+	}
+
+	conn, res, err := websocket.DefaultDialer.Dial(wsURL.String(), header)
+
+	if err != nil {
+		if res != nil {
+			defer res.Body.Close()
+
+			var errRes types.ExternalError
+
+			if decodeErr := json.NewDecoder(res.Body).Decode(&errRes); decodeErr == nil {
+				return newAPIError(&errRes)
+			}
+		}
+
+		return err
+	}
+
+	defer conn.Close()
+
+	for {
+		msg := newMessage()
+
+		if err := conn.ReadJSON(msg); err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+
+			return err
+		}
+
+		if done, err := onMessage(msg); err != nil || done {
+			return err
+		}
+	}
+}
+
 func (c *Client) sendRequest(req *http.Request, v interface{}, useCookie bool) (*types.ExternalError, error) {
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
+	req.Header.Set(types.DeploySourceHeader, string(getDeploySource()))
+
+	if c.FreezeOverrideReason != "" {
+		req.Header.Set(types.DeployFreezeOverrideHeader, c.FreezeOverrideReason)
+	}
 
 	if c.Token != "" {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
@@ -209,11 +340,12 @@ func (c *Client) sendRequest(req *http.Request, v interface{}, useCookie bool) (
 			return &errRes, nil
 		}
 
-		return nil, fmt.Errorf("unknown error, status code: %d", res.StatusCode)
+		return nil, &StatusError{StatusCode: res.StatusCode}
 	}
 
+	// responses without a body, such as upgrades that are applied directly, leave v unset
 	if v != nil {
-		if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		if err = json.NewDecoder(res.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
 			return nil, err
 		}
 	}
@@ -302,3 +434,12 @@ func GetProjectIDFromToken(token string) (uint, bool, error) {
 
 	return res.ProjectID, true, nil
 }
+
+// getDeploySource returns the source that is recorded for deploys from this client
+func getDeploySource() types.DeploySource {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return types.DeploySourceGithubAction
+	}
+
+	return types.DeploySourceCLI
+}
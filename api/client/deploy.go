@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/porter-dev/porter/api/types"
 )
@@ -74,6 +75,23 @@ func (c *Client) DeployTemplate(
 	)
 }
 
+func (c *Client) CloneNamespace(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace string,
+	req *types.CloneNamespaceRequest,
+) (*types.CloneNamespaceResponse, error) {
+	resp := &types.CloneNamespaceResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/namespaces/%s/clone", projID, clusterID, namespace),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 func (c *Client) DeployAddon(
 	ctx context.Context,
 	projID, clusterID uint,
@@ -87,6 +105,46 @@ func (c *Client) DeployAddon(
 	)
 }
 
+// AdoptRelease adopts a Helm release that was not deployed by Porter into Porter's management
+func (c *Client) AdoptRelease(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace, name string,
+	req *types.AdoptReleaseRequest,
+) (*types.AdoptReleaseResponse, error) {
+	resp := &types.AdoptReleaseResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/adopt",
+			projID, clusterID,
+			namespace, name,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// AdoptManifests adopts the existing objects of raw manifests into a new release
+func (c *Client) AdoptManifests(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace string,
+	req *types.AdoptManifestsRequest,
+) (*types.AdoptReleaseResponse, error) {
+	resp := &types.AdoptReleaseResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/clusters/%d/namespaces/%s/releases/adopt", projID, clusterID, namespace),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 // UpgradeRelease upgrades a specific release with new values or chart version
 func (c *Client) UpgradeRelease(
 	ctx context.Context,
@@ -94,16 +152,147 @@ func (c *Client) UpgradeRelease(
 	namespace, name string,
 	req *types.UpgradeReleaseRequest,
 ) error {
-	return c.postRequest(
+	resp := &struct {
+		types.ReleaseChangeRequest
+		types.UpgradeReleaseResponse
+	}{}
+
+	err := c.postRequest(
 		fmt.Sprintf(
 			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/upgrade",
 			projID, clusterID,
 			namespace, name,
 		),
 		req,
-		nil,
+		resp,
 		postRequestOpts{
 			retryCount: 3,
 		},
 	)
+
+	if err != nil {
+		return err
+	}
+
+	// upgrades of protected releases are accepted as change requests, and are not
+	// deployed until another user approves them
+	if resp.ID != 0 {
+		return &ChangeRequestedError{ChangeRequest: &resp.ReleaseChangeRequest}
+	}
+
+	// upgrades that change the image of a release with a pre-deploy command are deployed
+	// once the command succeeds
+	if resp.PreDeployRun != nil {
+		return c.waitForPreDeployRun(ctx, projID, clusterID, namespace, name, resp.PreDeployRun)
+	}
+
+	return nil
+}
+
+// UpdatePreDeployCommand sets the command that is run with the new image of a release
+// before each upgrade that changes the image
+func (c *Client) UpdatePreDeployCommand(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace, name string,
+	req *types.UpdatePreDeployCommandRequest,
+) (*types.PorterRelease, error) {
+	resp := &types.PorterRelease{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/pre_deploy_command",
+			projID, clusterID,
+			namespace, name,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetPreDeployRun retrieves the latest run of the pre-deploy command of a release
+func (c *Client) GetPreDeployRun(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace, name string,
+) (*types.PreDeployRun, error) {
+	resp := &types.PreDeployRun{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/pre_deploy_run",
+			projID, clusterID,
+			namespace, name,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// preDeployPollInterval is the interval at which the status of a pre-deploy run is read
+const preDeployPollInterval = 5 * time.Second
+
+// waitForPreDeployRun waits until a run of the pre-deploy command of a release and the
+// upgrade that follows it have finished, and returns an error with the logs of the
+// command if the run failed
+func (c *Client) waitForPreDeployRun(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace, name string,
+	run *types.PreDeployRun,
+) error {
+	for run.Status == types.PreDeployRunRunning {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(preDeployPollInterval):
+		}
+
+		curr, err := c.GetPreDeployRun(ctx, projID, clusterID, namespace, name)
+
+		if err != nil {
+			return err
+		}
+
+		// runs of a release never overlap, so a newer run means that the run finished
+		// without its status being read
+		if curr.ID != run.ID {
+			return fmt.Errorf("could not read the status of pre-deploy run %d", run.ID)
+		}
+
+		run = curr
+	}
+
+	if run.Status != types.PreDeployRunFailed {
+		return nil
+	}
+
+	message := run.Error
+
+	if run.Logs != "" {
+		message = fmt.Sprintf("%s. Logs:\n%s", run.Error, run.Logs)
+	}
+
+	return &APIError{
+		ErrorCode: types.ErrorCodePreDeployFailed,
+		Message:   message,
+	}
+}
+
+// ChangeRequestedError is returned for upgrades of protected releases, which are stored as
+// change requests instead of being deployed
+type ChangeRequestedError struct {
+	ChangeRequest *types.ReleaseChangeRequest
+}
+
+func (e *ChangeRequestedError) Error() string {
+	return fmt.Sprintf(
+		"%s is protected, so the upgrade was stored as change request %d and is deployed once another user approves it",
+		e.ChangeRequest.Name,
+		e.ChangeRequest.ID,
+	)
 }
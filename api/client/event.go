@@ -24,3 +24,105 @@ func (c *Client) CreateEvent(
 		nil,
 	)
 }
+
+// CreateBuildLog uploads the logs of a build of a release
+func (c *Client) CreateBuildLog(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace, name string,
+	req *types.CreateBuildLogRequest,
+) error {
+	return c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/build_logs",
+			projID, clusterID,
+			namespace, name,
+		),
+		req,
+		nil,
+	)
+}
+
+// GetBuildLog retrieves the most recent build log of a release for a commit
+func (c *Client) GetBuildLog(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace, name, commitSHA string,
+) (*types.GetBuildLogResponse, error) {
+	resp := &types.GetBuildLogResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/build_logs/%s",
+			projID, clusterID,
+			namespace, name, commitSHA,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// CreateReleaseSBOM uploads the SBOM of the image that a release was built with
+func (c *Client) CreateReleaseSBOM(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace, name string,
+	req *types.CreateReleaseSBOMRequest,
+) (*types.ImageSBOM, error) {
+	resp := &types.ImageSBOM{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/sbom",
+			projID, clusterID,
+			namespace, name,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// GetReleaseSBOM retrieves the SBOM of the image that a release was most recently built with
+func (c *Client) GetReleaseSBOM(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace, name string,
+) (*types.GetReleaseSBOMResponse, error) {
+	resp := &types.GetReleaseSBOMResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/sbom",
+			projID, clusterID,
+			namespace, name,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+func (c *Client) GetReleaseEvents(
+	ctx context.Context,
+	projID, clusterID uint,
+	namespace, name string,
+) (types.GetReleaseEventsResponse, error) {
+	resp := make(types.GetReleaseEventsResponse, 0)
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/events",
+			projID, clusterID,
+			namespace, name,
+		),
+		nil,
+		&resp,
+	)
+
+	return resp, err
+}
@@ -91,6 +91,72 @@ func (c *Client) GetEnvGroup(
 	return resp, err
 }
 
+// GetEnvGroupsBatch gets the latest versions of several env groups in a single request
+func (c *Client) GetEnvGroupsBatch(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace string,
+	req *types.GetEnvGroupsBatchRequest,
+) (*types.GetEnvGroupsBatchResponse, error) {
+	resp := &types.GetEnvGroupsBatchResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/envgroups/batch",
+			projectID, clusterID,
+			namespace,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// CreateEnvGroup creates a new env group in a namespace
+func (c *Client) CreateEnvGroup(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace string,
+	req *types.CreateEnvGroupRequest,
+) (*types.EnvGroup, error) {
+	resp := &types.EnvGroup{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/envgroup/create",
+			projectID, clusterID,
+			namespace,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// AddEnvGroupApplication adds a release to the applications that an env group is synced to
+func (c *Client) AddEnvGroupApplication(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace string,
+	req *types.AddEnvGroupApplicationRequest,
+) (*types.EnvGroup, error) {
+	resp := &types.EnvGroup{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/envgroup/add_application",
+			projectID, clusterID,
+			namespace,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
 func (c *Client) GetRelease(
 	ctx context.Context,
 	projectID, clusterID uint,
@@ -134,6 +200,149 @@ func (c *Client) GetJobs(
 }
 
 // GetK8sAllPods gets all pods for a given release
+func (c *Client) SearchReleaseLogs(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace, name string,
+	req *types.SearchReleaseLogsRequest,
+) (*types.SearchReleaseLogsResponse, error) {
+	resp := &types.SearchReleaseLogsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/logs/search",
+			projectID, clusterID,
+			namespace, name,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// StreamRolloutStatus streams the rollout status of a release until the rollout has
+// succeeded or failed, calling onStatus for every status update. The last status that
+// was received is returned.
+func (c *Client) StreamRolloutStatus(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace, name string,
+	req *types.StreamRolloutStatusRequest,
+	onStatus func(status *types.RolloutStatus),
+) (*types.RolloutStatus, error) {
+	var last *types.RolloutStatus
+
+	err := c.websocketRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/rollout/status",
+			projectID, clusterID,
+			namespace, name,
+		),
+		req,
+		func() interface{} {
+			return &types.RolloutStatus{}
+		},
+		func(msg interface{}) (bool, error) {
+			last = msg.(*types.RolloutStatus)
+			onStatus(last)
+
+			return last.Phase != types.RolloutProgressing, nil
+		},
+	)
+
+	if err != nil {
+		return last, err
+	}
+
+	if last == nil {
+		return nil, fmt.Errorf("connection closed before the rollout status was received")
+	}
+
+	return last, nil
+}
+
+func (c *Client) GetMaintenanceMode(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace, name string,
+) (*types.MaintenanceModeResponse, error) {
+	resp := &types.MaintenanceModeResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/maintenance",
+			projectID, clusterID,
+			namespace, name,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+func (c *Client) UpdateMaintenanceMode(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace, name string,
+	req *types.UpdateMaintenanceModeRequest,
+) (*types.MaintenanceModeResponse, error) {
+	resp := &types.MaintenanceModeResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/maintenance",
+			projectID, clusterID,
+			namespace, name,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+func (c *Client) PauseRelease(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace, name string,
+) (*types.PorterRelease, error) {
+	resp := &types.PorterRelease{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/pause",
+			projectID, clusterID,
+			namespace, name,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+func (c *Client) ResumeRelease(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace, name string,
+) (*types.PorterRelease, error) {
+	resp := &types.PorterRelease{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases/%s/0/resume",
+			projectID, clusterID,
+			namespace, name,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
 func (c *Client) GetK8sAllPods(
 	ctx context.Context,
 	projectID, clusterID uint,
@@ -153,3 +362,46 @@ func (c *Client) GetK8sAllPods(
 
 	return resp, err
 }
+
+// GetDependencyGraph gets the dependency graph between the releases in a cluster, or in a
+// namespace of the cluster
+func (c *Client) GetDependencyGraph(
+	ctx context.Context,
+	projectID, clusterID uint,
+	req *types.GetDependencyGraphRequest,
+) (*types.DependencyGraph, error) {
+	resp := &types.DependencyGraph{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/dependency_graph",
+			projectID, clusterID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListReleases lists the releases in a namespace of a cluster
+func (c *Client) ListReleases(
+	ctx context.Context,
+	projectID, clusterID uint,
+	namespace string,
+	req *types.ListReleasesRequest,
+) (types.ListReleasesResponse, error) {
+	resp := types.ListReleasesResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/namespaces/%s/releases",
+			projectID, clusterID,
+			namespace,
+		),
+		req,
+		&resp,
+	)
+
+	return resp, err
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// ListPipelines returns the promotion pipelines of a project
+func (c *Client) ListPipelines(
+	ctx context.Context,
+	projectID uint,
+) (*types.ListPipelinesResponse, error) {
+	resp := &types.ListPipelinesResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/pipelines",
+			projectID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// CreatePromotion promotes the release in a stage of a pipeline to the next stage. If
+// the pipeline requires approvals, the promotion is pending until it is approved.
+func (c *Client) CreatePromotion(
+	ctx context.Context,
+	projectID, pipelineID uint,
+	req *types.CreatePromotionRequest,
+) (*types.PipelinePromotion, error) {
+	resp := &types.PipelinePromotion{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/pipelines/%d/promotions",
+			projectID,
+			pipelineID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ApprovePromotion approves a pending promotion as the current user
+func (c *Client) ApprovePromotion(
+	ctx context.Context,
+	projectID, pipelineID, promotionID uint,
+) (*types.PipelinePromotion, error) {
+	resp := &types.PipelinePromotion{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/pipelines/%d/promotions/%d/approve",
+			projectID,
+			pipelineID,
+			promotionID,
+		),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
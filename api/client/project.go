@@ -177,3 +177,92 @@ func (c *Client) DeleteProject(
 		nil,
 	)
 }
+
+// GetProjectDefaultValues retrieves the default values of a project. Servers without
+// default values return no values.
+func (c *Client) GetProjectDefaultValues(
+	ctx context.Context,
+	projectID uint,
+) (*types.DefaultValuesResponse, error) {
+	resp := &types.DefaultValuesResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/default_values",
+			projectID,
+		),
+		nil,
+		resp,
+	)
+
+	if isNotFoundError(err) {
+		return &types.DefaultValuesResponse{}, nil
+	}
+
+	return resp, err
+}
+
+// GetClusterDefaultValues retrieves the default values of a project's cluster. Servers
+// without default values return no values.
+func (c *Client) GetClusterDefaultValues(
+	ctx context.Context,
+	projectID uint,
+	clusterID uint,
+) (*types.DefaultValuesResponse, error) {
+	resp := &types.DefaultValuesResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/clusters/%d/default_values",
+			projectID, clusterID,
+		),
+		nil,
+		resp,
+	)
+
+	if isNotFoundError(err) {
+		return &types.DefaultValuesResponse{}, nil
+	}
+
+	return resp, err
+}
+
+// UpdateProjectCLIVersion pins the CLI version of a project
+func (c *Client) UpdateProjectCLIVersion(
+	ctx context.Context,
+	projectID uint,
+	req *types.UpdateProjectCLIVersionRequest,
+) (*types.Project, error) {
+	resp := &types.Project{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/projects/%d/cli_version",
+			projectID,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListSBOMPackages lists the releases of a project whose images contain a package
+func (c *Client) ListSBOMPackages(
+	ctx context.Context,
+	projectID uint,
+	req *types.ListSBOMPackagesRequest,
+) (types.ListSBOMPackagesResponse, error) {
+	resp := make(types.ListSBOMPackagesResponse, 0)
+
+	err := c.getRequest(
+		fmt.Sprintf(
+			"/projects/%d/sbom_packages",
+			projectID,
+		),
+		req,
+		&resp,
+	)
+
+	return resp, err
+}
@@ -42,3 +42,69 @@ func (c *Client) GetTemplate(
 
 	return resp, err
 }
+
+// RenderTemplateForm validates form data for the form of a template and converts it to
+// values, in the same way as the dashboard
+func (c *Client) RenderTemplateForm(
+	ctx context.Context,
+	name, version string,
+	req *types.RenderTemplateFormRequest,
+) (*types.RenderTemplateFormResponse, error) {
+	resp := &types.RenderTemplateFormResponse{}
+
+	err := c.postRequest(
+		fmt.Sprintf(
+			"/templates/%s/%s/form/render",
+			name, version,
+		),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// ListCustomCharts lists the versions of the custom charts of a project
+func (c *Client) ListCustomCharts(
+	ctx context.Context,
+	projectID uint,
+) (*types.ListCustomChartsResponse, error) {
+	resp := &types.ListCustomChartsResponse{}
+
+	err := c.getRequest(
+		fmt.Sprintf("/projects/%d/custom_charts", projectID),
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
+// CreateCustomChart adds a version of a custom chart to a project
+func (c *Client) CreateCustomChart(
+	ctx context.Context,
+	projectID uint,
+	req *types.CreateCustomChartRequest,
+) (*types.CustomChart, error) {
+	resp := &types.CustomChart{}
+
+	err := c.postRequest(
+		fmt.Sprintf("/projects/%d/custom_charts", projectID),
+		req,
+		resp,
+	)
+
+	return resp, err
+}
+
+// DeleteCustomChart deletes a version of a custom chart
+func (c *Client) DeleteCustomChart(
+	ctx context.Context,
+	projectID, customChartID uint,
+) error {
+	return c.deleteRequest(
+		fmt.Sprintf("/projects/%d/custom_charts/%d", projectID, customChartID),
+		nil,
+		nil,
+	)
+}
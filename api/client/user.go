@@ -22,6 +22,19 @@ func (c *Client) AuthCheck(ctx context.Context) (*types.GetAuthenticatedUserResp
 	return resp, err
 }
 
+// GetMetadata retrieves the versions of the server and of the CLIs that it supports
+func (c *Client) GetMetadata(ctx context.Context) (*types.ServerMetadata, error) {
+	resp := &types.ServerMetadata{}
+
+	err := c.getRequest(
+		"/metadata",
+		nil,
+		resp,
+	)
+
+	return resp, err
+}
+
 // Login authorizes the user and grants them a cookie-based session
 func (c *Client) Login(ctx context.Context, req *types.LoginUserRequest) (*types.GetAuthenticatedUserResponse, error) {
 	resp := &types.GetAuthenticatedUserResponse{}
@@ -11,6 +11,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/auth/token"
+	"github.com/porter-dev/porter/internal/models"
 )
 
 // AuthNFactory generates a middleware handler `AuthN`
@@ -93,9 +94,40 @@ func (authn *AuthN) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// requests that instance admins make while impersonating a user are recorded, and are
+	// not performed if they cannot be recorded
+	if adminID, ok := session.Values["impersonator_id"].(uint); ok && adminID != 0 {
+		if err := authn.auditImpersonatedRequest(r, adminID, userID); err != nil {
+			apierrors.HandleAPIError(authn.config, w, r, apierrors.NewErrInternal(err), true)
+			return
+		}
+	}
+
 	authn.nextWithUserID(w, r, userID)
 }
 
+// auditImpersonatedRequest creates an audit log for a request that an instance admin
+// makes while impersonating a user. Requests that only read data are not recorded.
+func (authn *AuthN) auditImpersonatedRequest(r *http.Request, adminID, userID uint) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	_, err := authn.config.Repo.InstanceAdmin().CreateAuditLog(&models.AdminAuditLog{
+		AdminUserID:  adminID,
+		Action:       types.AdminAuditActionImpersonatedRequest,
+		TargetUserID: userID,
+		Details:      fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+	})
+
+	if err != nil {
+		return fmt.Errorf("could not create audit log: %w", err)
+	}
+
+	return nil
+}
+
 func (authn *AuthN) handleForbiddenForSession(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -126,9 +158,23 @@ func (authn *AuthN) nextWithToken(w http.ResponseWriter, r *http.Request, tok *t
 	// TODO: add section to get service account for server-side token
 
 	// for now, we just use nextWithUser using the `iby` field for the token
+	r = r.WithContext(context.WithValue(r.Context(), tokenContextKey, tok))
+
 	authn.nextWithUserID(w, r, tok.IBy)
 }
 
+type contextKey string
+
+const tokenContextKey contextKey = "token"
+
+// GetTokenFromContext returns the token that a request was authenticated with, or nil
+// if the request was authenticated with a session cookie
+func GetTokenFromContext(ctx context.Context) *token.Token {
+	tok, _ := ctx.Value(tokenContextKey).(*token.Token)
+
+	return tok
+}
+
 // nextWithUserID calls the next handler with the user set in the context with key
 // `types.UserScope`.
 func (authn *AuthN) nextWithUserID(w http.ResponseWriter, r *http.Request, userID uint) {
@@ -151,7 +197,7 @@ func (authn *AuthN) nextWithUserID(w http.ResponseWriter, r *http.Request, userI
 // sendForbiddenError sends a 403 Forbidden error to the end user while logging a
 // specific error
 func (authn *AuthN) sendForbiddenError(err error, w http.ResponseWriter, r *http.Request) {
-	reqErr := apierrors.NewErrForbidden(err)
+	reqErr := apierrors.WithCode(apierrors.NewErrForbidden(err), types.ErrorCodeNotLoggedIn)
 
 	apierrors.HandleAPIError(authn.config, w, r, reqErr, true)
 }
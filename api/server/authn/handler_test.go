@@ -198,6 +198,81 @@ func TestAuthBadSessionUserIDType(t *testing.T) {
 	assertForbiddenError(t, next, rr)
 }
 
+func TestImpersonatedRequestsAudited(t *testing.T) {
+	config, handler, next := loadHandlers(t)
+
+	user := apitest.CreateTestUser(t, config, true)
+
+	admin, err := config.Repo.User().CreateUser(&models.User{
+		Email:         "admin@test.it",
+		EmailVerified: true,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// create a session that the admin impersonates the user with
+	rr := httptest.NewRecorder()
+	loginReq, err := http.NewRequest("POST", "/api/admin/users/1/impersonate", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := authn.SaveUserImpersonated(rr, loginReq, config, admin, user); err != nil {
+		t.Fatal(err)
+	}
+
+	cookie := rr.Result().Cookies()[0]
+
+	// requests that only read data are not recorded
+	req, err := http.NewRequest("GET", "/api/projects", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.AddCookie(cookie)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assertNextHandlerCalled(t, next, rr, user)
+
+	logs, _, err := config.Repo.InstanceAdmin().ListAuditLogs(&types.AdminListRequest{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, logs, 0, "read requests should not be audited")
+
+	req, err = http.NewRequest("DELETE", "/api/projects/1", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.AddCookie(cookie)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assertNextHandlerCalled(t, next, rr, user)
+
+	logs, _, err = config.Repo.InstanceAdmin().ListAuditLogs(&types.AdminListRequest{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, logs, 1, "write requests should be audited") {
+		assert.Equal(t, admin.ID, logs[0].AdminUserID)
+		assert.Equal(t, user.ID, logs[0].TargetUserID)
+		assert.Equal(t, types.AdminAuditActionImpersonatedRequest, logs[0].Action)
+		assert.Equal(t, "DELETE /api/projects/1", logs[0].Details)
+	}
+}
+
 type testHandler struct {
 	WasCalled bool
 	User      *models.User
@@ -239,7 +314,10 @@ func assertForbiddenError(t *testing.T, next *testHandler, rr *httptest.Response
 	// first assert that that the next middleware was not called
 	assert.False(next.WasCalled, "next handler should not have been called")
 
-	apitest.AssertResponseForbidden(t, rr)
+	apitest.AssertResponseError(t, rr, http.StatusForbidden, &types.ExternalError{
+		Error:     "Forbidden",
+		ErrorCode: types.ErrorCodeNotLoggedIn,
+	})
 }
 
 func assertNextHandlerCalled(
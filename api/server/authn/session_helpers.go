@@ -49,5 +49,65 @@ func SaveUserUnauthenticated(
 	session.Values["authenticated"] = false
 	session.Values["user_id"] = nil
 	session.Values["email"] = nil
+	delete(session.Values, "impersonator_id")
+	return session.Save(r, w)
+}
+
+// SaveUserImpersonated authenticates the session as the impersonated user, and stores
+// the admin that started the impersonation so that the session can be restored
+func SaveUserImpersonated(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	admin *models.User,
+	user *models.User,
+) error {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+
+	if err != nil {
+		return err
+	}
+
+	session.Values["authenticated"] = true
+	session.Values["user_id"] = user.ID
+	session.Values["email"] = user.Email
+	session.Values["impersonator_id"] = admin.ID
+
+	return session.Save(r, w)
+}
+
+// GetImpersonatorID returns the id of the admin that is impersonating the user of the
+// session, if the session is impersonated
+func GetImpersonatorID(r *http.Request, config *config.Config) (uint, bool) {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+
+	if err != nil {
+		return 0, false
+	}
+
+	adminID, ok := session.Values["impersonator_id"].(uint)
+
+	return adminID, ok && adminID != 0
+}
+
+// SaveUserImpersonationStopped authenticates the session as the admin that started the
+// impersonation again
+func SaveUserImpersonationStopped(
+	w http.ResponseWriter,
+	r *http.Request,
+	config *config.Config,
+	admin *models.User,
+) error {
+	session, err := config.Store.Get(r, config.ServerConf.CookieName)
+
+	if err != nil {
+		return err
+	}
+
+	session.Values["authenticated"] = true
+	session.Values["user_id"] = admin.ID
+	session.Values["email"] = admin.Email
+	delete(session.Values, "impersonator_id")
+
 	return session.Save(r, w)
 }
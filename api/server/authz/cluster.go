@@ -50,9 +50,9 @@ func (p *ClusterScopedMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Reque
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			apierrors.HandleAPIError(p.config, w, r, apierrors.NewErrForbidden(
+			apierrors.HandleAPIError(p.config, w, r, apierrors.WithCode(apierrors.NewErrForbidden(
 				fmt.Errorf("cluster with id %d not found in project %d", clusterID, proj.ID),
-			), true)
+			), types.ErrorCodeClusterNotFound), true)
 		} else {
 			apierrors.HandleAPIError(p.config, w, r, apierrors.NewErrInternal(err), true)
 		}
@@ -74,6 +74,7 @@ type KubernetesAgentGetter interface {
 	GetDynamicClient(r *http.Request, cluster *models.Cluster) (dynamic.Interface, error)
 	GetAgent(r *http.Request, cluster *models.Cluster, namespace string) (*kubernetes.Agent, error)
 	GetHelmAgent(r *http.Request, cluster *models.Cluster, namespace string) (*helm.Agent, error)
+	GetStatusReader(r *http.Request, cluster *models.Cluster) (kubernetes.StatusReader, error)
 }
 
 type OutOfClusterAgentGetter struct {
@@ -151,6 +152,12 @@ func (d *OutOfClusterAgentGetter) GetHelmAgent(r *http.Request, cluster *models.
 		return nil, fmt.Errorf("failed to get Helm agent: %s", err.Error())
 	}
 
+	if d.config.OPAClient != nil {
+		helmAgent.PolicyEvaluator = d.config.OPAClient
+	}
+
+	helmAgent.ImageMirror = d.config.ImageMirror
+
 	newCtx := context.WithValue(r.Context(), HelmAgentCtxKey, helmAgent)
 
 	r = r.WithContext(newCtx)
@@ -158,6 +165,29 @@ func (d *OutOfClusterAgentGetter) GetHelmAgent(r *http.Request, cluster *models.
 	return helmAgent, nil
 }
 
+// GetStatusReader returns the informer cache of the cluster if the cache is enabled,
+// and falls back to the agent of the cluster if the informers of the cluster cannot
+// be synced, such as when the cluster's credentials cannot watch all namespaces
+func (d *OutOfClusterAgentGetter) GetStatusReader(r *http.Request, cluster *models.Cluster) (kubernetes.StatusReader, error) {
+	agent, err := d.GetAgent(r, cluster, "")
+
+	if err != nil {
+		return nil, err
+	} else if d.config.InformerCache == nil {
+		return agent, nil
+	}
+
+	informers, err := d.config.InformerCache.Get(cluster.ID, agent)
+
+	if err != nil {
+		d.config.Logger.Warn().Err(err).Uint("cluster_id", cluster.ID).Msg("falling back to the api server for cluster status")
+
+		return agent, nil
+	}
+
+	return informers, nil
+}
+
 func (d *OutOfClusterAgentGetter) GetDynamicClient(r *http.Request, cluster *models.Cluster) (dynamic.Interface, error) {
 	// look for the agent in context
 	ctxDynClientVal := r.Context().Value(KubernetesDynamicClientCtxKey)
@@ -0,0 +1,66 @@
+package authz
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type InstanceAdminFactory struct {
+	config *config.Config
+}
+
+func NewInstanceAdminFactory(
+	config *config.Config,
+) *InstanceAdminFactory {
+	return &InstanceAdminFactory{config}
+}
+
+func (p *InstanceAdminFactory) Middleware(next http.Handler) http.Handler {
+	return &InstanceAdminMiddleware{next, p.config}
+}
+
+type InstanceAdminMiddleware struct {
+	next   http.Handler
+	config *config.Config
+}
+
+func (p *InstanceAdminMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	if !IsInstanceAdmin(p.config, user) {
+		apierrors.HandleAPIError(p.config, w, r, apierrors.NewErrForbidden(
+			fmt.Errorf("user is not an instance admin"),
+		), true)
+
+		return
+	}
+
+	p.next.ServeHTTP(w, r)
+}
+
+// IsInstanceAdmin returns true if the user was made an instance admin, or if the email
+// of the user is one of the instance admin emails of the environment. Users need to have
+// verified their email, since anyone could otherwise register with an admin email.
+func IsInstanceAdmin(config *config.Config, user *models.User) bool {
+	if user == nil || !user.EmailVerified {
+		return false
+	}
+
+	if user.InstanceAdmin {
+		return true
+	}
+
+	for _, email := range config.ServerConf.InstanceAdminEmails {
+		if strings.EqualFold(email, user.Email) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+package authz_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInstanceAdmin(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.ServerConf.InstanceAdminEmails = []string{"admin@porter.run"}
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		expected bool
+	}{
+		{
+			name:     "no user",
+			expected: false,
+		},
+		{
+			name:     "user with another email",
+			user:     &models.User{Email: "user@porter.run", EmailVerified: true},
+			expected: false,
+		},
+		{
+			name:     "verified user with an admin email",
+			user:     &models.User{Email: "Admin@porter.run", EmailVerified: true},
+			expected: true,
+		},
+		{
+			name:     "unverified user with an admin email",
+			user:     &models.User{Email: "admin@porter.run"},
+			expected: false,
+		},
+		{
+			name:     "verified instance admin",
+			user:     &models.User{Email: "user@porter.run", EmailVerified: true, InstanceAdmin: true},
+			expected: true,
+		},
+		{
+			name:     "unverified instance admin",
+			user:     &models.User{Email: "user@porter.run", InstanceAdmin: true},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, authz.IsInstanceAdmin(config, test.user))
+		})
+	}
+}
+
+func TestInstanceAdminMiddleware(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.ServerConf.InstanceAdminEmails = []string{"test@test.it"}
+
+	tests := []struct {
+		name     string
+		verified bool
+	}{
+		{
+			name:     "verified admin",
+			verified: true,
+		},
+		{
+			name:     "unverified admin",
+			verified: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user := &models.User{Email: "test@test.it", EmailVerified: test.verified}
+			next := &testInstanceAdminHandler{}
+			handler := authz.NewInstanceAdminFactory(config).Middleware(next)
+
+			req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/admin/users", nil)
+			req = apitest.WithAuthenticatedUser(t, req, user)
+
+			handler.ServeHTTP(rr, req)
+
+			if test.verified {
+				assert.True(t, next.WasCalled, "next handler should have been called")
+				assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "status code should be ok")
+			} else {
+				assert.False(t, next.WasCalled, "next handler should not have been called")
+				apitest.AssertResponseForbidden(t, rr)
+			}
+		})
+	}
+}
+
+type testInstanceAdminHandler struct {
+	WasCalled bool
+}
+
+func (t *testInstanceAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t.WasCalled = true
+}
@@ -229,7 +229,8 @@ func TestPolicyMiddlewareFailBadParam(t *testing.T) {
 
 	assert.False(t, next.WasCalled, "next handler should not have been called")
 	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
-		Error: fmt.Sprintf("could not convert url parameter %s to uint, got %s", "project_id", "notuint"),
+		Error:     fmt.Sprintf("could not convert url parameter %s to uint, got %s", "project_id", "notuint"),
+		ErrorCode: types.ErrorCodeBadRequest,
 	})
 }
 
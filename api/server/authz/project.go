@@ -52,11 +52,26 @@ func (p *ProjectScopedMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// users that did not sign in through SSO cannot access projects that enforce it
+	if user, _ := r.Context().Value(types.UserScope).(*models.User); project.SSOEnforced && user != nil && !IsSSOUser(user) {
+		apierrors.HandleAPIError(p.config, w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("project %d requires its members to sign in through SSO", projID),
+			http.StatusForbidden,
+		), types.ErrorCodeSSORequired), true)
+
+		return
+	}
+
 	ctx := NewProjectContext(r.Context(), project)
 	r = r.Clone(ctx)
 	p.next.ServeHTTP(w, r)
 }
 
+// IsSSOUser returns true if the user signed up through the SSO provider of the instance
+func IsSSOUser(user *models.User) bool {
+	return user != nil && user.GoogleUserID != ""
+}
+
 func NewProjectContext(ctx context.Context, project *models.Project) context.Context {
 	return context.WithValue(ctx, types.ProjectScope, project)
 }
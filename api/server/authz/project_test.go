@@ -2,6 +2,7 @@ package authz_test
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/porter-dev/porter/api/server/authz"
@@ -72,6 +73,55 @@ func TestProjectMiddlewareFailedRead(t *testing.T) {
 	apitest.AssertResponseInternalServerError(t, rr)
 }
 
+func TestProjectMiddlewareSSOEnforced(t *testing.T) {
+	config, handler, next := loadProjectHandlers(t)
+
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proj.SSOEnforced = true
+
+	if _, err := config.Repo.Project().UpdateProject(proj); err != nil {
+		t.Fatal(err)
+	}
+
+	getRequest := func() (*http.Request, *httptest.ResponseRecorder) {
+		req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/api/projects/1", nil)
+		req = apitest.WithAuthenticatedUser(t, req, user)
+		req = apitest.WithRequestScopes(t, req, map[types.PermissionScope]*types.RequestAction{
+			types.ProjectScope: {
+				Verb: types.APIVerbCreate,
+				Resource: types.NameOrUInt{
+					UInt: 1,
+				},
+			},
+		})
+
+		return req, rr
+	}
+
+	req, rr := getRequest()
+
+	handler.ServeHTTP(rr, req)
+	assert.False(t, next.WasCalled, "next handler should not have been called for a user that did not sign in through SSO")
+	apitest.AssertResponseError(t, rr, http.StatusForbidden, &types.ExternalError{
+		Error:     "project 1 requires its members to sign in through SSO",
+		ErrorCode: types.ErrorCodeSSORequired,
+	})
+
+	user.GoogleUserID = "google-user"
+	req, rr = getRequest()
+
+	handler.ServeHTTP(rr, req)
+	assert.True(t, next.WasCalled, "next handler should have been called for a user that signed in through SSO")
+}
+
 func loadProjectHandlers(
 	t *testing.T,
 	failingRepoMethods ...string,
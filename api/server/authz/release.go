@@ -57,10 +57,10 @@ func (p *ReleaseScopedMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		// ugly casing since at the time of this commit Helm doesn't have an errors package.
 		// so we rely on the Helm error containing "not found"
 		if strings.Contains(err.Error(), "not found") {
-			apierrors.HandleAPIError(p.config, w, r, apierrors.NewErrPassThroughToClient(
+			apierrors.HandleAPIError(p.config, w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
 				fmt.Errorf("release not found"),
 				http.StatusNotFound,
-			), true)
+			), types.ErrorCodeReleaseNotFound), true)
 		} else {
 			apierrors.HandleAPIError(p.config, w, r, apierrors.NewErrInternal(err), true)
 		}
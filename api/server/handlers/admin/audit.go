@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// auditLogOpts are the targets and details of an action of an instance admin
+type auditLogOpts struct {
+	targetUserID    uint
+	targetProjectID uint
+	details         string
+}
+
+// createAuditLog records an action of an instance admin. Actions that cannot be
+// recorded are not performed, so the error is returned to the caller.
+func createAuditLog(
+	repo repository.Repository,
+	admin *models.User,
+	action types.AdminAuditAction,
+	opts *auditLogOpts,
+) error {
+	_, err := repo.InstanceAdmin().CreateAuditLog(&models.AdminAuditLog{
+		AdminUserID:     admin.ID,
+		Action:          action,
+		TargetUserID:    opts.targetUserID,
+		TargetProjectID: opts.targetProjectID,
+		Details:         opts.details,
+	})
+
+	if err != nil {
+		return fmt.Errorf("could not create audit log: %w", err)
+	}
+
+	return nil
+}
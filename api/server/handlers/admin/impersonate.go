@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authn"
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// ImpersonateUserHandler authenticates the session of an instance admin as another user,
+// so that support can see what the user sees
+type ImpersonateUserHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewImpersonateUserHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ImpersonateUserHandler {
+	return &ImpersonateUserHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ImpersonateUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admin, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	// impersonation is stored in the session, so it cannot be started with a token
+	if authn.GetTokenFromContext(r.Context()) != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("impersonation requires a session, and cannot be started with a token"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	user, reqErr := readTargetUser(c.Config(), r)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	if authz.IsInstanceAdmin(c.Config(), user) {
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(
+			fmt.Errorf("instance admin %d cannot impersonate instance admin %d", admin.ID, user.ID),
+		))
+
+		return
+	}
+
+	if err := createAuditLog(c.Repo(), admin, types.AdminAuditActionImpersonate, &auditLogOpts{
+		targetUserID: user.ID,
+	}); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := authn.SaveUserImpersonated(w, r, c.Config(), admin, user); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, user.ToUserType())
+}
+
+// StopImpersonationHandler authenticates an impersonated session as the instance admin
+// that started the impersonation again. It is not restricted to instance admins, since
+// the session belongs to the impersonated user.
+type StopImpersonationHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewStopImpersonationHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *StopImpersonationHandler {
+	return &StopImpersonationHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *StopImpersonationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	adminID, ok := authn.GetImpersonatorID(r, c.Config())
+
+	if !ok || authn.GetTokenFromContext(r.Context()) != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("session is not impersonated"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	admin, err := c.Repo().User().ReadUser(adminID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := createAuditLog(c.Repo(), admin, types.AdminAuditActionStopImpersonate, &auditLogOpts{
+		targetUserID: user.ID,
+	}); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := authn.SaveUserImpersonationStopped(w, r, c.Config(), admin); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, admin.ToUserType())
+}
@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+)
+
+type ListAuditLogsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewListAuditLogsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListAuditLogsHandler {
+	return &ListAuditLogsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListAuditLogsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &types.AdminListRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	logs, count, err := c.Repo().InstanceAdmin().ListAuditLogs(request)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.AdminListAuditLogsResponse{
+		AuditLogs: make([]*types.AdminAuditLog, 0),
+		Count:     count,
+	}
+
+	for _, log := range logs {
+		res.AuditLogs = append(res.AuditLogs, log.ToAdminAuditLogType())
+	}
+
+	c.WriteResult(w, r, res)
+}
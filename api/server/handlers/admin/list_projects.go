@@ -0,0 +1,54 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+)
+
+type ListProjectsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewListProjectsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListProjectsHandler {
+	return &ListProjectsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListProjectsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &types.AdminListRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	projects, count, err := c.Repo().InstanceAdmin().ListProjects(request)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.AdminListProjectsResponse{
+		Projects: make([]*types.AdminProject, 0),
+		Count:    count,
+	}
+
+	for _, proj := range projects {
+		res.Projects = append(res.Projects, &types.AdminProject{
+			Project:   proj.ToProjectType(),
+			CreatedAt: proj.CreatedAt,
+		})
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ListUsersHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewListUsersHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListUsersHandler {
+	return &ListUsersHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListUsersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &types.AdminListRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	users, count, err := c.Repo().InstanceAdmin().ListUsers(request)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.AdminListUsersResponse{
+		Users: make([]*types.AdminUser, 0),
+		Count: count,
+	}
+
+	for _, user := range users {
+		res.Users = append(res.Users, toAdminUser(c.Config(), user))
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+func toAdminUser(config *config.Config, user *models.User) *types.AdminUser {
+	_, whitelisted := config.WhitelistedUsers.Map()[user.ID]
+
+	return &types.AdminUser{
+		User:          user.ToUserType(),
+		CreatedAt:     user.CreatedAt,
+		InstanceAdmin: authz.IsInstanceAdmin(config, user),
+		Whitelisted:   whitelisted,
+	}
+}
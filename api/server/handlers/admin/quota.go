@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type GetQuotaHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetQuotaHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetQuotaHandler {
+	return &GetQuotaHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetQuotaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	quota, err := c.Repo().InstanceAdmin().ReadInstanceQuota()
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, quota.ToInstanceQuotaType())
+}
+
+type UpdateQuotaHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateQuotaHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateQuotaHandler {
+	return &UpdateQuotaHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateQuotaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admin, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	request := &types.UpdateInstanceQuotaRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if err := createAuditLog(c.Repo(), admin, types.AdminAuditActionUpdateQuota, &auditLogOpts{
+		details: fmt.Sprintf(
+			"max_projects_per_user=%d max_clusters_per_project=%d max_users_per_project=%d",
+			request.MaxProjectsPerUser,
+			request.MaxClustersPerProject,
+			request.MaxUsersPerProject,
+		),
+	}); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	quota, err := c.Repo().InstanceAdmin().UpdateInstanceQuota(&models.InstanceQuota{
+		MaxProjectsPerUser:    request.MaxProjectsPerUser,
+		MaxClustersPerProject: request.MaxClustersPerProject,
+		MaxUsersPerProject:    request.MaxUsersPerProject,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, quota.ToInstanceQuotaType())
+}
@@ -0,0 +1,140 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/settings"
+)
+
+type ListSettingsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListSettingsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListSettingsHandler {
+	return &ListSettingsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListSettingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.WriteResult(w, r, types.ListServerSettingsResponse(c.Config().Settings.List()))
+}
+
+type UpdateSettingHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateSettingHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateSettingHandler {
+	return &UpdateSettingHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateSettingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admin, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	key, reqErr := readSettingKey(r)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	request := &types.UpdateServerSettingRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if err := settings.Validate(key, request.Value); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := createAuditLog(c.Repo(), admin, types.AdminAuditActionUpdateSetting, &auditLogOpts{
+		details: fmt.Sprintf("%s: %q -> %q", key, c.Config().Settings.Get(key), request.Value),
+	}); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Config().Settings.Set(key, request.Value, admin.ID); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, types.ListServerSettingsResponse(c.Config().Settings.List()))
+}
+
+type ResetSettingHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewResetSettingHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ResetSettingHandler {
+	return &ResetSettingHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ResetSettingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admin, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	key, reqErr := readSettingKey(r)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	if err := createAuditLog(c.Repo(), admin, types.AdminAuditActionResetSetting, &auditLogOpts{
+		details: fmt.Sprintf("%s: %q -> environment", key, c.Config().Settings.Get(key)),
+	}); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Config().Settings.Unset(key); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, types.ListServerSettingsResponse(c.Config().Settings.List()))
+}
+
+// readSettingKey reads the setting key URL parameter, which must be a known setting
+func readSettingKey(r *http.Request) (types.ServerSettingKey, apierrors.RequestError) {
+	keyStr, reqErr := requestutils.GetURLParamString(r, types.URLParamServerSettingKey)
+
+	if reqErr != nil {
+		return "", reqErr
+	}
+
+	for _, key := range types.ServerSettingKeys {
+		if string(key) == keyStr {
+			return key, nil
+		}
+	}
+
+	return "", apierrors.NewErrPassThroughToClient(
+		fmt.Errorf("unknown setting %s", keyStr),
+		http.StatusNotFound,
+	)
+}
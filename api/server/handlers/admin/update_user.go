@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type UpdateUserHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateUserHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateUserHandler {
+	return &UpdateUserHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admin, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	request := &types.AdminUpdateUserRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	user, reqErr := readTargetUser(c.Config(), r)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	if request.InstanceAdmin != nil {
+		if user.ID == admin.ID && !*request.InstanceAdmin {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("instance admins cannot remove their own admin role"),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		if err := createAuditLog(c.Repo(), admin, types.AdminAuditActionUpdateUser, &auditLogOpts{
+			targetUserID: user.ID,
+			details:      fmt.Sprintf("instance_admin=%t", *request.InstanceAdmin),
+		}); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		user.InstanceAdmin = *request.InstanceAdmin
+	}
+
+	user, err := c.Repo().User().UpdateUser(user)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, toAdminUser(c.Config(), user))
+}
+
+// readTargetUser reads the user of the user id URL parameter
+func readTargetUser(config *config.Config, r *http.Request) (*models.User, apierrors.RequestError) {
+	userID, reqErr := requestutils.GetURLParamUint(r, types.URLParamUserID)
+
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	user, err := config.Repo.User().ReadUser(userID)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("user with id %d not found", userID),
+			http.StatusNotFound,
+		)
+	} else if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,155 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ListWhitelistedUsersHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListWhitelistedUsersHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListWhitelistedUsersHandler {
+	return &ListWhitelistedUsersHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListWhitelistedUsersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res := make(types.AdminListWhitelistedUsersResponse, 0)
+
+	for userID := range c.Config().WhitelistedUsers.Map() {
+		res = append(res, userID)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i] < res[j]
+	})
+
+	c.WriteResult(w, r, res)
+}
+
+type WhitelistUserHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewWhitelistUserHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *WhitelistUserHandler {
+	return &WhitelistUserHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *WhitelistUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admin, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	request := &types.AdminWhitelistUserRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if _, exists := c.Config().WhitelistedUsers.Map()[request.UserID]; exists {
+		c.WriteResult(w, r, nil)
+		return
+	}
+
+	if _, err := c.Repo().User().ReadUser(request.UserID); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("user with id %d not found", request.UserID),
+			http.StatusNotFound,
+		))
+
+		return
+	}
+
+	if err := createAuditLog(c.Repo(), admin, types.AdminAuditActionWhitelistUser, &auditLogOpts{
+		targetUserID: request.UserID,
+	}); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	_, err := c.Repo().InstanceAdmin().CreateWhitelistedUser(&models.WhitelistedUser{
+		UserID: request.UserID,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Config().WhitelistedUsers.Reload(); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, nil)
+}
+
+type RemoveWhitelistedUserHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewRemoveWhitelistedUserHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *RemoveWhitelistedUserHandler {
+	return &RemoveWhitelistedUserHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *RemoveWhitelistedUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admin, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	userID, reqErr := requestutils.GetURLParamUint(r, types.URLParamUserID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	if c.Config().WhitelistedUsers.IsFromEnv(userID) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("user %d is whitelisted through the WHITELISTED_USERS environment variable", userID),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	if err := createAuditLog(c.Repo(), admin, types.AdminAuditActionRemoveWhitelisted, &auditLogOpts{
+		targetUserID: userID,
+	}); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Repo().InstanceAdmin().DeleteWhitelistedUser(userID); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Config().WhitelistedUsers.Reload(); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, nil)
+}
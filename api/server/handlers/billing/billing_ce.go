@@ -46,3 +46,39 @@ func NewBillingAddProjectHandler(
 ) http.Handler {
 	return handlers.NewUnavailable(config, "billing_add_project")
 }
+
+type BillingGetUpcomingInvoiceHandler struct {
+	handlers.PorterHandlerWriter
+	handlers.Unavailable
+}
+
+func NewBillingGetUpcomingInvoiceHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) http.Handler {
+	return handlers.NewUnavailable(config, "billing_get_upcoming_invoice")
+}
+
+type BillingListInvoicesHandler struct {
+	handlers.PorterHandlerWriter
+	handlers.Unavailable
+}
+
+func NewBillingListInvoicesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) http.Handler {
+	return handlers.NewUnavailable(config, "billing_list_invoices")
+}
+
+type BillingGetPaymentMethodHandler struct {
+	handlers.PorterHandlerWriter
+	handlers.Unavailable
+}
+
+func NewBillingGetPaymentMethodHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) http.Handler {
+	return handlers.NewUnavailable(config, "billing_get_payment_method")
+}
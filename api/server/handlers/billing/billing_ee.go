@@ -27,8 +27,26 @@ var NewBillingAddProjectHandler func(
 	decoderValidator shared.RequestDecoderValidator,
 ) http.Handler
 
+var NewBillingGetUpcomingInvoiceHandler func(
+	config *config.Config,
+	writer shared.ResultWriter,
+) http.Handler
+
+var NewBillingListInvoicesHandler func(
+	config *config.Config,
+	writer shared.ResultWriter,
+) http.Handler
+
+var NewBillingGetPaymentMethodHandler func(
+	config *config.Config,
+	writer shared.ResultWriter,
+) http.Handler
+
 func init() {
 	NewBillingGetTokenHandler = billing.NewBillingGetTokenHandler
 	NewBillingWebhookHandler = billing.NewBillingWebhookHandler
 	NewBillingAddProjectHandler = billing.NewBillingAddProjectHandler
+	NewBillingGetUpcomingInvoiceHandler = billing.NewBillingGetUpcomingInvoiceHandler
+	NewBillingListInvoicesHandler = billing.NewBillingListInvoicesHandler
+	NewBillingGetPaymentMethodHandler = billing.NewBillingGetPaymentMethodHandler
 }
@@ -0,0 +1,45 @@
+package bucket
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type BucketListHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewBucketListHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *BucketListHandler {
+	return &BucketListHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (p *BucketListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	buckets, err := p.Repo().Bucket().ListBuckets(proj.ID, cluster.ID)
+
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListBucketResponse, len(buckets))
+
+	for i, bucket := range buckets {
+		res[i] = bucket.ToBucketType()
+	}
+
+	p.WriteResult(w, r, res)
+}
@@ -0,0 +1,601 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+type ListBackupsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewListBackupsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListBackupsHandler {
+	return &ListBackupsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP lists the Velero backups of a namespace or release in the cluster. The tracked
+// backups of the cluster are only synced when a backup is created.
+func (c *ListBackupsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.ListBackupsRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	dynClient, ok := getVeleroClient(c, c.KubernetesAgentGetter, w, r, cluster)
+
+	if !ok {
+		return
+	}
+
+	veleroBackups, err := kubernetes.ListVeleroBackups(dynClient)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListBackupsResponse, 0)
+
+	for i := range veleroBackups {
+		backup := toBackupModel(cluster, &veleroBackups[i], &models.Backup{})
+
+		if request.Namespace != "" && backup.Namespace != request.Namespace {
+			continue
+		}
+
+		if request.ReleaseName != "" && backup.ReleaseName != request.ReleaseName {
+			continue
+		}
+
+		res = append(res, backup.ToBackupType())
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type CreateBackupHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewCreateBackupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateBackupHandler {
+	return &CreateBackupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *CreateBackupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.CreateBackupRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if ok := validateBackupTTL(c, w, r, request.TTL); !ok {
+		return
+	}
+
+	dynClient, ok := getVeleroClient(c, c.KubernetesAgentGetter, w, r, cluster)
+
+	if !ok {
+		return
+	}
+
+	veleroBackup, err := kubernetes.CreateVeleroBackup(
+		dynClient,
+		getBackupScope(request.Namespace, request.ReleaseName),
+		request.TTL,
+	)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error creating backup: %s", err.Error()),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	veleroBackups, err := kubernetes.ListVeleroBackups(dynClient)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := syncBackups(c.Config(), cluster, veleroBackups); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, toBackupModel(cluster, veleroBackup, &models.Backup{}).ToBackupType())
+}
+
+type RestoreBackupHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewRestoreBackupHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *RestoreBackupHandler {
+	return &RestoreBackupHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP restores a completed backup into the namespace that it was taken from. A
+// backup of a namespace can be restored for a single release of the namespace.
+func (c *RestoreBackupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	name, reqErr := requestutils.GetURLParamString(r, types.URLParamBackupName)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	request := &types.RestoreBackupRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	dynClient, ok := getVeleroClient(c, c.KubernetesAgentGetter, w, r, cluster)
+
+	if !ok {
+		return
+	}
+
+	veleroBackup, err := kubernetes.GetVeleroBackup(dynClient, name)
+
+	if err != nil && k8serrors.IsNotFound(err) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("backup %s does not exist", name),
+			http.StatusNotFound,
+		))
+
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	backup := toBackupModel(cluster, veleroBackup, &models.Backup{})
+
+	if phase := types.BackupPhase(backup.Phase); phase != types.BackupPhaseCompleted && phase != types.BackupPhasePartiallyFailed {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("backup %s cannot be restored while its phase is %s", name, backup.Phase),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	if request.ReleaseName != "" && backup.ReleaseName != "" && request.ReleaseName != backup.ReleaseName {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("backup %s is a backup of release %s", name, backup.ReleaseName),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	var scope *kubernetes.VeleroBackupScope
+
+	if request.ReleaseName != "" {
+		scope = getBackupScope(backup.Namespace, request.ReleaseName)
+	}
+
+	policy := kubernetes.VeleroExistingResourcePolicyUpdate
+
+	if request.ExistingResourcePolicy != "" {
+		policy = kubernetes.VeleroExistingResourcePolicy(request.ExistingResourcePolicy)
+	}
+
+	restore, err := kubernetes.CreateVeleroRestore(dynClient, name, scope, policy)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error restoring backup: %s", err.Error()),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	phase, _, _ := unstructured.NestedString(restore.Object, "status", "phase")
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, &types.Restore{
+		Name:       restore.GetName(),
+		BackupName: name,
+		Phase:      phase,
+	})
+}
+
+type ListBackupSchedulesHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewListBackupSchedulesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListBackupSchedulesHandler {
+	return &ListBackupSchedulesHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ListBackupSchedulesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	dynClient, ok := getVeleroClient(c, c.KubernetesAgentGetter, w, r, cluster)
+
+	if !ok {
+		return
+	}
+
+	schedules, err := kubernetes.ListVeleroSchedules(dynClient)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListBackupSchedulesResponse, 0)
+
+	for i := range schedules {
+		res = append(res, toBackupScheduleType(&schedules[i]))
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type CreateBackupScheduleHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewCreateBackupScheduleHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateBackupScheduleHandler {
+	return &CreateBackupScheduleHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *CreateBackupScheduleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.CreateBackupScheduleRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if ok := validateBackupTTL(c, w, r, request.TTL); !ok {
+		return
+	}
+
+	name := request.Name
+
+	if name == "" {
+		name = request.Namespace
+
+		if request.ReleaseName != "" {
+			name = fmt.Sprintf("%s-%s", request.Namespace, request.ReleaseName)
+		}
+	}
+
+	dynClient, ok := getVeleroClient(c, c.KubernetesAgentGetter, w, r, cluster)
+
+	if !ok {
+		return
+	}
+
+	schedule, err := kubernetes.CreateVeleroSchedule(
+		dynClient,
+		name,
+		request.Schedule,
+		getBackupScope(request.Namespace, request.ReleaseName),
+		request.TTL,
+	)
+
+	if err != nil && k8serrors.IsAlreadyExists(err) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("backup schedule %s already exists", name),
+			http.StatusConflict,
+		))
+
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error creating backup schedule: %s", err.Error()),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, toBackupScheduleType(schedule))
+}
+
+type DeleteBackupScheduleHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewDeleteBackupScheduleHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DeleteBackupScheduleHandler {
+	return &DeleteBackupScheduleHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *DeleteBackupScheduleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	name, reqErr := requestutils.GetURLParamString(r, types.URLParamBackupScheduleName)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	dynClient, ok := getVeleroClient(c, c.KubernetesAgentGetter, w, r, cluster)
+
+	if !ok {
+		return
+	}
+
+	if err := kubernetes.DeleteVeleroSchedule(dynClient, name); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// getVeleroClient returns a dynamic client for the Velero resources of the cluster, or
+// writes an error if Velero is not installed
+func getVeleroClient(
+	c handlers.PorterHandler,
+	agentGetter authz.KubernetesAgentGetter,
+	w http.ResponseWriter,
+	r *http.Request,
+	cluster *models.Cluster,
+) (dynamic.Interface, bool) {
+	agent, err := agentGetter.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	installed, err := agent.IsResourceServed(kubernetes.VeleroBackupResource)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	if !installed {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("backups require Velero, which is not installed in this cluster"),
+			http.StatusBadRequest,
+		))
+
+		return nil, false
+	}
+
+	dynClient, err := agentGetter.GetDynamicClient(r, cluster)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	return dynClient, true
+}
+
+func validateBackupTTL(c handlers.PorterHandler, w http.ResponseWriter, r *http.Request, ttl string) bool {
+	if ttl == "" {
+		return true
+	}
+
+	if d, err := time.ParseDuration(ttl); err != nil || d <= 0 {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("ttl must be a positive duration, such as 720h"),
+			http.StatusBadRequest,
+		))
+
+		return false
+	}
+
+	return true
+}
+
+// getBackupScope returns the scope of a backup of a namespace, or of the resources of a
+// release in the namespace, which carry the release ownership label. Resources that were
+// deployed before the ownership label was set are matched by the instance label that
+// Helm charts set instead.
+func getBackupScope(namespace, releaseName string) *kubernetes.VeleroBackupScope {
+	scope := &kubernetes.VeleroBackupScope{
+		Namespace: namespace,
+	}
+
+	if releaseName != "" {
+		scope.Selector = map[string]string{
+			helm.LabelRelease: releaseName,
+		}
+
+		scope.AltSelectors = []map[string]string{
+			{kubernetes.ReleaseInstanceLabel: releaseName},
+		}
+	}
+
+	return scope
+}
+
+// syncBackups updates the tracked backups of a cluster from the Velero backups in the
+// cluster. Backups that no longer exist in the cluster, such as expired backups, are no
+// longer tracked.
+func syncBackups(
+	config *config.Config,
+	cluster *models.Cluster,
+	veleroBackups []unstructured.Unstructured,
+) error {
+	exists := make(map[string]bool)
+
+	for i := range veleroBackups {
+		exists[veleroBackups[i].GetName()] = true
+
+		backup, err := config.Repo.Backup().ReadBackupByName(cluster.ID, veleroBackups[i].GetName())
+
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_, err = config.Repo.Backup().CreateBackup(toBackupModel(cluster, &veleroBackups[i], &models.Backup{}))
+		} else {
+			_, err = config.Repo.Backup().UpdateBackup(toBackupModel(cluster, &veleroBackups[i], backup))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	tracked, err := config.Repo.Backup().ListBackupsByClusterID(cluster.ID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range tracked {
+		if exists[backup.Name] {
+			continue
+		}
+
+		if err := config.Repo.Backup().DeleteBackup(backup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toBackupModel sets the fields of a tracked backup from a Velero backup
+func toBackupModel(cluster *models.Cluster, obj *unstructured.Unstructured, backup *models.Backup) *models.Backup {
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	scope := kubernetes.GetVeleroBackupScope(spec)
+
+	backup.ProjectID = cluster.ProjectID
+	backup.ClusterID = cluster.ID
+	backup.Name = obj.GetName()
+	backup.Namespace = scope.Namespace
+	backup.ReleaseName = scope.Selector[helm.LabelRelease]
+	backup.ScheduleName = obj.GetLabels()[kubernetes.VeleroScheduleNameLabel]
+
+	backup.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+
+	if backup.Phase == "" {
+		backup.Phase = string(types.BackupPhaseNew)
+	}
+
+	errs, _, _ := unstructured.NestedInt64(obj.Object, "status", "errors")
+	warnings, _, _ := unstructured.NestedInt64(obj.Object, "status", "warnings")
+
+	backup.Errors = int(errs)
+	backup.Warnings = int(warnings)
+	backup.StartedAt = getStatusTime(obj, "startTimestamp")
+	backup.CompletedAt = getStatusTime(obj, "completionTimestamp")
+	backup.ExpiresAt = getStatusTime(obj, "expiration")
+
+	return backup
+}
+
+func toBackupScheduleType(obj *unstructured.Unstructured) *types.BackupSchedule {
+	template, _, _ := unstructured.NestedMap(obj.Object, "spec", "template")
+	scope := kubernetes.GetVeleroBackupScope(template)
+
+	res := &types.BackupSchedule{
+		Name:         obj.GetName(),
+		Namespace:    scope.Namespace,
+		ReleaseName:  scope.Selector[helm.LabelRelease],
+		LastBackupAt: getStatusTime(obj, "lastBackup"),
+	}
+
+	res.Schedule, _, _ = unstructured.NestedString(obj.Object, "spec", "schedule")
+	res.TTL, _, _ = unstructured.NestedString(template, "ttl")
+	res.Paused, _, _ = unstructured.NestedBool(obj.Object, "spec", "paused")
+
+	return res
+}
+
+func getStatusTime(obj *unstructured.Unstructured, field string) *time.Time {
+	val, _, _ := unstructured.NestedString(obj.Object, "status", field)
+
+	if val == "" {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, val)
+
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
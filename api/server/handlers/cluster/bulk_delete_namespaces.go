@@ -0,0 +1,312 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type BulkDeleteNamespacesHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewBulkDeleteNamespacesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *BulkDeleteNamespacesHandler {
+	return &BulkDeleteNamespacesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP deletes the preview environment namespaces that match a name pattern and a
+// minimum age. Namespaces that contain resources that were not created by Porter are
+// only deleted if the request is forced, and dry runs only list the namespaces.
+// Namespaces with protected releases or releases with deletion protection are never
+// deleted.
+func (c *BulkDeleteNamespacesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.BulkDeleteNamespacesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	pattern := request.Pattern
+
+	if pattern == "" {
+		pattern = previewNamespacePrefix + "*"
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("invalid pattern %s: %s", pattern, err.Error()),
+			http.StatusBadRequest,
+		))
+		return
+	}
+
+	var olderThan time.Duration
+
+	if request.OlderThan != "" {
+		var err error
+
+		olderThan, err = time.ParseDuration(request.OlderThan)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("invalid older_than %s: %s", request.OlderThan, err.Error()),
+				http.StatusBadRequest,
+			))
+			return
+		}
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	namespaces, err := agent.ListNamespaces()
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.BulkDeleteNamespacesResponse{
+		DryRun:     request.DryRun,
+		Namespaces: make([]*types.BulkDeleteNamespace, 0),
+	}
+
+	for _, ns := range namespaces.Items {
+		if !strings.HasPrefix(ns.Name, previewNamespacePrefix) || ns.DeletionTimestamp != nil {
+			continue
+		}
+
+		if matched, _ := path.Match(pattern, ns.Name); !matched {
+			continue
+		}
+
+		if time.Since(ns.CreationTimestamp.Time) < olderThan {
+			continue
+		}
+
+		nsRes := &types.BulkDeleteNamespace{
+			Name:      ns.Name,
+			CreatedAt: ns.CreationTimestamp.Time,
+		}
+
+		res.Namespaces = append(res.Namespaces, nsRes)
+
+		releases, err := c.Repo().Release().ListReleasesByNamespace(cluster.ID, ns.Name)
+
+		if err != nil {
+			nsRes.Error = err.Error()
+			continue
+		}
+
+		nsRes.NonPorterResources, err = getNonPorterResources(agent, cluster, ns.Name, releases)
+
+		if err != nil {
+			nsRes.Error = err.Error()
+			continue
+		}
+
+		if len(nsRes.NonPorterResources) > 0 && !request.Force {
+			nsRes.Error = "namespace contains resources that were not created by Porter"
+			continue
+		}
+
+		// releases with deletion protection are not deleted, even if the request is forced
+		if reqErr := release.CheckNamespaceDeletionProtection(c.Config(), cluster, ns.Name); reqErr != nil {
+			nsRes.Error = reqErr.Error()
+			continue
+		}
+
+		// deletes of protected releases must be approved through a change request, so
+		// namespaces with protected releases are not deleted either
+		if name := getProtectedReleaseName(releases); name != "" {
+			nsRes.Error = fmt.Sprintf("namespace contains protected release %s, which can only be deleted through a change request", name)
+			continue
+		}
+
+		if request.DryRun {
+			continue
+		}
+
+		if err := agent.DeleteNamespace(ns.Name); err != nil {
+			nsRes.Error = err.Error()
+			continue
+		}
+
+		nsRes.Deleted = true
+	}
+
+	sort.Slice(res.Namespaces, func(i, j int) bool {
+		return res.Namespaces[i].Name < res.Namespaces[j].Name
+	})
+
+	c.WriteResult(w, r, res)
+}
+
+// getNonPorterResources returns the resources of a namespace that were not created by
+// Porter, of the form kind/name
+func getNonPorterResources(
+	agent *kubernetes.Agent,
+	cluster *models.Cluster,
+	namespace string,
+	releases []*models.Release,
+) ([]string, error) {
+	resources, err := agent.ListNamespaceResources(namespace)
+
+	if err != nil {
+		return nil, err
+	}
+
+	checker := newPorterResourceChecker(cluster, releases, resources)
+	res := make([]string, 0)
+
+	for _, resource := range resources {
+		if !checker.isPorterResource(resource) {
+			res = append(res, fmt.Sprintf("%s/%s", resource.Kind, resource.Name))
+		}
+	}
+
+	return res, nil
+}
+
+// maxOwnerDepth limits how many owners are followed to find whether an owned resource
+// was created by Porter, such as the pods of the replicasets of a deployment
+const maxOwnerDepth = 5
+
+// porterResourceChecker finds whether the resources of a namespace were created by Porter
+type porterResourceChecker struct {
+	projectID string
+	clusterID string
+
+	// releases are the names of the Helm releases of the namespace that were deployed by
+	// Porter
+	releases map[string]bool
+
+	// resources are the resources of the namespace by kind and name
+	resources map[string]*kubernetes.LabeledResource
+}
+
+func newPorterResourceChecker(
+	cluster *models.Cluster,
+	releases []*models.Release,
+	resources []*kubernetes.LabeledResource,
+) *porterResourceChecker {
+	res := &porterResourceChecker{
+		projectID: fmt.Sprintf("%d", cluster.ProjectID),
+		clusterID: fmt.Sprintf("%d", cluster.ID),
+		releases:  make(map[string]bool),
+		resources: make(map[string]*kubernetes.LabeledResource),
+	}
+
+	for _, rel := range releases {
+		res.releases[rel.Name] = true
+	}
+
+	for _, resource := range resources {
+		res.resources[resource.Kind+"/"+resource.Name] = resource
+
+		// add-ons are not stored as releases, but their resources have the ownership
+		// labels of their release
+		if res.hasOwnershipLabels(resource) && resource.Labels[helm.LabelRelease] != "" {
+			res.releases[resource.Labels[helm.LabelRelease]] = true
+		}
+	}
+
+	return res
+}
+
+// isPorterResource returns true if a resource was created by Porter: resources with the
+// ownership labels of the cluster, resources and storage of the Helm releases deployed by
+// Porter, env groups, and the resources that Kubernetes creates in every namespace.
+// Owned resources, service account tokens and endpoints are checked through the resource
+// that they belong to.
+func (p *porterResourceChecker) isPorterResource(resource *kubernetes.LabeledResource) bool {
+	return p.isPorterResourceAtDepth(resource, 0)
+}
+
+func (p *porterResourceChecker) isPorterResourceAtDepth(resource *kubernetes.LabeledResource, depth int) bool {
+	if depth > maxOwnerDepth {
+		return false
+	}
+
+	labels := resource.Labels
+	annotations := resource.Annotations
+
+	switch {
+	case p.hasOwnershipLabels(resource):
+		return true
+	case labels["app.kubernetes.io/managed-by"] == "Helm" &&
+		annotations["meta.helm.sh/release-namespace"] == resource.Namespace &&
+		p.releases[annotations["meta.helm.sh/release-name"]]:
+		return true
+	case resource.Kind == "Secret" && labels["owner"] == "helm" && p.releases[labels["name"]]:
+		return true
+	case (resource.Kind == "ConfigMap" || resource.Kind == "Secret") &&
+		((labels["owner"] == "porter" && labels["envgroup"] != "") || labels["porter"] == "true"):
+		return true
+	case resource.Kind == "ConfigMap" && resource.Name == "kube-root-ca.crt",
+		resource.Kind == "ServiceAccount" && resource.Name == "default":
+		return true
+	}
+
+	var owner *kubernetes.LabeledResource
+
+	switch {
+	case resource.Owned:
+		owner = p.resources[resource.OwnerKind+"/"+resource.OwnerName]
+	case resource.Kind == "Secret" && annotations["kubernetes.io/service-account.name"] != "":
+		// tokens of service accounts are created by Kubernetes, and belong to the service
+		// account
+		owner = p.resources["ServiceAccount/"+annotations["kubernetes.io/service-account.name"]]
+	case resource.Kind == "Endpoints":
+		// endpoints are created by Kubernetes for the service with the same name
+		owner = p.resources["Service/"+resource.Name]
+	}
+
+	return owner != nil && p.isPorterResourceAtDepth(owner, depth+1)
+}
+
+// hasOwnershipLabels returns true if a resource has the ownership labels that Porter adds
+// to the resources of the releases of the cluster
+func (p *porterResourceChecker) hasOwnershipLabels(resource *kubernetes.LabeledResource) bool {
+	return resource.Labels[helm.LabelProject] == p.projectID && resource.Labels[helm.LabelCluster] == p.clusterID
+}
+
+// getProtectedReleaseName returns the name of the first protected release, or an empty
+// string if no release is protected
+func getProtectedReleaseName(releases []*models.Release) string {
+	for _, rel := range releases {
+		if rel.Protected {
+			return rel.Name
+		}
+	}
+
+	return ""
+}
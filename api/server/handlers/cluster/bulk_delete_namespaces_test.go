@@ -0,0 +1,110 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestIsPorterResource(t *testing.T) {
+	cluster := &models.Cluster{ProjectID: 1}
+	cluster.ID = 2
+
+	ownershipLabels := map[string]string{
+		helm.LabelProject: "1",
+		helm.LabelCluster: "2",
+		helm.LabelRelease: "redis",
+	}
+
+	helmAnnotations := func(release string) map[string]string {
+		return map[string]string{
+			"meta.helm.sh/release-name":      release,
+			"meta.helm.sh/release-namespace": "pr-1",
+		}
+	}
+
+	helmLabels := map[string]string{"app.kubernetes.io/managed-by": "Helm"}
+
+	resources := []*kubernetes.LabeledResource{
+		// resources of a release deployed by Porter, which only has Helm metadata
+		{Kind: "Deployment", Name: "web", Labels: helmLabels, Annotations: helmAnnotations("web")},
+		{Kind: "ReplicaSet", Name: "web-abc", Owned: true, OwnerKind: "Deployment", OwnerName: "web"},
+		{Kind: "Pod", Name: "web-abc-de", Owned: true, OwnerKind: "ReplicaSet", OwnerName: "web-abc"},
+		{Kind: "Service", Name: "web", Labels: helmLabels, Annotations: helmAnnotations("web")},
+		{Kind: "Endpoints", Name: "web"},
+		{Kind: "Secret", Name: "sh.helm.release.v1.web.v1", Labels: map[string]string{"owner": "helm", "name": "web"}},
+
+		// resources of an add-on, which have the ownership labels
+		{Kind: "StatefulSet", Name: "redis", Labels: ownershipLabels},
+		{Kind: "Secret", Name: "sh.helm.release.v1.redis.v1", Labels: map[string]string{"owner": "helm", "name": "redis"}},
+		{Kind: "ServiceAccount", Name: "redis", Labels: ownershipLabels},
+		{Kind: "Secret", Name: "redis-token-abc", Annotations: map[string]string{"kubernetes.io/service-account.name": "redis"}},
+
+		// env groups and the resources that Kubernetes creates in every namespace
+		{Kind: "ConfigMap", Name: "env.v1", Labels: map[string]string{"owner": "porter", "envgroup": "env"}},
+		{Kind: "ConfigMap", Name: "kube-root-ca.crt"},
+		{Kind: "ServiceAccount", Name: "default"},
+		{Kind: "Secret", Name: "default-token-abc", Annotations: map[string]string{"kubernetes.io/service-account.name": "default"}},
+	}
+
+	nonPorterResources := []*kubernetes.LabeledResource{
+		// a release that was installed with Helm outside of Porter
+		{Kind: "Deployment", Name: "other", Labels: helmLabels, Annotations: helmAnnotations("other")},
+		{Kind: "Secret", Name: "sh.helm.release.v1.other.v1", Labels: map[string]string{"owner": "helm", "name": "other"}},
+
+		// resources with the ownership labels of another cluster
+		{Kind: "Deployment", Name: "copied", Labels: map[string]string{
+			helm.LabelProject: "1",
+			helm.LabelCluster: "3",
+		}},
+
+		// resources that are owned by resources that were not created by Porter
+		{Kind: "Deployment", Name: "operated", Owned: true, OwnerKind: "Database", OwnerName: "db"},
+		{Kind: "ReplicaSet", Name: "other-abc", Owned: true, OwnerKind: "Deployment", OwnerName: "other"},
+		{Kind: "Database", Name: "db"},
+
+		// tokens of service accounts that were not created by Porter
+		{Kind: "ServiceAccount", Name: "ci"},
+		{Kind: "Secret", Name: "ci-token-abc", Annotations: map[string]string{"kubernetes.io/service-account.name": "ci"}},
+
+		// labels that only mark env groups
+		{Kind: "Deployment", Name: "labeled", Labels: map[string]string{"porter": "true"}},
+	}
+
+	all := append(resources, nonPorterResources...)
+
+	for _, resource := range all {
+		resource.Namespace = "pr-1"
+	}
+
+	checker := newPorterResourceChecker(cluster, []*models.Release{{Name: "web", Namespace: "pr-1"}}, all)
+
+	for _, resource := range resources {
+		if !checker.isPorterResource(resource) {
+			t.Errorf("expected %s/%s to be a Porter resource", resource.Kind, resource.Name)
+		}
+	}
+
+	for _, resource := range nonPorterResources {
+		if checker.isPorterResource(resource) {
+			t.Errorf("expected %s/%s not to be a Porter resource", resource.Kind, resource.Name)
+		}
+	}
+}
+
+func TestGetProtectedReleaseName(t *testing.T) {
+	releases := []*models.Release{
+		{Name: "web"},
+		{Name: "api", Protected: true},
+	}
+
+	if name := getProtectedReleaseName(releases); name != "api" {
+		t.Errorf("expected protected release api, got %q", name)
+	}
+
+	if name := getProtectedReleaseName(releases[:1]); name != "" {
+		t.Errorf("expected no protected release, got %q", name)
+	}
+}
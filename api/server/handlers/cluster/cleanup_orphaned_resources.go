@@ -0,0 +1,135 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type CleanupOrphanedResourcesHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewCleanupOrphanedResourcesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CleanupOrphanedResourcesHandler {
+	return &CleanupOrphanedResourcesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *CleanupOrphanedResourcesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.CleanupOrphanedResourcesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the report is generated again, so that only resources that are still orphaned
+	// are deleted
+	report, err := getOrphanedResourcesReport(c.Repo(), cluster, agent, helmAgent)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	orphanedResources := make(map[string]*types.OrphanedResource)
+
+	for _, resource := range report.Resources {
+		orphanedResources[getOrphanedResourceKey(resource)] = resource
+	}
+
+	orphanedNamespaces := make(map[string]bool)
+
+	for _, ns := range report.Namespaces {
+		orphanedNamespaces[ns] = true
+	}
+
+	for _, resource := range request.Resources {
+		if _, ok := orphanedResources[getOrphanedResourceKey(resource)]; !ok {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("%s %s/%s is not an orphaned resource", resource.Kind, resource.Namespace, resource.Name),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+	}
+
+	for _, ns := range request.Namespaces {
+		if !orphanedNamespaces[ns] {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("namespace %s is not an orphaned preview environment namespace", ns),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+	}
+
+	res := &types.CleanupOrphanedResourcesResponse{
+		Resources:  make([]*types.OrphanedResource, 0),
+		Namespaces: make([]string, 0),
+	}
+
+	for _, resource := range request.Resources {
+		err := agent.DeleteLabeledResource(resource.Kind, resource.Namespace, resource.Name)
+
+		if err != nil && err != kubernetes.IsNotFoundError {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(
+				fmt.Errorf("error deleting %s %s/%s: %w", resource.Kind, resource.Namespace, resource.Name, err),
+			))
+
+			return
+		}
+
+		res.Resources = append(res.Resources, orphanedResources[getOrphanedResourceKey(resource)])
+	}
+
+	for _, ns := range request.Namespaces {
+		if err := agent.DeleteNamespace(ns); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(
+				fmt.Errorf("error deleting namespace %s: %w", ns, err),
+			))
+
+			return
+		}
+
+		res.Namespaces = append(res.Namespaces, ns)
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+func getOrphanedResourceKey(resource *types.OrphanedResource) string {
+	return fmt.Sprintf("%s/%s/%s", resource.Kind, resource.Namespace, resource.Name)
+}
@@ -51,6 +51,15 @@ func (c *CreateNamespaceHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// namespaces created through Porter are managed by Porter, so they enforce the pod
+	// security level of the cluster
+	if level := cluster.GetPodSecurityLevel(); level != types.PodSecurityLevelPrivileged {
+		if err := agent.SetNamespacePodSecurityLevel(namespace.Name, level); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
 	res := types.CreateNamespaceResponse{
 		Namespace: namespace,
 	}
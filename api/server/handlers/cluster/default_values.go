@@ -0,0 +1,109 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type GetClusterDefaultValuesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetClusterDefaultValuesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetClusterDefaultValuesHandler {
+	return &GetClusterDefaultValuesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetClusterDefaultValuesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	res := &types.DefaultValuesResponse{
+		Values: map[string]interface{}{},
+	}
+
+	defaultValues, err := c.Repo().DefaultValues().ReadDefaultValues(cluster.ProjectID, cluster.ID)
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	} else if err == nil {
+		res.Values, err = defaultValues.GetValues()
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type UpdateClusterDefaultValuesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateClusterDefaultValuesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateClusterDefaultValuesHandler {
+	return &UpdateClusterDefaultValuesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateClusterDefaultValuesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateDefaultValuesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.Values == nil {
+		request.Values = map[string]interface{}{}
+	}
+
+	valuesBytes, err := json.Marshal(request.Values)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	defaultValues, err := c.Repo().DefaultValues().ReadDefaultValues(cluster.ProjectID, cluster.ID)
+
+	if err == gorm.ErrRecordNotFound {
+		defaultValues = &models.DefaultValues{
+			ProjectID: cluster.ProjectID,
+			ClusterID: cluster.ID,
+		}
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	defaultValues.Values = valuesBytes
+
+	if _, err := c.Repo().DefaultValues().UpdateDefaultValues(defaultValues); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.DefaultValuesResponse{
+		Values: request.Values,
+	})
+}
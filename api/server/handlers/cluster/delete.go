@@ -5,6 +5,7 @@ import (
 
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/release"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
@@ -30,6 +31,13 @@ func NewClusterDeleteHandler(
 func (c *ClusterDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 
+	// the releases of a deleted cluster can no longer be managed, so clusters with
+	// releases that have deletion protection cannot be deleted
+	if reqErr := release.CheckNamespaceDeletionProtection(c.Config(), cluster); reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
 	err := c.Repo().Cluster().DeleteCluster(cluster)
 
 	if err != nil {
@@ -37,5 +45,21 @@ func (c *ClusterDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if c.Config().InformerCache != nil {
+		c.Config().InformerCache.Evict(cluster.ID)
+	}
+
+	if c.Config().Inventory != nil {
+		c.Config().Inventory.Invalidate(cluster.ID)
+	}
+
+	// the releases of the cluster are no longer checked, so they are removed from the
+	// stale release report
+	if staleReleases, err := c.Repo().StaleRelease().ListStaleReleasesByClusterID(cluster.ID); err == nil {
+		for _, staleRelease := range staleReleases {
+			c.Repo().StaleRelease().DeleteStaleRelease(staleRelease)
+		}
+	}
+
 	c.WriteResult(w, r, cluster.ToClusterType())
 }
@@ -0,0 +1,125 @@
+package cluster
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+// GetDependencyGraphHandler returns the dependency graph between the releases managed by
+// Porter in the cluster, or in a namespace of the cluster. Releases are managed by Porter
+// if their resources carry the ownership labels of the cluster, or if they were deployed
+// through Porter.
+type GetDependencyGraphHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetDependencyGraphHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GetDependencyGraphHandler {
+	return &GetDependencyGraphHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetDependencyGraphHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.GetDependencyGraphRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	res, err := getDependencyGraph(c.Config(), c.KubernetesAgentGetter, r, cluster, request.Namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// getDependencyGraph computes the dependency graph between the releases managed by
+// Porter in a namespace, or in the whole cluster if the namespace is empty
+func getDependencyGraph(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	cluster *models.Cluster,
+	namespace string,
+) (*types.DependencyGraph, error) {
+	agent, err := agentGetter.GetAgent(r, cluster, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	helmAgent, err := agentGetter.GetHelmAgent(r, cluster, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	helmReleases, err := helmAgent.ListReleases(namespace, &types.ReleaseListFilter{
+		StatusFilter: []string{
+			"deployed",
+			"failed",
+			"pending-install",
+			"pending-upgrade",
+			"pending-rollback",
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]*helm.DependencyGraphRelease, 0)
+
+	for _, helmRelease := range helmReleases {
+		rel, err := config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		// releases that were deployed through Porter before Porter labeled resources
+		// do not carry the ownership labels, and are found through their record
+		if err != nil && !helm.IsPorterManaged(helmRelease, cluster) {
+			continue
+		}
+
+		depRelease := &helm.DependencyGraphRelease{
+			Release: helmRelease,
+		}
+
+		if rel != nil {
+			depRelease.Dependencies = rel.GetDependencies()
+		}
+
+		releases = append(releases, depRelease)
+	}
+
+	envGroups, err := agent.ListAllVersionedConfigMaps(namespace)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return helm.GetDependencyGraph(releases, envGroups)
+}
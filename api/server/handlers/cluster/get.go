@@ -45,6 +45,10 @@ func (c *ClusterGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	endpoint, found, ingressErr := domain.GetNGINXIngressServiceIP(agent.Clientset)
 
+	if lb := cluster.GetIngressLoadBalancer(); lb != nil && lb.StaticIP != "" {
+		endpoint, found = lb.StaticIP, true
+	}
+
 	if found {
 		res.IngressIP = endpoint
 	}
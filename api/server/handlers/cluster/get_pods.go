@@ -38,7 +38,7 @@ func (c *GetPodsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 
-	agent, err := c.GetAgent(r, cluster, "")
+	statusReader, err := c.GetStatusReader(r, cluster)
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -47,7 +47,7 @@ func (c *GetPodsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	pods := []v1.Pod{}
 	for _, selector := range request.Selectors {
-		podsList, err := agent.GetPodsByLabel(selector, request.Namespace)
+		podsList, err := statusReader.GetPodsByLabel(selector, request.Namespace)
 
 		if err != nil {
 			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -0,0 +1,212 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/cosign"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type InstallImageSigningPolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewInstallImageSigningPolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *InstallImageSigningPolicyHandler {
+	return &InstallImageSigningPolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP installs a ClusterImagePolicy of the sigstore policy-controller with the signing
+// authorities of the project, so that the signatures of images are also verified when pods
+// are admitted, and labels the namespaces of the request for the policy-controller. The
+// policy and its namespaces are replaced on every call, so it is called again after the
+// authorities of the project change.
+func (c *InstallImageSigningPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.InstallImageSigningPolicyRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	authorities, err := c.Repo().ImageSigningAuthority().ListImageSigningAuthoritiesByProjectID(proj.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if len(authorities) == 0 {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("the project does not have any signing authorities"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	agent, ok := getImagePolicyAgent(c, c.KubernetesAgentGetter, w, r, cluster)
+
+	if !ok {
+		return
+	}
+
+	policyAuthorities := make([]*cosign.Authority, 0)
+
+	for _, authority := range authorities {
+		policyAuthorities = append(policyAuthorities, &cosign.Authority{
+			Name:      authority.Name,
+			PublicKey: authority.PublicKey,
+			Issuer:    authority.Issuer,
+			Subject:   authority.Subject,
+		})
+	}
+
+	_, err = agent.ApplyObject(cosign.GetClusterImagePolicy(proj.ID, policyAuthorities, request.Enforce))
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	include := make(map[string]bool)
+
+	for _, namespace := range request.Namespaces {
+		include[namespace] = true
+
+		err := agent.SetNamespaceImagePolicy(namespace, true)
+
+		if err != nil && errors.Is(err, kubernetes.IsNotFoundError) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("namespace %s does not exist", namespace),
+				http.StatusBadRequest,
+			))
+
+			return
+		} else if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	if ok := excludeImagePolicyNamespaces(c, agent, w, r, include); !ok {
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+type DeleteImageSigningPolicyHandler struct {
+	handlers.PorterHandler
+	authz.KubernetesAgentGetter
+}
+
+func NewDeleteImageSigningPolicyHandler(
+	config *config.Config,
+) *DeleteImageSigningPolicyHandler {
+	return &DeleteImageSigningPolicyHandler{
+		PorterHandler:         handlers.NewDefaultPorterHandler(config, nil, nil),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *DeleteImageSigningPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	agent, ok := getImagePolicyAgent(c, c.KubernetesAgentGetter, w, r, cluster)
+
+	if !ok {
+		return
+	}
+
+	if err := agent.DeleteObject(cosign.GetClusterImagePolicy(proj.ID, nil, false)); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	excludeImagePolicyNamespaces(c, agent, w, r, nil)
+}
+
+// excludeImagePolicyNamespaces removes the label of the policy-controller from the labeled
+// namespaces that are not included
+func excludeImagePolicyNamespaces(
+	c handlers.PorterHandler,
+	agent *kubernetes.Agent,
+	w http.ResponseWriter,
+	r *http.Request,
+	include map[string]bool,
+) bool {
+	namespaces, err := agent.ListImagePolicyNamespaces()
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return false
+	}
+
+	for _, namespace := range namespaces {
+		if include[namespace] {
+			continue
+		}
+
+		if err := agent.SetNamespaceImagePolicy(namespace, false); err != nil && !errors.Is(err, kubernetes.IsNotFoundError) {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return false
+		}
+	}
+
+	return true
+}
+
+// getImagePolicyAgent returns the agent of the cluster, or writes an error if the sigstore
+// policy-controller is not installed
+func getImagePolicyAgent(
+	c handlers.PorterHandler,
+	agentGetter authz.KubernetesAgentGetter,
+	w http.ResponseWriter,
+	r *http.Request,
+	cluster *models.Cluster,
+) (*kubernetes.Agent, bool) {
+	agent, err := agentGetter.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	installed, err := agent.IsResourceServed(cosign.ClusterImagePolicyResource)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	if !installed {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("the sigstore policy-controller is not installed in this cluster"),
+			http.StatusBadRequest,
+		))
+
+		return nil, false
+	}
+
+	return agent, true
+}
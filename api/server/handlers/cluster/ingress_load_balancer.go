@@ -0,0 +1,160 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/addons"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// UpdateIngressLoadBalancerHandler pins the load balancer of the ingress controller of a
+// cluster to a pre-allocated address. The address is applied to the running ingress
+// controller, as well as when the ingress controller addon is installed or upgraded, and
+// is used as the endpoint of new DNS records.
+type UpdateIngressLoadBalancerHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpdateIngressLoadBalancerHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateIngressLoadBalancerHandler {
+	return &UpdateIngressLoadBalancerHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdateIngressLoadBalancerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateIngressLoadBalancerRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if err := validateIngressLoadBalancer(cluster.ToClusterType().Service, &request.IngressLoadBalancer); err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(
+			apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest),
+			types.ErrorCodeValidationFailed,
+		))
+
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	svc, err := agent.GetIngressNginxService()
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := addons.CheckIngressLoadBalancer(&request.IngressLoadBalancer, svc); err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(
+			apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest),
+			types.ErrorCodeValidationFailed,
+		))
+
+		return
+	}
+
+	cluster.SetIngressLoadBalancer(&request.IngressLoadBalancer)
+
+	cluster, err = c.Repo().Cluster().UpdateCluster(cluster)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the running ingress controller is upgraded with the new address. Controllers that
+	// are not installed through Helm get the address when the addon is installed.
+	if svc != nil && svc.Labels["app.kubernetes.io/instance"] != "" {
+		releaseName := svc.Labels["app.kubernetes.io/instance"]
+
+		helmAgent, err := c.GetHelmAgent(r, cluster, svc.Namespace)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		helmRelease, err := helmAgent.GetRelease(releaseName, 0, false)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("release %s of the ingress controller not found in namespace %s", releaseName, svc.Namespace),
+				http.StatusNotFound,
+			), types.ErrorCodeReleaseNotFound))
+
+			return
+		}
+
+		cr, err := release.NewReleaseUpgrader(c.Config()).Upgrade(&release.UpgradeOpts{
+			User:      user,
+			Cluster:   cluster,
+			Namespace: svc.Namespace,
+			Name:      releaseName,
+			Values:    addons.GetIngressLoadBalancerValues(cluster, helmRelease.Config, svc),
+			Request:   r,
+		})
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("error applying the load balancer to the ingress controller: %s", err.Error()),
+				http.StatusBadRequest,
+			), types.ErrorCodeHelmOperationFailed))
+
+			return
+		}
+
+		if cr != nil {
+			w.WriteHeader(http.StatusAccepted)
+			c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+
+			return
+		}
+	}
+
+	c.WriteResult(w, r, cluster.ToClusterType())
+}
+
+// validateIngressLoadBalancer checks that the settings of the load balancer are
+// supported by the provider of the cluster
+func validateIngressLoadBalancer(service types.ClusterService, lb *types.IngressLoadBalancer) error {
+	switch service {
+	case types.EKS:
+		if lb.StaticIP != "" || lb.LoadBalancerID != "" {
+			return fmt.Errorf("the load balancer of EKS clusters can only be pinned to elastic IPs")
+		}
+	case types.DOKS:
+		if lb.StaticIP != "" || len(lb.EIPAllocations) > 0 {
+			return fmt.Errorf("the load balancer of DOKS clusters can only be pinned to an existing load balancer")
+		}
+	default:
+		if len(lb.EIPAllocations) > 0 || lb.LoadBalancerID != "" {
+			return fmt.Errorf("the load balancer of %s clusters can only be pinned to a static IP", service)
+		}
+	}
+
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/imagemirror"
 	"github.com/porter-dev/porter/internal/models"
 )
 
@@ -43,7 +44,7 @@ func (c *InstallAgentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	chart, err := loader.LoadChartPublic(c.Config().ServerConf.DefaultAddonHelmRepoURL, "porter-agent", "")
+	chart, err := loader.LoadChartPublic(c.Config().Settings.Get(types.ServerSettingDefaultAddonHelmRepoURL), "porter-agent", "")
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -75,7 +76,7 @@ func (c *InstallAgentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	porterAgentValues := map[string]interface{}{
 		"agent": map[string]interface{}{
-			"image":       "public.ecr.aws/o1j4x7p4/porter-agent:latest",
+			"image":       c.Config().ImageMirror.Image(imagemirror.AgentImage + ":latest"),
 			"porterHost":  c.Config().ServerConf.ServerURL,
 			"porterPort":  "443",
 			"porterToken": encoded,
@@ -99,10 +100,10 @@ func (c *InstallAgentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	_, err = helmAgent.InstallChart(conf, c.Config().DOConf)
 
 	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
 			fmt.Errorf("error installing a new chart: %s", err.Error()),
 			http.StatusBadRequest,
-		))
+		), types.ErrorCodeHelmOperationFailed))
 
 		return
 	}
@@ -0,0 +1,140 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type InstallKEDAHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewInstallKEDAHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *InstallKEDAHandler {
+	return &InstallKEDAHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *InstallKEDAHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	helmAgent, err := c.GetHelmAgent(r, cluster, helm.KEDANamespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	installed, err := helmAgent.K8sAgent.IsKEDAInstalled()
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if installed {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("KEDA is already installed in this cluster"),
+			http.StatusConflict,
+		))
+
+		return
+	}
+
+	chart, err := loader.LoadChartPublic(helm.KEDAChartRepoURL, helm.KEDAChartName, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// create namespace if not exists
+	_, err = helmAgent.K8sAgent.CreateNamespace(helm.KEDANamespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	conf := &helm.InstallChartConfig{
+		Chart:     chart,
+		Name:      helm.KEDAChartName,
+		Namespace: helm.KEDANamespace,
+		Cluster:   cluster,
+		Repo:      c.Repo(),
+		Values:    map[string]interface{}{},
+	}
+
+	_, err = helmAgent.InstallChart(conf, c.Config().DOConf)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error installing KEDA: %s", err.Error()),
+			http.StatusBadRequest,
+		), types.ErrorCodeHelmOperationFailed))
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+type GetKEDAStatusHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetKEDAStatusHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetKEDAStatusHandler {
+	return &GetKEDAStatusHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetKEDAStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	helmAgent, err := c.GetHelmAgent(r, cluster, helm.KEDANamespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	installed, err := helmAgent.K8sAgent.IsKEDAInstalled()
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.KEDAStatusResponse{
+		Installed: installed,
+	}
+
+	// the version is only known if KEDA was installed through Porter
+	if installed {
+		if rel, err := helmAgent.GetRelease(helm.KEDAChartName, 0, false); err == nil && rel.Chart != nil && rel.Chart.Metadata != nil {
+			res.Version = rel.Chart.Metadata.AppVersion
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
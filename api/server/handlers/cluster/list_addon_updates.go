@@ -0,0 +1,63 @@
+package cluster
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/addons"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ListAddonUpdatesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewListAddonUpdatesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListAddonUpdatesHandler {
+	return &ListAddonUpdatesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListAddonUpdatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.ListAddonUpdatesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	clusterAddons, err := c.Repo().Addon().ListAddonsByClusterID(cluster.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if request.Refresh {
+		checker := addons.NewUpdateChecker(c.Repo(), c.Config().ServerConf.ServerURL, c.Config().Logger)
+
+		if err := checker.Check(clusterAddons); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	res := make(types.ListAddonUpdatesResponse, 0)
+
+	for _, addon := range clusterAddons {
+		if addon.IsOutdated() {
+			res = append(res, addon.ToAddonType())
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,166 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// previewNamespacePrefix is the prefix of the namespaces created for preview environments
+const previewNamespacePrefix = "pr-"
+
+type GetOrphanedResourcesHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetOrphanedResourcesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetOrphanedResourcesHandler {
+	return &GetOrphanedResourcesHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetOrphanedResourcesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res, err := getOrphanedResourcesReport(c.Repo(), cluster, agent, helmAgent)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// getOrphanedResourcesReport finds the resources that carry the Porter ownership labels
+// of the cluster but whose release no longer exists, and the preview environment
+// namespaces that no longer have a deployment
+func getOrphanedResourcesReport(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	agent *kubernetes.Agent,
+	helmAgent *helm.Agent,
+) (*types.OrphanedResourcesReport, error) {
+	res := &types.OrphanedResourcesReport{
+		Resources:  make([]*types.OrphanedResource, 0),
+		Namespaces: make([]string, 0),
+	}
+
+	// uninstalled releases whose history was kept count as deleted
+	helmReleases, err := helmAgent.ListReleases("", &types.ReleaseListFilter{
+		StatusFilter: []string{
+			"deployed",
+			"failed",
+			"pending-install",
+			"pending-upgrade",
+			"pending-rollback",
+			"superseded",
+			"uninstalling",
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make(map[string]bool)
+
+	for _, helmRelease := range helmReleases {
+		releases[fmt.Sprintf("%s/%s", helmRelease.Namespace, helmRelease.Name)] = true
+	}
+
+	selector := fmt.Sprintf(
+		"%s=%d,%s=%d,%s",
+		helm.LabelProject,
+		cluster.ProjectID,
+		helm.LabelCluster,
+		cluster.ID,
+		helm.LabelRelease,
+	)
+
+	resources, err := agent.ListLabeledResources(selector)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range resources {
+		release := resource.Labels[helm.LabelRelease]
+
+		if releases[fmt.Sprintf("%s/%s", resource.Namespace, release)] {
+			continue
+		}
+
+		res.Resources = append(res.Resources, &types.OrphanedResource{
+			Kind:      resource.Kind,
+			Namespace: resource.Namespace,
+			Name:      resource.Name,
+			Release:   release,
+		})
+	}
+
+	// preview environment namespaces are only checked if the cluster has preview
+	// environments, since the prefix may otherwise be used by other namespaces
+	envs, err := repo.Environment().ListEnvironments(cluster.ProjectID, cluster.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envs) == 0 {
+		return res, nil
+	}
+
+	namespaces, err := agent.ListNamespaces()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ns := range namespaces.Items {
+		if !strings.HasPrefix(ns.Name, previewNamespacePrefix) || ns.DeletionTimestamp != nil {
+			continue
+		}
+
+		_, err := repo.Environment().ReadDeploymentByCluster(cluster.ProjectID, cluster.ID, ns.Name)
+
+		if err == gorm.ErrRecordNotFound {
+			res.Namespaces = append(res.Namespaces, ns.Name)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
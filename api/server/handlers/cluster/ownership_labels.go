@@ -0,0 +1,114 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+// BackfillOwnershipLabelsHandler adds the Porter ownership labels to the resources of the
+// releases of the cluster that were deployed through Porter before Porter labeled
+// resources
+type BackfillOwnershipLabelsHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewBackfillOwnershipLabelsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *BackfillOwnershipLabelsHandler {
+	return &BackfillOwnershipLabelsHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *BackfillOwnershipLabelsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmReleases, err := helmAgent.ListReleases("", &types.ReleaseListFilter{
+		StatusFilter: []string{
+			"deployed",
+			"failed",
+		},
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.BackfillOwnershipLabelsResponse{
+		Releases: make([]string, 0),
+	}
+
+	namespaceAgents := make(map[string]*helm.Agent)
+
+	for _, helmRelease := range helmReleases {
+		// releases that were not deployed through Porter are not labeled, since the
+		// ownership labels mark a release as managed by Porter
+		_, err := c.Repo().Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		} else if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		// the manifest of a release is updated in the storage of its namespace
+		namespaceAgent, ok := namespaceAgents[helmRelease.Namespace]
+
+		if !ok {
+			namespaceAgent, err = helm.GetAgentFromK8sAgent("secret", helmRelease.Namespace, c.Config().Logger, agent)
+
+			if err != nil {
+				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+				return
+			}
+
+			namespaceAgents[helmRelease.Namespace] = namespaceAgent
+		}
+
+		backfilled, err := namespaceAgent.BackfillOwnershipLabels(cluster, helmRelease)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(
+				fmt.Errorf("could not label release %s in namespace %s: %w", helmRelease.Name, helmRelease.Namespace, err),
+			))
+
+			return
+		}
+
+		if backfilled {
+			res.Releases = append(res.Releases, fmt.Sprintf("%s/%s", helmRelease.Namespace, helmRelease.Name))
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
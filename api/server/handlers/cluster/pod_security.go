@@ -0,0 +1,253 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type GetPodSecurityHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetPodSecurityHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetPodSecurityHandler {
+	return &GetPodSecurityHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetPodSecurityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	preflight, err := getPodSecurityPreflight(c.Repo(), cluster, helmAgent, cluster.GetPodSecurityLevel())
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.PodSecurityResponse{
+		Level:      preflight.Level,
+		Namespaces: preflight.Namespaces,
+	})
+}
+
+type PodSecurityPreflightHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewPodSecurityPreflightHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *PodSecurityPreflightHandler {
+	return &PodSecurityPreflightHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *PodSecurityPreflightHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.PodSecurityPreflightRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res, err := getPodSecurityPreflight(c.Repo(), cluster, helmAgent, request.Level)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type UpdatePodSecurityHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpdatePodSecurityHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdatePodSecurityHandler {
+	return &UpdatePodSecurityHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdatePodSecurityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdatePodSecurityRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	preflight, err := getPodSecurityPreflight(c.Repo(), cluster, helmAgent, request.Level)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if len(preflight.Violations) > 0 && !request.Force {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf(
+				"%d controllers of existing releases violate the %s level, run the preflight check for details or set force to enforce it anyway",
+				len(preflight.Violations),
+				request.Level,
+			),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	// the level is only applied to namespaces that currently have a release managed by
+	// Porter, so namespaces whose releases were all deleted keep their previous level
+	for _, namespace := range preflight.Namespaces {
+		err := agent.SetNamespacePodSecurityLevel(namespace, request.Level)
+
+		if err != nil && err != kubernetes.IsNotFoundError {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	cluster.PodSecurityLevel = request.Level
+
+	cluster, err = c.Repo().Cluster().UpdateCluster(cluster)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.PodSecurityResponse{
+		Level:      cluster.GetPodSecurityLevel(),
+		Namespaces: preflight.Namespaces,
+	})
+}
+
+// getPodSecurityPreflight finds the namespaces of the releases that are managed by
+// Porter in the cluster, and checks the controllers of those releases against a Pod
+// Security Standards level. Releases are managed by Porter if their resources carry the
+// ownership labels of the cluster, or if they were deployed through Porter before Porter
+// labeled resources.
+func getPodSecurityPreflight(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	helmAgent *helm.Agent,
+	level types.PodSecurityLevel,
+) (*types.PodSecurityPreflightResponse, error) {
+	res := &types.PodSecurityPreflightResponse{
+		Level:      level,
+		Namespaces: make([]string, 0),
+		Violations: make([]*types.PodSecurityViolation, 0),
+	}
+
+	helmReleases, err := helmAgent.ListReleases("", &types.ReleaseListFilter{
+		StatusFilter: []string{
+			"deployed",
+			"failed",
+			"pending-install",
+			"pending-upgrade",
+			"pending-rollback",
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make(map[string]bool)
+
+	for _, helmRelease := range helmReleases {
+		if !helm.IsPorterManaged(helmRelease, cluster) {
+			_, err := repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+		}
+
+		namespaces[helmRelease.Namespace] = true
+
+		yamlArr := grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest))
+		objs := grapher.ParseObjs(yamlArr, helmRelease.Namespace)
+
+		violations, err := kubernetes.GetPodSecurityViolations(level, helmRelease.Namespace, helmRelease.Name, objs)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.Violations = append(res.Violations, violations...)
+	}
+
+	for namespace := range namespaces {
+		res.Namespaces = append(res.Namespaces, namespace)
+	}
+
+	sort.Strings(res.Namespaces)
+
+	return res, nil
+}
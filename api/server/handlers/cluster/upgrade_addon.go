@@ -0,0 +1,166 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/addons"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type UpgradeAddonHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpgradeAddonHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpgradeAddonHandler {
+	return &UpgradeAddonHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpgradeAddonHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	addonID, reqErr := requestutils.GetURLParamUint(r, types.URLParamAddonID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	request := &types.UpgradeAddonRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	addon, err := c.Repo().Addon().ReadAddon(cluster.ID, addonID)
+
+	if err == gorm.ErrRecordNotFound {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("addon not found"),
+			http.StatusNotFound,
+		))
+
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	version := request.Version
+
+	if version == "" {
+		version = addon.LatestVersion
+	}
+
+	if version == "" || version == addon.ChartVersion {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("addon %s is already at the latest known version", addon.Name),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	chart, err := loader.LoadChartPublic(addon.RepoURL, addon.ChartName, version)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("chart %s version %s not found", addon.ChartName, version),
+			http.StatusBadRequest,
+		), types.ErrorCodeChartNotFound))
+
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, addon.Namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmRelease, err := helmAgent.GetRelease(addon.Name, 0, false)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s not found in namespace %s", addon.Name, addon.Namespace),
+			http.StatusNotFound,
+		), types.ErrorCodeReleaseNotFound))
+
+		return
+	}
+
+	// the values of the installed release are kept, and only the chart is upgraded. The
+	// load balancer of the ingress controller is pinned to the address that is set for the
+	// cluster.
+	values := helmRelease.Config
+
+	if addons.IsIngressNginxChart(addon.ChartName) {
+		svc, err := helmAgent.K8sAgent.GetIngressNginxService()
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		values = addons.GetIngressLoadBalancerValues(cluster, values, svc)
+	}
+
+	// addons are upgraded through the shared upgrade path of releases, so upgrades of
+	// protected addons get change requests
+	cr, err := release.NewReleaseUpgrader(c.Config()).Upgrade(&release.UpgradeOpts{
+		User:      user,
+		Cluster:   cluster,
+		Namespace: addon.Namespace,
+		Name:      addon.Name,
+		Values:    values,
+		Chart:     chart,
+		Request:   r,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error upgrading addon: %s", err.Error()),
+			http.StatusBadRequest,
+		), types.ErrorCodeHelmOperationFailed))
+
+		return
+	}
+
+	if cr != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+
+		return
+	}
+
+	addon.ChartVersion = chart.Metadata.Version
+
+	addon, err = c.Repo().Addon().UpdateAddon(addon)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, addon.ToAddonType())
+}
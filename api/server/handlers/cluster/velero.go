@@ -0,0 +1,274 @@
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/addons"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type InstallVeleroHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewInstallVeleroHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *InstallVeleroHandler {
+	return &InstallVeleroHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *InstallVeleroHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.InstallVeleroRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, kubernetes.VeleroNamespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	installed, err := helmAgent.K8sAgent.IsResourceServed(kubernetes.VeleroBackupResource)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if installed {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("Velero is already installed in this cluster"),
+			http.StatusConflict,
+		))
+
+		return
+	}
+
+	opts, ok := c.getStorageOpts(w, r, cluster, request)
+
+	if !ok {
+		return
+	}
+
+	chart, err := loader.LoadChartPublic(addons.VeleroChartRepoURL, addons.VeleroChartName, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// create namespace if not exists
+	_, err = helmAgent.K8sAgent.CreateNamespace(kubernetes.VeleroNamespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// access keys are stored in a secret that the chart references, rather than in the
+	// values of the release, which can be read by anyone who can read the release
+	if opts.Credentials != "" {
+		if err := helmAgent.K8sAgent.ApplyVeleroCredentials(opts.Credentials); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		opts.ExistingSecret = kubernetes.VeleroCredentialsSecretName
+	}
+
+	values, err := addons.GetVeleroValues(opts)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	conf := &helm.InstallChartConfig{
+		Chart:     chart,
+		Name:      addons.VeleroChartName,
+		Namespace: kubernetes.VeleroNamespace,
+		Cluster:   cluster,
+		Repo:      c.Repo(),
+		Values:    values,
+	}
+
+	helmRelease, err := helmAgent.InstallChart(conf, c.Config().DOConf)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error installing Velero: %s", err.Error()),
+			http.StatusBadRequest,
+		), types.ErrorCodeHelmOperationFailed))
+
+		return
+	}
+
+	if _, err := addons.TrackAddon(c.Repo(), cluster, helmRelease, addons.VeleroChartRepoURL); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// getStorageOpts returns the object storage of Velero for the provider of the cluster.
+// The credentials of the cloud integration of the cluster are never passed to Velero,
+// since they are not scoped to the bucket: EKS clusters use an IAM role through IRSA and
+// GKE clusters use a GCP service account through workload identity, unless access keys
+// for the bucket are set in the request, while other clusters require the access keys of
+// an S3-compatible bucket.
+func (c *InstallVeleroHandler) getStorageOpts(
+	w http.ResponseWriter,
+	r *http.Request,
+	cluster *models.Cluster,
+	request *types.InstallVeleroRequest,
+) (*addons.VeleroStorageOpts, bool) {
+	opts := &addons.VeleroStorageOpts{
+		Provider: addons.VeleroProviderAWS,
+		Bucket:   request.Bucket,
+		Prefix:   request.Prefix,
+		Region:   request.Region,
+		S3URL:    request.S3URL,
+	}
+
+	hasKeys := request.AccessKeyID != "" && request.SecretAccessKey != ""
+
+	if hasKeys {
+		opts.Credentials = addons.GetAWSCredentials(request.AccessKeyID, request.SecretAccessKey)
+	}
+
+	switch cluster.ToClusterType().Service {
+	case types.EKS:
+		if opts.Region == "" {
+			awsInt, err := c.Repo().AWSIntegration().ReadAWSIntegration(cluster.ProjectID, cluster.AWSIntegrationID)
+
+			if err != nil {
+				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+				return nil, false
+			}
+
+			opts.Region = awsInt.AWSRegion
+		}
+
+		if !hasKeys && request.IAMRoleARN != "" {
+			opts.ServiceAccountAnnotations = map[string]string{
+				"eks.amazonaws.com/role-arn": request.IAMRoleARN,
+			}
+		}
+
+		// volumes can only be snapshotted through the aws plugin if the bucket is in S3
+		opts.Snapshots = opts.S3URL == ""
+	case types.GKE:
+		if hasKeys || request.GCPServiceAccount == "" {
+			break
+		}
+
+		opts.Provider = addons.VeleroProviderGCP
+		opts.GCPServiceAccount = request.GCPServiceAccount
+		opts.ServiceAccountAnnotations = map[string]string{
+			"iam.gke.io/gcp-service-account": request.GCPServiceAccount,
+		}
+		opts.Snapshots = true
+	case types.DOKS:
+		if opts.Region == "" && opts.S3URL == "" {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("the region of the Spaces bucket is required"),
+				http.StatusBadRequest,
+			))
+
+			return nil, false
+		}
+
+		if opts.S3URL == "" {
+			opts.S3URL = fmt.Sprintf("https://%s.digitaloceanspaces.com", opts.Region)
+		}
+	}
+
+	if opts.Credentials == "" && len(opts.ServiceAccountAnnotations) == 0 {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			getMissingVeleroCredentialsError(cluster),
+			http.StatusBadRequest,
+		))
+
+		return nil, false
+	}
+
+	return opts, true
+}
+
+func getMissingVeleroCredentialsError(cluster *models.Cluster) error {
+	switch cluster.ToClusterType().Service {
+	case types.EKS:
+		return fmt.Errorf("an IAM role or access keys with access to the bucket are required")
+	case types.GKE:
+		return fmt.Errorf("a GCP service account or access keys with access to the bucket are required")
+	}
+
+	return fmt.Errorf("access keys for the bucket are required")
+}
+
+type GetVeleroStatusHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetVeleroStatusHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetVeleroStatusHandler {
+	return &GetVeleroStatusHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetVeleroStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	helmAgent, err := c.GetHelmAgent(r, cluster, kubernetes.VeleroNamespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	installed, err := helmAgent.K8sAgent.IsResourceServed(kubernetes.VeleroBackupResource)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.VeleroStatusResponse{
+		Installed: installed,
+	}
+
+	// the version is only known if Velero was installed through Porter
+	if installed {
+		if rel, err := helmAgent.GetRelease(addons.VeleroChartName, 0, false); err == nil && rel.Chart != nil && rel.Chart.Metadata != nil {
+			res.Version = rel.Chart.Metadata.AppVersion
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,88 @@
+package environment
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+// CheckDeploymentApprovalHandler is called by the preview workflow before it checks out the
+// code of a pull request. It fails if the pull request is from a fork and its commit was
+// not approved by a maintainer, so that the code of forks does not run with the secrets of
+// the repository until it is approved.
+type CheckDeploymentApprovalHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCheckDeploymentApprovalHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CheckDeploymentApprovalHandler {
+	return &CheckDeploymentApprovalHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *CheckDeploymentApprovalHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ga, _ := r.Context().Value(types.GitInstallationScope).(*integrations.GithubAppInstallation)
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	owner, name, ok := gitinstallation.GetOwnerAndNameParams(c, w, r)
+
+	if !ok {
+		return
+	}
+
+	request := &types.CheckDeploymentApprovalRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	env, err := c.Repo().Environment().ReadEnvironment(project.ID, cluster.ID, uint(ga.InstallationID), owner, name)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	client, err := getGithubClientFromEnvironment(c.Config(), env)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	_, approved, err := checkForkApproval(c.Config(), client, env, &models.Deployment{
+		EnvironmentID: env.ID,
+		Namespace:     request.Namespace,
+		PullRequestID: request.PullRequestID,
+		RepoOwner:     owner,
+		RepoName:      name,
+		CommitSHA:     request.CommitSHA,
+		GHActionID:    request.ActionID,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if !approved {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("commit %s is pending the approval of a maintainer", request.CommitSHA),
+			http.StatusForbidden,
+		), types.ErrorCodeDeployPendingApproval))
+		return
+	}
+}
@@ -1,6 +1,7 @@
 package environment
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -51,6 +52,20 @@ func (c *CreateEnvironmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	routingMode := request.RoutingMode
+
+	if routingMode == "" {
+		routingMode = types.PreviewRoutingSubdomain
+	}
+
+	if routingMode == types.PreviewRoutingPath && request.PathRoutingHost == "" {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("path_routing_host is required for path-based routing"),
+			http.StatusBadRequest,
+		))
+		return
+	}
+
 	env, err := c.Repo().Environment().CreateEnvironment(&models.Environment{
 		ProjectID:         project.ID,
 		ClusterID:         cluster.ID,
@@ -58,6 +73,10 @@ func (c *CreateEnvironmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		Name:              request.Name,
 		GitRepoOwner:      owner,
 		GitRepoName:       name,
+
+		ForkApprovalRequired: request.ForkApprovalRequired,
+		RoutingMode:          routingMode,
+		PathRoutingHost:      request.PathRoutingHost,
 	})
 
 	if err != nil {
@@ -98,6 +117,8 @@ func (c *CreateEnvironmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		ClusterID:         cluster.ID,
 		GitInstallationID: uint(ga.InstallationID),
 		EnvironmentName:   request.Name,
+
+		ForkApprovalRequired: request.ForkApprovalRequired,
 	})
 
 	if err != nil {
@@ -109,6 +130,10 @@ func (c *CreateEnvironmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 }
 
 func getGithubClientFromEnvironment(config *config.Config, env *models.Environment) (*github.Client, error) {
+	if config.GithubAppTokens != nil {
+		return config.GithubAppTokens.Client(int64(env.GitInstallationID)), nil
+	}
+
 	// get the github app client
 	ghAppId, err := strconv.Atoi(config.ServerConf.GithubAppID)
 
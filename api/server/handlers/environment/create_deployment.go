@@ -2,6 +2,7 @@ package environment
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -13,8 +14,10 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/ci/actions"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/models/integrations"
+	"gorm.io/gorm"
 )
 
 type CreateDeploymentHandler struct {
@@ -66,6 +69,31 @@ func (c *CreateDeploymentHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	depl := &models.Deployment{
+		EnvironmentID: env.ID,
+		Namespace:     request.Namespace,
+		PullRequestID: request.PullRequestID,
+		RepoOwner:     request.GitHubMetadata.RepoOwner,
+		RepoName:      request.GitHubMetadata.RepoName,
+		PRName:        request.GitHubMetadata.PRName,
+		CommitSHA:     request.GitHubMetadata.CommitSHA,
+		GHActionID:    request.ActionID,
+	}
+
+	// pull requests from forks are only deployed once a maintainer approves their commit,
+	// if the environment requires it
+	depl, approved, err := checkForkApproval(c.Config(), client, env, depl)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if !approved {
+		c.WriteResult(w, r, depl.ToDeploymentType())
+		return
+	}
+
 	ghDeployment, err := createDeployment(client, env, request.CreateGHDeploymentRequest)
 
 	if err != nil {
@@ -73,18 +101,15 @@ func (c *CreateDeploymentHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// create the deployment
-	depl, err := c.Repo().Environment().CreateDeployment(&models.Deployment{
-		EnvironmentID:  env.ID,
-		Namespace:      request.Namespace,
-		Status:         types.DeploymentStatusCreating,
-		PullRequestID:  request.PullRequestID,
-		GHDeploymentID: ghDeployment.GetID(),
-		RepoOwner:      request.GitHubMetadata.RepoOwner,
-		RepoName:       request.GitHubMetadata.RepoName,
-		PRName:         request.GitHubMetadata.PRName,
-		CommitSHA:      request.GitHubMetadata.CommitSHA,
-	})
+	depl.Status = types.DeploymentStatusCreating
+	depl.GHDeploymentID = ghDeployment.GetID()
+
+	// create the deployment, or update it if it was approved
+	if depl.ID != 0 {
+		depl, err = c.Repo().Environment().UpdateDeployment(depl)
+	} else {
+		depl, err = c.Repo().Environment().CreateDeployment(depl)
+	}
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -159,3 +184,103 @@ func createDeployment(client *github.Client, env *models.Environment, request *t
 
 	return deployment, nil
 }
+
+// checkForkApproval returns true if a deployment may run the commit of its pull request.
+// If the environment requires it, each commit of a pull request from a fork needs to be
+// approved by a maintainer, and deployments of commits that were not approved are stored
+// as pending approval instead.
+func checkForkApproval(
+	config *config.Config,
+	client *github.Client,
+	env *models.Environment,
+	depl *models.Deployment,
+) (*models.Deployment, bool, error) {
+	if !env.ForkApprovalRequired {
+		return depl, true, nil
+	}
+
+	isFork, err := actions.IsForkPullRequest(client, env, depl.PullRequestID)
+
+	if err != nil {
+		return nil, false, err
+	} else if !isFork {
+		return depl, true, nil
+	}
+
+	depl.IsFork = true
+
+	existing, err := config.Repo.Environment().ReadDeployment(env.ID, depl.Namespace)
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	} else if err != nil {
+		existing = nil
+	}
+
+	if actions.IsDeploymentApproved(existing, depl.CommitSHA) {
+		depl.Model = existing.Model
+		depl.ApprovedBy = existing.ApprovedBy
+		depl.ApprovedCommitSHA = existing.ApprovedCommitSHA
+
+		return depl, true, nil
+	}
+
+	depl, err = requestDeploymentApproval(config, client, env, existing, depl)
+
+	return depl, false, err
+}
+
+// requestDeploymentApproval stores the deployment of a pull request from a fork as pending
+// the approval of its commit, and comments on the pull request how it can be approved. The
+// comment is posted for the first push to the pull request, and for the first push after
+// an earlier commit was approved.
+func requestDeploymentApproval(
+	config *config.Config,
+	client *github.Client,
+	env *models.Environment,
+	existing, depl *models.Deployment,
+) (*models.Deployment, error) {
+	var err error
+
+	comment := existing == nil || existing.ApprovedBy != ""
+
+	if existing != nil {
+		existing.Status = types.DeploymentStatusPendingApproval
+		existing.IsFork = true
+		existing.CommitSHA = depl.CommitSHA
+		existing.ApprovedBy = ""
+		existing.ApprovedCommitSHA = ""
+
+		if depl.GHActionID != 0 {
+			existing.GHActionID = depl.GHActionID
+		}
+
+		depl, err = config.Repo.Environment().UpdateDeployment(existing)
+	} else {
+		depl.Status = types.DeploymentStatusPendingApproval
+		depl, err = config.Repo.Environment().CreateDeployment(depl)
+	}
+
+	if err != nil || !comment {
+		return depl, err
+	}
+
+	body := fmt.Sprintf(
+		"This pull request is from a fork, so its preview environment needs the approval of a maintainer "+
+			"for commit %s. Maintainers can approve it by commenting `%s`, or from the Porter dashboard.",
+		depl.CommitSHA,
+		types.PreviewApproveCommand,
+	)
+
+	_, _, err = client.Issues.CreateComment(
+		context.Background(),
+		env.GitRepoOwner,
+		env.GitRepoName,
+		int(depl.PullRequestID),
+		&github.IssueComment{
+			Body: github.String(body),
+		},
+	)
+
+	return depl, err
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
+	"github.com/porter-dev/porter/api/server/handlers/release"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
@@ -49,6 +50,28 @@ func (c *DeleteEnvironmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	depls, err := c.Repo().Environment().ListDeployments(env.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the namespaces of the deployments are deleted along with the environment, so no
+	// release in them may have deletion protection
+	if len(depls) > 0 {
+		namespaces := make([]string, 0, len(depls))
+
+		for _, depl := range depls {
+			namespaces = append(namespaces, depl.Namespace)
+		}
+
+		if apiErr := release.CheckNamespaceDeletionProtection(c.Config(), cluster, namespaces...); apiErr != nil {
+			c.HandleAPIError(w, r, apiErr)
+			return
+		}
+	}
+
 	// delete Github actions files from the repo
 	client, err := getGithubClientFromEnvironment(c.Config(), env)
 
@@ -81,13 +104,6 @@ func (c *DeleteEnvironmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	depls, err := c.Repo().Environment().ListDeployments(env.ID)
-
-	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-		return
-	}
-
 	for _, depl := range depls {
 		agent.DeleteNamespace(depl.Namespace)
 	}
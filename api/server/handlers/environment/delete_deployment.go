@@ -9,6 +9,7 @@ import (
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
+	"github.com/porter-dev/porter/api/server/handlers/release"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
@@ -76,6 +77,11 @@ func (c *DeleteDeploymentHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 
 	// make sure we don't delete default or kube-system by checking for prefix, for now
 	if strings.Contains(depl.Namespace, "pr-") {
+		if apiErr := release.CheckNamespaceDeletionProtection(c.Config(), cluster, depl.Namespace); apiErr != nil {
+			c.HandleAPIError(w, r, apiErr)
+			return
+		}
+
 		err = agent.DeleteNamespace(depl.Namespace)
 
 		if err != nil {
@@ -1,6 +1,7 @@
 package environment
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/porter-dev/porter/api/server/authz"
@@ -10,6 +11,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/ci/actions"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/models/integrations"
 )
@@ -63,7 +65,6 @@ func (c *UpdateDeploymentHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// create deployment on GitHub API
 	client, err := getGithubClientFromEnvironment(c.Config(), env)
 
 	if err != nil {
@@ -71,6 +72,44 @@ func (c *UpdateDeploymentHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// deployments of pull requests from forks are only updated to commits that were
+	// approved, and new commits need to be approved again
+	if env.ForkApprovalRequired && depl.IsFork && !actions.IsDeploymentApproved(depl, request.CommitSHA) {
+		pending := &models.Deployment{CommitSHA: request.CommitSHA}
+
+		if request.CreateGHDeploymentRequest != nil {
+			pending.GHActionID = request.ActionID
+		}
+
+		if _, err := requestDeploymentApproval(c.Config(), client, env, depl, pending); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("commit %s is pending the approval of a maintainer", request.CommitSHA),
+			http.StatusForbidden,
+		), types.ErrorCodeDeployPendingApproval))
+		return
+	}
+
+	if depl.Status == types.DeploymentStatusPendingApproval {
+		// the namespace is only created once the deployment is approved
+		agent, err := c.GetAgent(r, cluster, "")
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		if _, err := agent.CreateNamespace(depl.Namespace); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		depl.Status = types.DeploymentStatusCreating
+	}
+
 	ghDeployment, err := createDeployment(client, env, request.CreateGHDeploymentRequest)
 
 	if err != nil {
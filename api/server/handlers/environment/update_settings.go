@@ -0,0 +1,133 @@
+package environment
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/auth/token"
+	"github.com/porter-dev/porter/internal/integrations/ci/actions"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+type UpdateEnvironmentSettingsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateEnvironmentSettingsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateEnvironmentSettingsHandler {
+	return &UpdateEnvironmentSettingsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateEnvironmentSettingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ga, _ := r.Context().Value(types.GitInstallationScope).(*integrations.GithubAppInstallation)
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	owner, name, ok := gitinstallation.GetOwnerAndNameParams(c, w, r)
+
+	if !ok {
+		return
+	}
+
+	request := &types.UpdateEnvironmentSettingsRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	env, err := c.Repo().Environment().ReadEnvironment(project.ID, cluster.ID, uint(ga.InstallationID), owner, name)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the workflow files only need to be committed again if the trigger changes
+	rewriteWorkflows := false
+
+	if request.ForkApprovalRequired != nil && *request.ForkApprovalRequired != env.ForkApprovalRequired {
+		env.ForkApprovalRequired = *request.ForkApprovalRequired
+		rewriteWorkflows = true
+	}
+
+	if request.RoutingMode != "" {
+		env.RoutingMode = request.RoutingMode
+	}
+
+	if request.PathRoutingHost != nil {
+		env.PathRoutingHost = *request.PathRoutingHost
+	}
+
+	// routing changes only apply to releases that are created afterwards
+	if env.RoutingMode == types.PreviewRoutingPath && env.PathRoutingHost == "" {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("path_routing_host is required for path-based routing"),
+			http.StatusBadRequest,
+		))
+		return
+	}
+
+	env, err = c.Repo().Environment().UpdateEnvironment(env)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if rewriteWorkflows {
+		client, err := getGithubClientFromEnvironment(c.Config(), env)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		// generate porter jwt token
+		jwt, err := token.GetTokenForAPI(user.ID, project.ID)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		encoded, err := jwt.EncodeToken(c.Config().TokenConf)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		err = actions.SetupEnv(&actions.EnvOpts{
+			Client:               client,
+			ServerURL:            c.Config().ServerConf.ServerURL,
+			PorterToken:          encoded,
+			GitRepoOwner:         owner,
+			GitRepoName:          name,
+			ProjectID:            project.ID,
+			ClusterID:            cluster.ID,
+			GitInstallationID:    uint(ga.InstallationID),
+			EnvironmentName:      env.Name,
+			ForkApprovalRequired: env.ForkApprovalRequired,
+		})
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	c.WriteResult(w, r, env.ToEnvironmentType())
+}
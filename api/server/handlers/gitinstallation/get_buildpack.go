@@ -1,19 +1,23 @@
 package gitinstallation
 
 import (
-	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
 
-	"github.com/google/go-github/v41/github"
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/imagemirror"
 	"github.com/porter-dev/porter/internal/integrations/buildpacks"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"gorm.io/gorm"
 )
 
 func initBuilderInfo() map[string]*buildpacks.BuilderInfo {
@@ -21,13 +25,13 @@ func initBuilderInfo() map[string]*buildpacks.BuilderInfo {
 	builders[buildpacks.PaketoBuilder] = &buildpacks.BuilderInfo{
 		Name: "Paketo",
 		Builders: []string{
-			"paketobuildpacks/builder:full",
+			imagemirror.DefaultPaketoBuilder,
 		},
 	}
 	builders[buildpacks.HerokuBuilder] = &buildpacks.BuilderInfo{
 		Name: "Heroku",
 		Builders: []string{
-			"heroku/buildpacks:20",
+			imagemirror.DefaultHerokuBuilder,
 			"heroku/buildpacks:18",
 		},
 	}
@@ -37,6 +41,9 @@ func initBuilderInfo() map[string]*buildpacks.BuilderInfo {
 type GithubGetBuildpackHandler struct {
 	handlers.PorterHandlerReadWriter
 	authz.KubernetesAgentGetter
+
+	// force re-runs detection even if a result is stored for the commit
+	force bool
 }
 
 func NewGithubGetBuildpackHandler(
@@ -49,7 +56,23 @@ func NewGithubGetBuildpackHandler(
 	}
 }
 
+// NewGithubRedetectBuildpackHandler returns a handler that runs buildpack detection on
+// the latest commit of the branch and overwrites the stored result
+func NewGithubRedetectBuildpackHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GithubGetBuildpackHandler {
+	return &GithubGetBuildpackHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		force:                   true,
+	}
+}
+
 func (c *GithubGetBuildpackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	ga, _ := r.Context().Value(types.GitInstallationScope).(*integrations.GithubAppInstallation)
+
 	request := &types.GetBuildpackRequest{}
 
 	ok := c.DecodeAndValidate(w, r, request)
@@ -77,41 +100,132 @@ func (c *GithubGetBuildpackHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	repoContentOptions := github.RepositoryContentGetOptions{}
-	repoContentOptions.Ref = branch
-	_, directoryContents, _, err := client.Repositories.GetContents(
-		context.Background(),
-		owner,
-		name,
-		request.Dir,
-		&repoContentOptions,
-	)
+	// the directory is listed once for all runtimes, and the listing is cached by the
+	// commit of the branch
+	contents, err := buildpacks.GetRepoContents(r.Context(), client, &buildpacks.GetRepoContentsOpts{
+		Owner:     owner,
+		Name:      name,
+		Path:      request.Dir,
+		Ref:       branch,
+		Recursive: true,
+	})
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
 	}
 
-	builderInfoMap := initBuilderInfo()
+	if !c.force {
+		detection, err := c.Repo().BuildpackDetection().ReadBuildpackDetectionByCommit(
+			proj.ID, uint(ga.InstallationID), owner, name, contents.Path, contents.SHA,
+		)
+
+		if err == nil {
+			var builders []*buildpacks.BuilderInfo
+
+			if err := json.Unmarshal(detection.Builders, &builders); err == nil {
+				c.WriteResult(w, r, mirrorBuilders(c.Config().ImageMirror, builders))
+				return
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	builders, err := detectBuildpacks(contents)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	buildersJSON, err := json.Marshal(builders)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	runtime, suggestedBuilder := suggestBuilder(builders)
+
+	_, err = c.Repo().BuildpackDetection().CreateBuildpackDetection(&models.BuildpackDetection{
+		ProjectID:         proj.ID,
+		GitInstallationID: uint(ga.InstallationID),
+		GitRepoOwner:      owner,
+		GitRepoName:       name,
+		GitBranch:         branch,
+		CommitSHA:         contents.SHA,
+		FolderPath:        contents.Path,
+		Runtime:           runtime,
+		SuggestedBuilder:  suggestedBuilder,
+		Builders:          buildersJSON,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, mirrorBuilders(c.Config().ImageMirror, builders))
+}
+
+// mirrorBuilders replaces the builder images of detected builders with the images of the
+// image mirror of the server. Detection results are stored with the public images, so
+// that they remain valid if the mirror changes.
+func mirrorBuilders(imageMirror *imagemirror.Mirror, builders []*buildpacks.BuilderInfo) []*buildpacks.BuilderInfo {
+	for _, builder := range builders {
+		for i, image := range builder.Builders {
+			builder.Builders[i] = imageMirror.Image(image)
+		}
+	}
+
+	return builders
+}
+
+func detectBuildpacks(contents *buildpacks.RepoContents) ([]*buildpacks.BuilderInfo, error) {
+	// each runtime detects into its own builder infos, which are merged in the order of
+	// the runtimes so that the result is the same for every detection of a commit
+	paketoResults := make([]*buildpacks.BuilderInfo, len(buildpacks.Runtimes))
+	herokuResults := make([]*buildpacks.BuilderInfo, len(buildpacks.Runtimes))
+	panics := make([]bool, len(buildpacks.Runtimes))
+
 	var wg sync.WaitGroup
 	wg.Add(len(buildpacks.Runtimes))
 	for i := range buildpacks.Runtimes {
+		paketoResults[i] = &buildpacks.BuilderInfo{}
+		herokuResults[i] = &buildpacks.BuilderInfo{}
+
 		go func(idx int) {
+			defer wg.Done()
 			defer func() {
 				if rec := recover(); rec != nil {
-					c.HandleAPIError(w, r, apierrors.NewErrInternal(fmt.Errorf("panic detected in runtime detection")))
-					return
+					panics[idx] = true
 				}
 			}()
-			buildpacks.Runtimes[idx].Detect(
-				client, directoryContents, owner, name, request.Dir, repoContentOptions,
-				builderInfoMap[buildpacks.PaketoBuilder], builderInfoMap[buildpacks.HerokuBuilder],
-			)
-			wg.Done()
+			buildpacks.Runtimes[idx].Detect(contents, paketoResults[idx], herokuResults[idx])
 		}(i)
 	}
 	wg.Wait()
 
+	builderInfoMap := initBuilderInfo()
+
+	for i := range buildpacks.Runtimes {
+		if panics[i] {
+			return nil, fmt.Errorf("panic detected in runtime detection")
+		}
+
+		paketo := builderInfoMap[buildpacks.PaketoBuilder]
+		paketo.Detected = append(paketo.Detected, paketoResults[i].Detected...)
+		paketo.Others = append(paketo.Others, paketoResults[i].Others...)
+		paketo.Fallbacks = append(paketo.Fallbacks, paketoResults[i].Fallbacks...)
+
+		heroku := builderInfoMap[buildpacks.HerokuBuilder]
+		heroku.Detected = append(heroku.Detected, herokuResults[i].Detected...)
+		heroku.Others = append(heroku.Others, herokuResults[i].Others...)
+		heroku.Fallbacks = append(heroku.Fallbacks, herokuResults[i].Fallbacks...)
+	}
+
 	// FIXME: add Java buildpacks
 	builderInfoMap[buildpacks.PaketoBuilder].Others = append(builderInfoMap[buildpacks.PaketoBuilder].Others,
 		buildpacks.BuildpackInfo{
@@ -124,9 +238,46 @@ func (c *GithubGetBuildpackHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 			Buildpack: "heroku/java",
 		})
 
-	var builders []*buildpacks.BuilderInfo
-	for _, v := range builderInfoMap {
-		builders = append(builders, v)
+	// the Procfile applies to every builder, so it is read once for all builders
+	for _, entry := range contents.Entries {
+		if entry.GetName() != "Procfile" || entry.GetType() != "file" {
+			continue
+		}
+
+		procfile, err := contents.ReadFile("Procfile")
+
+		if err != nil {
+			return nil, fmt.Errorf("error fetching contents of Procfile: %v", err)
+		}
+
+		processes := buildpacks.ParseProcfile(procfile)
+
+		builderInfoMap[buildpacks.PaketoBuilder].Processes = processes
+		builderInfoMap[buildpacks.HerokuBuilder].Processes = processes
 	}
-	c.WriteResult(w, r, builders)
+
+	return []*buildpacks.BuilderInfo{
+		builderInfoMap[buildpacks.PaketoBuilder],
+		builderInfoMap[buildpacks.HerokuBuilder],
+	}, nil
+}
+
+// suggestBuilder returns the first runtime that was detected and the builder stack that
+// is suggested for it. The heroku builder is suggested, as it is the default builder of
+// the dashboard. Runtimes without a buildpack have no suggested builder, as they are built
+// from a Dockerfile.
+func suggestBuilder(builders []*buildpacks.BuilderInfo) (string, string) {
+	for _, builder := range builders {
+		if builder.Name != "Heroku" {
+			continue
+		}
+
+		if len(builder.Detected) > 0 {
+			return builder.Detected[0].Name, builder.Builders[0]
+		} else if len(builder.Fallbacks) > 0 {
+			return builder.Fallbacks[0].Name, ""
+		}
+	}
+
+	return "", ""
 }
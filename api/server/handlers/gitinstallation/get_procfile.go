@@ -3,8 +3,6 @@ package gitinstallation
 import (
 	"context"
 	"net/http"
-	"regexp"
-	"strings"
 
 	"github.com/google/go-github/v41/github"
 	"github.com/porter-dev/porter/api/server/authz"
@@ -13,10 +11,9 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/buildpacks"
 )
 
-var procfileRegex = regexp.MustCompile("^([A-Za-z0-9_]+):\\s*(.+)$")
-
 type GithubGetProcfileHandler struct {
 	handlers.PorterHandlerReadWriter
 	authz.KubernetesAgentGetter
@@ -82,14 +79,5 @@ func (c *GithubGetProcfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	parsedContents := make(types.GetProcfileResponse)
-
-	// parse the procfile information
-	for _, line := range strings.Split(fileData, "\n") {
-		if matches := procfileRegex.FindStringSubmatch(line); matches != nil {
-			parsedContents[matches[1]] = matches[2]
-		}
-	}
-
-	c.WriteResult(w, r, parsedContents)
+	c.WriteResult(w, r, types.GetProcfileResponse(buildpacks.ParseProcfile(fileData)))
 }
@@ -62,18 +62,7 @@ func GetGithubAppClientFromRequest(config *config.Config, r *http.Request) (*git
 	// get installation id from context
 	ga, _ := r.Context().Value(types.GitInstallationScope).(*integrations.GithubAppInstallation)
 
-	itr, err := ghinstallation.NewKeyFromFile(
-		http.DefaultTransport,
-		config.GithubAppConf.AppID,
-		ga.InstallationID,
-		config.GithubAppConf.SecretPath,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return github.NewClient(&http.Client{Transport: itr}), nil
+	return getGithubAppClient(config, ga.InstallationID)
 }
 
 type GithubAppPermissions struct {
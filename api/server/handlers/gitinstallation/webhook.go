@@ -1,6 +1,7 @@
 package gitinstallation
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -8,12 +9,15 @@ import (
 	"net/http"
 	"strings"
 
+	ghinstallation "github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/google/go-github/v41/github"
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/ci/actions"
 	"gorm.io/gorm"
 
 	ints "github.com/porter-dev/porter/internal/models/integrations"
@@ -87,7 +91,107 @@ func (c *GithubAppWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 				return
 			}
 		}
+	case *github.IssueCommentEvent:
+		if e.GetAction() == "created" && e.GetIssue().IsPullRequest() &&
+			strings.TrimSpace(e.GetComment().GetBody()) == types.PreviewApproveCommand {
+			if err := c.approvePreviewDeployments(e); err != nil {
+				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+				return
+			}
+		}
+	}
+}
+
+// approvePreviewDeployments approves the pending preview deployments of a pull request
+// from a fork, if the comment was posted by a user with write access to the repository
+func (c *GithubAppWebhookHandler) approvePreviewDeployments(e *github.IssueCommentEvent) error {
+	owner := e.GetRepo().GetOwner().GetLogin()
+	name := e.GetRepo().GetName()
+
+	envs, err := c.Repo().Environment().ListEnvironmentsByRepo(uint(e.GetInstallation().GetID()), owner, name)
+
+	if err != nil {
+		return err
+	}
+
+	var client *github.Client
+
+	for _, env := range envs {
+		if !env.ForkApprovalRequired {
+			continue
+		}
+
+		depls, err := c.Repo().Environment().ListDeployments(env.ID, string(types.DeploymentStatusPendingApproval))
+
+		if err != nil {
+			return err
+		}
+
+		for _, depl := range depls {
+			if depl.PullRequestID != uint(e.GetIssue().GetNumber()) {
+				continue
+			}
+
+			if client == nil {
+				client, err = getGithubAppClient(c.Config(), e.GetInstallation().GetID())
+
+				if err != nil {
+					return err
+				}
+
+				permission, _, err := client.Repositories.GetPermissionLevel(
+					context.Background(),
+					owner,
+					name,
+					e.GetComment().GetUser().GetLogin(),
+				)
+
+				if err != nil {
+					return err
+				}
+
+				// comments of users without write access are ignored
+				if p := permission.GetPermission(); p != "admin" && p != "write" {
+					return nil
+				}
+			}
+
+			_, err = actions.ApprovePreviewDeployment(&actions.ApprovePreviewDeploymentOpts{
+				Client:     client,
+				Repo:       c.Repo(),
+				Env:        env,
+				Deployment: depl,
+				Approver:   e.GetComment().GetUser().GetLogin(),
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getGithubAppClient returns a client that authenticates as an installation, using the
+// cached token of the installation if the token cache is configured
+func getGithubAppClient(config *config.Config, installationID int64) (*github.Client, error) {
+	if config.GithubAppTokens != nil {
+		return config.GithubAppTokens.Client(installationID), nil
 	}
+
+	itr, err := ghinstallation.NewKeyFromFile(
+		http.DefaultTransport,
+		config.GithubAppConf.AppID,
+		installationID,
+		config.GithubAppConf.SecretPath,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return github.NewClient(&http.Client{Transport: itr}), nil
 }
 
 // verifySignature verifies a signature based on hmac protocal
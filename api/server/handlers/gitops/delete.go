@@ -0,0 +1,50 @@
+package gitops
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type DeleteGitOpsConfigHandler struct {
+	handlers.PorterHandler
+}
+
+func NewDeleteGitOpsConfigHandler(
+	config *config.Config,
+) *DeleteGitOpsConfigHandler {
+	return &DeleteGitOpsConfigHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+// ServeHTTP disables gitops for the project. Files that were already mirrored to the git
+// repository are not removed.
+func (c *DeleteGitOpsConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	conf, err := c.Repo().GitOpsConfig().ReadGitOpsConfig(proj.ID)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("gitops is not configured for this project"),
+			http.StatusNotFound,
+		))
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Repo().GitOpsConfig().DeleteGitOpsConfig(conf); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+}
@@ -0,0 +1,87 @@
+package gitops
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	"gorm.io/gorm"
+)
+
+type UpdateGitOpsConfigHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateGitOpsConfigHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateGitOpsConfigHandler {
+	return &UpdateGitOpsConfigHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateGitOpsConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ga, _ := r.Context().Value(types.GitInstallationScope).(*integrations.GithubAppInstallation)
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	owner, name, ok := gitinstallation.GetOwnerAndNameParams(c, w, r)
+
+	if !ok {
+		return
+	}
+
+	request := &types.UpdateGitOpsConfigRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	conf, err := c.Repo().GitOpsConfig().ReadGitOpsConfig(proj.ID)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		conf = &models.GitOpsConfig{
+			ProjectID: proj.ID,
+		}
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	path := strings.Trim(request.Path, "/")
+
+	if path == "" {
+		path = types.DefaultGitOpsPath
+	}
+
+	// the last reconciled commit is reset when the repository changes, so that the new
+	// repository is reconciled
+	if conf.GitRepoOwner != owner || conf.GitRepoName != name || conf.Branch != request.Branch || conf.Path != path {
+		conf.LastReconciledSHA = ""
+	}
+
+	conf.GitInstallationID = uint(ga.InstallationID)
+	conf.GitRepoOwner = owner
+	conf.GitRepoName = name
+	conf.Branch = request.Branch
+	conf.Path = path
+	conf.ReconcileEnabled = request.ReconcileEnabled
+
+	conf, err = c.Repo().GitOpsConfig().UpdateGitOpsConfig(conf)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, conf.ToGitOpsConfigType())
+}
@@ -123,9 +123,12 @@ func NewUnavailable(config *config.Config, handlerID string) *Unavailable {
 }
 
 func (u *Unavailable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	apierrors.HandleAPIError(u.config, w, r, apierrors.NewErrPassThroughToClient(
-		fmt.Errorf("%s not available in community edition", u.handlerID),
-		http.StatusBadRequest,
+	apierrors.HandleAPIError(u.config, w, r, apierrors.WithCode(
+		apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("%s not available in community edition", u.handlerID),
+			http.StatusBadRequest,
+		),
+		types.ErrorCodeUnavailableInCE,
 	), true, apierrors.ErrorOpts{
 		Code: types.ErrCodeUnavailable,
 	})
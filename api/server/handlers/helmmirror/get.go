@@ -0,0 +1,83 @@
+package helmmirror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/mirror"
+)
+
+type GetMirrorFileHandler struct {
+	handlers.PorterHandler
+}
+
+func NewGetMirrorFileHandler(
+	config *config.Config,
+) *GetMirrorFileHandler {
+	return &GetMirrorFileHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+// ServeHTTP serves the index or a chart archive of a mirrored repo, so that the mirror
+// can be used as a Helm repo. Archives that do not match the digest in the mirrored
+// index are not served.
+func (c *GetMirrorFileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	repoName, reqErr := requestutils.GetURLParamString(r, types.URLParamHelmMirrorRepo)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	filename, reqErr := requestutils.GetURLParamString(r, types.URLParamHelmMirrorFile)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	helmMirror := c.Config().HelmMirror
+
+	if helmMirror == nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("no chart repos are mirrored"),
+			http.StatusNotFound,
+		))
+
+		return
+	}
+
+	var data []byte
+	var err error
+
+	contentType := "application/gzip"
+
+	if filename == "index.yaml" {
+		contentType = "application/x-yaml"
+		data, err = helmMirror.GetIndex(repoName)
+	} else {
+		data, err = helmMirror.GetChart(repoName, filename)
+	}
+
+	if errors.Is(err, mirror.ErrNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("%s not found in mirrored repo %s", filename, repoName),
+			http.StatusNotFound,
+		))
+
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(fmt.Errorf("error reading %s of mirrored repo %s: %w", filename, repoName, err)))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+}
@@ -2,6 +2,8 @@ package infra
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/porter-dev/porter/api/server/handlers"
@@ -19,6 +21,8 @@ import (
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/do/doks"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/gke"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
 )
 
 type InfraDeleteHandler struct {
@@ -69,10 +73,14 @@ func (c *InfraDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		err = destroyGKE(c.Config(), infra)
 	case types.InfraRDS:
 		err = destroyRDS(c.Config(), infra)
+	case types.InfraS3, types.InfraGCS, types.InfraSQS, types.InfraPubSub:
+		err = destroyProvisionedResource(c.Config(), infra)
+	case types.InfraRDSNetwork, types.InfraCloudSQLNetwork:
+		err = destroyPrivateNetwork(c.Config(), infra)
 	}
 
 	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		c.HandleAPIError(w, r, provisionError(err))
 		return
 	}
 }
@@ -112,7 +120,7 @@ func destroyECR(conf *config.Config, infra *models.Infra) error {
 
 	opts.OperationKind = provisioner.Destroy
 
-	err = conf.ProvisionerAgent.Provision(opts)
+	err = provision.Provision(conf, opts)
 
 	return err
 }
@@ -145,15 +153,10 @@ func destroyEKS(conf *config.Config, infra *models.Infra) error {
 
 	opts.CredentialExchange.VaultToken = vaultToken
 
-	opts.EKS = &eks.Conf{
-		AWSRegion:   awsInt.AWSRegion,
-		ClusterName: lastAppliedEKS.EKSName,
-		MachineType: lastAppliedEKS.MachineType,
-		IssuerEmail: lastAppliedEKS.IssuerEmail,
-	}
+	opts.EKS = eks.NewConf(awsInt.AWSRegion, lastAppliedEKS)
 	opts.OperationKind = provisioner.Destroy
 
-	err = conf.ProvisionerAgent.Provision(opts)
+	err = provision.Provision(conf, opts)
 
 	return err
 }
@@ -222,11 +225,55 @@ func destroyRDS(conf *config.Config, infra *models.Infra) error {
 
 	opts.OperationKind = provisioner.Destroy
 
-	err = conf.ProvisionerAgent.Provision(opts)
+	err = provision.Provision(conf, opts)
 
 	return err
 }
 
+// destroyProvisionedResource destroys the bucket or the queue of an infra
+func destroyProvisionedResource(conf *config.Config, infra *models.Infra) error {
+	resourceRepo, ok := repository.GetProvisionedResourceRepository(conf.Repo, infra.Kind)
+
+	if !ok {
+		return fmt.Errorf("infra of kind %s is not a bucket or a queue", infra.Kind)
+	}
+
+	// the resource is only created once the infra is applied, so it may not exist yet
+	resource, err := resourceRepo.ReadProvisionedResourceByInfraID(infra.ProjectID, infra.ID)
+
+	if err == nil {
+		resource.GetProvisionedResource().Status = "destroying"
+
+		if _, err := resourceRepo.UpdateProvisionedResource(resource); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	opts, err := provision.GetProvisionedResourceProvisionerOpts(conf, infra)
+
+	if err != nil {
+		return err
+	}
+
+	opts.OperationKind = provisioner.Destroy
+
+	return provision.Provision(conf, opts)
+}
+
+func destroyPrivateNetwork(conf *config.Config, infra *models.Infra) error {
+	opts, err := provision.GetPrivateNetworkProvisionerOpts(conf, infra)
+
+	if err != nil {
+		return err
+	}
+
+	opts.OperationKind = provisioner.Destroy
+
+	return provision.Provision(conf, opts)
+}
+
 func destroyDOCR(conf *config.Config, infra *models.Infra) error {
 	lastAppliedDOCR := &types.CreateDOCRInfraRequest{}
 
@@ -262,7 +309,7 @@ func destroyDOCR(conf *config.Config, infra *models.Infra) error {
 
 	opts.OperationKind = provisioner.Destroy
 
-	err = conf.ProvisionerAgent.Provision(opts)
+	err = provision.Provision(conf, opts)
 
 	return err
 }
@@ -303,7 +350,7 @@ func destroyDOKS(conf *config.Config, infra *models.Infra) error {
 
 	opts.OperationKind = provisioner.Destroy
 
-	err = conf.ProvisionerAgent.Provision(opts)
+	err = provision.Provision(conf, opts)
 
 	return err
 }
@@ -335,16 +382,11 @@ func destroyGKE(conf *config.Config, infra *models.Infra) error {
 	}
 
 	opts.CredentialExchange.VaultToken = vaultToken
-	opts.GKE = &gke.Conf{
-		GCPProjectID: gcpInt.GCPProjectID,
-		GCPRegion:    lastAppliedGKE.GCPRegion,
-		ClusterName:  lastAppliedGKE.GKEName,
-		IssuerEmail:  lastAppliedGKE.IssuerEmail,
-	}
+	opts.GKE = gke.NewConf(gcpInt.GCPProjectID, lastAppliedGKE)
 
 	opts.OperationKind = provisioner.Destroy
 
-	err = conf.ProvisionerAgent.Provision(opts)
+	err = provision.Provision(conf, opts)
 
 	return err
 }
@@ -0,0 +1,63 @@
+package infra
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/ee/integrations/httpbackend"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type InfraExportHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewInfraExportHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *InfraExportHandler {
+	return &InfraExportHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *InfraExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	res := &types.ExportInfraResponse{
+		Kind:        infra.Kind,
+		WorkspaceID: infra.GetUniqueName(),
+		Variables:   make(map[string]interface{}),
+	}
+
+	if len(infra.LastApplied) > 0 {
+		variables := make(map[string]interface{})
+
+		if err := json.Unmarshal(infra.LastApplied, &variables); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		res.Variables = httpbackend.RedactMap(variables)
+	}
+
+	// TODO: move client out of this call
+	client := httpbackend.NewClient(c.Config().ServerConf.ProvisionerBackendURL)
+
+	current, err := client.GetCurrentState(infra.GetUniqueName())
+
+	if err != nil && !errors.Is(err, httpbackend.ErrNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	} else if err == nil {
+		res.State = current.Redacted()
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,44 @@
+package infra
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type InfraListResourcesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewInfraListResourcesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *InfraListResourcesHandler {
+	return &InfraListResourcesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *InfraListResourcesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	infra, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	resources, err := c.Repo().Infra().ListInfraResources(infra.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListInfraResourcesResponse, 0)
+
+	for _, resource := range resources {
+		res = append(res, resource.ToInfraResourceType())
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -1,6 +1,8 @@
 package infra
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -18,6 +20,7 @@ import (
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/gcr"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/gke"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/redis_stream/lease"
 	"gorm.io/gorm"
 )
 
@@ -40,7 +43,14 @@ func (c *InfraRetryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	opts, err := c.getProvisioningOpts(infraModel)
+	// failed upgrades are retried with the version of the upgrade
+	retried := *infraModel
+
+	if infraModel.PendingModuleVersion != "" {
+		retried.ModuleVersion = infraModel.PendingModuleVersion
+	}
+
+	opts, err := getProvisioningOpts(c.Config(), &retried)
 	if err != nil {
 		c.HandleAPIError(w, r, err)
 		return
@@ -48,21 +58,28 @@ func (c *InfraRetryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	opts.OperationKind = provisioner.Apply
 
-	provisionerErr := c.Config().ProvisionerAgent.Provision(opts)
+	provisionerErr := provision.Provision(c.Config(), opts)
 	if provisionerErr != nil {
 		infraModel.Status = types.StatusError
 		c.Repo().Infra().UpdateInfra(infraModel)
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(provisionerErr))
+		c.HandleAPIError(w, r, provisionError(provisionerErr))
 		return
 	}
 
-	infraModel.Status = types.StatusCreating
+	if infraModel.PendingModuleVersion != "" {
+		infraModel.Status = types.StatusUpdating
+	} else {
+		infraModel.Status = types.StatusCreating
+	}
+
 	infraModel, _ = c.Repo().Infra().UpdateInfra(infraModel)
 
 	c.WriteResult(w, r, infraModel.ToInfraType())
 }
 
-func (c *InfraRetryHandler) getProvisioningOpts(infraModel *models.Infra) (*provisioner.ProvisionOpts, apierrors.RequestError) {
+// getProvisioningOpts returns the options for re-applying an infra from its last-applied
+// configuration
+func getProvisioningOpts(conf *config.Config, infraModel *models.Infra) (*provisioner.ProvisionOpts, apierrors.RequestError) {
 	var vaultToken string
 	var opts *provisioner.ProvisionOpts
 
@@ -71,29 +88,31 @@ func (c *InfraRetryHandler) getProvisioningOpts(infraModel *models.Infra) (*prov
 	switch infra.Kind {
 	// ==================== Infrastructure Google Cloud ======================
 	case types.InfraGKE, types.InfraGCR:
-		integration, err := c.Repo().GCPIntegration().ReadGCPIntegration(infra.ProjectID, infra.GCPIntegrationID)
+		integration, err := conf.Repo.GCPIntegration().ReadGCPIntegration(infra.ProjectID, infra.GCPIntegrationID)
 		if err != nil {
-			return nil, c._qualifyGormError(err)
+			return nil, qualifyGormError(err)
 		}
 
-		opts, err = c.getOptions(infraModel)
+		opts, err = provision.GetSharedProvisionerOpts(conf, infraModel)
 		if err != nil {
 			return nil, apierrors.NewErrInternal(err)
 		}
 
-		if c.Config().CredentialBackend != nil {
-			vaultToken, err = c.Config().CredentialBackend.CreateGCPToken(integration)
+		if conf.CredentialBackend != nil {
+			vaultToken, err = conf.CredentialBackend.CreateGCPToken(integration)
 			if err != nil {
 				return nil, apierrors.NewErrInternal(err)
 			}
 		}
 
 		if infra.Kind == types.InfraGKE {
-			opts.GKE = &gke.Conf{
-				GCPProjectID: integration.GCPProjectID,
-				GCPRegion:    integration.GCPRegion,
-				ClusterName:  infra.LastApplied["gke_name"],
+			lastApplied := &types.CreateGKEInfraRequest{}
+
+			if err := json.Unmarshal(infraModel.LastApplied, lastApplied); err != nil {
+				return nil, apierrors.NewErrInternal(err)
 			}
+
+			opts.GKE = gke.NewConf(integration.GCPProjectID, lastApplied)
 		} else {
 			opts.GCR = &gcr.Conf{
 				GCPProjectID: integration.GCPProjectID,
@@ -102,30 +121,31 @@ func (c *InfraRetryHandler) getProvisioningOpts(infraModel *models.Infra) (*prov
 
 	// ========================== Infrastructure AWS ============================
 	case types.InfraEKS, types.InfraECR:
-		integration, err := c.Repo().AWSIntegration().ReadAWSIntegration(infra.ProjectID, infra.AWSIntegrationID)
+		integration, err := conf.Repo.AWSIntegration().ReadAWSIntegration(infra.ProjectID, infra.AWSIntegrationID)
 		if err != nil {
-			return nil, c._qualifyGormError(err)
+			return nil, qualifyGormError(err)
 		}
 
-		opts, err = c.getOptions(infraModel)
+		opts, err = provision.GetSharedProvisionerOpts(conf, infraModel)
 		if err != nil {
 			return nil, apierrors.NewErrInternal(err)
 		}
 
-		if c.Config().CredentialBackend != nil {
-			vaultToken, err = c.Config().CredentialBackend.CreateAWSToken(integration)
+		if conf.CredentialBackend != nil {
+			vaultToken, err = conf.CredentialBackend.CreateAWSToken(integration)
 			if err != nil {
 				return nil, apierrors.NewErrInternal(err)
 			}
 		}
 
 		if infra.Kind == types.InfraEKS {
-			opts.EKS = &eks.Conf{
-				AWSRegion:   integration.AWSRegion,
-				ClusterName: infra.LastApplied["eks_name"],
-				MachineType: infra.LastApplied["machine_type"],
-				IssuerEmail: infra.LastApplied["issuer_email"],
+			lastApplied := &types.CreateEKSInfraRequest{}
+
+			if err := json.Unmarshal(infraModel.LastApplied, lastApplied); err != nil {
+				return nil, apierrors.NewErrInternal(err)
 			}
+
+			opts.EKS = eks.NewConf(integration.AWSRegion, lastApplied)
 		} else {
 			opts.ECR = &ecr.Conf{
 				AWSRegion: integration.AWSRegion,
@@ -135,18 +155,18 @@ func (c *InfraRetryHandler) getProvisioningOpts(infraModel *models.Infra) (*prov
 
 	// ========================== Infrastructure Digital Ocean ============================
 	case types.InfraDOKS, types.InfraDOCR:
-		integration, err := c.Repo().OAuthIntegration().ReadOAuthIntegration(infra.ProjectID, infra.DOIntegrationID)
+		integration, err := conf.Repo.OAuthIntegration().ReadOAuthIntegration(infra.ProjectID, infra.DOIntegrationID)
 		if err != nil {
-			return nil, c._qualifyGormError(err)
+			return nil, qualifyGormError(err)
 		}
 
-		opts, err = c.getOptions(infraModel)
+		opts, err = provision.GetSharedProvisionerOpts(conf, infraModel)
 		if err != nil {
 			return nil, apierrors.NewErrInternal(err)
 		}
 
-		if c.Config().CredentialBackend != nil {
-			vaultToken, err = c.Config().CredentialBackend.CreateOAuthToken(integration)
+		if conf.CredentialBackend != nil {
+			vaultToken, err = conf.CredentialBackend.CreateOAuthToken(integration)
 			if err != nil {
 				return nil, apierrors.NewErrInternal(err)
 			}
@@ -165,9 +185,33 @@ func (c *InfraRetryHandler) getProvisioningOpts(infraModel *models.Infra) (*prov
 			}
 		}
 
+	// =================== Object storage and message queues ===================
+	case types.InfraS3, types.InfraGCS, types.InfraSQS, types.InfraPubSub:
+		opts, err := provision.GetProvisionedResourceProvisionerOpts(conf, infraModel)
+		if err != nil {
+			return nil, qualifyGormError(err)
+		}
+
+		opts.OperationKind = provisioner.Apply
+
+		return opts, nil
+
+	// ========================== Private networks ============================
+	case types.InfraRDSNetwork, types.InfraCloudSQLNetwork:
+		opts, err := provision.GetPrivateNetworkProvisionerOpts(conf, infraModel)
+		if err != nil {
+			return nil, qualifyGormError(err)
+		}
+
+		opts.OperationKind = provisioner.Apply
+
+		return opts, nil
+
 	default:
-		// infra == InfraTest
-		panic("not implemented!")
+		return nil, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("infras of kind %s cannot be re-applied", infra.Kind),
+			http.StatusBadRequest,
+		)
 	}
 
 	opts.CredentialExchange.VaultToken = vaultToken
@@ -176,20 +220,20 @@ func (c *InfraRetryHandler) getProvisioningOpts(infraModel *models.Infra) (*prov
 	return opts, nil
 }
 
-func (c *InfraRetryHandler) _qualifyGormError(err error) apierrors.RequestError {
+// provisionError converts an error from starting a provisioning operation to an API
+// error
+func provisionError(err error) apierrors.RequestError {
+	if errors.Is(err, lease.ErrLeaseHeld) {
+		return apierrors.NewErrPassThroughToClient(err, http.StatusConflict)
+	}
+
+	return apierrors.NewErrInternal(err)
+}
+
+func qualifyGormError(err error) apierrors.RequestError {
 	if err == gorm.ErrRecordNotFound {
 		return apierrors.NewErrForbidden(err)
 	} else {
 		return apierrors.NewErrInternal(err)
 	}
 }
-
-func (c *InfraRetryHandler) getOptions(infraModel *models.Infra) (*provisioner.ProvisionOpts, error) {
-	// get provisioner options
-	opts, err := provision.GetSharedProvisionerOpts(c.Config(), infraModel)
-	if err != nil {
-		return nil, apierrors.NewErrInternal(err)
-	}
-
-	return opts, nil
-}
@@ -0,0 +1,132 @@
+package infra
+
+import (
+	"fmt"
+	"net/http"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/provision"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type InfraUpgradeHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewInfraUpgradeHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *InfraUpgradeHandler {
+	return &InfraUpgradeHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *InfraUpgradeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	infraModel, _ := r.Context().Value(types.InfraScope).(*models.Infra)
+
+	request := &types.UpgradeInfraRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if infraModel.Status != types.StatusCreated {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("only infras with status %s may be upgraded", types.StatusCreated),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	currVersion := infraModel.ModuleVersion
+
+	if currVersion == "" {
+		currVersion = c.Config().ServerConf.ProvisionerImageTag
+	}
+
+	if err := checkModuleVersion(currVersion, request.ModuleVersion); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	// run the operation with a copy of the infra that points to the new version, so
+	// that a plan does not change the version the infra is pinned to
+	upgraded := *infraModel
+	upgraded.ModuleVersion = request.ModuleVersion
+
+	opts, reqErr := getProvisioningOpts(c.Config(), &upgraded)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	if request.PlanOnly {
+		opts.OperationKind = provisioner.Plan
+	} else {
+		opts.OperationKind = provisioner.Apply
+	}
+
+	if err := provision.Provision(c.Config(), opts); err != nil {
+		c.HandleAPIError(w, r, provisionError(err))
+		return
+	}
+
+	if request.PlanOnly {
+		res := infraModel.ToInfraType()
+
+		// the estimate is a best effort, so kinds that cannot be estimated are still planned
+		if estimate, err := c.Config().CostEstimator.Estimate(infraModel.Kind, infraModel.LastApplied); err == nil {
+			res.CostEstimate = estimate
+		}
+
+		c.WriteResult(w, r, res)
+		return
+	}
+
+	// the infra stays pinned to its current version until the apply succeeds, so that a
+	// failed upgrade is not reported as the version of the infra
+	infraModel.PendingModuleVersion = request.ModuleVersion
+	infraModel.Status = types.StatusUpdating
+
+	infraModel, err := c.Repo().Infra().UpdateInfra(infraModel)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, infraModel.ToInfraType())
+}
+
+// checkModuleVersion returns an error if the target version is not a semantic version,
+// or is older than the current version. Infras that are pinned to a non-semver tag (like
+// the "latest" default of the server) can be upgraded to any semantic version.
+func checkModuleVersion(currVersion, targetVersion string) error {
+	target, err := semver.NewVersion(targetVersion)
+
+	if err != nil {
+		return fmt.Errorf("module version %s is not a semantic version", targetVersion)
+	}
+
+	curr, err := semver.NewVersion(currVersion)
+
+	if err != nil {
+		return nil
+	}
+
+	if target.LessThan(curr) {
+		return fmt.Errorf("cannot downgrade from module version %s to %s", currVersion, targetVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package infra
+
+import "testing"
+
+func TestCheckModuleVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		currVersion   string
+		targetVersion string
+		valid         bool
+	}{
+		{"upgrade", "v0.2.0", "v0.3.0", true},
+		{"same version", "v0.2.0", "v0.2.0", true},
+		{"downgrade", "v0.3.0", "v0.2.0", false},
+		{"non-semver target", "v0.2.0", "latest", false},
+		{"non-semver target from the server default", "latest", "dev", false},
+		{"upgrade from the server default", "latest", "v0.2.0", true},
+	}
+
+	for _, tt := range tests {
+		if err := checkModuleVersion(tt.currVersion, tt.targetVersion); (err == nil) != tt.valid {
+			t.Errorf("%s: expected valid to be %t, got %v", tt.name, tt.valid, err)
+		}
+	}
+}
@@ -30,7 +30,7 @@ func NewGetPodsHandler(
 
 func (c *GetPodsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
-	agent, err := c.GetAgent(r, cluster, "")
+	statusReader, err := c.GetStatusReader(r, cluster)
 	name, _ := requestutils.GetURLParamString(r, types.URLParamJobName)
 	namespace, _ := requestutils.GetURLParamString(r, types.URLParamNamespace)
 
@@ -39,7 +39,7 @@ func (c *GetPodsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pods, err := agent.GetJobPods(namespace, name)
+	pods, err := statusReader.GetJobPods(namespace, name)
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -6,6 +6,7 @@ import (
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
 )
 
 type MetadataGetHandler struct {
@@ -22,5 +23,19 @@ func NewMetadataGetHandler(
 }
 
 func (v *MetadataGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	v.WriteResult(w, r, v.Config().Metadata)
+	res := *v.Config().Metadata
+
+	// the expiry of the license is checked on every request, since the server may run
+	// past the expiry
+	if license := v.Config().License; license != nil {
+		res.License = &types.LicenseStatus{
+			License:   license,
+			IsExpired: license.IsExpired(),
+		}
+	}
+
+	// instance admins can opt the installation out of analytics at runtime
+	res.Analytics = res.Analytics && !v.Config().Settings.GetBool(types.ServerSettingAnalyticsOptOut)
+
+	v.WriteResult(w, r, &res)
 }
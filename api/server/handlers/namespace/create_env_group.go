@@ -13,12 +13,13 @@ import (
 
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
+	releasehandler "github.com/porter-dev/porter/api/server/handlers/release"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
-	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/kubernetes/envgroup"
+	"github.com/porter-dev/porter/internal/kubernetes/envtemplate"
 	"github.com/porter-dev/porter/internal/models"
 )
 
@@ -45,7 +46,27 @@ func (c *CreateEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// templates are resolved when the releases that use the env group are deployed, so
+	// they are only checked for syntax errors here
+	for _, vars := range []map[string]string{request.Variables, request.SecretVariables} {
+		for key, val := range vars {
+			if !envtemplate.IsTemplate(val) {
+				continue
+			}
+
+			if err := envtemplate.Validate(val); err != nil {
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+					fmt.Errorf("invalid template in env variable %s: %v", key, err),
+					http.StatusBadRequest,
+				))
+
+				return
+			}
+		}
+	}
+
 	namespace := r.Context().Value(types.NamespaceScope).(string)
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 
 	agent, err := c.GetAgent(r, cluster, namespace)
@@ -103,7 +124,7 @@ func (c *CreateEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	c.WriteResult(w, r, envGroup)
 
 	// trigger rollout of new applications after writing the result
-	errors := rolloutApplications(c.Config(), cluster, helmAgent, envGroup, configMap, releases)
+	errors := rolloutApplications(c.Config(), r, user, cluster, envGroup, configMap, releases)
 
 	if len(errors) > 0 {
 		errStrArr := make([]string, 0)
@@ -117,19 +138,20 @@ func (c *CreateEnvGroupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// rolloutApplications upgrades the releases that are synced to an env group to its new
+// version. The releases are upgraded through the shared upgrade path of the release
+// handlers, so protected releases get change requests instead, and releases are not
+// upgraded during deploy freezes.
 func rolloutApplications(
 	config *config.Config,
+	r *http.Request,
+	user *models.User,
 	cluster *models.Cluster,
-	helmAgent *helm.Agent,
 	envGroup *types.EnvGroup,
 	configMap *v1.ConfigMap,
 	releases []*release.Release,
 ) []error {
-	registries, err := config.Repo.Registry().ListRegistriesByProjectID(cluster.ProjectID)
-
-	if err != nil {
-		return []error{err}
-	}
+	upgrader := releasehandler.NewReleaseUpgrader(config)
 
 	// construct the synced env section that should be written
 	newSection := &SyncedEnvSection{
@@ -153,8 +175,7 @@ func rolloutApplications(
 	mu := &sync.Mutex{}
 	errors := make([]error, 0)
 
-	for i, rel := range releases {
-		index := i
+	for _, rel := range releases {
 		release := rel
 		wg.Add(1)
 
@@ -175,15 +196,14 @@ func rolloutApplications(
 				newConfig["paused"] = true
 			}
 
-			conf := &helm.UpgradeReleaseConfig{
-				Name:       releases[index].Name,
-				Cluster:    cluster,
-				Repo:       config.Repo,
-				Registries: registries,
-				Values:     newConfig,
-			}
-
-			_, err = helmAgent.UpgradeReleaseByValues(conf, config.DOConf)
+			_, err = upgrader.Upgrade(&releasehandler.UpgradeOpts{
+				User:      user,
+				Cluster:   cluster,
+				Namespace: release.Namespace,
+				Name:      release.Name,
+				Values:    newConfig,
+				Request:   r,
+			})
 
 			if err != nil {
 				mu.Lock()
@@ -52,10 +52,10 @@ func (c *GetEnvGroupAllVersionsHandler) ServeHTTP(w http.ResponseWriter, r *http
 	configMaps, err := agent.ListVersionedConfigMaps(request.Name, namespace)
 
 	if err != nil && errors.Is(err, kubernetes.IsNotFoundError) {
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
 			fmt.Errorf("env group not found"),
 			http.StatusNotFound,
-		))
+		), types.ErrorCodeEnvGroupNotFound))
 		return
 	} else if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -0,0 +1,82 @@
+package namespace
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/envgroup"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type GetEnvGroupsBatchHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetEnvGroupsBatchHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GetEnvGroupsBatchHandler {
+	return &GetEnvGroupsBatchHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP gets the latest versions of several env groups, so that clients that read
+// every env group synced to a release do not make a request per env group. Env groups
+// that do not exist or cannot be read are returned separately instead of failing the
+// request.
+func (c *GetEnvGroupsBatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &types.GetEnvGroupsBatchRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.GetEnvGroupsBatchResponse{
+		EnvGroups: make([]*types.EnvGroup, 0),
+		NotFound:  make([]string, 0),
+		Errors:    make(map[string]string),
+	}
+
+	seen := make(map[string]bool)
+
+	for _, name := range request.Names {
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+
+		envGroup, err := envgroup.GetEnvGroup(agent, name, namespace, 0)
+
+		if err != nil && errors.Is(err, kubernetes.IsNotFoundError) {
+			res.NotFound = append(res.NotFound, name)
+		} else if err != nil {
+			res.Errors[name] = err.Error()
+		} else {
+			res.EnvGroups = append(res.EnvGroups, envGroup)
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -1,7 +1,9 @@
 package namespace
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
@@ -9,7 +11,9 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
 )
 
 type ListReleasesHandler struct {
@@ -45,6 +49,11 @@ func (c *ListReleasesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// the ownership of a release is determined from the labels of its manifest
+	if request.Ownership != "" {
+		request.SkipManifest = false
+	}
+
 	releases, err := helmAgent.ListReleases(namespace, request.ReleaseListFilter)
 
 	if err != nil {
@@ -52,7 +61,54 @@ func (c *ListReleasesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if ownership := request.Ownership; ownership != "" {
+		if ownership != types.ReleaseOwnershipPorter && ownership != types.ReleaseOwnershipExternal {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("ownership must be either %s or %s", types.ReleaseOwnershipPorter, types.ReleaseOwnershipExternal),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		filtered := make([]*release.Release, 0)
+
+		for _, rel := range releases {
+			if helm.IsPorterManaged(rel, cluster) == (ownership == types.ReleaseOwnershipPorter) {
+				filtered = append(filtered, rel)
+			}
+		}
+
+		// releases are paginated once they are filtered, so that pages of a filtered
+		// list are not short
+		releases = paginateReleases(filtered, request.Skip, request.Limit)
+	}
+
 	var res types.ListReleasesResponse = releases
 
 	c.WriteResult(w, r, res)
 }
+
+// paginateReleases returns a page of releases, sorted by namespace and name so that pages
+// are stable. All releases after the skipped releases are returned if the limit is 0.
+func paginateReleases(releases []*release.Release, skip, limit int) []*release.Release {
+	sort.Slice(releases, func(i, j int) bool {
+		if releases[i].Namespace != releases[j].Namespace {
+			return releases[i].Namespace < releases[j].Namespace
+		}
+
+		return releases[i].Name < releases[j].Name
+	})
+
+	if skip >= len(releases) {
+		return make([]*release.Release, 0)
+	} else if skip > 0 {
+		releases = releases[skip:]
+	}
+
+	if limit > 0 && limit < len(releases) {
+		releases = releases[:limit]
+	}
+
+	return releases
+}
@@ -0,0 +1,103 @@
+package namespace
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type GetNamespaceNetworkPolicyPresetHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetNamespaceNetworkPolicyPresetHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetNamespaceNetworkPolicyPresetHandler {
+	return &GetNamespaceNetworkPolicyPresetHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetNamespaceNetworkPolicyPresetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	preset, err := agent.GetNetworkPolicyPreset(namespace, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, getNamespaceNetworkPolicyPresetResponse(preset))
+}
+
+type UpdateNamespaceNetworkPolicyPresetHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpdateNamespaceNetworkPolicyPresetHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateNamespaceNetworkPolicyPresetHandler {
+	return &UpdateNamespaceNetworkPolicyPresetHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdateNamespaceNetworkPolicyPresetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	request := &types.UpdateNetworkPolicyPresetRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := agent.ApplyNetworkPolicyPreset(namespace, "", request.Preset); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, getNamespaceNetworkPolicyPresetResponse(request.Preset))
+}
+
+func getNamespaceNetworkPolicyPresetResponse(preset types.NetworkPolicyPreset) *types.NetworkPolicyPresetResponse {
+	res := &types.NetworkPolicyPresetResponse{
+		Preset: preset,
+	}
+
+	if preset != types.NetworkPolicyPresetOpen {
+		res.PolicyName = kubernetes.GetNetworkPolicyPresetName("")
+	}
+
+	return res
+}
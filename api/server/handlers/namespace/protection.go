@@ -0,0 +1,75 @@
+package namespace
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type GetNamespaceProtectionHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetNamespaceProtectionHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetNamespaceProtectionHandler {
+	return &GetNamespaceProtectionHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetNamespaceProtectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	protected, err := c.Repo().ChangeRequest().IsNamespaceProtected(cluster.ID, namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.NamespaceProtectionResponse{
+		Protected: protected,
+	})
+}
+
+type UpdateNamespaceProtectionHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateNamespaceProtectionHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateNamespaceProtectionHandler {
+	return &UpdateNamespaceProtectionHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateNamespaceProtectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	request := &types.UpdateProtectionRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if err := c.Repo().ChangeRequest().SetNamespaceProtected(cluster.ID, namespace, request.Protected); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.NamespaceProtectionResponse{
+		Protected: request.Protected,
+	})
+}
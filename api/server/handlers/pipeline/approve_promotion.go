@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type ApprovePromotionHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewApprovePromotionHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ApprovePromotionHandler {
+	return &ApprovePromotionHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ApprovePromotionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	pipeline, ok := readPipeline(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	promotionID, reqErr := requestutils.GetURLParamUint(r, types.URLParamPromotionID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	promotion, err := c.Repo().Pipeline().ReadPromotion(pipeline.ID, promotionID)
+
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("promotion with id %d not found", promotionID),
+			http.StatusNotFound,
+		))
+
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if promotion.Status != types.PromotionStatusPending {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("promotion is not pending approval"),
+			http.StatusConflict,
+		))
+
+		return
+	}
+
+	if promotion.RequestedByUserID == user.ID {
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(
+			fmt.Errorf("user %d cannot approve their own promotion", user.ID),
+		))
+
+		return
+	}
+
+	for _, approval := range promotion.Approvals {
+		if approval.UserID == user.ID {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("promotion was already approved by this user"),
+				http.StatusConflict,
+			))
+
+			return
+		}
+	}
+
+	approval, err := c.Repo().Pipeline().CreatePromotionApproval(&models.PipelinePromotionApproval{
+		PromotionID: promotion.ID,
+		UserID:      user.ID,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	promotion.Approvals = append(promotion.Approvals, *approval)
+
+	if uint(len(promotion.Approvals)) >= pipeline.RequiredApprovals {
+		promotion, reqErr = runPromotion(c.Config(), c.KubernetesAgentGetter, r, pipeline, promotion)
+
+		if reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+	}
+
+	c.WriteResult(w, r, promotion.ToPipelinePromotionType())
+}
@@ -0,0 +1,93 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type CreatePipelineHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreatePipelineHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreatePipelineHandler {
+	return &CreatePipelineHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *CreatePipelineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreatePipelineRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	pipeline := &models.Pipeline{
+		ProjectID:         project.ID,
+		Name:              request.Name,
+		RequiredApprovals: request.RequiredApprovals,
+		Stages:            make([]models.PipelineStage, 0),
+	}
+
+	stageNames := make(map[string]bool)
+
+	for i, stage := range request.Stages {
+		if stageNames[stage.Name] {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("stage names must be unique: %s is used more than once", stage.Name),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		stageNames[stage.Name] = true
+
+		// the cluster of each stage must belong to the project
+		_, err := c.Repo().Cluster().ReadCluster(project.ID, stage.ClusterID)
+
+		if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+			c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("cluster with id %d not found for stage %s", stage.ClusterID, stage.Name),
+				http.StatusBadRequest,
+			), types.ErrorCodeClusterNotFound))
+
+			return
+		} else if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		pipeline.Stages = append(pipeline.Stages, models.PipelineStage{
+			Position:    uint(i),
+			Name:        stage.Name,
+			ClusterID:   stage.ClusterID,
+			Namespace:   stage.Namespace,
+			ReleaseName: stage.ReleaseName,
+		})
+	}
+
+	pipeline, err := c.Repo().Pipeline().CreatePipeline(pipeline)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, pipeline.ToPipelineType())
+}
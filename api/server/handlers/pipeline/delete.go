@@ -0,0 +1,40 @@
+package pipeline
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+)
+
+type DeletePipelineHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewDeletePipelineHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DeletePipelineHandler {
+	return &DeletePipelineHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *DeletePipelineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pipeline, ok := readPipeline(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	pipeline, err := c.Repo().Pipeline().DeletePipeline(pipeline)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, pipeline.ToPipelineType())
+}
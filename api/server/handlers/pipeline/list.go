@@ -0,0 +1,44 @@
+package pipeline
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ListPipelinesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListPipelinesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListPipelinesHandler {
+	return &ListPipelinesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListPipelinesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	pipelines, err := c.Repo().Pipeline().ListPipelines(project.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListPipelinesResponse, 0)
+
+	for _, pipeline := range pipelines {
+		res = append(res, pipeline.ToPipelineType())
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,47 @@
+package pipeline
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+)
+
+type ListPromotionsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListPromotionsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListPromotionsHandler {
+	return &ListPromotionsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListPromotionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pipeline, ok := readPipeline(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	promotions, err := c.Repo().Pipeline().ListPromotions(pipeline.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListPipelinePromotionsResponse, 0)
+
+	for _, promotion := range promotions {
+		res = append(res, promotion.ToPipelinePromotionType())
+	}
+
+	c.WriteResult(w, r, res)
+}
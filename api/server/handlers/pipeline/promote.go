@@ -0,0 +1,118 @@
+package pipeline
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type CreatePromotionHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewCreatePromotionHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreatePromotionHandler {
+	return &CreatePromotionHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *CreatePromotionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	pipeline, ok := readPipeline(c.PorterHandlerReadWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	request := &types.CreatePromotionRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	var fromStage, toStage *models.PipelineStage
+
+	for i, stage := range pipeline.Stages {
+		if stage.Name == request.FromStage && i+1 < len(pipeline.Stages) {
+			fromStage = &pipeline.Stages[i]
+			toStage = &pipeline.Stages[i+1]
+		}
+	}
+
+	if fromStage == nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("stage %s not found, or is the last stage of the pipeline", request.FromStage),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	// the latest revision is recorded when the promotion is requested, so that approvers
+	// approve a fixed revision even if the source release is upgraded in the meantime
+	srcHelmAgent, _, err := getStageHelmAgent(c.Config(), c.KubernetesAgentGetter, r, pipeline, fromStage)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	srcRelease, err := srcHelmAgent.GetRelease(fromStage.ReleaseName, 0, false)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("could not read release %s in stage %s: %s", fromStage.ReleaseName, fromStage.Name, err.Error()),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	promotion := &models.PipelinePromotion{
+		PipelineID:        pipeline.ID,
+		FromStageID:       fromStage.ID,
+		ToStageID:         toStage.ID,
+		Status:            types.PromotionStatusPending,
+		RequestedByUserID: user.ID,
+		Revision:          srcRelease.Version,
+		ImageTag:          getImageTag(srcRelease),
+	}
+
+	if srcRelease.Chart != nil && srcRelease.Chart.Metadata != nil {
+		promotion.ChartVersion = srcRelease.Chart.Metadata.Version
+	}
+
+	promotion, err = c.Repo().Pipeline().CreatePromotion(promotion)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if pipeline.RequiredApprovals == 0 {
+		var reqErr apierrors.RequestError
+
+		promotion, reqErr = runPromotion(c.Config(), c.KubernetesAgentGetter, r, pipeline, promotion)
+
+		if reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+	}
+
+	c.WriteResult(w, r, promotion.ToPipelinePromotionType())
+}
@@ -0,0 +1,287 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	releasehandler "github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// readPipeline reads the pipeline in the URL, and writes an error if the pipeline does
+// not exist in the project
+func readPipeline(c handlers.PorterHandler, w http.ResponseWriter, r *http.Request) (*models.Pipeline, bool) {
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	pipelineID, reqErr := requestutils.GetURLParamUint(r, types.URLParamPipelineID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return nil, false
+	}
+
+	pipeline, err := c.Repo().Pipeline().ReadPipeline(project.ID, pipelineID)
+
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("pipeline with id %d not found", pipelineID),
+			http.StatusNotFound,
+		))
+
+		return nil, false
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	return pipeline, true
+}
+
+func getStage(pipeline *models.Pipeline, id uint) (*models.PipelineStage, error) {
+	for i := range pipeline.Stages {
+		if pipeline.Stages[i].ID == id {
+			return &pipeline.Stages[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("stage with id %d not found", id)
+}
+
+// getStageHelmAgent returns a Helm agent for the namespace of the stage
+func getStageHelmAgent(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	pipeline *models.Pipeline,
+	stage *models.PipelineStage,
+) (*helm.Agent, *models.Cluster, error) {
+	cluster, err := config.Repo.Cluster().ReadCluster(pipeline.ProjectID, stage.ClusterID)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read cluster of stage %s: %w", stage.Name, err)
+	}
+
+	helmAgent, err := agentGetter.GetHelmAgent(r, cluster, stage.Namespace)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return helmAgent, cluster, nil
+}
+
+// runPromotion deploys the chart and values of the promoted revision to the release in the
+// next stage, and records whether the promotion succeeded. The promotion is claimed first,
+// so that it is only run once when it is approved by several users at the same time. The
+// returned error is only non-nil if the promotion was already claimed or could not be saved.
+func runPromotion(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	pipeline *models.Pipeline,
+	promotion *models.PipelinePromotion,
+) (*models.PipelinePromotion, apierrors.RequestError) {
+	claimed, err := config.Repo.Pipeline().ClaimPromotion(promotion)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	if !claimed {
+		return nil, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("promotion is already running"),
+			http.StatusConflict,
+		)
+	}
+
+	if cr, err := promote(config, agentGetter, r, pipeline, promotion); err != nil {
+		promotion.Status = types.PromotionStatusFailed
+		promotion.Error = err.Error()
+	} else if cr != nil {
+		promotion.Status = types.PromotionStatusChangeRequested
+	} else {
+		promotion.Status = types.PromotionStatusSucceeded
+	}
+
+	promotion, err = config.Repo.Pipeline().UpdatePromotion(promotion)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	return promotion, nil
+}
+
+func promote(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	pipeline *models.Pipeline,
+	promotion *models.PipelinePromotion,
+) (*models.ReleaseChangeRequest, error) {
+	fromStage, err := getStage(pipeline, promotion.FromStageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	toStage, err := getStage(pipeline, promotion.ToStageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+
+	srcHelmAgent, srcCluster, err := getStageHelmAgent(config, agentGetter, r, pipeline, fromStage)
+
+	if err != nil {
+		return nil, err
+	}
+
+	srcRelease, err := srcHelmAgent.GetRelease(fromStage.ReleaseName, promotion.Revision, true)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read revision %d of %s: %w", promotion.Revision, fromStage.ReleaseName, err)
+	}
+
+	dstHelmAgent, dstCluster, err := getStageHelmAgent(config, agentGetter, r, pipeline, toStage)
+
+	if err != nil {
+		return nil, err
+	}
+
+	srcSensitiveValues, err := helm.GetStoredSensitiveValues(config.Repo, srcCluster, fromStage.Namespace, fromStage.ReleaseName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = dstHelmAgent.GetRelease(toStage.ReleaseName, 0, false)
+
+	if err == nil {
+		// sensitive values that are already stored for the next stage are kept, so that
+		// stages can use different credentials
+		dstSensitiveValues, err := helm.GetStoredSensitiveValues(config.Repo, dstCluster, toStage.Namespace, toStage.ReleaseName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for path := range dstSensitiveValues {
+			delete(srcSensitiveValues, path)
+		}
+
+		// the release in the next stage is upgraded through the shared upgrade path of
+		// releases, so promotions to protected releases get change requests
+		return releasehandler.NewReleaseUpgrader(config).Upgrade(&releasehandler.UpgradeOpts{
+			User:      user,
+			Cluster:   dstCluster,
+			Namespace: toStage.Namespace,
+			Name:      toStage.ReleaseName,
+			Values:    helm.ResolveSensitiveValues(srcRelease.Config, srcSensitiveValues),
+			Chart:     srcRelease.Chart,
+			Source:    types.DeploySourcePipeline,
+			Request:   r,
+		})
+	} else if !errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, err
+	}
+
+	// installs do not go through the shared upgrade path, so the deploy freezes and the
+	// chart allow-list of the next stage are checked before the release is installed
+	if reqErr := releasehandler.CheckDeployFreeze(config, r, user, dstCluster); reqErr != nil {
+		return nil, reqErr
+	}
+
+	if reqErr := releasehandler.CheckReleaseChartAllowed(config, dstCluster.ProjectID, srcRelease.Chart, ""); reqErr != nil {
+		return nil, reqErr
+	}
+
+	registries, err := config.Repo.Registry().ListRegistriesByProjectID(pipeline.ProjectID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the release does not exist in the next stage yet, so it is installed
+	dstRelease, err := dstHelmAgent.InstallChart(&helm.InstallChartConfig{
+		Chart:      srcRelease.Chart,
+		Name:       toStage.ReleaseName,
+		Namespace:  toStage.Namespace,
+		Values:     helm.ResolveSensitiveValues(srcRelease.Config, srcSensitiveValues),
+		Cluster:    dstCluster,
+		Repo:       config.Repo,
+		Registries: registries,
+	}, config.DOConf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, createPromotedRelease(config, pipeline, fromStage, dstCluster, dstRelease)
+}
+
+// createPromotedRelease creates the Porter release for a release that was installed by a
+// promotion, with the image repository of the release that it was promoted from
+func createPromotedRelease(
+	config *config.Config,
+	pipeline *models.Pipeline,
+	fromStage *models.PipelineStage,
+	dstCluster *models.Cluster,
+	dstRelease *release.Release,
+) error {
+	_, err := config.Repo.Release().ReadRelease(dstCluster.ID, dstRelease.Name, dstRelease.Namespace)
+
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	token, err := repository.GenerateRandomBytes(16)
+
+	if err != nil {
+		return err
+	}
+
+	rel := &models.Release{
+		ClusterID:    dstCluster.ID,
+		ProjectID:    pipeline.ProjectID,
+		Namespace:    dstRelease.Namespace,
+		Name:         dstRelease.Name,
+		WebhookToken: token,
+	}
+
+	if srcRel, err := config.Repo.Release().ReadRelease(fromStage.ClusterID, fromStage.ReleaseName, fromStage.Namespace); err == nil {
+		rel.ImageRepoURI = srcRel.ImageRepoURI
+	}
+
+	_, err = config.Repo.Release().CreateRelease(rel)
+
+	return err
+}
+
+// getImageTag returns the image tag under the image values key of the chart of a release,
+// or an empty string
+func getImageTag(helmRelease *release.Release) string {
+	image := helm.GetImageValues(helmRelease.Config, helm.GetImageValuesKey(helmRelease.Chart))
+
+	if image == nil || image["tag"] == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", image["tag"])
+}
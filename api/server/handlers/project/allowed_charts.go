@@ -0,0 +1,128 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type ListAllowedChartsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListAllowedChartsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListAllowedChartsHandler {
+	return &ListAllowedChartsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListAllowedChartsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	allowed, err := c.Repo().AllowedChart().ListAllowedChartsByProjectID(proj.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListAllowedChartsResponse, 0)
+
+	for _, entry := range allowed {
+		res = append(res, entry.ToAllowedChartType())
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type CreateAllowedChartHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateAllowedChartHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateAllowedChartHandler {
+	return &CreateAllowedChartHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP adds an entry to the chart allow-list of the project. Once the project has
+// an entry, only charts that match an entry can be deployed.
+func (c *CreateAllowedChartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateAllowedChartRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	allowed, err := c.Repo().AllowedChart().CreateAllowedChart(&models.AllowedChart{
+		ProjectID: proj.ID,
+		RepoURL:   request.RepoURL,
+		ChartName: request.ChartName,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, allowed.ToAllowedChartType())
+}
+
+type DeleteAllowedChartHandler struct {
+	handlers.PorterHandler
+}
+
+func NewDeleteAllowedChartHandler(
+	config *config.Config,
+) *DeleteAllowedChartHandler {
+	return &DeleteAllowedChartHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+func (c *DeleteAllowedChartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	id, reqErr := requestutils.GetURLParamUint(r, types.URLParamAllowedChartID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	allowed, err := c.Repo().AllowedChart().ReadAllowedChart(proj.ID, id)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("allowed chart %d not found in project", id),
+			http.StatusNotFound,
+		))
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Repo().AllowedChart().DeleteAllowedChart(allowed); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+}
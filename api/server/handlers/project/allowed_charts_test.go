@@ -0,0 +1,91 @@
+package project_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestCreateAndListAllowedCharts(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/allowed_charts",
+		&types.CreateAllowedChartRequest{
+			RepoURL:   "https://charts.getporter.dev",
+			ChartName: "web",
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewCreateAllowedChartHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	expAllowed := &types.AllowedChart{
+		ID:        1,
+		ProjectID: proj.ID,
+		RepoURL:   "https://charts.getporter.dev",
+		ChartName: "web",
+	}
+
+	apitest.AssertResponseExpected(t, rr, expAllowed, &types.AllowedChart{})
+
+	// the created entry should be returned by the list handler
+	req, rr = apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/allowed_charts", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	listHandler := project.NewListAllowedChartsHandler(
+		config,
+		shared.NewDefaultResultWriter(config),
+	)
+
+	listHandler.ServeHTTP(rr, req)
+
+	expList := &types.ListAllowedChartsResponse{expAllowed}
+
+	apitest.AssertResponseExpected(t, rr, expList, &types.ListAllowedChartsResponse{})
+}
+
+func TestAllowedChartMatches(t *testing.T) {
+	tests := []struct {
+		allowed  *models.AllowedChart
+		repoURL  string
+		chart    string
+		expected bool
+	}{
+		{&models.AllowedChart{RepoURL: "https://charts.getporter.dev", ChartName: "web"}, "https://charts.getporter.dev/", "web", true},
+		{&models.AllowedChart{RepoURL: "https://charts.getporter.dev", ChartName: "web"}, "https://charts.getporter.dev", "worker", false},
+		{&models.AllowedChart{RepoURL: "https://charts.getporter.dev"}, "https://charts.getporter.dev", "worker", true},
+		{&models.AllowedChart{ChartName: "redis"}, "https://charts.bitnami.com/bitnami", "redis", true},
+		{&models.AllowedChart{ChartName: "redis"}, "https://charts.bitnami.com/bitnami", "postgresql", false},
+	}
+
+	for _, test := range tests {
+		if got := test.allowed.Matches(test.repoURL, test.chart); got != test.expected {
+			t.Errorf("expected %s/%s allowed by %s/%s to be %t, got %t", test.repoURL, test.chart, test.allowed.RepoURL, test.allowed.ChartName, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type UpdateProjectAllowedRegistriesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateProjectAllowedRegistriesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateProjectAllowedRegistriesHandler {
+	return &UpdateProjectAllowedRegistriesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP replaces the registry allow-list of the project. Once the project has an
+// allow-list, releases can only be created and upgraded with images of the allowed
+// registries.
+func (c *UpdateProjectAllowedRegistriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateProjectAllowedRegistriesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	registries := make([]string, 0)
+
+	for _, registry := range request.Registries {
+		registry = normalizeAllowedRegistry(registry)
+
+		if registry == "" {
+			continue
+		}
+
+		if strings.ContainsAny(registry, ", \t") {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("invalid registry %s", registry),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		registries = append(registries, registry)
+	}
+
+	proj.AllowedRegistries = strings.Join(registries, ",")
+
+	proj, err := c.Repo().Project().UpdateProject(proj)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, proj.ToProjectType())
+}
+
+// normalizeAllowedRegistry removes the scheme and trailing slashes of a registry URL, so
+// that registries can be copied from the registry integrations of the project
+func normalizeAllowedRegistry(registry string) string {
+	registry = strings.TrimSpace(registry)
+
+	if i := strings.Index(registry, "://"); i >= 0 {
+		registry = registry[i+3:]
+	}
+
+	return strings.TrimSuffix(registry, "/")
+}
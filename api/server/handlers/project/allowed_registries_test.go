@@ -0,0 +1,116 @@
+package project_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestUpdateProjectAllowedRegistries(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/allowed_registries",
+		&types.UpdateProjectAllowedRegistriesRequest{
+			Registries: []string{"https://gcr.io/my-project/", "123456789012.dkr.ecr.us-east-1.amazonaws.com", ""},
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewUpdateProjectAllowedRegistriesHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	// the registries should be stored without their scheme and trailing slashes
+	expProject := proj.ToProjectType()
+	expProject.AllowedRegistries = []string{"gcr.io/my-project", "123456789012.dkr.ecr.us-east-1.amazonaws.com"}
+
+	apitest.AssertResponseExpected(t, rr, expProject, &types.Project{})
+}
+
+func TestUpdateProjectAllowedRegistriesInvalid(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/allowed_registries",
+		&types.UpdateProjectAllowedRegistriesRequest{
+			Registries: []string{"gcr.io,docker.io"},
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewUpdateProjectAllowedRegistriesHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "invalid registry gcr.io,docker.io",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+}
+
+func TestProjectIsImageAllowed(t *testing.T) {
+	proj := &models.Project{
+		AllowedRegistries: "gcr.io/my-project,docker.io/library",
+	}
+
+	tests := []struct {
+		image    string
+		expected bool
+	}{
+		{"gcr.io/my-project/web:latest", true},
+		{"gcr.io/my-project", true},
+		{"gcr.io/my-project-2/web", false},
+		{"gcr.io/other/web", false},
+		{"nginx:1.21", true},
+		{"bitnami/redis", false},
+		{"public.ecr.aws/o1j4x7p4/hello-porter", false},
+	}
+
+	for _, test := range tests {
+		if got := proj.IsImageAllowed(test.image); got != test.expected {
+			t.Errorf("expected %s allowed by %s to be %t, got %t", test.image, proj.AllowedRegistries, test.expected, got)
+		}
+	}
+
+	if !(&models.Project{}).IsImageAllowed("bitnami/redis") {
+		t.Errorf("expected projects without an allow-list to allow every image")
+	}
+}
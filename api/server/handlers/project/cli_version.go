@@ -0,0 +1,99 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type UpdateProjectCLIVersionHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateProjectCLIVersionHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateProjectCLIVersionHandler {
+	return &UpdateProjectCLIVersionHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateProjectCLIVersionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateProjectCLIVersionRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	version := ""
+
+	if request.Version != "" {
+		parsedVersion, err := semver.NewVersion(strings.TrimPrefix(request.Version, "v"))
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("invalid CLI version %s: %w", request.Version, err),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		if err := checkCLIVersionRange(parsedVersion, c.Config().Metadata); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		// versions are stored with the v prefix of the release tags
+		version = "v" + parsedVersion.String()
+	}
+
+	proj.CLIVersion = version
+
+	proj, err := c.Repo().Project().UpdateProject(proj)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, proj.ToProjectType())
+}
+
+// checkCLIVersionRange returns an error if a version is outside of the range of CLI
+// versions that the server supports
+func checkCLIVersionRange(version *semver.Version, metadata *config.Metadata) error {
+	if metadata == nil {
+		return nil
+	}
+
+	if metadata.MinCLIVersion != "" {
+		minVersion, err := semver.NewVersion(strings.TrimPrefix(metadata.MinCLIVersion, "v"))
+
+		if err == nil && version.LessThan(minVersion) {
+			return fmt.Errorf("CLI version %s is lower than the minimum supported version %s", version, metadata.MinCLIVersion)
+		}
+	}
+
+	if metadata.MaxCLIVersion != "" {
+		maxVersion, err := semver.NewVersion(strings.TrimPrefix(metadata.MaxCLIVersion, "v"))
+
+		if err == nil && version.GreaterThan(maxVersion) {
+			return fmt.Errorf("CLI version %s is higher than the maximum supported version %s", version, metadata.MaxCLIVersion)
+		}
+	}
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package project
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
@@ -39,6 +41,11 @@ func (p *ProjectCreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	// read the user from context
 	user, _ := r.Context().Value(types.UserScope).(*models.User)
 
+	if reqErr := checkProjectQuota(p.Config(), user); reqErr != nil {
+		p.HandleAPIError(w, r, reqErr)
+		return
+	}
+
 	proj := &models.Project{
 		Name: request.Name,
 	}
@@ -135,3 +142,36 @@ func CreateProjectWithUser(
 
 	return proj, role, nil
 }
+
+// checkProjectQuota returns an error if the user has reached the instance quota of
+// projects per user. Instance admins are not subject to the quota.
+func checkProjectQuota(config *config.Config, user *models.User) apierrors.RequestError {
+	if authz.IsInstanceAdmin(config, user) {
+		return nil
+	}
+
+	quota, err := config.Repo.InstanceAdmin().ReadInstanceQuota()
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if quota.MaxProjectsPerUser == 0 {
+		return nil
+	}
+
+	projects, err := config.Repo.Project().ListProjectsByUserID(user.ID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if uint(len(projects)) >= quota.MaxProjectsPerUser {
+		return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("instance quota reached: users can create at most %d projects", quota.MaxProjectsPerUser),
+			http.StatusBadRequest,
+		), types.ErrorCodeQuotaExceeded)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package project
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type GetProjectDefaultValuesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetProjectDefaultValuesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetProjectDefaultValuesHandler {
+	return &GetProjectDefaultValuesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetProjectDefaultValuesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	res := &types.DefaultValuesResponse{
+		Values: map[string]interface{}{},
+	}
+
+	defaultValues, err := c.Repo().DefaultValues().ReadDefaultValues(proj.ID, 0)
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	} else if err == nil {
+		res.Values, err = defaultValues.GetValues()
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type UpdateProjectDefaultValuesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateProjectDefaultValuesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateProjectDefaultValuesHandler {
+	return &UpdateProjectDefaultValuesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateProjectDefaultValuesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateDefaultValuesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.Values == nil {
+		request.Values = map[string]interface{}{}
+	}
+
+	valuesBytes, err := json.Marshal(request.Values)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	defaultValues, err := c.Repo().DefaultValues().ReadDefaultValues(proj.ID, 0)
+
+	if err == gorm.ErrRecordNotFound {
+		defaultValues = &models.DefaultValues{
+			ProjectID: proj.ID,
+		}
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	defaultValues.Values = valuesBytes
+
+	if _, err := c.Repo().DefaultValues().UpdateDefaultValues(defaultValues); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.DefaultValuesResponse{
+		Values: request.Values,
+	})
+}
@@ -0,0 +1,100 @@
+package project_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestUpdateProjectDefaultValuesSuccessful(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]interface{}{
+		"nodeSelector": map[string]interface{}{
+			"pool": "apps",
+		},
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/default_values",
+		&types.UpdateDefaultValuesRequest{
+			Values: values,
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewUpdateProjectDefaultValuesHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	expResponse := &types.DefaultValuesResponse{
+		Values: values,
+	}
+
+	apitest.AssertResponseExpected(t, rr, expResponse, &types.DefaultValuesResponse{})
+
+	// the updated values should be returned by the get handler
+	req, rr = apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/default_values", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	getHandler := project.NewGetProjectDefaultValuesHandler(
+		config,
+		shared.NewDefaultResultWriter(config),
+	)
+
+	getHandler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseExpected(t, rr, expResponse, &types.DefaultValuesResponse{})
+}
+
+func TestGetProjectDefaultValuesEmpty(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/default_values", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewGetProjectDefaultValuesHandler(
+		config,
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	expResponse := &types.DefaultValuesResponse{
+		Values: map[string]interface{}{},
+	}
+
+	apitest.AssertResponseExpected(t, rr, expResponse, &types.DefaultValuesResponse{})
+}
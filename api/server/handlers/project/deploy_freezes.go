@@ -0,0 +1,202 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/freeze"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type ListDeployFreezesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListDeployFreezesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListDeployFreezesHandler {
+	return &ListDeployFreezesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListDeployFreezesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	freezes, err := c.Repo().DeployFreeze().ListDeployFreezesByProjectID(proj.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListDeployFreezesResponse, 0)
+	now := time.Now()
+
+	for _, f := range freezes {
+		freezeType := f.ToDeployFreezeType()
+
+		if end, active, err := freeze.ActiveUntil(f, now); err == nil && active {
+			freezeType.ActiveUntil = &end
+		}
+
+		res = append(res, freezeType)
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type CreateDeployFreezeHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateDeployFreezeHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateDeployFreezeHandler {
+	return &CreateDeployFreezeHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP adds a deploy freeze to the project, or to a single cluster of the project.
+// While the freeze is active, deploys and infra changes are blocked unless a project
+// admin overrides the freeze with a reason.
+func (c *CreateDeployFreezeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateDeployFreezeRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.ClusterID != 0 {
+		if _, err := c.Repo().Cluster().ReadCluster(proj.ID, request.ClusterID); errors.Is(err, gorm.ErrRecordNotFound) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("cluster %d not found in project", request.ClusterID),
+				http.StatusNotFound,
+			))
+			return
+		} else if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	f := &models.DeployFreeze{
+		ProjectID:       proj.ID,
+		ClusterID:       request.ClusterID,
+		Name:            request.Name,
+		Schedule:        request.Schedule,
+		DurationMinutes: request.DurationMinutes,
+		Timezone:        request.Timezone,
+		StartsAt:        request.StartsAt,
+		EndsAt:          request.EndsAt,
+		CreatedByUserID: user.ID,
+	}
+
+	if err := freeze.Validate(f); err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(
+			apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest),
+			types.ErrorCodeValidationFailed,
+		))
+		return
+	}
+
+	f, err := c.Repo().DeployFreeze().CreateDeployFreeze(f)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, f.ToDeployFreezeType())
+}
+
+type DeleteDeployFreezeHandler struct {
+	handlers.PorterHandler
+}
+
+func NewDeleteDeployFreezeHandler(
+	config *config.Config,
+) *DeleteDeployFreezeHandler {
+	return &DeleteDeployFreezeHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+func (c *DeleteDeployFreezeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	id, reqErr := requestutils.GetURLParamUint(r, types.URLParamDeployFreezeID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	f, err := c.Repo().DeployFreeze().ReadDeployFreeze(proj.ID, id)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("deploy freeze %d not found in project", id),
+			http.StatusNotFound,
+		))
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if _, err := c.Repo().DeployFreeze().DeleteDeployFreeze(f); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+}
+
+type ListDeployFreezeOverridesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListDeployFreezeOverridesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListDeployFreezeOverridesHandler {
+	return &ListDeployFreezeOverridesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP lists the overrides of the deploy freezes of the project, which are the
+// audit log of the requests that were allowed during a freeze
+func (c *ListDeployFreezeOverridesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	overrides, err := c.Repo().DeployFreeze().ListDeployFreezeOverridesByProjectID(proj.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListDeployFreezeOverridesResponse, 0)
+
+	for _, override := range overrides {
+		res = append(res, override.ToDeployFreezeOverrideType())
+	}
+
+	c.WriteResult(w, r, res)
+}
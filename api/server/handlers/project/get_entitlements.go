@@ -0,0 +1,38 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ProjectGetEntitlementsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewProjectGetEntitlementsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ProjectGetEntitlementsHandler {
+	return &ProjectGetEntitlementsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (p *ProjectGetEntitlementsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	entitlements, err := p.Config().EntitlementManager.GetEntitlements(proj)
+
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	p.WriteResult(w, r, entitlements)
+}
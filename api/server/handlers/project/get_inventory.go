@@ -0,0 +1,85 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/usage"
+)
+
+type ProjectGetInventoryHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewProjectGetInventoryHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ProjectGetInventoryHandler {
+	return &ProjectGetInventoryHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP returns the resources of each cluster of the project and their totals,
+// along with the usage limit of the project. Clusters that cannot be reached are
+// returned with an error, and only their databases are counted.
+func (p *ProjectGetInventoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	clusters, err := p.Repo().Cluster().ListClustersByProjectID(proj.ID)
+
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	registries, err := p.Repo().Registry().ListRegistriesByProjectID(proj.ID)
+
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.ProjectInventory{
+		Clusters:   uint(len(clusters)),
+		Registries: uint(len(registries)),
+		Limit:      types.EnterprisePlan,
+	}
+
+	if p.Config().ServerConf.UsageTrackingEnabled {
+		limit, err := usage.GetLimit(p.Repo(), proj)
+
+		if err != nil {
+			p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		res.Limit = *limit
+	}
+
+	inventory := p.Config().Inventory
+
+	// without the cache, the inventories are collected on each request
+	if inventory == nil {
+		inventory = usage.NewInventoryCache(0, p.Repo(), p.Config().DOConf, nil)
+	}
+
+	res.ClusterInventories = inventory.GetProjectInventory(clusters)
+
+	for _, clusterInventory := range res.ClusterInventories {
+		res.Nodes += clusterInventory.Nodes
+		res.CPUAllocatable += clusterInventory.CPUAllocatable
+		res.CPURequested += clusterInventory.CPURequested
+		res.MemoryAllocatable += clusterInventory.MemoryAllocatable
+		res.MemoryRequested += clusterInventory.MemoryRequested
+		res.Releases += clusterInventory.Releases
+		res.Databases += clusterInventory.Databases
+	}
+
+	p.WriteResult(w, r, res)
+}
@@ -43,7 +43,8 @@ func (p *ProjectGetUsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		Project:          proj,
 		DOConf:           p.Config().DOConf,
 		Repo:             p.Repo(),
-		WhitelistedUsers: p.Config().WhitelistedUsers,
+		WhitelistedUsers: p.Config().WhitelistedUsers.Map(),
+		Inventory:        p.Config().Inventory,
 	})
 
 	if err != nil {
@@ -0,0 +1,205 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/cosign"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type ListImageSigningAuthoritiesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListImageSigningAuthoritiesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListImageSigningAuthoritiesHandler {
+	return &ListImageSigningAuthoritiesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListImageSigningAuthoritiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	authorities, err := c.Repo().ImageSigningAuthority().ListImageSigningAuthoritiesByProjectID(proj.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListImageSigningAuthoritiesResponse, 0)
+
+	for _, authority := range authorities {
+		res = append(res, authority.ToImageSigningAuthorityType())
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type CreateImageSigningAuthorityHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateImageSigningAuthorityHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateImageSigningAuthorityHandler {
+	return &CreateImageSigningAuthorityHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP adds a cosign public key or keyless identity to the project, whose signatures
+// are accepted when the project enforces image signing
+func (c *CreateImageSigningAuthorityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateImageSigningAuthorityRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	err := c.Config().ImageVerifier.ValidateAuthority(&cosign.Authority{
+		Name:      request.Name,
+		PublicKey: request.PublicKey,
+		Issuer:    request.Issuer,
+		Subject:   request.Subject,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("invalid signing authority: %s", err.Error()),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	authority, err := c.Repo().ImageSigningAuthority().CreateImageSigningAuthority(&models.ImageSigningAuthority{
+		ProjectID: proj.ID,
+		Name:      request.Name,
+		PublicKey: request.PublicKey,
+		Issuer:    request.Issuer,
+		Subject:   request.Subject,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, authority.ToImageSigningAuthorityType())
+}
+
+type DeleteImageSigningAuthorityHandler struct {
+	handlers.PorterHandler
+}
+
+func NewDeleteImageSigningAuthorityHandler(
+	config *config.Config,
+) *DeleteImageSigningAuthorityHandler {
+	return &DeleteImageSigningAuthorityHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+func (c *DeleteImageSigningAuthorityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	id, reqErr := requestutils.GetURLParamUint(r, types.URLParamImageSigningAuthorityID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	authority, err := c.Repo().ImageSigningAuthority().ReadImageSigningAuthority(proj.ID, id)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("image signing authority %d not found in project", id),
+			http.StatusNotFound,
+		))
+
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := c.Repo().ImageSigningAuthority().DeleteImageSigningAuthority(authority); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+}
+
+type UpdateProjectImageSigningHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateProjectImageSigningHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateProjectImageSigningHandler {
+	return &UpdateProjectImageSigningHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP turns the enforcement of image signing on or off. While enforcement is on,
+// releases can only be created and upgraded with images that are signed by an authority
+// of the project.
+func (c *UpdateProjectImageSigningHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateProjectImageSigningRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.Enforce {
+		authorities, err := c.Repo().ImageSigningAuthority().ListImageSigningAuthoritiesByProjectID(proj.ID)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		if len(authorities) == 0 {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("the project must have a signing authority before image signing can be enforced"),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+	}
+
+	proj.ImageSigningEnforced = request.Enforce
+
+	proj, err := c.Repo().Project().UpdateProject(proj)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, proj.ToProjectType())
+}
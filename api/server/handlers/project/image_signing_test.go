@@ -0,0 +1,174 @@
+package project_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestCreateAndListImageSigningAuthorities(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := getTestCosignPublicKey(t)
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/image_signing_authorities",
+		&types.CreateImageSigningAuthorityRequest{
+			Name:      "ci",
+			PublicKey: publicKey,
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewCreateImageSigningAuthorityHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Result().StatusCode != http.StatusCreated {
+		t.Fatalf("expected status code %d, got %d: %s", http.StatusCreated, rr.Result().StatusCode, rr.Body.String())
+	}
+
+	// the created authority should be returned by the list handler
+	req, rr = apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/image_signing_authorities", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	listHandler := project.NewListImageSigningAuthoritiesHandler(
+		config,
+		shared.NewDefaultResultWriter(config),
+	)
+
+	listHandler.ServeHTTP(rr, req)
+
+	authorities := types.ListImageSigningAuthoritiesResponse{}
+
+	if err := json.NewDecoder(rr.Body).Decode(&authorities); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(authorities) != 1 || authorities[0].Name != "ci" || authorities[0].PublicKey != publicKey {
+		t.Errorf("incorrect image signing authorities: %v", authorities)
+	}
+}
+
+func TestCreateImageSigningAuthorityInvalidKey(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/image_signing_authorities",
+		&types.CreateImageSigningAuthorityRequest{
+			Name:      "ci",
+			PublicKey: "not-a-key",
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewCreateImageSigningAuthorityHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "invalid signing authority: public key must be a PEM-encoded PUBLIC KEY block, such as a cosign.pub file",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+}
+
+func TestUpdateProjectImageSigningWithoutAuthorities(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/image_signing",
+		&types.UpdateProjectImageSigningRequest{
+			Enforce: true,
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewUpdateProjectImageSigningHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "the project must have a signing authority before image signing can be enforced",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+}
+
+func getTestCosignPublicKey(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: der,
+	}))
+}
@@ -0,0 +1,54 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ProjectListAPIErrorsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewProjectListAPIErrorsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ProjectListAPIErrorsHandler {
+	return &ProjectListAPIErrorsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (p *ProjectListAPIErrorsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.ListAPIErrorLogsRequest{}
+
+	if ok := p.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	logs, err := p.Repo().APIErrorLog().ListAPIErrorLogsByProjectID(proj.ID, request)
+
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListAPIErrorLogsResponse, 0, len(logs))
+
+	for _, log := range logs {
+		apiErr := log.ToAPIErrorLogType()
+		apiErr.SuggestedFix = apierrors.GetSuggestedFix(log.Category, log.Error)
+
+		res = append(res, apiErr)
+	}
+
+	p.WriteResult(w, r, res)
+}
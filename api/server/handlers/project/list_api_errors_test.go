@@ -0,0 +1,75 @@
+package project_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestListAPIErrorsByCategory(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createdAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	logs := []*models.APIErrorLog{
+		{
+			Model:      gorm.Model{CreatedAt: createdAt},
+			ProjectID:  proj.ID,
+			Method:     "POST",
+			Path:       "/api/webhooks/deploy/{token}",
+			Handler:    "release.WebhookHandler",
+			StatusCode: http.StatusBadRequest,
+			Error:      "Deploy webhook is disabled for this deployment.",
+			Category:   apierrors.GetAPIErrorCategory(http.StatusBadRequest, "Deploy webhook is disabled for this deployment."),
+		},
+		{
+			Model:      gorm.Model{CreatedAt: createdAt},
+			ProjectID:  proj.ID,
+			Method:     "DELETE",
+			Path:       "/api/projects/{project_id}",
+			StatusCode: http.StatusForbidden,
+			Error:      "Forbidden",
+			Category:   apierrors.GetAPIErrorCategory(http.StatusForbidden, "Forbidden"),
+		},
+	}
+
+	for _, log := range logs {
+		if _, err := config.Repo.APIErrorLog().CreateAPIErrorLog(log); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/api_errors?category=unavailable", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewProjectListAPIErrorsHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	expLog := logs[0].ToAPIErrorLogType()
+	expLog.SuggestedFix = apierrors.GetSuggestedFix(types.APIErrorCategoryUnavailable, logs[0].Error)
+
+	apitest.AssertResponseExpected(t, rr, &types.ListAPIErrorLogsResponse{expLog}, &types.ListAPIErrorLogsResponse{})
+}
@@ -0,0 +1,49 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ListStaleReleasesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListStaleReleasesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListStaleReleasesHandler {
+	return &ListStaleReleasesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP returns the releases in the clusters of the project that were found to be
+// stale by the last stale release check
+func (c *ListStaleReleasesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	staleReleases, err := c.Repo().StaleRelease().ListStaleReleasesByProjectID(proj.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListStaleReleasesResponse, 0)
+
+	for _, staleRelease := range staleReleases {
+		// releases that have not been deployed recently are tracked before they are stale
+		if staleRelease.IsStale() {
+			res = append(res, staleRelease.ToStaleReleaseType())
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,257 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/opa"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type ListManifestPoliciesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListManifestPoliciesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListManifestPoliciesHandler {
+	return &ListManifestPoliciesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListManifestPoliciesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	policies, err := c.Repo().ManifestPolicy().ListManifestPoliciesByProjectID(proj.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListManifestPoliciesResponse, 0)
+
+	for _, policy := range policies {
+		res = append(res, policy.ToManifestPolicyType())
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type CreateManifestPolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateManifestPolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateManifestPolicyHandler {
+	return &CreateManifestPolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP adds a Rego policy to the project, which is evaluated against the manifests
+// of every install and upgrade of the releases of the project
+func (c *CreateManifestPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateManifestPolicyRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if reqErr := validateManifestPolicy(c.Config(), request.Rego); reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	policy, err := c.Repo().ManifestPolicy().CreateManifestPolicy(&models.ManifestPolicy{
+		ProjectID:   proj.ID,
+		Name:        request.Name,
+		Description: request.Description,
+		Rego:        request.Rego,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := putManifestPolicy(c.Config(), policy); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, policy.ToManifestPolicyType())
+}
+
+type UpdateManifestPolicyHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateManifestPolicyHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateManifestPolicyHandler {
+	return &UpdateManifestPolicyHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP updates the fields of a manifest policy that are set in the request
+func (c *UpdateManifestPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	policy, reqErr := readManifestPolicy(c.Config(), r, proj)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	request := &types.UpdateManifestPolicyRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.Rego != "" {
+		if reqErr := validateManifestPolicy(c.Config(), request.Rego); reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+
+		policy.Rego = request.Rego
+	}
+
+	if request.Name != "" {
+		policy.Name = request.Name
+	}
+
+	if request.Description != "" {
+		policy.Description = request.Description
+	}
+
+	policy, err := c.Repo().ManifestPolicy().UpdateManifestPolicy(policy)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := putManifestPolicy(c.Config(), policy); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, policy.ToManifestPolicyType())
+}
+
+type DeleteManifestPolicyHandler struct {
+	handlers.PorterHandler
+}
+
+func NewDeleteManifestPolicyHandler(
+	config *config.Config,
+) *DeleteManifestPolicyHandler {
+	return &DeleteManifestPolicyHandler{
+		PorterHandler: handlers.NewDefaultPorterHandler(config, nil, nil),
+	}
+}
+
+func (c *DeleteManifestPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	policy, reqErr := readManifestPolicy(c.Config(), r, proj)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	if err := c.Repo().ManifestPolicy().DeleteManifestPolicy(policy); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if c.Config().OPAClient != nil {
+		if err := c.Config().OPAClient.DeletePolicy(proj.ID, toOPAPolicy(policy)); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+}
+
+func readManifestPolicy(config *config.Config, r *http.Request, proj *models.Project) (*models.ManifestPolicy, apierrors.RequestError) {
+	id, reqErr := requestutils.GetURLParamUint(r, types.URLParamManifestPolicyID)
+
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	policy, err := config.Repo.ManifestPolicy().ReadManifestPolicy(proj.ID, id)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("manifest policy %d not found in project", id),
+			http.StatusNotFound,
+		)
+	} else if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	return policy, nil
+}
+
+// validateManifestPolicy compiles a policy on the policy server, so that policies with
+// syntax errors are rejected before they can fail deploys
+func validateManifestPolicy(config *config.Config, rego string) apierrors.RequestError {
+	if config.OPAClient == nil {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("manifest policies are not enabled on this instance"),
+			http.StatusBadRequest,
+		)
+	}
+
+	if err := config.OPAClient.ValidatePolicy(rego); err != nil {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("invalid policy: %s", err.Error()),
+			http.StatusBadRequest,
+		)
+	}
+
+	return nil
+}
+
+// putManifestPolicy uploads a created or updated policy to the policy server, so that
+// deploys evaluate the policy without uploading the policies of the project
+func putManifestPolicy(config *config.Config, policy *models.ManifestPolicy) error {
+	if config.OPAClient == nil {
+		return nil
+	}
+
+	return config.OPAClient.PutPolicy(policy.ProjectID, toOPAPolicy(policy))
+}
+
+func toOPAPolicy(policy *models.ManifestPolicy) *opa.Policy {
+	return &opa.Policy{
+		ID:   policy.ID,
+		Name: policy.Name,
+		Rego: policy.Rego,
+	}
+}
@@ -0,0 +1,229 @@
+package project_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/opa"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+const testManifestPolicy = `deny[msg] {
+  input.spec.template.spec.containers[_].securityContext.privileged
+  msg := "privileged containers are not allowed"
+}`
+
+func TestCreateManifestPolicyWithoutPolicyServer(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/manifest_policies",
+		&types.CreateManifestPolicyRequest{
+			Name: "no-privileged-containers",
+			Rego: testManifestPolicy,
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewCreateManifestPolicyHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "manifest policies are not enabled on this instance",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+}
+
+func TestCreateAndListManifestPolicies(t *testing.T) {
+	// the policy server accepts every policy
+	uploaded := make([]string, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v1/policies/porter/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Method == http.MethodPut && !strings.HasPrefix(r.URL.Path, "/v1/policies/porter/validate/") {
+			uploaded = append(uploaded, strings.TrimPrefix(r.URL.Path, "/v1/policies/"))
+		}
+
+		w.Write([]byte("{}"))
+	}))
+
+	defer server.Close()
+
+	config := apitest.LoadConfig(t)
+	config.OPAClient = opa.NewClient(server.URL)
+
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/manifest_policies",
+		&types.CreateManifestPolicyRequest{
+			Name: "no-privileged-containers",
+			Rego: testManifestPolicy,
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewCreateManifestPolicyHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Result().StatusCode != http.StatusCreated {
+		t.Fatalf("expected status code %d, got %d: %s", http.StatusCreated, rr.Result().StatusCode, rr.Body.String())
+	}
+
+	// the created policy should be uploaded along with the batch module of the project
+	expectedUploads := []string{"porter/project_1/policy_1", "porter/batch/project_1"}
+
+	if strings.Join(uploaded, ",") != strings.Join(expectedUploads, ",") {
+		t.Errorf("expected policies %v to be uploaded, got %v", expectedUploads, uploaded)
+	}
+
+	// the created policy should be returned by the list handler
+	req, rr = apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/manifest_policies", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	listHandler := project.NewListManifestPoliciesHandler(
+		config,
+		shared.NewDefaultResultWriter(config),
+	)
+
+	listHandler.ServeHTTP(rr, req)
+
+	policies := types.ListManifestPoliciesResponse{}
+
+	if err := json.NewDecoder(rr.Body).Decode(&policies); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(policies) != 1 || policies[0].Name != "no-privileged-containers" || policies[0].Rego != testManifestPolicy {
+		t.Errorf("incorrect manifest policies: %v", policies)
+	}
+}
+
+func TestCreateManifestPolicyWithPackage(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.OPAClient = opa.NewClient("http://localhost:8181")
+
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/manifest_policies",
+		&types.CreateManifestPolicyRequest{
+			Name: "no-privileged-containers",
+			Rego: "package example\n\n" + testManifestPolicy,
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewCreateManifestPolicyHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "invalid policy: policies must not declare a package",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+}
+
+func TestCreateManifestPolicyWithDeniedBuiltin(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.OPAClient = opa.NewClient("http://localhost:8181")
+
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/manifest_policies",
+		&types.CreateManifestPolicyRequest{
+			Name: "exfiltrate",
+			Rego: `deny[msg] {
+  resp := http.send({"method": "get", "url": "http://169.254.169.254/"})
+  msg := resp.raw_body
+}`,
+		},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewCreateManifestPolicyHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "invalid policy: policies must not call http.send",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+}
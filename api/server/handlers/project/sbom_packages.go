@@ -0,0 +1,103 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/sbom"
+)
+
+type ListSBOMPackagesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewListSBOMPackagesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListSBOMPackagesHandler {
+	return &ListSBOMPackagesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP finds the releases of the project whose most recently built image contains a
+// package, such as the releases that contain log4j-core 2.x
+func (c *ListSBOMPackagesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.ListSBOMPackagesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	var constraint *semver.Constraints
+
+	if request.Version != "" {
+		var err error
+
+		constraint, err = semver.NewConstraint(request.Version)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("invalid version constraint %s: %s", request.Version, err.Error()),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+	}
+
+	imageSBOMs, err := c.Repo().ImageSBOM().ListImageSBOMsByPackageName(proj.ID, request.Name)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	sbomsByID := make(map[uint]*models.ImageSBOM)
+	ids := make([]uint, 0, len(imageSBOMs))
+
+	for _, imageSBOM := range imageSBOMs {
+		sbomsByID[imageSBOM.ID] = imageSBOM
+		ids = append(ids, imageSBOM.ID)
+	}
+
+	releases, err := c.Repo().Release().ListReleasesByImageSBOMIDs(proj.ID, ids)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListSBOMPackagesResponse, 0)
+
+	for _, release := range releases {
+		imageSBOM := sbomsByID[release.ImageSBOMID]
+
+		for _, pkg := range imageSBOM.Packages {
+			if !sbom.MatchesVersion(pkg.Version, constraint) {
+				continue
+			}
+
+			res = append(res, &types.SBOMPackageRelease{
+				ClusterID:   release.ClusterID,
+				Namespace:   release.Namespace,
+				ReleaseName: release.Name,
+				ImageRepo:   imageSBOM.ImageRepo,
+				Digest:      imageSBOM.Digest,
+				Package:     pkg.ToSBOMPackageType(),
+			})
+		}
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,124 @@
+package project_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestListSBOMPackagesByVersion(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imageSBOMs := []*models.ImageSBOM{
+		{
+			ProjectID: proj.ID,
+			ImageRepo: "gcr.io/project/api",
+			Digest:    "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+			Format:    types.SBOMFormatBuildpacks,
+			Packages: []models.SBOMPackage{
+				{Name: "log4j-core", Version: "2.14.1", Type: "maven"},
+				{Name: "spring-core", Version: "5.3.9", Type: "maven"},
+			},
+		},
+		{
+			ProjectID: proj.ID,
+			ImageRepo: "gcr.io/project/worker",
+			Digest:    "sha256:2222222222222222222222222222222222222222222222222222222222222222",
+			Format:    types.SBOMFormatCycloneDXJSON,
+			Packages: []models.SBOMPackage{
+				{Name: "log4j-core", Version: "1.2.17", Type: "maven"},
+			},
+		},
+	}
+
+	for i, imageSBOM := range imageSBOMs {
+		if _, err := config.Repo.ImageSBOM().CreateImageSBOM(imageSBOM); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := config.Repo.Release().CreateRelease(&models.Release{
+			Name:        []string{"api", "worker"}[i],
+			Namespace:   "default",
+			ProjectID:   proj.ID,
+			ClusterID:   1,
+			ImageSBOMID: imageSBOM.ID,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/sbom_packages?name=log4j&version=2.x", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewListSBOMPackagesHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	expRes := &types.ListSBOMPackagesResponse{
+		{
+			ClusterID:   1,
+			Namespace:   "default",
+			ReleaseName: "api",
+			ImageRepo:   "gcr.io/project/api",
+			Digest:      "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+			Package: &types.SBOMPackage{
+				Name:    "log4j-core",
+				Version: "2.14.1",
+				Type:    "maven",
+			},
+		},
+	}
+
+	apitest.AssertResponseExpected(t, rr, expRes, &types.ListSBOMPackagesResponse{})
+}
+
+func TestListSBOMPackagesInvalidVersion(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbGet), "/api/projects/1/sbom_packages?name=log4j&version=not-a-version", nil)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewListSBOMPackagesHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "invalid version constraint not-a-version: improper constraint: not-a-version",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+}
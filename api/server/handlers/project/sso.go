@@ -0,0 +1,82 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/router/middleware"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type UpdateProjectSSOHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateProjectSSOHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateProjectSSOHandler {
+	return &UpdateProjectSSOHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP turns the enforcement of SSO on or off. While enforcement is on, only users
+// that signed in through the SSO provider of the instance can access the project. SSO
+// can only be enforced on plans that include it, while enforcement can always be turned
+// off, so that the members of projects whose plan no longer includes SSO are not locked
+// out.
+func (c *UpdateProjectSSOHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.UpdateProjectSSORequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.Enforce {
+		if reqErr := middleware.CheckEntitlement(c.Config(), proj, types.EntitlementSSO); reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+
+		if c.Config().GoogleConf == nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("SSO is not configured for this instance"),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		// the user that enforces SSO would otherwise lose access to the project
+		if !authz.IsSSOUser(user) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("SSO can only be enforced by a user that signed in through SSO"),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+	}
+
+	proj.SSOEnforced = request.Enforce
+
+	proj, err := c.Repo().Project().UpdateProject(proj)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, proj.ToProjectType())
+}
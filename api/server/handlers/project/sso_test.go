@@ -0,0 +1,122 @@
+package project_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/project"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/billing"
+	"github.com/porter-dev/porter/internal/models"
+	"golang.org/x/oauth2"
+)
+
+func updateTestProjectSSO(
+	t *testing.T,
+	config *config.Config,
+	user *models.User,
+	proj *models.Project,
+	enforce bool,
+) *httptest.ResponseRecorder {
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/sso",
+		&types.UpdateProjectSSORequest{Enforce: enforce},
+	)
+
+	req = apitest.WithAuthenticatedUser(t, req, user)
+	req = apitest.WithProject(t, req, proj)
+
+	handler := project.NewUpdateProjectSSOHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestUpdateProjectSSO(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name: "test-project",
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := updateTestProjectSSO(t, config, user, proj, true)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "SSO is not configured for this instance",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+
+	config.GoogleConf = &oauth2.Config{}
+
+	// the user would lose access to the project if they did not sign in through SSO
+	rr = updateTestProjectSSO(t, config, user, proj, true)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "SSO can only be enforced by a user that signed in through SSO",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+
+	user.GoogleUserID = "google-user"
+
+	rr = updateTestProjectSSO(t, config, user, proj, true)
+
+	apitest.AssertResponseExpected(t, rr, &types.Project{
+		ID:          proj.ID,
+		Name:        proj.Name,
+		Roles:       proj.ToProjectType().Roles,
+		SSOEnforced: true,
+	}, &types.Project{})
+}
+
+func TestUpdateProjectSSOEntitlement(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	config.GoogleConf = &oauth2.Config{}
+	config.EntitlementManager = billing.NewEntitlementManager(config.Repo, false, false, &types.License{
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	user := apitest.CreateTestUser(t, config, true)
+	user.GoogleUserID = "google-user"
+
+	proj, _, err := project.CreateProjectWithUser(config.Repo.Project(), &models.Project{
+		Name:        "test-project",
+		SSOEnforced: true,
+	}, user)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := updateTestProjectSSO(t, config, user, proj, true)
+
+	if rr.Result().StatusCode != http.StatusPaymentRequired {
+		t.Errorf("expected status code %d for a license without SSO, got %d", http.StatusPaymentRequired, rr.Result().StatusCode)
+	}
+
+	// enforcement can be turned off without the entitlement
+	rr = updateTestProjectSSO(t, config, user, proj, false)
+
+	if rr.Result().StatusCode != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d: %s", http.StatusOK, rr.Result().StatusCode, rr.Body.String())
+	}
+
+	if proj, err := config.Repo.Project().ReadProject(proj.ID); err != nil || proj.SSOEnforced {
+		t.Errorf("expected SSO not to be enforced, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package provision
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+)
+
+type EstimateCostHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewEstimateCostHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *EstimateCostHandler {
+	return &EstimateCostHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP estimates the monthly cost of a provisioning request before the infra is
+// provisioned
+func (c *EstimateCostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &types.EstimateCostRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	res, err := c.Config().CostEstimator.Estimate(request.Kind, request.Config)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -1,11 +1,19 @@
 package provision
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/aws/rdsnetwork"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/aws/s3"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/aws/sqs"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/cloudsqlnetwork"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/gcs"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/pubsub"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/random"
 	"golang.org/x/crypto/bcrypt"
@@ -53,10 +61,17 @@ func GetSharedProvisionerOpts(conf *config.Config, infra *models.Infra) (*provis
 		return nil, err
 	}
 
+	// infras are pinned to the module version they were last applied with
+	imageTag := infra.ModuleVersion
+
+	if imageTag == "" {
+		imageTag = conf.ServerConf.ProvisionerImageTag
+	}
+
 	return &provisioner.ProvisionOpts{
 		DryRun:              true,
 		Infra:               infra,
-		ProvImageTag:        conf.ServerConf.ProvisionerImageTag,
+		ProvImageTag:        imageTag,
 		ProvJobNamespace:    conf.ServerConf.ProvisionerJobNamespace,
 		ProvImagePullSecret: conf.ServerConf.ProvisionerImagePullSecret,
 		TFHTTPBackendURL:    conf.ServerConf.ProvisionerBackendURL,
@@ -68,3 +83,187 @@ func GetSharedProvisionerOpts(conf *config.Config, infra *models.Infra) (*provis
 		},
 	}, nil
 }
+
+// GetBucketProvisionerOpts returns the options for an operation on an S3 or GCS bucket
+// infra from its last-applied configuration, using the cloud integration of the infra
+func GetBucketProvisionerOpts(conf *config.Config, infra *models.Infra) (*provisioner.ProvisionOpts, error) {
+	lastApplied := &types.BucketInfraLastApplied{}
+
+	if err := json.Unmarshal(infra.LastApplied, lastApplied); err != nil {
+		return nil, err
+	}
+
+	if lastApplied.CreateBucketInfraRequest == nil {
+		return nil, fmt.Errorf("bucket infra %d has no last applied configuration", infra.ID)
+	}
+
+	if infra.Kind != types.InfraS3 && infra.Kind != types.InfraGCS {
+		return nil, fmt.Errorf("infra of kind %s is not a bucket", infra.Kind)
+	}
+
+	opts, err := getCloudProvisionerOpts(conf, infra)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if infra.Kind == types.InfraS3 {
+		opts.S3 = s3.NewConf(lastApplied)
+	} else {
+		opts.GCS = gcs.NewConf(lastApplied)
+	}
+
+	return opts, nil
+}
+
+// GetQueueProvisionerOpts returns the options for an operation on an SQS or Pub/Sub queue
+// infra from its last-applied configuration, using the cloud integration of the infra
+func GetQueueProvisionerOpts(conf *config.Config, infra *models.Infra) (*provisioner.ProvisionOpts, error) {
+	lastApplied := &types.QueueInfraLastApplied{}
+
+	if err := json.Unmarshal(infra.LastApplied, lastApplied); err != nil {
+		return nil, err
+	}
+
+	if lastApplied.CreateQueueInfraRequest == nil {
+		return nil, fmt.Errorf("queue infra %d has no last applied configuration", infra.ID)
+	}
+
+	if infra.Kind != types.InfraSQS && infra.Kind != types.InfraPubSub {
+		return nil, fmt.Errorf("infra of kind %s is not a queue", infra.Kind)
+	}
+
+	opts, err := getCloudProvisionerOpts(conf, infra)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if infra.Kind == types.InfraSQS {
+		opts.SQS = sqs.NewConf(lastApplied)
+	} else {
+		opts.PubSub = pubsub.NewConf(lastApplied)
+	}
+
+	return opts, nil
+}
+
+// GetProvisionedResourceProvisionerOpts returns the options for an operation on an infra
+// that provisions a bucket or a queue
+func GetProvisionedResourceProvisionerOpts(conf *config.Config, infra *models.Infra) (*provisioner.ProvisionOpts, error) {
+	switch infra.Kind {
+	case types.InfraS3, types.InfraGCS:
+		return GetBucketProvisionerOpts(conf, infra)
+	case types.InfraSQS, types.InfraPubSub:
+		return GetQueueProvisionerOpts(conf, infra)
+	}
+
+	return nil, fmt.Errorf("infra of kind %s is not a bucket or a queue", infra.Kind)
+}
+
+// GetPrivateNetworkProvisionerOpts returns the options for an operation on the private
+// network between a cluster and a database from its last-applied configuration
+func GetPrivateNetworkProvisionerOpts(conf *config.Config, infra *models.Infra) (*provisioner.ProvisionOpts, error) {
+	lastApplied := &types.PrivateNetworkInfraLastApplied{}
+
+	if err := json.Unmarshal(infra.LastApplied, lastApplied); err != nil {
+		return nil, err
+	}
+
+	if lastApplied.CreatePrivateNetworkInfraRequest == nil {
+		return nil, fmt.Errorf("private network infra %d has no last applied configuration", infra.ID)
+	}
+
+	if infra.Kind != types.InfraRDSNetwork && infra.Kind != types.InfraCloudSQLNetwork {
+		return nil, fmt.Errorf("infra of kind %s is not a private network", infra.Kind)
+	}
+
+	opts, err := getCloudProvisionerOpts(conf, infra)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if infra.Kind == types.InfraRDSNetwork {
+		opts.RDSNetwork = rdsnetwork.NewConf(lastApplied)
+	} else {
+		opts.CloudSQLNetwork = cloudsqlnetwork.NewConf(lastApplied)
+	}
+
+	return opts, nil
+}
+
+// getCloudProvisionerOpts returns the shared options for an infra that is provisioned
+// with the AWS or GCP integration of the infra, with a vault token for the integration
+// if a credential backend is set
+func getCloudProvisionerOpts(conf *config.Config, infra *models.Infra) (*provisioner.ProvisionOpts, error) {
+	vaultToken := ""
+
+	if infra.AWSIntegrationID != 0 {
+		awsInt, err := conf.Repo.AWSIntegration().ReadAWSIntegration(infra.ProjectID, infra.AWSIntegrationID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if conf.CredentialBackend != nil {
+			vaultToken, err = conf.CredentialBackend.CreateAWSToken(awsInt)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if infra.GCPIntegrationID != 0 {
+		gcpInt, err := conf.Repo.GCPIntegration().ReadGCPIntegration(infra.ProjectID, infra.GCPIntegrationID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if conf.CredentialBackend != nil {
+			vaultToken, err = conf.CredentialBackend.CreateGCPToken(gcpInt)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		return nil, fmt.Errorf("infra %d has no AWS or GCP integration", infra.ID)
+	}
+
+	opts, err := GetSharedProvisionerOpts(conf, infra)
+
+	if err != nil {
+		return nil, err
+	}
+
+	opts.CredentialExchange.VaultToken = vaultToken
+
+	return opts, nil
+}
+
+// Provision claims the operation lease for the infra, if leases are enabled, and
+// creates the provisioner job for the operation with the generation of the lease. If
+// another operation is in progress for the infra, lease.ErrLeaseHeld is returned.
+func Provision(conf *config.Config, opts *provisioner.ProvisionOpts) error {
+	// plans do not modify the infra, so they may run alongside other operations
+	useLease := conf.ProvisionerLeases != nil && opts.OperationKind != provisioner.Plan
+
+	if useLease {
+		generation, err := conf.ProvisionerLeases.Claim(opts.Infra.GetUniqueName())
+
+		if err != nil {
+			return err
+		}
+
+		opts.LeaseGeneration = generation
+	}
+
+	err := conf.ProvisionerAgent.Provision(opts)
+
+	if err != nil && useLease {
+		conf.ProvisionerLeases.Release(opts.Infra.GetUniqueName(), opts.LeaseGeneration)
+	}
+
+	return err
+}
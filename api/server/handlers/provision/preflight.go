@@ -0,0 +1,111 @@
+package provision
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/preflight"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type PreflightHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewPreflightHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *PreflightHandler {
+	return &PreflightHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP checks the permissions of an integration of the project for an infra kind,
+// and returns a pass or fail result for each permission
+func (c *PreflightHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.PreflightRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	var checks []*types.PreflightCheck
+
+	if actions, ok := preflight.AWSActions[request.Kind]; ok {
+		if request.AWSIntegrationID == 0 {
+			c.HandleAPIError(w, r, missingIntegrationError(request.Kind, "aws_integration_id"))
+			return
+		}
+
+		awsInt, err := c.Repo().AWSIntegration().ReadAWSIntegration(proj.ID, request.AWSIntegrationID)
+
+		if err != nil {
+			c.HandleAPIError(w, r, c.qualifyGormError(err))
+			return
+		}
+
+		checks = preflight.CheckAWS(awsInt, actions)
+	} else if permissions, ok := preflight.GCPPermissions[request.Kind]; ok {
+		if request.GCPIntegrationID == 0 {
+			c.HandleAPIError(w, r, missingIntegrationError(request.Kind, "gcp_integration_id"))
+			return
+		}
+
+		gcpInt, err := c.Repo().GCPIntegration().ReadGCPIntegration(proj.ID, request.GCPIntegrationID)
+
+		if err != nil {
+			c.HandleAPIError(w, r, c.qualifyGormError(err))
+			return
+		}
+
+		checks = preflight.CheckGCP(gcpInt, permissions)
+	} else if preflight.DOKinds[request.Kind] {
+		if request.DOIntegrationID == 0 {
+			c.HandleAPIError(w, r, missingIntegrationError(request.Kind, "do_integration_id"))
+			return
+		}
+
+		doInt, err := c.Repo().OAuthIntegration().ReadOAuthIntegration(proj.ID, request.DOIntegrationID)
+
+		if err != nil {
+			c.HandleAPIError(w, r, c.qualifyGormError(err))
+			return
+		}
+
+		checks = preflight.CheckDO(doInt, request.Kind)
+	} else {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("preflight checks are not supported for infras of kind %s", request.Kind),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	c.WriteResult(w, r, preflight.NewResponse(request.Kind, checks))
+}
+
+func (c *PreflightHandler) qualifyGormError(err error) apierrors.RequestError {
+	if err == gorm.ErrRecordNotFound {
+		return apierrors.NewErrForbidden(err)
+	}
+
+	return apierrors.NewErrInternal(err)
+}
+
+func missingIntegrationError(kind types.InfraKind, field string) apierrors.RequestError {
+	return apierrors.NewErrPassThroughToClient(
+		fmt.Errorf("%s is required for infras of kind %s", field, kind),
+		http.StatusBadRequest,
+	)
+}
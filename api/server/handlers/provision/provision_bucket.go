@@ -0,0 +1,146 @@
+package provision
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ProvisionBucketHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewProvisionBucketHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ProvisionBucketHandler {
+	return &ProvisionBucketHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP provisions an S3 bucket for clusters with an AWS integration, or a GCS bucket
+// for clusters with a GCP integration. Once the bucket is created, its credentials are
+// stored in an env group in the namespace.
+func (c *ProvisionBucketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.CreateBucketInfraRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	suffix, err := repository.GenerateRandomBytes(6)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	bucketInfra := &models.Infra{
+		ProjectID:       proj.ID,
+		Status:          types.StatusCreating,
+		Suffix:          suffix,
+		CreatedByUserID: user.ID,
+		ModuleVersion:   c.Config().ServerConf.ProvisionerImageTag,
+	}
+
+	lastAppliedData := &types.BucketInfraLastApplied{
+		CreateBucketInfraRequest: request,
+		ClusterID:                cluster.ID,
+		Namespace:                namespace,
+	}
+
+	switch {
+	case cluster.AWSIntegrationID != 0:
+		integration, err := c.Repo().AWSIntegration().ReadAWSIntegration(proj.ID, cluster.AWSIntegrationID)
+
+		if err != nil {
+			c.HandleAPIError(w, r, c.qualifyGormError(err))
+			return
+		}
+
+		bucketInfra.Kind = types.InfraS3
+		bucketInfra.AWSIntegrationID = integration.ID
+		lastAppliedData.AWSRegion = integration.AWSRegion
+	case cluster.GCPIntegrationID != 0:
+		integration, err := c.Repo().GCPIntegration().ReadGCPIntegration(proj.ID, cluster.GCPIntegrationID)
+
+		if err != nil {
+			c.HandleAPIError(w, r, c.qualifyGormError(err))
+			return
+		}
+
+		bucketInfra.Kind = types.InfraGCS
+		bucketInfra.GCPIntegrationID = integration.ID
+		lastAppliedData.GCPProjectID = integration.GCPProjectID
+		lastAppliedData.GCPRegion = integration.GCPRegion
+	default:
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			errors.New("buckets can only be provisioned for clusters with an AWS or GCP integration"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	lastApplied, err := json.Marshal(lastAppliedData)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	bucketInfra.LastApplied = lastApplied
+
+	// handle write to the database
+	infra, err := c.Repo().Infra().CreateInfra(bucketInfra)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	opts, err := GetBucketProvisionerOpts(c.Config(), infra)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	opts.OperationKind = provisioner.Apply
+
+	err = Provision(c.Config(), opts)
+
+	if err != nil {
+		infra.Status = types.StatusError
+		infra, _ = c.Repo().Infra().UpdateInfra(infra)
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, infra.ToInfraType())
+}
+
+func (c *ProvisionBucketHandler) qualifyGormError(err error) apierrors.RequestError {
+	if err == gorm.ErrRecordNotFound {
+		return apierrors.NewErrForbidden(err)
+	}
+
+	return apierrors.NewErrInternal(err)
+}
@@ -79,6 +79,7 @@ func (c *ProvisionDOCRHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		Status:          types.StatusCreating,
 		DOIntegrationID: request.DOIntegrationID,
 		CreatedByUserID: user.ID,
+		ModuleVersion:   c.Config().ServerConf.ProvisionerImageTag,
 		LastApplied:     lastApplied,
 	}
 
@@ -116,7 +117,7 @@ func (c *ProvisionDOCRHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	opts.OperationKind = provisioner.Apply
 
-	err = c.Config().ProvisionerAgent.Provision(opts)
+	err = Provision(c.Config(), opts)
 
 	if err != nil {
 		infra.Status = types.StatusError
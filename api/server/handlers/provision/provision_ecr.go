@@ -79,6 +79,7 @@ func (c *ProvisionECRHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		Status:           types.StatusCreating,
 		AWSIntegrationID: request.AWSIntegrationID,
 		CreatedByUserID:  user.ID,
+		ModuleVersion:    c.Config().ServerConf.ProvisionerImageTag,
 		LastApplied:      lastApplied,
 	}
 
@@ -115,7 +116,7 @@ func (c *ProvisionECRHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 	opts.OperationKind = provisioner.Apply
 
-	err = c.Config().ProvisionerAgent.Provision(opts)
+	err = Provision(c.Config(), opts)
 
 	if err != nil {
 		infra.Status = types.StatusError
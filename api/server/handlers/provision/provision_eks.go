@@ -44,6 +44,11 @@ func (c *ProvisionEKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := request.Validate(); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
 	// get the AWS integration, to check that integration exists and belongs to the project
 	awsInt, err := c.Repo().AWSIntegration().ReadAWSIntegration(proj.ID, request.AWSIntegrationID)
 
@@ -79,6 +84,7 @@ func (c *ProvisionEKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		Status:           types.StatusCreating,
 		AWSIntegrationID: request.AWSIntegrationID,
 		CreatedByUserID:  user.ID,
+		ModuleVersion:    c.Config().ServerConf.ProvisionerImageTag,
 		LastApplied:      lastApplied,
 	}
 
@@ -109,15 +115,10 @@ func (c *ProvisionEKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 
 	opts.CredentialExchange.VaultToken = vaultToken
-	opts.EKS = &eks.Conf{
-		AWSRegion:   awsInt.AWSRegion,
-		ClusterName: request.EKSName,
-		MachineType: request.MachineType,
-		IssuerEmail: request.IssuerEmail,
-	}
+	opts.EKS = eks.NewConf(awsInt.AWSRegion, request)
 	opts.OperationKind = provisioner.Apply
 
-	err = c.Config().ProvisionerAgent.Provision(opts)
+	err = Provision(c.Config(), opts)
 
 	if err != nil {
 		infra.Status = types.StatusError
@@ -79,6 +79,7 @@ func (c *ProvisionGCRHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		Status:           types.StatusCreating,
 		GCPIntegrationID: request.GCPIntegrationID,
 		CreatedByUserID:  user.ID,
+		ModuleVersion:    c.Config().ServerConf.ProvisionerImageTag,
 		LastApplied:      lastApplied,
 	}
 
@@ -114,7 +115,7 @@ func (c *ProvisionGCRHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	opts.CredentialExchange.VaultToken = vaultToken
 	opts.OperationKind = provisioner.Apply
 
-	err = c.Config().ProvisionerAgent.Provision(opts)
+	err = Provision(c.Config(), opts)
 
 	if err != nil {
 		infra.Status = types.StatusError
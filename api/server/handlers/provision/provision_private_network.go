@@ -0,0 +1,272 @@
+package provision
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ProvisionPrivateNetworkHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewProvisionPrivateNetworkHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ProvisionPrivateNetworkHandler {
+	return &ProvisionPrivateNetworkHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP configures private networking between a cluster that was provisioned by
+// Porter and a managed database, as an infra that can be retried and destroyed like the
+// cluster and database themselves
+func (c *ProvisionPrivateNetworkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.CreatePrivateNetworkInfraRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if cluster.InfraID == 0 {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			errors.New("private networking can only be configured for clusters provisioned by Porter"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	clusterInfra, err := c.Repo().Infra().ReadInfra(proj.ID, cluster.InfraID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, c.qualifyGormError(err))
+		return
+	}
+
+	if clusterInfra.Status != types.StatusCreated {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("the infra of the cluster must have status %s", types.StatusCreated),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	suffix, err := repository.GenerateRandomBytes(6)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	networkInfra := &models.Infra{
+		ProjectID:        proj.ID,
+		Status:           types.StatusCreating,
+		Suffix:           suffix,
+		CreatedByUserID:  user.ID,
+		ModuleVersion:    c.Config().ServerConf.ProvisionerImageTag,
+		AWSIntegrationID: clusterInfra.AWSIntegrationID,
+		GCPIntegrationID: clusterInfra.GCPIntegrationID,
+	}
+
+	lastAppliedData := &types.PrivateNetworkInfraLastApplied{
+		CreatePrivateNetworkInfraRequest: request,
+		ClusterID:                        cluster.ID,
+		ClusterInfraID:                   clusterInfra.ID,
+	}
+
+	switch clusterInfra.Kind {
+	case types.InfraEKS:
+		networkInfra.Kind = types.InfraRDSNetwork
+
+		if reqErr := c.populateRDSNetwork(proj, cluster, clusterInfra, lastAppliedData); reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+	case types.InfraGKE:
+		networkInfra.Kind = types.InfraCloudSQLNetwork
+
+		if reqErr := c.populateCloudSQLNetwork(proj, clusterInfra, lastAppliedData); reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+	default:
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("private networking is not supported for clusters of kind %s", clusterInfra.Kind),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	lastApplied, err := json.Marshal(lastAppliedData)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	networkInfra.LastApplied = lastApplied
+
+	// handle write to the database
+	infra, err := c.Repo().Infra().CreateInfra(networkInfra)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	opts, err := GetPrivateNetworkProvisionerOpts(c.Config(), infra)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	opts.OperationKind = provisioner.Apply
+
+	err = Provision(c.Config(), opts)
+
+	if err != nil {
+		infra.Status = types.StatusError
+		infra, _ = c.Repo().Infra().UpdateInfra(infra)
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, infra.ToInfraType())
+}
+
+// populateRDSNetwork sets the config for opening an RDS infra to an EKS cluster, which
+// requires the database to have been provisioned for the cluster
+func (c *ProvisionPrivateNetworkHandler) populateRDSNetwork(
+	proj *models.Project,
+	cluster *models.Cluster,
+	clusterInfra *models.Infra,
+	lastApplied *types.PrivateNetworkInfraLastApplied,
+) apierrors.RequestError {
+	if lastApplied.DatabaseInfraID == 0 {
+		return apierrors.NewErrPassThroughToClient(
+			errors.New("database_infra_id is required for EKS clusters"),
+			http.StatusBadRequest,
+		)
+	}
+
+	dbInfra, err := c.Repo().Infra().ReadInfra(proj.ID, lastApplied.DatabaseInfraID)
+
+	if err != nil {
+		return c.qualifyGormError(err)
+	}
+
+	if dbInfra.Kind != types.InfraRDS || dbInfra.Status != types.StatusCreated {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("infra %d is not an RDS instance with status %s", dbInfra.ID, types.StatusCreated),
+			http.StatusBadRequest,
+		)
+	}
+
+	rdsLastApplied := &types.RDSInfraLastApplied{}
+
+	if err := json.Unmarshal(dbInfra.LastApplied, rdsLastApplied); err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if rdsLastApplied.ClusterID != cluster.ID {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("RDS instance %d was not provisioned for this cluster", dbInfra.ID),
+			http.StatusBadRequest,
+		)
+	}
+
+	database, err := c.Repo().Database().ReadDatabaseByInfraID(proj.ID, dbInfra.ID)
+
+	if err != nil {
+		return c.qualifyGormError(err)
+	}
+
+	eksLastApplied := &types.CreateEKSInfraRequest{}
+
+	if err := json.Unmarshal(clusterInfra.LastApplied, eksLastApplied); err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	awsInt, err := c.Repo().AWSIntegration().ReadAWSIntegration(proj.ID, clusterInfra.AWSIntegrationID)
+
+	if err != nil {
+		return c.qualifyGormError(err)
+	}
+
+	port := "5432"
+
+	if strArr := strings.Split(database.InstanceEndpoint, ":"); len(strArr) == 2 {
+		port = strArr[1]
+	}
+
+	lastApplied.AWSRegion = awsInt.AWSRegion
+	lastApplied.EKSName = eksLastApplied.EKSName
+	lastApplied.VPCID = rdsLastApplied.VPCID
+	lastApplied.DBInstanceID = database.InstanceID
+	lastApplied.DBInstancePort = port
+
+	return nil
+}
+
+// populateCloudSQLNetwork sets the config for connecting an existing Cloud SQL instance
+// to the network of a GKE cluster
+func (c *ProvisionPrivateNetworkHandler) populateCloudSQLNetwork(
+	proj *models.Project,
+	clusterInfra *models.Infra,
+	lastApplied *types.PrivateNetworkInfraLastApplied,
+) apierrors.RequestError {
+	if lastApplied.CloudSQLInstanceName == "" {
+		return apierrors.NewErrPassThroughToClient(
+			errors.New("cloudsql_instance_name is required for GKE clusters"),
+			http.StatusBadRequest,
+		)
+	}
+
+	gkeLastApplied := &types.CreateGKEInfraRequest{}
+
+	if err := json.Unmarshal(clusterInfra.LastApplied, gkeLastApplied); err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	gcpInt, err := c.Repo().GCPIntegration().ReadGCPIntegration(proj.ID, clusterInfra.GCPIntegrationID)
+
+	if err != nil {
+		return c.qualifyGormError(err)
+	}
+
+	lastApplied.GCPProjectID = gcpInt.GCPProjectID
+	lastApplied.GCPRegion = gkeLastApplied.GCPRegion
+	lastApplied.GKEName = gkeLastApplied.GKEName
+
+	return nil
+}
+
+func (c *ProvisionPrivateNetworkHandler) qualifyGormError(err error) apierrors.RequestError {
+	if err == gorm.ErrRecordNotFound {
+		return apierrors.NewErrForbidden(err)
+	}
+
+	return apierrors.NewErrInternal(err)
+}
@@ -124,6 +124,7 @@ func (c *ProvisionRDSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		Status:          types.StatusCreating,
 		Suffix:          suffix,
 		CreatedByUserID: user.ID,
+		ModuleVersion:   c.Config().ServerConf.ProvisionerImageTag,
 	}
 
 	switch clusterInfra.Kind {
@@ -244,7 +245,7 @@ func (c *ProvisionRDSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	opts.OperationKind = provisioner.Apply
 
-	err = c.Config().ProvisionerAgent.Provision(opts)
+	err = Provision(c.Config(), opts)
 	if err != nil {
 		infra.Status = types.StatusError
 		infra, _ = c.Repo().Infra().UpdateInfra(infra)
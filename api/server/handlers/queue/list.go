@@ -0,0 +1,45 @@
+package queue
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type QueueListHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewQueueListHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *QueueListHandler {
+	return &QueueListHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (p *QueueListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	queues, err := p.Repo().Queue().ListQueues(proj.ID, cluster.ID)
+
+	if err != nil {
+		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListQueueResponse, len(queues))
+
+	for i, queue := range queues {
+		res[i] = queue.ToQueueType()
+	}
+
+	p.WriteResult(w, r, res)
+}
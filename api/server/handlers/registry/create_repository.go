@@ -51,4 +51,9 @@ func (p *RegistryCreateRepositoryHandler) ServeHTTP(w http.ResponseWriter, r *ht
 		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
 	}
+
+	// the new repository is listed on the next listing of the registry
+	if p.Config().RegistryIndex != nil {
+		p.Config().RegistryIndex.Invalidate(reg.ID)
+	}
 }
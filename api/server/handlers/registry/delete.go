@@ -29,7 +29,10 @@ func (p *RegistryDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 
 	if err := p.Repo().Registry().DeleteRegistry(reg); err != nil {
 		p.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
 	}
 
-	return
+	if p.Config().RegistryIndex != nil {
+		p.Config().RegistryIndex.Invalidate(reg.ID)
+	}
 }
@@ -74,7 +74,7 @@ func (c *RegistryGetECRTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 				output, err := ecrSvc.GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{})
 
 				if err != nil {
-					c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+					c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrInternal(err), types.ErrorCodeRegistryAuth))
 					return
 				}
 
@@ -137,7 +137,7 @@ func (c *RegistryGetGCRTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 			if oauthTok != nil && err != nil {
 				c.HandleAPIErrorNoWrite(w, r, apierrors.NewErrInternal(err))
 			} else if err != nil {
-				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+				c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrInternal(err), types.ErrorCodeRegistryAuth))
 				return
 			}
 
@@ -205,7 +205,7 @@ func (c *RegistryGetDOCRTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.R
 			)
 
 			if err != nil {
-				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+				c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrInternal(err), types.ErrorCodeRegistryAuth))
 				return
 			}
 
@@ -35,7 +35,14 @@ func (c *RegistryListImagesHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 	_reg := registry.Registry(*reg)
 	regAPI := &_reg
 
-	imgs, err := regAPI.ListImages(repoName, c.Repo(), c.Config().DOConf)
+	var imgs []*types.Image
+	var err error
+
+	if c.Config().RegistryIndex != nil {
+		imgs, err = c.Config().RegistryIndex.ListImages(regAPI, repoName)
+	} else {
+		imgs, err = regAPI.ListImages(repoName, c.Repo(), c.Config().DOConf)
+	}
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -32,7 +32,14 @@ func (c *RegistryListRepositoriesHandler) ServeHTTP(w http.ResponseWriter, r *ht
 	_reg := registry.Registry(*reg)
 	regAPI := &_reg
 
-	repos, err := regAPI.ListRepositories(c.Repo(), c.Config().DOConf)
+	var repos []*types.RegistryRepository
+	var err error
+
+	if c.Config().RegistryIndex != nil {
+		repos, err = c.Config().RegistryIndex.ListRepositories(regAPI)
+	} else {
+		repos, err = regAPI.ListRepositories(c.Repo(), c.Config().DOConf)
+	}
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
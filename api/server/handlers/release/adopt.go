@@ -0,0 +1,367 @@
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// AdoptReleaseHandler adopts a Helm release that was installed outside of Porter, such as
+// with the Helm CLI, so that it can be upgraded and rolled back through Porter
+type AdoptReleaseHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewAdoptReleaseHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AdoptReleaseHandler {
+	return &AdoptReleaseHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *AdoptReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+
+	request := &types.AdoptReleaseRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if _, err := c.Repo().Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace); err == nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s is already managed by Porter", helmRelease.Name),
+			http.StatusBadRequest,
+		))
+
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.AdoptReleaseResponse{}
+	template, templateChart := getAdoptedTemplate(c.Config(), cluster, helmRelease.Chart)
+	res.Template = template
+
+	if request.UseTemplate {
+		if res.Template == nil {
+			c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("chart %s does not match a Porter template", helmRelease.Chart.Metadata.Name),
+				http.StatusBadRequest,
+			), types.ErrorCodeChartNotFound))
+
+			return
+		}
+
+		protected, err := isReleaseProtected(c.Repo(), cluster, helmRelease.Name, helmRelease.Namespace)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		if protected {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("namespace %s is protected, so the release must be adopted without upgrading it to the template", helmRelease.Namespace),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		currValues := helmRelease.Config
+
+		if currValues == nil {
+			currValues = make(map[string]interface{})
+		}
+
+		values, err := json.Marshal(currValues)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		// the release is upgraded with its current values to the same version of the chart,
+		// loaded from the template repo
+		_, _, reqErr := upgradeRelease(c.Config(), c.KubernetesAgentGetter, r, &upgradeOpts{
+			user:         user,
+			cluster:      cluster,
+			helmRelease:  helmRelease,
+			chart:        templateChart,
+			chartRepoURL: template.RepoURL,
+			request: &types.UpgradeReleaseRequest{
+				Values:       string(values),
+				ChartVersion: helmRelease.Chart.Metadata.Version,
+				Message:      "Adopted into Porter",
+			},
+		})
+
+		if reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+
+		res.Template.Applied = true
+	}
+
+	rel, err := createAdoptedRelease(c.Config(), cluster, helmRelease)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res.PorterRelease = rel.ToReleaseType()
+
+	c.WriteResult(w, r, res)
+}
+
+// AdoptManifestsHandler adopts objects that were applied from raw manifests, such as with
+// kubectl apply, into a new Helm release that Porter manages
+type AdoptManifestsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewAdoptManifestsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *AdoptManifestsHandler {
+	return &AdoptManifestsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *AdoptManifestsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	request := &types.AdoptManifestsRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if _, err := helmAgent.GetRelease(request.Name, 0, false); err == nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s already exists", request.Name),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	objs, err := helm.ParseManifests([]byte(request.Manifests), namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the manifests identify the objects to adopt, but the release is created from the live
+	// objects, so that adopting them does not change them
+	liveObjs := make([]*unstructured.Unstructured, 0, len(objs))
+	adoptedObjs := make([]*unstructured.Unstructured, 0, len(objs))
+
+	for _, obj := range objs {
+		target := obj.DeepCopy()
+		target.SetNamespace(namespace)
+
+		live, err := agent.GetObject(target)
+
+		if errors.Is(err, kubernetes.IsNotFoundError) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("%s does not exist in namespace %s", helm.ObjectKey(obj), namespace),
+				http.StatusBadRequest,
+			))
+
+			return
+		} else if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("could not read %s: %s", helm.ObjectKey(obj), err.Error()),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		if owner := live.GetAnnotations()[helm.AnnotationReleaseName]; owner != "" && owner != request.Name {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("%s belongs to release %s", helm.ObjectKey(obj), owner),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		liveObjs = append(liveObjs, live)
+		adoptedObjs = append(adoptedObjs, helm.GetAdoptedObject(live, request.Name, namespace))
+	}
+
+	chart, err := helm.NewManifestChart(request.Name, adoptedObjs)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	registries, err := c.Repo().Registry().ListRegistriesByProjectID(cluster.ProjectID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// Helm fails to install a release whose objects already exist, unless the objects are
+	// labeled and annotated as objects of the release. The metadata is removed again if
+	// the release is not installed, so that the objects do not belong to a release that
+	// does not exist.
+	labels, annotations := helm.GetAdoptionMetadata(request.Name, namespace)
+	patched := make([]*unstructured.Unstructured, 0, len(liveObjs))
+
+	rollback := func() {
+		for _, live := range patched {
+			if err := agent.RestoreObjectMetadata(live, labels, annotations); err != nil {
+				c.Config().Logger.Error().Err(err).Msgf("could not remove the adoption metadata of %s", helm.ObjectKey(live))
+			}
+		}
+	}
+
+	for _, live := range liveObjs {
+		if err := agent.PatchObjectMetadata(live, labels, annotations); err != nil {
+			rollback()
+
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("could not adopt %s: %s", helm.ObjectKey(live), err.Error()),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		patched = append(patched, live)
+	}
+
+	helmRelease, err := helmAgent.InstallChart(&helm.InstallChartConfig{
+		Chart:      chart,
+		Name:       request.Name,
+		Namespace:  namespace,
+		Values:     make(map[string]interface{}),
+		Cluster:    cluster,
+		Repo:       c.Repo(),
+		Registries: registries,
+	}, c.Config().DOConf)
+
+	if err != nil {
+		rollback()
+
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error adopting manifests: %s", err.Error()),
+			http.StatusBadRequest,
+		), types.ErrorCodeHelmOperationFailed))
+
+		return
+	}
+
+	rel, err := createAdoptedRelease(c.Config(), cluster, helmRelease)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.AdoptReleaseResponse{
+		PorterRelease: rel.ToReleaseType(),
+	})
+}
+
+// getAdoptedTemplate returns the Porter template that matches the chart of an adopted
+// release, along with the chart of the template. A chart only matches a template if the
+// template repos have a chart with the same name and version that is identical to it,
+// since charts with common names, such as web or redis, are often unrelated to the
+// Porter templates.
+func getAdoptedTemplate(config *config.Config, cluster *models.Cluster, ch *chart.Chart) (*types.AdoptedTemplate, *chart.Chart) {
+	chartRepoURL, found := getChartRepoURL(config, cluster.ProjectID, ch.Metadata.Name)
+
+	if !found {
+		return nil, nil
+	}
+
+	templateChart, err := loadChart(config, cluster, chartRepoURL, ch.Metadata.Name, ch.Metadata.Version)
+
+	if err != nil || !helm.IsSameChart(ch, templateChart) {
+		return nil, nil
+	}
+
+	return &types.AdoptedTemplate{
+		Name:    ch.Metadata.Name,
+		Version: ch.Metadata.Version,
+		RepoURL: chartRepoURL,
+	}, templateChart
+}
+
+// createAdoptedRelease creates the release of an adopted Helm release. Unlike releases
+// that are created from Porter templates, adopted releases may not have an image
+// repository in their values.
+func createAdoptedRelease(config *config.Config, cluster *models.Cluster, helmRelease *release.Release) (*models.Release, error) {
+	token, err := repository.GenerateRandomBytes(16)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rel := &models.Release{
+		ClusterID:    cluster.ID,
+		ProjectID:    cluster.ProjectID,
+		Namespace:    helmRelease.Namespace,
+		Name:         helmRelease.Name,
+		WebhookToken: token,
+	}
+
+	if image, ok := helmRelease.Config["image"].(map[string]interface{}); ok {
+		rel.ImageRepoURI, _ = image["repository"].(string)
+	}
+
+	return config.Repo.Release().CreateRelease(rel)
+}
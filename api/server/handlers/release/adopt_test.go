@@ -0,0 +1,182 @@
+package release_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/urlcache"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestAdoptReleaseWithUnrelatedChartOfSameName(t *testing.T) {
+	config, user, cluster := createAdoptTestConfig(t)
+
+	// the template repo has a chart named web, which renders other templates than the
+	// chart of the release
+	withAdoptTestRepo(t, config, &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "web", Version: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/deployment.yaml", Data: []byte("kind: Deployment")},
+		},
+	})
+
+	rr := adoptTestRelease(t, config, user, cluster, true)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "chart web does not match a Porter template",
+		ErrorCode: types.ErrorCodeChartNotFound,
+	})
+
+	rr = adoptTestRelease(t, config, user, cluster, false)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "release should be adopted without a template")
+
+	res := &types.AdoptReleaseResponse{}
+
+	if err := json.NewDecoder(rr.Body).Decode(res); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, res.Template, "chart should not match the template of the same name")
+
+	if _, err := config.Repo.Release().ReadRelease(cluster.ID, "web", "default"); err != nil {
+		t.Errorf("expected the adopted release to be created: %v", err)
+	}
+}
+
+func TestAdoptReleaseWithTemplateChart(t *testing.T) {
+	config, user, cluster := createAdoptTestConfig(t)
+
+	withAdoptTestRepo(t, config, &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "web", Version: "0.1.0"},
+	})
+
+	rr := adoptTestRelease(t, config, user, cluster, false)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "release should be adopted")
+
+	res := &types.AdoptReleaseResponse{}
+
+	if err := json.NewDecoder(rr.Body).Decode(res); err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.NotNil(t, res.Template, "chart should match the identical template") {
+		assert.Equal(t, "web", res.Template.Name)
+		assert.Equal(t, "0.1.0", res.Template.Version)
+		assert.False(t, res.Template.Applied, "template should not be applied")
+	}
+}
+
+func TestAdoptManagedRelease(t *testing.T) {
+	config, user, cluster := createAdoptTestConfig(t)
+
+	if _, err := config.Repo.Release().CreateRelease(&models.Release{
+		ClusterID: cluster.ID,
+		Name:      "web",
+		Namespace: "default",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := adoptTestRelease(t, config, user, cluster, false)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     "release web is already managed by Porter",
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+}
+
+func createAdoptTestConfig(t *testing.T) (*config.Config, *models.User, *models.Cluster) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	cluster := &models.Cluster{ProjectID: 1, NotificationsDisabled: true}
+	cluster.ID = 1
+
+	if _, err := config.Repo.Project().CreateProject(&models.Project{Name: "project"}); err != nil {
+		t.Fatal(err)
+	}
+
+	return config, user, cluster
+}
+
+// withAdoptTestRepo serves a chart from a chart repo, which is the default repo of the
+// chart cache of the config
+func withAdoptTestRepo(t *testing.T, config *config.Config, ch *chart.Chart) {
+	filename, err := chartutil.Save(ch, t.TempDir())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := ioutil.ReadFile(filename)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := `apiVersion: v1
+entries:
+  web:
+  - apiVersion: v2
+    name: web
+    version: 0.1.0
+    urls:
+    - charts/web-0.1.0.tgz
+`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			w.Write([]byte(index))
+		case "/charts/web-0.1.0.tgz":
+			w.Write(archive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	config.URLCache = urlcache.Init(urlcache.Opts{}, urlcache.Repo{URL: server.URL})
+}
+
+func adoptTestRelease(
+	t *testing.T,
+	config *config.Config,
+	user *models.User,
+	cluster *models.Cluster,
+	useTemplate bool,
+) *httptest.ResponseRecorder {
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/adopt",
+		&types.AdoptReleaseRequest{
+			UseTemplate: useTemplate,
+		},
+	)
+
+	req = withReleaseScopes(t, req, user, cluster, getTestHelmRelease(1, map[string]interface{}{}))
+
+	handler := release.NewAdoptReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
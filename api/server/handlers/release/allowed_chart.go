@@ -0,0 +1,80 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// checkChartAllowed returns an error if the chart allow-list of a project does not allow
+// a chart. Projects without an allow-list allow every chart. If the repo URL of the chart
+// is empty, its repository is unknown, so the chart is only allowed by entries that allow
+// it from every repository.
+func checkChartAllowed(config *config.Config, projectID uint, repoURL, chartName string) apierrors.RequestError {
+	allowed, err := config.Repo.AllowedChart().ListAllowedChartsByProjectID(projectID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	return matchAllowedCharts(allowed, repoURL, chartName)
+}
+
+func matchAllowedCharts(allowed []*models.AllowedChart, repoURL, chartName string) apierrors.RequestError {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _, entry := range allowed {
+		if entry.Matches(repoURL, chartName) {
+			return nil
+		}
+	}
+
+	if repoURL == "" {
+		return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("the repository of chart %s is unknown, and the chart is not allowed from every repository by the allow-list of the project", chartName),
+			http.StatusForbidden,
+		), types.ErrorCodeChartNotAllowed)
+	}
+
+	return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+		fmt.Errorf("chart %s from %s is not in the allow-list of the project", chartName, repoURL),
+		http.StatusForbidden,
+	), types.ErrorCodeChartNotAllowed)
+}
+
+// CheckReleaseChartAllowed returns an error if the chart allow-list of a project does not
+// allow a chart that is deployed to a release, such as the chart of an upgrade, rollback
+// or promotion. If the repo URL is empty, the repository of the chart is looked up from
+// the chart repos of the project.
+func CheckReleaseChartAllowed(config *config.Config, projectID uint, ch *chart.Chart, repoURL string) apierrors.RequestError {
+	if ch == nil || ch.Metadata == nil {
+		return nil
+	}
+
+	allowed, err := config.Repo.AllowedChart().ListAllowedChartsByProjectID(projectID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	// the repository of the chart is only looked up if the project has an allow-list
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	if repoURL == "" && helm.IsCustomChart(ch) {
+		repoURL = types.CustomChartRepoURL
+	} else if repoURL == "" {
+		repoURL, _ = getChartRepoURL(config, projectID, ch.Metadata.Name)
+	}
+
+	return matchAllowedCharts(allowed, repoURL, ch.Metadata.Name)
+}
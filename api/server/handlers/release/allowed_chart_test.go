@@ -0,0 +1,57 @@
+package release_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+)
+
+func TestRollbackToChartNotInAllowList(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	_, err := config.Repo.AllowedChart().CreateAllowedChart(&models.AllowedChart{
+		ProjectID: 1,
+		RepoURL:   "https://charts.getporter.dev",
+		ChartName: "web",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the custom chart of the earlier revision is not in the allow-list
+	prevRelease := getPreDeployTestHelmRelease(1, "v1")
+	prevRelease.Info.Status = helmrelease.StatusSuperseded
+
+	helmRelease := getPreDeployTestHelmRelease(2, "v2")
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/rollback",
+		&types.RollbackReleaseRequest{
+			Revision: 1,
+		},
+	)
+
+	req = withPreDeployTestAgents(t, config, withReleaseScopes(t, req, user, cluster, helmRelease), prevRelease, helmRelease)
+
+	handler := release.NewRollbackReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusForbidden, &types.ExternalError{
+		Error:     "chart web from porter://custom-charts is not in the allow-list of the project",
+		ErrorCode: types.ErrorCodeChartNotAllowed,
+	})
+}
@@ -0,0 +1,58 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/imagemirror"
+)
+
+// checkImagesAllowed returns an error if the images of a release, from its values and
+// the default values of its chart, are not pulled from the allowed registries of a
+// project. The default images that are deployed before an application is built are
+// always allowed, and projects without an allow-list allow every image.
+func checkImagesAllowed(
+	config *config.Config,
+	projectID uint,
+	chartValues, values map[string]interface{},
+) apierrors.RequestError {
+	project, err := config.Repo.Project().ReadProject(projectID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	allowed := project.GetAllowedRegistries()
+
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	notAllowed := make([]string, 0)
+
+	for path, image := range imagemirror.ReleaseImages(chartValues, values) {
+		if !project.IsImageAllowed(image) && !config.ImageMirror.IsDefaultImage(image) {
+			notAllowed = append(notAllowed, fmt.Sprintf("%s: %s", path, image))
+		}
+	}
+
+	if len(notAllowed) == 0 {
+		return nil
+	}
+
+	sort.Strings(notAllowed)
+
+	return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+		fmt.Errorf(
+			"images must be pulled from the allowed registries of the project (%s): %s",
+			strings.Join(allowed, ", "),
+			strings.Join(notAllowed, ", "),
+		),
+		http.StatusForbidden,
+	), types.ErrorCodeImageNotAllowed)
+}
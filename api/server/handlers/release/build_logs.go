@@ -0,0 +1,180 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type CreateBuildLogHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateBuildLogHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateBuildLogHandler {
+	return &CreateBuildLogHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *CreateBuildLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &types.CreateBuildLogRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	release, ok := readBuildLogRelease(c.PorterHandlerReadWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	logs := []byte(request.Logs)
+
+	if len(logs) > types.MaxBuildLogBytes {
+		logs = logs[len(logs)-types.MaxBuildLogBytes:]
+	}
+
+	buildLog, err := c.Repo().BuildLog().CreateBuildLog(&models.BuildLog{
+		ReleaseID: release.ID,
+		CommitSHA: request.CommitSHA,
+		Status:    request.Status,
+		Logs:      logs,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, buildLog.ToBuildLogType(false))
+}
+
+type ListBuildLogsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewListBuildLogsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListBuildLogsHandler {
+	return &ListBuildLogsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListBuildLogsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	release, ok := readBuildLogRelease(c.PorterHandlerReadWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	buildLogs, err := c.Repo().BuildLog().ListBuildLogsByReleaseID(release.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListBuildLogsResponse, 0, len(buildLogs))
+
+	for _, buildLog := range buildLogs {
+		res = append(res, buildLog.ToBuildLogType(false))
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type GetBuildLogHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewGetBuildLogHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GetBuildLogHandler {
+	return &GetBuildLogHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *GetBuildLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	commitSHA, reqErr := requestutils.GetURLParamString(r, types.URLParamCommitSHA)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	release, ok := readBuildLogRelease(c.PorterHandlerReadWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	buildLog, err := c.Repo().BuildLog().ReadBuildLog(release.ID, commitSHA)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("no build log found for commit %s", commitSHA),
+				http.StatusNotFound,
+			))
+
+			return
+		}
+
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := types.GetBuildLogResponse(*buildLog.ToBuildLogType(true))
+
+	c.WriteResult(w, r, &res)
+}
+
+// readBuildLogRelease reads the release that build logs and SBOMs are stored for. They
+// are kept with the release model rather than a Helm revision, since a build may fail
+// before a revision is created.
+func readBuildLogRelease(
+	c handlers.PorterHandlerReadWriter,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*models.Release, bool) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	release, err := c.Repo().Release().ReadRelease(cluster.ID, name, namespace)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("release %s not found", name),
+				http.StatusNotFound,
+			), types.ErrorCodeReleaseNotFound))
+
+			return nil, false
+		}
+
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	return release, true
+}
@@ -0,0 +1,170 @@
+package release
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/buildpacks"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// getBuildpackDetection returns the buildpacks that were last detected on the branch and
+// folder of a git action config, or nil if the config builds from a Dockerfile or the repo
+// was never detected
+func getBuildpackDetection(
+	config *config.Config,
+	projectID uint,
+	gitRepoID uint,
+	gitRepo, gitBranch, folderPath, dockerfilePath string,
+) (*models.BuildpackDetection, *buildpacks.BuilderInfo) {
+	if dockerfilePath != "" {
+		return nil, nil
+	}
+
+	detection, builders := readLatestBuildpackDetection(config, projectID, gitRepoID, gitRepo, gitBranch, folderPath)
+
+	if detection == nil || detection.SuggestedBuilder == "" {
+		return nil, nil
+	}
+
+	for _, builder := range builders {
+		for _, b := range builder.Builders {
+			if b == detection.SuggestedBuilder {
+				return detection, builder
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// getGeneratedDockerfile returns the path and contents of the generated Dockerfile of
+// the runtime that was last detected on the branch and folder of a git action config, if
+// the runtime has no buildpack. Configs that build from another Dockerfile have no
+// generated Dockerfile.
+func getGeneratedDockerfile(
+	config *config.Config,
+	projectID uint,
+	gitRepoID uint,
+	gitRepo, gitBranch, folderPath, dockerfilePath string,
+) (string, string) {
+	generatedPath := "./" + path.Join(
+		strings.Trim(path.Clean("/"+folderPath), "/"),
+		buildpacks.GeneratedDockerfileName,
+	)
+
+	if dockerfilePath != "" && path.Clean(dockerfilePath) != path.Clean(generatedPath) {
+		return "", ""
+	}
+
+	detection, builders := readLatestBuildpackDetection(config, projectID, gitRepoID, gitRepo, gitBranch, folderPath)
+
+	if detection == nil || detection.SuggestedBuilder != "" {
+		return "", ""
+	}
+
+	for _, builder := range builders {
+		for _, fallback := range builder.Fallbacks {
+			if fallback.Name == detection.Runtime && fallback.Dockerfile != "" {
+				return generatedPath, fallback.Dockerfile
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// readLatestBuildpackDetection returns the buildpacks that were last detected on the
+// branch and folder of a git repo, or nil if the repo was never detected
+func readLatestBuildpackDetection(
+	config *config.Config,
+	projectID uint,
+	gitRepoID uint,
+	gitRepo, gitBranch, folderPath string,
+) (*models.BuildpackDetection, []*buildpacks.BuilderInfo) {
+	repoSplit := strings.Split(gitRepo, "/")
+
+	if len(repoSplit) != 2 {
+		return nil, nil
+	}
+
+	detection, err := config.Repo.BuildpackDetection().ReadLatestBuildpackDetection(
+		projectID,
+		gitRepoID,
+		repoSplit[0],
+		repoSplit[1],
+		gitBranch,
+		strings.Trim(path.Clean("/"+folderPath), "/"),
+	)
+
+	if err != nil {
+		return nil, nil
+	}
+
+	var builders []*buildpacks.BuilderInfo
+
+	if err := json.Unmarshal(detection.Builders, &builders); err != nil {
+		return nil, nil
+	}
+
+	return detection, builders
+}
+
+// getBuildConfigFromDetection creates a build config request from the buildpacks that
+// were detected for a git action config, so that apps that are created without a build
+// config build with the suggested builder
+func getBuildConfigFromDetection(
+	config *config.Config,
+	projectID uint,
+	gaRequest *types.CreateGitActionConfigRequest,
+) *types.CreateBuildConfigRequest {
+	detection, builder := getBuildpackDetection(
+		config,
+		projectID,
+		gaRequest.GitRepoID,
+		gaRequest.GitRepo,
+		gaRequest.GitBranch,
+		gaRequest.FolderPath,
+		gaRequest.DockerfilePath,
+	)
+
+	if detection == nil {
+		return nil
+	}
+
+	bcRequest := &types.CreateBuildConfigRequest{
+		Builder:    detection.SuggestedBuilder,
+		Buildpacks: make([]string, 0),
+	}
+
+	for _, bp := range builder.Detected {
+		bcRequest.Buildpacks = append(bcRequest.Buildpacks, bp.Buildpack)
+	}
+
+	return bcRequest
+}
+
+// addDetectedBuildEnv adds the env var hints of the detected buildpacks to a build env,
+// without overwriting env vars that are already set
+func addDetectedBuildEnv(buildEnv map[string]string, builder *buildpacks.BuilderInfo) map[string]string {
+	if buildEnv == nil {
+		buildEnv = make(map[string]string)
+	}
+
+	if builder == nil {
+		return buildEnv
+	}
+
+	for _, bp := range builder.Detected {
+		for key, val := range bp.EnvHints {
+			if _, ok := buildEnv[key]; !ok {
+				buildEnv[key] = val
+			}
+		}
+	}
+
+	return buildEnv
+}
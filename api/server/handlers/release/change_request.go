@@ -0,0 +1,382 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/integrations/slack"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// maxNotifyDiffLines is the maximum number of lines of a values diff that are included
+// in a change request notification
+const maxNotifyDiffLines = 20
+
+// isReleaseProtected returns true if upgrades and deletions of the release require
+// approval, either because the release or its namespace is protected
+func isReleaseProtected(repo repository.Repository, cluster *models.Cluster, name, namespace string) (bool, error) {
+	rel, err := repo.Release().ReadRelease(cluster.ID, name, namespace)
+
+	if err == nil && rel.Protected {
+		return true, nil
+	} else if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	return repo.ChangeRequest().IsNamespaceProtected(cluster.ID, namespace)
+}
+
+// createUpgradeChangeRequest stores an upgrade of a protected release as a change request.
+// Sensitive values are replaced with placeholders in the stored values and stored
+// encrypted instead, and are redacted from the diff that is shown to reviewers and
+// included in notifications. If the same upgrade is already pending, such as for
+// repeated GitOps reconciles, the pending change request is returned instead.
+func createUpgradeChangeRequest(
+	config *config.Config,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	request *types.UpgradeReleaseRequest,
+) (*models.ReleaseChangeRequest, apierrors.RequestError) {
+	values, err := chartutil.ReadValues([]byte(request.Values))
+
+	if err != nil {
+		return nil, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("could not parse values: %s", err.Error()),
+			http.StatusBadRequest,
+		)
+	}
+
+	sensitivePaths := append(
+		helm.GetSensitiveValuePaths(helmRelease.Chart),
+		helm.GetSensitiveKeyPaths(values)...,
+	)
+
+	sensitivePaths = append(sensitivePaths, helm.GetSensitiveKeyPaths(helmRelease.Config)...)
+
+	diff := helm.DiffValues(
+		helm.RedactSensitiveValues(helmRelease.Config, sensitivePaths),
+		helm.RedactSensitiveValues(values, sensitivePaths),
+	)
+
+	sensitiveValues, err := json.Marshal(helm.ExtractSensitiveValues(values, sensitivePaths, nil))
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	storedValues, err := json.Marshal(values)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	cr := &models.ReleaseChangeRequest{
+		ProjectID:       cluster.ProjectID,
+		ClusterID:       cluster.ID,
+		Namespace:       helmRelease.Namespace,
+		Name:            helmRelease.Name,
+		Operation:       types.ChangeRequestUpgrade,
+		Status:          types.ChangeRequestPending,
+		Values:          string(storedValues),
+		SensitiveValues: sensitiveValues,
+		ChartVersion:    request.ChartVersion,
+		ValuesDiff:      strings.Join(diff, "\n"),
+		BaseRevision:    helmRelease.Version,
+	}
+
+	if user != nil {
+		cr.RequestedByUserID = user.ID
+	}
+
+	if pending, err := findPendingChangeRequest(config, cr); err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	} else if pending != nil {
+		return pending, nil
+	}
+
+	cr, err = config.Repo.ChangeRequest().CreateChangeRequest(cr)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	if len(diff) > maxNotifyDiffLines {
+		diff = append(diff[:maxNotifyDiffLines], fmt.Sprintf("... and %d more", len(diff)-maxNotifyDiffLines))
+	}
+
+	info := fmt.Sprintf("Upgrade requested by %s", getRequester(r, user))
+
+	if request.ChartVersion != "" {
+		info += fmt.Sprintf(" to chart version %s", request.ChartVersion)
+	}
+
+	if len(diff) > 0 {
+		info += ":\n" + strings.Join(diff, "\n")
+	}
+
+	notifyChangeRequest(config, cluster, cr, slack.StatusChangeRequested, info)
+
+	return cr, nil
+}
+
+// createRollbackChangeRequest stores a rollback of a protected release as a change request
+func createRollbackChangeRequest(
+	config *config.Config,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	revision int,
+) (*models.ReleaseChangeRequest, error) {
+	cr := &models.ReleaseChangeRequest{
+		ProjectID:        cluster.ProjectID,
+		ClusterID:        cluster.ID,
+		Namespace:        helmRelease.Namespace,
+		Name:             helmRelease.Name,
+		Operation:        types.ChangeRequestRollback,
+		Status:           types.ChangeRequestPending,
+		BaseRevision:     helmRelease.Version,
+		RollbackRevision: revision,
+	}
+
+	if user != nil {
+		cr.RequestedByUserID = user.ID
+	}
+
+	if pending, err := findPendingChangeRequest(config, cr); err != nil || pending != nil {
+		return pending, err
+	}
+
+	cr, err := config.Repo.ChangeRequest().CreateChangeRequest(cr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	notifyChangeRequest(
+		config,
+		cluster,
+		cr,
+		slack.StatusChangeRequested,
+		fmt.Sprintf("Rollback to revision %d requested by %s", revision, getRequester(r, user)),
+	)
+
+	return cr, nil
+}
+
+// findPendingChangeRequest returns the pending change request of a release that
+// requests the same change as a new change request, or nil if there is none
+func findPendingChangeRequest(config *config.Config, cr *models.ReleaseChangeRequest) (*models.ReleaseChangeRequest, error) {
+	pending, err := config.Repo.ChangeRequest().ListChangeRequests(
+		cr.ClusterID,
+		cr.Namespace,
+		cr.Name,
+		types.ChangeRequestPending,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, existing := range pending {
+		if existing.Operation == cr.Operation &&
+			existing.RequestedByUserID == cr.RequestedByUserID &&
+			existing.BaseRevision == cr.BaseRevision &&
+			existing.RollbackRevision == cr.RollbackRevision &&
+			existing.ChartVersion == cr.ChartVersion &&
+			existing.Values == cr.Values &&
+			existing.KustomizePatches == cr.KustomizePatches &&
+			bytes.Equal(existing.SensitiveValues, cr.SensitiveValues) {
+			return existing, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getChangeRequestValues returns the values of an approved upgrade, with the placeholders
+// of its sensitive values replaced with the stored sensitive values
+func getChangeRequestValues(cr *models.ReleaseChangeRequest) (string, error) {
+	values, err := chartutil.ReadValues([]byte(cr.Values))
+
+	if err != nil {
+		return "", err
+	}
+
+	sensitiveValues, err := cr.GetSensitiveValues()
+
+	if err != nil {
+		return "", err
+	}
+
+	res, err := json.Marshal(helm.ResolveSensitiveValues(values, sensitiveValues))
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+// getRequester returns how the requester of a change is shown in notifications, which is
+// the source of the deploy for changes without a user, such as webhook deploys
+func getRequester(r *http.Request, user *models.User) string {
+	if user != nil {
+		return user.Email
+	}
+
+	if source := r.Header.Get(types.DeploySourceHeader); source != "" {
+		return source
+	}
+
+	return string(types.DeploySourceWebhook)
+}
+
+func createDeleteChangeRequest(
+	config *config.Config,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	request *types.DeleteReleaseRequest,
+) (*models.ReleaseChangeRequest, error) {
+	cr := &models.ReleaseChangeRequest{
+		ProjectID:     cluster.ProjectID,
+		ClusterID:     cluster.ID,
+		Namespace:     helmRelease.Namespace,
+		Name:          helmRelease.Name,
+		Operation:     types.ChangeRequestDelete,
+		Status:        types.ChangeRequestPending,
+		BaseRevision:  helmRelease.Version,
+		DeleteCascade: request.Cascade,
+		DeleteVolumes: request.Cascade && request.DeleteVolumes,
+	}
+
+	if user != nil {
+		cr.RequestedByUserID = user.ID
+	}
+
+	if pending, err := findPendingChangeRequest(config, cr); err != nil || pending != nil {
+		return pending, err
+	}
+
+	cr, err := config.Repo.ChangeRequest().CreateChangeRequest(cr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	notifyChangeRequest(
+		config,
+		cluster,
+		cr,
+		slack.StatusChangeRequested,
+		fmt.Sprintf("Deletion requested by %s", getRequester(r, user)),
+	)
+
+	return cr, nil
+}
+
+// createKustomizePatchesChangeRequest stores a change of the kustomize patches of a
+// protected release as a change request
+func createKustomizePatchesChangeRequest(
+	config *config.Config,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	patches string,
+) (*models.ReleaseChangeRequest, error) {
+	cr := &models.ReleaseChangeRequest{
+		ProjectID:        cluster.ProjectID,
+		ClusterID:        cluster.ID,
+		Namespace:        helmRelease.Namespace,
+		Name:             helmRelease.Name,
+		Operation:        types.ChangeRequestKustomizePatches,
+		Status:           types.ChangeRequestPending,
+		BaseRevision:     helmRelease.Version,
+		KustomizePatches: patches,
+	}
+
+	if user != nil {
+		cr.RequestedByUserID = user.ID
+	}
+
+	if pending, err := findPendingChangeRequest(config, cr); err != nil || pending != nil {
+		return pending, err
+	}
+
+	cr, err := config.Repo.ChangeRequest().CreateChangeRequest(cr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	notifyChangeRequest(
+		config,
+		cluster,
+		cr,
+		slack.StatusChangeRequested,
+		fmt.Sprintf("Kustomize patches change requested by %s", getRequester(r, user)),
+	)
+
+	return cr, nil
+}
+
+// notifyChangeRequest sends a change request notification to the Slack integrations of
+// the project, unless notifications are disabled for the cluster or release
+func notifyChangeRequest(
+	config *config.Config,
+	cluster *models.Cluster,
+	cr *models.ReleaseChangeRequest,
+	status slack.DeploymentStatus,
+	info string,
+) {
+	if cluster.NotificationsDisabled {
+		return
+	}
+
+	slackInts, _ := config.Repo.SlackIntegration().ListSlackIntegrationsByProjectID(cluster.ProjectID)
+
+	var notifConf *types.NotificationConfig
+
+	if rel, err := config.Repo.Release().ReadRelease(cluster.ID, cr.Name, cr.Namespace); err == nil && rel.NotificationConfig != 0 {
+		if conf, err := config.Repo.NotificationConfig().ReadNotificationConfig(rel.NotificationConfig); err == nil {
+			notifConf = conf.ToNotificationConfigType()
+		}
+	}
+
+	notifier := slack.NewSlackNotifier(notifConf, slackInts...)
+
+	notifier.Notify(&slack.NotifyOpts{
+		ProjectID:   cluster.ProjectID,
+		ClusterID:   cluster.ID,
+		ClusterName: cluster.Name,
+		Status:      status,
+		Info:        info,
+		Name:        cr.Name,
+		Namespace:   cr.Namespace,
+		URL: fmt.Sprintf(
+			"%s/applications/%s/%s/%s?project_id=%d",
+			config.ServerConf.ServerURL,
+			url.PathEscape(cluster.Name),
+			cr.Namespace,
+			cr.Name,
+			cluster.ProjectID,
+		),
+	})
+}
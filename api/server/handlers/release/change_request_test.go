@@ -0,0 +1,249 @@
+package release_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+)
+
+func TestUpgradeProtectedReleaseCreatesChangeRequest(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	cluster := &models.Cluster{ProjectID: 1, NotificationsDisabled: true}
+	cluster.ID = 1
+
+	_, err := config.Repo.Release().CreateRelease(&models.Release{
+		ClusterID: cluster.ID,
+		Name:      "web",
+		Namespace: "default",
+		Protected: true,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	helmRelease := getTestHelmRelease(1, map[string]interface{}{
+		"image": map[string]interface{}{"tag": "v1"},
+		"env":   map[string]interface{}{"DB_PASSWORD": "old-password"},
+	})
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/upgrade",
+		&types.UpgradeReleaseRequest{
+			Values: "image:\n  tag: v2\nenv:\n  DB_PASSWORD: new-password\n",
+		},
+	)
+
+	req = withReleaseScopes(t, req, user, cluster, helmRelease)
+
+	handler := release.NewUpgradeReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Result().StatusCode, "status code should be accepted")
+
+	cr, err := config.Repo.ChangeRequest().ReadChangeRequest(cluster.ID, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.ChangeRequestPending, cr.Status, "change request should be pending")
+	assert.Equal(t, 1, cr.BaseRevision, "change request should be based on the current revision")
+	assert.Contains(t, cr.ValuesDiff, "v2", "diff should include the new image tag")
+
+	// sensitive values are neither shown in the diff nor stored in plaintext values
+	for _, secret := range []string{"old-password", "new-password"} {
+		assert.NotContains(t, cr.ValuesDiff, secret, "diff should not include sensitive values")
+		assert.NotContains(t, cr.Values, secret, "values should not include sensitive values")
+	}
+
+	assert.Contains(t, string(cr.SensitiveValues), "new-password", "sensitive values should be stored separately")
+}
+
+func TestApproveChangeRequestStaleRevision(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	cluster := &models.Cluster{ProjectID: 1, NotificationsDisabled: true}
+	cluster.ID = 1
+
+	createTestChangeRequest(t, config, cluster, &models.ReleaseChangeRequest{
+		Operation:         types.ChangeRequestUpgrade,
+		Values:            "{}",
+		BaseRevision:      1,
+		RequestedByUserID: user.ID + 1,
+	})
+
+	// the release was upgraded to revision 2 after the change was requested
+	helmRelease := getTestHelmRelease(2, map[string]interface{}{})
+	helmAgent := helm.GetAgentTesting(&helm.Form{Namespace: "default"}, nil, config.Logger, nil)
+
+	if err := helmAgent.ActionConfig.Releases.Create(helmRelease); err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := getChangeRequestReviewRequest(t, "approve")
+	req = withReleaseScopes(t, req, user, cluster, helmRelease)
+	req = req.WithContext(context.WithValue(req.Context(), authz.HelmAgentCtxKey, helmAgent))
+
+	handler := release.NewApproveChangeRequestHandler(config, shared.NewDefaultResultWriter(config))
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusConflict, &types.ExternalError{
+		Error:     "release was upgraded to revision 2 since the change was requested for revision 1",
+		ErrorCode: types.ErrorCodeRevisionConflict,
+	})
+
+	cr, err := config.Repo.ChangeRequest().ReadChangeRequest(cluster.ID, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.ChangeRequestFailed, cr.Status, "stale change request should be failed")
+	assert.Equal(t, user.ID, cr.ReviewedByUserID, "change request should be reviewed by the user")
+}
+
+func TestRejectChangeRequest(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	cluster := &models.Cluster{ProjectID: 1, NotificationsDisabled: true}
+	cluster.ID = 1
+
+	createTestChangeRequest(t, config, cluster, &models.ReleaseChangeRequest{
+		Operation:         types.ChangeRequestDelete,
+		BaseRevision:      1,
+		RequestedByUserID: user.ID + 1,
+	})
+
+	req, rr := getChangeRequestReviewRequest(t, "reject")
+	req = withReleaseScopes(t, req, user, cluster, getTestHelmRelease(1, map[string]interface{}{}))
+
+	handler := release.NewRejectChangeRequestHandler(config, shared.NewDefaultResultWriter(config))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "status code should be ok")
+
+	cr, err := config.Repo.ChangeRequest().ReadChangeRequest(cluster.ID, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.ChangeRequestRejected, cr.Status, "change request should be rejected")
+	assert.Equal(t, user.ID, cr.ReviewedByUserID, "change request should be reviewed by the user")
+}
+
+func TestReviewOwnChangeRequestForbidden(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	cluster := &models.Cluster{ProjectID: 1, NotificationsDisabled: true}
+	cluster.ID = 1
+
+	createTestChangeRequest(t, config, cluster, &models.ReleaseChangeRequest{
+		Operation:         types.ChangeRequestDelete,
+		BaseRevision:      1,
+		RequestedByUserID: user.ID,
+	})
+
+	req, rr := getChangeRequestReviewRequest(t, "reject")
+	req = withReleaseScopes(t, req, user, cluster, getTestHelmRelease(1, map[string]interface{}{}))
+
+	handler := release.NewRejectChangeRequestHandler(config, shared.NewDefaultResultWriter(config))
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseForbidden(t, rr)
+
+	cr, err := config.Repo.ChangeRequest().ReadChangeRequest(cluster.ID, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.ChangeRequestPending, cr.Status, "change request should still be pending")
+}
+
+func getTestHelmRelease(version int, values map[string]interface{}) *helmrelease.Release {
+	return &helmrelease.Release{
+		Name:      "web",
+		Namespace: "default",
+		Version:   version,
+		Config:    values,
+		Info:      &helmrelease.Info{Status: helmrelease.StatusDeployed},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{
+				Name:    "web",
+				Version: "0.1.0",
+			},
+		},
+	}
+}
+
+func createTestChangeRequest(t *testing.T, config *config.Config, cluster *models.Cluster, cr *models.ReleaseChangeRequest) {
+	cr.ProjectID = cluster.ProjectID
+	cr.ClusterID = cluster.ID
+	cr.Namespace = "default"
+	cr.Name = "web"
+	cr.Status = types.ChangeRequestPending
+
+	if _, err := config.Repo.ChangeRequest().CreateChangeRequest(cr); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getChangeRequestReviewRequest(t *testing.T, action string) (*http.Request, *httptest.ResponseRecorder) {
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		fmt.Sprintf("/api/projects/1/clusters/1/namespaces/default/releases/web/change_requests/1/%s", action),
+		nil,
+	)
+
+	req = apitest.WithURLParams(t, req, map[string]string{
+		string(types.URLParamReleaseName):     "web",
+		string(types.URLParamChangeRequestID): "1",
+	})
+
+	return req, rr
+}
+
+func withReleaseScopes(
+	t *testing.T,
+	req *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *helmrelease.Release,
+) *http.Request {
+	req = apitest.WithAuthenticatedUser(t, req, user)
+
+	ctx := context.WithValue(req.Context(), types.UserScope, user)
+	ctx = context.WithValue(ctx, types.ClusterScope, cluster)
+	ctx = context.WithValue(ctx, types.NamespaceScope, helmRelease.Namespace)
+	ctx = context.WithValue(ctx, types.ReleaseScope, helmRelease)
+
+	return req.WithContext(ctx)
+}
@@ -0,0 +1,317 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/envgroup"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CloneNamespaceHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewCloneNamespaceHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CloneNamespaceHandler {
+	return &CloneNamespaceHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *CloneNamespaceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	request := &types.CloneNamespaceRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	targetCluster := cluster
+
+	if request.TargetClusterID != 0 && request.TargetClusterID != cluster.ID {
+		var err error
+
+		targetCluster, err = c.Repo().Cluster().ReadCluster(cluster.ProjectID, request.TargetClusterID)
+
+		if err == gorm.ErrRecordNotFound {
+			c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("target cluster not found"),
+				http.StatusNotFound,
+			), types.ErrorCodeClusterNotFound))
+
+			return
+		} else if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	if targetCluster.ID == cluster.ID && request.TargetNamespace == namespace {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("target namespace must be different from the source namespace"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	// the releases are installed in the target cluster, so its deploy freezes apply
+	if reqErr := CheckDeployFreeze(c.Config(), r, user, targetCluster); reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	srcAgent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	srcHelmAgent, err := c.GetHelmAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	dstAgent, err := c.GetAgent(r, targetCluster, request.TargetNamespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	dstHelmAgent, err := c.GetHelmAgent(r, targetCluster, request.TargetNamespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the namespace is only deleted if the clone fails when it did not exist before
+	_, err = dstAgent.Clientset.CoreV1().Namespaces().Get(r.Context(), request.TargetNamespace, metav1.GetOptions{})
+
+	if err != nil && !k8serrors.IsNotFound(err) {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	clone := &namespaceClone{
+		config:           c.Config(),
+		agent:            dstAgent,
+		helmAgent:        dstHelmAgent,
+		cluster:          targetCluster,
+		namespace:        request.TargetNamespace,
+		createdNamespace: k8serrors.IsNotFound(err),
+	}
+
+	if _, err := dstAgent.CreateNamespace(request.TargetNamespace); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if reqErr := clone.cloneNamespace(srcAgent, srcHelmAgent, cluster, namespace, request.ImageTags); reqErr != nil {
+		clone.rollback()
+
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	c.WriteResult(w, r, &types.CloneNamespaceResponse{
+		Releases:  clone.releases,
+		EnvGroups: clone.envGroups,
+	})
+}
+
+// namespaceClone clones the env groups and releases of a namespace into a target namespace,
+// and keeps track of what was cloned, so that a failed clone can be rolled back instead of
+// leaving a partial copy of the namespace
+type namespaceClone struct {
+	config    *config.Config
+	agent     *kubernetes.Agent
+	helmAgent *helm.Agent
+	cluster   *models.Cluster
+	namespace string
+
+	// createdNamespace is whether the target namespace was created by the clone
+	createdNamespace bool
+
+	releases  []string
+	envGroups []string
+}
+
+func (clone *namespaceClone) cloneNamespace(
+	srcAgent *kubernetes.Agent,
+	srcHelmAgent *helm.Agent,
+	cluster *models.Cluster,
+	namespace string,
+	imageTags map[string]string,
+) apierrors.RequestError {
+	clone.releases = make([]string, 0)
+	clone.envGroups = make([]string, 0)
+
+	// env groups are cloned first, so that they exist when the releases that sync them
+	// are installed
+	configMaps, err := srcAgent.ListAllVersionedConfigMaps(namespace)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	for _, cm := range configMaps {
+		name := cm.Labels["envgroup"]
+
+		if _, err := envgroup.CloneEnvGroup(srcAgent, clone.agent, name, namespace, clone.namespace); err != nil {
+			return apierrors.NewErrInternal(fmt.Errorf("error cloning env group %s: %w", name, err))
+		}
+
+		clone.envGroups = append(clone.envGroups, name)
+	}
+
+	helmReleases, err := srcHelmAgent.ListReleases(namespace, &types.ReleaseListFilter{
+		Namespace:    namespace,
+		StatusFilter: []string{"deployed"},
+	})
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	registries, err := clone.config.Repo.Registry().ListRegistriesByProjectID(cluster.ProjectID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	for _, helmRelease := range helmReleases {
+		// only releases managed by Porter are cloned, which excludes add-ons
+		_, err := clone.config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, namespace)
+
+		if err == gorm.ErrRecordNotFound {
+			continue
+		} else if err != nil {
+			return apierrors.NewErrInternal(err)
+		}
+
+		// the cloned release does not share the stored sensitive values of the release, so
+		// they are resolved and stored again for the cloned release
+		sensitiveValues, err := helm.GetStoredSensitiveValues(clone.config.Repo, cluster, namespace, helmRelease.Name)
+
+		if err != nil {
+			return apierrors.NewErrInternal(err)
+		}
+
+		values := getClonedValues(
+			helm.ResolveSensitiveValues(helmRelease.Config, sensitiveValues),
+			helm.GetImageValuesKey(helmRelease.Chart),
+			imageTags[helmRelease.Name],
+		)
+
+		clonedRelease, err := clone.helmAgent.InstallChart(&helm.InstallChartConfig{
+			Chart:      helmRelease.Chart,
+			Name:       helmRelease.Name,
+			Namespace:  clone.namespace,
+			Values:     values,
+			Cluster:    clone.cluster,
+			Repo:       clone.config.Repo,
+			Registries: registries,
+		}, clone.config.DOConf)
+
+		if err != nil {
+			return apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("error cloning release %s: %s", helmRelease.Name, err.Error()),
+				http.StatusBadRequest,
+			)
+		}
+
+		clone.releases = append(clone.releases, helmRelease.Name)
+
+		_, err = createReleaseFromHelmRelease(clone.config, clone.cluster.ProjectID, clone.cluster.ID, clonedRelease)
+
+		if err != nil {
+			return apierrors.NewErrInternal(err)
+		}
+	}
+
+	return nil
+}
+
+// rollback removes the releases and env groups that were cloned, and the target namespace
+// if it was created by the clone. Errors are logged, so that the rest of the clone is
+// still removed.
+func (clone *namespaceClone) rollback() {
+	logger := clone.config.Logger
+
+	for _, name := range clone.releases {
+		if _, err := clone.helmAgent.UninstallChart(name); err != nil {
+			logger.Error().Err(err).Msgf("could not uninstall cloned release %s", name)
+		}
+
+		if err := clone.config.Repo.SensitiveValues().DeleteSensitiveValues(clone.cluster.ID, clone.namespace, name); err != nil {
+			logger.Error().Err(err).Msgf("could not delete the sensitive values of cloned release %s", name)
+		}
+
+		rel, err := clone.config.Repo.Release().ReadRelease(clone.cluster.ID, name, clone.namespace)
+
+		if err == nil {
+			_, err = clone.config.Repo.Release().DeleteRelease(rel)
+		}
+
+		if err != nil && err != gorm.ErrRecordNotFound {
+			logger.Error().Err(err).Msgf("could not delete cloned release %s", name)
+		}
+	}
+
+	for _, name := range clone.envGroups {
+		if err := envgroup.DeleteEnvGroup(clone.agent, name, clone.namespace); err != nil {
+			logger.Error().Err(err).Msgf("could not delete cloned env group %s", name)
+		}
+	}
+
+	if clone.createdNamespace {
+		if err := clone.agent.DeleteNamespace(clone.namespace); err != nil {
+			logger.Error().Err(err).Msgf("could not delete namespace %s", clone.namespace)
+		}
+	}
+}
+
+// getClonedValues returns the values of a release with the image tag under the image values
+// key of its chart replaced, if a tag is given. The values are changed in place, since they
+// are already a copy of the values of the release.
+func getClonedValues(values map[string]interface{}, imageValuesKey, tag string) map[string]interface{} {
+	if tag == "" {
+		return values
+	}
+
+	var repository interface{}
+
+	if image := helm.GetImageValues(values, imageValuesKey); image != nil {
+		repository = image["repository"]
+	}
+
+	helm.SetImageValues(values, imageValuesKey, repository, tag)
+
+	return values
+}
@@ -15,12 +15,12 @@ import (
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/helm"
-	"github.com/porter-dev/porter/internal/helm/loader"
 	"github.com/porter-dev/porter/internal/integrations/ci/actions"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/registry"
 	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/templater/utils"
 	"gopkg.in/yaml.v2"
 	"helm.sh/helm/v3/pkg/release"
 )
@@ -68,14 +68,19 @@ func (c *CreateReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 
 	if request.RepoURL == "" {
-		request.RepoURL = c.Config().ServerConf.DefaultApplicationHelmRepoURL
+		request.RepoURL = c.Config().Settings.Get(types.ServerSettingDefaultApplicationHelmRepoURL)
 	}
 
 	if request.TemplateVersion == "latest" {
 		request.TemplateVersion = ""
 	}
 
-	chart, err := loader.LoadChartPublic(request.RepoURL, request.TemplateName, request.TemplateVersion)
+	if err := checkChartAllowed(c.Config(), cluster.ProjectID, request.RepoURL, request.TemplateName); err != nil {
+		c.HandleAPIError(w, r, err)
+		return
+	}
+
+	chart, err := loadChart(c.Config(), cluster, request.RepoURL, request.TemplateName, request.TemplateVersion)
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -89,11 +94,55 @@ func (c *CreateReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	defaultValues, err := getDefaultValues(c.Repo(), cluster)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	values := utils.MergeValues(defaultValues, request.Values)
+
+	if request.Exposure != nil {
+		if err := setServiceExposure(values, request.Exposure); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		// the proxy port is checked before the release is installed, so that releases
+		// are not installed with a port that the ingress controller cannot proxy
+		if request.Exposure.Mode == types.ExposureModeProxy {
+			if err := checkServiceProxyPort(helmAgent.K8sAgent, nil, namespace, request.Exposure); err != nil {
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+				return
+			}
+		}
+	}
+
+	values, reqErr := mirrorImages(c.Config(), chart.Values, values)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	if reqErr := checkImagesAllowed(c.Config(), cluster.ProjectID, chart.Values, values); reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	values, reqErr = checkImagesSigned(c.Config(), cluster.ProjectID, chart.Values, values)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
 	conf := &helm.InstallChartConfig{
 		Chart:      chart,
 		Name:       request.Name,
 		Namespace:  namespace,
-		Values:     request.Values,
+		Values:     values,
 		Cluster:    cluster,
 		Repo:       c.Repo(),
 		Registries: registries,
@@ -102,14 +151,31 @@ func (c *CreateReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	helmRelease, err := helmAgent.InstallChart(conf, c.Config().DOConf)
 
 	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
 			fmt.Errorf("error installing a new chart: %s", err.Error()),
 			http.StatusBadRequest,
+		), types.ErrorCodeHelmOperationFailed))
+
+		return
+	}
+
+	// releases that cannot be exposed through the proxy of the ingress controller are
+	// uninstalled, so that a failed create does not leave an unreachable release
+	if err := syncServiceProxy(helmAgent.K8sAgent, nil, helmRelease); err != nil {
+		if _, uninstallErr := helmAgent.UninstallChart(helmRelease.Name); uninstallErr != nil {
+			c.Config().Logger.Error().Err(uninstallErr).Msgf("could not uninstall release %s", helmRelease.Name)
+		}
+
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release could not be exposed: %s", err.Error()),
+			http.StatusBadRequest,
 		))
 
 		return
 	}
 
+	syncReleaseToGit(c.Config(), user, cluster, helmRelease, false)
+
 	release, err := createReleaseFromHelmRelease(c.Config(), cluster.ProjectID, cluster.ID, helmRelease)
 
 	if err != nil {
@@ -135,6 +201,12 @@ func (c *CreateReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	// apps that build from a git repo without a build config use the stored buildpack
+	// detection of the repo
+	if request.BuildConfig == nil && request.GithubActionConfig != nil {
+		request.BuildConfig = getBuildConfigFromDetection(c.Config(), cluster.ProjectID, request.GithubActionConfig)
+	}
+
 	if request.BuildConfig != nil {
 		_, err = createBuildConfig(c.Config(), release, request.BuildConfig)
 	}
@@ -171,10 +243,11 @@ func createReleaseFromHelmRelease(
 	}
 
 	// create release with webhook token in db
-	image, ok := helmRelease.Config["image"].(map[string]interface{})
+	imageValuesKey := helm.GetImageValuesKey(helmRelease.Chart)
+	image := helm.GetImageValues(helmRelease.Config, imageValuesKey)
 
-	if !ok {
-		return nil, fmt.Errorf("Could not find field image in config")
+	if image == nil {
+		return nil, fmt.Errorf("Could not find field %s in config", imageValuesKey)
 	}
 
 	repository := image["repository"]
@@ -245,6 +318,22 @@ func createGitAction(
 		return nil, nil, err
 	}
 
+	// apps without a Dockerfile whose runtime has no buildpack are built from the
+	// Dockerfile that was generated when the repo was detected
+	dockerfilePath, generatedDockerfile := getGeneratedDockerfile(
+		config,
+		projectID,
+		request.GitRepoID,
+		request.GitRepo,
+		request.GitBranch,
+		request.FolderPath,
+		request.DockerfilePath,
+	)
+
+	if dockerfilePath == "" {
+		dockerfilePath = request.DockerfilePath
+	}
+
 	// create the commit in the git repo
 	gaRunner := &actions.GithubActions{
 		InstanceName:           config.ServerConf.InstanceName,
@@ -252,6 +341,7 @@ func createGitAction(
 		GithubOAuthIntegration: nil,
 		GithubAppID:            config.GithubAppConf.AppID,
 		GithubAppSecretPath:    config.GithubAppConf.SecretPath,
+		GithubAppTokens:        config.GithubAppTokens,
 		GithubInstallationID:   request.GitRepoID,
 		GitRepoName:            repoSplit[1],
 		GitRepoOwner:           repoSplit[0],
@@ -261,7 +351,8 @@ func createGitAction(
 		ReleaseName:            name,
 		ReleaseNamespace:       namespace,
 		GitBranch:              request.GitBranch,
-		DockerFilePath:         request.DockerfilePath,
+		DockerFilePath:         dockerfilePath,
+		GeneratedDockerfile:    generatedDockerfile,
 		FolderPath:             request.FolderPath,
 		ImageRepoURL:           request.ImageRepoURI,
 		PorterToken:            encoded,
@@ -291,10 +382,11 @@ func createGitAction(
 		GitBranch:      request.GitBranch,
 		ImageRepoURI:   request.ImageRepoURI,
 		GitRepoID:      request.GitRepoID,
-		DockerfilePath: request.DockerfilePath,
+		DockerfilePath: dockerfilePath,
 		FolderPath:     request.FolderPath,
 		IsInstallation: true,
 		Version:        "v0.1.0",
+		PRComments:     request.PRComments,
 	})
 
 	if err != nil {
@@ -382,13 +474,28 @@ func getGARunner(
 		return nil, fmt.Errorf("invalid formatting of repo name")
 	}
 
+	_, detectedBuilder := getBuildpackDetection(
+		config, projectID, ga.GitRepoID, ga.GitRepo, ga.GitBranch, ga.FolderPath, ga.DockerfilePath,
+	)
+
+	// the generated Dockerfile is only written for configs that were created to build
+	// from it
+	generatedDockerfile := ""
+
+	if ga.DockerfilePath != "" {
+		_, generatedDockerfile = getGeneratedDockerfile(
+			config, projectID, ga.GitRepoID, ga.GitRepo, ga.GitBranch, ga.FolderPath, ga.DockerfilePath,
+		)
+	}
+
 	// create the commit in the git repo
 	return &actions.GithubActions{
 		ServerURL:              config.ServerConf.ServerURL,
 		GithubOAuthIntegration: nil,
-		BuildEnv:               cEnv.Container.Env.Normal,
+		BuildEnv:               addDetectedBuildEnv(cEnv.Container.Env.Normal, detectedBuilder),
 		GithubAppID:            config.GithubAppConf.AppID,
 		GithubAppSecretPath:    config.GithubAppConf.SecretPath,
+		GithubAppTokens:        config.GithubAppTokens,
 		GithubInstallationID:   ga.GitRepoID,
 		GitRepoName:            repoSplit[1],
 		GitRepoOwner:           repoSplit[0],
@@ -398,6 +505,7 @@ func getGARunner(
 		ReleaseName:            name,
 		GitBranch:              ga.GitBranch,
 		DockerFilePath:         ga.DockerfilePath,
+		GeneratedDockerfile:    generatedDockerfile,
 		FolderPath:             ga.FolderPath,
 		ImageRepoURL:           ga.ImageRepoURI,
 		Version:                "v0.1.0",
@@ -10,9 +10,10 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/addons"
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/helm"
-	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/helm/repo"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/oauth"
 )
@@ -63,7 +64,12 @@ func (c *CreateAddonHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		request.TemplateVersion = ""
 	}
 
-	chart, err := loader.LoadChartPublic(request.RepoURL, request.TemplateName, request.TemplateVersion)
+	if err := checkChartAllowed(c.Config(), cluster.ProjectID, request.RepoURL, request.TemplateName); err != nil {
+		c.HandleAPIError(w, r, err)
+		return
+	}
+
+	chart, err := repo.LoadChartForCluster(c.Repo(), cluster, request.RepoURL, request.TemplateName, request.TemplateVersion)
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -77,11 +83,33 @@ func (c *CreateAddonHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	values := request.Values
+
+	// the load balancer of the ingress controller is pinned to the address that is set
+	// for the cluster
+	if addons.IsIngressNginxChart(chart.Metadata.Name) {
+		svc, err := helmAgent.K8sAgent.GetIngressNginxService()
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		values = addons.GetIngressLoadBalancerValues(cluster, values, svc)
+	}
+
+	values, reqErr := mirrorImages(c.Config(), chart.Values, values)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
 	conf := &helm.InstallChartConfig{
 		Chart:      chart,
 		Name:       request.Name,
 		Namespace:  namespace,
-		Values:     request.Values,
+		Values:     values,
 		Cluster:    cluster,
 		Repo:       c.Repo(),
 		Registries: registries,
@@ -90,14 +118,20 @@ func (c *CreateAddonHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	helmRelease, err := helmAgent.InstallChart(conf, c.Config().DOConf)
 
 	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
 			fmt.Errorf("error installing a new chart: %s", err.Error()),
 			http.StatusBadRequest,
-		))
+		), types.ErrorCodeHelmOperationFailed))
 
 		return
 	}
 
+	// addons are tracked so that they can be checked for new versions of their chart
+	if _, err := addons.TrackAddon(c.Repo(), cluster, helmRelease, request.RepoURL); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
 	c.Config().AnalyticsClient.Track(analytics.ApplicationLaunchSuccessTrack(
 		&analytics.ApplicationLaunchSuccessTrackOpts{
 			ApplicationScopedTrackOpts: analytics.GetApplicationScopedTrackOpts(
@@ -0,0 +1,233 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/addons"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/repo"
+	"github.com/porter-dev/porter/internal/kubernetes/envgroup"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+type CreateCacheHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewCreateCacheHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateCacheHandler {
+	return &CreateCacheHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP provisions a cache addon in the namespace, creates an env group with the
+// connection details of the cache, and syncs the env group to the listed releases
+func (c *CreateCacheHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	request := &types.CreateCacheRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	template := addons.CacheTemplates[request.Engine]
+
+	if err := checkChartAllowed(c.Config(), cluster.ProjectID, template.RepoURL, template.ChartName); err != nil {
+		c.HandleAPIError(w, r, err)
+		return
+	}
+
+	// the cache is installed outside of the shared upgrade path, so deploy freezes are
+	// checked before it is installed
+	if reqErr := CheckDeployFreeze(c.Config(), r, user, cluster); reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// the releases are read before the cache is provisioned, so that the cache is not
+	// provisioned for releases that do not exist
+	linkedReleases := make([]*release.Release, 0)
+
+	for _, name := range request.ReleaseNames {
+		rel, err := helmAgent.GetRelease(name, 0, false)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("release %s not found in namespace %s", name, namespace),
+				http.StatusNotFound,
+			))
+
+			return
+		}
+
+		linkedReleases = append(linkedReleases, rel)
+	}
+
+	chart, err := repo.LoadChartForCluster(c.Repo(), cluster, template.RepoURL, template.ChartName, request.Version)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	password, err := repository.GenerateRandomBytes(16)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	registries, err := c.Repo().Registry().ListRegistriesByProjectID(cluster.ProjectID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	conf := &helm.InstallChartConfig{
+		Chart:      chart,
+		Name:       request.Name,
+		Namespace:  namespace,
+		Values:     addons.GetCacheValues(request.Engine, request.Name, password, request.StorageSize),
+		Cluster:    cluster,
+		Repo:       c.Repo(),
+		Registries: registries,
+	}
+
+	helmRelease, err := helmAgent.InstallChart(conf, c.Config().DOConf)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error installing %s: %s", request.Engine, err.Error()),
+			http.StatusBadRequest,
+		), types.ErrorCodeHelmOperationFailed))
+
+		return
+	}
+
+	if _, err := addons.TrackAddon(c.Repo(), cluster, helmRelease, template.RepoURL); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	envGroupName := request.EnvGroupName
+
+	if envGroupName == "" {
+		envGroupName = fmt.Sprintf("%s-connection", request.Name)
+	}
+
+	variables, secretVariables := addons.GetCacheConnectionVariables(request.Engine, request.Name, namespace, password)
+
+	cm, err := envgroup.CreateEnvGroup(helmAgent.K8sAgent, types.ConfigMapInput{
+		Name:            envGroupName,
+		Namespace:       namespace,
+		Variables:       variables,
+		SecretVariables: secretVariables,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	envGroup, err := envgroup.ToEnvGroup(cm)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.CreateCacheResponse{
+		Name:     request.Name,
+		Engine:   request.Engine,
+		Host:     addons.GetCacheHost(request.Engine, request.Name, namespace),
+		Port:     template.Port,
+		Releases: make([]string, 0),
+	}
+
+	for _, rel := range linkedReleases {
+		values := envgroup.AddSyncedEnvGroup(rel.Config, envGroup)
+
+		// job releases are paused so that syncing the env group does not run the job
+		if rel.Chart.Name() == "job" {
+			values["paused"] = true
+		}
+
+		valuesJSON, err := json.Marshal(values)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		cr, _, reqErr := upgradeRelease(c.Config(), c.KubernetesAgentGetter, r, &upgradeOpts{
+			user:        user,
+			cluster:     cluster,
+			helmRelease: rel,
+			request: &types.UpgradeReleaseRequest{
+				Values: string(valuesJSON),
+			},
+		})
+
+		if reqErr != nil {
+			c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("error syncing env group %s to release %s: %s", envGroupName, rel.Name, reqErr.Error()),
+				http.StatusBadRequest,
+			), types.ErrorCodeHelmOperationFailed))
+
+			return
+		}
+
+		// protected releases are only synced to the env group once their change request
+		// is approved
+		if cr != nil {
+			res.ChangeRequests = append(res.ChangeRequests, cr.ToReleaseChangeRequestType())
+			continue
+		}
+
+		cm, err = helmAgent.K8sAgent.AddApplicationToVersionedConfigMap(cm, rel.Name)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		res.Releases = append(res.Releases, rel.Name)
+	}
+
+	res.EnvGroup, err = envgroup.ToEnvGroup(cm)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, res)
+}
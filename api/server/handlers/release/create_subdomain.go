@@ -11,8 +11,10 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
 	"github.com/porter-dev/porter/internal/kubernetes/domain"
 	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
 )
 
 type CreateSubdomainHandler struct {
@@ -34,6 +36,8 @@ func (c *CreateSubdomainHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
 	agent, err := c.GetAgent(r, cluster, "")
 
 	if err != nil {
@@ -41,8 +45,41 @@ func (c *CreateSubdomainHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// releases of preview environments with path-based routing are exposed under a path
+	// prefix on the shared host of the environment, so no DNS record is created
+	env, depl, err := getPreviewEnvironment(c.Config(), cluster, namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if env != nil && env.RoutingMode == types.PreviewRoutingPath {
+		pathPrefix := kubernetes.GetPreviewPathPrefix(depl.PullRequestID, name)
+
+		if err := agent.ApplyPreviewIngress(namespace, name, env.PathRoutingHost, pathPrefix); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		c.WriteResult(w, r, &types.DNSRecord{
+			ExternalURL: env.PathRoutingHost + pathPrefix,
+			Hostname:    env.PathRoutingHost,
+			ClusterID:   cluster.ID,
+			PathPrefix:  pathPrefix,
+		})
+
+		return
+	}
+
 	endpoint, found, err := domain.GetNGINXIngressServiceIP(agent.Clientset)
 
+	// records point to the static IP of the ingress controller if it is pinned to one, so
+	// that the records stay valid if the load balancer is recreated
+	if lb := cluster.GetIngressLoadBalancer(); lb != nil && lb.StaticIP != "" {
+		endpoint, found = lb.StaticIP, true
+	}
+
 	if !found {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(
 			fmt.Errorf("target cluster does not have nginx ingress"),
@@ -51,8 +88,10 @@ func (c *CreateSubdomainHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	}
 
 	createDomain := domain.CreateDNSRecordConfig{
+		ClusterID:   cluster.ID,
+		Namespace:   namespace,
 		ReleaseName: name,
-		RootDomain:  c.Config().ServerConf.AppRootDomain,
+		RootDomain:  c.Config().Settings.Get(types.ServerSettingAppRootDomain),
 		Endpoint:    endpoint,
 	}
 
@@ -76,3 +115,27 @@ func (c *CreateSubdomainHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 
 	c.WriteResult(w, r, record.ToDNSRecordType())
 }
+
+// getPreviewEnvironment returns the preview environment and deployment of a namespace, or
+// nil if the namespace does not belong to a preview environment
+func getPreviewEnvironment(
+	config *config.Config,
+	cluster *models.Cluster,
+	namespace string,
+) (*models.Environment, *models.Deployment, error) {
+	depl, err := config.Repo.Environment().ReadDeploymentByCluster(cluster.ProjectID, cluster.ID, namespace)
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	env, err := config.Repo.Environment().ReadEnvironmentByID(cluster.ProjectID, cluster.ID, depl.EnvironmentID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return env, depl, nil
+}
@@ -0,0 +1,60 @@
+package release
+
+import (
+	"fmt"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/repo"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// loadChart loads a chart from a repo, or from the custom charts of the project if the
+// repo URL is the custom chart repo URL. An empty version loads the latest version.
+func loadChart(config *config.Config, cluster *models.Cluster, repoURL, chartName, chartVersion string) (*chart.Chart, error) {
+	if repoURL != types.CustomChartRepoURL {
+		return repo.LoadChartForCluster(config.Repo, cluster, repoURL, chartName, chartVersion)
+	}
+
+	customChart, err := readCustomChart(config, cluster.ProjectID, chartName, chartVersion)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return helm.LoadCustomChart(customChart.Archive, customChart.ImageValuesKey)
+}
+
+func readCustomChart(config *config.Config, projectID uint, chartName, chartVersion string) (*models.CustomChart, error) {
+	if chartVersion == "" {
+		latestVersion, err := getLatestCustomChartVersion(config, projectID, chartName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		chartVersion = latestVersion
+	}
+
+	return config.Repo.CustomChart().ReadCustomChartByVersion(projectID, chartName, chartVersion)
+}
+
+// getLatestCustomChartVersion returns the most recently added version of a custom chart
+func getLatestCustomChartVersion(config *config.Config, projectID uint, chartName string) (string, error) {
+	customCharts, err := config.Repo.CustomChart().ListCustomChartsByProjectID(projectID)
+
+	if err != nil {
+		return "", err
+	}
+
+	// the versions of each custom chart are listed from the most recently added version
+	for _, customChart := range customCharts {
+		if customChart.Name == chartName {
+			return customChart.Version, nil
+		}
+	}
+
+	return "", fmt.Errorf("custom chart %s not found", chartName)
+}
@@ -0,0 +1,39 @@
+package release
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/templater/utils"
+	"gorm.io/gorm"
+)
+
+// getDefaultValues returns the default values that are merged beneath the values of new
+// releases in a cluster. Cluster defaults take precedence over project defaults, and
+// null cluster defaults unset the project defaults and the values of the chart.
+func getDefaultValues(repo repository.Repository, cluster *models.Cluster) (map[string]interface{}, error) {
+	projectValues, err := readDefaultValues(repo, cluster.ProjectID, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	clusterValues, err := readDefaultValues(repo, cluster.ProjectID, cluster.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.MergeValues(projectValues, clusterValues), nil
+}
+
+func readDefaultValues(repo repository.Repository, projectID, clusterID uint) (map[string]interface{}, error) {
+	defaultValues, err := repo.DefaultValues().ReadDefaultValues(projectID, clusterID)
+
+	if err == gorm.ErrRecordNotFound {
+		return map[string]interface{}{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return defaultValues.GetValues()
+}
@@ -1,6 +1,8 @@
 package release
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/porter-dev/porter/api/server/authz"
@@ -9,8 +11,12 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/addons"
+	"github.com/porter-dev/porter/internal/kubernetes"
 	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
 	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
 )
 
 type DeleteReleaseHandler struct {
@@ -34,30 +40,198 @@ func (c *DeleteReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
 
-	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+	request := &types.DeleteReleaseRequest{}
 
-	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	res, cr, reqErr := deleteRelease(c.Config(), c.KubernetesAgentGetter, r, user, cluster, helmRelease, request)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
 		return
 	}
 
+	// deletions of protected releases are stored as change requests, and are applied once
+	// they are approved by another user
+	if cr != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+
+		return
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+// checkDeletionProtection returns an error if deletion protection is enabled for a release
+func checkDeletionProtection(
+	config *config.Config,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+) apierrors.RequestError {
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if rel.DeletionProtection {
+		return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s has deletion protection enabled", helmRelease.Name),
+			http.StatusConflict,
+		), types.ErrorCodeDeletionProtected)
+	}
+
+	return nil
+}
+
+// CheckNamespaceDeletionProtection returns an error if a release with deletion protection
+// is in one of the namespaces of a cluster, since deleting a namespace deletes its
+// releases. If no namespaces are given, every namespace of the cluster is checked.
+func CheckNamespaceDeletionProtection(
+	config *config.Config,
+	cluster *models.Cluster,
+	namespaces ...string,
+) apierrors.RequestError {
+	releases, err := config.Repo.Release().ListDeletionProtectedReleases(cluster.ID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	checked := make(map[string]bool)
+
+	for _, namespace := range namespaces {
+		checked[namespace] = true
+	}
+
+	for _, rel := range releases {
+		if len(namespaces) > 0 && !checked[rel.Namespace] {
+			continue
+		}
+
+		return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s in namespace %s has deletion protection enabled", rel.Name, rel.Namespace),
+			http.StatusConflict,
+		), types.ErrorCodeDeletionProtected)
+	}
+
+	return nil
+}
+
+// deleteRelease is the path that every deletion of a release goes through. Releases with
+// deletion protection are not deleted, and deletions of protected releases are stored as
+// change requests, which are returned. Other releases are deleted directly.
+func deleteRelease(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	request *types.DeleteReleaseRequest,
+) (*types.DeleteReleaseResponse, *models.ReleaseChangeRequest, apierrors.RequestError) {
+	if err := checkDeletionProtection(config, cluster, helmRelease); err != nil {
+		return nil, nil, err
+	}
+
+	protected, err := isReleaseProtected(config.Repo, cluster, helmRelease.Name, helmRelease.Namespace)
+
+	if err != nil {
+		return nil, nil, apierrors.NewErrInternal(err)
+	}
+
+	if protected {
+		cr, err := createDeleteChangeRequest(config, r, user, cluster, helmRelease, request)
+
+		if err != nil {
+			return nil, nil, apierrors.NewErrInternal(err)
+		}
+
+		return nil, cr, nil
+	}
+
+	res, reqErr := applyDelete(config, agentGetter, r, user, cluster, helmRelease, request)
+
+	return res, nil, reqErr
+}
+
+// applyDelete uninstalls a release and cleans up its GitHub Actions workflow. If the
+// request cascades, the resources that are associated with the release are removed as
+// well, and the removed resources are reported. It does not check whether the release
+// is protected.
+func applyDelete(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	request *types.DeleteReleaseRequest,
+) (*types.DeleteReleaseResponse, apierrors.RequestError) {
+	res := &types.DeleteReleaseResponse{
+		DNSRecords:             []string{},
+		PersistentVolumeClaims: []string{},
+		EnvGroups:              []string{},
+	}
+
+	helmAgent, err := agentGetter.GetHelmAgent(r, cluster, helmRelease.Namespace)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	var agent *kubernetes.Agent
+	var claims []v1.PersistentVolumeClaim
+
+	if request.Cascade {
+		agent, err = agentGetter.GetAgent(r, cluster, helmRelease.Namespace)
+
+		if err != nil {
+			return nil, apierrors.NewErrInternal(err)
+		}
+
+		// the claims are read before the release is uninstalled, since they are found
+		// through the manifest of the release
+		if request.DeleteVolumes {
+			claims, err = getReleaseVolumeClaims(agent, helmRelease)
+
+			if err != nil {
+				return nil, apierrors.NewErrInternal(err)
+			}
+		}
+	}
+
 	_, err = helmAgent.UninstallChart(helmRelease.Name)
 
 	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-		return
+		return nil, apierrors.NewErrInternal(err)
 	}
 
-	rel, releaseErr := c.Repo().Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+	if err := addons.UntrackAddon(config.Repo, cluster, helmRelease.Namespace, helmRelease.Name); err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	if err := config.Repo.SensitiveValues().DeleteSensitiveValues(cluster.ID, helmRelease.Namespace, helmRelease.Name); err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	syncReleaseToGit(config, user, cluster, helmRelease, true)
+
+	rel, releaseErr := config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
 
 	// update the github actions env if the release exists and is built from source
-	if cName := helmRelease.Chart.Metadata.Name; cName == "job" || cName == "web" || cName == "worker" {
-		if releaseErr == nil && rel != nil {
+	if cName := helmRelease.Chart.Metadata.Name; request.Cascade || cName == "job" || cName == "web" || cName == "worker" {
+		if releaseErr == nil && rel != nil && user != nil {
 			gitAction := rel.GitActionConfig
 
 			if gitAction != nil && gitAction.ID != 0 {
 				gaRunner, err := getGARunner(
-					c.Config(),
+					config,
 					user.ID,
 					cluster.ProjectID,
 					cluster.ID,
@@ -69,17 +243,27 @@ func (c *DeleteReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 				)
 
 				if err != nil {
-					c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-					return
+					return nil, apierrors.NewErrInternal(err)
 				}
 
 				err = gaRunner.Cleanup()
 
 				if err != nil {
-					c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-					return
+					return nil, apierrors.NewErrInternal(err)
 				}
+
+				res.GithubWorkflow = gitAction.GitRepo
 			}
 		}
 	}
+
+	if request.Cascade {
+		if releaseErr != nil {
+			rel = nil
+		}
+
+		cleanupRelease(config, agent, cluster, helmRelease, rel, claims, res)
+	}
+
+	return res, nil
 }
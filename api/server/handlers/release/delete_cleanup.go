@@ -0,0 +1,118 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/domain"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+)
+
+// cleanupRelease removes the resources that are associated with a deleted release: its
+// DNS records, the given volume claims, its links to env groups and its deploy webhook.
+// The release has already been uninstalled, so resources that cannot be removed are
+// reported as errors instead of failing the delete.
+func cleanupRelease(
+	config *config.Config,
+	agent *kubernetes.Agent,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	rel *models.Release,
+	claims []v1.PersistentVolumeClaim,
+	res *types.DeleteReleaseResponse,
+) {
+	addErr := func(resource string, err error) {
+		res.Errors = append(res.Errors, fmt.Sprintf("%s: %s", resource, err.Error()))
+	}
+
+	records, err := config.Repo.DNSRecord().ListDNSRecordsByRelease(cluster.ID, helmRelease.Namespace, helmRelease.Name)
+
+	if err != nil {
+		addErr("dns records", err)
+	}
+
+	for _, record := range records {
+		if config.PowerDNSClient != nil {
+			_record := domain.DNSRecord(*record)
+
+			if err := _record.DeleteDomain(config.PowerDNSClient); err != nil {
+				addErr(record.Hostname, err)
+				continue
+			}
+		}
+
+		if err := config.Repo.DNSRecord().DeleteDNSRecord(record); err != nil {
+			addErr(record.Hostname, err)
+			continue
+		}
+
+		res.DNSRecords = append(res.DNSRecords, record.Hostname)
+	}
+
+	for _, claim := range claims {
+		if err := agent.DeleteVolumeClaim(claim.Namespace, claim.Name); err != nil {
+			addErr(claim.Name, err)
+			continue
+		}
+
+		res.PersistentVolumeClaims = append(res.PersistentVolumeClaims, claim.Name)
+	}
+
+	configMaps, err := agent.ListAllVersionedConfigMaps(helmRelease.Namespace)
+
+	if err != nil {
+		addErr("env groups", err)
+	}
+
+	for _, cm := range configMaps {
+		cm := cm
+
+		if !isAppLinkedToConfigMap(&cm, helmRelease.Name) {
+			continue
+		}
+
+		if _, err := agent.RemoveApplicationFromVersionedConfigMap(&cm, helmRelease.Name); err != nil {
+			addErr(cm.Labels["envgroup"], err)
+			continue
+		}
+
+		res.EnvGroups = append(res.EnvGroups, cm.Labels["envgroup"])
+	}
+
+	// services that are exposed through the proxy of the ingress controller have entries
+	// in the configmaps of the ingress controller, which are not part of the release
+	if exposure, err := helm.GetServiceExposure(helmRelease.Config); err == nil && exposure != nil &&
+		exposure.Mode == types.ExposureModeProxy {
+		for _, svc := range getManifestServices(helmRelease) {
+			if err := agent.RemoveServiceProxies(helmRelease.Namespace, svc.name); err != nil {
+				addErr("service proxy", err)
+			}
+		}
+	}
+
+	// the deploy webhook is served from the token of the release, so it is removed along
+	// with the release
+	if rel != nil {
+		if _, err := config.Repo.Release().DeleteRelease(rel); err != nil {
+			addErr("deploy webhook", err)
+		} else {
+			res.DeployWebhook = rel.WebhookToken != ""
+		}
+	}
+}
+
+func isAppLinkedToConfigMap(cm *v1.ConfigMap, appName string) bool {
+	for _, app := range strings.Split(cm.Annotations[kubernetes.PorterAppAnnotationName], ",") {
+		if app == appName {
+			return true
+		}
+	}
+
+	return false
+}
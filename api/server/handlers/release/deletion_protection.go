@@ -0,0 +1,79 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// UpdateDeletionProtectionHandler toggles whether a release can be deleted. Deletes of a
+// release with deletion protection fail until it is disabled, which only project admins
+// can do.
+type UpdateDeletionProtectionHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateDeletionProtectionHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateDeletionProtectionHandler {
+	return &UpdateDeletionProtectionHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateDeletionProtectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateDeletionProtectionRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	rel, ok := readPorterRelease(c.PorterHandlerReadWriter, w, r, cluster, helmRelease)
+
+	if !ok {
+		return
+	}
+
+	if rel.DeletionProtection && !request.Enabled {
+		role, err := c.Repo().Project().ReadProjectRole(cluster.ProjectID, user.ID)
+
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		if err != nil || role.Kind != types.RoleAdmin {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("only project admins can disable deletion protection"),
+				http.StatusForbidden,
+			))
+			return
+		}
+	}
+
+	rel.DeletionProtection = request.Enabled
+
+	rel, err := c.Repo().Release().UpdateRelease(rel)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, rel.ToReleaseType())
+}
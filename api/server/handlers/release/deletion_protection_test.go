@@ -0,0 +1,174 @@
+package release_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisableDeletionProtectionRequiresAdmin(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	enableDeletionProtection(t, config, cluster)
+
+	disable := func() int {
+		req, rr := apitest.GetRequestAndRecorder(
+			t,
+			string(types.HTTPVerbPost),
+			"/api/projects/1/clusters/1/namespaces/default/releases/web/0/deletion_protection",
+			&types.UpdateDeletionProtectionRequest{Enabled: false},
+		)
+
+		req = withReleaseScopes(t, req, user, cluster, getPreDeployTestHelmRelease(1, "v1"))
+
+		handler := release.NewUpdateDeletionProtectionHandler(
+			config,
+			shared.NewDefaultRequestDecoderValidator(config),
+			shared.NewDefaultResultWriter(config),
+		)
+
+		handler.ServeHTTP(rr, req)
+
+		return rr.Result().StatusCode
+	}
+
+	assert.Equal(t, http.StatusForbidden, disable(), "users that are not admins should not disable deletion protection")
+
+	project, err := config.Repo.Project().ReadProject(1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = config.Repo.Project().CreateProjectRole(project, &models.Role{
+		Role: types.Role{
+			Kind:      types.RoleAdmin,
+			UserID:    user.ID,
+			ProjectID: project.ID,
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusOK, disable(), "admins should disable deletion protection")
+
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, rel.DeletionProtection, "deletion protection should be disabled")
+}
+
+func TestDeleteProtectedReleaseKeepsDeleteOptions(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rel.Protected = true
+
+	if _, err := config.Repo.Release().UpdateRelease(rel); err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbDelete),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0?cascade=true&delete_volumes=true",
+		nil,
+	)
+
+	req = withReleaseScopes(t, req, user, cluster, getPreDeployTestHelmRelease(1, "v1"))
+
+	handler := release.NewDeleteReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Result().StatusCode, "status code should be accepted")
+
+	cr, err := config.Repo.ChangeRequest().ReadChangeRequest(cluster.ID, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.ChangeRequestDelete, cr.Operation)
+	assert.True(t, cr.DeleteCascade, "change request should cascade")
+	assert.True(t, cr.DeleteVolumes, "change request should delete the volumes")
+}
+
+func TestCheckNamespaceDeletionProtection(t *testing.T) {
+	config, _, cluster := createPreDeployTestRelease(t)
+
+	assert.Nil(t, release.CheckNamespaceDeletionProtection(config, cluster, "default"))
+
+	enableDeletionProtection(t, config, cluster)
+
+	tests := []struct {
+		name       string
+		namespaces []string
+		protected  bool
+	}{
+		{
+			name:       "namespace of the release",
+			namespaces: []string{"other", "default"},
+			protected:  true,
+		},
+		{
+			name:       "other namespace",
+			namespaces: []string{"other"},
+		},
+		{
+			name:      "whole cluster",
+			protected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := release.CheckNamespaceDeletionProtection(config, cluster, test.namespaces...)
+
+			if !test.protected {
+				assert.Nil(t, err)
+				return
+			}
+
+			if assert.NotNil(t, err) {
+				assert.Equal(t, http.StatusConflict, err.GetStatusCode())
+				assert.Equal(t, "release web in namespace default has deletion protection enabled", err.ExternalError())
+			}
+		})
+	}
+}
+
+func enableDeletionProtection(t *testing.T, config *config.Config, cluster *models.Cluster) {
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rel.DeletionProtection = true
+
+	if _, err := config.Repo.Release().UpdateRelease(rel); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// UpdateReleaseDependenciesHandler sets the dependencies of a release that are declared
+// by the user, which are added to the dependency graph along with the dependencies found
+// in the values of the release
+type UpdateReleaseDependenciesHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateReleaseDependenciesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateReleaseDependenciesHandler {
+	return &UpdateReleaseDependenciesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateReleaseDependenciesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateReleaseDependenciesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	deps := make([]string, 0, len(request.Dependencies))
+	seen := make(map[string]bool)
+
+	for _, dep := range request.Dependencies {
+		if strings.Contains(dep, ",") || strings.Count(dep, "/") > 1 {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("invalid dependency %s: must be of the form name or namespace/name", dep),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		if dep == helmRelease.Name || dep == fmt.Sprintf("%s/%s", helmRelease.Namespace, helmRelease.Name) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("a release cannot depend on itself"),
+				http.StatusBadRequest,
+			))
+
+			return
+		}
+
+		if !seen[dep] {
+			seen[dep] = true
+			deps = append(deps, dep)
+		}
+	}
+
+	rel, ok := readPorterRelease(c.PorterHandlerReadWriter, w, r, cluster, helmRelease)
+
+	if !ok {
+		return
+	}
+
+	rel.Dependencies = strings.Join(deps, ",")
+
+	rel, err := c.Repo().Release().UpdateRelease(rel)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, rel.ToReleaseType())
+}
@@ -0,0 +1,190 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/porter-dev/porter/api/server/authn"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// deployEventIndex sorts deploy events after the build, push and upgrade steps
+const deployEventIndex = 400
+
+type GetReleaseEventsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewGetReleaseEventsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GetReleaseEventsHandler {
+	return &GetReleaseEventsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP returns the deploy events of a release, from most recent
+func (c *GetReleaseEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	rel, err := c.Repo().Release().ReadRelease(cluster.ID, name, namespace)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.GetReleaseEventsResponse, 0)
+
+	if rel.EventContainer != 0 {
+		subevents, err := c.Repo().BuildEvent().ReadEventsByContainerID(rel.EventContainer)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		for _, sub := range subevents {
+			if sub.EventID == types.DeployEventID {
+				res = append(res, sub.ToSubEventType())
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Time > res[j].Time
+	})
+
+	c.WriteResult(w, r, res)
+}
+
+// deployEvent describes a deploy of a release that is recorded in its events
+type deployEvent struct {
+	// user is the user who started the deploy, or nil for webhooks
+	user *models.User
+
+	helmRelease *release.Release
+	commitSHA   string
+	message     string
+	err         error
+}
+
+// recordDeployEvent appends a deploy event to the events of a release. Errors are only
+// logged, since a deploy should not fail because it could not be recorded.
+func recordDeployEvent(config *config.Config, r *http.Request, rel *models.Release, event *deployEvent) {
+	if rel == nil {
+		return
+	}
+
+	if err := appendDeployEvent(config, r, rel, event); err != nil {
+		config.Logger.Error().Msgf(
+			"could not record deploy event for release %s in namespace %s: %s",
+			rel.Name,
+			rel.Namespace,
+			err.Error(),
+		)
+	}
+}
+
+func appendDeployEvent(config *config.Config, r *http.Request, rel *models.Release, event *deployEvent) error {
+	if rel.EventContainer == 0 {
+		container, err := config.Repo.BuildEvent().CreateEventContainer(&models.EventContainer{ReleaseID: rel.ID})
+
+		if err != nil {
+			return err
+		}
+
+		rel.EventContainer = container.ID
+
+		if _, err := config.Repo.Release().UpdateRelease(rel); err != nil {
+			return err
+		}
+	}
+
+	container, err := config.Repo.BuildEvent().ReadEventContainer(rel.EventContainer)
+
+	if err != nil {
+		return err
+	}
+
+	subEvent := &models.SubEvent{
+		EventContainerID: container.ID,
+		EventID:          types.DeployEventID,
+		Name:             "Deploy",
+		Index:            deployEventIndex,
+		Status:           types.EventStatusSuccess,
+		CommitSHA:        event.commitSHA,
+		Message:          event.message,
+	}
+
+	if event.err != nil {
+		subEvent.Status = types.EventStatusFailed
+		subEvent.Info = event.err.Error()
+	}
+
+	if event.user == nil {
+		subEvent.InitiatorKind = types.DeployInitiatorWebhook
+		subEvent.Source = types.DeploySourceWebhook
+
+		// GitOps reconciles upgrade releases without a user as well
+		if source := getDeploySource(r); source == types.DeploySourceGitOps {
+			subEvent.Source = source
+		}
+	} else {
+		subEvent.Initiator = event.user.Email
+		subEvent.InitiatorKind = types.DeployInitiatorUser
+		subEvent.Source = getDeploySource(r)
+
+		if authn.GetTokenFromContext(r.Context()) != nil {
+			subEvent.InitiatorKind = types.DeployInitiatorToken
+		}
+	}
+
+	if helmRelease := event.helmRelease; helmRelease != nil {
+		subEvent.Revision = helmRelease.Version
+
+		if image := helm.GetImageValues(helmRelease.Config, helm.GetImageValuesKey(helmRelease.Chart)); image != nil {
+			if tag, ok := image["tag"]; ok && tag != nil {
+				subEvent.ImageTag = fmt.Sprintf("%v", tag)
+			}
+		}
+	}
+
+	return config.Repo.BuildEvent().AppendEvent(container, subEvent)
+}
+
+// getDeploySource returns the source of a deploy from the header that the CLI and the
+// background deploys of the server set. Other requests with a token come from API clients, and requests with a session come
+// from the dashboard.
+func getDeploySource(r *http.Request) types.DeploySource {
+	switch source := types.DeploySource(r.Header.Get(types.DeploySourceHeader)); source {
+	case types.DeploySourceCLI, types.DeploySourceGithubAction, types.DeploySourceScheduled,
+		types.DeploySourcePipeline, types.DeploySourceSlack, types.DeploySourceGitOps:
+		return source
+	}
+
+	if authn.GetTokenFromContext(r.Context()) != nil {
+		return types.DeploySourceAPI
+	}
+
+	return types.DeploySourceDashboard
+}
@@ -0,0 +1,56 @@
+package release_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/router/middleware"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDeployFreeze(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	cluster := &models.Cluster{ProjectID: 1}
+	cluster.ID = 2
+
+	start, end := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	_, err := config.Repo.DeployFreeze().CreateDeployFreeze(&models.DeployFreeze{
+		ProjectID: 1,
+		ClusterID: cluster.ID,
+		Name:      "release",
+		StartsAt:  &start,
+		EndsAt:    &end,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := apitest.GetRequestAndRecorder(t, string(types.HTTPVerbPost), "/", nil)
+
+	// requests that did not pass the deploy freeze middleware, such as Slack commands and
+	// GitOps reconciles, are checked
+	reqErr := release.CheckDeployFreeze(config, req, user, cluster)
+
+	if assert.NotNil(t, reqErr, "deploy should be frozen") {
+		assert.Equal(t, http.StatusForbidden, reqErr.GetStatusCode())
+	}
+
+	// requests that passed the middleware for another cluster, such as promotions to the
+	// cluster of the next stage, are checked as well
+	checkedReq := req.WithContext(context.WithValue(req.Context(), middleware.DeployFreezeCheckedCtxKey, uint(0)))
+
+	assert.NotNil(t, release.CheckDeployFreeze(config, checkedReq, user, cluster), "deploy to another cluster should be frozen")
+
+	checkedReq = req.WithContext(context.WithValue(req.Context(), middleware.DeployFreezeCheckedCtxKey, cluster.ID))
+
+	assert.Nil(t, release.CheckDeployFreeze(config, checkedReq, user, cluster), "deploy freeze should only be checked once")
+}
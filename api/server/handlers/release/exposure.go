@@ -0,0 +1,357 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// GetServiceExposureHandler returns the service exposure that is stored in the values of
+// a release, which is http for releases without an exposure
+type GetServiceExposureHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetServiceExposureHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetServiceExposureHandler {
+	return &GetServiceExposureHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetServiceExposureHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+
+	exposure, err := helm.GetServiceExposure(helmRelease.Config)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if exposure == nil {
+		exposure = &types.ServiceExposure{
+			Protocol: types.ServiceProtocolHTTP,
+		}
+	}
+
+	c.WriteResult(w, r, &types.GetServiceExposureResponse{
+		ServiceExposure: exposure,
+	})
+}
+
+// UpdateServiceExposureHandler sets the service exposure of a release and upgrades the
+// release, which renders the exposure into its ingress and services and updates the proxy
+// of the ingress controller
+type UpdateServiceExposureHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpdateServiceExposureHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateServiceExposureHandler {
+	return &UpdateServiceExposureHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdateServiceExposureHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+
+	request := &types.UpdateServiceExposureRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	exposure := &request.ServiceExposure
+
+	prevExposure, err := helm.GetServiceExposure(helmRelease.Config)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	values, err := copyValues(helmRelease.Config)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := setServiceExposure(values, exposure); err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(
+			apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest),
+			types.ErrorCodeValidationFailed,
+		))
+
+		return
+	}
+
+	// releases whose ingress was disabled by their previous exposure have it enabled
+	// again once they are exposed through the ingress
+	if prevExposure != nil && !prevExposure.UsesIngress() && exposure.UsesIngress() {
+		if ingress, ok := values["ingress"].(map[string]interface{}); ok {
+			ingress["enabled"] = true
+		}
+	}
+
+	if exposure.Mode == types.ExposureModeProxy {
+		k8sAgent, err := c.GetAgent(r, cluster, "")
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		if err := checkServiceProxyPort(k8sAgent, helmRelease, helmRelease.Namespace, exposure); err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	valuesJSON, err := json.Marshal(values)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	cr, _, reqErr := upgradeRelease(c.Config(), c.KubernetesAgentGetter, r, &upgradeOpts{
+		user:        user,
+		cluster:     cluster,
+		helmRelease: helmRelease,
+		request: &types.UpgradeReleaseRequest{
+			Values: string(valuesJSON),
+		},
+	})
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	// exposures of protected releases are changed through change requests, like other
+	// upgrades of their values
+	if cr != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+
+		return
+	}
+
+	c.WriteResult(w, r, &types.GetServiceExposureResponse{
+		ServiceExposure: exposure,
+	})
+}
+
+// setServiceExposure stores the exposure of a release in its values. The ingress of
+// releases that are not exposed over http or grpc, or that are internal, is disabled so
+// that no subdomain is created for them.
+func setServiceExposure(values map[string]interface{}, exposure *types.ServiceExposure) error {
+	switch {
+	case exposure.IsInternal():
+		exposure.ProxyPort = 0
+	case exposure.Protocol == types.ServiceProtocolTCP || exposure.Protocol == types.ServiceProtocolUDP:
+		if exposure.Mode == "" {
+			exposure.Mode = types.ExposureModeLoadBalancer
+		}
+
+		if exposure.Mode == types.ExposureModeProxy && exposure.ProxyPort == 0 {
+			return fmt.Errorf("proxy_port is required if the expose mode is proxy")
+		} else if exposure.Mode != types.ExposureModeProxy {
+			exposure.ProxyPort = 0
+		}
+	default:
+		exposure.Mode = ""
+		exposure.ProxyPort = 0
+	}
+
+	values[types.ExposureValuesKey] = exposure
+
+	if exposure.UsesIngress() {
+		return nil
+	}
+
+	ingress, ok := values["ingress"].(map[string]interface{})
+
+	if !ok {
+		ingress = make(map[string]interface{})
+		values["ingress"] = ingress
+	}
+
+	ingress["enabled"] = false
+
+	return nil
+}
+
+// manifestService is a service in the manifest of a release, with the first of its ports
+type manifestService struct {
+	name string
+	port int
+}
+
+func getManifestServices(helmRelease *release.Release) []manifestService {
+	res := make([]manifestService, 0)
+
+	for _, obj := range grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest)) {
+		if kind, _ := obj["kind"].(string); kind != "Service" {
+			continue
+		}
+
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		spec, _ := obj["spec"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+
+		if name == "" {
+			continue
+		}
+
+		svc := manifestService{name: name}
+
+		if ports, _ := spec["ports"].([]interface{}); len(ports) > 0 {
+			port, _ := ports[0].(map[string]interface{})
+			svc.port, _ = port["port"].(int)
+		}
+
+		res = append(res, svc)
+	}
+
+	return res
+}
+
+// getInternalEndpoints returns the cluster DNS names of the services of a release, with
+// their ports
+func getInternalEndpoints(helmRelease *release.Release) []string {
+	res := make([]string, 0)
+
+	for _, svc := range getManifestServices(helmRelease) {
+		endpoint := fmt.Sprintf("%s.%s.svc.cluster.local", svc.name, helmRelease.Namespace)
+
+		if svc.port != 0 {
+			endpoint = fmt.Sprintf("%s:%d", endpoint, svc.port)
+		}
+
+		res = append(res, endpoint)
+	}
+
+	return res
+}
+
+// getProxiedService returns the service of a release that the proxy of the ingress
+// controller proxies to, which is the first service with a port
+func getProxiedService(helmRelease *release.Release) *manifestService {
+	for _, svc := range getManifestServices(helmRelease) {
+		if svc.port != 0 {
+			return &svc
+		}
+	}
+
+	return nil
+}
+
+// checkServiceProxyPort returns an error if the proxy port of an exposure cannot be
+// used, because the ingress controller already uses it or proxies it to another service.
+// The services of the current revision of a release may keep their ports.
+func checkServiceProxyPort(
+	agent *kubernetes.Agent,
+	helmRelease *release.Release,
+	namespace string,
+	exposure *types.ServiceExposure,
+) error {
+	if exposure == nil || exposure.Mode != types.ExposureModeProxy {
+		return nil
+	}
+
+	services := make([]string, 0)
+
+	if helmRelease != nil {
+		for _, svc := range getManifestServices(helmRelease) {
+			services = append(services, svc.name)
+		}
+	}
+
+	return agent.CheckServiceProxyPort(string(exposure.Protocol), exposure.ProxyPort, namespace, services)
+}
+
+// syncServiceProxy updates the proxy of the ingress controller to the exposure of a
+// release after it was installed, upgraded or rolled back. The proxies of the previous
+// revision of the release are removed if the release is no longer exposed through the
+// proxy, or if its proxied service changed.
+func syncServiceProxy(agent *kubernetes.Agent, prevRelease, helmRelease *release.Release) error {
+	exposure, err := helm.GetServiceExposure(helmRelease.Config)
+
+	if err != nil {
+		return err
+	}
+
+	var prevExposure *types.ServiceExposure
+
+	if prevRelease != nil {
+		if prevExposure, err = helm.GetServiceExposure(prevRelease.Config); err != nil {
+			return err
+		}
+	}
+
+	isProxied := exposure != nil && exposure.Mode == types.ExposureModeProxy
+	wasProxied := prevExposure != nil && prevExposure.Mode == types.ExposureModeProxy
+
+	if !isProxied && !wasProxied {
+		return nil
+	}
+
+	var proxied *manifestService
+
+	if isProxied {
+		if proxied = getProxiedService(helmRelease); proxied == nil {
+			return fmt.Errorf("release %s has no service to proxy to", helmRelease.Name)
+		}
+
+		err := agent.SetServiceProxy(
+			string(exposure.Protocol),
+			exposure.ProxyPort,
+			helmRelease.Namespace,
+			proxied.name,
+			proxied.port,
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if wasProxied {
+		for _, svc := range getManifestServices(prevRelease) {
+			if proxied != nil && svc.name == proxied.name {
+				continue
+			}
+
+			if err := agent.RemoveServiceProxies(prevRelease.Namespace, svc.name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
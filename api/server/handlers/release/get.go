@@ -10,6 +10,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/helm/loader"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/templater/parser"
@@ -39,8 +40,25 @@ func (c *ReleaseGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Release: helmRelease,
 	}
 
-	// look up the release in the database; if not found, do not populate Porter fields
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	// redact the sensitive values of the release from its rendered manifest
+	sensitiveValues, err := helm.GetStoredSensitiveValues(c.Repo(), cluster, helmRelease.Namespace, helmRelease.Name)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if len(sensitiveValues) > 0 {
+		redactedRelease := *helmRelease
+		redactedRelease.Manifest = helm.RedactManifest(helmRelease.Manifest, sensitiveValues)
+		res.Release = &redactedRelease
+	}
+
+	res.InternalEndpoints = getInternalEndpoints(helmRelease)
+
+	// look up the release in the database; if not found, do not populate Porter fields
 	release, err := c.Repo().Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
 
 	if err == nil {
@@ -72,14 +90,7 @@ func (c *ReleaseGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// detect if Porter application chart and attempt to get the latest version
 	// from chart repo
-	cache := c.Config().URLCache
-	chartRepoURL, foundFirst := cache.GetURL(helmRelease.Chart.Metadata.Name)
-
-	if !foundFirst {
-		cache.Update()
-
-		chartRepoURL, _ = cache.GetURL(helmRelease.Chart.Metadata.Name)
-	}
+	chartRepoURL, _ := getChartRepoURL(c.Config(), cluster.ProjectID, helmRelease.Chart.Metadata.Name)
 
 	if chartRepoURL != "" {
 		repoIndex, err := loader.LoadRepoIndexPublic(chartRepoURL)
@@ -98,6 +109,12 @@ func (c *ReleaseGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if helm.IsCustomChart(helmRelease.Chart) {
+		if latestVersion, err := getLatestCustomChartVersion(c.Config(), cluster.ProjectID, helmRelease.Chart.Metadata.Name); err == nil {
+			res.LatestVersion = latestVersion
+		}
+	}
+
 	// look for the form using the dynamic client
 	dynClient, err := c.GetDynamicClient(r, cluster)
 
@@ -184,3 +201,26 @@ tabs:
           message: [.status.conditions[].message] | unique | join(","),
           data: {}
         }`
+
+// getChartRepoURL finds the repo of a chart in the default repos and the template repos
+// of the project. If the chart is not found, the template repos of the project are read
+// again, since they may have been registered through another server replica.
+func getChartRepoURL(config *config.Config, projectID uint, chartName string) (string, bool) {
+	cache := config.URLCache
+
+	if chartRepoURL, found := cache.GetProjectURL(projectID, chartName); found {
+		return chartRepoURL, true
+	}
+
+	if templateRepos, err := config.Repo.TemplateRepo().ListTemplateReposByProjectID(projectID); err == nil {
+		urls := make([]string, 0)
+
+		for _, templateRepo := range templateRepos {
+			urls = append(urls, templateRepo.RepoURL)
+		}
+
+		cache.SetProjectURLs(projectID, urls...)
+	}
+
+	return cache.GetProjectURL(projectID, chartName)
+}
@@ -39,7 +39,7 @@ func (c *GetControllersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 
-	agent, err := c.GetAgent(r, cluster, "")
+	statusReader, err := c.GetStatusReader(r, cluster)
 
 	if err != nil {
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
@@ -53,7 +53,7 @@ func (c *GetControllersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	// get current status of each controller
 	for _, controller := range controllers {
 		controller.Namespace = helmRelease.Namespace
-		rc, _, err := getController(controller, agent)
+		rc, _, err := getController(controller, statusReader)
 
 		if targetErr := kubernetes.IsNotFoundError; errors.Is(err, targetErr) {
 			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
@@ -73,10 +73,10 @@ func (c *GetControllersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	c.WriteResult(w, r, retrievedControllers)
 }
 
-func getController(controller grapher.Object, agent *kubernetes.Agent) (rc interface{}, selector *metav1.LabelSelector, err error) {
+func getController(controller grapher.Object, statusReader kubernetes.StatusReader) (rc interface{}, selector *metav1.LabelSelector, err error) {
 	switch strings.ToLower(controller.Kind) {
 	case "deployment":
-		obj, err := agent.GetDeployment(controller)
+		obj, err := statusReader.GetDeployment(controller)
 
 		if err != nil {
 			return nil, nil, err
@@ -84,7 +84,7 @@ func getController(controller grapher.Object, agent *kubernetes.Agent) (rc inter
 
 		return obj, obj.Spec.Selector, nil
 	case "statefulset":
-		obj, err := agent.GetStatefulSet(controller)
+		obj, err := statusReader.GetStatefulSet(controller)
 
 		if err != nil {
 			return nil, nil, err
@@ -92,7 +92,7 @@ func getController(controller grapher.Object, agent *kubernetes.Agent) (rc inter
 
 		return obj, obj.Spec.Selector, nil
 	case "daemonset":
-		obj, err := agent.GetDaemonSet(controller)
+		obj, err := statusReader.GetDaemonSet(controller)
 
 		if err != nil {
 			return nil, nil, err
@@ -100,7 +100,7 @@ func getController(controller grapher.Object, agent *kubernetes.Agent) (rc inter
 
 		return obj, obj.Spec.Selector, nil
 	case "replicaset":
-		obj, err := agent.GetReplicaSet(controller)
+		obj, err := statusReader.GetReplicaSet(controller)
 
 		if err != nil {
 			return nil, nil, err
@@ -108,7 +108,7 @@ func getController(controller grapher.Object, agent *kubernetes.Agent) (rc inter
 
 		return obj, obj.Spec.Selector, nil
 	case "cronjob":
-		obj, err := agent.GetCronJob(controller)
+		obj, err := statusReader.GetCronJob(controller)
 
 		if err != nil {
 			return nil, nil, err
@@ -124,7 +124,7 @@ func getController(controller grapher.Object, agent *kubernetes.Agent) (rc inter
 
 		return obj, res, nil
 	case "job":
-		obj, err := agent.GetJob(controller)
+		obj, err := statusReader.GetJob(controller)
 
 		if err != nil {
 			return nil, nil, err
@@ -10,6 +10,7 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/requestutils"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/models"
 )
 
@@ -48,5 +49,18 @@ func (c *GetReleaseHistoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if len(history) > 0 {
+		sensitiveValues, err := helm.GetStoredSensitiveValues(c.Repo(), cluster, history[0].Namespace, name)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		for _, rel := range history {
+			rel.Manifest = helm.RedactManifest(rel.Manifest, sensitiveValues)
+		}
+	}
+
 	c.WriteResult(w, r, history)
 }
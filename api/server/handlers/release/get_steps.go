@@ -58,6 +58,11 @@ func (c *GetReleaseStepsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		}
 
 		for _, sub := range subevents {
+			// deploy events are returned by the events endpoint
+			if sub.EventID == types.DeployEventID {
+				continue
+			}
+
 			res = append(res, sub.ToSubEventType())
 		}
 	}
@@ -0,0 +1,188 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	ghinstallation "github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v41/github"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// githubDeployStatus is the commit status and pull request comment that are posted on
+// GitHub when a release that is built from a repository is deployed
+type githubDeployStatus struct {
+	cluster   *models.Cluster
+	release   *models.Release
+	namespace string
+	commitSHA string
+	message   string
+	url       string
+	err       error
+}
+
+// postGithubDeployStatus posts the status of a deploy to the commit that was deployed, and
+// comments on its pull requests if enabled in the git action config. This is done in the
+// background using the credentials of the Github app installation, and errors are only
+// logged, since the deploy itself has already completed.
+func postGithubDeployStatus(config *config.Config, status *githubDeployStatus) {
+	if config.GithubAppConf == nil || status.release == nil || status.commitSHA == "" {
+		return
+	}
+
+	gitAction := status.release.GitActionConfig
+
+	if gitAction == nil || gitAction.ID == 0 || gitAction.GitRepoID == 0 {
+		return
+	}
+
+	go func() {
+		if err := sendGithubDeployStatus(config, gitAction, status); err != nil {
+			config.Logger.Error().Msgf(
+				"could not post github deploy status for release %s in namespace %s: %s",
+				status.release.Name,
+				status.namespace,
+				err.Error(),
+			)
+		}
+	}()
+}
+
+func sendGithubDeployStatus(config *config.Config, gitAction *models.GitActionConfig, status *githubDeployStatus) error {
+	owner, repo, client, err := getGithubRepoClient(config, gitAction)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// image tags are usually short commit SHAs, while statuses need the full SHA
+	sha, _, err := client.Repositories.GetCommitSHA1(ctx, owner, repo, status.commitSHA, "")
+
+	if err != nil {
+		return err
+	}
+
+	state := "success"
+	description := fmt.Sprintf("Deployed to %s/%s", status.cluster.Name, status.namespace)
+
+	if status.err != nil {
+		state = "failure"
+		description = fmt.Sprintf("Deploy to %s/%s failed", status.cluster.Name, status.namespace)
+	}
+
+	_, _, err = client.Repositories.CreateStatus(ctx, owner, repo, sha, &github.RepoStatus{
+		State:       github.String(state),
+		TargetURL:   github.String(status.url),
+		Description: github.String(description),
+		Context:     github.String(fmt.Sprintf("porter/deploy/%s", status.release.Name)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if !gitAction.PRComments {
+		return nil
+	}
+
+	prs, _, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repo, sha, nil)
+
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Deployed %s version %s to %s — %s",
+		status.release.Name, status.commitSHA, status.namespace, status.url,
+	)
+
+	// pull requests may be public, while deploy errors can include values of the release
+	// and internal hostnames, so the error is only shown in the deploy events of the
+	// release on the dashboard
+	if status.err != nil {
+		body = fmt.Sprintf(
+			"Failed to deploy %s version %s to %s. See the deploy events of the release on the Porter dashboard for details — %s",
+			status.release.Name, status.commitSHA, status.namespace, status.url,
+		)
+	}
+
+	if status.message != "" {
+		body = fmt.Sprintf("%s\n\n> %s", body, status.message)
+	}
+
+	for _, pr := range prs {
+		if pr.GetState() != "open" {
+			continue
+		}
+
+		_, _, err := client.Issues.CreateComment(ctx, owner, repo, pr.GetNumber(), &github.IssueComment{
+			Body: github.String(body),
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getCommitMessage returns the subject line of the message of a commit in the repository
+// of a git action config, or an empty string if the message cannot be read, since the
+// message is only informational
+func getCommitMessage(config *config.Config, gitAction *models.GitActionConfig, commitSHA string) string {
+	if config.GithubAppConf == nil || commitSHA == "" || gitAction == nil || gitAction.ID == 0 || gitAction.GitRepoID == 0 {
+		return ""
+	}
+
+	owner, repo, client, err := getGithubRepoClient(config, gitAction)
+
+	if err != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	commit, _, err := client.Repositories.GetCommit(ctx, owner, repo, commitSHA, nil)
+
+	if err != nil || commit.GetCommit() == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.SplitN(commit.GetCommit().GetMessage(), "\n", 2)[0])
+}
+
+// getGithubRepoClient returns the owner and name of the repository of a git action config,
+// and a client that uses the credentials of the Github app installation of the repository
+func getGithubRepoClient(config *config.Config, gitAction *models.GitActionConfig) (string, string, *github.Client, error) {
+	repoSplit := strings.Split(gitAction.GitRepo, "/")
+
+	if len(repoSplit) != 2 {
+		return "", "", nil, fmt.Errorf("invalid formatting of repo name")
+	}
+
+	if config.GithubAppTokens != nil {
+		return repoSplit[0], repoSplit[1], config.GithubAppTokens.Client(int64(gitAction.GitRepoID)), nil
+	}
+
+	itr, err := ghinstallation.NewKeyFromFile(
+		http.DefaultTransport,
+		config.GithubAppConf.AppID,
+		int64(gitAction.GitRepoID),
+		config.GithubAppConf.SecretPath,
+	)
+
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return repoSplit[0], repoSplit[1], github.NewClient(&http.Client{Transport: itr}), nil
+}
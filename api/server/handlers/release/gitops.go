@@ -0,0 +1,51 @@
+package release
+
+import (
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/gitops"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// syncReleaseToGit mirrors a release to the gitops repository of its project in the
+// background, if the project has one. Errors are logged, since a failed export does not
+// fail the change to the release.
+func syncReleaseToGit(
+	config *config.Config,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	deleted bool,
+) {
+	if helmRelease == nil || config.GithubAppConf == nil {
+		return
+	}
+
+	conf, err := config.Repo.GitOpsConfig().ReadGitOpsConfig(cluster.ProjectID)
+
+	if err != nil {
+		return
+	}
+
+	syncer := gitops.NewSyncer(config.Repo, config.GithubAppConf, config.DOConf, config.Logger)
+	syncer.GithubAppTokens = config.GithubAppTokens
+
+	go func() {
+		var err error
+
+		if deleted {
+			err = syncer.DeleteRelease(conf, cluster, helmRelease.Namespace, helmRelease.Name, user)
+		} else {
+			repoURL, _ := getChartRepoURL(config, cluster.ProjectID, helmRelease.Chart.Metadata.Name)
+
+			err = syncer.ExportRelease(conf, cluster, helmRelease, repoURL, user)
+		}
+
+		if err != nil {
+			config.Logger.Error().Err(err).
+				Uint("project_id", cluster.ProjectID).
+				Str("release", helmRelease.Name).
+				Msg("error syncing release to gitops repository")
+		}
+	}()
+}
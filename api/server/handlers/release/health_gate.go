@@ -0,0 +1,62 @@
+package release
+
+import (
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/internal/healthgate"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/integrations/slack"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// startHealthGate verifies the newly deployed version of a release in the background, if
+// a health gate is enabled for the release. If verification fails, the release is rolled
+// back and the notifier is called.
+func startHealthGate(
+	config *config.Config,
+	cluster *models.Cluster,
+	k8sAgent *kubernetes.Agent,
+	helmAgent *helm.Agent,
+	rel *models.Release,
+	helmRelease *release.Release,
+	notifier slack.Notifier,
+	notifyOpts *slack.NotifyOpts,
+) error {
+	if rel == nil || rel.HealthGateConfig == 0 {
+		return nil
+	}
+
+	gateConfig, err := config.Repo.HealthGateConfig().ReadHealthGateConfig(rel.HealthGateConfig)
+
+	if err != nil {
+		return err
+	}
+
+	if !gateConfig.Enabled {
+		return nil
+	}
+
+	verifier := &healthgate.Verifier{
+		HelmAgent:  helmAgent,
+		K8sAgent:   k8sAgent,
+		Config:     gateConfig,
+		Repo:       config.Repo,
+		Cluster:    cluster,
+		Notifier:   notifier,
+		NotifyOpts: notifyOpts,
+	}
+
+	go func() {
+		if err := verifier.Verify(helmRelease); err != nil {
+			config.Logger.Info().Msgf(
+				"health gate failed for release %s in namespace %s: %s",
+				helmRelease.Name,
+				helmRelease.Namespace,
+				err.Error(),
+			)
+		}
+	}()
+
+	return nil
+}
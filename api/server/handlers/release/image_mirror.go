@@ -0,0 +1,51 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/templater/utils"
+)
+
+// mirrorImages replaces the default images that a release references, in its values or
+// in the default values of its chart, with the images of the image mirror of the server.
+// If the mirror is strict, an error is returned if the release references images outside
+// of the mirror. Servers without an image mirror return the values as they are.
+func mirrorImages(
+	config *config.Config,
+	chartValues, values map[string]interface{},
+) (map[string]interface{}, apierrors.RequestError) {
+	imageMirror := config.ImageMirror
+
+	if imageMirror == nil {
+		return values, nil
+	}
+
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+
+	values = utils.CoalesceValues(imageMirror.DefaultImageOverrides(chartValues), values)
+	imageMirror.RewriteDefaultImages(values)
+
+	if !imageMirror.Strict {
+		return values, nil
+	}
+
+	if unresolved := imageMirror.UnresolvedImages(chartValues, values); len(unresolved) > 0 {
+		return nil, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf(
+				"images must be pulled from the image mirror %s: %s",
+				imageMirror.Registry,
+				strings.Join(unresolved, ", "),
+			),
+			http.StatusBadRequest,
+		), types.ErrorCodeImageNotMirrored)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,177 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/imagemirror"
+	"github.com/porter-dev/porter/internal/integrations/cosign"
+	"github.com/porter-dev/porter/internal/registry"
+	"github.com/porter-dev/porter/internal/templater/utils"
+)
+
+// checkImagesSigned returns an error if the project enforces image signing, and the images
+// of a release are not signed by a signing authority of the project. Otherwise, it returns
+// the values with the verified images pinned to their digests, so that the images that are
+// deployed are the images that were verified even if their tags are moved. The default
+// images that are deployed before an application is built are not verified.
+func checkImagesSigned(
+	config *config.Config,
+	projectID uint,
+	chartValues, values map[string]interface{},
+) (map[string]interface{}, apierrors.RequestError) {
+	project, err := config.Repo.Project().ReadProject(projectID)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	if !project.ImageSigningEnforced {
+		return values, nil
+	}
+
+	if config.ImageVerifier == nil {
+		return nil, apierrors.NewErrInternal(fmt.Errorf("image signing is enforced, but the server cannot verify signatures"))
+	}
+
+	authorities, err := config.Repo.ImageSigningAuthority().ListImageSigningAuthoritiesByProjectID(projectID)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	verifyAuthorities := make([]*cosign.Authority, 0)
+
+	for _, authority := range authorities {
+		verifyAuthorities = append(verifyAuthorities, &cosign.Authority{
+			Name:      authority.Name,
+			PublicKey: authority.PublicKey,
+			Issuer:    authority.Issuer,
+			Subject:   authority.Subject,
+		})
+	}
+
+	// the images are read from copies of the values, since merging the values modifies
+	// them
+	defaultsCopy, err := copyValues(chartValues)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	merged, err := copyValues(values)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	merged = utils.CoalesceValues(defaultsCopy, merged)
+
+	images := imagemirror.ReleaseImageRefs(merged)
+
+	if len(images) == 0 {
+		return values, nil
+	}
+
+	creds, err := getRegistryCredentials(config, projectID)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	notSigned := make([]string, 0)
+	digests := make(map[string]string)
+
+	for path, image := range images {
+		if config.ImageMirror.IsDefaultImage(image) {
+			continue
+		}
+
+		digest, err := config.ImageVerifier.VerifyImage(image, verifyAuthorities, creds)
+
+		if err != nil {
+			notSigned = append(notSigned, fmt.Sprintf("%s: %s", path, err.Error()))
+			continue
+		}
+
+		digests[path] = digest
+	}
+
+	if len(notSigned) > 0 {
+		sort.Strings(notSigned)
+
+		return nil, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf(
+				"images must be signed by a signing authority of the project: %s",
+				strings.Join(notSigned, ", "),
+			),
+			http.StatusForbidden,
+		), types.ErrorCodeImageNotSigned)
+	}
+
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+
+	imagemirror.PinDigests(merged, values, digests)
+
+	return values, nil
+}
+
+// getRegistryCredentials returns the credentials of the registry integrations of a project,
+// keyed by the host of each registry, so that the signatures of private images can be read
+func getRegistryCredentials(config *config.Config, projectID uint) (map[string]*cosign.Credentials, error) {
+	regs, err := config.Repo.Registry().ListRegistriesByProjectID(projectID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]*cosign.Credentials)
+
+	for _, reg := range regs {
+		_reg := registry.Registry(*reg)
+
+		data, err := _reg.GetDockerConfigJSON(config.Repo, config.DOConf)
+
+		// registries with invalid credentials only prevent reading private signatures,
+		// which is reported when the images of the registry are verified
+		if err != nil {
+			continue
+		}
+
+		conf := &configfile.ConfigFile{}
+
+		if err := json.Unmarshal(data, conf); err != nil {
+			continue
+		}
+
+		for key, auth := range conf.AuthConfigs {
+			host := key
+
+			if i := strings.Index(host, "://"); i >= 0 {
+				host = host[i+3:]
+			}
+
+			host = strings.SplitN(host, "/", 2)[0]
+
+			if host == "index.docker.io" {
+				host = "docker.io"
+			}
+
+			res[host] = &cosign.Credentials{
+				Username: auth.Username,
+				Password: auth.Password,
+			}
+		}
+	}
+
+	return res, nil
+}
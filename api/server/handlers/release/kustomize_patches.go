@@ -0,0 +1,171 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// UpdateKustomizePatchesHandler sets the kustomize patches of a release, which are
+// applied to its rendered manifests after the other postrenderers. The patches take
+// effect on the next deploy of the release. Changes of the patches of protected releases
+// are stored as change requests, like upgrades.
+type UpdateKustomizePatchesHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpdateKustomizePatchesHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateKustomizePatchesHandler {
+	return &UpdateKustomizePatchesHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdateKustomizePatchesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateKustomizePatchesRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if _, err := helm.NewKustomizePostrenderer(request.Patches); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	if _, ok := readPorterRelease(c.PorterHandlerReadWriter, w, r, cluster, helmRelease); !ok {
+		return
+	}
+
+	protected, err := isReleaseProtected(c.Repo(), cluster, helmRelease.Name, helmRelease.Namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	// patches of protected releases are rendered before the change request is created, so
+	// that reviewers only see patches that apply to the release, and are rendered again
+	// once the change request is approved
+	if protected {
+		if reqErr := renderKustomizePatches(c.Config(), c.KubernetesAgentGetter, r, cluster, helmRelease, request.Patches); reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+
+		cr, err := createKustomizePatchesChangeRequest(c.Config(), r, user, cluster, helmRelease, request.Patches)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+
+		return
+	}
+
+	rel, reqErr := applyKustomizePatches(c.Config(), c.KubernetesAgentGetter, r, cluster, helmRelease, request.Patches)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	c.WriteResult(w, r, rel.ToReleaseType())
+}
+
+// applyKustomizePatches renders the release with the kustomize patches and then saves
+// them. It does not check whether the release is protected.
+func applyKustomizePatches(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	patches string,
+) (*models.Release, apierrors.RequestError) {
+	if reqErr := renderKustomizePatches(config, agentGetter, r, cluster, helmRelease, patches); reqErr != nil {
+		return nil, reqErr
+	}
+
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	rel.KustomizePatches = patches
+
+	rel, err = config.Repo.Release().UpdateRelease(rel)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	return rel, nil
+}
+
+// renderKustomizePatches dry-runs an upgrade of a release to its current chart and values
+// with the kustomize patches, so that patches of objects that the chart does not render
+// are rejected instead of failing every later deploy of the release. The patches are not
+// checked against the current manifests of the release, which may already have been
+// patched, such as by patches that delete objects.
+func renderKustomizePatches(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	patches string,
+) apierrors.RequestError {
+	helmAgent, err := agentGetter.GetHelmAgent(r, cluster, helmRelease.Namespace)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	registries, err := config.Repo.Registry().ListRegistriesByProjectID(cluster.ProjectID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	_, err = helmAgent.UpgradeReleaseByValues(&helm.UpgradeReleaseConfig{
+		Name:             helmRelease.Name,
+		Values:           helmRelease.Config,
+		Cluster:          cluster,
+		Repo:             config.Repo,
+		Registries:       registries,
+		DryRun:           true,
+		KustomizePatches: &patches,
+	}, config.DOConf)
+
+	if err != nil {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("kustomize patches could not be applied to the release: %s", err.Error()),
+			http.StatusBadRequest,
+		)
+	}
+
+	return nil
+}
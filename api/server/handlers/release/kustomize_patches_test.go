@@ -0,0 +1,164 @@
+package release_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+)
+
+const kustomizeTestDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`
+
+func TestUpdateKustomizePatches(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	patches := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+`
+
+	rr := updateKustomizeTestPatches(t, config, user, cluster, patches)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "patches should be saved")
+
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, patches, rel.KustomizePatches)
+}
+
+func TestUpdateKustomizePatchesOfObjectNotRendered(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	rr := updateKustomizeTestPatches(t, config, user, cluster, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 2
+`)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode, "patches of objects that are not rendered should be rejected")
+
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, rel.KustomizePatches, "rejected patches should not be saved")
+}
+
+func TestUpdateKustomizePatchesOfProtectedRelease(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rel.Protected = true
+
+	if _, err := config.Repo.Release().UpdateRelease(rel); err != nil {
+		t.Fatal(err)
+	}
+
+	patches := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+`
+
+	rr := updateKustomizeTestPatches(t, config, user, cluster, patches)
+
+	assert.Equal(t, http.StatusAccepted, rr.Result().StatusCode, "status code should be accepted")
+
+	cr, err := config.Repo.ChangeRequest().ReadChangeRequest(cluster.ID, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.ChangeRequestKustomizePatches, cr.Operation)
+	assert.Equal(t, patches, cr.KustomizePatches)
+	assert.Equal(t, 1, cr.BaseRevision)
+
+	rel, err = config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, rel.KustomizePatches, "patches should only be saved once the change request is approved")
+}
+
+func getKustomizeTestHelmRelease() *helmrelease.Release {
+	return &helmrelease.Release{
+		Name:      "web",
+		Namespace: "default",
+		Version:   1,
+		Manifest:  kustomizeTestDeployment,
+		Config:    map[string]interface{}{},
+		Info:      &helmrelease.Info{Status: helmrelease.StatusDeployed},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "web", Version: "0.1.0"},
+			Templates: []*chart.File{
+				{Name: "templates/deployment.yaml", Data: []byte(kustomizeTestDeployment)},
+			},
+		},
+	}
+}
+
+func updateKustomizeTestPatches(
+	t *testing.T,
+	config *config.Config,
+	user *models.User,
+	cluster *models.Cluster,
+	patches string,
+) *httptest.ResponseRecorder {
+	helmRelease := getKustomizeTestHelmRelease()
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/kustomize_patches",
+		&types.UpdateKustomizePatchesRequest{Patches: patches},
+	)
+
+	req = withReleaseScopes(t, req, user, cluster, helmRelease)
+	req = withPreDeployTestAgents(t, config, req, helmRelease)
+
+	handler := release.NewUpdateKustomizePatchesHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
@@ -0,0 +1,60 @@
+package release
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ListChangeRequestsHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewListChangeRequestsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListChangeRequestsHandler {
+	return &ListChangeRequestsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListChangeRequestsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	request := &types.ListChangeRequestsRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	statuses := make([]types.ChangeRequestStatus, 0)
+
+	if request.Status != "" {
+		statuses = append(statuses, request.Status)
+	}
+
+	crs, err := c.Repo().ChangeRequest().ListChangeRequests(cluster.ID, namespace, name, statuses...)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListChangeRequestsResponse, 0)
+
+	for _, cr := range crs {
+		res = append(res, cr.ToReleaseChangeRequestType())
+	}
+
+	c.WriteResult(w, r, res)
+}
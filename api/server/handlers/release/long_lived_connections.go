@@ -0,0 +1,125 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// UpdateLongLivedConnectionsHandler toggles the ingress settings for websockets and
+// server-sent events of a web release. The settings depend on the ingress controller that
+// serves the ingress class of the release, and take effect on the next deploy of the
+// release.
+type UpdateLongLivedConnectionsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpdateLongLivedConnectionsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateLongLivedConnectionsHandler {
+	return &UpdateLongLivedConnectionsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdateLongLivedConnectionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateLongLivedConnectionsRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if helmRelease.Chart == nil || helmRelease.Chart.Metadata == nil || helmRelease.Chart.Metadata.Name != "web" {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("long-lived connections can only be enabled for web releases"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	controllerType, err := agent.GetIngressClassControllerType(getManifestIngressClass(helmRelease))
+
+	// the ingress controller cannot be detected if the agent may not read ingress classes,
+	// in which case the ingresses of the release are deployed without the settings
+	if err != nil {
+		c.Config().Logger.Error().Err(err).Msgf("could not detect the ingress controller of release %s", helmRelease.Name)
+		controllerType = kubernetes.IngressControllerUnknown
+	} else if _, ok := helm.LongLivedConnectionAnnotations[controllerType]; request.Enabled && !ok {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("long-lived connections are not supported by the %s ingress controller of this release", controllerType),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	rel, ok := readPorterRelease(c.PorterHandlerReadWriter, w, r, cluster, helmRelease)
+
+	if !ok {
+		return
+	}
+
+	rel.LongLivedConnections = request.Enabled
+
+	rel, err = c.Repo().Release().UpdateRelease(rel)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.UpdateLongLivedConnectionsResponse{
+		PorterRelease:     rel.ToReleaseType(),
+		IngressController: string(controllerType),
+	})
+}
+
+// getManifestIngressClass returns the ingress class of the first ingress of a release,
+// from its spec or from the legacy ingress class annotation
+func getManifestIngressClass(helmRelease *release.Release) string {
+	for _, obj := range grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest)) {
+		if kind, _ := obj["kind"].(string); kind != "Ingress" {
+			continue
+		}
+
+		spec, _ := obj["spec"].(map[string]interface{})
+
+		if className, _ := spec["ingressClassName"].(string); className != "" {
+			return className
+		}
+
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		className, _ := annotations[kubernetes.LegacyIngressClassAnnotation].(string)
+
+		return className
+	}
+
+	return ""
+}
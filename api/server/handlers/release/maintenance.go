@@ -0,0 +1,142 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+type GetMaintenanceModeHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetMaintenanceModeHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetMaintenanceModeHandler {
+	return &GetMaintenanceModeHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetMaintenanceModeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.MaintenanceModeResponse{
+		Ingresses: getReleaseIngresses(helmRelease),
+	}
+
+	for _, ingress := range res.Ingresses {
+		inMaintenance, err := agent.IsIngressInMaintenanceMode(helmRelease.Namespace, ingress)
+
+		if err != nil && err != kubernetes.IsNotFoundError {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		res.Enabled = res.Enabled || inMaintenance
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type UpdateMaintenanceModeHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpdateMaintenanceModeHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateMaintenanceModeHandler {
+	return &UpdateMaintenanceModeHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdateMaintenanceModeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateMaintenanceModeRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	ingresses := getReleaseIngresses(helmRelease)
+
+	if len(ingresses) == 0 {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("maintenance mode is only supported for releases with an ingress"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	page, err := kubernetes.GetMaintenancePage(request.Page, request.Message)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	for _, ingress := range ingresses {
+		err := agent.SetIngressMaintenanceMode(helmRelease.Namespace, ingress, request.Enabled, page)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	}
+
+	c.WriteResult(w, r, &types.MaintenanceModeResponse{
+		Enabled:   request.Enabled,
+		Ingresses: ingresses,
+	})
+}
+
+// getReleaseIngresses returns the names of the ingresses in the manifest of a release
+func getReleaseIngresses(helmRelease *release.Release) []string {
+	res := make([]string, 0)
+
+	yamlArr := grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest))
+
+	for _, obj := range grapher.ParseObjs(yamlArr, helmRelease.Namespace) {
+		if obj.Kind == "Ingress" {
+			res = append(res, obj.Name)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,124 @@
+package release
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+type GetNetworkPolicyPresetHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewGetNetworkPolicyPresetHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetNetworkPolicyPresetHandler {
+	return &GetNetworkPolicyPresetHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *GetNetworkPolicyPresetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	preset, err := agent.GetNetworkPolicyPreset(helmRelease.Namespace, helmRelease.Name)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	namespacePreset, err := agent.GetNetworkPolicyPreset(helmRelease.Namespace, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, getNetworkPolicyPresetResponse(helmRelease.Name, preset, namespacePreset))
+}
+
+type UpdateNetworkPolicyPresetHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpdateNetworkPolicyPresetHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateNetworkPolicyPresetHandler {
+	return &UpdateNetworkPolicyPresetHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdateNetworkPolicyPresetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateNetworkPolicyPresetRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	err = agent.ApplyNetworkPolicyPreset(helmRelease.Namespace, helmRelease.Name, request.Preset)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	namespacePreset, err := agent.GetNetworkPolicyPreset(helmRelease.Namespace, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, getNetworkPolicyPresetResponse(helmRelease.Name, request.Preset, namespacePreset))
+}
+
+func getNetworkPolicyPresetResponse(
+	name string,
+	preset, namespacePreset types.NetworkPolicyPreset,
+) *types.NetworkPolicyPresetResponse {
+	res := &types.NetworkPolicyPresetResponse{
+		Preset:          preset,
+		NamespacePreset: namespacePreset,
+	}
+
+	if preset != types.NetworkPolicyPresetOpen {
+		res.PolicyName = kubernetes.GetNetworkPolicyPresetName(name)
+	}
+
+	return res
+}
@@ -0,0 +1,223 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+type PauseReleaseHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewPauseReleaseHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *PauseReleaseHandler {
+	return &PauseReleaseHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *PauseReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	rel, ok := readPorterRelease(c.PorterHandlerWriter, w, r, cluster, helmRelease)
+
+	if !ok {
+		return
+	}
+
+	if rel.Paused {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release is already paused"),
+			http.StatusConflict,
+		))
+
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	state, pauseErr := agent.PauseControllers(getReleaseControllers(helmRelease))
+
+	// the state is stored even if pausing failed partway, so that the controllers which
+	// were already paused can be resumed
+	stateBytes, err := json.Marshal(state)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rel.Paused = true
+	rel.PausedState = stateBytes
+
+	rel, err = c.Repo().Release().UpdateRelease(rel)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if pauseErr != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(pauseErr))
+		return
+	}
+
+	c.WriteResult(w, r, rel.ToReleaseType())
+}
+
+type ResumeReleaseHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewResumeReleaseHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ResumeReleaseHandler {
+	return &ResumeReleaseHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ResumeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	rel, ok := readPorterRelease(c.PorterHandlerWriter, w, r, cluster, helmRelease)
+
+	if !ok {
+		return
+	}
+
+	if !rel.Paused {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release is not paused"),
+			http.StatusConflict,
+		))
+
+		return
+	}
+
+	state := &kubernetes.PausedState{}
+
+	if err := json.Unmarshal(rel.PausedState, state); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := agent.ResumeControllers(getReleaseControllers(helmRelease), state); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	rel.Paused = false
+	rel.PausedState = nil
+
+	rel, err = c.Repo().Release().UpdateRelease(rel)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, rel.ToReleaseType())
+}
+
+// readPorterRelease reads the Porter release model for the Helm release, and writes an
+// error if the release is not managed by Porter
+func readPorterRelease(
+	c handlers.PorterHandlerWriter,
+	w http.ResponseWriter,
+	r *http.Request,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+) (*models.Release, bool) {
+	rel, err := c.Repo().Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("release %s is not managed by Porter", helmRelease.Name),
+				http.StatusBadRequest,
+			))
+
+			return nil, false
+		}
+
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	return rel, true
+}
+
+// getReleaseControllers returns the controllers in the manifest of a release
+func getReleaseControllers(helmRelease *release.Release) []grapher.Object {
+	yamlArr := grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest))
+	controllers := grapher.ParseControllers(yamlArr)
+
+	for i := range controllers {
+		controllers[i].Namespace = helmRelease.Namespace
+	}
+
+	return controllers
+}
+
+// checkReleasePaused returns an error if the release is paused. Paused releases are not
+// deployed until they are resumed, since a deploy would scale their controllers up again
+// while the release is still marked as paused.
+func checkReleasePaused(
+	config *config.Config,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+) apierrors.RequestError {
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+
+		return apierrors.NewErrInternal(err)
+	}
+
+	if rel.Paused {
+		return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s is paused, and must be resumed before it is deployed", helmRelease.Name),
+			http.StatusConflict,
+		), types.ErrorCodeReleasePaused)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package release_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/stretchr/testify/assert"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+)
+
+func TestDeployPausedRelease(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rel.Paused = true
+	rel.PausedState = []byte("{}")
+
+	if _, err := config.Repo.Release().UpdateRelease(rel); err != nil {
+		t.Fatal(err)
+	}
+
+	prevRelease := getPreDeployTestHelmRelease(1, "v1")
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/upgrade",
+		&types.UpgradeReleaseRequest{
+			Values: "image:\n  repository: app\n  tag: v2\n",
+		},
+	)
+
+	req = withPreDeployTestAgents(t, config, withReleaseScopes(t, req, user, cluster, prevRelease), prevRelease)
+
+	release.NewUpgradeReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	).ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusConflict, &types.ExternalError{
+		Error:     "release web is paused, and must be resumed before it is deployed",
+		ErrorCode: types.ErrorCodeReleasePaused,
+	})
+
+	prevRelease.Info.Status = helmrelease.StatusSuperseded
+	helmRelease := getPreDeployTestHelmRelease(2, "v2")
+
+	req, rr = apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/rollback",
+		&types.RollbackReleaseRequest{
+			Revision: 1,
+		},
+	)
+
+	req = withPreDeployTestAgents(t, config, withReleaseScopes(t, req, user, cluster, helmRelease), prevRelease, helmRelease)
+
+	release.NewRollbackReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Result().StatusCode, "paused releases should not be rolled back")
+
+	rel, err = config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, rel.Paused, "release should still be paused")
+}
@@ -0,0 +1,287 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/predeploy"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// UpdatePreDeployCommandHandler sets the command that is run with the new image of a
+// release before each upgrade that changes the image
+type UpdatePreDeployCommandHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdatePreDeployCommandHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdatePreDeployCommandHandler {
+	return &UpdatePreDeployCommandHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdatePreDeployCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdatePreDeployCommandRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	rel, ok := readPorterRelease(c.PorterHandlerReadWriter, w, r, cluster, helmRelease)
+
+	if !ok {
+		return
+	}
+
+	rel.PreDeployCommand = strings.TrimSpace(request.Command)
+
+	rel, err := c.Repo().Release().UpdateRelease(rel)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, rel.ToReleaseType())
+}
+
+// GetPreDeployRunHandler returns the latest run of the pre-deploy command of a release,
+// so that clients can wait for upgrades that are deployed once the command succeeds
+type GetPreDeployRunHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetPreDeployRunHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetPreDeployRunHandler {
+	return &GetPreDeployRunHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetPreDeployRunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	run, err := c.Repo().PreDeployRun().ReadLatestPreDeployRun(cluster.ID, helmRelease.Namespace, helmRelease.Name)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("the pre-deploy command of the release has not run"),
+				http.StatusNotFound,
+			))
+
+			return
+		}
+
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, run.ToPreDeployRunType())
+}
+
+// preDeployRunStaleAfter is how long after it started a running pre-deploy run is
+// considered stopped, such as when the server replica that ran it stopped. It outlasts
+// the timeout of the job and the upgrade that follows it.
+const preDeployRunStaleAfter = 2 * predeploy.DefaultTimeout
+
+// getPreDeployImage returns the image that the pre-deploy command of a release runs with
+// before the release is deployed with the new chart and values, along with the release
+// that stores the command. The image is empty if the release has no command or if the
+// new values do not change the image. Releases that are not stored have no command, but
+// other errors are returned, so that the command is never skipped.
+func getPreDeployImage(
+	config *config.Config,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	newChart *chart.Chart,
+	newValues map[string]interface{},
+) (*models.Release, string, error) {
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", fmt.Errorf("could not read the pre-deploy command of the release: %w", err)
+	}
+
+	if rel.PreDeployCommand == "" {
+		return rel, "", nil
+	}
+
+	newImage := getImage(newValues, helm.GetImageValuesKey(newChart))
+
+	if newImage == "" || newImage == getImage(helmRelease.Config, helm.GetImageValuesKey(helmRelease.Chart)) {
+		return rel, "", nil
+	}
+
+	return rel, newImage, nil
+}
+
+// startPreDeploy runs the pre-deploy command of a release with the new image as a job in
+// the background, and returns the run. Once the job finishes, deploy is called with the
+// error of the command and a request that outlives the request that started the run, and
+// the run succeeds once the release is deployed. Only one run of a release is started at
+// a time, so that commands such as migrations never run alongside each other.
+func startPreDeploy(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	rel *models.Release,
+	helmRelease *release.Release,
+	image string,
+	deploy func(r *http.Request, preDeployErr error) apierrors.RequestError,
+) (*models.PreDeployRun, apierrors.RequestError) {
+	latest, err := config.Repo.PreDeployRun().ReadLatestPreDeployRun(cluster.ID, helmRelease.Namespace, helmRelease.Name)
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	if err == nil && latest.Status == types.PreDeployRunRunning {
+		if time.Since(latest.CreatedAt) < preDeployRunStaleAfter {
+			return nil, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("the pre-deploy command of the release is already running"),
+				http.StatusConflict,
+			), types.ErrorCodePreDeployRunning)
+		}
+
+		finishPreDeployRun(config, latest, fmt.Errorf("the pre-deploy command did not finish"))
+	}
+
+	k8sAgent, err := agentGetter.GetAgent(r, cluster, helmRelease.Namespace)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	deployReq, err := newReleaseRequest(
+		user,
+		cluster,
+		helmRelease.Namespace,
+		types.DeploySource(r.Header.Get(types.DeploySourceHeader)),
+	)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	run, err := config.Repo.PreDeployRun().CreatePreDeployRun(&models.PreDeployRun{
+		ProjectID: cluster.ProjectID,
+		ClusterID: cluster.ID,
+		Namespace: helmRelease.Namespace,
+		Name:      helmRelease.Name,
+		Image:     image,
+		Command:   rel.PreDeployCommand,
+		Status:    types.PreDeployRunRunning,
+	})
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	runner := &predeploy.Runner{
+		K8sAgent: k8sAgent,
+	}
+
+	// the run is updated by the goroutine from here on, so the returned run is a copy
+	res := *run
+
+	go func() {
+		result, preDeployErr := runner.Run(helmRelease, image, rel.PreDeployCommand)
+
+		if result != nil {
+			run.JobName = result.JobName
+			run.Logs = result.Logs
+		}
+
+		runErr := preDeployErr
+
+		if reqErr := deploy(deployReq, preDeployErr); reqErr != nil && preDeployErr == nil {
+			config.Logger.Error().Err(reqErr).Msgf(
+				"could not deploy release %s in namespace %s after its pre-deploy command",
+				helmRelease.Name,
+				helmRelease.Namespace,
+			)
+
+			runErr = errors.New(reqErr.ExternalError())
+		}
+
+		finishPreDeployRun(config, run, runErr)
+	}()
+
+	return &res, nil
+}
+
+// finishPreDeployRun marks a run as finished, and as failed if err is set
+func finishPreDeployRun(config *config.Config, run *models.PreDeployRun, err error) {
+	now := time.Now()
+
+	run.Status = types.PreDeployRunSucceeded
+	run.FinishedAt = &now
+
+	if err != nil {
+		run.Status = types.PreDeployRunFailed
+		run.Error = err.Error()
+
+		// the logs of failed commands are stored separately from the error
+		var preDeployErr *predeploy.Error
+
+		if errors.As(err, &preDeployErr) {
+			run.Error = fmt.Sprintf("pre-deploy command failed: %s", preDeployErr.Reason)
+		}
+	}
+
+	if _, err := config.Repo.PreDeployRun().UpdatePreDeployRun(run); err != nil {
+		config.Logger.Error().Err(err).Msgf("could not update pre-deploy run %d", run.ID)
+	}
+}
+
+// getImage returns the image under the image values key of the values of a release in
+// repository:tag form, or an empty string if the values do not set an image
+func getImage(values map[string]interface{}, imageValuesKey string) string {
+	image := helm.GetImageValues(values, imageValuesKey)
+
+	if image == nil {
+		return ""
+	}
+
+	repository, _ := image["repository"].(string)
+
+	if repository == "" {
+		return ""
+	}
+
+	tag := fmt.Sprintf("%v", image["tag"])
+
+	if image["tag"] == nil || tag == "" {
+		tag = "latest"
+	}
+
+	return fmt.Sprintf("%s:%s", repository, tag)
+}
@@ -0,0 +1,285 @@
+package release_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const preDeployTestManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`
+
+func TestUpgradeWithPreDeployCommandIsAccepted(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	// the image of custom charts is read from the image values key of the chart
+	helmRelease := getPreDeployTestHelmRelease(1, "v1")
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/upgrade",
+		&types.UpgradeReleaseRequest{
+			Values: "app:\n  image:\n    repository: app\n    tag: v2\n",
+		},
+	)
+
+	req = withPreDeployTestAgents(t, config, withReleaseScopes(t, req, user, cluster, helmRelease), helmRelease)
+
+	handler := release.NewUpgradeReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	// the upgrade does not wait for the command, and is deployed once it succeeds
+	assert.Equal(t, http.StatusAccepted, rr.Result().StatusCode, "status code should be accepted")
+
+	res := &types.UpgradeReleaseResponse{}
+
+	if err := json.NewDecoder(rr.Body).Decode(res); err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.NotNil(t, res.PreDeployRun, "response should include the pre-deploy run") {
+		assert.Equal(t, types.PreDeployRunRunning, res.PreDeployRun.Status, "pre-deploy run should be running")
+		assert.Equal(t, "app:v2", res.PreDeployRun.Image, "pre-deploy command should run with the new image")
+		assert.Equal(t, "./migrate", res.PreDeployRun.Command)
+	}
+
+	// upgrades are rejected while the command is running
+	req, rr = apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/upgrade",
+		&types.UpgradeReleaseRequest{
+			Values: "app:\n  image:\n    repository: app\n    tag: v3\n",
+		},
+	)
+
+	req = withPreDeployTestAgents(t, config, withReleaseScopes(t, req, user, cluster, helmRelease), helmRelease)
+
+	handler.ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusConflict, &types.ExternalError{
+		Error:     "the pre-deploy command of the release is already running",
+		ErrorCode: types.ErrorCodePreDeployRunning,
+	})
+}
+
+func TestRollbackWithPreDeployCommandIsAccepted(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	prevRelease := getPreDeployTestHelmRelease(1, "v1")
+	prevRelease.Info.Status = helmrelease.StatusSuperseded
+
+	helmRelease := getPreDeployTestHelmRelease(2, "v2")
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/rollback",
+		&types.RollbackReleaseRequest{
+			Revision: 1,
+		},
+	)
+
+	req = withPreDeployTestAgents(t, config, withReleaseScopes(t, req, user, cluster, helmRelease), prevRelease, helmRelease)
+
+	handler := release.NewRollbackReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Result().StatusCode, "status code should be accepted")
+
+	run, err := config.Repo.PreDeployRun().ReadLatestPreDeployRun(cluster.ID, "default", "web")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "app:v1", run.Image, "pre-deploy command should run with the image of the revision")
+}
+
+func TestUpgradeWithoutImageChangeSkipsPreDeployCommand(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+	helmRelease := getPreDeployTestHelmRelease(1, "v1")
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/upgrade",
+		&types.UpgradeReleaseRequest{
+			Values: "app:\n  image:\n    repository: app\n    tag: v1\nreplicaCount: 2\n",
+		},
+	)
+
+	req = withPreDeployTestAgents(t, config, withReleaseScopes(t, req, user, cluster, helmRelease), helmRelease)
+
+	handler := release.NewUpgradeReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	_, err := config.Repo.PreDeployRun().ReadLatestPreDeployRun(cluster.ID, "default", "web")
+
+	assert.Error(t, err, "pre-deploy command should not run when the image does not change")
+}
+
+func TestGetPreDeployRun(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+	helmRelease := getPreDeployTestHelmRelease(1, "v1")
+
+	_, err := config.Repo.PreDeployRun().CreatePreDeployRun(&models.PreDeployRun{
+		ProjectID: 1,
+		ClusterID: cluster.ID,
+		Namespace: "default",
+		Name:      "web",
+		Image:     "app:v2",
+		Command:   "./migrate",
+		Status:    types.PreDeployRunFailed,
+		Logs:      "migration failed",
+		Error:     "pre-deploy command failed: BackoffLimitExceeded",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbGet),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/pre_deploy_run",
+		nil,
+	)
+
+	req = withReleaseScopes(t, req, user, cluster, helmRelease)
+
+	handler := release.NewGetPreDeployRunHandler(config, shared.NewDefaultResultWriter(config))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "status code should be ok")
+
+	run := &types.PreDeployRun{}
+
+	if err := json.NewDecoder(rr.Body).Decode(run); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.PreDeployRunFailed, run.Status)
+	assert.Equal(t, "migration failed", run.Logs, "run should include the logs of the command")
+	assert.Equal(t, "pre-deploy command failed: BackoffLimitExceeded", run.Error)
+}
+
+func createPreDeployTestRelease(t *testing.T) (*config.Config, *models.User, *models.Cluster) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+	cluster := &models.Cluster{ProjectID: 1, NotificationsDisabled: true}
+	cluster.ID = 1
+
+	if _, err := config.Repo.Project().CreateProject(&models.Project{Name: "project"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := config.Repo.Release().CreateRelease(&models.Release{
+		ProjectID:        1,
+		ClusterID:        cluster.ID,
+		Name:             "web",
+		Namespace:        "default",
+		PreDeployCommand: "./migrate",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return config, user, cluster
+}
+
+func getPreDeployTestHelmRelease(version int, tag string) *helmrelease.Release {
+	return &helmrelease.Release{
+		Name:      "web",
+		Namespace: "default",
+		Version:   version,
+		Manifest:  preDeployTestManifest,
+		Config: map[string]interface{}{
+			"app": map[string]interface{}{
+				"image": map[string]interface{}{"repository": "app", "tag": tag},
+			},
+		},
+		Info: &helmrelease.Info{Status: helmrelease.StatusDeployed},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{
+				Name:    "web",
+				Version: "0.1.0",
+				Annotations: map[string]string{
+					helm.CustomChartAnnotation:    "true",
+					helm.ImageValuesKeyAnnotation: "app.image",
+				},
+			},
+		},
+	}
+}
+
+// withPreDeployTestAgents adds agents with the revisions of the release and the deployment
+// whose pod template the pre-deploy job runs with
+func withPreDeployTestAgents(
+	t *testing.T,
+	config *config.Config,
+	req *http.Request,
+	helmReleases ...*helmrelease.Release,
+) *http.Request {
+	k8sAgent := kubernetes.GetAgentTesting(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "web", Image: "app:v1"}},
+				},
+			},
+		},
+	})
+
+	helmAgent := helm.GetAgentTesting(&helm.Form{Namespace: "default"}, nil, config.Logger, k8sAgent)
+
+	for _, helmRelease := range helmReleases {
+		if err := helmAgent.ActionConfig.Releases.Create(helmRelease); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.WithValue(req.Context(), authz.KubernetesAgentCtxKey, k8sAgent)
+	ctx = context.WithValue(ctx, authz.HelmAgentCtxKey, helmAgent)
+
+	return req.WithContext(ctx)
+}
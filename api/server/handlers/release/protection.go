@@ -0,0 +1,131 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type GetReleaseProtectionHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetReleaseProtectionHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetReleaseProtectionHandler {
+	return &GetReleaseProtectionHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetReleaseProtectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	rel, ok := readProtectableRelease(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	nsProtected, err := c.Repo().ChangeRequest().IsNamespaceProtected(cluster.ID, namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.ReleaseProtectionResponse{
+		Protected:          rel.Protected,
+		NamespaceProtected: nsProtected,
+	})
+}
+
+type UpdateReleaseProtectionHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateReleaseProtectionHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateReleaseProtectionHandler {
+	return &UpdateReleaseProtectionHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateReleaseProtectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	request := &types.UpdateProtectionRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	rel, ok := readProtectableRelease(c.PorterHandlerReadWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	rel.Protected = request.Protected
+
+	rel, err := c.Repo().Release().UpdateRelease(rel)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	nsProtected, err := c.Repo().ChangeRequest().IsNamespaceProtected(cluster.ID, namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.ReleaseProtectionResponse{
+		Protected:          rel.Protected,
+		NamespaceProtected: nsProtected,
+	})
+}
+
+// readProtectableRelease reads the Porter release in the URL. Only releases that are
+// managed by Porter can be protected.
+func readProtectableRelease(
+	c handlers.PorterHandler,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*models.Release, bool) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
+
+	rel, err := c.Repo().Release().ReadRelease(cluster.ID, name, namespace)
+
+	if err == gorm.ErrRecordNotFound {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s is not managed by Porter", name),
+			http.StatusNotFound,
+		))
+
+		return nil, false
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	return rel, true
+}
@@ -0,0 +1,58 @@
+package release
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// UpdateRestartOnEnvChangeHandler toggles whether the pods of a release are rolled when
+// the configmaps and secrets they read change. The toggle takes effect on the next
+// deploy of the release.
+type UpdateRestartOnEnvChangeHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateRestartOnEnvChangeHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateRestartOnEnvChangeHandler {
+	return &UpdateRestartOnEnvChangeHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateRestartOnEnvChangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateRestartOnEnvChangeRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	rel, ok := readPorterRelease(c.PorterHandlerReadWriter, w, r, cluster, helmRelease)
+
+	if !ok {
+		return
+	}
+
+	rel.RestartOnEnvChange = request.Enabled
+
+	rel, err := c.Repo().Release().UpdateRelease(rel)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, rel.ToReleaseType())
+}
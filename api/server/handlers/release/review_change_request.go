@@ -0,0 +1,269 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/slack"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+type ApproveChangeRequestHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewApproveChangeRequestHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ApproveChangeRequestHandler {
+	return &ApproveChangeRequestHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ApproveChangeRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	cr, ok := readPendingChangeRequest(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, cr.Namespace)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	helmRelease, err := helmAgent.GetRelease(cr.Name, 0, true)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s not found: %s", cr.Name, err.Error()),
+			http.StatusNotFound,
+		), types.ErrorCodeReleaseNotFound))
+
+		return
+	}
+
+	var applyErr apierrors.RequestError
+
+	// upgrades, rollbacks and kustomize patches are reviewed against the revision they
+	// were requested for, so they are not applied once the release was upgraded since,
+	// which would revert the later upgrade or apply patches that were not rendered
+	// against it
+	if cr.Operation != types.ChangeRequestDelete && cr.BaseRevision != 0 && cr.BaseRevision != helmRelease.Version {
+		applyErr = apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf(
+				"release was upgraded to revision %d since the change was requested for revision %d",
+				helmRelease.Version,
+				cr.BaseRevision,
+			),
+			http.StatusConflict,
+		), types.ErrorCodeRevisionConflict)
+	} else {
+		applyErr = applyChangeRequest(c.Config(), c.KubernetesAgentGetter, r, user, cluster, helmRelease, cr)
+	}
+
+	cr.ReviewedByUserID = user.ID
+	cr.Status = types.ChangeRequestApproved
+
+	if applyErr != nil {
+		cr.Status = types.ChangeRequestFailed
+		cr.Error = applyErr.Error()
+	}
+
+	cr, err = c.Repo().ChangeRequest().UpdateChangeRequest(cr)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if applyErr != nil {
+		c.HandleAPIError(w, r, applyErr)
+		return
+	}
+
+	notifyChangeRequest(
+		c.Config(),
+		cluster,
+		cr,
+		slack.StatusChangeApproved,
+		fmt.Sprintf("Approved by %s", user.Email),
+	)
+
+	c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+}
+
+// applyChangeRequest applies an approved change request to a release
+func applyChangeRequest(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	cr *models.ReleaseChangeRequest,
+) apierrors.RequestError {
+	switch cr.Operation {
+	case types.ChangeRequestUpgrade, types.ChangeRequestRollback:
+		if err := checkReleasePaused(config, cluster, helmRelease); err != nil {
+			return err
+		}
+	}
+
+	switch cr.Operation {
+	case types.ChangeRequestUpgrade:
+		values, err := getChangeRequestValues(cr)
+
+		if err != nil {
+			return apierrors.NewErrInternal(err)
+		}
+
+		_, reqErr := applyUpgrade(config, agentGetter, r, &upgradeOpts{
+			user:        user,
+			cluster:     cluster,
+			helmRelease: helmRelease,
+			request: &types.UpgradeReleaseRequest{
+				Values:       values,
+				ChartVersion: cr.ChartVersion,
+			},
+		})
+
+		return reqErr
+	case types.ChangeRequestRollback:
+		_, reqErr := applyRollback(config, agentGetter, r, user, cluster, helmRelease, cr.RollbackRevision)
+
+		return reqErr
+	case types.ChangeRequestDelete:
+		if err := checkDeletionProtection(config, cluster, helmRelease); err != nil {
+			return err
+		}
+
+		_, err := applyDelete(config, agentGetter, r, user, cluster, helmRelease, &types.DeleteReleaseRequest{
+			Cascade:       cr.DeleteCascade,
+			DeleteVolumes: cr.DeleteVolumes,
+		})
+
+		return err
+	case types.ChangeRequestKustomizePatches:
+		_, err := applyKustomizePatches(config, agentGetter, r, cluster, helmRelease, cr.KustomizePatches)
+
+		return err
+	}
+
+	return apierrors.NewErrInternal(fmt.Errorf("unknown change request operation %s", cr.Operation))
+}
+
+type RejectChangeRequestHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewRejectChangeRequestHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *RejectChangeRequestHandler {
+	return &RejectChangeRequestHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *RejectChangeRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	cr, ok := readPendingChangeRequest(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	cr.ReviewedByUserID = user.ID
+	cr.Status = types.ChangeRequestRejected
+
+	cr, err := c.Repo().ChangeRequest().UpdateChangeRequest(cr)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	notifyChangeRequest(
+		c.Config(),
+		cluster,
+		cr,
+		slack.StatusChangeRejected,
+		fmt.Sprintf("Rejected by %s", user.Email),
+	)
+
+	c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+}
+
+// readPendingChangeRequest reads the change request in the URL, and writes an error if it
+// is not pending or the user reviewing it is the user that requested it
+func readPendingChangeRequest(
+	c handlers.PorterHandler,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*models.ReleaseChangeRequest, bool) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
+
+	crID, reqErr := requestutils.GetURLParamUint(r, types.URLParamChangeRequestID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return nil, false
+	}
+
+	cr, err := c.Repo().ChangeRequest().ReadChangeRequest(cluster.ID, crID)
+
+	if (err != nil && errors.Is(err, gorm.ErrRecordNotFound)) || (err == nil && (cr.Name != name || cr.Namespace != namespace)) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("change request with id %d not found", crID),
+			http.StatusNotFound,
+		))
+
+		return nil, false
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	if cr.Status != types.ChangeRequestPending {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("change request is %s", cr.Status),
+			http.StatusConflict,
+		))
+
+		return nil, false
+	}
+
+	if cr.RequestedByUserID == user.ID {
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(
+			fmt.Errorf("user %d cannot review their own change request", user.ID),
+		))
+
+		return nil, false
+	}
+
+	return cr, true
+}
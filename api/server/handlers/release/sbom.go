@@ -0,0 +1,190 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/sbom"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+type CreateReleaseSBOMHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateReleaseSBOMHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateReleaseSBOMHandler {
+	return &CreateReleaseSBOMHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP stores the dependency inventory of an image of the release, which the CLI
+// uploads after it pushes the image. The inventory becomes the inventory of the release
+// once the release is deployed with the image, so that the inventory reflects the image
+// that the release runs. Images that already have an inventory are not parsed again,
+// since the digest of an image identifies its content.
+func (c *CreateReleaseSBOMHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.CreateReleaseSBOMRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if len(request.Document) > types.MaxSBOMBytes {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("SBOM documents must be smaller than %d bytes", types.MaxSBOMBytes),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	// the inventory is uploaded after the image is pushed, so the tag is listed from then on
+	if c.Config().RegistryIndex != nil {
+		c.Config().RegistryIndex.InvalidateImageRepository(cluster.ProjectID, request.ImageRepo)
+	}
+
+	if _, ok := readBuildLogRelease(c.PorterHandlerReadWriter, w, r); !ok {
+		return
+	}
+
+	imageSBOM, err := c.Repo().ImageSBOM().ReadImageSBOMByImage(cluster.ProjectID, request.ImageRepo, request.Tag)
+
+	if err == nil && imageSBOM.Digest != request.Digest {
+		// the tag was pushed again with a new image
+		err = gorm.ErrRecordNotFound
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		pkgs, err := sbom.Parse(request.Format, []byte(request.Document))
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+			return
+		}
+
+		imageSBOM = &models.ImageSBOM{
+			ProjectID: cluster.ProjectID,
+			Digest:    request.Digest,
+			ImageRepo: request.ImageRepo,
+			Tag:       request.Tag,
+			Format:    request.Format,
+		}
+
+		for _, pkg := range pkgs {
+			imageSBOM.Packages = append(imageSBOM.Packages, models.SBOMPackage{
+				Name:    pkg.Name,
+				Version: pkg.Version,
+				Type:    pkg.Type,
+				PURL:    pkg.PURL,
+			})
+		}
+
+		imageSBOM, err = c.Repo().ImageSBOM().CreateImageSBOM(imageSBOM)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, imageSBOM.ToImageSBOMType())
+}
+
+type GetReleaseSBOMHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewGetReleaseSBOMHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GetReleaseSBOMHandler {
+	return &GetReleaseSBOMHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP returns the dependency inventory of the image that the release runs
+func (c *GetReleaseSBOMHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	release, ok := readBuildLogRelease(c.PorterHandlerReadWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	if release.ImageSBOMID == 0 {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("no SBOM has been uploaded for the image that release %s runs", release.Name),
+			http.StatusNotFound,
+		))
+
+		return
+	}
+
+	imageSBOM, err := c.Repo().ImageSBOM().ReadImageSBOM(cluster.ProjectID, release.ImageSBOMID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := types.GetReleaseSBOMResponse(*imageSBOM.ToImageSBOMType())
+
+	c.WriteResult(w, r, &res)
+}
+
+// updateReleaseImageSBOM points a release to the dependency inventory of the image that
+// it runs after it is upgraded or rolled back. Releases whose image has no inventory no
+// longer point to an inventory.
+func updateReleaseImageSBOM(
+	config *config.Config,
+	cluster *models.Cluster,
+	rel *models.Release,
+	helmRelease *release.Release,
+) error {
+	image := helm.GetImageValues(helmRelease.Config, helm.GetImageValuesKey(helmRelease.Chart))
+	imageRepo, _ := image["repository"].(string)
+
+	var imageSBOMID uint
+
+	if tag, ok := image["tag"]; ok && tag != nil && imageRepo != "" {
+		imageSBOM, err := config.Repo.ImageSBOM().ReadImageSBOMByImage(cluster.ProjectID, imageRepo, fmt.Sprintf("%v", tag))
+
+		if err == nil {
+			imageSBOMID = imageSBOM.ID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+
+	if imageSBOMID == rel.ImageSBOMID {
+		return nil
+	}
+
+	rel.ImageSBOMID = imageSBOMID
+
+	_, err := config.Repo.Release().UpdateRelease(rel)
+
+	return err
+}
@@ -0,0 +1,123 @@
+package release_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+)
+
+const sbomTestDocument = `{
+  "bomFormat": "CycloneDX",
+  "components": [
+    {"type": "library", "name": "express", "version": "4.17.1", "purl": "pkg:npm/express@4.17.1"}
+  ]
+}`
+
+func TestReleaseSBOMIsSetOnDeploy(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	// the release has no pre-deploy command, so upgrades are deployed directly
+	rel := readSBOMTestRelease(t, config, cluster)
+	rel.PreDeployCommand = ""
+
+	if _, err := config.Repo.Release().UpdateRelease(rel); err != nil {
+		t.Fatal(err)
+	}
+
+	prevRelease := getPreDeployTestHelmRelease(1, "v1")
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/sbom",
+		&types.CreateReleaseSBOMRequest{
+			ImageRepo: "app",
+			Tag:       "v2",
+			Digest:    "sha256:aaaa",
+			Format:    types.SBOMFormatCycloneDXJSON,
+			Document:  sbomTestDocument,
+		},
+	)
+
+	req = withReleaseScopes(t, req, user, cluster, prevRelease)
+	req = apitest.WithURLParams(t, req, map[string]string{string(types.URLParamReleaseName): "web"})
+
+	release.NewCreateReleaseSBOMHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "SBOM should be uploaded")
+
+	imageSBOM, err := config.Repo.ImageSBOM().ReadImageSBOMByImage(1, "app", "v2")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the inventory only becomes the inventory of the release once the image is deployed
+	assert.Zero(t, readSBOMTestRelease(t, config, cluster).ImageSBOMID, "inventory should not be set before the upgrade")
+
+	req, rr = apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/upgrade",
+		&types.UpgradeReleaseRequest{
+			Values: "app:\n  image:\n    repository: app\n    tag: v2\n",
+		},
+	)
+
+	req = withPreDeployTestAgents(t, config, withReleaseScopes(t, req, user, cluster, prevRelease), prevRelease)
+
+	release.NewUpgradeReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "status code should be ok")
+	assert.Equal(t, imageSBOM.ID, readSBOMTestRelease(t, config, cluster).ImageSBOMID, "inventory should be set after the upgrade")
+
+	// the image of the revision that the release is rolled back to has no inventory
+	prevRelease.Info.Status = helmrelease.StatusSuperseded
+	helmRelease := getPreDeployTestHelmRelease(2, "v2")
+
+	req, rr = apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbPost),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/rollback",
+		&types.RollbackReleaseRequest{
+			Revision: 1,
+		},
+	)
+
+	req = withPreDeployTestAgents(t, config, withReleaseScopes(t, req, user, cluster, helmRelease), prevRelease, helmRelease)
+
+	release.NewRollbackReleaseHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode, "status code should be ok")
+	assert.Zero(t, readSBOMTestRelease(t, config, cluster).ImageSBOMID, "inventory should be unset after the rollback")
+}
+
+func readSBOMTestRelease(t *testing.T, config *config.Config, cluster *models.Cluster) *models.Release {
+	rel, err := config.Repo.Release().ReadRelease(cluster.ID, "web", "default")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rel
+}
@@ -0,0 +1,196 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// GetReleaseScalerHandler returns the queue scaler that is stored in the values of a
+// release
+type GetReleaseScalerHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewGetReleaseScalerHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *GetReleaseScalerHandler {
+	return &GetReleaseScalerHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *GetReleaseScalerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+
+	scaler, err := helm.GetReleaseScaler(helmRelease.Config)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if scaler == nil {
+		scaler = &types.ReleaseScaler{
+			Triggers: []types.ScalerTrigger{},
+		}
+	}
+
+	c.WriteResult(w, r, &types.GetReleaseScalerResponse{
+		ReleaseScaler: scaler,
+	})
+}
+
+// UpdateReleaseScalerHandler sets the queue scaler of a release and upgrades the release,
+// which renders the scaler into a KEDA ScaledObject
+type UpdateReleaseScalerHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewUpdateReleaseScalerHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateReleaseScalerHandler {
+	return &UpdateReleaseScalerHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *UpdateReleaseScalerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+
+	request := &types.UpdateReleaseScalerRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	scaler := &request.ReleaseScaler
+
+	if scaler.Enabled {
+		if err := validateReleaseScaler(scaler, helmRelease); err != nil {
+			c.HandleAPIError(w, r, apierrors.WithCode(
+				apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest),
+				types.ErrorCodeValidationFailed,
+			))
+
+			return
+		}
+
+		k8sAgent, err := c.GetAgent(r, cluster, "")
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		installed, err := k8sAgent.IsKEDAInstalled()
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		if !installed {
+			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("KEDA must be installed in the cluster to scale releases on queues"),
+				http.StatusPreconditionFailed,
+			))
+
+			return
+		}
+	}
+
+	values := make(map[string]interface{})
+
+	for key, val := range helmRelease.Config {
+		values[key] = val
+	}
+
+	values[types.ScalerValuesKey] = scaler
+
+	valuesJSON, err := json.Marshal(values)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	upgradeRequest := &types.UpgradeReleaseRequest{
+		Values: string(valuesJSON),
+	}
+
+	cr, _, reqErr := upgradeRelease(c.Config(), c.KubernetesAgentGetter, r, &upgradeOpts{
+		user:        user,
+		cluster:     cluster,
+		helmRelease: helmRelease,
+		request:     upgradeRequest,
+	})
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	// scalers of protected releases are changed through change requests, like other
+	// upgrades of their values
+	if cr != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+
+		return
+	}
+
+	c.WriteResult(w, r, &types.GetReleaseScalerResponse{
+		ReleaseScaler: scaler,
+	})
+}
+
+// validateReleaseScaler returns an error if a scaler is invalid, or if the release has
+// no deployment for the scaler to scale, such as for jobs
+func validateReleaseScaler(scaler *types.ReleaseScaler, helmRelease *release.Release) error {
+	if len(scaler.Triggers) == 0 {
+		return fmt.Errorf("at least one trigger is required")
+	}
+
+	hasDeployment := false
+
+	for _, controller := range getReleaseControllers(helmRelease) {
+		if controller.Kind == "Deployment" {
+			hasDeployment = true
+			break
+		}
+	}
+
+	if !hasDeployment {
+		return fmt.Errorf("release %s has no deployment to scale", helmRelease.Name)
+	}
+
+	if scaler.MaxReplicas != 0 && scaler.MaxReplicas < scaler.MinReplicas {
+		return fmt.Errorf("max_replicas must be greater than or equal to min_replicas")
+	}
+
+	for _, trigger := range scaler.Triggers {
+		if _, _, err := helm.GetKEDATrigger(trigger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
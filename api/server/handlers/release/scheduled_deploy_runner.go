@@ -0,0 +1,312 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/integrations/slack"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+// ScheduledDeployRunner runs the scheduled deploys whose run time has passed. Each deploy
+// is claimed before it runs, so that a deploy runs once when several server replicas run
+// scheduled deploys.
+type ScheduledDeployRunner struct {
+	config      *config.Config
+	agentGetter authz.KubernetesAgentGetter
+}
+
+func NewScheduledDeployRunner(config *config.Config) *ScheduledDeployRunner {
+	return &ScheduledDeployRunner{
+		config:      config,
+		agentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// Run runs the due scheduled deploys at the given interval until the context is cancelled
+func (s *ScheduledDeployRunner) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scheduledDeployClaimTimeout is how long a scheduled deploy may run after it was claimed.
+// It is longer than an upgrade with a pre-deploy step can take, so deploys that run longer
+// were claimed by a server replica that stopped.
+const scheduledDeployClaimTimeout = 30 * time.Minute
+
+// RunDue runs the pending scheduled deploys whose run time has passed, from earliest. The
+// result of each deploy is stored on the scheduled deploy.
+func (s *ScheduledDeployRunner) RunDue() {
+	s.failStale()
+
+	sds, err := s.config.Repo.ScheduledDeploy().ListDueScheduledDeploys(time.Now())
+
+	if err != nil {
+		s.config.Logger.Error().Err(err).Msg("error listing due scheduled deploys")
+		return
+	}
+
+	for _, sd := range sds {
+		claimed, err := s.config.Repo.ScheduledDeploy().ClaimScheduledDeploy(sd)
+
+		if err != nil {
+			s.config.Logger.Error().Err(err).Uint("scheduled_deploy_id", sd.ID).Msg("error claiming scheduled deploy")
+			continue
+		} else if !claimed {
+			continue
+		}
+
+		// the deploy is read again after it is claimed, in case it was modified after
+		// it was listed
+		if claimedSD, err := s.config.Repo.ScheduledDeploy().ReadScheduledDeploy(sd.ClusterID, sd.ID); err == nil {
+			sd = claimedSD
+		}
+
+		runErr := s.run(sd)
+
+		now := time.Now()
+		sd.ExecutedAt = &now
+		sd.Status = types.ScheduledDeploySucceeded
+
+		if runErr != nil {
+			sd.Status = types.ScheduledDeployFailed
+			sd.Error = runErr.Error()
+		}
+
+		if _, err := s.config.Repo.ScheduledDeploy().UpdateScheduledDeploy(sd); err != nil {
+			s.config.Logger.Error().Err(err).Uint("scheduled_deploy_id", sd.ID).Msg("error updating scheduled deploy")
+		}
+	}
+}
+
+// failStale fails the scheduled deploys whose claim timed out. They are not run again,
+// since the replica that claimed them may have stopped after the release was upgraded.
+func (s *ScheduledDeployRunner) failStale() {
+	sds, err := s.config.Repo.ScheduledDeploy().ListStaleScheduledDeploys(time.Now().Add(-scheduledDeployClaimTimeout))
+
+	if err != nil {
+		s.config.Logger.Error().Err(err).Msg("error listing stale scheduled deploys")
+		return
+	}
+
+	for _, sd := range sds {
+		now := time.Now()
+		sd.ExecutedAt = &now
+		sd.Status = types.ScheduledDeployFailed
+		sd.Error = "the server stopped while the deploy was running, so the result of the deploy is unknown"
+
+		if _, err := s.config.Repo.ScheduledDeploy().UpdateScheduledDeploy(sd); err != nil {
+			s.config.Logger.Error().Err(err).Uint("scheduled_deploy_id", sd.ID).Msg("error updating scheduled deploy")
+		}
+	}
+}
+
+// run upgrades the release of a scheduled deploy as the user that scheduled it. Errors
+// before the upgrade starts are notified here, and the result of the upgrade is notified
+// like other upgrades.
+func (s *ScheduledDeployRunner) run(sd *models.ScheduledDeploy) error {
+	cluster, err := s.config.Repo.Cluster().ReadCluster(sd.ProjectID, sd.ClusterID)
+
+	if err != nil {
+		return fmt.Errorf("could not read cluster: %w", err)
+	}
+
+	reqErr := s.upgrade(cluster, sd)
+
+	if reqErr == nil {
+		return nil
+	}
+
+	if _, ok := reqErr.(*upgradeStartedError); !ok {
+		s.notifyFailed(cluster, sd, reqErr)
+	}
+
+	return reqErr
+}
+
+// upgradeStartedError wraps the errors of upgrades that were started, which are already
+// notified by the upgrade
+type upgradeStartedError struct {
+	apierrors.RequestError
+}
+
+func (s *ScheduledDeployRunner) upgrade(cluster *models.Cluster, sd *models.ScheduledDeploy) apierrors.RequestError {
+	user, err := s.config.Repo.User().ReadUser(sd.ScheduledByUserID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(fmt.Errorf("could not read user that scheduled the deploy: %w", err))
+	}
+
+	// the deploy runs as the user that scheduled it, who may have been removed from the
+	// project or lost the permission to deploy since
+	role, err := s.config.Repo.Project().ReadProjectRole(cluster.ProjectID, user.ID)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && role.Kind == types.RoleViewer) {
+		return apierrors.NewErrForbidden(
+			fmt.Errorf("user %d that scheduled the deploy can no longer deploy to the project", user.ID),
+		)
+	} else if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	// the upgrade runs with a request that has the scopes of the release, which the
+	// agent getter and the deploy events read
+	r, err := newReleaseRequest(user, cluster, sd.Namespace, types.DeploySourceScheduled)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if reqErr := checkScheduledDeployAllowed(s.config, cluster, sd.Namespace, sd.Name); reqErr != nil {
+		return reqErr
+	}
+
+	helmAgent, err := s.agentGetter.GetHelmAgent(r, cluster, sd.Namespace)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	helmRelease, err := helmAgent.GetRelease(sd.Name, 0, true)
+
+	if err != nil {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s not found: %s", sd.Name, err.Error()),
+			http.StatusNotFound,
+		)
+	}
+
+	request := &types.UpgradeReleaseRequest{
+		Values:       sd.Values,
+		ChartVersion: sd.ChartVersion,
+		Patch:        sd.Patch,
+		PatchType:    sd.PatchType,
+		Message:      sd.Message,
+	}
+
+	// image tags are applied as a patch of the current values, like other patches. Custom
+	// charts may set the image under another key than "image".
+	if sd.ImageTag != "" {
+		patch, err := json.Marshal(getImageTagPatch(helm.GetImageValuesKey(helmRelease.Chart), sd.ImageTag))
+
+		if err != nil {
+			return apierrors.NewErrInternal(err)
+		}
+
+		request.Patch = string(patch)
+		request.PatchType = types.ValuesPatchTypeMerge
+	}
+
+	// upgrades that only change the chart version keep the current values
+	if request.Values == "" && request.Patch == "" {
+		values, err := json.Marshal(helmRelease.Config)
+
+		if err != nil {
+			return apierrors.NewErrInternal(err)
+		}
+
+		request.Values = string(values)
+	}
+
+	if request.Patch != "" {
+		values, err := getPatchedValues(helmRelease, request)
+
+		if err != nil {
+			return apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest)
+		}
+
+		request.Values = values
+	}
+
+	// scheduled deploys of protected releases are stored as change requests, which are
+	// notified when they are created
+	_, _, reqErr := upgradeRelease(s.config, s.agentGetter, r, &upgradeOpts{
+		user:        user,
+		cluster:     cluster,
+		helmRelease: helmRelease,
+		request:     request,
+	})
+
+	if reqErr != nil {
+		return &upgradeStartedError{reqErr}
+	}
+
+	return nil
+}
+
+// getImageTagPatch returns a merge patch that sets the image tag under a dot-separated
+// image values key
+func getImageTagPatch(imageValuesKey, tag string) map[string]interface{} {
+	res := map[string]interface{}{
+		"tag": tag,
+	}
+
+	keys := strings.Split(imageValuesKey, ".")
+
+	for i := len(keys) - 1; i >= 0; i-- {
+		res = map[string]interface{}{
+			keys[i]: res,
+		}
+	}
+
+	return res
+}
+
+func (s *ScheduledDeployRunner) notifyFailed(cluster *models.Cluster, sd *models.ScheduledDeploy, err error) {
+	if cluster.NotificationsDisabled {
+		return
+	}
+
+	slackInts, _ := s.config.Repo.SlackIntegration().ListSlackIntegrationsByProjectID(cluster.ProjectID)
+
+	var notifConf *types.NotificationConfig
+
+	if rel, err := s.config.Repo.Release().ReadRelease(cluster.ID, sd.Name, sd.Namespace); err == nil && rel.NotificationConfig != 0 {
+		if conf, err := s.config.Repo.NotificationConfig().ReadNotificationConfig(rel.NotificationConfig); err == nil {
+			notifConf = conf.ToNotificationConfigType()
+		}
+	}
+
+	notifier := slack.NewSlackNotifier(notifConf, slackInts...)
+
+	notifier.Notify(&slack.NotifyOpts{
+		ProjectID:   cluster.ProjectID,
+		ClusterID:   cluster.ID,
+		ClusterName: cluster.Name,
+		Status:      slack.StatusScheduledDeployFailed,
+		Info:        err.Error(),
+		Name:        sd.Name,
+		Namespace:   sd.Namespace,
+		Message:     sd.Message,
+		URL: fmt.Sprintf(
+			"%s/applications/%s/%s/%s?project_id=%d",
+			s.config.ServerConf.ServerURL,
+			url.PathEscape(cluster.Name),
+			sd.Namespace,
+			sd.Name,
+			cluster.ProjectID,
+		),
+	})
+}
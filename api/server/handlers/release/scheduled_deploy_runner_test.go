@@ -0,0 +1,82 @@
+package release_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDueFailsStaleScheduledDeploys(t *testing.T) {
+	config := apitest.LoadConfig(t)
+
+	claimedAt := time.Now().Add(-time.Hour)
+
+	sd, err := config.Repo.ScheduledDeploy().CreateScheduledDeploy(&models.ScheduledDeploy{
+		ProjectID: 1,
+		ClusterID: 1,
+		Namespace: "default",
+		Name:      "web",
+		Status:    types.ScheduledDeployRunning,
+		RunAt:     claimedAt,
+		ClaimedAt: &claimedAt,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release.NewScheduledDeployRunner(config).RunDue()
+
+	sd, err = config.Repo.ScheduledDeploy().ReadScheduledDeploy(1, sd.ID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.ScheduledDeployFailed, sd.Status, "stale scheduled deploy should be failed")
+	assert.NotNil(t, sd.ExecutedAt, "stale scheduled deploy should be executed")
+}
+
+func TestRunDueScheduledDeployOfRemovedUser(t *testing.T) {
+	config := apitest.LoadConfig(t)
+	user := apitest.CreateTestUser(t, config, true)
+
+	// the user that scheduled the deploy has no role in the project
+	cluster, err := config.Repo.Cluster().CreateCluster(&models.Cluster{ProjectID: 1, NotificationsDisabled: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sd, err := config.Repo.ScheduledDeploy().CreateScheduledDeploy(&models.ScheduledDeploy{
+		ProjectID:         1,
+		ClusterID:         cluster.ID,
+		Namespace:         "default",
+		Name:              "web",
+		Status:            types.ScheduledDeployPending,
+		RunAt:             time.Now().Add(-time.Minute),
+		ImageTag:          "v2",
+		ScheduledByUserID: user.ID,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release.NewScheduledDeployRunner(config).RunDue()
+
+	sd, err = config.Repo.ScheduledDeploy().ReadScheduledDeploy(cluster.ID, sd.ID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, types.ScheduledDeployFailed, sd.Status, "scheduled deploy should be failed")
+	assert.Contains(t, sd.Error, "can no longer deploy to the project", "scheduled deploy should not run as a removed user")
+	assert.NotNil(t, sd.ClaimedAt, "scheduled deploy should be claimed before it runs")
+}
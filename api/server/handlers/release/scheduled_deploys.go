@@ -0,0 +1,351 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+type CreateScheduledDeployHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewCreateScheduledDeployHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateScheduledDeployHandler {
+	return &CreateScheduledDeployHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP stores an upgrade of a release, which is run by the scheduled deploy runner
+// once its run time has passed
+func (c *CreateScheduledDeployHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
+
+	request := &types.CreateScheduledDeployRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if err := validateScheduledDeploy(request); err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(
+			apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest),
+			types.ErrorCodeValidationFailed,
+		))
+
+		return
+	}
+
+	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if _, err := helmAgent.GetRelease(name, 0, true); err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s not found: %s", name, err.Error()),
+			http.StatusNotFound,
+		), types.ErrorCodeReleaseNotFound))
+
+		return
+	}
+
+	if reqErr := checkScheduledDeployAllowed(c.Config(), cluster, namespace, name); reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	sd := &models.ScheduledDeploy{
+		ProjectID:         cluster.ProjectID,
+		ClusterID:         cluster.ID,
+		Namespace:         namespace,
+		Name:              name,
+		Status:            types.ScheduledDeployPending,
+		ScheduledByUserID: user.ID,
+	}
+
+	setScheduledDeployRequest(sd, request)
+
+	sd, err = c.Repo().ScheduledDeploy().CreateScheduledDeploy(sd)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, sd.ToScheduledDeployType())
+}
+
+type ListScheduledDeploysHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewListScheduledDeploysHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ListScheduledDeploysHandler {
+	return &ListScheduledDeploysHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *ListScheduledDeploysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
+
+	request := &types.ListScheduledDeploysRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	statuses := make([]types.ScheduledDeployStatus, 0)
+
+	if request.Status != "" {
+		statuses = append(statuses, request.Status)
+	}
+
+	sds, err := c.Repo().ScheduledDeploy().ListScheduledDeploys(cluster.ID, namespace, name, statuses...)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListScheduledDeploysResponse, 0)
+
+	for _, sd := range sds {
+		res = append(res, sd.ToScheduledDeployType())
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type UpdateScheduledDeployHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateScheduledDeployHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateScheduledDeployHandler {
+	return &UpdateScheduledDeployHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP replaces the upgrade and run time of a pending scheduled deploy
+func (c *UpdateScheduledDeployHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.UpdateScheduledDeployRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if err := validateScheduledDeploy((*types.CreateScheduledDeployRequest)(request)); err != nil {
+		c.HandleAPIError(w, r, apierrors.WithCode(
+			apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest),
+			types.ErrorCodeValidationFailed,
+		))
+
+		return
+	}
+
+	sd, ok := readPendingScheduledDeploy(c.PorterHandlerReadWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	if reqErr := checkScheduledDeployAllowed(c.Config(), cluster, sd.Namespace, sd.Name); reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	setScheduledDeployRequest(sd, (*types.CreateScheduledDeployRequest)(request))
+
+	// the user that modifies a scheduled deploy is the user that the upgrade runs as
+	sd.ScheduledByUserID = user.ID
+
+	sd, err := c.Repo().ScheduledDeploy().UpdateScheduledDeploy(sd)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, sd.ToScheduledDeployType())
+}
+
+type CancelScheduledDeployHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewCancelScheduledDeployHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *CancelScheduledDeployHandler {
+	return &CancelScheduledDeployHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *CancelScheduledDeployHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sd, ok := readPendingScheduledDeploy(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	sd.Status = types.ScheduledDeployCanceled
+
+	sd, err := c.Repo().ScheduledDeploy().UpdateScheduledDeploy(sd)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, sd.ToScheduledDeployType())
+}
+
+// validateScheduledDeploy returns an error if a scheduled deploy does not run in the
+// future, or does not set exactly one way of changing the values of the release
+func validateScheduledDeploy(request *types.CreateScheduledDeployRequest) error {
+	if !request.RunAt.After(time.Now()) {
+		return fmt.Errorf("run_at must be in the future")
+	}
+
+	numChanges := 0
+
+	for _, change := range []string{request.Values, request.Patch, request.ImageTag} {
+		if change != "" {
+			numChanges++
+		}
+	}
+
+	if numChanges > 1 {
+		return fmt.Errorf("only one of values, patch and image_tag can be set")
+	}
+
+	if numChanges == 0 && request.ChartVersion == "" {
+		return fmt.Errorf("one of values, patch, image_tag and version must be set")
+	}
+
+	if request.Values != "" {
+		if _, err := chartutil.ReadValues([]byte(request.Values)); err != nil {
+			return fmt.Errorf("values could not be parsed: %s", err.Error())
+		}
+	}
+
+	if request.Patch != "" {
+		if _, err := chartutil.ReadValues([]byte(request.Patch)); err != nil {
+			return fmt.Errorf("patch could not be parsed: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// checkScheduledDeployAllowed returns an error if a release is protected, since upgrades
+// of protected releases must be approved as change requests when they are requested
+func checkScheduledDeployAllowed(config *config.Config, cluster *models.Cluster, namespace, name string) apierrors.RequestError {
+	protected, err := isReleaseProtected(config.Repo, cluster, name, namespace)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if protected {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release %s is protected, and deploys of protected releases cannot be scheduled", name),
+			http.StatusConflict,
+		)
+	}
+
+	return nil
+}
+
+func setScheduledDeployRequest(sd *models.ScheduledDeploy, request *types.CreateScheduledDeployRequest) {
+	sd.RunAt = request.RunAt.UTC()
+	sd.Values = request.Values
+	sd.ChartVersion = request.ChartVersion
+	sd.Patch = request.Patch
+	sd.PatchType = request.PatchType
+	sd.ImageTag = request.ImageTag
+	sd.Message = request.Message
+}
+
+// readPendingScheduledDeploy reads the scheduled deploy in the URL, and writes an error if
+// it is not pending
+func readPendingScheduledDeploy(
+	c handlers.PorterHandler,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*models.ScheduledDeploy, bool) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
+
+	sdID, reqErr := requestutils.GetURLParamUint(r, types.URLParamScheduledDeployID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return nil, false
+	}
+
+	sd, err := c.Repo().ScheduledDeploy().ReadScheduledDeploy(cluster.ID, sdID)
+
+	if (err != nil && errors.Is(err, gorm.ErrRecordNotFound)) || (err == nil && (sd.Name != name || sd.Namespace != namespace)) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("scheduled deploy with id %d not found", sdID),
+			http.StatusNotFound,
+		))
+
+		return nil, false
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	if sd.Status != types.ScheduledDeployPending {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("scheduled deploy is %s", sd.Status),
+			http.StatusConflict,
+		))
+
+		return nil, false
+	}
+
+	return sd, true
+}
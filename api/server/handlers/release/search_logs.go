@@ -0,0 +1,133 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/loki"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+type SearchLogsHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewSearchLogsHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *SearchLogsHandler {
+	return &SearchLogsHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *SearchLogsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &types.SearchReleaseLogsRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	// the labels are sent by the client, so they are validated before loki is queried
+	if err := loki.ValidateLabels(request.Labels); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	lokiSvc, found, err := loki.GetLokiService(agent.Clientset)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	} else if !found {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("log search is not enabled for this cluster: loki is not installed"),
+			http.StatusNotFound,
+		))
+
+		return
+	}
+
+	now := uint(time.Now().Unix())
+
+	end := request.EndRange
+
+	if end == 0 || end > now {
+		end = now
+	}
+
+	start := request.StartRange
+
+	if start == 0 {
+		start = end - uint(loki.DefaultLookback.Seconds())
+	}
+
+	// logs older than the retention window have been deleted by loki, so don't search
+	// past the window
+	if retention := c.Config().ServerConf.LogSearchRetention; retention > 0 {
+		if minStart := now - uint(retention.Seconds()); start < minStart {
+			start = minStart
+		}
+	}
+
+	if start >= end {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("start of the search range must be before the end, and within the log retention window of %s", c.Config().ServerConf.LogSearchRetention),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	lines, err := loki.SearchLogs(agent.Clientset, lokiSvc, &loki.SearchOpts{
+		Namespace: helmRelease.Namespace,
+		Release:   helmRelease.Name,
+		Query:     request.Query,
+		Labels:    request.Labels,
+		Start:     start,
+		End:       end,
+		Limit:     request.Limit,
+		Direction: request.Direction,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := &types.SearchReleaseLogsResponse{
+		Start: start,
+		Lines: make([]*types.ReleaseLogLine, 0, len(lines)),
+	}
+
+	for _, line := range lines {
+		res.Lines = append(res.Lines, &types.ReleaseLogLine{
+			Timestamp: line.Timestamp,
+			Line:      line.Line,
+			Labels:    line.Labels,
+		})
+	}
+
+	c.WriteResult(w, r, res)
+}
@@ -0,0 +1,35 @@
+package release_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apitest"
+	"github.com/porter-dev/porter/api/types"
+)
+
+func TestSearchLogsWithInvalidLabels(t *testing.T) {
+	config, user, cluster := createPreDeployTestRelease(t)
+
+	req, rr := apitest.GetRequestAndRecorder(
+		t,
+		string(types.HTTPVerbGet),
+		"/api/projects/1/clusters/1/namespaces/default/releases/web/0/logs/search?query=error&labels=container",
+		nil,
+	)
+
+	req = withReleaseScopes(t, req, user, cluster, getPreDeployTestHelmRelease(1, "v1"))
+
+	release.NewSearchLogsHandler(
+		config,
+		shared.NewDefaultRequestDecoderValidator(config),
+		shared.NewDefaultResultWriter(config),
+	).ServeHTTP(rr, req)
+
+	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
+		Error:     `invalid label filter "container": must be of the form key=value`,
+		ErrorCode: types.ErrorCodeBadRequest,
+	})
+}
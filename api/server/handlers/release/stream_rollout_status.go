@@ -0,0 +1,129 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// rolloutPollInterval is the interval at which the rollout status is checked
+const rolloutPollInterval = 2 * time.Second
+
+type StreamRolloutStatusHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewStreamRolloutStatusHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *StreamRolloutStatusHandler {
+	return &StreamRolloutStatusHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *StreamRolloutStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	safeRW := r.Context().Value(types.RequestCtxWebsocketKey).(*websocket.WebsocketSafeReadWriter)
+
+	request := &types.StreamRolloutStatusRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	timeout := time.Duration(request.Timeout) * time.Second
+
+	if timeout == 0 {
+		timeout = types.DefaultRolloutTimeout * time.Second
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	yamlArr := grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest))
+	controllers := grapher.ParseControllers(yamlArr)
+
+	for i := range controllers {
+		controllers[i].Namespace = helmRelease.Namespace
+	}
+
+	closed := make(chan struct{})
+
+	go func() {
+		// listens for websocket closing handshake
+		for {
+			if _, _, err := safeRW.ReadMessage(); err != nil {
+				close(closed)
+				return
+			}
+		}
+	}()
+
+	// only report events since the release was deployed
+	since := time.Now().Add(-rolloutPollInterval)
+
+	if helmRelease.Info != nil && !helmRelease.Info.LastDeployed.IsZero() {
+		since = helmRelease.Info.LastDeployed.Time
+	}
+
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(rolloutPollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := agent.GetRolloutStatus(controllers, since)
+
+		if err != nil {
+			safeRW.WriteJSON(&types.RolloutStatus{
+				Phase: types.RolloutFailed,
+				Error: err.Error(),
+			})
+
+			return
+		}
+
+		for _, event := range status.Events {
+			if event.Timestamp.After(since) {
+				since = event.Timestamp
+			}
+		}
+
+		if err := safeRW.WriteJSON(status); err != nil || status.Phase != types.RolloutProgressing {
+			return
+		}
+
+		select {
+		case <-closed:
+			return
+		case <-deadline:
+			status.Phase = types.RolloutFailed
+			status.Events = []*types.RolloutEvent{}
+			status.Error = fmt.Sprintf("rollout did not complete within %s", timeout)
+
+			safeRW.WriteJSON(status)
+
+			return
+		case <-ticker.C:
+		}
+	}
+}
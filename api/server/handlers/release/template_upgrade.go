@@ -0,0 +1,239 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+	"sigs.k8s.io/yaml"
+)
+
+type GetTemplateUpgradeHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewGetTemplateUpgradeHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *GetTemplateUpgradeHandler {
+	return &GetTemplateUpgradeHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP compares the chart version of a release to a newer version of its chart, and
+// returns the changes of the structure of the values and the migrated values of the release
+func (c *GetTemplateUpgradeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+
+	request := &types.GetTemplateUpgradeRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	upgrade, reqErr := getTemplateUpgrade(c.Config(), cluster, helmRelease, request.Version)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	c.WriteResult(w, r, upgrade)
+}
+
+type ApplyTemplateUpgradeHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewApplyTemplateUpgradeHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ApplyTemplateUpgradeHandler {
+	return &ApplyTemplateUpgradeHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// ServeHTTP upgrades a release to a newer version of its chart with the migrated values
+// of the release, or with the values of the request if they are set
+func (c *ApplyTemplateUpgradeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+
+	request := &types.ApplyTemplateUpgradeRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	if request.Revision != 0 && request.Revision != helmRelease.Version {
+		conflict, err := getRevisionConflict(c.KubernetesAgentGetter, r, cluster, helmRelease, request.Revision)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusConflict)
+		c.WriteResult(w, r, conflict)
+
+		return
+	}
+
+	upgrade, reqErr := getTemplateUpgrade(c.Config(), cluster, helmRelease, request.Version)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	if upgrade.TargetVersion == upgrade.CurrentVersion {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release is already on version %s of chart %s", upgrade.CurrentVersion, upgrade.ChartName),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	upgradeRequest := &types.UpgradeReleaseRequest{
+		Values:       upgrade.MigratedValues,
+		ChartVersion: upgrade.TargetVersion,
+		Message:      request.Message,
+	}
+
+	if request.Values != "" {
+		upgradeRequest.Values = request.Values
+	}
+
+	cr, _, reqErr := upgradeRelease(c.Config(), c.KubernetesAgentGetter, r, &upgradeOpts{
+		user:        user,
+		cluster:     cluster,
+		helmRelease: helmRelease,
+		request:     upgradeRequest,
+	})
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	// like other upgrades, upgrades of protected releases are stored as change requests
+	if cr != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+
+		return
+	}
+
+	c.WriteResult(w, r, upgrade)
+}
+
+// getTemplateUpgrade compares the chart of a release to a version of its chart, or to
+// the latest version of the chart if the version is not set
+func getTemplateUpgrade(
+	config *config.Config,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	version string,
+) (*types.TemplateUpgrade, apierrors.RequestError) {
+	chartName := helmRelease.Chart.Metadata.Name
+
+	chartRepoURL, found := getChartRepoURL(config, cluster.ProjectID, chartName)
+
+	if helm.IsCustomChart(helmRelease.Chart) {
+		chartRepoURL, found = types.CustomChartRepoURL, true
+	}
+
+	if !found {
+		return nil, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("chart %s not found in the chart repos of the project", chartName),
+			http.StatusBadRequest,
+		), types.ErrorCodeChartNotFound)
+	}
+
+	res := &types.TemplateUpgrade{
+		ChartName:      chartName,
+		CurrentVersion: helmRelease.Chart.Metadata.Version,
+		TargetVersion:  version,
+		Changes:        make([]*types.ValuesSchemaChange, 0),
+		ValuesDiff:     make([]string, 0),
+	}
+
+	if chartRepoURL == types.CustomChartRepoURL {
+		res.LatestVersion, _ = getLatestCustomChartVersion(config, cluster.ProjectID, chartName)
+	} else if repoIndex, err := loader.LoadRepoIndexPublic(chartRepoURL); err == nil {
+		if porterChart := loader.FindPorterChartInIndexList(repoIndex, chartName); porterChart != nil && len(porterChart.Versions) > 0 {
+			res.LatestVersion = porterChart.Versions[0]
+		}
+	}
+
+	if res.TargetVersion == "" {
+		res.TargetVersion = res.LatestVersion
+	}
+
+	if res.TargetVersion == "" {
+		return nil, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("latest version of chart %s not found", chartName),
+			http.StatusBadRequest,
+		), types.ErrorCodeChartNotFound)
+	}
+
+	nextDefaults := helmRelease.Chart.Values
+
+	if res.TargetVersion != res.CurrentVersion {
+		nextChart, err := loadChart(config, cluster, chartRepoURL, chartName, res.TargetVersion)
+
+		if err != nil {
+			return nil, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("version %s of chart %s not found", res.TargetVersion, chartName),
+				http.StatusBadRequest,
+			), types.ErrorCodeChartNotFound)
+		}
+
+		nextDefaults = nextChart.Values
+	}
+
+	changes, migrated := helm.MigrateValues(helmRelease.Chart.Values, nextDefaults, helmRelease.Config)
+
+	res.Changes = changes
+
+	for _, change := range changes {
+		if change.Breaking {
+			res.HasBreakingChanges = true
+		}
+	}
+
+	migratedValues, err := yaml.Marshal(migrated)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	res.MigratedValues = string(migratedValues)
+
+	sensitivePaths := helm.GetSensitiveValuePaths(helmRelease.Chart)
+
+	res.ValuesDiff = append(res.ValuesDiff, helm.DiffValues(
+		helm.RedactSensitiveValues(helmRelease.Config, sensitivePaths),
+		helm.RedactSensitiveValues(migrated, sensitivePaths),
+	)...)
+
+	return res, nil
+}
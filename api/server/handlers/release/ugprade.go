@@ -1,6 +1,8 @@
 package release
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,10 +15,12 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/addons"
 	"github.com/porter-dev/porter/internal/helm"
-	"github.com/porter-dev/porter/internal/helm/loader"
 	"github.com/porter-dev/porter/internal/integrations/slack"
 	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/predeploy"
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/release"
 )
 
@@ -45,91 +49,344 @@ func (c *UpgradeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
 
-	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+	request := &types.UpgradeReleaseRequest{}
 
-	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
 		return
 	}
 
-	request := &types.UpgradeReleaseRequest{}
+	// upgrades that are based on an earlier revision are rejected, so that edits do not
+	// overwrite upgrades that were made after the edit started
+	if request.Revision != 0 && request.Revision != helmRelease.Version {
+		conflict, err := getRevisionConflict(c.KubernetesAgentGetter, r, cluster, helmRelease, request.Revision)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusConflict)
+		c.WriteResult(w, r, conflict)
 
-	if ok := c.DecodeAndValidate(w, r, request); !ok {
 		return
 	}
 
-	registries, err := c.Repo().Registry().ListRegistriesByProjectID(cluster.ProjectID)
+	// patches are applied to the current values of the release, and the upgrade then
+	// proceeds with the patched values
+	if request.Patch != "" {
+		values, err := getPatchedValues(helmRelease, request)
 
-	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.WithCode(
+				apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest),
+				types.ErrorCodeValidationFailed,
+			))
+
+			return
+		}
+
+		request.Values = values
+	}
+
+	cr, run, reqErr := upgradeRelease(c.Config(), c.KubernetesAgentGetter, r, &upgradeOpts{
+		user:        user,
+		cluster:     cluster,
+		helmRelease: helmRelease,
+		request:     request,
+	})
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
 		return
 	}
 
+	// upgrades of protected releases are stored as change requests, and are applied once
+	// they are approved by another user
+	if cr != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
+
+		return
+	}
+
+	// upgrades that wait for the pre-deploy command of the release are accepted, and
+	// clients read the run to wait for the upgrade
+	if run != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, &types.UpgradeReleaseResponse{
+			PreDeployRun: run.ToPreDeployRunType(),
+		})
+	}
+}
+
+// getRevisionConflict returns the conflict of an upgrade based on an earlier revision of
+// a release, with the changes of the values since that revision
+func getRevisionConflict(
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	revision int,
+) (*types.UpgradeReleaseConflict, error) {
+	res := &types.UpgradeReleaseConflict{
+		ExternalError: types.ExternalError{
+			Error:     fmt.Sprintf("release was upgraded to revision %d since revision %d", helmRelease.Version, revision),
+			ErrorCode: types.ErrorCodeRevisionConflict,
+		},
+		Revision:        revision,
+		CurrentRevision: helmRelease.Version,
+		ValuesDiff:      make([]string, 0),
+	}
+
+	helmAgent, err := agentGetter.GetHelmAgent(r, cluster, helmRelease.Namespace)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the revision may not exist, such as when it was removed from the history of the
+	// release, in which case the conflict is returned without a diff
+	prevRelease, err := helmAgent.GetRelease(helmRelease.Name, revision, false)
+
+	if err != nil {
+		return res, nil
+	}
+
+	sensitivePaths := helm.GetSensitiveValuePaths(helmRelease.Chart)
+
+	res.ValuesDiff = append(res.ValuesDiff, helm.DiffValues(
+		helm.RedactSensitiveValues(prevRelease.Config, sensitivePaths),
+		helm.RedactSensitiveValues(helmRelease.Config, sensitivePaths),
+	)...)
+
+	return res, nil
+}
+
+// getPatchedValues applies the values patch of an upgrade request to the current values
+// of a release, and returns the patched values
+func getPatchedValues(helmRelease *release.Release, request *types.UpgradeReleaseRequest) (string, error) {
+	if request.Values != "" {
+		return "", fmt.Errorf("only one of values and patch can be set")
+	}
+
+	patch, err := chartutil.ReadValues([]byte(request.Patch))
+
+	if err != nil {
+		return "", fmt.Errorf("patch could not be parsed: %s", err.Error())
+	}
+
+	patchType := request.PatchType
+
+	if patchType == "" {
+		patchType = types.ValuesPatchTypeMerge
+	}
+
+	values, err := json.Marshal(helm.PatchValues(helmRelease.Config, patch, patchType))
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(values), nil
+}
+
+// applyUpgrade upgrades a release to the values and chart version of the request, and
+// then sends notifications, starts the health gate and updates the GitHub Actions env.
+// It does not check whether the release is protected, so it is only called through
+// upgradeRelease and when approved change requests are applied.
+func applyUpgrade(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	opts *upgradeOpts,
+) (*models.PreDeployRun, apierrors.RequestError) {
+	cluster, helmRelease, request := opts.cluster, opts.helmRelease, opts.request
+
+	helmAgent, err := agentGetter.GetHelmAgent(r, cluster, helmRelease.Namespace)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	registries, err := config.Repo.Registry().ListRegistriesByProjectID(cluster.ProjectID)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
 	conf := &helm.UpgradeReleaseConfig{
 		Name:       helmRelease.Name,
 		Cluster:    cluster,
-		Repo:       c.Repo(),
+		Repo:       config.Repo,
 		Registries: registries,
 	}
 
-	// if the chart version is set, load a chart from the repo
-	if request.ChartVersion != "" {
-		cache := c.Config().URLCache
-		chartRepoURL, foundFirst := cache.GetURL(helmRelease.Chart.Metadata.Name)
-
-		if !foundFirst {
-			cache.Update()
+	chartRepoURL, found := types.CustomChartRepoURL, true
 
-			var found bool
-
-			chartRepoURL, found = cache.GetURL(helmRelease.Chart.Metadata.Name)
+	if !helm.IsCustomChart(helmRelease.Chart) {
+		chartRepoURL, found = getChartRepoURL(config, cluster.ProjectID, helmRelease.Chart.Metadata.Name)
+	}
 
-			if !found {
-				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
-					fmt.Errorf("chart not found"),
-					http.StatusBadRequest,
-				))
+	if reqErr := checkUpgradeChartAllowed(config, opts); reqErr != nil {
+		return nil, reqErr
+	}
 
-				return
-			}
+	// if the chart version is set, load a chart from the repo
+	if opts.chart != nil {
+		conf.Chart = opts.chart
+	} else if request.ChartVersion != "" {
+		if !found {
+			return nil, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("chart not found"),
+				http.StatusBadRequest,
+			), types.ErrorCodeChartNotFound)
 		}
 
-		chart, err := loader.LoadChartPublic(
+		chart, err := loadChart(
+			config,
+			cluster,
 			chartRepoURL,
 			helmRelease.Chart.Metadata.Name,
 			request.ChartVersion,
 		)
 
 		if err != nil {
-			c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			return nil, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
 				fmt.Errorf("chart not found"),
 				http.StatusBadRequest,
-			))
-
-			return
+			), types.ErrorCodeChartNotFound)
 		}
 
 		conf.Chart = chart
 	}
 
-	newHelmRelease, upgradeErr := helmAgent.UpgradeRelease(conf, request.Values, c.Config().DOConf)
+	chartValues := helmRelease.Chart.Values
+
+	if conf.Chart != nil {
+		chartValues = conf.Chart.Values
+	}
+
+	values, err := chartutil.ReadValues([]byte(request.Values))
+
+	if err != nil {
+		return nil, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("could not parse values: %s", err.Error()),
+			http.StatusBadRequest,
+		)
+	}
+
+	if config.ImageMirror != nil {
+		mirroredValues, reqErr := mirrorImages(config, chartValues, values)
+
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		valuesJSON, err := json.Marshal(mirroredValues)
+
+		if err != nil {
+			return nil, apierrors.NewErrInternal(err)
+		}
+
+		request.Values = string(valuesJSON)
+		values = mirroredValues
+	}
+
+	if reqErr := checkImagesAllowed(config, cluster.ProjectID, chartValues, values); reqErr != nil {
+		return nil, reqErr
+	}
+
+	values, reqErr := checkImagesSigned(config, cluster.ProjectID, chartValues, values)
+
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	// verified images are deployed by the digests that are pinned in the values
+	if len(values) > 0 {
+		valuesJSON, err := json.Marshal(values)
+
+		if err != nil {
+			return nil, apierrors.NewErrInternal(err)
+		}
+
+		request.Values = string(valuesJSON)
+	}
+
+	newChart := helmRelease.Chart
+
+	if conf.Chart != nil {
+		newChart = conf.Chart
+	}
+
+	rel, image, err := getPreDeployImage(config, cluster, helmRelease, newChart, values)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	// the pre-deploy command of the release runs in the background, and the release is
+	// upgraded once the command succeeds
+	if image != "" {
+		return startPreDeploy(config, agentGetter, r, opts.user, cluster, rel, helmRelease, image,
+			func(r *http.Request, preDeployErr error) apierrors.RequestError {
+				return deployUpgrade(config, agentGetter, r, opts, helmAgent, conf, preDeployErr)
+			},
+		)
+	}
+
+	return nil, deployUpgrade(config, agentGetter, r, opts, helmAgent, conf, nil)
+}
+
+// deployUpgrade upgrades a release with the config of applyUpgrade, unless its pre-deploy
+// command failed, and then sends notifications, starts the health gate and updates the
+// GitHub Actions env. A failed pre-deploy command is reported like a failed upgrade.
+func deployUpgrade(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	opts *upgradeOpts,
+	helmAgent *helm.Agent,
+	conf *helm.UpgradeReleaseConfig,
+	preDeployErr error,
+) apierrors.RequestError {
+	user, cluster, helmRelease, request := opts.user, opts.cluster, opts.helmRelease, opts.request
+
+	upgradeErr := preDeployErr
+
+	var newHelmRelease *release.Release
+
+	if upgradeErr == nil {
+		newHelmRelease, upgradeErr = helmAgent.UpgradeRelease(conf, request.Values, config.DOConf)
+	}
 
 	if upgradeErr == nil && newHelmRelease != nil {
 		helmRelease = newHelmRelease
+
+		syncReleaseToGit(config, user, cluster, helmRelease, false)
 	}
 
-	slackInts, _ := c.Repo().SlackIntegration().ListSlackIntegrationsByProjectID(cluster.ProjectID)
+	slackInts, _ := config.Repo.SlackIntegration().ListSlackIntegrationsByProjectID(cluster.ProjectID)
+
+	rel, releaseErr := config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
 
-	rel, releaseErr := c.Repo().Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+	if releaseErr == nil {
+		recordDeployEvent(config, r, rel, &deployEvent{
+			user:        user,
+			helmRelease: helmRelease,
+			commitSHA:   request.CommitSHA,
+			message:     request.Message,
+			err:         upgradeErr,
+		})
+	}
 
 	var notifConf *types.NotificationConfig
 	notifConf = nil
 	if rel != nil && rel.NotificationConfig != 0 {
-		conf, err := c.Repo().NotificationConfig().ReadNotificationConfig(rel.NotificationConfig)
+		conf, err := config.Repo.NotificationConfig().ReadNotificationConfig(rel.NotificationConfig)
 
 		if err != nil {
-			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-			return
+			return apierrors.NewErrInternal(err)
 		}
 
 		notifConf = conf.ToNotificationConfigType()
@@ -143,9 +400,10 @@ func (c *UpgradeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		ClusterName: cluster.Name,
 		Name:        helmRelease.Name,
 		Namespace:   helmRelease.Namespace,
+		Message:     request.Message,
 		URL: fmt.Sprintf(
 			"%s/applications/%s/%s/%s?project_id=%d",
-			c.Config().ServerConf.ServerURL,
+			config.ServerConf.ServerURL,
 			url.PathEscape(cluster.Name),
 			helmRelease.Namespace,
 			helmRelease.Name,
@@ -153,6 +411,18 @@ func (c *UpgradeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		),
 	}
 
+	if releaseErr == nil {
+		postGithubDeployStatus(config, &githubDeployStatus{
+			cluster:   cluster,
+			release:   rel,
+			namespace: helmRelease.Namespace,
+			commitSHA: request.CommitSHA,
+			message:   request.Message,
+			url:       notifyOpts.URL,
+			err:       upgradeErr,
+		})
+	}
+
 	if upgradeErr != nil {
 		notifyOpts.Status = slack.StatusHelmFailed
 		notifyOpts.Info = upgradeErr.Error()
@@ -161,12 +431,38 @@ func (c *UpgradeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 			notifier.Notify(notifyOpts)
 		}
 
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+		var preDeployErr *predeploy.Error
+
+		if errors.As(upgradeErr, &preDeployErr) {
+			return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+				upgradeErr,
+				http.StatusBadRequest,
+			), types.ErrorCodePreDeployFailed)
+		}
+
+		return apierrors.NewErrPassThroughToClient(
 			upgradeErr,
 			http.StatusBadRequest,
-		))
+		)
+	}
 
-		return
+	if err := addons.UpdateTrackedAddon(config.Repo, cluster, helmRelease); err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if releaseErr == nil && rel != nil {
+		if err := updateReleaseImageSBOM(config, cluster, rel, helmRelease); err != nil {
+			return apierrors.NewErrInternal(err)
+		}
+	}
+
+	// the proxy of the ingress controller is not part of the release, so it is updated to
+	// the exposure of the release after each upgrade
+	if err := syncServiceProxy(helmAgent.K8sAgent, opts.helmRelease, helmRelease); err != nil {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("release was upgraded, but its service proxy could not be updated: %s", err.Error()),
+			http.StatusBadRequest,
+		)
 	}
 
 	if helmRelease.Chart != nil && helmRelease.Chart.Metadata.Name != "job" {
@@ -176,23 +472,41 @@ func (c *UpgradeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		if !cluster.NotificationsDisabled {
 			notifier.Notify(notifyOpts)
 		}
+
+		if releaseErr == nil {
+			k8sAgent, err := agentGetter.GetAgent(r, cluster, helmRelease.Namespace)
+
+			if err != nil {
+				return apierrors.NewErrInternal(err)
+			}
+
+			if cluster.NotificationsDisabled {
+				notifier = nil
+			}
+
+			err = startHealthGate(config, cluster, k8sAgent, helmAgent, rel, helmRelease, notifier, notifyOpts)
+
+			if err != nil {
+				return apierrors.NewErrInternal(err)
+			}
+		}
 	}
 
-	// update the github actions env if the release exists and is built from source
+	// update the github actions env if the release exists and is built from source. Upgrades
+	// without a user, such as webhook deploys, only change the image tag, so the env is kept.
 	if cName := helmRelease.Chart.Metadata.Name; cName == "job" || cName == "web" || cName == "worker" {
-		if releaseErr == nil && rel != nil {
-			err = updateReleaseRepo(c.Config(), rel, helmRelease)
+		if releaseErr == nil && rel != nil && user != nil {
+			err := updateReleaseRepo(config, rel, helmRelease)
 
 			if err != nil {
-				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-				return
+				return apierrors.NewErrInternal(err)
 			}
 
 			gitAction := rel.GitActionConfig
 
 			if gitAction != nil && gitAction.ID != 0 {
 				gaRunner, err := getGARunner(
-					c.Config(),
+					config,
 					user.ID,
 					cluster.ProjectID,
 					cluster.ID,
@@ -204,24 +518,35 @@ func (c *UpgradeReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 				)
 
 				if err != nil {
-					c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-					return
+					return apierrors.NewErrInternal(err)
 				}
 
 				actionVersion, err := semver.NewVersion(gaRunner.Version)
 
 				if err != nil {
-					c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-					return
+					return apierrors.NewErrInternal(err)
 				}
 
 				if createEnvSecretConstraint.Check(actionVersion) {
 					if err := gaRunner.CreateEnvSecret(); err != nil {
-						c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-						return
+						return apierrors.NewErrInternal(err)
 					}
 				}
 			}
 		}
 	}
+
+	return nil
+}
+
+// checkUpgradeChartAllowed returns an error if the chart allow-list of the project does
+// not allow the chart that an upgrade deploys, which is the chart of the options if it is
+// set, and the chart of the release otherwise. Upgrades to a new chart version load the
+// chart from the repository of the chart of the release.
+func checkUpgradeChartAllowed(config *config.Config, opts *upgradeOpts) apierrors.RequestError {
+	if opts.chart != nil {
+		return CheckReleaseChartAllowed(config, opts.cluster.ProjectID, opts.chart, opts.chartRepoURL)
+	}
+
+	return CheckReleaseChartAllowed(config, opts.cluster.ProjectID, opts.helmRelease.Chart, "")
 }
@@ -0,0 +1,91 @@
+package release
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type UpdateHealthGateHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewUpdateHealthGateHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *UpdateHealthGateHandler {
+	return &UpdateHealthGateHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *UpdateHealthGateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+	name, _ := requestutils.GetURLParamString(r, types.URLParamReleaseName)
+	namespace := r.Context().Value(types.NamespaceScope).(string)
+
+	request := &types.UpdateHealthGateConfigRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	release, err := c.Repo().Release().ReadRelease(cluster.ID, name, namespace)
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	verificationMinutes := request.VerificationMinutes
+
+	if verificationMinutes == 0 {
+		verificationMinutes = types.DefaultHealthGateVerificationMinutes
+	}
+
+	// either create a new health gate config or update the current one
+	gateConfig := &models.HealthGateConfig{
+		Enabled:             request.Enabled,
+		VerificationMinutes: verificationMinutes,
+		MaxErrorRate:        request.MaxErrorRate,
+		MaxLatency:          request.MaxLatency,
+	}
+
+	if release.HealthGateConfig == 0 {
+		gateConfig, err = c.Repo().HealthGateConfig().CreateHealthGateConfig(gateConfig)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		release.HealthGateConfig = gateConfig.ID
+
+		_, err = c.Repo().Release().UpdateRelease(release)
+	} else {
+		gateConfig.ID = release.HealthGateConfig
+		gateConfig, err = c.Repo().HealthGateConfig().UpdateHealthGateConfig(gateConfig)
+	}
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, &types.GetHealthGateConfigResponse{
+		HealthGateConfig: gateConfig.ToHealthGateConfigType(),
+	})
+}
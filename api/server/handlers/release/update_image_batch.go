@@ -1,6 +1,7 @@
 package release
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -33,6 +34,7 @@ func NewUpdateImageBatchHandler(
 }
 
 func (c *UpdateImageBatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 
 	helmAgent, err := c.GetHelmAgent(r, cluster, "")
@@ -48,6 +50,11 @@ func (c *UpdateImageBatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// the tag is pushed before the releases are updated, so it is listed from then on
+	if c.Config().RegistryIndex != nil {
+		c.Config().RegistryIndex.InvalidateImageRepository(cluster.ProjectID, request.ImageRepoURI)
+	}
+
 	releases, err := c.Repo().Release().ListReleasesByImageRepoURI(cluster.ID, request.ImageRepoURI)
 
 	if err != nil {
@@ -59,13 +66,6 @@ func (c *UpdateImageBatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	registries, err := c.Repo().Registry().ListRegistriesByProjectID(cluster.ProjectID)
-
-	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-		return
-	}
-
 	// asynchronously update releases with that image repo uri
 	var wg sync.WaitGroup
 	mu := &sync.Mutex{}
@@ -78,35 +78,56 @@ func (c *UpdateImageBatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		go func() {
 			defer wg.Done()
 			// read release via agent
-			rel, err := helmAgent.GetRelease(releases[index].Name, 0, false)
+			rel, err := helmAgent.GetRelease(releases[index].Name, 0, true)
 
 			if err != nil {
 				mu.Lock()
 				errors = append(errors, err.Error())
 				mu.Unlock()
+
+				return
 			}
 
 			if rel.Chart.Name() == "job" {
-				image := map[string]interface{}{}
-				image["repository"] = releases[index].ImageRepoURI
-				image["tag"] = request.Tag
-				rel.Config["image"] = image
-				rel.Config["paused"] = true
-
-				conf := &helm.UpgradeReleaseConfig{
-					Name:       releases[index].Name,
-					Cluster:    cluster,
-					Repo:       c.Repo(),
-					Registries: registries,
-					Values:     rel.Config,
+				values, err := copyValues(rel.Config)
+
+				if err != nil {
+					mu.Lock()
+					errors = append(errors, err.Error())
+					mu.Unlock()
+
+					return
 				}
 
-				_, err = helmAgent.UpgradeReleaseByValues(conf, c.Config().DOConf)
+				// custom charts may set the image under another key than "image"
+				helm.SetImageValues(values, helm.GetImageValuesKey(rel.Chart), releases[index].ImageRepoURI, request.Tag)
+				values["paused"] = true
+
+				valuesJSON, err := json.Marshal(values)
 
 				if err != nil {
 					mu.Lock()
 					errors = append(errors, err.Error())
 					mu.Unlock()
+
+					return
+				}
+
+				// each release goes through the shared upgrade path, so protected
+				// releases get a change request instead of being upgraded
+				_, _, reqErr := upgradeRelease(c.Config(), c.KubernetesAgentGetter, r, &upgradeOpts{
+					user:        user,
+					cluster:     cluster,
+					helmRelease: rel,
+					request: &types.UpgradeReleaseRequest{
+						Values: string(valuesJSON),
+					},
+				})
+
+				if reqErr != nil {
+					mu.Lock()
+					errors = append(errors, reqErr.Error())
+					mu.Unlock()
 				}
 			}
 		}()
@@ -115,10 +136,10 @@ func (c *UpdateImageBatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 	wg.Wait()
 
 	if len(errors) > 0 {
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
 			fmt.Errorf("errors while deploying: %s", strings.Join(errors, ",")),
 			http.StatusBadRequest,
-		))
+		), types.ErrorCodeHelmOperationFailed))
 
 		return
 	}
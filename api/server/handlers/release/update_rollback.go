@@ -4,13 +4,12 @@ import (
 	"fmt"
 	"net/http"
 
-	semver "github.com/Masterminds/semver/v3"
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
 	"github.com/porter-dev/porter/api/server/shared"
-	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
 	"github.com/porter-dev/porter/internal/models"
 	"helm.sh/helm/v3/pkg/release"
 )
@@ -36,83 +35,39 @@ func (c *RollbackReleaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
 	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
 
-	helmAgent, err := c.GetHelmAgent(r, cluster, "")
+	request := &types.RollbackReleaseRequest{}
 
-	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
 		return
 	}
 
-	request := &types.RollbackReleaseRequest{}
+	cr, run, reqErr := rollbackRelease(c.Config(), c.KubernetesAgentGetter, r, user, cluster, helmRelease, request.Revision)
 
-	if ok := c.DecodeAndValidate(w, r, request); !ok {
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
 		return
 	}
 
-	err = helmAgent.RollbackRelease(helmRelease.Name, request.Revision)
-
-	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
-			fmt.Errorf("error rolling back release: %s", err.Error()),
-			http.StatusBadRequest,
-		))
+	// rollbacks of protected releases are stored as change requests, like upgrades
+	if cr != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
 
 		return
 	}
 
-	// update the github actions env if the release exists and is built from source
-	if cName := helmRelease.Chart.Metadata.Name; cName == "job" || cName == "web" || cName == "worker" {
-		rel, err := c.Repo().Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
-
-		if err == nil && rel != nil {
-			err = updateReleaseRepo(c.Config(), rel, helmRelease)
-
-			if err != nil {
-				c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-				return
-			}
-
-			gitAction := rel.GitActionConfig
-
-			if gitAction != nil && gitAction.ID != 0 {
-				gaRunner, err := getGARunner(
-					c.Config(),
-					user.ID,
-					cluster.ProjectID,
-					cluster.ID,
-					rel.GitActionConfig,
-					helmRelease.Name,
-					helmRelease.Namespace,
-					rel,
-					helmRelease,
-				)
-
-				if err != nil {
-					c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-					return
-				}
-
-				actionVersion, err := semver.NewVersion(gaRunner.Version)
-
-				if err != nil {
-					c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-					return
-				}
-
-				if createEnvSecretConstraint.Check(actionVersion) {
-					if err := gaRunner.CreateEnvSecret(); err != nil {
-						c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-						return
-					}
-				}
-			}
-		}
+	// rollbacks to a different image wait for the pre-deploy command of the release
+	if run != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, &types.UpgradeReleaseResponse{
+			PreDeployRun: run.ToPreDeployRunType(),
+		})
 	}
 }
 
 func updateReleaseRepo(config *config.Config, release *models.Release, helmRelease *release.Release) error {
-	repository := helmRelease.Config["image"].(map[string]interface{})["repository"]
-	repoStr, ok := repository.(string)
+	image := helm.GetImageValues(helmRelease.Config, helm.GetImageValuesKey(helmRelease.Chart))
+	repoStr, ok := image["repository"].(string)
 
 	if !ok {
 		return fmt.Errorf("Could not find field repository in config")
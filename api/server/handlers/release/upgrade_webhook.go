@@ -1,9 +1,10 @@
 package release
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
 
 	"github.com/porter-dev/porter/api/server/authz"
 	"github.com/porter-dev/porter/api/server/handlers"
@@ -14,7 +15,6 @@ import (
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/helm"
-	"github.com/porter-dev/porter/internal/integrations/slack"
 	"gorm.io/gorm"
 )
 
@@ -54,6 +54,15 @@ func (c *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the webhook is not a project-scoped route, so the scopes of the release are added
+	// to the request so that its errors are logged for the project of the release
+	r = r.WithContext(context.WithValue(r.Context(), types.RequestScopeCtxKey, map[types.PermissionScope]*types.RequestAction{
+		types.ProjectScope:   {Verb: types.APIVerbUpdate, Resource: types.NameOrUInt{UInt: release.ProjectID}},
+		types.ClusterScope:   {Verb: types.APIVerbUpdate, Resource: types.NameOrUInt{UInt: release.ClusterID}},
+		types.NamespaceScope: {Verb: types.APIVerbUpdate, Resource: types.NameOrUInt{Name: release.Namespace}},
+		types.ReleaseScope:   {Verb: types.APIVerbUpdate, Resource: types.NameOrUInt{Name: release.Name}},
+	}))
+
 	cluster, err := c.Repo().Cluster().ReadCluster(release.ProjectID, release.ClusterID)
 
 	if err != nil {
@@ -93,104 +102,99 @@ func (c *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// repository is set to current repository by default
-	repository := rel.Config["image"].(map[string]interface{})["repository"]
-
-	gitAction := release.GitActionConfig
+	// the image is set on a copy of the values, since the shared upgrade path compares
+	// them to the current values of the release
+	values, err := copyValues(rel.Config)
 
-	if gitAction != nil && gitAction.ID != 0 && (repository == "porterdev/hello-porter" || repository == "public.ecr.aws/o1j4x7p4/hello-porter") {
-		repository = gitAction.ImageRepoURI
-	} else if gitAction != nil && gitAction.ID != 0 && (repository == "porterdev/hello-porter-job" || repository == "public.ecr.aws/o1j4x7p4/hello-porter-job") {
-		repository = gitAction.ImageRepoURI
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
 	}
 
-	image := map[string]interface{}{}
-	image["repository"] = repository
-	image["tag"] = request.Commit
-	rel.Config["image"] = image
+	// custom charts may set the image under another key than "image"
+	imageValuesKey := helm.GetImageValuesKey(rel.Chart)
 
-	if rel.Config["auto_deploy"] == false {
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
-			fmt.Errorf("Deploy webhook is disabled for this deployment."),
-			http.StatusBadRequest,
-		))
+	// repository is set to current repository by default
+	var repository interface{}
 
-		return
+	if image := helm.GetImageValues(values, imageValuesKey); image != nil {
+		repository = image["repository"]
 	}
 
-	registries, err := c.Repo().Registry().ListRegistriesByProjectID(release.ProjectID)
+	gitAction := release.GitActionConfig
 
-	if err != nil {
-		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-		return
-	}
+	// the default images are matched by their public, legacy and mirrored names
+	imageMirror := c.Config().ImageMirror
+	currRepository, _ := repository.(string)
 
-	conf := &helm.UpgradeReleaseConfig{
-		Name:       release.Name,
-		Cluster:    cluster,
-		Repo:       c.Repo(),
-		Registries: registries,
-		Values:     rel.Config,
+	if gitAction != nil && gitAction.ID != 0 && imageMirror.IsDefaultAppImage(currRepository) {
+		repository = gitAction.ImageRepoURI
+	} else if gitAction != nil && gitAction.ID != 0 && imageMirror.IsDefaultJobImage(currRepository) {
+		repository = gitAction.ImageRepoURI
 	}
 
-	slackInts, _ := c.Repo().SlackIntegration().ListSlackIntegrationsByProjectID(release.ProjectID)
+	helm.SetImageValues(values, imageValuesKey, repository, request.Commit)
 
-	var notifConf *types.NotificationConfig
-	notifConf = nil
-	if release != nil && release.NotificationConfig != 0 {
-		conf, err := c.Repo().NotificationConfig().ReadNotificationConfig(release.NotificationConfig)
+	// the tag is pushed by CI before the webhook is called, so it is listed from then on
+	if imageRepo, ok := repository.(string); ok && imageRepo != "" && c.Config().RegistryIndex != nil {
+		c.Config().RegistryIndex.InvalidateImageRepository(cluster.ProjectID, imageRepo)
+	}
 
-		if err != nil {
-			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
-			return
-		}
+	if values["auto_deploy"] == false {
+		c.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("Deploy webhook is disabled for this deployment."),
+			http.StatusBadRequest,
+		), types.ErrorCodeWebhookDisabled))
 
-		notifConf = conf.ToNotificationConfigType()
+		return
 	}
 
-	notifier := slack.NewSlackNotifier(notifConf, slackInts...)
+	// webhook deploys are usually triggered by CI without a message, so the message of
+	// the deployed commit is used instead
+	message := request.Message
 
-	notifyOpts := &slack.NotifyOpts{
-		ProjectID:   release.ProjectID,
-		ClusterID:   cluster.ID,
-		ClusterName: cluster.Name,
-		Name:        rel.Name,
-		Namespace:   rel.Namespace,
-		URL: fmt.Sprintf(
-			"%s/applications/%s/%s/%s?project_id=%d",
-			c.Config().ServerConf.ServerURL,
-			url.PathEscape(cluster.Name),
-			release.Namespace,
-			rel.Name,
-			cluster.ProjectID,
-		),
+	if message == "" {
+		message = getCommitMessage(c.Config(), gitAction, request.Commit)
 	}
 
-	rel, err = helmAgent.UpgradeReleaseByValues(conf, c.Config().DOConf)
+	valuesJSON, err := json.Marshal(values)
 
 	if err != nil {
-		notifyOpts.Status = slack.StatusHelmFailed
-		notifyOpts.Info = err.Error()
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
 
-		if !cluster.NotificationsDisabled {
-			notifier.Notify(notifyOpts)
-		}
+	// webhook deploys go through the same path as other upgrades, so deploys of protected
+	// releases are stored as change requests
+	cr, run, reqErr := upgradeRelease(c.Config(), c.KubernetesAgentGetter, r, &upgradeOpts{
+		cluster:     cluster,
+		helmRelease: rel,
+		request: &types.UpgradeReleaseRequest{
+			Values:    string(valuesJSON),
+			CommitSHA: request.Commit,
+			Message:   message,
+		},
+	})
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
 
-		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
-			err,
-			http.StatusBadRequest,
-		))
+	if cr != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, cr.ToReleaseChangeRequestType())
 
 		return
 	}
 
-	if rel.Chart != nil && rel.Chart.Metadata.Name != "job" {
-		notifyOpts.Status = slack.StatusHelmDeployed
-		notifyOpts.Version = rel.Version
+	if run != nil {
+		w.WriteHeader(http.StatusAccepted)
+		c.WriteResult(w, r, &types.UpgradeReleaseResponse{
+			PreDeployRun: run.ToPreDeployRunType(),
+		})
 
-		if !cluster.NotificationsDisabled {
-			notifier.Notify(notifyOpts)
-		}
+		return
 	}
 
 	c.Config().AnalyticsClient.Track(analytics.ApplicationDeploymentWebhookTrack(&analytics.ApplicationDeploymentWebhookTrackOpts{
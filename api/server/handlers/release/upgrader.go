@@ -0,0 +1,522 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/router/middleware"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// upgradeOpts are the options of an upgrade through the shared upgrade path
+type upgradeOpts struct {
+	// user is the user that upgrades the release, or nil for webhooks and other
+	// automated upgrades
+	user        *models.User
+	cluster     *models.Cluster
+	helmRelease *release.Release
+	request     *types.UpgradeReleaseRequest
+
+	// chart overrides the chart of the release, such as the chart of a promoted release,
+	// and takes precedence over the chart version of the request
+	chart *chart.Chart
+
+	// chartRepoURL is the repo URL of chart, if it is known
+	chartRepoURL string
+}
+
+// upgradeRelease is the path that every upgrade of a release goes through. Upgrades of
+// protected releases are stored as change requests, which are returned, and are applied
+// once they are approved by another user. Other upgrades are applied directly, or once
+// the pre-deploy command of the release succeeds, in which case its run is returned.
+func upgradeRelease(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	opts *upgradeOpts,
+) (*models.ReleaseChangeRequest, *models.PreDeployRun, apierrors.RequestError) {
+	if reqErr := CheckDeployFreeze(config, r, opts.user, opts.cluster); reqErr != nil {
+		return nil, nil, reqErr
+	}
+
+	if reqErr := checkReleasePaused(config, opts.cluster, opts.helmRelease); reqErr != nil {
+		return nil, nil, reqErr
+	}
+
+	protected, err := isReleaseProtected(config.Repo, opts.cluster, opts.helmRelease.Name, opts.helmRelease.Namespace)
+
+	if err != nil {
+		return nil, nil, apierrors.NewErrInternal(err)
+	}
+
+	if protected {
+		// change requests are only created for charts that are allowed, and the chart is
+		// checked again once the change request is approved
+		if reqErr := checkUpgradeChartAllowed(config, opts); reqErr != nil {
+			return nil, nil, reqErr
+		}
+
+		request := opts.request
+
+		// the chart of the upgrade is stored by its version, and is loaded again from
+		// the chart repo once the change request is approved
+		if opts.chart != nil && opts.chart.Metadata != nil {
+			request = &types.UpgradeReleaseRequest{
+				Values:       opts.request.Values,
+				ChartVersion: opts.chart.Metadata.Version,
+				CommitSHA:    opts.request.CommitSHA,
+				Message:      opts.request.Message,
+			}
+		}
+
+		cr, reqErr := createUpgradeChangeRequest(config, r, opts.user, opts.cluster, opts.helmRelease, request)
+
+		return cr, nil, reqErr
+	}
+
+	run, reqErr := applyUpgrade(config, agentGetter, r, opts)
+
+	return nil, run, reqErr
+}
+
+// CheckDeployFreeze returns an error if a deploy freeze of the project or cluster is
+// active. Every upgrade and rollback through the shared upgrade path is checked, and
+// handlers that install releases check freezes before they install. Freezes that the
+// deploy freeze middleware already checked for the request are not checked again, so that
+// overrides are only recorded once.
+func CheckDeployFreeze(
+	config *config.Config,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+) apierrors.RequestError {
+	if middleware.IsDeployFreezeChecked(r, cluster.ID) {
+		return nil
+	}
+
+	return middleware.CheckDeployFreeze(config, r, cluster.ProjectID, cluster.ID, user)
+}
+
+// rollbackRelease is the path that every rollback of a release goes through. Rollbacks
+// of protected releases are stored as change requests, like upgrades, and rollbacks that
+// wait for the pre-deploy command of the release return its run.
+func rollbackRelease(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	revision int,
+) (*models.ReleaseChangeRequest, *models.PreDeployRun, apierrors.RequestError) {
+	if reqErr := CheckDeployFreeze(config, r, user, cluster); reqErr != nil {
+		return nil, nil, reqErr
+	}
+
+	if reqErr := checkReleasePaused(config, cluster, helmRelease); reqErr != nil {
+		return nil, nil, reqErr
+	}
+
+	protected, err := isReleaseProtected(config.Repo, cluster, helmRelease.Name, helmRelease.Namespace)
+
+	if err != nil {
+		return nil, nil, apierrors.NewErrInternal(err)
+	}
+
+	if protected {
+		cr, err := createRollbackChangeRequest(config, r, user, cluster, helmRelease, revision)
+
+		if err != nil {
+			return nil, nil, apierrors.NewErrInternal(err)
+		}
+
+		return cr, nil, nil
+	}
+
+	run, reqErr := applyRollback(config, agentGetter, r, user, cluster, helmRelease, revision)
+
+	return nil, run, reqErr
+}
+
+// applyRollback rolls a release back to a revision, and then syncs the release to git and
+// updates the GitHub Actions env. If the rollback changes the image of the release, the
+// pre-deploy command of the release runs first in the background, and its run is
+// returned. Like applyUpgrade, it does not check whether the release is protected.
+func applyRollback(
+	config *config.Config,
+	agentGetter authz.KubernetesAgentGetter,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	revision int,
+) (*models.PreDeployRun, apierrors.RequestError) {
+	helmAgent, err := agentGetter.GetHelmAgent(r, cluster, helmRelease.Namespace)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	// a revision of 0 rolls the release back to its previous revision
+	toRevision := revision
+
+	if toRevision <= 0 {
+		toRevision = helmRelease.Version - 1
+	}
+
+	toRelease, err := helmAgent.GetRelease(helmRelease.Name, toRevision, false)
+
+	if err != nil {
+		return nil, apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error rolling back release: %s", err.Error()),
+			http.StatusBadRequest,
+		), types.ErrorCodeHelmOperationFailed)
+	}
+
+	// the revision may have a chart that was removed from the allow-list since
+	if reqErr := CheckReleaseChartAllowed(config, cluster.ProjectID, toRelease.Chart, ""); reqErr != nil {
+		return nil, reqErr
+	}
+
+	rel, image, err := getPreDeployImage(config, cluster, helmRelease, toRelease.Chart, toRelease.Config)
+
+	if err != nil {
+		return nil, apierrors.NewErrInternal(err)
+	}
+
+	if image != "" {
+		return startPreDeploy(config, agentGetter, r, user, cluster, rel, helmRelease, image,
+			func(r *http.Request, preDeployErr error) apierrors.RequestError {
+				if preDeployErr != nil {
+					return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+						preDeployErr,
+						http.StatusBadRequest,
+					), types.ErrorCodePreDeployFailed)
+				}
+
+				return deployRollback(config, r, user, cluster, helmAgent, helmRelease, revision)
+			},
+		)
+	}
+
+	return nil, deployRollback(config, r, user, cluster, helmAgent, helmRelease, revision)
+}
+
+// deployRollback rolls a release back to a revision with the helm agent of applyRollback
+func deployRollback(
+	config *config.Config,
+	r *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	helmAgent *helm.Agent,
+	helmRelease *release.Release,
+	revision int,
+) apierrors.RequestError {
+	err := helmAgent.RollbackRelease(helmRelease.Name, revision)
+
+	if err != nil {
+		return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error rolling back release: %s", err.Error()),
+			http.StatusBadRequest,
+		), types.ErrorCodeHelmOperationFailed)
+	}
+
+	if rolledBackRelease, err := helmAgent.GetRelease(helmRelease.Name, 0, false); err == nil {
+		// a revision of 0 rolls the release back to its previous revision
+		fromRevision := revision
+
+		if fromRevision <= 0 {
+			fromRevision = helmRelease.Version - 1
+		}
+
+		err := helm.RestoreSensitiveValues(
+			config.Repo,
+			cluster,
+			helmRelease.Namespace,
+			helmRelease.Name,
+			fromRevision,
+			rolledBackRelease.Version,
+		)
+
+		if err != nil {
+			return apierrors.NewErrInternal(err)
+		}
+
+		syncReleaseToGit(config, user, cluster, rolledBackRelease, false)
+
+		if err := syncServiceProxy(helmAgent.K8sAgent, helmRelease, rolledBackRelease); err != nil {
+			return apierrors.NewErrPassThroughToClient(
+				fmt.Errorf("release was rolled back, but its service proxy could not be updated: %s", err.Error()),
+				http.StatusBadRequest,
+			)
+		}
+
+		if rel, err := config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace); err == nil {
+			if err := updateReleaseImageSBOM(config, cluster, rel, rolledBackRelease); err != nil {
+				return apierrors.NewErrInternal(err)
+			}
+		}
+	}
+
+	// update the github actions env if the release exists and is built from source
+	if cName := helmRelease.Chart.Metadata.Name; user != nil && (cName == "job" || cName == "web" || cName == "worker") {
+		rel, err := config.Repo.Release().ReadRelease(cluster.ID, helmRelease.Name, helmRelease.Namespace)
+
+		if err == nil && rel != nil {
+			err = updateReleaseRepo(config, rel, helmRelease)
+
+			if err != nil {
+				return apierrors.NewErrInternal(err)
+			}
+
+			gitAction := rel.GitActionConfig
+
+			if gitAction != nil && gitAction.ID != 0 {
+				gaRunner, err := getGARunner(
+					config,
+					user.ID,
+					cluster.ProjectID,
+					cluster.ID,
+					rel.GitActionConfig,
+					helmRelease.Name,
+					helmRelease.Namespace,
+					rel,
+					helmRelease,
+				)
+
+				if err != nil {
+					return apierrors.NewErrInternal(err)
+				}
+
+				actionVersion, err := semver.NewVersion(gaRunner.Version)
+
+				if err != nil {
+					return apierrors.NewErrInternal(err)
+				}
+
+				if createEnvSecretConstraint.Check(actionVersion) {
+					if err := gaRunner.CreateEnvSecret(); err != nil {
+						return apierrors.NewErrInternal(err)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyValues returns a deep copy of the values of a release, so that values can be set on
+// the copy without changing the values of the release
+func copyValues(values map[string]interface{}) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+
+	if values == nil {
+		return res, nil
+	}
+
+	valuesJSON, err := json.Marshal(values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(valuesJSON, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ReleaseUpgrader upgrades and rolls back releases for other handlers and background
+// jobs, such as pipeline promotions, Slack commands and GitOps reconciles. Upgrades go
+// through the same path as upgrades through the release endpoints, so protected releases
+// get change requests and every upgrade is subject to the same checks.
+type ReleaseUpgrader struct {
+	config      *config.Config
+	agentGetter authz.KubernetesAgentGetter
+}
+
+func NewReleaseUpgrader(config *config.Config) *ReleaseUpgrader {
+	return &ReleaseUpgrader{
+		config:      config,
+		agentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+// UpgradeOpts are the options of an upgrade through a ReleaseUpgrader
+type UpgradeOpts struct {
+	// User is the user that upgrades the release, or nil for automated upgrades
+	User *models.User
+
+	Cluster   *models.Cluster
+	Namespace string
+	Name      string
+	Values    map[string]interface{}
+
+	// Chart overrides the chart of the release, if set
+	Chart *chart.Chart
+
+	// ChartRepoURL is the repo URL of Chart, if it is known. Otherwise, the repository
+	// of the chart is looked up from the chart repos of the project.
+	ChartRepoURL string
+
+	// Source is the source of the upgrade that is recorded in its deploy event
+	Source  types.DeploySource
+	Message string
+
+	// Request is the request that started the upgrade, if any. Its deploy freeze check
+	// and override are kept for the upgrade.
+	Request *http.Request
+}
+
+// Upgrade upgrades a release to a set of values. If the release is protected, the upgrade
+// is stored as a change request, which is returned.
+func (u *ReleaseUpgrader) Upgrade(opts *UpgradeOpts) (*models.ReleaseChangeRequest, error) {
+	r, helmRelease, err := u.getRelease(opts.Request, opts.User, opts.Cluster, opts.Namespace, opts.Name, opts.Source)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := json.Marshal(opts.Values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cr, _, reqErr := upgradeRelease(u.config, u.agentGetter, r, &upgradeOpts{
+		user:        opts.User,
+		cluster:     opts.Cluster,
+		helmRelease: helmRelease,
+		request: &types.UpgradeReleaseRequest{
+			Values:  string(values),
+			Message: opts.Message,
+		},
+		chart:        opts.Chart,
+		chartRepoURL: opts.ChartRepoURL,
+	})
+
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	return cr, nil
+}
+
+// RollbackOpts are the options of a rollback through a ReleaseUpgrader
+type RollbackOpts struct {
+	// User is the user that rolls back the release, or nil for automated rollbacks
+	User *models.User
+
+	Cluster   *models.Cluster
+	Namespace string
+	Name      string
+	Revision  int
+	Source    types.DeploySource
+
+	// Request is the request that started the rollback, if any. Its deploy freeze check
+	// and override are kept for the rollback.
+	Request *http.Request
+}
+
+// Rollback rolls a release back to a revision. If the release is protected, the rollback
+// is stored as a change request, which is returned.
+func (u *ReleaseUpgrader) Rollback(opts *RollbackOpts) (*models.ReleaseChangeRequest, error) {
+	r, helmRelease, err := u.getRelease(opts.Request, opts.User, opts.Cluster, opts.Namespace, opts.Name, opts.Source)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cr, _, reqErr := rollbackRelease(u.config, u.agentGetter, r, opts.User, opts.Cluster, helmRelease, opts.Revision)
+
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	return cr, nil
+}
+
+func (u *ReleaseUpgrader) getRelease(
+	parent *http.Request,
+	user *models.User,
+	cluster *models.Cluster,
+	namespace, name string,
+	source types.DeploySource,
+) (*http.Request, *release.Release, error) {
+	r, err := newReleaseRequest(user, cluster, namespace, source)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if parent != nil {
+		r.Header.Set(types.DeployFreezeOverrideHeader, parent.Header.Get(types.DeployFreezeOverrideHeader))
+
+		if checked, ok := parent.Context().Value(middleware.DeployFreezeCheckedCtxKey).(uint); ok {
+			r = r.WithContext(context.WithValue(r.Context(), middleware.DeployFreezeCheckedCtxKey, checked))
+		}
+	}
+
+	helmAgent, err := u.agentGetter.GetHelmAgent(r, cluster, namespace)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	helmRelease, err := helmAgent.GetRelease(name, 0, true)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read release %s: %w", name, err)
+	}
+
+	return r, helmRelease, nil
+}
+
+// newReleaseRequest returns a request with the scopes of a release, which the shared
+// upgrade path reads, for upgrades that are not started by a request to the release
+// endpoints
+func newReleaseRequest(
+	user *models.User,
+	cluster *models.Cluster,
+	namespace string,
+	source types.DeploySource,
+) (*http.Request, error) {
+	r, err := http.NewRequest(http.MethodPost, "/", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if source != "" {
+		r.Header.Set(types.DeploySourceHeader, string(source))
+	}
+
+	ctx := context.WithValue(r.Context(), types.ClusterScope, cluster)
+	ctx = context.WithValue(ctx, types.NamespaceScope, namespace)
+
+	// agent getters read the namespace of requests from their request scopes
+	ctx = context.WithValue(ctx, types.RequestScopeCtxKey, map[types.PermissionScope]*types.RequestAction{
+		types.NamespaceScope: {
+			Verb:     types.APIVerbUpdate,
+			Resource: types.NameOrUInt{Name: namespace},
+		},
+	})
+
+	if user != nil {
+		ctx = context.WithValue(ctx, types.UserScope, user)
+	}
+
+	return r.WithContext(ctx), nil
+}
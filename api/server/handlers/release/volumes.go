@@ -0,0 +1,310 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type ListVolumeClaimsHandler struct {
+	handlers.PorterHandlerWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewListVolumeClaimsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListVolumeClaimsHandler {
+	return &ListVolumeClaimsHandler{
+		PorterHandlerWriter:   handlers.NewDefaultPorterHandler(config, nil, writer),
+		KubernetesAgentGetter: authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ListVolumeClaimsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	claims, err := getReleaseVolumeClaims(agent, helmRelease)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	claimNames := make([]string, 0)
+
+	for _, claim := range claims {
+		claimNames = append(claimNames, claim.Name)
+	}
+
+	// usage is only reported if prometheus is installed in the cluster, and is omitted
+	// if it cannot be queried
+	usage := make(map[string]int64)
+
+	if promSvc, found, err := prometheus.GetPrometheusService(agent.Clientset); err == nil && found {
+		if volumeUsage, err := prometheus.GetVolumeUsage(agent.Clientset, promSvc, helmRelease.Namespace, claimNames); err == nil {
+			usage = volumeUsage
+		}
+	}
+
+	res := make(types.ListVolumeClaimsResponse, 0)
+
+	for i := range claims {
+		claim := &claims[i]
+
+		resizable, err := agent.CanResizeVolumeClaim(claim)
+
+		if err != nil && err != kubernetes.IsNotFoundError {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		volumeClaim := toVolumeClaimType(claim, resizable)
+
+		if usedBytes, ok := usage[claim.Name]; ok {
+			volumeClaim.UsedBytes = &usedBytes
+		}
+
+		res = append(res, volumeClaim)
+	}
+
+	c.WriteResult(w, r, res)
+}
+
+type ResizeVolumeClaimHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewResizeVolumeClaimHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *ResizeVolumeClaimHandler {
+	return &ResizeVolumeClaimHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *ResizeVolumeClaimHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.ResizeVolumeClaimRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	size, err := resource.ParseQuantity(request.Size)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("invalid size %s", request.Size),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	claim, ok := readReleaseVolumeClaim(c, w, r, agent, helmRelease)
+
+	if !ok {
+		return
+	}
+
+	claim, err = agent.ResizeVolumeClaim(claim, size)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	c.WriteResult(w, r, toVolumeClaimType(claim, true))
+}
+
+type CreateVolumeSnapshotHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewCreateVolumeSnapshotHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateVolumeSnapshotHandler {
+	return &CreateVolumeSnapshotHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *CreateVolumeSnapshotHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	helmRelease, _ := r.Context().Value(types.ReleaseScope).(*release.Release)
+	cluster, _ := r.Context().Value(types.ClusterScope).(*models.Cluster)
+
+	request := &types.CreateVolumeSnapshotRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	agent, err := c.GetAgent(r, cluster, "")
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	claim, ok := readReleaseVolumeClaim(c, w, r, agent, helmRelease)
+
+	if !ok {
+		return
+	}
+
+	served, err := agent.IsResourceServed(kubernetes.VolumeSnapshotResource)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if !served {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("volume snapshots require the CSI snapshot controller, which is not installed in the cluster"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	dynClient, err := c.GetDynamicClient(r, cluster)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	snapshot, err := kubernetes.CreateVolumeSnapshot(dynClient, claim, request.Name, request.SnapshotClassName)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("error creating volume snapshot, which requires the CSI snapshot controller: %s", err.Error()),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	c.WriteResult(w, r, &types.VolumeSnapshot{
+		Name:              snapshot.GetName(),
+		Namespace:         snapshot.GetNamespace(),
+		VolumeClaimName:   claim.Name,
+		SnapshotClassName: request.SnapshotClassName,
+		CreatedAt:         snapshot.GetCreationTimestamp().Time,
+	})
+}
+
+func getReleaseVolumeClaims(agent *kubernetes.Agent, helmRelease *release.Release) ([]v1.PersistentVolumeClaim, error) {
+	yamlArr := grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest))
+	objs := grapher.ParseObjs(yamlArr, helmRelease.Namespace)
+
+	return agent.GetReleaseVolumeClaims(helmRelease.Namespace, objs)
+}
+
+// readReleaseVolumeClaim reads the claim in the URL, which must belong to the release
+func readReleaseVolumeClaim(
+	c handlers.PorterHandler,
+	w http.ResponseWriter,
+	r *http.Request,
+	agent *kubernetes.Agent,
+	helmRelease *release.Release,
+) (*v1.PersistentVolumeClaim, bool) {
+	name, reqErr := requestutils.GetURLParamString(r, types.URLParamVolumeClaimName)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return nil, false
+	}
+
+	claims, err := getReleaseVolumeClaims(agent, helmRelease)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	for i := range claims {
+		if claims[i].Name == name {
+			return &claims[i], true
+		}
+	}
+
+	c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+		fmt.Errorf("volume claim %s not found in release", name),
+		http.StatusNotFound,
+	))
+
+	return nil, false
+}
+
+func toVolumeClaimType(claim *v1.PersistentVolumeClaim, resizable bool) *types.VolumeClaim {
+	res := &types.VolumeClaim{
+		Name:        claim.Name,
+		Namespace:   claim.Namespace,
+		Status:      string(claim.Status.Phase),
+		AccessModes: make([]string, 0),
+		Resizable:   resizable,
+	}
+
+	if claim.Spec.StorageClassName != nil {
+		res.StorageClass = *claim.Spec.StorageClassName
+	}
+
+	for _, mode := range claim.Spec.AccessModes {
+		res.AccessModes = append(res.AccessModes, string(mode))
+	}
+
+	if capacity, ok := claim.Status.Capacity[v1.ResourceStorage]; ok {
+		res.Capacity = capacity.String()
+		res.CapacityBytes = capacity.Value()
+	}
+
+	if requested, ok := claim.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+		res.RequestedCapacity = requested.String()
+	}
+
+	return res
+}
@@ -0,0 +1,520 @@
+package slack_integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/authz/policy"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/integrations/slack"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+const slackCommandUsage = "Usage: `/porter status|redeploy|rollback <release> [namespace=<namespace>] [cluster=<cluster>] [revision=<revision>]`"
+
+// SlackCommandHandler handles the `/porter` slash command of the Slack app
+type SlackCommandHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewSlackCommandHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *SlackCommandHandler {
+	return &SlackCommandHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *SlackCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, ok := readSlackRequest(c.PorterHandlerReadWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	form, err := url.ParseQuery(string(payload))
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	runner := &commandRunner{
+		config:      c.Config(),
+		agentGetter: c.KubernetesAgentGetter,
+		r:           r,
+		teamID:      form.Get("team_id"),
+		channelID:   form.Get("channel_id"),
+		userID:      form.Get("user_id"),
+		responseURL: form.Get("response_url"),
+	}
+
+	c.WriteResult(w, r, runner.run(form.Get("text")))
+}
+
+// SlackInteractionHandler handles clicks on the buttons of messages that were sent
+// in response to the `/porter` slash command
+type SlackInteractionHandler struct {
+	handlers.PorterHandlerReadWriter
+	authz.KubernetesAgentGetter
+}
+
+func NewSlackInteractionHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *SlackInteractionHandler {
+	return &SlackInteractionHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+		KubernetesAgentGetter:   authz.NewOutOfClusterAgentGetter(config),
+	}
+}
+
+func (c *SlackInteractionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, ok := readSlackRequest(c.PorterHandlerReadWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	form, err := url.ParseQuery(string(payload))
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	interaction := &slack.InteractionPayload{}
+
+	if err := json.Unmarshal([]byte(form.Get("payload")), interaction); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	runner := &commandRunner{
+		config:      c.Config(),
+		agentGetter: c.KubernetesAgentGetter,
+		r:           r,
+		teamID:      interaction.Team.ID,
+		channelID:   interaction.Channel.ID,
+		userID:      interaction.User.ID,
+		responseURL: interaction.ResponseURL,
+	}
+
+	// the value of a button is the command that it runs, and responses to interactions
+	// are only accepted via the response url
+	for _, action := range interaction.Actions {
+		if msg := runner.run(action.Value); msg != nil {
+			go slack.PostResponse(runner.responseURL, msg)
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// readSlackRequest reads the body of a request from Slack and verifies its signature
+func readSlackRequest(c handlers.PorterHandlerReadWriter, w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	signingSecret := c.Config().ServerConf.SlackSigningSecret
+
+	if signingSecret == "" {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("slack commands are not enabled on this instance"),
+			http.StatusNotFound,
+		))
+
+		return nil, false
+	}
+
+	payload, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	if !slack.VerifyRequest(
+		signingSecret,
+		r.Header.Get("X-Slack-Request-Timestamp"),
+		r.Header.Get("X-Slack-Signature"),
+		payload,
+	) {
+		c.HandleAPIError(w, r, apierrors.NewErrForbidden(fmt.Errorf("invalid slack request signature")))
+		return nil, false
+	}
+
+	return payload, true
+}
+
+type commandRunner struct {
+	config      *config.Config
+	agentGetter authz.KubernetesAgentGetter
+	r           *http.Request
+
+	teamID      string
+	channelID   string
+	userID      string
+	responseURL string
+}
+
+type slackCommand struct {
+	name      string
+	release   string
+	namespace string
+	cluster   string
+	revision  int
+}
+
+func parseSlackCommand(text string) (*slackCommand, error) {
+	fields := strings.Fields(text)
+
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("missing command or release")
+	}
+
+	cmd := &slackCommand{
+		name:      fields[0],
+		release:   fields[1],
+		namespace: "default",
+	}
+
+	for _, field := range fields[2:] {
+		kv := strings.SplitN(field, "=", 2)
+
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid argument %s", field)
+		}
+
+		switch kv[0] {
+		case "namespace":
+			cmd.namespace = kv[1]
+		case "cluster":
+			cmd.cluster = kv[1]
+		case "revision":
+			revision, err := strconv.Atoi(kv[1])
+
+			if err != nil || revision <= 0 {
+				return nil, fmt.Errorf("invalid revision %s", kv[1])
+			}
+
+			cmd.revision = revision
+		default:
+			return nil, fmt.Errorf("unknown argument %s", kv[0])
+		}
+	}
+
+	return cmd, nil
+}
+
+// run runs a command and returns the message that is sent back to the user. Errors are
+// returned as ephemeral messages, since Slack does not display error responses.
+func (c *commandRunner) run(text string) *slack.Message {
+	cmd, err := parseSlackCommand(text)
+
+	if err != nil {
+		return ephemeralMessage(fmt.Sprintf("%s. %s", err.Error(), slackCommandUsage))
+	}
+
+	var verb types.APIVerb
+
+	switch cmd.name {
+	case "status":
+		verb = types.APIVerbGet
+	case "redeploy", "rollback":
+		verb = types.APIVerbUpdate
+	default:
+		return ephemeralMessage(fmt.Sprintf("Unknown command %s. %s", cmd.name, slackCommandUsage))
+	}
+
+	slackInt, err := c.getSlackIntegration()
+
+	if err != nil {
+		return ephemeralMessage(err.Error())
+	}
+
+	user, err := c.getPorterUser(slackInt)
+
+	if err != nil {
+		return ephemeralMessage(err.Error())
+	}
+
+	cluster, err := c.getCluster(slackInt.ProjectID, cmd.cluster)
+
+	if err != nil {
+		return ephemeralMessage(err.Error())
+	}
+
+	if err := c.checkAccess(user, cluster, cmd, verb); err != nil {
+		return ephemeralMessage(err.Error())
+	}
+
+	helmAgent, err := c.agentGetter.GetHelmAgent(c.r, cluster, cmd.namespace)
+
+	if err != nil {
+		return ephemeralMessage(fmt.Sprintf("Could not connect to cluster %s: %s", cluster.Name, err.Error()))
+	}
+
+	helmRelease, err := helmAgent.GetRelease(cmd.release, 0, false)
+
+	if err != nil {
+		return ephemeralMessage(fmt.Sprintf("Could not find release %s in namespace %s", cmd.release, cmd.namespace))
+	}
+
+	args := fmt.Sprintf("%s namespace=%s cluster=%s", helmRelease.Name, helmRelease.Namespace, cluster.Name)
+
+	switch cmd.name {
+	case "status":
+		return releaseMessage("ephemeral", fmt.Sprintf(
+			"*%s* in namespace *%s* of cluster *%s* is %s at revision %d, last deployed %s",
+			helmRelease.Name,
+			helmRelease.Namespace,
+			cluster.Name,
+			helmRelease.Info.Status,
+			helmRelease.Version,
+			helmRelease.Info.LastDeployed.Format("2006-01-02 15:04:05 MST"),
+		), args)
+	case "redeploy":
+		// releases are redeployed and rolled back through the shared upgrade path of
+		// releases, so commands on protected releases get change requests
+		go c.runInBackground(user, "redeployed", args, func() (*models.ReleaseChangeRequest, error) {
+			return release.NewReleaseUpgrader(c.config).Upgrade(&release.UpgradeOpts{
+				User:      user,
+				Cluster:   cluster,
+				Namespace: helmRelease.Namespace,
+				Name:      helmRelease.Name,
+				Values:    helmRelease.Config,
+				Source:    types.DeploySourceSlack,
+			})
+		})
+	case "rollback":
+		revision := cmd.revision
+
+		if revision == 0 {
+			revision = helmRelease.Version - 1
+		}
+
+		if revision <= 0 || revision == helmRelease.Version {
+			return ephemeralMessage(fmt.Sprintf("There is no revision of %s to roll back to", helmRelease.Name))
+		}
+
+		go c.runInBackground(user, fmt.Sprintf("rolled back to revision %d", revision), args, func() (*models.ReleaseChangeRequest, error) {
+			return release.NewReleaseUpgrader(c.config).Rollback(&release.RollbackOpts{
+				User:      user,
+				Cluster:   cluster,
+				Namespace: helmRelease.Namespace,
+				Name:      helmRelease.Name,
+				Revision:  revision,
+				Source:    types.DeploySourceSlack,
+			})
+		})
+	}
+
+	return ephemeralMessage(fmt.Sprintf("Running `%s %s`...", cmd.name, args))
+}
+
+// runInBackground runs a redeploy or rollback after the command has been acknowledged, since
+// Slack expects a response within 3 seconds. Errors and panics are logged, since they can no
+// longer be returned, and posted to the channel.
+func (c *commandRunner) runInBackground(
+	user *models.User,
+	action, args string,
+	run func() (*models.ReleaseChangeRequest, error),
+) {
+	var cr *models.ReleaseChangeRequest
+	var err error
+
+	func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = fmt.Errorf("%v", rec)
+			}
+		}()
+
+		cr, err = run()
+	}()
+
+	if err != nil {
+		c.config.Logger.Error().Err(err).Msgf("slack command by %s failed for %s", user.Email, args)
+	}
+
+	if err := slack.PostResponse(c.responseURL, getCommandResponse(user, action, args, cr, err)); err != nil {
+		c.config.Logger.Error().Err(err).Msgf("could not post response of slack command for %s", args)
+	}
+}
+
+// getResponse returns the message with the result of a redeploy or rollback
+func getCommandResponse(
+	user *models.User,
+	action, args string,
+	cr *models.ReleaseChangeRequest,
+	err error,
+) *slack.Message {
+	if err != nil {
+		return ephemeralMessage(fmt.Sprintf("Failed: %s", err.Error()))
+	}
+
+	if cr != nil {
+		return releaseMessage("in_channel", fmt.Sprintf(
+			"%s requested a change to %s, which is protected and needs approval by another user",
+			user.Email,
+			args,
+		), args)
+	}
+
+	return releaseMessage("in_channel", fmt.Sprintf("%s %s %s", user.Email, action, args), args)
+}
+
+// getSlackIntegration returns the integration of the channel that the command was run in,
+// or the only integration of the Slack team if it belongs to a single project
+func (c *commandRunner) getSlackIntegration() (*integrations.SlackIntegration, error) {
+	slackInts, err := c.config.Repo.SlackIntegration().ListSlackIntegrationsByTeamID(c.teamID)
+
+	if err != nil || len(slackInts) == 0 {
+		return nil, fmt.Errorf("This Slack workspace is not connected to a Porter project")
+	}
+
+	for _, slackInt := range slackInts {
+		if slackInt.ChannelID == c.channelID {
+			return slackInt, nil
+		}
+	}
+
+	for _, slackInt := range slackInts[1:] {
+		if slackInt.ProjectID != slackInts[0].ProjectID {
+			return nil, fmt.Errorf("This Slack workspace is connected to multiple Porter projects, please run the command in a connected channel")
+		}
+	}
+
+	return slackInts[0], nil
+}
+
+// getPorterUser maps the Slack user to the Porter user with the same email
+func (c *commandRunner) getPorterUser(slackInt *integrations.SlackIntegration) (*models.User, error) {
+	email, err := slack.GetUserEmail(string(slackInt.AccessToken), c.userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("Could not get your Slack email, the Slack integration may need to be reinstalled")
+	}
+
+	user, err := c.config.Repo.User().ReadUserByEmail(email)
+
+	if err != nil {
+		return nil, fmt.Errorf("There is no Porter user with the email %s", email)
+	}
+
+	return user, nil
+}
+
+func (c *commandRunner) getCluster(projectID uint, name string) (*models.Cluster, error) {
+	clusters, err := c.config.Repo.Cluster().ListClustersByProjectID(projectID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		if len(clusters) != 1 {
+			return nil, fmt.Errorf("The project has %d clusters, please specify one with cluster=<cluster>", len(clusters))
+		}
+
+		return clusters[0], nil
+	}
+
+	for _, cluster := range clusters {
+		if cluster.Name == name || fmt.Sprintf("%d", cluster.ID) == name {
+			return cluster, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Could not find cluster %s", name)
+}
+
+// checkAccess checks that the policy of the user permits the command, in the same way as
+// the policy middleware of the equivalent API endpoints
+func (c *commandRunner) checkAccess(
+	user *models.User,
+	cluster *models.Cluster,
+	cmd *slackCommand,
+	verb types.APIVerb,
+) error {
+	policyDocs, reqErr := policy.NewBasicPolicyDocumentLoader(c.config.Repo.Project()).LoadPolicyDocuments(
+		user.ID,
+		cluster.ProjectID,
+	)
+
+	if reqErr != nil {
+		return fmt.Errorf("You do not have access to this project")
+	}
+
+	hasAccess := policy.HasScopeAccess(policyDocs, map[types.PermissionScope]*types.RequestAction{
+		types.ProjectScope: {
+			Verb:     verb,
+			Resource: types.NameOrUInt{UInt: cluster.ProjectID},
+		},
+		types.ClusterScope: {
+			Verb:     verb,
+			Resource: types.NameOrUInt{UInt: cluster.ID},
+		},
+		types.NamespaceScope: {
+			Verb:     verb,
+			Resource: types.NameOrUInt{Name: cmd.namespace},
+		},
+		types.ReleaseScope: {
+			Verb:     verb,
+			Resource: types.NameOrUInt{Name: cmd.release},
+		},
+	})
+
+	if !hasAccess {
+		return fmt.Errorf("You do not have permission to %s %s", cmd.name, cmd.release)
+	}
+
+	return nil
+}
+
+// releaseMessage returns a message with buttons to run the next command on the same release
+func releaseMessage(responseType, text, args string) *slack.Message {
+	return &slack.Message{
+		ResponseType: responseType,
+		Text:         text,
+		Blocks: []interface{}{
+			&slack.SlackBlock{
+				Type: "section",
+				Text: &slack.SlackText{
+					Type: "mrkdwn",
+					Text: text,
+				},
+			},
+			&slack.ActionsBlock{
+				Type: "actions",
+				Elements: []*slack.Button{
+					slack.NewButton("Status", "status", "status "+args),
+					slack.NewButton("Redeploy", "redeploy", "redeploy "+args),
+					slack.NewButton("Roll back", "rollback", "rollback "+args),
+				},
+			},
+		},
+	}
+}
+
+func ephemeralMessage(text string) *slack.Message {
+	return &slack.Message{
+		ResponseType: "ephemeral",
+		Text:         text,
+	}
+}
@@ -0,0 +1,192 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/porter-dev/porter/api/server/authz"
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type CreateCustomChartHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateCustomChartHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateCustomChartHandler {
+	return &CreateCustomChartHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *CreateCustomChartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateCustomChartRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	customChart := &models.CustomChart{
+		ProjectID:      project.ID,
+		Source:         types.CustomChartSourceUpload,
+		ImageValuesKey: request.ImageValuesKey,
+		Archive:        request.Archive,
+	}
+
+	if customChart.ImageValuesKey == "" {
+		customChart.ImageValuesKey = helm.DefaultImageValuesKey
+	}
+
+	if request.GitRepo != "" {
+		// the installation is read from the request instead of the URL, so the git
+		// installation middleware does not check that the user can access it
+		err := authz.DoesUserHaveGitInstallationAccess(c.Config(), user.GithubAppIntegrationID, request.GitRepoID)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrForbidden(err))
+			return
+		}
+
+		customChart.Source = types.CustomChartSourceGithub
+		customChart.GitRepoID = request.GitRepoID
+		customChart.GitRepo = request.GitRepo
+		customChart.GitBranch = request.GitBranch
+		customChart.ChartPath = request.ChartPath
+
+		if reqErr := archiveGithubChart(c.Config(), customChart); reqErr != nil {
+			c.HandleAPIError(w, r, reqErr)
+			return
+		}
+	}
+
+	chart, err := helm.LoadCustomChart(customChart.Archive, customChart.ImageValuesKey)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest))
+		return
+	}
+
+	customChart.Name = chart.Metadata.Name
+	customChart.Version = chart.Metadata.Version
+	customChart.Description = chart.Metadata.Description
+
+	// versions are immutable, so that releases can be rolled back to the exact chart that
+	// they were deployed with
+	_, err = c.Repo().CustomChart().ReadCustomChartByVersion(project.ID, customChart.Name, customChart.Version)
+
+	if err == nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("version %s of chart %s already exists: bump the version in Chart.yaml", customChart.Version, customChart.Name),
+			http.StatusConflict,
+		))
+
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	customChart, err = c.Repo().CustomChart().CreateCustomChart(customChart)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, customChart.ToCustomChartType())
+}
+
+// archiveGithubChart packages the chart at the path of a Github repo, at the latest commit
+// of the branch of the custom chart
+func archiveGithubChart(config *config.Config, customChart *models.CustomChart) apierrors.RequestError {
+	if config.GithubAppTokens == nil {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("the Github app is not configured"),
+			http.StatusBadRequest,
+		)
+	}
+
+	repoSplit := strings.Split(customChart.GitRepo, "/")
+
+	if len(repoSplit) != 2 {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("invalid formatting of repo name"),
+			http.StatusBadRequest,
+		)
+	}
+
+	owner, name := repoSplit[0], repoSplit[1]
+	client := config.GithubAppTokens.Client(int64(customChart.GitRepoID))
+
+	ref := customChart.GitBranch
+
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	sha, _, err := client.Repositories.GetCommitSHA1(context.Background(), owner, name, ref, "")
+
+	if err != nil {
+		return apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("could not read branch of %s: %s", customChart.GitRepo, err.Error()),
+			http.StatusBadRequest,
+		)
+	}
+
+	archiveURL, _, err := client.Repositories.GetArchiveLink(
+		context.Background(),
+		owner,
+		name,
+		github.Tarball,
+		&github.RepositoryContentGetOptions{
+			Ref: sha,
+		},
+		true,
+	)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	resp, err := http.Get(archiveURL.String())
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apierrors.NewErrInternal(fmt.Errorf("could not download archive of %s: status code %d", customChart.GitRepo, resp.StatusCode))
+	}
+
+	archive, err := helm.ArchiveRepoChart(io.LimitReader(resp.Body, 1<<30), customChart.ChartPath)
+
+	if err != nil {
+		return apierrors.NewErrPassThroughToClient(err, http.StatusBadRequest)
+	}
+
+	customChart.CommitSHA = sha
+	customChart.Archive = archive
+
+	return nil
+}
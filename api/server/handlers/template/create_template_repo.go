@@ -0,0 +1,76 @@
+package template
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type CreateTemplateRepoHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewCreateTemplateRepoHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *CreateTemplateRepoHandler {
+	return &CreateTemplateRepoHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (c *CreateTemplateRepoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	request := &types.CreateTemplateRepoRequest{}
+
+	if ok := c.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	// the repo must serve a valid index with at least one chart
+	repoIndex, err := loader.LoadRepoIndexPublic(request.RepoURL)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("could not load the index of the repo: %s", err.Error()),
+			http.StatusBadRequest,
+		))
+
+		return
+	} else if len(repoIndex.Entries) == 0 {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("the repo does not contain any charts"),
+			http.StatusBadRequest,
+		))
+
+		return
+	}
+
+	templateRepo, err := c.Repo().TemplateRepo().CreateTemplateRepo(&models.TemplateRepo{
+		ProjectID: project.ID,
+		Name:      request.Name,
+		RepoURL:   request.RepoURL,
+	})
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := updateProjectURLCache(c.Config(), project.ID); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	c.WriteResult(w, r, templateRepo.ToTemplateRepoType())
+}
@@ -0,0 +1,66 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+// DeleteCustomChartHandler deletes a version of a custom chart. Releases that were
+// deployed with the version can still be rolled back, since Helm stores the chart of
+// each revision, but they can no longer be upgraded to it.
+type DeleteCustomChartHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewDeleteCustomChartHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DeleteCustomChartHandler {
+	return &DeleteCustomChartHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *DeleteCustomChartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	customChartID, reqErr := requestutils.GetURLParamUint(r, types.URLParamCustomChartID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return
+	}
+
+	customChart, err := c.Repo().CustomChart().ReadCustomChart(project.ID, customChartID)
+
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("custom chart with id %d not found", customChartID),
+			http.StatusNotFound,
+		))
+
+		return
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	customChart, err = c.Repo().CustomChart().DeleteCustomChart(customChart)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, customChart.ToCustomChartType())
+}
@@ -0,0 +1,45 @@
+package template
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+)
+
+type DeleteTemplateRepoHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewDeleteTemplateRepoHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *DeleteTemplateRepoHandler {
+	return &DeleteTemplateRepoHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *DeleteTemplateRepoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	templateRepo, ok := readTemplateRepo(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	templateRepo, err := c.Repo().TemplateRepo().DeleteTemplateRepo(templateRepo)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	if err := updateProjectURLCache(c.Config(), templateRepo.ProjectID); err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	c.WriteResult(w, r, templateRepo.ToTemplateRepoType())
+}
@@ -0,0 +1,65 @@
+package template
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/templater/parser"
+)
+
+// readTemplateForm loads the template in the URL and parses its form, with the fields
+// populated from the values of the template
+func readTemplateForm(c handlers.PorterHandler, w http.ResponseWriter, r *http.Request, repoURL string) (*types.FormYAML, bool) {
+	name, _ := requestutils.GetURLParamString(r, types.URLParamTemplateName)
+	version, _ := requestutils.GetURLParamString(r, types.URLParamTemplateVersion)
+
+	// if version passed as latest, pass empty string to loader to get latest
+	if version == "latest" {
+		version = ""
+	}
+
+	if repoURL == "" {
+		repoURL = c.Config().Settings.Get(types.ServerSettingDefaultApplicationHelmRepoURL)
+	}
+
+	chart, err := loader.LoadChartPublic(repoURL, name, version)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	parserDef := &parser.ClientConfigDefault{
+		HelmChart: chart,
+	}
+
+	for _, file := range chart.Files {
+		if strings.Contains(file.Name, "form.yaml") {
+			form, err := parser.FormYAMLFromBytes(parserDef, file.Data, "declared")
+
+			if err != nil {
+				c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+					fmt.Errorf("form of template %s could not be parsed: %s", name, err.Error()),
+					http.StatusBadRequest,
+				))
+
+				return nil, false
+			}
+
+			return form, true
+		}
+	}
+
+	c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+		fmt.Errorf("template %s does not have a form", name),
+		http.StatusNotFound,
+	))
+
+	return nil, false
+}
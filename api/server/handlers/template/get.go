@@ -46,13 +46,13 @@ func (t *TemplateGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if request.RepoURL == "" {
-		request.RepoURL = t.Config().ServerConf.DefaultApplicationHelmRepoURL
+		request.RepoURL = t.Config().Settings.Get(types.ServerSettingDefaultApplicationHelmRepoURL)
 	}
 
 	chart, err := loader.LoadChartPublic(request.RepoURL, name, version)
 
 	if err != nil {
-		t.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		t.HandleAPIError(w, r, apierrors.WithCode(apierrors.NewErrInternal(err), types.ErrorCodeChartNotFound))
 		return
 	}
 
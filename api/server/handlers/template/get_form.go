@@ -0,0 +1,40 @@
+package template
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+)
+
+type TemplateGetFormHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewTemplateGetFormHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *TemplateGetFormHandler {
+	return &TemplateGetFormHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+func (t *TemplateGetFormHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &types.GetTemplateFormRequest{}
+
+	if ok := t.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	form, ok := readTemplateForm(t.PorterHandlerReadWriter, w, r, request.RepoURL)
+
+	if !ok {
+		return
+	}
+
+	t.WriteResult(w, r, form)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/types"
-	"github.com/porter-dev/porter/internal/helm/loader"
 )
 
 type TemplateListHandler struct {
@@ -37,17 +36,15 @@ func (t *TemplateListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	repoURL := request.RepoURL
 
 	if repoURL == "" {
-		repoURL = t.Config().ServerConf.DefaultApplicationHelmRepoURL
+		repoURL = t.Config().Settings.Get(types.ServerSettingDefaultApplicationHelmRepoURL)
 	}
 
-	repoIndex, err := loader.LoadRepoIndexPublic(repoURL)
+	porterCharts, etag, err := t.Config().URLCache.GetTemplates(repoURL)
 
 	if err != nil {
 		t.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
 	}
 
-	porterCharts := loader.RepoIndexToPorterChartList(repoIndex)
-
-	t.WriteResult(w, r, porterCharts)
+	writeTemplates(t, w, r, porterCharts, etag)
 }
@@ -0,0 +1,44 @@
+package template
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ListCustomChartsHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListCustomChartsHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListCustomChartsHandler {
+	return &ListCustomChartsHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListCustomChartsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	customCharts, err := c.Repo().CustomChart().ListCustomChartsByProjectID(project.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListCustomChartsResponse, 0)
+
+	for _, customChart := range customCharts {
+		res = append(res, customChart.ToCustomChartType())
+	}
+
+	c.WriteResult(w, r, res)
+}
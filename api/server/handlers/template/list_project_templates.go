@@ -0,0 +1,75 @@
+package template
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ListProjectTemplatesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListProjectTemplatesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListProjectTemplatesHandler {
+	return &ListProjectTemplatesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+// ServeHTTP lists the templates of the default application and add-on repos, followed by
+// the templates of the repos registered by the project
+func (c *ListProjectTemplatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	res := make(types.ListTemplatesResponse, 0)
+	etags := make([]string, 0)
+
+	for _, repoURL := range []string{
+		c.Config().Settings.Get(types.ServerSettingDefaultApplicationHelmRepoURL),
+		c.Config().Settings.Get(types.ServerSettingDefaultAddonHelmRepoURL),
+	} {
+		if repoURL == "" {
+			continue
+		}
+
+		templates, etag, err := c.Config().URLCache.GetTemplates(repoURL)
+
+		if err != nil {
+			c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+			return
+		}
+
+		res = append(res, getRepoTemplates(templates, repoURL)...)
+		etags = append(etags, repoURL, etag)
+	}
+
+	templateRepos, err := c.Repo().TemplateRepo().ListTemplateReposByProjectID(project.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	for _, templateRepo := range templateRepos {
+		templates, etag, err := c.Config().URLCache.GetTemplates(templateRepo.RepoURL)
+
+		// a repo registered by the project that is unavailable should not prevent the
+		// other templates from being listed
+		if err != nil {
+			continue
+		}
+
+		res = append(res, getRepoTemplates(templates, templateRepo.RepoURL)...)
+		etags = append(etags, templateRepo.RepoURL, etag)
+	}
+
+	writeTemplates(c, w, r, res, combineETags(etags...))
+}
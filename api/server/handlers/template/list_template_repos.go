@@ -0,0 +1,44 @@
+package template
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type ListTemplateReposHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewListTemplateReposHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) *ListTemplateReposHandler {
+	return &ListTemplateReposHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *ListTemplateReposHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	templateRepos, err := c.Repo().TemplateRepo().ListTemplateReposByProjectID(project.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return
+	}
+
+	res := make(types.ListTemplateReposResponse, 0)
+
+	for _, templateRepo := range templateRepos {
+		res = append(res, templateRepo.ToTemplateRepoType())
+	}
+
+	c.WriteResult(w, r, res)
+}
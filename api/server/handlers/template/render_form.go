@@ -0,0 +1,49 @@
+package template
+
+import (
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/templater/parser"
+)
+
+type TemplateRenderFormHandler struct {
+	handlers.PorterHandlerReadWriter
+}
+
+func NewTemplateRenderFormHandler(
+	config *config.Config,
+	decoderValidator shared.RequestDecoderValidator,
+	writer shared.ResultWriter,
+) *TemplateRenderFormHandler {
+	return &TemplateRenderFormHandler{
+		PorterHandlerReadWriter: handlers.NewDefaultPorterHandler(config, decoderValidator, writer),
+	}
+}
+
+// ServeHTTP validates form data submitted for the form of a template and converts it to
+// values. Validation errors are returned in the response instead of as an API error, so
+// that every invalid field is reported.
+func (t *TemplateRenderFormHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &types.RenderTemplateFormRequest{}
+
+	if ok := t.DecodeAndValidate(w, r, request); !ok {
+		return
+	}
+
+	form, ok := readTemplateForm(t.PorterHandlerReadWriter, w, r, request.RepoURL)
+
+	if !ok {
+		return
+	}
+
+	values, errs := parser.FormDataToValues(form, request.Data)
+
+	t.WriteResult(w, r, &types.RenderTemplateFormResponse{
+		Values: values,
+		Errors: errs,
+	})
+}
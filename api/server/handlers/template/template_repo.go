@@ -0,0 +1,107 @@
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/server/shared/requestutils"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func readTemplateRepo(c handlers.PorterHandler, w http.ResponseWriter, r *http.Request) (*models.TemplateRepo, bool) {
+	project, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	templateRepoID, reqErr := requestutils.GetURLParamUint(r, types.URLParamTemplateRepoID)
+
+	if reqErr != nil {
+		c.HandleAPIError(w, r, reqErr)
+		return nil, false
+	}
+
+	templateRepo, err := c.Repo().TemplateRepo().ReadTemplateRepo(project.ID, templateRepoID)
+
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("template repo with id %d not found", templateRepoID),
+			http.StatusNotFound,
+		))
+
+		return nil, false
+	} else if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	return templateRepo, true
+}
+
+// updateProjectURLCache reloads the charts of the template repos of a project into the
+// URL cache, so that releases of those charts can be upgraded
+func updateProjectURLCache(config *config.Config, projectID uint) error {
+	templateRepos, err := config.Repo.TemplateRepo().ListTemplateReposByProjectID(projectID)
+
+	if err != nil {
+		return err
+	}
+
+	urls := make([]string, 0)
+
+	for _, templateRepo := range templateRepos {
+		urls = append(urls, templateRepo.RepoURL)
+	}
+
+	config.URLCache.UpdateProject(projectID, urls...)
+
+	return nil
+}
+
+// getRepoTemplates sets the repo of the templates of a repo, which are listed along with
+// their icons, metadata and versions
+func getRepoTemplates(res types.ListTemplatesResponse, repoURL string) types.ListTemplatesResponse {
+	for i := range res {
+		res[i].RepoURL = repoURL
+	}
+
+	return res
+}
+
+// writeTemplates writes a list of templates with their ETag, or responds with 304 Not
+// Modified if the client already has the templates with the same ETag
+func writeTemplates(
+	c handlers.PorterHandlerWriter,
+	w http.ResponseWriter,
+	r *http.Request,
+	templates types.ListTemplatesResponse,
+	etag string,
+) {
+	etag = fmt.Sprintf("%q", etag)
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	for _, match := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if strings.TrimPrefix(strings.TrimSpace(match), "W/") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	c.WriteResult(w, r, templates)
+}
+
+// combineETags returns the ETag of a list of templates that are concatenated from
+// several repos
+func combineETags(etags ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(etags, ",")))
+
+	return hex.EncodeToString(hash[:16])
+}
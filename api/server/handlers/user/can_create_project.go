@@ -27,7 +27,7 @@ func NewCanCreateProjectHandler(
 }
 
 func (c *CanCreateProject) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if c.Config().ServerConf.DisableAllowlist {
+	if c.Config().Settings.GetBool(types.ServerSettingDisableAllowlist) {
 		c.WriteResult(w, r, "")
 		return
 	}
@@ -66,7 +66,8 @@ func TestCreateUserBadEmail(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 
 	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
-		Error: fmt.Sprintf("validation failed on field 'Email' on condition 'email'"),
+		Error:     fmt.Sprintf("validation failed on field 'Email' on condition 'email'"),
+		ErrorCode: types.ErrorCodeValidationFailed,
 	})
 }
 
@@ -91,7 +92,8 @@ func TestCreateUserMissingField(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 
 	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
-		Error: fmt.Sprintf("validation failed on field 'Password' on condition 'required'"),
+		Error:     fmt.Sprintf("validation failed on field 'Password' on condition 'required'"),
+		ErrorCode: types.ErrorCodeValidationFailed,
 	})
 }
 
@@ -120,7 +122,8 @@ func TestCreateUserSameEmail(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 
 	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
-		Error: fmt.Sprintf("email already taken"),
+		Error:     fmt.Sprintf("email already taken"),
+		ErrorCode: types.ErrorCodeBadRequest,
 	})
 }
 
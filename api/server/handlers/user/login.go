@@ -57,7 +57,10 @@ func (u *UserLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(storedUser.Password), []byte(request.Password)); err != nil {
-		reqErr := apierrors.NewErrPassThroughToClient(fmt.Errorf("incorrect password"), http.StatusUnauthorized)
+		reqErr := apierrors.WithCode(
+			apierrors.NewErrPassThroughToClient(fmt.Errorf("incorrect password"), http.StatusUnauthorized),
+			types.ErrorCodeInvalidCredentials,
+		)
 		u.HandleAPIError(w, r, reqErr)
 		return
 	}
@@ -68,7 +68,8 @@ func TestLoginUserIncorrectPassword(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 
 	apitest.AssertResponseError(t, rr, http.StatusUnauthorized, &types.ExternalError{
-		Error: fmt.Sprintf("incorrect password"),
+		Error:     fmt.Sprintf("incorrect password"),
+		ErrorCode: types.ErrorCodeInvalidCredentials,
 	})
 }
 
@@ -95,7 +96,8 @@ func TestLoginUserBadEmail(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 
 	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
-		Error: fmt.Sprintf("validation failed on field 'Email' on condition 'email'"),
+		Error:     fmt.Sprintf("validation failed on field 'Email' on condition 'email'"),
+		ErrorCode: types.ErrorCodeValidationFailed,
 	})
 }
 
@@ -122,7 +124,8 @@ func TestLoginUserEmptyPassword(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 
 	apitest.AssertResponseError(t, rr, http.StatusBadRequest, &types.ExternalError{
-		Error: fmt.Sprintf("validation failed on field 'Password' on condition 'required'"),
+		Error:     fmt.Sprintf("validation failed on field 'Password' on condition 'required'"),
+		ErrorCode: types.ErrorCodeValidationFailed,
 	})
 }
 
@@ -0,0 +1,416 @@
+package router
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/porter-dev/porter/api/server/handlers/admin"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+)
+
+func NewAdminRegisterer(children ...*Registerer) *Registerer {
+	return &Registerer{
+		GetRoutes: GetAdminRoutes,
+		Children:  children,
+	}
+}
+
+func GetAdminRoutes(
+	r chi.Router,
+	config *config.Config,
+	basePath *types.Path,
+	factory shared.APIEndpointFactory,
+	children ...*Registerer,
+) []*Route {
+	relPath := "/admin"
+
+	routes := make([]*Route, 0)
+
+	// GET /api/admin/projects -> admin.NewListProjectsHandler
+	listProjectsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/projects",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	listProjectsHandler := admin.NewListProjectsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listProjectsEndpoint,
+		Handler:  listProjectsHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/users -> admin.NewListUsersHandler
+	listUsersEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/users",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	listUsersHandler := admin.NewListUsersHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listUsersEndpoint,
+		Handler:  listUsersHandler,
+		Router:   r,
+	})
+
+	// POST /api/admin/users/{user_id} -> admin.NewUpdateUserHandler
+	updateUserEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/users/{user_id}",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	updateUserHandler := admin.NewUpdateUserHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: updateUserEndpoint,
+		Handler:  updateUserHandler,
+		Router:   r,
+	})
+
+	// POST /api/admin/users/{user_id}/impersonate -> admin.NewImpersonateUserHandler
+	impersonateEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/users/{user_id}/impersonate",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	impersonateHandler := admin.NewImpersonateUserHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: impersonateEndpoint,
+		Handler:  impersonateHandler,
+		Router:   r,
+	})
+
+	// the impersonated user stops the impersonation, so the endpoint is not restricted to
+	// instance admins
+	// POST /api/admin/impersonate/stop -> admin.NewStopImpersonationHandler
+	stopImpersonationEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/impersonate/stop",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+			},
+		},
+	)
+
+	stopImpersonationHandler := admin.NewStopImpersonationHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: stopImpersonationEndpoint,
+		Handler:  stopImpersonationHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/quotas -> admin.NewGetQuotaHandler
+	getQuotaEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/quotas",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	getQuotaHandler := admin.NewGetQuotaHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: getQuotaEndpoint,
+		Handler:  getQuotaHandler,
+		Router:   r,
+	})
+
+	// POST /api/admin/quotas -> admin.NewUpdateQuotaHandler
+	updateQuotaEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/quotas",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	updateQuotaHandler := admin.NewUpdateQuotaHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: updateQuotaEndpoint,
+		Handler:  updateQuotaHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/whitelisted_users -> admin.NewListWhitelistedUsersHandler
+	listWhitelistedEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/whitelisted_users",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	listWhitelistedHandler := admin.NewListWhitelistedUsersHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listWhitelistedEndpoint,
+		Handler:  listWhitelistedHandler,
+		Router:   r,
+	})
+
+	// POST /api/admin/whitelisted_users -> admin.NewWhitelistUserHandler
+	whitelistUserEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/whitelisted_users",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	whitelistUserHandler := admin.NewWhitelistUserHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: whitelistUserEndpoint,
+		Handler:  whitelistUserHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/admin/whitelisted_users/{user_id} -> admin.NewRemoveWhitelistedUserHandler
+	removeWhitelistedEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/whitelisted_users/{user_id}",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	removeWhitelistedHandler := admin.NewRemoveWhitelistedUserHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: removeWhitelistedEndpoint,
+		Handler:  removeWhitelistedHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/audit_logs -> admin.NewListAuditLogsHandler
+	listAuditLogsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/audit_logs",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	listAuditLogsHandler := admin.NewListAuditLogsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listAuditLogsEndpoint,
+		Handler:  listAuditLogsHandler,
+		Router:   r,
+	})
+
+	// GET /api/admin/settings -> admin.NewListSettingsHandler
+	listSettingsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/settings",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	listSettingsHandler := admin.NewListSettingsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listSettingsEndpoint,
+		Handler:  listSettingsHandler,
+		Router:   r,
+	})
+
+	// POST /api/admin/settings/{key} -> admin.NewUpdateSettingHandler
+	updateSettingEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/settings/{key}",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	updateSettingHandler := admin.NewUpdateSettingHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: updateSettingEndpoint,
+		Handler:  updateSettingHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/admin/settings/{key} -> admin.NewResetSettingHandler
+	resetSettingEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/settings/{key}",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.InstanceAdminScope,
+			},
+		},
+	)
+
+	resetSettingHandler := admin.NewResetSettingHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: resetSettingEndpoint,
+		Handler:  resetSettingHandler,
+		Router:   r,
+	})
+
+	return routes
+}
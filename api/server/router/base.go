@@ -6,8 +6,10 @@ import (
 	"github.com/porter-dev/porter/api/server/handlers/credentials"
 	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
 	"github.com/porter-dev/porter/api/server/handlers/healthcheck"
+	"github.com/porter-dev/porter/api/server/handlers/helmmirror"
 	"github.com/porter-dev/porter/api/server/handlers/metadata"
 	"github.com/porter-dev/porter/api/server/handlers/release"
+	"github.com/porter-dev/porter/api/server/handlers/slack_integration"
 	"github.com/porter-dev/porter/api/server/handlers/user"
 	"github.com/porter-dev/porter/api/server/shared"
 	"github.com/porter-dev/porter/api/server/shared/config"
@@ -339,6 +341,27 @@ func GetBaseRoutes(
 		Router:   r,
 	})
 
+	// GET /api/helm_mirror/{mirror_repo}/{mirror_file} -> helmmirror.NewGetMirrorFileHandler
+	getMirrorFileEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/helm_mirror/{mirror_repo}/{mirror_file}",
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	getMirrorFileHandler := helmmirror.NewGetMirrorFileHandler(config)
+
+	routes = append(routes, &Route{
+		Endpoint: getMirrorFileEndpoint,
+		Handler:  getMirrorFileHandler,
+		Router:   r,
+	})
+
 	//  GET /api/integrations/github-app/install
 	githubAppInstallEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -387,6 +410,56 @@ func GetBaseRoutes(
 		Router:   r,
 	})
 
+	//  POST /api/integrations/slack/commands -> slack_integration.NewSlackCommandHandler
+	slackCommandEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/integrations/slack/commands",
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	slackCommandHandler := slack_integration.NewSlackCommandHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: slackCommandEndpoint,
+		Handler:  slackCommandHandler,
+		Router:   r,
+	})
+
+	//  POST /api/integrations/slack/interactions -> slack_integration.NewSlackInteractionHandler
+	slackInteractionEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: "/integrations/slack/interactions",
+			},
+			Scopes: []types.PermissionScope{},
+		},
+	)
+
+	slackInteractionHandler := slack_integration.NewSlackInteractionHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: slackInteractionEndpoint,
+		Handler:  slackInteractionHandler,
+		Router:   r,
+	})
+
 	// GET /api/oauth/login/github
 	githubLoginStartEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/freeze"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+// DeployFreezeMiddleware blocks deploys and infra changes during the deploy freezes of
+// the project, or of the cluster of the request
+type DeployFreezeMiddleware struct {
+	config *config.Config
+}
+
+func NewDeployFreezeMiddleware(config *config.Config) *DeployFreezeMiddleware {
+	return &DeployFreezeMiddleware{config}
+}
+
+func (d *DeployFreezeMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+		user, _ := r.Context().Value(types.UserScope).(*models.User)
+
+		var clusterID uint
+
+		if cluster, ok := r.Context().Value(types.ClusterScope).(*models.Cluster); ok {
+			clusterID = cluster.ID
+		}
+
+		if reqErr := CheckDeployFreeze(d.config, r, proj.ID, clusterID, user); reqErr != nil {
+			apierrors.HandleAPIError(d.config, w, r, reqErr, true)
+			return
+		}
+
+		// handlers that deploy through the shared deploy path check freezes as well, which
+		// is skipped for requests that passed this middleware so that overrides are only
+		// recorded once
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), DeployFreezeCheckedCtxKey, clusterID)))
+	})
+}
+
+// DeployFreezeCheckedCtxKey is set to the cluster ID on requests that passed the deploy
+// freeze middleware, or to 0 if the request is not scoped to a cluster
+const DeployFreezeCheckedCtxKey string = "deploy-freeze-checked"
+
+// IsDeployFreezeChecked returns true if the deploy freezes of a cluster were already
+// checked for a request by the deploy freeze middleware
+func IsDeployFreezeChecked(r *http.Request, clusterID uint) bool {
+	checked, ok := r.Context().Value(DeployFreezeCheckedCtxKey).(uint)
+
+	return ok && checked == clusterID
+}
+
+// CheckDeployFreeze returns an error if a deploy freeze of the project or cluster is
+// active, for handlers that are not scoped to a project such as deploy webhooks. Project
+// admins can override a freeze by setting the reason in the override header, and the
+// override is recorded. Requests without a user cannot override freezes.
+func CheckDeployFreeze(
+	config *config.Config,
+	r *http.Request,
+	projectID, clusterID uint,
+	user *models.User,
+) apierrors.RequestError {
+	freezes, err := config.Repo.DeployFreeze().ListDeployFreezesByProjectID(projectID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	active, end, err := freeze.FindActive(freezes, clusterID, time.Now())
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if active == nil {
+		return nil
+	}
+
+	isAdmin := false
+
+	if user != nil {
+		role, err := config.Repo.Project().ReadProjectRole(projectID, user.ID)
+
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return apierrors.NewErrInternal(err)
+		}
+
+		isAdmin = err == nil && role.Kind == types.RoleAdmin
+	}
+
+	reason := strings.TrimSpace(r.Header.Get(types.DeployFreezeOverrideHeader))
+
+	if isAdmin && reason != "" {
+		_, err := config.Repo.DeployFreeze().CreateDeployFreezeOverride(&models.DeployFreezeOverride{
+			ProjectID:      projectID,
+			ClusterID:      clusterID,
+			DeployFreezeID: active.ID,
+			UserID:         user.ID,
+			Method:         r.Method,
+			Path:           r.URL.Path,
+			Reason:         reason,
+		})
+
+		// overrides that cannot be recorded are not allowed, since every override must
+		// be audited
+		if err != nil {
+			return apierrors.NewErrInternal(fmt.Errorf("could not record deploy freeze override: %w", err))
+		}
+
+		return nil
+	}
+
+	msg := fmt.Sprintf(
+		"deploys and infra changes are frozen by deploy freeze \"%s\" until %s",
+		active.Name,
+		end.UTC().Format(time.RFC3339),
+	)
+
+	if isAdmin {
+		msg = fmt.Sprintf("%s; project admins can override the freeze by setting a reason in the %s header", msg, types.DeployFreezeOverrideHeader)
+	}
+
+	return apierrors.WithCode(
+		apierrors.NewErrPassThroughToClient(errors.New(msg), http.StatusForbidden),
+		types.ErrorCodeDeployFrozen,
+	)
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type EntitlementMiddleware struct {
+	config      *config.Config
+	entitlement types.Entitlement
+}
+
+func NewEntitlementMiddleware(config *config.Config, entitlement types.Entitlement) *EntitlementMiddleware {
+	return &EntitlementMiddleware{config, entitlement}
+}
+
+var EntitlementErrFmt = "the plan of project %d does not include %s"
+
+func (e *EntitlementMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+		if reqErr := CheckEntitlement(e.config, proj, e.entitlement); reqErr != nil {
+			apierrors.HandleAPIError(e.config, w, r, reqErr, true)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CheckEntitlement returns an error if the plan of the project does not include the
+// entitlement, for handlers that only require an entitlement for some requests
+func CheckEntitlement(config *config.Config, proj *models.Project, entitlement types.Entitlement) apierrors.RequestError {
+	allowed, err := config.EntitlementManager.HasEntitlement(proj, entitlement)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if !allowed {
+		return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf(EntitlementErrFmt, proj.ID, entitlement),
+			http.StatusPaymentRequired,
+		), types.ErrorCodeEntitlementRequired)
+	}
+
+	return nil
+}
+
+// ClusterEntitlementMiddleware blocks the creation of clusters once a project has the
+// maximum number of clusters that its plan or license includes
+type ClusterEntitlementMiddleware struct {
+	config *config.Config
+}
+
+func NewClusterEntitlementMiddleware(config *config.Config) *ClusterEntitlementMiddleware {
+	return &ClusterEntitlementMiddleware{config}
+}
+
+var ClusterEntitlementErrFmt = "the plan of project %d includes at most %d clusters"
+
+func (e *ClusterEntitlementMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+		if reqErr := CheckClusterEntitlement(e.config, proj); reqErr != nil {
+			apierrors.HandleAPIError(e.config, w, r, reqErr, true)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CheckClusterEntitlement returns an error if the project cannot add another cluster
+// under the maximum number of clusters of its plan
+func CheckClusterEntitlement(config *config.Config, proj *models.Project) apierrors.RequestError {
+	entitlements, err := config.EntitlementManager.GetEntitlements(proj)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if entitlements.MaxClusters == 0 {
+		return nil
+	}
+
+	clusters, err := config.Repo.Cluster().ListClustersByProjectID(proj.ID)
+
+	if err != nil {
+		return apierrors.NewErrInternal(err)
+	}
+
+	if uint(len(clusters)) >= entitlements.MaxClusters {
+		return apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+			fmt.Errorf(ClusterEntitlementErrFmt, proj.ID, entitlements.MaxClusters),
+			http.StatusPaymentRequired,
+		), types.ErrorCodeEntitlementRequired)
+	}
+
+	return nil
+}
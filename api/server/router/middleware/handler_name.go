@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// HandlerNameMiddleware stores the name of the handler of a route in the request context,
+// so that the logs and alerts of errors of the request are tagged with the handler
+type HandlerNameMiddleware struct {
+	name string
+}
+
+// NewHandlerNameMiddleware returns a middleware for the given handler, whose name is
+// its type without the pointer, such as release.CreateReleaseHandler
+func NewHandlerNameMiddleware(handler http.Handler) *HandlerNameMiddleware {
+	return &HandlerNameMiddleware{
+		name: strings.TrimPrefix(fmt.Sprintf("%T", handler), "*"),
+	}
+}
+
+func (h *HandlerNameMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), types.HandlerNameCtxKey, h.name)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
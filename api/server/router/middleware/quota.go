@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// QuotaMiddleware enforces the instance quotas that instance admins set, independently
+// of the usage limits of billing plans
+type QuotaMiddleware struct {
+	config *config.Config
+	metric types.UsageMetric
+}
+
+func NewQuotaMiddleware(config *config.Config, metric types.UsageMetric) *QuotaMiddleware {
+	return &QuotaMiddleware{config, metric}
+}
+
+var QuotaErrFmt = "instance quota reached for metric %s: quota %d, current %d"
+
+func (q *QuotaMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+		quota, err := q.config.Repo.InstanceAdmin().ReadInstanceQuota()
+
+		if err != nil {
+			apierrors.HandleAPIError(q.config, w, r, apierrors.NewErrInternal(err), true)
+			return
+		}
+
+		var max, curr uint
+
+		switch q.metric {
+		case types.Clusters:
+			clusters, err := q.config.Repo.Cluster().ListClustersByProjectID(proj.ID)
+
+			if err != nil {
+				apierrors.HandleAPIError(q.config, w, r, apierrors.NewErrInternal(err), true)
+				return
+			}
+
+			max, curr = quota.MaxClustersPerProject, uint(len(clusters))
+		case types.Users:
+			roles, err := q.config.Repo.Project().ListProjectRoles(proj.ID)
+
+			if err != nil {
+				apierrors.HandleAPIError(q.config, w, r, apierrors.NewErrInternal(err), true)
+				return
+			}
+
+			max, curr = quota.MaxUsersPerProject, uint(len(roles))
+		}
+
+		if max != 0 && curr+1 > max {
+			apierrors.HandleAPIError(
+				q.config,
+				w, r,
+				apierrors.WithCode(apierrors.NewErrPassThroughToClient(
+					fmt.Errorf(QuotaErrFmt, q.metric, max, curr),
+					http.StatusBadRequest,
+				), types.ErrorCodeQuotaExceeded),
+				true,
+			)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
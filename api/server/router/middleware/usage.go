@@ -31,7 +31,8 @@ func (b *UsageMiddleware) Middleware(next http.Handler) http.Handler {
 			Project:          proj,
 			DOConf:           b.config.DOConf,
 			Repo:             b.config.Repo,
-			WhitelistedUsers: b.config.WhitelistedUsers,
+			WhitelistedUsers: b.config.WhitelistedUsers.Map(),
+			Inventory:        b.config.Inventory,
 		})
 
 		if err != nil {
@@ -56,10 +57,10 @@ func (b *UsageMiddleware) Middleware(next http.Handler) http.Handler {
 			apierrors.HandleAPIError(
 				b.config,
 				w, r,
-				apierrors.NewErrPassThroughToClient(
+				apierrors.WithCode(apierrors.NewErrPassThroughToClient(
 					fmt.Errorf(UsageErrFmt, b.metric, limit, curr),
 					http.StatusBadRequest,
-				),
+				), types.ErrorCodeUsageLimitExceeded),
 				true,
 			)
 		}
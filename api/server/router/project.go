@@ -5,6 +5,8 @@ import (
 	"github.com/porter-dev/porter/api/server/handlers/billing"
 	"github.com/porter-dev/porter/api/server/handlers/cluster"
 	"github.com/porter-dev/porter/api/server/handlers/gitinstallation"
+	"github.com/porter-dev/porter/api/server/handlers/gitops"
+	"github.com/porter-dev/porter/api/server/handlers/helmrepo"
 	"github.com/porter-dev/porter/api/server/handlers/project"
 	"github.com/porter-dev/porter/api/server/handlers/provision"
 	"github.com/porter-dev/porter/api/server/handlers/registry"
@@ -138,6 +140,704 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/default_values -> project.NewGetProjectDefaultValuesHandler
+	getProjectDefaultValuesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/default_values",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getProjectDefaultValuesHandler := project.NewGetProjectDefaultValuesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: getProjectDefaultValuesEndpoint,
+		Handler:  getProjectDefaultValuesHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/default_values -> project.NewUpdateProjectDefaultValuesHandler
+	updateProjectDefaultValuesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/default_values",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateProjectDefaultValuesHandler := project.NewUpdateProjectDefaultValuesHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: updateProjectDefaultValuesEndpoint,
+		Handler:  updateProjectDefaultValuesHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/gitops -> gitops.NewGetGitOpsConfigHandler
+	getGitOpsConfigEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/gitops",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getGitOpsConfigHandler := gitops.NewGetGitOpsConfigHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: getGitOpsConfigEndpoint,
+		Handler:  getGitOpsConfigHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/gitops -> gitops.NewDeleteGitOpsConfigHandler
+	deleteGitOpsConfigEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/gitops",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	deleteGitOpsConfigHandler := gitops.NewDeleteGitOpsConfigHandler(
+		config,
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: deleteGitOpsConfigEndpoint,
+		Handler:  deleteGitOpsConfigHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/helmrepos -> helmrepo.NewHelmRepoListHandler
+	listHelmReposEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/helmrepos",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listHelmReposHandler := helmrepo.NewHelmRepoListHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listHelmReposEndpoint,
+		Handler:  listHelmReposHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/helmrepos -> helmrepo.NewHelmRepoCreateHandler
+	createHelmRepoEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/helmrepos",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	createHelmRepoHandler := helmrepo.NewHelmRepoCreateHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: createHelmRepoEndpoint,
+		Handler:  createHelmRepoHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/allowed_charts -> project.NewListAllowedChartsHandler
+	listAllowedChartsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/allowed_charts",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listAllowedChartsHandler := project.NewListAllowedChartsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listAllowedChartsEndpoint,
+		Handler:  listAllowedChartsHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/allowed_charts -> project.NewCreateAllowedChartHandler
+	createAllowedChartEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/allowed_charts",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	createAllowedChartHandler := project.NewCreateAllowedChartHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: createAllowedChartEndpoint,
+		Handler:  createAllowedChartHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/allowed_charts/{allowed_chart_id} -> project.NewDeleteAllowedChartHandler
+	deleteAllowedChartEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/allowed_charts/{allowed_chart_id}",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	deleteAllowedChartHandler := project.NewDeleteAllowedChartHandler(
+		config,
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: deleteAllowedChartEndpoint,
+		Handler:  deleteAllowedChartHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/manifest_policies -> project.NewListManifestPoliciesHandler
+	listManifestPoliciesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/manifest_policies",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listManifestPoliciesHandler := project.NewListManifestPoliciesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listManifestPoliciesEndpoint,
+		Handler:  listManifestPoliciesHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/manifest_policies -> project.NewCreateManifestPolicyHandler
+	createManifestPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/manifest_policies",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	createManifestPolicyHandler := project.NewCreateManifestPolicyHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: createManifestPolicyEndpoint,
+		Handler:  createManifestPolicyHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/manifest_policies/{manifest_policy_id} -> project.NewUpdateManifestPolicyHandler
+	updateManifestPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/manifest_policies/{manifest_policy_id}",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateManifestPolicyHandler := project.NewUpdateManifestPolicyHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: updateManifestPolicyEndpoint,
+		Handler:  updateManifestPolicyHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/manifest_policies/{manifest_policy_id} -> project.NewDeleteManifestPolicyHandler
+	deleteManifestPolicyEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/manifest_policies/{manifest_policy_id}",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	deleteManifestPolicyHandler := project.NewDeleteManifestPolicyHandler(
+		config,
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: deleteManifestPolicyEndpoint,
+		Handler:  deleteManifestPolicyHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/image_signing_authorities -> project.NewListImageSigningAuthoritiesHandler
+	listImageSigningAuthoritiesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/image_signing_authorities",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listImageSigningAuthoritiesHandler := project.NewListImageSigningAuthoritiesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listImageSigningAuthoritiesEndpoint,
+		Handler:  listImageSigningAuthoritiesHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/image_signing_authorities -> project.NewCreateImageSigningAuthorityHandler
+	createImageSigningAuthorityEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/image_signing_authorities",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	createImageSigningAuthorityHandler := project.NewCreateImageSigningAuthorityHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: createImageSigningAuthorityEndpoint,
+		Handler:  createImageSigningAuthorityHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/image_signing_authorities/{image_signing_authority_id} -> project.NewDeleteImageSigningAuthorityHandler
+	deleteImageSigningAuthorityEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/image_signing_authorities/{image_signing_authority_id}",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	deleteImageSigningAuthorityHandler := project.NewDeleteImageSigningAuthorityHandler(
+		config,
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: deleteImageSigningAuthorityEndpoint,
+		Handler:  deleteImageSigningAuthorityHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/sbom_packages -> project.NewListSBOMPackagesHandler
+	listSBOMPackagesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/sbom_packages",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listSBOMPackagesHandler := project.NewListSBOMPackagesHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listSBOMPackagesEndpoint,
+		Handler:  listSBOMPackagesHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/deploy_freezes -> project.NewListDeployFreezesHandler
+	listDeployFreezesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/deploy_freezes",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listDeployFreezesHandler := project.NewListDeployFreezesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listDeployFreezesEndpoint,
+		Handler:  listDeployFreezesHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/deploy_freezes -> project.NewCreateDeployFreezeHandler
+	createDeployFreezeEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/deploy_freezes",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	createDeployFreezeHandler := project.NewCreateDeployFreezeHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: createDeployFreezeEndpoint,
+		Handler:  createDeployFreezeHandler,
+		Router:   r,
+	})
+
+	// DELETE /api/projects/{project_id}/deploy_freezes/{deploy_freeze_id} -> project.NewDeleteDeployFreezeHandler
+	deleteDeployFreezeEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbDelete,
+			Method: types.HTTPVerbDelete,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/deploy_freezes/{deploy_freeze_id}",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	deleteDeployFreezeHandler := project.NewDeleteDeployFreezeHandler(
+		config,
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: deleteDeployFreezeEndpoint,
+		Handler:  deleteDeployFreezeHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/deploy_freezes/overrides -> project.NewListDeployFreezeOverridesHandler
+	listDeployFreezeOverridesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/deploy_freezes/overrides",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listDeployFreezeOverridesHandler := project.NewListDeployFreezeOverridesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listDeployFreezeOverridesEndpoint,
+		Handler:  listDeployFreezeOverridesHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/cli_version -> project.NewUpdateProjectCLIVersionHandler
+	updateProjectCLIVersionEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/cli_version",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateProjectCLIVersionHandler := project.NewUpdateProjectCLIVersionHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: updateProjectCLIVersionEndpoint,
+		Handler:  updateProjectCLIVersionHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/allowed_registries -> project.NewUpdateProjectAllowedRegistriesHandler
+	updateProjectAllowedRegistriesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/allowed_registries",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateProjectAllowedRegistriesHandler := project.NewUpdateProjectAllowedRegistriesHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: updateProjectAllowedRegistriesEndpoint,
+		Handler:  updateProjectAllowedRegistriesHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/sso -> project.NewUpdateProjectSSOHandler
+	updateProjectSSOEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/sso",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateProjectSSOHandler := project.NewUpdateProjectSSOHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: updateProjectSSOEndpoint,
+		Handler:  updateProjectSSOHandler,
+		Router:   r,
+	})
+
+	// POST /api/projects/{project_id}/image_signing -> project.NewUpdateProjectImageSigningHandler
+	updateProjectImageSigningEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbUpdate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/image_signing",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	updateProjectImageSigningHandler := project.NewUpdateProjectImageSigningHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: updateProjectImageSigningEndpoint,
+		Handler:  updateProjectImageSigningHandler,
+		Router:   r,
+	})
+
 	// GET /api/projects/{project_id}/onboarding -> project.NewProjectGetOnboardingHandler
 	getOnboardingEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -221,6 +921,115 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/inventory -> project.NewProjectGetInventoryHandler
+	getInventoryEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/inventory",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getInventoryHandler := project.NewProjectGetInventoryHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: getInventoryEndpoint,
+		Handler:  getInventoryHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/stale_releases -> project.NewListStaleReleasesHandler
+	listStaleReleasesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/stale_releases",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listStaleReleasesHandler := project.NewListStaleReleasesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listStaleReleasesEndpoint,
+		Handler:  listStaleReleasesHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/entitlements -> project.NewProjectGetEntitlementsHandler
+	getEntitlementsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/entitlements",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	getEntitlementsHandler := project.NewProjectGetEntitlementsHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: getEntitlementsEndpoint,
+		Handler:  getEntitlementsHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/api_errors -> project.NewProjectListAPIErrorsHandler
+	listAPIErrorsEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbList,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/api_errors",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	listAPIErrorsHandler := project.NewProjectListAPIErrorsHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listAPIErrorsEndpoint,
+		Handler:  listAPIErrorsHandler,
+		Router:   r,
+	})
+
 	// GET /api/projects/{project_id}/billing -> project.NewProjectGetBillingHandler
 	getBillingEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -277,6 +1086,90 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	// GET /api/projects/{project_id}/billing/invoices/upcoming -> billing.NewBillingGetUpcomingInvoiceHandler
+	getUpcomingInvoiceEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/billing/invoices/upcoming",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	getUpcomingInvoiceHandler := billing.NewBillingGetUpcomingInvoiceHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: getUpcomingInvoiceEndpoint,
+		Handler:  getUpcomingInvoiceHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/billing/invoices -> billing.NewBillingListInvoicesHandler
+	listInvoicesEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/billing/invoices",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	listInvoicesHandler := billing.NewBillingListInvoicesHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: listInvoicesEndpoint,
+		Handler:  listInvoicesHandler,
+		Router:   r,
+	})
+
+	// GET /api/projects/{project_id}/billing/payment_method -> billing.NewBillingGetPaymentMethodHandler
+	getPaymentMethodEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/billing/payment_method",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+				types.SettingsScope,
+			},
+		},
+	)
+
+	getPaymentMethodHandler := billing.NewBillingGetPaymentMethodHandler(
+		config,
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: getPaymentMethodEndpoint,
+		Handler:  getPaymentMethodHandler,
+		Router:   r,
+	})
+
 	// GET /api/billing_webhook -> billing.NewBillingWebhookHandler
 	getBillingWebhookEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -632,6 +1525,62 @@ func getProjectRoutes(
 		Router:   r,
 	})
 
+	//  POST /api/projects/{project_id}/provision/preflight -> provision.NewPreflightHandler
+	preflightEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/provision/preflight",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	preflightHandler := provision.NewPreflightHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: preflightEndpoint,
+		Handler:  preflightHandler,
+		Router:   r,
+	})
+
+	//  POST /api/projects/{project_id}/provision/estimate -> provision.NewEstimateCostHandler
+	estimateCostEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbCreate,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent:       basePath,
+				RelativePath: relPath + "/provision/estimate",
+			},
+			Scopes: []types.PermissionScope{
+				types.UserScope,
+				types.ProjectScope,
+			},
+		},
+	)
+
+	estimateCostHandler := provision.NewEstimateCostHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: estimateCostEndpoint,
+		Handler:  estimateCostHandler,
+		Router:   r,
+	})
+
 	//  POST /api/projects/{project_id}/provision/ecr -> provision.NewProvisionECRHandler
 	provisionECREndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -645,6 +1594,7 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			CheckDeployFreeze: true,
 		},
 	)
 
@@ -673,8 +1623,9 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
-			CheckUsage:  true,
-			UsageMetric: types.Clusters,
+			CheckDeployFreeze: true,
+			CheckUsage:        true,
+			UsageMetric:       types.Clusters,
 		},
 	)
 
@@ -703,6 +1654,7 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			CheckDeployFreeze: true,
 		},
 	)
 
@@ -731,8 +1683,9 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
-			CheckUsage:  true,
-			UsageMetric: types.Clusters,
+			CheckDeployFreeze: true,
+			CheckUsage:        true,
+			UsageMetric:       types.Clusters,
 		},
 	)
 
@@ -761,6 +1714,7 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
+			CheckDeployFreeze: true,
 		},
 	)
 
@@ -789,8 +1743,9 @@ func getProjectRoutes(
 				types.UserScope,
 				types.ProjectScope,
 			},
-			CheckUsage:  true,
-			UsageMetric: types.Clusters,
+			CheckDeployFreeze: true,
+			CheckUsage:        true,
+			UsageMetric:       types.Clusters,
 		},
 	)
 
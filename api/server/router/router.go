@@ -24,6 +24,7 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 
 	baseRegisterer := NewBaseRegisterer()
 	oauthCallbackRegisterer := NewOAuthCallbackRegisterer()
+	adminRegisterer := NewAdminRegisterer()
 
 	releaseRegisterer := NewReleaseScopedRegisterer()
 	namespaceRegisterer := NewNamespaceScopedRegisterer(releaseRegisterer)
@@ -36,6 +37,8 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 	projectIntegrationRegisterer := NewProjectIntegrationScopedRegisterer()
 	projectOAuthRegisterer := NewProjectOAuthScopedRegisterer()
 	slackIntegrationRegisterer := NewSlackIntegrationScopedRegisterer()
+	pipelineRegisterer := NewPipelineScopedRegisterer()
+	templateRepoRegisterer := NewTemplateRepoScopedRegisterer()
 	projRegisterer := NewProjectScopedRegisterer(
 		clusterRegisterer,
 		registryRegisterer,
@@ -46,6 +49,8 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 		projectIntegrationRegisterer,
 		projectOAuthRegisterer,
 		slackIntegrationRegisterer,
+		pipelineRegisterer,
+		templateRepoRegisterer,
 	)
 
 	userRegisterer := NewUserScopedRegisterer(projRegisterer)
@@ -80,6 +85,15 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 			endpointFactory,
 		)
 
+		adminRoutes := adminRegisterer.GetRoutes(
+			r,
+			config,
+			&types.Path{
+				RelativePath: "",
+			},
+			endpointFactory,
+		)
+
 		userRoutes := userRegisterer.GetRoutes(
 			r,
 			config,
@@ -94,6 +108,7 @@ func NewAPIRouter(config *config.Config) *chi.Mux {
 			baseRoutes,
 			userRoutes,
 			oauthCallbackRoutes,
+			adminRoutes,
 		}
 
 		var allRoutes []*Route
@@ -187,6 +202,10 @@ func registerRoutes(config *config.Config, routes []*Route) {
 	// after authorization. Each subsequent http.Handler can lookup the release in context.
 	releaseFactory := authz.NewReleaseScopedFactory(config)
 
+	// Create a new instance admin factory, which only allows instance admins to make the
+	// request. The user must be attached to the context first.
+	instanceAdminFactory := authz.NewInstanceAdminFactory(config)
+
 	// Policy doc loader loads the policy documents for a specific project.
 	policyDocLoader := policy.NewBasicPolicyDocumentLoader(config.Repo.Project())
 
@@ -199,6 +218,11 @@ func registerRoutes(config *config.Config, routes []*Route) {
 	for _, route := range routes {
 		atomicGroup := route.Router.Group(nil)
 
+		// tag the errors of the request with the handler before any other middleware runs
+		handlerNameMW := middleware.NewHandlerNameMiddleware(route.Handler)
+
+		atomicGroup.Use(handlerNameMW.Middleware)
+
 		for _, scope := range route.Endpoint.Metadata.Scopes {
 			switch scope {
 			case types.UserScope:
@@ -229,6 +253,8 @@ func registerRoutes(config *config.Config, routes []*Route) {
 				atomicGroup.Use(infraFactory.Middleware)
 			case types.ReleaseScope:
 				atomicGroup.Use(releaseFactory.Middleware)
+			case types.InstanceAdminScope:
+				atomicGroup.Use(instanceAdminFactory.Middleware)
 			}
 		}
 
@@ -240,12 +266,36 @@ func registerRoutes(config *config.Config, routes []*Route) {
 			atomicGroup.Use(websocketMw.Middleware)
 		}
 
+		if route.Endpoint.Metadata.CheckUsage {
+			quotaMW := middleware.NewQuotaMiddleware(config, route.Endpoint.Metadata.UsageMetric)
+
+			atomicGroup.Use(quotaMW.Middleware)
+		}
+
 		if route.Endpoint.Metadata.CheckUsage && config.ServerConf.UsageTrackingEnabled {
 			usageMW := middleware.NewUsageMiddleware(config, route.Endpoint.Metadata.UsageMetric)
 
 			atomicGroup.Use(usageMW.Middleware)
 		}
 
+		if route.Endpoint.Metadata.CheckUsage && route.Endpoint.Metadata.UsageMetric == types.Clusters {
+			clusterEntitlementMW := middleware.NewClusterEntitlementMiddleware(config)
+
+			atomicGroup.Use(clusterEntitlementMW.Middleware)
+		}
+
+		if route.Endpoint.Metadata.Entitlement != "" {
+			entitlementMW := middleware.NewEntitlementMiddleware(config, route.Endpoint.Metadata.Entitlement)
+
+			atomicGroup.Use(entitlementMW.Middleware)
+		}
+
+		if route.Endpoint.Metadata.CheckDeployFreeze {
+			deployFreezeMW := middleware.NewDeployFreezeMiddleware(config)
+
+			atomicGroup.Use(deployFreezeMW.Middleware)
+		}
+
 		atomicGroup.Method(
 			string(route.Endpoint.Metadata.Method),
 			route.Endpoint.Metadata.Path.RelativePath,
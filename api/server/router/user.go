@@ -346,6 +346,64 @@ func getUserRoutes(
 		Router:   r,
 	})
 
+	// GET /api/templates/{name}/{version}/form -> template.NewTemplateGetFormHandler
+	getTemplateFormEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbGet,
+			Path: &types.Path{
+				Parent: basePath,
+				RelativePath: fmt.Sprintf(
+					"/templates/{%s}/{%s}/form",
+					types.URLParamTemplateName,
+					types.URLParamTemplateVersion,
+				),
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	getTemplateFormRequest := template.NewTemplateGetFormHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: getTemplateFormEndpoint,
+		Handler:  getTemplateFormRequest,
+		Router:   r,
+	})
+
+	// POST /api/templates/{name}/{version}/form/render -> template.NewTemplateRenderFormHandler
+	renderTemplateFormEndpoint := factory.NewAPIEndpoint(
+		&types.APIRequestMetadata{
+			Verb:   types.APIVerbGet,
+			Method: types.HTTPVerbPost,
+			Path: &types.Path{
+				Parent: basePath,
+				RelativePath: fmt.Sprintf(
+					"/templates/{%s}/{%s}/form/render",
+					types.URLParamTemplateName,
+					types.URLParamTemplateVersion,
+				),
+			},
+			Scopes: []types.PermissionScope{types.UserScope},
+		},
+	)
+
+	renderTemplateFormRequest := template.NewTemplateRenderFormHandler(
+		config,
+		factory.GetDecoderValidator(),
+		factory.GetResultWriter(),
+	)
+
+	routes = append(routes, &Route{
+		Endpoint: renderTemplateFormEndpoint,
+		Handler:  renderTemplateFormRequest,
+		Router:   r,
+	})
+
 	//  GET /api/integrations/github-app/oauth -> gitinstallation.NewGithubAppOAuthStartHandler
 	githubAppOAuthStartEndpoint := factory.NewAPIEndpoint(
 		&types.APIRequestMetadata{
@@ -2,8 +2,51 @@ package alerter
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
 )
 
 type Alerter interface {
 	SendAlert(ctx context.Context, err error, data map[string]interface{})
 }
+
+// The keys of the alert data that identify where an error happened, which alerters
+// attach to alerts as tags
+const (
+	TagHandler   = "handler"
+	TagUserID    = "user_id"
+	TagProject   = "project"
+	TagCluster   = "cluster"
+	TagNamespace = "namespace"
+	TagRelease   = "release"
+)
+
+var tagKeys = []string{TagHandler, TagProject, TagCluster, TagNamespace, TagRelease}
+
+// getTags returns the tags of the alert data in the form key:value
+func getTags(data map[string]interface{}) []string {
+	res := make([]string, 0)
+
+	for _, key := range tagKeys {
+		if val, ok := data[key]; ok {
+			res = append(res, fmt.Sprintf("%s:%v", key, val))
+		}
+	}
+
+	return res
+}
+
+// getSummary returns a one-line summary of an error, prefixed with the handler if known
+func getSummary(err error, data map[string]interface{}) string {
+	if handler, ok := data[TagHandler]; ok {
+		return fmt.Sprintf("[%v] %s", handler, err.Error())
+	}
+
+	return err.Error()
+}
+
+// getDedupKey returns a key that is the same for repeated errors of the same handler,
+// so that alerters group them into a single alert
+func getDedupKey(err error, data map[string]interface{}) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(getSummary(err, data))))
+}
@@ -0,0 +1,97 @@
+package alerter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultOpsgenieAPIURL = "https://api.opsgenie.com"
+
+// OpsgenieAlerter creates alerts with the Opsgenie Alert API. Repeated errors of the same
+// handler share an alias, so that Opsgenie counts them on a single open alert.
+type OpsgenieAlerter struct {
+	apiKey     string
+	apiURL     string
+	source     string
+	httpClient *http.Client
+}
+
+// NewOpsgenieAlerter returns an alerter for the Opsgenie API at the given URL, which
+// defaults to the US instance of Opsgenie
+func NewOpsgenieAlerter(apiKey, apiURL, source string) *OpsgenieAlerter {
+	if apiURL == "" {
+		apiURL = defaultOpsgenieAPIURL
+	}
+
+	return &OpsgenieAlerter{
+		apiKey: apiKey,
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		source: source,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+}
+
+// SendAlert sends the alert in the background, so that the request is not delayed
+func (o *OpsgenieAlerter) SendAlert(ctx context.Context, err error, data map[string]interface{}) {
+	details := make(map[string]string)
+
+	for key, val := range data {
+		details[key] = fmt.Sprintf("%v", val)
+	}
+
+	summary := getSummary(err, data)
+
+	alert := &opsgenieAlert{
+		Message:     truncate(summary, 130),
+		Alias:       getDedupKey(err, data),
+		Description: truncate(summary, 15000),
+		Tags:        getTags(data),
+		Details:     details,
+		Source:      o.source,
+		Priority:    "P3",
+	}
+
+	go o.send(alert)
+}
+
+func (o *OpsgenieAlerter) send(alert *opsgenieAlert) {
+	body, err := json.Marshal(alert)
+
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/v2/alerts", o.apiURL), bytes.NewReader(body))
+
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", o.apiKey))
+
+	resp, err := o.httpClient.Do(req)
+
+	if err != nil {
+		return
+	}
+
+	resp.Body.Close()
+}
@@ -0,0 +1,90 @@
+package alerter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyAlerter triggers incidents with the PagerDuty Events API v2. Repeated errors
+// of the same handler are deduplicated into a single incident.
+type PagerDutyAlerter struct {
+	routingKey string
+	source     string
+	httpClient *http.Client
+}
+
+func NewPagerDutyAlerter(routingKey, source string) *PagerDutyAlerter {
+	return &PagerDutyAlerter{
+		routingKey: routingKey,
+		source:     source,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string                 `json:"routing_key"`
+	EventAction string                 `json:"event_action"`
+	DedupKey    string                 `json:"dedup_key"`
+	Payload     *pagerDutyEventPayload `json:"payload"`
+}
+
+type pagerDutyEventPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Component     string                 `json:"component,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+// SendAlert sends the alert in the background, so that the request is not delayed
+func (p *PagerDutyAlerter) SendAlert(ctx context.Context, err error, data map[string]interface{}) {
+	event := &pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: "trigger",
+		DedupKey:    getDedupKey(err, data),
+		Payload: &pagerDutyEventPayload{
+			Summary:       truncate(getSummary(err, data), 1024),
+			Source:        p.source,
+			Severity:      "error",
+			CustomDetails: data,
+		},
+	}
+
+	if handler, ok := data[TagHandler]; ok {
+		event.Payload.Component = fmt.Sprintf("%v", handler)
+	}
+
+	go p.send(event)
+}
+
+func (p *PagerDutyAlerter) send(event *pagerDutyEvent) {
+	body, err := json.Marshal(event)
+
+	if err != nil {
+		return
+	}
+
+	resp, err := p.httpClient.Post(pagerDutyEventsURL, "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return
+	}
+
+	resp.Body.Close()
+}
+
+func truncate(str string, length int) string {
+	if len(str) <= length {
+		return str
+	}
+
+	return str[:length]
+}
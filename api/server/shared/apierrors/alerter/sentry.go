@@ -39,6 +39,19 @@ func (s *SentryAlerter) SendAlert(ctx context.Context, err error, data map[strin
 		scope.SetTag(key, fmt.Sprintf("%v", val))
 	}
 
+	// errors are grouped by handler in Sentry, rather than by the stack trace of the
+	// shared error handling code
+	if handler, ok := data[TagHandler]; ok {
+		scope.SetTransaction(fmt.Sprintf("%v", handler))
+		scope.SetFingerprint([]string{"{{ default }}", fmt.Sprintf("%v", handler)})
+	}
+
+	if userID, ok := data[TagUserID]; ok {
+		scope.SetUser(sentry.User{
+			ID: fmt.Sprintf("%v", userID),
+		})
+	}
+
 	s.client.CaptureException(
 		err,
 		&sentry.EventHint{
@@ -0,0 +1,174 @@
+package apierrors
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// maxLoggedErrorLength is the length after which the error of a failed API operation is
+// truncated when it is logged for a project
+const maxLoggedErrorLength = 1000
+
+// secretPatterns match values of parameters and headers that may be secret, which are
+// redacted from logged errors
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)((?:token|password|secret|api_key|apikey|access_key)["']?\s*[=:]\s*["']?)[^\s,"'&]+`),
+	regexp.MustCompile(`(?i)(bearer\s+)[^\s,"']+`),
+}
+
+// logAPIError stores the failed API operation of a project-scoped request, so that users
+// of the project can see why their requests failed. The error is stored outside of the
+// request, and errors from storing it are ignored.
+func logAPIError(config *config.Config, r *http.Request, err RequestError) {
+	if config.Repo == nil || config.ServerConf == nil || config.ServerConf.APIErrorLogRetention <= 0 {
+		return
+	}
+
+	reqScopes, _ := r.Context().Value(types.RequestScopeCtxKey).(map[types.PermissionScope]*types.RequestAction)
+	projScope, ok := reqScopes[types.ProjectScope]
+
+	if !ok || projScope.Resource.UInt == 0 {
+		return
+	}
+
+	extErr := SanitizeError(err.ExternalError())
+
+	log := &models.APIErrorLog{
+		ProjectID:  projScope.Resource.UInt,
+		Method:     r.Method,
+		Path:       getRoutePattern(r),
+		StatusCode: err.GetStatusCode(),
+		Error:      extErr,
+		ErrorCode:  err.ErrorCode(),
+		Category:   GetAPIErrorCategory(err.GetStatusCode(), extErr),
+	}
+
+	log.Handler, _ = r.Context().Value(types.HandlerNameCtxKey).(string)
+
+	if scope, ok := reqScopes[types.ClusterScope]; ok {
+		log.ClusterID = scope.Resource.UInt
+	}
+
+	if scope, ok := reqScopes[types.NamespaceScope]; ok {
+		log.Namespace = scope.Resource.Name
+	}
+
+	if scope, ok := reqScopes[types.ReleaseScope]; ok {
+		log.ReleaseName = scope.Resource.Name
+	}
+
+	retention := config.ServerConf.APIErrorLogRetention
+
+	go func() {
+		repo := config.Repo.APIErrorLog()
+
+		if _, err := repo.CreateAPIErrorLog(log); err != nil {
+			return
+		}
+
+		repo.DeleteAPIErrorLogsBefore(log.ProjectID, time.Now().Add(-retention))
+	}()
+}
+
+// getRoutePattern returns the route of the request with its URL parameters unfilled, so
+// that tokens in the path of the request are not logged
+func getRoutePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+
+	return r.URL.Path
+}
+
+// SanitizeError redacts values that may be secret from an error, and truncates it
+func SanitizeError(errStr string) string {
+	for _, pattern := range secretPatterns {
+		errStr = pattern.ReplaceAllString(errStr, "${1}[REDACTED]")
+	}
+
+	if len(errStr) > maxLoggedErrorLength {
+		errStr = errStr[:maxLoggedErrorLength] + "..."
+	}
+
+	return errStr
+}
+
+// GetAPIErrorCategory returns the category of a failed API operation from its status code
+// and the error returned to the client
+func GetAPIErrorCategory(statusCode int, errStr string) types.APIErrorCategory {
+	lowerErr := strings.ToLower(errStr)
+
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		return types.APIErrorCategoryAuthentication
+	case statusCode == http.StatusForbidden:
+		return types.APIErrorCategoryPermission
+	case statusCode == http.StatusNotFound:
+		return types.APIErrorCategoryNotFound
+	case statusCode == http.StatusConflict:
+		return types.APIErrorCategoryConflict
+	case statusCode == http.StatusPaymentRequired,
+		statusCode == http.StatusTooManyRequests,
+		strings.Contains(lowerErr, "limit"),
+		strings.Contains(lowerErr, "quota"):
+		return types.APIErrorCategoryLimit
+	case strings.Contains(lowerErr, "not available"),
+		strings.Contains(lowerErr, "disabled"):
+		return types.APIErrorCategoryUnavailable
+	case statusCode >= http.StatusInternalServerError:
+		return types.APIErrorCategoryInternal
+	}
+
+	return types.APIErrorCategoryValidation
+}
+
+// GetSuggestedFix returns a suggestion for fixing a failed API operation. Suggestions for
+// well-known errors take precedence over the suggestion of the category.
+func GetSuggestedFix(category types.APIErrorCategory, errStr string) string {
+	lowerErr := strings.ToLower(errStr)
+
+	switch {
+	case strings.Contains(lowerErr, "deploy webhook is disabled"):
+		return "Enable auto-deploy in the settings of the application, or deploy the application with the CLI instead of the webhook."
+	case strings.Contains(lowerErr, "json syntax error"), strings.Contains(lowerErr, "could not parse json"):
+		return "The request body is not valid JSON. Check that the body is quoted and escaped correctly, and that the Content-Type header is application/json."
+	case strings.Contains(lowerErr, "invalid type for body param"):
+		return "A field of the request body has the wrong type. Check the field named in the error against the API reference."
+	case strings.Contains(lowerErr, "community edition"):
+		return "This feature is only available in the enterprise edition of Porter."
+	case strings.Contains(lowerErr, "image") && strings.Contains(lowerErr, "pull"):
+		return "The image could not be pulled. Check that the image tag was pushed and that the cluster has access to the registry."
+	case strings.Contains(lowerErr, "timed out"), strings.Contains(lowerErr, "timeout"):
+		return "The operation timed out. Check that the cluster is reachable, and retry the request."
+	}
+
+	switch category {
+	case types.APIErrorCategoryAuthentication:
+		return "Log in again, or check that the API token of the request has not expired or been revoked."
+	case types.APIErrorCategoryPermission:
+		return "Your role in the project does not allow this operation. Ask an admin of the project for access."
+	case types.APIErrorCategoryNotFound:
+		return "The resource does not exist, or was deleted. Check the IDs and names in the path of the request."
+	case types.APIErrorCategoryValidation:
+		return "The request was invalid. Check the fields named in the error against the API reference."
+	case types.APIErrorCategoryConflict:
+		return "The resource already exists, or was changed by another request. Retry the request with the current state of the resource."
+	case types.APIErrorCategoryLimit:
+		return "The project has reached a limit of its plan. Remove unused resources, or upgrade the plan of the project."
+	case types.APIErrorCategoryUnavailable:
+		return "This feature is disabled for the project or the installation. Enable it in the settings, or contact the admin of the installation."
+	case types.APIErrorCategoryInternal:
+		return "An internal error occurred. Retry the request, and contact support with the time of the error if it keeps failing."
+	}
+
+	return ""
+}
@@ -15,6 +15,7 @@ type RequestError interface {
 	ExternalError() string
 	InternalError() string
 	GetStatusCode() int
+	ErrorCode() types.ErrorCode
 }
 
 type ErrInternal struct {
@@ -41,6 +42,10 @@ func (e *ErrInternal) GetStatusCode() int {
 	return http.StatusInternalServerError
 }
 
+func (e *ErrInternal) ErrorCode() types.ErrorCode {
+	return types.ErrorCodeInternal
+}
+
 type ErrForbidden struct {
 	err error
 }
@@ -65,6 +70,10 @@ func (e *ErrForbidden) GetStatusCode() int {
 	return http.StatusForbidden
 }
 
+func (e *ErrForbidden) ErrorCode() types.ErrorCode {
+	return types.ErrorCodeForbidden
+}
+
 // errors that should be passed directly, with no filter
 type ErrPassThroughToClient struct {
 	err        error
@@ -92,6 +101,52 @@ func (e *ErrPassThroughToClient) GetStatusCode() int {
 	return e.statusCode
 }
 
+func (e *ErrPassThroughToClient) ErrorCode() types.ErrorCode {
+	return ErrorCodeFromStatus(e.statusCode)
+}
+
+// ErrorCodeFromStatus returns the generic error code of a status code, for errors without
+// a more specific code
+func ErrorCodeFromStatus(statusCode int) types.ErrorCode {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return types.ErrorCodeBadRequest
+	case http.StatusUnauthorized:
+		return types.ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return types.ErrorCodeForbidden
+	case http.StatusNotFound:
+		return types.ErrorCodeNotFound
+	case http.StatusConflict:
+		return types.ErrorCodeConflict
+	case http.StatusPaymentRequired, http.StatusTooManyRequests:
+		return types.ErrorCodeLimitExceeded
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		return types.ErrorCodeInternal
+	}
+
+	return types.ErrorCodeUnknown
+}
+
+// errWithCode overrides the error code of a request error
+type errWithCode struct {
+	RequestError
+
+	code types.ErrorCode
+}
+
+// WithCode attaches a specific error code to a request error, which is returned to the
+// client instead of the generic code of the error
+func WithCode(err RequestError, code types.ErrorCode) RequestError {
+	return &errWithCode{err, code}
+}
+
+func (e *errWithCode) ErrorCode() types.ErrorCode {
+	return e.code
+}
+
 type ErrorOpts struct {
 	Code uint
 }
@@ -109,13 +164,16 @@ func HandleAPIError(
 	// log the internal error
 	event := config.Logger.Warn().
 		Str("internal_error", err.InternalError()).
-		Str("external_error", extErrorStr)
+		Str("external_error", extErrorStr).
+		Str("error_code", string(err.ErrorCode()))
 
 	data := logger.AddLoggingContextScopes(r.Context(), event)
 	logger.AddLoggingRequestMeta(r, event)
 
 	event.Send()
 
+	logAPIError(config, r, err)
+
 	// if the status code is internal server error, use alerter
 	if err.GetStatusCode() == http.StatusInternalServerError && config.Alerter != nil {
 		data["method"] = r.Method
@@ -127,7 +185,8 @@ func HandleAPIError(
 	if writeErr {
 		// send the external error
 		resp := &types.ExternalError{
-			Error: extErrorStr,
+			Error:     extErrorStr,
+			ErrorCode: err.ErrorCode(),
 		}
 
 		if len(opts) > 0 {
@@ -2,16 +2,20 @@ package apitest
 
 import (
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/config/envloader"
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/auth/sessionstore"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/billing"
 	"github.com/porter-dev/porter/internal/logger"
 	"github.com/porter-dev/porter/internal/repository/test"
+	"github.com/porter-dev/porter/internal/settings"
+	"github.com/porter-dev/porter/internal/usage"
 )
 
 type TestConfigLoader struct {
@@ -50,15 +54,32 @@ func (t *TestConfigLoader) LoadConfig() (*config.Config, error) {
 
 	notifier := NewFakeUserNotifier()
 
+	// the settings are loaded when the config is loaded, so they are always read from a
+	// repository that can be queried
+	settingsManager, err := settings.NewManager(test.NewServerSettingRepository(true), map[types.ServerSettingKey]string{
+		types.ServerSettingAppRootDomain:                 envConf.ServerConf.AppRootDomain,
+		types.ServerSettingDefaultApplicationHelmRepoURL: envConf.ServerConf.DefaultApplicationHelmRepoURL,
+		types.ServerSettingDefaultAddonHelmRepoURL:       envConf.ServerConf.DefaultAddonHelmRepoURL,
+		types.ServerSettingDisableAllowlist:              strconv.FormatBool(envConf.ServerConf.DisableAllowlist),
+		types.ServerSettingAnalyticsOptOut:               strconv.FormatBool(envConf.ServerConf.AnalyticsOptOut),
+	}, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &config.Config{
-		Logger:          l,
-		Repo:            repo,
-		Store:           store,
-		ServerConf:      envConf.ServerConf,
-		TokenConf:       tokenConf,
-		UserNotifier:    notifier,
-		AnalyticsClient: analytics.InitializeAnalyticsSegmentClient("", l),
-		BillingManager:  &billing.NoopBillingManager{},
+		Logger:             l,
+		Repo:               repo,
+		Store:              store,
+		ServerConf:         envConf.ServerConf,
+		TokenConf:          tokenConf,
+		UserNotifier:       notifier,
+		AnalyticsClient:    analytics.NoopClient{},
+		BillingManager:     &billing.NoopBillingManager{},
+		EntitlementManager: billing.NewEntitlementManager(repo, false, false, nil),
+		WhitelistedUsers:   usage.NewWhitelistedUsers(nil, repo.InstanceAdmin(), 0),
+		Settings:           settingsManager,
 	}, nil
 }
 
@@ -34,7 +34,8 @@ func AssertResponseForbidden(t *testing.T, rr *httptest.ResponseRecorder) {
 	}
 
 	expReqErr := &types.ExternalError{
-		Error: "Forbidden",
+		Error:     "Forbidden",
+		ErrorCode: types.ErrorCodeForbidden,
 	}
 
 	assert.Equal(t, http.StatusForbidden, rr.Result().StatusCode, "status code should be forbidden")
@@ -50,7 +51,8 @@ func AssertResponseInternalServerError(t *testing.T, rr *httptest.ResponseRecord
 	}
 
 	expReqErr := &types.ExternalError{
-		Error: "An internal error occurred.",
+		Error:     "An internal error occurred.",
+		ErrorCode: types.ErrorCodeInternal,
 	}
 
 	assert.Equal(t, http.StatusInternalServerError, rr.Result().StatusCode, "status code should be internal server error")
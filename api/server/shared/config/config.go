@@ -5,17 +5,28 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors/alerter"
 	"github.com/porter-dev/porter/api/server/shared/config/env"
 	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/billing"
+	"github.com/porter-dev/porter/internal/helm/mirror"
 	"github.com/porter-dev/porter/internal/helm/urlcache"
+	"github.com/porter-dev/porter/internal/imagemirror"
+	"github.com/porter-dev/porter/internal/integrations/cosign"
+	"github.com/porter-dev/porter/internal/integrations/githubapp"
+	"github.com/porter-dev/porter/internal/integrations/opa"
 	"github.com/porter-dev/porter/internal/integrations/powerdns"
 	"github.com/porter-dev/porter/internal/kubernetes"
 	"github.com/porter-dev/porter/internal/logger"
 	"github.com/porter-dev/porter/internal/notifier"
 	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/pricing"
+	"github.com/porter-dev/porter/internal/redis_stream/lease"
+	"github.com/porter-dev/porter/internal/registry"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/repository/credentials"
+	"github.com/porter-dev/porter/internal/settings"
+	"github.com/porter-dev/porter/internal/usage"
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
 )
@@ -63,6 +74,10 @@ type Config struct {
 	// GithubAppConf is the configuration for a Github App OAuth client
 	GithubAppConf *oauth.GithubAppConf
 
+	// GithubAppTokens caches the access tokens of Github App installations. This is nil
+	// if the Github App is not configured.
+	GithubAppTokens *githubapp.TokenCache
+
 	// GoogleConf is the configuration for a Google OAuth client
 	GoogleConf *oauth2.Config
 
@@ -79,17 +94,37 @@ type Config struct {
 	// jobs
 	ProvisionerAgent *kubernetes.Agent
 
+	// CostEstimator estimates the monthly cost of provisioning requests
+	CostEstimator *pricing.Estimator
+
+	// ProvisionerLeases claims leases on provisioning operations, so that multiple server
+	// replicas do not run operations on the same infra at once. This is nil if Redis is
+	// not enabled.
+	ProvisionerLeases *lease.Manager
+
 	// DB is the gorm DB instance
 	DB *gorm.DB
 
-	// AnalyticsClient if Segment analytics reporting is enabled on the API instance
-	AnalyticsClient analytics.AnalyticsSegmentClient
+	// AnalyticsClient sends analytics events to the configured provider, and drops them
+	// if analytics are disabled or the installation has opted out
+	AnalyticsClient analytics.AnalyticsClient
 
 	// BillingManager manages billing for Porter instances with billing enabled
 	BillingManager billing.BillingManager
 
+	// EntitlementManager determines the capabilities that a project is entitled to by
+	// its billing plan
+	EntitlementManager *billing.EntitlementManager
+
+	// License is the offline license of an enterprise installation, if one is configured
+	License *types.License
+
+	// Settings are the settings of the server that can be overridden at runtime, which
+	// should be read instead of the equivalent fields of ServerConf
+	Settings *settings.Manager
+
 	// WhitelistedUsers do not count toward usage limits
-	WhitelistedUsers map[uint]uint
+	WhitelistedUsers *usage.WhitelistedUsers
 
 	// PowerDNSClient is a client for PowerDNS, if the Porter instance supports vanity URLs
 	PowerDNSClient *powerdns.Client
@@ -97,6 +132,33 @@ type Config struct {
 	// CredentialBackend is the backend for credential storage, if external cred storage (like Vault)
 	// is used
 	CredentialBackend credentials.CredentialStorage
+
+	// InformerCache caches the pods and controllers of connected clusters for status
+	// endpoints. This is nil if the cache is disabled.
+	InformerCache *kubernetes.InformerCache
+
+	// RegistryIndex caches the repositories and images of registries. This is nil if the
+	// index is disabled.
+	RegistryIndex *registry.RepositoryIndex
+
+	// Inventory caches the inventory of connected clusters for the inventory endpoint and
+	// usage limits. This is nil if the cache is disabled.
+	Inventory *usage.InventoryCache
+
+	// HelmMirror serves the charts of mirrored repos. This is nil if no repos are mirrored.
+	HelmMirror *mirror.Mirror
+
+	// ImageMirror is the registry that mirrors the public images that Porter uses. This is
+	// nil if no mirror is set.
+	ImageMirror *imagemirror.Mirror
+
+	// OPAClient evaluates the manifest policies of projects against the manifests of
+	// releases. This is nil if no policy server is set.
+	OPAClient *opa.Client
+
+	// ImageVerifier verifies the cosign signatures of images for projects that enforce
+	// image signing
+	ImageVerifier *cosign.Verifier
 }
 
 type ConfigLoader interface {
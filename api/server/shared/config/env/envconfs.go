@@ -58,10 +58,30 @@ type ServerConf struct {
 	SlackClientID     string `env:"SLACK_CLIENT_ID"`
 	SlackClientSecret string `env:"SLACK_CLIENT_SECRET"`
 
+	// SlackSigningSecret verifies the slash commands and interactive messages of the
+	// Slack app, which are only enabled if it is set
+	SlackSigningSecret string `env:"SLACK_SIGNING_SECRET"`
+
 	IronPlansAPIKey    string `env:"IRON_PLANS_API_KEY"`
 	IronPlansServerURL string `env:"IRON_PLANS_SERVER_URL"`
 	WhitelistedUsers   []uint `env:"WHITELISTED_USERS"`
 
+	// The emails of the users that are admins of the instance, in addition to the users
+	// that were made admins through the admin API
+	InstanceAdminEmails []string `env:"INSTANCE_ADMIN_EMAILS"`
+
+	// The time after which the server settings that instance admins override at runtime
+	// are reloaded from the database
+	SettingsCacheTTL time.Duration `env:"SETTINGS_CACHE_TTL,default=1m"`
+
+	// The path to the license file of an enterprise installation without a billing
+	// provider, such as an air-gapped installation
+	LicenseFilePath string `env:"LICENSE_FILE_PATH"`
+
+	// The interval at which the usage of billed projects is reported to IronPlans. Setting
+	// the interval to 0 disables usage reporting.
+	UsageReportInterval time.Duration `env:"USAGE_REPORT_INTERVAL,default=1h"`
+
 	DOClientID     string `env:"DO_CLIENT_ID"`
 	DOClientSecret string `env:"DO_CLIENT_SECRET"`
 
@@ -72,20 +92,151 @@ type ServerConf struct {
 	ProvisionerBackendURL      string `env:"PROV_BACKEND_URL"`
 	ProvisionerCredExchangeURL string `env:"PROV_CRED_EXCHANGE_URL,default=http://porter:8080"`
 
+	// The duration after which the lease on a provisioning operation expires once its
+	// provisioner job has stopped, if the job did not return a result
+	ProvisionerLeaseTTL time.Duration `env:"PROV_LEASE_TTL,default=60s"`
+
+	// The window of logs that can be searched for a release, which should match the
+	// retention period configured for Loki in the clusters
+	LogSearchRetention time.Duration `env:"LOG_SEARCH_RETENTION,default=720h"`
+
+	// The interval at which installed addons are checked for new versions of their
+	// charts. Setting the interval to 0 disables the background check.
+	AddonUpdateCheckInterval time.Duration `env:"ADDON_UPDATE_CHECK_INTERVAL,default=6h"`
+
+	// The interval at which the gitops repositories of projects with reconciliation enabled
+	// are checked for new commits. Setting the interval to 0 disables reconciliation.
+	GitOpsReconcileInterval time.Duration `env:"GITOPS_RECONCILE_INTERVAL,default=5m"`
+
+	// The interval at which releases are checked for staleness, the period without
+	// deploys, traffic or replicas after which a release is stale, and whether projects
+	// are notified through Slack when a release becomes stale. Setting the interval to 0
+	// disables the check.
+	StaleReleaseCheckInterval time.Duration `env:"STALE_RELEASE_CHECK_INTERVAL,default=24h"`
+	StaleReleasePeriod        time.Duration `env:"STALE_RELEASE_PERIOD,default=720h"`
+	StaleReleaseNotify        bool          `env:"STALE_RELEASE_NOTIFY,default=false"`
+
+	// The interval at which scheduled deploys are checked, and due deploys are run. Setting
+	// the interval to 0 disables scheduled deploys.
+	ScheduledDeployInterval time.Duration `env:"SCHEDULED_DEPLOY_INTERVAL,default=1m"`
+
+	// Chart repos whose indexes are cached in addition to the default application and
+	// addon repos, as <url> or <url>=<ttl>. A TTL can also be set for a default repo by
+	// listing it with a TTL.
+	HelmRepoCacheURLs []string `env:"HELM_REPO_CACHE_URLS"`
+
+	// The time after which the cached index of a chart repo is refreshed, and whether
+	// chart lookups are served from expired indexes while they are refreshed in the
+	// background instead of waiting on the chart repo
+	HelmRepoCacheTTL                  time.Duration `env:"HELM_REPO_CACHE_TTL,default=10m"`
+	HelmRepoCacheStaleWhileRevalidate bool          `env:"HELM_REPO_CACHE_STALE_WHILE_REVALIDATE,default=true"`
+
+	// Chart repos that are mirrored for air-gapped installs, as <name>=<url>. Mirrored
+	// charts are synced with the helm-mirror command into the S3 bucket, or into the
+	// directory if no bucket is set, and are served at /api/helm_mirror/<name>. The
+	// default repo URLs can be set to the URLs of the mirror to install templates without
+	// access to the upstream repos.
+	HelmMirrorRepos    []string `env:"HELM_MIRROR_REPOS"`
+	HelmMirrorBucket   string   `env:"HELM_MIRROR_BUCKET"`
+	HelmMirrorRegion   string   `env:"HELM_MIRROR_REGION"`
+	HelmMirrorEndpoint string   `env:"HELM_MIRROR_ENDPOINT"`
+	HelmMirrorDir      string   `env:"HELM_MIRROR_DIR"`
+
+	// The registry that mirrors the public images that Porter uses, such as the default
+	// application images and the buildpack builders, with an optional path prefix. Images
+	// are mirrored under their repository path, so public.ecr.aws/o1j4x7p4/hello-porter is
+	// pulled as <registry>/o1j4x7p4/hello-porter. If strict, releases that reference images
+	// outside of the mirror are rejected.
+	ImageMirrorRegistry string `env:"IMAGE_MIRROR_REGISTRY"`
+	ImageMirrorStrict   bool   `env:"IMAGE_MIRROR_STRICT,default=false"`
+
+	// The time after which the informers that cache the pods and controllers of a cluster
+	// are stopped if the cluster's status endpoints are not called. Setting the TTL to 0
+	// disables the cache, and status endpoints read from the api server directly.
+	InformerCacheTTL time.Duration `env:"INFORMER_CACHE_TTL,default=10m"`
+
+	// The time after which the cached repositories and images of a registry are refreshed
+	// in the background. Setting the TTL to 0 disables the index, and registries are
+	// listed directly.
+	RegistryIndexTTL time.Duration `env:"REGISTRY_INDEX_TTL,default=5m"`
+
+	// The time after which the cached inventory of a cluster, which is read by the
+	// inventory endpoint and for usage limits, is collected again. Setting the TTL to 0
+	// disables the cache, and usage is read from the clusters directly. If Redis is
+	// enabled, the cached inventories are shared by all of the replicas.
+	InventoryCacheTTL time.Duration `env:"INVENTORY_CACHE_TTL,default=10m"`
+
+	// The backend of the session store, which is either postgres or redis. The redis store
+	// uses the redis instance of the REDIS_* variables.
+	SessionStore string `env:"SESSION_STORE,default=postgres"`
+
+	// Whether sessions that are not found in the redis session store are moved from the
+	// postgres session store, so that users stay logged in after switching to redis
+	SessionStoreMigrate bool `env:"SESSION_STORE_MIGRATE,default=true"`
+
+	// The analytics provider, which is one of segment, posthog or none. If unset, Segment
+	// is used when a Segment client key is set.
+	AnalyticsProvider string `env:"ANALYTICS_PROVIDER"`
+
 	SegmentClientKey string `env:"SEGMENT_CLIENT_KEY"`
 
+	// PostHog API key and the host of a self-hosted PostHog instance
+	PostHogAPIKey string `env:"POSTHOG_API_KEY"`
+	PostHogHost   string `env:"POSTHOG_HOST"`
+
+	// How often queued analytics events are sent, and the maximum number of events sent
+	// at once
+	AnalyticsFlushInterval time.Duration `env:"ANALYTICS_FLUSH_INTERVAL,default=5s"`
+	AnalyticsBatchSize     int           `env:"ANALYTICS_BATCH_SIZE,default=100"`
+
+	// How long failed API operations are stored for the users of a project. Setting the
+	// retention to 0 disables storing them.
+	APIErrorLogRetention time.Duration `env:"API_ERROR_LOG_RETENTION,default=168h"`
+
+	// Opts the installation out of analytics, which instance admins can override at runtime
+	AnalyticsOptOut bool `env:"ANALYTICS_OPT_OUT"`
+
+	// A URL that serves pricing tables for cost estimates of provisioning requests, which
+	// override the built-in tables, and how often the tables are fetched again
+	PricingTablesURL string        `env:"PRICING_TABLES_URL"`
+	PricingTablesTTL time.Duration `env:"PRICING_TABLES_TTL,default=24h"`
+
+	// The range of CLI versions that the server supports, which is advertised to the CLI
+	// so that it can warn users of mismatched versions. Either bound may be empty.
+	MinCLIVersion string `env:"MIN_CLI_VERSION"`
+	MaxCLIVersion string `env:"MAX_CLI_VERSION"`
+
 	// PowerDNS client API key and the host of the PowerDNS API server
 	PowerDNSAPIServerURL string `env:"POWER_DNS_API_SERVER_URL"`
 	PowerDNSAPIKey       string `env:"POWER_DNS_API_KEY"`
 
+	// The URL of an Open Policy Agent server that evaluates the manifest policies of
+	// projects. If unset, manifest policies are disabled.
+	OPAURL string `env:"OPA_URL"`
+
+	// The PEM-encoded root certificates of Fulcio and public key of Rekor, which verify
+	// keyless cosign signatures. If either is unset, only signatures of public keys are
+	// verified.
+	CosignFulcioRoots    string `env:"COSIGN_FULCIO_ROOTS"`
+	CosignRekorPublicKey string `env:"COSIGN_REKOR_PUBLIC_KEY"`
+
 	// Email for an admin user. On a self-hosted instance of Porter, the
 	// admin user is the only user that can log in and register. After the admin
 	// user has logged in, registration is turned off.
 	AdminEmail string `env:"ADMIN_EMAIL"`
 
+	// The alerter that internal server errors are sent to, which is one of sentry,
+	// pagerduty or opsgenie. If unset, errors are sent to Sentry if SENTRY_DSN is set.
+	Alerter string `env:"ALERTER"`
+
 	SentryDSN string `env:"SENTRY_DSN"`
 	SentryEnv string `env:"SENTRY_ENV,default=dev"`
 
+	PagerDutyRoutingKey string `env:"PAGERDUTY_ROUTING_KEY"`
+
+	OpsgenieAPIKey string `env:"OPSGENIE_API_KEY"`
+	OpsgenieAPIURL string `env:"OPSGENIE_API_URL"`
+
 	ProvisionerCluster string `env:"PROVISIONER_CLUSTER"`
 	IngressCluster     string `env:"INGRESS_CLUSTER"`
 	SelfKubeconfig     string `env:"SELF_KUBECONFIG"`
@@ -5,6 +5,7 @@ package loader
 import (
 	eeBilling "github.com/porter-dev/porter/ee/billing"
 	"github.com/porter-dev/porter/ee/integrations/vault"
+	"github.com/porter-dev/porter/ee/license"
 	"github.com/porter-dev/porter/ee/models"
 	eeGorm "github.com/porter-dev/porter/ee/repository/gorm"
 	"github.com/porter-dev/porter/internal/billing"
@@ -40,6 +41,18 @@ func init() {
 		InstanceBillingManager = &billing.NoopBillingManager{}
 	}
 
+	if path := InstanceEnvConf.ServerConf.LicenseFilePath; path != "" {
+		var err error
+
+		// an invalid license fails startup, while an expired license only disables the
+		// enterprise features
+		InstanceLicense, err = license.LoadFromFile(path)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	if InstanceEnvConf.DBConf.VaultAPIKey != "" && InstanceEnvConf.DBConf.VaultServerURL != "" && InstanceEnvConf.DBConf.VaultPrefix != "" {
 		InstanceCredentialBackend = vault.NewClient(
 			InstanceEnvConf.DBConf.VaultServerURL,
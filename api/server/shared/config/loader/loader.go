@@ -3,28 +3,42 @@ package loader
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 
+	"github.com/gorilla/sessions"
 	gorillaws "github.com/gorilla/websocket"
 	"github.com/porter-dev/porter/api/server/shared/apierrors/alerter"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"github.com/porter-dev/porter/api/server/shared/config/env"
 	"github.com/porter-dev/porter/api/server/shared/config/envloader"
 	"github.com/porter-dev/porter/api/server/shared/websocket"
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/adapter"
 	"github.com/porter-dev/porter/internal/analytics"
 	"github.com/porter-dev/porter/internal/auth/sessionstore"
 	"github.com/porter-dev/porter/internal/auth/token"
 	"github.com/porter-dev/porter/internal/billing"
+	"github.com/porter-dev/porter/internal/helm/mirror"
 	"github.com/porter-dev/porter/internal/helm/urlcache"
+	"github.com/porter-dev/porter/internal/imagemirror"
+	"github.com/porter-dev/porter/internal/integrations/cosign"
+	"github.com/porter-dev/porter/internal/integrations/githubapp"
+	"github.com/porter-dev/porter/internal/integrations/opa"
 	"github.com/porter-dev/porter/internal/integrations/powerdns"
 	"github.com/porter-dev/porter/internal/kubernetes"
 	"github.com/porter-dev/porter/internal/kubernetes/local"
 	"github.com/porter-dev/porter/internal/notifier"
 	"github.com/porter-dev/porter/internal/notifier/sendgrid"
 	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/pricing"
+	"github.com/porter-dev/porter/internal/redis_stream/lease"
+	"github.com/porter-dev/porter/internal/registry"
+	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/repository/credentials"
 	"github.com/porter-dev/porter/internal/repository/gorm"
+	"github.com/porter-dev/porter/internal/settings"
+	"github.com/porter-dev/porter/internal/usage"
 
 	lr "github.com/porter-dev/porter/internal/logger"
 
@@ -32,6 +46,7 @@ import (
 )
 
 var InstanceBillingManager billing.BillingManager
+var InstanceLicense *types.License
 var InstanceEnvConf *envloader.EnvConf
 var InstanceDB *pgorm.DB
 var InstanceCredentialBackend credentials.CredentialStorage
@@ -68,6 +83,7 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 		RedisConf:         envConf.RedisConf,
 		BillingManager:    InstanceBillingManager,
 		CredentialBackend: InstanceCredentialBackend,
+		License:           InstanceLicense,
 	}
 
 	res.Metadata = config.MetadataFromConf(envConf.ServerConf, e.version)
@@ -87,14 +103,16 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 
 	res.Repo = gorm.NewRepository(InstanceDB, &key, InstanceCredentialBackend)
 
-	// create the session store
-	res.Store, err = sessionstore.NewStore(
-		&sessionstore.NewStoreOpts{
-			SessionRepository: res.Repo.Session(),
-			CookieSecrets:     envConf.ServerConf.CookieSecrets,
-		},
+	res.EntitlementManager = billing.NewEntitlementManager(
+		res.Repo,
+		sc.IronPlansAPIKey != "" && sc.IronPlansServerURL != "",
+		sc.UsageTrackingEnabled,
+		InstanceLicense,
 	)
 
+	// create the session store
+	res.Store, err = getSessionStore(res.Repo.Session(), envConf.RedisConf, sc)
+
 	if err != nil {
 		return nil, err
 	}
@@ -116,10 +134,10 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 		})
 	}
 
-	res.Alerter = alerter.NoOpAlerter{}
+	res.Alerter, err = getAlerter(sc)
 
-	if envConf.ServerConf.SentryDSN != "" {
-		res.Alerter, err = alerter.NewSentryAlerter(envConf.ServerConf.SentryDSN, envConf.ServerConf.SentryEnv)
+	if err != nil {
+		return nil, err
 	}
 
 	if sc.DOClientID != "" && sc.DOClientSecret != "" {
@@ -166,6 +184,14 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 				Scopes:       []string{"read:user"},
 				BaseURL:      sc.ServerURL,
 			}, sc.GithubAppName, sc.GithubAppWebhookSecret, sc.GithubAppSecretPath, AppID)
+
+			// if the private key cannot be read, installation clients are created for each
+			// request and fail with the same error
+			res.GithubAppTokens, err = githubapp.NewTokenCache(res.Repo.GithubAppInstallation(), res.GithubAppConf, res.Logger)
+
+			if err != nil {
+				res.Logger.Warn().Err(err).Msg("could not create github app token cache")
+			}
 		}
 	}
 
@@ -176,6 +202,9 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 			Scopes: []string{
 				"incoming-webhook",
 				"team:read",
+				"commands",
+				"users:read",
+				"users:read.email",
 			},
 			BaseURL: sc.ServerURL,
 		})
@@ -192,16 +221,73 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 		},
 	}
 
-	// construct the whitelisted users map
-	wlUsers := make(map[uint]uint)
+	// construct the whitelisted users from the environment and the users whitelisted
+	// by instance admins
+	res.WhitelistedUsers = usage.NewWhitelistedUsers(sc.WhitelistedUsers, res.Repo.InstanceAdmin(), sc.SettingsCacheTTL)
 
-	for _, userID := range sc.WhitelistedUsers {
-		wlUsers[userID] = userID
+	if err := res.WhitelistedUsers.Reload(); err != nil {
+		return nil, err
 	}
 
-	res.WhitelistedUsers = wlUsers
+	if sc.InformerCacheTTL > 0 {
+		res.InformerCache = kubernetes.NewInformerCache(sc.InformerCacheTTL)
+	}
+
+	if sc.RegistryIndexTTL > 0 {
+		res.RegistryIndex, err = getRegistryIndex(envConf.RedisConf, sc, res)
+
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	res.URLCache = urlcache.Init(sc.DefaultApplicationHelmRepoURL, sc.DefaultAddonHelmRepoURL)
+	if sc.InventoryCacheTTL > 0 {
+		res.Inventory, err = getInventoryCache(envConf.RedisConf, sc, res)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res.HelmMirror, err = mirror.NewFromConf(sc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res.ImageMirror = imagemirror.NewFromConf(sc)
+
+	// load the settings of the environment, overridden by the settings of instance admins
+	res.Settings, err = settings.NewManager(res.Repo.ServerSetting(), map[types.ServerSettingKey]string{
+		types.ServerSettingAppRootDomain:                 sc.AppRootDomain,
+		types.ServerSettingDefaultApplicationHelmRepoURL: sc.DefaultApplicationHelmRepoURL,
+		types.ServerSettingDefaultAddonHelmRepoURL:       sc.DefaultAddonHelmRepoURL,
+		types.ServerSettingDisableAllowlist:              strconv.FormatBool(sc.DisableAllowlist),
+		types.ServerSettingAnalyticsOptOut:               strconv.FormatBool(sc.AnalyticsOptOut),
+	}, sc.SettingsCacheTTL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	urlCacheRepos, err := getURLCacheRepos(res.Settings, sc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res.URLCache = urlcache.Init(urlcache.Opts{
+		DefaultTTL:           sc.HelmRepoCacheTTL,
+		StaleWhileRevalidate: sc.HelmRepoCacheStaleWhileRevalidate,
+	}, urlCacheRepos...)
+
+	// the built-in pricing tables are used if the tables cannot be fetched, so that the
+	// server can start without access to the pricing URL
+	res.CostEstimator, err = pricing.NewEstimator(sc.PricingTablesURL, sc.PricingTablesTTL)
+
+	if err != nil {
+		res.Logger.Warn().Err(err).Msg("could not fetch pricing tables, using built-in tables")
+	}
 
 	provAgent, err := getProvisionerAgent(sc)
 
@@ -213,17 +299,217 @@ func (e *EnvConfigLoader) LoadConfig() (res *config.Config, err error) {
 
 	if res.ProvisionerAgent != nil && res.RedisConf.Enabled {
 		res.Metadata.Provisioning = true
+
+		res.ProvisionerLeases, err = getProvisionerLeases(res.RedisConf, sc, provAgent)
+
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	res.AnalyticsClient = analytics.InitializeAnalyticsSegmentClient(sc.SegmentClientKey, res.Logger)
+	res.AnalyticsClient, err = analytics.NewClient(&analytics.ClientOpts{
+		Provider:         sc.AnalyticsProvider,
+		SegmentClientKey: sc.SegmentClientKey,
+		PostHogAPIKey:    sc.PostHogAPIKey,
+		PostHogHost:      sc.PostHogHost,
+		FlushInterval:    sc.AnalyticsFlushInterval,
+		BatchSize:        sc.AnalyticsBatchSize,
+		OptedOut: func() bool {
+			return res.Settings.GetBool(types.ServerSettingAnalyticsOptOut)
+		},
+		Logger: res.Logger,
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
 	if sc.PowerDNSAPIKey != "" && sc.PowerDNSAPIServerURL != "" {
-		res.PowerDNSClient = powerdns.NewClient(sc.PowerDNSAPIServerURL, sc.PowerDNSAPIKey, sc.AppRootDomain)
+		res.PowerDNSClient = powerdns.NewClient(
+			sc.PowerDNSAPIServerURL,
+			sc.PowerDNSAPIKey,
+			res.Settings.Get(types.ServerSettingAppRootDomain),
+		)
+	}
+
+	if sc.OPAURL != "" {
+		res.OPAClient = opa.NewClient(sc.OPAURL)
+	}
+
+	res.ImageVerifier, err = cosign.NewFromConf(sc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// apply changes to the settings to the clients that were created with them
+	res.Settings.OnChange(func(s *settings.Manager) {
+		if repos, err := getURLCacheRepos(s, sc); err == nil {
+			go res.URLCache.SetRepos(repos...)
+		}
+
+		if res.PowerDNSClient != nil {
+			res.PowerDNSClient.SetRunDomain(s.Get(types.ServerSettingAppRootDomain))
+		}
+	})
+
+	return res, nil
+}
+
+// getURLCacheRepos returns the default application and addon repos followed by the
+// additional repos of the environment. A repo that is listed in the environment with a
+// TTL replaces the default repo with the same URL.
+func getURLCacheRepos(s *settings.Manager, sc *env.ServerConf) ([]urlcache.Repo, error) {
+	res := []urlcache.Repo{
+		{URL: s.Get(types.ServerSettingDefaultApplicationHelmRepoURL)},
+		{URL: s.Get(types.ServerSettingDefaultAddonHelmRepoURL)},
+	}
+
+	for _, repoStr := range sc.HelmRepoCacheURLs {
+		repo, err := urlcache.ParseRepo(repoStr)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid HELM_REPO_CACHE_URLS entry %q: %w", repoStr, err)
+		}
+
+		replaced := false
+
+		for i := range res {
+			if res[i].URL == repo.URL {
+				res[i] = repo
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			res = append(res, repo)
+		}
 	}
 
 	return res, nil
 }
 
+// getAlerter returns the alerter that is selected in the environment
+func getAlerter(sc *env.ServerConf) (alerter.Alerter, error) {
+	alerterName := sc.Alerter
+
+	if alerterName == "" && sc.SentryDSN != "" {
+		alerterName = "sentry"
+	}
+
+	switch alerterName {
+	case "":
+		return alerter.NoOpAlerter{}, nil
+	case "sentry":
+		if sc.SentryDSN == "" {
+			return nil, fmt.Errorf("SENTRY_DSN must be set to use the sentry alerter")
+		}
+
+		return alerter.NewSentryAlerter(sc.SentryDSN, sc.SentryEnv)
+	case "pagerduty":
+		if sc.PagerDutyRoutingKey == "" {
+			return nil, fmt.Errorf("PAGERDUTY_ROUTING_KEY must be set to use the pagerduty alerter")
+		}
+
+		return alerter.NewPagerDutyAlerter(sc.PagerDutyRoutingKey, sc.ServerURL), nil
+	case "opsgenie":
+		if sc.OpsgenieAPIKey == "" {
+			return nil, fmt.Errorf("OPSGENIE_API_KEY must be set to use the opsgenie alerter")
+		}
+
+		return alerter.NewOpsgenieAlerter(sc.OpsgenieAPIKey, sc.OpsgenieAPIURL, sc.ServerURL), nil
+	}
+
+	return nil, fmt.Errorf("unknown alerter %s", alerterName)
+}
+
+func getSessionStore(
+	sessionRepo repository.SessionRepository,
+	rc *env.RedisConf,
+	sc *env.ServerConf,
+) (sessions.Store, error) {
+	switch sc.SessionStore {
+	case "", "postgres":
+		return sessionstore.NewStore(
+			&sessionstore.NewStoreOpts{
+				SessionRepository: sessionRepo,
+				CookieSecrets:     sc.CookieSecrets,
+			},
+		)
+	case "redis":
+		client, err := adapter.NewRedisClient(rc)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not create redis client for session store: %v", err)
+		}
+
+		opts := &sessionstore.NewRedisStoreOpts{
+			Client:        client,
+			CookieSecrets: sc.CookieSecrets,
+		}
+
+		if sc.SessionStoreMigrate {
+			opts.Fallback = sessionRepo
+		}
+
+		return sessionstore.NewRedisStore(opts)
+	}
+
+	return nil, fmt.Errorf("unknown session store %s", sc.SessionStore)
+}
+
+// getRegistryIndex returns the registry index, which publishes its invalidations to the
+// other replicas if Redis is enabled
+func getRegistryIndex(rc *env.RedisConf, sc *env.ServerConf, res *config.Config) (*registry.RepositoryIndex, error) {
+	if !rc.Enabled {
+		return registry.NewRepositoryIndex(sc.RegistryIndexTTL, res.Repo, res.DOConf, nil), nil
+	}
+
+	client, err := adapter.NewRedisClient(rc)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create redis client for registry index: %v", err)
+	}
+
+	return registry.NewRepositoryIndex(sc.RegistryIndexTTL, res.Repo, res.DOConf, client), nil
+}
+
+func getInventoryCache(rc *env.RedisConf, sc *env.ServerConf, res *config.Config) (*usage.InventoryCache, error) {
+	if !rc.Enabled {
+		return usage.NewInventoryCache(sc.InventoryCacheTTL, res.Repo, res.DOConf, nil), nil
+	}
+
+	client, err := adapter.NewRedisClient(rc)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create redis client for inventory cache: %v", err)
+	}
+
+	return usage.NewInventoryCache(sc.InventoryCacheTTL, res.Repo, res.DOConf, client), nil
+}
+
+func getProvisionerLeases(rc *env.RedisConf, sc *env.ServerConf, provAgent *kubernetes.Agent) (*lease.Manager, error) {
+	client, err := adapter.NewRedisClient(rc)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create redis client for provisioner leases: %v", err)
+	}
+
+	// the hostname is the pod name when running in a cluster, which is unique per replica
+	workerID, err := os.Hostname()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// leases are renewed while the provisioner job of their operation is running
+	isOperationRunning := func(workspaceID string, generation int64) (bool, error) {
+		return provAgent.IsProvisionerJobRunning(sc.ProvisionerJobNamespace, workspaceID, generation)
+	}
+
+	return lease.NewManager(client, workerID, sc.ProvisionerLeaseTTL, isOperationRunning), nil
+}
+
 func getProvisionerAgent(sc *env.ServerConf) (*kubernetes.Agent, error) {
 	if sc.ProvisionerCluster == "kubeconfig" && sc.SelfKubeconfig != "" {
 		agent, err := local.GetSelfAgentFromFileConfig(sc.SelfKubeconfig)
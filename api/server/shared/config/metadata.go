@@ -1,6 +1,11 @@
 package config
 
-import "github.com/porter-dev/porter/api/server/shared/config/env"
+import (
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/analytics"
+	"github.com/porter-dev/porter/internal/imagemirror"
+)
 
 type Metadata struct {
 	Provisioning       bool   `json:"provisioner"`
@@ -12,9 +17,24 @@ type Metadata struct {
 	Email              bool   `json:"email"`
 	Analytics          bool   `json:"analytics"`
 	Version            string `json:"version"`
+	MinCLIVersion      string `json:"min_cli_version,omitempty"`
+	MaxCLIVersion      string `json:"max_cli_version,omitempty"`
+
+	// License is set for enterprise installations with an offline license
+	License *types.LicenseStatus `json:"license,omitempty"`
+
+	// ImageMirror is the registry that mirrors the public images that Porter uses, and the
+	// default images are the mirrored images if it is set
+	ImageMirror       string            `json:"image_mirror,omitempty"`
+	ImageMirrorStrict bool              `json:"image_mirror_strict"`
+	DefaultAppImage   string            `json:"default_app_image"`
+	DefaultJobImage   string            `json:"default_job_image"`
+	DefaultBuilders   map[string]string `json:"default_builders"`
 }
 
 func MetadataFromConf(sc *env.ServerConf, version string) *Metadata {
+	imageMirror := imagemirror.NewFromConf(sc)
+
 	return &Metadata{
 		// note: provisioning is set in the metadata after the loader is called
 		Provisioning:       false,
@@ -24,8 +44,18 @@ func MetadataFromConf(sc *env.ServerConf, version string) *Metadata {
 		GoogleLogin:        sc.GoogleClientID != "" && sc.GoogleClientSecret != "",
 		SlackNotifications: sc.SlackClientID != "" && sc.SlackClientSecret != "",
 		Email:              sc.SendgridAPIKey != "",
-		Analytics:          sc.SegmentClientKey != "",
+		Analytics:          hasAnalytics(sc),
 		Version:            version,
+		MinCLIVersion:      sc.MinCLIVersion,
+		MaxCLIVersion:      sc.MaxCLIVersion,
+		ImageMirror:        sc.ImageMirrorRegistry,
+		ImageMirrorStrict:  sc.ImageMirrorRegistry != "" && sc.ImageMirrorStrict,
+		DefaultAppImage:    imageMirror.Image(imagemirror.DefaultAppImage),
+		DefaultJobImage:    imageMirror.Image(imagemirror.DefaultJobImage),
+		DefaultBuilders: map[string]string{
+			"heroku": imageMirror.Image(imagemirror.DefaultHerokuBuilder),
+			"paketo": imageMirror.Image(imagemirror.DefaultPaketoBuilder),
+		},
 	}
 }
 
@@ -37,3 +67,14 @@ func hasGithubAppVars(sc *env.ServerConf) bool {
 		sc.GithubAppSecretPath != "" &&
 		sc.GithubAppID != ""
 }
+
+func hasAnalytics(sc *env.ServerConf) bool {
+	switch sc.AnalyticsProvider {
+	case "", analytics.ProviderSegment:
+		return sc.SegmentClientKey != ""
+	case analytics.ProviderPostHog:
+		return sc.PostHogAPIKey != ""
+	}
+
+	return false
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/gorilla/schema"
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
 )
 
 // Decoder populates a request form from the request body and URL.
@@ -70,10 +71,13 @@ func requestErrorFromJSONErr(err error) apierrors.RequestError {
 	} else if errors.As(err, &typeErr) {
 		clientErr = fmt.Errorf("Invalid type for body param %s: expected %s, got %s", typeErr.Field, typeErr.Type.Kind().String(), typeErr.Value)
 	} else {
-		return apierrors.NewErrPassThroughToClient(fmt.Errorf("Could not parse JSON request"), http.StatusBadRequest, err.Error())
+		return apierrors.WithCode(
+			apierrors.NewErrPassThroughToClient(fmt.Errorf("Could not parse JSON request"), http.StatusBadRequest, err.Error()),
+			types.ErrorCodeInvalidJSON,
+		)
 	}
 
-	return apierrors.NewErrPassThroughToClient(clientErr, http.StatusBadRequest)
+	return apierrors.WithCode(apierrors.NewErrPassThroughToClient(clientErr, http.StatusBadRequest), types.ErrorCodeInvalidJSON)
 }
 
 func requestErrorFromSchemaErr(err error) apierrors.RequestError {
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/types"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -67,7 +68,10 @@ func (v *DefaultValidator) Validate(s interface{}) apierrors.RequestError {
 
 func NewErrFailedRequestValidation(valError string) apierrors.RequestError {
 	// return 400 error since a validation error indicates an issue with the user request
-	return apierrors.NewErrPassThroughToClient(fmt.Errorf(valError), http.StatusBadRequest)
+	return apierrors.WithCode(
+		apierrors.NewErrPassThroughToClient(fmt.Errorf(valError), http.StatusBadRequest),
+		types.ErrorCodeValidationFailed,
+	)
 }
 
 // ValidationErrObject represents an error referencing a specific field in a struct that
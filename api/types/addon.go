@@ -0,0 +1,39 @@
+package types
+
+import "time"
+
+const (
+	URLParamAddonID URLParam = "addon_id"
+)
+
+// Addon is an addon installed in a cluster, along with the latest version of its chart
+// found by the last update check
+type Addon struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ClusterID uint      `json:"cluster_id"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+
+	ChartName    string `json:"chart_name"`
+	ChartVersion string `json:"chart_version"`
+	RepoURL      string `json:"repo_url"`
+
+	LatestVersion   string     `json:"latest_version"`
+	UpdateAvailable bool       `json:"update_available"`
+	LastCheckedAt   *time.Time `json:"last_checked_at"`
+}
+
+type ListAddonUpdatesRequest struct {
+	// Refresh checks the addons against their repo index before listing them, instead
+	// of waiting for the next background check
+	Refresh bool `schema:"refresh"`
+}
+
+type ListAddonUpdatesResponse []*Addon
+
+// UpgradeAddonRequest upgrades an addon to a version of its chart, keeping the values
+// of the installed release. The version defaults to the latest version.
+type UpgradeAddonRequest struct {
+	Version string `json:"version"`
+}
@@ -0,0 +1,18 @@
+package types
+
+// AllowedChart is an entry of the chart allow-list of a project. If a project has any
+// entries, only charts that match an entry can be deployed. An empty repo URL or chart
+// name matches any repo or chart.
+type AllowedChart struct {
+	ID        uint   `json:"id"`
+	ProjectID uint   `json:"project_id"`
+	RepoURL   string `json:"repo_url"`
+	ChartName string `json:"chart_name"`
+}
+
+type CreateAllowedChartRequest struct {
+	RepoURL   string `json:"repo_url" form:"required_without=ChartName"`
+	ChartName string `json:"chart_name" form:"required_without=RepoURL"`
+}
+
+type ListAllowedChartsResponse []*AllowedChart
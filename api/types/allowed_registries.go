@@ -0,0 +1,7 @@
+package types
+
+type UpdateProjectAllowedRegistriesRequest struct {
+	// Registries are the registries that the images of releases may be pulled from, such
+	// as gcr.io/my-project. The allow-list is removed if it is empty.
+	Registries []string `json:"registries"`
+}
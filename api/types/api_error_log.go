@@ -0,0 +1,46 @@
+package types
+
+import "time"
+
+// APIErrorCategory groups failed API operations by their likely cause
+type APIErrorCategory string
+
+const (
+	APIErrorCategoryAuthentication APIErrorCategory = "authentication"
+	APIErrorCategoryPermission     APIErrorCategory = "permission"
+	APIErrorCategoryNotFound       APIErrorCategory = "not_found"
+	APIErrorCategoryValidation     APIErrorCategory = "validation"
+	APIErrorCategoryConflict       APIErrorCategory = "conflict"
+	APIErrorCategoryLimit          APIErrorCategory = "limit"
+	APIErrorCategoryUnavailable    APIErrorCategory = "unavailable"
+	APIErrorCategoryInternal       APIErrorCategory = "internal"
+)
+
+// MaxListedAPIErrorLogs is the maximum number of failed API operations that are listed
+// for a project at once
+const MaxListedAPIErrorLogs = 100
+
+// APIErrorLog is a failed API operation of a project. Only the error that was returned
+// to the client is stored, so internal errors are not exposed.
+type APIErrorLog struct {
+	ID           uint             `json:"id"`
+	CreatedAt    time.Time        `json:"created_at"`
+	Method       string           `json:"method"`
+	Path         string           `json:"path"`
+	Handler      string           `json:"handler"`
+	StatusCode   int              `json:"status_code"`
+	Error        string           `json:"error"`
+	ErrorCode    ErrorCode        `json:"error_code"`
+	Category     APIErrorCategory `json:"category"`
+	SuggestedFix string           `json:"suggested_fix,omitempty"`
+	ClusterID    uint             `json:"cluster_id,omitempty"`
+	Namespace    string           `json:"namespace,omitempty"`
+	ReleaseName  string           `json:"release_name,omitempty"`
+}
+
+type ListAPIErrorLogsRequest struct {
+	Category APIErrorCategory `schema:"category"`
+	Limit    int              `schema:"limit"`
+}
+
+type ListAPIErrorLogsResponse []*APIErrorLog
@@ -0,0 +1,125 @@
+package types
+
+import "time"
+
+const (
+	URLParamBackupName         URLParam = "backup_name"
+	URLParamBackupScheduleName URLParam = "schedule_name"
+)
+
+// InstallVeleroRequest installs Velero with a backup storage location in an object storage
+// bucket. The credentials of the cloud integration of the cluster are never used for the
+// bucket. On EKS clusters, the bucket is accessed with an IAM role for the service account
+// of Velero, and on GKE clusters with a GCP service account through workload identity.
+// Otherwise, the access keys of a credential that is scoped to the bucket must be set,
+// such as the access keys of a DigitalOcean Spaces bucket.
+type InstallVeleroRequest struct {
+	Bucket string `json:"bucket" form:"required"`
+	Prefix string `json:"prefix"`
+	Region string `json:"region"`
+
+	// S3URL is the endpoint of an S3-compatible bucket, which defaults to the Spaces
+	// endpoint of the region on DOKS clusters
+	S3URL string `json:"s3_url" form:"omitempty,url"`
+
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+
+	// IAMRoleARN is the ARN of an IAM role with access to the bucket, which the service
+	// account of Velero assumes through IRSA on EKS clusters
+	IAMRoleARN string `json:"iam_role_arn"`
+
+	// GCPServiceAccount is the email of a GCP service account with access to the bucket,
+	// which the service account of Velero impersonates through workload identity on GKE
+	// clusters
+	GCPServiceAccount string `json:"gcp_service_account" form:"omitempty,email"`
+}
+
+type VeleroStatusResponse struct {
+	Installed bool   `json:"installed"`
+	Version   string `json:"version,omitempty"`
+}
+
+type BackupPhase string
+
+const (
+	BackupPhaseNew             BackupPhase = "New"
+	BackupPhaseInProgress      BackupPhase = "InProgress"
+	BackupPhaseCompleted       BackupPhase = "Completed"
+	BackupPhasePartiallyFailed BackupPhase = "PartiallyFailed"
+	BackupPhaseFailed          BackupPhase = "Failed"
+	BackupPhaseDeleting        BackupPhase = "Deleting"
+)
+
+// Backup is a Velero backup of a namespace, or of the resources of a release in a
+// namespace if the release name is set
+type Backup struct {
+	Name         string      `json:"name"`
+	Namespace    string      `json:"namespace"`
+	ReleaseName  string      `json:"release_name,omitempty"`
+	ScheduleName string      `json:"schedule_name,omitempty"`
+	Phase        BackupPhase `json:"phase"`
+	Errors       int         `json:"errors"`
+	Warnings     int         `json:"warnings"`
+
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+type ListBackupsRequest struct {
+	Namespace   string `schema:"namespace"`
+	ReleaseName string `schema:"release_name"`
+}
+
+type ListBackupsResponse []*Backup
+
+// CreateBackupRequest creates an on-demand backup of a namespace, or of a release in the
+// namespace. The TTL is a duration such as 720h, and defaults to the TTL of Velero.
+type CreateBackupRequest struct {
+	Namespace   string `json:"namespace" form:"required"`
+	ReleaseName string `json:"release_name"`
+	TTL         string `json:"ttl"`
+}
+
+// BackupSchedule is a Velero schedule that backs up a namespace, or a release in the
+// namespace, on a cron schedule
+type BackupSchedule struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	ReleaseName string `json:"release_name,omitempty"`
+	Schedule    string `json:"schedule"`
+	TTL         string `json:"ttl,omitempty"`
+	Paused      bool   `json:"paused"`
+
+	LastBackupAt *time.Time `json:"last_backup_at,omitempty"`
+}
+
+type ListBackupSchedulesResponse []*BackupSchedule
+
+// CreateBackupScheduleRequest creates a schedule that backs up a namespace, or a release
+// in the namespace. The name defaults to the name of the namespace and release.
+type CreateBackupScheduleRequest struct {
+	Name        string `json:"name" form:"omitempty,max=63"`
+	Namespace   string `json:"namespace" form:"required"`
+	ReleaseName string `json:"release_name"`
+
+	// Schedule is a cron expression, such as 0 3 * * *
+	Schedule string `json:"schedule" form:"required"`
+	TTL      string `json:"ttl"`
+}
+
+// RestoreBackupRequest restores a backup into the namespace that it was taken from. A
+// backup of a namespace can be restored for a single release by setting the release name.
+// Resources that still exist in the namespace are updated to their state in the backup,
+// unless the existing resource policy is none.
+type RestoreBackupRequest struct {
+	ReleaseName            string `json:"release_name"`
+	ExistingResourcePolicy string `json:"existing_resource_policy" form:"omitempty,oneof=none update"`
+}
+
+type Restore struct {
+	Name       string `json:"name"`
+	BackupName string `json:"backup_name"`
+	Phase      string `json:"phase"`
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type AddProjectBillingRequest struct {
 	ProjectID uint `json:"project_id" form:"required"`
 
@@ -13,3 +15,64 @@ type AddProjectBillingRequest struct {
 
 	ExistingPlanName string `json:"existing_plan_name"`
 }
+
+// Invoice is an invoice of the billing provider. Amounts are in cents of the currency.
+type Invoice struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	AmountDue   uint       `json:"amount_due"`
+	AmountPaid  uint       `json:"amount_paid"`
+	Currency    string     `json:"currency"`
+	PeriodStart *time.Time `json:"period_start,omitempty"`
+	PeriodEnd   *time.Time `json:"period_end,omitempty"`
+
+	// HostedURL is the page of the billing provider where the invoice can be viewed
+	// and paid, if any
+	HostedURL string `json:"hosted_url,omitempty"`
+}
+
+// GetUpcomingInvoiceResponse is the upcoming invoice of a project, which is nil if the
+// project does not have one
+type GetUpcomingInvoiceResponse struct {
+	Invoice *Invoice `json:"invoice"`
+}
+
+type ListInvoicesResponse []*Invoice
+
+// PaymentMethod is the default payment method of a project's billing team
+type PaymentMethod struct {
+	HasPaymentMethod bool `json:"has_payment_method"`
+
+	// Type is the type of the payment method, such as "card"
+	Type     string `json:"type,omitempty"`
+	Brand    string `json:"brand,omitempty"`
+	Last4    string `json:"last4,omitempty"`
+	ExpMonth uint   `json:"exp_month,omitempty"`
+	ExpYear  uint   `json:"exp_year,omitempty"`
+
+	// IsExpired is set if the card expired before the current month
+	IsExpired bool `json:"is_expired"`
+}
+
+// Entitlement is a capability of the app that is only available on some billing plans
+type Entitlement string
+
+const (
+	EntitlementPreviewEnvironments Entitlement = "preview_environments"
+	EntitlementSSO                 Entitlement = "sso"
+)
+
+// ProjectEntitlements are the capabilities that the billing plan of a project includes
+type ProjectEntitlements struct {
+	PreviewEnvironments bool `json:"preview_environments"`
+	SSO                 bool `json:"sso"`
+
+	// MaxClusters is the maximum number of clusters, or 0 if unlimited
+	MaxClusters uint `json:"max_clusters"`
+
+	// IsTrial is set if the plan is a trial, which ends at TrialEndsAt. Projects whose
+	// trial has expired are downgraded to the basic plan.
+	IsTrial      bool       `json:"is_trial"`
+	TrialEndsAt  *time.Time `json:"trial_ends_at,omitempty"`
+	TrialExpired bool       `json:"trial_expired"`
+}
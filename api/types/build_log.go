@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+// MaxBuildLogBytes is the maximum size of stored build logs. Longer logs are truncated
+// from the start, since the end of a log usually shows why a build failed.
+const MaxBuildLogBytes = 1 << 20
+
+type BuildLog struct {
+	ID        uint        `json:"id"`
+	CreatedAt time.Time   `json:"created_at"`
+	CommitSHA string      `json:"commit_sha"`
+	Status    EventStatus `json:"status"`
+
+	// Logs are only set when a single build log is read
+	Logs string `json:"logs,omitempty"`
+}
+
+type CreateBuildLogRequest struct {
+	CommitSHA string      `json:"commit_sha" form:"required"`
+	Status    EventStatus `json:"status" form:"required,oneof=1 3"`
+	Logs      string      `json:"logs"`
+}
+
+type ListBuildLogsResponse []*BuildLog
+
+type GetBuildLogResponse BuildLog
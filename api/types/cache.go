@@ -0,0 +1,41 @@
+package types
+
+type CacheEngine string
+
+const (
+	CacheEngineRedis     CacheEngine = "redis"
+	CacheEngineMemcached CacheEngine = "memcached"
+)
+
+// CreateCacheRequest provisions an in-cluster Redis or memcached addon in a namespace,
+// and creates an env group with the connection details of the cache. The env group is
+// synced to the releases of the namespace that are listed, which are redeployed with
+// the variables of the env group. The env group name defaults to the name of the cache
+// with a -connection suffix.
+type CreateCacheRequest struct {
+	Name    string      `json:"name" form:"required,max=63"`
+	Engine  CacheEngine `json:"engine" form:"required,oneof=redis memcached"`
+	Version string      `json:"version"`
+
+	// StorageSize is the size of the persistent volume of Redis, such as 8Gi. Memcached is
+	// not persisted.
+	StorageSize string `json:"storage_size"`
+
+	EnvGroupName string   `json:"env_group_name" form:"omitempty,max=63"`
+	ReleaseNames []string `json:"release_names"`
+}
+
+type CreateCacheResponse struct {
+	Name     string      `json:"name"`
+	Engine   CacheEngine `json:"engine"`
+	Host     string      `json:"host"`
+	Port     int         `json:"port"`
+	EnvGroup *EnvGroup   `json:"env_group"`
+
+	// Releases are the releases that the env group was synced to
+	Releases []string `json:"releases"`
+
+	// ChangeRequests are the change requests of protected releases, which are synced to
+	// the env group once they are approved
+	ChangeRequests []*ReleaseChangeRequest `json:"change_requests,omitempty"`
+}
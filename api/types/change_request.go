@@ -0,0 +1,77 @@
+package types
+
+import "time"
+
+type ChangeRequestOperation string
+
+const (
+	ChangeRequestUpgrade          ChangeRequestOperation = "upgrade"
+	ChangeRequestDelete           ChangeRequestOperation = "delete"
+	ChangeRequestRollback         ChangeRequestOperation = "rollback"
+	ChangeRequestKustomizePatches ChangeRequestOperation = "kustomize_patches"
+)
+
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestPending  ChangeRequestStatus = "pending"
+	ChangeRequestApproved ChangeRequestStatus = "approved"
+	ChangeRequestRejected ChangeRequestStatus = "rejected"
+	ChangeRequestFailed   ChangeRequestStatus = "failed"
+)
+
+// ReleaseChangeRequest is an upgrade, rollback, deletion or kustomize patches change of a
+// protected release, which is only
+// applied once it is approved by a user other than the requester
+type ReleaseChangeRequest struct {
+	ID           uint                   `json:"id"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+	Namespace    string                 `json:"namespace"`
+	Name         string                 `json:"name"`
+	Operation    ChangeRequestOperation `json:"operation"`
+	Status       ChangeRequestStatus    `json:"status"`
+	ChartVersion string                 `json:"chart_version,omitempty"`
+
+	// ValuesDiff lists the values that an upgrade adds (+), removes (-) or changes (~).
+	// Sensitive values are redacted.
+	ValuesDiff []string `json:"values_diff"`
+
+	// BaseRevision is the revision of the release that the change was requested for
+	BaseRevision int `json:"base_revision"`
+
+	// Revision is the revision that a rollback rolls the release back to
+	Revision int `json:"revision,omitempty"`
+
+	// Cascade and DeleteVolumes are the options of a deletion
+	Cascade       bool `json:"cascade,omitempty"`
+	DeleteVolumes bool `json:"delete_volumes,omitempty"`
+
+	// KustomizePatches are the kustomize patches that a kustomize patches change sets
+	KustomizePatches string `json:"kustomize_patches,omitempty"`
+
+	RequestedBy uint   `json:"requested_by"`
+	ReviewedBy  uint   `json:"reviewed_by,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type ListChangeRequestsRequest struct {
+	Status ChangeRequestStatus `schema:"status"`
+}
+
+type ListChangeRequestsResponse []*ReleaseChangeRequest
+
+type UpdateProtectionRequest struct {
+	Protected bool `json:"protected"`
+}
+
+type ReleaseProtectionResponse struct {
+	Protected bool `json:"protected"`
+
+	// NamespaceProtected is true if the release is protected because its namespace is
+	NamespaceProtected bool `json:"namespace_protected"`
+}
+
+type NamespaceProtectionResponse struct {
+	Protected bool `json:"protected"`
+}
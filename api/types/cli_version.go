@@ -0,0 +1,14 @@
+package types
+
+type UpdateProjectCLIVersionRequest struct {
+	// Version is the CLI version to pin, such as v0.20.0. The pin is removed if it is empty.
+	Version string `json:"version"`
+}
+
+// ServerMetadata is the part of the server metadata that describes the versions of the
+// server and of the CLIs that it supports
+type ServerMetadata struct {
+	Version       string `json:"version"`
+	MinCLIVersion string `json:"min_cli_version,omitempty"`
+	MaxCLIVersion string `json:"max_cli_version,omitempty"`
+}
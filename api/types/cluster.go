@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
 	v1 "k8s.io/api/core/v1"
 )
@@ -30,6 +32,12 @@ type Cluster struct {
 
 	// (optional) The aws integration id, if available
 	AWSIntegrationID uint `json:"aws_integration_id"`
+
+	// The Pod Security Standards level enforced on the namespaces managed by Porter
+	PodSecurityLevel PodSecurityLevel `json:"pod_security_level"`
+
+	// The address that the load balancer of the ingress controller is pinned to, if any
+	IngressLoadBalancer *IngressLoadBalancer `json:"ingress_load_balancer,omitempty"`
 }
 
 type ClusterCandidate struct {
@@ -200,6 +208,42 @@ type DeleteNamespaceRequest struct {
 	Name string `json:"name" form:"required"`
 }
 
+// BulkDeleteNamespacesRequest selects the preview environment namespaces to delete. Only
+// namespaces with the preview environment prefix are selected.
+type BulkDeleteNamespacesRequest struct {
+	// Pattern is a glob that the names of the namespaces must match, such as pr-12*. All
+	// preview environment namespaces match if it is empty.
+	Pattern string `json:"pattern"`
+
+	// OlderThan is the minimum age of the namespaces, such as 72h
+	OlderThan string `json:"older_than"`
+
+	// DryRun lists the namespaces that would be deleted without deleting them
+	DryRun bool `json:"dry_run"`
+
+	// Force deletes namespaces that contain resources that were not created by Porter
+	Force bool `json:"force"`
+}
+
+type BulkDeleteNamespacesResponse struct {
+	DryRun     bool                   `json:"dry_run"`
+	Namespaces []*BulkDeleteNamespace `json:"namespaces"`
+}
+
+type BulkDeleteNamespace struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Deleted   bool      `json:"deleted"`
+
+	// NonPorterResources are the resources of the namespace that were not created by
+	// Porter, of the form kind/name. Namespaces with such resources are only deleted if
+	// the request is forced.
+	NonPorterResources []string `json:"non_porter_resources,omitempty"`
+
+	// Error is the reason that the namespace was not deleted
+	Error string `json:"error,omitempty"`
+}
+
 type GetTemporaryKubeconfigResponse struct {
 	Kubeconfig []byte `json:"kubeconfig"`
 }
@@ -0,0 +1,60 @@
+package types
+
+import "time"
+
+const URLParamCustomChartID URLParam = "custom_chart_id"
+
+// CustomChartRepoURL is the repo URL of the custom charts of a project. Releases are
+// created from a custom chart with this repo URL, the name of the chart as the template
+// name and the version of the chart as the template version.
+const CustomChartRepoURL = "porter://custom-charts"
+
+// CustomChartSource is where a version of a custom chart was loaded from
+type CustomChartSource string
+
+const (
+	CustomChartSourceUpload CustomChartSource = "upload"
+	CustomChartSourceGithub CustomChartSource = "github"
+)
+
+// CustomChart is a version of a Helm chart that was provided by a project, which
+// releases can be created from instead of the Porter templates
+type CustomChart struct {
+	ID          uint              `json:"id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	ProjectID   uint              `json:"project_id"`
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Description string            `json:"description"`
+	Source      CustomChartSource `json:"source"`
+
+	// The repo, branch, path and commit that the chart was loaded from, if the source
+	// is github
+	GitRepoID uint   `json:"git_repo_id,omitempty"`
+	GitRepo   string `json:"git_repo,omitempty"`
+	GitBranch string `json:"git_branch,omitempty"`
+	ChartPath string `json:"chart_path,omitempty"`
+	CommitSHA string `json:"commit_sha,omitempty"`
+
+	// ImageValuesKey is the dot-separated key of the image repository and tag in the
+	// values of the chart, which the deploy webhook updates
+	ImageValuesKey string `json:"image_values_key"`
+}
+
+// CreateCustomChartRequest adds a version of a custom chart, either from a packaged
+// chart or from a directory of a Github repo. The name and version of the chart are read
+// from its Chart.yaml, and each version can only be added once.
+type CreateCustomChartRequest struct {
+	// Archive is a packaged chart, such as the archive created by "helm package"
+	Archive []byte `json:"archive" form:"required_without=GitRepo"`
+
+	GitRepoID uint   `json:"git_repo_id" form:"required_with=GitRepo"`
+	GitRepo   string `json:"git_repo" form:"required_without=Archive"`
+	GitBranch string `json:"git_branch"`
+	ChartPath string `json:"chart_path"`
+
+	// ImageValuesKey defaults to "image"
+	ImageValuesKey string `json:"image_values_key"`
+}
+
+type ListCustomChartsResponse []*CustomChart
@@ -19,3 +19,61 @@ type Database struct {
 }
 
 type ListDatabaseResponse []*Database
+
+// Bucket is an object storage bucket provisioned by Porter. The credentials of the bucket
+// are not returned, and are injected into releases through the env group of the bucket.
+type Bucket struct {
+	ID uint `json:"id"`
+
+	ProjectID uint `json:"project_id"`
+	ClusterID uint `json:"cluster_id"`
+	InfraID   uint `json:"infra_id"`
+
+	Kind   InfraKind `json:"kind"`
+	Name   string    `json:"name"`
+	Region string    `json:"region"`
+
+	// The env group with the bucket name and credentials, in the namespace that the
+	// bucket was provisioned from
+	Namespace    string `json:"namespace"`
+	EnvGroupName string `json:"env_group_name"`
+
+	Status string `json:"status"`
+}
+
+type ListBucketResponse []*Bucket
+
+// DefaultQueueScalerTarget is the number of messages per replica that the scaler trigger
+// of a provisioned queue targets by default
+const DefaultQueueScalerTarget = 5
+
+// Queue is a message queue provisioned by Porter. The credentials of the queue are not
+// returned, and are injected into releases through the env group of the queue.
+type Queue struct {
+	ID uint `json:"id"`
+
+	ProjectID uint `json:"project_id"`
+	ClusterID uint `json:"cluster_id"`
+	InfraID   uint `json:"infra_id"`
+
+	Kind   InfraKind `json:"kind"`
+	Name   string    `json:"name"`
+	Region string    `json:"region,omitempty"`
+
+	// URL is set for SQS queues, and SubscriptionName for Pub/Sub topics
+	URL              string `json:"url,omitempty"`
+	SubscriptionName string `json:"subscription_name,omitempty"`
+
+	// The env group with the queue details and credentials, in the namespace that the
+	// queue was provisioned from
+	Namespace    string `json:"namespace"`
+	EnvGroupName string `json:"env_group_name"`
+
+	Status string `json:"status"`
+
+	// ScalerTrigger is a trigger for the queue scaler of a release, which scales a
+	// worker on the depth of the queue once the env group is synced to the worker
+	ScalerTrigger *ScalerTrigger `json:"scaler_trigger,omitempty"`
+}
+
+type ListQueueResponse []*Queue
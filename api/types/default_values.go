@@ -0,0 +1,12 @@
+package types
+
+// UpdateDefaultValuesRequest sets the default values of a project or cluster. Default
+// values are merged beneath the values of new releases, with cluster defaults taking
+// precedence over project defaults. A release can unset a default by setting it to null.
+type UpdateDefaultValuesRequest struct {
+	Values map[string]interface{} `json:"values"`
+}
+
+type DefaultValuesResponse struct {
+	Values map[string]interface{} `json:"values"`
+}
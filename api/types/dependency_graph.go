@@ -0,0 +1,57 @@
+package types
+
+// DependencyReason is the reason that a release depends on another release
+type DependencyReason string
+
+const (
+	// DependencyReasonExplicit is a dependency that was declared by the user
+	DependencyReasonExplicit DependencyReason = "explicit"
+
+	// DependencyReasonEnv is a reference to a release from the values of an env group
+	// that the release uses
+	DependencyReasonEnv DependencyReason = "env"
+
+	// DependencyReasonValues is a reference to a release from the values of the release,
+	// such as the cluster DNS name of a service
+	DependencyReasonValues DependencyReason = "values"
+)
+
+type GetDependencyGraphRequest struct {
+	// Namespace limits the graph to the releases in a namespace. The graph of the whole
+	// cluster is returned if it is empty.
+	Namespace string `schema:"namespace"`
+}
+
+// DependencyGraph is the graph of dependencies between releases
+type DependencyGraph struct {
+	Nodes []*DependencyGraphNode `json:"nodes"`
+	Edges []*DependencyGraphEdge `json:"edges"`
+
+	// Stages are the IDs of the releases grouped in deploy order: the releases in a stage
+	// only depend on releases in earlier stages
+	Stages [][]string `json:"stages"`
+
+	// Cyclic are the IDs of the releases that are part of, or depend on, a dependency
+	// cycle, and cannot be ordered
+	Cyclic []string `json:"cyclic"`
+}
+
+type DependencyGraphNode struct {
+	// ID is of the form namespace/name
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// DependencyGraphEdge is a dependency of the release From on the release To
+type DependencyGraphEdge struct {
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	Reasons []DependencyReason `json:"reasons"`
+}
+
+type UpdateReleaseDependenciesRequest struct {
+	// Dependencies are the releases that the release depends on, of the form name for
+	// releases in the same namespace or namespace/name
+	Dependencies []string `json:"dependencies" form:"dive,required"`
+}
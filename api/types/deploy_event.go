@@ -0,0 +1,40 @@
+package types
+
+// DeploySource is where a deploy of a release was started from
+type DeploySource string
+
+const (
+	DeploySourceDashboard    DeploySource = "dashboard"
+	DeploySourceCLI          DeploySource = "cli"
+	DeploySourceGithubAction DeploySource = "github_action"
+	DeploySourceWebhook      DeploySource = "webhook"
+
+	// DeploySourceAPI is the source of deploys with an API token from other clients
+	DeploySourceAPI DeploySource = "api"
+
+	// DeploySourceScheduled is the source of deploys that were scheduled for a later time
+	DeploySourceScheduled DeploySource = "scheduled"
+
+	// DeploySourcePipeline, DeploySourceSlack and DeploySourceGitOps are the sources of
+	// deploys by pipeline promotions, Slack commands and GitOps reconciles
+	DeploySourcePipeline DeploySource = "pipeline"
+	DeploySourceSlack    DeploySource = "slack"
+	DeploySourceGitOps   DeploySource = "gitops"
+)
+
+// DeploySourceHeader is the header that the CLI identifies itself with
+const DeploySourceHeader = "X-Porter-Source"
+
+// DeployInitiatorKind is the kind of subject that started a deploy
+type DeployInitiatorKind string
+
+const (
+	DeployInitiatorUser    DeployInitiatorKind = "user"
+	DeployInitiatorToken   DeployInitiatorKind = "token"
+	DeployInitiatorWebhook DeployInitiatorKind = "webhook"
+)
+
+// DeployEventID is the event ID of the sub events that record deploys
+const DeployEventID = "deploy"
+
+type GetReleaseEventsResponse []SubEvent
@@ -0,0 +1,61 @@
+package types
+
+import "time"
+
+// DeployFreezeOverrideHeader is the header with the reason for overriding an active
+// deploy freeze. Only project admins can override a freeze, and each override is
+// recorded with its reason.
+const DeployFreezeOverrideHeader = "X-Porter-Freeze-Override"
+
+type DeployFreeze struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// ClusterID is omitted for freezes of all clusters of the project
+	ClusterID uint   `json:"cluster_id,omitempty"`
+	Name      string `json:"name"`
+
+	// Schedule is a cron expression for the start of each window of a recurring freeze,
+	// which lasts for the duration in minutes. The schedule is evaluated in the timezone,
+	// or in UTC if no timezone is set.
+	Schedule        string `json:"schedule,omitempty"`
+	DurationMinutes uint   `json:"duration_minutes,omitempty"`
+	Timezone        string `json:"timezone,omitempty"`
+
+	// StartsAt and EndsAt are the range of a freeze that does not recur
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+
+	CreatedBy uint `json:"created_by"`
+
+	// ActiveUntil is the end of the current window of the freeze, if it is active
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
+}
+
+type CreateDeployFreezeRequest struct {
+	ClusterID uint   `json:"cluster_id"`
+	Name      string `json:"name" form:"required,max=255"`
+
+	// the duration of each window of a recurring freeze is at most 31 days
+	Schedule        string `json:"schedule" form:"required_without=StartsAt"`
+	DurationMinutes uint   `json:"duration_minutes" form:"required_with=Schedule,max=44640"`
+	Timezone        string `json:"timezone"`
+
+	StartsAt *time.Time `json:"starts_at" form:"required_without=Schedule"`
+	EndsAt   *time.Time `json:"ends_at" form:"required_with=StartsAt"`
+}
+
+type ListDeployFreezesResponse []*DeployFreeze
+
+type DeployFreezeOverride struct {
+	ID             uint      `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	ClusterID      uint      `json:"cluster_id,omitempty"`
+	DeployFreezeID uint      `json:"deploy_freeze_id"`
+	UserID         uint      `json:"user_id"`
+	Method         string    `json:"method"`
+	Path           string    `json:"path"`
+	Reason         string    `json:"reason"`
+}
+
+type ListDeployFreezeOverridesResponse []*DeployFreezeOverride
@@ -11,12 +11,50 @@ type Environment struct {
 	GitRepoName       string `json:"git_repo_name"`
 
 	Name string `json:"name"`
+
+	ForkApprovalRequired bool `json:"fork_approval_required"`
+
+	RoutingMode     PreviewRoutingMode `json:"routing_mode"`
+	PathRoutingHost string             `json:"path_routing_host,omitempty"`
 }
 
+// PreviewRoutingMode determines how the web applications of preview environments are
+// exposed
+type PreviewRoutingMode string
+
+const (
+	// PreviewRoutingSubdomain exposes every application on its own subdomain, which
+	// needs a DNS record per application
+	PreviewRoutingSubdomain PreviewRoutingMode = "subdomain"
+
+	// PreviewRoutingPath exposes the applications on a single shared host, under path
+	// prefixes such as /pr-123/web/, for clusters without wildcard DNS
+	PreviewRoutingPath PreviewRoutingMode = "path"
+)
+
 type CreateEnvironmentRequest struct {
 	Name string `json:"name" form:"required"`
+
+	// ForkApprovalRequired determines if pull requests from forks are only deployed once
+	// a maintainer approves them, by commenting "/porter approve" or from the dashboard
+	ForkApprovalRequired bool `json:"fork_approval_required"`
+
+	// PathRoutingHost is required if the routing mode is path
+	RoutingMode     PreviewRoutingMode `json:"routing_mode" form:"omitempty,oneof=subdomain path"`
+	PathRoutingHost string             `json:"path_routing_host" form:"omitempty,hostname"`
+}
+
+// UpdateEnvironmentSettingsRequest only updates the settings that are set
+type UpdateEnvironmentSettingsRequest struct {
+	ForkApprovalRequired *bool              `json:"fork_approval_required"`
+	RoutingMode          PreviewRoutingMode `json:"routing_mode" form:"omitempty,oneof=subdomain path"`
+	PathRoutingHost      *string            `json:"path_routing_host" form:"omitempty,hostname"`
 }
 
+// PreviewApproveCommand is the pull request comment that maintainers approve the preview
+// deployment of a pull request from a fork with
+const PreviewApproveCommand = "/porter approve"
+
 type GitHubMetadata struct {
 	DeploymentID int64  `json:"gh_deployment_id"`
 	PRName       string `json:"gh_pr_name"`
@@ -32,6 +70,10 @@ const (
 	DeploymentStatusCreating DeploymentStatus = "creating"
 	DeploymentStatusInactive DeploymentStatus = "inactive"
 	DeploymentStatusFailed   DeploymentStatus = "failed"
+
+	// DeploymentStatusPendingApproval is the status of deployments of pull requests from
+	// forks that are waiting for the approval of a maintainer
+	DeploymentStatusPendingApproval DeploymentStatus = "pending_approval"
 )
 
 type Deployment struct {
@@ -46,6 +88,9 @@ type Deployment struct {
 	Status            DeploymentStatus `json:"status"`
 	Subdomain         string           `json:"subdomain"`
 	PullRequestID     uint             `json:"pull_request_id"`
+	IsFork            bool             `json:"is_fork"`
+	ApprovedBy        string           `json:"approved_by,omitempty"`
+	ApprovedCommitSHA string           `json:"approved_commit_sha,omitempty"`
 }
 
 type CreateGHDeploymentRequest struct {
@@ -88,6 +133,20 @@ type DeleteDeploymentRequest struct {
 	Namespace string `json:"namespace" form:"required"`
 }
 
+type ApproveDeploymentRequest struct {
+	Namespace string `json:"namespace" form:"required"`
+}
+
+// CheckDeploymentApprovalRequest is sent by the preview workflow before it checks out the
+// code of a pull request, so that the code of pull requests from forks only runs once the
+// commit was approved
+type CheckDeploymentApprovalRequest struct {
+	Namespace     string `json:"namespace" form:"required"`
+	PullRequestID uint   `json:"pull_request_id" form:"required"`
+	CommitSHA     string `json:"commit_sha" form:"required"`
+	ActionID      uint   `json:"action_id"`
+}
+
 type GetDeploymentRequest struct {
 	Namespace string `schema:"namespace" form:"required"`
 }
@@ -4,9 +4,68 @@ const (
 	ErrCodeUnavailable uint = 601
 )
 
+// ErrorCode is a machine-readable code that is returned with every API error, so that
+// clients can handle well-known errors without matching the error message
+type ErrorCode string
+
+// Generic error codes, which are returned for errors without a more specific code
+const (
+	ErrorCodeInternal      ErrorCode = "PORTER_ERR_INTERNAL"
+	ErrorCodeBadRequest    ErrorCode = "PORTER_ERR_BAD_REQUEST"
+	ErrorCodeUnauthorized  ErrorCode = "PORTER_ERR_UNAUTHORIZED"
+	ErrorCodeForbidden     ErrorCode = "PORTER_ERR_FORBIDDEN"
+	ErrorCodeNotFound      ErrorCode = "PORTER_ERR_NOT_FOUND"
+	ErrorCodeConflict      ErrorCode = "PORTER_ERR_CONFLICT"
+	ErrorCodeLimitExceeded ErrorCode = "PORTER_ERR_LIMIT_EXCEEDED"
+	ErrorCodeUnknown       ErrorCode = "PORTER_ERR_UNKNOWN"
+)
+
+// Error codes of requests that could not be decoded or validated
+const (
+	ErrorCodeInvalidJSON      ErrorCode = "PORTER_ERR_INVALID_JSON"
+	ErrorCodeValidationFailed ErrorCode = "PORTER_ERR_VALIDATION_FAILED"
+)
+
+// Error codes of authentication and plans
+const (
+	ErrorCodeNotLoggedIn           ErrorCode = "PORTER_ERR_NOT_LOGGED_IN"
+	ErrorCodeInvalidCredentials    ErrorCode = "PORTER_ERR_INVALID_CREDENTIALS"
+	ErrorCodeUnavailableInCE       ErrorCode = "PORTER_ERR_UNAVAILABLE_IN_CE"
+	ErrorCodeQuotaExceeded         ErrorCode = "PORTER_ERR_QUOTA_EXCEEDED"
+	ErrorCodeUsageLimitExceeded    ErrorCode = "PORTER_ERR_USAGE_LIMIT_EXCEEDED"
+	ErrorCodeEntitlementRequired   ErrorCode = "PORTER_ERR_ENTITLEMENT_REQUIRED"
+	ErrorCodeSSORequired           ErrorCode = "PORTER_ERR_SSO_REQUIRED"
+	ErrorCodeDeployPendingApproval ErrorCode = "PORTER_ERR_DEPLOY_PENDING_APPROVAL"
+)
+
+// Error codes of resources
+const (
+	ErrorCodeClusterNotFound     ErrorCode = "PORTER_ERR_CLUSTER_NOT_FOUND"
+	ErrorCodeReleaseNotFound     ErrorCode = "PORTER_ERR_RELEASE_NOT_FOUND"
+	ErrorCodeChartNotFound       ErrorCode = "PORTER_ERR_CHART_NOT_FOUND"
+	ErrorCodeEnvGroupNotFound    ErrorCode = "PORTER_ERR_ENV_GROUP_NOT_FOUND"
+	ErrorCodeRegistryAuth        ErrorCode = "PORTER_ERR_REGISTRY_AUTH"
+	ErrorCodeWebhookDisabled     ErrorCode = "PORTER_ERR_WEBHOOK_DISABLED"
+	ErrorCodeHelmOperationFailed ErrorCode = "PORTER_ERR_HELM_OPERATION_FAILED"
+	ErrorCodeChartNotAllowed     ErrorCode = "PORTER_ERR_CHART_NOT_ALLOWED"
+	ErrorCodeDeletionProtected   ErrorCode = "PORTER_ERR_DELETION_PROTECTED"
+	ErrorCodePreDeployFailed     ErrorCode = "PORTER_ERR_PRE_DEPLOY_FAILED"
+	ErrorCodePreDeployRunning    ErrorCode = "PORTER_ERR_PRE_DEPLOY_RUNNING"
+	ErrorCodeDeployFrozen        ErrorCode = "PORTER_ERR_DEPLOY_FROZEN"
+	ErrorCodeRevisionConflict    ErrorCode = "PORTER_ERR_REVISION_CONFLICT"
+	ErrorCodeImageNotMirrored    ErrorCode = "PORTER_ERR_IMAGE_NOT_MIRRORED"
+	ErrorCodeImageNotAllowed     ErrorCode = "PORTER_ERR_IMAGE_NOT_ALLOWED"
+	ErrorCodeImageNotSigned      ErrorCode = "PORTER_ERR_IMAGE_NOT_SIGNED"
+	ErrorCodeReleasePaused       ErrorCode = "PORTER_ERR_RELEASE_PAUSED"
+)
+
 type ExternalError struct {
 	// Optional error code for well-known error types
 	Code uint `json:"code,omitempty"`
 
+	// ErrorCode is set for every error, and is more specific than the status code for
+	// well-known errors
+	ErrorCode ErrorCode `json:"error_code,omitempty"`
+
 	Error string `json:"error"`
 }
@@ -0,0 +1,73 @@
+package types
+
+// ExposureValuesKey is the key of the values of a release that its service exposure is
+// stored under. The charts of releases ignore the key, and the exposure is rendered into
+// the ingress and service of the release when it is deployed.
+const ExposureValuesKey = "porterExposure"
+
+// ServiceProtocol is the protocol that a release is exposed with
+type ServiceProtocol string
+
+const (
+	// ServiceProtocolHTTP exposes a release through the ingress of its chart
+	ServiceProtocolHTTP ServiceProtocol = "http"
+
+	// ServiceProtocolGRPC exposes a release through the ingress of its chart, which
+	// forwards requests to the release over cleartext HTTP/2 (h2c)
+	ServiceProtocolGRPC ServiceProtocol = "grpc"
+
+	// ServiceProtocolTCP and ServiceProtocolUDP expose the service of a release without
+	// an ingress
+	ServiceProtocolTCP ServiceProtocol = "tcp"
+	ServiceProtocolUDP ServiceProtocol = "udp"
+)
+
+// ExposureMode is how a tcp or udp release is reachable from outside the cluster
+type ExposureMode string
+
+const (
+	// ExposureModeLoadBalancer exposes the service of a release through its own load
+	// balancer
+	ExposureModeLoadBalancer ExposureMode = "loadbalancer"
+
+	// ExposureModeProxy exposes the service of a release through the tcp and udp proxy of
+	// the ingress-nginx controller of the cluster, which shares the load balancer of the
+	// ingress controller
+	ExposureModeProxy ExposureMode = "proxy"
+
+	// ExposureModeInternal only exposes the service of a release inside the cluster, under
+	// its cluster DNS name. No ingress or subdomain is created for the release.
+	ExposureModeInternal ExposureMode = "internal"
+)
+
+// ServiceExposure is how the service of a release is exposed
+type ServiceExposure struct {
+	Protocol ServiceProtocol `json:"protocol" form:"required,oneof=http grpc tcp udp"`
+
+	// Mode defaults to loadbalancer for tcp and udp releases. Releases of other protocols
+	// only support the internal mode.
+	Mode ExposureMode `json:"mode,omitempty" form:"omitempty,oneof=loadbalancer proxy internal"`
+
+	// ProxyPort is the port of the ingress controller that proxies to the release, which
+	// is required if the mode is proxy. Ports that the ingress controller already uses,
+	// such as 80 and 443, cannot be used.
+	ProxyPort int `json:"proxy_port,omitempty" form:"omitempty,min=1,max=65535"`
+}
+
+// UsesIngress returns true if the release is exposed through the ingress of its chart
+func (e *ServiceExposure) UsesIngress() bool {
+	return !e.IsInternal() && (e.Protocol == ServiceProtocolHTTP || e.Protocol == ServiceProtocolGRPC)
+}
+
+// IsInternal returns true if the release is only reachable from inside the cluster
+func (e *ServiceExposure) IsInternal() bool {
+	return e.Mode == ExposureModeInternal
+}
+
+type UpdateServiceExposureRequest struct {
+	ServiceExposure
+}
+
+type GetServiceExposureResponse struct {
+	*ServiceExposure
+}
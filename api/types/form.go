@@ -43,6 +43,7 @@ type FormContent struct {
 		DisableAfterLaunch bool        `yaml:"disableAfterLaunch,omitempty" json:"disableAfterLaunch,omitempty"`
 		Options            interface{} `yaml:"options,omitempty" json:"options,omitempty"`
 		Placeholder        string      `yaml:"placeholder,omitempty" json:"placeholder,omitempty"`
+		Type               string      `yaml:"type,omitempty" json:"type,omitempty"`
 	} `yaml:"settings,omitempty" json:"settings,omitempty"`
 }
 
@@ -56,3 +57,9 @@ type FormYAML struct {
 	Tags                []string   `yaml:"tags" json:"tags"`
 	Tabs                []*FormTab `yaml:"tabs" json:"tabs,omitempty"`
 }
+
+// FormValidationError is an error in the value submitted for a form field
+type FormValidationError struct {
+	Variable string `json:"variable"`
+	Error    string `json:"error"`
+}
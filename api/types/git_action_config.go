@@ -19,6 +19,9 @@ type GitActionConfig struct {
 
 	// The build context
 	FolderPath string `json:"folder_path"`
+
+	// Whether pull requests are commented on when their commits are deployed
+	PRComments bool `json:"pr_comments"`
 }
 
 type CreateGitActionConfigRequest struct {
@@ -29,6 +32,7 @@ type CreateGitActionConfigRequest struct {
 	FolderPath     string `json:"folder_path"`
 	GitRepoID      uint   `json:"git_repo_id" form:"required"`
 	RegistryID     uint   `json:"registry_id"`
+	PRComments     bool   `json:"pr_comments"`
 
 	ShouldCreateWorkflow bool `json:"should_create_workflow"`
 }
@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+// DefaultGitOpsPath is the directory of the git repository that releases are mirrored
+// to if no path is set
+const DefaultGitOpsPath = "porter"
+
+// GitOpsConfig is the configuration of the git repository that the chart versions and
+// values of the releases of a project are mirrored to. If reconciliation is enabled,
+// changes that are pushed to the repository are applied to the releases.
+type GitOpsConfig struct {
+	ID        uint `json:"id"`
+	ProjectID uint `json:"project_id"`
+
+	GitInstallationID uint   `json:"git_installation_id"`
+	GitRepoOwner      string `json:"git_repo_owner"`
+	GitRepoName       string `json:"git_repo_name"`
+	Branch            string `json:"branch"`
+	Path              string `json:"path"`
+
+	ReconcileEnabled   bool       `json:"reconcile_enabled"`
+	LastReconciledSHA  string     `json:"last_reconciled_sha,omitempty"`
+	LastReconciledAt   *time.Time `json:"last_reconciled_at,omitempty"`
+	LastReconcileError string     `json:"last_reconcile_error,omitempty"`
+}
+
+type UpdateGitOpsConfigRequest struct {
+	Branch           string `json:"branch" form:"required"`
+	Path             string `json:"path"`
+	ReconcileEnabled bool   `json:"reconcile_enabled"`
+}
@@ -10,6 +10,25 @@ type HelmRepo struct {
 	Name string `json:"name"`
 
 	RepoURL string `json:"repo_name"`
+
+	// The cluster that the credentials of the repo are used for, or 0 if the
+	// credentials are used for every cluster of the project
+	ClusterID uint `json:"cluster_id"`
 }
 
 type GetHelmRepoResponse HelmRepo
+
+type CreateHelmRepoRequest struct {
+	Name    string `json:"name" form:"required"`
+	RepoURL string `json:"repo_url" form:"required,url"`
+
+	// The basic auth integration that is used to authenticate with the repo
+	BasicIntegrationID uint `json:"basic_integration_id" form:"required"`
+
+	// If set, the credentials are only used for releases of this cluster
+	ClusterID uint `json:"cluster_id"`
+}
+
+type CreateHelmRepoResponse HelmRepo
+
+type ListHelmReposResponse []*HelmRepo
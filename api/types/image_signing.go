@@ -0,0 +1,42 @@
+package types
+
+const (
+	URLParamImageSigningAuthorityID URLParam = "image_signing_authority_id"
+)
+
+// ImageSigningAuthority is a cosign signer of the images of a project. An authority is
+// either a public key, or a keyless identity with the OIDC issuer and subject of the
+// certificates of its signatures.
+type ImageSigningAuthority struct {
+	ID        uint   `json:"id"`
+	ProjectID uint   `json:"project_id"`
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key,omitempty"`
+	Issuer    string `json:"issuer,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+}
+
+type CreateImageSigningAuthorityRequest struct {
+	Name      string `json:"name" form:"required"`
+	PublicKey string `json:"public_key" form:"required_without=Issuer"`
+	Issuer    string `json:"issuer" form:"required_without=PublicKey"`
+	Subject   string `json:"subject" form:"required_with=Issuer"`
+}
+
+type ListImageSigningAuthoritiesResponse []*ImageSigningAuthority
+
+type UpdateProjectImageSigningRequest struct {
+	// Enforce blocks the deploys of images that are not signed by an authority of the
+	// project
+	Enforce bool `json:"enforce"`
+}
+
+type InstallImageSigningPolicyRequest struct {
+	// Enforce rejects pods with unsigned images, instead of only warning about them
+	Enforce bool `json:"enforce"`
+
+	// Namespaces are the namespaces whose pods are verified. The policy-controller only
+	// verifies the pods of namespaces that are labeled for it, so other namespaces, such as
+	// the namespaces of addons with public images, are not verified.
+	Namespaces []string `json:"namespaces" form:"required,min=1,dive,required"`
+}
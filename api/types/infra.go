@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // InfraStatus is the status that an infrastructure can take
 type InfraStatus string
@@ -12,6 +15,7 @@ const (
 	StatusError      InfraStatus = "error"
 	StatusDestroying InfraStatus = "destroying"
 	StatusDestroyed  InfraStatus = "destroyed"
+	StatusUpdating   InfraStatus = "updating"
 )
 
 // InfraKind is the kind that infra can be
@@ -28,6 +32,17 @@ const (
 	InfraDOKS InfraKind = "doks"
 
 	InfraRDS InfraKind = "rds"
+
+	InfraS3  InfraKind = "s3"
+	InfraGCS InfraKind = "gcs"
+
+	InfraSQS    InfraKind = "sqs"
+	InfraPubSub InfraKind = "pubsub"
+
+	// InfraRDSNetwork and InfraCloudSQLNetwork configure private networking between a
+	// provisioned cluster and a managed database
+	InfraRDSNetwork      InfraKind = "rdsnetwork"
+	InfraCloudSQLNetwork InfraKind = "cloudsqlnetwork"
 )
 
 type Infra struct {
@@ -55,8 +70,119 @@ type Infra struct {
 	// this points to an OAuthIntegrationID
 	DOIntegrationID uint `json:"do_integration_id,omitempty"`
 
+	// The version of the Terraform modules that the infra was last applied with
+	ModuleVersion string `json:"module_version"`
+
+	// The version of the Terraform modules that the infra is being upgraded to, until
+	// the upgrade is applied
+	PendingModuleVersion string `json:"pending_module_version,omitempty"`
+
 	// The last-applied, non-sensitive input variables to the provisioner. For now,
 	// this is a map[string]string since we marshal into env vars anyway, but
 	// eventually this config will be more complex.
 	LastApplied map[string]string `json:"last_applied"`
+
+	// CostEstimate is the estimated monthly cost of the infra, which is only set on
+	// plan previews
+	CostEstimate *CostEstimate `json:"cost_estimate,omitempty"`
+}
+
+// ExportInfraResponse is the Terraform configuration and state snapshot of an
+// infra, with sensitive values redacted. It can be used to move management of the
+// infra to a separate Terraform pipeline.
+type ExportInfraResponse struct {
+	// The kind of infra, which corresponds to the Terraform module that was applied
+	Kind InfraKind `json:"kind"`
+
+	// The name of the Terraform workspace for this infra
+	WorkspaceID string `json:"workspace_id"`
+
+	// The last-applied input variables to the Terraform module
+	Variables map[string]interface{} `json:"variables"`
+
+	// The current Terraform state, or nil if the infra has no state
+	State interface{} `json:"state"`
+}
+
+// UpgradeInfraRequest upgrades the Terraform modules of an infra to a newer version
+type UpgradeInfraRequest struct {
+	// The module version to upgrade to
+	ModuleVersion string `json:"module_version" form:"required"`
+
+	// If PlanOnly is set, a Terraform plan for the upgrade is run and streamed
+	// through the infra logs, but no changes are applied
+	PlanOnly bool `json:"plan_only"`
+}
+
+// InfraResourceStatus is the status of a single resource managed by an infra
+type InfraResourceStatus string
+
+const (
+	InfraResourceCreated InfraResourceStatus = "created"
+	InfraResourceErrored InfraResourceStatus = "errored"
+)
+
+// InfraResource is a resource managed by the Terraform module of an infra, as of the
+// last finished operation on the infra
+type InfraResource struct {
+	Addr         string              `json:"addr"`
+	ResourceType string              `json:"resource_type"`
+	ResourceName string              `json:"resource_name"`
+	Provider     string              `json:"provider"`
+	Status       InfraResourceStatus `json:"status"`
+	Error        string              `json:"error,omitempty"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+type ListInfraResourcesResponse []*InfraResource
+
+// PreflightRequest checks that a cloud integration has the permissions that the
+// Terraform modules of an infra kind require, before the infra is provisioned. The
+// integration of the cloud of the kind must be set.
+type PreflightRequest struct {
+	Kind InfraKind `json:"kind" form:"required"`
+
+	AWSIntegrationID uint `json:"aws_integration_id"`
+	GCPIntegrationID uint `json:"gcp_integration_id"`
+	DOIntegrationID  uint `json:"do_integration_id"`
+}
+
+// PreflightCheck is the result of checking a single permission or property of the
+// credentials of an integration
+type PreflightCheck struct {
+	Permission string `json:"permission"`
+	Passed     bool   `json:"passed"`
+
+	// Message is the reason that a check failed
+	Message string `json:"message,omitempty"`
+}
+
+type PreflightResponse struct {
+	Kind   InfraKind         `json:"kind"`
+	Passed bool              `json:"passed"`
+	Checks []*PreflightCheck `json:"checks"`
+}
+
+// EstimateCostRequest estimates the monthly cost of provisioning an infra kind. The
+// config is the body of the provisioning request of the kind.
+type EstimateCostRequest struct {
+	Kind   InfraKind       `json:"kind" form:"required"`
+	Config json.RawMessage `json:"config" form:"required"`
+}
+
+// CostEstimate is an estimate of the monthly on-demand cost of an infra in USD, based on
+// list prices. Resources that are billed by usage, such as storage and data transfer,
+// are listed in the notes instead of being estimated.
+type CostEstimate struct {
+	Currency     string              `json:"currency"`
+	MonthlyTotal float64             `json:"monthly_total"`
+	Items        []*CostEstimateItem `json:"items"`
+	Notes        []string            `json:"notes,omitempty"`
+}
+
+type CostEstimateItem struct {
+	Name        string  `json:"name"`
+	Quantity    uint    `json:"quantity"`
+	UnitMonthly float64 `json:"unit_monthly"`
+	Monthly     float64 `json:"monthly"`
 }
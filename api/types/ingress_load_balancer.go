@@ -0,0 +1,28 @@
+package types
+
+// IngressLoadBalancer pins the load balancer of the ingress controller of a cluster to a
+// pre-allocated address, so that the address of the ingress is stable across installs of
+// the ingress controller. The settings that are supported depend on the provider of the
+// cluster.
+type IngressLoadBalancer struct {
+	// StaticIP is a reserved IP that is assigned to the load balancer, which is supported
+	// by GKE clusters and clusters that are not managed by a provider
+	StaticIP string `json:"static_ip,omitempty" form:"omitempty,ip"`
+
+	// EIPAllocations are the allocation IDs of the elastic IPs of the network load
+	// balancer of an EKS cluster, one per subnet of the load balancer
+	EIPAllocations []string `json:"eip_allocations,omitempty" form:"omitempty,dive,startswith=eipalloc-"`
+
+	// LoadBalancerID is the ID of an existing load balancer of a DOKS cluster, which is
+	// reused instead of creating a new load balancer
+	LoadBalancerID string `json:"load_balancer_id,omitempty"`
+}
+
+// IsEmpty returns true if the load balancer of the ingress controller is not pinned
+func (l *IngressLoadBalancer) IsEmpty() bool {
+	return l.StaticIP == "" && len(l.EIPAllocations) == 0 && l.LoadBalancerID == ""
+}
+
+type UpdateIngressLoadBalancerRequest struct {
+	IngressLoadBalancer
+}
@@ -0,0 +1,86 @@
+package types
+
+import "time"
+
+const URLParamUserID URLParam = "user_id"
+
+type AdminListRequest struct {
+	Limit int `schema:"limit"`
+	Skip  int `schema:"skip"`
+}
+
+type AdminProject struct {
+	*Project
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AdminListProjectsResponse struct {
+	Projects []*AdminProject `json:"projects"`
+	Count    int64           `json:"count"`
+}
+
+type AdminUser struct {
+	*User
+
+	CreatedAt     time.Time `json:"created_at"`
+	InstanceAdmin bool      `json:"instance_admin"`
+	Whitelisted   bool      `json:"whitelisted"`
+}
+
+type AdminListUsersResponse struct {
+	Users []*AdminUser `json:"users"`
+	Count int64        `json:"count"`
+}
+
+type AdminUpdateUserRequest struct {
+	InstanceAdmin *bool `json:"instance_admin"`
+}
+
+// InstanceQuota is the set of quotas for every project and user of an instance, which
+// apply in addition to the limits of billing plans. Quotas of 0 are unlimited.
+type InstanceQuota struct {
+	MaxProjectsPerUser    uint `json:"max_projects_per_user"`
+	MaxClustersPerProject uint `json:"max_clusters_per_project"`
+	MaxUsersPerProject    uint `json:"max_users_per_project"`
+}
+
+type UpdateInstanceQuotaRequest InstanceQuota
+
+type AdminListWhitelistedUsersResponse []uint
+
+type AdminWhitelistUserRequest struct {
+	UserID uint `json:"user_id" form:"required"`
+}
+
+type AdminAuditAction string
+
+const (
+	AdminAuditActionImpersonate       AdminAuditAction = "impersonate"
+	AdminAuditActionStopImpersonate   AdminAuditAction = "stop_impersonate"
+	AdminAuditActionUpdateUser        AdminAuditAction = "update_user"
+	AdminAuditActionUpdateQuota       AdminAuditAction = "update_quota"
+	AdminAuditActionWhitelistUser     AdminAuditAction = "whitelist_user"
+	AdminAuditActionRemoveWhitelisted AdminAuditAction = "remove_whitelisted_user"
+	AdminAuditActionUpdateSetting     AdminAuditAction = "update_setting"
+	AdminAuditActionResetSetting      AdminAuditAction = "reset_setting"
+
+	// AdminAuditActionImpersonatedRequest records a request that changes data, which an
+	// instance admin made while impersonating a user
+	AdminAuditActionImpersonatedRequest AdminAuditAction = "impersonated_request"
+)
+
+type AdminAuditLog struct {
+	ID              uint             `json:"id"`
+	CreatedAt       time.Time        `json:"created_at"`
+	AdminUserID     uint             `json:"admin_user_id"`
+	Action          AdminAuditAction `json:"action"`
+	TargetUserID    uint             `json:"target_user_id,omitempty"`
+	TargetProjectID uint             `json:"target_project_id,omitempty"`
+	Details         string           `json:"details,omitempty"`
+}
+
+type AdminListAuditLogsResponse struct {
+	AuditLogs []*AdminAuditLog `json:"audit_logs"`
+	Count     int64            `json:"count"`
+}
@@ -0,0 +1,34 @@
+package types
+
+import "time"
+
+// License is an offline license of an enterprise installation, which is signed by Porter
+// so that enterprise features can be used without a billing provider
+type License struct {
+	ID        string    `json:"id"`
+	Licensee  string    `json:"licensee"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	Entitlements LicenseEntitlements `json:"entitlements"`
+}
+
+// LicenseEntitlements are the capabilities that a license includes. The maximum number
+// of clusters is unlimited if 0.
+type LicenseEntitlements struct {
+	PreviewEnvironments bool `json:"preview_environments"`
+	SSO                 bool `json:"sso"`
+	MaxClusters         uint `json:"max_clusters"`
+}
+
+// IsExpired returns true if the license has expired
+func (l *License) IsExpired() bool {
+	return l.ExpiresAt.Before(time.Now())
+}
+
+// LicenseStatus is the license of an installation, as surfaced by the metadata endpoint
+type LicenseStatus struct {
+	*License
+
+	IsExpired bool `json:"is_expired"`
+}
@@ -0,0 +1,34 @@
+package types
+
+import "time"
+
+const (
+	URLParamManifestPolicyID URLParam = "manifest_policy_id"
+)
+
+// ManifestPolicy is a Rego policy of a project, which is evaluated against each object
+// of the rendered manifests of releases. The deny rules of the policy report violations,
+// which fail the install or upgrade of the release.
+type ManifestPolicy struct {
+	ID          uint      `json:"id"`
+	ProjectID   uint      `json:"project_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Rego        string    `json:"rego"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreateManifestPolicyRequest struct {
+	Name        string `json:"name" form:"required"`
+	Description string `json:"description"`
+	Rego        string `json:"rego" form:"required"`
+}
+
+type UpdateManifestPolicyRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Rego        string `json:"rego"`
+}
+
+type ListManifestPoliciesResponse []*ManifestPolicy
@@ -21,8 +21,24 @@ type ReleaseListFilter struct {
 	Skip         int      `json:"skip"`
 	ByDate       bool     `json:"byDate"`
 	StatusFilter []string `json:"statusFilter"`
+
+	// Ownership filters releases by whether their resources carry the Porter ownership
+	// labels of the cluster, and is either "porter" or "external". All releases are
+	// listed if it is empty. Filtered releases are paginated by Skip and Limit.
+	Ownership ReleaseOwnership `json:"ownership"`
+
+	// SkipManifest lists releases without their manifests, which are the largest part of
+	// a release and are not needed by most release lists
+	SkipManifest bool `json:"skip_manifest" schema:"skip_manifest"`
 }
 
+type ReleaseOwnership string
+
+const (
+	ReleaseOwnershipPorter   ReleaseOwnership = "porter"
+	ReleaseOwnershipExternal ReleaseOwnership = "external"
+)
+
 // listStatesFromNames accepts the following list of names:
 //
 // "deployed", "uninstalled", "uninstalling", "pending-install", "pending-upgrade",
@@ -110,6 +126,20 @@ type GetEnvGroupRequest struct {
 	Version uint   `schema:"version"`
 }
 
+// GetEnvGroupsBatchRequest gets the latest versions of several env groups at once
+type GetEnvGroupsBatchRequest struct {
+	Names []string `schema:"names,required"`
+}
+
+// GetEnvGroupsBatchResponse contains the env groups that were found, the names of the
+// env groups that do not exist, and the error for each env group that could not be read,
+// keyed by name
+type GetEnvGroupsBatchResponse struct {
+	EnvGroups []*EnvGroup       `json:"env_groups"`
+	NotFound  []string          `json:"not_found,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
 type CloneEnvGroupRequest struct {
 	Namespace string `json:"namespace" form:"required"`
 	Name      string `json:"name" form:"required"`
@@ -0,0 +1,36 @@
+package types
+
+// NetworkPolicyPreset is a network isolation preset that can be applied to a release or
+// a namespace
+type NetworkPolicyPreset string
+
+const (
+	// NetworkPolicyPresetIngressOnly denies all ingress traffic except traffic from the
+	// NGINX ingress controller
+	NetworkPolicyPresetIngressOnly NetworkPolicyPreset = "deny-all-ingress-except-ingress-controller"
+
+	// NetworkPolicyPresetNamespaceIsolated only allows ingress traffic from pods in the
+	// same namespace and from the NGINX ingress controller
+	NetworkPolicyPresetNamespaceIsolated NetworkPolicyPreset = "namespace-isolated"
+
+	// NetworkPolicyPresetOpen does not restrict traffic beyond the other network policies
+	// in the namespace
+	NetworkPolicyPresetOpen NetworkPolicyPreset = "open"
+)
+
+type UpdateNetworkPolicyPresetRequest struct {
+	Preset NetworkPolicyPreset `json:"preset" form:"required,oneof=deny-all-ingress-except-ingress-controller namespace-isolated open"`
+}
+
+type NetworkPolicyPresetResponse struct {
+	Preset NetworkPolicyPreset `json:"preset"`
+
+	// NamespacePreset is the preset of the namespace of a release, which also applies to
+	// the pods of the release since network policies are additive. It is only set for
+	// releases.
+	NamespacePreset NetworkPolicyPreset `json:"namespace_preset,omitempty"`
+
+	// PolicyName is the name of the generated network policy, which is empty for the open
+	// preset
+	PolicyName string `json:"policy_name,omitempty"`
+}
@@ -0,0 +1,34 @@
+package types
+
+// OrphanedResource is a resource labeled as managed by Porter whose release no longer
+// exists in the cluster
+type OrphanedResource struct {
+	Kind      string `json:"kind" form:"required"`
+	Namespace string `json:"namespace" form:"required"`
+	Name      string `json:"name" form:"required"`
+	Release   string `json:"release"`
+}
+
+// OrphanedResourcesReport lists the orphaned resources of a cluster, and the preview
+// environment namespaces that no longer have a deployment
+type OrphanedResourcesReport struct {
+	Resources  []*OrphanedResource `json:"resources"`
+	Namespaces []string            `json:"namespaces"`
+}
+
+// CleanupOrphanedResourcesRequest deletes orphaned resources and namespaces. Only
+// resources and namespaces that are in the current report of the cluster are deleted,
+// and the request fails if any of them is not.
+type CleanupOrphanedResourcesRequest struct {
+	Resources  []*OrphanedResource `json:"resources" form:"omitempty,dive"`
+	Namespaces []string            `json:"namespaces"`
+}
+
+// CleanupOrphanedResourcesResponse lists the resources and namespaces that were deleted
+type CleanupOrphanedResourcesResponse OrphanedResourcesReport
+
+// BackfillOwnershipLabelsResponse lists the releases, as <namespace>/<name>, whose
+// resources were labeled by a backfill of the ownership labels
+type BackfillOwnershipLabelsResponse struct {
+	Releases []string `json:"releases"`
+}
@@ -0,0 +1,86 @@
+package types
+
+import "time"
+
+// PipelineStage is an environment of a pipeline, which is a release in a namespace of a
+// cluster. Stages are ordered, and a release is promoted from a stage to the next one.
+type PipelineStage struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	ClusterID   uint   `json:"cluster_id"`
+	Namespace   string `json:"namespace"`
+	ReleaseName string `json:"release_name"`
+}
+
+type Pipeline struct {
+	ID        uint   `json:"id"`
+	ProjectID uint   `json:"project_id"`
+	Name      string `json:"name"`
+
+	// RequiredApprovals is the number of users, other than the user that requested the
+	// promotion, that must approve a promotion before it is run
+	RequiredApprovals uint `json:"required_approvals"`
+
+	Stages []*PipelineStage `json:"stages"`
+}
+
+type CreatePipelineStageRequest struct {
+	Name        string `json:"name" form:"required"`
+	ClusterID   uint   `json:"cluster_id" form:"required"`
+	Namespace   string `json:"namespace" form:"required"`
+	ReleaseName string `json:"release_name" form:"required"`
+}
+
+type CreatePipelineRequest struct {
+	Name              string                        `json:"name" form:"required"`
+	RequiredApprovals uint                          `json:"required_approvals"`
+	Stages            []*CreatePipelineStageRequest `json:"stages" form:"required,min=2,dive"`
+}
+
+type ListPipelinesResponse []*Pipeline
+
+type PromotionStatus string
+
+const (
+	PromotionStatusPending   PromotionStatus = "pending"
+	PromotionStatusRunning   PromotionStatus = "running"
+	PromotionStatusSucceeded PromotionStatus = "succeeded"
+	PromotionStatusFailed    PromotionStatus = "failed"
+
+	// PromotionStatusChangeRequested is the status of promotions to protected releases,
+	// which are stored as change requests of the release in the next stage
+	PromotionStatusChangeRequested PromotionStatus = "change_requested"
+)
+
+type CreatePromotionRequest struct {
+	// FromStage is the name of the stage to promote from
+	FromStage string `json:"from_stage" form:"required"`
+}
+
+type PipelinePromotionApproval struct {
+	UserID    uint      `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PipelinePromotion copies the chart version and values, including the image, of a
+// revision of a release in one stage to the release in the next stage
+type PipelinePromotion struct {
+	ID          uint            `json:"id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	PipelineID  uint            `json:"pipeline_id"`
+	FromStageID uint            `json:"from_stage_id"`
+	ToStageID   uint            `json:"to_stage_id"`
+	Status      PromotionStatus `json:"status"`
+	RequestedBy uint            `json:"requested_by"`
+
+	// Revision is the revision of the release in the source stage that is promoted
+	Revision     int    `json:"revision"`
+	ChartVersion string `json:"chart_version"`
+	ImageTag     string `json:"image_tag"`
+	Error        string `json:"error,omitempty"`
+
+	Approvals []*PipelinePromotionApproval `json:"approvals"`
+}
+
+type ListPipelinePromotionsResponse []*PipelinePromotion
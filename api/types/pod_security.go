@@ -0,0 +1,58 @@
+package types
+
+// PodSecurityLevel is a level of the Kubernetes Pod Security Standards, which is
+// enforced by the Pod Security admission controller
+type PodSecurityLevel string
+
+const (
+	// PodSecurityLevelPrivileged does not restrict pods
+	PodSecurityLevelPrivileged PodSecurityLevel = "privileged"
+
+	// PodSecurityLevelBaseline prevents known privilege escalations, such as privileged
+	// containers and host namespaces
+	PodSecurityLevelBaseline PodSecurityLevel = "baseline"
+
+	// PodSecurityLevelRestricted additionally requires pods to follow hardening best
+	// practices, such as running as a non-root user
+	PodSecurityLevelRestricted PodSecurityLevel = "restricted"
+)
+
+type PodSecurityResponse struct {
+	Level PodSecurityLevel `json:"level"`
+
+	// Namespaces are the namespaces managed by Porter that the level is enforced on
+	Namespaces []string `json:"namespaces"`
+}
+
+type UpdatePodSecurityRequest struct {
+	Level PodSecurityLevel `json:"level" form:"required,oneof=privileged baseline restricted"`
+
+	// Force enforces the level even if existing releases violate it. Pods of those
+	// releases will fail to be created until the releases are fixed.
+	Force bool `json:"force"`
+}
+
+type PodSecurityPreflightRequest struct {
+	Level PodSecurityLevel `schema:"level" form:"required,oneof=privileged baseline restricted"`
+}
+
+type PodSecurityPreflightResponse struct {
+	Level PodSecurityLevel `json:"level"`
+
+	// Namespaces are the namespaces managed by Porter that the level would be enforced on
+	Namespaces []string `json:"namespaces"`
+
+	Violations []*PodSecurityViolation `json:"violations"`
+}
+
+// PodSecurityViolation is a controller of a release whose pods would be rejected by
+// the Pod Security admission controller
+type PodSecurityViolation struct {
+	Namespace   string `json:"namespace"`
+	ReleaseName string `json:"release_name"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+
+	// Reasons are the checks of the level that the pod template fails
+	Reasons []string `json:"reasons"`
+}
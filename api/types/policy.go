@@ -14,6 +14,10 @@ const (
 	NamespaceScope       PermissionScope = "namespace"
 	SettingsScope        PermissionScope = "settings"
 	ReleaseScope         PermissionScope = "release"
+
+	// InstanceAdminScope restricts an endpoint to the admins of the instance, and is not
+	// part of project policies
+	InstanceAdminScope PermissionScope = "instance_admin"
 )
 
 type NameOrUInt struct {
@@ -6,6 +6,25 @@ type Project struct {
 	Roles               []*Role `json:"roles"`
 	PreviewEnvsEnabled  bool    `json:"preview_envs_enabled"`
 	RDSDatabasesEnabled bool    `json:"enable_rds_databases"`
+	CLIVersion          string  `json:"cli_version,omitempty"`
+
+	// AllowedRegistries are the registries that the images of releases may be pulled
+	// from. If empty, images may be pulled from any registry.
+	AllowedRegistries []string `json:"allowed_registries,omitempty"`
+
+	// ImageSigningEnforced is true if images must be signed by an image signing authority
+	// of the project to be deployed
+	ImageSigningEnforced bool `json:"image_signing_enforced"`
+
+	// SSOEnforced is true if the members of the project must sign in through the SSO
+	// provider of the instance
+	SSOEnforced bool `json:"sso_enforced"`
+}
+
+type UpdateProjectSSORequest struct {
+	// Enforce requires the members of the project to sign in through the SSO provider of
+	// the instance
+	Enforce bool `json:"enforce"`
 }
 
 type CreateProjectRequest struct {
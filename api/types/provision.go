@@ -1,6 +1,9 @@
 package types
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 type CreateECRInfraRequest struct {
 	ECRName          string `json:"ecr_name" form:"required"`
@@ -14,6 +17,64 @@ type CreateEKSInfraRequest struct {
 	IssuerEmail      string `json:"issuer_email" form:"required"`
 	ProjectID        uint   `json:"-" form:"required"`
 	AWSIntegrationID uint   `json:"aws_integration_id" form:"required"`
+
+	// NodeGroups are the managed node groups to create for the cluster. If empty,
+	// a single on-demand node group using MachineType is created.
+	NodeGroups []*EKSNodeGroup `json:"node_groups,omitempty" form:"omitempty,dive"`
+
+	// VPCID and SubnetIDs can be set to provision the cluster into an existing VPC
+	// instead of creating a new one
+	VPCID     string   `json:"vpc_id,omitempty"`
+	SubnetIDs []string `json:"subnet_ids,omitempty"`
+
+	// EnableClusterAutoscaler installs the cluster autoscaler, which scales each node
+	// group between its min and max size
+	EnableClusterAutoscaler bool `json:"enable_cluster_autoscaler"`
+}
+
+// EKSNodeGroup is the configuration for a single EKS managed node group
+type EKSNodeGroup struct {
+	Name          string   `json:"name" form:"required,max=63"`
+	InstanceTypes []string `json:"instance_types" form:"required,min=1"`
+	MinSize       uint     `json:"min_size"`
+	MaxSize       uint     `json:"max_size" form:"required,min=1"`
+	DesiredSize   uint     `json:"desired_size"`
+
+	// Spot uses spot capacity for the node group instead of on-demand capacity
+	Spot bool `json:"spot"`
+}
+
+// Validate checks the EKS request for settings that are incompatible with
+// each other
+func (req *CreateEKSInfraRequest) Validate() error {
+	names := make(map[string]bool)
+
+	for _, ng := range req.NodeGroups {
+		if names[ng.Name] {
+			return fmt.Errorf("node group name %s is used more than once", ng.Name)
+		}
+
+		names[ng.Name] = true
+
+		if ng.MinSize > ng.MaxSize {
+			return fmt.Errorf("node group %s: min_size cannot be greater than max_size", ng.Name)
+		}
+
+		if ng.DesiredSize != 0 && (ng.DesiredSize < ng.MinSize || ng.DesiredSize > ng.MaxSize) {
+			return fmt.Errorf("node group %s: desired_size must be between min_size and max_size", ng.Name)
+		}
+	}
+
+	if req.VPCID == "" && len(req.SubnetIDs) > 0 {
+		return fmt.Errorf("subnet_ids can only be set along with vpc_id")
+	}
+
+	// EKS requires subnets in at least two availability zones
+	if req.VPCID != "" && len(req.SubnetIDs) < 2 {
+		return fmt.Errorf("at least two subnet_ids are required when using an existing VPC")
+	}
+
+	return nil
 }
 
 type CreateGCRInfraRequest struct {
@@ -27,6 +88,59 @@ type CreateGKEInfraRequest struct {
 	IssuerEmail      string `json:"issuer_email" form:"required"`
 	ProjectID        uint   `json:"-" form:"required"`
 	GCPIntegrationID uint   `json:"gcp_integration_id" form:"required"`
+
+	// Autopilot creates a GKE Autopilot cluster, where nodes are managed by GKE.
+	// Autopilot clusters are always regional.
+	Autopilot bool `json:"autopilot"`
+
+	// Regional creates a cluster with a control plane and nodes replicated across
+	// multiple zones in the region, instead of a single-zone cluster
+	Regional bool `json:"regional"`
+
+	// NodeZones are the zones in GCPRegion that nodes run in for regional clusters.
+	// If empty, GKE picks the zones.
+	NodeZones []string `json:"node_zones,omitempty"`
+
+	// MachineType is the machine type of the default node pool. This is not used
+	// by Autopilot clusters.
+	MachineType string `json:"machine_type,omitempty"`
+}
+
+// DefaultGKEMachineType is the machine type used for the default node pool of
+// standard GKE clusters when none is set
+const DefaultGKEMachineType = "e2-standard-2"
+
+// SetDefaults fills in defaults for unset fields of the GKE request
+func (req *CreateGKEInfraRequest) SetDefaults() {
+	if req.Autopilot {
+		req.Regional = true
+		return
+	}
+
+	if req.MachineType == "" {
+		req.MachineType = DefaultGKEMachineType
+	}
+}
+
+// Validate checks the GKE request for settings that are incompatible with
+// each other. It should be called after SetDefaults.
+func (req *CreateGKEInfraRequest) Validate() error {
+	if req.Autopilot && req.MachineType != "" {
+		return fmt.Errorf("machine_type cannot be set for autopilot clusters")
+	}
+
+	if !req.Regional && len(req.NodeZones) > 0 {
+		return fmt.Errorf("node_zones can only be set for regional clusters")
+	}
+
+	for _, zone := range req.NodeZones {
+		// GCP zones are of the form <region>-<zone letter>, for example us-east1-b
+		if !strings.HasPrefix(zone, req.GCPRegion+"-") {
+			return fmt.Errorf("zone %s is not in region %s", zone, req.GCPRegion)
+		}
+	}
+
+	return nil
 }
 
 type CreateDOCRInfraRequest struct {
@@ -80,6 +194,83 @@ type RDSInfraLastApplied struct {
 	Subnet3              string
 }
 
+// CreateBucketInfraRequest provisions an object storage bucket in the cloud of the
+// cluster, along with credentials that can only access the bucket: an IAM user on AWS,
+// or a service account on GCP
+type CreateBucketInfraRequest struct {
+	BucketName string `json:"bucket_name" form:"required,min=3,max=63"`
+	Versioning bool   `json:"versioning"`
+}
+
+type BucketInfraLastApplied struct {
+	*CreateBucketInfraRequest
+
+	ClusterID uint   `json:"cluster_id"`
+	Namespace string `json:"namespace"`
+
+	AWSRegion    string `json:"aws_region,omitempty"`
+	GCPProjectID string `json:"gcp_project_id,omitempty"`
+	GCPRegion    string `json:"gcp_region,omitempty"`
+}
+
+// CreateQueueInfraRequest provisions a message queue in the cloud of the cluster, along
+// with credentials that can only consume from and publish to the queue. On AWS, an SQS
+// queue is created. On GCP, a Pub/Sub topic is created with a subscription that the
+// workers of the queue pull from.
+type CreateQueueInfraRequest struct {
+	QueueName string `json:"queue_name" form:"required,min=1,max=80"`
+
+	// FIFO creates an SQS FIFO queue, and is ignored for Pub/Sub
+	FIFO bool `json:"fifo"`
+
+	// VisibilityTimeout is the time in seconds that a received message is hidden from
+	// other consumers, which is the ack deadline of the subscription on Pub/Sub
+	VisibilityTimeout uint `json:"visibility_timeout" form:"omitempty,min=10,max=600"`
+
+	// MessageRetention is the time in seconds that unacknowledged messages are kept
+	MessageRetention uint `json:"message_retention" form:"omitempty,min=600,max=604800"`
+}
+
+type QueueInfraLastApplied struct {
+	*CreateQueueInfraRequest
+
+	ClusterID uint   `json:"cluster_id"`
+	Namespace string `json:"namespace"`
+
+	AWSRegion    string `json:"aws_region,omitempty"`
+	GCPProjectID string `json:"gcp_project_id,omitempty"`
+}
+
+// CreatePrivateNetworkInfraRequest configures private networking between a cluster
+// provisioned by Porter and a managed database. On EKS clusters, the database is an RDS
+// infra, and the security group of the database is opened to the nodes of the cluster.
+// On GKE clusters, the database is an existing Cloud SQL instance, which is connected to
+// the network of the cluster through private services access, with the pod and node
+// ranges of the cluster added as authorized networks.
+type CreatePrivateNetworkInfraRequest struct {
+	DatabaseInfraID      uint   `json:"database_infra_id"`
+	CloudSQLInstanceName string `json:"cloudsql_instance_name"`
+}
+
+type PrivateNetworkInfraLastApplied struct {
+	*CreatePrivateNetworkInfraRequest
+
+	ClusterID      uint `json:"cluster_id"`
+	ClusterInfraID uint `json:"cluster_infra_id"`
+
+	// set for RDS networks
+	AWSRegion      string `json:"aws_region,omitempty"`
+	EKSName        string `json:"eks_name,omitempty"`
+	VPCID          string `json:"vpc_id,omitempty"`
+	DBInstanceID   string `json:"db_instance_id,omitempty"`
+	DBInstancePort string `json:"db_instance_port,omitempty"`
+
+	// set for Cloud SQL networks
+	GCPProjectID string `json:"gcp_project_id,omitempty"`
+	GCPRegion    string `json:"gcp_region,omitempty"`
+	GKEName      string `json:"gke_name,omitempty"`
+}
+
 type Family string
 
 type EngineVersion string
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	"helm.sh/helm/v3/pkg/release"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,15 +14,27 @@ type Release struct {
 	*PorterRelease
 
 	Form *FormYAML `json:"form,omitempty"`
+
+	// InternalEndpoints are the cluster DNS names and ports of the services of the release,
+	// which other releases in the cluster can reach the release at
+	InternalEndpoints []string `json:"internal_endpoints,omitempty"`
 }
 
 type PorterRelease struct {
-	ID              uint             `json:"id"`
-	WebhookToken    string           `json:"webhook_token"`
-	LatestVersion   string           `json:"latest_version"`
-	GitActionConfig *GitActionConfig `json:"git_action_config,omitempty"`
-	ImageRepoURI    string           `json:"image_repo_uri"`
-	BuildConfig     *BuildConfig     `json:"build_config,omitempty"`
+	ID                   uint             `json:"id"`
+	WebhookToken         string           `json:"webhook_token"`
+	LatestVersion        string           `json:"latest_version"`
+	GitActionConfig      *GitActionConfig `json:"git_action_config,omitempty"`
+	ImageRepoURI         string           `json:"image_repo_uri"`
+	BuildConfig          *BuildConfig     `json:"build_config,omitempty"`
+	Paused               bool             `json:"paused"`
+	Protected            bool             `json:"protected"`
+	DeletionProtection   bool             `json:"deletion_protection"`
+	RestartOnEnvChange   bool             `json:"restart_on_env_change"`
+	Dependencies         []string         `json:"dependencies"`
+	PreDeployCommand     string           `json:"pre_deploy_command,omitempty"`
+	LongLivedConnections bool             `json:"long_lived_connections"`
+	KustomizePatches     string           `json:"kustomize_patches,omitempty"`
 }
 
 type GetReleaseResponse Release
@@ -47,19 +61,126 @@ type CreateReleaseRequest struct {
 	ImageURL           string                        `json:"image_url" form:"required"`
 	GithubActionConfig *CreateGitActionConfigRequest `json:"github_action_config,omitempty"`
 	BuildConfig        *CreateBuildConfigRequest     `json:"build_config,omitempty"`
+
+	// Exposure sets how the service of the release is exposed, which defaults to http
+	Exposure *ServiceExposure `json:"exposure,omitempty"`
 }
 
 type CreateAddonRequest struct {
 	*CreateReleaseBaseRequest
 }
 
+// CloneNamespaceRequest clones the Porter-managed releases and env groups of a namespace to
+// a target namespace, which may be in a different cluster of the same project
+type CloneNamespaceRequest struct {
+	TargetNamespace string `json:"target_namespace" form:"required"`
+
+	// TargetClusterID defaults to the cluster of the source namespace
+	TargetClusterID uint `json:"target_cluster_id"`
+
+	// ImageTags maps release names to the image tag to deploy in the target namespace.
+	// Releases that are not in the map keep their current image tag.
+	ImageTags map[string]string `json:"image_tags"`
+}
+
+type CloneNamespaceResponse struct {
+	Releases  []string `json:"releases"`
+	EnvGroups []string `json:"env_groups"`
+}
+
+// AdoptReleaseRequest adopts a Helm release that was not deployed by Porter into Porter's
+// management
+type AdoptReleaseRequest struct {
+	// UseTemplate upgrades the release to the chart of the matching Porter template, at the
+	// version of the chart of the release, so that later upgrades use the template repo. A
+	// template only matches if its chart is identical to the chart of the release.
+	UseTemplate bool `json:"use_template"`
+}
+
+// AdoptManifestsRequest adopts existing objects, which were applied from raw manifests,
+// into a new Helm release that is managed by Porter
+type AdoptManifestsRequest struct {
+	Name string `json:"name" form:"required,max=53"`
+
+	// Manifests are the YAML or JSON manifests of the objects, which must exist in the
+	// namespace of the release. They only identify the objects: the release is created
+	// from the live state of the objects, so adopting them does not change them.
+	Manifests string `json:"manifests" form:"required"`
+}
+
+// AdoptedTemplate is the Porter template that matches the chart of an adopted release
+type AdoptedTemplate struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	RepoURL string `json:"repo_url"`
+
+	// Applied is true if the release was upgraded to the chart of the template
+	Applied bool `json:"applied"`
+}
+
+type AdoptReleaseResponse struct {
+	*PorterRelease
+
+	// Template is only set if the chart of the release matches a Porter template
+	Template *AdoptedTemplate `json:"template,omitempty"`
+}
+
 type RollbackReleaseRequest struct {
 	Revision int `json:"revision" form:"required"`
 }
 
+// ValuesPatchType is the type of a patch of the values of a release
+type ValuesPatchType string
+
+const (
+	// ValuesPatchTypeMerge patches values like a JSON merge patch (RFC 7386): maps are merged,
+	// null values remove keys, and all other values, including lists, are replaced
+	ValuesPatchTypeMerge ValuesPatchType = "merge"
+
+	// ValuesPatchTypeStrategic patches values like a merge patch, but merges lists of maps
+	// whose items all have a name, such as env variables, by the name of the items. Items
+	// are removed from such lists by setting "$patch: delete" on the item.
+	ValuesPatchTypeStrategic ValuesPatchType = "strategic"
+)
+
 type UpgradeReleaseRequest struct {
-	Values       string `json:"values" form:"required"`
+	// Values are the full values of the release. Either the values or a patch of the
+	// current values of the release must be set.
+	Values       string `json:"values" form:"required_without=Patch"`
 	ChartVersion string `json:"version"`
+
+	// Patch is a YAML or JSON patch that is applied to the current values of the release,
+	// so that single values can be updated without sending the full values
+	Patch     string          `json:"patch,omitempty" form:"required_without=Values"`
+	PatchType ValuesPatchType `json:"patch_type,omitempty" form:"omitempty,oneof=merge strategic"`
+
+	// Revision is optional, and if set, the upgrade fails with a conflict if the release
+	// was upgraded since this revision. The conflict lists the values that changed since
+	// the revision.
+	Revision int `json:"revision,omitempty"`
+
+	// CommitSHA is recorded in the deploy event of the upgrade
+	CommitSHA string `json:"commit_sha,omitempty"`
+
+	// Message describes the changes of the upgrade. It is recorded in the deploy event
+	// of the upgrade and included in its notifications.
+	Message string `json:"message,omitempty" form:"omitempty,max=1000"`
+}
+
+// UpgradeReleaseConflict is returned with a conflict status when an upgrade is based on a
+// revision of a release that is no longer the latest revision, so that clients can show
+// the changes that they would otherwise overwrite
+type UpgradeReleaseConflict struct {
+	ExternalError
+
+	// Revision is the revision that the upgrade was based on
+	Revision        int `json:"revision"`
+	CurrentRevision int `json:"current_revision"`
+
+	// ValuesDiff lists the values that changed between the revision of the upgrade and the
+	// current revision, in the format of change request diffs. Sensitive values are
+	// redacted.
+	ValuesDiff []string `json:"values_diff"`
 }
 
 type UpdateImageBatchRequest struct {
@@ -77,6 +198,10 @@ const URLParamToken URLParam = "token"
 type WebhookRequest struct {
 	Commit string `schema:"commit"`
 
+	// Message describes the changes of the deploy. If it is not set, the message of the
+	// commit is used for releases that are built from a Github repository.
+	Message string `schema:"message"`
+
 	// NOTICE: deprecated. This field should no longer be used; it is not supported
 	// internally.
 	Repository string `schema:"repository"`
@@ -98,6 +223,16 @@ type SubEvent struct {
 	Status  EventStatus `json:"status"`
 	Info    string      `json:"info"`
 	Time    int64       `json:"time"`
+
+	// The following fields are only set for deploy events. The initiator is the email of
+	// the user who started the deploy, or of the user who issued the token.
+	Initiator     string              `json:"initiator,omitempty"`
+	InitiatorKind DeployInitiatorKind `json:"initiator_kind,omitempty"`
+	Source        DeploySource        `json:"source,omitempty"`
+	CommitSHA     string              `json:"commit_sha,omitempty"`
+	ImageTag      string              `json:"image_tag,omitempty"`
+	Revision      int                 `json:"revision,omitempty"`
+	Message       string              `json:"message,omitempty"`
 }
 
 type EventStatus int64
@@ -124,6 +259,33 @@ type GetNotificationConfigResponse struct {
 	*NotificationConfig
 }
 
+// DefaultHealthGateVerificationMinutes is the default duration that a new version of a
+// release is verified for, if the health gate is enabled
+const DefaultHealthGateVerificationMinutes = 5
+
+type HealthGateConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// VerificationMinutes is the duration that a new version is watched for after an upgrade
+	VerificationMinutes uint `json:"verification_minutes" form:"omitempty,min=1,max=60"`
+
+	// MaxErrorRate is the maximum percentage of 5xx responses through the ingress of the
+	// release. If 0, the error rate is not checked.
+	MaxErrorRate float64 `json:"max_error_rate" form:"omitempty,min=0,max=100"`
+
+	// MaxLatency is the maximum average response latency in seconds through the ingress of
+	// the release. If 0, the latency is not checked.
+	MaxLatency float64 `json:"max_latency" form:"omitempty,min=0"`
+}
+
+type UpdateHealthGateConfigRequest struct {
+	HealthGateConfig
+}
+
+type GetHealthGateConfigResponse struct {
+	*HealthGateConfig
+}
+
 type DNSRecord struct {
 	ExternalURL string `json:"external_url"`
 
@@ -131,6 +293,194 @@ type DNSRecord struct {
 	Hostname string `json:"hostname"`
 
 	ClusterID uint `json:"cluster_id"`
+
+	// PathPrefix is set for releases of preview environments with path-based routing,
+	// which are exposed under the prefix on a shared host instead of on a subdomain. No
+	// DNS record is created for them, and the ingress of their chart should be disabled.
+	PathPrefix string `json:"path_prefix,omitempty"`
 }
 
 type GetReleaseAllPodsResponse []v1.Pod
+
+type SearchReleaseLogsRequest struct {
+	// Query is a free-text string that every returned log line contains
+	Query string `schema:"query"`
+
+	// Labels are additional Loki stream label filters of the form key=value
+	Labels []string `schema:"labels"`
+
+	// StartRange and EndRange are unix timestamps in seconds
+	StartRange uint `schema:"startrange"`
+	EndRange   uint `schema:"endrange"`
+
+	Limit     uint   `schema:"limit" form:"omitempty,max=5000"`
+	Direction string `schema:"direction" form:"omitempty,oneof=forward backward"`
+}
+
+type ReleaseLogLine struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Line      string            `json:"line"`
+	Labels    map[string]string `json:"labels"`
+}
+
+type SearchReleaseLogsResponse struct {
+	// Start is the unix timestamp that was actually searched from, which may be later
+	// than the requested start if it is past the log retention window
+	Start uint `json:"start"`
+
+	Lines []*ReleaseLogLine `json:"lines"`
+}
+
+type RolloutPhase string
+
+const (
+	RolloutProgressing RolloutPhase = "progressing"
+	RolloutSucceeded   RolloutPhase = "succeeded"
+	RolloutFailed      RolloutPhase = "failed"
+)
+
+// DefaultRolloutTimeout is the time after which a rollout that has not completed is
+// considered failed, in seconds
+const DefaultRolloutTimeout = 300
+
+type StreamRolloutStatusRequest struct {
+	// Timeout is the number of seconds to wait for the rollout to complete
+	Timeout uint `schema:"timeout" form:"omitempty,max=3600"`
+}
+
+type ControllerRolloutStatus struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	DesiredReplicas int32 `json:"desired_replicas"`
+	UpdatedReplicas int32 `json:"updated_replicas"`
+	ReadyReplicas   int32 `json:"ready_replicas"`
+
+	Complete bool `json:"complete"`
+
+	// FailureReason is set if the rollout of the controller cannot complete without
+	// intervention, such as when the image cannot be pulled
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+type RolloutEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Object    string    `json:"object"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+}
+
+// RolloutStatus is a single message sent over the rollout status stream. The stream is
+// closed after a message with a succeeded or failed phase is sent.
+type RolloutStatus struct {
+	Phase       RolloutPhase               `json:"phase"`
+	Controllers []*ControllerRolloutStatus `json:"controllers"`
+
+	// Events are the warning events for the pods of the release since the previous message
+	Events []*RolloutEvent `json:"events"`
+
+	Error string `json:"error,omitempty"`
+}
+
+type UpdateMaintenanceModeRequest struct {
+	Enabled bool `json:"enabled"`
+
+	// Message is shown on the default maintenance page
+	Message string `json:"message"`
+
+	// Page is a custom HTML page to serve instead of the default maintenance page
+	Page string `json:"page"`
+}
+
+type MaintenanceModeResponse struct {
+	Enabled bool `json:"enabled"`
+
+	// Ingresses are the names of the ingresses of the release that serve the maintenance page
+	Ingresses []string `json:"ingresses"`
+}
+
+type UpdateRestartOnEnvChangeRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type UpdateDeletionProtectionRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type DeleteReleaseRequest struct {
+	// Cascade also removes the DNS records, env group links and deploy webhook of the
+	// release
+	Cascade bool `schema:"cascade"`
+
+	// DeleteVolumes also removes the persistent volume claims of the release. It is only
+	// used if Cascade is set.
+	DeleteVolumes bool `schema:"delete_volumes"`
+}
+
+// DeleteReleaseResponse reports the resources that were removed along with a release.
+// Resources that could not be removed are listed in Errors.
+type DeleteReleaseResponse struct {
+	DNSRecords             []string `json:"dns_records"`
+	PersistentVolumeClaims []string `json:"persistent_volume_claims"`
+	EnvGroups              []string `json:"env_groups"`
+	DeployWebhook          bool     `json:"deploy_webhook"`
+	GithubWorkflow         string   `json:"github_workflow,omitempty"`
+	Errors                 []string `json:"errors,omitempty"`
+}
+
+type UpdatePreDeployCommandRequest struct {
+	// Command is run with the new image of the release before each upgrade that changes
+	// the image, and the upgrade is aborted if the command fails. An empty command disables
+	// the pre-deploy command.
+	Command string `json:"command"`
+}
+
+type PreDeployRunStatus string
+
+const (
+	PreDeployRunRunning   PreDeployRunStatus = "running"
+	PreDeployRunSucceeded PreDeployRunStatus = "succeeded"
+	PreDeployRunFailed    PreDeployRunStatus = "failed"
+)
+
+// PreDeployRun is a run of the pre-deploy command of a release. The upgrade or rollback
+// that started the run is applied in the background once the command succeeds, and the
+// run succeeds once the release is deployed.
+type PreDeployRun struct {
+	ID         uint               `json:"id"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Namespace  string             `json:"namespace"`
+	Name       string             `json:"name"`
+	Image      string             `json:"image"`
+	Command    string             `json:"command"`
+	Status     PreDeployRunStatus `json:"status"`
+	JobName    string             `json:"job_name,omitempty"`
+	Logs       string             `json:"logs,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+}
+
+// UpgradeReleaseResponse is returned with a 202 status for upgrades and rollbacks that
+// are deployed once the pre-deploy command of the release succeeds
+type UpgradeReleaseResponse struct {
+	PreDeployRun *PreDeployRun `json:"pre_deploy_run,omitempty"`
+}
+
+type UpdateLongLivedConnectionsRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// UpdateLongLivedConnectionsResponse is the release with the ingress controller of the
+// cluster, whose settings are applied to the ingress of the release on the next deploy
+type UpdateLongLivedConnectionsResponse struct {
+	*PorterRelease
+
+	IngressController string `json:"ingress_controller"`
+}
+
+type UpdateKustomizePatchesRequest struct {
+	// Patches are multi-document YAML strategic merge patches, which are applied to the
+	// rendered manifests of the release on each deploy. Each patch must set the apiVersion,
+	// kind and name of the object that it patches. Empty patches remove the overlay.
+	Patches string `json:"patches"`
+}
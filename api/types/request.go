@@ -40,9 +40,18 @@ const (
 	URLParamGitInstallationID URLParam = "git_installation_id"
 	URLParamInfraID           URLParam = "infra_id"
 	URLParamInviteID          URLParam = "invite_id"
+	URLParamPipelineID        URLParam = "pipeline_id"
+	URLParamPromotionID       URLParam = "promotion_id"
+	URLParamChangeRequestID   URLParam = "change_request_id"
+	URLParamAllowedChartID    URLParam = "allowed_chart_id"
+	URLParamDeployFreezeID    URLParam = "deploy_freeze_id"
+	URLParamScheduledDeployID URLParam = "scheduled_deploy_id"
+	URLParamHelmMirrorRepo    URLParam = "mirror_repo"
+	URLParamHelmMirrorFile    URLParam = "mirror_file"
 	URLParamNamespace         URLParam = "namespace"
 	URLParamReleaseName       URLParam = "name"
 	URLParamReleaseVersion    URLParam = "version"
+	URLParamCommitSHA         URLParam = "commit_sha"
 	URLParamWildcard          URLParam = "*"
 )
 
@@ -69,10 +78,21 @@ type APIRequestMetadata struct {
 
 	// The usage metric that the request should check for, if CheckUsage
 	UsageMetric UsageMetric
+
+	// The entitlement that the project's plan must include, if any
+	Entitlement Entitlement
+
+	// Whether the endpoint deploys releases or changes infra, and is blocked during the
+	// deploy freezes of the project
+	CheckDeployFreeze bool
 }
 
 const RequestScopeCtxKey = "requestscopes"
 
+// HandlerNameCtxKey is the context key of the name of the handler of a request, which
+// tags the logs and alerts of the request
+const HandlerNameCtxKey = "handlername"
+
 type RequestAction struct {
 	Verb     APIVerb
 	Resource NameOrUInt
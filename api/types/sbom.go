@@ -0,0 +1,68 @@
+package types
+
+import "time"
+
+// MaxSBOMBytes is the maximum size of an uploaded SBOM document
+const MaxSBOMBytes = 16 << 20
+
+// SBOMFormat is the format of an uploaded SBOM document
+type SBOMFormat string
+
+const (
+	// SBOMFormatBuildpacks is the bill of materials that buildpacks write to the
+	// io.buildpacks.build.metadata label of the images that they build
+	SBOMFormatBuildpacks SBOMFormat = "buildpacks"
+
+	SBOMFormatCycloneDXJSON SBOMFormat = "cyclonedx-json"
+	SBOMFormatSPDXJSON      SBOMFormat = "spdx-json"
+)
+
+type SBOMPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+// ImageSBOM is the dependency inventory of an image, which is keyed by the repository
+// and tag of the image along with its digest
+type ImageSBOM struct {
+	ID        uint           `json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	ImageRepo string         `json:"image_repo"`
+	Tag       string         `json:"tag"`
+	Digest    string         `json:"digest"`
+	Format    SBOMFormat     `json:"format"`
+	Packages  []*SBOMPackage `json:"packages"`
+}
+
+type CreateReleaseSBOMRequest struct {
+	ImageRepo string     `json:"image_repo" form:"required"`
+	Tag       string     `json:"tag" form:"required"`
+	Digest    string     `json:"digest" form:"required,startswith=sha256:"`
+	Format    SBOMFormat `json:"format" form:"required,oneof=buildpacks cyclonedx-json spdx-json"`
+	Document  string     `json:"document" form:"required"`
+}
+
+type GetReleaseSBOMResponse ImageSBOM
+
+type ListSBOMPackagesRequest struct {
+	// Name matches the packages whose name contains it, ignoring case
+	Name string `schema:"name" form:"required"`
+
+	// Version is an optional semantic version constraint of the packages, such as 2.x or
+	// < 2.17.1
+	Version string `schema:"version"`
+}
+
+// SBOMPackageRelease is a package of the image that a release runs
+type SBOMPackageRelease struct {
+	ClusterID   uint         `json:"cluster_id"`
+	Namespace   string       `json:"namespace"`
+	ReleaseName string       `json:"release_name"`
+	ImageRepo   string       `json:"image_repo"`
+	Digest      string       `json:"digest"`
+	Package     *SBOMPackage `json:"package"`
+}
+
+type ListSBOMPackagesResponse []*SBOMPackageRelease
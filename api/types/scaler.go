@@ -0,0 +1,120 @@
+package types
+
+// ScalerValuesKey is the key of the values of a release that its queue scaler is stored
+// under. The charts of releases ignore the key, and the scaler is rendered into a KEDA
+// ScaledObject when the release is deployed.
+const ScalerValuesKey = "porterScaler"
+
+// ScalerTriggerType is a source of metrics that a release is scaled on
+type ScalerTriggerType string
+
+const (
+	// ScalerTriggerSQS scales on the number of messages in an AWS SQS queue
+	ScalerTriggerSQS ScalerTriggerType = "sqs"
+
+	// ScalerTriggerRabbitMQ scales on the number of messages in a RabbitMQ queue
+	ScalerTriggerRabbitMQ ScalerTriggerType = "rabbitmq"
+
+	// ScalerTriggerRedis scales on the length of a Redis list
+	ScalerTriggerRedis ScalerTriggerType = "redis"
+
+	// ScalerTriggerKafka scales on the consumer group lag of a Kafka topic
+	ScalerTriggerKafka ScalerTriggerType = "kafka"
+
+	// ScalerTriggerPubSub scales on the number of undelivered messages in a GCP Pub/Sub
+	// subscription
+	ScalerTriggerPubSub ScalerTriggerType = "pubsub"
+)
+
+// ReleaseScaler scales the replicas of a release on the depth of queues through KEDA,
+// instead of on CPU and memory
+type ReleaseScaler struct {
+	Enabled bool `json:"enabled"`
+
+	// MinReplicas can be 0, in which case the release is scaled to zero when its queues
+	// are empty
+	MinReplicas int `json:"min_replicas" form:"omitempty,min=0"`
+	MaxReplicas int `json:"max_replicas" form:"omitempty,min=1"`
+
+	// PollingInterval is the interval in seconds at which the triggers are checked
+	PollingInterval int `json:"polling_interval,omitempty" form:"omitempty,min=1"`
+
+	// CooldownPeriod is the time in seconds after the last active trigger before the
+	// release is scaled to zero
+	CooldownPeriod int `json:"cooldown_period,omitempty" form:"omitempty,min=0"`
+
+	Triggers []ScalerTrigger `json:"triggers" form:"dive"`
+}
+
+// ScalerTrigger is a queue that a release is scaled on. Credentials of queues are read
+// from env vars of the release, so they are not stored with the scaler.
+type ScalerTrigger struct {
+	Type ScalerTriggerType `json:"type" form:"required,oneof=sqs rabbitmq redis kafka pubsub"`
+
+	// Target is the queue length or consumer lag per replica
+	Target int `json:"target" form:"required,min=1"`
+
+	// QueueURL and AWSRegion are used by sqs triggers
+	QueueURL  string `json:"queue_url,omitempty"`
+	AWSRegion string `json:"aws_region,omitempty"`
+
+	// AccessKeyIDFromEnv and SecretAccessKeyFromEnv are the env vars of the release that
+	// hold the AWS credentials of an sqs trigger. Without them, the queue is read with the
+	// IAM role of the service account of the release.
+	AccessKeyIDFromEnv     string `json:"access_key_id_from_env,omitempty"`
+	SecretAccessKeyFromEnv string `json:"secret_access_key_from_env,omitempty"`
+
+	// QueueName is used by rabbitmq triggers
+	QueueName string `json:"queue_name,omitempty"`
+
+	// ListName is used by redis triggers
+	ListName string `json:"list_name,omitempty"`
+
+	// BootstrapServers, ConsumerGroup and Topic are used by kafka triggers
+	BootstrapServers string `json:"bootstrap_servers,omitempty"`
+	ConsumerGroup    string `json:"consumer_group,omitempty"`
+	Topic            string `json:"topic,omitempty"`
+
+	// SubscriptionName is used by pubsub triggers
+	SubscriptionName string `json:"subscription_name,omitempty"`
+
+	// HostFromEnv is the env var of the release that holds the connection string of a
+	// rabbitmq or redis trigger
+	HostFromEnv string `json:"host_from_env,omitempty"`
+
+	// PasswordFromEnv is the env var of the release that holds the password of a redis
+	// trigger
+	PasswordFromEnv string `json:"password_from_env,omitempty"`
+
+	// CredentialsFromEnv is the env var of the release that holds the service account key
+	// of a pubsub trigger, which defaults to the env var of provisioned Pub/Sub queues
+	CredentialsFromEnv string `json:"credentials_from_env,omitempty"`
+
+	// Metadata is merged into the metadata of the KEDA trigger, for options that are not
+	// covered by the fields above
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// PubSubCredentialsEnv is the env var that the service account key of provisioned
+// Pub/Sub queues is injected into
+const PubSubCredentialsEnv = "GCP_PUBSUB_CREDENTIALS_JSON"
+
+// SQSAccessKeyIDEnv and SQSSecretAccessKeyEnv are the env vars that the credentials of
+// provisioned SQS queues are injected into
+const (
+	SQSAccessKeyIDEnv     = "AWS_ACCESS_KEY_ID"
+	SQSSecretAccessKeyEnv = "AWS_SECRET_ACCESS_KEY"
+)
+
+type UpdateReleaseScalerRequest struct {
+	ReleaseScaler
+}
+
+type GetReleaseScalerResponse struct {
+	*ReleaseScaler
+}
+
+type KEDAStatusResponse struct {
+	Installed bool   `json:"installed"`
+	Version   string `json:"version,omitempty"`
+}
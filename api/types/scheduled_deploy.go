@@ -0,0 +1,58 @@
+package types
+
+import "time"
+
+type ScheduledDeployStatus string
+
+const (
+	ScheduledDeployPending   ScheduledDeployStatus = "pending"
+	ScheduledDeployRunning   ScheduledDeployStatus = "running"
+	ScheduledDeploySucceeded ScheduledDeployStatus = "succeeded"
+	ScheduledDeployFailed    ScheduledDeployStatus = "failed"
+	ScheduledDeployCanceled  ScheduledDeployStatus = "canceled"
+)
+
+// ScheduledDeploy is an upgrade of a release that runs at a future time. The values and
+// patch of the upgrade are not returned, since they may contain secrets.
+type ScheduledDeploy struct {
+	ID        uint                  `json:"id"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	Namespace string                `json:"namespace"`
+	Name      string                `json:"name"`
+	Status    ScheduledDeployStatus `json:"status"`
+	RunAt     time.Time             `json:"run_at"`
+
+	ChartVersion string `json:"chart_version,omitempty"`
+	ImageTag     string `json:"image_tag,omitempty"`
+	HasValues    bool   `json:"has_values"`
+	HasPatch     bool   `json:"has_patch"`
+	Message      string `json:"message,omitempty"`
+
+	ScheduledBy uint       `json:"scheduled_by"`
+	ExecutedAt  *time.Time `json:"executed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// CreateScheduledDeployRequest is an upgrade to run at a future time. At most one of the
+// values, the patch and the image tag can be set. The patch and image tag are applied to
+// the values of the release at the time that the upgrade runs.
+type CreateScheduledDeployRequest struct {
+	Values       string          `json:"values,omitempty"`
+	ChartVersion string          `json:"version,omitempty"`
+	Patch        string          `json:"patch,omitempty"`
+	PatchType    ValuesPatchType `json:"patch_type,omitempty" form:"omitempty,oneof=merge strategic"`
+	ImageTag     string          `json:"image_tag,omitempty"`
+	Message      string          `json:"message,omitempty" form:"omitempty,max=1000"`
+
+	RunAt time.Time `json:"run_at" form:"required"`
+}
+
+// UpdateScheduledDeployRequest replaces the upgrade and time of a pending scheduled deploy
+type UpdateScheduledDeployRequest CreateScheduledDeployRequest
+
+type ListScheduledDeploysRequest struct {
+	Status ScheduledDeployStatus `schema:"status"`
+}
+
+type ListScheduledDeploysResponse []*ScheduledDeploy
@@ -0,0 +1,45 @@
+package types
+
+import "time"
+
+const URLParamServerSettingKey URLParam = "key"
+
+// ServerSettingKey is a setting of the server that instance admins can override at
+// runtime, without changing the environment and restarting the server
+type ServerSettingKey string
+
+const (
+	ServerSettingAppRootDomain                 ServerSettingKey = "app_root_domain"
+	ServerSettingDefaultApplicationHelmRepoURL ServerSettingKey = "default_application_helm_repo_url"
+	ServerSettingDefaultAddonHelmRepoURL       ServerSettingKey = "default_addon_helm_repo_url"
+	ServerSettingDisableAllowlist              ServerSettingKey = "disable_allowlist"
+	ServerSettingAnalyticsOptOut               ServerSettingKey = "analytics_opt_out"
+)
+
+// ServerSettingKeys are all settings that can be overridden at runtime
+var ServerSettingKeys = []ServerSettingKey{
+	ServerSettingAppRootDomain,
+	ServerSettingDefaultApplicationHelmRepoURL,
+	ServerSettingDefaultAddonHelmRepoURL,
+	ServerSettingDisableAllowlist,
+	ServerSettingAnalyticsOptOut,
+}
+
+type ServerSetting struct {
+	Key ServerSettingKey `json:"key"`
+
+	// Value is the current value of the setting, which is the override if the setting
+	// is overridden, and the value from the environment otherwise
+	Value string `json:"value"`
+
+	EnvValue   string     `json:"env_value"`
+	Overridden bool       `json:"overridden"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	UpdatedBy  uint       `json:"updated_by,omitempty"`
+}
+
+type ListServerSettingsResponse []*ServerSetting
+
+type UpdateServerSettingRequest struct {
+	Value string `json:"value" form:"required"`
+}
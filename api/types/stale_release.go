@@ -0,0 +1,29 @@
+package types
+
+import "time"
+
+type StaleReason string
+
+const (
+	StaleReasonNoDeploys  StaleReason = "no_deploys"
+	StaleReasonNoTraffic  StaleReason = "no_traffic"
+	StaleReasonScaledDown StaleReason = "scaled_down"
+)
+
+// StaleRelease is a release that has not been deployed for the stale period, has been
+// scaled down to no replicas for the period and has received no traffic in the period.
+// Traffic is only checked in clusters with metrics for their ingress controller.
+type StaleRelease struct {
+	ClusterID uint   `json:"cluster_id"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	LastDeployedAt  time.Time     `json:"last_deployed_at"`
+	ScaledDownSince *time.Time    `json:"scaled_down_since,omitempty"`
+	TrafficChecked  bool          `json:"traffic_checked"`
+	Reasons         []StaleReason `json:"reasons"`
+	StaleSince      *time.Time    `json:"stale_since,omitempty"`
+	LastCheckedAt   time.Time     `json:"last_checked_at"`
+}
+
+type ListStaleReleasesResponse []*StaleRelease
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	"github.com/porter-dev/porter/internal/helm/upgrade"
 	"helm.sh/helm/v3/pkg/chart"
 )
@@ -8,6 +10,7 @@ import (
 const (
 	URLParamTemplateName    URLParam = "name"
 	URLParamTemplateVersion URLParam = "version"
+	URLParamTemplateRepoID  URLParam = "template_repo_id"
 )
 
 type TemplateGetBaseRequest struct {
@@ -23,6 +26,10 @@ type PorterTemplateSimple struct {
 	Versions    []string `json:"versions"`
 	Description string   `json:"description"`
 	Icon        string   `json:"icon"`
+
+	// RepoURL is the repo that the template was loaded from. It is only set when the
+	// templates of a project are listed.
+	RepoURL string `json:"repo_url,omitempty"`
 }
 
 // ListTemplatesResponse is how a chart gets displayed when listed
@@ -40,9 +47,44 @@ type GetTemplateResponse struct {
 	Form     *FormYAML              `json:"form"`
 }
 
+type GetTemplateFormRequest struct {
+	TemplateGetBaseRequest
+}
+
+// RenderTemplateFormRequest is form data submitted for the form of a template, keyed by
+// the variables of the form fields
+type RenderTemplateFormRequest struct {
+	RepoURL string                 `json:"repo_url"`
+	Data    map[string]interface{} `json:"data"`
+}
+
+// RenderTemplateFormResponse contains the values that the form data was converted to, or
+// the validation errors if the form data is invalid
+type RenderTemplateFormResponse struct {
+	Values map[string]interface{} `json:"values,omitempty"`
+	Errors []*FormValidationError `json:"errors,omitempty"`
+}
+
 type GetTemplateUpgradeNotesRequest struct {
 	TemplateGetBaseRequest
 	PrevVersion string `schema:"prev_version"`
 }
 
 type GetTemplateUpgradeNotesResponse upgrade.UpgradeFile
+
+// TemplateRepo is a Helm repo registered by a project, whose charts are listed as
+// templates of the project alongside the default templates
+type TemplateRepo struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ProjectID uint      `json:"project_id"`
+	Name      string    `json:"name"`
+	RepoURL   string    `json:"repo_url"`
+}
+
+type CreateTemplateRepoRequest struct {
+	Name    string `json:"name" form:"required"`
+	RepoURL string `json:"repo_url" form:"required,url"`
+}
+
+type ListTemplateReposResponse []*TemplateRepo
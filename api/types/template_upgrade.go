@@ -0,0 +1,69 @@
+package types
+
+// ValuesSchemaChangeKind is the kind of change of a value between the default values of
+// two versions of a chart
+type ValuesSchemaChangeKind string
+
+const (
+	ValuesSchemaChangeAdded       ValuesSchemaChangeKind = "added"
+	ValuesSchemaChangeRemoved     ValuesSchemaChangeKind = "removed"
+	ValuesSchemaChangeTypeChanged ValuesSchemaChangeKind = "type_changed"
+)
+
+// ValuesMigrationAction is how the migrated values of a release handle a breaking change
+type ValuesMigrationAction string
+
+const (
+	ValuesMigrationDropped   ValuesMigrationAction = "dropped"
+	ValuesMigrationConverted ValuesMigrationAction = "converted"
+)
+
+type ValuesSchemaChange struct {
+	// Path is the dot-separated path of the value
+	Path     string                 `json:"path"`
+	Kind     ValuesSchemaChangeKind `json:"kind"`
+	PrevType string                 `json:"prev_type,omitempty"`
+	NextType string                 `json:"next_type,omitempty"`
+
+	// Breaking is set for changes of values that the release sets, which are migrated
+	// as described by the migration
+	Breaking  bool                  `json:"breaking"`
+	Migration ValuesMigrationAction `json:"migration,omitempty"`
+}
+
+type GetTemplateUpgradeRequest struct {
+	// Version is the chart version to upgrade to, and defaults to the latest version
+	Version string `schema:"version"`
+}
+
+// TemplateUpgrade compares the chart version of a release to a newer version of its
+// chart, and proposes migrated values for the newer version
+type TemplateUpgrade struct {
+	ChartName      string `json:"chart_name"`
+	CurrentVersion string `json:"current_version"`
+	TargetVersion  string `json:"target_version"`
+	LatestVersion  string `json:"latest_version"`
+
+	Changes            []*ValuesSchemaChange `json:"changes"`
+	HasBreakingChanges bool                  `json:"has_breaking_changes"`
+
+	// MigratedValues is the YAML document of the values of the release, migrated to the
+	// target version
+	MigratedValues string `json:"migrated_values"`
+
+	// ValuesDiff lists the changes from the current values of the release to the migrated
+	// values. Sensitive values are redacted.
+	ValuesDiff []string `json:"values_diff"`
+}
+
+type ApplyTemplateUpgradeRequest struct {
+	// Version is the chart version to upgrade to, and defaults to the latest version
+	Version string `json:"version"`
+
+	// Values replace the proposed migrated values, such as when the proposed values
+	// were edited
+	Values string `json:"values,omitempty"`
+
+	Message  string `json:"message,omitempty" form:"omitempty,max=1000"`
+	Revision int    `json:"revision,omitempty"`
+}
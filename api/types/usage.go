@@ -64,3 +64,41 @@ type GetProjectUsageResponse struct {
 	// When the usage has been exceeded since, if IsExceeded
 	ExceededSince *time.Time `json:"exceeded_since,omitempty"`
 }
+
+// ClusterInventory is the capacity and resource requests of a cluster, along with the
+// number of releases and databases in the cluster. CPU is in millicores and memory is in
+// bytes. If the cluster cannot be reached, only the databases are counted and the error
+// is set.
+type ClusterInventory struct {
+	ClusterID   uint   `json:"cluster_id"`
+	ClusterName string `json:"cluster_name"`
+
+	Nodes             uint `json:"nodes"`
+	CPUAllocatable    uint `json:"cpu_allocatable"`
+	CPURequested      uint `json:"cpu_requested"`
+	MemoryAllocatable uint `json:"memory_allocatable"`
+	MemoryRequested   uint `json:"memory_requested"`
+	Releases          uint `json:"releases"`
+	Databases         uint `json:"databases"`
+
+	CollectedAt time.Time `json:"collected_at"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// ProjectInventory is the total inventory of the clusters of a project, along with the
+// usage limit of the project
+type ProjectInventory struct {
+	Clusters          uint `json:"clusters"`
+	Nodes             uint `json:"nodes"`
+	CPUAllocatable    uint `json:"cpu_allocatable"`
+	CPURequested      uint `json:"cpu_requested"`
+	MemoryAllocatable uint `json:"memory_allocatable"`
+	MemoryRequested   uint `json:"memory_requested"`
+	Releases          uint `json:"releases"`
+	Registries        uint `json:"registries"`
+	Databases         uint `json:"databases"`
+
+	Limit ProjectUsage `json:"limit"`
+
+	ClusterInventories []*ClusterInventory `json:"cluster_inventories"`
+}
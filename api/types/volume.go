@@ -0,0 +1,51 @@
+package types
+
+import "time"
+
+const (
+	URLParamVolumeClaimName URLParam = "volume_claim_name"
+)
+
+// VolumeClaim is a persistent volume claim of a release
+type VolumeClaim struct {
+	Name         string   `json:"name"`
+	Namespace    string   `json:"namespace"`
+	Status       string   `json:"status"`
+	StorageClass string   `json:"storage_class"`
+	AccessModes  []string `json:"access_modes"`
+
+	// Capacity is the capacity of the bound volume, and RequestedCapacity is the capacity
+	// requested by the claim. They differ while a resize is in progress.
+	Capacity          string `json:"capacity"`
+	CapacityBytes     int64  `json:"capacity_bytes"`
+	RequestedCapacity string `json:"requested_capacity"`
+
+	// UsedBytes is only set if the usage of the volume is reported by Prometheus
+	UsedBytes *int64 `json:"used_bytes,omitempty"`
+
+	// Resizable is true if the storage class of the claim allows volume expansion
+	Resizable bool `json:"resizable"`
+}
+
+type ListVolumeClaimsResponse []*VolumeClaim
+
+type ResizeVolumeClaimRequest struct {
+	// Size is the new capacity of the claim, such as 20Gi
+	Size string `json:"size" form:"required"`
+}
+
+// CreateVolumeSnapshotRequest creates a VolumeSnapshot of a claim, which requires the CSI
+// snapshot controller to be installed in the cluster. The name defaults to a generated
+// name, and the snapshot class defaults to the default snapshot class of the cluster.
+type CreateVolumeSnapshotRequest struct {
+	Name              string `json:"name"`
+	SnapshotClassName string `json:"snapshot_class_name"`
+}
+
+type VolumeSnapshot struct {
+	Name              string    `json:"name"`
+	Namespace         string    `json:"namespace"`
+	VolumeClaimName   string    `json:"volume_claim_name"`
+	SnapshotClassName string    `json:"snapshot_class_name,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
@@ -0,0 +1,601 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/spf13/cobra"
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Commands for the instance admins of a self-hosted Porter instance.",
+	Long: fmt.Sprintf(`
+%s
+
+Manages the projects, users, quotas, whitelisted users and settings of a self-hosted Porter instance.
+These commands require the current user to be an instance admin. For example:
+
+  %s
+
+To limit the number of projects that each user can create:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter admin\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter admin users list"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter admin quotas set --max-projects-per-user 3"),
+	),
+}
+
+var adminProjectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "Lists all projects of the instance.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminListProjects)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminUsersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Commands that manage the users of the instance.",
+}
+
+var adminUsersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all users of the instance.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminListUsers)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminUsersPromoteCmd = &cobra.Command{
+	Use:   "promote [user_id]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Makes a user an instance admin.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminPromoteUser)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminUsersDemoteCmd = &cobra.Command{
+	Use:   "demote [user_id]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Removes the instance admin role of a user.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminDemoteUser)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminQuotasCmd = &cobra.Command{
+	Use:   "quotas",
+	Short: "Commands that manage the quotas of the instance.",
+}
+
+var adminQuotasGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Prints the quotas of the instance.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminGetQuota)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminQuotasSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Sets the quotas of the instance. A quota of 0 is unlimited.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, func(user *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+			return adminSetQuota(cmd, client)
+		})
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminWhitelistCmd = &cobra.Command{
+	Use:   "whitelist",
+	Short: "Commands that manage the users that do not count toward usage limits.",
+}
+
+var adminWhitelistListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the ids of the whitelisted users.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminListWhitelistedUsers)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminWhitelistAddCmd = &cobra.Command{
+	Use:   "add [user_id]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Adds a user to the whitelisted users.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminWhitelistUser)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminWhitelistRemoveCmd = &cobra.Command{
+	Use:   "remove [user_id]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Removes a user from the whitelisted users.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminRemoveWhitelistedUser)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminSettingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Commands that override the settings of the server at runtime.",
+}
+
+var adminSettingsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the settings of the server.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminListSettings)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminSettingsSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Args:  cobra.ExactArgs(2),
+	Short: "Overrides the value of a setting from the environment.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminSetSetting)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminSettingsResetCmd = &cobra.Command{
+	Use:   "reset [key]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Resets a setting to the value from the environment.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminResetSetting)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminAuditLogsCmd = &cobra.Command{
+	Use:   "audit-logs",
+	Short: "Lists the actions of instance admins, starting with the most recent.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, adminListAuditLogs)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var adminListLimit int
+var adminListSkip int
+
+var adminMaxProjectsPerUser uint
+var adminMaxClustersPerProject uint
+var adminMaxUsersPerProject uint
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+
+	adminCmd.AddCommand(adminProjectsCmd)
+	adminCmd.AddCommand(adminUsersCmd)
+	adminCmd.AddCommand(adminQuotasCmd)
+	adminCmd.AddCommand(adminWhitelistCmd)
+	adminCmd.AddCommand(adminSettingsCmd)
+	adminCmd.AddCommand(adminAuditLogsCmd)
+
+	adminUsersCmd.AddCommand(adminUsersListCmd)
+	adminUsersCmd.AddCommand(adminUsersPromoteCmd)
+	adminUsersCmd.AddCommand(adminUsersDemoteCmd)
+
+	adminQuotasCmd.AddCommand(adminQuotasGetCmd)
+	adminQuotasCmd.AddCommand(adminQuotasSetCmd)
+
+	adminWhitelistCmd.AddCommand(adminWhitelistListCmd)
+	adminWhitelistCmd.AddCommand(adminWhitelistAddCmd)
+	adminWhitelistCmd.AddCommand(adminWhitelistRemoveCmd)
+
+	adminSettingsCmd.AddCommand(adminSettingsListCmd)
+	adminSettingsCmd.AddCommand(adminSettingsSetCmd)
+	adminSettingsCmd.AddCommand(adminSettingsResetCmd)
+
+	for _, cmd := range []*cobra.Command{adminProjectsCmd, adminUsersListCmd, adminAuditLogsCmd} {
+		cmd.PersistentFlags().IntVar(
+			&adminListLimit,
+			"limit",
+			50,
+			"the maximum number of results",
+		)
+
+		cmd.PersistentFlags().IntVar(
+			&adminListSkip,
+			"skip",
+			0,
+			"the number of results to skip",
+		)
+	}
+
+	adminQuotasSetCmd.PersistentFlags().UintVar(
+		&adminMaxProjectsPerUser,
+		"max-projects-per-user",
+		0,
+		"the maximum number of projects that a user can create",
+	)
+
+	adminQuotasSetCmd.PersistentFlags().UintVar(
+		&adminMaxClustersPerProject,
+		"max-clusters-per-project",
+		0,
+		"the maximum number of clusters of a project",
+	)
+
+	adminQuotasSetCmd.PersistentFlags().UintVar(
+		&adminMaxUsersPerProject,
+		"max-users-per-project",
+		0,
+		"the maximum number of users of a project",
+	)
+}
+
+func adminListProjects(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	resp, err := client.AdminListProjects(context.Background(), &types.AdminListRequest{
+		Limit: adminListLimit,
+		Skip:  adminListSkip,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if ok, err := printStructuredOutput(resp); ok {
+		return err
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "ID", "NAME", "USERS", "CREATED")
+
+	for _, project := range resp.Projects {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", project.ID, project.Name, len(project.Roles), project.CreatedAt.Format("2006-01-02"))
+	}
+
+	w.Flush()
+
+	fmt.Printf("Showing %d of %d projects\n", len(resp.Projects), resp.Count)
+
+	return nil
+}
+
+func adminListUsers(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	resp, err := client.AdminListUsers(context.Background(), &types.AdminListRequest{
+		Limit: adminListLimit,
+		Skip:  adminListSkip,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if ok, err := printStructuredOutput(resp); ok {
+		return err
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "ID", "EMAIL", "ADMIN", "WHITELISTED", "CREATED")
+
+	for _, user := range resp.Users {
+		fmt.Fprintf(
+			w, "%d\t%s\t%t\t%t\t%s\n",
+			user.ID, user.Email, user.InstanceAdmin, user.Whitelisted, user.CreatedAt.Format("2006-01-02"),
+		)
+	}
+
+	w.Flush()
+
+	fmt.Printf("Showing %d of %d users\n", len(resp.Users), resp.Count)
+
+	return nil
+}
+
+func adminPromoteUser(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	return adminSetInstanceAdmin(client, args[0], true)
+}
+
+func adminDemoteUser(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	return adminSetInstanceAdmin(client, args[0], false)
+}
+
+func adminSetInstanceAdmin(client *api.Client, userIDArg string, instanceAdmin bool) error {
+	userID, err := parseAdminUserID(userIDArg)
+
+	if err != nil {
+		return err
+	}
+
+	user, err := client.AdminUpdateUser(context.Background(), userID, &types.AdminUpdateUserRequest{
+		InstanceAdmin: &instanceAdmin,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if user.InstanceAdmin {
+		color.New(color.FgGreen).Printf("%s is an instance admin\n", user.Email)
+	} else {
+		color.New(color.FgGreen).Printf("%s is not an instance admin\n", user.Email)
+	}
+
+	return nil
+}
+
+func adminGetQuota(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	quota, err := client.AdminGetQuota(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return printInstanceQuota(quota)
+}
+
+// adminSetQuota only changes the quotas whose flags were set
+func adminSetQuota(cmd *cobra.Command, client *api.Client) error {
+	quota, err := client.AdminGetQuota(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.PersistentFlags()
+
+	if flags.Changed("max-projects-per-user") {
+		quota.MaxProjectsPerUser = adminMaxProjectsPerUser
+	}
+
+	if flags.Changed("max-clusters-per-project") {
+		quota.MaxClustersPerProject = adminMaxClustersPerProject
+	}
+
+	if flags.Changed("max-users-per-project") {
+		quota.MaxUsersPerProject = adminMaxUsersPerProject
+	}
+
+	req := types.UpdateInstanceQuotaRequest(*quota)
+
+	quota, err = client.AdminUpdateQuota(context.Background(), &req)
+
+	if err != nil {
+		return err
+	}
+
+	return printInstanceQuota(quota)
+}
+
+func printInstanceQuota(quota *types.InstanceQuota) error {
+	if ok, err := printStructuredOutput(quota); ok {
+		return err
+	}
+
+	formatQuota := func(quota uint) string {
+		if quota == 0 {
+			return "unlimited"
+		}
+
+		return fmt.Sprintf("%d", quota)
+	}
+
+	fmt.Printf("Max projects per user: %s\n", formatQuota(quota.MaxProjectsPerUser))
+	fmt.Printf("Max clusters per project: %s\n", formatQuota(quota.MaxClustersPerProject))
+	fmt.Printf("Max users per project: %s\n", formatQuota(quota.MaxUsersPerProject))
+
+	return nil
+}
+
+func adminListWhitelistedUsers(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	userIDs, err := client.AdminListWhitelistedUsers(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	if ok, err := printStructuredOutput(userIDs); ok {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		fmt.Println(userID)
+	}
+
+	return nil
+}
+
+func adminWhitelistUser(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	userID, err := parseAdminUserID(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	if err := client.AdminWhitelistUser(context.Background(), &types.AdminWhitelistUserRequest{
+		UserID: userID,
+	}); err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("User %d is whitelisted\n", userID)
+
+	return nil
+}
+
+func adminRemoveWhitelistedUser(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	userID, err := parseAdminUserID(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	if err := client.AdminRemoveWhitelistedUser(context.Background(), userID); err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("User %d is no longer whitelisted\n", userID)
+
+	return nil
+}
+
+func adminListSettings(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	settings, err := client.AdminListSettings(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return printServerSettings(settings)
+}
+
+func adminSetSetting(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	settings, err := client.AdminUpdateSetting(context.Background(), types.ServerSettingKey(args[0]), &types.UpdateServerSettingRequest{
+		Value: args[1],
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return printServerSettings(settings)
+}
+
+func adminResetSetting(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	settings, err := client.AdminResetSetting(context.Background(), types.ServerSettingKey(args[0]))
+
+	if err != nil {
+		return err
+	}
+
+	return printServerSettings(settings)
+}
+
+func printServerSettings(settings types.ListServerSettingsResponse) error {
+	if ok, err := printStructuredOutput(settings); ok {
+		return err
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\n", "KEY", "VALUE", "ENVIRONMENT VALUE")
+
+	for _, setting := range settings {
+		if setting.Overridden {
+			color.New(color.FgGreen).Fprintf(w, "%s\t%s (overridden)\t%s\n", setting.Key, setting.Value, setting.EnvValue)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", setting.Key, setting.Value, setting.EnvValue)
+		}
+	}
+
+	w.Flush()
+
+	return nil
+}
+
+func adminListAuditLogs(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	resp, err := client.AdminListAuditLogs(context.Background(), &types.AdminListRequest{
+		Limit: adminListLimit,
+		Skip:  adminListSkip,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if ok, err := printStructuredOutput(resp); ok {
+		return err
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "TIME", "ADMIN", "ACTION", "TARGET USER", "DETAILS")
+
+	for _, log := range resp.AuditLogs {
+		targetUser := ""
+
+		if log.TargetUserID != 0 {
+			targetUser = fmt.Sprintf("%d", log.TargetUserID)
+		}
+
+		fmt.Fprintf(
+			w, "%s\t%d\t%s\t%s\t%s\n",
+			log.CreatedAt.Format("2006-01-02 15:04:05"), log.AdminUserID, log.Action, targetUser, log.Details,
+		)
+	}
+
+	w.Flush()
+
+	return nil
+}
+
+func parseAdminUserID(arg string) (uint, error) {
+	userID, err := strconv.ParseUint(arg, 10, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid user id %s", arg)
+	}
+
+	return uint(userID), nil
+}
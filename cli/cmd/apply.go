@@ -242,7 +242,8 @@ func (d *Driver) applyAddon(resource *models.Resource, client *api.Client, shoul
 			d.target.Namespace,
 			resource.Name,
 			&types.UpgradeReleaseRequest{
-				Values: string(bytes),
+				Values:    string(bytes),
+				CommitSHA: os.Getenv("GITHUB_SHA"),
 			},
 		)
 	}
@@ -796,7 +797,9 @@ func (t *DeploymentHook) PreApply() error {
 	// TODO: case this on the response status code rather than text
 	if err != nil && strings.Contains(err.Error(), "deployment not found") {
 		// in this case, create the deployment
-		_, err = t.client.CreateDeployment(
+		var depl *types.Deployment
+
+		depl, err = t.client.CreateDeployment(
 			context.Background(),
 			t.projectID, t.gitInstallationID, t.clusterID,
 			t.repoOwner, t.repoName,
@@ -815,6 +818,10 @@ func (t *DeploymentHook) PreApply() error {
 				},
 			},
 		)
+
+		if err == nil && depl.Status == types.DeploymentStatusPendingApproval {
+			return fmt.Errorf("this pull request is from a fork, and its preview environment is pending the approval of a maintainer")
+		}
 	} else if err == nil {
 		_, err = t.client.UpdateDeployment(
 			context.Background(),
@@ -0,0 +1,373 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/switchboard/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+var bulkUpdateCmd = &cobra.Command{
+	Use:   "bulk-update",
+	Short: "Updates a set of applications in dependency order.",
+	Long: fmt.Sprintf(`
+%s
+
+Updates a set of applications in the order of their dependencies, which are computed from the
+dependencies declared for each application and from the references between applications in their
+env groups and values. Applications are updated in stages: each stage only contains applications
+whose dependencies were updated in earlier stages, and the command waits for the rollouts of a stage
+to complete before starting the next one. For example:
+
+  %s
+
+The applications can also be read from the resources of a porter.yaml file:
+
+  %s
+
+By default, the current configuration of each application is redeployed, optionally with a new image
+tag passed via the --tag flag. To build each application from its remote Git repository before
+updating it, use the --build flag:
+
+  %s
+
+If an application fails to update or roll out, or its update is pending approval since the application
+is protected, the applications in later stages are skipped. Applications that are not in the dependency
+graph are updated in the last stage. The command prints the result for each application, and exits with
+a non-zero code if any application was not deployed.
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter bulk-update\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter bulk-update --apps api,web,worker --tag v1.2.0"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter bulk-update -f porter.yaml --namespace production"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter bulk-update --apps api,web --build"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, bulkUpdate)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var bulkUpdateApps []string
+var bulkUpdateBuild bool
+
+func init() {
+	rootCmd.AddCommand(bulkUpdateCmd)
+
+	bulkUpdateCmd.PersistentFlags().StringSliceVar(
+		&bulkUpdateApps,
+		"apps",
+		[]string{},
+		"Comma-separated list of applications in the Porter dashboard",
+	)
+
+	bulkUpdateCmd.PersistentFlags().StringVarP(
+		&porterYAML,
+		"file",
+		"f",
+		"",
+		"path to a porter.yaml file to read the applications from",
+	)
+
+	bulkUpdateCmd.PersistentFlags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"Namespace of the applications",
+	)
+
+	bulkUpdateCmd.PersistentFlags().StringVarP(
+		&tag,
+		"tag",
+		"t",
+		"",
+		"the image tag to update the applications to",
+	)
+
+	bulkUpdateCmd.PersistentFlags().BoolVar(
+		&bulkUpdateBuild,
+		"build",
+		false,
+		"build and push a new image for each application from its remote Git repository",
+	)
+
+	bulkUpdateCmd.PersistentFlags().DurationVar(
+		&waitTimeout,
+		"wait-timeout",
+		5*time.Minute,
+		"the maximum time to wait for the rollout of each application to complete",
+	)
+}
+
+type bulkUpdateResult struct {
+	app    string
+	stage  int
+	status string
+	err    error
+}
+
+func bulkUpdate(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	apps, err := getBulkUpdateApps()
+
+	if err != nil {
+		return err
+	}
+
+	stages, err := getBulkUpdateStages(client, apps)
+
+	if err != nil {
+		return err
+	}
+
+	results := make([]*bulkUpdateResult, 0, len(apps))
+	failed := false
+
+	for i, stage := range stages {
+		if failed {
+			for _, stageApp := range stage {
+				results = append(results, &bulkUpdateResult{
+					app:    stageApp,
+					stage:  i + 1,
+					status: "skipped",
+				})
+			}
+
+			continue
+		}
+
+		color.New(color.FgBlue, color.Bold).Printf("Stage %d of %d: %s\n", i+1, len(stages), strings.Join(stage, ", "))
+
+		stageResults := make(map[string]*bulkUpdateResult)
+
+		// all applications of a stage are updated before waiting for their rollouts, so
+		// that they roll out concurrently
+		for _, stageApp := range stage {
+			result := &bulkUpdateResult{
+				app:   stageApp,
+				stage: i + 1,
+			}
+
+			stageResults[stageApp] = result
+			results = append(results, result)
+
+			err := bulkUpdateApp(client, stageApp)
+
+			var changeRequestedErr *api.ChangeRequestedError
+
+			if errors.As(err, &changeRequestedErr) {
+				// upgrades of protected applications are not deployed until they are
+				// approved, so the applications that depend on them cannot be updated yet
+				color.New(color.FgYellow).Println(err.Error())
+
+				result.status = "pending approval"
+				result.err = err
+				failed = true
+			} else if err != nil {
+				color.New(color.FgRed).Printf("Could not update %s: %s\n", stageApp, err.Error())
+
+				result.status = "failed"
+				result.err = err
+				failed = true
+			}
+		}
+
+		for _, stageApp := range stage {
+			result := stageResults[stageApp]
+
+			if result.err != nil {
+				continue
+			}
+
+			if err := waitForReleaseRollout(client, namespace, stageApp); err != nil {
+				color.New(color.FgRed).Println(err.Error())
+
+				result.status = "failed"
+				result.err = err
+				failed = true
+
+				continue
+			}
+
+			result.status = "deployed"
+		}
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "STAGE", "APP", "STATUS", "ERROR")
+
+	for _, result := range results {
+		errStr := ""
+
+		if result.err != nil {
+			errStr = result.err.Error()
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", result.stage, result.app, result.status, errStr)
+	}
+
+	w.Flush()
+
+	if failed {
+		return fmt.Errorf("not all applications were deployed")
+	}
+
+	color.New(color.FgGreen).Printf("Successfully updated %d applications\n", len(results))
+
+	return nil
+}
+
+// getBulkUpdateApps returns the applications passed via the --apps flag, or the
+// resources of the porter.yaml file passed via the --file flag
+func getBulkUpdateApps() ([]string, error) {
+	if len(bulkUpdateApps) > 0 && porterYAML != "" {
+		return nil, fmt.Errorf("only one of --apps and --file can be set")
+	}
+
+	apps := bulkUpdateApps
+
+	if porterYAML != "" {
+		fileBytes, err := ioutil.ReadFile(porterYAML)
+
+		if err != nil {
+			return nil, err
+		}
+
+		resGroup, err := parser.ParseRawBytes(fileBytes)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resource := range resGroup.Resources {
+			apps = append(apps, resource.Name)
+		}
+	}
+
+	if len(apps) == 0 {
+		return nil, fmt.Errorf("no applications to update: set either --apps or --file")
+	}
+
+	return apps, nil
+}
+
+// getBulkUpdateStages orders applications in stages using the dependency graph of the
+// namespace
+func getBulkUpdateStages(client *api.Client, apps []string) ([][]string, error) {
+	graph, err := client.GetDependencyGraph(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		&types.GetDependencyGraphRequest{
+			Namespace: namespace,
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not get the dependency graph: %w", err)
+	}
+
+	stages, missing, err := orderBulkUpdateApps(graph, namespace, apps)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		color.New(color.FgYellow).Fprintf(
+			os.Stderr,
+			"The dependencies of %s are unknown, so they are updated in the last stage\n",
+			strings.Join(missing, ", "),
+		)
+	}
+
+	return stages, nil
+}
+
+// orderBulkUpdateApps orders applications in the stages of the dependency graph.
+// Dependencies on applications that are not updated are already deployed, so the
+// applications are only ordered relative to each other. Applications that are not in the
+// graph, such as releases whose resources are not labeled, are returned separately and
+// added to a last stage, since their dependencies are unknown.
+func orderBulkUpdateApps(graph *types.DependencyGraph, namespace string, apps []string) ([][]string, []string, error) {
+	selected := make(map[string]bool)
+
+	for _, selectedApp := range apps {
+		selected[fmt.Sprintf("%s/%s", namespace, selectedApp)] = true
+	}
+
+	for _, id := range graph.Cyclic {
+		if selected[id] {
+			return nil, nil, fmt.Errorf("cannot order %s, since it is part of or depends on a dependency cycle", id)
+		}
+	}
+
+	res := make([][]string, 0)
+	found := make(map[string]bool)
+
+	for _, graphStage := range graph.Stages {
+		stage := make([]string, 0)
+
+		for _, id := range graphStage {
+			if selected[id] {
+				found[id] = true
+				stage = append(stage, strings.TrimPrefix(id, namespace+"/"))
+			}
+		}
+
+		if len(stage) > 0 {
+			res = append(res, stage)
+		}
+	}
+
+	missing := make([]string, 0)
+
+	for _, selectedApp := range apps {
+		id := fmt.Sprintf("%s/%s", namespace, selectedApp)
+
+		if !found[id] {
+			// apps may be passed more than once
+			found[id] = true
+			missing = append(missing, selectedApp)
+		}
+	}
+
+	if len(missing) > 0 {
+		res = append(res, missing)
+	}
+
+	return res, missing, nil
+}
+
+func bulkUpdateApp(client *api.Client, stageApp string) error {
+	updateAgent, err := getUpdateAgent(client, stageApp, !bulkUpdateBuild)
+
+	if err != nil {
+		return err
+	}
+
+	if bulkUpdateBuild {
+		if err := updateBuildWithAgent(updateAgent); err != nil {
+			return err
+		}
+
+		if err := updatePushWithAgent(updateAgent); err != nil {
+			return err
+		}
+	}
+
+	return updateUpgradeWithAgent(updateAgent)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+func TestOrderBulkUpdateApps(t *testing.T) {
+	graph := &types.DependencyGraph{
+		Stages: [][]string{
+			{"default/db", "default/cache"},
+			{"default/api", "staging/api"},
+			{"default/web"},
+		},
+		Cyclic: []string{"default/a", "default/b"},
+	}
+
+	tests := []struct {
+		name            string
+		apps            []string
+		expectedStages  [][]string
+		expectedMissing []string
+		expectedErr     string
+	}{
+		{
+			name:            "ordered relative to each other",
+			apps:            []string{"web", "db"},
+			expectedStages:  [][]string{{"db"}, {"web"}},
+			expectedMissing: []string{},
+		},
+		{
+			name:            "apps missing from the graph are updated last",
+			apps:            []string{"worker", "api", "worker", "cron"},
+			expectedStages:  [][]string{{"api"}, {"worker", "cron"}},
+			expectedMissing: []string{"worker", "cron"},
+		},
+		{
+			name:        "cyclic",
+			apps:        []string{"web", "a"},
+			expectedErr: "cannot order default/a, since it is part of or depends on a dependency cycle",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stages, missing, err := orderBulkUpdateApps(graph, "default", test.apps)
+
+			if test.expectedErr != "" {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(stages, test.expectedStages) {
+				t.Errorf("expected stages %v, got %v", test.expectedStages, stages)
+			}
+
+			if !reflect.DeepEqual(missing, test.expectedMissing) {
+				t.Errorf("expected missing apps %v, got %v", test.expectedMissing, missing)
+			}
+		})
+	}
+}
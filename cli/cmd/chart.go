@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// chartCmd represents the "porter chart" base command when called
+// without any subcommands
+var chartCmd = &cobra.Command{
+	Use:     "chart",
+	Aliases: []string{"charts"},
+	Short:   "Commands that manage the custom Helm charts of a project",
+}
+
+var chartPushCmd = &cobra.Command{
+	Use:   "push [path]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Adds a version of a custom chart from a chart directory or packaged chart.",
+	Long: fmt.Sprintf(`
+%s
+
+Adds a version of a custom Helm chart to the project, so that applications can be deployed from
+the chart instead of a Porter template. The path is either a chart directory or a chart packaged
+with "helm package". For example:
+
+  %s
+
+The name and version of the chart are read from its Chart.yaml. Each version can only be pushed
+once, so the version must be bumped to push changes to the chart.
+
+The deploy webhook of an application sets the image repository and tag under the "image" key of
+the values, like Porter templates. If the chart reads the image from another key, use the
+--image-values-key flag:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter chart push\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter chart push ./charts/example-app"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter chart push ./charts/example-app --image-values-key app.image"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, pushChart)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var chartListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "Lists the versions of the custom charts of a project.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, listCharts)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var chartDeployCmd = &cobra.Command{
+	Use:   "deploy [chart]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Creates an application from a custom chart.",
+	Long: fmt.Sprintf(`
+%s
+
+Creates an application from a custom chart of the project, with the values of an optional values
+file. The image is set under the image values key of the chart. For example:
+
+  %s
+
+The latest version of the chart is deployed, unless the --version flag is set. Like applications
+that are created from Porter templates, new images can be deployed through the deploy webhook of
+the application, and the env groups that are synced to it are loaded into all of its containers.
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter chart deploy\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter chart deploy example-app --app example-app --image gcr.io/snowflake-12345/example-app:latest --values values.yaml"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, deployChart)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var imageValuesKey string
+var chartVersion string
+
+func init() {
+	rootCmd.AddCommand(chartCmd)
+	chartCmd.AddCommand(chartPushCmd)
+	chartCmd.AddCommand(chartListCmd)
+	chartCmd.AddCommand(chartDeployCmd)
+
+	chartPushCmd.Flags().StringVar(
+		&imageValuesKey,
+		"image-values-key",
+		helm.DefaultImageValuesKey,
+		"dot-separated key of the image repository and tag in the values of the chart",
+	)
+
+	chartDeployCmd.Flags().StringVar(
+		&name,
+		"app",
+		"",
+		"name of the application to create",
+	)
+
+	chartDeployCmd.MarkFlagRequired("app")
+
+	chartDeployCmd.Flags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"namespace to create the application in",
+	)
+
+	chartDeployCmd.Flags().StringVar(
+		&chartVersion,
+		"version",
+		"",
+		"version of the chart, which defaults to the latest version",
+	)
+
+	chartDeployCmd.Flags().StringVar(
+		&image,
+		"image",
+		"",
+		"the image to deploy, in repository:tag format",
+	)
+
+	chartDeployCmd.MarkFlagRequired("image")
+
+	chartDeployCmd.Flags().StringVarP(
+		&values,
+		"values",
+		"v",
+		"",
+		"filepath to a values.yaml file",
+	)
+}
+
+func pushChart(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	// charts are loaded and packaged again, so that chart directories can be pushed
+	ch, err := loader.Load(args[0])
+
+	if err != nil {
+		return fmt.Errorf("could not load chart: %w", err)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "porter-chart")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(tmpDir)
+
+	archivePath, err := chartutil.Save(ch, tmpDir)
+
+	if err != nil {
+		return fmt.Errorf("could not package chart: %w", err)
+	}
+
+	archive, err := ioutil.ReadFile(archivePath)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.CreateCustomChart(context.Background(), config.Project, &types.CreateCustomChartRequest{
+		Archive:        archive,
+		ImageValuesKey: imageValuesKey,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("Pushed version %s of chart %s\n", resp.Version, resp.Name)
+
+	return nil
+}
+
+func listCharts(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	resp, err := client.ListCustomCharts(context.Background(), config.Project)
+
+	if err != nil {
+		return err
+	}
+
+	customCharts := *resp
+
+	if ok, err := printStructuredOutput(customCharts); ok {
+		return err
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", "ID", "NAME", "VERSION", "SOURCE", "CREATED")
+
+	for _, customChart := range customCharts {
+		fmt.Fprintf(
+			w, "%d\t%s\t%s\t%s\t%s\n",
+			customChart.ID,
+			customChart.Name,
+			customChart.Version,
+			customChart.Source,
+			customChart.CreatedAt.Format("2006-01-02"),
+		)
+	}
+
+	w.Flush()
+
+	return nil
+}
+
+func deployChart(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	resp, err := client.ListCustomCharts(context.Background(), config.Project)
+
+	if err != nil {
+		return err
+	}
+
+	var customChart *types.CustomChart
+
+	// the versions of each custom chart are listed from the most recently pushed version
+	for _, curr := range *resp {
+		if curr.Name == args[0] && (chartVersion == "" || curr.Version == chartVersion) {
+			customChart = curr
+			break
+		}
+	}
+
+	if customChart == nil {
+		return fmt.Errorf("chart %s not found, push it with \"porter chart push\"", args[0])
+	}
+
+	valuesObj, err := readValuesFile()
+
+	if err != nil {
+		return err
+	}
+
+	imageSpl := strings.Split(image, ":")
+
+	if len(imageSpl) != 2 {
+		return fmt.Errorf("invalid image: must be in repository:tag format")
+	}
+
+	helm.SetImageValues(valuesObj, customChart.ImageValuesKey, imageSpl[0], imageSpl[1])
+
+	err = client.DeployTemplate(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		namespace,
+		&types.CreateReleaseRequest{
+			CreateReleaseBaseRequest: &types.CreateReleaseBaseRequest{
+				RepoURL:         types.CustomChartRepoURL,
+				TemplateName:    customChart.Name,
+				TemplateVersion: customChart.Version,
+				Values:          valuesObj,
+				Name:            name,
+			},
+			ImageURL: imageSpl[0],
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("Deployed %s from version %s of chart %s\n", name, customChart.Version, customChart.Name)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/spf13/cobra"
+)
+
+var cloneNamespaceCmd = &cobra.Command{
+	Use:   "clone-namespace",
+	Short: "Clones the applications and env groups of a namespace to another namespace.",
+	Long: fmt.Sprintf(`
+%s
+
+Clones every application and env group in a namespace to a target namespace, which may be
+in a different cluster of the current project. This can be used to promote a staging
+environment to production. For example:
+
+  %s
+
+Image tags are preserved by default. The tag of an application can be remapped with the
+--tag flag, which can be passed multiple times:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter clone-namespace\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter clone-namespace --namespace staging --target-namespace production"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter clone-namespace --namespace staging --target-namespace production --tag web=v1.2.0"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, cloneNamespace)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var cloneTargetNamespace string
+var cloneTargetCluster uint
+var cloneImageTags []string
+
+func init() {
+	rootCmd.AddCommand(cloneNamespaceCmd)
+
+	cloneNamespaceCmd.PersistentFlags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"namespace to clone",
+	)
+
+	cloneNamespaceCmd.PersistentFlags().StringVar(
+		&cloneTargetNamespace,
+		"target-namespace",
+		"",
+		"namespace to clone into",
+	)
+
+	cloneNamespaceCmd.MarkPersistentFlagRequired("target-namespace")
+
+	cloneNamespaceCmd.PersistentFlags().UintVar(
+		&cloneTargetCluster,
+		"target-cluster",
+		0,
+		"id of the cluster to clone into (defaults to the current cluster)",
+	)
+
+	cloneNamespaceCmd.PersistentFlags().StringArrayVar(
+		&cloneImageTags,
+		"tag",
+		[]string{},
+		"image tag to deploy for an application, in the form app=tag",
+	)
+}
+
+func cloneNamespace(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	req := &types.CloneNamespaceRequest{
+		TargetNamespace: cloneTargetNamespace,
+		TargetClusterID: cloneTargetCluster,
+		ImageTags:       make(map[string]string),
+	}
+
+	for _, tag := range cloneImageTags {
+		spl := strings.SplitN(tag, "=", 2)
+
+		if len(spl) != 2 || spl[0] == "" || spl[1] == "" {
+			return fmt.Errorf("invalid tag %s: must be in the form app=tag", tag)
+		}
+
+		req.ImageTags[spl[0]] = spl[1]
+	}
+
+	resp, err := client.CloneNamespace(context.Background(), config.Project, config.Cluster, namespace, req)
+
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf(
+		"Cloned %d applications and %d env groups to namespace %s\n",
+		len(resp.Releases),
+		len(resp.EnvGroups),
+		cloneTargetNamespace,
+	)
+
+	return nil
+}
@@ -85,6 +85,10 @@ func listClusters(user *types.GetAuthenticatedUserResponse, client *api.Client,
 
 	clusters := *resp
 
+	if ok, err := printStructuredOutput(clusters); ok {
+		return err
+	}
+
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
 
@@ -154,6 +158,10 @@ func listNamespaces(user *types.GetAuthenticatedUserResponse, client *api.Client
 		return err
 	}
 
+	if ok, err := printStructuredOutput(namespaces.Items); ok {
+		return err
+	}
+
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
 
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:       "completion [bash|zsh|fish]",
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Short:     "Generates a shell completion script.",
+	Long: fmt.Sprintf(`
+%s
+
+Generates a completion script for the given shell. Besides commands and flags, the completions
+include the applications, namespaces, clusters, projects and registries of the current project,
+which are read from the Porter API. To load the completions in the current bash session:
+
+  %s
+
+To load the completions for every zsh session, add the script to a directory in your $fpath:
+
+  %s
+
+To load the completions for every fish session:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter completion\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("source <(porter completion bash)"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter completion zsh > \"${fpath[1]}/_porter\""),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter completion fish > ~/.config/fish/completions/porter.fish"),
+	),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		}
+
+		if err != nil {
+			color.New(color.FgRed).Printf("An error occurred: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	logsCmd.ValidArgsFunction = completeFirstArg(completeReleases)
+	runCmd.ValidArgsFunction = completeFirstArg(completeReleases)
+	devCmd.ValidArgsFunction = completeFirstArg(completeReleases)
+
+	configSetProjectCmd.ValidArgsFunction = completeFirstArg(completeProjects)
+	deleteProjectCmd.ValidArgsFunction = completeFirstArg(completeProjects)
+
+	configSetClusterCmd.ValidArgsFunction = completeFirstArg(completeClusters)
+	clusterDeleteCmd.ValidArgsFunction = completeFirstArg(completeClusters)
+
+	configSetRegistryCmd.ValidArgsFunction = completeFirstArg(completeRegistries)
+	registryDeleteCmd.ValidArgsFunction = completeFirstArg(completeRegistries)
+	registryImageListCmd.ValidArgsFunction = completeFirstArg(completeRepos)
+}
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// flagCompletions are the completions of flags that are shared by multiple commands
+var flagCompletions = map[string]completionFunc{
+	"app":       completeReleases,
+	"namespace": completeNamespaces,
+	"project":   completeProjects,
+	"cluster":   completeClusters,
+	"registry":  completeRegistries,
+}
+
+// registerFlagCompletions registers the completions of the shared flags on a command and
+// its subcommands. It is called once all commands and flags have been added.
+func registerFlagCompletions(cmd *cobra.Command) {
+	for name, fn := range flagCompletions {
+		if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+			// persistent flags are shared with subcommands, which leads to an error for
+			// flags that were already registered on the parent command
+			cmd.RegisterFlagCompletionFunc(name, fn)
+		}
+	}
+
+	for _, subCmd := range cmd.Commands() {
+		registerFlagCompletions(subCmd)
+	}
+}
+
+// completeFirstArg only completes the first argument of a command
+func completeFirstArg(fn completionFunc) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return fn(cmd, args, toComplete)
+	}
+}
+
+func completeReleases(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	releaseNamespace := "default"
+
+	if flag := cmd.Flags().Lookup("namespace"); flag != nil && flag.Value.String() != "" {
+		releaseNamespace = flag.Value.String()
+	}
+
+	releases, err := GetAPIClient(config).ListReleases(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		releaseNamespace,
+		&types.ListReleasesRequest{
+			ReleaseListFilter: &types.ReleaseListFilter{
+				StatusFilter: []string{
+					"deployed",
+					"failed",
+					"pending-install",
+					"pending-upgrade",
+					"pending-rollback",
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	res := make([]string, 0, len(releases))
+
+	for _, rel := range releases {
+		res = append(res, fmt.Sprintf("%s\t%s", rel.Name, rel.Chart.Name()))
+	}
+
+	return res, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeNamespaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	namespaces, err := GetAPIClient(config).GetK8sNamespaces(context.Background(), config.Project, config.Cluster)
+
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	res := make([]string, 0, len(namespaces.Items))
+
+	for _, namespace := range namespaces.Items {
+		res = append(res, namespace.Name)
+	}
+
+	return res, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	resp, err := GetAPIClient(config).ListUserProjects(context.Background())
+
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	res := make([]string, 0, len(*resp))
+
+	for _, project := range *resp {
+		res = append(res, fmt.Sprintf("%d\t%s", project.ID, project.Name))
+	}
+
+	return res, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeClusters(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	resp, err := GetAPIClient(config).ListProjectClusters(context.Background(), config.Project)
+
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	res := make([]string, 0, len(*resp))
+
+	for _, cluster := range *resp {
+		res = append(res, fmt.Sprintf("%d\t%s", cluster.ID, cluster.Name))
+	}
+
+	return res, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeRegistries(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	resp, err := GetAPIClient(config).ListRegistries(context.Background(), config.Project)
+
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	res := make([]string, 0, len(*resp))
+
+	for _, registry := range *resp {
+		res = append(res, fmt.Sprintf("%d\t%s", registry.ID, registry.URL))
+	}
+
+	return res, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeRepos(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	resp, err := GetAPIClient(config).ListRegistryRepositories(context.Background(), config.Project, config.Registry)
+
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	res := make([]string, 0, len(*resp))
+
+	for _, repo := range *resp {
+		res = append(res, repo.Name)
+	}
+
+	return res, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,731 @@
+package compose
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Options configure how a Compose file is translated
+type Options struct {
+	// Prefix is prepended to the names of the releases and env groups
+	Prefix string
+
+	// LookupEnv reads the variables that are set without a value in the environment of a
+	// service, which Compose reads from the shell. If nil, these variables are skipped.
+	LookupEnv func(key string) (string, bool)
+}
+
+// Build is the build context of a service that is built from source
+type Build struct {
+	// Context is the slash-separated path to the build context, relative to the
+	// directory of the Compose file
+	Context string
+
+	// Dockerfile is the slash-separated path to the Dockerfile, relative to the context
+	Dockerfile string
+
+	// Args are the build arguments of the image
+	Args map[string]string
+}
+
+// Release is a Porter release that is created for a service
+type Release struct {
+	Service string
+	Name    string
+
+	// Kind is the template of the release, which is "web" for services with ports
+	// and "worker" otherwise
+	Kind string
+
+	// Image is the image of the release in repository:tag format, if it is not built
+	Image string
+	Build *Build
+
+	// Internal is set for web releases that are only exposed inside the cluster
+	Internal bool
+
+	// EnvGroup is the name of the env group that is synced to the release, if the
+	// service has environment variables
+	EnvGroup string
+
+	Values map[string]interface{}
+
+	// DependsOn are the names of the releases that the release depends on
+	DependsOn []string
+}
+
+// EnvGroup is an env group that is created for the environment of a service
+type EnvGroup struct {
+	Name      string
+	Variables map[string]string
+}
+
+// Plan is the translation of a Compose file
+type Plan struct {
+	// Releases are sorted so that the dependencies of a release come before it
+	Releases  []*Release
+	EnvGroups []*EnvGroup
+
+	// Unsupported are the fields of the Compose file that could not be translated, in
+	// the form "field: reason", sorted by field
+	Unsupported []string
+}
+
+var supportedTopLevelKeys = map[string]bool{
+	"version":  true,
+	"name":     true,
+	"services": true,
+	"volumes":  true,
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Translate translates the services of a Compose file into Porter releases and env groups
+func Translate(data []byte, opts *Options) (*Plan, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	file := make(map[string]interface{})
+
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("could not parse compose file: %w", err)
+	}
+
+	services, ok := file["services"].(map[string]interface{})
+
+	if !ok || len(services) == 0 {
+		return nil, fmt.Errorf("compose file has no services")
+	}
+
+	plan := &Plan{
+		Releases:    make([]*Release, 0),
+		EnvGroups:   make([]*EnvGroup, 0),
+		Unsupported: make([]string, 0),
+	}
+
+	for key := range file {
+		if !supportedTopLevelKeys[key] && !strings.HasPrefix(key, "x-") {
+			plan.unsupported(key, "not supported")
+		}
+	}
+
+	releases := make(map[string]*Release)
+	names := make(map[string]string)
+
+	for _, serviceName := range sortedKeys(services) {
+		service, ok := services[serviceName].(map[string]interface{})
+
+		if !ok {
+			return nil, fmt.Errorf("service %s is not a mapping", serviceName)
+		}
+
+		rel, err := plan.translateService(serviceName, service, opts)
+
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", serviceName, err)
+		}
+
+		if other, exists := names[rel.Name]; exists {
+			return nil, fmt.Errorf("services %s and %s both translate to the release name %s", other, serviceName, rel.Name)
+		}
+
+		names[rel.Name] = serviceName
+		releases[serviceName] = rel
+	}
+
+	sorted, err := sortReleases(releases)
+
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Releases = sorted
+
+	sort.Strings(plan.Unsupported)
+
+	return plan, nil
+}
+
+func (p *Plan) unsupported(field, reason string) {
+	p.Unsupported = append(p.Unsupported, fmt.Sprintf("%s: %s", field, reason))
+}
+
+func (p *Plan) translateService(serviceName string, service map[string]interface{}, opts *Options) (*Release, error) {
+	name := ReleaseName(opts.Prefix, serviceName)
+
+	if name == "" {
+		return nil, fmt.Errorf("could not derive a release name")
+	}
+
+	rel := &Release{
+		Service:   serviceName,
+		Name:      name,
+		Kind:      "worker",
+		Values:    make(map[string]interface{}),
+		DependsOn: make([]string, 0),
+	}
+
+	container := make(map[string]interface{})
+
+	for _, key := range sortedKeys(service) {
+		val := service[key]
+		field := serviceName + "." + key
+
+		switch key {
+		case "image":
+			image, ok := val.(string)
+
+			if !ok {
+				return nil, fmt.Errorf("image must be a string")
+			}
+
+			if strings.Contains(image, "@") {
+				return nil, fmt.Errorf("image digests are not supported, use a tag instead")
+			}
+
+			rel.Image = imageWithTag(image)
+		case "build":
+			build, err := p.parseBuild(field, val)
+
+			if err != nil {
+				return nil, err
+			}
+
+			rel.Build = build
+		case "ports", "expose":
+			// handled below, as ports take precedence over exposed ports
+		case "environment":
+			vars, err := p.parseEnvironment(field, val, opts.LookupEnv)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if len(vars) > 0 {
+				rel.EnvGroup = name + "-env"
+
+				p.EnvGroups = append(p.EnvGroups, &EnvGroup{
+					Name:      rel.EnvGroup,
+					Variables: vars,
+				})
+			}
+		case "volumes":
+			if mountPath := p.parseVolumes(field, val); mountPath != "" {
+				rel.Values["pvc"] = map[string]interface{}{
+					"enabled":   true,
+					"mountPath": mountPath,
+				}
+			}
+		case "depends_on":
+			deps, err := p.parseDependsOn(field, val)
+
+			if err != nil {
+				return nil, err
+			}
+
+			rel.DependsOn = deps
+		case "command":
+			command, err := parseCommand(val)
+
+			if err != nil {
+				return nil, err
+			}
+
+			container["command"] = command
+		default:
+			if !strings.HasPrefix(key, "x-") {
+				p.unsupported(field, "not supported")
+			}
+		}
+	}
+
+	if rel.Image == "" && rel.Build == nil {
+		return nil, fmt.Errorf("either an image or a build context must be set")
+	}
+
+	// the image of a service with a build context is the tag of the built image
+	if rel.Build != nil {
+		rel.Image = ""
+	}
+
+	port, err := p.parsePorts(serviceName, service)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if port != 0 {
+		rel.Kind = "web"
+		container["port"] = port
+
+		// services that only expose ports to other services are not exposed outside of
+		// the cluster
+		_, hasPorts := service["ports"]
+		rel.Internal = !hasPorts
+	}
+
+	if len(container) > 0 {
+		rel.Values["container"] = container
+	}
+
+	return rel, nil
+}
+
+func (p *Plan) parseBuild(field string, val interface{}) (*Build, error) {
+	build := &Build{
+		Dockerfile: "Dockerfile",
+		Args:       make(map[string]string),
+	}
+
+	switch v := val.(type) {
+	case string:
+		build.Context = v
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			switch key {
+			case "context":
+				build.Context, _ = v[key].(string)
+			case "dockerfile":
+				if dockerfile, ok := v[key].(string); ok && dockerfile != "" {
+					build.Dockerfile = dockerfile
+				}
+			case "args":
+				args, err := parseKeyValues(v[key])
+
+				if err != nil {
+					return nil, fmt.Errorf("build args: %w", err)
+				}
+
+				for argKey, argVal := range args {
+					if argVal != nil {
+						build.Args[argKey] = *argVal
+					} else {
+						p.unsupported(field+".args."+argKey, "build args without a value are not supported")
+					}
+				}
+			default:
+				p.unsupported(field+"."+key, "not supported")
+			}
+		}
+	default:
+		return nil, fmt.Errorf("build must be a string or a mapping")
+	}
+
+	if build.Context == "" {
+		build.Context = "."
+	}
+
+	if strings.Contains(build.Context, "://") || strings.HasPrefix(build.Context, "git@") {
+		return nil, fmt.Errorf("remote build contexts are not supported")
+	}
+
+	return build, nil
+}
+
+func (p *Plan) parseEnvironment(
+	field string,
+	val interface{},
+	lookupEnv func(key string) (string, bool),
+) (map[string]string, error) {
+	env, err := parseKeyValues(val)
+
+	if err != nil {
+		return nil, fmt.Errorf("environment: %w", err)
+	}
+
+	res := make(map[string]string)
+
+	for key, envVal := range env {
+		if envVal != nil {
+			res[key] = *envVal
+			continue
+		}
+
+		// variables without a value are read from the environment of the shell
+		if lookupEnv != nil {
+			if shellVal, ok := lookupEnv(key); ok {
+				res[key] = shellVal
+				continue
+			}
+		}
+
+		p.unsupported(field+"."+key, "the variable has no value and is not set in the environment")
+	}
+
+	return res, nil
+}
+
+// parseVolumes returns the mount path of the first volume of a service. Bind mounts and
+// the volumes after the first are reported, as releases can only mount a single volume.
+func (p *Plan) parseVolumes(field string, val interface{}) string {
+	volumes, ok := val.([]interface{})
+
+	if !ok {
+		p.unsupported(field, "volumes must be a list")
+		return ""
+	}
+
+	mountPath := ""
+
+	for i, volume := range volumes {
+		volumeField := fmt.Sprintf("%s[%d]", field, i)
+		volumeType, source, target := parseVolume(volume)
+
+		switch {
+		case target == "":
+			p.unsupported(volumeField, "could not read the mount path of the volume")
+		case volumeType == "bind":
+			p.unsupported(volumeField, fmt.Sprintf("bind mount of %s is not supported, add the files to the image instead", source))
+		case volumeType != "volume":
+			p.unsupported(volumeField, fmt.Sprintf("%s volumes are not supported", volumeType))
+		case mountPath != "":
+			p.unsupported(volumeField, fmt.Sprintf("only one volume is supported, %s is not mounted", target))
+		default:
+			mountPath = target
+		}
+	}
+
+	return mountPath
+}
+
+func (p *Plan) parseDependsOn(field string, val interface{}) ([]string, error) {
+	res := make([]string, 0)
+
+	switch v := val.(type) {
+	case []interface{}:
+		for _, dep := range v {
+			depName, ok := dep.(string)
+
+			if !ok {
+				return nil, fmt.Errorf("depends_on must be a list of service names")
+			}
+
+			res = append(res, depName)
+		}
+	case map[string]interface{}:
+		for _, depName := range sortedKeys(v) {
+			res = append(res, depName)
+
+			if depConf, ok := v[depName].(map[string]interface{}); ok {
+				if condition, _ := depConf["condition"].(string); condition != "" && condition != "service_started" {
+					p.unsupported(
+						field+"."+depName+".condition",
+						"releases are created in dependency order, but do not wait for their dependencies to become healthy",
+					)
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("depends_on must be a list or a mapping")
+	}
+
+	return res, nil
+}
+
+// parsePorts returns the container port of a service, from its published ports or from its
+// exposed ports if it has no published ports. Releases expose a single port, so the other
+// ports are reported.
+func (p *Plan) parsePorts(serviceName string, service map[string]interface{}) (int, error) {
+	res := 0
+
+	for _, key := range []string{"ports", "expose"} {
+		val, exists := service[key]
+
+		if !exists {
+			continue
+		}
+
+		ports, ok := val.([]interface{})
+
+		if !ok {
+			return 0, fmt.Errorf("%s must be a list", key)
+		}
+
+		for i, port := range ports {
+			field := fmt.Sprintf("%s.%s[%d]", serviceName, key, i)
+			containerPort, protocol, err := parsePort(port)
+
+			if err != nil {
+				p.unsupported(field, err.Error())
+				continue
+			}
+
+			switch {
+			case protocol != "tcp":
+				p.unsupported(field, fmt.Sprintf("%s ports are not supported", protocol))
+			case res == 0:
+				res = containerPort
+			case containerPort != res:
+				p.unsupported(field, fmt.Sprintf("only one port is exposed per release, port %d is not exposed", containerPort))
+			}
+		}
+
+		if res != 0 {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// ReleaseName returns the name of the release of a service, which is a lowercase DNS label
+func ReleaseName(prefix, serviceName string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(prefix+serviceName), "-")
+
+	return strings.Trim(name, "-")
+}
+
+// parsePort parses a port in the short syntax ("8080:80/tcp") or in the long syntax, and
+// returns the container port and its protocol
+func parsePort(port interface{}) (int, string, error) {
+	protocol := "tcp"
+	target := ""
+
+	switch v := port.(type) {
+	case float64:
+		target = strconv.Itoa(int(v))
+	case string:
+		target = v
+
+		if i := strings.LastIndex(target, "/"); i != -1 {
+			protocol = target[i+1:]
+			target = target[:i]
+		}
+
+		// the container port is the last part of the published port
+		if i := strings.LastIndex(target, ":"); i != -1 {
+			target = target[i+1:]
+		}
+	case map[string]interface{}:
+		switch t := v["target"].(type) {
+		case float64:
+			target = strconv.Itoa(int(t))
+		case string:
+			target = t
+		}
+
+		if p, ok := v["protocol"].(string); ok && p != "" {
+			protocol = p
+		}
+	}
+
+	if strings.Contains(target, "-") {
+		return 0, "", fmt.Errorf("port ranges are not supported")
+	}
+
+	res, err := strconv.Atoi(target)
+
+	if err != nil || res <= 0 {
+		return 0, "", fmt.Errorf("could not read the container port")
+	}
+
+	return res, strings.ToLower(protocol), nil
+}
+
+// parseVolume parses a volume in the short syntax ("data:/var/lib/data:ro") or in the
+// long syntax, and returns the type, source and target of the volume
+func parseVolume(volume interface{}) (volumeType, source, target string) {
+	switch v := volume.(type) {
+	case string:
+		parts := strings.Split(v, ":")
+
+		// Windows paths start with a drive letter, which is joined with the path
+		if len(parts) > 2 && len(parts[0]) == 1 && strings.HasPrefix(parts[1], `\`) {
+			parts = append([]string{parts[0] + ":" + parts[1]}, parts[2:]...)
+		}
+
+		if len(parts) == 1 {
+			// anonymous volume
+			return "volume", "", parts[0]
+		}
+
+		source, target = parts[0], parts[1]
+	case map[string]interface{}:
+		volumeType, _ = v["type"].(string)
+		source, _ = v["source"].(string)
+		target, _ = v["target"].(string)
+
+		if volumeType != "" {
+			return volumeType, source, target
+		}
+	}
+
+	if isPath(source) {
+		return "bind", source, target
+	}
+
+	return "volume", source, target
+}
+
+func isPath(source string) bool {
+	return strings.HasPrefix(source, ".") || strings.HasPrefix(source, "/") ||
+		strings.HasPrefix(source, "~") || strings.Contains(source, `\`)
+}
+
+// parseKeyValues parses a list of "KEY=VALUE" strings or a mapping. Keys without a value
+// are returned with a nil value.
+func parseKeyValues(val interface{}) (map[string]*string, error) {
+	res := make(map[string]*string)
+
+	switch v := val.(type) {
+	case []interface{}:
+		for _, item := range v {
+			str, ok := item.(string)
+
+			if !ok {
+				return nil, fmt.Errorf("must be a list of KEY=VALUE strings")
+			}
+
+			if kv := strings.SplitN(str, "=", 2); len(kv) == 2 {
+				res[kv[0]] = &kv[1]
+			} else {
+				res[str] = nil
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			if item == nil {
+				res[key] = nil
+				continue
+			}
+
+			str := scalarString(item)
+			res[key] = &str
+		}
+	case nil:
+	default:
+		return nil, fmt.Errorf("must be a list or a mapping")
+	}
+
+	return res, nil
+}
+
+func parseCommand(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case []interface{}:
+		args := make([]string, 0, len(v))
+
+		for _, arg := range v {
+			args = append(args, quoteArg(scalarString(arg)))
+		}
+
+		return strings.Join(args, " "), nil
+	}
+
+	return "", fmt.Errorf("command must be a string or a list")
+}
+
+// quoteArg quotes an argument of a command in the exec form, so that it is read as a
+// single argument when the command is split by the shell
+func quoteArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`&|;<>()*?[]#~") {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+func scalarString(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	return fmt.Sprintf("%v", val)
+}
+
+// imageWithTag adds the latest tag to an image without a tag
+func imageWithTag(image string) string {
+	if strings.Contains(path.Base(image), ":") {
+		return image
+	}
+
+	return image + ":latest"
+}
+
+// sortReleases sorts releases so that the dependencies of a release come before it, and
+// otherwise by the name of their service
+func sortReleases(releases map[string]*Release) ([]*Release, error) {
+	res := make([]*Release, 0, len(releases))
+	visited := make(map[string]bool)
+	visiting := make(map[string]bool)
+
+	var visit func(serviceName string, from string) error
+
+	visit = func(serviceName string, from string) error {
+		rel, ok := releases[serviceName]
+
+		if !ok {
+			return fmt.Errorf("service %s depends on undefined service %s", from, serviceName)
+		}
+
+		if visited[serviceName] {
+			return nil
+		}
+
+		if visiting[serviceName] {
+			return fmt.Errorf("services %s and %s depend on each other", from, serviceName)
+		}
+
+		visiting[serviceName] = true
+
+		deps := rel.DependsOn
+		rel.DependsOn = make([]string, 0, len(deps))
+
+		for _, dep := range deps {
+			if err := visit(dep, serviceName); err != nil {
+				return err
+			}
+
+			rel.DependsOn = append(rel.DependsOn, releases[dep].Name)
+		}
+
+		visiting[serviceName] = false
+		visited[serviceName] = true
+
+		res = append(res, rel)
+
+		return nil
+	}
+
+	serviceNames := make([]string, 0, len(releases))
+
+	for serviceName := range releases {
+		serviceNames = append(serviceNames, serviceName)
+	}
+
+	sort.Strings(serviceNames)
+
+	for _, serviceName := range serviceNames {
+		if err := visit(serviceName, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	res := make([]string, 0, len(m))
+
+	for key := range m {
+		res = append(res, key)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
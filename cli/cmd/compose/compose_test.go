@@ -0,0 +1,197 @@
+package compose_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/porter-dev/porter/cli/cmd/compose"
+)
+
+const composeFile = `
+version: "3.9"
+services:
+  web_app:
+    build:
+      context: ./web
+      dockerfile: docker/Dockerfile
+      args:
+        - NODE_ENV=production
+    ports:
+      - "8080:3000"
+      - "9229:9229"
+    environment:
+      DATABASE_URL: postgres://db:5432/app
+      DEBUG: false
+      API_KEY:
+    volumes:
+      - ./web:/app
+    depends_on:
+      - db
+      - cache
+    healthcheck:
+      test: ["CMD", "curl", "localhost:3000"]
+  db:
+    image: postgres:13
+    expose:
+      - 5432
+    environment:
+      - POSTGRES_PASSWORD=secret
+    volumes:
+      - data:/var/lib/postgresql/data
+      - logs:/var/log
+  cache:
+    image: redis
+  queue:
+    image: example/worker
+    command: ["celery", "-A", "app worker"]
+    depends_on:
+      web_app:
+        condition: service_healthy
+volumes:
+  data:
+  logs:
+networks:
+  backend:
+`
+
+func TestTranslate(t *testing.T) {
+	env := map[string]string{"API_KEY": "key"}
+
+	plan, err := compose.Translate([]byte(composeFile), &compose.Options{
+		Prefix: "shop-",
+		LookupEnv: func(key string) (string, bool) {
+			val, ok := env[key]
+			return val, ok
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := make([]string, 0)
+
+	for _, rel := range plan.Releases {
+		names = append(names, rel.Name)
+	}
+
+	// dependencies are created before the releases that depend on them
+	if expected := []string{"shop-cache", "shop-db", "shop-web-app", "shop-queue"}; !reflect.DeepEqual(expected, names) {
+		t.Fatalf("incorrect release order: expected %v, got %v", expected, names)
+	}
+
+	cache, db, web, queue := plan.Releases[0], plan.Releases[1], plan.Releases[2], plan.Releases[3]
+
+	if cache.Kind != "worker" || cache.Image != "redis:latest" || cache.EnvGroup != "" {
+		t.Errorf("incorrect cache release: %+v", cache)
+	}
+
+	expectedDB := map[string]interface{}{
+		"pvc": map[string]interface{}{
+			"enabled":   true,
+			"mountPath": "/var/lib/postgresql/data",
+		},
+		"container": map[string]interface{}{
+			"port": 5432,
+		},
+	}
+
+	if db.Kind != "web" || !db.Internal || db.Image != "postgres:13" || !reflect.DeepEqual(expectedDB, db.Values) {
+		t.Errorf("incorrect db release: %+v", db)
+	}
+
+	expectedBuild := &compose.Build{
+		Context:    "./web",
+		Dockerfile: "docker/Dockerfile",
+		Args:       map[string]string{"NODE_ENV": "production"},
+	}
+
+	if web.Kind != "web" || web.Internal || web.Image != "" || !reflect.DeepEqual(expectedBuild, web.Build) {
+		t.Errorf("incorrect web release: %+v", web)
+	}
+
+	if expected := []string{"shop-db", "shop-cache"}; !reflect.DeepEqual(expected, web.DependsOn) {
+		t.Errorf("incorrect dependencies: expected %v, got %v", expected, web.DependsOn)
+	}
+
+	if expected := map[string]interface{}{"port": 3000}; !reflect.DeepEqual(expected, web.Values["container"]) {
+		t.Errorf("incorrect web container values: %v", web.Values["container"])
+	}
+
+	expectedCommand := map[string]interface{}{"command": "celery -A 'app worker'"}
+
+	if queue.Kind != "worker" || !reflect.DeepEqual(expectedCommand, queue.Values["container"]) {
+		t.Errorf("incorrect queue release: %+v", queue)
+	}
+
+	expectedEnvGroups := []*compose.EnvGroup{
+		{
+			Name:      "shop-db-env",
+			Variables: map[string]string{"POSTGRES_PASSWORD": "secret"},
+		},
+		{
+			Name: "shop-web-app-env",
+			Variables: map[string]string{
+				"DATABASE_URL": "postgres://db:5432/app",
+				"DEBUG":        "false",
+				"API_KEY":      "key",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(expectedEnvGroups, plan.EnvGroups) {
+		t.Errorf("incorrect env groups: %+v", plan.EnvGroups)
+	}
+
+	expectedUnsupported := []string{
+		"db.volumes[1]: only one volume is supported, /var/log is not mounted",
+		"networks: not supported",
+		"queue.depends_on.web_app.condition: releases are created in dependency order, but do not wait for their dependencies to become healthy",
+		"web_app.healthcheck: not supported",
+		"web_app.ports[1]: only one port is exposed per release, port 9229 is not exposed",
+		"web_app.volumes[0]: bind mount of ./web is not supported, add the files to the image instead",
+	}
+
+	if !reflect.DeepEqual(expectedUnsupported, plan.Unsupported) {
+		t.Errorf("incorrect unsupported fields:\nexpected %v\ngot %v", expectedUnsupported, plan.Unsupported)
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tests := map[string]string{
+		"no services": `version: "3"`,
+		"no image or build": `
+services:
+  web:
+    ports: ["80"]
+`,
+		"undefined dependency": `
+services:
+  web:
+    image: nginx
+    depends_on: [db]
+`,
+		"dependency cycle": `
+services:
+  a:
+    image: nginx
+    depends_on: [b]
+  b:
+    image: nginx
+    depends_on: [a]
+`,
+		"duplicate release names": `
+services:
+  web_app:
+    image: nginx
+  web-app:
+    image: nginx
+`,
+	}
+
+	for name, file := range tests {
+		if _, err := compose.Translate([]byte(file), nil); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
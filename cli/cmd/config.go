@@ -30,16 +30,16 @@ var config = &CLIConfig{}
 type CLIConfig struct {
 	// Driver can be either "docker" or "local", and represents which driver is
 	// used to run an instance of the server.
-	Driver string `yaml:"driver"`
+	Driver string `yaml:"driver" json:"driver"`
 
-	Host    string `yaml:"host"`
-	Project uint   `yaml:"project"`
-	Cluster uint   `yaml:"cluster"`
+	Host    string `yaml:"host" json:"host"`
+	Project uint   `yaml:"project" json:"project"`
+	Cluster uint   `yaml:"cluster" json:"cluster"`
 
-	Token string `yaml:"token"`
+	Token string `yaml:"token" json:"token"`
 
-	Registry uint `yaml:"registry"`
-	HelmRepo uint `yaml:"helm_repo"`
+	Registry uint `yaml:"registry" json:"registry"`
+	HelmRepo uint `yaml:"helm_repo" json:"helm_repo"`
 }
 
 // InitAndLoadConfig populates the config object with the following precedence rules:
@@ -385,6 +385,10 @@ func init() {
 }
 
 func printConfig() error {
+	if ok, err := printStructuredOutput(config); ok {
+		return err
+	}
+
 	config, err := ioutil.ReadFile(filepath.Join(home, ".porter", "porter.yaml"))
 
 	if err != nil {
@@ -1,17 +1,21 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/cli/cmd/deploy"
 	"github.com/porter-dev/porter/cli/cmd/gitutils"
+	"github.com/porter-dev/porter/internal/integrations/buildpacks"
+	"github.com/porter-dev/porter/internal/templater/utils"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
 )
@@ -55,6 +59,26 @@ To deploy an application from a Docker registry, use "--source registry" and pas
 --image flag. The image flag must be of the form repository:tag. For example:
 
   %s
+
+To expose a web application over a protocol other than HTTP, use the --expose flag, which can be one of
+http, grpc, tcp or udp. gRPC applications are exposed through the ingress of the cluster over HTTP/2. TCP
+and UDP applications are exposed through their own load balancer, or through the TCP and UDP proxy of the
+ingress controller with "--expose-mode proxy". For example:
+
+  %s
+
+To only expose the application to other applications in the cluster, use "--expose-mode internal". No
+ingress or subdomain is created, and the application is reachable at the cluster DNS name of its service,
+which is printed once the application is created. For example:
+
+  %s
+
+To create an application for each process type in the Procfile at the build path, use the "procfile"
+kind. The web process is created as a web application with the name given by --app, and all other
+processes as workers named {app}-{process}. The release process is not created as an application, but
+runs with the new image before each deploy of the web application. For example:
+
+  %s
 `,
 		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter create\":"),
 		color.New(color.FgGreen, color.Bold).Sprintf("porter create web --app example-app"),
@@ -62,6 +86,9 @@ To deploy an application from a Docker registry, use "--source registry" and pas
 		color.New(color.FgGreen, color.Bold).Sprintf("porter create web --app example-app --path ./path/to/app"),
 		color.New(color.FgGreen, color.Bold).Sprintf("porter create web --app example-app --source github"),
 		color.New(color.FgGreen, color.Bold).Sprintf("porter create web --app example-app --source registry --image gcr.io/snowflake-12345/example-app:latest"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter create web --app example-app --expose tcp --expose-mode proxy --proxy-port 5432"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter create web --app example-api --expose-mode internal"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter create procfile --app example-app --source github"),
 	),
 	Run: func(cmd *cobra.Command, args []string) {
 		err := checkLoginAndRun(args, createFull)
@@ -77,6 +104,9 @@ var values string
 var source string
 var image string
 var registryURL string
+var expose string
+var exposeMode string
+var proxyPort int
 
 func init() {
 	rootCmd.AddCommand(createCmd)
@@ -155,14 +185,49 @@ func init() {
 		"",
 		"the registry URL to use (must exist in \"porter registries list\")",
 	)
+
+	createCmd.PersistentFlags().StringVar(
+		&expose,
+		"expose",
+		"",
+		"for web applications, the protocol to expose the application with (\"http\", \"grpc\", \"tcp\", or \"udp\")",
+	)
+
+	createCmd.PersistentFlags().StringVar(
+		&exposeMode,
+		"expose-mode",
+		"",
+		"how to expose the application (\"loadbalancer\" or \"proxy\" for tcp and udp applications, or \"internal\")",
+	)
+
+	createCmd.PersistentFlags().IntVar(
+		&proxyPort,
+		"proxy-port",
+		0,
+		"the port of the ingress controller to proxy to the application, which is required if the expose mode is \"proxy\"",
+	)
+
+	createCmd.PersistentFlags().BoolVar(
+		&waitForRollout,
+		"wait",
+		false,
+		"wait for the rollout of the application to complete, and exit with a non-zero code if it fails",
+	)
+
+	createCmd.PersistentFlags().DurationVar(
+		&waitTimeout,
+		"wait-timeout",
+		5*time.Minute,
+		"the maximum time to wait for the rollout to complete, if --wait is set",
+	)
 }
 
 var supportedKinds = map[string]string{"web": "", "job": "", "worker": ""}
 
 func createFull(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
 	// check the kind
-	if _, exists := supportedKinds[args[0]]; !exists {
-		return fmt.Errorf("%s is not a supported type: specify web, job, or worker", args[0])
+	if _, exists := supportedKinds[args[0]]; !exists && args[0] != "procfile" {
+		return fmt.Errorf("%s is not a supported type: specify web, job, worker, or procfile", args[0])
 	}
 
 	var err error
@@ -173,14 +238,63 @@ func createFull(_ *types.GetAuthenticatedUserResponse, client *api.Client, args
 		return err
 	}
 
+	if args[0] == "procfile" {
+		return createFromProcfile(client, valuesObj)
+	}
+
 	color.New(color.FgGreen).Printf("Creating %s release: %s\n", args[0], name)
 
-	fullPath, err := filepath.Abs(localPath)
+	createAgent, err := newCreateAgent(client, args[0], name)
 
 	if err != nil {
 		return err
 	}
 
+	if source == "local" {
+		subdomain, createErr := createAgent.CreateFromDocker(valuesObj, "default", nil)
+
+		err = handleSubdomainCreate(subdomain, createErr)
+	} else if source == "github" {
+		err = createFromGithub(createAgent, valuesObj)
+	} else {
+		subdomain, createErr := createAgent.CreateFromRegistry(image, valuesObj)
+
+		err = handleSubdomainCreate(subdomain, createErr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := printInternalEndpoints(client, createAgent); err != nil {
+		return err
+	}
+
+	if waitForRollout {
+		return waitForReleaseRollout(client, namespace, name)
+	}
+
+	return nil
+}
+
+func newCreateAgent(client *api.Client, kind, releaseName string) (*deploy.CreateAgent, error) {
+	fullPath, err := filepath.Abs(localPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var exposure *types.ServiceExposure
+
+	// only web applications are exposed outside of the cluster
+	if kind == "web" {
+		exposure, err = getServiceExposure()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var buildMethod deploy.DeployBuildType
 
 	if method != "" {
@@ -198,7 +312,7 @@ func createFull(_ *types.GetAuthenticatedUserResponse, client *api.Client, args
 		}
 	}
 
-	createAgent := &deploy.CreateAgent{
+	return &deploy.CreateAgent{
 		Client: client,
 		CreateOpts: &deploy.CreateOpts{
 			SharedOpts: &deploy.SharedOpts{
@@ -210,23 +324,216 @@ func createFull(_ *types.GetAuthenticatedUserResponse, client *api.Client, args
 				Method:          buildMethod,
 				AdditionalEnv:   additionalEnv,
 			},
-			Kind:        args[0],
-			ReleaseName: name,
+			Kind:        kind,
+			ReleaseName: releaseName,
 			RegistryURL: registryURL,
+			Exposure:    exposure,
 		},
+	}, nil
+}
+
+// getServiceExposure returns the exposure that is set by the --expose flags, or nil if
+// the application is exposed over http through the ingress
+func getServiceExposure() (*types.ServiceExposure, error) {
+	exposure := &types.ServiceExposure{
+		Protocol:  types.ServiceProtocol(expose),
+		Mode:      types.ExposureMode(exposeMode),
+		ProxyPort: proxyPort,
 	}
 
-	if source == "local" {
-		subdomain, err := createAgent.CreateFromDocker(valuesObj, "default", nil)
+	if exposure.Protocol == "" {
+		exposure.Protocol = types.ServiceProtocolHTTP
+	}
 
-		return handleSubdomainCreate(subdomain, err)
-	} else if source == "github" {
-		return createFromGithub(createAgent, valuesObj)
+	switch exposure.Protocol {
+	case types.ServiceProtocolHTTP, types.ServiceProtocolGRPC:
+		if proxyPort != 0 || (exposure.Mode != "" && !exposure.IsInternal()) {
+			return nil, fmt.Errorf("%s applications only support the internal expose mode", exposure.Protocol)
+		}
+
+		if exposure.Protocol == types.ServiceProtocolHTTP && !exposure.IsInternal() {
+			return nil, nil
+		}
+	case types.ServiceProtocolTCP, types.ServiceProtocolUDP:
+		if exposure.Mode == "" {
+			exposure.Mode = types.ExposureModeLoadBalancer
+		}
+
+		if exposure.Mode != types.ExposureModeLoadBalancer && exposure.Mode != types.ExposureModeProxy &&
+			!exposure.IsInternal() {
+			return nil, fmt.Errorf("%s is not a supported expose mode: specify loadbalancer, proxy, or internal", exposeMode)
+		}
+
+		if proxyPort != 0 && exposure.Mode != types.ExposureModeProxy {
+			return nil, fmt.Errorf("--proxy-port can only be set if the expose mode is proxy")
+		} else if proxyPort == 0 && exposure.Mode == types.ExposureModeProxy {
+			return nil, fmt.Errorf("--proxy-port is required if the expose mode is proxy")
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a supported protocol: specify http, grpc, tcp, or udp", expose)
 	}
 
-	subdomain, err := createAgent.CreateFromRegistry(image, valuesObj)
+	return exposure, nil
+}
 
-	return handleSubdomainCreate(subdomain, err)
+// printInternalEndpoints prints the cluster DNS names of an application that is only
+// exposed inside the cluster
+func printInternalEndpoints(client *api.Client, createAgent *deploy.CreateAgent) error {
+	if createAgent.CreateOpts.Exposure == nil || !createAgent.CreateOpts.Exposure.IsInternal() {
+		return nil
+	}
+
+	release, err := client.GetRelease(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		namespace,
+		createAgent.CreateOpts.ReleaseName,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range release.InternalEndpoints {
+		color.New(color.FgGreen).Printf("Your application is reachable inside the cluster at: %s\n", endpoint)
+	}
+
+	return nil
+}
+
+// createFromProcfile creates a release for each process type in the Procfile at the build
+// path. For local builds, the image is built once for the first release and shared by the
+// releases of the other processes. The release process is set as the pre-deploy command
+// of the first release, so that it runs before each deploy of a new image.
+func createFromProcfile(client *api.Client, valuesObj map[string]interface{}) error {
+	fullPath, err := filepath.Abs(localPath)
+
+	if err != nil {
+		return err
+	}
+
+	procfile, err := ioutil.ReadFile(filepath.Join(fullPath, "Procfile"))
+
+	if err != nil {
+		return fmt.Errorf("could not read Procfile: %w", err)
+	}
+
+	processes := make(map[string]string)
+	releaseCommand := ""
+
+	for processType, command := range buildpacks.ParseProcfile(string(procfile)) {
+		if processType == buildpacks.ReleaseProcessType {
+			releaseCommand = command
+		} else {
+			processes[processType] = command
+		}
+	}
+
+	if len(processes) == 0 {
+		return fmt.Errorf("no process types found in Procfile")
+	}
+
+	releaseNames := make([]string, 0)
+	sharedImage := image
+
+	for _, processType := range buildpacks.SortProcessTypes(processes) {
+		kind := buildpacks.ProcessKind(processType)
+		releaseName := name
+
+		if processType != "web" {
+			releaseName = fmt.Sprintf("%s-%s", name, processType)
+		}
+
+		color.New(color.FgGreen).Printf("Creating %s release for process %s: %s\n", kind, processType, releaseName)
+
+		createAgent, err := newCreateAgent(client, kind, releaseName)
+
+		if err != nil {
+			return err
+		}
+
+		// the values of each release are copied from the values file, as they are modified
+		// when merged with the command of the process
+		processValues := utils.CoalesceValues(copyValues(valuesObj), map[string]interface{}{
+			"container": map[string]interface{}{
+				"command": processes[processType],
+			},
+		})
+
+		if source == "github" {
+			err = createFromGithub(createAgent, processValues)
+		} else if source == "local" && sharedImage == "" {
+			subdomain, createErr := createAgent.CreateFromDocker(processValues, "default", nil)
+
+			if err = handleSubdomainCreate(subdomain, createErr); err == nil {
+				_, imageURL, imageErr := createAgent.GetImageRepoURL(releaseName, namespace)
+
+				if imageErr != nil {
+					return imageErr
+				}
+
+				sharedImage = fmt.Sprintf("%s:default", imageURL)
+			}
+		} else {
+			subdomain, createErr := createAgent.CreateFromRegistry(sharedImage, processValues)
+
+			err = handleSubdomainCreate(subdomain, createErr)
+		}
+
+		if err == nil {
+			err = printInternalEndpoints(client, createAgent)
+		}
+
+		if err != nil {
+			return fmt.Errorf("error creating release for process %s: %w", processType, err)
+		}
+
+		if releaseCommand != "" && len(releaseNames) == 0 {
+			_, err := client.UpdatePreDeployCommand(
+				context.Background(),
+				config.Project,
+				config.Cluster,
+				namespace,
+				releaseName,
+				&types.UpdatePreDeployCommandRequest{
+					Command: releaseCommand,
+				},
+			)
+
+			if err != nil {
+				return fmt.Errorf("error setting the release process as the pre-deploy command of %s: %w", releaseName, err)
+			}
+
+			color.New(color.FgGreen).Printf("The release process runs before each deploy of %s: %s\n", releaseName, releaseCommand)
+		}
+
+		releaseNames = append(releaseNames, releaseName)
+	}
+
+	if waitForRollout {
+		for _, releaseName := range releaseNames {
+			if err := waitForReleaseRollout(client, namespace, releaseName); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+
+	for key, val := range values {
+		if valMap, ok := val.(map[string]interface{}); ok {
+			res[key] = copyValues(valMap)
+		} else {
+			res[key] = val
+		}
+	}
+
+	return res
 }
 
 func handleSubdomainCreate(subdomain string, err error) error {
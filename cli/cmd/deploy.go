@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
@@ -49,6 +50,17 @@ in your remote Git repository. For example, if a Dockerfile is found at ./docker
 specify it as follows:
 
   %s
+
+To wait for the new version of the application to roll out, for example to gate a CI pipeline on
+a successful deploy, use the --wait flag. The command exits with a non-zero code if the rollout fails
+or does not complete within the --wait-timeout:
+
+  %s
+
+To describe the changes of an update, pass a message via the --message flag. The message is shown
+in the deploy history of the application and in its deploy notifications:
+
+  %s
 `,
 		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter update\":"),
 		color.New(color.FgGreen, color.Bold).Sprintf("porter update --app example-app"),
@@ -56,6 +68,8 @@ specify it as follows:
 		color.New(color.FgGreen, color.Bold).Sprintf("porter update --app remote-git-app --source github"),
 		color.New(color.FgGreen, color.Bold).Sprintf("porter update --app example-app --values my-values.yaml"),
 		color.New(color.FgGreen, color.Bold).Sprintf("porter update --app example-app --method docker --dockerfile ./docker/prod.Dockerfile"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter update --app example-app --wait --wait-timeout 10m"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter update --app example-app --message \"Fix checkout timeouts\""),
 	),
 	Run: func(cmd *cobra.Command, args []string) {
 		err := checkLoginAndRun(args, updateFull)
@@ -206,6 +220,8 @@ var dockerfile string
 var method string
 var stream bool
 var buildFlagsEnv []string
+var deployMessage string
+var generateSBOM bool
 
 func init() {
 	buildFlagsEnv = []string{}
@@ -288,6 +304,35 @@ func init() {
 		"stream update logs to porter dashboard",
 	)
 
+	updateCmd.PersistentFlags().BoolVar(
+		&waitForRollout,
+		"wait",
+		false,
+		"wait for the rollout of the application to complete, and exit with a non-zero code if it fails",
+	)
+
+	updateCmd.PersistentFlags().DurationVar(
+		&waitTimeout,
+		"wait-timeout",
+		5*time.Minute,
+		"the maximum time to wait for the rollout to complete, if --wait is set",
+	)
+
+	updateCmd.PersistentFlags().StringVarP(
+		&deployMessage,
+		"message",
+		"m",
+		"",
+		"a message describing the changes of the update, which is shown in the deploy history and notifications",
+	)
+
+	updateCmd.PersistentFlags().BoolVar(
+		&generateSBOM,
+		"generate-sbom",
+		false,
+		"generate an SBOM with syft, which must be installed, for images built with docker or with buildpacks that write no SBOM. SBOMs that buildpacks write are always uploaded, and are read with pack.",
+	)
+
 	updateCmd.AddCommand(updateGetEnvCmd)
 
 	updateGetEnvCmd.PersistentFlags().StringVar(
@@ -329,6 +374,10 @@ func updateFull(_ *types.GetAuthenticatedUserResponse, client *api.Client, args
 		return err
 	}
 
+	if waitForRollout {
+		return waitForReleaseRollout(client, namespace, app)
+	}
+
 	return nil
 }
 
@@ -354,6 +403,12 @@ func updateGetEnv(_ *types.GetAuthenticatedUserResponse, client *api.Client, arg
 		return err
 	}
 
+	if getEnvFileDest == "" {
+		if ok, err := printStructuredOutput(buildEnv); ok {
+			return err
+		}
+	}
+
 	// write the environment variables to either a file or stdout (stdout by default)
 	return updateAgent.WriteBuildEnv(getEnvFileDest)
 }
@@ -385,11 +440,25 @@ func updateUpgrade(_ *types.GetAuthenticatedUserResponse, client *api.Client, ar
 		return err
 	}
 
-	return updateUpgradeWithAgent(updateAgent)
+	if err := updateUpgradeWithAgent(updateAgent); err != nil {
+		return err
+	}
+
+	if waitForRollout {
+		return waitForReleaseRollout(client, namespace, app)
+	}
+
+	return nil
 }
 
 // HELPER METHODS
 func updateGetAgent(client *api.Client) (*deploy.DeployAgent, error) {
+	return getUpdateAgent(client, app, source != "github")
+}
+
+// getUpdateAgent returns the update agent of the given application, which is built
+// from the local filesystem if local is set, and from its remote Git repository otherwise
+func getUpdateAgent(client *api.Client, appName string, local bool) (*deploy.DeployAgent, error) {
 	var buildMethod deploy.DeployBuildType
 
 	if method != "" {
@@ -406,7 +475,7 @@ func updateGetAgent(client *api.Client) (*deploy.DeployAgent, error) {
 	}
 
 	// initialize the update agent
-	return deploy.NewDeployAgent(client, app, &deploy.DeployOpts{
+	return deploy.NewDeployAgent(client, appName, &deploy.DeployOpts{
 		SharedOpts: &deploy.SharedOpts{
 			ProjectID:       config.Project,
 			ClusterID:       config.Cluster,
@@ -416,14 +485,44 @@ func updateGetAgent(client *api.Client) (*deploy.DeployAgent, error) {
 			OverrideTag:     tag,
 			Method:          buildMethod,
 			AdditionalEnv:   additionalEnv,
+			Message:         deployMessage,
 		},
-		Local: source != "github",
+		Local: local,
 	})
 }
 
 func updateBuildWithAgent(updateAgent *deploy.DeployAgent) error {
+	if !stream {
+		return runUpdateBuild(updateAgent)
+	}
+
+	// the output of streamed builds is uploaded, so that the logs of failed builds in
+	// Github workflows can be viewed in the dashboard
+	capture, err := deploy.StartBuildLogCapture()
+
+	if err != nil {
+		return runUpdateBuild(updateAgent)
+	}
+
+	buildErr := runUpdateBuild(updateAgent)
+	logs := capture.Stop()
+
+	var status types.EventStatus = types.EventStatusSuccess
+
+	if buildErr != nil {
+		status = types.EventStatusFailed
+	}
+
+	if err := updateAgent.UploadBuildLog(status, logs); err != nil {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Could not upload the build logs: %s\n", err.Error())
+	}
+
+	return buildErr
+}
+
+func runUpdateBuild(updateAgent *deploy.DeployAgent) error {
 	// build the deployment
-	color.New(color.FgGreen).Println("Building docker image for", app)
+	color.New(color.FgGreen).Println("Building docker image for", updateAgent.App)
 
 	if stream {
 		updateAgent.StreamEvent(types.SubEvent{
@@ -504,7 +603,7 @@ func updateBuildWithAgent(updateAgent *deploy.DeployAgent) error {
 
 func updatePushWithAgent(updateAgent *deploy.DeployAgent) error {
 	// push the deployment
-	color.New(color.FgGreen).Println("Pushing new image for", app)
+	color.New(color.FgGreen).Println("Pushing new image for", updateAgent.App)
 
 	if stream {
 		updateAgent.StreamEvent(types.SubEvent{
@@ -529,6 +628,10 @@ func updatePushWithAgent(updateAgent *deploy.DeployAgent) error {
 		return err
 	}
 
+	if err := updateAgent.UploadSBOM(generateSBOM); err != nil {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Could not upload the SBOM of the image: %s\n", err.Error())
+	}
+
 	if stream {
 		updateAgent.StreamEvent(types.SubEvent{
 			EventID: "push",
@@ -544,7 +647,7 @@ func updatePushWithAgent(updateAgent *deploy.DeployAgent) error {
 
 func updateUpgradeWithAgent(updateAgent *deploy.DeployAgent) error {
 	// push the deployment
-	color.New(color.FgGreen).Println("Upgrading configuration for", app)
+	color.New(color.FgGreen).Println("Upgrading configuration for", updateAgent.App)
 
 	if stream {
 		updateAgent.StreamEvent(types.SubEvent{
@@ -577,6 +680,12 @@ func updateUpgradeWithAgent(updateAgent *deploy.DeployAgent) error {
 		return err
 	}
 
+	// the upgrade waits for the pre-deploy command, and fails with its logs if the
+	// command fails
+	if cmd := updateAgent.PreDeployCommand(); cmd != "" {
+		color.New(color.FgGreen).Printf("Running pre-deploy command for %s: %s\n", updateAgent.App, cmd)
+	}
+
 	err = updateAgent.UpdateImageAndValues(valuesObj)
 
 	if err != nil {
@@ -602,7 +711,7 @@ func updateUpgradeWithAgent(updateAgent *deploy.DeployAgent) error {
 		})
 	}
 
-	color.New(color.FgGreen).Println("Successfully updated", app)
+	color.New(color.FgGreen).Println("Successfully updated", updateAgent.App)
 
 	return nil
 }
@@ -102,6 +102,10 @@ func (b *BuildAgent) BuildPack(dockerAgent *docker.Agent, dst, tag, prevTag stri
 // The return value will be relative if the dockerfile exists within the build context, absolute
 // otherwise. The second return value is true if the dockerfile exists within the build context,
 // false otherwise.
+//
+// The paths may use forward slashes on every platform, since the paths of git action configs
+// use forward slashes. The relative path to the dockerfile uses forward slashes, since it
+// is a path in the build context that is sent to the Docker daemon.
 func ResolveDockerPaths(
 	basePath string,
 	buildContextPath string,
@@ -112,25 +116,18 @@ func ResolveDockerPaths(
 	isDockerfileRelative bool,
 	err error,
 ) {
-	resBuildCtxPath, err = filepath.Abs(buildContextPath)
-	resDockerfilePath = dockerfilePath
-
-	// determine if the given dockerfile path is relative
-	if !filepath.IsAbs(dockerfilePath) {
-		// if path is relative, join basepath with path
-		resDockerfilePath = filepath.Join(basePath, dockerfilePath)
-	}
-
-	// compare the path to the dockerfile with the build context
-	pathComp, err := filepath.Rel(resBuildCtxPath, resDockerfilePath)
+	resBuildCtxPath, err = filepath.Abs(filepath.FromSlash(buildContextPath))
 
 	if err != nil {
 		return "", "", false, err
 	}
 
-	if !strings.HasPrefix(pathComp, ".."+string(os.PathSeparator)) {
-		// return the relative path to the dockerfile
-		return resBuildCtxPath, pathComp, true, nil
+	resDockerfilePath = filepath.FromSlash(dockerfilePath)
+
+	// determine if the given dockerfile path is relative
+	if !filepath.IsAbs(resDockerfilePath) {
+		// if path is relative, join basepath with path
+		resDockerfilePath = filepath.Join(basePath, resDockerfilePath)
 	}
 
 	resDockerfilePath, err = filepath.Abs(resDockerfilePath)
@@ -139,5 +136,15 @@ func ResolveDockerPaths(
 		return "", "", false, err
 	}
 
+	// compare the path to the dockerfile with the build context. On Windows, paths on
+	// different volumes have no relative path, and the dockerfile is outside of the
+	// build context.
+	pathComp, err := filepath.Rel(resBuildCtxPath, resDockerfilePath)
+
+	if err == nil && pathComp != ".." && !strings.HasPrefix(pathComp, ".."+string(os.PathSeparator)) {
+		// return the relative path to the dockerfile
+		return resBuildCtxPath, filepath.ToSlash(pathComp), true, nil
+	}
+
 	return resBuildCtxPath, resDockerfilePath, false, nil
 }
@@ -0,0 +1,115 @@
+//go:build integration
+// +build integration
+
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/cli/cmd/docker"
+)
+
+// TestBuildDockerPush builds an image from a build context with the local Docker daemon,
+// and pushes it to the registry at PORTER_TEST_REGISTRY, such as a registry:2 container
+// listening on localhost:5000. The Dockerfile is outside of the build context, so that
+// both the relative and the absolute Dockerfile paths are built.
+func TestBuildDockerPush(t *testing.T) {
+	registry := os.Getenv("PORTER_TEST_REGISTRY")
+
+	if registry == "" {
+		t.Skip("PORTER_TEST_REGISTRY is not set")
+	}
+
+	basePath := t.TempDir()
+
+	writeFile(t, filepath.Join(basePath, "app", "index.txt"), "hello")
+	writeFile(t, filepath.Join(basePath, "app", "Dockerfile"), "FROM busybox\nCOPY index.txt /index.txt\n")
+	writeFile(t, filepath.Join(basePath, "docker", "Dockerfile"), "FROM busybox\nCOPY index.txt /index.txt\n")
+
+	agent, err := docker.NewAgentFromEnv()
+
+	if err != nil {
+		t.Fatalf("could not create docker agent: %v", err)
+	}
+
+	imageRepo := fmt.Sprintf("%s/porter-cli-test", registry)
+
+	buildAgent := &BuildAgent{
+		SharedOpts: &SharedOpts{},
+		imageRepo:  imageRepo,
+		env: map[string]string{
+			"PORTER_TEST": "true",
+		},
+	}
+
+	for tag, dockerfilePath := range map[string]string{
+		"in-context":     "app/Dockerfile",
+		"out-of-context": "docker/Dockerfile",
+	} {
+		err := buildAgent.BuildDocker(agent, basePath, filepath.Join(basePath, "app"), dockerfilePath, tag, "")
+
+		if err != nil {
+			t.Fatalf("could not build image with dockerfile %s: %v", dockerfilePath, err)
+		}
+
+		if err := agent.PushImage(fmt.Sprintf("%s:%s", imageRepo, tag)); err != nil {
+			t.Fatalf("could not push image with tag %s: %v", tag, err)
+		}
+	}
+
+	tags := listRegistryTags(t, registry, "porter-cli-test")
+
+	for _, tag := range []string{"in-context", "out-of-context"} {
+		if !tags[tag] {
+			t.Errorf("expected tag %s to be pushed to the registry, got %v", tag, tags)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+}
+
+func listRegistryTags(t *testing.T, registry, name string) map[string]bool {
+	t.Helper()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	resp, err := client.Get(fmt.Sprintf("http://%s/v2/%s/tags/list", registry, name))
+
+	if err != nil {
+		t.Fatalf("could not list tags: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	res := &struct {
+		Tags []string `json:"tags"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		t.Fatalf("could not decode tags: %v", err)
+	}
+
+	tags := make(map[string]bool)
+
+	for _, tag := range res.Tags {
+		tags[tag] = true
+	}
+
+	return tags
+}
@@ -0,0 +1,114 @@
+package deploy
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// BuildLogCapture copies everything that is written to stdout and stderr into a
+// buffer, while still writing it to the original outputs. Only the end of the output
+// is kept, up to types.MaxBuildLogBytes.
+type BuildLogCapture struct {
+	stdout, stderr *os.File
+	writers        []*os.File
+	wg             sync.WaitGroup
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// StartBuildLogCapture redirects stdout and stderr until Stop is called
+func StartBuildLogCapture() (*BuildLogCapture, error) {
+	b := &BuildLogCapture{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+
+	stdoutWriter, err := b.tee(b.stdout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stderrWriter, err := b.tee(b.stderr)
+
+	if err != nil {
+		stdoutWriter.Close()
+		return nil, err
+	}
+
+	os.Stdout = stdoutWriter
+	os.Stderr = stderrWriter
+
+	return b, nil
+}
+
+// tee returns a pipe whose output is written to dst and to the buffer
+func (b *BuildLogCapture) tee(dst *os.File) (*os.File, error) {
+	r, w, err := os.Pipe()
+
+	if err != nil {
+		return nil, err
+	}
+
+	b.writers = append(b.writers, w)
+	b.wg.Add(1)
+
+	go func() {
+		defer b.wg.Done()
+		defer r.Close()
+
+		io.Copy(io.MultiWriter(dst, b), r)
+	}()
+
+	return w, nil
+}
+
+func (b *BuildLogCapture) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+
+	if len(b.buf) > types.MaxBuildLogBytes {
+		b.buf = b.buf[len(b.buf)-types.MaxBuildLogBytes:]
+	}
+
+	return len(p), nil
+}
+
+// Stop restores stdout and stderr, and returns the captured output
+func (b *BuildLogCapture) Stop() string {
+	os.Stdout = b.stdout
+	os.Stderr = b.stderr
+
+	for _, w := range b.writers {
+		w.Close()
+	}
+
+	b.wg.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return string(b.buf)
+}
+
+// UploadBuildLog stores the logs of a build of the release, keyed by the image tag,
+// which is the short commit SHA for builds from Github
+func (d *DeployAgent) UploadBuildLog(status types.EventStatus, logs string) error {
+	return d.client.CreateBuildLog(
+		context.Background(),
+		d.opts.ProjectID, d.opts.ClusterID,
+		d.release.Namespace, d.release.Name,
+		&types.CreateBuildLogRequest{
+			CommitSHA: d.tag,
+			Status:    status,
+			Logs:      logs,
+		},
+	)
+}
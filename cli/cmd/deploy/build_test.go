@@ -0,0 +1,94 @@
+package deploy
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolveDockerPaths(t *testing.T) {
+	basePath := t.TempDir()
+
+	tests := []struct {
+		name               string
+		buildCtx           string
+		dockerfilePath     string
+		expectedDockerfile string
+		expectedInCtx      bool
+	}{
+		{
+			name:               "dockerfile in build context",
+			buildCtx:           basePath,
+			dockerfilePath:     "./Dockerfile",
+			expectedDockerfile: "Dockerfile",
+			expectedInCtx:      true,
+		},
+		{
+			name:               "dockerfile in subdirectory with forward slashes",
+			buildCtx:           basePath,
+			dockerfilePath:     "docker/app/Dockerfile",
+			expectedDockerfile: "docker/app/Dockerfile",
+			expectedInCtx:      true,
+		},
+		{
+			name:               "dockerfile outside of build context",
+			buildCtx:           filepath.Join(basePath, "app"),
+			dockerfilePath:     "docker/Dockerfile",
+			expectedDockerfile: filepath.Join(basePath, "docker", "Dockerfile"),
+			expectedInCtx:      false,
+		},
+		{
+			name:               "dockerfile in directory with a name that starts with dots",
+			buildCtx:           basePath,
+			dockerfilePath:     "..docker/Dockerfile",
+			expectedDockerfile: "..docker/Dockerfile",
+			expectedInCtx:      true,
+		},
+	}
+
+	for _, test := range tests {
+		buildCtx, dockerfile, inCtx, err := ResolveDockerPaths(basePath, test.buildCtx, test.dockerfilePath)
+
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+
+		if !filepath.IsAbs(buildCtx) {
+			t.Errorf("%s: expected an absolute build context, got %s", test.name, buildCtx)
+		}
+
+		if dockerfile != test.expectedDockerfile {
+			t.Errorf("%s: expected dockerfile %s, got %s", test.name, test.expectedDockerfile, dockerfile)
+		}
+
+		if inCtx != test.expectedInCtx {
+			t.Errorf("%s: expected dockerfile in build context to be %t", test.name, test.expectedInCtx)
+		}
+	}
+}
+
+func TestGetBuildEnvLines(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"PORTER_WEB_PORT=8080",
+		"PORTER_WEB_URL=https://example.com?q=PORTER_WEB_X",
+		"porter_web_debug=true",
+		"PORTER_WEBHOOK=true",
+		"=C:=C:\\porter",
+	}
+
+	expected := []string{
+		"PORT=8080",
+		"URL=https://example.com?q=PORTER_WEB_X",
+	}
+
+	if lines := getBuildEnvLines(environ, "PORTER_WEB", false); !reflect.DeepEqual(expected, lines) {
+		t.Errorf("incorrect build env: expected %v, got %v", expected, lines)
+	}
+
+	expected = append(expected, "debug=true")
+
+	if lines := getBuildEnvLines(environ, "PORTER_WEB", true); !reflect.DeepEqual(expected, lines) {
+		t.Errorf("incorrect case-insensitive build env: expected %v, got %v", expected, lines)
+	}
+}
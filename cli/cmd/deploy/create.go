@@ -31,6 +31,9 @@ type CreateOpts struct {
 	// Suffix for the name of the image in the repository. By default the suffix is the
 	// target namespace.
 	RepoSuffix string
+
+	// Exposure sets how the application is exposed, or is nil for http applications
+	Exposure *types.ServiceExposure
 }
 
 // GithubOpts are the options for linking a Github source to the app
@@ -134,6 +137,7 @@ func (c *CreateAgent) CreateFromGithub(
 				Values:          mergedValues,
 				Name:            opts.ReleaseName,
 			},
+			Exposure: opts.Exposure,
 			ImageURL: imageURL,
 			GithubActionConfig: &types.CreateGitActionConfigRequest{
 				GitRepo:              ghOpts.Repo,
@@ -202,6 +206,7 @@ func (c *CreateAgent) CreateFromRegistry(
 				Values:          mergedValues,
 				Name:            opts.ReleaseName,
 			},
+			Exposure: opts.Exposure,
 			ImageURL: imageSpl[0],
 		},
 	)
@@ -275,7 +280,7 @@ func (c *CreateAgent) CreateFromDocker(
 	env, err := GetEnvForRelease(c.Client, mergedValues, opts.ProjectID, opts.ClusterID, opts.Namespace)
 
 	if err != nil {
-		env = map[string]string{}
+		return "", err
 	}
 
 	// add additional env based on options
@@ -345,6 +350,7 @@ func (c *CreateAgent) CreateFromDocker(
 				Values:          mergedValues,
 				Name:            opts.ReleaseName,
 			},
+			Exposure: opts.Exposure,
 			ImageURL: imageURL,
 		},
 	)
@@ -478,8 +484,30 @@ func (c *CreateAgent) getMergedValues(overrideValues map[string]interface{}) (st
 		return "", nil, err
 	}
 
+	// project and cluster defaults are merged beneath the overriding values, so that they
+	// take precedence over the defaults of the template
+	projDefaults, err := c.Client.GetProjectDefaultValues(context.Background(), c.CreateOpts.ProjectID)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	clusterDefaults, err := c.Client.GetClusterDefaultValues(
+		context.Background(),
+		c.CreateOpts.ProjectID,
+		c.CreateOpts.ClusterID,
+	)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	// null values are kept, so that they still unset the values beneath them when the
+	// server merges the defaults and when the values are coalesced with the chart
+	values = utils.MergeValues(values, utils.MergeValues(projDefaults.Values, clusterDefaults.Values))
+
 	// merge existing values with overriding values
-	mergedValues := utils.CoalesceValues(values, overrideValues)
+	mergedValues := utils.MergeValues(values, overrideValues)
 
 	return latestVersion, mergedValues, err
 }
@@ -487,8 +515,9 @@ func (c *CreateAgent) getMergedValues(overrideValues map[string]interface{}) (st
 func (c *CreateAgent) CreateSubdomainIfRequired(mergedValues map[string]interface{}) (string, error) {
 	subdomain := ""
 
-	// check for automatic subdomain creation if web kind
-	if c.CreateOpts.Kind == "web" {
+	// check for automatic subdomain creation if web kind. Applications that are not
+	// exposed through the ingress do not get a subdomain.
+	if c.CreateOpts.Kind == "web" && (c.CreateOpts.Exposure == nil || c.CreateOpts.Exposure.UsesIngress()) {
 		// look for ingress.enabled and no custom domains set
 		ingressMap, err := getNestedMap(mergedValues, "ingress")
 
@@ -529,6 +558,14 @@ func (c *CreateAgent) CreateSubdomainIfRequired(mergedValues map[string]interfac
 						ingressValMap["porter_hosts"] = []string{
 							subdomain,
 						}
+
+						// releases of preview environments with path-based routing are
+						// exposed by an ingress that the server generates, so the ingress
+						// of the chart is disabled. The host is still stored in the values,
+						// so that the URL of the release can be read from them.
+						if dnsRecord.PathPrefix != "" {
+							ingressValMap["enabled"] = false
+						}
 					}
 				}
 			}
@@ -7,8 +7,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/porter-dev/porter/api/client"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/cli/cmd/docker"
@@ -192,17 +194,10 @@ func (d *DeployAgent) SetBuildEnv(envVars map[string]string) error {
 
 // WriteBuildEnv writes the build env to either a file or stdout
 func (d *DeployAgent) WriteBuildEnv(fileDest string) error {
-	// join lines together
-	lines := make([]string, 0)
-
 	// use os.Environ to get output already formatted as KEY=value
-	for _, line := range os.Environ() {
-		// filter for PORTER_<RELEASE> and strip prefix
-		if strings.Contains(line, d.envPrefix+"_") {
-			lines = append(lines, strings.Split(line, d.envPrefix+"_")[1])
-		}
-	}
+	lines := getBuildEnvLines(os.Environ(), d.envPrefix, runtime.GOOS == "windows")
 
+	// join lines together
 	output := strings.Join(lines, "\n")
 
 	if fileDest != "" {
@@ -250,6 +245,10 @@ func (d *DeployAgent) Build(overrideBuildConfig *types.BuildConfig) error {
 			return err
 		}
 
+		// the folder path of the git action config is relative to the root of the
+		// repository, and uses forward slashes
+		buildCtx = filepath.Join(basePath, filepath.FromSlash(buildCtx))
+
 		if d.tag == "" {
 			shortRef := fmt.Sprintf("%.7s", zipResp.LatestCommitSHA)
 			d.tag = shortRef
@@ -310,6 +309,16 @@ func (d *DeployAgent) Build(overrideBuildConfig *types.BuildConfig) error {
 	return buildAgent.BuildPack(d.agent, buildCtx, d.tag, currTag, buildConfig)
 }
 
+// PreDeployCommand returns the command that is run with the new image of the release
+// before the release is upgraded, or an empty string if the release has none
+func (d *DeployAgent) PreDeployCommand() string {
+	if d.release.PorterRelease == nil {
+		return ""
+	}
+
+	return d.release.PreDeployCommand
+}
+
 // Push pushes a local image to the remote repository linked in the release
 func (d *DeployAgent) Push() error {
 	return d.agent.PushImage(fmt.Sprintf("%s:%s", d.imageRepo, d.tag))
@@ -332,8 +341,7 @@ func (d *DeployAgent) UpdateImageAndValues(overrideValues map[string]interface{}
 	currImageSection := mergedValues["image"].(map[string]interface{})
 
 	// if the current image section is hello-porter, the image must be overriden
-	if currImageSection["repository"] == "public.ecr.aws/o1j4x7p4/hello-porter" ||
-		currImageSection["repository"] == "public.ecr.aws/o1j4x7p4/hello-porter-job" {
+	if isDefaultImage(currImageSection["repository"]) {
 		newImage, err := d.getReleaseImage()
 
 		if err != nil {
@@ -364,7 +372,9 @@ func (d *DeployAgent) UpdateImageAndValues(overrideValues map[string]interface{}
 		d.release.Namespace,
 		d.release.Name,
 		&types.UpgradeReleaseRequest{
-			Values: string(bytes),
+			Values:    string(bytes),
+			CommitSHA: os.Getenv("GITHUB_SHA"),
+			Message:   d.opts.Message,
 		},
 	)
 }
@@ -381,7 +391,9 @@ type SyncedEnvSectionKey struct {
 }
 
 // GetEnvForRelease gets the env vars for a standard Porter template config. These env
-// vars are found at `container.env.normal`.
+// vars are found at `container.env.normal`, followed by the env groups synced to the
+// release. Synced env groups that do not exist are skipped with a warning. If some env
+// groups cannot be read, the env vars are returned along with an *EnvGroupsError.
 func GetEnvForRelease(client *client.Client, config map[string]interface{}, projID, clusterID uint, namespace string) (map[string]string, error) {
 	res := make(map[string]string)
 
@@ -503,15 +515,29 @@ func GetEnvForRelease(client *client.Client, config map[string]interface{}, proj
 			syncedArr = append(syncedArr, syncedArrObj)
 		}
 
+		names := make([]string, 0, len(syncedArr))
+
 		for _, syncedEG := range syncedArr {
-			// for each synced environment group, get the environment group from the client
-			eg, err := client.GetEnvGroup(context.Background(), projID, clusterID, namespace,
-				&types.GetEnvGroupRequest{
-					Name: syncedEG.Name,
-				},
+			names = append(names, syncedEG.Name)
+		}
+
+		// get all synced environment groups at once, and apply them in the order that
+		// they are synced. Env groups that were deleted since they were synced are
+		// skipped, so that the release can still be built and deployed.
+		envGroups, notFound, egErr := getEnvGroups(client, projID, clusterID, namespace, names)
+
+		for _, name := range notFound {
+			color.New(color.FgYellow).Fprintf(
+				os.Stderr,
+				"Env group %s is synced to the release but does not exist, so its variables are not set\n",
+				name,
 			)
+		}
+
+		for _, syncedEG := range syncedArr {
+			eg, ok := envGroups[syncedEG.Name]
 
-			if err != nil {
+			if !ok {
 				continue
 			}
 
@@ -521,6 +547,10 @@ func GetEnvForRelease(client *client.Client, config map[string]interface{}, proj
 				}
 			}
 		}
+
+		if egErr != nil {
+			return res, egErr
+		}
 	}
 
 	return res, nil
@@ -574,8 +604,7 @@ func (d *DeployAgent) pullCurrentReleaseImage() (string, error) {
 	}
 
 	// if image repo is a hello-porter image, skip
-	if d.imageRepo == "public.ecr.aws/o1j4x7p4/hello-porter" ||
-		d.imageRepo == "public.ecr.aws/o1j4x7p4/hello-porter-job" {
+	if isDefaultImage(d.imageRepo) {
 		return "", nil
 	}
 
@@ -664,3 +693,36 @@ func getNestedMap(obj map[string]interface{}, fields ...string) (map[string]inte
 
 	return res, nil
 }
+
+// isDefaultImage returns true if an image repository is a hello-porter image, by its public
+// name or by its name in an image mirror, which keeps the path of the public image
+func isDefaultImage(repository interface{}) bool {
+	repoStr, _ := repository.(string)
+
+	return strings.HasSuffix(repoStr, "/o1j4x7p4/hello-porter") ||
+		strings.HasSuffix(repoStr, "/o1j4x7p4/hello-porter-job")
+}
+
+// getBuildEnvLines returns the variables of an environment whose names start with the
+// prefix of the build env, as KEY=value lines without the prefix. Only the name of a
+// variable is matched, so values that contain the prefix are kept as they are. Names are
+// matched regardless of case on Windows, where environment variables are not case
+// sensitive.
+func getBuildEnvLines(environ []string, envPrefix string, ignoreCase bool) []string {
+	res := make([]string, 0)
+	prefix := envPrefix + "_"
+
+	for _, line := range environ {
+		name := strings.SplitN(line, "=", 2)[0]
+
+		if len(name) <= len(prefix) {
+			continue
+		}
+
+		if name[:len(prefix)] == prefix || (ignoreCase && strings.EqualFold(name[:len(prefix)], prefix)) {
+			res = append(res, line[len(prefix):])
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,182 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+)
+
+// envGroupCache caches the env groups that are read during a single CLI invocation, so
+// that env groups synced to several releases of a deploy are only read once
+var envGroupCache = struct {
+	mu     sync.Mutex
+	groups map[string]*types.EnvGroup
+}{
+	groups: make(map[string]*types.EnvGroup),
+}
+
+// EnvGroupsError is returned when some of the env groups synced to a release could not
+// be read. The env of the release is returned along with the error, without the
+// variables of the env groups that could not be read.
+type EnvGroupsError struct {
+	// Errors are the errors of the env groups that could not be read, keyed by name
+	Errors map[string]string
+}
+
+func (e *EnvGroupsError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	errs := make([]string, 0, len(names))
+
+	for _, name := range names {
+		errs = append(errs, fmt.Sprintf("%s: %s", name, e.Errors[name]))
+	}
+
+	return fmt.Sprintf("could not read env groups: %s", strings.Join(errs, "; "))
+}
+
+func getEnvGroupCacheKey(projID, clusterID uint, namespace, name string) string {
+	return fmt.Sprintf("%d/%d/%s/%s", projID, clusterID, namespace, name)
+}
+
+// getEnvGroups reads the latest versions of env groups with a single request, and
+// returns them keyed by name, along with the names of the env groups that do not exist.
+// Env groups that were already read during this invocation are served from the cache. If
+// the server does not support reading env groups in a batch, the env groups are read
+// concurrently instead.
+func getEnvGroups(
+	apiClient *client.Client,
+	projID, clusterID uint,
+	namespace string,
+	names []string,
+) (map[string]*types.EnvGroup, []string, error) {
+	res := make(map[string]*types.EnvGroup)
+	notFound := make([]string, 0)
+	missing := make([]string, 0)
+	seen := make(map[string]bool)
+
+	envGroupCache.mu.Lock()
+
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+
+		if eg, ok := envGroupCache.groups[getEnvGroupCacheKey(projID, clusterID, namespace, name)]; ok {
+			res[name] = eg
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	envGroupCache.mu.Unlock()
+
+	if len(missing) == 0 {
+		return res, notFound, nil
+	}
+
+	errs := make(map[string]string)
+
+	batchResp, err := apiClient.GetEnvGroupsBatch(context.Background(), projID, clusterID, namespace,
+		&types.GetEnvGroupsBatchRequest{
+			Names: missing,
+		},
+	)
+
+	if err == nil {
+		for _, eg := range batchResp.EnvGroups {
+			res[eg.Name] = eg
+		}
+
+		notFound = append(notFound, batchResp.NotFound...)
+
+		for name, egErr := range batchResp.Errors {
+			errs[name] = egErr
+		}
+	} else {
+		notFound = getEnvGroupsConcurrently(apiClient, projID, clusterID, namespace, missing, res, errs)
+	}
+
+	isNotFound := make(map[string]bool)
+
+	for _, name := range notFound {
+		isNotFound[name] = true
+	}
+
+	envGroupCache.mu.Lock()
+
+	for _, name := range missing {
+		if eg, ok := res[name]; ok {
+			envGroupCache.groups[getEnvGroupCacheKey(projID, clusterID, namespace, name)] = eg
+		} else if _, ok := errs[name]; !ok && !isNotFound[name] {
+			errs[name] = "env group was not returned by the server"
+		}
+	}
+
+	envGroupCache.mu.Unlock()
+
+	if len(errs) > 0 {
+		return res, notFound, &EnvGroupsError{Errors: errs}
+	}
+
+	return res, notFound, nil
+}
+
+func getEnvGroupsConcurrently(
+	apiClient *client.Client,
+	projID, clusterID uint,
+	namespace string,
+	names []string,
+	res map[string]*types.EnvGroup,
+	errs map[string]string,
+) []string {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	notFound := make([]string, 0)
+
+	for _, name := range names {
+		wg.Add(1)
+
+		go func(name string) {
+			defer wg.Done()
+
+			eg, err := apiClient.GetEnvGroup(context.Background(), projID, clusterID, namespace,
+				&types.GetEnvGroupRequest{
+					Name: name,
+				},
+			)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			var apiErr *client.APIError
+
+			if errors.As(err, &apiErr) && apiErr.ErrorCode == types.ErrorCodeEnvGroupNotFound {
+				notFound = append(notFound, name)
+			} else if err != nil {
+				errs[name] = err.Error()
+			} else {
+				res[name] = eg
+			}
+		}(name)
+	}
+
+	wg.Wait()
+
+	return notFound
+}
@@ -0,0 +1,140 @@
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+)
+
+var testEnvGroups = map[string]*types.EnvGroup{
+	"shared": {Name: "shared", Variables: map[string]string{"DATABASE_URL": "postgres://db", "LOG_LEVEL": "info"}},
+	"web":    {Name: "web", Variables: map[string]string{"LOG_LEVEL": "debug", "API_KEY": "PORTERSECRET_API_KEY"}},
+}
+
+// newEnvGroupsTestClient returns a client of a server that serves testEnvGroups, and fails
+// to read the env group named "broken". If batch is false, the server does not support
+// reading env groups in a batch.
+func newEnvGroupsTestClient(t *testing.T, batch bool) *client.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/envgroups/batch") {
+			if !batch {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			res := &types.GetEnvGroupsBatchResponse{
+				EnvGroups: make([]*types.EnvGroup, 0),
+				Errors:    make(map[string]string),
+			}
+
+			for _, name := range r.URL.Query()["names"] {
+				if eg, ok := testEnvGroups[name]; ok {
+					res.EnvGroups = append(res.EnvGroups, eg)
+				} else if name == "broken" {
+					res.Errors[name] = "could not read configmap"
+				} else {
+					res.NotFound = append(res.NotFound, name)
+				}
+			}
+
+			json.NewEncoder(w).Encode(res)
+			return
+		}
+
+		name := r.URL.Query().Get("name")
+
+		if eg, ok := testEnvGroups[name]; ok {
+			json.NewEncoder(w).Encode(eg)
+		} else if name == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(&types.ExternalError{
+				Error:     "could not read configmap",
+				ErrorCode: types.ErrorCodeInternal,
+			})
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(&types.ExternalError{
+				Error:     "env group not found",
+				ErrorCode: types.ErrorCodeEnvGroupNotFound,
+			})
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return client.NewClientWithToken(server.URL, "token")
+}
+
+func getEnvGroupsTestConfig(names ...string) map[string]interface{} {
+	synced := make([]interface{}, 0)
+
+	for _, name := range names {
+		synced = append(synced, map[string]interface{}{"name": name, "version": float64(1)})
+	}
+
+	return map[string]interface{}{
+		"container": map[string]interface{}{
+			"env": map[string]interface{}{
+				"normal": map[string]interface{}{"PORT": "8080"},
+				"synced": synced,
+			},
+		},
+	}
+}
+
+func TestGetEnvForReleaseSkipsMissingEnvGroups(t *testing.T) {
+	// the env groups are cached per project, so that each case reads them from its server
+	for projID, batch := range map[uint]bool{1: true, 2: false} {
+		apiClient := newEnvGroupsTestClient(t, batch)
+
+		env, err := GetEnvForRelease(apiClient, getEnvGroupsTestConfig("shared", "deleted", "web"), projID, 1, "default")
+
+		if err != nil {
+			t.Fatalf("batch %t: expected deleted env groups to be skipped, got %v", batch, err)
+		}
+
+		expected := map[string]string{
+			"PORT":         "8080",
+			"DATABASE_URL": "postgres://db",
+			"LOG_LEVEL":    "debug",
+		}
+
+		if len(env) != len(expected) {
+			t.Errorf("batch %t: expected env %v, got %v", batch, expected, env)
+		}
+
+		for key, val := range expected {
+			if env[key] != val {
+				t.Errorf("batch %t: expected %s to be %s, got %s", batch, key, val, env[key])
+			}
+		}
+	}
+}
+
+func TestGetEnvForReleaseFailsOnUnreadableEnvGroups(t *testing.T) {
+	for projID, batch := range map[uint]bool{3: true, 4: false} {
+		apiClient := newEnvGroupsTestClient(t, batch)
+
+		env, err := GetEnvForRelease(apiClient, getEnvGroupsTestConfig("shared", "broken"), projID, 1, "default")
+
+		var egErr *EnvGroupsError
+
+		if !errors.As(err, &egErr) {
+			t.Fatalf("batch %t: expected an env groups error, got %v", batch, err)
+		}
+
+		if _, ok := egErr.Errors["broken"]; !ok || len(egErr.Errors) != 1 {
+			t.Errorf("batch %t: expected only the broken env group to fail, got %v", batch, egErr.Errors)
+		}
+
+		if env["DATABASE_URL"] != "postgres://db" {
+			t.Errorf("batch %t: expected the env of the other env groups, got %v", batch, env)
+		}
+	}
+}
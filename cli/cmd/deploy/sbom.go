@@ -0,0 +1,194 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/sbom"
+)
+
+// UploadSBOM stores the dependency inventory of the pushed image of the release, keyed
+// by the image and its digest. The inventory becomes the inventory of the release once
+// the release is deployed with the image. Images that are built with pack carry the
+// SBOMs that the buildpacks generated. There is no SBOM for Docker builds, or for images
+// of buildpacks that write no SBOM, unless generate is set, in which case one is
+// generated with syft.
+func (d *DeployAgent) UploadSBOM(generate bool) error {
+	image := fmt.Sprintf("%s:%s", d.imageRepo, d.tag)
+
+	var format types.SBOMFormat
+	var document string
+
+	if d.opts.Method == DeployBuildTypePack {
+		var err error
+
+		format, document, err = d.getBuildpacksSBOM(image)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if document == "" && generate {
+		var err error
+
+		format = types.SBOMFormatCycloneDXJSON
+		document, err = generateSyftSBOM(image)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if document == "" {
+		return nil
+	}
+
+	if len(document) > types.MaxSBOMBytes {
+		return fmt.Errorf("the SBOM of image %s is larger than %d bytes", image, types.MaxSBOMBytes)
+	}
+
+	digest, err := d.agent.GetImageRepoDigest(image, d.imageRepo)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.CreateReleaseSBOM(
+		context.Background(),
+		d.opts.ProjectID, d.opts.ClusterID,
+		d.release.Namespace, d.release.Name,
+		&types.CreateReleaseSBOMRequest{
+			ImageRepo: d.imageRepo,
+			Tag:       d.tag,
+			Digest:    digest,
+			Format:    format,
+			Document:  document,
+		},
+	)
+
+	return err
+}
+
+// getBuildpacksSBOM returns the SBOM of an image built by buildpacks. Current buildpacks
+// write SBOMs for each of their layers to the SBOM layer of the image, which is
+// downloaded with pack. Older buildpacks write their bill of materials to a label of the
+// image instead.
+func (d *DeployAgent) getBuildpacksSBOM(image string) (types.SBOMFormat, string, error) {
+	labels, err := d.agent.GetImageLabels(image)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if sbom.HasSBOMLayer(labels[sbom.LifecycleMetadataLabel]) {
+		documents, err := downloadPackSBOMs(image)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		if len(documents) == 0 {
+			return "", "", nil
+		}
+
+		document, err := sbom.MergeCycloneDX(documents)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		return types.SBOMFormatCycloneDXJSON, string(document), nil
+	}
+
+	// the label lists no packages for buildpacks that write SBOMs to the SBOM layer
+	document := labels[sbom.BuildpacksMetadataLabel]
+
+	if pkgs, err := sbom.Parse(types.SBOMFormatBuildpacks, []byte(document)); err != nil || len(pkgs) == 0 {
+		return "", "", nil
+	}
+
+	return types.SBOMFormatBuildpacks, document, nil
+}
+
+// downloadPackSBOMs downloads the SBOM layer of a local image with pack, which must be
+// installed, and returns the CycloneDX SBOMs of the layers of the image that are part of
+// the app image
+func downloadPackSBOMs(image string) ([][]byte, error) {
+	if _, err := exec.LookPath("pack"); err != nil {
+		return nil, fmt.Errorf("pack must be installed to read the SBOM layer of images built with buildpacks")
+	}
+
+	dir, err := os.MkdirTemp("", "porter-sbom")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("pack", "sbom", "download", image, "--output-dir", dir)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("could not download SBOM layer with pack: %s", stderr.String())
+	}
+
+	res := make([][]byte, 0)
+
+	// the SBOMs of the layers of each buildpack are written to
+	// sbom/launch/<buildpack>/<layer>/sbom.cdx.json, along with SPDX and syft SBOMs
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".cdx.json") {
+			return nil
+		}
+
+		document, err := os.ReadFile(path)
+
+		if err != nil {
+			return err
+		}
+
+		res = append(res, document)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// generateSyftSBOM generates a CycloneDX SBOM of a local image with syft, which must be
+// installed
+func generateSyftSBOM(image string) (string, error) {
+	if _, err := exec.LookPath("syft"); err != nil {
+		return "", fmt.Errorf("syft must be installed to generate SBOMs of Docker builds")
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("syft", "docker:"+image, "-o", "cyclonedx-json", "-q")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("could not generate SBOM with syft: %s", stderr.String())
+	}
+
+	return stdout.String(), nil
+}
@@ -10,4 +10,7 @@ type SharedOpts struct {
 	OverrideTag     string
 	Method          DeployBuildType
 	AdditionalEnv   map[string]string
+
+	// Message describes the changes of a deploy
+	Message string
 }
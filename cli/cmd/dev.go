@@ -0,0 +1,429 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/dev"
+	"github.com/porter-dev/porter/cli/cmd/utils"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// devCmd represents the "porter dev" command
+var devCmd = &cobra.Command{
+	Use:   "dev [release]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Syncs local file changes into a running container of an application.",
+	Long: fmt.Sprintf(`
+%s
+
+Syncs the files of a local directory into a running container of an application, and syncs
+every change to the files until the command is stopped. This shortens the edit-deploy loop
+for interpreted runtimes, which can reload changed files without a new build. For example:
+
+  %s
+
+The --exec flag runs a command in the container after each sync, such as a command that
+reloads the application:
+
+  %s
+
+The container must have the tar command. Synced changes are lost when the pod restarts or
+the application is deployed, and the files are synced again if the pod is replaced while
+the command runs.
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter dev\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter dev web --path ./src --sync-path /app/src"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter dev web --exec \"kill -HUP 1\" --ignore node_modules"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, devSync)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var devLocalPath string
+var devSyncPath string
+var devExec string
+var devContainer string
+var devIgnore []string
+var devInterval time.Duration
+
+func init() {
+	rootCmd.AddCommand(devCmd)
+
+	devCmd.PersistentFlags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"namespace of release to sync to",
+	)
+
+	devCmd.PersistentFlags().StringVar(
+		&devLocalPath,
+		"path",
+		".",
+		"local directory to sync",
+	)
+
+	devCmd.PersistentFlags().StringVar(
+		&devSyncPath,
+		"sync-path",
+		"/app",
+		"directory in the container that the local directory is synced to",
+	)
+
+	devCmd.PersistentFlags().StringVar(
+		&devExec,
+		"exec",
+		"",
+		"command to run in the container after each sync",
+	)
+
+	devCmd.PersistentFlags().StringVar(
+		&devContainer,
+		"container",
+		"",
+		"container to sync to, if the pods of the release have multiple containers",
+	)
+
+	devCmd.PersistentFlags().StringArrayVar(
+		&devIgnore,
+		"ignore",
+		nil,
+		"pattern of files or directories that are not synced, such as node_modules or *.pyc",
+	)
+
+	devCmd.PersistentFlags().DurationVar(
+		&devInterval,
+		"interval",
+		500*time.Millisecond,
+		"how often the local directory is checked for changes",
+	)
+}
+
+// devSession is the pod and container that local files are synced to
+type devSession struct {
+	config    *PorterRunSharedConfig
+	client    *api.Client
+	release   string
+	pod       string
+	container string
+	root      string
+	ignore    []string
+
+	// containerName is the name of the container to sync to, which is selected once so
+	// that the same container is selected if the pod is replaced
+	containerName string
+}
+
+func devSync(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	root, err := filepath.Abs(devLocalPath)
+
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", devLocalPath)
+	}
+
+	if !path.IsAbs(devSyncPath) {
+		return fmt.Errorf("the sync path must be an absolute path in the container")
+	}
+
+	config := &PorterRunSharedConfig{
+		Client: client,
+	}
+
+	if err := config.setSharedConfig(); err != nil {
+		return fmt.Errorf("Could not retrieve kube credentials: %s", err.Error())
+	}
+
+	session := &devSession{
+		config:  config,
+		client:  client,
+		release: args[0],
+		root:    root,
+		ignore:  append(append([]string{}, dev.DefaultIgnore...), devIgnore...),
+
+		containerName: devContainer,
+	}
+
+	if err := session.selectPod(true); err != nil {
+		return err
+	}
+
+	color.New(color.FgYellow).Println("Synced changes are lost when the pod restarts or the application is deployed")
+
+	snapshot, err := session.syncAll()
+
+	if err != nil {
+		return err
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+
+	ticker := time.NewTicker(devInterval)
+	defer ticker.Stop()
+
+	color.New(color.FgGreen).Printf("Watching %s for changes, press Ctrl+C to stop\n", root)
+
+	for {
+		select {
+		case <-sig:
+			return nil
+		case <-ticker.C:
+		}
+
+		// if the pod was replaced, the files are synced to a new pod of the release
+		if !session.podExists() {
+			color.New(color.FgYellow).Printf("Pod %s no longer exists, selecting a new pod\n", session.pod)
+
+			if err := session.selectPod(false); err != nil {
+				color.New(color.FgRed).Printf("Could not select a new pod: %s\n", err.Error())
+				continue
+			}
+
+			if next, err := session.syncAll(); err == nil {
+				snapshot = next
+			} else {
+				color.New(color.FgRed).Printf("Could not sync files: %s\n", err.Error())
+			}
+
+			continue
+		}
+
+		next, err := dev.TakeSnapshot(root, session.ignore)
+
+		if err != nil {
+			color.New(color.FgRed).Printf("Could not read local files: %s\n", err.Error())
+			continue
+		}
+
+		changed, deleted := dev.Diff(snapshot, next)
+
+		if len(changed) == 0 && len(deleted) == 0 {
+			continue
+		}
+
+		// the snapshot is only updated after a successful sync, so that failed changes
+		// are synced again
+		if err := session.sync(changed, deleted); err != nil {
+			color.New(color.FgRed).Printf("Could not sync files: %s\n", err.Error())
+			continue
+		}
+
+		snapshot = next
+	}
+}
+
+// selectPod selects the pod and container of the release to sync to. The user is
+// prompted to select a pod if the release has multiple pods and prompt is set.
+func (s *devSession) selectPod(prompt bool) error {
+	podsSimple, err := getPods(s.client, namespace, s.release)
+
+	if err != nil {
+		return fmt.Errorf("Could not retrieve list of pods: %s", err.Error())
+	}
+
+	if len(podsSimple) == 0 {
+		return fmt.Errorf("At least one pod must exist in this deployment.")
+	}
+
+	selectedPod := podsSimple[0]
+
+	if len(podsSimple) > 1 && prompt {
+		podNames := make([]string, 0)
+
+		for _, podSimple := range podsSimple {
+			podNames = append(podNames, podSimple.Name)
+		}
+
+		selectedPodName, err := utils.PromptSelect("Select the pod:", podNames)
+
+		if err != nil {
+			return err
+		}
+
+		for _, podSimple := range podsSimple {
+			if selectedPodName == podSimple.Name {
+				selectedPod = podSimple
+			}
+		}
+	}
+
+	if len(selectedPod.ContainerNames) == 0 {
+		return fmt.Errorf("At least one container must exist in the pod.")
+	}
+
+	selectedContainer := selectedPod.ContainerNames[0]
+
+	if s.containerName != "" {
+		selectedContainer = ""
+
+		for _, containerName := range selectedPod.ContainerNames {
+			if containerName == s.containerName {
+				selectedContainer = containerName
+			}
+		}
+
+		if selectedContainer == "" {
+			return fmt.Errorf("container %s not found in pod %s", s.containerName, selectedPod.Name)
+		}
+	} else if len(selectedPod.ContainerNames) > 1 && prompt {
+		selectedContainer, err = utils.PromptSelect("Select the container:", selectedPod.ContainerNames)
+
+		if err != nil {
+			return err
+		}
+
+		s.containerName = selectedContainer
+	}
+
+	s.pod = selectedPod.Name
+	s.container = selectedContainer
+
+	return nil
+}
+
+func (s *devSession) podExists() bool {
+	_, err := s.config.Clientset.CoreV1().Pods(namespace).Get(context.Background(), s.pod, metav1.GetOptions{})
+
+	return err == nil
+}
+
+// syncAll syncs every local file to the container, and returns the snapshot of the
+// synced files
+func (s *devSession) syncAll() (dev.Snapshot, error) {
+	snapshot, err := dev.TakeSnapshot(s.root, s.ignore)
+
+	if err != nil {
+		return nil, err
+	}
+
+	changed, _ := dev.Diff(dev.Snapshot{}, snapshot)
+
+	if err := s.sync(changed, nil); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// sync copies changed files to the container and removes deleted files from the
+// container, and runs the exec command if files were synced
+func (s *devSession) sync(changed, deleted []string) error {
+	if len(changed) > 0 {
+		pr, pw := io.Pipe()
+
+		go func() {
+			pw.CloseWithError(dev.WriteTar(pw, s.root, changed))
+		}()
+
+		err := s.exec([]string{
+			"sh", "-c",
+			fmt.Sprintf("mkdir -p %s && tar -xf - -C %s", shellQuote(devSyncPath), shellQuote(devSyncPath)),
+		}, pr, io.Discard)
+
+		pr.Close()
+
+		if err != nil {
+			return fmt.Errorf("could not copy files to %s: %w", s.pod, err)
+		}
+	}
+
+	if len(deleted) > 0 {
+		command := []string{"rm", "-f", "--"}
+
+		for _, file := range deleted {
+			command = append(command, path.Join(devSyncPath, file))
+		}
+
+		if err := s.exec(command, nil, io.Discard); err != nil {
+			return fmt.Errorf("could not remove files from %s: %w", s.pod, err)
+		}
+	}
+
+	if len(changed) == 0 && len(deleted) == 0 {
+		return nil
+	}
+
+	color.New(color.FgGreen).Printf(
+		"[%s] Synced %d changed and %d deleted files to %s\n",
+		time.Now().Format("15:04:05"),
+		len(changed),
+		len(deleted),
+		s.pod,
+	)
+
+	if devExec != "" {
+		if err := s.exec([]string{"sh", "-c", devExec}, nil, os.Stdout); err != nil {
+			// the files were synced, so a failed command is not retried
+			color.New(color.FgRed).Printf("Command %q failed: %s\n", devExec, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// exec runs a command in the container without a TTY. The output of the command on
+// stderr is returned with its error.
+func (s *devSession) exec(command []string, stdin io.Reader, stdout io.Writer) error {
+	req := s.config.RestClient.Post().
+		Resource("pods").
+		Name(s.pod).
+		Namespace(namespace).
+		SubResource("exec")
+
+	for _, arg := range command {
+		req.Param("command", arg)
+	}
+
+	req.Param("container", s.container)
+	req.Param("stdin", fmt.Sprintf("%t", stdin != nil))
+	req.Param("stdout", "true")
+	req.Param("stderr", "true")
+
+	exec, err := remotecommand.NewSPDYExecutor(s.config.RestConf, "POST", req.URL())
+
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	})
+
+	if err != nil && stderr.Len() > 0 {
+		return fmt.Errorf("%s: %s", err.Error(), strings.TrimSpace(stderr.String()))
+	}
+
+	return err
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,168 @@
+package dev
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultIgnore are the patterns of files that are never synced
+var DefaultIgnore = []string{".git"}
+
+// FileInfo is the state of a file that is compared between snapshots
+type FileInfo struct {
+	ModTime time.Time
+	Size    int64
+	Mode    os.FileMode
+}
+
+// Snapshot is the state of the files under a directory, by their slash-separated path
+// relative to the directory
+type Snapshot map[string]FileInfo
+
+// TakeSnapshot reads the state of the regular files under a directory, skipping the files
+// and directories that match an ignore pattern
+func TakeSnapshot(root string, ignore []string) (Snapshot, error) {
+	res := make(Snapshot)
+
+	err := filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, filePath)
+
+		if err != nil {
+			return err
+		}
+
+		relPath = filepath.ToSlash(relPath)
+
+		if relPath == "." {
+			return nil
+		}
+
+		if IsIgnored(relPath, ignore) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if info.Mode().IsRegular() {
+			res[relPath] = FileInfo{
+				ModTime: info.ModTime(),
+				Size:    info.Size(),
+				Mode:    info.Mode(),
+			}
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// Diff returns the files that were created or modified, and the files that were deleted,
+// between two snapshots, sorted by path
+func Diff(prev, next Snapshot) (changed []string, deleted []string) {
+	changed = make([]string, 0)
+	deleted = make([]string, 0)
+
+	for filePath, info := range next {
+		if prevInfo, ok := prev[filePath]; !ok || !prevInfo.ModTime.Equal(info.ModTime) ||
+			prevInfo.Size != info.Size || prevInfo.Mode != info.Mode {
+			changed = append(changed, filePath)
+		}
+	}
+
+	for filePath := range prev {
+		if _, ok := next[filePath]; !ok {
+			deleted = append(deleted, filePath)
+		}
+	}
+
+	sort.Strings(changed)
+	sort.Strings(deleted)
+
+	return changed, deleted
+}
+
+// IsIgnored returns true if a slash-separated path, or one of its parent directories,
+// matches an ignore pattern. Patterns are matched against the whole path and against the
+// name of each element of the path, so "node_modules" ignores every node_modules
+// directory, and "*.pyc" ignores every compiled Python file.
+func IsIgnored(relPath string, ignore []string) bool {
+	elems := strings.Split(relPath, "/")
+
+	for _, pattern := range ignore {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+
+		for i := range elems {
+			if matched, _ := path.Match(pattern, strings.Join(elems[:i+1], "/")); matched {
+				return true
+			}
+
+			if matched, _ := path.Match(pattern, elems[i]); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// WriteTar writes a tar archive of files under a directory to a writer. The files are
+// slash-separated paths relative to the directory, and are stored under the same paths
+// in the archive.
+func WriteTar(w io.Writer, root string, files []string) error {
+	tw := tar.NewWriter(w)
+
+	for _, file := range files {
+		if err := writeTarFile(tw, root, file); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+func writeTarFile(tw *tar.Writer, root, file string) error {
+	f, err := os.Open(filepath.Join(root, filepath.FromSlash(file)))
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+
+	if err != nil {
+		return err
+	}
+
+	header.Name = file
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	// the size of the file is read once, so that a file that is written while it is
+	// archived is archived with the size of its header
+	_, err = io.CopyN(tw, f, header.Size)
+
+	return err
+}
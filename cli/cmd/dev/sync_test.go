@@ -0,0 +1,140 @@
+package dev_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/cli/cmd/dev"
+)
+
+func TestSnapshotDiff(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "app.py", "print('hello')")
+	writeFile(t, root, "lib/util.py", "x = 1")
+	writeFile(t, root, "lib/util.pyc", "compiled")
+	writeFile(t, root, "node_modules/pkg/index.js", "module.exports = {}")
+	writeFile(t, root, ".git/HEAD", "ref: refs/heads/main")
+
+	ignore := append([]string{"node_modules", "*.pyc"}, dev.DefaultIgnore...)
+
+	prev, err := dev.TakeSnapshot(root, ignore)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changed, deleted := dev.Diff(dev.Snapshot{}, prev)
+
+	if expected := []string{"app.py", "lib/util.py"}; !reflect.DeepEqual(expected, changed) {
+		t.Errorf("incorrect initial files: expected %v, got %v", expected, changed)
+	}
+
+	if len(deleted) != 0 {
+		t.Errorf("expected no deleted files, got %v", deleted)
+	}
+
+	// modification times may have a coarse resolution, so the modified file is given a
+	// later modification time
+	writeFile(t, root, "lib/util.py", "x = 2")
+	later := time.Now().Add(time.Minute)
+
+	if err := os.Chtimes(filepath.Join(root, "lib", "util.py"), later, later); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	writeFile(t, root, "lib/new.py", "y = 1")
+
+	if err := os.Remove(filepath.Join(root, "app.py")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	next, err := dev.TakeSnapshot(root, ignore)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changed, deleted = dev.Diff(prev, next)
+
+	if expected := []string{"lib/new.py", "lib/util.py"}; !reflect.DeepEqual(expected, changed) {
+		t.Errorf("incorrect changed files: expected %v, got %v", expected, changed)
+	}
+
+	if expected := []string{"app.py"}; !reflect.DeepEqual(expected, deleted) {
+		t.Errorf("incorrect deleted files: expected %v, got %v", expected, deleted)
+	}
+}
+
+func TestIsIgnored(t *testing.T) {
+	ignore := []string{"node_modules/", "*.log", "build/cache"}
+
+	tests := map[string]bool{
+		"node_modules/pkg/index.js": true,
+		"web/node_modules/pkg.js":   true,
+		"logs/app.log":              true,
+		"build/cache/a.o":           true,
+		"build/out/a.o":             false,
+		"src/index.js":              false,
+	}
+
+	for relPath, expected := range tests {
+		if res := dev.IsIgnored(relPath, ignore); res != expected {
+			t.Errorf("expected %s to be ignored to be %t", relPath, expected)
+		}
+	}
+}
+
+func TestWriteTar(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "lib/util.py", "x = 1")
+
+	var buf bytes.Buffer
+
+	if err := dev.WriteTar(&buf, root, []string{"lib/util.py"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tr := tar.NewReader(&buf)
+
+	header, err := tr.Next()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if header.Name != "lib/util.py" {
+		t.Errorf("expected archived file lib/util.py, got %s", header.Name)
+	}
+
+	contents, err := io.ReadAll(tr)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(contents) != "x = 1" {
+		t.Errorf("incorrect archived contents: %s", contents)
+	}
+}
+
+func writeFile(t *testing.T, root, relPath, contents string) {
+	t.Helper()
+
+	filePath := filepath.Join(root, filepath.FromSlash(relPath))
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+
+	if err := os.WriteFile(filePath, []byte(contents), 0o644); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+}
@@ -17,8 +17,6 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
-	"github.com/moby/moby/pkg/jsonmessage"
-	"github.com/moby/term"
 )
 
 // Agent is a Docker client for performing operations that interact
@@ -143,6 +141,39 @@ func (a *Agent) TagImage(old, new string) error {
 	return a.client.ImageTag(a.ctx, old, new)
 }
 
+// GetImageLabels returns the labels of a local image
+func (a *Agent) GetImageLabels(image string) (map[string]string, error) {
+	inspect, _, err := a.client.ImageInspectWithRaw(a.ctx, image)
+
+	if err != nil {
+		return nil, a.handleDockerClientErr(err, "Could not inspect image "+image)
+	}
+
+	if inspect.Config == nil {
+		return map[string]string{}, nil
+	}
+
+	return inspect.Config.Labels, nil
+}
+
+// GetImageRepoDigest returns the digest of a local image in a repository, which is only
+// known once the image has been pushed to or pulled from the repository
+func (a *Agent) GetImageRepoDigest(image, imageRepo string) (string, error) {
+	inspect, _, err := a.client.ImageInspectWithRaw(a.ctx, image)
+
+	if err != nil {
+		return "", a.handleDockerClientErr(err, "Could not inspect image "+image)
+	}
+
+	for _, repoDigest := range inspect.RepoDigests {
+		if strings.HasPrefix(repoDigest, imageRepo+"@") {
+			return strings.TrimPrefix(repoDigest, imageRepo+"@"), nil
+		}
+	}
+
+	return "", fmt.Errorf("image %s has no digest in repository %s", image, imageRepo)
+}
+
 // PullImageEvent represents a response from the Docker API with an image pull event
 type PullImageEvent struct {
 	Status         string `json:"status"`
@@ -158,8 +189,15 @@ var PullImageErrNotFound = fmt.Errorf("Requested image not found")
 
 var PullImageErrUnauthorized = fmt.Errorf("Could not pull image: unauthorized")
 
-// PullImage pulls an image specified by the image string
+// PullImage pulls an image specified by the image string. Pulls that fail with a
+// transient error are retried with exponential backoff.
 func (a *Agent) PullImage(image string) error {
+	return withRetry("Pull of "+image, func() error {
+		return a.pullImage(image)
+	})
+}
+
+func (a *Agent) pullImage(image string) error {
 	opts, err := a.getPullOptions(image)
 
 	if err != nil {
@@ -181,13 +219,19 @@ func (a *Agent) PullImage(image string) error {
 
 	defer out.Close()
 
-	termFd, isTerm := term.GetFdInfo(os.Stderr)
-
-	return jsonmessage.DisplayJSONMessagesStream(out, os.Stderr, termFd, isTerm, nil)
+	return DisplayProgress(out, os.Stderr)
 }
 
-// PushImage pushes an image specified by the image string
+// PushImage pushes an image specified by the image string. Pushes that fail with a
+// transient error are retried with exponential backoff.
 func (a *Agent) PushImage(image string) error {
+	return withRetry("Push of "+image, func() error {
+		return a.pushImage(image)
+	})
+}
+
+func (a *Agent) pushImage(image string) error {
+	// the options are read for every attempt, since registry tokens may expire
 	opts, err := a.getPushOptions(image)
 
 	if err != nil {
@@ -208,9 +252,7 @@ func (a *Agent) PushImage(image string) error {
 		return err
 	}
 
-	termFd, isTerm := term.GetFdInfo(os.Stderr)
-
-	return jsonmessage.DisplayJSONMessagesStream(out, os.Stderr, termFd, isTerm, nil)
+	return DisplayProgress(out, os.Stderr)
 }
 
 func (a *Agent) getPullOptions(image string) (types.ImagePullOptions, error) {
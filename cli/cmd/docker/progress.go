@@ -0,0 +1,105 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/moby/moby/pkg/jsonmessage"
+	"github.com/moby/term"
+)
+
+// progressStep is the step in percent at which the progress of a layer is printed
+// when the output is not a terminal
+const progressStep = 25
+
+// DisplayProgress displays the progress of a push or pull from a stream of Docker
+// JSON messages. Terminals get the progress bars of the Docker CLI. Other outputs,
+// such as CI logs, get a line whenever the status of a layer changes and for every
+// 25% of progress of a layer, so that the output stays readable. The error of the
+// stream is returned, if any.
+func DisplayProgress(in io.Reader, out io.Writer) error {
+	termFd, isTerm := term.GetFdInfo(out)
+
+	if isTerm {
+		return jsonmessage.DisplayJSONMessagesStream(in, out, termFd, isTerm, nil)
+	}
+
+	return displayLayerProgress(in, out)
+}
+
+type layerProgress struct {
+	status  string
+	percent int64
+}
+
+func displayLayerProgress(in io.Reader, out io.Writer) error {
+	decoder := json.NewDecoder(in)
+	layers := make(map[string]*layerProgress)
+
+	for {
+		var msg jsonmessage.JSONMessage
+
+		if err := decoder.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if msg.Error != nil {
+			return msg.Error
+		}
+
+		if msg.ID == "" {
+			if msg.Status != "" {
+				fmt.Fprintln(out, msg.Status)
+			}
+
+			continue
+		}
+
+		layer, ok := layers[msg.ID]
+
+		if !ok {
+			layer = &layerProgress{}
+			layers[msg.ID] = layer
+		}
+
+		if msg.Status != layer.status {
+			layer.status = msg.Status
+			layer.percent = 0
+
+			fmt.Fprintf(out, "%s: %s\n", msg.ID, msg.Status)
+		}
+
+		if msg.Progress == nil || msg.Progress.Total <= 0 {
+			continue
+		}
+
+		percent := msg.Progress.Current * 100 / msg.Progress.Total
+		percent -= percent % progressStep
+
+		if percent > layer.percent && percent < 100 {
+			layer.percent = percent
+
+			fmt.Fprintf(out, "%s: %s %d%% of %s\n", msg.ID, msg.Status, percent, formatBytes(msg.Progress.Total))
+		}
+	}
+}
+
+func formatBytes(size int64) string {
+	const unit = 1000
+
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	div, exp := int64(unit), 0
+
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%cB", float64(size)/float64(div), "kMGTPE"[exp])
+}
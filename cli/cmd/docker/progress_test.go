@@ -0,0 +1,96 @@
+package docker
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDisplayLayerProgress(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"status":"The push refers to repository [docker.io/test/app]"}`,
+		`{"status":"Preparing","id":"abc123"}`,
+		`{"status":"Pushing","id":"abc123","progressDetail":{"current":100,"total":1000}}`,
+		`{"status":"Pushing","id":"abc123","progressDetail":{"current":300,"total":1000}}`,
+		`{"status":"Pushing","id":"abc123","progressDetail":{"current":400,"total":1000}}`,
+		`{"status":"Pushing","id":"abc123","progressDetail":{"current":800,"total":1000}}`,
+		`{"status":"Pushed","id":"abc123"}`,
+		`{"status":"latest: digest: sha256:123 size: 1000"}`,
+	}, "\n")
+
+	var out bytes.Buffer
+
+	if err := displayLayerProgress(strings.NewReader(stream), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"The push refers to repository [docker.io/test/app]",
+		"abc123: Preparing",
+		"abc123: Pushing",
+		"abc123: Pushing 25% of 1.0kB",
+		"abc123: Pushing 75% of 1.0kB",
+		"abc123: Pushed",
+		"latest: digest: sha256:123 size: 1000",
+	}, "\n") + "\n"
+
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestDisplayLayerProgressError(t *testing.T) {
+	stream := `{"status":"Pushing","id":"abc123"}
+{"errorDetail":{"message":"received unexpected HTTP status: 503 Service Unavailable"},"error":"received unexpected HTTP status: 503 Service Unavailable"}`
+
+	var out bytes.Buffer
+
+	err := displayLayerProgress(strings.NewReader(stream), &out)
+
+	if err == nil || !isTransientErr(err) {
+		t.Errorf("expected a transient error, got %v", err)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	initialPushPullBackoff = 0
+
+	attempts := 0
+
+	err := withRetry("Push", func() error {
+		attempts++
+
+		if attempts < 3 {
+			return errors.New("read: connection reset by peer")
+		}
+
+		return nil
+	})
+
+	if err != nil || attempts != 3 {
+		t.Errorf("expected success after 3 attempts, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+
+	err = withRetry("Pull", func() error {
+		attempts++
+		return PullImageErrUnauthorized
+	})
+
+	if err != PullImageErrUnauthorized || attempts != 1 {
+		t.Errorf("expected permanent error to not be retried, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+
+	err = withRetry("Push", func() error {
+		attempts++
+		return errors.New("net/http: TLS handshake timeout")
+	})
+
+	if err == nil || attempts != maxPushPullAttempts {
+		t.Errorf("expected error after %d attempts, got %v after %d attempts", maxPushPullAttempts, err, attempts)
+	}
+}
@@ -0,0 +1,75 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	// maxPushPullAttempts is the number of attempts of a push or pull before the error
+	// is returned
+	maxPushPullAttempts = 5
+
+	// initialPushPullBackoff is the wait time after the first failed attempt, which is
+	// doubled after each following attempt
+	initialPushPullBackoff = 2 * time.Second
+)
+
+// transientErrs are substrings of errors from the Docker daemon or registries that
+// are likely to succeed when retried
+var transientErrs = []string{
+	"timeout",
+	"connection reset",
+	"broken pipe",
+	"unexpected eof",
+	"temporary failure",
+	"toomanyrequests",
+	"too many requests",
+	"500 internal server error",
+	"502 bad gateway",
+	"503 service unavailable",
+	"504 gateway timeout",
+	"received unexpected http status: 5",
+}
+
+func isTransientErr(err error) bool {
+	if err == nil || errors.Is(err, PullImageErrNotFound) || errors.Is(err, PullImageErrUnauthorized) {
+		return false
+	}
+
+	errStr := strings.ToLower(err.Error())
+
+	for _, transientErr := range transientErrs {
+		if strings.Contains(errStr, transientErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withRetry runs a push or pull operation, and retries it with exponential backoff
+// if it fails with a transient error
+func withRetry(operation string, fn func() error) error {
+	backoff := initialPushPullBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+
+		if err == nil || !isTransientErr(err) || attempt >= maxPushPullAttempts {
+			return err
+		}
+
+		fmt.Fprintf(
+			os.Stderr,
+			"%s failed: %s. Retrying in %s (attempt %d of %d)\n",
+			operation, err.Error(), backoff, attempt+1, maxPushPullAttempts,
+		)
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
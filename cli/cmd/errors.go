@@ -35,6 +35,8 @@ func checkLoginAndRun(args []string, runner func(user *types.GetAuthenticatedUse
 		return err
 	}
 
+	checkCLIVersion(client)
+
 	err = runner(user, client, args)
 
 	if err != nil {
@@ -51,8 +53,52 @@ func checkLoginAndRun(args []string, runner func(user *types.GetAuthenticatedUse
 		}
 
 		red.Printf("Error: %v\n", err.Error())
+		printErrorHint(err)
 		return err
 	}
 
 	return nil
 }
+
+// errorCodeHints are the remediation hints that are printed for well-known API errors
+var errorCodeHints = map[types.ErrorCode]string{
+	types.ErrorCodeNotLoggedIn:           "Log in using \"porter auth login\"",
+	types.ErrorCodeInvalidCredentials:    "Check your email and password, and log in again using \"porter auth login\"",
+	types.ErrorCodeForbidden:             "Your role in the project does not allow this operation. Ask an admin of the project for access.",
+	types.ErrorCodeInvalidJSON:           "The request could not be parsed. Check the values and files that were passed to the command.",
+	types.ErrorCodeValidationFailed:      "Check the values that were passed to the command against the fields named in the error.",
+	types.ErrorCodeUnavailableInCE:       "This feature is only available in the enterprise edition of Porter.",
+	types.ErrorCodeQuotaExceeded:         "Remove unused resources, or ask the admin of your Porter instance to raise the quota.",
+	types.ErrorCodeUsageLimitExceeded:    "Remove unused resources, or upgrade the plan of your project.",
+	types.ErrorCodeEntitlementRequired:   "This feature is not included in the plan of your project. Upgrade the plan to use it.",
+	types.ErrorCodeSSORequired:           "This project requires its members to sign in through SSO. Log in again using \"porter auth login\" and sign in through SSO.",
+	types.ErrorCodeDeployPendingApproval: "Ask a maintainer of the repository to approve the deployment.",
+	types.ErrorCodeClusterNotFound:       "List the clusters of the project using \"porter cluster list\", and select one using \"porter config set-cluster [id]\"",
+	types.ErrorCodeReleaseNotFound:       "Check the name and namespace of the application. The namespace can be set with the --namespace flag.",
+	types.ErrorCodeChartNotFound:         "Check the name and version of the chart, and that the chart exists in the Helm repository.",
+	types.ErrorCodeEnvGroupNotFound:      "Check the name and namespace of the env group.",
+	types.ErrorCodeRegistryAuth:          "Check the credentials of the registry integration, and refresh your local credentials using \"porter docker configure\"",
+	types.ErrorCodeWebhookDisabled:       "Enable auto-deploy in the settings of the application to use its deploy webhook.",
+	types.ErrorCodeHelmOperationFailed:   "Check the values of the application, and the events of the application in the dashboard.",
+	types.ErrorCodeChartNotAllowed:       "The chart is not in the allow-list of the project. Ask an admin of the project to allow the chart.",
+	types.ErrorCodeImageNotAllowed:       "Push the image to an allowed registry of the project, or ask an admin of the project to allow the registry.",
+	types.ErrorCodeImageNotSigned:        "Sign the image with a signing authority of the project using cosign, and push its signature to the registry of the image.",
+	types.ErrorCodeDeletionProtected:     "Disable deletion protection in the settings of the application before deleting it.",
+	types.ErrorCodeDeployFrozen:          "Wait for the deploy freeze to end, or ask an admin of the project to override it with --freeze-override-reason.",
+	types.ErrorCodeRevisionConflict:      "The application was upgraded since the revision that the change was based on. Review the latest values and retry the command.",
+	types.ErrorCodeReleasePaused:         "Resume the application using \"porter resume --app <app>\" before deploying it.",
+	types.ErrorCodeInternal:              "Retry the command, and contact support if it keeps failing.",
+}
+
+// printErrorHint prints the remediation hint of an API error with a well-known error code
+func printErrorHint(err error) {
+	var apiErr *api.APIError
+
+	if !errors.As(err, &apiErr) {
+		return
+	}
+
+	if hint, ok := errorCodeHints[apiErr.ErrorCode]; ok {
+		color.New(color.FgYellow).Printf("Hint: %s (%s)\n", hint, apiErr.ErrorCode)
+	}
+}
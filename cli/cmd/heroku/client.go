@@ -0,0 +1,139 @@
+package heroku
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultBaseURL is the URL of the Heroku Platform API
+const DefaultBaseURL = "https://api.heroku.com"
+
+// Client reads apps from the Heroku Platform API
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client of the Heroku Platform API that authenticates with an API
+// token, such as the token printed by "heroku auth:token"
+func NewClient(token string) *Client {
+	return &Client{
+		BaseURL: DefaultBaseURL,
+		Token:   token,
+		HTTPClient: &http.Client{
+			Timeout: time.Minute,
+		},
+	}
+}
+
+// Formation is the process type, command and scale of a set of dynos
+type Formation struct {
+	Type     string `json:"type"`
+	Command  string `json:"command"`
+	Quantity int    `json:"quantity"`
+	Size     string `json:"size"`
+}
+
+// Addon is an add-on that is attached to an app
+type Addon struct {
+	Name         string `json:"name"`
+	AddonService struct {
+		Name string `json:"name"`
+	} `json:"addon_service"`
+	Plan struct {
+		Name string `json:"name"`
+	} `json:"plan"`
+
+	// ConfigVars are the config vars of the app that are set by the add-on
+	ConfigVars []string `json:"config_vars"`
+}
+
+// BuildpackInstallation is a buildpack that builds an app
+type BuildpackInstallation struct {
+	Ordinal   int `json:"ordinal"`
+	Buildpack struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"buildpack"`
+}
+
+// App is the configuration of a Heroku app that is migrated to Porter
+type App struct {
+	Name       string
+	ConfigVars map[string]string
+	Formation  []Formation
+	Addons     []Addon
+	Buildpacks []BuildpackInstallation
+}
+
+// GetApp reads the config vars, formation, add-ons and buildpacks of an app
+func (c *Client) GetApp(name string) (*App, error) {
+	app := &App{
+		Name:       name,
+		ConfigVars: make(map[string]string),
+		Formation:  make([]Formation, 0),
+		Addons:     make([]Addon, 0),
+		Buildpacks: make([]BuildpackInstallation, 0),
+	}
+
+	appPath := "/apps/" + url.PathEscape(name)
+
+	if err := c.get(appPath+"/config-vars", &app.ConfigVars); err != nil {
+		return nil, fmt.Errorf("could not read config vars: %w", err)
+	}
+
+	if err := c.get(appPath+"/formation", &app.Formation); err != nil {
+		return nil, fmt.Errorf("could not read formation: %w", err)
+	}
+
+	if err := c.get(appPath+"/addons", &app.Addons); err != nil {
+		return nil, fmt.Errorf("could not read add-ons: %w", err)
+	}
+
+	if err := c.get(appPath+"/buildpack-installations", &app.Buildpacks); err != nil {
+		return nil, fmt.Errorf("could not read buildpacks: %w", err)
+	}
+
+	return app, nil
+}
+
+// apiError is the body of an error response of the Heroku Platform API
+type apiError struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+func (c *Client) get(relPath string, res interface{}) error {
+	req, err := http.NewRequest("GET", c.BaseURL+relPath, nil)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.heroku+json; version=3")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+
+	resp, err := c.HTTPClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &apiError{}
+
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+			return fmt.Errorf("request failed with status code %d", resp.StatusCode)
+		}
+
+		return fmt.Errorf("%s (%s)", apiErr.Message, apiErr.ID)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(res)
+}
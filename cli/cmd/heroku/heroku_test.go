@@ -0,0 +1,163 @@
+package heroku_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/cli/cmd/heroku"
+)
+
+func TestGetApp(t *testing.T) {
+	responses := map[string]string{
+		"/apps/shop/config-vars": `{"DATABASE_URL": "postgres://heroku", "SECRET_KEY": "secret"}`,
+		"/apps/shop/formation":   `[{"type": "web", "command": "npm start", "quantity": 2, "size": "Standard-2X"}]`,
+		"/apps/shop/addons": `[{
+			"name": "postgresql-curly-12345",
+			"addon_service": {"name": "heroku-postgresql"},
+			"plan": {"name": "heroku-postgresql:mini"},
+			"config_vars": ["DATABASE_URL"]
+		}]`,
+		"/apps/shop/buildpack-installations": `[{"ordinal": 0, "buildpack": {"name": "heroku/nodejs", "url": "heroku/nodejs"}}]`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"id": "unauthorized", "message": "Invalid credentials provided."}`))
+			return
+		}
+
+		res, ok := responses[r.URL.Path]
+
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"id": "not_found", "message": "Couldn't find that app."}`))
+			return
+		}
+
+		w.Write([]byte(res))
+	}))
+
+	defer server.Close()
+
+	client := heroku.NewClient("token")
+	client.BaseURL = server.URL
+
+	app, err := client.GetApp("shop")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := map[string]string{"DATABASE_URL": "postgres://heroku", "SECRET_KEY": "secret"}; !reflect.DeepEqual(expected, app.ConfigVars) {
+		t.Errorf("incorrect config vars: %v", app.ConfigVars)
+	}
+
+	expectedFormation := []heroku.Formation{{Type: "web", Command: "npm start", Quantity: 2, Size: "Standard-2X"}}
+
+	if !reflect.DeepEqual(expectedFormation, app.Formation) {
+		t.Errorf("incorrect formation: %+v", app.Formation)
+	}
+
+	if len(app.Addons) != 1 || app.Addons[0].AddonService.Name != "heroku-postgresql" ||
+		!reflect.DeepEqual([]string{"DATABASE_URL"}, app.Addons[0].ConfigVars) {
+		t.Errorf("incorrect add-ons: %+v", app.Addons)
+	}
+
+	if len(app.Buildpacks) != 1 || app.Buildpacks[0].Buildpack.Name != "heroku/nodejs" {
+		t.Errorf("incorrect buildpacks: %+v", app.Buildpacks)
+	}
+
+	if _, err := client.GetApp("missing"); err == nil || !strings.Contains(err.Error(), "Couldn't find that app.") {
+		t.Errorf("expected the error message of the API, got %v", err)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	app := &heroku.App{
+		Name: "shop",
+		ConfigVars: map[string]string{
+			"DATABASE_URL": "postgres://heroku",
+		},
+		Formation: []heroku.Formation{
+			{Type: "worker", Command: "npm run worker", Quantity: 0, Size: "Performance-M"},
+			{Type: "web", Command: "npm start", Quantity: 2, Size: "Standard-2X"},
+			{Type: "clock", Command: "npm run clock", Quantity: 1, Size: "Custom"},
+		},
+		Addons: []heroku.Addon{
+			{Name: "postgresql-curly-12345", ConfigVars: []string{"DATABASE_URL"}},
+			{Name: "papertrail-round-12345"},
+		},
+		Buildpacks: []heroku.BuildpackInstallation{
+			{Ordinal: 1},
+			{Ordinal: 0},
+		},
+	}
+
+	app.Addons[0].AddonService.Name = "heroku-postgresql"
+	app.Addons[1].AddonService.Name = "papertrail"
+	app.Buildpacks[0].Buildpack.URL = "https://github.com/heroku/heroku-buildpack-pgbouncer"
+	app.Buildpacks[1].Buildpack.Name = "heroku/nodejs"
+
+	plan, err := heroku.Translate(app, &heroku.Options{Name: "store"})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := make([]string, 0)
+
+	for _, rel := range plan.Releases {
+		names = append(names, rel.Name)
+	}
+
+	if expected := []string{"store", "store-clock", "store-worker"}; !reflect.DeepEqual(expected, names) {
+		t.Fatalf("incorrect releases: expected %v, got %v", expected, names)
+	}
+
+	expectedWeb := map[string]interface{}{
+		"container": map[string]interface{}{
+			"command": "npm start",
+			"port":    heroku.DefaultPort,
+			"env": map[string]interface{}{
+				"normal": map[string]interface{}{
+					"PORT": "8080",
+				},
+			},
+		},
+		"replicaCount": 2,
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{
+				"memory": "1024Mi",
+			},
+		},
+	}
+
+	if web := plan.Releases[0]; web.Kind != "web" || !reflect.DeepEqual(expectedWeb, web.Values) {
+		t.Errorf("incorrect web release: %+v", web)
+	}
+
+	if worker := plan.Releases[2]; worker.Kind != "worker" || worker.Values["replicaCount"] != 0 {
+		t.Errorf("incorrect worker release: %+v", worker)
+	}
+
+	if plan.EnvGroup == nil || plan.EnvGroup.Name != "store-env" || plan.EnvGroup.Variables["DATABASE_URL"] != "postgres://heroku" {
+		t.Errorf("incorrect env group: %+v", plan.EnvGroup)
+	}
+
+	if len(plan.Addons) != 2 || plan.Addons[0].Service != "papertrail" || plan.Addons[0].Template != "" ||
+		plan.Addons[1].Template != "postgresql" || !strings.Contains(plan.Addons[1].Suggestion, "DATABASE_URL") {
+		t.Errorf("incorrect add-on suggestions: %+v", plan.Addons)
+	}
+
+	if expected := []string{"heroku/nodejs"}; !reflect.DeepEqual(expected, plan.BuildConfig.Buildpacks) {
+		t.Errorf("incorrect buildpacks: %v", plan.BuildConfig.Buildpacks)
+	}
+
+	if len(plan.Notes) != 3 {
+		t.Errorf("expected notes for the scaled down worker, the custom dyno size and the buildpack URL, got %v", plan.Notes)
+	}
+}
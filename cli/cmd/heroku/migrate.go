@@ -0,0 +1,287 @@
+package heroku
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/imagemirror"
+	"github.com/porter-dev/porter/internal/integrations/buildpacks"
+)
+
+// DefaultPort is the port that web processes listen on, through the PORT env var, if the
+// app does not set the PORT config var
+const DefaultPort = 8080
+
+// dynoMemory is the memory of each dyno size, which is requested by the release of a
+// process type
+var dynoMemory = map[string]string{
+	"free":          "512Mi",
+	"hobby":         "512Mi",
+	"eco":           "512Mi",
+	"basic":         "512Mi",
+	"standard-1x":   "512Mi",
+	"standard-2x":   "1024Mi",
+	"performance-m": "2560Mi",
+	"performance-l": "14336Mi",
+	"private-s":     "1024Mi",
+	"private-m":     "2560Mi",
+	"private-l":     "14336Mi",
+	"shield-s":      "1024Mi",
+	"shield-m":      "2560Mi",
+	"shield-l":      "14336Mi",
+}
+
+// addonTemplates are the Porter add-ons that replace Heroku add-ons, keyed by the name of
+// the add-on service
+var addonTemplates = map[string]string{
+	"heroku-postgresql":  "postgresql",
+	"heroku-redis":       "redis",
+	"rediscloud":         "redis",
+	"redistogo":          "redis",
+	"mongolab":           "mongodb",
+	"ormongo":            "mongodb",
+	"cleardb":            "mysql",
+	"jawsdb":             "mysql",
+	"jawsdb-maria":       "mysql",
+	"cloudamqp":          "rabbitmq",
+	"bonsai":             "elasticsearch",
+	"searchbox":          "elasticsearch",
+	"foundelasticsearch": "elasticsearch",
+	"logdna":             "logdna",
+}
+
+// Options configure how a Heroku app is translated
+type Options struct {
+	// Name is the name of the release of the web process, and the prefix of the names of
+	// the other releases and of the env group. It defaults to the name of the app.
+	Name string
+}
+
+// Release is a Porter release that is created for a process type
+type Release struct {
+	ProcessType string
+	Name        string
+	Kind        string
+	Values      map[string]interface{}
+}
+
+// EnvGroup is the env group that is created for the config vars of the app
+type EnvGroup struct {
+	Name      string
+	Variables map[string]string
+}
+
+// AddonSuggestion suggests how to replace an add-on of the app on Porter
+type AddonSuggestion struct {
+	Name    string
+	Service string
+	Plan    string
+
+	// Template is the Porter add-on that replaces the add-on, if there is one
+	Template   string
+	Suggestion string
+}
+
+// Plan is the translation of a Heroku app
+type Plan struct {
+	// Releases are sorted so that the web process comes first
+	Releases []*Release
+	EnvGroup *EnvGroup
+	Addons   []*AddonSuggestion
+
+	// BuildConfig builds the app with the buildpacks of the app on Heroku
+	BuildConfig *types.BuildConfig
+
+	// Notes are the parts of the app that could not be translated
+	Notes []string
+}
+
+// Translate translates the formation, config vars, add-ons and buildpacks of a Heroku app
+// into Porter releases, an env group and add-on suggestions
+func Translate(app *App, opts *Options) (*Plan, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	name := opts.Name
+
+	if name == "" {
+		name = app.Name
+	}
+
+	if len(app.Formation) == 0 {
+		return nil, fmt.Errorf("app %s has no process types", app.Name)
+	}
+
+	plan := &Plan{
+		Releases: make([]*Release, 0),
+		Addons:   make([]*AddonSuggestion, 0),
+		Notes:    make([]string, 0),
+	}
+
+	envGroupName := name + "-env"
+
+	if len(app.ConfigVars) > 0 {
+		plan.EnvGroup = &EnvGroup{
+			Name:      envGroupName,
+			Variables: app.ConfigVars,
+		}
+	}
+
+	processes := make(map[string]string)
+	formations := make(map[string]Formation)
+
+	for _, formation := range app.Formation {
+		processes[formation.Type] = formation.Command
+		formations[formation.Type] = formation
+	}
+
+	for _, processType := range buildpacks.SortProcessTypes(processes) {
+		rel, err := plan.translateFormation(name, formations[processType], app.ConfigVars)
+
+		if err != nil {
+			return nil, fmt.Errorf("process type %s: %w", processType, err)
+		}
+
+		plan.Releases = append(plan.Releases, rel)
+	}
+
+	for _, addon := range app.Addons {
+		plan.Addons = append(plan.Addons, suggestAddon(addon, envGroupName))
+	}
+
+	sort.Slice(plan.Addons, func(i, j int) bool {
+		return plan.Addons[i].Name < plan.Addons[j].Name
+	})
+
+	plan.BuildConfig = plan.translateBuildpacks(app.Buildpacks)
+
+	return plan, nil
+}
+
+func (p *Plan) translateFormation(name string, formation Formation, configVars map[string]string) (*Release, error) {
+	rel := &Release{
+		ProcessType: formation.Type,
+		Name:        name,
+		Kind:        buildpacks.ProcessKind(formation.Type),
+		Values:      make(map[string]interface{}),
+	}
+
+	if formation.Type != "web" {
+		rel.Name = fmt.Sprintf("%s-%s", name, strings.ToLower(strings.ReplaceAll(formation.Type, "_", "-")))
+	}
+
+	container := map[string]interface{}{
+		"command": formation.Command,
+	}
+
+	// web processes listen on the port that is set by the PORT env var
+	if rel.Kind == "web" {
+		port := DefaultPort
+
+		if portVar, ok := configVars["PORT"]; ok {
+			var err error
+
+			if port, err = strconv.Atoi(portVar); err != nil {
+				return nil, fmt.Errorf("the PORT config var must be a number")
+			}
+		} else {
+			container["env"] = map[string]interface{}{
+				"normal": map[string]interface{}{
+					"PORT": strconv.Itoa(port),
+				},
+			}
+		}
+
+		container["port"] = port
+	}
+
+	rel.Values["container"] = container
+
+	if rel.Kind != "job" {
+		rel.Values["replicaCount"] = formation.Quantity
+
+		if formation.Quantity == 0 {
+			p.Notes = append(p.Notes, fmt.Sprintf("%s is scaled to zero dynos on Heroku, so %s is created without replicas", formation.Type, rel.Name))
+		}
+	}
+
+	if memory, ok := dynoMemory[strings.ToLower(formation.Size)]; ok {
+		rel.Values["resources"] = map[string]interface{}{
+			"requests": map[string]interface{}{
+				"memory": memory,
+			},
+		}
+	} else if formation.Size != "" {
+		p.Notes = append(p.Notes, fmt.Sprintf("the %s dyno size of %s is unknown, so %s uses the default resources", formation.Size, formation.Type, rel.Name))
+	}
+
+	return rel, nil
+}
+
+// translateBuildpacks returns the build config of the app. Heroku buildpacks that are
+// referenced by name, such as heroku/nodejs, are also available as Cloud Native
+// Buildpacks, while buildpacks that are referenced by URL are not.
+func (p *Plan) translateBuildpacks(buildpackInstallations []BuildpackInstallation) *types.BuildConfig {
+	installations := append([]BuildpackInstallation{}, buildpackInstallations...)
+
+	sort.Slice(installations, func(i, j int) bool {
+		return installations[i].Ordinal < installations[j].Ordinal
+	})
+
+	res := &types.BuildConfig{
+		Builder:    imagemirror.DefaultHerokuBuilder,
+		Buildpacks: make([]string, 0),
+	}
+
+	for _, installation := range installations {
+		bp := installation.Buildpack.Name
+
+		if bp == "" {
+			bp = installation.Buildpack.URL
+		}
+
+		if strings.HasPrefix(bp, "heroku/") {
+			res.Buildpacks = append(res.Buildpacks, bp)
+		} else {
+			p.Notes = append(p.Notes, fmt.Sprintf("the buildpack %s is not a Cloud Native Buildpack, so it is not used", bp))
+		}
+	}
+
+	return res
+}
+
+func suggestAddon(addon Addon, envGroupName string) *AddonSuggestion {
+	res := &AddonSuggestion{
+		Name:     addon.Name,
+		Service:  addon.AddonService.Name,
+		Plan:     addon.Plan.Name,
+		Template: addonTemplates[addon.AddonService.Name],
+	}
+
+	configVars := "its config vars"
+
+	if len(addon.ConfigVars) > 0 {
+		configVars = strings.Join(addon.ConfigVars, ", ")
+	}
+
+	switch {
+	case res.Service == "scheduler":
+		res.Suggestion = "create a job with a cron schedule for each scheduled task, with \"porter create job\""
+	case res.Template != "":
+		res.Suggestion = fmt.Sprintf(
+			"deploy the %s add-on on Porter, migrate its data, and update %s in the env group %s",
+			res.Template, configVars, envGroupName,
+		)
+	default:
+		res.Suggestion = fmt.Sprintf(
+			"there is no Porter add-on for %s: keep using it through %s, or replace it with an external service",
+			res.Service, configVars,
+		)
+	}
+
+	return res
+}
@@ -0,0 +1,775 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/compose"
+	"github.com/porter-dev/porter/cli/cmd/deploy"
+	"github.com/porter-dev/porter/cli/cmd/heroku"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the "porter import" base command when called
+// without any subcommands
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Commands that import applications from other platforms",
+}
+
+var importComposeCmd = &cobra.Command{
+	Use:   "compose",
+	Args:  cobra.NoArgs,
+	Short: "Creates applications and env groups from the services of a docker-compose file.",
+	Long: fmt.Sprintf(`
+%s
+
+Creates a Porter application for each service of a docker-compose file. For example:
+
+  %s
+
+Services with published ports are created as web applications, and services that only expose
+ports to other services are created as web applications that are only reachable inside the
+cluster. All other services are created as workers. Services with a build context are built
+with the local Docker daemon, and all other services are deployed from their image.
+
+The environment of each service is stored in an env group named {app}-env, which is synced
+to the application. Variables without a value are read from the current shell. The first
+named volume of a service is mounted as a persistent volume, and applications are created
+after the applications they depend on.
+
+Fields that cannot be translated, such as bind mounts, health checks and networks, are
+listed in a report before the applications are created. To only print the report and the
+applications that would be created, use the --dry-run flag:
+
+  %s
+
+Use the --prefix flag to prepend a prefix to the names of the applications and env groups:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter import compose\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter import compose -f docker-compose.yml"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter import compose -f docker-compose.yml --dry-run"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter import compose -f docker-compose.yml --prefix shop- --namespace shop"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, importCompose)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var importHerokuCmd = &cobra.Command{
+	Use:   "heroku",
+	Args:  cobra.NoArgs,
+	Short: "Creates applications and an env group from a Heroku app.",
+	Long: fmt.Sprintf(`
+%s
+
+Reads the formation, config vars, add-ons and buildpacks of a Heroku app through the Heroku
+Platform API, and creates a Porter application for each process type. The API token is read
+from the HEROKU_API_KEY environment variable, such as the token printed by "heroku auth:token",
+or from the --heroku-token flag. For example:
+
+  %s
+
+The web process is created as a web application with the name given by --app, which defaults
+to the name of the Heroku app, and all other processes as workers named {app}-{process}. The
+number and size of the dynos of each process are translated into replicas and memory requests.
+The config vars are stored as secrets in an env group named {app}-env, which is synced to the
+applications.
+
+By default, the application at the local path given by --path is built once with Cloud Native
+Buildpacks, using the buildpacks of the Heroku app, and the image is shared by all applications.
+To deploy an existing image instead, use "--source registry":
+
+  %s
+
+Add-ons are not migrated. Instead, a suggestion is printed for each add-on, such as the Porter
+add-on that replaces it. To only print the applications, env group and suggestions, use the
+--dry-run flag:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter import heroku\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter import heroku --heroku-app example-app --path ./example-app"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter import heroku --heroku-app example-app --source registry --image gcr.io/snowflake-12345/example-app:latest"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter import heroku --heroku-app example-app --dry-run"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, importHeroku)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var importReleaseCmd = &cobra.Command{
+	Use:   "release [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Adopts a Helm release that was not deployed by Porter.",
+	Long: fmt.Sprintf(`
+%s
+
+Adopts a Helm release that was installed outside of Porter, such as with the Helm CLI, so that
+it is managed by Porter and can be upgraded and rolled back like other applications. For example:
+
+  %s
+
+If the chart of the release matches a Porter template, the template is printed. To upgrade the
+release to the chart of the template, at the same version and with the same values, so that
+later upgrades use the template, use the --use-template flag:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter import release\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter import release example-app --namespace default"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter import release example-app --use-template"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, importRelease)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var importManifestsCmd = &cobra.Command{
+	Use:   "manifests",
+	Args:  cobra.NoArgs,
+	Short: "Adopts objects that were applied from raw manifests into a new application.",
+	Long: fmt.Sprintf(`
+%s
+
+Adopts existing objects, which were applied from raw Kubernetes manifests such as with
+"kubectl apply", into a new application that is managed by Porter. For example:
+
+  %s
+
+Every object of the manifests must already exist in the namespace of the application. The
+objects are not changed, except for the labels and annotations that mark them as objects of
+the application, and the application can then be upgraded and rolled back through Porter.
+The application is created from the current state of the objects in the cluster, so the
+manifests only need to identify them.
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter import manifests\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter import manifests -f ./k8s/web.yaml --app web --namespace default"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, importManifests)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var composeFile string
+var importPrefix string
+var importDryRun bool
+var herokuApp string
+var herokuToken string
+var importSource string
+var useTemplate bool
+var manifestsFile string
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importComposeCmd)
+	importCmd.AddCommand(importHerokuCmd)
+	importCmd.AddCommand(importReleaseCmd)
+	importCmd.AddCommand(importManifestsCmd)
+
+	importComposeCmd.Flags().StringVarP(
+		&composeFile,
+		"file",
+		"f",
+		"docker-compose.yml",
+		"path to the docker-compose file",
+	)
+
+	importComposeCmd.Flags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"namespace to create the applications and env groups in",
+	)
+
+	importComposeCmd.Flags().StringVar(
+		&importPrefix,
+		"prefix",
+		"",
+		"prefix of the names of the applications and env groups",
+	)
+
+	importComposeCmd.Flags().StringVar(
+		&registryURL,
+		"registry-url",
+		"",
+		"the registry URL to push built images to (must exist in \"porter registries list\")",
+	)
+
+	importComposeCmd.Flags().BoolVar(
+		&importDryRun,
+		"dry-run",
+		false,
+		"only print the applications and env groups that would be created",
+	)
+
+	importHerokuCmd.Flags().StringVar(
+		&herokuApp,
+		"heroku-app",
+		"",
+		"name of the Heroku app to import",
+	)
+
+	importHerokuCmd.MarkFlagRequired("heroku-app")
+
+	importHerokuCmd.Flags().StringVar(
+		&herokuToken,
+		"heroku-token",
+		"",
+		"Heroku API token, which defaults to the HEROKU_API_KEY environment variable",
+	)
+
+	importHerokuCmd.Flags().StringVar(
+		&name,
+		"app",
+		"",
+		"name of the web application, and prefix of the names of the other applications and the env group",
+	)
+
+	importHerokuCmd.Flags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"namespace to create the applications and env group in",
+	)
+
+	importHerokuCmd.Flags().StringVar(
+		&importSource,
+		"source",
+		"local",
+		"the type of source (\"local\" or \"registry\")",
+	)
+
+	importHerokuCmd.Flags().StringVarP(
+		&localPath,
+		"path",
+		"p",
+		"",
+		"if the source is \"local\", the path to the application to build, which defaults to the current directory",
+	)
+
+	importHerokuCmd.Flags().StringVar(
+		&image,
+		"image",
+		"",
+		"if the source is \"registry\", the image to use, in repository:tag format",
+	)
+
+	importHerokuCmd.Flags().StringVar(
+		&registryURL,
+		"registry-url",
+		"",
+		"the registry URL to push the built image to (must exist in \"porter registries list\")",
+	)
+
+	importHerokuCmd.Flags().BoolVar(
+		&importDryRun,
+		"dry-run",
+		false,
+		"only print the applications, env group and add-on suggestions",
+	)
+
+	importReleaseCmd.Flags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"namespace of the release",
+	)
+
+	importReleaseCmd.Flags().BoolVar(
+		&useTemplate,
+		"use-template",
+		false,
+		"upgrade the release to the chart of the matching Porter template",
+	)
+
+	importManifestsCmd.Flags().StringVarP(
+		&manifestsFile,
+		"file",
+		"f",
+		"",
+		"path to the YAML or JSON manifests of the objects",
+	)
+
+	importManifestsCmd.MarkFlagRequired("file")
+
+	importManifestsCmd.Flags().StringVar(
+		&name,
+		"app",
+		"",
+		"name of the application to create",
+	)
+
+	importManifestsCmd.MarkFlagRequired("app")
+
+	importManifestsCmd.Flags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"namespace of the objects",
+	)
+}
+
+func importCompose(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	composePath, err := filepath.Abs(composeFile)
+
+	if err != nil {
+		return err
+	}
+
+	fileBytes, err := ioutil.ReadFile(composePath)
+
+	if err != nil {
+		return fmt.Errorf("could not read compose file: %w", err)
+	}
+
+	plan, err := compose.Translate(fileBytes, &compose.Options{
+		Prefix:    importPrefix,
+		LookupEnv: os.LookupEnv,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	printComposePlan(plan)
+
+	if importDryRun {
+		return nil
+	}
+
+	envGroups := make(map[string]*types.EnvGroup)
+
+	for _, eg := range plan.EnvGroups {
+		color.New(color.FgGreen).Printf("Creating env group: %s\n", eg.Name)
+
+		envGroup, err := client.CreateEnvGroup(
+			context.Background(),
+			config.Project,
+			config.Cluster,
+			namespace,
+			&types.CreateEnvGroupRequest{
+				Name:            eg.Name,
+				Variables:       eg.Variables,
+				SecretVariables: make(map[string]string),
+			},
+		)
+
+		if err != nil {
+			return fmt.Errorf("error creating env group %s: %w", eg.Name, err)
+		}
+
+		envGroups[eg.Name] = envGroup
+	}
+
+	for _, rel := range plan.Releases {
+		color.New(color.FgGreen).Printf("Creating %s release for service %s: %s\n", rel.Kind, rel.Service, rel.Name)
+
+		if err := createComposeRelease(client, filepath.Dir(composePath), rel, envGroups[rel.EnvGroup]); err != nil {
+			return fmt.Errorf("error creating release for service %s: %w", rel.Service, err)
+		}
+	}
+
+	return nil
+}
+
+// createComposeRelease creates the release of a service, and syncs the env group of the
+// service to the release
+func createComposeRelease(client *api.Client, composeDir string, rel *compose.Release, envGroup *types.EnvGroup) error {
+	values := copyValues(rel.Values)
+
+	if envGroup != nil {
+		setSyncedEnvGroup(values, envGroup)
+	}
+
+	createAgent := &deploy.CreateAgent{
+		Client: client,
+		CreateOpts: &deploy.CreateOpts{
+			SharedOpts: &deploy.SharedOpts{
+				ProjectID: config.Project,
+				ClusterID: config.Cluster,
+				Namespace: namespace,
+			},
+			Kind:        rel.Kind,
+			ReleaseName: rel.Name,
+			RegistryURL: registryURL,
+		},
+	}
+
+	if rel.Internal {
+		createAgent.CreateOpts.Exposure = &types.ServiceExposure{
+			Protocol: types.ServiceProtocolHTTP,
+			Mode:     types.ExposureModeInternal,
+		}
+	}
+
+	var subdomain string
+	var err error
+
+	if rel.Build != nil {
+		buildPath := filepath.FromSlash(rel.Build.Context)
+
+		if !filepath.IsAbs(buildPath) {
+			buildPath = filepath.Join(composeDir, buildPath)
+		}
+
+		createAgent.CreateOpts.LocalPath = buildPath
+		createAgent.CreateOpts.LocalDockerfile = filepath.Join(buildPath, filepath.FromSlash(rel.Build.Dockerfile))
+		createAgent.CreateOpts.Method = deploy.DeployBuildTypeDocker
+		createAgent.CreateOpts.AdditionalEnv = rel.Build.Args
+
+		subdomain, err = createAgent.CreateFromDocker(values, "default", nil)
+	} else {
+		subdomain, err = createAgent.CreateFromRegistry(rel.Image, values)
+	}
+
+	if err = handleSubdomainCreate(subdomain, err); err != nil {
+		return err
+	}
+
+	if err := printInternalEndpoints(client, createAgent); err != nil {
+		return err
+	}
+
+	if envGroup == nil {
+		return nil
+	}
+
+	return addEnvGroupApplication(client, envGroup, rel.Name)
+}
+
+// setSyncedEnvGroup syncs an env group to a release by setting the synced env section of
+// its values
+func setSyncedEnvGroup(values map[string]interface{}, envGroup *types.EnvGroup) {
+	keys := make([]string, 0, len(envGroup.Variables))
+
+	for key := range envGroup.Variables {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	syncedKeys := make([]interface{}, 0, len(keys))
+
+	for _, key := range keys {
+		syncedKeys = append(syncedKeys, map[string]interface{}{
+			"name":   key,
+			"secret": strings.Contains(envGroup.Variables[key], "PORTERSECRET"),
+		})
+	}
+
+	container, _ := values["container"].(map[string]interface{})
+
+	if container == nil {
+		container = make(map[string]interface{})
+		values["container"] = container
+	}
+
+	env, _ := container["env"].(map[string]interface{})
+
+	if env == nil {
+		env = make(map[string]interface{})
+		container["env"] = env
+	}
+
+	env["synced"] = []interface{}{
+		map[string]interface{}{
+			"name":    envGroup.Name,
+			"version": envGroup.Version,
+			"keys":    syncedKeys,
+		},
+	}
+}
+
+// addEnvGroupApplication adds a release to the applications of an env group, so that the
+// release is redeployed when the env group is updated
+func addEnvGroupApplication(client *api.Client, envGroup *types.EnvGroup, releaseName string) error {
+	_, err := client.AddEnvGroupApplication(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		namespace,
+		&types.AddEnvGroupApplicationRequest{
+			Name:            envGroup.Name,
+			ApplicationName: releaseName,
+		},
+	)
+
+	return err
+}
+
+func printComposePlan(plan *compose.Plan) {
+	color.New(color.FgBlue, color.Bold).Println("Applications:")
+
+	for _, rel := range plan.Releases {
+		source := rel.Image
+
+		if rel.Build != nil {
+			source = fmt.Sprintf("built from %s", rel.Build.Context)
+		}
+
+		details := []string{rel.Kind, source}
+
+		if rel.Internal {
+			details = append(details, "internal")
+		}
+
+		if rel.EnvGroup != "" {
+			details = append(details, fmt.Sprintf("env group %s", rel.EnvGroup))
+		}
+
+		if len(rel.DependsOn) > 0 {
+			details = append(details, fmt.Sprintf("depends on %s", strings.Join(rel.DependsOn, ", ")))
+		}
+
+		fmt.Printf("  %s (%s)\n", rel.Name, strings.Join(details, ", "))
+	}
+
+	if len(plan.Unsupported) == 0 {
+		return
+	}
+
+	color.New(color.FgYellow, color.Bold).Println("The following fields are not supported and will be ignored:")
+
+	for _, field := range plan.Unsupported {
+		color.New(color.FgYellow).Printf("  %s\n", field)
+	}
+}
+
+func importHeroku(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	if importSource != "local" && importSource != "registry" {
+		return fmt.Errorf("%s is not a supported source: specify local or registry", importSource)
+	}
+
+	if importSource == "registry" && image == "" {
+		return fmt.Errorf("the --image flag must be set if the source is registry")
+	}
+
+	if herokuToken == "" {
+		herokuToken = os.Getenv("HEROKU_API_KEY")
+	}
+
+	if herokuToken == "" {
+		return fmt.Errorf("a Heroku API token must be set with HEROKU_API_KEY or --heroku-token")
+	}
+
+	app, err := heroku.NewClient(herokuToken).GetApp(herokuApp)
+
+	if err != nil {
+		return fmt.Errorf("could not read Heroku app %s: %w", herokuApp, err)
+	}
+
+	plan, err := heroku.Translate(app, &heroku.Options{
+		Name: name,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	printHerokuPlan(plan)
+
+	if importDryRun {
+		return nil
+	}
+
+	var envGroup *types.EnvGroup
+
+	if plan.EnvGroup != nil {
+		color.New(color.FgGreen).Printf("Creating env group: %s\n", plan.EnvGroup.Name)
+
+		// config vars often contain credentials, so they are stored as secrets
+		envGroup, err = client.CreateEnvGroup(
+			context.Background(),
+			config.Project,
+			config.Cluster,
+			namespace,
+			&types.CreateEnvGroupRequest{
+				Name:            plan.EnvGroup.Name,
+				Variables:       make(map[string]string),
+				SecretVariables: plan.EnvGroup.Variables,
+			},
+		)
+
+		if err != nil {
+			return fmt.Errorf("error creating env group %s: %w", plan.EnvGroup.Name, err)
+		}
+	}
+
+	fullPath, err := filepath.Abs(localPath)
+
+	if err != nil {
+		return err
+	}
+
+	sharedImage := image
+
+	for _, rel := range plan.Releases {
+		color.New(color.FgGreen).Printf("Creating %s release for process %s: %s\n", rel.Kind, rel.ProcessType, rel.Name)
+
+		values := copyValues(rel.Values)
+
+		if envGroup != nil {
+			setSyncedEnvGroup(values, envGroup)
+		}
+
+		createAgent := &deploy.CreateAgent{
+			Client: client,
+			CreateOpts: &deploy.CreateOpts{
+				SharedOpts: &deploy.SharedOpts{
+					ProjectID: config.Project,
+					ClusterID: config.Cluster,
+					Namespace: namespace,
+					LocalPath: fullPath,
+					Method:    deploy.DeployBuildTypePack,
+				},
+				Kind:        rel.Kind,
+				ReleaseName: rel.Name,
+				RegistryURL: registryURL,
+			},
+		}
+
+		// the image is built once for the first release, and shared by the other releases
+		if sharedImage == "" {
+			subdomain, createErr := createAgent.CreateFromDocker(values, "default", plan.BuildConfig)
+
+			if err = handleSubdomainCreate(subdomain, createErr); err == nil {
+				_, imageURL, imageErr := createAgent.GetImageRepoURL(rel.Name, namespace)
+
+				if imageErr != nil {
+					return imageErr
+				}
+
+				sharedImage = fmt.Sprintf("%s:default", imageURL)
+			}
+		} else {
+			subdomain, createErr := createAgent.CreateFromRegistry(sharedImage, values)
+
+			err = handleSubdomainCreate(subdomain, createErr)
+		}
+
+		if err == nil && envGroup != nil {
+			err = addEnvGroupApplication(client, envGroup, rel.Name)
+		}
+
+		if err != nil {
+			return fmt.Errorf("error creating release for process %s: %w", rel.ProcessType, err)
+		}
+	}
+
+	return nil
+}
+
+func printHerokuPlan(plan *heroku.Plan) {
+	color.New(color.FgBlue, color.Bold).Println("Applications:")
+
+	for _, rel := range plan.Releases {
+		fmt.Printf("  %s (%s, process %s)\n", rel.Name, rel.Kind, rel.ProcessType)
+	}
+
+	if plan.EnvGroup != nil {
+		color.New(color.FgBlue, color.Bold).Println("Env group:")
+		fmt.Printf("  %s (%d config vars)\n", plan.EnvGroup.Name, len(plan.EnvGroup.Variables))
+	}
+
+	if len(plan.Addons) > 0 {
+		color.New(color.FgBlue, color.Bold).Println("Add-ons:")
+
+		for _, addon := range plan.Addons {
+			fmt.Printf("  %s (%s): %s\n", addon.Name, addon.Plan, addon.Suggestion)
+		}
+	}
+
+	if len(plan.Notes) > 0 {
+		color.New(color.FgYellow, color.Bold).Println("Notes:")
+
+		for _, note := range plan.Notes {
+			color.New(color.FgYellow).Printf("  %s\n", note)
+		}
+	}
+}
+
+func importRelease(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	resp, err := client.AdoptRelease(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		namespace,
+		args[0],
+		&types.AdoptReleaseRequest{
+			UseTemplate: useTemplate,
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("Release %s is now managed by Porter\n", args[0])
+
+	if resp.Template == nil {
+		fmt.Println("The chart of the release does not match a Porter template")
+	} else if resp.Template.Applied {
+		fmt.Printf("Upgraded the release to the %s template, version %s\n", resp.Template.Name, resp.Template.Version)
+	} else {
+		fmt.Printf(
+			"The chart of the release matches the %s template. To upgrade the release to the template, run \"porter import release %s --use-template\"\n",
+			resp.Template.Name, args[0],
+		)
+	}
+
+	return nil
+}
+
+func importManifests(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	fileBytes, err := ioutil.ReadFile(manifestsFile)
+
+	if err != nil {
+		return fmt.Errorf("could not read manifests: %w", err)
+	}
+
+	_, err = client.AdoptManifests(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		namespace,
+		&types.AdoptManifestsRequest{
+			Name:      name,
+			Manifests: string(fileBytes),
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("The objects of %s are now managed by Porter as the application %s\n", manifestsFile, name)
+
+	return nil
+}
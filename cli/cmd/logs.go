@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/fatih/color"
 	api "github.com/porter-dev/porter/api/client"
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/cli/cmd/utils"
@@ -16,6 +19,25 @@ var logsCmd = &cobra.Command{
 	Use:   "logs [release]",
 	Args:  cobra.ExactArgs(1),
 	Short: "Logs the output from a given application.",
+	Long: fmt.Sprintf(`
+%s
+
+Logs the output from a given application. By default, the logs of a single container are
+streamed from the cluster.
+
+If Loki is installed in the cluster, the --search flag searches the persisted logs of all
+pods of the application instead, including pods that no longer exist. For example:
+
+  %s
+
+Search results can be filtered by Loki stream labels and by time range:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter logs\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter logs web --search \"connection refused\""),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter logs web --search error --label container=web --since 24h"),
+	),
 	Run: func(cmd *cobra.Command, args []string) {
 		err := checkLoginAndRun(args, logs)
 
@@ -26,6 +48,10 @@ var logsCmd = &cobra.Command{
 }
 
 var follow bool
+var searchQuery string
+var searchLabels []string
+var searchSince time.Duration
+var searchLimit uint
 
 func init() {
 	rootCmd.AddCommand(logsCmd)
@@ -44,9 +70,41 @@ func init() {
 		false,
 		"specify if the logs should be streamed",
 	)
+
+	logsCmd.PersistentFlags().StringVar(
+		&searchQuery,
+		"search",
+		"",
+		"search the persisted logs of the application for lines containing this text (requires Loki in the cluster)",
+	)
+
+	logsCmd.PersistentFlags().StringArrayVar(
+		&searchLabels,
+		"label",
+		nil,
+		"filter searched logs by a Loki stream label, in the form 'KEY=VALUE'",
+	)
+
+	logsCmd.PersistentFlags().DurationVar(
+		&searchSince,
+		"since",
+		time.Hour,
+		"how far back to search the logs",
+	)
+
+	logsCmd.PersistentFlags().UintVar(
+		&searchLimit,
+		"limit",
+		1000,
+		"the maximum number of log lines to return from a search",
+	)
 }
 
 func logs(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	if searchQuery != "" {
+		return searchLogs(client, args[0])
+	}
+
 	podsSimple, err := getPods(client, namespace, args[0])
 
 	if err != nil {
@@ -112,3 +170,42 @@ func logs(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []stri
 
 	return err
 }
+
+func searchLogs(client *api.Client, releaseName string) error {
+	now := time.Now()
+
+	resp, err := client.SearchReleaseLogs(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		namespace,
+		releaseName,
+		&types.SearchReleaseLogsRequest{
+			Query:      searchQuery,
+			Labels:     searchLabels,
+			StartRange: uint(now.Add(-searchSince).Unix()),
+			EndRange:   uint(now.Unix()),
+			Limit:      searchLimit,
+			// search backward so that the most recent lines are returned if the limit
+			// is hit
+			Direction: "backward",
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("Could not search logs: %s", err.Error())
+	}
+
+	if len(resp.Lines) == 0 {
+		fmt.Println("No log lines matched the search.")
+		return nil
+	}
+
+	// print the lines in chronological order
+	for i := len(resp.Lines) - 1; i >= 0; i-- {
+		line := resp.Lines[i]
+		fmt.Printf("%s %s %s\n", line.Timestamp.Local().Format(time.RFC3339), line.Labels["pod"], line.Line)
+	}
+
+	return nil
+}
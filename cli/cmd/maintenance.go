@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/spf13/cobra"
+)
+
+var maintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: "Commands that put a web application into or out of maintenance mode.",
+	Long: fmt.Sprintf(`
+%s
+
+Puts a web application into maintenance mode, in which every request to the application is
+answered with a static maintenance page, or takes it out of maintenance mode. The application
+does not need to be redeployed. For example:
+
+  %s
+
+The message on the default maintenance page can be set with the --message flag, or a custom
+HTML page can be passed in with the --page flag:
+
+  %s
+
+To take the application out of maintenance mode:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter maintenance\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter maintenance enable --app example-app"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter maintenance enable --app example-app --page ./maintenance.html"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter maintenance disable --app example-app"),
+	),
+}
+
+var maintenanceEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Puts an application into maintenance mode.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, enableMaintenance)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var maintenanceDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Takes an application out of maintenance mode.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, disableMaintenance)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var maintenanceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Prints whether an application is in maintenance mode.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, getMaintenanceStatus)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var maintenanceMessage string
+var maintenancePagePath string
+
+func init() {
+	rootCmd.AddCommand(maintenanceCmd)
+	maintenanceCmd.AddCommand(maintenanceEnableCmd)
+	maintenanceCmd.AddCommand(maintenanceDisableCmd)
+	maintenanceCmd.AddCommand(maintenanceStatusCmd)
+
+	maintenanceCmd.PersistentFlags().StringVar(
+		&app,
+		"app",
+		"",
+		"Application in the Porter dashboard",
+	)
+
+	maintenanceCmd.MarkPersistentFlagRequired("app")
+
+	maintenanceCmd.PersistentFlags().StringVar(
+		&namespace,
+		"namespace",
+		"default",
+		"Namespace of the application",
+	)
+
+	maintenanceEnableCmd.PersistentFlags().StringVar(
+		&maintenanceMessage,
+		"message",
+		"",
+		"the message to show on the default maintenance page",
+	)
+
+	maintenanceEnableCmd.PersistentFlags().StringVar(
+		&maintenancePagePath,
+		"page",
+		"",
+		"filepath to a custom HTML maintenance page",
+	)
+}
+
+func enableMaintenance(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	req := &types.UpdateMaintenanceModeRequest{
+		Enabled: true,
+		Message: maintenanceMessage,
+	}
+
+	if maintenancePagePath != "" {
+		page, err := ioutil.ReadFile(maintenancePagePath)
+
+		if err != nil {
+			return fmt.Errorf("could not read maintenance page: %w", err)
+		}
+
+		req.Page = string(page)
+	}
+
+	resp, err := client.UpdateMaintenanceMode(context.Background(), config.Project, config.Cluster, namespace, app, req)
+
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("%s is now in maintenance mode (ingresses: %s)\n", app, strings.Join(resp.Ingresses, ", "))
+
+	return nil
+}
+
+func disableMaintenance(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	_, err := client.UpdateMaintenanceMode(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		namespace,
+		app,
+		&types.UpdateMaintenanceModeRequest{
+			Enabled: false,
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("%s is no longer in maintenance mode\n", app)
+
+	return nil
+}
+
+func getMaintenanceStatus(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	resp, err := client.GetMaintenanceMode(context.Background(), config.Project, config.Cluster, namespace, app)
+
+	if err != nil {
+		return err
+	}
+
+	if ok, err := printStructuredOutput(resp); ok {
+		return err
+	}
+
+	if resp.Enabled {
+		fmt.Printf("%s is in maintenance mode\n", app)
+	} else {
+		fmt.Printf("%s is not in maintenance mode\n", app)
+	}
+
+	return nil
+}
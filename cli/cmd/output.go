@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// outputFormat is the format that list and get commands print their results in. The
+// results are printed in a human-readable format if it is empty.
+var outputFormat string
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(
+		&outputFormat,
+		"output",
+		"o",
+		"",
+		"output format of list and get commands (\"json\" or \"yaml\")",
+	)
+
+	rootCmd.RegisterFlagCompletionFunc(
+		"output",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+}
+
+// printStructuredOutput prints a result as JSON or YAML if the --output flag is set. It
+// returns false if the result should be printed in the human-readable format instead.
+func printStructuredOutput(result interface{}) (bool, error) {
+	var bytes []byte
+	var err error
+
+	switch outputFormat {
+	case "":
+		return false, nil
+	case "json":
+		bytes, err = json.MarshalIndent(result, "", "  ")
+
+		if err == nil {
+			bytes = append(bytes, '\n')
+		}
+	case "yaml":
+		bytes, err = yaml.Marshal(result)
+	default:
+		return true, fmt.Errorf("unknown output format %s: must be either json or yaml", outputFormat)
+	}
+
+	if err != nil {
+		return true, err
+	}
+
+	fmt.Print(string(bytes))
+
+	return true, nil
+}
@@ -37,7 +37,7 @@ func (a *Agent) Build(opts *docker.BuildOpts, buildConfig *types.BuildConfig) er
 		RelativeBaseDir: filepath.Dir(absPath),
 		Image:           fmt.Sprintf("%s:%s", opts.ImageRepo, opts.Tag),
 		Builder:         "paketobuildpacks/builder:full",
-		AppPath:         opts.BuildContext,
+		AppPath:         absPath,
 		TrustBuilder:    true,
 		Env:             opts.Env,
 	}
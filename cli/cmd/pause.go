@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/spf13/cobra"
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Scales an application down to zero replicas.",
+	Long: fmt.Sprintf(`
+%s
+
+Pauses an application by scaling its deployments and statefulsets down to zero replicas and
+suspending its cron jobs. The previous replica counts are stored, and are restored when the
+application is resumed. For example:
+
+  %s
+
+To resume the application:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter pause\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter pause --app example-app"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter resume --app example-app"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, pauseApp)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Restores the replicas of an application that was paused.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, resumeApp)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{pauseCmd, resumeCmd} {
+		rootCmd.AddCommand(cmd)
+
+		cmd.PersistentFlags().StringVar(
+			&app,
+			"app",
+			"",
+			"Application in the Porter dashboard",
+		)
+
+		cmd.MarkPersistentFlagRequired("app")
+
+		cmd.PersistentFlags().StringVar(
+			&namespace,
+			"namespace",
+			"default",
+			"Namespace of the application",
+		)
+	}
+}
+
+func pauseApp(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	_, err := client.PauseRelease(context.Background(), config.Project, config.Cluster, namespace, app)
+
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("%s has been paused\n", app)
+
+	return nil
+}
+
+func resumeApp(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	_, err := client.ResumeRelease(context.Background(), config.Project, config.Cluster, namespace, app)
+
+	if err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("%s has been resumed\n", app)
+
+	return nil
+}
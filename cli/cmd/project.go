@@ -92,6 +92,10 @@ func listProjects(user *types.GetAuthenticatedUserResponse, client *api.Client,
 
 	projects := *resp
 
+	if ok, err := printStructuredOutput(projects); ok {
+		return err
+	}
+
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
 
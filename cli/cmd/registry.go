@@ -115,6 +115,10 @@ func listRegistries(user *types.GetAuthenticatedUserResponse, client *api.Client
 
 	registries := *resp
 
+	if ok, err := printStructuredOutput(registries); ok {
+		return err
+	}
+
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
 
@@ -184,6 +188,10 @@ func listRepos(user *types.GetAuthenticatedUserResponse, client *api.Client, arg
 
 	repos := *resp
 
+	if ok, err := printStructuredOutput(repos); ok {
+		return err
+	}
+
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
 
@@ -217,6 +225,10 @@ func listImages(user *types.GetAuthenticatedUserResponse, client *api.Client, ar
 
 	imgs := *resp
 
+	if ok, err := printStructuredOutput(imgs); ok {
+		return err
+	}
+
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 3, 8, 0, '\t', tabwriter.AlignRight)
 
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+)
+
+var waitForRollout bool
+var waitTimeout time.Duration
+
+// waitForReleaseRollout streams the rollout status of a release to stdout, and returns an
+// error if the rollout fails or does not complete within the wait timeout
+func waitForReleaseRollout(client *api.Client, namespace, releaseName string) error {
+	color.New(color.FgGreen).Printf("Waiting for the rollout of %s to complete...\n", releaseName)
+
+	readyCounts := make(map[string]string)
+
+	status, err := client.StreamRolloutStatus(
+		context.Background(),
+		config.Project,
+		config.Cluster,
+		namespace,
+		releaseName,
+		&types.StreamRolloutStatusRequest{
+			Timeout: uint(waitTimeout.Seconds()),
+		},
+		func(status *types.RolloutStatus) {
+			for _, controller := range status.Controllers {
+				key := fmt.Sprintf("%s/%s", controller.Kind, controller.Name)
+				readyCount := fmt.Sprintf(
+					"%d of %d updated replicas ready",
+					controller.ReadyReplicas,
+					controller.DesiredReplicas,
+				)
+
+				// only print the replica counts when they change
+				if readyCounts[key] != readyCount {
+					readyCounts[key] = readyCount
+					fmt.Printf("%s: %s\n", key, readyCount)
+				}
+			}
+
+			for _, event := range status.Events {
+				color.New(color.FgYellow).Printf("%s: %s: %s\n", event.Object, event.Reason, event.Message)
+			}
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not get rollout status: %w", err)
+	}
+
+	if status.Phase == types.RolloutSucceeded {
+		color.New(color.FgGreen).Printf("Rollout of %s completed successfully\n", releaseName)
+		return nil
+	}
+
+	reason := status.Error
+
+	for _, controller := range status.Controllers {
+		if controller.FailureReason != "" {
+			reason = controller.FailureReason
+			break
+		}
+	}
+
+	return fmt.Errorf("rollout of %s failed: %s", releaseName, reason)
+}
@@ -25,6 +25,10 @@ var rootCmd = &cobra.Command{
 
 var home = homedir.HomeDir()
 
+// freezeOverrideReason is sent with every request, so that project admins can override
+// active deploy freezes from any command that deploys or changes infra
+var freezeOverrideReason string
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -32,6 +36,15 @@ func Execute() {
 
 	rootCmd.PersistentFlags().AddFlagSet(defaultFlagSet)
 
+	rootCmd.PersistentFlags().StringVar(
+		&freezeOverrideReason,
+		"freeze-override-reason",
+		"",
+		"the reason for overriding an active deploy freeze, which only project admins can do",
+	)
+
+	registerFlagCompletions(rootCmd)
+
 	if Version != "dev" {
 		ghClient := github.NewClient(nil)
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -69,9 +82,15 @@ func Setup() {
 }
 
 func GetAPIClient(config *CLIConfig) *api.Client {
+	var client *api.Client
+
 	if token := config.Token; token != "" {
-		return api.NewClientWithToken(config.Host+"/api", token)
+		client = api.NewClientWithToken(config.Host+"/api", token)
+	} else {
+		client = api.NewClient(config.Host+"/api", "cookie.json")
 	}
 
-	return api.NewClient(config.Host+"/api", "cookie.json")
+	client.FreezeOverrideReason = freezeOverrideReason
+
+	return client
 }
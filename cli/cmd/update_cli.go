@@ -0,0 +1,423 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fatih/color"
+	ghrelease "github.com/google/go-github/v41/github"
+	api "github.com/porter-dev/porter/api/client"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/cli/cmd/github"
+	"github.com/spf13/cobra"
+)
+
+var updateCLICmd = &cobra.Command{
+	Use:   "update-cli",
+	Short: "Updates the Porter CLI to a new version.",
+	Long: fmt.Sprintf(`
+%s
+
+Downloads a release of the Porter CLI from Github and replaces the running binary with it. The
+SHA-256 checksum of the downloaded release is verified against the checksums that are published
+with the release. By default, the CLI version that is pinned for the current project is installed,
+or the latest release if no version is pinned:
+
+  %s
+
+To install a specific version:
+
+  %s
+
+Admins can pin the CLI version of a project, so that users of other versions are warned when they
+run a command:
+
+  %s
+`,
+		color.New(color.FgBlue, color.Bold).Sprintf("Help for \"porter update-cli\":"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter update-cli"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter update-cli --version v0.20.0"),
+		color.New(color.FgGreen, color.Bold).Sprintf("porter config pin-cli-version v0.20.0"),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := updateCLI()
+
+		if err != nil {
+			color.New(color.FgRed).Printf("An error occurred: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var pinCLIVersionCmd = &cobra.Command{
+	Use:   "pin-cli-version [version]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Pins the CLI version of the current project, or removes the pin if no version is given.",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkLoginAndRun(args, pinCLIVersion)
+
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var updateCLIVersion string
+var updateCLIForce bool
+
+func init() {
+	rootCmd.AddCommand(updateCLICmd)
+	configCmd.AddCommand(pinCLIVersionCmd)
+
+	updateCLICmd.PersistentFlags().StringVar(
+		&updateCLIVersion,
+		"version",
+		"",
+		"the version to install, such as v0.20.0",
+	)
+
+	updateCLICmd.PersistentFlags().BoolVar(
+		&updateCLIForce,
+		"force",
+		false,
+		"install the version even if it is already installed",
+	)
+}
+
+func updateCLI() error {
+	targetVersion, err := getUpdateCLITargetVersion()
+
+	if err != nil {
+		return err
+	}
+
+	if targetVersion == Version && !updateCLIForce {
+		color.New(color.FgGreen).Printf("Version %s of the Porter CLI is already installed\n", Version)
+		return nil
+	}
+
+	zipName, err := getCLIReleaseZipName(targetVersion)
+
+	if err != nil {
+		return err
+	}
+
+	exePath, err := os.Executable()
+
+	if err != nil {
+		return err
+	}
+
+	exePath, err = filepath.EvalSymlinks(exePath)
+
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := ioutil.TempDir("", "porter-update-cli")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(tmpDir)
+
+	releaseURL := fmt.Sprintf("https://github.com/porter-dev/porter/releases/download/%s", targetVersion)
+
+	checksums, err := getCLIReleaseChecksums(fmt.Sprintf("%s/porter_%s_checksums.txt", releaseURL, targetVersion))
+
+	if err != nil {
+		return err
+	}
+
+	expChecksum, ok := checksums[zipName]
+
+	if !ok {
+		return fmt.Errorf("no checksum is published for %s", zipName)
+	}
+
+	fmt.Printf("Downloading version %s of the Porter CLI\n", targetVersion)
+
+	downloader := &github.ZIPDownloader{
+		ZipFolderDest:   tmpDir,
+		ZipName:         zipName,
+		AssetFolderDest: tmpDir,
+	}
+
+	if err := downloader.DownloadToFile(fmt.Sprintf("%s/%s", releaseURL, zipName)); err != nil {
+		return err
+	}
+
+	checksum, err := getFileChecksum(filepath.Join(tmpDir, zipName))
+
+	if err != nil {
+		return err
+	}
+
+	if checksum != expChecksum {
+		return fmt.Errorf("checksum of %s does not match: expected %s, got %s", zipName, expChecksum, checksum)
+	}
+
+	if err := downloader.UnzipToDir(); err != nil {
+		return err
+	}
+
+	// the new binary is moved next to the current binary before replacing it, since a
+	// rename is atomic but only works within a filesystem
+	tmpExe, err := ioutil.TempFile(filepath.Dir(exePath), ".porter-update-cli")
+
+	if err != nil {
+		return fmt.Errorf("could not write to %s, try running the command with sudo: %w", filepath.Dir(exePath), err)
+	}
+
+	tmpExePath := tmpExe.Name()
+	defer os.Remove(tmpExePath)
+
+	newExe, err := os.Open(filepath.Join(tmpDir, getCLIBinaryName()))
+
+	if err != nil {
+		tmpExe.Close()
+		return err
+	}
+
+	defer newExe.Close()
+
+	_, err = io.Copy(tmpExe, newExe)
+	tmpExe.Close()
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpExePath, 0755); err != nil {
+		return err
+	}
+
+	if err := replaceExecutable(tmpExePath, exePath); err != nil {
+		return err
+	}
+
+	color.New(color.FgGreen).Printf("Successfully updated the Porter CLI to version %s\n", targetVersion)
+
+	return nil
+}
+
+// getUpdateCLITargetVersion returns the version passed via the --version flag, or the
+// version pinned for the current project, or the latest release
+func getUpdateCLITargetVersion() (string, error) {
+	if updateCLIVersion != "" {
+		return normalizeCLIVersion(updateCLIVersion)
+	}
+
+	if config.Project != 0 {
+		// the pinned version is best-effort, since the user may not be logged in
+		project, err := GetAPIClient(config).GetProject(context.Background(), config.Project)
+
+		if err == nil && project.CLIVersion != "" {
+			fmt.Printf("Using version %s, which is pinned for project %d\n", project.CLIVersion, config.Project)
+			return project.CLIVersion, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	release, _, err := ghrelease.NewClient(nil).Repositories.GetLatestRelease(ctx, "porter-dev", "porter")
+
+	if err != nil {
+		return "", fmt.Errorf("could not get the latest release: %w", err)
+	}
+
+	return release.GetTagName(), nil
+}
+
+func getCLIReleaseZipName(version string) (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		// the Darwin binaries also run on arm64 through Rosetta
+		return fmt.Sprintf("porter_%s_Darwin_x86_64.zip", version), nil
+	case "linux", "windows":
+		if runtime.GOARCH != "amd64" {
+			return "", fmt.Errorf("%s is not a supported architecture for Porter binaries", runtime.GOARCH)
+		}
+
+		if runtime.GOOS == "windows" {
+			return fmt.Sprintf("porter_%s_Windows_x86_64.zip", version), nil
+		}
+
+		return fmt.Sprintf("porter_%s_Linux_x86_64.zip", version), nil
+	}
+
+	return "", fmt.Errorf("%s is not a supported platform for Porter binaries", runtime.GOOS)
+}
+
+// getCLIBinaryName returns the name of the binary in the release zip
+func getCLIBinaryName() string {
+	if runtime.GOOS == "windows" {
+		return "porter.exe"
+	}
+
+	return "porter"
+}
+
+// replaceExecutable replaces the running binary with a new binary. Windows does not allow
+// the running binary to be replaced, but allows it to be renamed, so it is moved aside
+// first and removed by the next update.
+func replaceExecutable(newExePath, exePath string) error {
+	if runtime.GOOS != "windows" {
+		return os.Rename(newExePath, exePath)
+	}
+
+	oldExePath := exePath + ".old"
+
+	// the binary that was moved aside by the previous update is no longer running
+	os.Remove(oldExePath)
+
+	if err := os.Rename(exePath, oldExePath); err != nil {
+		return fmt.Errorf("could not move the running binary aside: %w", err)
+	}
+
+	if err := os.Rename(newExePath, exePath); err != nil {
+		// restore the running binary, so that the CLI is still installed
+		os.Rename(oldExePath, exePath)
+		return err
+	}
+
+	return nil
+}
+
+// getCLIReleaseChecksums downloads the checksums file of a release, which is in the
+// format of sha256sum, and returns the checksums keyed by file name
+func getCLIReleaseChecksums(url string) (map[string]string, error) {
+	resp, err := http.Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download the checksums of the release: status code %d", resp.StatusCode)
+	}
+
+	res := make(map[string]string)
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) == 2 {
+			res[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+		}
+	}
+
+	return res, scanner.Err()
+}
+
+func getFileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func pinCLIVersion(_ *types.GetAuthenticatedUserResponse, client *api.Client, args []string) error {
+	version := ""
+
+	if len(args) == 1 {
+		version = args[0]
+	}
+
+	project, err := client.UpdateProjectCLIVersion(
+		context.Background(),
+		config.Project,
+		&types.UpdateProjectCLIVersionRequest{
+			Version: version,
+		},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if project.CLIVersion == "" {
+		color.New(color.FgGreen).Printf("Removed the pinned CLI version of project %d\n", config.Project)
+	} else {
+		color.New(color.FgGreen).Printf("Pinned the CLI version of project %d to %s\n", config.Project, project.CLIVersion)
+	}
+
+	return nil
+}
+
+// checkCLIVersion warns the user if the version of the CLI is not supported by the
+// server, or differs from the version pinned for the current project. Errors are
+// ignored, since the check should never block a command.
+func checkCLIVersion(client *api.Client) {
+	if Version == "dev" {
+		return
+	}
+
+	version, err := semver.NewVersion(strings.TrimPrefix(Version, "v"))
+
+	if err != nil {
+		return
+	}
+
+	yellow := color.New(color.FgYellow)
+
+	if metadata, err := client.GetMetadata(context.Background()); err == nil {
+		if minVersion, err := semver.NewVersion(strings.TrimPrefix(metadata.MinCLIVersion, "v")); err == nil && version.LessThan(minVersion) {
+			yellow.Fprintf(os.Stderr, "Version %s of the Porter CLI is lower than the minimum version %s that the server supports, and commands may fail.\n", Version, metadata.MinCLIVersion)
+			yellow.Fprintf(os.Stderr, "Run \"porter update-cli --version %s\" to update.\n\n", metadata.MinCLIVersion)
+		} else if maxVersion, err := semver.NewVersion(strings.TrimPrefix(metadata.MaxCLIVersion, "v")); err == nil && version.GreaterThan(maxVersion) {
+			yellow.Fprintf(os.Stderr, "Version %s of the Porter CLI is higher than the maximum version %s that the server supports, and commands may fail.\n", Version, metadata.MaxCLIVersion)
+			yellow.Fprintf(os.Stderr, "Run \"porter update-cli --version %s\" to downgrade.\n\n", metadata.MaxCLIVersion)
+		}
+	}
+
+	if config.Project == 0 {
+		return
+	}
+
+	if project, err := client.GetProject(context.Background(), config.Project); err == nil && project.CLIVersion != "" {
+		pinnedVersion, err := semver.NewVersion(strings.TrimPrefix(project.CLIVersion, "v"))
+
+		if err == nil && !version.Equal(pinnedVersion) {
+			yellow.Fprintf(os.Stderr, "Project %d is pinned to version %s of the Porter CLI, but version %s is installed.\n", config.Project, project.CLIVersion, Version)
+			yellow.Fprint(os.Stderr, "Run \"porter update-cli\" to install the pinned version.\n\n")
+		}
+	}
+}
+
+func normalizeCLIVersion(version string) (string, error) {
+	parsedVersion, err := semver.NewVersion(strings.TrimPrefix(version, "v"))
+
+	if err != nil {
+		return "", fmt.Errorf("invalid CLI version %s: %w", version, err)
+	}
+
+	return "v" + parsedVersion.String(), nil
+}
@@ -1,16 +1,28 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
+	redis "github.com/go-redis/redis/v8"
+	"github.com/porter-dev/porter/api/server/handlers/release"
 	"github.com/porter-dev/porter/api/server/router"
 	"github.com/porter-dev/porter/api/server/shared/config/loader"
+	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/adapter"
+	"github.com/porter-dev/porter/internal/addons"
+	"github.com/porter-dev/porter/internal/billing"
+	"github.com/porter-dev/porter/internal/gitops"
+	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/redis_stream"
+	"github.com/porter-dev/porter/internal/redis_stream/lease"
+	"github.com/porter-dev/porter/internal/stale"
+	"helm.sh/helm/v3/pkg/chart"
 )
 
 // Version will be linked by an ldflag during build
@@ -35,19 +47,124 @@ func main() {
 		log.Fatal("Config loading failed: ", err)
 	}
 
+	var redisClient *redis.Client
+
 	if config.RedisConf.Enabled {
-		redis, err := adapter.NewRedisClient(config.RedisConf)
+		redisClient, err = adapter.NewRedisClient(config.RedisConf)
 
 		if err != nil {
 			config.Logger.Fatal().Err(err).Msg("redis connection failed")
 			return
 		}
 
-		redis_stream.InitGlobalStream(redis)
+		redis_stream.InitGlobalStream(redisClient)
 
 		errorChan := make(chan error)
 
-		go redis_stream.GlobalStreamListener(redis, config, config.Repo, config.AnalyticsClient, errorChan)
+		go redis_stream.GlobalStreamListener(redisClient, config, config.Repo, config.AnalyticsClient, errorChan)
+
+		if config.ProvisionerLeases != nil {
+			go config.ProvisionerLeases.Heartbeat(context.Background())
+			go redis_stream.ReclaimGlobalStreamMessages(context.Background(), redisClient, config, config.Repo, config.AnalyticsClient)
+		}
+	}
+
+	if interval := config.ServerConf.AddonUpdateCheckInterval; interval > 0 {
+		checker := addons.NewUpdateChecker(config.Repo, config.ServerConf.ServerURL, config.Logger)
+
+		// addons are checked by a single replica, so that update notifications are sent once
+		if redisClient != nil {
+			checker.Leader = getLeader(redisClient, "addon-update-checker", 2*interval)
+		}
+
+		go checker.Run(context.Background(), interval)
+	}
+
+	if interval := config.ServerConf.GitOpsReconcileInterval; interval > 0 && config.GithubAppConf != nil {
+		syncer := gitops.NewSyncer(config.Repo, config.GithubAppConf, config.DOConf, config.Logger)
+		syncer.GithubAppTokens = config.GithubAppTokens
+		upgrader := release.NewReleaseUpgrader(config)
+
+		syncer.Upgrade = func(
+			cluster *models.Cluster,
+			namespace, name string,
+			values map[string]interface{},
+			ch *chart.Chart,
+			repoURL string,
+			message string,
+		) error {
+			_, err := upgrader.Upgrade(&release.UpgradeOpts{
+				Cluster:      cluster,
+				Namespace:    namespace,
+				Name:         name,
+				Values:       values,
+				Chart:        ch,
+				ChartRepoURL: repoURL,
+				Source:       types.DeploySourceGitOps,
+				Message:      message,
+			})
+
+			return err
+		}
+
+		go syncer.Run(context.Background(), interval)
+	}
+
+	if interval := config.ServerConf.StaleReleaseCheckInterval; interval > 0 {
+		detector := stale.NewDetector(
+			config.Repo,
+			config.DOConf,
+			config.ServerConf.ServerURL,
+			config.Logger,
+			config.ServerConf.StaleReleasePeriod,
+			config.ServerConf.StaleReleaseNotify,
+		)
+
+		// releases are checked by a single replica, so that notifications are sent once
+		if redisClient != nil {
+			detector.Leader = getLeader(redisClient, "stale-release-detector", 2*interval)
+		}
+
+		go detector.Run(context.Background(), interval)
+	}
+
+	if interval := config.ServerConf.ScheduledDeployInterval; interval > 0 {
+		runner := release.NewScheduledDeployRunner(config)
+
+		go runner.Run(context.Background(), interval)
+	}
+
+	if interval := config.ServerConf.UsageReportInterval; interval > 0 && config.ServerConf.IronPlansAPIKey != "" {
+		reporter := billing.NewUsageReporter(
+			config.Repo,
+			config.DOConf,
+			config.BillingManager,
+			config.WhitelistedUsers,
+			config.Logger,
+		)
+
+		reporter.Inventory = config.Inventory
+
+		// usage is reported by a single replica, since each report replaces the usage
+		if redisClient != nil {
+			reporter.Leader = getLeader(redisClient, "usage-reporter", 2*interval)
+		}
+
+		go reporter.Run(context.Background(), interval)
+	}
+
+	go config.URLCache.Run(context.Background(), time.Minute)
+
+	if config.InformerCache != nil {
+		go config.InformerCache.Run(context.Background(), time.Minute)
+	}
+
+	if config.RegistryIndex != nil {
+		go config.RegistryIndex.Run(context.Background(), time.Minute)
+	}
+
+	if config.Inventory != nil {
+		go config.Inventory.Run(context.Background(), time.Minute)
 	}
 
 	appRouter := router.NewAPIRouter(config)
@@ -68,3 +185,16 @@ func main() {
 		config.Logger.Fatal().Err(err).Msg("Server startup failed")
 	}
 }
+
+// getLeader returns the leader election of a periodic task for this replica. The leader
+// keeps the leadership for the given ttl after each run of the task.
+func getLeader(client *redis.Client, task string, ttl time.Duration) *lease.Leader {
+	// the hostname is the pod name when running in a cluster, which is unique per replica
+	workerID, err := os.Hostname()
+
+	if err != nil {
+		workerID = fmt.Sprintf("%s-%d", task, time.Now().UnixNano())
+	}
+
+	return lease.NewLeader(client, task, workerID, ttl)
+}
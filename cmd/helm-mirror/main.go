@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/porter-dev/porter/api/server/shared/config/envloader"
+	"github.com/porter-dev/porter/internal/helm/mirror"
+	lr "github.com/porter-dev/porter/internal/logger"
+)
+
+// helm-mirror syncs the charts of the mirrored repos of the server config from their
+// upstream repos into the store of the mirror. It is run from a machine with access to
+// the upstream repos and to the store, such as before the mirror is moved into an
+// air-gapped network, or on a schedule.
+func main() {
+	var repoName string
+	var verifyOnly bool
+
+	flag.StringVar(&repoName, "repo", "", "only sync the mirrored repo with this name")
+	flag.BoolVar(&verifyOnly, "verify", false, "verify the mirrored charts against the mirrored indexes without syncing")
+	flag.Parse()
+
+	logger := lr.NewConsole(true)
+
+	envConf, err := envloader.FromEnv()
+
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not load env conf")
+		return
+	}
+
+	helmMirror, err := mirror.NewFromConf(envConf.ServerConf)
+
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not create helm mirror")
+		return
+	} else if helmMirror == nil {
+		logger.Fatal().Msg("no repos are mirrored, set HELM_MIRROR_REPOS to mirror repos")
+		return
+	}
+
+	failed := false
+
+	for _, repo := range helmMirror.Repos {
+		if repoName != "" && repo.Name != repoName {
+			continue
+		}
+
+		if !verifyOnly {
+			res, err := helmMirror.Sync(repo)
+
+			if err != nil {
+				logger.Error().Err(err).Str("repo", repo.Name).Msg("error syncing mirrored repo")
+				failed = true
+
+				continue
+			}
+
+			for _, msg := range res.Failed {
+				logger.Error().Str("repo", repo.Name).Msg(msg)
+			}
+
+			failed = failed || len(res.Failed) > 0
+
+			logger.Info().
+				Str("repo", repo.Name).
+				Int("mirrored", res.Mirrored).
+				Int("skipped", res.Skipped).
+				Int("failed", len(res.Failed)).
+				Msg("synced mirrored repo")
+		}
+
+		invalid, err := helmMirror.Verify(repo)
+
+		if err != nil {
+			logger.Error().Err(err).Str("repo", repo.Name).Msg("error verifying mirrored repo")
+			failed = true
+
+			continue
+		}
+
+		for _, msg := range invalid {
+			logger.Error().Str("repo", repo.Name).Msg(msg)
+		}
+
+		failed = failed || len(invalid) > 0
+
+		logger.Info().Str("repo", repo.Name).Int("invalid", len(invalid)).Msg("verified mirrored repo")
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -46,7 +46,16 @@ export const BuildpackSelection: React.FC<{
   hide: boolean;
   onChange: (config: BuildConfig) => void;
 }> = ({ actionConfig, folderPath, branch, hide, onChange }) => {
-  const { currentProject } = useContext(Context);
+  const { currentProject, capabilities } = useContext(Context);
+
+  // the default stacks are the mirrored builders when the server has an image mirror
+  const herokuStack =
+    capabilities?.default_builders?.heroku || DEFAULT_HEROKU_STACK;
+  const paketoStack =
+    capabilities?.default_builders?.paketo || DEFAULT_PAKETO_STACK;
+
+  const isDefaultStack = (stack: string) =>
+    stack === herokuStack || stack === paketoStack;
 
   const [builders, setBuilders] = useState<DetectedBuildpack[]>(null);
   const [selectedBuilder, setSelectedBuilder] = useState<string>(null);
@@ -97,11 +106,7 @@ export const BuildpackSelection: React.FC<{
 
         const detectedBuildpacks = defaultBuilder.detected;
         const availableBuildpacks = defaultBuilder.others;
-        const defaultStack = defaultBuilder.builders.find((stack) => {
-          return (
-            stack === DEFAULT_HEROKU_STACK || stack === DEFAULT_PAKETO_STACK
-          );
-        });
+        const defaultStack = defaultBuilder.builders.find(isDefaultStack);
 
         setBuilders(builders);
         setSelectedBuilder(defaultBuilder.name.toLowerCase());
@@ -152,9 +157,7 @@ export const BuildpackSelection: React.FC<{
     );
     const detectedBuildpacks = builder.detected;
     const availableBuildpacks = builder.others;
-    const defaultStack = builder.builders.find((stack) => {
-      return stack === DEFAULT_HEROKU_STACK || stack === DEFAULT_PAKETO_STACK;
-    });
+    const defaultStack = builder.builders.find(isDefaultStack);
     setSelectedBuilder(builderName);
     setBuilders(builders);
     setSelectedBuilder(builderName.toLowerCase());
@@ -80,6 +80,7 @@ const ChartList: React.FunctionComponent<Props> = ({
             "pending-rollback",
             "failed",
           ],
+          skip_manifest: true,
         },
         {
           id: currentProject.id,
@@ -31,6 +31,7 @@ import DeploymentType from "./DeploymentType";
 import EventsTab from "./events/EventsTab";
 import { PopulatedEnvGroup } from "components/porter-form/types";
 import { onlyInLeft } from "shared/array_utils";
+import { getRevisionConflictMessage, isDefaultImage } from "shared/common";
 
 type Props = {
   namespace: string;
@@ -92,6 +93,7 @@ const ExpandedChart: React.FC<Props> = (props) => {
     currentProject,
     setCurrentError,
     setCurrentOverlay,
+    capabilities,
   } = useContext(Context);
 
   // Retrieve full chart data (includes form and values)
@@ -113,8 +115,7 @@ const ExpandedChart: React.FC<Props> = (props) => {
     const newNewestImage = tag ? image + ":" + tag : image;
     let imageIsPlaceholder = false;
     if (
-      (image === "porterdev/hello-porter" ||
-        image === "public.ecr.aws/o1j4x7p4/hello-porter") &&
+      isDefaultImage(image, capabilities, false) &&
       !newestImage
     ) {
       imageIsPlaceholder = true;
@@ -325,6 +326,7 @@ const ExpandedChart: React.FC<Props> = (props) => {
         "<token>",
         {
           values: valuesYaml,
+          revision: currentChart.version,
         },
         {
           id: currentProject.id,
@@ -344,7 +346,7 @@ const ExpandedChart: React.FC<Props> = (props) => {
         values: valuesYaml,
       });
     } catch (err) {
-      const parsedErr = err?.response?.data?.error;
+      const parsedErr = getRevisionConflictMessage(err);
 
       if (parsedErr) {
         err = parsedErr;
@@ -628,6 +630,15 @@ const ExpandedChart: React.FC<Props> = (props) => {
       );
     }
 
+    if (currentChart.internal_endpoints?.length) {
+      return (
+        <Url>
+          <Bolded>Internal URI:</Bolded>
+          {currentChart.internal_endpoints[0]}
+        </Url>
+      );
+    }
+
     const service: any = components?.find((c) => {
       return c.Kind === "Service";
     });
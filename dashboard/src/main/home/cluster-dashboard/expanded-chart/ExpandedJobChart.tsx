@@ -8,6 +8,7 @@ import loading from "assets/loading.gif";
 
 import { ChartType, ClusterType, StorageType } from "shared/types";
 import { Context } from "shared/Context";
+import { isDefaultImage } from "shared/common";
 import api from "shared/api";
 
 import Logs from "./status/Logs";
@@ -122,8 +123,7 @@ class ExpandedJobChart extends Component<PropsType, StateType> {
         let newestImage = tag ? image + ":" + tag : image;
 
         if (
-          (image === "porterdev/hello-porter-job" ||
-            image === "public.ecr.aws/o1j4x7p4/hello-porter-job") &&
+          isDefaultImage(image, this.context.capabilities, true) &&
           !this.state.newestImage
         ) {
           this.setState(
@@ -299,10 +299,7 @@ class ExpandedJobChart extends Component<PropsType, StateType> {
               ?.image;
           if (
             newestImage &&
-            newestImage !== "porterdev/hello-porter-job" &&
-            newestImage !== "porterdev/hello-porter-job:latest" &&
-            newestImage !== "public.ecr.aws/o1j4x7p4/hello-porter-job" &&
-            newestImage !== "public.ecr.aws/o1j4x7p4/hello-porter-job:latest"
+            !isDefaultImage(newestImage, this.context.capabilities, true)
           ) {
             this.setState({ newestImage, imageIsPlaceholder: false });
           }
@@ -469,10 +466,7 @@ class ExpandedJobChart extends Component<PropsType, StateType> {
     let newestImage = jobs[0]?.spec?.template?.spec?.containers[0]?.image;
     if (
       newestImage &&
-      newestImage !== "porterdev/hello-porter-job" &&
-      newestImage !== "porterdev/hello-porter-job:latest" &&
-      newestImage !== "public.ecr.aws/o1j4x7p4/hello-porter-job" &&
-      newestImage !== "public.ecr.aws/o1j4x7p4/hello-porter-job:latest"
+      !isDefaultImage(newestImage, this.context.capabilities, true)
     ) {
       this.setState({ jobs, newestImage, imageIsPlaceholder: false });
     } else {
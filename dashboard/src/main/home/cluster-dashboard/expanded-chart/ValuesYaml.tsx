@@ -6,6 +6,7 @@ import _ from "lodash";
 import { ChartType, StorageType } from "shared/types";
 import api from "shared/api";
 import { Context } from "shared/Context";
+import { getRevisionConflictMessage } from "shared/common";
 
 import YamlEditor from "components/YamlEditor";
 import SaveButton from "components/SaveButton";
@@ -64,6 +65,7 @@ export default class ValuesYaml extends Component<PropsType, StateType> {
         "<token>",
         {
           values: valuesString,
+          revision: this.props.currentChart.version,
         },
         {
           id: currentProject.id,
@@ -77,7 +79,7 @@ export default class ValuesYaml extends Component<PropsType, StateType> {
         this.props.refreshChart();
       })
       .catch((err) => {
-        let parsedErr = err?.response?.data?.error;
+        let parsedErr = getRevisionConflictMessage(err);
 
         if (parsedErr) {
           err = parsedErr;
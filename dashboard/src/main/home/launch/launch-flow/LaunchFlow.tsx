@@ -6,6 +6,7 @@ import { RouteComponentProps, withRouter } from "react-router";
 
 import api from "shared/api";
 import { Context } from "shared/Context";
+import { getDefaultImage } from "shared/common";
 import { getQueryParam, getQueryParams, pushFiltered } from "shared/routing";
 
 import { hardcodedNames } from "shared/hardcodedNameDict";
@@ -205,13 +206,11 @@ const LaunchFlow: React.FC<PropsType> = (props) => {
     }
 
     if (sourceType === "repo") {
-      if (props.currentTemplate?.name == "job") {
-        url = "public.ecr.aws/o1j4x7p4/hello-porter-job";
-        tag = "latest";
-      } else {
-        url = "public.ecr.aws/o1j4x7p4/hello-porter";
-        tag = "latest";
-      }
+      url = getDefaultImage(
+        context.capabilities,
+        props.currentTemplate?.name == "job"
+      );
+      tag = "latest";
     }
 
     let provider;
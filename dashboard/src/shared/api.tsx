@@ -463,6 +463,26 @@ const deployAddon = baseApi<
   return `/api/projects/${id}/clusters/${cluster_id}/namespaces/${namespace}/addons?repo_url=${repo_url}`;
 });
 
+const createCache = baseApi<
+  {
+    name: string;
+    engine: "redis" | "memcached";
+    version?: string;
+    storage_size?: string;
+    env_group_name?: string;
+    release_names?: string[];
+  },
+  {
+    id: number;
+    cluster_id: number;
+    namespace: string;
+  }
+>("POST", (pathParams) => {
+  let { cluster_id, id, namespace } = pathParams;
+
+  return `/api/projects/${id}/clusters/${cluster_id}/namespaces/${namespace}/caches`;
+});
+
 const detectBuildpack = baseApi<
   {},
   {
@@ -481,6 +501,26 @@ const detectBuildpack = baseApi<
   }/${encodeURIComponent(pathParams.branch)}/buildpack/detect`;
 });
 
+const redetectBuildpack = baseApi<
+  {
+    dir: string;
+  },
+  {
+    project_id: number;
+    git_repo_id: number;
+    kind: string;
+    owner: string;
+    name: string;
+    branch: string;
+  }
+>("POST", (pathParams) => {
+  return `/api/projects/${pathParams.project_id}/gitrepos/${
+    pathParams.git_repo_id
+  }/repos/${pathParams.kind}/${pathParams.owner}/${
+    pathParams.name
+  }/${encodeURIComponent(pathParams.branch)}/buildpack/detect`;
+});
+
 const getBranchContents = baseApi<
   {
     dir: string;
@@ -555,6 +595,7 @@ const getCharts = baseApi<
     skip: number;
     byDate: boolean;
     statusFilter: string[];
+    skip_manifest?: boolean;
   },
   {
     id: number;
@@ -1033,6 +1074,8 @@ const upgradeChartValues = baseApi<
   {
     values: string;
     version?: string;
+    message?: string;
+    revision?: number;
   },
   {
     id: number;
@@ -1045,6 +1088,39 @@ const upgradeChartValues = baseApi<
   return `/api/projects/${id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/0/upgrade`;
 });
 
+const getTemplateUpgrade = baseApi<
+  {
+    version?: string;
+  },
+  {
+    id: number;
+    name: string;
+    namespace: string;
+    cluster_id: number;
+  }
+>("GET", (pathParams) => {
+  let { id, name, cluster_id, namespace } = pathParams;
+  return `/api/projects/${id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/0/template_upgrade`;
+});
+
+const applyTemplateUpgrade = baseApi<
+  {
+    version?: string;
+    values?: string;
+    message?: string;
+    revision?: number;
+  },
+  {
+    id: number;
+    name: string;
+    namespace: string;
+    cluster_id: number;
+  }
+>("POST", (pathParams) => {
+  let { id, name, cluster_id, namespace } = pathParams;
+  return `/api/projects/${id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/0/template_upgrade`;
+});
+
 const listEnvGroups = baseApi<
   {},
   {
@@ -1295,6 +1371,139 @@ const getUsage = baseApi<{}, { project_id: number }>(
   ({ project_id }) => `/api/projects/${project_id}/usage`
 );
 
+const getProjectInventory = baseApi<{}, { project_id: number }>(
+  "GET",
+  ({ project_id }) => `/api/projects/${project_id}/inventory`
+);
+
+const getStaleReleases = baseApi<{}, { project_id: number }>(
+  "GET",
+  ({ project_id }) => `/api/projects/${project_id}/stale_releases`
+);
+
+const getDeployFreezes = baseApi<{}, { project_id: number }>(
+  "GET",
+  ({ project_id }) => `/api/projects/${project_id}/deploy_freezes`
+);
+
+const createDeployFreeze = baseApi<
+  {
+    cluster_id?: number;
+    name: string;
+    schedule?: string;
+    duration_minutes?: number;
+    timezone?: string;
+    starts_at?: string;
+    ends_at?: string;
+  },
+  { project_id: number }
+>(
+  "POST",
+  ({ project_id }) => `/api/projects/${project_id}/deploy_freezes`
+);
+
+const deleteDeployFreeze = baseApi<
+  {},
+  { project_id: number; deploy_freeze_id: number }
+>(
+  "DELETE",
+  ({ project_id, deploy_freeze_id }) =>
+    `/api/projects/${project_id}/deploy_freezes/${deploy_freeze_id}`
+);
+
+const getDeployFreezeOverrides = baseApi<{}, { project_id: number }>(
+  "GET",
+  ({ project_id }) => `/api/projects/${project_id}/deploy_freezes/overrides`
+);
+
+const listScheduledDeploys = baseApi<
+  {
+    status?: string;
+  },
+  {
+    project_id: number;
+    cluster_id: number;
+    namespace: string;
+    name: string;
+  }
+>("GET", (pathParams) => {
+  let { project_id, cluster_id, namespace, name } = pathParams;
+
+  return `/api/projects/${project_id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/scheduled_deploys`;
+});
+
+const createScheduledDeploy = baseApi<
+  {
+    values?: string;
+    version?: string;
+    patch?: string;
+    patch_type?: string;
+    image_tag?: string;
+    message?: string;
+    run_at: string;
+  },
+  {
+    project_id: number;
+    cluster_id: number;
+    namespace: string;
+    name: string;
+  }
+>("POST", (pathParams) => {
+  let { project_id, cluster_id, namespace, name } = pathParams;
+
+  return `/api/projects/${project_id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/scheduled_deploys`;
+});
+
+const updateScheduledDeploy = baseApi<
+  {
+    values?: string;
+    version?: string;
+    patch?: string;
+    patch_type?: string;
+    image_tag?: string;
+    message?: string;
+    run_at: string;
+  },
+  {
+    project_id: number;
+    cluster_id: number;
+    namespace: string;
+    name: string;
+    scheduled_deploy_id: number;
+  }
+>("POST", (pathParams) => {
+  let {
+    project_id,
+    cluster_id,
+    namespace,
+    name,
+    scheduled_deploy_id,
+  } = pathParams;
+
+  return `/api/projects/${project_id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/scheduled_deploys/${scheduled_deploy_id}`;
+});
+
+const cancelScheduledDeploy = baseApi<
+  {},
+  {
+    project_id: number;
+    cluster_id: number;
+    namespace: string;
+    name: string;
+    scheduled_deploy_id: number;
+  }
+>("POST", (pathParams) => {
+  let {
+    project_id,
+    cluster_id,
+    namespace,
+    name,
+    scheduled_deploy_id,
+  } = pathParams;
+
+  return `/api/projects/${project_id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/scheduled_deploys/${scheduled_deploy_id}/cancel`;
+});
+
 // Used for billing purposes
 const getCustomerToken = baseApi<{}, { project_id: number }>(
   "GET",
@@ -1352,6 +1561,189 @@ const installPorterAgent = baseApi<
     `/api/projects/${project_id}/clusters/${cluster_id}/agent/install`
 );
 
+const getKEDAStatus = baseApi<{}, { project_id: number; cluster_id: number }>(
+  "GET",
+  ({ cluster_id, project_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/keda`
+);
+
+const installKEDA = baseApi<{}, { project_id: number; cluster_id: number }>(
+  "POST",
+  ({ cluster_id, project_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/keda/install`
+);
+
+const getVeleroStatus = baseApi<{}, { project_id: number; cluster_id: number }>(
+  "GET",
+  ({ cluster_id, project_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/velero`
+);
+
+const installVelero = baseApi<
+  {
+    bucket: string;
+    prefix?: string;
+    region?: string;
+    s3_url?: string;
+    access_key_id?: string;
+    secret_access_key?: string;
+  },
+  { project_id: number; cluster_id: number }
+>(
+  "POST",
+  ({ cluster_id, project_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/velero/install`
+);
+
+const listBackups = baseApi<
+  {
+    namespace?: string;
+    release_name?: string;
+  },
+  { project_id: number; cluster_id: number }
+>(
+  "GET",
+  ({ cluster_id, project_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/backups`
+);
+
+const createBackup = baseApi<
+  {
+    namespace: string;
+    release_name?: string;
+    ttl?: string;
+  },
+  { project_id: number; cluster_id: number }
+>(
+  "POST",
+  ({ cluster_id, project_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/backups`
+);
+
+const restoreBackup = baseApi<
+  {
+    release_name?: string;
+  },
+  { project_id: number; cluster_id: number; backup_name: string }
+>(
+  "POST",
+  ({ cluster_id, project_id, backup_name }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/backups/${backup_name}/restore`
+);
+
+const listBackupSchedules = baseApi<
+  {},
+  { project_id: number; cluster_id: number }
+>(
+  "GET",
+  ({ cluster_id, project_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/backup_schedules`
+);
+
+const createBackupSchedule = baseApi<
+  {
+    name?: string;
+    namespace: string;
+    release_name?: string;
+    schedule: string;
+    ttl?: string;
+  },
+  { project_id: number; cluster_id: number }
+>(
+  "POST",
+  ({ cluster_id, project_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/backup_schedules`
+);
+
+const deleteBackupSchedule = baseApi<
+  {},
+  { project_id: number; cluster_id: number; schedule_name: string }
+>(
+  "DELETE",
+  ({ cluster_id, project_id, schedule_name }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/backup_schedules/${schedule_name}`
+);
+
+const getReleaseScaler = baseApi<
+  {},
+  {
+    id: number;
+    name: string;
+    namespace: string;
+    cluster_id: number;
+  }
+>("GET", (pathParams) => {
+  let { id, name, cluster_id, namespace } = pathParams;
+  return `/api/projects/${id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/0/scaler`;
+});
+
+const updateReleaseScaler = baseApi<
+  {
+    enabled: boolean;
+    min_replicas: number;
+    max_replicas: number;
+    polling_interval?: number;
+    cooldown_period?: number;
+    triggers: {
+      type: "sqs" | "rabbitmq" | "redis" | "kafka" | "pubsub";
+      target: number;
+      queue_url?: string;
+      aws_region?: string;
+      access_key_id_from_env?: string;
+      secret_access_key_from_env?: string;
+      queue_name?: string;
+      list_name?: string;
+      bootstrap_servers?: string;
+      consumer_group?: string;
+      topic?: string;
+      subscription_name?: string;
+      host_from_env?: string;
+      password_from_env?: string;
+      credentials_from_env?: string;
+      metadata?: Record<string, string>;
+    }[];
+  },
+  {
+    id: number;
+    name: string;
+    namespace: string;
+    cluster_id: number;
+  }
+>("POST", (pathParams) => {
+  let { id, name, cluster_id, namespace } = pathParams;
+  return `/api/projects/${id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/0/scaler`;
+});
+
+const getServiceExposure = baseApi<
+  {},
+  {
+    id: number;
+    name: string;
+    namespace: string;
+    cluster_id: number;
+  }
+>("GET", (pathParams) => {
+  let { id, name, cluster_id, namespace } = pathParams;
+  return `/api/projects/${id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/0/exposure`;
+});
+
+const updateServiceExposure = baseApi<
+  {
+    protocol: "http" | "grpc" | "tcp" | "udp";
+    mode?: "loadbalancer" | "proxy" | "internal";
+    proxy_port?: number;
+  },
+  {
+    id: number;
+    name: string;
+    namespace: string;
+    cluster_id: number;
+  }
+>("POST", (pathParams) => {
+  let { id, name, cluster_id, namespace } = pathParams;
+  return `/api/projects/${id}/clusters/${cluster_id}/namespaces/${namespace}/releases/${name}/0/exposure`;
+});
+
 const getKubeEvents = baseApi<
   {
     skip: number;
@@ -1468,6 +1860,107 @@ const getDatabases = baseApi<
     `/api/projects/${project_id}/clusters/${cluster_id}/databases`
 );
 
+const provisionBucket = baseApi<
+  {
+    bucket_name: string;
+    versioning: boolean;
+  },
+  {
+    project_id: number;
+    cluster_id: number;
+    namespace: string;
+  }
+>(
+  "POST",
+  ({ project_id, cluster_id, namespace }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/namespaces/${namespace}/provision/bucket`
+);
+
+const getBuckets = baseApi<
+  {},
+  {
+    project_id: number;
+    cluster_id: number;
+  }
+>(
+  "GET",
+  ({ project_id, cluster_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/buckets`
+);
+
+const provisionQueue = baseApi<
+  {
+    queue_name: string;
+    fifo?: boolean;
+    visibility_timeout?: number;
+    message_retention?: number;
+  },
+  {
+    project_id: number;
+    cluster_id: number;
+    namespace: string;
+  }
+>(
+  "POST",
+  ({ project_id, cluster_id, namespace }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/namespaces/${namespace}/provision/queue`
+);
+
+const getQueues = baseApi<
+  {},
+  {
+    project_id: number;
+    cluster_id: number;
+  }
+>(
+  "GET",
+  ({ project_id, cluster_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/queues`
+);
+
+const provisionPrivateNetwork = baseApi<
+  {
+    database_infra_id?: number;
+    cloudsql_instance_name?: string;
+  },
+  {
+    project_id: number;
+    cluster_id: number;
+  }
+>(
+  "POST",
+  ({ project_id, cluster_id }) =>
+    `/api/projects/${project_id}/clusters/${cluster_id}/provision/private_network`
+);
+
+const runProvisionPreflight = baseApi<
+  {
+    kind: string;
+    aws_integration_id?: number;
+    gcp_integration_id?: number;
+    do_integration_id?: number;
+  },
+  {
+    project_id: number;
+  }
+>(
+  "POST",
+  ({ project_id }) => `/api/projects/${project_id}/provision/preflight`
+);
+
+const estimateProvisionCost = baseApi<
+  {
+    kind: string;
+    config: any;
+  },
+  {
+    project_id: number;
+  }
+>(
+  "POST",
+  ({ project_id }) => `/api/projects/${project_id}/provision/estimate`
+);
+
 // Bundle export to allow default api import (api.<method> is more readable)
 export default {
   checkAuth,
@@ -1507,8 +2000,10 @@ export default {
   createSubdomain,
   deployTemplate,
   deployAddon,
+  createCache,
   destroyInfra,
   detectBuildpack,
+  redetectBuildpack,
   getBranchContents,
   getBranches,
   getMetadata,
@@ -1577,6 +2072,8 @@ export default {
   renameConfigMap,
   updateConfigMap,
   upgradeChartValues,
+  getTemplateUpgrade,
+  applyTemplateUpgrade,
   deleteJob,
   stopJob,
   updateInvite,
@@ -1587,6 +2084,16 @@ export default {
   getPolicyDocument,
   createWebhookToken,
   getUsage,
+  getProjectInventory,
+  getStaleReleases,
+  getDeployFreezes,
+  createDeployFreeze,
+  deleteDeployFreeze,
+  getDeployFreezeOverrides,
+  listScheduledDeploys,
+  createScheduledDeploy,
+  updateScheduledDeploy,
+  cancelScheduledDeploy,
   getCustomerToken,
   getHasBilling,
   getOnboardingState,
@@ -1595,6 +2102,20 @@ export default {
   getOnboardingRegistry,
   detectPorterAgent,
   installPorterAgent,
+  getKEDAStatus,
+  installKEDA,
+  getVeleroStatus,
+  installVelero,
+  listBackups,
+  createBackup,
+  restoreBackup,
+  listBackupSchedules,
+  createBackupSchedule,
+  deleteBackupSchedule,
+  getReleaseScaler,
+  updateReleaseScaler,
+  getServiceExposure,
+  updateServiceExposure,
   getKubeEvents,
   getKubeEvent,
   getLogBuckets,
@@ -1610,4 +2131,11 @@ export default {
   removeApplicationFromEnvGroup,
   provisionDatabase,
   getDatabases,
+  provisionBucket,
+  getBuckets,
+  provisionQueue,
+  getQueues,
+  provisionPrivateNetwork,
+  runProvisionPreflight,
+  estimateProvisionCost,
 };
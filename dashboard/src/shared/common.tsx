@@ -2,6 +2,7 @@ import aws from "../assets/aws.png";
 import digitalOcean from "../assets/do.png";
 import gcp from "../assets/gcp.png";
 import github from "../assets/github.png";
+import { CapabilityType } from "./types";
 
 export const infraNames: any = {
   ecr: "Elastic Container Registry (ECR)",
@@ -103,3 +104,61 @@ export const getIgnoreCase = (object: any, key: string) => {
     Object.keys(object).find((k) => k.toLowerCase() === key.toLowerCase())
   ];
 };
+
+// getRevisionConflictMessage returns the error of a release upgrade, and lists the values
+// that changed since the edit started when the upgrade conflicts with a newer revision
+export const getRevisionConflictMessage = (err: any): string | undefined => {
+  const data = err?.response?.data;
+
+  if (data?.error_code !== "PORTER_ERR_REVISION_CONFLICT") {
+    return data?.error;
+  }
+
+  const diff: string[] = data.values_diff || [];
+
+  if (diff.length == 0) {
+    return `${data.error}. Reload the latest values before saving again.`;
+  }
+
+  return `${data.error}. Reload the latest values before saving again. Changed values: ${diff.join(
+    ", "
+  )}`;
+};
+
+const DEFAULT_APP_IMAGE = "public.ecr.aws/o1j4x7p4/hello-porter";
+const DEFAULT_JOB_IMAGE = "public.ecr.aws/o1j4x7p4/hello-porter-job";
+
+// getDefaultImage returns the image that is deployed before an application is built,
+// which is the mirrored image when the server has an image mirror
+export const getDefaultImage = (
+  capabilities: CapabilityType,
+  isJob: boolean
+): string => {
+  if (isJob) {
+    return capabilities?.default_job_image || DEFAULT_JOB_IMAGE;
+  }
+
+  return capabilities?.default_app_image || DEFAULT_APP_IMAGE;
+};
+
+// isDefaultImage returns true if an image, with or without its tag, is the default image
+// by its public, legacy or mirrored name
+export const isDefaultImage = (
+  image: string,
+  capabilities: CapabilityType,
+  isJob: boolean
+): boolean => {
+  if (!image) {
+    return false;
+  }
+
+  const repository = image.replace(/:latest$/, "");
+  const names = isJob
+    ? ["porterdev/hello-porter-job", DEFAULT_JOB_IMAGE]
+    : ["porterdev/hello-porter", DEFAULT_APP_IMAGE];
+
+  return (
+    names.includes(repository) ||
+    repository === getDefaultImage(capabilities, isJob)
+  );
+};
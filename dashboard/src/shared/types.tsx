@@ -48,6 +48,7 @@ export interface ChartType {
   version: number;
   namespace: string;
   latest_version: string;
+  internal_endpoints?: string[];
 }
 
 export interface ChartTypeWithExtendedConfig extends ChartType {
@@ -276,6 +277,14 @@ export interface FullActionConfigType extends ActionConfigType {
 export interface CapabilityType {
   github: boolean;
   provisioner: boolean;
+  image_mirror?: string;
+  image_mirror_strict?: boolean;
+  default_app_image?: string;
+  default_job_image?: string;
+  default_builders?: {
+    heroku: string;
+    paketo: string;
+  };
 }
 
 export interface ContextProps {
@@ -0,0 +1,153 @@
+package billing
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/porter-dev/porter/api/server/handlers"
+	"github.com/porter-dev/porter/api/server/shared"
+	"github.com/porter-dev/porter/api/server/shared/apierrors"
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+type BillingGetUpcomingInvoiceHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewBillingGetUpcomingInvoiceHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) http.Handler {
+	return &BillingGetUpcomingInvoiceHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *BillingGetUpcomingInvoiceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, ok := getBilledProject(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	invoice, err := c.Config().BillingManager.GetUpcomingInvoice(proj)
+
+	if err != nil {
+		handleBillingProviderError(c.PorterHandlerWriter, w, r, proj, err)
+		return
+	}
+
+	c.WriteResult(w, r, &types.GetUpcomingInvoiceResponse{
+		Invoice: invoice,
+	})
+}
+
+type BillingListInvoicesHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewBillingListInvoicesHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) http.Handler {
+	return &BillingListInvoicesHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *BillingListInvoicesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, ok := getBilledProject(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	invoices, err := c.Config().BillingManager.ListInvoices(proj)
+
+	if err != nil {
+		handleBillingProviderError(c.PorterHandlerWriter, w, r, proj, err)
+		return
+	}
+
+	c.WriteResult(w, r, types.ListInvoicesResponse(invoices))
+}
+
+type BillingGetPaymentMethodHandler struct {
+	handlers.PorterHandlerWriter
+}
+
+func NewBillingGetPaymentMethodHandler(
+	config *config.Config,
+	writer shared.ResultWriter,
+) http.Handler {
+	return &BillingGetPaymentMethodHandler{
+		PorterHandlerWriter: handlers.NewDefaultPorterHandler(config, nil, writer),
+	}
+}
+
+func (c *BillingGetPaymentMethodHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proj, ok := getBilledProject(c.PorterHandlerWriter, w, r)
+
+	if !ok {
+		return
+	}
+
+	method, err := c.Config().BillingManager.GetPaymentMethod(proj)
+
+	if err != nil {
+		handleBillingProviderError(c.PorterHandlerWriter, w, r, proj, err)
+		return
+	}
+
+	c.WriteResult(w, r, method)
+}
+
+// getBilledProject returns the project of the request after double-checking that the
+// user is an admin of the project, since billing data is only visible to admins
+func getBilledProject(c handlers.PorterHandlerWriter, w http.ResponseWriter, r *http.Request) (*models.Project, bool) {
+	user, _ := r.Context().Value(types.UserScope).(*models.User)
+	proj, _ := r.Context().Value(types.ProjectScope).(*models.Project)
+
+	roles, err := c.Repo().Project().ListProjectRoles(proj.ID)
+
+	if err != nil {
+		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+		return nil, false
+	}
+
+	for _, role := range roles {
+		if role.UserID != 0 && role.UserID == user.ID && role.Kind != types.RoleAdmin {
+			c.HandleAPIError(w, r, apierrors.NewErrForbidden(
+				fmt.Errorf("user %d is not an admin in project %d", user.ID, proj.ID),
+			))
+
+			return nil, false
+		}
+	}
+
+	return proj, true
+}
+
+func handleBillingProviderError(
+	c handlers.PorterHandlerWriter,
+	w http.ResponseWriter,
+	r *http.Request,
+	proj *models.Project,
+	err error,
+) {
+	// projects without a billing team do not have billing data
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.HandleAPIError(w, r, apierrors.NewErrPassThroughToClient(
+			fmt.Errorf("billing is not enabled for project %d", proj.ID),
+			http.StatusNotFound,
+		))
+
+		return
+	}
+
+	c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
+}
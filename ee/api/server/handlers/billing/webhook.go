@@ -12,6 +12,8 @@ import (
 	"github.com/porter-dev/porter/api/server/shared/apierrors"
 	"github.com/porter-dev/porter/api/server/shared/config"
 	"gorm.io/gorm"
+
+	cebilling "github.com/porter-dev/porter/internal/billing"
 )
 
 type BillingWebhookHandler struct {
@@ -81,4 +83,20 @@ func (c *BillingWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		c.HandleAPIError(w, r, apierrors.NewErrInternal(err))
 		return
 	}
+
+	// reconcile the usage of the project with the new subscription, so that usage against
+	// the new limits is reported without waiting for the next interval
+	reporter := cebilling.NewUsageReporter(
+		c.Repo(),
+		c.Config().DOConf,
+		c.Config().BillingManager,
+		c.Config().WhitelistedUsers,
+		c.Config().Logger,
+	)
+
+	go func() {
+		if err := reporter.ReportProject(newUsage.ProjectID); err != nil {
+			c.Config().Logger.Error().Err(err).Msgf("could not report usage of project %d", newUsage.ProjectID)
+		}
+	}()
 }
@@ -467,6 +467,178 @@ func (c *Client) VerifySignature(signature string, body []byte) bool {
 	return hmac.Equal(computed.Sum(nil), actual)
 }
 
+// ListBilledProjectIDs lists the ids of the projects that have a billing team
+func (c *Client) ListBilledProjectIDs() ([]uint, error) {
+	projBillings, err := c.repo.ProjectBilling().ListProjectBillings()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]uint, 0, len(projBillings))
+
+	for _, projBilling := range projBillings {
+		res = append(res, projBilling.ProjectID)
+	}
+
+	return res, nil
+}
+
+// ReportUsage sets the usage of each metered feature of the project's team. Usage is
+// reported in the same units as the plan limits, so memory is reported in GB. The
+// idempotency key is unique per team, feature, hour and quantity, so that a retried
+// report is only counted once, while a report that corrects the usage of the hour is
+// counted and replaces the earlier report.
+func (c *Client) ReportUsage(proj *cemodels.Project, usage *types.ProjectUsage) error {
+	teamID, err := c.GetTeamID(proj)
+
+	if err != nil {
+		return err
+	}
+
+	listResp := &ListFeaturesResponse{}
+
+	if err := c.getRequest("/features/v1", listResp); err != nil {
+		return err
+	}
+
+	recordedAt := time.Now().UTC().Truncate(time.Hour)
+
+	for _, feature := range listResp.Results {
+		var quantity uint
+
+		switch feature.Slug {
+		case FeatureSlugUsers:
+			quantity = usage.Users
+		case FeatureSlugClusters:
+			quantity = usage.Clusters
+		case FeatureSlugCPU:
+			quantity = usage.ResourceCPU
+		case FeatureSlugMemory:
+			quantity = usage.ResourceMemory / 1000
+		default:
+			continue
+		}
+
+		err := c.postRequest("/usage/v1/", &CreateUsageRequest{
+			TeamID:         teamID,
+			FeatureID:      feature.ID,
+			Quantity:       quantity,
+			Action:         UsageActionSet,
+			RecordedAt:     recordedAt.Format(time.RFC3339),
+			IdempotencyKey: getUsageIdempotencyKey(teamID, feature.Slug, recordedAt, quantity),
+		}, nil)
+
+		if err != nil {
+			return fmt.Errorf("could not report usage of feature %s: %w", feature.Slug, err)
+		}
+	}
+
+	return nil
+}
+
+func getUsageIdempotencyKey(teamID, featureSlug string, recordedAt time.Time, quantity uint) string {
+	return fmt.Sprintf("%s-%s-%d-%d", teamID, featureSlug, recordedAt.Unix(), quantity)
+}
+
+// GetUpcomingInvoice gets the next invoice of the project's team, or nil if there is none
+func (c *Client) GetUpcomingInvoice(proj *cemodels.Project) (*types.Invoice, error) {
+	teamID, err := c.GetTeamID(proj)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &UpcomingInvoiceResponse{}
+
+	if err := c.getRequest("/invoices/v1/upcoming/", resp, map[string]string{"team_id": teamID}); err != nil {
+		return nil, err
+	}
+
+	if resp.Invoice == nil {
+		return nil, nil
+	}
+
+	return resp.Invoice.toInvoiceType(), nil
+}
+
+// ListInvoices lists the past invoices of the project's team, newest first
+func (c *Client) ListInvoices(proj *cemodels.Project) ([]*types.Invoice, error) {
+	teamID, err := c.GetTeamID(proj)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListInvoicesResponse{}
+
+	err = c.getRequest("/invoices/v1/", resp, map[string]string{
+		"team_id":  teamID,
+		"ordering": "-period_start",
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*types.Invoice, 0, len(resp.Results))
+
+	for _, invoice := range resp.Results {
+		res = append(res, invoice.toInvoiceType())
+	}
+
+	return res, nil
+}
+
+// GetPaymentMethod gets the default payment method of the project's team
+func (c *Client) GetPaymentMethod(proj *cemodels.Project) (*types.PaymentMethod, error) {
+	teamID, err := c.GetTeamID(proj)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListPaymentMethodsResponse{}
+
+	if err := c.getRequest("/payment_methods/v1/", resp, map[string]string{"team_id": teamID}); err != nil {
+		return nil, err
+	}
+
+	for _, method := range resp.Results {
+		if !method.IsDefault {
+			continue
+		}
+
+		now := time.Now()
+		year, month := uint(now.Year()), uint(now.Month())
+
+		return &types.PaymentMethod{
+			HasPaymentMethod: true,
+			Type:             method.Type,
+			Brand:            method.Brand,
+			Last4:            method.Last4,
+			ExpMonth:         method.ExpMonth,
+			ExpYear:          method.ExpYear,
+			IsExpired:        method.ExpYear != 0 && (method.ExpYear < year || (method.ExpYear == year && method.ExpMonth < month)),
+		}, nil
+	}
+
+	return &types.PaymentMethod{}, nil
+}
+
+func (i *Invoice) toInvoiceType() *types.Invoice {
+	return &types.Invoice{
+		ID:          i.ID,
+		Status:      i.Status,
+		AmountDue:   i.AmountDueCents,
+		AmountPaid:  i.AmountPaidCents,
+		Currency:    i.Currency,
+		PeriodStart: i.PeriodStart,
+		PeriodEnd:   i.PeriodEnd,
+		HostedURL:   i.HostedInvoiceURL,
+	}
+}
+
 func (c *Client) postRequest(path string, data interface{}, dst interface{}) error {
 	return c.writeRequest("POST", path, data, dst)
 }
@@ -599,6 +771,10 @@ const (
 	FeatureSlugMemory   string = "memory"
 	FeatureSlugClusters string = "clusters"
 	FeatureSlugUsers    string = "users"
+
+	// features that are included in a plan rather than metered
+	FeatureSlugPreviewEnvironments string = "preview-environments"
+	FeatureSlugSSO                 string = "sso"
 )
 
 func (c *Client) ParseProjectUsageFromWebhook(payload []byte) (*cemodels.ProjectUsage, error) {
@@ -623,7 +799,8 @@ func (c *Client) ParseProjectUsageFromWebhook(payload []byte) (*cemodels.Project
 	}
 
 	usage := &cemodels.ProjectUsage{
-		ProjectID: projBilling.ProjectID,
+		ProjectID:   projBilling.ProjectID,
+		TrialEndsAt: subscription.TrialEndsAt,
 	}
 
 	for _, feature := range subscription.Plan.Features {
@@ -638,6 +815,10 @@ func (c *Client) ParseProjectUsageFromWebhook(payload []byte) (*cemodels.Project
 			usage.Clusters = maxLimit
 		case FeatureSlugUsers:
 			usage.Users = maxLimit
+		case FeatureSlugPreviewEnvironments:
+			usage.PreviewEnvironments = feature.IsActive
+		case FeatureSlugSSO:
+			usage.SSO = feature.IsActive
 		}
 	}
 
@@ -2,6 +2,8 @@
 
 package billing
 
+import "time"
+
 type Team struct {
 	ID           string       `json:"id"`
 	ProviderID   string       `json:"provider_id"`
@@ -129,9 +131,10 @@ type CreateIDTokenResponse struct {
 }
 
 type SubscriptionWebhookRequest struct {
-	EventType string `json:"event_type"`
-	TeamID    string `json:"team_id"`
-	Plan      Plan   `json:"plan"`
+	EventType   string     `json:"event_type"`
+	TeamID      string     `json:"team_id"`
+	Plan        Plan       `json:"plan"`
+	TrialEndsAt *time.Time `json:"trial_ends_at"`
 }
 
 type CreateSubscriptionRequest struct {
@@ -140,3 +143,52 @@ type CreateSubscriptionRequest struct {
 	IsPaused   bool   `json:"is_paused"`
 	NextPlanID string `json:"next_plan_id"`
 }
+
+type UsageAction string
+
+const (
+	// UsageActionSet replaces the usage of a feature, rather than incrementing it
+	UsageActionSet UsageAction = "set"
+)
+
+type CreateUsageRequest struct {
+	TeamID         string      `json:"team_id"`
+	FeatureID      string      `json:"feature_id"`
+	Quantity       uint        `json:"quantity"`
+	Action         UsageAction `json:"action"`
+	RecordedAt     string      `json:"recorded_at"`
+	IdempotencyKey string      `json:"idempotency_key"`
+}
+
+type Invoice struct {
+	ID               string     `json:"id"`
+	Status           string     `json:"status"`
+	AmountDueCents   uint       `json:"amount_due_cents"`
+	AmountPaidCents  uint       `json:"amount_paid_cents"`
+	Currency         string     `json:"currency"`
+	PeriodStart      *time.Time `json:"period_start"`
+	PeriodEnd        *time.Time `json:"period_end"`
+	HostedInvoiceURL string     `json:"hosted_invoice_url"`
+}
+
+type ListInvoicesResponse struct {
+	Results []Invoice `json:"results"`
+}
+
+type UpcomingInvoiceResponse struct {
+	Invoice *Invoice `json:"invoice"`
+}
+
+type PaymentMethod struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Brand     string `json:"brand"`
+	Last4     string `json:"last4"`
+	ExpMonth  uint   `json:"exp_month"`
+	ExpYear   uint   `json:"exp_year"`
+	IsDefault bool   `json:"is_default"`
+}
+
+type ListPaymentMethodsResponse struct {
+	Results []PaymentMethod `json:"results"`
+}
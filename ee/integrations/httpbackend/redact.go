@@ -0,0 +1,226 @@
+package httpbackend
+
+import "strings"
+
+// RedactedValue replaces sensitive values in redacted Terraform state
+const RedactedValue = "<redacted>"
+
+// sensitiveAttributeSubstrings are substrings of resource attribute and variable names
+// that indicate that the value is a secret
+var sensitiveAttributeSubstrings = []string{
+	"password",
+	"secret",
+	"token",
+	"private_key",
+	"client_key",
+	"client_certificate",
+	"kubeconfig",
+	"kube_config",
+	"raw_config",
+	"access_key",
+	"credentials",
+}
+
+// IsSensitiveKey returns true if a Terraform attribute or variable with this name
+// may contain a secret
+func IsSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+
+	for _, substr := range sensitiveAttributeSubstrings {
+		if strings.Contains(lower, substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redacted returns a copy of the Terraform state with all sensitive resource attributes
+// and outputs replaced by RedactedValue, so that the state can be shared outside of the
+// provisioner
+func (s *TFState) Redacted() *TFState {
+	res := &TFState{
+		Version:          s.Version,
+		TerraformVersion: s.TerraformVersion,
+		Serial:           s.Serial,
+		Lineage:          s.Lineage,
+		Outputs:          redactOutputs(s.Outputs),
+		Resources:        make([]TFStateResource, 0, len(s.Resources)),
+	}
+
+	for _, resource := range s.Resources {
+		redactedResource := resource
+		redactedResource.Instances = make([]Instance, 0, len(resource.Instances))
+
+		for _, instance := range resource.Instances {
+			attributes := RedactMap(instance.Attributes)
+
+			for _, path := range instance.SensitiveAttributes {
+				redactPath(attributes, path)
+			}
+
+			redactedResource.Instances = append(redactedResource.Instances, Instance{
+				Attributes:          attributes,
+				Dependencies:        instance.Dependencies,
+				SensitiveAttributes: instance.SensitiveAttributes,
+			})
+		}
+
+		res.Resources = append(res.Resources, redactedResource)
+	}
+
+	return res
+}
+
+// RedactMap returns a copy of the map with the values of sensitive keys replaced by
+// RedactedValue. Nested maps and lists are redacted recursively.
+func RedactMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	res := make(map[string]interface{}, len(m))
+
+	for key, val := range m {
+		if IsSensitiveKey(key) {
+			res[key] = RedactedValue
+			continue
+		}
+
+		res[key] = redactValue(val)
+	}
+
+	return res
+}
+
+// redactPath replaces the value at an attribute path of the attributes of a resource
+// instance by RedactedValue. If the path cannot be followed, the whole attribute that the
+// path starts at is redacted, so that sensitive values are never returned.
+func redactPath(attributes map[string]interface{}, path AttributePath) {
+	if len(path) == 0 || path[0].Type != "get_attr" {
+		return
+	}
+
+	root, ok := path[0].Value.(string)
+
+	if !ok {
+		return
+	}
+
+	if _, exists := attributes[root]; !exists {
+		return
+	}
+
+	var parent interface{} = attributes
+
+	for i, step := range path {
+		key := getPathStepKey(step)
+		last := i == len(path)-1
+
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			k, ok := key.(string)
+
+			if !ok {
+				break
+			}
+
+			if _, exists := p[k]; !exists {
+				break
+			}
+
+			if last {
+				p[k] = RedactedValue
+				return
+			}
+
+			parent = p[k]
+			continue
+		case []interface{}:
+			index, ok := key.(float64)
+
+			if !ok || index < 0 || int(index) >= len(p) {
+				break
+			}
+
+			if last {
+				p[int(index)] = RedactedValue
+				return
+			}
+
+			parent = p[int(index)]
+			continue
+		}
+
+		attributes[root] = RedactedValue
+
+		return
+	}
+}
+
+// getPathStepKey returns the attribute name, list index or map key of a path step
+func getPathStepKey(step AttributePathStep) interface{} {
+	if step.Type == "get_attr" {
+		return step.Value
+	}
+
+	if index, ok := step.Value.(map[string]interface{}); ok {
+		return index["value"]
+	}
+
+	return step.Value
+}
+
+func redactValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		return RedactMap(v)
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+
+		for _, elem := range v {
+			res = append(res, redactValue(elem))
+		}
+
+		return res
+	}
+
+	return val
+}
+
+// redactOutputs redacts the value of every output that Terraform marks as sensitive,
+// along with outputs whose names look sensitive
+func redactOutputs(outputs interface{}) interface{} {
+	outputMap, ok := outputs.(map[string]interface{})
+
+	if !ok {
+		return outputs
+	}
+
+	res := make(map[string]interface{}, len(outputMap))
+
+	for name, output := range outputMap {
+		outputObj, ok := output.(map[string]interface{})
+
+		if !ok {
+			res[name] = output
+			continue
+		}
+
+		redactedObj := make(map[string]interface{}, len(outputObj))
+
+		for key, val := range outputObj {
+			redactedObj[key] = val
+		}
+
+		if sensitive, _ := outputObj["sensitive"].(bool); sensitive || IsSensitiveKey(name) {
+			redactedObj["value"] = RedactedValue
+		} else {
+			redactedObj["value"] = redactValue(outputObj["value"])
+		}
+
+		res[name] = redactedObj
+	}
+
+	return res
+}
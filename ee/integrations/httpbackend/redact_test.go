@@ -0,0 +1,109 @@
+package httpbackend
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testState = `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "digitalocean_kubernetes_cluster",
+      "name": "cluster",
+      "instances": [
+        {
+          "attributes": {
+            "name": "porter-cluster",
+            "endpoint": "https://cluster.k8s.ondigitalocean.com",
+            "kube_config": [
+              {
+                "host": "https://cluster.k8s.ondigitalocean.com",
+                "raw_config": "apiVersion: v1\nusers:\n- user:\n    token: dop_v1_abc"
+              }
+            ]
+          },
+          "sensitive_attributes": [
+            [
+              {"type": "get_attr", "value": "kube_config"},
+              {"type": "index", "value": {"value": 0, "type": "number"}},
+              {"type": "get_attr", "value": "raw_config"}
+            ]
+          ]
+        }
+      ]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "db",
+      "instances": [
+        {
+          "attributes": {
+            "identifier": "porter-db",
+            "master": {"user": "porter", "pw": "hunter2"},
+            "tags": {"team": "platform"}
+          },
+          "sensitive_attributes": [
+            [
+              {"type": "get_attr", "value": "master"},
+              {"type": "index", "value": {"value": "pw", "type": "string"}}
+            ],
+            [
+              {"type": "get_attr", "value": "tags"},
+              {"type": "index", "value": {"value": "missing", "type": "string"}}
+            ]
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+func TestRedacted(t *testing.T) {
+	state := &TFState{}
+
+	if err := json.Unmarshal([]byte(testState), state); err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := state.Redacted()
+
+	cluster := redacted.Resources[0].Instances[0].Attributes
+
+	assert.Equal(t, RedactedValue, cluster["kube_config"], "DOKS kube config should be redacted")
+	assert.Equal(t, "porter-cluster", cluster["name"])
+
+	db := redacted.Resources[1].Instances[0].Attributes
+
+	assert.Equal(t, map[string]interface{}{"user": "porter", "pw": RedactedValue}, db["master"],
+		"attributes that the provider marks as sensitive should be redacted")
+
+	// paths that cannot be followed redact the whole attribute
+	assert.Equal(t, RedactedValue, db["tags"])
+	assert.Equal(t, "porter-db", db["identifier"])
+
+	// the state itself is not changed
+	assert.Equal(t, "hunter2", state.Resources[1].Instances[0].Attributes["master"].(map[string]interface{})["pw"])
+}
+
+func TestRedactPath(t *testing.T) {
+	attributes := map[string]interface{}{
+		"node_pool": []interface{}{
+			map[string]interface{}{"name": "default", "bootstrap_token": "abc"},
+		},
+	}
+
+	redactPath(attributes, AttributePath{
+		{Type: "get_attr", Value: "node_pool"},
+		{Type: "index", Value: map[string]interface{}{"value": float64(0), "type": "number"}},
+		{Type: "get_attr", Value: "bootstrap_token"},
+	})
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "default", "bootstrap_token": RedactedValue},
+	}, attributes["node_pool"])
+}
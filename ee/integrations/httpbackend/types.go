@@ -86,6 +86,21 @@ type TFStateResource struct {
 type Instance struct {
 	Attributes   map[string]interface{} `json:"attributes"`
 	Dependencies []string               `json:"dependencies"`
+
+	// SensitiveAttributes are the paths of the attributes that the provider marks as
+	// sensitive
+	SensitiveAttributes []AttributePath `json:"sensitive_attributes,omitempty"`
+}
+
+// AttributePath is a path to a nested value of the attributes of a resource instance
+type AttributePath []AttributePathStep
+
+// AttributePathStep is a step of an attribute path. Steps of type get_attr have the name
+// of an attribute as their value, and steps of type index have an object with the type
+// and value of the index or key.
+type AttributePathStep struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
 }
 
 type AWSVPCConfig struct {
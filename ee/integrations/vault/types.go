@@ -48,6 +48,26 @@ type GetAWSCredentialData struct {
 	Data     *credentials.AWSCredential `json:"data"`
 }
 
+type GetSensitiveValuesCredentialResponse struct {
+	*VaultGetResponse
+	Data *GetSensitiveValuesCredentialData `json:"data"`
+}
+
+type GetSensitiveValuesCredentialData struct {
+	Metadata *VaultMetadata                         `json:"metadata"`
+	Data     *credentials.SensitiveValuesCredential `json:"data"`
+}
+
+type GetProvisionedResourceCredentialResponse struct {
+	*VaultGetResponse
+	Data *GetProvisionedResourceCredentialData `json:"data"`
+}
+
+type GetProvisionedResourceCredentialData struct {
+	Metadata *VaultMetadata                             `json:"metadata"`
+	Data     *credentials.ProvisionedResourceCredential `json:"data"`
+}
+
 type CreatePolicyRequest struct {
 	Policy string `json:"policy"`
 }
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/models/integrations"
 	"github.com/porter-dev/porter/internal/repository/credentials"
 )
@@ -147,6 +148,76 @@ func (c *Client) getAWSCredentialPath(awsIntegration *integrations.AWSIntegratio
 	)
 }
 
+func (c *Client) WriteSensitiveValuesCredential(
+	sensitiveValues *models.SensitiveValues,
+	data *credentials.SensitiveValuesCredential) error {
+	reqData := &CreateVaultSecretRequest{
+		Data: data,
+	}
+
+	return c.postRequest(fmt.Sprintf("/v1/%s", c.getSensitiveValuesCredentialPath(sensitiveValues)), reqData, nil)
+}
+
+func (c *Client) GetSensitiveValuesCredential(
+	sensitiveValues *models.SensitiveValues,
+) (*credentials.SensitiveValuesCredential, error) {
+	resp := &GetSensitiveValuesCredentialResponse{}
+
+	err := c.getRequest(fmt.Sprintf("/v1/%s", c.getSensitiveValuesCredentialPath(sensitiveValues)), resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Data.Data, nil
+}
+
+func (c *Client) getSensitiveValuesCredentialPath(sensitiveValues *models.SensitiveValues) string {
+	return fmt.Sprintf(
+		"kv/data/secret/%s/%d/sensitive_values/%d",
+		c.secretPrefix,
+		sensitiveValues.ProjectID,
+		sensitiveValues.ID,
+	)
+}
+
+func (c *Client) WriteProvisionedResourceCredential(
+	resource *models.ProvisionedResource,
+	data *credentials.ProvisionedResourceCredential) error {
+	reqData := &CreateVaultSecretRequest{
+		Data: data,
+	}
+
+	return c.postRequest(fmt.Sprintf("/v1/%s", c.getProvisionedResourceCredentialPath(resource)), reqData, nil)
+}
+
+func (c *Client) GetProvisionedResourceCredential(
+	resource *models.ProvisionedResource,
+) (*credentials.ProvisionedResourceCredential, error) {
+	resp := &GetProvisionedResourceCredentialResponse{}
+
+	err := c.getRequest(fmt.Sprintf("/v1/%s", c.getProvisionedResourceCredentialPath(resource)), resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Data.Data, nil
+}
+
+// getProvisionedResourceCredentialPath returns the path of the credentials of a bucket or
+// a queue, which is keyed by the kind of the resource since each kind of resource is
+// stored in its own table
+func (c *Client) getProvisionedResourceCredentialPath(resource *models.ProvisionedResource) string {
+	return fmt.Sprintf(
+		"kv/data/secret/%s/%d/provisioned_resources/%s/%d",
+		c.secretPrefix,
+		resource.ProjectID,
+		resource.Kind,
+		resource.ID,
+	)
+}
+
 const readOnlyPolicyTemplate = `path "%s" {
   capabilities = ["read"]
 }`
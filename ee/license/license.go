@@ -0,0 +1,76 @@
+// +build ee
+
+package license
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// PublicKey is the base64-encoded ed25519 public key that licenses are signed with. It
+// is linked by an ldflag during build.
+var PublicKey string
+
+// licenseFile is the format of a license file. The payload is the base64-encoded JSON
+// of the license, and the signature is the base64-encoded signature of the payload.
+type licenseFile struct {
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// LoadFromFile reads a license file and verifies its signature. An expired license is
+// returned without an error, so that the installation keeps running with the enterprise
+// features disabled.
+func LoadFromFile(path string) (*types.License, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read license file: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// Parse verifies the signature of a license file and returns its license
+func Parse(data []byte) (*types.License, error) {
+	pubKey, err := base64.StdEncoding.DecodeString(PublicKey)
+
+	if err != nil || len(pubKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("this build does not support licenses")
+	}
+
+	file := &licenseFile{}
+
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("invalid license file: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(file.Signature)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid license signature: %w", err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(pubKey), []byte(file.Payload), signature) {
+		return nil, fmt.Errorf("license signature could not be verified")
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(file.Payload)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid license payload: %w", err)
+	}
+
+	license := &types.License{}
+
+	if err := json.Unmarshal(payload, license); err != nil {
+		return nil, fmt.Errorf("invalid license payload: %w", err)
+	}
+
+	return license, nil
+}
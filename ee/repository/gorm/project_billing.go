@@ -44,3 +44,13 @@ func (repo *ProjectBillingRepository) ReadProjectBillingByTeamID(teamID string)
 
 	return projBilling, nil
 }
+
+func (repo *ProjectBillingRepository) ListProjectBillings() ([]*models.ProjectBilling, error) {
+	projBillings := []*models.ProjectBilling{}
+
+	if err := repo.db.Order("project_id asc").Find(&projBillings).Error; err != nil {
+		return nil, err
+	}
+
+	return projBillings, nil
+}
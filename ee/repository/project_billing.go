@@ -8,4 +8,5 @@ type ProjectBillingRepository interface {
 	CreateProjectBilling(userBilling *models.ProjectBilling) (*models.ProjectBilling, error)
 	ReadProjectBillingByProjectID(projectID uint) (*models.ProjectBilling, error)
 	ReadProjectBillingByTeamID(teamID string) (*models.ProjectBilling, error)
+	ListProjectBillings() ([]*models.ProjectBilling, error)
 }
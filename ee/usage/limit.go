@@ -21,6 +21,11 @@ func GetLimit(repo repository.Repository, proj *models.Project) (limit *types.Pr
 		limit = &copyBasic
 	} else if err != nil {
 		return nil, err
+	} else if limitModel.IsTrialExpired() {
+		// downgrade projects with an expired trial to the basic plan, so that existing
+		// resources keep working but new ones are limited
+		copyBasic := types.BasicPlan
+		limit = &copyBasic
 	} else {
 		limit = limitModel.ToProjectUsageType()
 	}
@@ -0,0 +1,98 @@
+package addons
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// CacheTemplate is the chart that an in-cluster cache is provisioned from
+type CacheTemplate struct {
+	ChartName string
+	RepoURL   string
+	Port      int
+}
+
+// CacheTemplates are the addon templates of the cache engines
+var CacheTemplates = map[types.CacheEngine]*CacheTemplate{
+	types.CacheEngineRedis: {
+		ChartName: "redis",
+		RepoURL:   "https://charts.bitnami.com/bitnami",
+		Port:      6379,
+	},
+	types.CacheEngineMemcached: {
+		ChartName: "memcached",
+		RepoURL:   "https://charts.bitnami.com/bitnami",
+		Port:      11211,
+	},
+}
+
+// GetCacheHost returns the in-cluster host of the service of a cache
+func GetCacheHost(engine types.CacheEngine, name, namespace string) string {
+	service := name
+
+	// the standalone architecture of the redis chart serves the primary through the
+	// master service
+	if engine == types.CacheEngineRedis {
+		service = fmt.Sprintf("%s-master", name)
+	}
+
+	return fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace)
+}
+
+// GetCacheValues returns the values of the chart of a cache. The full name of the chart
+// is set to the name of the cache, so that the service of the cache has a known name.
+// The password is only used by Redis, as memcached has no authentication.
+func GetCacheValues(engine types.CacheEngine, name, password, storageSize string) map[string]interface{} {
+	values := map[string]interface{}{
+		"fullnameOverride": name,
+	}
+
+	if engine == types.CacheEngineRedis {
+		master := map[string]interface{}{}
+
+		if storageSize != "" {
+			master["persistence"] = map[string]interface{}{
+				"size": storageSize,
+			}
+		}
+
+		values["architecture"] = "standalone"
+		values["auth"] = map[string]interface{}{
+			"enabled":  true,
+			"password": password,
+		}
+		values["master"] = master
+	}
+
+	return values
+}
+
+// GetCacheConnectionVariables returns the env variables and secret env variables with
+// the connection details of a cache, which are prefixed with the name of the engine
+func GetCacheConnectionVariables(
+	engine types.CacheEngine,
+	name, namespace, password string,
+) (map[string]string, map[string]string) {
+	host := GetCacheHost(engine, name, namespace)
+	port := CacheTemplates[engine].Port
+	prefix := strings.ToUpper(string(engine))
+
+	variables := map[string]string{
+		prefix + "_HOST": host,
+		prefix + "_PORT": fmt.Sprintf("%d", port),
+	}
+
+	secretVariables := make(map[string]string)
+
+	switch engine {
+	case types.CacheEngineRedis:
+		secretVariables["REDIS_PASSWORD"] = password
+		secretVariables["REDIS_URL"] = fmt.Sprintf("redis://:%s@%s:%d", password, host, port)
+	case types.CacheEngineMemcached:
+		variables["MEMCACHED_SERVERS"] = fmt.Sprintf("%s:%d", host, port)
+	}
+
+	return variables, secretVariables
+}
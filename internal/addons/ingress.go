@@ -0,0 +1,133 @@
+package addons
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	awsLoadBalancerTypeAnnotation    = "service.beta.kubernetes.io/aws-load-balancer-type"
+	awsEIPAllocationsAnnotation      = "service.beta.kubernetes.io/aws-load-balancer-eip-allocations"
+	doLoadBalancerIDAnnotation       = "kubernetes.digitalocean.com/load-balancer-id"
+	ingressLoadBalancerIPValuesField = "loadBalancerIP"
+)
+
+// IsIngressNginxChart returns true if a chart installs the ingress-nginx controller
+func IsIngressNginxChart(chartName string) bool {
+	return chartName == "ingress-nginx" || chartName == "nginx-ingress"
+}
+
+// GetIngressLoadBalancerValues returns the values of an ingress-nginx addon with the load
+// balancer of the controller pinned to the address that is set for the cluster. The
+// settings of a previous pin are removed from the values if the load balancer is no
+// longer pinned. The values are not modified.
+//
+// svc is the running service of the controller, or nil if the controller is not
+// installed. Elastic IPs can only be assigned to network load balancers, so a load
+// balancer type that already supports them, such as the type of the AWS load balancer
+// controller, is kept instead of recreating the load balancer as an nlb.
+func GetIngressLoadBalancerValues(
+	cluster *models.Cluster,
+	values map[string]interface{},
+	svc *v1.Service,
+) map[string]interface{} {
+	res := copyValuesMap(values)
+	controller := copyValuesMap(getValuesMap(res, "controller"))
+	service := copyValuesMap(getValuesMap(controller, "service"))
+	annotations := copyValuesMap(getValuesMap(service, "annotations"))
+
+	delete(service, ingressLoadBalancerIPValuesField)
+	delete(annotations, awsEIPAllocationsAnnotation)
+	delete(annotations, doLoadBalancerIDAnnotation)
+
+	if lb := cluster.GetIngressLoadBalancer(); lb != nil {
+		if lb.StaticIP != "" {
+			service[ingressLoadBalancerIPValuesField] = lb.StaticIP
+		}
+
+		if len(lb.EIPAllocations) > 0 {
+			annotations[awsEIPAllocationsAnnotation] = strings.Join(lb.EIPAllocations, ",")
+
+			if lbType, _ := annotations[awsLoadBalancerTypeAnnotation].(string); !supportsEIPAllocations(lbType) {
+				annotations[awsLoadBalancerTypeAnnotation] = getServiceLoadBalancerType(svc)
+			}
+		}
+
+		if lb.LoadBalancerID != "" {
+			annotations[doLoadBalancerIDAnnotation] = lb.LoadBalancerID
+		}
+	}
+
+	setValuesMap(service, "annotations", annotations)
+	setValuesMap(controller, "service", service)
+	setValuesMap(res, "controller", controller)
+
+	return res
+}
+
+// CheckIngressLoadBalancer returns an error if the running service of the ingress
+// controller cannot be pinned to a load balancer without being recreated. AWS only
+// assigns elastic IPs when a network load balancer is created, so the elastic IPs of a
+// load balancer that is already provisioned cannot be changed.
+func CheckIngressLoadBalancer(lb *types.IngressLoadBalancer, svc *v1.Service) error {
+	if svc == nil || len(svc.Status.LoadBalancer.Ingress) == 0 {
+		return nil
+	}
+
+	curr := svc.Annotations[awsEIPAllocationsAnnotation]
+
+	if len(lb.EIPAllocations) > 0 && curr != strings.Join(lb.EIPAllocations, ",") {
+		return fmt.Errorf(
+			"the elastic IPs of the load balancer of the ingress controller cannot be changed after it is created. "+
+				"Delete the service %s/%s of the ingress controller to recreate its load balancer with the elastic IPs",
+			svc.Namespace, svc.Name,
+		)
+	}
+
+	return nil
+}
+
+func supportsEIPAllocations(lbType string) bool {
+	return lbType == "nlb" || lbType == "external"
+}
+
+// getServiceLoadBalancerType returns the type of the AWS load balancer of the running
+// service of the controller if it supports elastic IPs, or nlb otherwise
+func getServiceLoadBalancerType(svc *v1.Service) string {
+	if svc != nil && supportsEIPAllocations(svc.Annotations[awsLoadBalancerTypeAnnotation]) {
+		return svc.Annotations[awsLoadBalancerTypeAnnotation]
+	}
+
+	return "nlb"
+}
+
+func getValuesMap(values map[string]interface{}, key string) map[string]interface{} {
+	res, _ := values[key].(map[string]interface{})
+	return res
+}
+
+// copyValuesMap copies the top level of a map of values, so that nested maps that are
+// modified can be replaced without modifying the original values
+func copyValuesMap(values map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+
+	for key, val := range values {
+		res[key] = val
+	}
+
+	return res
+}
+
+// setValuesMap sets a nested map of values, or removes it if it is empty
+func setValuesMap(values map[string]interface{}, key string, val map[string]interface{}) {
+	if len(val) == 0 {
+		delete(values, key)
+		return
+	}
+
+	values[key] = val
+}
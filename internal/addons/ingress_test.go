@@ -0,0 +1,135 @@
+package addons_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/addons"
+	"github.com/porter-dev/porter/internal/models"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	eipAllocationsAnnotation = "service.beta.kubernetes.io/aws-load-balancer-eip-allocations"
+	lbTypeAnnotation         = "service.beta.kubernetes.io/aws-load-balancer-type"
+)
+
+func getIngressTestService(values map[string]interface{}) map[string]interface{} {
+	controller, _ := values["controller"].(map[string]interface{})
+	service, _ := controller["service"].(map[string]interface{})
+
+	return service
+}
+
+func getIngressTestAnnotations(values map[string]interface{}) map[string]interface{} {
+	annotations, _ := getIngressTestService(values)["annotations"].(map[string]interface{})
+	return annotations
+}
+
+func TestGetIngressLoadBalancerValuesStaticIP(t *testing.T) {
+	cluster := &models.Cluster{IngressStaticIP: "34.1.2.3"}
+
+	values := map[string]interface{}{
+		"controller": map[string]interface{}{
+			"replicaCount": 2,
+		},
+	}
+
+	res := addons.GetIngressLoadBalancerValues(cluster, values, nil)
+
+	if ip := getIngressTestService(res)["loadBalancerIP"]; ip != "34.1.2.3" {
+		t.Errorf("expected the static IP to be set, got %v", ip)
+	}
+
+	if res["controller"].(map[string]interface{})["replicaCount"] != 2 {
+		t.Errorf("expected the values of the addon to be kept")
+	}
+
+	if getIngressTestService(values) != nil {
+		t.Errorf("expected the values of the addon not to be modified")
+	}
+}
+
+func TestGetIngressLoadBalancerValuesUnpin(t *testing.T) {
+	values := map[string]interface{}{
+		"controller": map[string]interface{}{
+			"service": map[string]interface{}{
+				"loadBalancerIP": "34.1.2.3",
+				"annotations": map[string]interface{}{
+					lbTypeAnnotation:         "nlb",
+					eipAllocationsAnnotation: "eipalloc-1,eipalloc-2",
+					"custom":                 "value",
+				},
+			},
+		},
+	}
+
+	res := addons.GetIngressLoadBalancerValues(&models.Cluster{}, values, nil)
+
+	if _, ok := getIngressTestService(res)["loadBalancerIP"]; ok {
+		t.Errorf("expected the static IP of the previous pin to be removed")
+	}
+
+	annotations := getIngressTestAnnotations(res)
+
+	if _, ok := annotations[eipAllocationsAnnotation]; ok {
+		t.Errorf("expected the elastic IPs of the previous pin to be removed")
+	}
+
+	// changing the type of the load balancer would recreate it
+	if annotations[lbTypeAnnotation] != "nlb" || annotations["custom"] != "value" {
+		t.Errorf("expected the other annotations to be kept, got %v", annotations)
+	}
+}
+
+func TestGetIngressLoadBalancerValuesEIPAllocations(t *testing.T) {
+	cluster := &models.Cluster{IngressEIPAllocations: "eipalloc-1,eipalloc-2"}
+
+	res := addons.GetIngressLoadBalancerValues(cluster, map[string]interface{}{}, nil)
+	annotations := getIngressTestAnnotations(res)
+
+	if annotations[eipAllocationsAnnotation] != "eipalloc-1,eipalloc-2" || annotations[lbTypeAnnotation] != "nlb" {
+		t.Errorf("expected a network load balancer with the elastic IPs, got %v", annotations)
+	}
+
+	// the type of the AWS load balancer controller also supports elastic IPs
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{lbTypeAnnotation: "external"},
+		},
+	}
+
+	res = addons.GetIngressLoadBalancerValues(cluster, map[string]interface{}{}, svc)
+
+	if lbType := getIngressTestAnnotations(res)[lbTypeAnnotation]; lbType != "external" {
+		t.Errorf("expected the load balancer type of the running service to be kept, got %v", lbType)
+	}
+}
+
+func TestCheckIngressLoadBalancer(t *testing.T) {
+	lb := &types.IngressLoadBalancer{EIPAllocations: []string{"eipalloc-1"}}
+
+	if err := addons.CheckIngressLoadBalancer(lb, nil); err != nil {
+		t.Errorf("expected elastic IPs to be allowed without a running controller, got %v", err)
+	}
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress-nginx-controller", Namespace: "ingress-nginx"},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{Hostname: "lb.elb.amazonaws.com"}},
+			},
+		},
+	}
+
+	if err := addons.CheckIngressLoadBalancer(lb, svc); err == nil {
+		t.Errorf("expected an error for elastic IPs of a provisioned load balancer")
+	}
+
+	svc.Annotations = map[string]string{eipAllocationsAnnotation: "eipalloc-1"}
+
+	if err := addons.CheckIngressLoadBalancer(lb, svc); err != nil {
+		t.Errorf("expected the elastic IPs of the load balancer to be allowed, got %v", err)
+	}
+}
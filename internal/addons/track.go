@@ -0,0 +1,77 @@
+package addons
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// TrackAddon records the chart version of an addon that was installed from a repo, or
+// updates the record if the addon is already tracked
+func TrackAddon(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	repoURL string,
+) (*models.Addon, error) {
+	addon, err := repo.Addon().ReadAddonByName(cluster.ID, helmRelease.Namespace, helmRelease.Name)
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	} else if err == gorm.ErrRecordNotFound {
+		return repo.Addon().CreateAddon(&models.Addon{
+			ProjectID:    cluster.ProjectID,
+			ClusterID:    cluster.ID,
+			Namespace:    helmRelease.Namespace,
+			Name:         helmRelease.Name,
+			ChartName:    helmRelease.Chart.Metadata.Name,
+			ChartVersion: helmRelease.Chart.Metadata.Version,
+			RepoURL:      repoURL,
+		})
+	}
+
+	addon.ChartName = helmRelease.Chart.Metadata.Name
+	addon.ChartVersion = helmRelease.Chart.Metadata.Version
+	addon.RepoURL = repoURL
+
+	return repo.Addon().UpdateAddon(addon)
+}
+
+// UpdateTrackedAddon updates the chart version of an upgraded release, if the release
+// is a tracked addon
+func UpdateTrackedAddon(repo repository.Repository, cluster *models.Cluster, helmRelease *release.Release) error {
+	addon, err := repo.Addon().ReadAddonByName(cluster.ID, helmRelease.Namespace, helmRelease.Name)
+
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if addon.ChartVersion == helmRelease.Chart.Metadata.Version {
+		return nil
+	}
+
+	addon.ChartVersion = helmRelease.Chart.Metadata.Version
+
+	_, err = repo.Addon().UpdateAddon(addon)
+
+	return err
+}
+
+// UntrackAddon stops tracking a release that was uninstalled, if the release is a
+// tracked addon
+func UntrackAddon(repo repository.Repository, cluster *models.Cluster, namespace, name string) error {
+	addon, err := repo.Addon().ReadAddonByName(cluster.ID, namespace, name)
+
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	_, err = repo.Addon().DeleteAddon(addon)
+
+	return err
+}
@@ -0,0 +1,168 @@
+package addons
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/integrations/slack"
+	"github.com/porter-dev/porter/internal/logger"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/redis_stream/lease"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// UpdateChecker checks the addons installed in clusters against the index of their
+// repo, stores the latest version of each chart, and notifies the project through
+// Slack the first time that a new version is found
+type UpdateChecker struct {
+	Repo      repository.Repository
+	ServerURL string
+	Logger    *logger.Logger
+
+	// Leader elects the replica that checks the addons periodically, so that update
+	// notifications are not sent by every replica. If Leader is nil, the addons are
+	// checked by this replica.
+	Leader lease.LeaderElector
+}
+
+func NewUpdateChecker(repo repository.Repository, serverURL string, logger *logger.Logger) *UpdateChecker {
+	return &UpdateChecker{
+		Repo:      repo,
+		ServerURL: serverURL,
+		Logger:    logger,
+	}
+}
+
+// Run checks all addons at the given interval until the context is cancelled
+func (u *UpdateChecker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if u.isLeader(ctx) {
+			if addons, err := u.Repo.Addon().ListAddons(); err == nil {
+				u.Check(addons)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *UpdateChecker) isLeader(ctx context.Context) bool {
+	if u.Leader == nil {
+		return true
+	}
+
+	isLeader, err := u.Leader.IsLeader(ctx)
+
+	if err != nil {
+		u.Logger.Error().Err(err).Msg("error electing the replica that checks for addon updates")
+		return false
+	}
+
+	return isLeader
+}
+
+// Check updates the latest version of each addon. The index of each repo is only loaded
+// once, and addons whose repo cannot be loaded keep the result of the previous check.
+func (u *UpdateChecker) Check(addons []*models.Addon) error {
+	latestVersions := make(map[string]map[string]string)
+	now := time.Now()
+
+	for _, addon := range addons {
+		versions, ok := latestVersions[addon.RepoURL]
+
+		if !ok {
+			versions = loadLatestVersions(addon.RepoURL)
+			latestVersions[addon.RepoURL] = versions
+		}
+
+		latest, ok := versions[addon.ChartName]
+
+		if !ok {
+			continue
+		}
+
+		prevLatest := addon.LatestVersion
+
+		addon.LatestVersion = latest
+		addon.LastCheckedAt = &now
+
+		if _, err := u.Repo.Addon().UpdateAddon(addon); err != nil {
+			return err
+		}
+
+		if addon.IsOutdated() && latest != prevLatest {
+			u.notify(addon)
+		}
+	}
+
+	return nil
+}
+
+// loadLatestVersions returns the latest version of each chart in a repo, or nil if
+// the index of the repo cannot be loaded
+func loadLatestVersions(repoURL string) map[string]string {
+	indexFile, err := loader.LoadRepoIndexPublic(repoURL)
+
+	if err != nil {
+		return nil
+	}
+
+	res := make(map[string]string)
+
+	// entries are sorted by version, with the latest version first
+	for chartName, entryVersions := range indexFile.Entries {
+		if len(entryVersions) > 0 {
+			res[chartName] = entryVersions[0].Version
+		}
+	}
+
+	return res
+}
+
+func (u *UpdateChecker) notify(addon *models.Addon) {
+	cluster, err := u.Repo.Cluster().ReadCluster(addon.ProjectID, addon.ClusterID)
+
+	if err != nil || cluster.NotificationsDisabled {
+		return
+	}
+
+	slackInts, err := u.Repo.SlackIntegration().ListSlackIntegrationsByProjectID(addon.ProjectID)
+
+	if err != nil || len(slackInts) == 0 {
+		return
+	}
+
+	notifier := slack.NewSlackNotifier(nil, slackInts...)
+
+	notifier.Notify(&slack.NotifyOpts{
+		ProjectID:   addon.ProjectID,
+		ClusterID:   addon.ClusterID,
+		ClusterName: cluster.Name,
+		Status:      slack.StatusAddonUpdateAvailable,
+		Info: fmt.Sprintf(
+			"*Installed version:* `%s`\n*Latest version:* `%s`",
+			addon.ChartVersion,
+			addon.LatestVersion,
+		),
+		Name:      addon.Name,
+		Namespace: addon.Namespace,
+		URL: fmt.Sprintf(
+			"%s/applications/%s/%s/%s?project_id=%d",
+			u.ServerURL,
+			url.PathEscape(cluster.Name),
+			addon.Namespace,
+			addon.Name,
+			addon.ProjectID,
+		),
+	})
+}
@@ -0,0 +1,43 @@
+package addons
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/logger"
+)
+
+type testLeader struct {
+	isLeader bool
+	err      error
+}
+
+func (l *testLeader) IsLeader(ctx context.Context) (bool, error) {
+	return l.isLeader, l.err
+}
+
+func TestIsLeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		leader   *testLeader
+		expected bool
+	}{
+		{"leader", &testLeader{isLeader: true}, true},
+		{"other replica", &testLeader{isLeader: false}, false},
+		{"election error", &testLeader{err: errors.New("connection refused")}, false},
+	}
+
+	for _, tt := range tests {
+		u := &UpdateChecker{Logger: logger.NewConsole(false), Leader: tt.leader}
+
+		if res := u.isLeader(context.Background()); res != tt.expected {
+			t.Errorf("%s: expected leader to be %t, got %t", tt.name, tt.expected, res)
+		}
+	}
+
+	// addons are checked by every replica without a leader election
+	if u := (&UpdateChecker{}); !u.isLeader(context.Background()) {
+		t.Errorf("expected the addons to be checked without a leader election")
+	}
+}
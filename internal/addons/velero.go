@@ -0,0 +1,157 @@
+package addons
+
+import (
+	"fmt"
+)
+
+const (
+	// VeleroChartName and VeleroChartRepoURL are the chart that Velero is installed from
+	VeleroChartName    = "velero"
+	VeleroChartRepoURL = "https://vmware-tanzu.github.io/helm-charts"
+
+	veleroAWSPluginImage = "velero/velero-plugin-for-aws:v1.8.0"
+	veleroGCPPluginImage = "velero/velero-plugin-for-gcp:v1.8.0"
+)
+
+type VeleroProvider string
+
+const (
+	VeleroProviderAWS VeleroProvider = "aws"
+	VeleroProviderGCP VeleroProvider = "gcp"
+)
+
+// VeleroStorageOpts is the object storage that Velero stores backups in. S3-compatible
+// buckets, such as DigitalOcean Spaces buckets, use the aws provider with an S3 URL.
+type VeleroStorageOpts struct {
+	Provider VeleroProvider
+	Bucket   string
+	Prefix   string
+	Region   string
+	S3URL    string
+
+	// Credentials are the contents of an AWS shared credentials file for the bucket. They
+	// are never set in the values of the chart, which Helm stores in the release, but in
+	// the existing secret that the values reference.
+	Credentials    string
+	ExistingSecret string
+
+	// ServiceAccountAnnotations are set on the service account of the Velero server when
+	// the bucket is accessed through the identity of the service account, such as IRSA on
+	// EKS and workload identity on GKE
+	ServiceAccountAnnotations map[string]string
+
+	// GCPServiceAccount is the GCP service account that signs the download URLs of backups
+	// when the bucket is accessed through workload identity
+	GCPServiceAccount string
+
+	// Snapshots enables volume snapshots through the provider, which is only supported on
+	// the cloud of the bucket
+	Snapshots bool
+}
+
+// GetAWSCredentials returns an AWS shared credentials file for an access key
+func GetAWSCredentials(accessKeyID, secretAccessKey string) string {
+	return fmt.Sprintf("[default]\naws_access_key_id=%s\naws_secret_access_key=%s\n", accessKeyID, secretAccessKey)
+}
+
+// GetVeleroValues returns the values of the Velero chart that configure the backup
+// storage location, the volume snapshot location and the plugin of the provider
+func GetVeleroValues(opts *VeleroStorageOpts) (map[string]interface{}, error) {
+	var pluginImage string
+	storageConfig := make(map[string]interface{})
+	snapshotConfig := make(map[string]interface{})
+
+	switch opts.Provider {
+	case VeleroProviderAWS:
+		pluginImage = veleroAWSPluginImage
+
+		if opts.Region != "" {
+			storageConfig["region"] = opts.Region
+			snapshotConfig["region"] = opts.Region
+		}
+
+		if opts.S3URL != "" {
+			storageConfig["s3Url"] = opts.S3URL
+			storageConfig["s3ForcePathStyle"] = "true"
+		}
+	case VeleroProviderGCP:
+		pluginImage = veleroGCPPluginImage
+
+		if opts.GCPServiceAccount != "" {
+			storageConfig["serviceAccount"] = opts.GCPServiceAccount
+		}
+	default:
+		return nil, fmt.Errorf("unsupported velero provider %q", opts.Provider)
+	}
+
+	storageLocation := map[string]interface{}{
+		"name":     "default",
+		"provider": string(opts.Provider),
+		"bucket":   opts.Bucket,
+		"default":  true,
+		"config":   storageConfig,
+	}
+
+	if opts.Prefix != "" {
+		storageLocation["prefix"] = opts.Prefix
+	}
+
+	configuration := map[string]interface{}{
+		"backupStorageLocation": []interface{}{storageLocation},
+	}
+
+	if opts.Snapshots {
+		configuration["volumeSnapshotLocation"] = []interface{}{
+			map[string]interface{}{
+				"name":     "default",
+				"provider": string(opts.Provider),
+				"config":   snapshotConfig,
+			},
+		}
+	}
+
+	credentials := map[string]interface{}{
+		"useSecret": false,
+	}
+
+	if opts.ExistingSecret != "" {
+		credentials["useSecret"] = true
+		credentials["existingSecret"] = opts.ExistingSecret
+	} else if len(opts.ServiceAccountAnnotations) == 0 {
+		return nil, fmt.Errorf("velero requires a credentials secret or a service account identity")
+	}
+
+	values := map[string]interface{}{
+		"configuration":    configuration,
+		"snapshotsEnabled": opts.Snapshots,
+		"credentials":      credentials,
+		"initContainers": []interface{}{
+			map[string]interface{}{
+				"name":  fmt.Sprintf("velero-plugin-for-%s", opts.Provider),
+				"image": pluginImage,
+				"volumeMounts": []interface{}{
+					map[string]interface{}{
+						"mountPath": "/target",
+						"name":      "plugins",
+					},
+				},
+			},
+		},
+	}
+
+	if len(opts.ServiceAccountAnnotations) > 0 {
+		annotations := make(map[string]interface{})
+
+		for key, val := range opts.ServiceAccountAnnotations {
+			annotations[key] = val
+		}
+
+		values["serviceAccount"] = map[string]interface{}{
+			"server": map[string]interface{}{
+				"annotations": annotations,
+			},
+		}
+	}
+
+	return values, nil
+}
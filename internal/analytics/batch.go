@@ -0,0 +1,163 @@
+package analytics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/logger"
+)
+
+const (
+	defaultFlushInterval = 5 * time.Second
+	defaultBatchSize     = 100
+
+	// maxQueuedEvents is the number of queued events after which new events are dropped,
+	// so that an unreachable provider does not exhaust the memory of the server
+	maxQueuedEvents = 10000
+
+	maxSendAttempts    = 5
+	initialSendBackoff = time.Second
+)
+
+// batcher queues events, and sends them in batches when the batch size is reached or
+// at the flush interval. Batches that fail to send are retried with exponential backoff.
+type batcher struct {
+	send      func(events []map[string]interface{}) error
+	interval  time.Duration
+	batchSize int
+	logger    *logger.Logger
+
+	mu     sync.Mutex
+	queue  []map[string]interface{}
+	closed bool
+
+	flushCh chan struct{}
+	doneCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newBatcher(
+	send func(events []map[string]interface{}) error,
+	interval time.Duration,
+	batchSize int,
+	logger *logger.Logger,
+) *batcher {
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	b := &batcher{
+		send:      send,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+		flushCh:   make(chan struct{}, 1),
+		doneCh:    make(chan struct{}),
+	}
+
+	b.wg.Add(1)
+	go b.run()
+
+	return b
+}
+
+func (b *batcher) enqueue(event map[string]interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	if len(b.queue) >= maxQueuedEvents {
+		b.logger.Error().Msg("analytics queue is full, dropping event")
+		return
+	}
+
+	b.queue = append(b.queue, event)
+
+	if len(b.queue) >= b.batchSize {
+		select {
+		case b.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *batcher) run() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.doneCh:
+			b.flush()
+			return
+		case <-ticker.C:
+			b.flush()
+		case <-b.flushCh:
+			b.flush()
+		}
+	}
+}
+
+// flush sends all queued events in batches of at most the batch size
+func (b *batcher) flush() {
+	b.mu.Lock()
+	events := b.queue
+	b.queue = nil
+	b.mu.Unlock()
+
+	for len(events) > 0 {
+		size := b.batchSize
+
+		if len(events) < size {
+			size = len(events)
+		}
+
+		b.sendWithRetry(events[:size])
+		events = events[size:]
+	}
+}
+
+func (b *batcher) sendWithRetry(events []map[string]interface{}) {
+	backoff := initialSendBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := b.send(events)
+
+		if err == nil {
+			return
+		}
+
+		if attempt >= maxSendAttempts {
+			b.logger.Error().Err(err).Msgf("dropping %d analytics events after %d attempts", len(events), attempt)
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// close sends the queued events and stops the batcher
+func (b *batcher) close() {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.doneCh)
+	b.wg.Wait()
+}
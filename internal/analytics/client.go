@@ -0,0 +1,124 @@
+package analytics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/internal/logger"
+)
+
+// AnalyticsClient sends the identifies and tracks of this package to an analytics
+// provider
+type AnalyticsClient interface {
+	Identify(segmentIdentifier) error
+	Track(segmentTrack) error
+
+	// Close flushes the events that have not been sent yet
+	Close() error
+}
+
+const (
+	ProviderSegment = "segment"
+	ProviderPostHog = "posthog"
+	ProviderNone    = "none"
+)
+
+type ClientOpts struct {
+	// Provider is one of segment, posthog or none. If unset, Segment is used if a Segment
+	// client key is set.
+	Provider string
+
+	SegmentClientKey string
+
+	PostHogAPIKey string
+	PostHogHost   string
+
+	// FlushInterval and BatchSize determine how often queued events are sent
+	FlushInterval time.Duration
+	BatchSize     int
+
+	// OptedOut returns true if the installation has opted out of analytics, and is
+	// checked before every identify and track
+	OptedOut func() bool
+
+	Logger *logger.Logger
+}
+
+// NewClient returns the client of the analytics provider of the options
+func NewClient(opts *ClientOpts) (AnalyticsClient, error) {
+	provider := opts.Provider
+
+	if provider == "" && opts.SegmentClientKey != "" {
+		provider = ProviderSegment
+	}
+
+	var client AnalyticsClient
+	var err error
+
+	switch provider {
+	case "", ProviderNone:
+		return NoopClient{}, nil
+	case ProviderSegment:
+		if opts.SegmentClientKey == "" {
+			return nil, fmt.Errorf("a segment client key must be set to use the segment analytics provider")
+		}
+
+		client, err = NewSegmentClient(opts.SegmentClientKey, opts.FlushInterval, opts.BatchSize, opts.Logger)
+	case ProviderPostHog:
+		if opts.PostHogAPIKey == "" {
+			return nil, fmt.Errorf("a posthog api key must be set to use the posthog analytics provider")
+		}
+
+		client = NewPostHogClient(opts.PostHogAPIKey, opts.PostHogHost, opts.FlushInterval, opts.BatchSize, opts.Logger)
+	default:
+		return nil, fmt.Errorf("unknown analytics provider %s", provider)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.OptedOut != nil {
+		client = &optOutClient{client, opts.OptedOut}
+	}
+
+	return client, nil
+}
+
+// NoopClient drops all events, for installations without analytics
+type NoopClient struct{}
+
+func (c NoopClient) Identify(identifier segmentIdentifier) error {
+	return nil
+}
+
+func (c NoopClient) Track(track segmentTrack) error {
+	return nil
+}
+
+func (c NoopClient) Close() error {
+	return nil
+}
+
+// optOutClient drops all events while the installation has opted out of analytics
+type optOutClient struct {
+	AnalyticsClient
+
+	optedOut func() bool
+}
+
+func (c *optOutClient) Identify(identifier segmentIdentifier) error {
+	if c.optedOut() {
+		return nil
+	}
+
+	return c.AnalyticsClient.Identify(identifier)
+}
+
+func (c *optOutClient) Track(track segmentTrack) error {
+	if c.optedOut() {
+		return nil
+	}
+
+	return c.AnalyticsClient.Track(track)
+}
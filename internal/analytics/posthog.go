@@ -0,0 +1,100 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/logger"
+)
+
+const defaultPostHogHost = "https://app.posthog.com"
+
+// PostHogClient sends events to the batch API of PostHog, which may be self-hosted
+type PostHogClient struct {
+	apiKey     string
+	host       string
+	httpClient *http.Client
+	batcher    *batcher
+}
+
+func NewPostHogClient(
+	apiKey, host string,
+	flushInterval time.Duration,
+	batchSize int,
+	logger *logger.Logger,
+) *PostHogClient {
+	if host == "" {
+		host = defaultPostHogHost
+	}
+
+	c := &PostHogClient{
+		apiKey: apiKey,
+		host:   strings.TrimSuffix(host, "/"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+
+	c.batcher = newBatcher(c.sendBatch, flushInterval, batchSize, logger)
+
+	return c
+}
+
+func (c *PostHogClient) Identify(identifier segmentIdentifier) error {
+	c.batcher.enqueue(map[string]interface{}{
+		"event":       "$identify",
+		"distinct_id": identifier.getUserId(),
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"properties": map[string]interface{}{
+			"$set": map[string]interface{}(identifier.getTraits()),
+		},
+	})
+
+	return nil
+}
+
+func (c *PostHogClient) Track(track segmentTrack) error {
+	c.batcher.enqueue(map[string]interface{}{
+		"event":       string(track.getEvent()),
+		"distinct_id": track.getUserId(),
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"properties":  map[string]interface{}(track.getProperties()),
+	})
+
+	return nil
+}
+
+func (c *PostHogClient) Close() error {
+	c.batcher.close()
+
+	return nil
+}
+
+func (c *PostHogClient) sendBatch(events []map[string]interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"api_key": c.apiKey,
+		"batch":   events,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Post(fmt.Sprintf("%s/batch/", c.host), "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("posthog batch request failed with status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
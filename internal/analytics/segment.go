@@ -1,77 +1,62 @@
 package analytics
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/porter-dev/porter/internal/logger"
 	segment "gopkg.in/segmentio/analytics-go.v3"
 )
 
-type AnalyticsSegmentClient interface {
-	Identify(segmentIdentifier) error
-	Track(segmentTrack) error
-}
-
-type AnalyticsSegment struct {
+// SegmentClient sends events to Segment. The Segment client batches events, and retries
+// failed batches with backoff.
+type SegmentClient struct {
 	segment.Client
-	isEnabled bool
-	logger    *logger.Logger
 }
 
-// Initialize the segment client and return a superset of it, the AnalyticsSegmentClient will handle cases when
-// the segment client failed on initialization or not enabled
-func InitializeAnalyticsSegmentClient(segmentClientKey string, logger *logger.Logger) AnalyticsSegmentClient {
-	if segmentClientKey != "" {
-
-		client := segment.New(segmentClientKey)
-
-		if client == nil {
-			return &AnalyticsSegment{
-				isEnabled: false,
-				logger:    logger,
-			}
-		}
+func NewSegmentClient(
+	segmentClientKey string,
+	flushInterval time.Duration,
+	batchSize int,
+	logger *logger.Logger,
+) (*SegmentClient, error) {
+	client, err := segment.NewWithConfig(segmentClientKey, segment.Config{
+		Interval:  flushInterval,
+		BatchSize: batchSize,
+		Logger:    &segmentLogger{logger},
+	})
 
-		return &AnalyticsSegment{
-			Client:    client,
-			isEnabled: true,
-			logger:    logger,
-		}
+	if err != nil {
+		return nil, err
 	}
 
-	return &AnalyticsSegment{
-		isEnabled: false,
-		logger:    logger,
-	}
+	return &SegmentClient{client}, nil
 }
 
-//	Superset of segment client identify function, this will accept analytics defined identifiers only
-//	and will log an error if the client is not initialized
-
-func (c *AnalyticsSegment) Identify(identifier segmentIdentifier) error {
-	if !c.isEnabled {
-		c.logger.Error().Msg("Analytics not enabled")
-		return nil
-	}
-
-	err := c.Enqueue(segment.Identify{
+func (c *SegmentClient) Identify(identifier segmentIdentifier) error {
+	return c.Enqueue(segment.Identify{
 		UserId: identifier.getUserId(),
 		Traits: identifier.getTraits(),
 	})
-	return err
 }
 
-//	Superset of segment client track function, this will accept analytics defined tracks only
-//	and will log an error if the client is not initialized
-func (c *AnalyticsSegment) Track(track segmentTrack) error {
-	if !c.isEnabled {
-		c.logger.Error().Msg("Analytics not enabled")
-		return nil
-	}
-
-	err := c.Enqueue(segment.Track{
+func (c *SegmentClient) Track(track segmentTrack) error {
+	return c.Enqueue(segment.Track{
 		UserId:     track.getUserId(),
 		Event:      string(track.getEvent()),
 		Properties: track.getProperties(),
 	})
+}
+
+// segmentLogger writes the logs of the Segment client to the logger of the server
+type segmentLogger struct {
+	logger *logger.Logger
+}
+
+func (l *segmentLogger) Logf(format string, args ...interface{}) {
+	l.logger.Debug().Msg(fmt.Sprintf(format, args...))
+}
 
-	return err
+func (l *segmentLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Error().Msg(fmt.Sprintf(format, args...))
 }
@@ -0,0 +1,222 @@
+package sessionstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// defaultRedisKeyPrefix is the prefix of the redis keys of sessions
+const defaultRedisKeyPrefix = "session:"
+
+var errSessionNotFound = errors.New("session not found")
+
+// RedisStore is a gorilla/sessions store that keeps sessions in redis, with the same
+// cookie encoding as PGStore. Sessions expire through the TTL of their redis key, so
+// expired sessions do not need to be cleaned up.
+type RedisStore struct {
+	Codecs    []securecookie.Codec
+	Options   *sessions.Options
+	Client    *redis.Client
+	KeyPrefix string
+
+	// Fallback is the session repository of a PGStore that sessions are migrated from. If
+	// it is set, sessions that are not found in redis are read from the repository, moved
+	// to redis and deleted from the repository, so that users stay logged in after the
+	// store is switched to redis.
+	Fallback repository.SessionRepository
+}
+
+type NewRedisStoreOpts struct {
+	Client        *redis.Client
+	CookieSecrets []string
+
+	// Fallback is the session repository to migrate sessions from, or nil
+	Fallback repository.SessionRepository
+}
+
+// NewRedisStore takes an initialized redis client and session key pairs to create a
+// session-store in redis.
+func NewRedisStore(opts *NewRedisStoreOpts) (*RedisStore, error) {
+	keyPairs := [][]byte{}
+
+	for _, key := range opts.CookieSecrets {
+		keyPairs = append(keyPairs, []byte(key))
+	}
+
+	return &RedisStore{
+		Codecs: securecookie.CodecsFromPairs(keyPairs...),
+		Options: &sessions.Options{
+			Path:     "/",
+			MaxAge:   86400 * 30,
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+		Client:    opts.Client,
+		KeyPrefix: defaultRedisKeyPrefix,
+		Fallback:  opts.Fallback,
+	}, nil
+}
+
+// MaxLength restricts the maximum length of new sessions to l.
+// If l is 0 there is no limit to the size of a session, use with caution.
+func (store *RedisStore) MaxLength(l int) {
+	for _, c := range store.Codecs {
+		if codec, ok := c.(*securecookie.SecureCookie); ok {
+			codec.MaxLength(l)
+		}
+	}
+}
+
+// MaxAge sets the maximum age for the store and the underlying cookie
+// implementation. Individual sessions can be deleted by setting Options.MaxAge
+// = -1 for that session.
+func (store *RedisStore) MaxAge(age int) {
+	store.Options.MaxAge = age
+
+	// Set the maxAge for each securecookie instance.
+	for _, codec := range store.Codecs {
+		if sc, ok := codec.(*securecookie.SecureCookie); ok {
+			sc.MaxAge(age)
+		}
+	}
+}
+
+// Get Fetches a session for a given name after it has been added to the
+// registry.
+func (store *RedisStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(store, name)
+}
+
+// New returns a new session for the given name without adding it to the registry.
+func (store *RedisStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(store, name)
+
+	opts := *store.Options
+	session.Options = &(opts)
+	session.IsNew = true
+
+	var err error
+	if c, errCookie := r.Cookie(name); errCookie == nil {
+		err = securecookie.DecodeMulti(name, c.Value, &session.ID, store.Codecs...)
+		if err == nil {
+			err = store.load(r.Context(), session)
+
+			if err != nil {
+				if errors.Is(err, errSessionNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+					err = nil
+				} else if strings.Contains(err.Error(), "expired timestamp") {
+					err = nil
+					session.IsNew = false
+				}
+			} else {
+				session.IsNew = false
+			}
+		}
+	}
+
+	store.MaxAge(store.Options.MaxAge)
+
+	return session, err
+}
+
+// Save saves the given session into redis and deletes cookies if needed
+func (store *RedisStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	// Set delete if max-age is < 0
+	if session.Options.MaxAge < 0 {
+		if err := store.Client.Del(r.Context(), store.key(session.ID)).Err(); err != nil {
+			return err
+		}
+
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if session.ID == "" {
+		session.ID = generateSessionID()
+	}
+
+	if err := store.save(r.Context(), session); err != nil {
+		return err
+	}
+
+	// Keep the session ID key in a cookie so it can be looked up in redis later.
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, store.Codecs...)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+// load fetches a session by ID from redis, or migrates it from the fallback repository,
+// and decodes its content into session.Values.
+func (store *RedisStore) load(ctx context.Context, session *sessions.Session) error {
+	data, err := store.Client.Get(ctx, store.key(session.ID)).Bytes()
+
+	if errors.Is(err, redis.Nil) {
+		if store.Fallback == nil {
+			return errSessionNotFound
+		}
+
+		data, err = store.migrate(ctx, session.ID)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return securecookie.DecodeMulti(session.Name(), string(data), &session.Values, store.Codecs...)
+}
+
+// migrate moves a session from the fallback repository to redis, and returns its data
+func (store *RedisStore) migrate(ctx context.Context, id string) ([]byte, error) {
+	res, err := store.Fallback.SelectSession(&models.Session{Key: id})
+
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Until(res.ExpiresAt)
+
+	if ttl <= 0 {
+		return nil, errSessionNotFound
+	}
+
+	if err := store.Client.Set(ctx, store.key(id), res.Data, ttl).Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := store.Fallback.DeleteSession(res); err != nil {
+		return nil, err
+	}
+
+	return res.Data, nil
+}
+
+// save writes encoded session.Values to redis, with a TTL of the expiry of the session
+func (store *RedisStore) save(ctx context.Context, session *sessions.Session) error {
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.Values, store.Codecs...)
+	if err != nil {
+		return err
+	}
+
+	return store.Client.Set(ctx, store.key(session.ID), encoded, time.Until(getExpiresOn(session))).Err()
+}
+
+func (store *RedisStore) key(id string) string {
+	return store.KeyPrefix + id
+}
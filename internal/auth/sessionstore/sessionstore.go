@@ -1,5 +1,6 @@
 // Package sessionstore is a postgresql backend implementation of gorilla/sessions Session interface, based on
-// antonlindstrom/pgstore. Key change is to use GORM instead of typical sql driver using queries.
+// antonlindstrom/pgstore. Key change is to use GORM instead of typical sql driver using queries. A redis
+// backend with the same cookie encoding is implemented by RedisStore.
 package sessionstore
 
 import (
@@ -76,23 +77,10 @@ func (store *PGStore) save(session *sessions.Session) error {
 		return err
 	}
 
-	exOn := session.Values["expires_on"]
-
-	var expiresOn time.Time
-
-	if exOn == nil {
-		expiresOn = time.Now().Add(time.Second * time.Duration(session.Options.MaxAge))
-	} else {
-		expiresOn = exOn.(time.Time)
-		if expiresOn.Sub(time.Now().Add(time.Second*time.Duration(session.Options.MaxAge))) < 0 {
-			expiresOn = time.Now().Add(time.Second * time.Duration(session.Options.MaxAge))
-		}
-	}
-
 	s := &models.Session{
 		Key:       session.ID,
 		Data:      []byte(encoded),
-		ExpiresAt: expiresOn,
+		ExpiresAt: getExpiresOn(session),
 	}
 
 	repo := store.Repo
@@ -205,11 +193,7 @@ func (store *PGStore) Save(r *http.Request, w http.ResponseWriter, session *sess
 	}
 
 	if session.ID == "" {
-		// Generate a random session ID key suitable for storage in the DB
-		session.ID = strings.TrimRight(
-			base32.StdEncoding.EncodeToString(
-				securecookie.GenerateRandomKey(32),
-			), "=")
+		session.ID = generateSessionID()
 	}
 
 	if err := store.save(session); err != nil {
@@ -225,3 +209,23 @@ func (store *PGStore) Save(r *http.Request, w http.ResponseWriter, session *sess
 	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
 	return nil
 }
+
+// getExpiresOn returns the expiry of a session, which is the later of the expires_on
+// value of the session and the max age of the session from now
+func getExpiresOn(session *sessions.Session) time.Time {
+	maxAgeExpiresOn := time.Now().Add(time.Second * time.Duration(session.Options.MaxAge))
+
+	if exOn, ok := session.Values["expires_on"].(time.Time); ok && exOn.After(maxAgeExpiresOn) {
+		return exOn
+	}
+
+	return maxAgeExpiresOn
+}
+
+// generateSessionID generates a random session ID key suitable for storage in the DB
+func generateSessionID() string {
+	return strings.TrimRight(
+		base32.StdEncoding.EncodeToString(
+			securecookie.GenerateRandomKey(32),
+		), "=")
+}
@@ -50,6 +50,22 @@ type BillingManager interface {
 
 	// VerifySignature verifies the signature for a webhook
 	VerifySignature(signature string, body []byte) bool
+
+	// ListBilledProjectIDs lists the ids of the projects that have a billing team, whose
+	// usage should be reported
+	ListBilledProjectIDs() ([]uint, error)
+
+	// ReportUsage reports the current usage of the metered features of a project
+	ReportUsage(proj *models.Project, usage *types.ProjectUsage) error
+
+	// GetUpcomingInvoice gets the next invoice of a project, or nil if there is none
+	GetUpcomingInvoice(proj *models.Project) (*types.Invoice, error)
+
+	// ListInvoices lists the past invoices of a project, newest first
+	ListInvoices(proj *models.Project) ([]*types.Invoice, error)
+
+	// GetPaymentMethod gets the default payment method of a project
+	GetPaymentMethod(proj *models.Project) (*types.PaymentMethod, error)
 }
 
 // NoopBillingManager performs no billing operations
@@ -102,3 +118,23 @@ func (n *NoopBillingManager) ParseProjectUsageFromWebhook(payload []byte) (*mode
 func (n *NoopBillingManager) VerifySignature(signature string, body []byte) bool {
 	return false
 }
+
+func (n *NoopBillingManager) ListBilledProjectIDs() ([]uint, error) {
+	return nil, nil
+}
+
+func (n *NoopBillingManager) ReportUsage(proj *models.Project, usage *types.ProjectUsage) error {
+	return nil
+}
+
+func (n *NoopBillingManager) GetUpcomingInvoice(proj *models.Project) (*types.Invoice, error) {
+	return nil, nil
+}
+
+func (n *NoopBillingManager) ListInvoices(proj *models.Project) ([]*types.Invoice, error) {
+	return []*types.Invoice{}, nil
+}
+
+func (n *NoopBillingManager) GetPaymentMethod(proj *models.Project) (*types.PaymentMethod, error) {
+	return &types.PaymentMethod{}, nil
+}
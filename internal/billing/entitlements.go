@@ -0,0 +1,122 @@
+package billing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/usage"
+	"gorm.io/gorm"
+)
+
+// EntitlementManager maps the features of the billing plan of a project to the
+// capabilities of the app that the project can use
+type EntitlementManager struct {
+	repo repository.Repository
+
+	// billingEnabled is false for instances without billing, where every project is
+	// entitled to every capability
+	billingEnabled bool
+
+	// usageTrackingEnabled is false for instances that do not enforce usage limits, where
+	// the number of clusters is unlimited
+	usageTrackingEnabled bool
+
+	// license is the offline license of an installation without billing, which then
+	// determines the entitlements of every project
+	license *types.License
+}
+
+func NewEntitlementManager(
+	repo repository.Repository,
+	billingEnabled, usageTrackingEnabled bool,
+	license *types.License,
+) *EntitlementManager {
+	return &EntitlementManager{repo, billingEnabled, usageTrackingEnabled, license}
+}
+
+// GetEntitlements returns the entitlements of a project. Projects without a plan are
+// entitled to every capability, like they are on the enterprise plan, and projects whose
+// trial has expired are downgraded to the basic plan rather than returning an error.
+func (e *EntitlementManager) GetEntitlements(proj *models.Project) (*types.ProjectEntitlements, error) {
+	res := &types.ProjectEntitlements{
+		PreviewEnvironments: true,
+		SSO:                 true,
+	}
+
+	if e.usageTrackingEnabled {
+		limit, err := usage.GetLimit(e.repo, proj)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.MaxClusters = limit.Clusters
+	}
+
+	if !e.billingEnabled && e.license != nil {
+		return getLicenseEntitlements(e.license), nil
+	}
+
+	if !e.billingEnabled {
+		return res, nil
+	}
+
+	plan, err := e.repo.ProjectUsage().ReadProjectUsage(proj.ID)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	res.IsTrial = plan.TrialEndsAt != nil
+	res.TrialEndsAt = plan.TrialEndsAt
+	res.TrialExpired = plan.IsTrialExpired()
+
+	// preview environments that were enabled for a project before they were part of a
+	// plan stay enabled
+	res.PreviewEnvironments = proj.PreviewEnvsEnabled || (plan.PreviewEnvironments && !res.TrialExpired)
+	res.SSO = plan.SSO && !res.TrialExpired
+
+	return res, nil
+}
+
+// HasEntitlement returns true if the project is entitled to a capability
+func (e *EntitlementManager) HasEntitlement(proj *models.Project, entitlement types.Entitlement) (bool, error) {
+	entitlements, err := e.GetEntitlements(proj)
+
+	if err != nil {
+		return false, err
+	}
+
+	switch entitlement {
+	case types.EntitlementPreviewEnvironments:
+		return entitlements.PreviewEnvironments, nil
+	case types.EntitlementSSO:
+		return entitlements.SSO, nil
+	}
+
+	return false, fmt.Errorf("unknown entitlement %s", entitlement)
+}
+
+// getLicenseEntitlements returns the entitlements of an offline license. Like an expired
+// trial, an expired license is downgraded to the basic plan. The expiry of the license
+// is surfaced by the metadata endpoint.
+func getLicenseEntitlements(license *types.License) *types.ProjectEntitlements {
+	res := &types.ProjectEntitlements{
+		PreviewEnvironments: license.Entitlements.PreviewEnvironments,
+		SSO:                 license.Entitlements.SSO,
+		MaxClusters:         license.Entitlements.MaxClusters,
+	}
+
+	if license.IsExpired() {
+		res.PreviewEnvironments = false
+		res.SSO = false
+		res.MaxClusters = types.BasicPlan.Clusters
+	}
+
+	return res
+}
@@ -0,0 +1,121 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"github.com/porter-dev/porter/internal/logger"
+	"github.com/porter-dev/porter/internal/redis_stream/lease"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/usage"
+	"golang.org/x/oauth2"
+)
+
+// UsageReporter reports the usage of the metered features of billed projects. CPU and
+// memory are read from the project usage cache, which is refreshed from the clusters of
+// the project when it is stale.
+type UsageReporter struct {
+	Repo             repository.Repository
+	DOConf           *oauth2.Config
+	BillingManager   BillingManager
+	WhitelistedUsers *usage.WhitelistedUsers
+	Logger           *logger.Logger
+
+	// Leader elects the replica that reports usage, so that usage is not reported by
+	// every replica. If Leader is nil, usage is reported by this replica.
+	Leader lease.LeaderElector
+
+	// Inventory is the cache of cluster inventories that CPU and memory are read from,
+	// which is shared with the inventory endpoint
+	Inventory *usage.InventoryCache
+}
+
+func NewUsageReporter(
+	repo repository.Repository,
+	doConf *oauth2.Config,
+	billingManager BillingManager,
+	whitelistedUsers *usage.WhitelistedUsers,
+	logger *logger.Logger,
+) *UsageReporter {
+	return &UsageReporter{
+		Repo:             repo,
+		DOConf:           doConf,
+		BillingManager:   billingManager,
+		WhitelistedUsers: whitelistedUsers,
+		Logger:           logger,
+	}
+}
+
+// Run reports the usage of all billed projects at the given interval until the context
+// is cancelled, if this replica is elected to report usage
+func (u *UsageReporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if u.isLeader(ctx) {
+			u.ReportAll()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReportAll reports the usage of all billed projects. A project that fails is skipped,
+// and is reported again at the next interval.
+func (u *UsageReporter) ReportAll() {
+	projIDs, err := u.BillingManager.ListBilledProjectIDs()
+
+	if err != nil {
+		u.Logger.Error().Err(err).Msg("error listing billed projects")
+		return
+	}
+
+	for _, projID := range projIDs {
+		if err := u.ReportProject(projID); err != nil {
+			u.Logger.Error().Err(err).Msgf("error reporting usage of project %d", projID)
+		}
+	}
+}
+
+func (u *UsageReporter) isLeader(ctx context.Context) bool {
+	if u.Leader == nil {
+		return true
+	}
+
+	isLeader, err := u.Leader.IsLeader(ctx)
+
+	if err != nil {
+		u.Logger.Error().Err(err).Msg("error electing the replica that reports usage")
+		return false
+	}
+
+	return isLeader
+}
+
+// ReportProject reports the current usage of a single project
+func (u *UsageReporter) ReportProject(projID uint) error {
+	proj, err := u.Repo.Project().ReadProject(projID)
+
+	if err != nil {
+		return err
+	}
+
+	current, _, _, err := usage.GetUsage(&usage.GetUsageOpts{
+		Repo:             u.Repo,
+		DOConf:           u.DOConf,
+		Project:          proj,
+		WhitelistedUsers: u.WhitelistedUsers.Map(),
+		Inventory:        u.Inventory,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return u.BillingManager.ReportUsage(proj, current)
+}
@@ -0,0 +1,113 @@
+package billing_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/billing"
+	"github.com/porter-dev/porter/internal/logger"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+	"github.com/porter-dev/porter/internal/usage"
+)
+
+// usageTestBillingManager records the usage that is reported for each project
+type usageTestBillingManager struct {
+	billing.NoopBillingManager
+
+	projIDs []uint
+
+	mu       sync.Mutex
+	reported map[uint]*types.ProjectUsage
+}
+
+func (b *usageTestBillingManager) ListBilledProjectIDs() ([]uint, error) {
+	return b.projIDs, nil
+}
+
+func (b *usageTestBillingManager) ReportUsage(proj *models.Project, usage *types.ProjectUsage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reported[proj.ID] = usage
+
+	return nil
+}
+
+type usageTestLeader struct {
+	isLeader bool
+	err      error
+}
+
+func (l *usageTestLeader) IsLeader(ctx context.Context) (bool, error) {
+	return l.isLeader, l.err
+}
+
+func getUsageTestReporter(t *testing.T, projIDs ...uint) (*billing.UsageReporter, *usageTestBillingManager) {
+	repo := test.NewRepository(true)
+
+	if _, err := repo.Project().CreateProject(&models.Project{Name: "project"}); err != nil {
+		t.Fatal(err)
+	}
+
+	billingManager := &usageTestBillingManager{
+		projIDs:  projIDs,
+		reported: make(map[uint]*types.ProjectUsage),
+	}
+
+	reporter := billing.NewUsageReporter(
+		repo,
+		nil,
+		billingManager,
+		usage.NewWhitelistedUsers(nil, repo.InstanceAdmin(), time.Hour),
+		logger.NewConsole(false),
+	)
+
+	return reporter, billingManager
+}
+
+func TestReportAllSkipsFailedProjects(t *testing.T) {
+	// project 2 does not exist, so its usage cannot be read
+	reporter, billingManager := getUsageTestReporter(t, 2, 1)
+
+	reporter.ReportAll()
+
+	if _, ok := billingManager.reported[1]; !ok {
+		t.Errorf("expected the usage of project 1 to be reported after project 2 failed")
+	}
+
+	if len(billingManager.reported) != 1 {
+		t.Errorf("expected only the usage of project 1 to be reported, got %v", billingManager.reported)
+	}
+}
+
+func TestRunReportsUsageOnLeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		leader   *usageTestLeader
+		expected bool
+	}{
+		{"leader", &usageTestLeader{isLeader: true}, true},
+		{"other replica", &usageTestLeader{isLeader: false}, false},
+		{"election error", &usageTestLeader{err: errors.New("connection refused")}, false},
+	}
+
+	for _, tt := range tests {
+		reporter, billingManager := getUsageTestReporter(t, 1)
+		reporter.Leader = tt.leader
+
+		// usage is reported once before the cancelled context stops the reporter
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		reporter.Run(ctx, time.Hour)
+
+		if _, ok := billingManager.reported[1]; ok != tt.expected {
+			t.Errorf("%s: expected usage to be reported to be %t, got %t", tt.name, tt.expected, ok)
+		}
+	}
+}
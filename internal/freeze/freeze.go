@@ -0,0 +1,101 @@
+package freeze
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// Validate returns an error if a freeze has neither a valid schedule nor a valid range
+func Validate(f *models.DeployFreeze) error {
+	if f.Schedule != "" {
+		if f.StartsAt != nil || f.EndsAt != nil {
+			return fmt.Errorf("a freeze cannot have both a schedule and a range")
+		}
+
+		if _, err := ParseSchedule(f.Schedule); err != nil {
+			return fmt.Errorf("invalid schedule: %w", err)
+		}
+
+		if f.DurationMinutes == 0 {
+			return fmt.Errorf("a scheduled freeze must have a duration")
+		}
+
+		if _, err := time.LoadLocation(f.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+
+		return nil
+	}
+
+	if f.StartsAt == nil || f.EndsAt == nil {
+		return fmt.Errorf("a freeze must have either a schedule or a start and end")
+	}
+
+	if !f.EndsAt.After(*f.StartsAt) {
+		return fmt.Errorf("the end of a freeze must be after its start")
+	}
+
+	return nil
+}
+
+// ActiveUntil returns the end of the window of a freeze that the given time is in, if
+// the freeze is active at the time
+func ActiveUntil(f *models.DeployFreeze, now time.Time) (time.Time, bool, error) {
+	if f.Schedule == "" {
+		if f.StartsAt == nil || f.EndsAt == nil {
+			return time.Time{}, false, nil
+		}
+
+		return *f.EndsAt, !now.Before(*f.StartsAt) && now.Before(*f.EndsAt), nil
+	}
+
+	schedule, err := ParseSchedule(f.Schedule)
+
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	// the timezone defaults to UTC when it is empty
+	loc, err := time.LoadLocation(f.Timezone)
+
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	duration := time.Duration(f.DurationMinutes) * time.Minute
+	start, ok := schedule.LastRun(now.In(loc), duration)
+
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	return start.Add(duration), true, nil
+}
+
+// FindActive returns the active freeze that applies to a cluster and ends last, and the
+// end of its current window. Freezes of all clusters of a project apply to every
+// cluster, and a cluster ID of 0 only matches these freezes.
+func FindActive(freezes []*models.DeployFreeze, clusterID uint, now time.Time) (*models.DeployFreeze, time.Time, error) {
+	var res *models.DeployFreeze
+	var resEnd time.Time
+
+	for _, f := range freezes {
+		if f.ClusterID != 0 && f.ClusterID != clusterID {
+			continue
+		}
+
+		end, active, err := ActiveUntil(f, now)
+
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("invalid deploy freeze %d: %w", f.ID, err)
+		}
+
+		if active && (res == nil || end.After(resEnd)) {
+			res, resEnd = f, end
+		}
+	}
+
+	return res, resEnd, nil
+}
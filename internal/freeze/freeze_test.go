@@ -0,0 +1,130 @@
+package freeze_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/freeze"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestParseSchedule(t *testing.T) {
+	valid := []string{"* * * * *", "0 18 * * 5", "*/15 9-17 * * 1-5", "0,30 * 1 1,6 *", "0 0 * * 7"}
+
+	for _, expr := range valid {
+		if _, err := freeze.ParseSchedule(expr); err != nil {
+			t.Errorf("expected schedule %q to be valid, got %v", expr, err)
+		}
+	}
+
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"}
+
+	for _, expr := range invalid {
+		if _, err := freeze.ParseSchedule(expr); err == nil {
+			t.Errorf("expected schedule %q to be invalid", expr)
+		}
+	}
+}
+
+func TestScheduledFreeze(t *testing.T) {
+	// from friday at 18:00 until monday at 08:00
+	f := &models.DeployFreeze{
+		Schedule:        "0 18 * * 5",
+		DurationMinutes: 62 * 60,
+		Timezone:        "America/New_York",
+	}
+
+	loc, _ := time.LoadLocation("America/New_York")
+
+	tests := []struct {
+		time   time.Time
+		active bool
+	}{
+		{time.Date(2022, 3, 4, 17, 59, 0, 0, loc), false},
+		{time.Date(2022, 3, 4, 18, 0, 0, 0, loc), true},
+		{time.Date(2022, 3, 6, 12, 0, 0, 0, loc), true},
+		{time.Date(2022, 3, 7, 7, 59, 0, 0, loc), true},
+		{time.Date(2022, 3, 7, 8, 0, 0, 0, loc), false},
+		{time.Date(2022, 3, 9, 12, 0, 0, 0, loc), false},
+	}
+
+	for _, test := range tests {
+		end, active, err := freeze.ActiveUntil(f, test.time.UTC())
+
+		if err != nil {
+			t.Fatalf("error checking freeze: %v", err)
+		}
+
+		if active != test.active {
+			t.Errorf("expected freeze active to be %t at %s, got %t", test.active, test.time, active)
+		}
+
+		if active && !end.Equal(time.Date(2022, 3, 7, 8, 0, 0, 0, loc)) {
+			t.Errorf("expected freeze to end on monday at 08:00, got %s", end.In(loc))
+		}
+	}
+}
+
+func TestFindActive(t *testing.T) {
+	now := time.Date(2022, 3, 4, 12, 0, 0, 0, time.UTC)
+	start, projectEnd, clusterEnd := now.Add(-time.Hour), now.Add(time.Hour), now.Add(2*time.Hour)
+
+	freezes := []*models.DeployFreeze{
+		{ProjectID: 1, StartsAt: &start, EndsAt: &projectEnd},
+		{ProjectID: 1, ClusterID: 2, StartsAt: &start, EndsAt: &clusterEnd},
+	}
+
+	if f, end, _ := freeze.FindActive(freezes, 2, now); f != freezes[1] || !end.Equal(clusterEnd) {
+		t.Errorf("expected the cluster freeze to be active for cluster 2")
+	}
+
+	if f, _, _ := freeze.FindActive(freezes, 3, now); f != freezes[0] {
+		t.Errorf("expected the project freeze to be active for cluster 3")
+	}
+
+	if f, _, _ := freeze.FindActive(freezes, 2, clusterEnd); f != nil {
+		t.Errorf("expected no freeze to be active after the freezes end")
+	}
+}
+
+func TestLastRun(t *testing.T) {
+	exprs := []string{"0 18 * * 5", "*/15 9-17 * * 1-5", "30 2 1,15 * 0", "0 0 29 2 *", "59 23 * 12 *"}
+	windows := []time.Duration{time.Minute, 90 * time.Minute, 62 * time.Hour, 40 * 24 * time.Hour}
+
+	loc, _ := time.LoadLocation("America/New_York")
+	times := []time.Time{
+		time.Date(2022, 3, 4, 18, 0, 0, 0, loc),
+		time.Date(2022, 3, 13, 2, 45, 0, 0, loc),
+		time.Date(2022, 3, 15, 9, 14, 30, 0, loc),
+		time.Date(2022, 1, 2, 0, 5, 0, 0, time.UTC),
+	}
+
+	for _, expr := range exprs {
+		schedule, err := freeze.ParseSchedule(expr)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, now := range times {
+			for _, window := range windows {
+				run, ok := schedule.LastRun(now, window)
+
+				// the last run is found by checking every minute of the window
+				var expRun time.Time
+				expOK := false
+
+				for curr := now.Truncate(time.Minute); curr.After(now.Truncate(time.Minute).Add(-window)); curr = curr.Add(-time.Minute) {
+					if schedule.Matches(curr) {
+						expRun, expOK = curr, true
+						break
+					}
+				}
+
+				if ok != expOK || !run.Equal(expRun) {
+					t.Errorf("expected last run of %q at %s within %s to be %s (%t), got %s (%t)", expr, now, window, expRun, expOK, run, ok)
+				}
+			}
+		}
+	}
+}
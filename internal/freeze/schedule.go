@@ -0,0 +1,226 @@
+package freeze
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a cron expression with the five standard fields: minute, hour, day of
+// month, month and day of week. Each field is a list of values, ranges and steps, such
+// as "*", "1-5", "*/15" or "0,30".
+type Schedule struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+
+	// dom and dow are true if the day of month and day of week are restricted. As in cron,
+	// a time matches if either field matches when both are restricted.
+	dom bool
+	dow bool
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseSchedule parses a cron expression with five fields
+func ParseSchedule(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("schedule must have %d fields, got %d", len(fields), len(parts))
+	}
+
+	bits := make([]uint64, len(fields))
+
+	for i, part := range parts {
+		var err error
+
+		bits[i], err = parseField(part, fields[i])
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// both 0 and 7 are sunday
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &Schedule{
+		minutes:  bits[0],
+		hours:    bits[1],
+		days:     bits[2],
+		months:   bits[3],
+		weekdays: bits[4],
+		dom:      parts[2] != "*",
+		dow:      parts[4] != "*",
+	}, nil
+}
+
+func parseField(expr string, f field) (uint64, error) {
+	var res uint64
+
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+
+		if i := strings.Index(item, "/"); i != -1 {
+			var err error
+
+			rangeExpr = item[:i]
+			step, err = strconv.Atoi(item[i+1:])
+
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field: %s", f.name, item)
+			}
+		}
+
+		start, end := f.min, f.max
+
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+
+			var err error
+
+			start, err = strconv.Atoi(bounds[0])
+
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in %s field: %s", f.name, item)
+			}
+
+			end = start
+
+			if len(bounds) == 2 {
+				end, err = strconv.Atoi(bounds[1])
+
+				if err != nil {
+					return 0, fmt.Errorf("invalid value in %s field: %s", f.name, item)
+				}
+			} else if step != 1 {
+				// a single value with a step, such as "5/15", runs from the value to the max
+				end = f.max
+			}
+		}
+
+		if start < f.min || end > f.max || start > end {
+			return 0, fmt.Errorf("%s field must be between %d and %d: %s", f.name, f.min, f.max, item)
+		}
+
+		for v := start; v <= end; v += step {
+			res |= 1 << uint(v)
+		}
+	}
+
+	return res, nil
+}
+
+// Matches returns true if the schedule runs at the minute of the given time
+func (s *Schedule) Matches(t time.Time) bool {
+	return s.minutes&(1<<uint(t.Minute())) != 0 &&
+		s.hours&(1<<uint(t.Hour())) != 0 &&
+		s.matchesDay(t)
+}
+
+// matchesDay returns true if the schedule runs on the day of the given time
+func (s *Schedule) matchesDay(t time.Time) bool {
+	if s.months&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	domMatch := s.days&(1<<uint(t.Day())) != 0
+	dowMatch := s.weekdays&(1<<uint(t.Weekday())) != 0
+
+	if s.dom && s.dow {
+		return domMatch || dowMatch
+	}
+
+	return domMatch && dowMatch
+}
+
+// LastRun returns the last time that the schedule ran at or before the given time, if
+// it ran within the window before the time. Days are searched backwards from the time,
+// and the last hour and minute that the schedule runs on a day are found from the bits
+// of the schedule.
+func (s *Schedule) LastRun(t time.Time, window time.Duration) (time.Time, bool) {
+	t = t.Truncate(time.Minute)
+	earliest := t.Add(-window)
+	loc := t.Location()
+
+	for day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc); ; day = day.AddDate(0, 0, -1) {
+		// runs on earlier days are before the end of this day, so they are out of the
+		// window as well
+		if !time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 0, 0, loc).After(earliest) {
+			return time.Time{}, false
+		}
+
+		if !s.matchesDay(day) {
+			continue
+		}
+
+		isToday := day.Year() == t.Year() && day.YearDay() == t.YearDay()
+		maxHour := 23
+
+		if isToday {
+			maxHour = t.Hour()
+		}
+
+		for hour := lastBit(s.hours, maxHour); hour >= 0; hour = lastBit(s.hours, hour-1) {
+			maxMinute := 59
+
+			if isToday && hour == t.Hour() {
+				maxMinute = t.Minute()
+			}
+
+			minute := lastBit(s.minutes, maxMinute)
+
+			if minute < 0 {
+				continue
+			}
+
+			run := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+
+			// times that do not exist on days when the clock changes are normalized
+			// after the time
+			if run.After(t) {
+				continue
+			}
+
+			if !run.After(earliest) {
+				return time.Time{}, false
+			}
+
+			return run, true
+		}
+	}
+}
+
+// lastBit returns the highest set bit of the bitmask that is at most max, or -1 if no
+// such bit is set
+func lastBit(mask uint64, max int) int {
+	if max < 0 {
+		return -1
+	}
+
+	mask &= (1 << uint(max+1)) - 1
+
+	if mask == 0 {
+		return -1
+	}
+
+	return 63 - bits.LeadingZeros64(mask)
+}
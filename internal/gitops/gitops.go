@@ -0,0 +1,314 @@
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	ghinstallation "github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v41/github"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/integrations/githubapp"
+	"github.com/porter-dev/porter/internal/logger"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/repository"
+	"golang.org/x/oauth2"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"sigs.k8s.io/yaml"
+)
+
+// ReleaseFile is the file that mirrors the chart version and values of a release in the
+// git repository. Sensitive values are mirrored as their placeholders, and values whose
+// keys are named like credentials are redacted.
+type ReleaseFile struct {
+	Chart   string                 `json:"chart"`
+	RepoURL string                 `json:"repo_url,omitempty"`
+	Version string                 `json:"version"`
+	Values  map[string]interface{} `json:"values"`
+
+	// Revision is the revision of the release that the file was exported from. Files
+	// that were exported from an older revision than the current revision of their
+	// release are not reconciled, since the release was changed after the file.
+	Revision int `json:"revision,omitempty"`
+}
+
+// Syncer mirrors the releases of projects to the git repository of their gitops config,
+// and reconciles the releases with changes that are pushed to the repository
+type Syncer struct {
+	Repo          repository.Repository
+	GithubAppConf *oauth.GithubAppConf
+	DOConf        *oauth2.Config
+	Logger        *logger.Logger
+
+	// GithubAppTokens caches the access tokens of the Github App installations of the
+	// repositories. If it is nil, a new token is requested for each client.
+	GithubAppTokens *githubapp.TokenCache
+
+	// Upgrade upgrades the releases whose files changed when a commit is reconciled. The
+	// server sets it to the shared upgrade path of releases, so that reconciles get the
+	// same protection and deploy checks as other upgrades.
+	Upgrade UpgradeFunc
+}
+
+// UpgradeFunc upgrades a release to a set of values and, if it is set, a new chart from
+// the repository with the given repo URL
+type UpgradeFunc func(
+	cluster *models.Cluster,
+	namespace, name string,
+	values map[string]interface{},
+	ch *chart.Chart,
+	repoURL string,
+	message string,
+) error
+
+func NewSyncer(
+	repo repository.Repository,
+	githubAppConf *oauth.GithubAppConf,
+	doConf *oauth2.Config,
+	logger *logger.Logger,
+) *Syncer {
+	return &Syncer{
+		Repo:          repo,
+		GithubAppConf: githubAppConf,
+		DOConf:        doConf,
+		Logger:        logger,
+	}
+}
+
+// GetReleaseFilePath returns the path of the file of a release in the git repository,
+// which is <path>/cluster-<cluster id>/<namespace>/<name>.yaml
+func GetReleaseFilePath(conf *models.GitOpsConfig, clusterID uint, namespace, name string) string {
+	return path.Join(conf.Path, fmt.Sprintf("cluster-%d", clusterID), namespace, name+".yaml")
+}
+
+// parseReleaseFilePath returns the cluster id, namespace and name of the release of a
+// file in the git repository, or ok=false if the file does not mirror a release
+func parseReleaseFilePath(conf *models.GitOpsConfig, filePath string) (clusterID uint, namespace, name string, ok bool) {
+	relPath := strings.TrimPrefix(filePath, strings.TrimSuffix(conf.Path, "/")+"/")
+	parts := strings.Split(relPath, "/")
+
+	if relPath == filePath || len(parts) != 3 || !strings.HasSuffix(parts[2], ".yaml") {
+		return 0, "", "", false
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(parts[0], "cluster-"), 10, 64)
+
+	if err != nil || !strings.HasPrefix(parts[0], "cluster-") {
+		return 0, "", "", false
+	}
+
+	return uint(id), parts[1], strings.TrimSuffix(parts[2], ".yaml"), true
+}
+
+// maxExportAttempts is the number of times that a file is committed when the file is
+// changed by another commit at the same time, such as the export of another revision
+const maxExportAttempts = 3
+
+// ExportRelease commits the chart version and values of a release to the git repository,
+// authored by the user that changed the release. Nothing is committed if the file of the
+// release is unchanged, such as when a change that was pushed to the repository is
+// reconciled, or if the file was already exported from a newer revision.
+func (s *Syncer) ExportRelease(
+	conf *models.GitOpsConfig,
+	cluster *models.Cluster,
+	helmRelease *release.Release,
+	repoURL string,
+	user *models.User,
+) error {
+	client, err := s.getClient(conf)
+
+	if err != nil {
+		return err
+	}
+
+	contents, err := getReleaseFileContents(helmRelease, repoURL)
+
+	if err != nil {
+		return err
+	}
+
+	filePath := GetReleaseFilePath(conf, cluster.ID, helmRelease.Namespace, helmRelease.Name)
+
+	for attempt := 1; ; attempt++ {
+		err = s.commitReleaseFile(client, conf, helmRelease, filePath, contents, user)
+
+		var ghErr *github.ErrorResponse
+
+		if attempt >= maxExportAttempts || !errors.As(err, &ghErr) || ghErr.Response == nil ||
+			ghErr.Response.StatusCode != http.StatusConflict {
+			return err
+		}
+	}
+}
+
+// getReleaseFileContents returns the file of a release. The values of the release whose
+// keys are named like credentials are redacted, since sensitive values that are not
+// marked by the chart are stored in plaintext in the values of the release.
+func getReleaseFileContents(helmRelease *release.Release, repoURL string) ([]byte, error) {
+	return yaml.Marshal(&ReleaseFile{
+		Chart:    helmRelease.Chart.Metadata.Name,
+		RepoURL:  repoURL,
+		Version:  helmRelease.Chart.Metadata.Version,
+		Values:   helm.RedactSensitiveValues(helmRelease.Config, helm.GetSensitiveKeyPaths(helmRelease.Config)),
+		Revision: helmRelease.Version,
+	})
+}
+
+func (s *Syncer) commitReleaseFile(
+	client *github.Client,
+	conf *models.GitOpsConfig,
+	helmRelease *release.Release,
+	filePath string,
+	contents []byte,
+	user *models.User,
+) error {
+	existing, sha, err := getFile(client, conf, filePath)
+
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(existing, contents) {
+		return nil
+	}
+
+	// exports run in the background, so the export of an older revision may finish after
+	// the export of a newer revision
+	existingFile := &ReleaseFile{}
+
+	if err := yaml.Unmarshal(existing, existingFile); err == nil && existingFile.Revision > helmRelease.Version {
+		return nil
+	}
+
+	opts := &github.RepositoryContentFileOptions{
+		Message: github.String(fmt.Sprintf(
+			"Deploy %s/%s revision %d", helmRelease.Namespace, helmRelease.Name, helmRelease.Version,
+		)),
+		Content:   contents,
+		Branch:    github.String(conf.Branch),
+		Author:    getCommitAuthor(user),
+		Committer: getCommitAuthor(nil),
+	}
+
+	if sha != "" {
+		opts.SHA = github.String(sha)
+	}
+
+	_, _, err = client.Repositories.UpdateFile(context.Background(), conf.GitRepoOwner, conf.GitRepoName, filePath, opts)
+
+	return err
+}
+
+// DeleteRelease removes the file of a deleted release from the git repository
+func (s *Syncer) DeleteRelease(
+	conf *models.GitOpsConfig,
+	cluster *models.Cluster,
+	namespace, name string,
+	user *models.User,
+) error {
+	client, err := s.getClient(conf)
+
+	if err != nil {
+		return err
+	}
+
+	filePath := GetReleaseFilePath(conf, cluster.ID, namespace, name)
+
+	_, sha, err := getFile(client, conf, filePath)
+
+	if err != nil || sha == "" {
+		return err
+	}
+
+	_, _, err = client.Repositories.DeleteFile(
+		context.Background(),
+		conf.GitRepoOwner,
+		conf.GitRepoName,
+		filePath,
+		&github.RepositoryContentFileOptions{
+			Message:   github.String(fmt.Sprintf("Delete %s/%s", namespace, name)),
+			Branch:    github.String(conf.Branch),
+			SHA:       github.String(sha),
+			Author:    getCommitAuthor(user),
+			Committer: getCommitAuthor(nil),
+		},
+	)
+
+	return err
+}
+
+func (s *Syncer) getClient(conf *models.GitOpsConfig) (*github.Client, error) {
+	if s.GithubAppConf == nil {
+		return nil, errors.New("github app is not configured")
+	}
+
+	if s.GithubAppTokens != nil {
+		return s.GithubAppTokens.Client(int64(conf.GitInstallationID)), nil
+	}
+
+	itr, err := ghinstallation.NewKeyFromFile(
+		http.DefaultTransport,
+		s.GithubAppConf.AppID,
+		int64(conf.GitInstallationID),
+		s.GithubAppConf.SecretPath,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return github.NewClient(&http.Client{Transport: itr}), nil
+}
+
+// getFile returns the contents and sha of a file on the branch of the gitops config, or
+// an empty sha if the file does not exist
+func getFile(client *github.Client, conf *models.GitOpsConfig, filePath string) ([]byte, string, error) {
+	fileData, _, resp, err := client.Repositories.GetContents(
+		context.Background(),
+		conf.GitRepoOwner,
+		conf.GitRepoName,
+		filePath,
+		&github.RepositoryContentGetOptions{
+			Ref: conf.Branch,
+		},
+	)
+
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", err
+	} else if fileData == nil {
+		return nil, "", fmt.Errorf("%s is not a file", filePath)
+	}
+
+	contents, err := fileData.GetContent()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return []byte(contents), fileData.GetSHA(), nil
+}
+
+// getCommitAuthor returns the user as the author of a commit, or the Porter bot if the
+// change was not made by a user
+func getCommitAuthor(user *models.User) *github.CommitAuthor {
+	if user == nil || user.Email == "" {
+		return &github.CommitAuthor{
+			Name:  github.String("Porter Bot"),
+			Email: github.String("contact@getporter.dev"),
+		}
+	}
+
+	return &github.CommitAuthor{
+		Name:  github.String(user.Email),
+		Email: github.String(user.Email),
+	}
+}
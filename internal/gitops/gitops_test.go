@@ -0,0 +1,104 @@
+package gitops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"sigs.k8s.io/yaml"
+)
+
+func TestReleaseFilePath(t *testing.T) {
+	conf := &models.GitOpsConfig{
+		Path: "porter",
+	}
+
+	filePath := GetReleaseFilePath(conf, 3, "default", "web")
+
+	if filePath != "porter/cluster-3/default/web.yaml" {
+		t.Fatalf("unexpected file path %s", filePath)
+	}
+
+	clusterID, namespace, name, ok := parseReleaseFilePath(conf, filePath)
+
+	if !ok || clusterID != 3 || namespace != "default" || name != "web" {
+		t.Errorf("unexpected parsed path: %d %s %s %t", clusterID, namespace, name, ok)
+	}
+
+	for _, invalidPath := range []string{
+		"README.md",
+		"porter/cluster-3/web.yaml",
+		"porter/cluster-x/default/web.yaml",
+		"porter/3/default/web.yaml",
+		"porter/cluster-3/default/web.json",
+		"other/cluster-3/default/web.yaml",
+	} {
+		if _, _, _, ok := parseReleaseFilePath(conf, invalidPath); ok {
+			t.Errorf("expected %s to not be parsed as a release file", invalidPath)
+		}
+	}
+}
+
+func TestReleaseFileContentsRedactsCredentials(t *testing.T) {
+	contents, err := getReleaseFileContents(&release.Release{
+		Version: 4,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "web", Version: "0.1.0"},
+		},
+		Config: map[string]interface{}{
+			"image": map[string]interface{}{"tag": "v1"},
+			"container": map[string]interface{}{
+				"env": map[string]interface{}{
+					"normal": map[string]interface{}{
+						"DB_PASSWORD": "hunter2",
+						"PORT":        "8080",
+					},
+				},
+			},
+		},
+	}, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(contents), "hunter2") {
+		t.Errorf("expected credentials to be redacted, got %s", contents)
+	}
+
+	file := &ReleaseFile{}
+
+	if err := yaml.Unmarshal(contents, file); err != nil {
+		t.Fatal(err)
+	}
+
+	if file.Revision != 4 {
+		t.Errorf("expected revision 4, got %d", file.Revision)
+	}
+
+	// redacted values are restored from the release when the file is reconciled
+	current := map[string]interface{}{
+		"container": map[string]interface{}{
+			"env": map[string]interface{}{
+				"normal": map[string]interface{}{"DB_PASSWORD": "hunter2"},
+			},
+		},
+	}
+
+	if err := restoreRedactedValues(file.Values, current, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	env := file.Values["container"].(map[string]interface{})["env"].(map[string]interface{})["normal"].(map[string]interface{})
+
+	if env["DB_PASSWORD"] != "hunter2" || env["PORT"] != "8080" {
+		t.Errorf("expected redacted values to be restored, got %v", env)
+	}
+
+	if err := restoreRedactedValues(map[string]interface{}{"API_KEY": helm.RedactedValue}, current, ""); err == nil {
+		t.Errorf("expected redacted values that the release does not have to fail")
+	}
+}
@@ -0,0 +1,316 @@
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"sigs.k8s.io/yaml"
+)
+
+// Run reconciles the releases of all projects with reconciliation enabled at the given
+// interval until the context is cancelled
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.ReconcileAll()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcileLeaseTTL is how long a server replica holds the reconcile lease of a gitops
+// config. It is longer than a reconcile of a repository is expected to take, so that a
+// lease only expires before it is released if the replica stopped.
+const reconcileLeaseTTL = 15 * time.Minute
+
+// ReconcileAll reconciles the releases of all projects with reconciliation enabled. Every
+// server replica runs the reconcile loop, so each gitops config is only reconciled by the
+// replica that claims its lease. The error of a project is stored on its gitops config,
+// and the project is reconciled again at the next interval.
+func (s *Syncer) ReconcileAll() {
+	confs, err := s.Repo.GitOpsConfig().ListGitOpsConfigs()
+
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("error listing gitops configs")
+		return
+	}
+
+	for _, conf := range confs {
+		if !conf.ReconcileEnabled {
+			continue
+		}
+
+		claimed, err := s.Repo.GitOpsConfig().ClaimGitOpsReconcile(conf, time.Now().Add(reconcileLeaseTTL))
+
+		if err != nil {
+			s.Logger.Error().Err(err).Uint("project_id", conf.ProjectID).Msg("error claiming gitops reconcile lease")
+			continue
+		} else if !claimed {
+			continue
+		}
+
+		if err := s.reconcileClaimed(conf.ProjectID); err != nil {
+			s.Logger.Error().Err(err).Uint("project_id", conf.ProjectID).Msg("error reconciling gitops repository")
+		}
+	}
+}
+
+// reconcileClaimed reconciles the gitops config of a project whose lease was claimed, and
+// releases the lease. The config is read again after the lease is claimed, since another
+// replica may have reconciled a commit after the configs were listed.
+func (s *Syncer) reconcileClaimed(projectID uint) error {
+	conf, err := s.Repo.GitOpsConfig().ReadGitOpsConfig(projectID)
+
+	if err != nil {
+		return err
+	}
+
+	reconcileErr := s.Reconcile(conf)
+
+	conf.ReconcileLeaseExpiresAt = nil
+
+	if _, err := s.Repo.GitOpsConfig().UpdateGitOpsConfig(conf); err != nil {
+		return err
+	}
+
+	return reconcileErr
+}
+
+// Reconcile applies the latest commit of the branch of a gitops config to the releases
+// of the project. Releases whose chart version or values differ from their file are
+// upgraded. Files of releases that do not exist are skipped, and releases are not
+// deleted when their file is removed.
+func (s *Syncer) Reconcile(conf *models.GitOpsConfig) error {
+	client, err := s.getClient(conf)
+
+	if err != nil {
+		return err
+	}
+
+	branch, _, err := client.Repositories.GetBranch(
+		context.Background(),
+		conf.GitRepoOwner,
+		conf.GitRepoName,
+		conf.Branch,
+		true,
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not read branch %s: %w", conf.Branch, err)
+	}
+
+	sha := branch.GetCommit().GetSHA()
+
+	if sha == conf.LastReconciledSHA {
+		return nil
+	}
+
+	reconcileErr := s.reconcileCommit(client, conf, sha)
+
+	// the commit is reconciled again at the next interval if any release failed, so that
+	// releases are not left out of sync with the repository
+	if reconcileErr != nil {
+		conf.LastReconcileError = reconcileErr.Error()
+	} else {
+		now := time.Now()
+
+		conf.LastReconciledSHA = sha
+		conf.LastReconciledAt = &now
+		conf.LastReconcileError = ""
+	}
+
+	if _, err := s.Repo.GitOpsConfig().UpdateGitOpsConfig(conf); err != nil {
+		return err
+	}
+
+	return reconcileErr
+}
+
+func (s *Syncer) reconcileCommit(client *github.Client, conf *models.GitOpsConfig, sha string) error {
+	tree, _, err := client.Git.GetTree(context.Background(), conf.GitRepoOwner, conf.GitRepoName, sha, true)
+
+	if err != nil {
+		return fmt.Errorf("could not read tree of commit %s: %w", sha, err)
+	}
+
+	errs := make([]string, 0)
+
+	for _, entry := range tree.Entries {
+		if entry.GetType() != "blob" {
+			continue
+		}
+
+		clusterID, namespace, name, ok := parseReleaseFilePath(conf, entry.GetPath())
+
+		if !ok {
+			continue
+		}
+
+		contents, _, err := client.Git.GetBlobRaw(context.Background(), conf.GitRepoOwner, conf.GitRepoName, entry.GetSHA())
+
+		if err == nil {
+			err = s.reconcileRelease(conf, sha, clusterID, namespace, name, contents)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", entry.GetPath(), err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("could not reconcile releases: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (s *Syncer) reconcileRelease(
+	conf *models.GitOpsConfig,
+	sha string,
+	clusterID uint,
+	namespace, name string,
+	contents []byte,
+) error {
+	file := &ReleaseFile{}
+
+	if err := yaml.Unmarshal(contents, file); err != nil {
+		return fmt.Errorf("could not parse file: %w", err)
+	}
+
+	cluster, err := s.Repo.Cluster().ReadCluster(conf.ProjectID, clusterID)
+
+	if err != nil {
+		return fmt.Errorf("could not read cluster %d: %w", clusterID, err)
+	}
+
+	helmAgent, err := helm.GetAgentOutOfClusterConfig(&helm.Form{
+		Cluster:           cluster,
+		Repo:              s.Repo,
+		DigitalOceanOAuth: s.DOConf,
+		Storage:           "secret",
+		Namespace:         namespace,
+	}, s.Logger)
+
+	if err != nil {
+		return err
+	}
+
+	helmRelease, err := helmAgent.GetRelease(name, 0, false)
+
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		// releases are only created through Porter, so files of releases that do not
+		// exist are skipped
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not read release: %w", err)
+	}
+
+	if file.Revision != 0 && file.Revision < helmRelease.Version {
+		return fmt.Errorf(
+			"file was exported from revision %d, but the release was upgraded to revision %d since",
+			file.Revision, helmRelease.Version,
+		)
+	}
+
+	if file.Values == nil {
+		file.Values = map[string]interface{}{}
+	}
+
+	if err := restoreRedactedValues(file.Values, helmRelease.Config, ""); err != nil {
+		return err
+	}
+
+	versionChanged := file.Version != "" && file.Version != helmRelease.Chart.Metadata.Version
+	valuesChanged, err := valuesDiffer(helmRelease.Config, file.Values)
+
+	if err != nil || (!versionChanged && !valuesChanged) {
+		return err
+	}
+
+	var ch *chart.Chart
+
+	if versionChanged {
+		if file.RepoURL == "" {
+			return fmt.Errorf("repo_url is required to change the chart version")
+		}
+
+		ch, err = loader.LoadChartPublic(file.RepoURL, file.Chart, file.Version)
+
+		if err != nil {
+			return fmt.Errorf("could not load chart %s version %s: %w", file.Chart, file.Version, err)
+		}
+	}
+
+	if s.Upgrade == nil {
+		return fmt.Errorf("releases cannot be upgraded by this syncer")
+	}
+
+	// the repo URL of the file is passed with the chart, so that the chart is checked
+	// against the allow-list of the project with the repository it was loaded from
+	return s.Upgrade(cluster, namespace, name, file.Values, ch, file.RepoURL, fmt.Sprintf("Reconciled from commit %s", sha))
+}
+
+// restoreRedactedValues replaces the redacted values of a file with the values of the
+// release, since values whose keys are named like credentials are redacted when the
+// release is exported. Redacted values that the release does not have cannot be restored.
+func restoreRedactedValues(values, current map[string]interface{}, prefix string) error {
+	for key, val := range values {
+		currentVal, exists := current[key]
+
+		switch typed := val.(type) {
+		case map[string]interface{}:
+			currentMap, _ := currentVal.(map[string]interface{})
+
+			if err := restoreRedactedValues(typed, currentMap, prefix+key+"."); err != nil {
+				return err
+			}
+		case string:
+			if typed != helm.RedactedValue {
+				continue
+			}
+
+			if !exists {
+				return fmt.Errorf("value %s%s is redacted, but the release does not have it", prefix, key)
+			}
+
+			values[key] = currentVal
+		}
+	}
+
+	return nil
+}
+
+// valuesDiffer compares values after a JSON round trip, since the values of a release
+// and the values of a file decode numbers to different types
+func valuesDiffer(a, b map[string]interface{}) (bool, error) {
+	aBytes, err := json.Marshal(a)
+
+	if err != nil {
+		return false, err
+	}
+
+	bBytes, err := json.Marshal(b)
+
+	if err != nil {
+		return false, err
+	}
+
+	return !bytes.Equal(aBytes, bBytes), nil
+}
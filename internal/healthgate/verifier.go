@@ -0,0 +1,273 @@
+package healthgate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/integrations/slack"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+)
+
+// pollInterval is the interval at which the health of a new version is checked
+const pollInterval = 15 * time.Second
+
+// metricsWindow is the trailing window that error rate and latency are averaged over
+const metricsWindow = 5 * time.Minute
+
+// Verifier watches a newly deployed version of a release, and rolls it back to the
+// previous version if it does not become healthy or violates the configured error rate
+// or latency thresholds
+type Verifier struct {
+	HelmAgent *helm.Agent
+	K8sAgent  *kubernetes.Agent
+	Config    *models.HealthGateConfig
+
+	// Repo and Cluster are used to restore the sensitive values of the version that the
+	// release is rolled back to
+	Repo    repository.Repository
+	Cluster *models.Cluster
+
+	// Notifier and NotifyOpts are used to notify when a release is rolled back
+	Notifier   slack.Notifier
+	NotifyOpts *slack.NotifyOpts
+}
+
+// Verify blocks until the release has passed or failed verification. If verification
+// fails, the release is rolled back and a notification is sent. The returned error is
+// the reason that verification failed, if it did.
+func (v *Verifier) Verify(helmRelease *release.Release) error {
+	// there is no version to roll back to
+	if helmRelease.Version <= 1 {
+		return nil
+	}
+
+	reason := v.watch(helmRelease)
+
+	if reason == nil {
+		return nil
+	}
+
+	// earlier versions may have failed to deploy, so the release is rolled back to the
+	// last version that was deployed
+	history, err := v.HelmAgent.GetReleaseHistory(helmRelease.Name)
+
+	if err != nil {
+		return fmt.Errorf("%s, and reading the history of the release failed: %w", reason.Error(), err)
+	}
+
+	prevVersion, found := getRollbackVersion(history, helmRelease.Version)
+
+	if !found {
+		return fmt.Errorf("%s, and there is no earlier deployed version to roll back to", reason.Error())
+	}
+
+	if err := v.HelmAgent.RollbackRelease(helmRelease.Name, prevVersion); err != nil {
+		return fmt.Errorf("%s, and rolling back failed: %w", reason.Error(), err)
+	}
+
+	if v.Repo != nil && v.Cluster != nil {
+		rolledBackRelease, err := v.HelmAgent.GetRelease(helmRelease.Name, 0, false)
+
+		if err == nil {
+			err = helm.RestoreSensitiveValues(
+				v.Repo,
+				v.Cluster,
+				helmRelease.Namespace,
+				helmRelease.Name,
+				prevVersion,
+				rolledBackRelease.Version,
+			)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s, and restoring the sensitive values of the previous version failed: %w", reason.Error(), err)
+		}
+	}
+
+	if v.Notifier != nil && v.NotifyOpts != nil {
+		notifyOpts := *v.NotifyOpts
+		notifyOpts.Status = slack.StatusRolledBack
+		notifyOpts.Version = helmRelease.Version
+		notifyOpts.Info = reason.Error()
+
+		v.Notifier.Notify(&notifyOpts)
+	}
+
+	return reason
+}
+
+// watch returns a non-nil error if the release fails verification
+func (v *Verifier) watch(helmRelease *release.Release) error {
+	minutes := v.Config.VerificationMinutes
+
+	if minutes == 0 {
+		minutes = types.DefaultHealthGateVerificationMinutes
+	}
+
+	deadline := time.Now().Add(time.Duration(minutes) * time.Minute)
+
+	yamlArr := grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest))
+	controllers := grapher.ParseControllers(yamlArr)
+	ingresses := make([]string, 0)
+
+	for i := range controllers {
+		controllers[i].Namespace = helmRelease.Namespace
+	}
+
+	for _, obj := range grapher.ParseObjs(yamlArr, helmRelease.Namespace) {
+		if obj.Kind == "Ingress" {
+			ingresses = append(ingresses, obj.Name)
+		}
+	}
+
+	checkMetrics := len(ingresses) > 0 && (v.Config.MaxErrorRate > 0 || v.Config.MaxLatency > 0)
+
+	var rollout *types.RolloutStatus
+	var err error
+
+	for time.Now().Before(deadline) {
+		// warning events are not used for verification, so only request new ones
+		rollout, err = v.K8sAgent.GetRolloutStatus(controllers, time.Now())
+
+		if err == nil && rollout.Phase == types.RolloutFailed {
+			return fmt.Errorf("rollout failed: %s", getFailureReason(rollout))
+		}
+
+		if err == nil && checkMetrics {
+			if err := v.checkMetrics(helmRelease, ingresses); err != nil {
+				return err
+			}
+		}
+
+		time.Sleep(pollInterval)
+	}
+
+	if rollout != nil && rollout.Phase != types.RolloutSucceeded {
+		return fmt.Errorf("rollout did not complete within %d minutes", minutes)
+	}
+
+	return nil
+}
+
+func (v *Verifier) checkMetrics(helmRelease *release.Release, ingresses []string) error {
+	promSvc, found, err := prometheus.GetPrometheusService(v.K8sAgent.Clientset)
+
+	// metrics are optional, so if prometheus is unavailable the check is skipped
+	if err != nil || !found {
+		return nil
+	}
+
+	now := time.Now()
+
+	queryOpts := prometheus.QueryOpts{
+		Kind:       "ingress",
+		Name:       strings.Join(ingresses, "|"),
+		Namespace:  helmRelease.Namespace,
+		StartRange: uint(getMetricsStart(helmRelease, now).Unix()),
+		EndRange:   uint(now.Unix()),
+		Resolution: "1m",
+	}
+
+	if v.Config.MaxErrorRate > 0 {
+		errorOpts := queryOpts
+		errorOpts.Metric = "nginx:errors"
+
+		errorRate, ok := queryLatestValue(v.K8sAgent, promSvc, &errorOpts)
+
+		if ok && errorRate > v.Config.MaxErrorRate {
+			return fmt.Errorf("error rate of %.2f%% exceeded the maximum of %.2f%%", errorRate, v.Config.MaxErrorRate)
+		}
+	}
+
+	if v.Config.MaxLatency > 0 {
+		latencyOpts := queryOpts
+		latencyOpts.Metric = "nginx:latency"
+
+		latency, ok := queryLatestValue(v.K8sAgent, promSvc, &latencyOpts)
+
+		if ok && latency > v.Config.MaxLatency {
+			return fmt.Errorf("average latency of %.3fs exceeded the maximum of %.3fs", latency, v.Config.MaxLatency)
+		}
+	}
+
+	return nil
+}
+
+// queryLatestValue returns the most recent value of an error rate or latency metric, and
+// false if there is no value
+func queryLatestValue(agent *kubernetes.Agent, promSvc *v1.Service, opts *prometheus.QueryOpts) (float64, bool) {
+	res, err := prometheus.QueryPrometheus(agent.Clientset, promSvc, opts)
+
+	if err != nil || len(res) == 0 || len(res[0].Results) == 0 {
+		return 0, false
+	}
+
+	latest := res[0].Results[len(res[0].Results)-1]
+
+	var val interface{}
+
+	if opts.Metric == "nginx:errors" {
+		val = latest.ErrorPct
+	} else {
+		val = latest.Latency
+	}
+
+	parsed, err := strconv.ParseFloat(fmt.Sprintf("%v", val), 64)
+
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return 0, false
+	}
+
+	return parsed, true
+}
+
+// getRollbackVersion returns the latest version of the release before the given version
+// that was deployed, and false if there is none
+func getRollbackVersion(history []*release.Release, version int) (int, bool) {
+	res := 0
+
+	for _, rel := range history {
+		if rel.Version >= version || rel.Version <= res || rel.Info == nil {
+			continue
+		}
+
+		if rel.Info.Status == release.StatusDeployed || rel.Info.Status == release.StatusSuperseded {
+			res = rel.Version
+		}
+	}
+
+	return res, res > 0
+}
+
+// getMetricsStart returns the start of the window that metrics are averaged over, which
+// does not include traffic served by the previous version before the release was deployed
+func getMetricsStart(helmRelease *release.Release, now time.Time) time.Time {
+	start := now.Add(-metricsWindow)
+
+	if helmRelease.Info != nil && helmRelease.Info.LastDeployed.Time.After(start) {
+		return helmRelease.Info.LastDeployed.Time
+	}
+
+	return start
+}
+
+func getFailureReason(rollout *types.RolloutStatus) string {
+	for _, controller := range rollout.Controllers {
+		if controller.FailureReason != "" {
+			return controller.FailureReason
+		}
+	}
+
+	return rollout.Error
+}
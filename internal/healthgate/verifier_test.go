@@ -0,0 +1,88 @@
+package healthgate
+
+import (
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+)
+
+func TestGetRollbackVersion(t *testing.T) {
+	getRelease := func(version int, status release.Status) *release.Release {
+		return &release.Release{
+			Version: version,
+			Info:    &release.Info{Status: status},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		history  []*release.Release
+		version  int
+		expected int
+		found    bool
+	}{
+		{
+			name: "previous version",
+			history: []*release.Release{
+				getRelease(1, release.StatusSuperseded),
+				getRelease(2, release.StatusDeployed),
+				getRelease(3, release.StatusPendingUpgrade),
+			},
+			version:  3,
+			expected: 2,
+			found:    true,
+		},
+		{
+			name: "previous version failed",
+			history: []*release.Release{
+				getRelease(3, release.StatusFailed),
+				getRelease(1, release.StatusSuperseded),
+				getRelease(2, release.StatusDeployed),
+				getRelease(4, release.StatusDeployed),
+			},
+			version:  4,
+			expected: 2,
+			found:    true,
+		},
+		{
+			name: "no deployed version",
+			history: []*release.Release{
+				getRelease(1, release.StatusFailed),
+				getRelease(2, release.StatusDeployed),
+			},
+			version: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			version, found := getRollbackVersion(test.history, test.version)
+
+			if version != test.expected || found != test.found {
+				t.Errorf("expected version %d (%t), got %d (%t)", test.expected, test.found, version, found)
+			}
+		})
+	}
+}
+
+func TestGetMetricsStart(t *testing.T) {
+	now := time.Now()
+
+	recent := &release.Release{
+		Info: &release.Info{LastDeployed: helmtime.Time{Time: now.Add(-time.Minute)}},
+	}
+
+	if start := getMetricsStart(recent, now); !start.Equal(now.Add(-time.Minute)) {
+		t.Errorf("expected metrics of a recent deploy to start at the deploy, got %s", start)
+	}
+
+	old := &release.Release{
+		Info: &release.Info{LastDeployed: helmtime.Time{Time: now.Add(-time.Hour)}},
+	}
+
+	if start := getMetricsStart(old, now); !start.Equal(now.Add(-metricsWindow)) {
+		t.Errorf("expected metrics to start at the beginning of the window, got %s", start)
+	}
+}
@@ -0,0 +1,269 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// ManifestChartVersion is the version of the charts that are created to adopt the objects
+// of raw manifests into a release
+const ManifestChartVersion = "0.1.0"
+
+// The labels and annotations that Helm reads to adopt existing objects into a release
+const (
+	LabelManagedBy             = "app.kubernetes.io/managed-by"
+	AnnotationReleaseName      = "meta.helm.sh/release-name"
+	AnnotationReleaseNamespace = "meta.helm.sh/release-namespace"
+)
+
+const annotationLastAppliedConfig = "kubectl.kubernetes.io/last-applied-configuration"
+
+// manifestChartTemplate renders the files of a manifest chart without rendering them as
+// templates, so that manifests that contain template delimiters are rendered unchanged
+const manifestChartTemplate = `{{- range $path, $_ := .Files.Glob "manifests/*.yaml" }}
+---
+{{ $.Files.Get $path }}
+{{- end }}
+`
+
+// ParseManifests parses multi-document YAML or JSON manifests into objects in a namespace.
+// Lists are expanded into their items, and the fields that are set by the cluster, such as
+// the status and the resource version, are removed.
+func ParseManifests(manifests []byte, namespace string) ([]*unstructured.Unstructured, error) {
+	res := make([]*unstructured.Unstructured, 0)
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+
+	for {
+		doc := make(map[string]interface{})
+
+		if err := decoder.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not parse manifests: %w", err)
+		}
+
+		if len(doc) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: doc}
+
+		if obj.IsList() {
+			list, err := obj.ToList()
+
+			if err != nil {
+				return nil, fmt.Errorf("could not parse list: %w", err)
+			}
+
+			for i := range list.Items {
+				res = append(res, &list.Items[i])
+			}
+
+			continue
+		}
+
+		res = append(res, obj)
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("manifests do not contain any objects")
+	}
+
+	seen := make(map[string]bool)
+
+	for _, obj := range res {
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("every object must have an apiVersion, a kind and a name")
+		}
+
+		if objNamespace := obj.GetNamespace(); objNamespace != "" && objNamespace != namespace {
+			return nil, fmt.Errorf("%s %s is in namespace %s, not in namespace %s", obj.GetKind(), obj.GetName(), objNamespace, namespace)
+		}
+
+		key := ObjectKey(obj)
+
+		if seen[key] {
+			return nil, fmt.Errorf("%s is defined more than once", key)
+		}
+
+		seen[key] = true
+
+		removeServerFields(obj)
+	}
+
+	return res, nil
+}
+
+// ObjectKey returns the kind and name of an object, such as Deployment/web
+func ObjectKey(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+}
+
+// NewManifestChart creates a chart that renders the objects of raw manifests
+func NewManifestChart(name string, objs []*unstructured.Unstructured) (*chart.Chart, error) {
+	files := make([]*chart.File, 0, len(objs))
+
+	for i, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, &chart.File{
+			Name: fmt.Sprintf("manifests/%03d-%s-%s.yaml", i, strings.ToLower(obj.GetKind()), obj.GetName()),
+			Data: data,
+		})
+	}
+
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion:  chart.APIVersionV2,
+			Name:        name,
+			Version:     ManifestChartVersion,
+			Description: "Objects adopted from raw manifests",
+			Type:        "application",
+		},
+		Templates: []*chart.File{
+			{
+				Name: "templates/manifests.yaml",
+				Data: []byte(manifestChartTemplate),
+			},
+		},
+		Values: make(map[string]interface{}),
+		Files:  files,
+	}, nil
+}
+
+// GetAdoptionMetadata returns the labels and annotations that let Helm adopt existing
+// objects into a release, instead of failing because the objects already exist
+func GetAdoptionMetadata(releaseName, namespace string) (labels map[string]string, annotations map[string]string) {
+	labels = map[string]string{
+		LabelManagedBy: "Helm",
+	}
+
+	annotations = map[string]string{
+		AnnotationReleaseName:      releaseName,
+		AnnotationReleaseNamespace: namespace,
+	}
+
+	return labels, annotations
+}
+
+// GetAdoptedObject returns the object that a manifest chart renders for the live state of
+// an object, so that adopting the object into a release does not change it. The fields
+// that are set by the cluster are removed, and the adoption metadata is added.
+func GetAdoptedObject(live *unstructured.Unstructured, releaseName, namespace string) *unstructured.Unstructured {
+	res := live.DeepCopy()
+
+	removeServerFields(res)
+
+	labels, annotations := GetAdoptionMetadata(releaseName, namespace)
+
+	res.SetLabels(mergeStringMaps(res.GetLabels(), labels))
+	res.SetAnnotations(mergeStringMaps(res.GetAnnotations(), annotations))
+
+	return res
+}
+
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	res := make(map[string]string)
+
+	for key, val := range base {
+		res[key] = val
+	}
+
+	for key, val := range override {
+		res[key] = val
+	}
+
+	return res
+}
+
+// IsSameChart returns true if two charts have the same name and version, and render the
+// same templates with the same default values, including the templates of their
+// dependencies. Charts with the same name from different repos are often unrelated.
+func IsSameChart(a, b *chart.Chart) bool {
+	if a.Metadata == nil || b.Metadata == nil {
+		return false
+	}
+
+	if a.Metadata.Name != b.Metadata.Name || a.Metadata.Version != b.Metadata.Version {
+		return false
+	}
+
+	if !isSameFiles(a.Templates, b.Templates) || !isSameValues(a.Values, b.Values) {
+		return false
+	}
+
+	aDeps, bDeps := a.Dependencies(), b.Dependencies()
+
+	if len(aDeps) != len(bDeps) {
+		return false
+	}
+
+	for i := range aDeps {
+		if !IsSameChart(aDeps[i], bDeps[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isSameValues(a, b map[string]interface{}) bool {
+	return len(a) == 0 && len(b) == 0 || reflect.DeepEqual(a, b)
+}
+
+func isSameFiles(a, b []*chart.File) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	files := make(map[string][]byte)
+
+	for _, file := range a {
+		files[file.Name] = file.Data
+	}
+
+	for _, file := range b {
+		if data, ok := files[file.Name]; !ok || !bytes.Equal(data, file.Data) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func removeServerFields(obj *unstructured.Unstructured) {
+	delete(obj.Object, "status")
+
+	for _, field := range []string{
+		"uid",
+		"resourceVersion",
+		"generation",
+		"creationTimestamp",
+		"managedFields",
+		"selfLink",
+	} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		delete(annotations, annotationLastAppliedConfig)
+
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+
+		obj.SetAnnotations(annotations)
+	}
+}
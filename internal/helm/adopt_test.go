@@ -0,0 +1,232 @@
+package helm_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const adoptManifests = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  uid: 1234
+  resourceVersion: "10"
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+spec:
+  replicas: 1
+status:
+  readyReplicas: 1
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+    annotations:
+      team: platform
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: web-config
+  data:
+    template: "{{ .Values.name }}"
+`
+
+func TestParseManifests(t *testing.T) {
+	objs, err := helm.ParseManifests([]byte(adoptManifests), "default")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := make([]string, 0)
+
+	for _, obj := range objs {
+		keys = append(keys, helm.ObjectKey(obj))
+	}
+
+	if expected := []string{"Deployment/web", "Service/web", "ConfigMap/web-config"}; !reflect.DeepEqual(expected, keys) {
+		t.Fatalf("incorrect objects: expected %v, got %v", expected, keys)
+	}
+
+	deployment := objs[0]
+
+	if _, ok := deployment.Object["status"]; ok {
+		t.Errorf("expected the status to be removed")
+	}
+
+	if deployment.GetUID() != "" || deployment.GetResourceVersion() != "" || deployment.GetAnnotations() != nil {
+		t.Errorf("expected the server fields to be removed, got %v", deployment.Object["metadata"])
+	}
+
+	if expected := map[string]string{"team": "platform"}; !reflect.DeepEqual(expected, objs[1].GetAnnotations()) {
+		t.Errorf("expected the other annotations to be kept, got %v", objs[1].GetAnnotations())
+	}
+}
+
+func TestParseManifestsErrors(t *testing.T) {
+	tests := map[string]struct {
+		manifests string
+		err       string
+	}{
+		"empty": {
+			manifests: "---\n",
+			err:       "do not contain any objects",
+		},
+		"no name": {
+			manifests: "apiVersion: v1\nkind: Service\n",
+			err:       "must have an apiVersion, a kind and a name",
+		},
+		"other namespace": {
+			manifests: "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n  namespace: other\n",
+			err:       "Service web is in namespace other",
+		},
+		"duplicate": {
+			manifests: "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n",
+			err:       "Service/web is defined more than once",
+		},
+	}
+
+	for name, test := range tests {
+		_, err := helm.ParseManifests([]byte(test.manifests), "default")
+
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: expected error containing %q, got %v", name, test.err, err)
+		}
+	}
+}
+
+func TestNewManifestChart(t *testing.T) {
+	objs, err := helm.ParseManifests([]byte(adoptManifests), "default")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ch, err := helm.NewManifestChart("web", objs)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := ch.Validate(); err != nil {
+		t.Fatalf("invalid chart: %v", err)
+	}
+
+	names := make([]string, 0)
+
+	for _, file := range ch.Files {
+		names = append(names, file.Name)
+	}
+
+	expected := []string{
+		"manifests/000-deployment-web.yaml",
+		"manifests/001-service-web.yaml",
+		"manifests/002-configmap-web-config.yaml",
+	}
+
+	if !reflect.DeepEqual(expected, names) {
+		t.Fatalf("incorrect files: expected %v, got %v", expected, names)
+	}
+
+	if len(ch.Templates) != 1 {
+		t.Fatalf("expected a single template, got %d", len(ch.Templates))
+	}
+
+	if !strings.Contains(string(ch.Files[2].Data), "{{ .Values.name }}") {
+		t.Errorf("expected the manifests to be stored unchanged, got %s", ch.Files[2].Data)
+	}
+}
+
+func TestGetAdoptedObject(t *testing.T) {
+	objs, err := helm.ParseManifests([]byte(adoptManifests), "default")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the live object has fields that the manifests do not have
+	live := objs[1].DeepCopy()
+	live.SetResourceVersion("20")
+	live.SetLabels(map[string]string{"app": "web"})
+
+	if err := unstructured.SetNestedField(live.Object, "10.0.0.1", "spec", "clusterIP"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	adopted := helm.GetAdoptedObject(live, "web", "default")
+
+	if adopted.GetResourceVersion() != "" {
+		t.Errorf("expected the server fields to be removed, got %v", adopted.Object["metadata"])
+	}
+
+	if clusterIP, _, _ := unstructured.NestedString(adopted.Object, "spec", "clusterIP"); clusterIP != "10.0.0.1" {
+		t.Errorf("expected the live spec to be kept, got %v", adopted.Object["spec"])
+	}
+
+	expectedLabels := map[string]string{"app": "web", helm.LabelManagedBy: "Helm"}
+
+	if !reflect.DeepEqual(expectedLabels, adopted.GetLabels()) {
+		t.Errorf("incorrect labels: expected %v, got %v", expectedLabels, adopted.GetLabels())
+	}
+
+	expectedAnnotations := map[string]string{
+		"team":                          "platform",
+		helm.AnnotationReleaseName:      "web",
+		helm.AnnotationReleaseNamespace: "default",
+	}
+
+	if !reflect.DeepEqual(expectedAnnotations, adopted.GetAnnotations()) {
+		t.Errorf("incorrect annotations: expected %v, got %v", expectedAnnotations, adopted.GetAnnotations())
+	}
+
+	if live.GetResourceVersion() != "20" || len(live.GetAnnotations()) != 1 {
+		t.Errorf("expected the live object not to be modified")
+	}
+}
+
+func TestIsSameChart(t *testing.T) {
+	getChart := func(version, template string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: "web", Version: version},
+			Templates: []*chart.File{
+				{Name: "templates/deployment.yaml", Data: []byte(template)},
+			},
+		}
+	}
+
+	if !helm.IsSameChart(getChart("0.1.0", "kind: Deployment"), getChart("0.1.0", "kind: Deployment")) {
+		t.Errorf("expected identical charts to be the same")
+	}
+
+	if helm.IsSameChart(getChart("0.1.0", "kind: Deployment"), getChart("0.1.0", "kind: StatefulSet")) {
+		t.Errorf("expected charts with other templates not to be the same")
+	}
+
+	if helm.IsSameChart(getChart("0.1.0", "kind: Deployment"), getChart("0.2.0", "kind: Deployment")) {
+		t.Errorf("expected charts with other versions not to be the same")
+	}
+
+	withValues := getChart("0.1.0", "kind: Deployment")
+	withValues.Values = map[string]interface{}{"replicaCount": 1}
+
+	if helm.IsSameChart(getChart("0.1.0", "kind: Deployment"), withValues) {
+		t.Errorf("expected charts with other values not to be the same")
+	}
+
+	withDependency := getChart("0.1.0", "kind: Deployment")
+	withDependency.AddDependency(getChart("0.1.0", "kind: Service"))
+
+	if helm.IsSameChart(getChart("0.1.0", "kind: Deployment"), withDependency) {
+		t.Errorf("expected charts with other dependencies not to be the same")
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"k8s.io/helm/pkg/chartutil"
 
 	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/imagemirror"
 	"github.com/porter-dev/porter/internal/kubernetes"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
@@ -26,6 +27,14 @@ import (
 type Agent struct {
 	ActionConfig *action.Configuration
 	K8sAgent     *kubernetes.Agent
+
+	// PolicyEvaluator evaluates the manifest policies of the project on installs and
+	// upgrades, if set
+	PolicyEvaluator PolicyEvaluator
+
+	// ImageMirror is the mirror of the default images, which are allowed on installs and
+	// upgrades whatever the allowed registries of the project are
+	ImageMirror *imagemirror.Mirror
 }
 
 // ListReleases lists releases based on a ListFilter
@@ -47,47 +56,45 @@ func (a *Agent) ListReleases(
 		return nil, err
 	}
 
-	// before decoding to helm release, only keep the latest releases for each chart
-	latestMap := make(map[string]corev1.Secret)
+	// before decoding to helm release, only keep the latest releases for each chart,
+	// by their index in the secret list
+	latestMap := make(map[string]int)
 
-	for _, secret := range secretList.Items {
+	for i, secret := range secretList.Items {
 		relName, relNameExists := secret.Labels["name"]
 
 		if !relNameExists {
 			continue
 		}
 
-		id := fmt.Sprintf("%s/%s", secret.Namespace, relName)
+		id := secret.Namespace + "/" + relName
 
 		if currLatest, exists := latestMap[id]; exists {
 			// get version
-			currVersionStr, currVersionExists := currLatest.Labels["version"]
+			currVersionStr, currVersionExists := secretList.Items[currLatest].Labels["version"]
 			versionStr, versionExists := secret.Labels["version"]
 
 			if versionExists && currVersionExists {
 				currVersion, currErr := strconv.Atoi(currVersionStr)
 				version, err := strconv.Atoi(versionStr)
 				if currErr == nil && err == nil && currVersion < version {
-					latestMap[id] = secret
+					latestMap[id] = i
 				}
 			}
 		} else {
-			latestMap[id] = secret
+			latestMap[id] = i
 		}
 	}
 
-	chartList := []string{}
-	res := make([]*release.Release, 0)
-
-	for _, secret := range latestMap {
-		rel, isErr, err := kubernetes.ParseSecretToHelmRelease(secret, chartList)
+	latestSecrets := make([]corev1.Secret, 0, len(latestMap))
 
-		if !isErr && err == nil {
-			res = append(res, rel)
-		}
+	for _, i := range latestMap {
+		latestSecrets = append(latestSecrets, secretList.Items[i])
 	}
 
-	return res, nil
+	return kubernetes.ParseSecretsToHelmReleases(latestSecrets, []string{}, kubernetes.DecodeReleaseOpts{
+		SkipManifest: filter.SkipManifest,
+	}), nil
 }
 
 // GetRelease returns the info of a release.
@@ -151,6 +158,12 @@ type UpgradeReleaseConfig struct {
 
 	// Optional, if chart should be overriden
 	Chart *chart.Chart
+
+	// Optional, renders the upgrade without deploying it
+	DryRun bool
+
+	// Optional, if the kustomize patches of the release should be overriden
+	KustomizePatches *string
 }
 
 // UpgradeRelease upgrades a specific release with new values.yaml
@@ -191,6 +204,12 @@ func (a *Agent) UpgradeReleaseByValues(
 	cmd := action.NewUpgrade(a.ActionConfig)
 	cmd.Namespace = rel.Namespace
 
+	sensitiveValues, err := ExtractReleaseSensitiveValues(conf.Repo, conf.Cluster, rel.Namespace, conf.Name, ch, conf.Values)
+
+	if err != nil {
+		return nil, err
+	}
+
 	cmd.PostRenderer, err = NewPorterPostrenderer(
 		conf.Cluster,
 		conf.Repo,
@@ -198,16 +217,43 @@ func (a *Agent) UpgradeReleaseByValues(
 		rel.Namespace,
 		conf.Registries,
 		doAuth,
+		conf.Name,
+		rel.Version+1,
+		conf.Values,
+		sensitiveValues,
+		IsCustomChart(ch),
+		a.PolicyEvaluator,
+		a.ImageMirror,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if conf.KustomizePatches != nil {
+		if postrenderer, ok := cmd.PostRenderer.(*PorterPostrenderer); ok {
+			postrenderer.KustomizePostrenderer, err = NewKustomizePostrenderer(*conf.KustomizePatches)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cmd.DryRun = conf.DryRun
+
 	res, err := cmd.Run(conf.Name, ch, conf.Values)
 
 	if err != nil {
-		return nil, fmt.Errorf("Upgrade failed: %v", err)
+		return nil, fmt.Errorf("Upgrade failed: %v", RedactError(err, sensitiveValues))
+	}
+
+	if conf.DryRun {
+		return res, nil
+	}
+
+	if err := StoreSensitiveValues(conf.Repo, conf.Cluster, rel.Namespace, conf.Name, res.Version, sensitiveValues); err != nil {
+		return nil, err
 	}
 
 	return res, nil
@@ -260,7 +306,11 @@ func (a *Agent) InstallChart(
 		return nil, err
 	}
 
-	var err error
+	sensitiveValues, err := ExtractReleaseSensitiveValues(conf.Repo, conf.Cluster, conf.Namespace, conf.Name, conf.Chart, conf.Values)
+
+	if err != nil {
+		return nil, err
+	}
 
 	cmd.PostRenderer, err = NewPorterPostrenderer(
 		conf.Cluster,
@@ -269,6 +319,13 @@ func (a *Agent) InstallChart(
 		conf.Namespace,
 		conf.Registries,
 		doAuth,
+		conf.Name,
+		1,
+		conf.Values,
+		sensitiveValues,
+		IsCustomChart(conf.Chart),
+		a.PolicyEvaluator,
+		a.ImageMirror,
 	)
 
 	if err != nil {
@@ -282,7 +339,17 @@ func (a *Agent) InstallChart(
 		}
 	}
 
-	return cmd.Run(conf.Chart, conf.Values)
+	res, err := cmd.Run(conf.Chart, conf.Values)
+
+	if err != nil {
+		return nil, RedactError(err, sensitiveValues)
+	}
+
+	if err := StoreSensitiveValues(conf.Repo, conf.Cluster, conf.Namespace, conf.Name, res.Version, sensitiveValues); err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
 
 // UninstallChart uninstalls a chart
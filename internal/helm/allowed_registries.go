@@ -0,0 +1,92 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/imagemirror"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// AllowedRegistriesPostrenderer fails deploys whose rendered manifests run images that
+// are not pulled from the allowed registries of the project. Images are read from the
+// containers of the rendered pod specs, so images that charts build from other values,
+// such as the registry and repository of Bitnami charts, and images of init containers
+// and sidecars are checked as well. The manifests are not modified.
+type AllowedRegistriesPostrenderer struct {
+	project     *models.Project
+	imageMirror *imagemirror.Mirror
+}
+
+// NewAllowedRegistriesPostrenderer returns a postrenderer for the allowed registries of
+// a project, or nil if the project allows every image. The default images, or their
+// mirrored images, are always allowed.
+func NewAllowedRegistriesPostrenderer(project *models.Project, imageMirror *imagemirror.Mirror) *AllowedRegistriesPostrenderer {
+	if len(project.GetAllowedRegistries()) == 0 {
+		return nil
+	}
+
+	return &AllowedRegistriesPostrenderer{
+		project:     project,
+		imageMirror: imageMirror,
+	}
+}
+
+func (a *AllowedRegistriesPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	resources, err := decodeRenderedManifests(bytes.NewBuffer(renderedManifests.Bytes()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	notAllowed := make([]string, 0)
+
+	for _, res := range resources {
+		kind, _ := res["kind"].(string)
+		podSpec := getPodSpecFromResource(kind, res)
+
+		if podSpec == nil {
+			continue
+		}
+
+		name, _ := getNestedResource(res, "metadata")["name"].(string)
+
+		for _, key := range []string{"initContainers", "containers", "ephemeralContainers"} {
+			containers, _ := podSpec[key].([]interface{})
+
+			for _, containerVal := range containers {
+				container, ok := containerVal.(resource)
+
+				if !ok {
+					continue
+				}
+
+				image, _ := container["image"].(string)
+
+				if image == "" || a.project.IsImageAllowed(image) || a.imageMirror.IsDefaultImage(image) {
+					continue
+				}
+
+				containerName, _ := container["name"].(string)
+
+				notAllowed = append(notAllowed, fmt.Sprintf("%s/%s (%s): %s", kind, name, containerName, image))
+			}
+		}
+	}
+
+	if len(notAllowed) > 0 {
+		sort.Strings(notAllowed)
+
+		return nil, fmt.Errorf(
+			"images must be pulled from the allowed registries of the project (%s): %s",
+			strings.Join(a.project.GetAllowedRegistries(), ", "),
+			strings.Join(notAllowed, ", "),
+		)
+	}
+
+	return renderedManifests, nil
+}
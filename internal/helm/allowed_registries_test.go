@@ -0,0 +1,86 @@
+package helm_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/imagemirror"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+const allowedRegistriesManifests = `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: redis
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: volume-permissions
+        image: docker.io/bitnami/bitnami-shell:10
+      containers:
+      - name: redis
+        image: gcr.io/my-project/redis:6
+      - name: metrics
+        image: quay.io/oliver006/redis_exporter:1
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: cleanup
+            image: public.ecr.aws/o1j4x7p4/hello-porter-job:latest
+`
+
+func TestAllowedRegistriesPostrenderer(t *testing.T) {
+	project := &models.Project{AllowedRegistries: "gcr.io/my-project"}
+
+	if helm.NewAllowedRegistriesPostrenderer(&models.Project{}, nil) != nil {
+		t.Errorf("expected no postrenderer for a project that allows every image")
+	}
+
+	_, err := helm.NewAllowedRegistriesPostrenderer(project, nil).Run(bytes.NewBufferString(allowedRegistriesManifests))
+
+	if err == nil {
+		t.Fatalf("expected images outside of the allowed registries to be rejected")
+	}
+
+	// images of init containers and sidecars are checked, and the default images are
+	// always allowed
+	for _, expected := range []string{
+		"StatefulSet/redis (volume-permissions): docker.io/bitnami/bitnami-shell:10",
+		"StatefulSet/redis (metrics): quay.io/oliver006/redis_exporter:1",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q, got %v", expected, err)
+		}
+	}
+
+	for _, unexpected := range []string{"gcr.io/my-project/redis", "hello-porter-job"} {
+		if strings.Contains(err.Error(), unexpected) {
+			t.Errorf("expected error not to contain %q, got %v", unexpected, err)
+		}
+	}
+
+	project.AllowedRegistries = "gcr.io/my-project,docker.io/bitnami,quay.io"
+
+	res, err := helm.NewAllowedRegistriesPostrenderer(project, imagemirror.NewMirror("registry.example.com", false)).Run(
+		bytes.NewBufferString(allowedRegistriesManifests),
+	)
+
+	if err != nil {
+		t.Fatalf("expected images of the allowed registries to be deployed, got %v", err)
+	}
+
+	if res.String() != allowedRegistriesManifests {
+		t.Errorf("expected manifests not to be modified")
+	}
+}
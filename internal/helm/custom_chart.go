@@ -0,0 +1,211 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// The annotations that are set on the metadata of custom charts when they are loaded.
+// Helm stores the chart of each revision of a release, so the annotations are read from
+// the chart of a release when it is upgraded.
+const (
+	CustomChartAnnotation    = "porter.run/custom-chart"
+	ImageValuesKeyAnnotation = "porter.run/image-values-key"
+)
+
+// DefaultImageValuesKey is the key of the image repository and tag in the values of
+// Porter templates, and of custom charts that do not set another key
+const DefaultImageValuesKey = "image"
+
+// MaxCustomChartSize is the maximum size in bytes of a packaged custom chart
+const MaxCustomChartSize = 10 << 20
+
+// LoadCustomChart loads a packaged custom chart, and marks it as a custom chart whose
+// image repository and tag are set under the image values key, such as "image" or
+// "app.image"
+func LoadCustomChart(archive []byte, imageValuesKey string) (*chart.Chart, error) {
+	if len(archive) > MaxCustomChartSize {
+		return nil, fmt.Errorf("chart is larger than %d MB", MaxCustomChartSize>>20)
+	}
+
+	ch, err := loader.LoadArchive(bytes.NewReader(archive))
+
+	if err != nil {
+		return nil, fmt.Errorf("could not load chart: %w", err)
+	}
+
+	if err := ch.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid chart: %w", err)
+	}
+
+	if err := checkIfInstallable(ch); err != nil {
+		return nil, err
+	}
+
+	if ch.Metadata.Annotations == nil {
+		ch.Metadata.Annotations = make(map[string]string)
+	}
+
+	if imageValuesKey == "" {
+		imageValuesKey = DefaultImageValuesKey
+	}
+
+	ch.Metadata.Annotations[CustomChartAnnotation] = "true"
+	ch.Metadata.Annotations[ImageValuesKeyAnnotation] = imageValuesKey
+
+	return ch, nil
+}
+
+// IsCustomChart returns true if a chart was loaded as a custom chart
+func IsCustomChart(ch *chart.Chart) bool {
+	return ch != nil && ch.Metadata != nil && ch.Metadata.Annotations[CustomChartAnnotation] == "true"
+}
+
+// GetImageValuesKey returns the key of the image repository and tag in the values of a
+// chart
+func GetImageValuesKey(ch *chart.Chart) string {
+	if IsCustomChart(ch) && ch.Metadata.Annotations[ImageValuesKeyAnnotation] != "" {
+		return ch.Metadata.Annotations[ImageValuesKeyAnnotation]
+	}
+
+	return DefaultImageValuesKey
+}
+
+// GetImageValues returns the values under a dot-separated image values key, or nil if
+// the values do not contain the key
+func GetImageValues(values map[string]interface{}, imageValuesKey string) map[string]interface{} {
+	curr := values
+
+	for _, key := range strings.Split(imageValuesKey, ".") {
+		next, ok := curr[key].(map[string]interface{})
+
+		if !ok {
+			return nil
+		}
+
+		curr = next
+	}
+
+	return curr
+}
+
+// SetImageValues sets the image repository and tag under a dot-separated image values
+// key. Other values under the key, such as the pull policy, are kept.
+func SetImageValues(values map[string]interface{}, imageValuesKey string, repository interface{}, tag string) {
+	curr := values
+
+	for _, key := range strings.Split(imageValuesKey, ".") {
+		next, ok := curr[key].(map[string]interface{})
+
+		if !ok {
+			next = make(map[string]interface{})
+			curr[key] = next
+		}
+
+		curr = next
+	}
+
+	curr["repository"] = repository
+	curr["tag"] = tag
+}
+
+// ArchiveRepoChart packages the chart in a directory of a gzipped tarball of a git repo,
+// such as the tarballs of the Github API, whose files are all in a single top-level
+// directory
+func ArchiveRepoChart(repoArchive io.Reader, chartPath string) ([]byte, error) {
+	chartPath = strings.Trim(path.Clean("/"+chartPath), "/")
+
+	gzr, err := gzip.NewReader(repoArchive)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read repo archive: %w", err)
+	}
+
+	defer gzr.Close()
+
+	dirName := path.Base(chartPath)
+
+	if chartPath == "" {
+		dirName = "chart"
+	}
+
+	buf := &bytes.Buffer{}
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+
+	tr := tar.NewReader(gzr)
+	size := 0
+	found := false
+
+	for {
+		header, err := tr.Next()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read repo archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// the top-level directory of the archive is named after the repo and commit
+		parts := strings.SplitN(header.Name, "/", 2)
+
+		if len(parts) != 2 {
+			continue
+		}
+
+		relPath := parts[1]
+
+		if chartPath != "" {
+			if !strings.HasPrefix(relPath, chartPath+"/") {
+				continue
+			}
+
+			relPath = strings.TrimPrefix(relPath, chartPath+"/")
+		}
+
+		if size += int(header.Size); size > MaxCustomChartSize {
+			return nil, fmt.Errorf("chart is larger than %d MB", MaxCustomChartSize>>20)
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    path.Join(dirName, relPath),
+			Mode:    0644,
+			Size:    header.Size,
+			ModTime: header.ModTime,
+		}); err != nil {
+			return nil, err
+		}
+
+		if _, err := io.Copy(tw, tr); err != nil {
+			return nil, err
+		}
+
+		found = found || relPath == "Chart.yaml"
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no Chart.yaml found in %s", path.Join("/", chartPath))
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := gzw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,185 @@
+package helm_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"gopkg.in/yaml.v2"
+)
+
+func getTestRepoArchive(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     "porter-dev-example-abc123/" + name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		}); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	tw.Close()
+	gzw.Close()
+
+	return buf
+}
+
+func TestCustomChart(t *testing.T) {
+	repoArchive := getTestRepoArchive(t, map[string]string{
+		"README.md":                          "# example",
+		"deploy/chart/Chart.yaml":            "apiVersion: v2\nname: example\nversion: 1.2.0\n",
+		"deploy/chart/values.yaml":           "app:\n  image:\n    repository: nginx\n    tag: latest\n    pullPolicy: Always\n",
+		"deploy/chart/templates/deploy.yaml": "kind: Deployment\n",
+		"deploy/other/Chart.yaml":            "apiVersion: v2\nname: other\nversion: 0.1.0\n",
+	})
+
+	archive, err := helm.ArchiveRepoChart(repoArchive, "/deploy/chart/")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ch, err := helm.LoadCustomChart(archive, "app.image")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ch.Metadata.Name != "example" || ch.Metadata.Version != "1.2.0" || len(ch.Templates) != 1 {
+		t.Fatalf("incorrect chart: %+v", ch.Metadata)
+	}
+
+	if !helm.IsCustomChart(ch) || helm.GetImageValuesKey(ch) != "app.image" {
+		t.Errorf("expected a custom chart with the app.image key, got %v", ch.Metadata.Annotations)
+	}
+
+	values := ch.Values
+	helm.SetImageValues(values, helm.GetImageValuesKey(ch), "gcr.io/example/web", "abc123")
+
+	expected := map[string]interface{}{
+		"repository": "gcr.io/example/web",
+		"tag":        "abc123",
+		"pullPolicy": "Always",
+	}
+
+	if image := helm.GetImageValues(values, "app.image"); !reflect.DeepEqual(expected, image) {
+		t.Errorf("incorrect image values: %v", image)
+	}
+
+	if _, err := helm.ArchiveRepoChart(getTestRepoArchive(t, map[string]string{"README.md": ""}), "chart"); err == nil ||
+		!strings.Contains(err.Error(), "no Chart.yaml found in /chart") {
+		t.Errorf("expected an error for a missing chart, got %v", err)
+	}
+}
+
+func TestCustomChartNotInstallable(t *testing.T) {
+	archive, err := helm.ArchiveRepoChart(getTestRepoArchive(t, map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: library\nversion: 0.1.0\ntype: library\n",
+	}), "")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := helm.LoadCustomChart(archive, ""); err == nil {
+		t.Errorf("expected library charts to be rejected")
+	}
+}
+
+func TestSyncedEnvGroupsPostrenderer(t *testing.T) {
+	values := map[string]interface{}{
+		"container": map[string]interface{}{
+			"env": map[string]interface{}{
+				"synced": []interface{}{
+					map[string]interface{}{
+						"name":    "shared",
+						"version": float64(3),
+						"keys": []interface{}{
+							map[string]interface{}{"name": "API_URL", "secret": false},
+						},
+					},
+					map[string]interface{}{
+						"name":    "secrets",
+						"version": float64(1),
+						"keys": []interface{}{
+							map[string]interface{}{"name": "TOKEN", "secret": true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	postrenderer, err := helm.NewSyncedEnvGroupsPostrenderer(values)
+
+	if err != nil || postrenderer == nil {
+		t.Fatalf("expected a postrenderer, got %v", err)
+	}
+
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        envFrom:
+        - configMapRef:
+            name: shared.v3
+`
+
+	res, err := postrenderer.Run(bytes.NewBufferString(manifest))
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	deployment := struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						EnvFrom []map[string]map[string]string `yaml:"envFrom"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}{}
+
+	if err := yaml.Unmarshal(res.Bytes(), &deployment); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expected := []map[string]map[string]string{
+		{"configMapRef": {"name": "shared.v3"}},
+		{"configMapRef": {"name": "secrets.v1"}},
+		{"secretRef": {"name": "secrets.v1"}},
+	}
+
+	if envFrom := deployment.Spec.Template.Spec.Containers[0].EnvFrom; !reflect.DeepEqual(expected, envFrom) {
+		t.Errorf("incorrect env sources: %v", envFrom)
+	}
+
+	if postrenderer, err := helm.NewSyncedEnvGroupsPostrenderer(map[string]interface{}{"container": "web"}); err != nil || postrenderer != nil {
+		t.Errorf("expected no postrenderer without synced env groups, got %v", err)
+	}
+}
@@ -0,0 +1,357 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"helm.sh/helm/v3/pkg/release"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+var (
+	// svcTemplateRegex matches the svc env templates, which reference a release by name
+	svcTemplateRegex = regexp.MustCompile(`\{\{-?\s*svc\s+"([^"]+)"(?:\s+"([^"]+)")?`)
+
+	// hostnameRegex matches dotted hostnames, such as backend.default.svc.cluster.local
+	hostnameRegex = regexp.MustCompile(`[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)+`)
+
+	// bareHostRegex matches values that only consist of a hostname without dots and an
+	// optional port, such as backend:8080
+	bareHostRegex = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?)(:[0-9]+)?$`)
+)
+
+// DependencyGraphRelease is a release that is added to a dependency graph
+type DependencyGraphRelease struct {
+	Release *release.Release
+
+	// Dependencies are the dependencies declared by the user, of the form name for
+	// releases in the same namespace or namespace/name
+	Dependencies []string
+}
+
+// GetDependencyGraph computes the dependencies between releases. A release depends on
+// another release if it declares the dependency, or if its values or the values of an
+// env group that it uses reference a service of the other release, either through the
+// svc env template or through the DNS name of the service. Only the latest version of
+// each env group should be passed. Dependencies on releases that are not in the graph
+// are ignored.
+func GetDependencyGraph(
+	releases []*DependencyGraphRelease,
+	envGroups []v1.ConfigMap,
+) (*types.DependencyGraph, error) {
+	g := &dependencyGraph{
+		nodes:     make(map[string]*types.DependencyGraphNode),
+		edges:     make(map[string]map[string]map[types.DependencyReason]bool),
+		hosts:     make(map[string]string),
+		bareHosts: make(map[string]map[string]string),
+	}
+
+	for _, rel := range releases {
+		id := getDependencyGraphID(rel.Release.Namespace, rel.Release.Name)
+
+		g.nodes[id] = &types.DependencyGraphNode{
+			ID:        id,
+			Name:      rel.Release.Name,
+			Namespace: rel.Release.Namespace,
+		}
+
+		yamlArr := grapher.ImportMultiDocYAML([]byte(rel.Release.Manifest))
+
+		for _, obj := range grapher.ParseObjs(yamlArr, rel.Release.Namespace) {
+			if obj.Kind == "Service" {
+				g.addService(obj.Name, obj.Namespace, id)
+			}
+		}
+	}
+
+	// configMapConsumers maps the env group configmaps to the releases that use them
+	configMapConsumers := make(map[string]map[string]bool)
+
+	addConsumer := func(configMapID, releaseID string) {
+		if _, ok := configMapConsumers[configMapID]; !ok {
+			configMapConsumers[configMapID] = make(map[string]bool)
+		}
+
+		configMapConsumers[configMapID][releaseID] = true
+	}
+
+	for _, envGroup := range envGroups {
+		configMapID := getDependencyGraphID(envGroup.Namespace, envGroup.Name)
+
+		for _, app := range strings.Split(envGroup.Annotations[kubernetes.PorterAppAnnotationName], ",") {
+			if app != "" {
+				addConsumer(configMapID, getDependencyGraphID(envGroup.Namespace, app))
+			}
+		}
+	}
+
+	for _, rel := range releases {
+		id := getDependencyGraphID(rel.Release.Namespace, rel.Release.Name)
+		namespace := rel.Release.Namespace
+
+		for _, dep := range rel.Dependencies {
+			depID := dep
+
+			if !strings.Contains(dep, "/") {
+				depID = getDependencyGraphID(namespace, dep)
+			}
+
+			g.addEdge(id, depID, types.DependencyReasonExplicit)
+		}
+
+		for _, value := range getStringValues(rel.Release.Config) {
+			for _, depID := range g.getReferencedReleases(value, namespace) {
+				g.addEdge(id, depID, types.DependencyReasonValues)
+			}
+		}
+
+		resources, err := decodeRenderedManifests(bytes.NewBufferString(rel.Release.Manifest))
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, res := range resources {
+			kind, _ := res["kind"].(string)
+			podSpec := getPodSpecFromResource(kind, res)
+
+			if podSpec == nil {
+				continue
+			}
+
+			for _, ref := range getEnvSourceRefs(podSpec) {
+				if name := strings.TrimPrefix(ref, "ConfigMap/"); name != ref {
+					addConsumer(getDependencyGraphID(namespace, name), id)
+				}
+			}
+		}
+	}
+
+	for _, envGroup := range envGroups {
+		consumers := configMapConsumers[getDependencyGraphID(envGroup.Namespace, envGroup.Name)]
+
+		for _, value := range envGroup.Data {
+			for _, depID := range g.getReferencedReleases(value, envGroup.Namespace) {
+				for id := range consumers {
+					g.addEdge(id, depID, types.DependencyReasonEnv)
+				}
+			}
+		}
+	}
+
+	return g.toDependencyGraph(), nil
+}
+
+type dependencyGraph struct {
+	nodes map[string]*types.DependencyGraphNode
+
+	// edges maps the IDs of releases to the IDs of the releases they depend on, and the
+	// reasons for the dependencies
+	edges map[string]map[string]map[types.DependencyReason]bool
+
+	// hosts maps the dotted DNS names of services to the IDs of their releases, and
+	// bareHosts maps namespaces and service names to the IDs of their releases
+	hosts     map[string]string
+	bareHosts map[string]map[string]string
+}
+
+func getDependencyGraphID(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+func (g *dependencyGraph) addService(name, namespace, releaseID string) {
+	for _, host := range []string{
+		fmt.Sprintf("%s.%s", name, namespace),
+		fmt.Sprintf("%s.%s.svc", name, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+	} {
+		g.hosts[host] = releaseID
+	}
+
+	if _, ok := g.bareHosts[namespace]; !ok {
+		g.bareHosts[namespace] = make(map[string]string)
+	}
+
+	g.bareHosts[namespace][name] = releaseID
+}
+
+func (g *dependencyGraph) addEdge(from, to string, reason types.DependencyReason) {
+	if from == to || g.nodes[from] == nil || g.nodes[to] == nil {
+		return
+	}
+
+	if _, ok := g.edges[from]; !ok {
+		g.edges[from] = make(map[string]map[types.DependencyReason]bool)
+	}
+
+	if _, ok := g.edges[from][to]; !ok {
+		g.edges[from][to] = make(map[types.DependencyReason]bool)
+	}
+
+	g.edges[from][to][reason] = true
+}
+
+// getReferencedReleases returns the IDs of the releases that a value references. Service
+// names without a namespace are only matched if they make up the whole value, since
+// short names are likely to appear in unrelated values.
+func (g *dependencyGraph) getReferencedReleases(value, namespace string) []string {
+	res := make([]string, 0)
+
+	for _, match := range svcTemplateRegex.FindAllStringSubmatch(value, -1) {
+		refNamespace := namespace
+
+		if match[2] != "" {
+			refNamespace = match[2]
+		}
+
+		res = append(res, getDependencyGraphID(refNamespace, match[1]))
+	}
+
+	lowerValue := strings.ToLower(value)
+
+	for _, host := range hostnameRegex.FindAllString(lowerValue, -1) {
+		if id, ok := g.hosts[host]; ok {
+			res = append(res, id)
+		}
+	}
+
+	bareHost := ""
+	trimmedValue := strings.TrimSpace(lowerValue)
+
+	if match := bareHostRegex.FindStringSubmatch(trimmedValue); match != nil {
+		bareHost = match[1]
+	} else if parsedURL, err := url.Parse(trimmedValue); err == nil {
+		bareHost = parsedURL.Hostname()
+	}
+
+	if id, ok := g.bareHosts[namespace][bareHost]; ok && bareHost != "" {
+		res = append(res, id)
+	}
+
+	return res
+}
+
+// toDependencyGraph sorts the nodes and edges of the graph, and orders the releases in
+// deploy stages
+func (g *dependencyGraph) toDependencyGraph() *types.DependencyGraph {
+	res := &types.DependencyGraph{
+		Nodes:  make([]*types.DependencyGraphNode, 0),
+		Edges:  make([]*types.DependencyGraphEdge, 0),
+		Stages: make([][]string, 0),
+		Cyclic: make([]string, 0),
+	}
+
+	ids := make([]string, 0, len(g.nodes))
+
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		res.Nodes = append(res.Nodes, g.nodes[id])
+
+		deps := make([]string, 0, len(g.edges[id]))
+
+		for dep := range g.edges[id] {
+			deps = append(deps, dep)
+		}
+
+		sort.Strings(deps)
+
+		for _, dep := range deps {
+			reasons := make([]types.DependencyReason, 0)
+
+			for _, reason := range []types.DependencyReason{
+				types.DependencyReasonExplicit,
+				types.DependencyReasonEnv,
+				types.DependencyReasonValues,
+			} {
+				if g.edges[id][dep][reason] {
+					reasons = append(reasons, reason)
+				}
+			}
+
+			res.Edges = append(res.Edges, &types.DependencyGraphEdge{
+				From:    id,
+				To:      dep,
+				Reasons: reasons,
+			})
+		}
+	}
+
+	// releases are added to a stage once all of their dependencies are in earlier
+	// stages, and the releases that are left over are part of or depend on a cycle
+	staged := make(map[string]bool)
+
+	for {
+		stage := make([]string, 0)
+
+		for _, id := range ids {
+			if staged[id] {
+				continue
+			}
+
+			ready := true
+
+			for dep := range g.edges[id] {
+				ready = ready && staged[dep]
+			}
+
+			if ready {
+				stage = append(stage, id)
+			}
+		}
+
+		if len(stage) == 0 {
+			break
+		}
+
+		for _, id := range stage {
+			staged[id] = true
+		}
+
+		res.Stages = append(res.Stages, stage)
+	}
+
+	for _, id := range ids {
+		if !staged[id] {
+			res.Cyclic = append(res.Cyclic, id)
+		}
+	}
+
+	return res
+}
+
+// getStringValues returns all string values in a nested values map
+func getStringValues(values interface{}) []string {
+	res := make([]string, 0)
+
+	switch v := values.(type) {
+	case string:
+		res = append(res, v)
+	case map[string]interface{}:
+		for _, val := range v {
+			res = append(res, getStringValues(val)...)
+		}
+	case map[interface{}]interface{}:
+		for _, val := range v {
+			res = append(res, getStringValues(val)...)
+		}
+	case []interface{}:
+		for _, val := range v {
+			res = append(res, getStringValues(val)...)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,135 @@
+package helm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"helm.sh/helm/v3/pkg/release"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getDependencyGraphRelease(name string, config map[string]interface{}, deps ...string) *helm.DependencyGraphRelease {
+	return &helm.DependencyGraphRelease{
+		Release: &release.Release{
+			Name:      name,
+			Namespace: "default",
+			Config:    config,
+			Manifest: fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %s
+spec:
+  template:
+    spec:
+      containers:
+      - name: %s
+        image: nginx
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: %s-svc
+`, name, name, name),
+		},
+		Dependencies: deps,
+	}
+}
+
+func TestGetDependencyGraph(t *testing.T) {
+	releases := []*helm.DependencyGraphRelease{
+		getDependencyGraphRelease("db", nil),
+		getDependencyGraphRelease("api", map[string]interface{}{
+			"container": map[string]interface{}{
+				"env": map[string]interface{}{
+					"normal": map[string]interface{}{
+						"DB_HOST": "db-svc.default.svc.cluster.local:5432",
+					},
+				},
+			},
+		}),
+		getDependencyGraphRelease("web", map[string]interface{}{
+			"upstream": "http://api-svc:8080",
+		}),
+		getDependencyGraphRelease("worker", nil, "db", "default/missing"),
+	}
+
+	envGroups := []v1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared.v2",
+				Namespace: "default",
+				Annotations: map[string]string{
+					kubernetes.PorterAppAnnotationName: "worker",
+				},
+			},
+			Data: map[string]string{
+				"API_HOST":  `{{ svc "api" }}`,
+				"UNRELATED": "api-svc is not referenced by a partial value",
+			},
+		},
+	}
+
+	graph, err := helm.GetDependencyGraph(releases, envGroups)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expEdges := []*types.DependencyGraphEdge{
+		{From: "default/api", To: "default/db", Reasons: []types.DependencyReason{types.DependencyReasonValues}},
+		{From: "default/web", To: "default/api", Reasons: []types.DependencyReason{types.DependencyReasonValues}},
+		{From: "default/worker", To: "default/api", Reasons: []types.DependencyReason{types.DependencyReasonEnv}},
+		{From: "default/worker", To: "default/db", Reasons: []types.DependencyReason{types.DependencyReasonExplicit}},
+	}
+
+	if diff := deep.Equal(graph.Edges, expEdges); diff != nil {
+		t.Errorf("incorrect edges")
+		t.Error(diff)
+	}
+
+	expStages := [][]string{
+		{"default/db"},
+		{"default/api"},
+		{"default/web", "default/worker"},
+	}
+
+	if diff := deep.Equal(graph.Stages, expStages); diff != nil {
+		t.Errorf("incorrect stages")
+		t.Error(diff)
+	}
+
+	if len(graph.Cyclic) != 0 {
+		t.Errorf("expected no cyclic releases, got %v", graph.Cyclic)
+	}
+}
+
+func TestGetDependencyGraphCycle(t *testing.T) {
+	releases := []*helm.DependencyGraphRelease{
+		getDependencyGraphRelease("a", nil, "b"),
+		getDependencyGraphRelease("b", nil, "a"),
+		getDependencyGraphRelease("c", nil, "a"),
+		getDependencyGraphRelease("d", nil),
+	}
+
+	graph, err := helm.GetDependencyGraph(releases, nil)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if diff := deep.Equal(graph.Stages, [][]string{{"default/d"}}); diff != nil {
+		t.Errorf("incorrect stages")
+		t.Error(diff)
+	}
+
+	if diff := deep.Equal(graph.Cyclic, []string{"default/a", "default/b", "default/c"}); diff != nil {
+		t.Errorf("incorrect cyclic releases")
+		t.Error(diff)
+	}
+}
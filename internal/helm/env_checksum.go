@@ -0,0 +1,262 @@
+package helm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// EnvChecksumAnnotation is set on the pod templates of a release that restarts on env
+// changes. It stores a checksum of the configmaps and secrets that the pods read, so
+// that pods are rolled when an env group or a linked secret changes.
+const EnvChecksumAnnotation = "porter.run/env-checksum"
+
+// EnvChecksumPostrenderer adds the env checksum annotation to the pod templates of a
+// release. The data of configmaps and secrets in the rendered manifests is used if they
+// exist there, and is otherwise read from the cluster.
+type EnvChecksumPostrenderer struct {
+	agent     *kubernetes.Agent
+	namespace string
+
+	// rendered are the data of the configmaps and secrets in the rendered manifests,
+	// keyed by kind/name
+	rendered  map[string]interface{}
+	resources []resource
+}
+
+func NewEnvChecksumPostrenderer(agent *kubernetes.Agent, namespace string) *EnvChecksumPostrenderer {
+	return &EnvChecksumPostrenderer{
+		agent:     agent,
+		namespace: namespace,
+		rendered:  make(map[string]interface{}),
+		resources: make([]resource, 0),
+	}
+}
+
+func (e *EnvChecksumPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	e.resources, err = decodeRenderedManifests(renderedManifests)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range e.resources {
+		kind, _ := res["kind"].(string)
+		name, _ := getNestedResource(res, "metadata")["name"].(string)
+
+		if kind == "ConfigMap" || kind == "Secret" {
+			e.rendered[fmt.Sprintf("%s/%s", kind, name)] = []interface{}{res["data"], res["stringData"]}
+		}
+	}
+
+	for _, res := range e.resources {
+		kind, _ := res["kind"].(string)
+		podTemplate := getPodTemplateFromResource(kind, res)
+
+		if podTemplate == nil {
+			continue
+		}
+
+		checksum, err := e.getChecksum(getNestedResource(podTemplate, "spec"))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if checksum == "" {
+			continue
+		}
+
+		metadata, ok := podTemplate["metadata"].(resource)
+
+		if !ok {
+			metadata = make(resource)
+			podTemplate["metadata"] = metadata
+		}
+
+		annotations, ok := metadata["annotations"].(resource)
+
+		if !ok {
+			annotations = make(resource)
+			metadata["annotations"] = annotations
+		}
+
+		annotations[EnvChecksumAnnotation] = checksum
+	}
+
+	modifiedManifests = bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(modifiedManifests)
+	defer encoder.Close()
+
+	for _, resource := range e.resources {
+		err = encoder.Encode(resource)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modifiedManifests, nil
+}
+
+// getChecksum computes the checksum of the configmaps and secrets referenced by a pod
+// spec, or returns an empty string if the pod spec does not reference any
+func (e *EnvChecksumPostrenderer) getChecksum(podSpec resource) (string, error) {
+	refs := getEnvSourceRefs(podSpec)
+
+	if len(refs) == 0 {
+		return "", nil
+	}
+
+	sort.Strings(refs)
+
+	hash := sha256.New()
+
+	for _, ref := range refs {
+		data, err := e.getData(ref)
+
+		if err != nil {
+			return "", err
+		}
+
+		// yaml.v2 sorts map keys, so the encoding of the data is stable
+		dataBytes, err := yaml.Marshal(data)
+
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(hash, "%s\n%s\n", ref, dataBytes)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// getData returns the data of a configmap or secret of the form kind/name. Missing
+// configmaps and secrets have no data, so that the checksum changes once they are
+// created.
+func (e *EnvChecksumPostrenderer) getData(ref string) (interface{}, error) {
+	if data, ok := e.rendered[ref]; ok {
+		return data, nil
+	}
+
+	refArr := strings.SplitN(ref, "/", 2)
+	kind, name := refArr[0], refArr[1]
+
+	switch kind {
+	case "ConfigMap":
+		configMap, err := e.agent.GetConfigMap(name, e.namespace)
+
+		if err != nil && errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		return []interface{}{configMap.Data, configMap.BinaryData}, nil
+	case "Secret":
+		secret, err := e.agent.GetSecret(name, e.namespace)
+
+		if err != nil && errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		return secret.Data, nil
+	}
+
+	return nil, nil
+}
+
+// getEnvSourceRefs returns the configmaps and secrets that are referenced by the env
+// variables and volumes of a pod spec, of the form kind/name
+func getEnvSourceRefs(podSpec resource) []string {
+	refs := make(map[string]bool)
+
+	addRef := func(kind string, nameVal interface{}) {
+		if name, ok := nameVal.(string); ok && name != "" {
+			refs[fmt.Sprintf("%s/%s", kind, name)] = true
+		}
+	}
+
+	for _, key := range []string{"initContainers", "containers"} {
+		containers, _ := podSpec[key].([]interface{})
+
+		for _, containerVal := range containers {
+			container, ok := containerVal.(resource)
+
+			if !ok {
+				continue
+			}
+
+			env, _ := container["env"].([]interface{})
+
+			for _, envVarVal := range env {
+				envVar, ok := envVarVal.(resource)
+
+				if !ok {
+					continue
+				}
+
+				addRef("ConfigMap", getNestedResource(envVar, "valueFrom", "configMapKeyRef")["name"])
+				addRef("Secret", getNestedResource(envVar, "valueFrom", "secretKeyRef")["name"])
+			}
+
+			envFrom, _ := container["envFrom"].([]interface{})
+
+			for _, envFromVal := range envFrom {
+				source, ok := envFromVal.(resource)
+
+				if !ok {
+					continue
+				}
+
+				addRef("ConfigMap", getNestedResource(source, "configMapRef")["name"])
+				addRef("Secret", getNestedResource(source, "secretRef")["name"])
+			}
+		}
+	}
+
+	volumes, _ := podSpec["volumes"].([]interface{})
+
+	for _, volumeVal := range volumes {
+		volume, ok := volumeVal.(resource)
+
+		if !ok {
+			continue
+		}
+
+		addRef("ConfigMap", getNestedResource(volume, "configMap")["name"])
+		addRef("Secret", getNestedResource(volume, "secret")["secretName"])
+
+		sources, _ := getNestedResource(volume, "projected")["sources"].([]interface{})
+
+		for _, sourceVal := range sources {
+			source, ok := sourceVal.(resource)
+
+			if !ok {
+				continue
+			}
+
+			addRef("ConfigMap", getNestedResource(source, "configMap")["name"])
+			addRef("Secret", getNestedResource(source, "secret")["name"])
+		}
+	}
+
+	res := make([]string, 0, len(refs))
+
+	for ref := range refs {
+		res = append(res, ref)
+	}
+
+	return res
+}
@@ -0,0 +1,111 @@
+package helm_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"gopkg.in/yaml.v2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testEnvManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        envFrom:
+        - configMapRef:
+            name: web-env
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      containers:
+      - name: worker
+        image: nginx
+`
+
+func getEnvChecksums(t *testing.T, agent *kubernetes.Agent) map[string]string {
+	t.Helper()
+
+	res, err := helm.NewEnvChecksumPostrenderer(agent, "default").Run(bytes.NewBufferString(testEnvManifest))
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	checksums := make(map[string]string)
+	decoder := yaml.NewDecoder(res)
+
+	for {
+		deployment := struct {
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+			Spec struct {
+				Template struct {
+					Metadata struct {
+						Annotations map[string]string `yaml:"annotations"`
+					} `yaml:"metadata"`
+				} `yaml:"template"`
+			} `yaml:"spec"`
+		}{}
+
+		if err := decoder.Decode(&deployment); err != nil {
+			break
+		}
+
+		if checksum, ok := deployment.Spec.Template.Metadata.Annotations[helm.EnvChecksumAnnotation]; ok {
+			checksums[deployment.Metadata.Name] = checksum
+		}
+	}
+
+	return checksums
+}
+
+func TestEnvChecksumPostrenderer(t *testing.T) {
+	configMap := func(value string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-env",
+				Namespace: "default",
+			},
+			Data: map[string]string{
+				"KEY": value,
+			},
+		}
+	}
+
+	checksums := getEnvChecksums(t, kubernetes.GetAgentTesting(configMap("1")))
+
+	if _, ok := checksums["worker"]; ok {
+		t.Errorf("expected deployment without env sources to not have a checksum")
+	}
+
+	checksum, ok := checksums["web"]
+
+	if !ok {
+		t.Fatalf("expected deployment with env sources to have a checksum")
+	}
+
+	if sameChecksum := getEnvChecksums(t, kubernetes.GetAgentTesting(configMap("1")))["web"]; sameChecksum != checksum {
+		t.Errorf("expected checksum to be stable, got %s and %s", checksum, sameChecksum)
+	}
+
+	if newChecksum := getEnvChecksums(t, kubernetes.GetAgentTesting(configMap("2")))["web"]; newChecksum == checksum {
+		t.Errorf("expected checksum to change when the configmap changes")
+	}
+}
@@ -0,0 +1,236 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/envtemplate"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// EnvTemplatesPostrenderer resolves the templates in the env variables of a release,
+// which reference the services of other releases and the credentials of managed
+// databases. Templates can be set as literal values or in env groups. Since resolved
+// values may contain credentials, they are stored in a secret that is added to the
+// release, and the env variables are pointed at that secret.
+type EnvTemplatesPostrenderer struct {
+	agent       *kubernetes.Agent
+	resolver    *envtemplate.Resolver
+	namespace   string
+	releaseName string
+
+	configMaps map[string]map[string]string
+	secrets    map[string]map[string][]byte
+
+	// resolved are the resolved values, keyed by container and env variable name, which
+	// are added to the secret of the release
+	resolved  map[string]string
+	resources []resource
+}
+
+func NewEnvTemplatesPostrenderer(
+	agent *kubernetes.Agent,
+	resolver *envtemplate.Resolver,
+	namespace, releaseName string,
+) *EnvTemplatesPostrenderer {
+	return &EnvTemplatesPostrenderer{
+		agent:       agent,
+		resolver:    resolver,
+		namespace:   namespace,
+		releaseName: releaseName,
+		configMaps:  make(map[string]map[string]string),
+		secrets:     make(map[string]map[string][]byte),
+		resolved:    make(map[string]string),
+		resources:   make([]resource, 0),
+	}
+}
+
+// GetEnvTemplatesSecretName returns the name of the secret that stores the resolved env
+// group values of a release
+func GetEnvTemplatesSecretName(releaseName string) string {
+	return fmt.Sprintf("%s-env-templates", releaseName)
+}
+
+func (e *EnvTemplatesPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	e.resources, err = decodeRenderedManifests(renderedManifests)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range e.resources {
+		kind, _ := res["kind"].(string)
+		podSpec := getPodSpecFromResource(kind, res)
+
+		if podSpec == nil {
+			continue
+		}
+
+		for _, key := range []string{"initContainers", "containers"} {
+			containers, _ := podSpec[key].([]interface{})
+
+			for _, containerVal := range containers {
+				container, ok := containerVal.(resource)
+
+				if !ok {
+					continue
+				}
+
+				containerName, _ := container["name"].(string)
+				env, _ := container["env"].([]interface{})
+
+				for _, envVarVal := range env {
+					envVar, ok := envVarVal.(resource)
+
+					if !ok {
+						continue
+					}
+
+					if err := e.resolveEnvVar(containerName, envVar); err != nil {
+						name, _ := envVar["name"].(string)
+
+						return nil, fmt.Errorf("could not resolve env variable %s: %v", name, err)
+					}
+				}
+			}
+		}
+	}
+
+	if len(e.resolved) > 0 {
+		data := make(resource)
+
+		for key, val := range e.resolved {
+			data[key] = base64.StdEncoding.EncodeToString([]byte(val))
+		}
+
+		e.resources = append(e.resources, resource{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"type":       "Opaque",
+			"metadata": resource{
+				"name":      GetEnvTemplatesSecretName(e.releaseName),
+				"namespace": e.namespace,
+			},
+			"data": data,
+		})
+	}
+
+	modifiedManifests = bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(modifiedManifests)
+	defer encoder.Close()
+
+	for _, resource := range e.resources {
+		err = encoder.Encode(resource)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modifiedManifests, nil
+}
+
+func (e *EnvTemplatesPostrenderer) resolveEnvVar(containerName string, envVar resource) error {
+	var value string
+
+	if literal, ok := envVar["value"].(string); ok {
+		value = literal
+	} else if ref := getNestedResource(envVar, "valueFrom", "configMapKeyRef"); ref != nil {
+		name, _ := ref["name"].(string)
+		key, _ := ref["key"].(string)
+
+		data, err := e.getConfigMapData(name)
+
+		if err != nil {
+			return err
+		}
+
+		value = data[key]
+	} else if ref := getNestedResource(envVar, "valueFrom", "secretKeyRef"); ref != nil {
+		name, _ := ref["name"].(string)
+		key, _ := ref["key"].(string)
+
+		data, err := e.getSecretData(name)
+
+		if err != nil {
+			return err
+		}
+
+		value = string(data[key])
+	}
+
+	if !envtemplate.IsTemplate(value) {
+		return nil
+	}
+
+	resolved, err := e.resolver.Resolve(value)
+
+	if err != nil {
+		return err
+	}
+
+	envName, _ := envVar["name"].(string)
+	secretKey := fmt.Sprintf("%s.%s", containerName, envName)
+
+	e.resolved[secretKey] = resolved
+
+	delete(envVar, "value")
+
+	envVar["valueFrom"] = resource{
+		"secretKeyRef": resource{
+			"name": GetEnvTemplatesSecretName(e.releaseName),
+			"key":  secretKey,
+		},
+	}
+
+	return nil
+}
+
+// getConfigMapData reads the data of an env group configmap. Configmaps that are not
+// part of an env group are skipped, since their values are not templated.
+func (e *EnvTemplatesPostrenderer) getConfigMapData(name string) (map[string]string, error) {
+	if data, ok := e.configMaps[name]; ok {
+		return data, nil
+	}
+
+	configMap, err := e.agent.GetConfigMap(name, e.namespace)
+
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err == nil && configMap.Labels["envgroup"] != "" {
+		e.configMaps[name] = configMap.Data
+	} else {
+		e.configMaps[name] = map[string]string{}
+	}
+
+	return e.configMaps[name], nil
+}
+
+// getSecretData reads the data of an env group secret. Secrets that are not part of an
+// env group are skipped, since their values are not templated.
+func (e *EnvTemplatesPostrenderer) getSecretData(name string) (map[string][]byte, error) {
+	if data, ok := e.secrets[name]; ok {
+		return data, nil
+	}
+
+	secret, err := e.agent.GetSecret(name, e.namespace)
+
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err == nil && secret.Labels["envgroup"] != "" {
+		e.secrets[name] = secret.Data
+	} else {
+		e.secrets[name] = map[string][]byte{}
+	}
+
+	return e.secrets[name], nil
+}
@@ -0,0 +1,80 @@
+package helm_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/envtemplate"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testEnvTemplatesManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        env:
+        - name: BACKEND_HOST
+          value: '{{ svc "backend" }}'
+        - name: PLAIN
+          value: plain
+`
+
+func TestEnvTemplatesPostrenderer(t *testing.T) {
+	agent := kubernetes.GetAgentTesting(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backend-web",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app.kubernetes.io/instance": "backend",
+			},
+		},
+	})
+
+	resolver := envtemplate.NewResolver(agent, nil, nil, "default")
+
+	res, err := helm.NewEnvTemplatesPostrenderer(agent, resolver, "default", "web").Run(
+		bytes.NewBufferString(testEnvTemplatesManifest),
+	)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	manifest := res.String()
+
+	if strings.Contains(manifest, "svc \"backend\"") {
+		t.Errorf("expected the template to be resolved, got:\n%s", manifest)
+	}
+
+	if !strings.Contains(manifest, helm.GetEnvTemplatesSecretName("web")) {
+		t.Errorf("expected the env variable to reference the env templates secret, got:\n%s", manifest)
+	}
+
+	if !strings.Contains(manifest, "value: plain") {
+		t.Errorf("expected values that are not templates to be left untouched, got:\n%s", manifest)
+	}
+}
+
+func TestEnvTemplatesPostrendererMissingService(t *testing.T) {
+	agent := kubernetes.GetAgentTesting()
+	resolver := envtemplate.NewResolver(agent, nil, nil, "default")
+
+	_, err := helm.NewEnvTemplatesPostrenderer(agent, resolver, "default", "web").Run(
+		bytes.NewBufferString(testEnvTemplatesManifest),
+	)
+
+	if err == nil {
+		t.Errorf("expected an error when the referenced release has no service")
+	}
+}
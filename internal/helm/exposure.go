@@ -0,0 +1,161 @@
+package helm
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// grpcBackendProtocolAnnotation makes ingress-nginx forward requests to the backend
+	// over cleartext HTTP/2
+	grpcBackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+
+	// h2cAppProtocol marks service ports that serve cleartext HTTP/2
+	h2cAppProtocol = "kubernetes.io/h2c"
+)
+
+// GetServiceExposure reads the service exposure of a release from its values. It returns
+// nil if the values do not contain an exposure.
+func GetServiceExposure(values map[string]interface{}) (*types.ServiceExposure, error) {
+	exposure := &types.ServiceExposure{}
+
+	if ok, err := decodeValuesKey(values, types.ExposureValuesKey, exposure); !ok || err != nil {
+		return nil, err
+	}
+
+	return exposure, nil
+}
+
+// ServiceExposurePostrenderer renders the service exposure of a release into its ingress
+// and services. gRPC releases keep their ingress, which forwards requests over h2c. TCP
+// and UDP releases have their ingress removed, and their services are exposed through a
+// load balancer or through the tcp and udp proxy of the ingress controller. Internal
+// releases have their ingress removed, and their services are only reachable in the
+// cluster.
+type ServiceExposurePostrenderer struct {
+	exposure *types.ServiceExposure
+
+	resources []resource
+}
+
+// NewServiceExposurePostrenderer returns a postrenderer for the exposure in the values of
+// a release, or nil if the release is exposed over http through its ingress
+func NewServiceExposurePostrenderer(values map[string]interface{}) (*ServiceExposurePostrenderer, error) {
+	exposure, err := GetServiceExposure(values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if exposure == nil || (exposure.Protocol == types.ServiceProtocolHTTP || exposure.Protocol == "") && !exposure.IsInternal() {
+		return nil, nil
+	}
+
+	return &ServiceExposurePostrenderer{
+		exposure:  exposure,
+		resources: make([]resource, 0),
+	}, nil
+}
+
+func (s *ServiceExposurePostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	resources, err := decodeRenderedManifests(renderedManifests)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range resources {
+		kind, _ := res["kind"].(string)
+
+		switch kind {
+		case "Ingress":
+			if !s.exposure.UsesIngress() {
+				continue
+			}
+
+			s.updateIngress(res)
+		case "Service":
+			s.updateService(res)
+		}
+
+		s.resources = append(s.resources, res)
+	}
+
+	modifiedManifests = bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(modifiedManifests)
+	defer encoder.Close()
+
+	for _, resource := range s.resources {
+		err = encoder.Encode(resource)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modifiedManifests, nil
+}
+
+func (s *ServiceExposurePostrenderer) updateIngress(res resource) {
+	if s.exposure.Protocol != types.ServiceProtocolGRPC {
+		return
+	}
+
+	metadata, ok := res["metadata"].(resource)
+
+	if !ok {
+		metadata = make(resource)
+		res["metadata"] = metadata
+	}
+
+	annotations, ok := metadata["annotations"].(resource)
+
+	if !ok {
+		annotations = make(resource)
+		metadata["annotations"] = annotations
+	}
+
+	annotations[grpcBackendProtocolAnnotation] = "GRPC"
+}
+
+func (s *ServiceExposurePostrenderer) updateService(res resource) {
+	spec := getNestedResource(res, "spec")
+
+	if spec == nil {
+		return
+	}
+
+	if s.exposure.IsInternal() {
+		spec["type"] = "ClusterIP"
+	} else if (s.exposure.Protocol == types.ServiceProtocolTCP || s.exposure.Protocol == types.ServiceProtocolUDP) &&
+		s.exposure.Mode != types.ExposureModeProxy {
+		spec["type"] = "LoadBalancer"
+	}
+
+	ports, _ := spec["ports"].([]interface{})
+
+	for _, p := range ports {
+		port, ok := p.(resource)
+
+		if !ok {
+			continue
+		}
+
+		// node ports are only allowed on services that are exposed outside the cluster
+		if s.exposure.IsInternal() {
+			delete(port, "nodePort")
+		}
+
+		switch s.exposure.Protocol {
+		case types.ServiceProtocolGRPC:
+			port["appProtocol"] = h2cAppProtocol
+		case types.ServiceProtocolTCP, types.ServiceProtocolUDP:
+			port["protocol"] = strings.ToUpper(string(s.exposure.Protocol))
+		}
+	}
+}
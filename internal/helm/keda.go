@@ -0,0 +1,339 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// KEDAChartName, KEDAChartRepoURL and KEDANamespace are the chart and namespace
+	// that KEDA is installed from and into
+	KEDAChartName    = "keda"
+	KEDAChartRepoURL = "https://kedacore.github.io/charts"
+	KEDANamespace    = "keda"
+)
+
+// GetReleaseScaler reads the queue scaler of a release from its values. It returns nil
+// if the values do not contain a scaler.
+func GetReleaseScaler(values map[string]interface{}) (*types.ReleaseScaler, error) {
+	scaler := &types.ReleaseScaler{}
+
+	if ok, err := decodeValuesKey(values, types.ScalerValuesKey, scaler); !ok || err != nil {
+		return nil, err
+	}
+
+	return scaler, nil
+}
+
+// GetKEDATrigger returns the type and metadata of the KEDA trigger of a scaler trigger,
+// or an error if the trigger is missing a field that its type requires
+func GetKEDATrigger(trigger types.ScalerTrigger) (string, map[string]string, error) {
+	var kedaType string
+	var metadata map[string]string
+	var missing string
+
+	target := strconv.Itoa(trigger.Target)
+
+	switch trigger.Type {
+	case types.ScalerTriggerSQS:
+		kedaType = "aws-sqs-queue"
+		metadata = map[string]string{
+			"queueURL":    trigger.QueueURL,
+			"awsRegion":   trigger.AWSRegion,
+			"queueLength": target,
+		}
+
+		// triggers without credentials are authenticated with the IAM role of the pods of
+		// the release, through the TriggerAuthentication of the scaler
+		if isPodIdentityTrigger(trigger) {
+			metadata["identityOwner"] = "pod"
+		} else {
+			metadata["awsAccessKeyIDFromEnv"] = trigger.AccessKeyIDFromEnv
+			metadata["awsSecretAccessKeyFromEnv"] = trigger.SecretAccessKeyFromEnv
+		}
+
+		if trigger.QueueURL == "" {
+			missing = "queue_url"
+		} else if trigger.AWSRegion == "" {
+			missing = "aws_region"
+		} else if trigger.AccessKeyIDFromEnv == "" && trigger.SecretAccessKeyFromEnv != "" {
+			missing = "access_key_id_from_env"
+		} else if trigger.AccessKeyIDFromEnv != "" && trigger.SecretAccessKeyFromEnv == "" {
+			missing = "secret_access_key_from_env"
+		}
+	case types.ScalerTriggerRabbitMQ:
+		kedaType = "rabbitmq"
+		metadata = map[string]string{
+			"queueName":   trigger.QueueName,
+			"mode":        "QueueLength",
+			"value":       target,
+			"hostFromEnv": trigger.HostFromEnv,
+		}
+
+		if trigger.QueueName == "" {
+			missing = "queue_name"
+		} else if trigger.HostFromEnv == "" {
+			missing = "host_from_env"
+		}
+	case types.ScalerTriggerRedis:
+		kedaType = "redis"
+		metadata = map[string]string{
+			"listName":       trigger.ListName,
+			"listLength":     target,
+			"addressFromEnv": trigger.HostFromEnv,
+		}
+
+		if trigger.PasswordFromEnv != "" {
+			metadata["passwordFromEnv"] = trigger.PasswordFromEnv
+		}
+
+		if trigger.ListName == "" {
+			missing = "list_name"
+		} else if trigger.HostFromEnv == "" {
+			missing = "host_from_env"
+		}
+	case types.ScalerTriggerKafka:
+		kedaType = "kafka"
+		metadata = map[string]string{
+			"bootstrapServers": trigger.BootstrapServers,
+			"consumerGroup":    trigger.ConsumerGroup,
+			"topic":            trigger.Topic,
+			"lagThreshold":     target,
+		}
+
+		if trigger.BootstrapServers == "" {
+			missing = "bootstrap_servers"
+		} else if trigger.ConsumerGroup == "" {
+			missing = "consumer_group"
+		} else if trigger.Topic == "" {
+			missing = "topic"
+		}
+	case types.ScalerTriggerPubSub:
+		credentialsFromEnv := trigger.CredentialsFromEnv
+
+		if credentialsFromEnv == "" {
+			credentialsFromEnv = types.PubSubCredentialsEnv
+		}
+
+		kedaType = "gcp-pubsub"
+		metadata = map[string]string{
+			"subscriptionName":   trigger.SubscriptionName,
+			"subscriptionSize":   target,
+			"credentialsFromEnv": credentialsFromEnv,
+		}
+
+		if trigger.SubscriptionName == "" {
+			missing = "subscription_name"
+		}
+	default:
+		return "", nil, fmt.Errorf("unsupported scaler trigger type %q", trigger.Type)
+	}
+
+	if missing != "" {
+		return "", nil, fmt.Errorf("%s triggers require %s", trigger.Type, missing)
+	}
+
+	for key, val := range trigger.Metadata {
+		metadata[key] = val
+	}
+
+	return kedaType, metadata, nil
+}
+
+// isPodIdentityTrigger returns true if a trigger reads its queue with the IAM role of the
+// pods of the release, instead of credentials from env vars
+func isPodIdentityTrigger(trigger types.ScalerTrigger) bool {
+	return trigger.Type == types.ScalerTriggerSQS && trigger.AccessKeyIDFromEnv == "" && trigger.SecretAccessKeyFromEnv == ""
+}
+
+// KEDAScalerPostrenderer adds a KEDA ScaledObject for the queue scaler of a release,
+// which scales the deployment of the release. HorizontalPodAutoscalers of the release
+// are removed, as KEDA creates its own autoscaler for the deployment, and so are the
+// replicas of the deployment, which would otherwise reset the replicas that KEDA scaled
+// the deployment to on every deploy. Releases without a deployment, such as jobs, are
+// not changed.
+type KEDAScalerPostrenderer struct {
+	scaler      *types.ReleaseScaler
+	releaseName string
+
+	resources []resource
+}
+
+// NewKEDAScalerPostrenderer returns a postrenderer for the scaler in the values of a
+// release, or nil if the release has no enabled scaler
+func NewKEDAScalerPostrenderer(
+	values map[string]interface{},
+	releaseName string,
+) (*KEDAScalerPostrenderer, error) {
+	scaler, err := GetReleaseScaler(values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if scaler == nil || !scaler.Enabled || len(scaler.Triggers) == 0 {
+		return nil, nil
+	}
+
+	return &KEDAScalerPostrenderer{
+		scaler:      scaler,
+		releaseName: releaseName,
+		resources:   make([]resource, 0),
+	}, nil
+}
+
+func (k *KEDAScalerPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	resources, err := decodeRenderedManifests(bytes.NewBuffer(renderedManifests.Bytes()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var deployment resource
+
+	for _, res := range resources {
+		if kind, _ := res["kind"].(string); kind == "Deployment" {
+			deployment = res
+			break
+		}
+	}
+
+	if deployment == nil {
+		return renderedManifests, nil
+	}
+
+	for _, res := range resources {
+		if kind, _ := res["kind"].(string); kind == "HorizontalPodAutoscaler" {
+			continue
+		}
+
+		k.resources = append(k.resources, res)
+	}
+
+	delete(getNestedResource(deployment, "spec"), "replicas")
+
+	deploymentName, _ := getNestedResource(deployment, "metadata")["name"].(string)
+
+	scalerResources, err := k.getScalerResources(deploymentName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	k.resources = append(k.resources, scalerResources...)
+
+	modifiedManifests = bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(modifiedManifests)
+	defer encoder.Close()
+
+	for _, resource := range k.resources {
+		err = encoder.Encode(resource)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modifiedManifests, nil
+}
+
+// getScalerResources returns the ScaledObject of the scaler, and the TriggerAuthentication
+// of its triggers that are authenticated with the IAM role of the pods of the release
+func (k *KEDAScalerPostrenderer) getScalerResources(deploymentName string) ([]resource, error) {
+	triggers := make([]interface{}, 0)
+	authName := fmt.Sprintf("%s-scaler-aws", k.releaseName)
+	podIdentity := false
+
+	for _, trigger := range k.scaler.Triggers {
+		kedaType, metadata, err := GetKEDATrigger(trigger)
+
+		if err != nil {
+			return nil, err
+		}
+
+		triggerMetadata := make(resource)
+
+		for key, val := range metadata {
+			triggerMetadata[key] = val
+		}
+
+		kedaTrigger := resource{
+			"type":     kedaType,
+			"metadata": triggerMetadata,
+		}
+
+		if isPodIdentityTrigger(trigger) {
+			kedaTrigger["authenticationRef"] = resource{
+				"name": authName,
+			}
+
+			podIdentity = true
+		}
+
+		triggers = append(triggers, kedaTrigger)
+	}
+
+	res := []resource{k.getScaledObject(deploymentName, triggers)}
+
+	if podIdentity {
+		res = append(res, resource{
+			"apiVersion": kubernetes.KEDAGroupVersion,
+			"kind":       "TriggerAuthentication",
+			"metadata": resource{
+				"name": authName,
+				"labels": resource{
+					"app.kubernetes.io/instance": k.releaseName,
+				},
+			},
+			"spec": resource{
+				"podIdentity": resource{
+					"provider": "aws-eks",
+				},
+			},
+		})
+	}
+
+	return res, nil
+}
+
+func (k *KEDAScalerPostrenderer) getScaledObject(deploymentName string, triggers []interface{}) resource {
+	spec := resource{
+		"scaleTargetRef": resource{
+			"kind": "Deployment",
+			"name": deploymentName,
+		},
+		"minReplicaCount": k.scaler.MinReplicas,
+		"triggers":        triggers,
+	}
+
+	if k.scaler.MaxReplicas > 0 {
+		spec["maxReplicaCount"] = k.scaler.MaxReplicas
+	}
+
+	if k.scaler.PollingInterval > 0 {
+		spec["pollingInterval"] = k.scaler.PollingInterval
+	}
+
+	if k.scaler.CooldownPeriod > 0 {
+		spec["cooldownPeriod"] = k.scaler.CooldownPeriod
+	}
+
+	return resource{
+		"apiVersion": kubernetes.KEDAGroupVersion,
+		"kind":       "ScaledObject",
+		"metadata": resource{
+			"name": fmt.Sprintf("%s-scaler", k.releaseName),
+			"labels": resource{
+				"app.kubernetes.io/instance": k.releaseName,
+			},
+		},
+		"spec": spec,
+	}
+}
@@ -0,0 +1,191 @@
+package helm_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"gopkg.in/yaml.v2"
+)
+
+const kedaTestManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 2
+---
+apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: worker
+`
+
+const kedaTestJobManifest = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+`
+
+func getKEDATestValues(trigger types.ScalerTrigger) map[string]interface{} {
+	return map[string]interface{}{
+		types.ScalerValuesKey: map[string]interface{}{
+			"enabled":      true,
+			"min_replicas": 0,
+			"max_replicas": 10,
+			"triggers": []interface{}{
+				map[string]interface{}{
+					"type":                       string(trigger.Type),
+					"target":                     trigger.Target,
+					"queue_url":                  trigger.QueueURL,
+					"aws_region":                 trigger.AWSRegion,
+					"access_key_id_from_env":     trigger.AccessKeyIDFromEnv,
+					"secret_access_key_from_env": trigger.SecretAccessKeyFromEnv,
+				},
+			},
+		},
+	}
+}
+
+func runKEDATestPostrenderer(t *testing.T, trigger types.ScalerTrigger, manifest string) map[string]map[string]interface{} {
+	postrenderer, err := helm.NewKEDAScalerPostrenderer(getKEDATestValues(trigger), "worker")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := postrenderer.Run(bytes.NewBufferString(manifest))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects := make(map[string]map[string]interface{})
+	decoder := yaml.NewDecoder(res)
+
+	for {
+		obj := make(map[string]interface{})
+
+		if err := decoder.Decode(&obj); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		objects[obj["kind"].(string)] = obj
+	}
+
+	return objects
+}
+
+func TestKEDAScalerPostrendererScalesDeployment(t *testing.T) {
+	objects := runKEDATestPostrenderer(t, types.ScalerTrigger{
+		Type:                   types.ScalerTriggerSQS,
+		Target:                 5,
+		QueueURL:               "https://sqs.us-east-1.amazonaws.com/123/jobs",
+		AWSRegion:              "us-east-1",
+		AccessKeyIDFromEnv:     "QUEUE_KEY_ID",
+		SecretAccessKeyFromEnv: "QUEUE_SECRET",
+	}, kedaTestManifest)
+
+	if _, ok := objects["HorizontalPodAutoscaler"]; ok {
+		t.Errorf("expected the autoscaler of the release to be removed")
+	}
+
+	if _, ok := objects["TriggerAuthentication"]; ok {
+		t.Errorf("expected no trigger authentication for triggers with credentials")
+	}
+
+	deploymentSpec := objects["Deployment"]["spec"].(map[interface{}]interface{})
+
+	if _, ok := deploymentSpec["replicas"]; ok {
+		t.Errorf("expected the replicas of the deployment to be removed")
+	}
+
+	scaledObject, ok := objects["ScaledObject"]
+
+	if !ok {
+		t.Fatalf("expected a scaled object")
+	}
+
+	spec := scaledObject["spec"].(map[interface{}]interface{})
+	target := spec["scaleTargetRef"].(map[interface{}]interface{})
+
+	if target["name"] != "worker" {
+		t.Errorf("expected the scaled object to scale deployment worker, got %v", target["name"])
+	}
+
+	metadata := spec["triggers"].([]interface{})[0].(map[interface{}]interface{})["metadata"].(map[interface{}]interface{})
+
+	if metadata["awsAccessKeyIDFromEnv"] != "QUEUE_KEY_ID" || metadata["awsSecretAccessKeyFromEnv"] != "QUEUE_SECRET" {
+		t.Errorf("expected the credentials to be read from the env vars of the trigger, got %v", metadata)
+	}
+}
+
+func TestKEDAScalerPostrendererPodIdentity(t *testing.T) {
+	objects := runKEDATestPostrenderer(t, types.ScalerTrigger{
+		Type:      types.ScalerTriggerSQS,
+		Target:    5,
+		QueueURL:  "https://sqs.us-east-1.amazonaws.com/123/jobs",
+		AWSRegion: "us-east-1",
+	}, kedaTestManifest)
+
+	auth, ok := objects["TriggerAuthentication"]
+
+	if !ok {
+		t.Fatalf("expected a trigger authentication for triggers without credentials")
+	}
+
+	authName := auth["metadata"].(map[interface{}]interface{})["name"]
+	trigger := objects["ScaledObject"]["spec"].(map[interface{}]interface{})["triggers"].([]interface{})[0].(map[interface{}]interface{})
+
+	if ref := trigger["authenticationRef"].(map[interface{}]interface{}); ref["name"] != authName {
+		t.Errorf("expected the trigger to reference trigger authentication %v, got %v", authName, ref["name"])
+	}
+
+	metadata := trigger["metadata"].(map[interface{}]interface{})
+
+	if _, ok := metadata["awsAccessKeyIDFromEnv"]; ok {
+		t.Errorf("expected no credentials env vars for triggers without credentials")
+	}
+}
+
+func TestKEDAScalerPostrendererWithoutDeployment(t *testing.T) {
+	postrenderer, err := helm.NewKEDAScalerPostrenderer(getKEDATestValues(types.ScalerTrigger{
+		Type:      types.ScalerTriggerSQS,
+		Target:    5,
+		QueueURL:  "https://sqs.us-east-1.amazonaws.com/123/jobs",
+		AWSRegion: "us-east-1",
+	}), "migrate")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := postrenderer.Run(bytes.NewBufferString(kedaTestJobManifest))
+
+	if err != nil {
+		t.Fatalf("expected releases without a deployment not to fail, got %v", err)
+	}
+
+	if res.String() != kedaTestJobManifest {
+		t.Errorf("expected the manifests of releases without a deployment not to change, got %s", res.String())
+	}
+}
+
+func TestGetKEDATriggerSQSCredentials(t *testing.T) {
+	_, _, err := helm.GetKEDATrigger(types.ScalerTrigger{
+		Type:               types.ScalerTriggerSQS,
+		Target:             5,
+		QueueURL:           "https://sqs.us-east-1.amazonaws.com/123/jobs",
+		AWSRegion:          "us-east-1",
+		AccessKeyIDFromEnv: "QUEUE_KEY_ID",
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "secret_access_key_from_env") {
+		t.Errorf("expected an error for a trigger with only an access key id, got %v", err)
+	}
+}
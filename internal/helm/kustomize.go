@@ -0,0 +1,186 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"sigs.k8s.io/kustomize/api/krusty"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// KustomizePostrenderer applies the strategic merge patches of a release to its rendered
+// manifests, as a kustomize overlay on top of the chart. This allows modifications that
+// the values of the chart do not support, without forking the chart.
+type KustomizePostrenderer struct {
+	patches []resource
+}
+
+// NewKustomizePostrenderer returns a postrenderer for the multi-document YAML patches of
+// a release, or nil if there are no patches
+func NewKustomizePostrenderer(patches string) (*KustomizePostrenderer, error) {
+	parsed, err := parseKustomizePatches(patches)
+
+	if err != nil {
+		return nil, err
+	} else if len(parsed) == 0 {
+		return nil, nil
+	}
+
+	return &KustomizePostrenderer{
+		patches: parsed,
+	}, nil
+}
+
+// parseKustomizePatches splits multi-document YAML into strategic merge patches. Each
+// patch must set the apiVersion, kind and name of the object that it patches, and may
+// omit the namespace.
+func parseKustomizePatches(patches string) ([]resource, error) {
+	res := make([]resource, 0)
+
+	decoder := yaml.NewDecoder(strings.NewReader(patches))
+
+	for i := 1; ; i++ {
+		patch := make(resource)
+
+		if err := decoder.Decode(&patch); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not parse patch %d: %w", i, err)
+		}
+
+		if len(patch) == 0 {
+			continue
+		}
+
+		metadata := getNestedResource(patch, "metadata")
+
+		if kind, _ := patch["kind"].(string); kind == "" {
+			return nil, fmt.Errorf("patch %d does not set a kind", i)
+		} else if apiVersion, _ := patch["apiVersion"].(string); apiVersion == "" {
+			return nil, fmt.Errorf("patch %d does not set an apiVersion", i)
+		} else if name, _ := metadata["name"].(string); name == "" {
+			return nil, fmt.Errorf("patch %d does not set metadata.name", i)
+		}
+
+		res = append(res, patch)
+	}
+
+	return res, nil
+}
+
+func (k *KustomizePostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	if strings.TrimSpace(renderedManifests.String()) == "" {
+		return renderedManifests, nil
+	}
+
+	// the manifests and patches are written to an in-memory overlay, so that the patches
+	// are applied with the same semantics as kustomize build
+	fSys := filesys.MakeFsInMemory()
+
+	resources, err := decodeRenderedManifests(bytes.NewBuffer(renderedManifests.Bytes()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fSys.WriteFile("/overlay/manifests.yaml", renderedManifests.Bytes()); err != nil {
+		return nil, err
+	}
+
+	kustomization := &kustypes.Kustomization{
+		TypeMeta: kustypes.TypeMeta{
+			APIVersion: kustypes.KustomizationVersion,
+			Kind:       kustypes.KustomizationKind,
+		},
+		Resources: []string{"manifests.yaml"},
+	}
+
+	for i, patch := range k.patches {
+		path := fmt.Sprintf("patch-%d.yaml", i+1)
+
+		data, err := yaml.Marshal(setPatchNamespace(patch, resources))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := fSys.WriteFile("/overlay/"+path, data); err != nil {
+			return nil, err
+		}
+
+		kustomization.PatchesStrategicMerge = append(
+			kustomization.PatchesStrategicMerge,
+			kustypes.PatchStrategicMerge(path),
+		)
+	}
+
+	kustomizationBytes, err := yaml.Marshal(kustomization)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fSys.WriteFile("/overlay/kustomization.yaml", kustomizationBytes); err != nil {
+		return nil, err
+	}
+
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, "/overlay")
+
+	if err != nil {
+		return nil, fmt.Errorf("could not apply kustomize patches: %w", err)
+	}
+
+	data, err := resMap.AsYaml()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(data), nil
+}
+
+// setPatchNamespace returns a patch with the namespace of the object that it patches, if
+// the patch does not set a namespace. Kustomize only matches patches without a namespace
+// to objects without a namespace, while some charts set the namespace of their objects.
+func setPatchNamespace(patch resource, resources []resource) resource {
+	metadata := getNestedResource(patch, "metadata")
+
+	if namespace, _ := metadata["namespace"].(string); namespace != "" {
+		return patch
+	}
+
+	for _, res := range resources {
+		resMetadata := getNestedResource(res, "metadata")
+
+		if res["kind"] != patch["kind"] || resMetadata["name"] != metadata["name"] {
+			continue
+		}
+
+		if namespace, _ := resMetadata["namespace"].(string); namespace != "" {
+			namespaced := make(resource)
+
+			for key, val := range patch {
+				namespaced[key] = val
+			}
+
+			nextMetadata := make(resource)
+
+			for key, val := range metadata {
+				nextMetadata[key] = val
+			}
+
+			nextMetadata["namespace"] = namespace
+			namespaced["metadata"] = nextMetadata
+
+			return namespaced
+		}
+	}
+
+	return patch
+}
@@ -0,0 +1,212 @@
+package helm_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"gopkg.in/yaml.v2"
+)
+
+type kustomizeTest struct {
+	name      string
+	patches   string
+	manifest  string
+	expected  []map[string]interface{}
+	expectErr string
+}
+
+var kustomizeTests = []kustomizeTest{
+	{
+		name: "merges patches into objects",
+		patches: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        securityContext:
+          readOnlyRootFilesystem: true
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  annotations:
+    example.com/internal: "true"
+`,
+		manifest: testDeploymentManifest,
+		expected: []map[string]interface{}{
+			{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[interface{}]interface{}{
+					"name":   "web",
+					"labels": map[interface{}]interface{}{"app": "web"},
+				},
+				"spec": map[interface{}]interface{}{
+					"template": map[interface{}]interface{}{
+						"metadata": map[interface{}]interface{}{
+							"labels": map[interface{}]interface{}{"app": "web"},
+						},
+						"spec": map[interface{}]interface{}{
+							"containers": []interface{}{
+								map[interface{}]interface{}{
+									"name":  "web",
+									"image": "nginx",
+									"securityContext": map[interface{}]interface{}{
+										"readOnlyRootFilesystem": true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata": map[interface{}]interface{}{
+					"name":        "web",
+					"annotations": map[interface{}]interface{}{"example.com/internal": "true"},
+				},
+			},
+		},
+	},
+	{
+		name: "deletes objects",
+		patches: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+$patch: delete
+`,
+		manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+data:
+  key: value
+`,
+		expected: []map[string]interface{}{
+			{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[interface{}]interface{}{"name": "web"},
+				"data":       map[interface{}]interface{}{"key": "value"},
+			},
+		},
+	},
+	{
+		name: "sets the namespace of patches",
+		patches: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: NodePort
+`,
+		manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+spec:
+  type: ClusterIP
+`,
+		expected: []map[string]interface{}{
+			{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   map[interface{}]interface{}{"name": "web", "namespace": "prod"},
+				"spec":       map[interface{}]interface{}{"type": "NodePort"},
+			},
+		},
+	},
+	{
+		name: "fails if a patch matches no object",
+		patches: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 2
+`,
+		manifest:  testDeploymentManifest,
+		expectErr: "could not apply kustomize patches",
+	},
+}
+
+func TestKustomizePostrenderer(t *testing.T) {
+	for _, test := range kustomizeTests {
+		postrenderer, err := helm.NewKustomizePostrenderer(test.patches)
+
+		if err != nil {
+			t.Fatalf("[ %s ] %v", test.name, err)
+		}
+
+		res, err := postrenderer.Run(bytes.NewBufferString(test.manifest))
+
+		if test.expectErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expectErr) {
+				t.Errorf("[ %s ] expected error containing %q, got %v", test.name, test.expectErr, err)
+			}
+
+			continue
+		} else if err != nil {
+			t.Fatalf("[ %s ] %v", test.name, err)
+		}
+
+		decoder := yaml.NewDecoder(res)
+		objs := make([]map[string]interface{}, 0)
+
+		for {
+			obj := make(map[string]interface{})
+
+			if err := decoder.Decode(&obj); err != nil {
+				break
+			}
+
+			objs = append(objs, obj)
+		}
+
+		if !reflect.DeepEqual(objs, test.expected) {
+			t.Errorf("[ %s ] incorrect manifests: expected %v, got %v", test.name, test.expected, objs)
+		}
+	}
+}
+
+func TestNewKustomizePostrenderer(t *testing.T) {
+	postrenderer, err := helm.NewKustomizePostrenderer("\n---\n")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if postrenderer != nil {
+		t.Errorf("expected no postrenderer for empty patches")
+	}
+
+	invalid := map[string]string{
+		"does not set a kind":        "apiVersion: v1\nmetadata:\n  name: web\n",
+		"does not set an apiVersion": "kind: Service\nmetadata:\n  name: web\n",
+		"does not set metadata.name": "apiVersion: v1\nkind: Service\nspec:\n  type: NodePort\n",
+		"could not parse patch 2":    "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n---\n: :\n",
+	}
+
+	for expectErr, patches := range invalid {
+		if _, err := helm.NewKustomizePostrenderer(patches); err == nil || !strings.Contains(err.Error(), expectErr) {
+			t.Errorf("expected error containing %q, got %v", expectErr, err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package helm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// Labels that are added to every resource of a release deployed by Porter, which
+// identify the project, cluster and release that own the resource
+const (
+	LabelProject = "porter.run/project"
+	LabelCluster = "porter.run/cluster"
+	LabelRelease = "porter.run/release"
+
+	// LabelVersion is the revision of the release that last applied the resource
+	LabelVersion = "porter.run/version"
+)
+
+// OwnershipLabelsPostrenderer adds the Porter ownership labels to the metadata of all
+// resources of a release. Pod templates are not labeled, since changing a pod template
+// restarts the pods of a controller, and the pod templates of Jobs cannot be changed.
+type OwnershipLabelsPostrenderer struct {
+	labels    map[string]string
+	resources []resource
+}
+
+func NewOwnershipLabelsPostrenderer(
+	cluster *models.Cluster,
+	releaseName string,
+	revision int,
+) *OwnershipLabelsPostrenderer {
+	return &OwnershipLabelsPostrenderer{
+		labels:    GetOwnershipLabels(cluster, releaseName, revision),
+		resources: make([]resource, 0),
+	}
+}
+
+// GetOwnershipLabels returns the ownership labels of a release. The version label is
+// omitted if the revision is 0.
+func GetOwnershipLabels(cluster *models.Cluster, releaseName string, revision int) map[string]string {
+	res := map[string]string{
+		LabelProject: fmt.Sprintf("%d", cluster.ProjectID),
+		LabelCluster: fmt.Sprintf("%d", cluster.ID),
+		LabelRelease: releaseName,
+	}
+
+	if revision > 0 {
+		res[LabelVersion] = fmt.Sprintf("%d", revision)
+	}
+
+	return res
+}
+
+func (o *OwnershipLabelsPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	o.resources, err = decodeRenderedManifests(renderedManifests)
+
+	if err != nil {
+		return nil, err
+	}
+
+	o.addLabels(o.resources)
+
+	modifiedManifests = bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(modifiedManifests)
+	defer encoder.Close()
+
+	for _, resource := range o.resources {
+		err = encoder.Encode(resource)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modifiedManifests, nil
+}
+
+func (o *OwnershipLabelsPostrenderer) addLabels(resources []resource) {
+	for _, res := range resources {
+		if _, ok := res["kind"].(string); !ok {
+			continue
+		}
+
+		// manifests of list type will have an items field, items should
+		// be recursively parsed
+		if itemsVal, isList := res["items"]; isList {
+			if items, ok := itemsVal.([]interface{}); ok {
+				resArr := make([]resource, 0)
+
+				for _, item := range items {
+					if arrVal, ok := item.(resource); ok {
+						resArr = append(resArr, arrVal)
+					}
+				}
+
+				o.addLabels(resArr)
+			}
+
+			continue
+		}
+
+		setLabels(res, o.labels)
+	}
+}
+
+// setLabels adds labels to the metadata of a resource, creating the metadata and labels
+// if they do not exist
+func setLabels(res resource, labels map[string]string) {
+	metadata, ok := res["metadata"].(resource)
+
+	if !ok {
+		metadata = make(resource)
+		res["metadata"] = metadata
+	}
+
+	resLabels, ok := metadata["labels"].(resource)
+
+	if !ok {
+		resLabels = make(resource)
+		metadata["labels"] = resLabels
+	}
+
+	for key, val := range labels {
+		resLabels[key] = val
+	}
+}
+
+func getPodTemplateFromResource(kind string, res resource) resource {
+	switch kind {
+	case "DaemonSet", "Deployment", "Job", "ReplicaSet", "ReplicationController", "StatefulSet":
+		return getNestedResource(res, "spec", "template")
+	case "PodTemplate":
+		return getNestedResource(res, "template")
+	case "CronJob":
+		return getNestedResource(res, "spec", "jobTemplate", "spec", "template")
+	}
+
+	return nil
+}
+
+// IsPorterManaged returns true if the manifest of a release was labeled by Porter as
+// belonging to the release in the given cluster
+func IsPorterManaged(rel *release.Release, cluster *models.Cluster) bool {
+	if rel.Manifest == "" {
+		return false
+	}
+
+	resources, err := decodeRenderedManifests(bytes.NewBufferString(rel.Manifest))
+
+	if err != nil {
+		return false
+	}
+
+	labels := GetOwnershipLabels(cluster, rel.Name, 0)
+
+	for _, res := range resources {
+		resLabels := getNestedResource(res, "metadata", "labels")
+
+		if resLabels == nil {
+			continue
+		}
+
+		matches := true
+
+		for key, val := range labels {
+			if resLabels[key] != val {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BackfillOwnershipLabels adds the ownership labels to the resources of a release that was
+// deployed before Porter labeled resources, and to the manifest of its latest revision, so
+// that features that find resources by their ownership labels see the release. It returns
+// false if the release is already labeled.
+func (a *Agent) BackfillOwnershipLabels(cluster *models.Cluster, rel *release.Release) (bool, error) {
+	if rel.Manifest == "" || IsPorterManaged(rel, cluster) {
+		return false, nil
+	}
+
+	manifest, err := NewOwnershipLabelsPostrenderer(cluster, rel.Name, rel.Version).Run(
+		bytes.NewBufferString(rel.Manifest),
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	objs, err := ParseManifests(manifest.Bytes(), rel.Namespace)
+
+	if err != nil {
+		return false, err
+	}
+
+	labels := GetOwnershipLabels(cluster, rel.Name, rel.Version)
+
+	// only the metadata of the live objects is patched, so that the backfill does not
+	// restart any pods
+	for _, obj := range objs {
+		err := a.K8sAgent.PatchObjectMetadata(obj, labels, map[string]string{})
+
+		if err != nil && !errors.Is(err, kubernetes.IsNotFoundError) {
+			return false, fmt.Errorf("could not label %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+
+	rel.Manifest = manifest.String()
+
+	if err := a.ActionConfig.Releases.Update(rel); err != nil {
+		return false, fmt.Errorf("could not update the manifest of release %s: %w", rel.Name, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,145 @@
+package helm_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/models"
+	"gopkg.in/yaml.v2"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+const testDeploymentManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+func TestOwnershipLabelsPostrenderer(t *testing.T) {
+	cluster := &models.Cluster{
+		Model:     gorm.Model{ID: 2},
+		ProjectID: 1,
+	}
+
+	postrenderer := helm.NewOwnershipLabelsPostrenderer(cluster, "web", 3)
+
+	res, err := postrenderer.Run(bytes.NewBufferString(testDeploymentManifest))
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	manifest := res.String()
+	decoder := yaml.NewDecoder(bytes.NewBufferString(manifest))
+
+	deployment := struct {
+		Metadata struct {
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Template struct {
+				Metadata struct {
+					Labels map[string]string `yaml:"labels"`
+				} `yaml:"metadata"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}{}
+
+	if err := decoder.Decode(&deployment); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expLabels := map[string]string{
+		"app":                "web",
+		"porter.run/project": "1",
+		"porter.run/cluster": "2",
+		"porter.run/release": "web",
+		"porter.run/version": "3",
+	}
+
+	if diff := deep.Equal(deployment.Metadata.Labels, expLabels); diff != nil {
+		t.Errorf("incorrect deployment labels")
+		t.Error(diff)
+	}
+
+	// pod templates are not labeled, so that labeling does not restart pods
+	if diff := deep.Equal(deployment.Spec.Template.Metadata.Labels, map[string]string{"app": "web"}); diff != nil {
+		t.Errorf("incorrect pod template labels")
+		t.Error(diff)
+	}
+
+	rel := &release.Release{
+		Name:     "web",
+		Manifest: manifest,
+	}
+
+	if !helm.IsPorterManaged(rel, cluster) {
+		t.Errorf("expected release to be managed by Porter")
+	}
+
+	otherCluster := &models.Cluster{
+		Model:     gorm.Model{ID: 3},
+		ProjectID: 1,
+	}
+
+	if helm.IsPorterManaged(rel, otherCluster) {
+		t.Errorf("expected release to not be managed by Porter in another cluster")
+	}
+
+	rel.Manifest = testDeploymentManifest
+
+	if helm.IsPorterManaged(rel, cluster) {
+		t.Errorf("expected unlabeled release to not be managed by Porter")
+	}
+}
+
+func TestBackfillOwnershipLabelsSkipsLabeledReleases(t *testing.T) {
+	cluster := &models.Cluster{
+		Model:     gorm.Model{ID: 2},
+		ProjectID: 1,
+	}
+
+	manifest, err := helm.NewOwnershipLabelsPostrenderer(cluster, "web", 1).Run(
+		bytes.NewBufferString(testDeploymentManifest),
+	)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	agent := newAgentFixture(t, "default")
+
+	backfilled, err := agent.BackfillOwnershipLabels(cluster, &release.Release{
+		Name:      "web",
+		Namespace: "default",
+		Version:   1,
+		Manifest:  manifest.String(),
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if backfilled {
+		t.Errorf("expected labeled release not to be backfilled")
+	}
+}
@@ -0,0 +1,144 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"gopkg.in/yaml.v2"
+)
+
+// longLivedConnectionsTimeout is the timeout in seconds of idle websocket and server-sent
+// event connections
+const longLivedConnectionsTimeout = "3600"
+
+const albAttributesAnnotation = "alb.ingress.kubernetes.io/load-balancer-attributes"
+
+// LongLivedConnectionAnnotations are the ingress annotations that keep websocket and
+// server-sent event connections open for each supported ingress controller. Traefik
+// neither buffers responses nor times out connections that are in use, so it needs no
+// annotations.
+var LongLivedConnectionAnnotations = map[kubernetes.IngressControllerType]map[string]string{
+	kubernetes.IngressControllerNGINX: {
+		"nginx.ingress.kubernetes.io/proxy-read-timeout":      longLivedConnectionsTimeout,
+		"nginx.ingress.kubernetes.io/proxy-send-timeout":      longLivedConnectionsTimeout,
+		"nginx.ingress.kubernetes.io/proxy-buffering":         "off",
+		"nginx.ingress.kubernetes.io/proxy-request-buffering": "off",
+		"nginx.ingress.kubernetes.io/proxy-http-version":      "1.1",
+	},
+	kubernetes.IngressControllerTraefik: {},
+	kubernetes.IngressControllerALB: {
+		albAttributesAnnotation: fmt.Sprintf("idle_timeout.timeout_seconds=%s", longLivedConnectionsTimeout),
+	},
+}
+
+// LongLivedConnectionsPostrenderer adds the annotations for long-lived connections of
+// the ingress controller that serves each ingress of a release, which is the controller
+// of the ingress class of the ingress. Annotations that are set by the values of the
+// release are kept. Ingresses whose controller is not supported or cannot be detected,
+// such as if the agent may not read ingress classes, are deployed without annotations.
+type LongLivedConnectionsPostrenderer struct {
+	agent *kubernetes.Agent
+
+	// controllerTypes caches the ingress controllers of the ingress classes of a release
+	controllerTypes map[string]kubernetes.IngressControllerType
+
+	resources []resource
+}
+
+// NewLongLivedConnectionsPostrenderer returns a postrenderer that detects the ingress
+// controllers of ingresses through the agent
+func NewLongLivedConnectionsPostrenderer(agent *kubernetes.Agent) *LongLivedConnectionsPostrenderer {
+	return &LongLivedConnectionsPostrenderer{
+		agent:           agent,
+		controllerTypes: make(map[string]kubernetes.IngressControllerType),
+		resources:       make([]resource, 0),
+	}
+}
+
+func (l *LongLivedConnectionsPostrenderer) getAnnotations(res resource) map[string]string {
+	className, _ := getNestedResource(res, "spec")["ingressClassName"].(string)
+
+	if className == "" {
+		className, _ = getNestedResource(res, "metadata", "annotations")[kubernetes.LegacyIngressClassAnnotation].(string)
+	}
+
+	controllerType, ok := l.controllerTypes[className]
+
+	if !ok {
+		var err error
+
+		controllerType, err = l.agent.GetIngressClassControllerType(className)
+
+		if err != nil {
+			controllerType = kubernetes.IngressControllerUnknown
+		}
+
+		l.controllerTypes[className] = controllerType
+	}
+
+	return LongLivedConnectionAnnotations[controllerType]
+}
+
+func (l *LongLivedConnectionsPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	l.resources, err = decodeRenderedManifests(renderedManifests)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range l.resources {
+		if kind, _ := res["kind"].(string); kind != "Ingress" {
+			continue
+		}
+
+		ingressAnnotations := l.getAnnotations(res)
+
+		if len(ingressAnnotations) == 0 {
+			continue
+		}
+
+		metadata, ok := res["metadata"].(resource)
+
+		if !ok {
+			metadata = make(resource)
+			res["metadata"] = metadata
+		}
+
+		annotations, ok := metadata["annotations"].(resource)
+
+		if !ok {
+			annotations = make(resource)
+			metadata["annotations"] = annotations
+		}
+
+		for key, val := range ingressAnnotations {
+			curr, exists := annotations[key].(string)
+
+			if !exists {
+				annotations[key] = val
+			} else if key == albAttributesAnnotation && !strings.Contains(curr, "idle_timeout.timeout_seconds") {
+				// load balancer attributes are a single annotation, so the idle timeout is
+				// added to the attributes that are already set
+				annotations[key] = curr + "," + val
+			}
+		}
+	}
+
+	modifiedManifests = bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(modifiedManifests)
+	defer encoder.Close()
+
+	for _, resource := range l.resources {
+		err = encoder.Encode(resource)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modifiedManifests, nil
+}
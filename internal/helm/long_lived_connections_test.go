@@ -0,0 +1,130 @@
+package helm_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"gopkg.in/yaml.v2"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const longLivedConnectionsTestManifest = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  ingressClassName: internal-alb
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web-public
+  annotations:
+    nginx.ingress.kubernetes.io/proxy-read-timeout: "60"
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web-gce
+  annotations:
+    kubernetes.io/ingress.class: gce
+`
+
+func getLongLivedConnectionsTestAgent() *kubernetes.Agent {
+	return kubernetes.GetAgentTesting(
+		&networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "nginx",
+				Annotations: map[string]string{
+					"ingressclass.kubernetes.io/is-default-class": "true",
+				},
+			},
+			Spec: networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+		},
+		&networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "internal-alb"},
+			Spec:       networkingv1.IngressClassSpec{Controller: "ingress.k8s.aws/alb"},
+		},
+	)
+}
+
+func runLongLivedConnectionsTestPostrenderer(t *testing.T, agent *kubernetes.Agent) map[string]map[interface{}]interface{} {
+	res, err := helm.NewLongLivedConnectionsPostrenderer(agent).Run(bytes.NewBufferString(longLivedConnectionsTestManifest))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotations := make(map[string]map[interface{}]interface{})
+	decoder := yaml.NewDecoder(res)
+
+	for {
+		obj := make(map[string]interface{})
+
+		if err := decoder.Decode(&obj); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		metadata := obj["metadata"].(map[interface{}]interface{})
+		annotations[metadata["name"].(string)], _ = metadata["annotations"].(map[interface{}]interface{})
+	}
+
+	return annotations
+}
+
+func TestLongLivedConnectionsPostrendererIngressClasses(t *testing.T) {
+	annotations := runLongLivedConnectionsTestPostrenderer(t, getLongLivedConnectionsTestAgent())
+
+	if val := annotations["web"]["alb.ingress.kubernetes.io/load-balancer-attributes"]; val != "idle_timeout.timeout_seconds=3600" {
+		t.Errorf("expected the alb annotations for the class of the ingress, got %v", annotations["web"])
+	}
+
+	if _, ok := annotations["web"]["nginx.ingress.kubernetes.io/proxy-buffering"]; ok {
+		t.Errorf("expected no nginx annotations for an ingress of the alb class")
+	}
+
+	if val := annotations["web-public"]["nginx.ingress.kubernetes.io/proxy-read-timeout"]; val != "60" {
+		t.Errorf("expected the annotations of the values to be kept, got %v", val)
+	}
+
+	if val := annotations["web-public"]["nginx.ingress.kubernetes.io/proxy-buffering"]; val != "off" {
+		t.Errorf("expected the nginx annotations for an ingress without a class, got %v", annotations["web-public"])
+	}
+
+	if len(annotations["web-gce"]) != 1 {
+		t.Errorf("expected no annotations for an ingress of an unsupported controller, got %v", annotations["web-gce"])
+	}
+}
+
+func TestLongLivedConnectionsPostrendererForbidden(t *testing.T) {
+	agent := getLongLivedConnectionsTestAgent()
+
+	agent.Clientset.(*fake.Clientset).PrependReactor(
+		"*",
+		"ingressclasses",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.NewForbidden(networkingv1.Resource("ingressclasses"), "", fmt.Errorf("namespaced role"))
+		},
+	)
+
+	annotations := runLongLivedConnectionsTestPostrenderer(t, agent)
+
+	if len(annotations["web"]) != 0 {
+		t.Errorf("expected no annotations if ingress classes cannot be read, got %v", annotations["web"])
+	}
+
+	if len(annotations["web-public"]) != 1 {
+		t.Errorf("expected only the annotations of the values if ingress classes cannot be read, got %v", annotations["web-public"])
+	}
+}
@@ -0,0 +1,376 @@
+package mirror
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+	"github.com/porter-dev/porter/internal/helm/loader"
+	chartloader "helm.sh/helm/v3/pkg/chart/loader"
+	helmrepo "k8s.io/helm/pkg/repo"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrIntegrity is returned for mirrored charts that do not match the digest in the
+// mirrored index of their repo
+var ErrIntegrity = errors.New("chart does not match its digest")
+
+// indexFile is the name of the index of each mirrored repo
+const indexFile = "index.yaml"
+
+var repoNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Repo is an upstream chart repo that is mirrored under a name, so that its charts are
+// served at /api/helm_mirror/<name>
+type Repo struct {
+	Name string
+	URL  string
+}
+
+// ParseRepo parses a repo of the form <name>=<url>, such as
+// applications=https://charts.getporter.dev
+func ParseRepo(s string) (Repo, error) {
+	i := strings.Index(s, "=")
+
+	if i == -1 {
+		return Repo{}, fmt.Errorf("mirrored repo %s must be of the form <name>=<url>", s)
+	}
+
+	name, repoURL := s[:i], s[i+1:]
+
+	if !repoNameRegex.MatchString(name) {
+		return Repo{}, fmt.Errorf("mirrored repo name %s must be lowercase alphanumeric", name)
+	}
+
+	if repoURL == "" {
+		return Repo{}, fmt.Errorf("mirrored repo url cannot be empty")
+	}
+
+	return Repo{Name: name, URL: repoURL}, nil
+}
+
+// Mirror copies the indexes and chart archives of upstream repos into a store, so that
+// the Porter server can serve the charts to air-gapped installs
+type Mirror struct {
+	Store Store
+	Repos []Repo
+}
+
+func NewMirror(store Store, repos ...Repo) *Mirror {
+	return &Mirror{
+		Store: store,
+		Repos: repos,
+	}
+}
+
+// NewFromConf returns the mirror of the repos of the server config, or nil if no repos
+// are mirrored. Charts are stored in the bucket of the config, or in the directory if no
+// bucket is set.
+func NewFromConf(sc *env.ServerConf) (*Mirror, error) {
+	if len(sc.HelmMirrorRepos) == 0 {
+		return nil, nil
+	}
+
+	repos := make([]Repo, 0)
+
+	for _, s := range sc.HelmMirrorRepos {
+		repo, err := ParseRepo(s)
+
+		if err != nil {
+			return nil, err
+		}
+
+		repos = append(repos, repo)
+	}
+
+	var store Store
+
+	if sc.HelmMirrorBucket != "" {
+		s3Store, err := NewS3Store(sc.HelmMirrorBucket, sc.HelmMirrorRegion, sc.HelmMirrorEndpoint)
+
+		if err != nil {
+			return nil, err
+		}
+
+		store = s3Store
+	} else if sc.HelmMirrorDir != "" {
+		store = &FileStore{Dir: sc.HelmMirrorDir}
+	} else {
+		return nil, fmt.Errorf("a bucket or a directory must be set for the helm mirror")
+	}
+
+	return NewMirror(store, repos...), nil
+}
+
+// GetRepo returns the mirrored repo with a name
+func (m *Mirror) GetRepo(name string) (Repo, bool) {
+	for _, repo := range m.Repos {
+		if repo.Name == name {
+			return repo, true
+		}
+	}
+
+	return Repo{}, false
+}
+
+// SyncResult is the result of syncing a mirrored repo. Failed lists the chart versions
+// that could not be mirrored, with their errors.
+type SyncResult struct {
+	Mirrored int
+	Skipped  int
+	Failed   []string
+}
+
+// Sync mirrors the chart versions of a repo that are not mirrored yet, or whose digest
+// changed upstream. Archives are checked against the digests of the upstream index
+// before they are stored, and the index of the mirror is stored last, so that it only
+// lists archives that were stored. Versions that fail keep their previously mirrored
+// archive, if any.
+func (m *Mirror) Sync(repo Repo) (*SyncResult, error) {
+	upstream, err := loader.LoadRepoIndexPublic(repo.URL)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not load index of %s: %w", repo.URL, err)
+	}
+
+	prev, err := m.loadIndex(repo.Name)
+
+	if errors.Is(err, ErrNotFound) {
+		prev = helmrepo.NewIndexFile()
+	} else if err != nil {
+		return nil, err
+	}
+
+	res := &SyncResult{
+		Failed: make([]string, 0),
+	}
+
+	index := helmrepo.NewIndexFile()
+
+	for name, versions := range upstream.Entries {
+		mirrored := make(helmrepo.ChartVersions, 0)
+
+		for _, cv := range versions {
+			prevCV := findVersion(prev, cv.Name, cv.Version)
+
+			if prevCV != nil && prevCV.Digest != "" && (cv.Digest == "" || cv.Digest == prevCV.Digest) {
+				mirrored = append(mirrored, prevCV)
+				res.Skipped++
+
+				continue
+			}
+
+			mirroredCV, err := m.mirrorVersion(repo, cv)
+
+			if err != nil {
+				res.Failed = append(res.Failed, fmt.Sprintf("%s-%s: %s", cv.Name, cv.Version, err.Error()))
+
+				if prevCV != nil {
+					mirrored = append(mirrored, prevCV)
+				}
+
+				continue
+			}
+
+			mirrored = append(mirrored, mirroredCV)
+			res.Mirrored++
+		}
+
+		if len(mirrored) > 0 {
+			index.Entries[name] = mirrored
+		}
+	}
+
+	index.SortEntries()
+
+	data, err := yaml.Marshal(index)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Store.Put(repo.Name+"/"+indexFile, data); err != nil {
+		return nil, fmt.Errorf("could not store index: %w", err)
+	}
+
+	return res, nil
+}
+
+// Verify checks every archive of a mirrored repo against the digest in the mirrored
+// index, and returns the archives that are missing or do not match
+func (m *Mirror) Verify(repo Repo) ([]string, error) {
+	index, err := m.loadIndex(repo.Name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0)
+
+	for _, versions := range index.Entries {
+		for _, cv := range versions {
+			filename := archiveName(cv)
+
+			if _, err := m.getArchive(repo.Name, cv); err != nil {
+				res = append(res, fmt.Sprintf("%s: %s", filename, err.Error()))
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// GetIndex returns the mirrored index of a repo
+func (m *Mirror) GetIndex(name string) ([]byte, error) {
+	if _, ok := m.GetRepo(name); !ok {
+		return nil, ErrNotFound
+	}
+
+	return m.Store.Get(name + "/" + indexFile)
+}
+
+// GetChart returns a mirrored chart archive of a repo by its file name, after checking
+// the archive against the digest in the mirrored index
+func (m *Mirror) GetChart(name, filename string) ([]byte, error) {
+	if _, ok := m.GetRepo(name); !ok {
+		return nil, ErrNotFound
+	}
+
+	index, err := m.loadIndex(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, versions := range index.Entries {
+		for _, cv := range versions {
+			if archiveName(cv) == filename {
+				return m.getArchive(name, cv)
+			}
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *Mirror) mirrorVersion(repo Repo, cv *helmrepo.ChartVersion) (*helmrepo.ChartVersion, error) {
+	data, err := downloadArchive(repo.URL, cv)
+
+	if err != nil {
+		return nil, err
+	}
+
+	digest := getDigest(data)
+
+	if cv.Digest != "" && cv.Digest != digest {
+		return nil, ErrIntegrity
+	}
+
+	// archives that are not valid charts are not mirrored, since they could not be
+	// installed from the mirror
+	if _, err := chartloader.LoadArchive(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("invalid chart archive: %w", err)
+	}
+
+	mirroredCV := *cv
+	mirroredCV.Digest = digest
+	mirroredCV.URLs = []string{fmt.Sprintf("%s-%s.tgz", cv.Name, cv.Version)}
+
+	if err := m.Store.Put(repo.Name+"/"+archiveName(&mirroredCV), data); err != nil {
+		return nil, err
+	}
+
+	return &mirroredCV, nil
+}
+
+func (m *Mirror) getArchive(name string, cv *helmrepo.ChartVersion) ([]byte, error) {
+	data, err := m.Store.Get(name + "/" + archiveName(cv))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if getDigest(data) != cv.Digest {
+		return nil, ErrIntegrity
+	}
+
+	return data, nil
+}
+
+func (m *Mirror) loadIndex(name string) (*helmrepo.IndexFile, error) {
+	data, err := m.Store.Get(name + "/" + indexFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	index := &helmrepo.IndexFile{}
+
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("could not parse mirrored index of %s: %w", name, err)
+	}
+
+	return index, nil
+}
+
+// archiveName returns the file name of a mirrored chart version, which is the only URL
+// of the version in the mirrored index
+func archiveName(cv *helmrepo.ChartVersion) string {
+	if len(cv.URLs) == 0 {
+		return ""
+	}
+
+	return cv.URLs[0]
+}
+
+func findVersion(index *helmrepo.IndexFile, name, version string) *helmrepo.ChartVersion {
+	for _, cv := range index.Entries[name] {
+		if cv.Version == version {
+			return cv
+		}
+	}
+
+	return nil
+}
+
+func getDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// downloadArchive downloads the archive of a chart version from its upstream repo. Like
+// Helm, relative URLs are resolved against the URL of the repo.
+func downloadArchive(repoURL string, cv *helmrepo.ChartVersion) ([]byte, error) {
+	if len(cv.URLs) == 0 {
+		return nil, fmt.Errorf("no download urls")
+	}
+
+	chartURL := cv.URLs[0]
+
+	if u, err := url.Parse(chartURL); err != nil || !u.IsAbs() {
+		chartURL = strings.TrimSuffix(strings.TrimSpace(repoURL), "/") + "/" + strings.TrimPrefix(chartURL, "/")
+	}
+
+	resp, err := http.Get(chartURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,162 @@
+package mirror_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm/mirror"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestParseRepo(t *testing.T) {
+	repo, err := mirror.ParseRepo("applications=https://charts.getporter.dev")
+
+	if err != nil {
+		t.Fatalf("error parsing repo: %v", err)
+	}
+
+	if repo.Name != "applications" || repo.URL != "https://charts.getporter.dev" {
+		t.Errorf("incorrect repo %v", repo)
+	}
+
+	for _, s := range []string{"https://charts.getporter.dev", "Apps=https://charts.getporter.dev", "apps="} {
+		if _, err := mirror.ParseRepo(s); err == nil {
+			t.Errorf("expected repo %q to be invalid", s)
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	archive := newChartArchive(t, "web", "0.2.0")
+	prevArchive := newChartArchive(t, "web", "0.1.0")
+
+	// the digest of the previous version does not match its archive, so the version
+	// must not be mirrored
+	index := fmt.Sprintf(`apiVersion: v1
+entries:
+  web:
+  - apiVersion: v2
+    name: web
+    version: 0.2.0
+    digest: %s
+    urls:
+    - charts/web-0.2.0.tgz
+  - apiVersion: v2
+    name: web
+    version: 0.1.0
+    digest: %s
+    urls:
+    - charts/web-0.1.0.tgz
+`, getDigest(archive), getDigest([]byte("other")))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			w.Write([]byte(index))
+		case "/charts/web-0.2.0.tgz":
+			w.Write(archive)
+		case "/charts/web-0.1.0.tgz":
+			w.Write(prevArchive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	store := &mirror.FileStore{Dir: t.TempDir()}
+	repo := mirror.Repo{Name: "applications", URL: server.URL}
+	helmMirror := mirror.NewMirror(store, repo)
+
+	res, err := helmMirror.Sync(repo)
+
+	if err != nil {
+		t.Fatalf("error syncing repo: %v", err)
+	}
+
+	if res.Mirrored != 1 || len(res.Failed) != 1 {
+		t.Errorf("expected 1 mirrored and 1 failed version, got %d mirrored and %v failed", res.Mirrored, res.Failed)
+	}
+
+	data, err := helmMirror.GetChart("applications", "web-0.2.0.tgz")
+
+	if err != nil {
+		t.Fatalf("error getting mirrored chart: %v", err)
+	}
+
+	if !bytes.Equal(data, archive) {
+		t.Errorf("mirrored chart does not match the upstream archive")
+	}
+
+	if _, err := helmMirror.GetChart("applications", "web-0.1.0.tgz"); !errors.Is(err, mirror.ErrNotFound) {
+		t.Errorf("expected the version with an invalid digest to not be mirrored, got %v", err)
+	}
+
+	// versions that are already mirrored are skipped
+	res, err = helmMirror.Sync(repo)
+
+	if err != nil {
+		t.Fatalf("error syncing repo: %v", err)
+	}
+
+	if res.Mirrored != 0 || res.Skipped != 1 {
+		t.Errorf("expected 1 skipped version, got %d mirrored and %d skipped", res.Mirrored, res.Skipped)
+	}
+
+	// archives that were modified in the store are not served
+	if err := store.Put("applications/web-0.2.0.tgz", []byte("modified")); err != nil {
+		t.Fatalf("error modifying archive: %v", err)
+	}
+
+	if _, err := helmMirror.GetChart("applications", "web-0.2.0.tgz"); !errors.Is(err, mirror.ErrIntegrity) {
+		t.Errorf("expected modified archive to fail the integrity check, got %v", err)
+	}
+
+	invalid, err := helmMirror.Verify(repo)
+
+	if err != nil {
+		t.Fatalf("error verifying repo: %v", err)
+	}
+
+	if len(invalid) != 1 {
+		t.Errorf("expected 1 invalid archive, got %v", invalid)
+	}
+}
+
+func newChartArchive(t *testing.T, name, version string) []byte {
+	t.Helper()
+
+	filename, err := chartutil.Save(&chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       name,
+			Version:    version,
+		},
+	}, t.TempDir())
+
+	if err != nil {
+		t.Fatalf("error saving chart: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(filename)
+
+	if err != nil {
+		t.Fatalf("error reading chart: %v", err)
+	}
+
+	return data
+}
+
+func getDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
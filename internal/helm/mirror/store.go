@@ -0,0 +1,146 @@
+package mirror
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ErrNotFound is returned by stores for objects that do not exist
+var ErrNotFound = errors.New("object not found")
+
+// Store stores the indexes and chart archives of mirrored repos by key
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, data []byte) error
+}
+
+// S3Store stores objects in an S3 bucket, or in a bucket of an S3-compatible object
+// storage if the endpoint is set
+type S3Store struct {
+	client *s3.S3
+	bucket string
+}
+
+func NewS3Store(bucket, region, endpoint string) (*S3Store, error) {
+	awsConf := &aws.Config{}
+
+	if region != "" {
+		awsConf.Region = aws.String(region)
+	}
+
+	// S3-compatible object storages are usually addressed by path instead of by the
+	// subdomain of the bucket
+	if endpoint != "" {
+		awsConf.Endpoint = aws.String(endpoint)
+		awsConf.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            *awsConf,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Store{
+		client: s3.New(sess),
+		bucket: bucket,
+	}, nil
+}
+
+func (s *S3Store) Get(key string) ([]byte, error) {
+	output, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		var awsErr awserr.Error
+
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	defer output.Body.Close()
+
+	return ioutil.ReadAll(output.Body)
+}
+
+func (s *S3Store) Put(key string, data []byte) error {
+	_, err := s.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+
+	return err
+}
+
+// FileStore stores objects in a directory, such as a volume that is shared with the
+// machine that syncs the mirror
+type FileStore struct {
+	Dir string
+}
+
+func (s *FileStore) Get(key string) ([]byte, error) {
+	path, err := s.path(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return data, err
+}
+
+func (s *FileStore) Put(key string, data []byte) error {
+	path, err := s.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// objects are written to a temporary file first, so that a partially written
+	// object is never read
+	tmpPath := path + ".tmp"
+
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func (s *FileStore) path(key string) (string, error) {
+	cleanKey := filepath.Clean("/" + key)
+
+	if cleanKey == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid key %s", key)
+	}
+
+	return filepath.Join(s.Dir, cleanKey), nil
+}
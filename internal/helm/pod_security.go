@@ -0,0 +1,82 @@
+package helm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+)
+
+// PodSecurityPostrenderer fails deploys whose rendered controllers violate the Pod
+// Security Standards level of the cluster, since their pods would otherwise be rejected
+// after the deploy succeeds. Once the manifests pass, the level is enforced on the
+// namespace of the release, so namespaces that were created outside of Porter enforce it
+// as well. The manifests are not modified.
+type PodSecurityPostrenderer struct {
+	agent       *kubernetes.Agent
+	level       types.PodSecurityLevel
+	namespace   string
+	releaseName string
+}
+
+// NewPodSecurityPostrenderer returns a postrenderer for a Pod Security Standards level,
+// or nil if the level is privileged
+func NewPodSecurityPostrenderer(
+	agent *kubernetes.Agent,
+	level types.PodSecurityLevel,
+	namespace, releaseName string,
+) *PodSecurityPostrenderer {
+	if level == types.PodSecurityLevelPrivileged {
+		return nil
+	}
+
+	return &PodSecurityPostrenderer{
+		agent:       agent,
+		level:       level,
+		namespace:   namespace,
+		releaseName: releaseName,
+	}
+}
+
+func (p *PodSecurityPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	objs := grapher.ParseObjs(grapher.ImportMultiDocYAML(renderedManifests.Bytes()), p.namespace)
+
+	violations, err := kubernetes.GetPodSecurityViolations(p.level, p.namespace, p.releaseName, objs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(violations) > 0 {
+		errs := make([]string, 0, len(violations))
+
+		for _, violation := range violations {
+			errs = append(errs, fmt.Sprintf(
+				"%s/%s (%s)",
+				violation.Kind,
+				violation.Name,
+				strings.Join(violation.Reasons, "; "),
+			))
+		}
+
+		return nil, fmt.Errorf(
+			"controllers violate the %s pod security level of the cluster: %s",
+			p.level,
+			strings.Join(errs, ", "),
+		)
+	}
+
+	err = p.agent.SetNamespacePodSecurityLevel(p.namespace, p.level)
+
+	if err != nil && !errors.Is(err, kubernetes.IsNotFoundError) {
+		return nil, fmt.Errorf("could not enforce the pod security level on namespace %s: %w", p.namespace, err)
+	}
+
+	return renderedManifests, nil
+}
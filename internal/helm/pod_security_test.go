@@ -0,0 +1,61 @@
+package helm_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const podSecurityManifests = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        securityContext:
+          privileged: true
+`
+
+func TestPodSecurityPostrenderer(t *testing.T) {
+	agent := kubernetes.GetAgentTesting(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+
+	if helm.NewPodSecurityPostrenderer(agent, types.PodSecurityLevelPrivileged, "default", "web") != nil {
+		t.Errorf("expected no postrenderer for the privileged level")
+	}
+
+	postrenderer := helm.NewPodSecurityPostrenderer(agent, types.PodSecurityLevelBaseline, "default", "web")
+
+	_, err := postrenderer.Run(bytes.NewBufferString(podSecurityManifests))
+
+	if err == nil || !strings.Contains(err.Error(), "Deployment/web") {
+		t.Fatalf("expected privileged deployment to be rejected, got %v", err)
+	}
+
+	manifests := strings.Replace(podSecurityManifests, "privileged: true", "privileged: false", 1)
+
+	if _, err := postrenderer.Run(bytes.NewBufferString(manifests)); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// the namespace of the release enforces the level once the manifests pass
+	namespace, err := agent.Clientset.CoreV1().Namespaces().Get(context.Background(), "default", metav1.GetOptions{})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if level := namespace.Labels[kubernetes.PodSecurityEnforceLabel]; level != string(types.PodSecurityLevelBaseline) {
+		t.Errorf("expected namespace to enforce the baseline level, got %q", level)
+	}
+}
@@ -0,0 +1,91 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/integrations/opa"
+)
+
+// PolicyEvaluator evaluates the Rego policies of a project against rendered objects. It
+// returns the violations of each object, in the order of the objects.
+type PolicyEvaluator interface {
+	EvaluateAll(projectID uint, policies []*opa.Policy, inputs []interface{}) ([][]*opa.Violation, error)
+}
+
+// PolicyPostrenderer evaluates the manifest policies of a project against each rendered
+// object, and fails the deploy if any policy is violated. The manifests are not modified.
+type PolicyPostrenderer struct {
+	evaluator PolicyEvaluator
+	projectID uint
+	policies  []*opa.Policy
+}
+
+// NewPolicyPostrenderer returns a postrenderer for the policies of a project, or nil if
+// the project has no policies
+func NewPolicyPostrenderer(evaluator PolicyEvaluator, projectID uint, policies []*opa.Policy) *PolicyPostrenderer {
+	if evaluator == nil || len(policies) == 0 {
+		return nil
+	}
+
+	return &PolicyPostrenderer{
+		evaluator: evaluator,
+		projectID: projectID,
+		policies:  policies,
+	}
+}
+
+func (p *PolicyPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	resources, err := decodeRenderedManifests(bytes.NewBuffer(renderedManifests.Bytes()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]resource, 0)
+	inputs := make([]interface{}, 0)
+
+	for _, res := range resources {
+		if len(res) == 0 {
+			continue
+		}
+
+		objects = append(objects, res)
+		inputs = append(inputs, toJSONCompatible(res))
+	}
+
+	// every object is evaluated with a single query, so that deploys with many objects
+	// do not send a request to the policy server for each object
+	results, err := p.evaluator.EvaluateAll(p.projectID, p.policies, inputs)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not evaluate the policies of the project: %w", err)
+	}
+
+	msgs := make([]string, 0)
+
+	for i, violations := range results {
+		if i >= len(objects) {
+			break
+		}
+
+		kind, _ := objects[i]["kind"].(string)
+		name, _ := getNestedResource(objects[i], "metadata")["name"].(string)
+
+		for _, violation := range violations {
+			msgs = append(msgs, fmt.Sprintf("%s/%s: %s (%s)", kind, name, violation.Message, violation.Policy))
+		}
+	}
+
+	if len(msgs) > 0 {
+		return nil, fmt.Errorf(
+			"the manifests of the release violate the policies of the project:\n  - %s",
+			strings.Join(msgs, "\n  - "),
+		)
+	}
+
+	return renderedManifests, nil
+}
@@ -0,0 +1,83 @@
+package helm_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/integrations/opa"
+)
+
+// fakePolicyEvaluator denies every object of a kind, with the name of the policy as
+// the message
+type fakePolicyEvaluator struct {
+	deniedKinds map[string]bool
+	queries     int
+}
+
+func (f *fakePolicyEvaluator) EvaluateAll(projectID uint, policies []*opa.Policy, inputs []interface{}) ([][]*opa.Violation, error) {
+	f.queries++
+	res := make([][]*opa.Violation, 0)
+
+	for _, input := range inputs {
+		obj := input.(map[string]interface{})
+		violations := make([]*opa.Violation, 0)
+
+		if kind, _ := obj["kind"].(string); f.deniedKinds[kind] {
+			for _, policy := range policies {
+				violations = append(violations, &opa.Violation{
+					Policy:  policy.Name,
+					Message: kind + " objects are not allowed",
+				})
+			}
+		}
+
+		res = append(res, violations)
+	}
+
+	return res, nil
+}
+
+func TestPolicyPostrenderer(t *testing.T) {
+	policies := []*opa.Policy{{ID: 1, Name: "no-services"}}
+
+	evaluator := &fakePolicyEvaluator{
+		deniedKinds: map[string]bool{},
+	}
+
+	postrenderer := helm.NewPolicyPostrenderer(evaluator, 1, policies)
+
+	res, err := postrenderer.Run(bytes.NewBufferString(testDeploymentManifest))
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if res.String() != testDeploymentManifest {
+		t.Errorf("expected manifests to be unmodified, got %s", res.String())
+	}
+
+	if evaluator.queries != 1 {
+		t.Errorf("expected every object to be evaluated with a single query, got %d queries", evaluator.queries)
+	}
+
+	evaluator.deniedKinds["Service"] = true
+
+	_, err = postrenderer.Run(bytes.NewBufferString(testDeploymentManifest))
+
+	expected := "the manifests of the release violate the policies of the project:\n  - Service/web: Service objects are not allowed (no-services)"
+
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestNewPolicyPostrenderer(t *testing.T) {
+	if helm.NewPolicyPostrenderer(&fakePolicyEvaluator{}, 1, nil) != nil {
+		t.Errorf("expected no postrenderer for a project without policies")
+	}
+
+	if helm.NewPolicyPostrenderer(nil, 1, []*opa.Policy{{ID: 1}}) != nil {
+		t.Errorf("expected no postrenderer without an evaluator")
+	}
+}
@@ -2,13 +2,18 @@ package helm
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/url"
 	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/porter-dev/porter/internal/imagemirror"
+	"github.com/porter-dev/porter/internal/integrations/opa"
 	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/envtemplate"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
 	"golang.org/x/oauth2"
@@ -19,8 +24,20 @@ import (
 )
 
 type PorterPostrenderer struct {
-	DockerSecretsPostRenderer       *DockerSecretsPostRenderer
-	EnvironmentVariablePostrenderer *EnvironmentVariablePostrenderer
+	SensitiveValuesPostrenderer      *SensitiveValuesPostrenderer
+	DockerSecretsPostRenderer        *DockerSecretsPostRenderer
+	EnvironmentVariablePostrenderer  *EnvironmentVariablePostrenderer
+	SyncedEnvGroupsPostrenderer      *SyncedEnvGroupsPostrenderer
+	EnvTemplatesPostrenderer         *EnvTemplatesPostrenderer
+	OwnershipLabelsPostrenderer      *OwnershipLabelsPostrenderer
+	EnvChecksumPostrenderer          *EnvChecksumPostrenderer
+	KEDAScalerPostrenderer           *KEDAScalerPostrenderer
+	ServiceExposurePostrenderer      *ServiceExposurePostrenderer
+	LongLivedConnectionsPostrenderer *LongLivedConnectionsPostrenderer
+	KustomizePostrenderer            *KustomizePostrenderer
+	PolicyPostrenderer               *PolicyPostrenderer
+	AllowedRegistriesPostrenderer    *AllowedRegistriesPostrenderer
+	PodSecurityPostrenderer          *PodSecurityPostrenderer
 }
 
 func NewPorterPostrenderer(
@@ -30,10 +47,22 @@ func NewPorterPostrenderer(
 	namespace string,
 	regs []*models.Registry,
 	doAuth *oauth2.Config,
+	releaseName string,
+	revision int,
+	values map[string]interface{},
+	sensitiveValues map[string]interface{},
+	customChart bool,
+	policyEvaluator PolicyEvaluator,
+	imageMirror *imagemirror.Mirror,
 ) (postrender.PostRenderer, error) {
+	var sensitiveValuesPostrenderer *SensitiveValuesPostrenderer
 	var dockerSecretsPostrenderer *DockerSecretsPostRenderer
 	var err error
 
+	if len(sensitiveValues) > 0 {
+		sensitiveValuesPostrenderer = NewSensitiveValuesPostrenderer(sensitiveValues)
+	}
+
 	if cluster != nil && agent != nil && regs != nil && len(regs) > 0 {
 		dockerSecretsPostrenderer, err = NewDockerSecretsPostRenderer(cluster, repo, agent, namespace, regs, doAuth)
 
@@ -48,15 +77,141 @@ func NewPorterPostrenderer(
 		return nil, err
 	}
 
+	var syncedEnvGroupsPostrenderer *SyncedEnvGroupsPostrenderer
+
+	if customChart {
+		syncedEnvGroupsPostrenderer, err = NewSyncedEnvGroupsPostrenderer(values)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ownershipLabelsPostrenderer *OwnershipLabelsPostrenderer
+
+	if cluster != nil {
+		ownershipLabelsPostrenderer = NewOwnershipLabelsPostrenderer(cluster, releaseName, revision)
+	}
+
+	var envTemplatesPostrenderer *EnvTemplatesPostrenderer
+	var envChecksumPostrenderer *EnvChecksumPostrenderer
+	var longLivedConnectionsPostrenderer *LongLivedConnectionsPostrenderer
+	var kustomizePostrenderer *KustomizePostrenderer
+
+	if cluster != nil && repo != nil && agent != nil {
+		envTemplatesPostrenderer = NewEnvTemplatesPostrenderer(
+			agent,
+			envtemplate.NewResolver(agent, repo, cluster, namespace),
+			namespace,
+			releaseName,
+		)
+
+		rel, err := repo.Release().ReadRelease(cluster.ID, releaseName, namespace)
+
+		if err == nil && rel.RestartOnEnvChange {
+			envChecksumPostrenderer = NewEnvChecksumPostrenderer(agent, namespace)
+		}
+
+		if err == nil && rel.KustomizePatches != "" {
+			kustomizePostrenderer, err = NewKustomizePostrenderer(rel.KustomizePatches)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err == nil && rel.LongLivedConnections {
+			longLivedConnectionsPostrenderer = NewLongLivedConnectionsPostrenderer(agent)
+		}
+	}
+
+	var policyPostrenderer *PolicyPostrenderer
+
+	if cluster != nil && repo != nil {
+		manifestPolicies, err := repo.ManifestPolicy().ListManifestPoliciesByProjectID(cluster.ProjectID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		policies := make([]*opa.Policy, 0)
+
+		for _, manifestPolicy := range manifestPolicies {
+			policies = append(policies, &opa.Policy{
+				ID:   manifestPolicy.ID,
+				Name: manifestPolicy.Name,
+				Rego: manifestPolicy.Rego,
+			})
+		}
+
+		// agents that are not created for requests, such as the agents of background jobs,
+		// have no evaluator, and must not deploy the releases of projects with policies
+		if len(policies) > 0 && policyEvaluator == nil {
+			return nil, fmt.Errorf("the policies of the project cannot be evaluated")
+		}
+
+		policyPostrenderer = NewPolicyPostrenderer(policyEvaluator, cluster.ProjectID, policies)
+	}
+
+	var allowedRegistriesPostrenderer *AllowedRegistriesPostrenderer
+
+	if cluster != nil && repo != nil {
+		project, err := repo.Project().ReadProject(cluster.ProjectID)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read the allowed registries of the project: %w", err)
+		}
+
+		allowedRegistriesPostrenderer = NewAllowedRegistriesPostrenderer(project, imageMirror)
+	}
+
+	var podSecurityPostrenderer *PodSecurityPostrenderer
+
+	if cluster != nil && agent != nil {
+		podSecurityPostrenderer = NewPodSecurityPostrenderer(agent, cluster.GetPodSecurityLevel(), namespace, releaseName)
+	}
+
+	kedaScalerPostrenderer, err := NewKEDAScalerPostrenderer(values, releaseName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	serviceExposurePostrenderer, err := NewServiceExposurePostrenderer(values)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &PorterPostrenderer{
-		DockerSecretsPostRenderer:       dockerSecretsPostrenderer,
-		EnvironmentVariablePostrenderer: envVarPostrenderer,
+		SensitiveValuesPostrenderer:      sensitiveValuesPostrenderer,
+		DockerSecretsPostRenderer:        dockerSecretsPostrenderer,
+		EnvironmentVariablePostrenderer:  envVarPostrenderer,
+		SyncedEnvGroupsPostrenderer:      syncedEnvGroupsPostrenderer,
+		EnvTemplatesPostrenderer:         envTemplatesPostrenderer,
+		OwnershipLabelsPostrenderer:      ownershipLabelsPostrenderer,
+		EnvChecksumPostrenderer:          envChecksumPostrenderer,
+		KEDAScalerPostrenderer:           kedaScalerPostrenderer,
+		ServiceExposurePostrenderer:      serviceExposurePostrenderer,
+		LongLivedConnectionsPostrenderer: longLivedConnectionsPostrenderer,
+		KustomizePostrenderer:            kustomizePostrenderer,
+		PolicyPostrenderer:               policyPostrenderer,
+		AllowedRegistriesPostrenderer:    allowedRegistriesPostrenderer,
+		PodSecurityPostrenderer:          podSecurityPostrenderer,
 	}, nil
 }
 
 func (p *PorterPostrenderer) Run(
 	renderedManifests *bytes.Buffer,
 ) (modifiedManifests *bytes.Buffer, err error) {
+	if p.SensitiveValuesPostrenderer != nil {
+		renderedManifests, err = p.SensitiveValuesPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if p.DockerSecretsPostRenderer != nil {
 		renderedManifests, err = p.DockerSecretsPostRenderer.Run(renderedManifests)
 
@@ -67,6 +222,101 @@ func (p *PorterPostrenderer) Run(
 
 	renderedManifests, err = p.EnvironmentVariablePostrenderer.Run(renderedManifests)
 
+	if err != nil {
+		return nil, err
+	}
+
+	if p.SyncedEnvGroupsPostrenderer != nil {
+		renderedManifests, err = p.SyncedEnvGroupsPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.EnvTemplatesPostrenderer != nil {
+		renderedManifests, err = p.EnvTemplatesPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.OwnershipLabelsPostrenderer != nil {
+		renderedManifests, err = p.OwnershipLabelsPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.EnvChecksumPostrenderer != nil {
+		renderedManifests, err = p.EnvChecksumPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.KEDAScalerPostrenderer != nil {
+		renderedManifests, err = p.KEDAScalerPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.ServiceExposurePostrenderer != nil {
+		renderedManifests, err = p.ServiceExposurePostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.LongLivedConnectionsPostrenderer != nil {
+		renderedManifests, err = p.LongLivedConnectionsPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the kustomize patches of the release run last, so that they can modify the objects
+	// that the other postrenderers add or change
+	if p.KustomizePostrenderer != nil {
+		renderedManifests, err = p.KustomizePostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the policies of the project are evaluated against the final manifests, so that
+	// kustomize patches cannot bypass them
+	if p.PolicyPostrenderer != nil {
+		renderedManifests, err = p.PolicyPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// images are checked in the final manifests as well, for the same reason
+	if p.AllowedRegistriesPostrenderer != nil {
+		renderedManifests, err = p.AllowedRegistriesPostrenderer.Run(renderedManifests)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the pod security level of the cluster is checked last, since it labels the
+	// namespace of the release once the manifests pass
+	if p.PodSecurityPostrenderer != nil {
+		renderedManifests, err = p.PodSecurityPostrenderer.Run(renderedManifests)
+	}
+
 	return renderedManifests, err
 }
 
@@ -810,3 +1060,62 @@ func getRegNameFromImageRef(image string) (string, error) {
 
 	return regName, nil
 }
+
+// decodeValuesKey decodes the values under a key into the target, and returns false if
+// the values do not contain the key
+func decodeValuesKey(values map[string]interface{}, key string, target interface{}) (bool, error) {
+	val, ok := values[key]
+
+	if !ok || val == nil {
+		return false, nil
+	}
+
+	// the values may be decoded from yaml, so they are converted to json-compatible maps
+	// before they are decoded into the target
+	valJSON, err := json.Marshal(toJSONCompatible(val))
+
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(valJSON, target); err != nil {
+		return false, fmt.Errorf("invalid %s values: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// toJSONCompatible converts the map[interface{}]interface{} maps of decoded yaml, including
+// decoded resources, into map[string]interface{} maps, which can be encoded as json
+func toJSONCompatible(val interface{}) interface{} {
+	switch v := val.(type) {
+	case resource:
+		return toJSONCompatible(map[interface{}]interface{}(v))
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{})
+
+		for key, elem := range v {
+			res[fmt.Sprintf("%v", key)] = toJSONCompatible(elem)
+		}
+
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{})
+
+		for key, elem := range v {
+			res[key] = toJSONCompatible(elem)
+		}
+
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+
+		for i, elem := range v {
+			res[i] = toJSONCompatible(elem)
+		}
+
+		return res
+	}
+
+	return val
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/helm/loader"
@@ -83,3 +84,53 @@ func (hr *HelmRepo) getChartBasic(
 
 	return loader.LoadChart(client, hr.RepoURL, chartName, chartVersion)
 }
+
+// LoadChartForCluster loads a chart for a release of a cluster. If the project has
+// credentials for the Helm repository of the chart, the credentials of the cluster are
+// preferred over the credentials of the project. Otherwise, the chart is loaded from
+// the repository without credentials.
+func LoadChartForCluster(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	repoURL, chartName, chartVersion string,
+) (*chart.Chart, error) {
+	hr, err := getClusterHelmRepo(repo, cluster, repoURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if hr != nil && hr.BasicAuthIntegrationID != 0 {
+		return hr.GetChart(repo, chartName, chartVersion)
+	}
+
+	return loader.LoadChartPublic(repoURL, chartName, chartVersion)
+}
+
+func getClusterHelmRepo(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	repoURL string,
+) (*HelmRepo, error) {
+	hrs, err := repo.HelmRepo().ListHelmReposByProjectID(cluster.ProjectID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var res *HelmRepo
+
+	for _, hr := range hrs {
+		if strings.TrimSuffix(hr.RepoURL, "/") != strings.TrimSuffix(repoURL, "/") {
+			continue
+		}
+
+		if hr.ClusterID == cluster.ID {
+			return (*HelmRepo)(hr), nil
+		} else if hr.ClusterID == 0 {
+			res = (*HelmRepo)(hr)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,493 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gopkg.in/yaml.v2"
+	"gorm.io/gorm"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// SensitiveValuesAnnotation is the Chart.yaml annotation that marks values paths of a
+// template as sensitive, as a comma-separated list of dot-separated paths. A path that
+// points at a map marks every value beneath it as sensitive.
+const SensitiveValuesAnnotation = "porter.run/sensitive-values"
+
+// RedactedValue replaces sensitive values that are not stored yet, such as the values
+// of an upgrade in a change request diff
+const RedactedValue = "[REDACTED]"
+
+// minRedactedManifestValueLen is the minimum length of a sensitive value that is
+// redacted from a rendered manifest, since shorter values would also redact unrelated
+// parts of the manifest
+const minRedactedManifestValueLen = 4
+
+var sensitiveValuePlaceholderRegex = regexp.MustCompile(`porter-sensitive-value\(([^()\s]+)\)`)
+
+// sensitiveKeyRegex matches the names of values that usually hold credentials, such as
+// the DATABASE_PASSWORD env variable of an application
+var sensitiveKeyRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key|access_?key|credential)`)
+
+// GetSensitiveValuePaths returns the values paths that a chart marks as sensitive
+func GetSensitiveValuePaths(ch *chart.Chart) []string {
+	res := make([]string, 0)
+
+	if ch == nil || ch.Metadata == nil {
+		return res
+	}
+
+	for _, path := range strings.Split(ch.Metadata.Annotations[SensitiveValuesAnnotation], ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			res = append(res, path)
+		}
+	}
+
+	return res
+}
+
+// GetSensitiveKeyPaths returns the paths of the values whose keys are named like
+// credentials. Charts that do not mark their sensitive values, such as the env variables
+// of applications, are redacted by these paths where values are shown to other users.
+// Keys that contain dots are skipped, since they cannot be addressed by a path.
+func GetSensitiveKeyPaths(values map[string]interface{}) []string {
+	res := make([]string, 0)
+
+	collectSensitiveKeyPaths(values, "", &res)
+
+	sort.Strings(res)
+
+	return res
+}
+
+func collectSensitiveKeyPaths(values map[string]interface{}, prefix string, res *[]string) {
+	for key, val := range values {
+		if strings.Contains(key, ".") {
+			continue
+		}
+
+		path := prefix + key
+
+		if nested, ok := val.(map[string]interface{}); ok {
+			collectSensitiveKeyPaths(nested, path+".", res)
+		} else if sensitiveKeyRegex.MatchString(key) {
+			*res = append(*res, path)
+		}
+	}
+}
+
+// GetSensitiveValuePlaceholder returns the placeholder that replaces a sensitive value
+// in the values of a release
+func GetSensitiveValuePlaceholder(path string) string {
+	return fmt.Sprintf("porter-sensitive-value(%s)", path)
+}
+
+// ExtractSensitiveValues replaces the sensitive values of a set of values with
+// placeholders, and returns the replaced values keyed by path. Values that are already
+// placeholders, such as values that were read from an existing release, keep their
+// previously stored value.
+func ExtractSensitiveValues(
+	values map[string]interface{},
+	paths []string,
+	stored map[string]interface{},
+) map[string]interface{} {
+	res := make(map[string]interface{})
+
+	replaceSensitiveValues(values, paths, func(path string, val interface{}) interface{} {
+		placeholder := GetSensitiveValuePlaceholder(path)
+
+		if val == placeholder {
+			if storedVal, ok := stored[path]; ok {
+				res[path] = storedVal
+			}
+		} else {
+			res[path] = val
+		}
+
+		return placeholder
+	})
+
+	return res
+}
+
+// RedactSensitiveValues returns a copy of a set of values in which the sensitive
+// values that are not placeholders are replaced with RedactedValue
+func RedactSensitiveValues(values map[string]interface{}, paths []string) map[string]interface{} {
+	res, _ := copyValue(values).(map[string]interface{})
+
+	replaceSensitiveValues(res, paths, func(path string, val interface{}) interface{} {
+		if val == GetSensitiveValuePlaceholder(path) {
+			return val
+		}
+
+		return RedactedValue
+	})
+
+	return res
+}
+
+// ResolveSensitiveValues returns a copy of a set of values in which placeholders are
+// replaced with their stored sensitive values. It is used to copy the values of a
+// release to a release that does not share its stored sensitive values.
+func ResolveSensitiveValues(values, sensitive map[string]interface{}) map[string]interface{} {
+	res, _ := resolvePlaceholders(copyValue(values), sensitive).(map[string]interface{})
+
+	return res
+}
+
+func resolvePlaceholders(val interface{}, sensitive map[string]interface{}) interface{} {
+	switch typed := val.(type) {
+	case map[string]interface{}:
+		for key, nestedVal := range typed {
+			typed[key] = resolvePlaceholders(nestedVal, sensitive)
+		}
+	case []interface{}:
+		for i, nestedVal := range typed {
+			typed[i] = resolvePlaceholders(nestedVal, sensitive)
+		}
+	case string:
+		if match := sensitiveValuePlaceholderRegex.FindStringSubmatch(typed); match != nil && match[0] == typed {
+			if sensitiveVal, ok := sensitive[match[1]]; ok {
+				return sensitiveVal
+			}
+		}
+	}
+
+	return val
+}
+
+// RedactManifest replaces the sensitive values of a release in its rendered manifest
+// with their placeholders. Values in secrets are redacted if they make up the complete
+// value of a secret key.
+func RedactManifest(manifest string, sensitive map[string]interface{}) string {
+	for path, val := range sensitive {
+		strVal := formatSensitiveValue(val)
+
+		if len(strVal) < minRedactedManifestValueLen {
+			continue
+		}
+
+		placeholder := GetSensitiveValuePlaceholder(path)
+
+		manifest = strings.ReplaceAll(
+			manifest,
+			base64.StdEncoding.EncodeToString([]byte(strVal)),
+			base64.StdEncoding.EncodeToString([]byte(placeholder)),
+		)
+
+		manifest = strings.ReplaceAll(manifest, strVal, placeholder)
+	}
+
+	return manifest
+}
+
+// ExtractReleaseSensitiveValues replaces the sensitive values of a release with
+// placeholders before the release is installed or upgraded, and returns the sensitive
+// values that are substituted when the release is rendered. Placeholders keep the values
+// of the latest stored revision. The values are only stored by StoreSensitiveValues once
+// the release was deployed, so that failed deploys do not change the stored values.
+func ExtractReleaseSensitiveValues(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	namespace, name string,
+	ch *chart.Chart,
+	values map[string]interface{},
+) (map[string]interface{}, error) {
+	if repo == nil || cluster == nil {
+		return nil, nil
+	}
+
+	stored, err := GetStoredSensitiveValues(repo, cluster, namespace, name)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read sensitive values: %w", err)
+	}
+
+	paths := GetSensitiveValuePaths(ch)
+
+	if len(paths) == 0 {
+		return stored, nil
+	}
+
+	return ExtractSensitiveValues(values, paths, stored), nil
+}
+
+// StoreSensitiveValues stores the sensitive values of a revision of a release after the
+// revision was deployed
+func StoreSensitiveValues(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	namespace, name string,
+	revision int,
+	sensitive map[string]interface{},
+) error {
+	if repo == nil || cluster == nil || len(sensitive) == 0 {
+		return nil
+	}
+
+	valuesBytes, err := json.Marshal(sensitive)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.SensitiveValues().UpdateSensitiveValues(&models.SensitiveValues{
+		ProjectID: cluster.ProjectID,
+		ClusterID: cluster.ID,
+		Namespace: namespace,
+		Name:      name,
+		Revision:  revision,
+		Values:    valuesBytes,
+	})
+
+	if err != nil {
+		return fmt.Errorf("could not store sensitive values: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreSensitiveValues stores the sensitive values of a revision of a release for the
+// revision that rolled the release back to it, so that later upgrades keep the values of
+// the revision that was rolled back to. Revisions without stored values, such as
+// revisions that were deployed before values were stored per revision, keep the values
+// of the latest revision.
+func RestoreSensitiveValues(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	namespace, name string,
+	fromRevision, toRevision int,
+) error {
+	sensitiveValues, err := repo.SensitiveValues().ReadSensitiveValuesRevision(cluster.ID, namespace, name, fromRevision)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not read sensitive values: %w", err)
+	}
+
+	stored, err := sensitiveValues.GetValues()
+
+	if err != nil {
+		return err
+	}
+
+	return StoreSensitiveValues(repo, cluster, namespace, name, toRevision, stored)
+}
+
+// RedactError replaces the sensitive values of a release in the message of an error of a
+// deploy, since errors of deploys are shown to users and recorded in deploy events
+func RedactError(err error, sensitive map[string]interface{}) error {
+	if err == nil || len(sensitive) == 0 {
+		return err
+	}
+
+	return errors.New(RedactManifest(err.Error(), sensitive))
+}
+
+// GetStoredSensitiveValues returns the stored sensitive values of a release, or no
+// values if the release does not have any
+func GetStoredSensitiveValues(
+	repo repository.Repository,
+	cluster *models.Cluster,
+	namespace, name string,
+) (map[string]interface{}, error) {
+	sensitiveValues, err := repo.SensitiveValues().ReadSensitiveValues(cluster.ID, namespace, name)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return map[string]interface{}{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return sensitiveValues.GetValues()
+}
+
+// replaceSensitiveValues replaces each scalar value at or beneath the sensitive paths
+// with the result of fn. Lists are not traversed.
+func replaceSensitiveValues(
+	values map[string]interface{},
+	paths []string,
+	fn func(path string, val interface{}) interface{},
+) {
+	for _, path := range paths {
+		keys := strings.Split(path, ".")
+		parent := values
+
+		for _, key := range keys[:len(keys)-1] {
+			parent, _ = parent[key].(map[string]interface{})
+
+			if parent == nil {
+				break
+			}
+		}
+
+		if parent != nil {
+			replaceSensitiveValue(parent, keys[len(keys)-1], path, fn)
+		}
+	}
+}
+
+func replaceSensitiveValue(
+	parent map[string]interface{},
+	key, path string,
+	fn func(path string, val interface{}) interface{},
+) {
+	switch val := parent[key].(type) {
+	case map[string]interface{}:
+		for nestedKey := range val {
+			replaceSensitiveValue(val, nestedKey, path+"."+nestedKey, fn)
+		}
+	case []interface{}, nil:
+	default:
+		parent[key] = fn(path, val)
+	}
+}
+
+// SensitiveValuesPostrenderer substitutes the placeholders of sensitive values in the
+// rendered manifests of a release with the stored values. Placeholders in the data of
+// secrets are substituted after the data is decoded.
+type SensitiveValuesPostrenderer struct {
+	values    map[string]interface{}
+	resources []resource
+}
+
+func NewSensitiveValuesPostrenderer(values map[string]interface{}) *SensitiveValuesPostrenderer {
+	return &SensitiveValuesPostrenderer{
+		values:    values,
+		resources: make([]resource, 0),
+	}
+}
+
+func (s *SensitiveValuesPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	s.resources, err = decodeRenderedManifests(renderedManifests)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range s.resources {
+		if kind, _ := res["kind"].(string); kind == "Secret" {
+			if err := s.substituteSecretData(res); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := s.substitute(res); err != nil {
+			return nil, err
+		}
+	}
+
+	modifiedManifests = bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(modifiedManifests)
+	defer encoder.Close()
+
+	for _, resource := range s.resources {
+		err = encoder.Encode(resource)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modifiedManifests, nil
+}
+
+func (s *SensitiveValuesPostrenderer) substituteSecretData(res resource) error {
+	data, _ := res["data"].(resource)
+
+	for key, val := range data {
+		strVal, ok := val.(string)
+
+		if !ok {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(strVal)
+
+		if err != nil || !sensitiveValuePlaceholderRegex.Match(decoded) {
+			continue
+		}
+
+		substituted, err := s.substituteString(string(decoded))
+
+		if err != nil {
+			return err
+		}
+
+		data[key] = base64.StdEncoding.EncodeToString([]byte(substituted))
+	}
+
+	return nil
+}
+
+func (s *SensitiveValuesPostrenderer) substitute(val interface{}) (interface{}, error) {
+	var err error
+
+	switch typed := val.(type) {
+	case resource:
+		for key, nestedVal := range typed {
+			if typed[key], err = s.substitute(nestedVal); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, nestedVal := range typed {
+			if typed[i], err = s.substitute(nestedVal); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		return s.substituteString(typed)
+	}
+
+	return val, nil
+}
+
+func (s *SensitiveValuesPostrenderer) substituteString(val string) (string, error) {
+	var err error
+
+	res := sensitiveValuePlaceholderRegex.ReplaceAllStringFunc(val, func(placeholder string) string {
+		path := sensitiveValuePlaceholderRegex.FindStringSubmatch(placeholder)[1]
+		sensitiveVal, ok := s.values[path]
+
+		if !ok {
+			err = fmt.Errorf("no value is stored for sensitive value %s", path)
+			return placeholder
+		}
+
+		return formatSensitiveValue(sensitiveVal)
+	})
+
+	return res, err
+}
+
+// formatSensitiveValue formats a sensitive value as it is written in a manifest. Numbers
+// are decoded from JSON as floats, so they are formatted without exponents.
+func formatSensitiveValue(val interface{}) string {
+	switch typed := val.(type) {
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(typed)
+	case int64:
+		return strconv.FormatInt(typed, 10)
+	case bool:
+		return strconv.FormatBool(typed)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
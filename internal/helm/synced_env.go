@@ -0,0 +1,159 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// SyncedEnvGroupsPostrenderer loads the env groups that are synced to a release of a
+// custom chart into the containers of the release. Porter templates read the synced env
+// groups from container.env.synced in their values, while custom charts do not, so the
+// configmap and secret of each synced env group version are added as env sources of
+// every container.
+type SyncedEnvGroupsPostrenderer struct {
+	envGroups []*syncedEnvGroup
+
+	resources []resource
+}
+
+type syncedEnvGroup struct {
+	Name    string `json:"name"`
+	Version uint   `json:"version"`
+	Keys    []struct {
+		Name   string `json:"name"`
+		Secret bool   `json:"secret"`
+	} `json:"keys"`
+}
+
+// NewSyncedEnvGroupsPostrenderer returns a postrenderer for the env groups that are
+// synced in the values of a release, or nil if no env groups are synced
+func NewSyncedEnvGroupsPostrenderer(values map[string]interface{}) (*SyncedEnvGroupsPostrenderer, error) {
+	container, _ := values["container"].(map[string]interface{})
+	env, _ := container["env"].(map[string]interface{})
+
+	envGroups := make([]*syncedEnvGroup, 0)
+
+	if ok, err := decodeValuesKey(env, "synced", &envGroups); err != nil {
+		return nil, err
+	} else if !ok || len(envGroups) == 0 {
+		return nil, nil
+	}
+
+	return &SyncedEnvGroupsPostrenderer{
+		envGroups: envGroups,
+		resources: make([]resource, 0),
+	}, nil
+}
+
+func (s *SyncedEnvGroupsPostrenderer) Run(
+	renderedManifests *bytes.Buffer,
+) (modifiedManifests *bytes.Buffer, err error) {
+	s.resources, err = decodeRenderedManifests(renderedManifests)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sources := s.getEnvSources()
+
+	for _, res := range s.resources {
+		kind, _ := res["kind"].(string)
+		podSpec := getPodSpecFromResource(kind, res)
+
+		if podSpec == nil {
+			continue
+		}
+
+		for _, key := range []string{"initContainers", "containers"} {
+			containers, _ := podSpec[key].([]interface{})
+
+			for _, containerVal := range containers {
+				if container, ok := containerVal.(resource); ok {
+					addEnvSources(container, sources)
+				}
+			}
+		}
+	}
+
+	modifiedManifests = bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(modifiedManifests)
+	defer encoder.Close()
+
+	for _, resource := range s.resources {
+		err = encoder.Encode(resource)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modifiedManifests, nil
+}
+
+// getEnvSources returns the env sources of the synced env groups. The secret of an env
+// group comes after its configmap, since the configmap stores placeholders for the
+// secret variables and later env sources take precedence.
+func (s *SyncedEnvGroupsPostrenderer) getEnvSources() []resource {
+	res := make([]resource, 0)
+
+	for _, envGroup := range s.envGroups {
+		name := fmt.Sprintf("%s.v%d", envGroup.Name, envGroup.Version)
+
+		res = append(res, resource{
+			"configMapRef": resource{
+				"name": name,
+			},
+		})
+
+		for _, key := range envGroup.Keys {
+			if key.Secret {
+				res = append(res, resource{
+					"secretRef": resource{
+						"name": name,
+					},
+				})
+
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// addEnvSources appends env sources to the envFrom of a container, unless the container
+// already loads them
+func addEnvSources(container resource, sources []resource) {
+	envFrom, _ := container["envFrom"].([]interface{})
+
+	existing := make(map[string]bool)
+
+	for _, sourceVal := range envFrom {
+		if source, ok := sourceVal.(resource); ok {
+			existing[getEnvSourceKey(source)] = true
+		}
+	}
+
+	for _, source := range sources {
+		if key := getEnvSourceKey(source); !existing[key] {
+			envFrom = append(envFrom, source)
+			existing[key] = true
+		}
+	}
+
+	container["envFrom"] = envFrom
+}
+
+func getEnvSourceKey(source resource) string {
+	if ref := getNestedResource(source, "configMapRef"); ref != nil {
+		return fmt.Sprintf("ConfigMap/%v", ref["name"])
+	}
+
+	if ref := getNestedResource(source, "secretRef"); ref != nil {
+		return fmt.Sprintf("Secret/%v", ref["name"])
+	}
+
+	return ""
+}
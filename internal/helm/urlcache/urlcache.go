@@ -1,19 +1,110 @@
 package urlcache
 
-import "github.com/porter-dev/porter/internal/helm/loader"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
 
-// ChartLookupURLs contains an in-memory store of Porter chart names matched with
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/loader"
+)
+
+// minMissRefreshInterval is the minimum time between the refreshes that are triggered
+// by lookups of charts that are not in any repo, so that lookups of charts that do not
+// exist do not reload every index
+const minMissRefreshInterval = time.Minute
+
+// catalogIdleTTL is the time after which the index of a repo that is not a default repo
+// or a template repo, and whose templates are not listed, is dropped from the cache
+const catalogIdleTTL = time.Hour
+
+// Repo is a chart repo whose index is cached, and the TTL after which the index is
+// refreshed. The default TTL of the cache is used if the TTL is 0.
+type Repo struct {
+	URL string
+	TTL time.Duration
+}
+
+// ParseRepo parses a repo of the form <url> or <url>=<ttl>, such as
+// https://charts.getporter.dev=5m
+func ParseRepo(s string) (Repo, error) {
+	if i := strings.LastIndex(s, "="); i != -1 {
+		if ttl, err := time.ParseDuration(s[i+1:]); err == nil {
+			return Repo{URL: s[:i], TTL: ttl}, nil
+		}
+	}
+
+	if s == "" {
+		return Repo{}, fmt.Errorf("repo url cannot be empty")
+	}
+
+	return Repo{URL: s}, nil
+}
+
+// Opts are the options of a ChartURLCache
+type Opts struct {
+	// DefaultTTL is the TTL of repos that do not set a TTL
+	DefaultTTL time.Duration
+
+	// StaleWhileRevalidate serves lookups from expired indexes while the indexes are
+	// refreshed in the background, so that lookups never wait on a chart repo. If it is
+	// false, expired indexes are refreshed before a lookup returns.
+	StaleWhileRevalidate bool
+}
+
+// ChartURLCache contains an in-memory store of Porter chart names matched with
 // a repo URL, so that finding a chart does not involve multiple lookups to our
-// chart repo's index.yaml file
+// chart repo's index.yaml file. It also serves as the catalog of the templates of each
+// repo, pre-parsed with their icons, descriptions and versions. The index of each repo
+// is cached once, even if the repo is a default repo and a template repo of several
+// projects.
 type ChartURLCache struct {
-	cache map[string]string
-	urls  []string
+	opts Opts
+
+	mu sync.RWMutex
+
+	// defaultRepos are visible to all projects, and are searched in order
+	defaultRepos []Repo
+
+	// projectRepos are the template repos registered by each project, which are only
+	// visible to that project
+	projectRepos map[uint][]Repo
+
+	indexes         map[string]*repoIndex
+	lastMissRefresh time.Time
+
+	// loadIndex returns the templates in the index of a repo
+	loadIndex func(repoURL string) (types.ListTemplatesResponse, error)
+}
+
+type repoIndex struct {
+	charts    map[string]bool
+	templates types.ListTemplatesResponse
+
+	// etag is a hash of the templates, which changes when the index of the repo changes
+	etag string
+
+	ttl        time.Duration
+	expiresAt  time.Time
+	lastUsed   time.Time
+	refreshing bool
+	done       chan struct{}
 }
 
-func Init(urls ...string) *ChartURLCache {
+// Init creates a cache for the given default repos, and loads their indexes
+func Init(opts Opts, repos ...Repo) *ChartURLCache {
 	res := &ChartURLCache{
-		cache: make(map[string]string),
-		urls:  urls,
+		opts:         opts,
+		defaultRepos: repos,
+		projectRepos: make(map[uint][]Repo),
+		indexes:      make(map[string]*repoIndex),
+		loadIndex:    loadTemplates,
 	}
 
 	res.Update()
@@ -21,26 +112,414 @@ func Init(urls ...string) *ChartURLCache {
 	return res
 }
 
+// Update reloads the indexes of the default repos
 func (c *ChartURLCache) Update() {
-	newCharts := make(map[string]string)
+	c.mu.RLock()
+	repos := c.defaultRepos
+	c.mu.RUnlock()
+
+	c.refreshAll(repos)
+}
+
+// SetRepos replaces the default repos and reloads their indexes
+func (c *ChartURLCache) SetRepos(repos ...Repo) {
+	c.mu.Lock()
+	c.defaultRepos = repos
+	c.mu.Unlock()
+
+	c.Update()
+}
 
-	for _, chartRepo := range c.urls {
-		indexFile, err := loader.LoadRepoIndexPublic(chartRepo)
+func (c *ChartURLCache) GetURL(chartName string) (string, bool) {
+	c.mu.RLock()
+	repos := c.defaultRepos
+	c.mu.RUnlock()
+
+	return c.lookup(repos, chartName)
+}
+
+// UpdateProject replaces the template repos of a project and reloads their indexes
+func (c *ChartURLCache) UpdateProject(projectID uint, urls ...string) {
+	repos := c.setProjectURLs(projectID, urls...)
+
+	c.refreshAll(repos)
+}
 
-		if err != nil {
-			continue
+// SetProjectURLs replaces the template repos of a project, and loads the indexes of
+// repos that are not cached yet. The indexes are loaded in the background if the cache
+// serves stale indexes.
+func (c *ChartURLCache) SetProjectURLs(projectID uint, urls ...string) {
+	repos := c.setProjectURLs(projectID, urls...)
+	missing := make([]Repo, 0)
+
+	c.mu.RLock()
+
+	for _, repo := range repos {
+		if _, ok := c.indexes[repo.URL]; !ok {
+			missing = append(missing, repo)
 		}
+	}
 
-		for chartName := range indexFile.Entries {
-			newCharts[chartName] = chartRepo
+	c.mu.RUnlock()
+
+	// lookups of the repos wait for the indexes that are loaded in the background
+	if c.opts.StaleWhileRevalidate {
+		for _, repo := range missing {
+			go c.refresh(repo)
 		}
+
+		return
 	}
 
-	c.cache = newCharts
+	c.refreshAll(missing)
 }
 
-func (c *ChartURLCache) GetURL(chartName string) (string, bool) {
-	res, ok := c.cache[chartName]
+// GetProjectURL looks up a chart in the default repos, and then in the template repos
+// of the project
+func (c *ChartURLCache) GetProjectURL(projectID uint, chartName string) (string, bool) {
+	c.mu.RLock()
+	repos := append(append([]Repo{}, c.defaultRepos...), c.projectRepos[projectID]...)
+	c.mu.RUnlock()
+
+	return c.lookup(repos, chartName)
+}
+
+// GetTemplates returns the templates of a repo and their ETag. Templates are served from
+// the cache like chart lookups, and the index of a repo that is not cached yet is loaded
+// before GetTemplates returns.
+func (c *ChartURLCache) GetTemplates(repoURL string) (types.ListTemplatesResponse, string, error) {
+	repo := Repo{URL: repoURL}
+
+	c.mu.Lock()
+
+	index, ok := c.indexes[repoURL]
+	loaded := ok && index.templates != nil
+
+	if ok {
+		index.lastUsed = time.Now()
+	}
+
+	c.mu.Unlock()
+
+	if !loaded {
+		c.refresh(repo)
+		c.waitForRefresh(repoURL)
+	} else {
+		c.revalidate(c.getExpired([]Repo{repo}))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index, ok = c.indexes[repoURL]
+
+	if !ok || index.templates == nil {
+		return nil, "", fmt.Errorf("could not load the index of repo %s", repoURL)
+	}
+
+	index.lastUsed = time.Now()
+
+	// the cached templates are copied, since callers may set fields of the templates
+	res := make(types.ListTemplatesResponse, len(index.templates))
+	copy(res, index.templates)
+
+	return res, index.etag, nil
+}
+
+// Run refreshes expired indexes in the background at the given interval until the
+// context is cancelled, and drops the indexes of repos that are no longer used
+func (c *ChartURLCache) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, repo := range c.pruneAndGetExpired() {
+				go c.refresh(repo)
+			}
+		}
+	}
+}
+
+func (c *ChartURLCache) pruneAndGetExpired() []Repo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	used := make(map[string]bool)
+
+	for _, repo := range c.defaultRepos {
+		used[repo.URL] = true
+	}
+
+	for _, repos := range c.projectRepos {
+		for _, repo := range repos {
+			used[repo.URL] = true
+		}
+	}
+
+	res := make([]Repo, 0)
+	now := time.Now()
+
+	for url, index := range c.indexes {
+		if !used[url] && now.Sub(index.lastUsed) > catalogIdleTTL {
+			delete(c.indexes, url)
+		} else if now.After(index.expiresAt) {
+			res = append(res, Repo{URL: url, TTL: index.ttl})
+		}
+	}
+
+	return res
+}
+
+func (c *ChartURLCache) setProjectURLs(projectID uint, urls ...string) []Repo {
+	repos := make([]Repo, 0, len(urls))
+
+	for _, url := range urls {
+		repos = append(repos, Repo{URL: url})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.projectRepos[projectID] = repos
+
+	return repos
+}
+
+// lookup finds the first repo that contains a chart. Expired indexes are revalidated,
+// and all indexes are reloaded before lookup returns if the chart is not found, since the
+// chart may have been added to a repo after its index was cached.
+func (c *ChartURLCache) lookup(repos []Repo, chartName string) (string, bool) {
+	c.revalidate(c.getExpired(repos))
+
+	if res, ok := c.find(repos, chartName); ok {
+		return res, ok
+	}
+
+	c.mu.Lock()
+	refreshOnMiss := time.Since(c.lastMissRefresh) > minMissRefreshInterval
+
+	if refreshOnMiss {
+		c.lastMissRefresh = time.Now()
+	}
+
+	c.mu.Unlock()
+
+	// misses are never served from stale indexes, so the first lookup of a chart that
+	// was added to a repo finds the chart
+	if refreshOnMiss {
+		c.refreshAll(repos)
+	}
+
+	for _, repo := range repos {
+		c.waitForRefresh(repo.URL)
+	}
+
+	return c.find(repos, chartName)
+}
+
+func (c *ChartURLCache) find(repos []Repo, chartName string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, repo := range repos {
+		if index, ok := c.indexes[repo.URL]; ok && index.charts[chartName] {
+			return repo.URL, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *ChartURLCache) getExpired(repos []Repo) []Repo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	res := make([]Repo, 0)
+	now := time.Now()
+
+	for _, repo := range repos {
+		if index, ok := c.indexes[repo.URL]; !ok || now.After(index.expiresAt) {
+			res = append(res, repo)
+		}
+	}
+
+	return res
+}
+
+// revalidate refreshes the indexes of repos in the background if the cache serves stale
+// indexes, and otherwise refreshes them before returning. Indexes that have not been
+// loaded yet are always loaded before returning, since there is nothing to serve.
+func (c *ChartURLCache) revalidate(repos []Repo) {
+	if len(repos) == 0 {
+		return
+	}
+
+	if !c.opts.StaleWhileRevalidate {
+		c.refreshAll(repos)
+		return
+	}
+
+	missing := make([]Repo, 0)
+
+	for _, repo := range repos {
+		if c.isLoaded(repo.URL) {
+			go c.refresh(repo)
+		} else {
+			missing = append(missing, repo)
+		}
+	}
+
+	c.refreshAll(missing)
+
+	for _, repo := range missing {
+		c.waitForRefresh(repo.URL)
+	}
+}
+
+func (c *ChartURLCache) isLoaded(repoURL string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	index, ok := c.indexes[repoURL]
+
+	return ok && index.templates != nil
+}
+
+func (c *ChartURLCache) refreshAll(repos []Repo) {
+	var wg sync.WaitGroup
+
+	for _, repo := range repos {
+		wg.Add(1)
+
+		go func(repo Repo) {
+			defer wg.Done()
+			c.refresh(repo)
+		}(repo)
+	}
+
+	wg.Wait()
+}
+
+// refresh reloads the index of a repo, unless the index is already being reloaded. If
+// the index cannot be loaded, the previous index is kept and the reload is retried
+// after a minute.
+func (c *ChartURLCache) refresh(repo Repo) {
+	c.mu.Lock()
+
+	index, ok := c.indexes[repo.URL]
+
+	if !ok {
+		index = &repoIndex{}
+		c.indexes[repo.URL] = index
+	}
+
+	if index.refreshing {
+		c.mu.Unlock()
+		return
+	}
+
+	index.refreshing = true
+	index.done = make(chan struct{})
+
+	// repos without a TTL, such as repos whose templates are listed, keep the TTL of the
+	// default or template repo with the same URL
+	if repo.TTL != 0 {
+		index.ttl = repo.TTL
+	}
+
+	ttl := index.ttl
+
+	if ttl == 0 {
+		ttl = c.opts.DefaultTTL
+	}
+
+	c.mu.Unlock()
+
+	templates, err := c.loadIndex(repo.URL)
+
+	var etag string
+
+	if err == nil {
+		etag, err = getTemplatesETag(templates)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index.refreshing = false
+	close(index.done)
+
+	if err != nil {
+		if ttl > time.Minute {
+			ttl = time.Minute
+		}
+
+		index.expiresAt = time.Now().Add(jitter(ttl))
+
+		return
+	}
+
+	index.charts = make(map[string]bool)
+
+	for _, template := range templates {
+		index.charts[template.Name] = true
+	}
+
+	index.templates = templates
+	index.etag = etag
+	index.expiresAt = time.Now().Add(jitter(ttl))
+}
+
+// waitForRefresh waits for a refresh of the index of a repo that is in progress, such as
+// a refresh that was started by a concurrent lookup
+func (c *ChartURLCache) waitForRefresh(repoURL string) {
+	c.mu.RLock()
+
+	var done chan struct{}
+
+	if index, ok := c.indexes[repoURL]; ok && index.refreshing {
+		done = index.done
+	}
+
+	c.mu.RUnlock()
+
+	if done != nil {
+		<-done
+	}
+}
+
+// jitter spreads a TTL by up to 10% in either direction, so that the indexes of repos
+// that were loaded at the same time do not expire at the same time
+func jitter(ttl time.Duration) time.Duration {
+	if spread := int64(ttl) / 5; spread > 0 {
+		return ttl - ttl/10 + time.Duration(rand.Int63n(spread))
+	}
+
+	return ttl
+}
+
+func loadTemplates(repoURL string) (types.ListTemplatesResponse, error) {
+	indexFile, err := loader.LoadRepoIndexPublic(repoURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.RepoIndexToPorterChartList(indexFile), nil
+}
+
+func getTemplatesETag(templates types.ListTemplatesResponse) (string, error) {
+	templatesBytes, err := json.Marshal(templates)
+
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(templatesBytes)
 
-	return res, ok
+	return hex.EncodeToString(hash[:16]), nil
 }
@@ -0,0 +1,250 @@
+package urlcache
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+type fakeIndexLoader struct {
+	mu     sync.Mutex
+	charts map[string]map[string]bool
+	loads  map[string]int
+}
+
+func (f *fakeIndexLoader) load(repoURL string) (types.ListTemplatesResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.loads[repoURL]++
+
+	res := make(types.ListTemplatesResponse, 0)
+
+	for chartName := range f.charts[repoURL] {
+		res = append(res, types.PorterTemplateSimple{
+			Name:     chartName,
+			Versions: []string{"0.1.0"},
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return res, nil
+}
+
+func (f *fakeIndexLoader) setCharts(repoURL string, chartNames ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.charts[repoURL] = make(map[string]bool)
+
+	for _, chartName := range chartNames {
+		f.charts[repoURL][chartName] = true
+	}
+}
+
+func newCacheFixture(t *testing.T, opts Opts, repos ...Repo) (*ChartURLCache, *fakeIndexLoader) {
+	t.Helper()
+
+	loader := &fakeIndexLoader{
+		charts: make(map[string]map[string]bool),
+		loads:  make(map[string]int),
+	}
+
+	loader.setCharts("https://charts.getporter.dev", "web", "worker")
+	loader.setCharts("https://chart-addons.getporter.dev", "postgresql", "web")
+	loader.setCharts("https://charts.example.com", "custom")
+
+	cache := &ChartURLCache{
+		opts:         opts,
+		defaultRepos: repos,
+		projectRepos: make(map[uint][]Repo),
+		indexes:      make(map[string]*repoIndex),
+		loadIndex:    loader.load,
+	}
+
+	cache.Update()
+
+	return cache, loader
+}
+
+func TestGetProjectURL(t *testing.T) {
+	cache, _ := newCacheFixture(
+		t,
+		Opts{DefaultTTL: time.Hour},
+		Repo{URL: "https://charts.getporter.dev"},
+		Repo{URL: "https://chart-addons.getporter.dev"},
+	)
+
+	// charts in several repos resolve to the first default repo
+	if url, ok := cache.GetURL("web"); !ok || url != "https://charts.getporter.dev" {
+		t.Errorf("expected web in https://charts.getporter.dev, got %q", url)
+	}
+
+	if url, ok := cache.GetURL("postgresql"); !ok || url != "https://chart-addons.getporter.dev" {
+		t.Errorf("expected postgresql in https://chart-addons.getporter.dev, got %q", url)
+	}
+
+	cache.UpdateProject(1, "https://charts.example.com")
+
+	if url, ok := cache.GetProjectURL(1, "custom"); !ok || url != "https://charts.example.com" {
+		t.Errorf("expected custom in https://charts.example.com, got %q", url)
+	}
+
+	// template repos are only visible to their project
+	if _, ok := cache.GetProjectURL(2, "custom"); ok {
+		t.Errorf("expected custom not to be visible to project 2")
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	cache, loader := newCacheFixture(
+		t,
+		Opts{DefaultTTL: time.Hour, StaleWhileRevalidate: true},
+		Repo{URL: "https://charts.getporter.dev", TTL: time.Millisecond},
+	)
+
+	loader.setCharts("https://charts.getporter.dev", "web", "job")
+
+	time.Sleep(5 * time.Millisecond)
+
+	// the expired index is served while it is refreshed in the background
+	if _, ok := cache.GetURL("web"); !ok {
+		t.Errorf("expected web to be served from the expired index")
+	}
+
+	deadline := time.Now().Add(time.Second)
+
+	for {
+		if _, ok := cache.find(cache.defaultRepos, "job"); ok {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("expected the index to be refreshed in the background")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStaleWhileRevalidateMiss(t *testing.T) {
+	cache, loader := newCacheFixture(
+		t,
+		Opts{DefaultTTL: time.Hour, StaleWhileRevalidate: true},
+		Repo{URL: "https://charts.getporter.dev"},
+	)
+
+	// the index of a new template repo is loaded before the first lookup returns
+	cache.SetProjectURLs(1, "https://charts.example.com")
+
+	if url, ok := cache.GetProjectURL(1, "custom"); !ok || url != "https://charts.example.com" {
+		t.Errorf("expected custom in https://charts.example.com, got %q", url)
+	}
+
+	// a chart that was added to a repo is found by its first lookup, since misses are
+	// not served from stale indexes
+	loader.setCharts("https://charts.getporter.dev", "web", "job")
+
+	if _, ok := cache.GetURL("job"); !ok {
+		t.Errorf("expected job to be found after the index was reloaded")
+	}
+}
+
+func TestRefreshOnMiss(t *testing.T) {
+	cache, loader := newCacheFixture(
+		t,
+		Opts{DefaultTTL: time.Hour},
+		Repo{URL: "https://charts.getporter.dev"},
+	)
+
+	loader.setCharts("https://charts.getporter.dev", "web", "job")
+
+	// a miss reloads the index, since the chart may have been added after it was cached
+	if _, ok := cache.GetURL("job"); !ok {
+		t.Errorf("expected job to be found after the index was reloaded")
+	}
+
+	// misses within the minimum interval do not reload the index again
+	cache.GetURL("missing")
+
+	if loads := loader.loads["https://charts.getporter.dev"]; loads != 2 {
+		t.Errorf("expected the index to be loaded 2 times, got %d", loads)
+	}
+}
+
+func TestGetTemplates(t *testing.T) {
+	cache, loader := newCacheFixture(
+		t,
+		Opts{DefaultTTL: time.Hour},
+		Repo{URL: "https://charts.getporter.dev"},
+	)
+
+	templates, etag, err := cache.GetTemplates("https://charts.getporter.dev")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(templates) != 2 || templates[0].Name != "web" || templates[1].Name != "worker" {
+		t.Errorf("unexpected templates %v", templates)
+	}
+
+	// templates of repos that are not cached are loaded on the first request
+	templates, otherETag, err := cache.GetTemplates("https://charts.example.com")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(templates) != 1 || templates[0].Name != "custom" {
+		t.Errorf("unexpected templates %v", templates)
+	}
+
+	if otherETag == etag {
+		t.Errorf("expected repos with different templates to have different etags")
+	}
+
+	// the etag changes when the index of the repo changes
+	loader.setCharts("https://charts.getporter.dev", "web")
+	cache.Update()
+
+	_, newETag, err := cache.GetTemplates("https://charts.getporter.dev")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if newETag == etag {
+		t.Errorf("expected the etag to change after the index changed")
+	}
+
+	if loads := loader.loads["https://charts.example.com"]; loads != 1 {
+		t.Errorf("expected the index to be loaded once, got %d", loads)
+	}
+}
+
+func TestParseRepo(t *testing.T) {
+	repo, err := ParseRepo("https://charts.example.com=5m")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if repo.URL != "https://charts.example.com" || repo.TTL != 5*time.Minute {
+		t.Errorf("unexpected repo %+v", repo)
+	}
+
+	repo, err = ParseRepo("https://charts.example.com/?token=abc")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if repo.URL != "https://charts.example.com/?token=abc" || repo.TTL != 0 {
+		t.Errorf("unexpected repo %+v", repo)
+	}
+}
@@ -0,0 +1,59 @@
+package helm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DiffValues compares two sets of Helm values, and returns a sorted list of the values
+// that were added (+), removed (-) or changed (~), keyed by their dot-separated path.
+// Lists are compared as a whole.
+func DiffValues(prev, next map[string]interface{}) []string {
+	prevFlat := make(map[string]string)
+	nextFlat := make(map[string]string)
+
+	flattenValues("", prev, prevFlat)
+	flattenValues("", next, nextFlat)
+
+	res := make([]string, 0)
+
+	for key, prevVal := range prevFlat {
+		nextVal, ok := nextFlat[key]
+
+		if !ok {
+			res = append(res, fmt.Sprintf("- %s: %s", key, prevVal))
+		} else if nextVal != prevVal {
+			res = append(res, fmt.Sprintf("~ %s: %s -> %s", key, prevVal, nextVal))
+		}
+	}
+
+	for key, nextVal := range nextFlat {
+		if _, ok := prevFlat[key]; !ok {
+			res = append(res, fmt.Sprintf("+ %s: %s", key, nextVal))
+		}
+	}
+
+	// sort by path, so that the diff is stable
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][2:] < res[j][2:]
+	})
+
+	return res
+}
+
+func flattenValues(prefix string, values map[string]interface{}, res map[string]string) {
+	for key, val := range values {
+		path := key
+
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if nested, ok := val.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenValues(path, nested, res)
+			continue
+		}
+
+		res[path] = fmt.Sprintf("%v", val)
+	}
+}
@@ -0,0 +1,48 @@
+package helm_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/internal/helm"
+)
+
+func TestDiffValues(t *testing.T) {
+	prev := map[string]interface{}{
+		"replicaCount": 1,
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.19",
+		},
+		"ingress": map[string]interface{}{
+			"enabled": true,
+		},
+	}
+
+	next := map[string]interface{}{
+		"replicaCount": 3,
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.21",
+		},
+		"resources": map[string]interface{}{
+			"memory": "256Mi",
+		},
+	}
+
+	expected := []string{
+		"~ image.tag: 1.19 -> 1.21",
+		"- ingress.enabled: true",
+		"~ replicaCount: 1 -> 3",
+		"+ resources.memory: 256Mi",
+	}
+
+	if diff := deep.Equal(expected, helm.DiffValues(prev, next)); diff != nil {
+		t.Errorf("incorrect values diff")
+		t.Error(diff)
+	}
+
+	if res := helm.DiffValues(prev, prev); len(res) != 0 {
+		t.Errorf("expected no diff for identical values, got %v", res)
+	}
+}
@@ -0,0 +1,261 @@
+package helm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// MigrateValues compares the structure of the default values of two versions of a
+// chart, and migrates the values of a release from the first version to the second.
+// Values of the release that were removed from the defaults are dropped, and values whose
+// type changed are converted when they are scalars, or dropped otherwise. Changes of
+// values that the release sets are breaking. The values of the release are not modified.
+func MigrateValues(prevDefaults, nextDefaults, values map[string]interface{}) ([]*types.ValuesSchemaChange, map[string]interface{}) {
+	changes := DiffValuesSchema(prevDefaults, nextDefaults)
+
+	migrated, _ := copyValue(values).(map[string]interface{})
+
+	if migrated == nil {
+		migrated = make(map[string]interface{})
+	}
+
+	valueTypes := make(map[string]string)
+	flattenValueTypes("", values, valueTypes)
+
+	for _, change := range changes {
+		if change.Kind == types.ValuesSchemaChangeAdded || !isPathSet(valueTypes, change.Path) {
+			continue
+		}
+
+		change.Breaking = true
+		change.Migration = types.ValuesMigrationDropped
+
+		if change.Kind == types.ValuesSchemaChangeTypeChanged {
+			val, _ := getValuesPath(migrated, change.Path)
+
+			if converted, ok := convertValue(val, change.NextType); ok {
+				setValuesPath(migrated, change.Path, converted)
+				change.Migration = types.ValuesMigrationConverted
+
+				continue
+			}
+		}
+
+		deleteValuesPath(migrated, change.Path)
+	}
+
+	return changes, migrated
+}
+
+// DiffValuesSchema returns the values that were added to, removed from, or changed type
+// between two sets of default values, sorted by path. Only the topmost path of a change
+// is returned, so the values beneath a removed map are not listed. Values that are null
+// in either set match any type.
+func DiffValuesSchema(prev, next map[string]interface{}) []*types.ValuesSchemaChange {
+	prevTypes := make(map[string]string)
+	nextTypes := make(map[string]string)
+
+	flattenValueTypes("", prev, prevTypes)
+	flattenValueTypes("", next, nextTypes)
+
+	res := make([]*types.ValuesSchemaChange, 0)
+	changed := make([]string, 0)
+
+	for _, path := range sortedKeys(prevTypes) {
+		if hasAncestor(changed, path) {
+			continue
+		}
+
+		prevType := prevTypes[path]
+		nextType, ok := nextTypes[path]
+
+		if !ok {
+			res = append(res, &types.ValuesSchemaChange{
+				Path:     path,
+				Kind:     types.ValuesSchemaChangeRemoved,
+				PrevType: prevType,
+			})
+
+			changed = append(changed, path)
+		} else if prevType != nextType && prevType != "null" && nextType != "null" {
+			res = append(res, &types.ValuesSchemaChange{
+				Path:     path,
+				Kind:     types.ValuesSchemaChangeTypeChanged,
+				PrevType: prevType,
+				NextType: nextType,
+			})
+
+			changed = append(changed, path)
+		}
+	}
+
+	for _, path := range sortedKeys(nextTypes) {
+		if _, ok := prevTypes[path]; ok || hasAncestor(changed, path) {
+			continue
+		}
+
+		res = append(res, &types.ValuesSchemaChange{
+			Path:     path,
+			Kind:     types.ValuesSchemaChangeAdded,
+			NextType: nextTypes[path],
+		})
+
+		changed = append(changed, path)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Path < res[j].Path
+	})
+
+	return res
+}
+
+// flattenValueTypes stores the type of every value by its dot-separated path, including
+// the maps that contain other values
+func flattenValueTypes(prefix string, values map[string]interface{}, res map[string]string) {
+	for key, val := range values {
+		path := key
+
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		res[path] = valueType(val)
+
+		if nested, ok := val.(map[string]interface{}); ok {
+			flattenValueTypes(path, nested, res)
+		}
+	}
+}
+
+func valueType(val interface{}) string {
+	switch val.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "map"
+	case []interface{}:
+		return "list"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return "number"
+	}
+
+	return fmt.Sprintf("%T", val)
+}
+
+// convertValue converts a scalar value to a scalar type, and returns false if the value
+// cannot be converted
+func convertValue(val interface{}, toType string) (interface{}, bool) {
+	fromType := valueType(val)
+
+	if fromType != "string" && fromType != "number" && fromType != "bool" {
+		return nil, false
+	}
+
+	str := fmt.Sprintf("%v", val)
+
+	switch toType {
+	case "string":
+		return str, true
+	case "number":
+		if i, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return i, true
+		}
+
+		if f, err := strconv.ParseFloat(str, 64); err == nil {
+			return f, true
+		}
+	case "bool":
+		if b, err := strconv.ParseBool(str); err == nil {
+			return b, true
+		}
+	}
+
+	return nil, false
+}
+
+func isPathSet(valueTypes map[string]string, path string) bool {
+	_, ok := valueTypes[path]
+
+	return ok
+}
+
+func hasAncestor(paths []string, path string) bool {
+	for _, ancestor := range paths {
+		if strings.HasPrefix(path, ancestor+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	res := make([]string, 0, len(m))
+
+	for key := range m {
+		res = append(res, key)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func getValuesPath(values map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+
+	for _, key := range keys[:len(keys)-1] {
+		nested, ok := values[key].(map[string]interface{})
+
+		if !ok {
+			return nil, false
+		}
+
+		values = nested
+	}
+
+	val, ok := values[keys[len(keys)-1]]
+
+	return val, ok
+}
+
+func setValuesPath(values map[string]interface{}, path string, val interface{}) {
+	keys := strings.Split(path, ".")
+
+	for _, key := range keys[:len(keys)-1] {
+		nested, ok := values[key].(map[string]interface{})
+
+		if !ok {
+			return
+		}
+
+		values = nested
+	}
+
+	values[keys[len(keys)-1]] = val
+}
+
+func deleteValuesPath(values map[string]interface{}, path string) {
+	keys := strings.Split(path, ".")
+
+	for _, key := range keys[:len(keys)-1] {
+		nested, ok := values[key].(map[string]interface{})
+
+		if !ok {
+			return
+		}
+
+		values = nested
+	}
+
+	delete(values, keys[len(keys)-1])
+}
@@ -0,0 +1,107 @@
+package helm_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+)
+
+func TestMigrateValues(t *testing.T) {
+	prevDefaults := map[string]interface{}{
+		"replicaCount": 1,
+		"port":         "80",
+		"ingress": map[string]interface{}{
+			"enabled": false,
+			"hosts":   []interface{}{},
+		},
+		"legacy": map[string]interface{}{
+			"enabled": false,
+		},
+		"resources": map[string]interface{}{
+			"memory": "256Mi",
+		},
+	}
+
+	nextDefaults := map[string]interface{}{
+		"replicaCount": 1,
+		"port":         80,
+		"ingress": map[string]interface{}{
+			"enabled": false,
+			"hosts":   "",
+		},
+		"resources": map[string]interface{}{
+			"memory": "256Mi",
+			"cpu":    "100m",
+		},
+		"autoscaling": map[string]interface{}{
+			"enabled": false,
+		},
+	}
+
+	values := map[string]interface{}{
+		"replicaCount": 3,
+		"port":         "8080",
+		"ingress": map[string]interface{}{
+			"enabled": true,
+			"hosts":   []interface{}{"example.com"},
+		},
+		"legacy": map[string]interface{}{
+			"enabled": true,
+		},
+	}
+
+	changes, migrated := helm.MigrateValues(prevDefaults, nextDefaults, values)
+
+	expectedChanges := []*types.ValuesSchemaChange{
+		{Path: "autoscaling", Kind: types.ValuesSchemaChangeAdded, NextType: "map"},
+		{
+			Path:      "ingress.hosts",
+			Kind:      types.ValuesSchemaChangeTypeChanged,
+			PrevType:  "list",
+			NextType:  "string",
+			Breaking:  true,
+			Migration: types.ValuesMigrationDropped,
+		},
+		{
+			Path:      "legacy",
+			Kind:      types.ValuesSchemaChangeRemoved,
+			PrevType:  "map",
+			Breaking:  true,
+			Migration: types.ValuesMigrationDropped,
+		},
+		{
+			Path:      "port",
+			Kind:      types.ValuesSchemaChangeTypeChanged,
+			PrevType:  "string",
+			NextType:  "number",
+			Breaking:  true,
+			Migration: types.ValuesMigrationConverted,
+		},
+		{Path: "resources.cpu", Kind: types.ValuesSchemaChangeAdded, NextType: "string"},
+	}
+
+	if diff := deep.Equal(expectedChanges, changes); diff != nil {
+		t.Errorf("incorrect values schema changes")
+		t.Error(diff)
+	}
+
+	expectedValues := map[string]interface{}{
+		"replicaCount": 3,
+		"port":         int64(8080),
+		"ingress": map[string]interface{}{
+			"enabled": true,
+		},
+	}
+
+	if diff := deep.Equal(expectedValues, migrated); diff != nil {
+		t.Errorf("incorrect migrated values")
+		t.Error(diff)
+	}
+
+	// the values of the release are not modified
+	if _, ok := values["legacy"]; !ok {
+		t.Errorf("expected the values of the release to not be modified")
+	}
+}
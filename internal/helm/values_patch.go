@@ -0,0 +1,139 @@
+package helm
+
+import (
+	"github.com/porter-dev/porter/api/types"
+)
+
+// patchDirectiveKey is set to patchDirectiveDelete on an item of a list in a strategic
+// patch to remove the item with the same name from the list
+const (
+	patchDirectiveKey    = "$patch"
+	patchDirectiveDelete = "delete"
+)
+
+// PatchValues applies a patch to a set of Helm values, and returns the patched values
+// without modifying the original values
+func PatchValues(
+	values, patch map[string]interface{},
+	patchType types.ValuesPatchType,
+) map[string]interface{} {
+	return patchMap(values, patch, patchType == types.ValuesPatchTypeStrategic)
+}
+
+func patchMap(values, patch map[string]interface{}, strategic bool) map[string]interface{} {
+	res := make(map[string]interface{}, len(values))
+
+	for key, val := range values {
+		res[key] = copyValue(val)
+	}
+
+	for key, patchVal := range patch {
+		if patchVal == nil {
+			delete(res, key)
+			continue
+		}
+
+		res[key] = patchValue(res[key], patchVal, strategic)
+	}
+
+	return res
+}
+
+func patchValue(val, patchVal interface{}, strategic bool) interface{} {
+	switch patchTyped := patchVal.(type) {
+	case map[string]interface{}:
+		valMap, ok := val.(map[string]interface{})
+
+		if !ok {
+			valMap = map[string]interface{}{}
+		}
+
+		return patchMap(valMap, patchTyped, strategic)
+	case []interface{}:
+		if valList, ok := val.([]interface{}); ok && strategic && isNamedList(valList) && isNamedList(patchTyped) {
+			return patchNamedList(valList, patchTyped)
+		}
+	}
+
+	return copyValue(patchVal)
+}
+
+// patchNamedList merges the items of a patch into a list of maps by their name. Items
+// that are not in the list are appended.
+func patchNamedList(values, patch []interface{}) []interface{} {
+	res := make([]interface{}, 0, len(values))
+
+	for _, val := range values {
+		res = append(res, copyValue(val))
+	}
+
+	for _, patchVal := range patch {
+		patchItem := patchVal.(map[string]interface{})
+		name := patchItem["name"]
+
+		index := -1
+
+		for i, val := range res {
+			if val.(map[string]interface{})["name"] == name {
+				index = i
+				break
+			}
+		}
+
+		if patchItem[patchDirectiveKey] == patchDirectiveDelete {
+			if index >= 0 {
+				res = append(res[:index], res[index+1:]...)
+			}
+
+			continue
+		}
+
+		if index >= 0 {
+			res[index] = patchMap(res[index].(map[string]interface{}), patchItem, true)
+		} else {
+			res = append(res, patchMap(map[string]interface{}{}, patchItem, true))
+		}
+	}
+
+	return res
+}
+
+// isNamedList returns true if all items of a list are maps with a string name
+func isNamedList(list []interface{}) bool {
+	for _, item := range list {
+		itemMap, ok := item.(map[string]interface{})
+
+		if !ok {
+			return false
+		}
+
+		if _, ok := itemMap["name"].(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func copyValue(val interface{}) interface{} {
+	switch typed := val.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(typed))
+
+		for key, item := range typed {
+			res[key] = copyValue(item)
+		}
+
+		return res
+	case []interface{}:
+		res := make([]interface{}, 0, len(typed))
+
+		for _, item := range typed {
+			res = append(res, copyValue(item))
+		}
+
+		return res
+	}
+
+	return val
+}
@@ -0,0 +1,88 @@
+package helm_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+)
+
+func TestPatchValuesMerge(t *testing.T) {
+	values := map[string]interface{}{
+		"replicaCount": 1,
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.19",
+		},
+		"ingress": map[string]interface{}{
+			"enabled": true,
+		},
+		"env": []interface{}{
+			map[string]interface{}{"name": "A", "value": "1"},
+		},
+	}
+
+	patch := map[string]interface{}{
+		"image": map[string]interface{}{
+			"tag": "1.21",
+		},
+		"ingress": nil,
+		"env": []interface{}{
+			map[string]interface{}{"name": "B", "value": "2"},
+		},
+	}
+
+	expected := map[string]interface{}{
+		"replicaCount": 1,
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.21",
+		},
+		"env": []interface{}{
+			map[string]interface{}{"name": "B", "value": "2"},
+		},
+	}
+
+	if diff := deep.Equal(expected, helm.PatchValues(values, patch, types.ValuesPatchTypeMerge)); diff != nil {
+		t.Errorf("unexpected patched values: %v", diff)
+	}
+
+	// the original values should not be modified
+	if tag := values["image"].(map[string]interface{})["tag"]; tag != "1.19" {
+		t.Errorf("original values were modified: expected tag 1.19, got %v", tag)
+	}
+}
+
+func TestPatchValuesStrategic(t *testing.T) {
+	values := map[string]interface{}{
+		"env": []interface{}{
+			map[string]interface{}{"name": "A", "value": "1"},
+			map[string]interface{}{"name": "B", "value": "2"},
+			map[string]interface{}{"name": "C", "value": "3"},
+		},
+		"args": []interface{}{"--verbose"},
+	}
+
+	patch := map[string]interface{}{
+		"env": []interface{}{
+			map[string]interface{}{"name": "B", "value": "20"},
+			map[string]interface{}{"name": "C", "$patch": "delete"},
+			map[string]interface{}{"name": "D", "value": "4"},
+		},
+		"args": []interface{}{"--quiet"},
+	}
+
+	expected := map[string]interface{}{
+		"env": []interface{}{
+			map[string]interface{}{"name": "A", "value": "1"},
+			map[string]interface{}{"name": "B", "value": "20"},
+			map[string]interface{}{"name": "D", "value": "4"},
+		},
+		"args": []interface{}{"--quiet"},
+	}
+
+	if diff := deep.Equal(expected, helm.PatchValues(values, patch, types.ValuesPatchTypeStrategic)); diff != nil {
+		t.Errorf("unexpected patched values: %v", diff)
+	}
+}
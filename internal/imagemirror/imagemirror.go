@@ -0,0 +1,390 @@
+package imagemirror
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+)
+
+// The images that the create flows deploy before an application is built, the images
+// that Porter installs in clusters, and the default buildpack builders
+const (
+	DefaultAppImage = "public.ecr.aws/o1j4x7p4/hello-porter"
+	DefaultJobImage = "public.ecr.aws/o1j4x7p4/hello-porter-job"
+	AgentImage      = "public.ecr.aws/o1j4x7p4/porter-agent"
+
+	DefaultHerokuBuilder = "heroku/buildpacks:20"
+	DefaultPaketoBuilder = "paketobuildpacks/builder:full"
+)
+
+// legacy names of the default images, which releases created by older versions may use
+var legacyImages = map[string]string{
+	"porterdev/hello-porter":     DefaultAppImage,
+	"porterdev/hello-porter-job": DefaultJobImage,
+}
+
+// Mirror is a private registry that mirrors the public images that Porter uses, for
+// installations without access to public registries. Images are mirrored under the
+// same repository path, so public.ecr.aws/o1j4x7p4/hello-porter is mirrored as
+// <registry>/o1j4x7p4/hello-porter, and images of Docker Hub such as nginx are mirrored
+// as <registry>/library/nginx.
+type Mirror struct {
+	// Registry is the host of the mirror, with an optional path prefix such as
+	// registry.example.com/porter
+	Registry string
+
+	// Strict rejects releases that reference images outside of the mirror
+	Strict bool
+}
+
+func NewMirror(registry string, strict bool) *Mirror {
+	return &Mirror{
+		Registry: strings.TrimSuffix(registry, "/"),
+		Strict:   strict,
+	}
+}
+
+// NewFromConf returns the image mirror of the server, or nil if no mirror is set
+func NewFromConf(sc *env.ServerConf) *Mirror {
+	if sc.ImageMirrorRegistry == "" {
+		return nil
+	}
+
+	return NewMirror(sc.ImageMirrorRegistry, sc.ImageMirrorStrict)
+}
+
+// Image returns the mirrored reference of an image, with the tag or digest of the image.
+// Images that are already in the mirror are returned as they are, and a nil mirror
+// returns every image as it is.
+func (m *Mirror) Image(image string) string {
+	if m == nil || image == "" || m.Resolves(image) {
+		return image
+	}
+
+	_, path := splitRegistry(image)
+
+	return m.Registry + "/" + path
+}
+
+// Resolves returns true if an image is pulled from the mirror. A nil mirror resolves
+// every image.
+func (m *Mirror) Resolves(image string) bool {
+	if m == nil {
+		return true
+	}
+
+	return strings.HasPrefix(image, m.Registry+"/")
+}
+
+// IsDefaultAppImage returns true if a repository is the default application image, by
+// its public, legacy or mirrored name
+func (m *Mirror) IsDefaultAppImage(repository string) bool {
+	return m.isImage(repository, DefaultAppImage)
+}
+
+// IsDefaultJobImage returns true if a repository is the default job image, by its public,
+// legacy or mirrored name
+func (m *Mirror) IsDefaultJobImage(repository string) bool {
+	return m.isImage(repository, DefaultJobImage)
+}
+
+func (m *Mirror) isImage(repository, image string) bool {
+	if legacy, ok := legacyImages[repository]; ok {
+		repository = legacy
+	}
+
+	return repository == image || (m != nil && repository == m.Image(image))
+}
+
+// RewriteDefaultImages replaces the default images that the values of a release reference
+// with their mirrored images. Other images are not modified, since they are chosen by the
+// user.
+func (m *Mirror) RewriteDefaultImages(values map[string]interface{}) {
+	if m == nil {
+		return
+	}
+
+	walkImages(nil, values, func(path []string, parent map[string]interface{}, key, image string) {
+		if mirrored, ok := m.defaultImage(image); ok {
+			parent[key] = mirrored
+		}
+	})
+}
+
+// DefaultImageOverrides returns values that replace the default images that the default
+// values of a chart reference with their mirrored images, so that releases that do not
+// set an image pull the mirrored default image. Images in lists are not replaced.
+func (m *Mirror) DefaultImageOverrides(defaults map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+
+	if m == nil {
+		return res
+	}
+
+	walkImages(nil, defaults, func(path []string, parent map[string]interface{}, key, image string) {
+		mirrored, ok := m.defaultImage(image)
+
+		if !ok {
+			return
+		}
+
+		curr := res
+
+		for _, pathKey := range path[:len(path)-1] {
+			if strings.HasSuffix(pathKey, "]") {
+				return
+			}
+
+			nested, ok := curr[pathKey].(map[string]interface{})
+
+			if !ok {
+				nested = make(map[string]interface{})
+				curr[pathKey] = nested
+			}
+
+			curr = nested
+		}
+
+		curr[path[len(path)-1]] = mirrored
+	})
+
+	return res
+}
+
+// defaultImage returns the mirrored image of a default image, with the tag of the image
+func (m *Mirror) defaultImage(image string) (string, bool) {
+	repository, tag := splitTag(image)
+
+	if !m.IsDefaultAppImage(repository) && !m.IsDefaultJobImage(repository) {
+		return "", false
+	}
+
+	if legacy, ok := legacyImages[repository]; ok {
+		repository = legacy
+	}
+
+	return m.Image(repository) + tag, true
+}
+
+// IsDefaultImage returns true if an image is one of the default images, with or without
+// a tag
+func (m *Mirror) IsDefaultImage(image string) bool {
+	_, ok := m.defaultImage(image)
+
+	return ok
+}
+
+// UnresolvedImages returns the images of a release that are not pulled from the mirror,
+// as "<path>: <image>" sorted by path. A nil mirror resolves every image.
+func (m *Mirror) UnresolvedImages(defaults, values map[string]interface{}) []string {
+	res := make([]string, 0)
+
+	if m == nil {
+		return res
+	}
+
+	for path, image := range ReleaseImages(defaults, values) {
+		if !m.Resolves(image) {
+			res = append(res, fmt.Sprintf("%s: %s", path, image))
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// ReleaseImages returns the images of a release by their dot-separated path. The images
+// of a release are the images of its values, and the images of the default values of its
+// chart that its values do not override.
+func ReleaseImages(defaults, values map[string]interface{}) map[string]string {
+	images := make(map[string]string)
+
+	walkImages(nil, defaults, func(path []string, parent map[string]interface{}, key, image string) {
+		images[strings.Join(path, ".")] = image
+	})
+
+	walkImages(nil, values, func(path []string, parent map[string]interface{}, key, image string) {
+		images[strings.Join(path, ".")] = image
+	})
+
+	return images
+}
+
+// ReleaseImageRefs returns the images of the merged default values and values of a
+// release by their dot-separated path, like ReleaseImages. Images of maps named image
+// include the tag of the map, such as the image.tag value of the application charts, so
+// that the images can be resolved to the digests that are deployed.
+func ReleaseImageRefs(merged map[string]interface{}) map[string]string {
+	res := make(map[string]string)
+
+	walkImages(nil, merged, func(path []string, parent map[string]interface{}, key, image string) {
+		if key == "repository" {
+			if tag := getTag(parent); tag != "" {
+				image = image + ":" + tag
+			}
+		}
+
+		res[strings.Join(path, ".")] = image
+	})
+
+	return res
+}
+
+// PinDigests pins the images of a release to their digests, which are keyed by the paths
+// of ReleaseImageRefs. The images are pinned in the merged default values and values of
+// the release, and the pinned images are then set in the values, so that the values pin
+// images of the default values as well. Images of maps named image are pinned through
+// their tag as <tag>@<digest>, which charts render as <repository>:<tag>@<digest>.
+func PinDigests(merged, values map[string]interface{}, digests map[string]string) {
+	walkImages(nil, merged, func(path []string, parent map[string]interface{}, key, image string) {
+		digest, ok := digests[strings.Join(path, ".")]
+
+		if !ok {
+			return
+		}
+
+		if key == "repository" {
+			tag := getTag(parent)
+
+			if tag == "" {
+				tag = "latest"
+			}
+
+			parent["tag"] = stripDigest(tag) + "@" + digest
+
+			setMergedValue(values, merged, appendKey(path[:len(path)-1], "tag"))
+
+			return
+		}
+
+		parent[key] = stripDigest(image) + "@" + digest
+
+		setMergedValue(values, merged, path)
+	})
+}
+
+// setMergedValue sets a value of the merged values in the values at the same path. Lists
+// are replaced as a whole when values are merged, so values in lists are set by setting
+// the list of the merged values.
+func setMergedValue(values, merged map[string]interface{}, path []string) {
+	for i, key := range path {
+		if j := strings.LastIndex(key, "["); j > 0 && strings.HasSuffix(key, "]") {
+			values[key[:j]] = merged[key[:j]]
+			return
+		}
+
+		if i == len(path)-1 {
+			values[key] = merged[key]
+			return
+		}
+
+		nextMerged, _ := merged[key].(map[string]interface{})
+		nextValues, ok := values[key].(map[string]interface{})
+
+		if !ok {
+			nextValues = make(map[string]interface{})
+			values[key] = nextValues
+		}
+
+		values, merged = nextValues, nextMerged
+	}
+}
+
+// getTag returns the tag of a map named image, which may be parsed from yaml as a number
+func getTag(image map[string]interface{}) string {
+	switch tag := image["tag"].(type) {
+	case string:
+		return tag
+	case int:
+		return strconv.Itoa(tag)
+	case int64:
+		return strconv.FormatInt(tag, 10)
+	case float64:
+		return strconv.FormatFloat(tag, 'f', -1, 64)
+	}
+
+	return ""
+}
+
+// stripDigest removes the digest from an image or tag that is already pinned
+func stripDigest(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i]
+	}
+
+	return image
+}
+
+// walkImages calls fn for every image in a set of values, with the keys of the image.
+// Images are the string values of keys named image, and the repository of maps named
+// image, such as the image.repository value of the application charts. The keys of the
+// items of lists are suffixed with their index.
+func walkImages(
+	prefix []string,
+	values map[string]interface{},
+	fn func(path []string, parent map[string]interface{}, key, image string),
+) {
+	for key, val := range values {
+		path := appendKey(prefix, key)
+
+		switch typed := val.(type) {
+		case string:
+			if key == "image" && typed != "" {
+				fn(path, values, key, typed)
+			}
+		case map[string]interface{}:
+			if repository, ok := typed["repository"].(string); key == "image" && ok && repository != "" {
+				fn(appendKey(path, "repository"), typed, "repository", repository)
+			}
+
+			walkImages(path, typed, fn)
+		case []interface{}:
+			for i, item := range typed {
+				if nested, ok := item.(map[string]interface{}); ok {
+					walkImages(appendKey(prefix, fmt.Sprintf("%s[%d]", key, i)), nested, fn)
+				}
+			}
+		}
+	}
+}
+
+func appendKey(path []string, key string) []string {
+	res := make([]string, len(path), len(path)+1)
+	copy(res, path)
+
+	return append(res, key)
+}
+
+// splitRegistry splits an image into the host of its registry and its path. Images
+// without a host are Docker Hub images, whose official images are under library/.
+func splitRegistry(image string) (string, string) {
+	parts := strings.SplitN(image, "/", 2)
+
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0], parts[1]
+	}
+
+	if len(parts) == 1 {
+		return "docker.io", "library/" + image
+	}
+
+	return "docker.io", image
+}
+
+// splitTag splits an image into its repository and its tag or digest, which keeps the
+// separator
+func splitTag(image string) (string, string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i], image[i:]
+	}
+
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i:]
+	}
+
+	return image, ""
+}
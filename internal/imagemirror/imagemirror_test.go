@@ -0,0 +1,207 @@
+package imagemirror_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/imagemirror"
+)
+
+func TestImage(t *testing.T) {
+	mirror := imagemirror.NewMirror("registry.example.com/porter/", false)
+
+	tests := map[string]string{
+		"public.ecr.aws/o1j4x7p4/hello-porter:latest": "registry.example.com/porter/o1j4x7p4/hello-porter:latest",
+		"paketobuildpacks/builder:full":               "registry.example.com/porter/paketobuildpacks/builder:full",
+		"nginx":                                       "registry.example.com/porter/library/nginx",
+		"localhost:5000/app@sha256:abc":               "registry.example.com/porter/app@sha256:abc",
+		"registry.example.com/porter/app:1.0":         "registry.example.com/porter/app:1.0",
+	}
+
+	for image, expected := range tests {
+		if res := mirror.Image(image); res != expected {
+			t.Errorf("expected %s to be mirrored as %s, got %s", image, expected, res)
+		}
+	}
+
+	var nilMirror *imagemirror.Mirror
+
+	if res := nilMirror.Image("nginx"); res != "nginx" {
+		t.Errorf("expected a nil mirror to not modify images, got %s", res)
+	}
+}
+
+func TestIsDefaultImage(t *testing.T) {
+	mirror := imagemirror.NewMirror("registry.example.com", false)
+
+	for _, repository := range []string{
+		imagemirror.DefaultAppImage,
+		"porterdev/hello-porter",
+		"registry.example.com/o1j4x7p4/hello-porter",
+	} {
+		if !mirror.IsDefaultAppImage(repository) {
+			t.Errorf("expected %s to be the default application image", repository)
+		}
+	}
+
+	if mirror.IsDefaultAppImage(imagemirror.DefaultJobImage) {
+		t.Errorf("expected the default job image to not be the default application image")
+	}
+}
+
+func TestRewriteDefaultImages(t *testing.T) {
+	mirror := imagemirror.NewMirror("registry.example.com", true)
+
+	values := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "porterdev/hello-porter-job",
+			"tag":        "latest",
+		},
+		"sidecar": map[string]interface{}{
+			"image": "public.ecr.aws/o1j4x7p4/hello-porter:latest",
+		},
+		"worker": map[string]interface{}{
+			"image": "redis:6",
+		},
+	}
+
+	mirror.RewriteDefaultImages(values)
+
+	expected := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "registry.example.com/o1j4x7p4/hello-porter-job",
+			"tag":        "latest",
+		},
+		"sidecar": map[string]interface{}{
+			"image": "registry.example.com/o1j4x7p4/hello-porter:latest",
+		},
+		"worker": map[string]interface{}{
+			"image": "redis:6",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, values) {
+		t.Errorf("incorrect rewritten values: expected %v, got %v", expected, values)
+	}
+
+	defaults := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": imagemirror.DefaultAppImage,
+		},
+		"worker": map[string]interface{}{
+			"image": "redis:latest",
+		},
+		"metrics": map[string]interface{}{
+			"image": "prom/statsd-exporter",
+		},
+	}
+
+	unresolved := mirror.UnresolvedImages(defaults, values)
+
+	if !reflect.DeepEqual([]string{"metrics.image: prom/statsd-exporter", "worker.image: redis:6"}, unresolved) {
+		t.Errorf("incorrect unresolved images: %v", unresolved)
+	}
+}
+
+func TestDefaultImageOverrides(t *testing.T) {
+	mirror := imagemirror.NewMirror("registry.example.com", false)
+
+	defaults := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": imagemirror.DefaultAppImage,
+			"tag":        "latest",
+		},
+		"sidecars": []interface{}{
+			map[string]interface{}{
+				"image": imagemirror.DefaultAppImage,
+			},
+		},
+		"redis": map[string]interface{}{
+			"image": "redis:6",
+		},
+	}
+
+	expected := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "registry.example.com/o1j4x7p4/hello-porter",
+		},
+	}
+
+	if res := mirror.DefaultImageOverrides(defaults); !reflect.DeepEqual(expected, res) {
+		t.Errorf("incorrect default image overrides: expected %v, got %v", expected, res)
+	}
+
+	if repository := defaults["image"].(map[string]interface{})["repository"]; repository != imagemirror.DefaultAppImage {
+		t.Errorf("expected the default values to not be modified, got %v", repository)
+	}
+}
+
+func TestPinDigests(t *testing.T) {
+	defaults := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "registry.example.com/app",
+			"tag":        "v1",
+		},
+		"sidecars": []interface{}{
+			map[string]interface{}{
+				"image": "registry.example.com/proxy:1.0",
+			},
+		},
+	}
+
+	values := map[string]interface{}{
+		"image": map[string]interface{}{
+			"tag": "v2",
+		},
+		"worker": map[string]interface{}{
+			"image": "registry.example.com/worker:v2@sha256:old",
+		},
+	}
+
+	merged := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "registry.example.com/app",
+			"tag":        "v2",
+		},
+		"sidecars": defaults["sidecars"],
+		"worker": map[string]interface{}{
+			"image": "registry.example.com/worker:v2@sha256:old",
+		},
+	}
+
+	refs := imagemirror.ReleaseImageRefs(merged)
+
+	expectedRefs := map[string]string{
+		"image.repository":  "registry.example.com/app:v2",
+		"sidecars[0].image": "registry.example.com/proxy:1.0",
+		"worker.image":      "registry.example.com/worker:v2@sha256:old",
+	}
+
+	if !reflect.DeepEqual(refs, expectedRefs) {
+		t.Fatalf("expected refs %v, got %v", expectedRefs, refs)
+	}
+
+	imagemirror.PinDigests(merged, values, map[string]string{
+		"image.repository":  "sha256:app",
+		"sidecars[0].image": "sha256:proxy",
+		"worker.image":      "sha256:worker",
+	})
+
+	expected := map[string]interface{}{
+		"image": map[string]interface{}{
+			"tag": "v2@sha256:app",
+		},
+		"sidecars": []interface{}{
+			map[string]interface{}{
+				"image": "registry.example.com/proxy:1.0@sha256:proxy",
+			},
+		},
+		"worker": map[string]interface{}{
+			"image": "registry.example.com/worker:v2@sha256:worker",
+		},
+	}
+
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected pinned values %v, got %v", expected, values)
+	}
+}
@@ -0,0 +1,128 @@
+package buildpacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type bunRuntime struct{}
+
+func NewBunRuntime() Runtime {
+	return &bunRuntime{}
+}
+
+// bunEntrypoints are the files that bun apps are conventionally started from, in order of
+// preference
+var bunEntrypoints = []string{"index.ts", "server.ts", "main.ts", "index.js", "server.js"}
+
+// Detect detects bun apps, which have no buildpack in the paketo or heroku builders, so
+// they are built from a generated Dockerfile instead. The node buildpacks can still build
+// bun apps that are compatible with node, and are detected by the node runtime.
+func (runtime *bunRuntime) Detect(
+	contents *RepoContents,
+	paketo, heroku *BuilderInfo,
+) error {
+	files := make(map[string]bool)
+
+	for _, entry := range contents.Entries {
+		if entry.GetType() == "file" {
+			files[entry.GetName()] = true
+		}
+	}
+
+	lockfile := ""
+
+	if files["bun.lockb"] {
+		lockfile = "bun.lockb"
+	} else if files["bun.lock"] {
+		lockfile = "bun.lock"
+	}
+
+	if lockfile == "" || !files["package.json"] {
+		return nil
+	}
+
+	data, err := contents.ReadFile("package.json")
+
+	if err != nil {
+		return fmt.Errorf("error fetching contents of package.json: %v", err)
+	}
+
+	var packageJSON struct {
+		Scripts map[string]string `json:"scripts"`
+		Module  string            `json:"module"`
+		Main    string            `json:"main"`
+	}
+
+	if err := json.NewDecoder(strings.NewReader(data)).Decode(&packageJSON); err != nil {
+		return fmt.Errorf("error decoding package.json contents to struct: %v", err)
+	}
+
+	entrypoint := packageJSON.Module
+
+	if entrypoint == "" {
+		entrypoint = packageJSON.Main
+	}
+
+	for _, name := range bunEntrypoints {
+		if entrypoint != "" {
+			break
+		}
+
+		if files[name] {
+			entrypoint = name
+		}
+	}
+
+	var cmd []string
+
+	if _, ok := packageJSON.Scripts["start"]; ok {
+		cmd = []string{"bun", "run", "start"}
+	} else if entrypoint != "" {
+		cmd = []string{"bun", entrypoint}
+	} else {
+		return nil
+	}
+
+	dockerfile := []string{
+		"FROM oven/bun:1",
+		"WORKDIR /app",
+		fmt.Sprintf("COPY package.json %s ./", lockfile),
+	}
+
+	// the build script usually needs dev dependencies, so they are only pruned once the
+	// app is built
+	if _, ok := packageJSON.Scripts["build"]; ok {
+		dockerfile = append(
+			dockerfile,
+			"RUN bun install --frozen-lockfile",
+			"COPY . .",
+			"RUN bun run build",
+			"RUN rm -rf node_modules && bun install --frozen-lockfile --production",
+		)
+	} else {
+		dockerfile = append(
+			dockerfile,
+			"RUN bun install --frozen-lockfile --production",
+			"COPY . .",
+		)
+	}
+
+	dockerfile = append(dockerfile, fmt.Sprintf("CMD %s", dockerCmd(cmd)))
+
+	info := BuildpackInfo{
+		Name: "Bun",
+		Config: map[string]interface{}{
+			"scripts":    packageJSON.Scripts,
+			"entrypoint": entrypoint,
+		},
+		PackageManager: "bun",
+		Dockerfile:     strings.Join(dockerfile, "\n") + "\n",
+	}
+
+	paketo.Fallbacks = append(paketo.Fallbacks, info)
+	heroku.Fallbacks = append(heroku.Fallbacks, info)
+
+	return nil
+}
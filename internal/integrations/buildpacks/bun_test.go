@@ -0,0 +1,80 @@
+package buildpacks
+
+import (
+	"strings"
+	"testing"
+)
+
+func detectBunTestApp(t *testing.T, packageJSON string) *BuilderInfo {
+	t.Helper()
+
+	contents := newTestRepoContents(map[string]string{
+		"package.json": packageJSON,
+		"bun.lockb":    "",
+		"index.ts":     "",
+	})
+
+	paketo, heroku := &BuilderInfo{}, &BuilderInfo{}
+
+	if err := NewBunRuntime().Detect(contents, paketo, heroku); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(heroku.Fallbacks) != 1 || len(paketo.Fallbacks) != 1 {
+		t.Fatalf("expected bun to be detected as a fallback, got %v", heroku.Fallbacks)
+	}
+
+	return heroku
+}
+
+func TestBunDockerfileBuildsWithDevDependencies(t *testing.T) {
+	heroku := detectBunTestApp(t, `{"scripts": {"build": "bun build ./index.ts --outdir dist", "start": "bun dist/index.js"}}`)
+
+	lines := strings.Split(strings.TrimSpace(heroku.Fallbacks[0].Dockerfile), "\n")
+
+	expected := []string{
+		"FROM oven/bun:1",
+		"WORKDIR /app",
+		"COPY package.json bun.lockb ./",
+		"RUN bun install --frozen-lockfile",
+		"COPY . .",
+		"RUN bun run build",
+		"RUN rm -rf node_modules && bun install --frozen-lockfile --production",
+		`CMD ["bun","run","start"]`,
+	}
+
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected Dockerfile:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+}
+
+func TestBunDockerfileWithoutBuild(t *testing.T) {
+	heroku := detectBunTestApp(t, `{"name": "app"}`)
+
+	dockerfile := heroku.Fallbacks[0].Dockerfile
+
+	if !strings.Contains(dockerfile, "RUN bun install --frozen-lockfile --production\nCOPY . .\n") {
+		t.Errorf("expected only production dependencies to be installed, got:\n%s", dockerfile)
+	}
+
+	if !strings.HasSuffix(dockerfile, `CMD ["bun","index.ts"]`+"\n") {
+		t.Errorf("expected the app to be started from its entrypoint, got:\n%s", dockerfile)
+	}
+}
+
+func TestBunNotDetectedWithoutLockfile(t *testing.T) {
+	contents := newTestRepoContents(map[string]string{
+		"package.json": `{"scripts": {"start": "node index.js"}}`,
+		"index.js":     "",
+	})
+
+	paketo, heroku := &BuilderInfo{}, &BuilderInfo{}
+
+	if err := NewBunRuntime().Detect(contents, paketo, heroku); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(heroku.Fallbacks) != 0 {
+		t.Errorf("expected node apps not to be detected as bun apps, got %v", heroku.Fallbacks)
+	}
+}
@@ -0,0 +1,362 @@
+package buildpacks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+const (
+	// the contents of a commit never change, so cached contents only expire to bound the
+	// memory of the cache
+	repoContentsCacheTTL        = time.Hour
+	repoContentsCacheMaxEntries = 512
+
+	// repoContentsCacheMaxBytes bounds the estimated size of the cached snapshots,
+	// including the file contents that each snapshot may keep
+	repoContentsCacheMaxBytes = 256 << 20
+
+	// repoSnapshotMaxFileBytes bounds the contents of the files that are kept by a
+	// snapshot. Files that are read once the bound is reached are fetched on each read.
+	repoSnapshotMaxFileBytes = 1 << 20
+)
+
+// RepoContents are the contents of a directory of a GitHub repo at a commit. The
+// directory is listed once and shared by all runtime detectors, and files that are read
+// by several detectors are only fetched once.
+type RepoContents struct {
+	Owner string
+	Name  string
+	Path  string
+	SHA   string
+
+	// Entries are the files and directories in the directory
+	Entries []*github.RepositoryContent
+
+	// ctx is the context of the request that read the contents, which files are
+	// fetched with
+	ctx      context.Context
+	client   *github.Client
+	snapshot *repoSnapshot
+}
+
+// repoSnapshot is the part of the contents of a directory that is cached by commit SHA
+type repoSnapshot struct {
+	entries []*github.RepositoryContent
+
+	// blobs are the blob SHAs of files, keyed by their path relative to the directory
+	blobs     map[string]string
+	expiresAt time.Time
+
+	// size is the estimated size of the snapshot in the cache
+	size int
+
+	mu        sync.Mutex
+	files     map[string]*snapshotFile
+	fileBytes int
+}
+
+// snapshotFile is a file that has been read, or is being fetched. Concurrent reads of
+// the file wait for done to be closed instead of fetching the file again.
+type snapshotFile struct {
+	done    chan struct{}
+	content string
+	err     error
+}
+
+type GetRepoContentsOpts struct {
+	Owner string
+	Name  string
+	Path  string
+
+	// Ref is the branch, tag or SHA to read the contents at, or the default branch if empty
+	Ref string
+
+	// Recursive lists the directory with a single recursive request to the git trees api,
+	// which lets detectors read files in subdirectories without listing them. Directories
+	// of repos whose tree is too large to be listed recursively are listed through the
+	// contents api.
+	Recursive bool
+}
+
+var repoContentsCache = struct {
+	mu        sync.Mutex
+	snapshots map[string]*repoSnapshot
+	bytes     int
+}{
+	snapshots: make(map[string]*repoSnapshot),
+}
+
+// GetRepoContents resolves the ref of the options to a commit, and returns the contents
+// of the directory at that commit. Contents are cached by commit SHA.
+func GetRepoContents(ctx context.Context, client *github.Client, opts *GetRepoContentsOpts) (*RepoContents, error) {
+	ref := opts.Ref
+
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	sha, _, err := client.Repositories.GetCommitSHA1(ctx, opts.Owner, opts.Name, ref, "")
+
+	if err != nil {
+		return nil, fmt.Errorf("error resolving ref %s: %w", ref, err)
+	}
+
+	dir := strings.Trim(path.Clean("/"+opts.Path), "/")
+	key := fmt.Sprintf("%s/%s@%s:%s:%t", opts.Owner, opts.Name, sha, dir, opts.Recursive)
+
+	snapshot := getCachedSnapshot(key)
+
+	if snapshot == nil {
+		snapshot, err = fetchSnapshot(ctx, client, opts.Owner, opts.Name, dir, sha, opts.Recursive)
+
+		if err != nil {
+			return nil, err
+		}
+
+		setCachedSnapshot(key, snapshot)
+	}
+
+	return &RepoContents{
+		Owner:    opts.Owner,
+		Name:     opts.Name,
+		Path:     dir,
+		SHA:      sha,
+		Entries:  snapshot.entries,
+		ctx:      ctx,
+		client:   client,
+		snapshot: snapshot,
+	}, nil
+}
+
+// ReadFile returns the contents of a file at a path relative to the directory. Files in
+// subdirectories can only be read if the contents were listed recursively.
+func (c *RepoContents) ReadFile(filePath string) (string, error) {
+	// the blobs of a snapshot are not modified once it is fetched
+	blobSHA, ok := c.snapshot.blobs[filePath]
+
+	if !ok {
+		return "", fmt.Errorf("file %s not found in %s/%s", filePath, c.Owner, c.Name)
+	}
+
+	c.snapshot.mu.Lock()
+	file, ok := c.snapshot.files[filePath]
+
+	if !ok {
+		file = &snapshotFile{done: make(chan struct{})}
+		c.snapshot.files[filePath] = file
+	}
+
+	c.snapshot.mu.Unlock()
+
+	if !ok {
+		c.fetchFile(filePath, blobSHA, file)
+	}
+
+	select {
+	case <-file.done:
+		return file.content, file.err
+	case <-c.ctx.Done():
+		return "", c.ctx.Err()
+	}
+}
+
+// fetchFile fetches a file that is being read for the first time. Files that cannot be
+// fetched, or that do not fit in the contents kept by the snapshot, are dropped once the
+// reads that are waiting on them are done, so that they are fetched again on the next
+// read.
+func (c *RepoContents) fetchFile(filePath, blobSHA string, file *snapshotFile) {
+	content, _, err := c.client.Git.GetBlobRaw(c.ctx, c.Owner, c.Name, blobSHA)
+
+	file.content, file.err = string(content), err
+	close(file.done)
+
+	c.snapshot.mu.Lock()
+	defer c.snapshot.mu.Unlock()
+
+	if err != nil || c.snapshot.fileBytes+len(content) > repoSnapshotMaxFileBytes {
+		delete(c.snapshot.files, filePath)
+		return
+	}
+
+	c.snapshot.fileBytes += len(content)
+}
+
+func fetchSnapshot(
+	ctx context.Context,
+	client *github.Client,
+	owner, name, dir, sha string,
+	recursive bool,
+) (*repoSnapshot, error) {
+	if recursive {
+		tree, _, err := client.Git.GetTree(ctx, owner, name, sha, true)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !tree.GetTruncated() {
+			return snapshotFromTree(tree, dir), nil
+		}
+	}
+
+	_, directoryContents, _, err := client.Repositories.GetContents(
+		ctx,
+		owner,
+		name,
+		dir,
+		&github.RepositoryContentGetOptions{Ref: sha},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &repoSnapshot{
+		entries: directoryContents,
+		blobs:   make(map[string]string),
+		files:   make(map[string]*snapshotFile),
+	}
+
+	for _, entry := range directoryContents {
+		if entry.GetType() == "file" {
+			snapshot.blobs[entry.GetName()] = entry.GetSHA()
+		}
+	}
+
+	return snapshot, nil
+}
+
+// snapshotFromTree builds the contents of a directory from the recursive tree of a commit
+func snapshotFromTree(tree *github.Tree, dir string) *repoSnapshot {
+	snapshot := &repoSnapshot{
+		entries: make([]*github.RepositoryContent, 0),
+		blobs:   make(map[string]string),
+		files:   make(map[string]*snapshotFile),
+	}
+
+	prefix := ""
+
+	if dir != "" {
+		prefix = dir + "/"
+	}
+
+	for _, entry := range tree.Entries {
+		if !strings.HasPrefix(entry.GetPath(), prefix) {
+			continue
+		}
+
+		relPath := strings.TrimPrefix(entry.GetPath(), prefix)
+
+		entryType := "file"
+
+		if entry.GetType() == "tree" {
+			entryType = "dir"
+		} else if entry.GetType() == "blob" {
+			snapshot.blobs[relPath] = entry.GetSHA()
+		}
+
+		// only the direct children of the directory are entries
+		if !strings.Contains(relPath, "/") {
+			snapshot.entries = append(snapshot.entries, &github.RepositoryContent{
+				Name: github.String(relPath),
+				Path: github.String(entry.GetPath()),
+				Type: github.String(entryType),
+				SHA:  github.String(entry.GetSHA()),
+			})
+		}
+	}
+
+	return snapshot
+}
+
+func getCachedSnapshot(key string) *repoSnapshot {
+	repoContentsCache.mu.Lock()
+	defer repoContentsCache.mu.Unlock()
+
+	snapshot, ok := repoContentsCache.snapshots[key]
+
+	if !ok || time.Now().After(snapshot.expiresAt) {
+		return nil
+	}
+
+	return snapshot
+}
+
+// getSnapshotSize estimates the size of a snapshot from the paths and SHAs of its
+// entries and blobs, and reserves the file contents that the snapshot may keep
+func getSnapshotSize(snapshot *repoSnapshot) int {
+	// the overhead of each entry and blob, such as pointers and map buckets
+	const overhead = 64
+
+	size := repoSnapshotMaxFileBytes
+
+	for _, entry := range snapshot.entries {
+		size += len(entry.GetName()) + len(entry.GetPath()) + len(entry.GetSHA()) + overhead
+	}
+
+	for filePath, blobSHA := range snapshot.blobs {
+		size += len(filePath) + len(blobSHA) + overhead
+	}
+
+	return size
+}
+
+// setCachedSnapshot caches a snapshot, evicting expired snapshots and then the snapshots
+// that expire first until the snapshot fits in the cache. Snapshots that are larger than
+// the whole cache are not cached.
+func setCachedSnapshot(key string, snapshot *repoSnapshot) {
+	size := getSnapshotSize(snapshot)
+
+	if size > repoContentsCacheMaxBytes {
+		return
+	}
+
+	repoContentsCache.mu.Lock()
+	defer repoContentsCache.mu.Unlock()
+
+	now := time.Now()
+
+	// a snapshot of the same contents may have been cached by a concurrent request
+	deleteCachedSnapshot(key)
+
+	for k, s := range repoContentsCache.snapshots {
+		if now.After(s.expiresAt) {
+			deleteCachedSnapshot(k)
+		}
+	}
+
+	for len(repoContentsCache.snapshots) >= repoContentsCacheMaxEntries ||
+		repoContentsCache.bytes+size > repoContentsCacheMaxBytes {
+		var oldestKey string
+		var oldest time.Time
+
+		for k, s := range repoContentsCache.snapshots {
+			if oldestKey == "" || s.expiresAt.Before(oldest) {
+				oldestKey, oldest = k, s.expiresAt
+			}
+		}
+
+		deleteCachedSnapshot(oldestKey)
+	}
+
+	snapshot.expiresAt = now.Add(repoContentsCacheTTL)
+	snapshot.size = size
+
+	repoContentsCache.snapshots[key] = snapshot
+	repoContentsCache.bytes += size
+}
+
+// deleteCachedSnapshot removes a snapshot from the cache, and must be called with the
+// lock of the cache held
+func deleteCachedSnapshot(key string) {
+	if snapshot, ok := repoContentsCache.snapshots[key]; ok {
+		repoContentsCache.bytes -= snapshot.size
+		delete(repoContentsCache.snapshots, key)
+	}
+}
@@ -0,0 +1,229 @@
+package buildpacks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// newTestRepoContents returns the contents of a directory whose files are already read,
+// so that runtimes can be detected without the GitHub API
+func newTestRepoContents(files map[string]string) *RepoContents {
+	snapshot := &repoSnapshot{
+		entries: make([]*github.RepositoryContent, 0),
+		blobs:   make(map[string]string),
+		files:   make(map[string]*snapshotFile),
+	}
+
+	for name, content := range files {
+		snapshot.entries = append(snapshot.entries, &github.RepositoryContent{
+			Name: github.String(name),
+			Path: github.String(name),
+			Type: github.String("file"),
+		})
+
+		snapshot.blobs[name] = name
+		snapshot.files[name] = &snapshotFile{done: make(chan struct{}), content: content}
+		close(snapshot.files[name].done)
+	}
+
+	return &RepoContents{
+		Owner:    "porter-dev",
+		Name:     "app",
+		Entries:  snapshot.entries,
+		ctx:      context.Background(),
+		snapshot: snapshot,
+	}
+}
+
+// newTestBlobServer returns a client of a server that serves the given blobs, along with
+// the number of requests made for each blob. Requests for the blob named "slow" wait
+// until release is closed.
+func newTestBlobServer(t *testing.T, blobs map[string]string, release chan struct{}) (*github.Client, func(string) int) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sha := strings.TrimPrefix(r.URL.Path, "/repos/porter-dev/app/git/blobs/")
+
+		mu.Lock()
+		requests[sha]++
+		mu.Unlock()
+
+		if sha == "slow" {
+			<-release
+		}
+
+		content, ok := blobs[sha]
+
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprint(w, content)
+	}))
+
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	return client, func(sha string) int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests[sha]
+	}
+}
+
+// newTestBlobContents returns the contents of a directory whose files are fetched from
+// the client, with each file stored in the blob of the same name
+func newTestBlobContents(ctx context.Context, client *github.Client, names ...string) *RepoContents {
+	contents := newTestRepoContents(nil)
+	contents.ctx = ctx
+	contents.client = client
+
+	for _, name := range names {
+		contents.snapshot.blobs[name] = name
+	}
+
+	return contents
+}
+
+func TestReadFileFetchesEachFileOnce(t *testing.T) {
+	release := make(chan struct{})
+	client, requests := newTestBlobServer(t, map[string]string{"slow": "slow", "fast": "fast"}, release)
+	contents := newTestBlobContents(context.Background(), client, "slow", "fast")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if content, err := contents.ReadFile("slow"); err != nil || content != "slow" {
+				t.Errorf("expected the contents of the file, got %s: %v", content, err)
+			}
+		}()
+	}
+
+	for requests("slow") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// other files can be read while a file is being fetched
+	if content, err := contents.ReadFile("fast"); err != nil || content != "fast" {
+		t.Errorf("expected the contents of the file, got %s: %v", content, err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if requests("slow") != 1 {
+		t.Errorf("expected the file to be fetched once, got %d requests", requests("slow"))
+	}
+
+	if _, err := contents.ReadFile("missing"); err == nil {
+		t.Errorf("expected an error reading a file that is not in the directory")
+	}
+}
+
+func TestReadFileUsesRequestContext(t *testing.T) {
+	client, requests := newTestBlobServer(t, map[string]string{"package.json": "{}"}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cancelled := newTestBlobContents(ctx, client, "package.json")
+
+	if _, err := cancelled.ReadFile("package.json"); err == nil {
+		t.Errorf("expected an error reading a file after the request was cancelled")
+	}
+
+	// the failed read is not cached for the other requests of the same snapshot
+	contents := &RepoContents{
+		Owner:    "porter-dev",
+		Name:     "app",
+		ctx:      context.Background(),
+		client:   client,
+		snapshot: cancelled.snapshot,
+	}
+
+	if content, err := contents.ReadFile("package.json"); err != nil || content != "{}" {
+		t.Errorf("expected the contents of the file, got %s: %v", content, err)
+	}
+
+	if requests("package.json") != 1 {
+		t.Errorf("expected the file to be fetched by the request that was not cancelled, got %d requests", requests("package.json"))
+	}
+}
+
+func TestReadFileBoundsCachedContents(t *testing.T) {
+	client, requests := newTestBlobServer(t, map[string]string{
+		"large": strings.Repeat("a", repoSnapshotMaxFileBytes+1),
+		"small": "a",
+	}, nil)
+
+	contents := newTestBlobContents(context.Background(), client, "large", "small")
+
+	for i := 0; i < 2; i++ {
+		for _, name := range []string{"large", "small"} {
+			if _, err := contents.ReadFile(name); err != nil {
+				t.Fatalf("%v", err)
+			}
+		}
+	}
+
+	if requests("large") != 2 || requests("small") != 1 {
+		t.Errorf("expected only the small file to be cached, got %d and %d requests", requests("large"), requests("small"))
+	}
+}
+
+func TestSetCachedSnapshotBoundsCache(t *testing.T) {
+	repoContentsCache.mu.Lock()
+	snapshots, bytes := repoContentsCache.snapshots, repoContentsCache.bytes
+	repoContentsCache.snapshots, repoContentsCache.bytes = make(map[string]*repoSnapshot), 0
+	repoContentsCache.mu.Unlock()
+
+	t.Cleanup(func() {
+		repoContentsCache.mu.Lock()
+		repoContentsCache.snapshots, repoContentsCache.bytes = snapshots, bytes
+		repoContentsCache.mu.Unlock()
+	})
+
+	// a snapshot that is cached again replaces the previous snapshot
+	for i := 0; i < 2; i++ {
+		setCachedSnapshot("porter-dev/app@sha", newTestRepoContents(map[string]string{"Procfile": ""}).snapshot)
+	}
+
+	maxSnapshots := repoContentsCacheMaxBytes / repoSnapshotMaxFileBytes
+
+	for i := 0; i < maxSnapshots; i++ {
+		setCachedSnapshot(fmt.Sprintf("porter-dev/app@%d", i), newTestRepoContents(nil).snapshot)
+	}
+
+	if getCachedSnapshot("porter-dev/app@sha") != nil {
+		t.Errorf("expected the snapshot that expires first to be evicted")
+	}
+
+	size := 0
+
+	for _, snapshot := range repoContentsCache.snapshots {
+		size += snapshot.size
+	}
+
+	if len(repoContentsCache.snapshots) > repoContentsCacheMaxEntries || size != repoContentsCache.bytes || size > repoContentsCacheMaxBytes {
+		t.Errorf("expected the cache to be bounded, got %d snapshots of %d bytes", len(repoContentsCache.snapshots), repoContentsCache.bytes)
+	}
+}
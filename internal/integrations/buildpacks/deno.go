@@ -0,0 +1,136 @@
+package buildpacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type denoRuntime struct{}
+
+func NewDenoRuntime() Runtime {
+	return &denoRuntime{}
+}
+
+// denoEntrypoints are the files that deno apps are conventionally started from, in order
+// of preference
+var denoEntrypoints = []string{"main.ts", "server.ts", "mod.ts", "index.ts", "main.js", "server.js"}
+
+// Detect detects deno apps, which have no buildpack in the paketo or heroku builders, so
+// they are built from a generated Dockerfile instead
+func (runtime *denoRuntime) Detect(
+	contents *RepoContents,
+	paketo, heroku *BuilderInfo,
+) error {
+	files := make(map[string]bool)
+
+	for _, entry := range contents.Entries {
+		if entry.GetType() == "file" {
+			files[entry.GetName()] = true
+		}
+	}
+
+	configFile := ""
+
+	if files["deno.json"] {
+		configFile = "deno.json"
+	} else if files["deno.jsonc"] {
+		configFile = "deno.jsonc"
+	}
+
+	denoJSONFound := configFile != ""
+	importMapFound := files["import_map.json"]
+	entrypoint := ""
+
+	for _, name := range denoEntrypoints {
+		if files[name] {
+			entrypoint = name
+			break
+		}
+	}
+
+	// a typescript entrypoint is not enough to tell deno apps apart from node apps, so deno
+	// apps are only detected from their config or import map
+	if !denoJSONFound && !importMapFound {
+		return nil
+	}
+
+	var denoJSON struct {
+		Tasks     map[string]string `json:"tasks"`
+		ImportMap string            `json:"importMap"`
+	}
+
+	if configFile == "deno.json" {
+		data, err := contents.ReadFile(configFile)
+
+		if err != nil {
+			return fmt.Errorf("error fetching contents of deno.json: %v", err)
+		}
+
+		// deno.json can be invalid for go without being invalid for deno, so the tasks and
+		// import map are best effort
+		json.NewDecoder(strings.NewReader(data)).Decode(&denoJSON)
+	}
+
+	importMap := denoJSON.ImportMap
+
+	if importMap == "" && importMapFound {
+		importMap = "import_map.json"
+	}
+
+	runArgs := []string{"run", "--allow-net", "--allow-env", "--allow-read"}
+
+	if importMap != "" {
+		runArgs = append(runArgs, "--import-map="+importMap)
+	}
+
+	var cmd []string
+
+	if _, ok := denoJSON.Tasks["start"]; ok {
+		cmd = []string{"task", "start"}
+	} else if entrypoint != "" {
+		cmd = append(runArgs, entrypoint)
+	} else {
+		return nil
+	}
+
+	dockerfile := []string{
+		"FROM denoland/deno:alpine",
+		"WORKDIR /app",
+		"COPY . .",
+	}
+
+	if entrypoint != "" {
+		cacheCmd := "RUN deno cache"
+
+		if importMap != "" {
+			cacheCmd += " --import-map=" + importMap
+		}
+
+		dockerfile = append(dockerfile, cacheCmd+" "+entrypoint)
+	}
+
+	dockerfile = append(dockerfile, fmt.Sprintf("CMD %s", dockerCmd(cmd)))
+
+	info := BuildpackInfo{
+		Name: "Deno",
+		Config: map[string]interface{}{
+			"entrypoint": entrypoint,
+			"import_map": importMap,
+			"tasks":      denoJSON.Tasks,
+		},
+		Dockerfile: strings.Join(dockerfile, "\n") + "\n",
+	}
+
+	paketo.Fallbacks = append(paketo.Fallbacks, info)
+	heroku.Fallbacks = append(heroku.Fallbacks, info)
+
+	return nil
+}
+
+// dockerCmd formats a command in the exec form of a Dockerfile CMD instruction
+func dockerCmd(args []string) string {
+	data, _ := json.Marshal(args)
+
+	return string(data)
+}
@@ -0,0 +1,53 @@
+package buildpacks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDenoDockerfile(t *testing.T) {
+	contents := newTestRepoContents(map[string]string{
+		"deno.json":       `{"importMap": "./imports.json"}`,
+		"main.ts":         "",
+		"import_map.json": "{}",
+	})
+
+	paketo, heroku := &BuilderInfo{}, &BuilderInfo{}
+
+	if err := NewDenoRuntime().Detect(contents, paketo, heroku); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(heroku.Fallbacks) != 1 || len(paketo.Fallbacks) != 1 {
+		t.Fatalf("expected deno to be detected as a fallback, got %v", heroku.Fallbacks)
+	}
+
+	expected := strings.Join([]string{
+		"FROM denoland/deno:alpine",
+		"WORKDIR /app",
+		"COPY . .",
+		"RUN deno cache --import-map=./imports.json main.ts",
+		`CMD ["run","--allow-net","--allow-env","--allow-read","--import-map=./imports.json","main.ts"]`,
+	}, "\n") + "\n"
+
+	if dockerfile := heroku.Fallbacks[0].Dockerfile; dockerfile != expected {
+		t.Errorf("expected Dockerfile:\n%s\ngot:\n%s", expected, dockerfile)
+	}
+}
+
+func TestDenoNotDetectedFromTypescript(t *testing.T) {
+	contents := newTestRepoContents(map[string]string{
+		"package.json": `{"scripts": {"start": "ts-node main.ts"}}`,
+		"main.ts":      "",
+	})
+
+	paketo, heroku := &BuilderInfo{}, &BuilderInfo{}
+
+	if err := NewDenoRuntime().Detect(contents, paketo, heroku); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(heroku.Fallbacks) != 0 {
+		t.Errorf("expected typescript node apps not to be detected as deno apps, got %v", heroku.Fallbacks)
+	}
+}
@@ -62,12 +62,11 @@ func (runtime *goRuntime) detectDep(results chan struct {
 }
 
 func (runtime *goRuntime) Detect(
-	client *github.Client,
-	directoryContent []*github.RepositoryContent,
-	owner, name, path string,
-	repoContentOptions github.RepositoryContentGetOptions,
+	contents *RepoContents,
 	paketo, heroku *BuilderInfo,
 ) error {
+	directoryContent := contents.Entries
+
 	results := make(chan struct {
 		string
 		bool
@@ -94,6 +93,10 @@ func (runtime *goRuntime) Detect(
 		return nil
 	}
 
+	packageManager := detectedPackageManager(results, mod, dep)
+	paketoBuildpackInfo.PackageManager = packageManager
+	herokuBuildpackInfo.PackageManager = packageManager
+
 	paketo.Detected = append(paketo.Detected, paketoBuildpackInfo)
 	heroku.Detected = append(heroku.Detected, herokuBuildpackInfo)
 
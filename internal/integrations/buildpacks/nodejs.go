@@ -1,7 +1,6 @@
 package buildpacks
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -54,6 +53,32 @@ func (runtime *nodejsRuntime) detectYarn(results chan struct {
 	runtime.wg.Done()
 }
 
+func (runtime *nodejsRuntime) detectPNPM(results chan struct {
+	string
+	bool
+}, directoryContent []*github.RepositoryContent) {
+	pnpmLockFound := false
+	packageJSONFound := false
+	for i := 0; i < len(directoryContent); i++ {
+		name := directoryContent[i].GetName()
+		if name == "pnpm-lock.yaml" {
+			pnpmLockFound = true
+		} else if name == "package.json" {
+			packageJSONFound = true
+		}
+		if pnpmLockFound && packageJSONFound {
+			break
+		}
+	}
+	if pnpmLockFound && packageJSONFound {
+		results <- struct {
+			string
+			bool
+		}{pnpm, true}
+	}
+	runtime.wg.Done()
+}
+
 func (runtime *nodejsRuntime) detectNPM(results chan struct {
 	string
 	bool
@@ -158,19 +183,19 @@ func validateNodeVersion(content string) (string, error) {
 }
 
 func (runtime *nodejsRuntime) Detect(
-	client *github.Client,
-	directoryContent []*github.RepositoryContent,
-	owner, name, path string,
-	repoContentOptions github.RepositoryContentGetOptions,
+	contents *RepoContents,
 	paketo, heroku *BuilderInfo,
 ) error {
+	directoryContent := contents.Entries
+
 	results := make(chan struct {
 		string
 		bool
-	}, 3)
+	}, 4)
 
-	runtime.wg.Add(3)
+	runtime.wg.Add(4)
 	go runtime.detectYarn(results, directoryContent)
+	go runtime.detectPNPM(results, directoryContent)
 	go runtime.detectNPM(results, directoryContent)
 	go runtime.detectStandalone(results, directoryContent)
 	runtime.wg.Wait()
@@ -192,11 +217,14 @@ func (runtime *nodejsRuntime) Detect(
 	}
 
 	foundYarn := false
+	foundPNPM := false
 	foundNPM := false
 	foundStandalone := false
 	for result := range results {
 		if result.string == yarn {
 			foundYarn = true
+		} else if result.string == pnpm {
+			foundPNPM = true
 		} else if result.string == npm {
 			foundNPM = true
 		} else if result.string == standalone {
@@ -204,15 +232,9 @@ func (runtime *nodejsRuntime) Detect(
 		}
 	}
 
-	if foundYarn || foundNPM {
+	if foundYarn || foundPNPM || foundNPM {
 		// it is safe to assume that the project contains a package.json
-		fileContent, _, _, err := client.Repositories.GetContents(
-			context.Background(),
-			owner,
-			name,
-			fmt.Sprintf("%s/package.json", path),
-			&repoContentOptions,
-		)
+		data, err := contents.ReadFile("package.json")
 		if err != nil {
 			paketo.Others = append(paketo.Others, paketoBuildpackInfo)
 			heroku.Others = append(heroku.Others, herokuBuildpackInfo)
@@ -223,14 +245,9 @@ func (runtime *nodejsRuntime) Detect(
 			Engines struct {
 				Node string `json:"node"`
 			} `json:"engines"`
+			Workspaces packageJSONWorkspaces `json:"workspaces"`
 		}
 
-		data, err := fileContent.GetContent()
-		if err != nil {
-			paketo.Others = append(paketo.Others, paketoBuildpackInfo)
-			heroku.Others = append(heroku.Others, herokuBuildpackInfo)
-			return fmt.Errorf("error calling GetContent() on package.json: %v", err)
-		}
 		err = json.NewDecoder(strings.NewReader(data)).Decode(&packageJSON)
 		if err != nil {
 			paketo.Others = append(paketo.Others, paketoBuildpackInfo)
@@ -253,24 +270,12 @@ func (runtime *nodejsRuntime) Detect(
 
 			if nvmrcFound {
 				// copy exact behavior of https://github.com/paketo-buildpacks/node-engine/blob/main/nvmrc_parser.go
-				fileContent, _, _, err = client.Repositories.GetContents(
-					context.Background(),
-					owner,
-					name,
-					fmt.Sprintf("%s/.nvmrc", path),
-					&repoContentOptions,
-				)
+				data, err = contents.ReadFile(".nvmrc")
 				if err != nil {
 					paketo.Others = append(paketo.Others, paketoBuildpackInfo)
 					heroku.Others = append(heroku.Others, herokuBuildpackInfo)
 					return fmt.Errorf("error fetching contents of .nvmrc: %v", err)
 				}
-				data, err = fileContent.GetContent()
-				if err != nil {
-					paketo.Others = append(paketo.Others, paketoBuildpackInfo)
-					heroku.Others = append(heroku.Others, herokuBuildpackInfo)
-					return fmt.Errorf("error calling GetContent() on .nvmrc: %v", err)
-				}
 				nvmrcVersion, err := validateNvmrc(data)
 				if err != nil {
 					paketo.Others = append(paketo.Others, paketoBuildpackInfo)
@@ -286,24 +291,12 @@ func (runtime *nodejsRuntime) Detect(
 
 			if packageJSON.Engines.Node == "" && nodeVersionFound {
 				// copy exact behavior of https://github.com/paketo-buildpacks/node-engine/blob/main/node_version_parser.go
-				fileContent, _, _, err = client.Repositories.GetContents(
-					context.Background(),
-					owner,
-					name,
-					fmt.Sprintf("%s/.node-version", path),
-					&repoContentOptions,
-				)
+				data, err = contents.ReadFile(".node-version")
 				if err != nil {
 					paketo.Others = append(paketo.Others, paketoBuildpackInfo)
 					heroku.Others = append(heroku.Others, herokuBuildpackInfo)
 					return fmt.Errorf("error fetching contents of .node-version: %v", err)
 				}
-				data, err = fileContent.GetContent()
-				if err != nil {
-					paketo.Others = append(paketo.Others, paketoBuildpackInfo)
-					heroku.Others = append(heroku.Others, herokuBuildpackInfo)
-					return fmt.Errorf("error calling GetContent() on .node-version: %v", err)
-				}
 				nodeVersion, err := validateNodeVersion(data)
 				if err != nil {
 					paketo.Others = append(paketo.Others, paketoBuildpackInfo)
@@ -321,14 +314,49 @@ func (runtime *nodejsRuntime) Detect(
 			packageJSON.Engines.Node = "16.*.*"
 		}
 
+		packageManager := npm
+
+		if foundPNPM {
+			packageManager = pnpm
+		} else if foundYarn {
+			packageManager = yarn
+		}
+
+		workspace := detectNodeWorkspace(contents, packageManager, packageJSON.Workspaces)
+
 		paketoBuildpackInfo.Config = make(map[string]interface{})
 		paketoBuildpackInfo.Config["scripts"] = packageJSON.Scripts
 		paketoBuildpackInfo.Config["node_engine"] = packageJSON.Engines.Node
-		paketo.Detected = append(paketo.Detected, paketoBuildpackInfo)
+		paketoBuildpackInfo.PackageManager = packageManager
+		paketoBuildpackInfo.EnvHints = map[string]string{
+			"BP_NODE_VERSION": packageJSON.Engines.Node,
+		}
+
+		if _, ok := packageJSON.Scripts["build"]; ok {
+			paketoBuildpackInfo.EnvHints["BP_NODE_RUN_SCRIPTS"] = "build"
+		}
 
 		herokuBuildpackInfo.Config = make(map[string]interface{})
 		herokuBuildpackInfo.Config["scripts"] = packageJSON.Scripts
 		herokuBuildpackInfo.Config["node_engine"] = packageJSON.Engines.Node
+		herokuBuildpackInfo.PackageManager = packageManager
+
+		if workspace != nil {
+			for _, info := range []*BuildpackInfo{&paketoBuildpackInfo, &herokuBuildpackInfo} {
+				info.Config["workspace_tool"] = workspace.Tool
+				info.Config["workspaces"] = workspace.Patterns
+				info.Config["project_paths"] = workspace.ProjectPaths
+			}
+
+			// a monorepo root that cannot be started on its own is built from the only app
+			// of the workspace, and apps of workspaces with several apps are picked by the user
+			// from the project paths
+			if _, ok := packageJSON.Scripts["start"]; !ok && len(workspace.ProjectPaths) == 1 {
+				paketoBuildpackInfo.EnvHints["BP_NODE_PROJECT_PATH"] = workspace.ProjectPaths[0]
+			}
+		}
+
+		paketo.Detected = append(paketo.Detected, paketoBuildpackInfo)
 		heroku.Detected = append(heroku.Detected, herokuBuildpackInfo)
 	} else if foundStandalone {
 		paketo.Detected = append(paketo.Detected, paketoBuildpackInfo)
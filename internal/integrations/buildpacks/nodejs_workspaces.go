@@ -0,0 +1,141 @@
+package buildpacks
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// Node workspace tools
+	turborepo = "turborepo"
+	nx        = "nx"
+	lerna     = "lerna"
+)
+
+// nodeWorkspace describes the workspaces of a node monorepo
+type nodeWorkspace struct {
+	// Tool is the tool that orchestrates the workspaces, such as turborepo or nx, or the
+	// package manager if the workspaces are only declared to the package manager
+	Tool string
+
+	// Patterns are the globs of the workspace packages
+	Patterns []string
+
+	// ProjectPaths are the directories of the workspace packages
+	ProjectPaths []string
+}
+
+// packageJSONWorkspaces can be a list of globs, or an object with a list of globs in
+// packages
+type packageJSONWorkspaces []string
+
+func (w *packageJSONWorkspaces) UnmarshalJSON(data []byte) error {
+	var patterns []string
+
+	if err := json.Unmarshal(data, &patterns); err == nil {
+		*w = patterns
+		return nil
+	}
+
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+
+	*w = object.Packages
+
+	return nil
+}
+
+// detectNodeWorkspace returns the workspaces of the directory if it is the root of a node
+// monorepo, or nil otherwise
+func detectNodeWorkspace(contents *RepoContents, packageManager string, workspaces []string) *nodeWorkspace {
+	tool := ""
+
+	for _, entry := range contents.Entries {
+		switch entry.GetName() {
+		case "turbo.json":
+			tool = turborepo
+		case "nx.json":
+			if tool == "" {
+				tool = nx
+			}
+		case "lerna.json":
+			if tool == "" {
+				tool = lerna
+			}
+		case "pnpm-workspace.yaml":
+			if data, err := contents.ReadFile("pnpm-workspace.yaml"); err == nil {
+				var pnpmWorkspace struct {
+					Packages []string `yaml:"packages"`
+				}
+
+				if err := yaml.Unmarshal([]byte(data), &pnpmWorkspace); err == nil {
+					workspaces = append(workspaces, pnpmWorkspace.Packages...)
+				}
+			}
+		}
+	}
+
+	if tool == "" && len(workspaces) == 0 {
+		return nil
+	}
+
+	if tool == "" {
+		tool = packageManager
+	}
+
+	return &nodeWorkspace{
+		Tool:         tool,
+		Patterns:     workspaces,
+		ProjectPaths: contents.matchPackageDirs(workspaces),
+	}
+}
+
+// matchPackageDirs returns the directories with a package.json that match any of the
+// workspace globs. Globs can only be matched if the contents were listed recursively.
+func (c *RepoContents) matchPackageDirs(patterns []string) []string {
+	dirs := make([]string, 0)
+
+	for filePath := range c.snapshot.blobs {
+		if path.Base(filePath) != "package.json" || strings.Contains(filePath, "node_modules/") {
+			continue
+		}
+
+		dir := path.Dir(filePath)
+
+		for _, pattern := range patterns {
+			if matchWorkspacePattern(strings.TrimPrefix(pattern, "./"), dir) {
+				dirs = append(dirs, dir)
+				break
+			}
+		}
+	}
+
+	sort.Strings(dirs)
+
+	return dirs
+}
+
+func matchWorkspacePattern(pattern, dir string) bool {
+	if strings.HasPrefix(pattern, "!") {
+		return false
+	}
+
+	// "packages/**" matches packages at any depth under the directory
+	if strings.HasSuffix(pattern, "/**") {
+		prefix := strings.TrimSuffix(pattern, "**")
+		return strings.HasPrefix(dir, prefix)
+	}
+
+	matched, err := path.Match(strings.TrimSuffix(pattern, "/"), dir)
+
+	return err == nil && matched
+}
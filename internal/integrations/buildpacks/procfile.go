@@ -0,0 +1,57 @@
+package buildpacks
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var procfileRegex = regexp.MustCompile("^([A-Za-z0-9_-]+):\\s*(.+)$")
+
+// ParseProcfile parses the contents of a Procfile into the command of each process type
+func ParseProcfile(content string) map[string]string {
+	processes := make(map[string]string)
+
+	for _, line := range strings.Split(content, "\n") {
+		if matches := procfileRegex.FindStringSubmatch(strings.TrimRight(line, "\r")); matches != nil {
+			processes[matches[1]] = strings.TrimSpace(matches[2])
+		}
+	}
+
+	return processes
+}
+
+// ReleaseProcessType is the process type of the command that is run with the new image of
+// an app before each deploy, such as database migrations. It is not run by a chart of its
+// own, but as the pre-deploy command of the release of another process.
+const ReleaseProcessType = "release"
+
+// ProcessKind returns the kind of chart that runs a process type: the web process is run
+// by a web chart, and all other processes are run by worker charts
+func ProcessKind(processType string) string {
+	if processType == "web" {
+		return "web"
+	}
+
+	return "worker"
+}
+
+// SortProcessTypes sorts process types in the order their releases should be created:
+// the web process first, and then the other processes by name
+func SortProcessTypes(processes map[string]string) []string {
+	processTypes := make([]string, 0, len(processes))
+
+	for processType := range processes {
+		processTypes = append(processTypes, processType)
+	}
+
+	sort.Slice(processTypes, func(i, j int) bool {
+		if (processTypes[i] == "web") != (processTypes[j] == "web") {
+			return processTypes[i] == "web"
+		}
+
+		return processTypes[i] < processTypes[j]
+	})
+
+	return processTypes
+}
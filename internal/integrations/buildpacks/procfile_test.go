@@ -0,0 +1,48 @@
+package buildpacks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProcfile(t *testing.T) {
+	procfile := "web: bundle exec puma -C config/puma.rb\r\n" +
+		"# workers are scaled separately\r\n" +
+		"worker:   bundle exec sidekiq  \r\n" +
+		"release: bin/rails db:migrate\n" +
+		"\n" +
+		"clock-tick: bin/clock --interval=60\n" +
+		"not a process\n" +
+		"empty:\n"
+
+	expected := map[string]string{
+		"web":        "bundle exec puma -C config/puma.rb",
+		"worker":     "bundle exec sidekiq",
+		"release":    "bin/rails db:migrate",
+		"clock-tick": "bin/clock --interval=60",
+	}
+
+	if processes := ParseProcfile(procfile); !reflect.DeepEqual(processes, expected) {
+		t.Errorf("expected %v, got %v", expected, processes)
+	}
+
+	if processes := ParseProcfile(""); len(processes) != 0 {
+		t.Errorf("expected no processes, got %v", processes)
+	}
+}
+
+func TestSortProcessTypes(t *testing.T) {
+	processes := ParseProcfile("worker: sidekiq\nclock: clockwork\nweb: puma\n")
+
+	expected := []string{"web", "clock", "worker"}
+
+	if processTypes := SortProcessTypes(processes); !reflect.DeepEqual(processTypes, expected) {
+		t.Errorf("expected %v, got %v", expected, processTypes)
+	}
+
+	for processType, kind := range map[string]string{"web": "web", "worker": "worker", "clock": "worker"} {
+		if ProcessKind(processType) != kind {
+			t.Errorf("expected process %s to be run by a %s chart, got %s", processType, kind, ProcessKind(processType))
+		}
+	}
+}
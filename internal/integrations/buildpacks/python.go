@@ -108,12 +108,11 @@ func (runtime *pythonRuntime) detectStandalone(results chan struct {
 }
 
 func (runtime *pythonRuntime) Detect(
-	client *github.Client,
-	directoryContent []*github.RepositoryContent,
-	owner, name, path string,
-	repoContentOptions github.RepositoryContentGetOptions,
+	contents *RepoContents,
 	paketo, heroku *BuilderInfo,
 ) error {
+	directoryContent := contents.Entries
+
 	results := make(chan struct {
 		string
 		bool
@@ -142,6 +141,10 @@ func (runtime *pythonRuntime) Detect(
 		return nil
 	}
 
+	packageManager := detectedPackageManager(results, pipenv, conda, pip)
+	paketoBuildpackInfo.PackageManager = packageManager
+	herokuBuildpackInfo.PackageManager = packageManager
+
 	paketo.Detected = append(paketo.Detected, paketoBuildpackInfo)
 	heroku.Detected = append(heroku.Detected, herokuBuildpackInfo)
 
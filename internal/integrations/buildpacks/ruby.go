@@ -2,13 +2,11 @@ package buildpacks
 
 import (
 	"bufio"
-	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
-
-	"github.com/google/go-github/v41/github"
 )
 
 type rubyRuntime struct {
@@ -115,20 +113,11 @@ func (runtime *rubyRuntime) detectPassenger(gemfileContent string, results chan
 	runtime.wg.Done()
 }
 
-func (runtime *rubyRuntime) detectRackup(
-	client *github.Client, owner, name string,
-	repoContentOptions github.RepositoryContentGetOptions, results chan struct {
-		string
-		bool
-	},
-) {
-	fileContent, _, _, err := client.Repositories.GetContents(context.Background(),
-		owner, name, "Gemfile.lock", &repoContentOptions)
-	if err != nil {
-		runtime.wg.Done()
-		return
-	}
-	gemfileLockContent, err := fileContent.GetContent()
+func (runtime *rubyRuntime) detectRackup(contents *RepoContents, results chan struct {
+	string
+	bool
+}) {
+	gemfileLockContent, err := contents.ReadFile("Gemfile.lock")
 	if err != nil {
 		runtime.wg.Done()
 		return
@@ -179,17 +168,120 @@ func (runtime *rubyRuntime) detectRake(gemfileContent string, results chan struc
 	runtime.wg.Done()
 }
 
+// rubySystemPackages are the system packages that gems with native extensions need in
+// order to be installed
+var rubySystemPackages = map[string][]string{
+	"pg":          {"libpq-dev"},
+	"mysql2":      {"default-libmysqlclient-dev"},
+	"sqlite3":     {"libsqlite3-dev"},
+	"nokogiri":    {"libxml2-dev", "libxslt1-dev"},
+	"rmagick":     {"libmagickwand-dev"},
+	"mini_magick": {"imagemagick"},
+	"ruby-vips":   {"libvips"},
+}
+
+var (
+	gemfileGemRe         = regexp.MustCompile(`^\s*gem\s+["']([^"']+)["']`)
+	gemfileRubyVersionRe = regexp.MustCompile(`^\s*ruby\s+["']([^"']+)["']`)
+	gemfileLockSpecRe    = regexp.MustCompile(`^    ([a-zA-Z0-9_.-]+) \(`)
+	versionRe            = regexp.MustCompile(`[0-9]+(\.[0-9]+)*`)
+)
+
+// detectRubyVersion returns the ruby version of the app, which is read from the
+// Gemfile.lock, the Gemfile and the .ruby-version file in that order
+func detectRubyVersion(contents *RepoContents, gemfileContent string, gemfileLockFound, rubyVersionFound bool) string {
+	if gemfileLockFound {
+		if gemfileLockContent, err := contents.ReadFile("Gemfile.lock"); err == nil {
+			scanner := bufio.NewScanner(strings.NewReader(gemfileLockContent))
+			for scanner.Scan() {
+				if strings.TrimSpace(scanner.Text()) == "RUBY VERSION" && scanner.Scan() {
+					// the version is written as "ruby 3.1.2p20"
+					if version := versionRe.FindString(scanner.Text()); version != "" {
+						return version
+					}
+				}
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(gemfileContent))
+	for scanner.Scan() {
+		if matches := gemfileRubyVersionRe.FindStringSubmatch(scanner.Text()); matches != nil {
+			// keep version constraints such as "~> 2.7", as they are understood by buildpacks
+			return strings.TrimSpace(matches[1])
+		}
+	}
+
+	if rubyVersionFound {
+		if rubyVersionContent, err := contents.ReadFile(".ruby-version"); err == nil {
+			return versionRe.FindString(strings.TrimPrefix(strings.TrimSpace(rubyVersionContent), "ruby-"))
+		}
+	}
+
+	return ""
+}
+
+// listGems returns the gems that the app depends on, which are read from the Gemfile and
+// the specs of the Gemfile.lock
+func listGems(contents *RepoContents, gemfileContent string, gemfileLockFound bool) map[string]bool {
+	gems := make(map[string]bool)
+
+	scanner := bufio.NewScanner(strings.NewReader(gemfileContent))
+	for scanner.Scan() {
+		if matches := gemfileGemRe.FindStringSubmatch(scanner.Text()); matches != nil {
+			gems[matches[1]] = true
+		}
+	}
+
+	if gemfileLockFound {
+		if gemfileLockContent, err := contents.ReadFile("Gemfile.lock"); err == nil {
+			scanner := bufio.NewScanner(strings.NewReader(gemfileLockContent))
+			for scanner.Scan() {
+				if matches := gemfileLockSpecRe.FindStringSubmatch(scanner.Text()); matches != nil {
+					gems[matches[1]] = true
+				}
+			}
+		}
+	}
+
+	return gems
+}
+
+func detectRubyFramework(gems map[string]bool) string {
+	if gems["rails"] || gems["railties"] {
+		return "rails"
+	} else if gems["sinatra"] {
+		return "sinatra"
+	}
+
+	return ""
+}
+
+func detectRubySystemPackages(gems map[string]bool) []string {
+	packages := make([]string, 0)
+
+	for gem, gemPackages := range rubySystemPackages {
+		if gems[gem] {
+			packages = append(packages, gemPackages...)
+		}
+	}
+
+	sort.Strings(packages)
+
+	return packages
+}
+
 func (runtime *rubyRuntime) Detect(
-	client *github.Client,
-	directoryContent []*github.RepositoryContent,
-	owner, name, path string,
-	repoContentOptions github.RepositoryContentGetOptions,
+	contents *RepoContents,
 	paketo, heroku *BuilderInfo,
 ) error {
+	directoryContent := contents.Entries
+
 	gemfileFound := false
 	gemfileLockFound := false
 	configRuFound := false
 	rakefileFound := false
+	rubyVersionFound := false
 	for i := range directoryContent {
 		name := directoryContent[i].GetName()
 		if name == "Gemfile" {
@@ -200,6 +292,8 @@ func (runtime *rubyRuntime) Detect(
 			configRuFound = true
 		} else if name == "Rakefile" || name == "Rakefile.rb" || name == "rakefile" || name == "rakefile.rb" {
 			rakefileFound = true
+		} else if name == ".ruby-version" {
+			rubyVersionFound = true
 		}
 	}
 
@@ -218,17 +312,11 @@ func (runtime *rubyRuntime) Detect(
 		return nil
 	}
 
-	fileContent, _, _, err := client.Repositories.GetContents(context.Background(), owner, name, "Gemfile", &repoContentOptions)
-	if err != nil {
-		paketo.Others = append(paketo.Others, paketoBuildpackInfo)
-		heroku.Others = append(heroku.Others, herokuBuildpackInfo)
-		return fmt.Errorf("error fetching contents of Gemfile for %s/%s: %v", owner, name, err)
-	}
-	gemfileContent, err := fileContent.GetContent()
+	gemfileContent, err := contents.ReadFile("Gemfile")
 	if err != nil {
 		paketo.Others = append(paketo.Others, paketoBuildpackInfo)
 		heroku.Others = append(heroku.Others, herokuBuildpackInfo)
-		return fmt.Errorf("error calling GetContent() on Gemfile for %s/%s: %v", owner, name, err)
+		return fmt.Errorf("error fetching contents of Gemfile for %s/%s: %v", contents.Owner, contents.Name, err)
 	}
 
 	count := 6
@@ -265,7 +353,7 @@ func (runtime *rubyRuntime) Detect(
 	}
 	go runtime.detectPassenger(gemfileContent, results)
 	if !configRuFound && gemfileLockFound {
-		go runtime.detectRackup(client, owner, name, repoContentOptions, results)
+		go runtime.detectRackup(contents, results)
 	}
 	if rakefileFound {
 		go runtime.detectRake(gemfileContent, results)
@@ -273,6 +361,27 @@ func (runtime *rubyRuntime) Detect(
 	runtime.wg.Wait()
 	close(results)
 
+	paketoBuildpackInfo.PackageManager = "bundler"
+	herokuBuildpackInfo.PackageManager = "bundler"
+
+	gems := listGems(contents, gemfileContent, gemfileLockFound)
+	rubyVersion := detectRubyVersion(contents, gemfileContent, gemfileLockFound, rubyVersionFound)
+
+	for _, info := range []*BuildpackInfo{&paketoBuildpackInfo, &herokuBuildpackInfo} {
+		info.Config = make(map[string]interface{})
+		info.Config["ruby_version"] = rubyVersion
+		info.Config["framework"] = detectRubyFramework(gems)
+		info.Config["system_packages"] = detectRubySystemPackages(gems)
+	}
+
+	// the heroku buildpack reads the ruby version from the Gemfile, while the paketo
+	// buildpack is configured through the environment
+	if rubyVersion != "" {
+		paketoBuildpackInfo.EnvHints = map[string]string{
+			"BP_RUBY_VERSION": rubyVersion,
+		}
+	}
+
 	paketo.Detected = append(paketo.Detected, paketoBuildpackInfo)
 	heroku.Detected = append(heroku.Detected, herokuBuildpackInfo)
 
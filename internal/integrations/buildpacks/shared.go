@@ -1,13 +1,10 @@
 package buildpacks
 
-import (
-	"github.com/google/go-github/v41/github"
-)
-
 const (
 	// NodeJS
 	yarn = "yarn"
 	npm  = "npm"
+	pnpm = "pnpm"
 
 	// Go
 	mod = "mod"
@@ -34,10 +31,24 @@ const (
 	HerokuBuilder = "heroku"
 )
 
+// GeneratedDockerfileName is the name of the file that the generated Dockerfile of a
+// runtime without a buildpack is written to, in the folder of the app, before it is built
+const GeneratedDockerfileName = "porter.Dockerfile"
+
 type BuildpackInfo struct {
 	Name      string                 `json:"name"`
 	Buildpack string                 `json:"buildpack"`
 	Config    map[string]interface{} `json:"config"`
+
+	// PackageManager is the package manager that was detected for the buildpack
+	PackageManager string `json:"package_manager,omitempty"`
+
+	// EnvHints are build env vars that are suggested for the buildpack from the contents of
+	// the repo
+	EnvHints map[string]string `json:"env_hints,omitempty"`
+
+	// Dockerfile is a generated Dockerfile for runtimes that have no buildpack
+	Dockerfile string `json:"dockerfile,omitempty"`
 }
 
 type BuilderInfo struct {
@@ -45,25 +56,50 @@ type BuilderInfo struct {
 	Builders []string        `json:"builders"`
 	Detected []BuildpackInfo `json:"detected"`
 	Others   []BuildpackInfo `json:"others"`
+
+	// Fallbacks are runtimes that were detected but have no buildpack in the builder, which
+	// can be built from their generated Dockerfile instead
+	Fallbacks []BuildpackInfo `json:"fallbacks,omitempty"`
+
+	// Processes are the commands of the process types in the Procfile of the directory
+	Processes map[string]string `json:"processes,omitempty"`
 }
 
 type Runtime interface {
 	Detect(
-		*github.Client, // github client to pull contents of files
-		[]*github.RepositoryContent, // the root folder structure of the git repo
-		string, // owner
-		string, // name
-		string, // path
-		github.RepositoryContentGetOptions, // SHA, branch or tag
+		*RepoContents, // the contents of the directory, shared by all runtimes
 		*BuilderInfo, // paketo
 		*BuilderInfo, // heroku
 	) error
 }
 
+// detectedPackageManager returns the first of the preferred package managers that was
+// found by the detectors of a runtime. The results must be closed.
+func detectedPackageManager(results chan struct {
+	string
+	bool
+}, preferred ...string) string {
+	found := make(map[string]bool)
+
+	for result := range results {
+		found[result.string] = result.bool
+	}
+
+	for _, pm := range preferred {
+		if found[pm] {
+			return pm
+		}
+	}
+
+	return ""
+}
+
 // Runtimes is a list of all API runtimes
 var Runtimes = []Runtime{
 	NewGoRuntime(),
 	NewNodeRuntime(),
 	NewPythonRuntime(),
 	NewRubyRuntime(),
+	NewDenoRuntime(),
+	NewBunRuntime(),
 }
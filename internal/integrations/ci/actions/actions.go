@@ -9,6 +9,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	ghinstallation "github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/google/go-github/v41/github"
+	"github.com/porter-dev/porter/internal/integrations/githubapp"
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/oauth"
 	"github.com/porter-dev/porter/internal/repository"
@@ -34,6 +35,10 @@ type GithubActions struct {
 	GithubAppSecretPath  string
 	GithubInstallationID uint
 
+	// GithubAppTokens caches the access tokens of Github App installations. If it is
+	// nil, a new token is requested for the client of the installation.
+	GithubAppTokens *githubapp.TokenCache
+
 	PorterToken      string
 	BuildEnv         map[string]string
 	ProjectID        uint
@@ -46,6 +51,11 @@ type GithubActions struct {
 	FolderPath     string
 	ImageRepoURL   string
 
+	// GeneratedDockerfile is the contents of a Dockerfile that was generated for an app
+	// without a buildpack. If set, the workflow writes it to DockerFilePath before the app
+	// is built.
+	GeneratedDockerfile string
+
 	defaultBranch string
 	Version       string
 
@@ -191,9 +201,17 @@ func (g *GithubActions) GetGithubActionYAML() ([]byte, error) {
 	gaSteps := []GithubActionYAMLStep{
 		getCheckoutCodeStep(),
 		getSetTagStep(),
-		getUpdateAppStep(g.ServerURL, g.getPorterTokenSecretName(), g.ProjectID, g.ClusterID, g.ReleaseName, g.ReleaseNamespace, g.Version),
 	}
 
+	if g.GeneratedDockerfile != "" {
+		gaSteps = append(gaSteps, getWriteDockerfileStep(g.DockerFilePath, g.GeneratedDockerfile))
+	}
+
+	gaSteps = append(
+		gaSteps,
+		getUpdateAppStep(g.ServerURL, g.getPorterTokenSecretName(), g.ProjectID, g.ClusterID, g.ReleaseName, g.ReleaseNamespace, g.Version),
+	)
+
 	branch := g.GitBranch
 
 	if branch == "" {
@@ -249,6 +267,10 @@ func (g *GithubActions) getClient() (*github.Client, error) {
 	}
 
 	// authenticate as github app installation
+	if g.GithubAppTokens != nil {
+		return g.GithubAppTokens.Client(int64(g.GithubInstallationID)), nil
+	}
+
 	itr, err := ghinstallation.NewKeyFromFile(
 		http.DefaultTransport,
 		g.GithubAppID,
@@ -0,0 +1,67 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func getTestActionSteps(t *testing.T, g *GithubActions) []GithubActionYAMLStep {
+	t.Helper()
+
+	data, err := g.GetGithubActionYAML()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workflow := &GithubActionYAML{}
+
+	if err := yaml.Unmarshal(data, workflow); err != nil {
+		t.Fatal(err)
+	}
+
+	return workflow.Jobs["porter-deploy"].Steps
+}
+
+func TestActionYAMLWritesGeneratedDockerfile(t *testing.T) {
+	steps := getTestActionSteps(t, &GithubActions{
+		ServerURL:           "https://dashboard.getporter.dev",
+		ProjectID:           1,
+		ClusterID:           2,
+		ReleaseName:         "web",
+		GitBranch:           "main",
+		DockerFilePath:      "./app/porter.Dockerfile",
+		GeneratedDockerfile: "FROM oven/bun:1\nCMD [\"bun\",\"run\",\"start\"]\n",
+		Version:             "v0.1.0",
+	})
+
+	if len(steps) != 4 {
+		t.Fatalf("expected 4 steps, got %d", len(steps))
+	}
+
+	// the Dockerfile is written after the code is checked out, and before the app is built
+	assert.Equal(t, "Checkout code", steps[0].Name)
+	assert.Equal(t, "Write generated Dockerfile", steps[2].Name)
+	assert.Equal(t, "./app/porter.Dockerfile", steps[2].Env["DOCKERFILE_PATH"])
+	assert.Equal(t, "FROM oven/bun:1\nCMD [\"bun\",\"run\",\"start\"]\n", steps[2].Env["DOCKERFILE"])
+	assert.Equal(t, "Update Porter App", steps[3].Name)
+}
+
+func TestActionYAMLWithoutGeneratedDockerfile(t *testing.T) {
+	steps := getTestActionSteps(t, &GithubActions{
+		ServerURL:   "https://dashboard.getporter.dev",
+		ProjectID:   1,
+		ClusterID:   2,
+		ReleaseName: "web",
+		GitBranch:   "main",
+		Version:     "v0.1.0",
+	})
+
+	if len(steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(steps))
+	}
+
+	assert.Equal(t, "Update Porter App", steps[2].Name)
+}
@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	"github.com/google/go-github/v41/github"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
 
 	"gopkg.in/yaml.v2"
 )
@@ -18,6 +21,12 @@ type EnvOpts struct {
 	GitRepoOwner, GitRepoName               string
 	EnvironmentName                         string
 	ProjectID, ClusterID, GitInstallationID uint
+
+	// ForkApprovalRequired runs the workflow on pull_request_target, so that pull requests
+	// from forks have access to the Porter token once a maintainer approves them. The
+	// workflow checks that the commit of the pull request was approved before it checks
+	// out the code of the pull request.
+	ForkApprovalRequired bool
 }
 
 func SetupEnv(opts *EnvOpts) error {
@@ -170,8 +179,34 @@ func DeleteEnv(opts *EnvOpts) error {
 }
 
 func getPreviewApplyActionYAML(opts *EnvOpts) ([]byte, error) {
-	gaSteps := []GithubActionYAMLStep{
-		getCheckoutCodeStep(),
+	on := "pull_request"
+	gaSteps := []GithubActionYAMLStep{}
+	checkoutStep := getCheckoutCodeStep()
+
+	if opts.ForkApprovalRequired {
+		// workflows that run on pull_request_target have access to the secrets of the
+		// repository, so the code of the pull request is only checked out once the commit
+		// was approved, and the checkout does not persist the token of the workflow
+		on = "pull_request_target"
+		checkoutStep.With = map[string]string{
+			"ref":                 "${{ github.event.pull_request.head.sha }}",
+			"persist-credentials": "false",
+		}
+
+		gaSteps = append(gaSteps, getCheckPreviewApprovalStep(
+			opts.ServerURL,
+			getPorterTokenSecretName(opts.ProjectID),
+			opts.ProjectID,
+			opts.ClusterID,
+			opts.GitInstallationID,
+			opts.GitRepoOwner,
+			opts.GitRepoName,
+		))
+	}
+
+	gaSteps = append(
+		gaSteps,
+		checkoutStep,
 		getCreatePreviewEnvStep(
 			opts.ServerURL,
 			getPorterTokenSecretName(opts.ProjectID),
@@ -181,10 +216,10 @@ func getPreviewApplyActionYAML(opts *EnvOpts) ([]byte, error) {
 			opts.GitRepoName,
 			"v0.1.0",
 		),
-	}
+	)
 
 	actionYAML := GithubActionYAML{
-		On:   []string{"pull_request"},
+		On:   []string{on},
 		Name: "Porter Preview Environment",
 		Jobs: map[string]GithubActionYAMLJob{
 			"porter-preview": {
@@ -227,3 +262,91 @@ func getPreviewDeleteActionYAML(opts *EnvOpts) ([]byte, error) {
 
 	return yaml.Marshal(actionYAML)
 }
+
+// ApprovePreviewDeploymentOpts are the options to approve the preview deployment of a pull
+// request from a fork
+type ApprovePreviewDeploymentOpts struct {
+	Client     *github.Client
+	Repo       repository.Repository
+	Env        *models.Environment
+	Deployment *models.Deployment
+	Approver   string
+}
+
+// ApprovePreviewDeployment approves the commit of a preview deployment that is pending
+// approval, and re-runs the workflow run that requested it, which then creates the
+// deployment. Commits that are pushed after the approval need to be approved again.
+func ApprovePreviewDeployment(opts *ApprovePreviewDeploymentOpts) (*models.Deployment, error) {
+	depl := opts.Deployment
+
+	if depl.Status != types.DeploymentStatusPendingApproval {
+		return nil, fmt.Errorf("deployment is not pending approval")
+	}
+
+	if depl.CommitSHA == "" {
+		return nil, fmt.Errorf("deployment has no commit to approve")
+	}
+
+	depl.ApprovedBy = opts.Approver
+	depl.ApprovedCommitSHA = depl.CommitSHA
+
+	depl, err := opts.Repo.Environment().UpdateDeployment(depl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf(
+		"The preview deployment of commit %s of this pull request was approved by %s.",
+		depl.ApprovedCommitSHA,
+		opts.Approver,
+	)
+
+	if depl.GHActionID != 0 {
+		_, err = opts.Client.Actions.RerunWorkflowByID(
+			context.Background(),
+			opts.Env.GitRepoOwner,
+			opts.Env.GitRepoName,
+			int64(depl.GHActionID),
+		)
+	}
+
+	if depl.GHActionID == 0 || err != nil {
+		body += " Re-run the Porter Preview Environment workflow to deploy it."
+	}
+
+	_, _, err = opts.Client.Issues.CreateComment(
+		context.Background(),
+		opts.Env.GitRepoOwner,
+		opts.Env.GitRepoName,
+		int(depl.PullRequestID),
+		&github.IssueComment{
+			Body: github.String(body),
+		},
+	)
+
+	return depl, err
+}
+
+// IsForkPullRequest returns true if the head of a pull request is in a different repository
+// than its base
+func IsForkPullRequest(client *github.Client, env *models.Environment, prNumber uint) (bool, error) {
+	pr, _, err := client.PullRequests.Get(
+		context.Background(),
+		env.GitRepoOwner,
+		env.GitRepoName,
+		int(prNumber),
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return pr.GetHead().GetRepo().GetFullName() != pr.GetBase().GetRepo().GetFullName(), nil
+}
+
+// IsDeploymentApproved returns true if a maintainer approved the commit of a deployment of
+// a pull request from a fork
+func IsDeploymentApproved(depl *models.Deployment, commitSHA string) bool {
+	return depl != nil && depl.ApprovedBy != "" && commitSHA != "" && depl.ApprovedCommitSHA == commitSHA
+}
@@ -0,0 +1,125 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPreviewApplyActionYAMLForkApproval(t *testing.T) {
+	data, err := getPreviewApplyActionYAML(&EnvOpts{
+		ServerURL:            "https://dashboard.getporter.dev",
+		GitRepoOwner:         "porter-dev",
+		GitRepoName:          "porter",
+		ProjectID:            1,
+		ClusterID:            2,
+		GitInstallationID:    3,
+		ForkApprovalRequired: true,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workflow := &GithubActionYAML{}
+
+	if err := yaml.Unmarshal(data, workflow); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []interface{}{"pull_request_target"}, workflow.On)
+
+	steps := workflow.Jobs["porter-preview"].Steps
+
+	if len(steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(steps))
+	}
+
+	// the approval of the commit is checked before the code of the pull request is
+	// checked out
+	assert.Equal(t, "Check Porter preview approval", steps[0].Name)
+	assert.Contains(t, steps[0].Run, "/api/projects/$PORTER_PROJECT/gitrepos/$INSTALLATION_ID/$REPO_OWNER/$REPO_NAME/clusters/$PORTER_CLUSTER/deployment/approval")
+	assert.Equal(t, "${{ github.event.pull_request.head.sha }}", steps[0].Env["COMMIT_SHA"])
+	assert.NotContains(t, steps[0].Run, "${{", "event values should not be interpolated into the script")
+
+	assert.Equal(t, "Checkout code", steps[1].Name)
+	assert.Equal(t, "${{ github.event.pull_request.head.sha }}", steps[1].With["ref"])
+	assert.Equal(t, "false", steps[1].With["persist-credentials"])
+}
+
+func TestPreviewApplyActionYAMLWithoutForkApproval(t *testing.T) {
+	data, err := getPreviewApplyActionYAML(&EnvOpts{
+		ServerURL:   "https://dashboard.getporter.dev",
+		GitRepoName: "porter",
+		ProjectID:   1,
+		ClusterID:   2,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workflow := &GithubActionYAML{}
+
+	if err := yaml.Unmarshal(data, workflow); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []interface{}{"pull_request"}, workflow.On)
+
+	steps := workflow.Jobs["porter-preview"].Steps
+
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(steps))
+	}
+
+	assert.Equal(t, "Checkout code", steps[0].Name)
+	assert.Empty(t, steps[0].With)
+}
+
+func TestIsDeploymentApproved(t *testing.T) {
+	tests := []struct {
+		name      string
+		depl      *models.Deployment
+		commitSHA string
+		expected  bool
+	}{
+		{
+			name:      "no deployment",
+			commitSHA: "abc",
+			expected:  false,
+		},
+		{
+			name:      "not approved",
+			depl:      &models.Deployment{CommitSHA: "abc"},
+			commitSHA: "abc",
+			expected:  false,
+		},
+		{
+			name:      "approved commit",
+			depl:      &models.Deployment{ApprovedBy: "maintainer", ApprovedCommitSHA: "abc"},
+			commitSHA: "abc",
+			expected:  true,
+		},
+		{
+			name:      "commit pushed after the approval",
+			depl:      &models.Deployment{ApprovedBy: "maintainer", ApprovedCommitSHA: "abc"},
+			commitSHA: "def",
+			expected:  false,
+		},
+		{
+			name:      "empty commit",
+			depl:      &models.Deployment{ApprovedBy: "maintainer"},
+			commitSHA: "",
+			expected:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsDeploymentApproved(test.depl, test.commitSHA))
+		})
+	}
+}
@@ -23,6 +23,20 @@ func getSetTagStep() GithubActionYAMLStep {
 	}
 }
 
+// getWriteDockerfileStep writes a generated Dockerfile to the checked out code. The
+// contents are passed through the env of the step, so that they are not interpreted by
+// the shell.
+func getWriteDockerfileStep(dockerfilePath, dockerfile string) GithubActionYAMLStep {
+	return GithubActionYAMLStep{
+		Name: "Write generated Dockerfile",
+		Run:  `printf '%s' "$DOCKERFILE" > "$DOCKERFILE_PATH"`,
+		Env: map[string]string{
+			"DOCKERFILE":      dockerfile,
+			"DOCKERFILE_PATH": dockerfilePath,
+		},
+	}
+}
+
 func getUpdateAppStep(serverURL, porterTokenSecretName string, projectID uint, clusterID uint, appName string, appNamespace, actionVersion string) GithubActionYAMLStep {
 	return GithubActionYAMLStep{
 		Name: "Update Porter App",
@@ -62,6 +76,34 @@ func getCreatePreviewEnvStep(serverURL, porterTokenSecretName string, projectID,
 	}
 }
 
+// getCheckPreviewApprovalStep fails the workflow if the commit of a pull request from a
+// fork was not approved yet. It runs before the code of the pull request is checked out,
+// and passes the event values through the env so that they are not interpolated into the
+// script.
+func getCheckPreviewApprovalStep(serverURL, porterTokenSecretName string, projectID, clusterID, gitInstallationID uint, repoOwner, repoName string) GithubActionYAMLStep {
+	return GithubActionYAMLStep{
+		Name: "Check Porter preview approval",
+		Run: `curl --fail-with-body -sS -X POST \
+  -H "Authorization: Bearer $PORTER_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d "{\"namespace\":\"pr-$PR_ID-$REPO_NAME\",\"pull_request_id\":$PR_ID,\"commit_sha\":\"$COMMIT_SHA\",\"action_id\":$ACTION_ID}" \
+  "$PORTER_HOST/api/projects/$PORTER_PROJECT/gitrepos/$INSTALLATION_ID/$REPO_OWNER/$REPO_NAME/clusters/$PORTER_CLUSTER/deployment/approval"`,
+		Env: map[string]string{
+			"PORTER_HOST":     serverURL,
+			"PORTER_PROJECT":  fmt.Sprintf("%d", projectID),
+			"PORTER_CLUSTER":  fmt.Sprintf("%d", clusterID),
+			"PORTER_TOKEN":    fmt.Sprintf("${{ secrets.%s }}", porterTokenSecretName),
+			"INSTALLATION_ID": fmt.Sprintf("%d", gitInstallationID),
+			"REPO_OWNER":      repoOwner,
+			"REPO_NAME":       repoName,
+			"PR_ID":           "${{ github.event.pull_request.number }}",
+			"COMMIT_SHA":      "${{ github.event.pull_request.head.sha }}",
+			"ACTION_ID":       "${{ github.run_id }}",
+		},
+		Timeout: 5,
+	}
+}
+
 func getDeletePreviewEnvStep(serverURL, porterTokenSecretName string, projectID, clusterID, gitInstallationID uint, repoName, actionVersion string) GithubActionYAMLStep {
 	return GithubActionYAMLStep{
 		Name: "Delete Porter preview env",
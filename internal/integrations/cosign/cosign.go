@@ -0,0 +1,457 @@
+package cosign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/porter-dev/porter/api/server/shared/config/env"
+)
+
+// The annotations of the layers of cosign signature manifests
+const (
+	signatureAnnotation   = "dev.cosignproject.cosign/signature"
+	certificateAnnotation = "dev.sigstore.cosign/certificate"
+	chainAnnotation       = "dev.sigstore.cosign/chain"
+	bundleAnnotation      = "dev.sigstore.cosign/bundle"
+)
+
+// The extensions of Fulcio certificates that contain the OIDC issuer of the identity
+var (
+	issuerExtensionOID   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	issuerV2ExtensionOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// Authority is a signer of the images of a project, which is either a public key, or a
+// keyless identity with the OIDC issuer and subject of the Fulcio certificates of its
+// signatures
+type Authority struct {
+	Name      string
+	PublicKey string
+	Issuer    string
+	Subject   string
+}
+
+// IsKeyless returns true if the authority is a keyless identity
+func (a *Authority) IsKeyless() bool {
+	return a.PublicKey == ""
+}
+
+// Credentials are the credentials of a registry, which are used to read the signatures of
+// private images
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Verifier verifies the cosign signatures of images, which are stored in the registry of
+// each image under the sha256-<digest>.sig tag. Keyless signatures are only verified if
+// the root certificates of Fulcio and the public key of Rekor are set, since the
+// certificates of keyless signatures are verified at the time that the transparency log
+// signed.
+type Verifier struct {
+	fulcioRoots *x509.CertPool
+	rekorKey    crypto.PublicKey
+
+	httpClient *http.Client
+	scheme     string
+}
+
+// NewVerifier creates a verifier with optional PEM-encoded Fulcio root certificates and
+// Rekor public key. Keyless signatures are only enabled if both are set.
+func NewVerifier(fulcioRoots, rekorPublicKey string) (*Verifier, error) {
+	res := &Verifier{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		scheme: "https",
+	}
+
+	if fulcioRoots != "" {
+		res.fulcioRoots = x509.NewCertPool()
+
+		if !res.fulcioRoots.AppendCertsFromPEM([]byte(fulcioRoots)) {
+			return nil, fmt.Errorf("could not parse Fulcio root certificates")
+		}
+	}
+
+	if rekorPublicKey != "" {
+		key, err := parsePublicKey(rekorPublicKey)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not parse Rekor public key: %w", err)
+		}
+
+		res.rekorKey = key
+	}
+
+	return res, nil
+}
+
+// NewFromConf returns the verifier of the server
+func NewFromConf(sc *env.ServerConf) (*Verifier, error) {
+	return NewVerifier(sc.CosignFulcioRoots, sc.CosignRekorPublicKey)
+}
+
+// ValidateAuthority returns an error if the public key of an authority cannot be parsed,
+// or if it is a keyless identity and keyless signatures are not supported
+func (v *Verifier) ValidateAuthority(authority *Authority) error {
+	if !authority.IsKeyless() {
+		_, err := parsePublicKey(authority.PublicKey)
+
+		return err
+	}
+
+	if authority.Issuer == "" || authority.Subject == "" {
+		return fmt.Errorf("keyless identities must set an issuer and a subject")
+	}
+
+	if v == nil || v.fulcioRoots == nil || v.rekorKey == nil {
+		return fmt.Errorf("keyless signatures are not enabled on this instance")
+	}
+
+	return nil
+}
+
+// VerifyImage returns the digest of an image if it has a valid signature by one of the
+// authorities. Images should be deployed by the returned digest, since the tag of the
+// image can be moved to another image after it is verified. The credentials of registries
+// are keyed by the host of the registry.
+func (v *Verifier) VerifyImage(image string, authorities []*Authority, creds map[string]*Credentials) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+
+	if err != nil {
+		return "", fmt.Errorf("invalid image %s: %w", image, err)
+	}
+
+	host := reference.Domain(named)
+	apiHost := host
+
+	// the registry API of Docker Hub is served from another host than the host of images
+	if host == "docker.io" {
+		apiHost = "registry-1.docker.io"
+	}
+
+	client := &registryClient{
+		httpClient: v.httpClient,
+		scheme:     v.scheme,
+		host:       apiHost,
+		repository: reference.Path(named),
+		creds:      creds[host],
+	}
+
+	ref := "latest"
+
+	if digested, ok := named.(reference.Digested); ok {
+		ref = digested.Digest().String()
+	} else if tagged, ok := named.(reference.Tagged); ok {
+		ref = tagged.Tag()
+	}
+
+	_, digest, err := client.getManifest(ref)
+
+	if err == errNotFound {
+		return "", fmt.Errorf("image %s not found", image)
+	} else if err != nil {
+		return "", fmt.Errorf("could not read image %s: %w", image, err)
+	} else if strings.HasPrefix(ref, "sha256:") && digest != ref {
+		return "", fmt.Errorf("content of image %s does not match its digest", image)
+	}
+
+	sigManifestBytes, _, err := client.getManifest(strings.Replace(digest, ":", "-", 1) + ".sig")
+
+	if err == errNotFound {
+		return "", fmt.Errorf("image %s is not signed", image)
+	} else if err != nil {
+		return "", fmt.Errorf("could not read signatures of image %s: %w", image, err)
+	}
+
+	sigManifest := &manifest{}
+
+	if err := json.Unmarshal(sigManifestBytes, sigManifest); err != nil {
+		return "", fmt.Errorf("could not read signatures of image %s: %w", image, err)
+	}
+
+	for _, layer := range sigManifest.Layers {
+		sig, err := base64.StdEncoding.DecodeString(layer.Annotations[signatureAnnotation])
+
+		if err != nil || len(sig) == 0 || layer.Size > maxRegistryResponseSize {
+			continue
+		}
+
+		payload, err := client.getBlob(layer.Digest)
+
+		if err != nil || !payloadMatchesDigest(payload, digest) {
+			continue
+		}
+
+		for _, authority := range authorities {
+			if v.verifyLayer(authority, payload, sig, layer.Annotations) == nil {
+				return digest, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("image %s is not signed by a signing authority of the project", image)
+}
+
+// payloadMatchesDigest returns true if a simple signing payload is the payload of a
+// signature of the image with a digest
+func payloadMatchesDigest(payload []byte, digest string) bool {
+	simpleSigning := &struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+			Type string `json:"type"`
+		} `json:"critical"`
+	}{}
+
+	if err := json.Unmarshal(payload, simpleSigning); err != nil {
+		return false
+	}
+
+	return simpleSigning.Critical.Type == "cosign container image signature" &&
+		simpleSigning.Critical.Image.DockerManifestDigest == digest
+}
+
+func (v *Verifier) verifyLayer(authority *Authority, payload, sig []byte, annotations map[string]string) error {
+	if !authority.IsKeyless() {
+		key, err := parsePublicKey(authority.PublicKey)
+
+		if err != nil {
+			return err
+		}
+
+		return verifySignature(key, payload, sig)
+	}
+
+	if v.fulcioRoots == nil || v.rekorKey == nil {
+		return fmt.Errorf("keyless signatures are not enabled")
+	}
+
+	cert, err := parseCertificate(annotations[certificateAnnotation])
+
+	if err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM([]byte(annotations[chainAnnotation]))
+
+	// Fulcio certificates are only valid for a few minutes, so they are verified at the
+	// time that the signature was added to the transparency log
+	integratedTime, err := v.verifyBundle(annotations[bundleAnnotation], payload, sig, cert)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:         v.fulcioRoots,
+		Intermediates: intermediates,
+		CurrentTime:   integratedTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if !certMatchesIdentity(cert, authority.Issuer, authority.Subject) {
+		return fmt.Errorf("certificate does not match the identity of the authority")
+	}
+
+	return verifySignature(cert.PublicKey, payload, sig)
+}
+
+// verifyBundle returns the time that a signature was added to the transparency log. The
+// time is only trusted once the signed entry timestamp of the bundle is verified with the
+// Rekor public key, so bundles are never accepted without the key: an unverified time
+// would let an expired certificate verify forever.
+func (v *Verifier) verifyBundle(bundleJSON string, payload, sig []byte, cert *x509.Certificate) (time.Time, error) {
+	bundle := &struct {
+		SignedEntryTimestamp string `json:"SignedEntryTimestamp"`
+		Payload              struct {
+			Body           string `json:"body"`
+			IntegratedTime int64  `json:"integratedTime"`
+			LogIndex       int64  `json:"logIndex"`
+			LogID          string `json:"logID"`
+		} `json:"Payload"`
+	}{}
+
+	if bundleJSON == "" {
+		return time.Time{}, fmt.Errorf("keyless signature has no transparency log bundle")
+	}
+
+	if err := json.Unmarshal([]byte(bundleJSON), bundle); err != nil {
+		return time.Time{}, err
+	}
+
+	if v.rekorKey == nil {
+		return time.Time{}, fmt.Errorf("transparency log bundles cannot be verified without the Rekor public key")
+	}
+
+	// the signed entry timestamp signs the canonical json of the payload, whose keys are
+	// sorted
+	canonical, err := json.Marshal(map[string]interface{}{
+		"body":           bundle.Payload.Body,
+		"integratedTime": bundle.Payload.IntegratedTime,
+		"logIndex":       bundle.Payload.LogIndex,
+		"logID":          bundle.Payload.LogID,
+	})
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	set, err := base64.StdEncoding.DecodeString(bundle.SignedEntryTimestamp)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := verifySignature(v.rekorKey, canonical, set); err != nil {
+		return time.Time{}, fmt.Errorf("invalid transparency log bundle: %w", err)
+	}
+
+	if err := entryMatchesSignature(bundle.Payload.Body, payload, sig); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(bundle.Payload.IntegratedTime, 0), nil
+}
+
+// entryMatchesSignature returns an error if a hashedrekord entry of the transparency log
+// is not the entry of a signature of a payload
+func entryMatchesSignature(body string, payload, sig []byte) error {
+	bodyBytes, err := base64.StdEncoding.DecodeString(body)
+
+	if err != nil {
+		return err
+	}
+
+	entry := &struct {
+		Spec struct {
+			Signature struct {
+				Content string `json:"content"`
+			} `json:"signature"`
+			Data struct {
+				Hash struct {
+					Value string `json:"value"`
+				} `json:"hash"`
+			} `json:"data"`
+		} `json:"spec"`
+	}{}
+
+	if err := json.Unmarshal(bodyBytes, entry); err != nil {
+		return err
+	}
+
+	entrySig, err := base64.StdEncoding.DecodeString(entry.Spec.Signature.Content)
+
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(payload)
+
+	if !bytes.Equal(entrySig, sig) || entry.Spec.Data.Hash.Value != hex.EncodeToString(sum[:]) {
+		return fmt.Errorf("transparency log entry does not match the signature")
+	}
+
+	return nil
+}
+
+// certMatchesIdentity returns true if a Fulcio certificate was issued for a subject, which
+// is an email or URI, by an OIDC issuer
+func certMatchesIdentity(cert *x509.Certificate, issuer, subject string) bool {
+	certIssuer := ""
+
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(issuerV2ExtensionOID) {
+			var value string
+
+			if _, err := asn1.Unmarshal(ext.Value, &value); err == nil {
+				certIssuer = value
+			}
+		} else if ext.Id.Equal(issuerExtensionOID) && certIssuer == "" {
+			certIssuer = string(ext.Value)
+		}
+	}
+
+	if certIssuer != issuer {
+		return false
+	}
+
+	for _, email := range cert.EmailAddresses {
+		if email == subject {
+			return true
+		}
+	}
+
+	for _, uri := range cert.URIs {
+		if uri.String() == subject {
+			return true
+		}
+	}
+
+	return false
+}
+
+func verifySignature(key crypto.PublicKey, payload, sig []byte) error {
+	digest := sha256.Sum256(payload)
+
+	switch typed := key.(type) {
+	case *ecdsa.PublicKey:
+		if ecdsa.VerifyASN1(typed, digest[:], sig) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(typed, crypto.SHA256, digest[:], sig) == nil {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(typed, payload, sig) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+
+	return fmt.Errorf("invalid signature")
+}
+
+func parsePublicKey(pemKey string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(pemKey)))
+
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("public key must be a PEM-encoded PUBLIC KEY block, such as a cosign.pub file")
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func parseCertificate(pemCert string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(pemCert))
+
+	if block == nil {
+		return nil, fmt.Errorf("keyless signature has no certificate")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
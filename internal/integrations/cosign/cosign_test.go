@@ -0,0 +1,133 @@
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyImageReturnsDigest(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKeyBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authority := &Authority{
+		Name:      "ci",
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubKeyBytes})),
+	}
+
+	imageManifest := []byte(`{"schemaVersion":2}`)
+	imageDigest := getTestDigest(imageManifest)
+
+	payload := []byte(fmt.Sprintf(
+		`{"critical":{"identity":{"docker-reference":"app"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"}}`,
+		imageDigest,
+	))
+
+	payloadSum := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, payloadSum[:])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sigManifest, err := json.Marshal(map[string]interface{}{
+		"layers": []interface{}{
+			map[string]interface{}{
+				"mediaType": "application/vnd.dev.cosign.simplesigning.v1+json",
+				"digest":    getTestDigest(payload),
+				"size":      len(payload),
+				"annotations": map[string]string{
+					signatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/app/manifests/v1":
+			w.Write(imageManifest)
+		case "/v2/app/manifests/" + strings.Replace(imageDigest, ":", "-", 1) + ".sig":
+			w.Write(sigManifest)
+		case "/v2/app/blobs/" + getTestDigest(payload):
+			w.Write(payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	verifier := &Verifier{
+		httpClient: server.Client(),
+		scheme:     "http",
+	}
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	digest, err := verifier.VerifyImage(host+"/app:v1", []*Authority{authority}, nil)
+
+	if err != nil {
+		t.Fatalf("expected image to be verified, got %v", err)
+	}
+
+	if digest != imageDigest {
+		t.Errorf("expected digest %s, got %s", imageDigest, digest)
+	}
+
+	if _, err := verifier.VerifyImage(host+"/app:v2", []*Authority{authority}, nil); err == nil {
+		t.Errorf("expected image that does not exist not to be verified")
+	}
+}
+
+func TestVerifyBundleRequiresRekorKey(t *testing.T) {
+	verifier := &Verifier{
+		fulcioRoots: x509.NewCertPool(),
+	}
+
+	_, err := verifier.verifyBundle(`{"Payload":{"integratedTime":1600000000}}`, []byte("payload"), []byte("sig"), nil)
+
+	if err == nil {
+		t.Fatalf("expected bundle not to be trusted without the Rekor public key")
+	}
+
+	err = verifier.ValidateAuthority(&Authority{
+		Name:    "ci",
+		Issuer:  "https://token.actions.githubusercontent.com",
+		Subject: "https://github.com/porter-dev/porter/.github/workflows/release.yml@refs/heads/master",
+	})
+
+	if err == nil {
+		t.Fatalf("expected keyless authorities to be rejected without the Rekor public key")
+	}
+}
+
+func getTestDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,81 @@
+package cosign
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ClusterImagePolicyResource is the resource of the ClusterImagePolicy CRD of the sigstore
+// policy-controller, which verifies the signatures of the images of pods on admission
+var ClusterImagePolicyResource = schema.GroupVersionResource{
+	Group:    "policy.sigstore.dev",
+	Version:  "v1beta1",
+	Resource: "clusterimagepolicies",
+}
+
+// GetClusterImagePolicy returns a ClusterImagePolicy that requires the images of a project
+// to be signed by one of its authorities. The policy-controller only verifies the pods of
+// namespaces with the policy.sigstore.dev/include=true label, and only warns about
+// unsigned images unless the policy is enforced. Keyless identities are verified against
+// the public Fulcio instance.
+func GetClusterImagePolicy(projectID uint, authorities []*Authority, enforce bool) *unstructured.Unstructured {
+	policyAuthorities := make([]interface{}, 0)
+
+	for _, authority := range authorities {
+		if authority.IsKeyless() {
+			policyAuthorities = append(policyAuthorities, map[string]interface{}{
+				"name": authority.Name,
+				"keyless": map[string]interface{}{
+					"identities": []interface{}{
+						map[string]interface{}{
+							"issuer":  authority.Issuer,
+							"subject": authority.Subject,
+						},
+					},
+				},
+			})
+		} else {
+			policyAuthorities = append(policyAuthorities, map[string]interface{}{
+				"name": authority.Name,
+				"key": map[string]interface{}{
+					"data": authority.PublicKey,
+				},
+			})
+		}
+	}
+
+	mode := "warn"
+
+	if enforce {
+		mode = "enforce"
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": ClusterImagePolicyResource.GroupVersion().String(),
+			"kind":       "ClusterImagePolicy",
+			"metadata": map[string]interface{}{
+				"name": GetClusterImagePolicyName(projectID),
+				"labels": map[string]interface{}{
+					"porter.run/project": fmt.Sprintf("%d", projectID),
+				},
+			},
+			"spec": map[string]interface{}{
+				"mode": mode,
+				"images": []interface{}{
+					map[string]interface{}{
+						"glob": "**",
+					},
+				},
+				"authorities": policyAuthorities,
+			},
+		},
+	}
+}
+
+// GetClusterImagePolicyName returns the name of the ClusterImagePolicy of a project
+func GetClusterImagePolicyName(projectID uint) string {
+	return fmt.Sprintf("porter-project-%d-image-signatures", projectID)
+}
@@ -0,0 +1,208 @@
+package cosign
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxRegistryResponseSize limits the size of the manifests and signature payloads that are
+// read from registries
+const maxRegistryResponseSize = 4 << 20
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}
+
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// registryClient reads the manifests and blobs of a repository with the Docker Registry
+// HTTP API, which every registry that cosign supports implements
+type registryClient struct {
+	httpClient *http.Client
+	scheme     string
+	host       string
+	repository string
+	creds      *Credentials
+
+	token string
+}
+
+type manifest struct {
+	Layers []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Size        int64             `json:"size"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// getManifest returns a manifest of the repository and its digest. Manifests that do not
+// exist return errNotFound.
+func (c *registryClient) getManifest(ref string) ([]byte, string, error) {
+	body, err := c.get(fmt.Sprintf("/v2/%s/manifests/%s", c.repository, ref), manifestMediaTypes)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256(body)
+
+	return body, "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// getBlob returns a blob of the repository, after checking that its content matches its
+// digest
+func (c *registryClient) getBlob(digest string) ([]byte, error) {
+	body, err := c.get(fmt.Sprintf("/v2/%s/blobs/%s", c.repository, digest), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, fmt.Errorf("content of blob %s does not match its digest", digest)
+	}
+
+	return body, nil
+}
+
+var errNotFound = fmt.Errorf("not found")
+
+func (c *registryClient) get(path string, accept []string) ([]byte, error) {
+	resp, err := c.do(path, accept)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// registries respond with a bearer challenge to requests without a token, which is
+	// answered once with the credentials of the registry
+	if resp.StatusCode == http.StatusUnauthorized && c.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		if err := c.authenticate(challenge); err != nil {
+			return nil, err
+		}
+
+		resp, err = c.do(path, accept)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry %s returned status code %d", c.host, resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxRegistryResponseSize))
+}
+
+func (c *registryClient) do(path string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s://%s%s", c.scheme, c.host, path), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.creds != nil {
+		req.SetBasicAuth(c.creds.Username, c.creds.Password)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// authenticate requests a pull token from the token server of a bearer challenge
+func (c *registryClient) authenticate(challenge string) error {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return fmt.Errorf("could not authenticate with registry %s", c.host)
+	}
+
+	params := make(map[string]string)
+
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("invalid authentication challenge of registry %s", c.host)
+	}
+
+	query := tokenURL.Query()
+
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", c.repository))
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, tokenURL.String(), nil)
+
+	if err != nil {
+		return err
+	}
+
+	if c.creds != nil {
+		req.SetBasicAuth(c.creds.Username, c.creds.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not authenticate with registry %s: status code %d", c.host, resp.StatusCode)
+	}
+
+	tokenResp := &struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(tokenResp); err != nil {
+		return err
+	}
+
+	c.token = tokenResp.Token
+
+	if c.token == "" {
+		c.token = tokenResp.AccessToken
+	}
+
+	if c.token == "" {
+		return fmt.Errorf("could not authenticate with registry %s", c.host)
+	}
+
+	return nil
+}
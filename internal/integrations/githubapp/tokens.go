@@ -0,0 +1,205 @@
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	ghinstallation "github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v41/github"
+	"github.com/porter-dev/porter/internal/logger"
+	"github.com/porter-dev/porter/internal/oauth"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// tokenRefreshBefore is how long before its expiry a token is refreshed, so that a token
+// does not expire while it is used by a request
+const tokenRefreshBefore = 5 * time.Minute
+
+// TokenCache caches the access tokens of GitHub app installations in memory and in the
+// DB, so that handlers that call GitHub as an installation share tokens instead of
+// requesting a new token from GitHub on every request. Tokens that are cached in the DB
+// are shared between instances of the server.
+type TokenCache struct {
+	repo   repository.GithubAppInstallationRepository
+	logger *logger.Logger
+
+	// createToken requests a new token of an installation from GitHub
+	createToken func(ctx context.Context, installationID int64) (string, time.Time, error)
+
+	mu     sync.Mutex
+	tokens map[int64]*cachedToken
+}
+
+type cachedToken struct {
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+
+	// rejected is the last token of the installation that GitHub rejected, which is not
+	// read from the DB again if it could not be removed from the DB
+	rejected string
+}
+
+// NewTokenCache creates a token cache that authenticates as the GitHub app of the config
+func NewTokenCache(
+	repo repository.GithubAppInstallationRepository,
+	conf *oauth.GithubAppConf,
+	logger *logger.Logger,
+) (*TokenCache, error) {
+	appsTransport, err := ghinstallation.NewAppsTransportKeyFromFile(
+		http.DefaultTransport,
+		conf.AppID,
+		conf.SecretPath,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	appClient := github.NewClient(&http.Client{Transport: appsTransport})
+
+	return &TokenCache{
+		repo:   repo,
+		logger: logger,
+		createToken: func(ctx context.Context, installationID int64) (string, time.Time, error) {
+			token, _, err := appClient.Apps.CreateInstallationToken(ctx, installationID, nil)
+
+			if err != nil {
+				return "", time.Time{}, err
+			}
+
+			return token.GetToken(), token.GetExpiresAt(), nil
+		},
+		tokens: make(map[int64]*cachedToken),
+	}, nil
+}
+
+// Token returns a valid access token of an installation. The token is read from memory,
+// then from the DB, and is only requested from GitHub if neither holds a token that is
+// valid for longer than tokenRefreshBefore.
+func (c *TokenCache) Token(ctx context.Context, installationID int64) (string, error) {
+	entry := c.getEntry(installationID)
+
+	// concurrent requests for the token of an installation wait for a single refresh
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if isValid(entry.expiresAt) {
+		return entry.token, nil
+	}
+
+	ga, err := c.repo.ReadGithubAppInstallationByInstallationID(uint(installationID))
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.logger.Warn().Err(err).Int64("installation_id", installationID).Msg("could not read cached github app installation token")
+	}
+
+	if err == nil && len(ga.AccessToken) > 0 && string(ga.AccessToken) != entry.rejected &&
+		ga.AccessTokenExpiry != nil && isValid(*ga.AccessTokenExpiry) {
+		entry.token = string(ga.AccessToken)
+		entry.expiresAt = *ga.AccessTokenExpiry
+
+		return entry.token, nil
+	}
+
+	token, expiresAt, err := c.createToken(ctx, installationID)
+
+	if err != nil {
+		return "", err
+	}
+
+	entry.token = token
+	entry.expiresAt = expiresAt
+
+	// a token that cannot be written to the DB is still cached in memory, and other
+	// instances of the server request their own token
+	if err := c.repo.UpdateGithubAppInstallationToken(installationID, []byte(token), expiresAt); err != nil {
+		c.logger.Warn().Err(err).Int64("installation_id", installationID).Msg("could not store github app installation token")
+	}
+
+	return token, nil
+}
+
+// Invalidate drops a cached token of an installation that GitHub rejected, such as when
+// the installation was removed or its permissions changed. Tokens that were already
+// replaced by a new token are not dropped.
+func (c *TokenCache) Invalidate(installationID int64, token string) error {
+	entry := c.getEntry(installationID)
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	entry.rejected = token
+
+	if entry.token != token {
+		return nil
+	}
+
+	entry.token = ""
+	entry.expiresAt = time.Time{}
+
+	return c.repo.UpdateGithubAppInstallationToken(installationID, nil, time.Time{})
+}
+
+// Client returns a GitHub client that authenticates as an installation with the cached
+// token of the installation
+func (c *TokenCache) Client(installationID int64) *github.Client {
+	return github.NewClient(&http.Client{
+		Transport: &tokenTransport{
+			cache:          c,
+			installationID: installationID,
+			base:           http.DefaultTransport,
+		},
+	})
+}
+
+func (c *TokenCache) getEntry(installationID int64) *cachedToken {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.tokens[installationID]
+
+	if !ok {
+		entry = &cachedToken{}
+		c.tokens[installationID] = entry
+	}
+
+	return entry
+}
+
+func isValid(expiresAt time.Time) bool {
+	return time.Now().Add(tokenRefreshBefore).Before(expiresAt)
+}
+
+// tokenTransport sets the cached token of an installation on each request
+type tokenTransport struct {
+	cache          *TokenCache
+	installationID int64
+	base           http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.cache.Token(req.Context(), t.installationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// round trippers must not modify the request
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+token)
+
+	resp, err := t.base.RoundTrip(req)
+
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		if err := t.cache.Invalidate(t.installationID, token); err != nil {
+			t.cache.logger.Warn().Err(err).Int64("installation_id", t.installationID).Msg("could not remove rejected github app installation token")
+		}
+	}
+
+	return resp, err
+}
@@ -0,0 +1,235 @@
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/logger"
+	ints "github.com/porter-dev/porter/internal/models/integrations"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+// failingTokenRepository fails to store tokens, such as when the DB is unavailable
+type failingTokenRepository struct {
+	repository.GithubAppInstallationRepository
+}
+
+func (repo *failingTokenRepository) UpdateGithubAppInstallationToken(installationID int64, token []byte, expiry time.Time) error {
+	return errors.New("cannot write database")
+}
+
+func newTestTokenRepository(t *testing.T) repository.GithubAppInstallationRepository {
+	repo := test.NewRepository(true).GithubAppInstallation()
+
+	if _, err := repo.CreateGithubAppInstallation(&ints.GithubAppInstallation{InstallationID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	return repo
+}
+
+// newTestTokenCache returns a token cache whose tokens are numbered in the order in which
+// they are requested from GitHub
+func newTestTokenCache(repo repository.GithubAppInstallationRepository) (*TokenCache, func() int) {
+	var mu sync.Mutex
+	created := 0
+
+	cache := &TokenCache{
+		repo:   repo,
+		logger: logger.NewConsole(false),
+		createToken: func(ctx context.Context, installationID int64) (string, time.Time, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			created++
+
+			return fmt.Sprintf("token-%d", created), time.Now().Add(time.Hour), nil
+		},
+		tokens: make(map[int64]*cachedToken),
+	}
+
+	return cache, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return created
+	}
+}
+
+func TestTokenIsCached(t *testing.T) {
+	repo := newTestTokenRepository(t)
+	cache, created := newTestTokenCache(repo)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if token, err := cache.Token(context.Background(), 1); err != nil || token != "token-1" {
+				t.Errorf("expected token-1, got %s: %v", token, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if created() != 1 {
+		t.Errorf("expected a single token to be requested from GitHub, got %d", created())
+	}
+
+	// other instances of the server read the token from the DB
+	other, otherCreated := newTestTokenCache(repo)
+
+	if token, err := other.Token(context.Background(), 1); err != nil || token != "token-1" {
+		t.Errorf("expected the token of the DB, got %s: %v", token, err)
+	}
+
+	if otherCreated() != 0 {
+		t.Errorf("expected no token to be requested from GitHub, got %d", otherCreated())
+	}
+}
+
+func TestTokenIsRefreshedBeforeExpiry(t *testing.T) {
+	repo := newTestTokenRepository(t)
+	cache, created := newTestTokenCache(repo)
+
+	expiresAt := time.Now().Add(time.Minute)
+
+	if err := repo.UpdateGithubAppInstallationToken(1, []byte("expiring"), expiresAt); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, err := cache.Token(context.Background(), 1); err != nil || token != "token-1" {
+		t.Errorf("expected a new token, got %s: %v", token, err)
+	}
+
+	if created() != 1 {
+		t.Errorf("expected a token to be requested from GitHub, got %d", created())
+	}
+}
+
+func TestTokenIsCachedWhenDBFails(t *testing.T) {
+	cache, created := newTestTokenCache(&failingTokenRepository{newTestTokenRepository(t)})
+
+	for i := 0; i < 2; i++ {
+		if token, err := cache.Token(context.Background(), 1); err != nil || token != "token-1" {
+			t.Errorf("expected token-1, got %s: %v", token, err)
+		}
+	}
+
+	if created() != 1 {
+		t.Errorf("expected the token to be cached in memory, got %d tokens", created())
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	repo := newTestTokenRepository(t)
+	cache, _ := newTestTokenCache(repo)
+
+	if _, err := cache.Token(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Invalidate(1, "token-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	ga, err := repo.ReadGithubAppInstallationByInstallationID(1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ga.AccessToken) != 0 {
+		t.Errorf("expected the rejected token to be removed from the DB, got %s", ga.AccessToken)
+	}
+
+	if token, err := cache.Token(context.Background(), 1); err != nil || token != "token-2" {
+		t.Errorf("expected a new token, got %s: %v", token, err)
+	}
+
+	// a token that was already replaced is not dropped
+	if err := cache.Invalidate(1, "token-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, err := cache.Token(context.Background(), 1); err != nil || token != "token-2" {
+		t.Errorf("expected token-2 to be kept, got %s: %v", token, err)
+	}
+}
+
+func TestInvalidateWhenDBFails(t *testing.T) {
+	repo := newTestTokenRepository(t)
+	cache, _ := newTestTokenCache(&failingTokenRepository{repo})
+
+	// the rejected token is in the DB, and cannot be removed
+	if err := repo.UpdateGithubAppInstallationToken(1, []byte("rejected"), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, err := cache.Token(context.Background(), 1); err != nil || token != "rejected" {
+		t.Fatalf("expected the token of the DB, got %s: %v", token, err)
+	}
+
+	if err := cache.Invalidate(1, "rejected"); err == nil {
+		t.Errorf("expected an error when the token cannot be removed from the DB")
+	}
+
+	if token, err := cache.Token(context.Background(), 1); err != nil || token != "token-1" {
+		t.Errorf("expected the rejected token to not be read from the DB again, got %s: %v", token, err)
+	}
+}
+
+func TestClientInvalidatesRejectedToken(t *testing.T) {
+	repo := newTestTokenRepository(t)
+	cache, _ := newTestTokenCache(repo)
+
+	var mu sync.Mutex
+	var authorizations []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		if r.Header.Get("Authorization") == "token token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &tokenTransport{cache: cache, installationID: 1, base: http.DefaultTransport},
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(authorizations) != 2 || authorizations[0] != "token token-1" || authorizations[1] != "token token-2" {
+		t.Errorf("expected a new token after the token was rejected, got %v", authorizations)
+	}
+}
@@ -0,0 +1,471 @@
+package opa
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/random"
+)
+
+// Client evaluates the manifest policies of projects with the REST API of an Open Policy
+// Agent server. Policies are written in Rego without a package declaration, and are
+// uploaded to the server under a package of their project when they are created or
+// updated. Each project also has a batch module, which evaluates every policy of the
+// project against every rendered object of a deploy with a single query.
+//
+// The server is shared by every project, so policies may not call the builtins that
+// reach outside of the server or read its environment, and may not read the documents
+// of the server, which include the policies of other projects.
+type Client struct {
+	serverURL string
+
+	httpClient *http.Client
+}
+
+// NewClient creates a new client for an Open Policy Agent server
+func NewClient(serverURL string) *Client {
+	return &Client{
+		serverURL: strings.TrimSuffix(serverURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Policy is a Rego policy of a project. Policies report violations through deny rules,
+// whose values are the messages of the violations:
+//
+//	deny[msg] {
+//	  input.kind == "Deployment"
+//	  input.spec.template.spec.containers[_].securityContext.privileged
+//	  msg := "privileged containers are not allowed"
+//	}
+type Policy struct {
+	ID   uint
+	Name string
+	Rego string
+}
+
+// Violation is a message of a deny rule of a policy
+type Violation struct {
+	Policy  string
+	Message string
+}
+
+// DeniedBuiltins are the builtins that policies may not call: http.send and the net
+// builtins make requests from inside the network of the server, and opa.runtime returns
+// the environment and config of the server
+var DeniedBuiltins = []string{
+	"http.send",
+	"net.lookup_ip_addr",
+	"opa.runtime",
+}
+
+// ValidatePolicy returns an error if the Rego of a policy declares a package, calls a
+// denied builtin or reads the documents of the server, or cannot be compiled by the server
+func (c *Client) ValidatePolicy(rego string) error {
+	if err := checkPolicy(rego); err != nil {
+		return err
+	}
+
+	suffix, err := random.StringWithCharset(16, "abcdefghijklmnopqrstuvwxyz0123456789")
+
+	if err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("porter/validate/%s", suffix)
+
+	if err := c.putPolicy(id, fmt.Sprintf("package porter.validate.policy_%s\n\n%s", suffix, rego)); err != nil {
+		return err
+	}
+
+	return c.deletePolicy(id)
+}
+
+// PutPolicy uploads a created or updated policy of a project to the server, along with the
+// batch module of the project
+func (c *Client) PutPolicy(projectID uint, policy *Policy) error {
+	if err := checkPolicy(policy.Rego); err != nil {
+		return fmt.Errorf("policy %s: %w", policy.Name, err)
+	}
+
+	if err := c.putPolicy(getPolicyID(projectID, policy), getPolicyModule(projectID, policy)); err != nil {
+		return fmt.Errorf("policy %s: %w", policy.Name, err)
+	}
+
+	return c.putPolicy(getBatchID(projectID), getBatchModule(projectID))
+}
+
+// DeletePolicy deletes a policy of a project from the server
+func (c *Client) DeletePolicy(projectID uint, policy *Policy) error {
+	err := c.deletePolicy(getPolicyID(projectID, policy))
+
+	if errors.Is(err, errNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// SyncPolicies uploads the policies of a project to the server, and deletes the policies
+// of the project that the server stores but that were deleted from the project. Policies
+// are uploaded when they are created or updated, so the policies of a project are only
+// synced when the server is missing policies, such as after it restarts.
+func (c *Client) SyncPolicies(projectID uint, policies []*Policy) error {
+	prefix := fmt.Sprintf("porter/project_%d/", projectID)
+	current := make(map[string]bool)
+
+	for _, policy := range policies {
+		if err := checkPolicy(policy.Rego); err != nil {
+			return fmt.Errorf("policy %s: %w", policy.Name, err)
+		}
+
+		id := getPolicyID(projectID, policy)
+		current[id] = true
+
+		if err := c.putPolicy(id, getPolicyModule(projectID, policy)); err != nil {
+			return fmt.Errorf("policy %s: %w", policy.Name, err)
+		}
+	}
+
+	if err := c.putPolicy(getBatchID(projectID), getBatchModule(projectID)); err != nil {
+		return err
+	}
+
+	stored := &struct {
+		Result []struct {
+			ID string `json:"id"`
+		} `json:"result"`
+	}{}
+
+	if err := c.sendRequest(http.MethodGet, "/v1/policies", "", nil, stored); err != nil {
+		return err
+	}
+
+	for _, policy := range stored.Result {
+		if strings.HasPrefix(policy.ID, prefix) && !current[policy.ID] {
+			if err := c.deletePolicy(policy.ID); err != nil && !errors.Is(err, errNotFound) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// EvaluateAll evaluates the deny rules of the policies of a project against a list of
+// inputs, such as the rendered objects of a deploy, with a single query. It returns the
+// violations of each input. If the server does not store exactly the policies of the
+// project, the policies are synced and evaluated again.
+func (c *Client) EvaluateAll(projectID uint, policies []*Policy, inputs []interface{}) ([][]*Violation, error) {
+	res, loaded, err := c.evaluateBatch(projectID, policies, inputs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if loaded {
+		return res, nil
+	}
+
+	if err := c.SyncPolicies(projectID, policies); err != nil {
+		return nil, err
+	}
+
+	res, loaded, err = c.evaluateBatch(projectID, policies, inputs)
+
+	if err != nil {
+		return nil, err
+	} else if !loaded {
+		return nil, fmt.Errorf("the policies of the project could not be loaded")
+	}
+
+	return res, nil
+}
+
+// evaluateBatch queries the batch module of a project, and returns loaded=false if the
+// server does not store the batch module or exactly the policies of the project
+func (c *Client) evaluateBatch(projectID uint, policies []*Policy, inputs []interface{}) ([][]*Violation, bool, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"input": map[string]interface{}{
+			"objects": inputs,
+		},
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp := &struct {
+		Result *struct {
+			Loaded     []string        `json:"loaded"`
+			Violations [][]interface{} `json:"violations"`
+		} `json:"result"`
+	}{}
+
+	if err := c.sendRequest(
+		http.MethodPost,
+		fmt.Sprintf("/v1/data/porter/batch/project_%d", projectID),
+		"application/json",
+		body,
+		resp,
+	); err != nil {
+		return nil, false, err
+	}
+
+	names := make(map[string]string)
+
+	for _, policy := range policies {
+		names[getPolicyKey(policy)] = policy.Name
+	}
+
+	if resp.Result == nil || len(resp.Result.Loaded) != len(policies) {
+		return nil, false, nil
+	}
+
+	for _, key := range resp.Result.Loaded {
+		if _, ok := names[key]; !ok {
+			return nil, false, nil
+		}
+	}
+
+	res := make([][]*Violation, len(inputs))
+
+	for i := range res {
+		res[i] = make([]*Violation, 0)
+	}
+
+	// violations are [index of the input, key of the policy, message] tuples
+	for _, violation := range resp.Result.Violations {
+		if len(violation) != 3 {
+			continue
+		}
+
+		index, ok := violation[0].(float64)
+
+		if !ok || int(index) < 0 || int(index) >= len(inputs) {
+			continue
+		}
+
+		key, _ := violation[1].(string)
+		message, ok := violation[2].(string)
+
+		if !ok {
+			msgBytes, _ := json.Marshal(violation[2])
+			message = string(msgBytes)
+		}
+
+		res[int(index)] = append(res[int(index)], &Violation{
+			Policy:  names[key],
+			Message: message,
+		})
+	}
+
+	// the values of deny rules are sets, which are not ordered
+	for _, violations := range res {
+		sort.SliceStable(violations, func(i, j int) bool {
+			if violations[i].Policy != violations[j].Policy {
+				return violations[i].Policy < violations[j].Policy
+			}
+
+			return violations[i].Message < violations[j].Message
+		})
+	}
+
+	return res, true, nil
+}
+
+func getPolicyKey(policy *Policy) string {
+	return fmt.Sprintf("policy_%d", policy.ID)
+}
+
+func getPolicyID(projectID uint, policy *Policy) string {
+	return fmt.Sprintf("porter/project_%d/%s", projectID, getPolicyKey(policy))
+}
+
+func getPolicyModule(projectID uint, policy *Policy) string {
+	return fmt.Sprintf("package porter.project_%d.%s\n\n%s", projectID, getPolicyKey(policy), policy.Rego)
+}
+
+func getBatchID(projectID uint) string {
+	return fmt.Sprintf("porter/batch/project_%d", projectID)
+}
+
+// getBatchModule returns the batch module of a project, which lists the policies of the
+// project that the server stores, and evaluates every policy against every object of the
+// input. It is outside of the package of the project, since a module cannot read the
+// package that it is in.
+func getBatchModule(projectID uint) string {
+	return fmt.Sprintf(`package porter.batch.project_%d
+
+loaded[key] {
+  data.porter.project_%d[key]
+}
+
+violations[[i, key, msg]] {
+  obj := input.objects[i]
+  msg := data.porter.project_%d[key].deny[_] with input as obj
+}
+`, projectID, projectID, projectID)
+}
+
+// checkPolicy returns an error if a policy declares a package, calls a denied builtin or
+// reads the documents of the server. Strings and comments are removed before the policy
+// is checked, so that they cannot cause false positives.
+func checkPolicy(rego string) error {
+	code := stripStringsAndComments(rego)
+
+	for _, line := range strings.Split(code, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == "package" {
+			return fmt.Errorf("policies must not declare a package")
+		}
+	}
+
+	for _, builtin := range DeniedBuiltins {
+		if regexp.MustCompile(`(^|[^\w.])` + regexp.QuoteMeta(builtin) + `\b`).MatchString(code) {
+			return fmt.Errorf("policies must not call %s", builtin)
+		}
+	}
+
+	if dataRefRegex.MatchString(code) {
+		return fmt.Errorf("policies must only read their input, and not data")
+	}
+
+	return nil
+}
+
+// dataRefRegex matches references to the data document, but not to fields named data,
+// such as input.data of ConfigMaps
+var dataRefRegex = regexp.MustCompile(`(^|[^\w.])data\b`)
+
+// stripStringsAndComments replaces the strings of a policy with empty strings, and
+// removes its comments
+func stripStringsAndComments(rego string) string {
+	var res strings.Builder
+
+	for i := 0; i < len(rego); i++ {
+		switch ch := rego[i]; ch {
+		case '#':
+			for i < len(rego) && rego[i] != '\n' {
+				i++
+			}
+
+			if i < len(rego) {
+				res.WriteByte('\n')
+			}
+		case '"', '`':
+			res.WriteString(`""`)
+
+			for i++; i < len(rego) && rego[i] != ch; i++ {
+				// only double-quoted strings have escapes
+				if ch == '"' && rego[i] == '\\' {
+					i++
+				}
+			}
+		default:
+			res.WriteByte(ch)
+		}
+	}
+
+	return res.String()
+}
+
+var errNotFound = fmt.Errorf("not found")
+
+func (c *Client) putPolicy(id, module string) error {
+	return c.sendRequest(http.MethodPut, "/v1/policies/"+id, "text/plain", []byte(module), nil)
+}
+
+func (c *Client) deletePolicy(id string) error {
+	return c.sendRequest(http.MethodDelete, "/v1/policies/"+id, "", nil, nil)
+}
+
+func (c *Client) sendRequest(method, path, contentType string, body []byte, result interface{}) error {
+	reqURL, err := url.Parse(c.serverURL + path)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, reqURL.String(), bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := c.httpClient.Do(req)
+
+	if err != nil {
+		return fmt.Errorf("could not reach the policy server: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	resBytes, err := ioutil.ReadAll(res.Body)
+
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode == http.StatusNotFound && method == http.MethodDelete {
+		return errNotFound
+	} else if res.StatusCode >= 300 {
+		return getServerError(res.StatusCode, resBytes)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(resBytes, result)
+}
+
+// getServerError returns the compile errors of a policy, or the message of another error
+// of the server
+func getServerError(statusCode int, body []byte) error {
+	serverErr := &struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message  string `json:"message"`
+			Location *struct {
+				Row int `json:"row"`
+			} `json:"location"`
+		} `json:"errors"`
+	}{}
+
+	if err := json.Unmarshal(body, serverErr); err != nil || serverErr.Message == "" {
+		return fmt.Errorf("policy server returned status code %d", statusCode)
+	}
+
+	if len(serverErr.Errors) == 0 {
+		return fmt.Errorf("%s", serverErr.Message)
+	}
+
+	msgs := make([]string, 0)
+
+	for _, compileErr := range serverErr.Errors {
+		// the package declaration is prepended to policies, so the rows of errors are
+		// shifted back to the rows of the policy
+		if compileErr.Location != nil && compileErr.Location.Row > 2 {
+			msgs = append(msgs, fmt.Sprintf("line %d: %s", compileErr.Location.Row-2, compileErr.Message))
+		} else {
+			msgs = append(msgs, compileErr.Message)
+		}
+	}
+
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
@@ -0,0 +1,134 @@
+package opa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		rego     string
+		expected string
+	}{
+		{
+			name: "input only",
+			rego: `deny[msg] {
+  input.kind == "ConfigMap"
+  input.data.password
+  msg := "http.send and data are only in this string" # and in data of this comment
+}`,
+		},
+		{
+			name:     "package",
+			rego:     "package example\n\ndeny[msg] { msg := input.kind }",
+			expected: "policies must not declare a package",
+		},
+		{
+			name:     "http.send",
+			rego:     `deny[msg] { resp := http.send({"method": "get", "url": "http://169.254.169.254/"}); msg := resp.raw_body }`,
+			expected: "policies must not call http.send",
+		},
+		{
+			name:     "opa.runtime",
+			rego:     `deny[msg] { msg := opa.runtime().env.AWS_SECRET_ACCESS_KEY }`,
+			expected: "policies must not call opa.runtime",
+		},
+		{
+			name:     "data",
+			rego:     `deny[msg] { msg := data.porter.project_2.policy_1.deny[_] }`,
+			expected: "policies must only read their input, and not data",
+		},
+		{
+			name:     "data in raw string",
+			rego:     "deny[msg] { msg := `data` }",
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkPolicy(test.rego)
+
+			if test.expected == "" && err != nil {
+				t.Errorf("expected policy to be allowed, got %v", err)
+			} else if test.expected != "" && (err == nil || err.Error() != test.expected) {
+				t.Errorf("expected error %q, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestEvaluateAllSyncsMissingPolicies(t *testing.T) {
+	synced := false
+	queries := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut:
+			synced = true
+			w.Write([]byte("{}"))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/policies":
+			w.Write([]byte(`{"result":[{"id":"porter/project_1/policy_1"},{"id":"porter/project_1/policy_9"}]}`))
+		case r.Method == http.MethodDelete:
+			w.Write([]byte("{}"))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/data/porter/batch/project_1":
+			queries++
+
+			body := &struct {
+				Input struct {
+					Objects []interface{} `json:"objects"`
+				} `json:"input"`
+			}{}
+
+			if err := json.NewDecoder(r.Body).Decode(body); err != nil || len(body.Input.Objects) != 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			// the server has no policies until they are synced
+			if !synced {
+				w.Write([]byte(`{}`))
+				return
+			}
+
+			w.Write([]byte(`{"result":{"loaded":["policy_1"],"violations":[[1,"policy_1","b"],[1,"policy_1","a"]]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	res, err := client.EvaluateAll(
+		1,
+		[]*Policy{{ID: 1, Name: "no-services", Rego: `deny[msg] { input.kind == "Service"; msg := "x" }`}},
+		[]interface{}{
+			map[string]interface{}{"kind": "Deployment"},
+			map[string]interface{}{"kind": "Service"},
+		},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !synced || queries != 2 {
+		t.Errorf("expected policies to be synced and evaluated again, got synced=%t and %d queries", synced, queries)
+	}
+
+	if len(res) != 2 || len(res[0]) != 0 || len(res[1]) != 2 {
+		t.Fatalf("expected violations of the second object only, got %v", res)
+	}
+
+	messages := []string{res[1][0].Message, res[1][1].Message}
+
+	if strings.Join(messages, ",") != "a,b" || res[1][0].Policy != "no-services" {
+		t.Errorf("expected sorted violations of no-services, got %v", messages)
+	}
+}
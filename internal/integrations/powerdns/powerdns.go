@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,6 +16,7 @@ type Client struct {
 	apiKey    string
 	serverURL string
 	runDomain string
+	mu        sync.RWMutex
 
 	httpClient *http.Client
 }
@@ -25,7 +27,20 @@ func NewClient(serverURL, apiKey, runDomain string) *Client {
 		Timeout: time.Minute,
 	}
 
-	return &Client{apiKey, serverURL, runDomain, httpClient}
+	return &Client{
+		apiKey:     apiKey,
+		serverURL:  serverURL,
+		runDomain:  runDomain,
+		httpClient: httpClient,
+	}
+}
+
+// SetRunDomain changes the zone that records are created in
+func (c *Client) SetRunDomain(runDomain string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.runDomain = runDomain
 }
 
 // RecordData represents the data required to create or delete an A/CNAME record
@@ -93,6 +108,20 @@ func (c *Client) CreateARecord(value, hostname string) error {
 	})
 }
 
+// DeleteRecord deletes the record of a type for a hostname from the nameserver
+func (c *Client) DeleteRecord(recordType, hostname string) error {
+	hostnameC := canonicalize(hostname)
+
+	return c.sendRequest("PATCH", &RecordData{
+		RRSets: []RR{{
+			Name:       hostnameC,
+			Type:       recordType,
+			ChangeType: "DELETE",
+			Records:    []Record{},
+		}},
+	})
+}
+
 func canonicalize(value string) string {
 	// if the string ends in a period, return
 	if value[len(value)-1:] == "." {
@@ -109,7 +138,9 @@ func (c *Client) sendRequest(method string, data *RecordData) error {
 		return nil
 	}
 
+	c.mu.RLock()
 	reqURL.Path = fmt.Sprintf("/api/v1/servers/localhost/zones/%s", c.runDomain)
+	c.mu.RUnlock()
 
 	strData, err := json.Marshal(data)
 
@@ -0,0 +1,115 @@
+package preflight
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+// CheckAWS simulates the IAM actions against the policies of the principal of an AWS
+// integration. If the credentials are invalid, or the principal cannot simulate its own
+// policies, a single failed check is returned for the call that failed.
+func CheckAWS(awsInt *integrations.AWSIntegration, actions []string) []*types.PreflightCheck {
+	sess, err := awsInt.GetSession()
+
+	if err != nil {
+		return []*types.PreflightCheck{failedCheck("sts:GetCallerIdentity", err)}
+	}
+
+	identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+
+	if err != nil {
+		return []*types.PreflightCheck{failedCheck(
+			"sts:GetCallerIdentity",
+			fmt.Errorf("the credentials are invalid: %s", err.Error()),
+		)}
+	}
+
+	iamSvc := iam.New(sess)
+	principalARN := aws.StringValue(identity.Arn)
+
+	// the policies of an assumed role are simulated on the role, whose ARN is read since
+	// the ARN of the session does not contain the path of the role
+	if roleName, ok := getAssumedRoleName(principalARN); ok {
+		role, err := iamSvc.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+
+		if err != nil {
+			return []*types.PreflightCheck{failedCheck(
+				"iam:GetRole",
+				fmt.Errorf("the role of the credentials could not be read: %s", err.Error()),
+			)}
+		}
+
+		principalARN = aws.StringValue(role.Role.Arn)
+	}
+
+	decisions := make(map[string]string)
+
+	err = iamSvc.SimulatePrincipalPolicyPages(
+		&iam.SimulatePrincipalPolicyInput{
+			PolicySourceArn: aws.String(principalARN),
+			ActionNames:     aws.StringSlice(actions),
+		},
+		func(page *iam.SimulatePolicyResponse, lastPage bool) bool {
+			for _, result := range page.EvaluationResults {
+				decisions[aws.StringValue(result.EvalActionName)] = aws.StringValue(result.EvalDecision)
+			}
+
+			return true
+		},
+	)
+
+	if err != nil {
+		return []*types.PreflightCheck{failedCheck(
+			"iam:SimulatePrincipalPolicy",
+			fmt.Errorf("the permissions of the credentials could not be checked: %s", err.Error()),
+		)}
+	}
+
+	checks := make([]*types.PreflightCheck, 0, len(actions))
+
+	for _, action := range actions {
+		check := &types.PreflightCheck{
+			Permission: action,
+			Passed:     decisions[action] == iam.PolicyEvaluationDecisionTypeAllowed,
+		}
+
+		if !check.Passed {
+			decision := decisions[action]
+
+			if decision == "" {
+				decision = "not evaluated"
+			}
+
+			check.Message = fmt.Sprintf("action is %s for %s", decision, aws.StringValue(identity.Arn))
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// getAssumedRoleName returns the name of the IAM role of the session ARN of an assumed
+// role, since the policies of sessions cannot be simulated
+func getAssumedRoleName(arn string) (string, bool) {
+	// arn:<partition>:sts::<account>:assumed-role/<role>/<session>
+	parts := strings.Split(arn, ":")
+
+	if len(parts) != 6 || parts[2] != "sts" || !strings.HasPrefix(parts[5], "assumed-role/") {
+		return "", false
+	}
+
+	resource := strings.Split(parts[5], "/")
+
+	if len(resource) != 3 || resource[1] == "" {
+		return "", false
+	}
+
+	return resource[1], true
+}
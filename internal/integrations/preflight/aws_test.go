@@ -0,0 +1,26 @@
+package preflight
+
+import "testing"
+
+func TestGetAssumedRoleName(t *testing.T) {
+	tests := []struct {
+		arn      string
+		roleName string
+		ok       bool
+	}{
+		{"arn:aws:sts::123456789012:assumed-role/porter-provisioner/session", "porter-provisioner", true},
+		{"arn:aws-us-gov:sts::123456789012:assumed-role/porter/1625097600", "porter", true},
+		{"arn:aws:iam::123456789012:user/porter", "", false},
+		{"arn:aws:iam::123456789012:role/teams/porter", "", false},
+		{"arn:aws:sts::123456789012:federated-user/porter", "", false},
+		{"not an arn", "", false},
+	}
+
+	for _, test := range tests {
+		roleName, ok := getAssumedRoleName(test.arn)
+
+		if roleName != test.roleName || ok != test.ok {
+			t.Errorf("%s: expected %s, %t, got %s, %t", test.arn, test.roleName, test.ok, roleName, ok)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
+
+// CheckDO checks the account of a DigitalOcean integration. DigitalOcean tokens are not
+// scoped to individual permissions, so the checks ensure that the token is valid and that
+// the account can create resources.
+func CheckDO(doInt *integrations.OAuthIntegration, kind types.InfraKind) []*types.PreflightCheck {
+	client := godo.NewFromToken(string(doInt.AccessToken))
+
+	account, _, err := client.Account.Get(context.Background())
+
+	if err != nil {
+		return []*types.PreflightCheck{failedCheck(
+			"account:read",
+			fmt.Errorf("the credentials are invalid: %s", err.Error()),
+		)}
+	}
+
+	checks := []*types.PreflightCheck{
+		{
+			Permission: "account:read",
+			Passed:     true,
+		},
+		{
+			Permission: "account:active",
+			Passed:     account.Status == "active",
+		},
+		{
+			Permission: "account:email_verified",
+			Passed:     account.EmailVerified,
+		},
+	}
+
+	if !checks[1].Passed {
+		checks[1].Message = fmt.Sprintf("account status is %s: %s", account.Status, account.StatusMessage)
+	}
+
+	if !checks[2].Passed {
+		checks[2].Message = "the email of the account must be verified to create resources"
+	}
+
+	// clusters need room for at least one node pool of droplets
+	if kind == types.InfraDOKS {
+		check := &types.PreflightCheck{
+			Permission: "droplet:create",
+			Passed:     account.DropletLimit > 0,
+		}
+
+		if !check.Passed {
+			check.Message = "the droplet limit of the account is 0"
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
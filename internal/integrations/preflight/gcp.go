@@ -0,0 +1,71 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models/integrations"
+	crm "google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/option"
+)
+
+// gcpPermissionsPerRequest is the maximum number of permissions that can be tested in a
+// single request
+const gcpPermissionsPerRequest = 100
+
+// CheckGCP tests which of the permissions the service account of a GCP integration has
+// on the project of the integration. If the credentials are invalid, a single failed
+// check is returned for the call that failed.
+func CheckGCP(gcpInt *integrations.GCPIntegration, permissions []string) []*types.PreflightCheck {
+	svc, err := crm.NewService(context.Background(), option.WithCredentialsJSON(gcpInt.GCPKeyData))
+
+	if err != nil {
+		return []*types.PreflightCheck{failedCheck(
+			"resourcemanager.projects.testIamPermissions",
+			fmt.Errorf("the credentials are invalid: %s", err.Error()),
+		)}
+	}
+
+	granted := make(map[string]bool)
+
+	for start := 0; start < len(permissions); start += gcpPermissionsPerRequest {
+		end := start + gcpPermissionsPerRequest
+
+		if end > len(permissions) {
+			end = len(permissions)
+		}
+
+		resp, err := svc.Projects.TestIamPermissions(gcpInt.GCPProjectID, &crm.TestIamPermissionsRequest{
+			Permissions: permissions[start:end],
+		}).Do()
+
+		if err != nil {
+			return []*types.PreflightCheck{failedCheck(
+				"resourcemanager.projects.testIamPermissions",
+				fmt.Errorf("the permissions on project %s could not be checked: %s", gcpInt.GCPProjectID, err.Error()),
+			)}
+		}
+
+		for _, permission := range resp.Permissions {
+			granted[permission] = true
+		}
+	}
+
+	checks := make([]*types.PreflightCheck, 0, len(permissions))
+
+	for _, permission := range permissions {
+		check := &types.PreflightCheck{
+			Permission: permission,
+			Passed:     granted[permission],
+		}
+
+		if !check.Passed {
+			check.Message = fmt.Sprintf("permission is not granted on project %s", gcpInt.GCPProjectID)
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
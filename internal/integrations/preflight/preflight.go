@@ -0,0 +1,380 @@
+// Package preflight checks that the credentials of cloud integrations have the
+// permissions that the Terraform modules of the provisioner require, so that missing
+// permissions are reported before an infra is provisioned instead of during an apply.
+package preflight
+
+import (
+	"github.com/porter-dev/porter/api/types"
+)
+
+// AWSActions are the IAM actions that the modules of each AWS infra kind require. The
+// actions of EKS are those of the minimum policy in docs/getting-started/aws.md, with its
+// wildcards expanded to the actions that the modules call, since wildcards cannot be
+// simulated. The actions of the other kinds are the calls that the Terraform provider
+// makes to create, read, update and delete the resources of their modules.
+var AWSActions = map[types.InfraKind][]string{
+	types.InfraECR: {
+		"ecr:CreateRepository",
+		"ecr:DescribeRepositories",
+		"ecr:ListTagsForResource",
+		"ecr:DeleteRepository",
+		"ecr:GetAuthorizationToken",
+	},
+	types.InfraEKS: {
+		"autoscaling:AttachInstances",
+		"autoscaling:CreateAutoScalingGroup",
+		"autoscaling:CreateLaunchConfiguration",
+		"autoscaling:CreateOrUpdateTags",
+		"autoscaling:DeleteAutoScalingGroup",
+		"autoscaling:DeleteLaunchConfiguration",
+		"autoscaling:DeleteTags",
+		"autoscaling:DescribeAutoScalingGroups",
+		"autoscaling:DescribeLaunchConfigurations",
+		"autoscaling:DescribeScalingActivities",
+		"autoscaling:DescribeTags",
+		"autoscaling:DetachInstances",
+		"autoscaling:SetDesiredCapacity",
+		"autoscaling:UpdateAutoScalingGroup",
+		"autoscaling:SuspendProcesses",
+		"ec2:AllocateAddress",
+		"ec2:AssignPrivateIpAddresses",
+		"ec2:AssociateAddress",
+		"ec2:AssociateDhcpOptions",
+		"ec2:AssociateRouteTable",
+		"ec2:AssociateVpcCidrBlock",
+		"ec2:AttachInternetGateway",
+		"ec2:AttachNetworkInterface",
+		"ec2:AuthorizeSecurityGroupEgress",
+		"ec2:AuthorizeSecurityGroupIngress",
+		"ec2:CreateDefaultSubnet",
+		"ec2:CreateDhcpOptions",
+		"ec2:CreateEgressOnlyInternetGateway",
+		"ec2:CreateInternetGateway",
+		"ec2:CreateNatGateway",
+		"ec2:CreateNetworkInterface",
+		"ec2:CreateRoute",
+		"ec2:CreateRouteTable",
+		"ec2:CreateSecurityGroup",
+		"ec2:CreateSubnet",
+		"ec2:CreateTags",
+		"ec2:CreateVolume",
+		"ec2:CreateVpc",
+		"ec2:CreateVpcEndpoint",
+		"ec2:DeleteDhcpOptions",
+		"ec2:DeleteEgressOnlyInternetGateway",
+		"ec2:DeleteInternetGateway",
+		"ec2:DeleteNatGateway",
+		"ec2:DeleteNetworkInterface",
+		"ec2:DeleteRoute",
+		"ec2:DeleteRouteTable",
+		"ec2:DeleteSecurityGroup",
+		"ec2:DeleteSubnet",
+		"ec2:DeleteTags",
+		"ec2:DeleteVolume",
+		"ec2:DeleteVpc",
+		"ec2:DeleteVpnGateway",
+		"ec2:DescribeAccountAttributes",
+		"ec2:DescribeAddresses",
+		"ec2:DescribeAvailabilityZones",
+		"ec2:DescribeDhcpOptions",
+		"ec2:DescribeImages",
+		"ec2:DescribeInstances",
+		"ec2:DescribeInternetGateways",
+		"ec2:DescribeNatGateways",
+		"ec2:DescribeNetworkAcls",
+		"ec2:DescribeNetworkInterfaces",
+		"ec2:DescribeRouteTables",
+		"ec2:DescribeSecurityGroups",
+		"ec2:DescribeSubnets",
+		"ec2:DescribeTags",
+		"ec2:DescribeVpcAttribute",
+		"ec2:DescribeVpcEndpoints",
+		"ec2:DescribeVpcs",
+		"ec2:DetachInternetGateway",
+		"ec2:DetachNetworkInterface",
+		"ec2:DetachVolume",
+		"ec2:DisassociateAddress",
+		"ec2:DisassociateRouteTable",
+		"ec2:DisassociateVpcCidrBlock",
+		"ec2:ModifySubnetAttribute",
+		"ec2:ModifyVpcAttribute",
+		"ec2:ModifyVpcEndpoint",
+		"ec2:ReleaseAddress",
+		"ec2:RevokeSecurityGroupEgress",
+		"ec2:RevokeSecurityGroupIngress",
+		"ec2:UpdateSecurityGroupRuleDescriptionsEgress",
+		"ec2:UpdateSecurityGroupRuleDescriptionsIngress",
+		"ec2:CreateLaunchTemplate",
+		"ec2:CreateLaunchTemplateVersion",
+		"ec2:DeleteLaunchTemplate",
+		"ec2:DeleteLaunchTemplateVersions",
+		"ec2:DescribeLaunchTemplates",
+		"ec2:DescribeLaunchTemplateVersions",
+		"ec2:GetLaunchTemplateData",
+		"ec2:ModifyLaunchTemplate",
+		"ec2:RunInstances",
+		"eks:CreateCluster",
+		"eks:DeleteCluster",
+		"eks:DescribeCluster",
+		"eks:ListClusters",
+		"eks:UpdateClusterConfig",
+		"eks:UpdateClusterVersion",
+		"eks:DescribeUpdate",
+		"eks:TagResource",
+		"eks:UntagResource",
+		"eks:ListTagsForResource",
+		"eks:CreateFargateProfile",
+		"eks:DeleteFargateProfile",
+		"eks:DescribeFargateProfile",
+		"eks:ListFargateProfiles",
+		"eks:CreateNodegroup",
+		"eks:DeleteNodegroup",
+		"eks:DescribeNodegroup",
+		"eks:ListNodegroups",
+		"eks:UpdateNodegroupConfig",
+		"eks:UpdateNodegroupVersion",
+		"iam:AddRoleToInstanceProfile",
+		"iam:AttachRolePolicy",
+		"iam:CreateInstanceProfile",
+		"iam:CreateOpenIDConnectProvider",
+		"iam:CreateServiceLinkedRole",
+		"iam:CreatePolicy",
+		"iam:CreatePolicyVersion",
+		"iam:CreateRole",
+		"iam:DeleteInstanceProfile",
+		"iam:DeleteOpenIDConnectProvider",
+		"iam:DeletePolicy",
+		"iam:DeletePolicyVersion",
+		"iam:DeleteRole",
+		"iam:DeleteRolePolicy",
+		"iam:DeleteServiceLinkedRole",
+		"iam:DetachRolePolicy",
+		"iam:GetInstanceProfile",
+		"iam:GetOpenIDConnectProvider",
+		"iam:GetPolicy",
+		"iam:GetPolicyVersion",
+		"iam:GetRole",
+		"iam:GetRolePolicy",
+		"iam:ListAttachedRolePolicies",
+		"iam:ListInstanceProfilesForRole",
+		"iam:ListOpenIDConnectProviders",
+		"iam:ListPolicyVersions",
+		"iam:ListRolePolicies",
+		"iam:PassRole",
+		"iam:PutRolePolicy",
+		"iam:RemoveRoleFromInstanceProfile",
+		"iam:TagOpenIDConnectProvider",
+		"iam:TagRole",
+		"iam:UntagRole",
+		"iam:UpdateAssumeRolePolicy",
+		"logs:CreateLogGroup",
+		"logs:DescribeLogGroups",
+		"logs:DeleteLogGroup",
+		"logs:ListTagsLogGroup",
+		"logs:PutRetentionPolicy",
+		"kms:CreateAlias",
+		"kms:CreateGrant",
+		"kms:CreateKey",
+		"kms:DeleteAlias",
+		"kms:DescribeKey",
+		"kms:GetKeyPolicy",
+		"kms:GetKeyRotationStatus",
+		"kms:ListAliases",
+		"kms:ListResourceTags",
+		"kms:ScheduleKeyDeletion",
+	},
+	types.InfraRDS: {
+		"rds:CreateDBInstance",
+		"rds:DescribeDBInstances",
+		"rds:ModifyDBInstance",
+		"rds:DeleteDBInstance",
+		"rds:AddTagsToResource",
+		"rds:ListTagsForResource",
+		"rds:CreateDBSubnetGroup",
+		"rds:DescribeDBSubnetGroups",
+		"rds:DeleteDBSubnetGroup",
+		"rds:CreateDBParameterGroup",
+		"rds:DescribeDBParameterGroups",
+		"rds:DescribeDBParameters",
+		"rds:ModifyDBParameterGroup",
+		"rds:DeleteDBParameterGroup",
+		"ec2:DescribeVpcs",
+		"ec2:DescribeSubnets",
+		"ec2:CreateSecurityGroup",
+		"ec2:DescribeSecurityGroups",
+		"ec2:AuthorizeSecurityGroupIngress",
+		"ec2:AuthorizeSecurityGroupEgress",
+		"ec2:RevokeSecurityGroupEgress",
+		"ec2:DeleteSecurityGroup",
+		"ec2:CreateTags",
+		"kms:DescribeKey",
+		"kms:CreateGrant",
+	},
+	types.InfraS3: {
+		"s3:CreateBucket",
+		"s3:ListBucket",
+		"s3:GetBucketAcl",
+		"s3:GetBucketCORS",
+		"s3:GetBucketLocation",
+		"s3:GetBucketLogging",
+		"s3:GetBucketObjectLockConfiguration",
+		"s3:GetBucketPolicy",
+		"s3:GetBucketRequestPayment",
+		"s3:GetBucketTagging",
+		"s3:GetBucketVersioning",
+		"s3:GetBucketWebsite",
+		"s3:GetAccelerateConfiguration",
+		"s3:GetEncryptionConfiguration",
+		"s3:GetLifecycleConfiguration",
+		"s3:GetReplicationConfiguration",
+		"s3:PutBucketVersioning",
+		"s3:PutBucketPolicy",
+		"s3:DeleteBucketPolicy",
+		"s3:DeleteBucket",
+		"iam:CreateUser",
+		"iam:GetUser",
+		"iam:DeleteUser",
+		"iam:PutUserPolicy",
+		"iam:GetUserPolicy",
+		"iam:DeleteUserPolicy",
+		"iam:CreateAccessKey",
+		"iam:ListAccessKeys",
+		"iam:DeleteAccessKey",
+	},
+	types.InfraSQS: {
+		"sqs:CreateQueue",
+		"sqs:GetQueueAttributes",
+		"sqs:GetQueueUrl",
+		"sqs:SetQueueAttributes",
+		"sqs:ListQueueTags",
+		"sqs:DeleteQueue",
+		"iam:CreateUser",
+		"iam:GetUser",
+		"iam:DeleteUser",
+		"iam:PutUserPolicy",
+		"iam:GetUserPolicy",
+		"iam:DeleteUserPolicy",
+		"iam:CreateAccessKey",
+		"iam:ListAccessKeys",
+		"iam:DeleteAccessKey",
+	},
+	types.InfraRDSNetwork: {
+		"eks:DescribeCluster",
+		"rds:DescribeDBInstances",
+		"ec2:DescribeVpcs",
+		"ec2:DescribeSecurityGroups",
+		"ec2:AuthorizeSecurityGroupIngress",
+		"ec2:RevokeSecurityGroupIngress",
+	},
+}
+
+// GCPPermissions are the IAM permissions that the modules of each GCP infra kind require
+// on the project of the integration. The permissions of GKE and GCR are granted by the
+// roles in docs/getting-started/gcp.md, so that credentials that follow the docs pass.
+var GCPPermissions = map[types.InfraKind][]string{
+	types.InfraGCR: {
+		"storage.buckets.create",
+		"storage.buckets.get",
+		"storage.objects.create",
+		"storage.objects.get",
+		"storage.objects.list",
+	},
+	types.InfraGKE: {
+		"container.clusters.create",
+		"container.clusters.get",
+		"container.clusters.update",
+		"container.clusters.delete",
+		"container.clusters.getCredentials",
+		"container.operations.get",
+		"compute.networks.create",
+		"compute.networks.get",
+		"compute.networks.delete",
+		"compute.subnetworks.create",
+		"compute.subnetworks.get",
+		"compute.subnetworks.delete",
+		"compute.routers.create",
+		"compute.routers.get",
+		"compute.routers.update",
+		"compute.routers.delete",
+		"compute.regions.get",
+		"compute.zones.list",
+		"compute.instanceGroupManagers.get",
+		"iam.serviceAccounts.actAs",
+	},
+	types.InfraGCS: {
+		"storage.buckets.create",
+		"storage.buckets.get",
+		"storage.buckets.update",
+		"storage.buckets.delete",
+		"storage.buckets.getIamPolicy",
+		"storage.buckets.setIamPolicy",
+		"iam.serviceAccounts.create",
+		"iam.serviceAccounts.get",
+		"iam.serviceAccounts.delete",
+		"iam.serviceAccountKeys.create",
+		"iam.serviceAccountKeys.delete",
+	},
+	types.InfraPubSub: {
+		"pubsub.topics.create",
+		"pubsub.topics.get",
+		"pubsub.topics.delete",
+		"pubsub.topics.getIamPolicy",
+		"pubsub.topics.setIamPolicy",
+		"pubsub.subscriptions.create",
+		"pubsub.subscriptions.get",
+		"pubsub.subscriptions.delete",
+		"pubsub.subscriptions.getIamPolicy",
+		"pubsub.subscriptions.setIamPolicy",
+		"iam.serviceAccounts.create",
+		"iam.serviceAccounts.get",
+		"iam.serviceAccounts.delete",
+		"iam.serviceAccountKeys.create",
+		"iam.serviceAccountKeys.delete",
+
+		// the service account is granted the project roles of the infra
+		"resourcemanager.projects.getIamPolicy",
+		"resourcemanager.projects.setIamPolicy",
+	},
+	types.InfraCloudSQLNetwork: {
+		"container.clusters.get",
+		"compute.networks.get",
+		"compute.globalAddresses.create",
+		"compute.globalAddresses.get",
+		"compute.globalAddresses.delete",
+		"servicenetworking.services.addPeering",
+		"cloudsql.instances.get",
+		"cloudsql.instances.update",
+	},
+}
+
+// DOKinds are the infra kinds that are provisioned with a DigitalOcean integration
+var DOKinds = map[types.InfraKind]bool{
+	types.InfraDOCR: true,
+	types.InfraDOKS: true,
+}
+
+// NewResponse returns the response for the checks of an infra kind, which has passed if
+// all checks passed
+func NewResponse(kind types.InfraKind, checks []*types.PreflightCheck) *types.PreflightResponse {
+	res := &types.PreflightResponse{
+		Kind:   kind,
+		Passed: true,
+		Checks: checks,
+	}
+
+	for _, check := range checks {
+		if !check.Passed {
+			res.Passed = false
+		}
+	}
+
+	return res
+}
+
+func failedCheck(permission string, err error) *types.PreflightCheck {
+	return &types.PreflightCheck{
+		Permission: permission,
+		Passed:     false,
+		Message:    err.Error(),
+	}
+}
@@ -0,0 +1,129 @@
+package preflight
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+var awsActionRegex = regexp.MustCompile(`^[a-z0-9-]+:[A-Za-z0-9]+$`)
+var gcpPermissionRegex = regexp.MustCompile(`^[a-z]+(\.[A-Za-z]+){2,}$`)
+
+// getDocumentedAWSActions returns the actions of the minimum policy that the AWS docs ask
+// users to attach to their credentials
+func getDocumentedAWSActions(t *testing.T) []string {
+	t.Helper()
+
+	data, err := ioutil.ReadFile("../../../docs/getting-started/aws.md")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := string(data)
+	start := strings.Index(doc, "```json")
+	end := strings.Index(doc[start+len("```json"):], "```")
+
+	if start == -1 || end == -1 {
+		t.Fatalf("the minimum policy was not found in the AWS docs")
+	}
+
+	policy := struct {
+		Statement []struct {
+			Action []string
+		}
+	}{}
+
+	if err := json.Unmarshal([]byte(doc[start+len("```json"):start+len("```json")+end]), &policy); err != nil {
+		t.Fatal(err)
+	}
+
+	actions := make([]string, 0)
+
+	for _, statement := range policy.Statement {
+		actions = append(actions, statement.Action...)
+	}
+
+	return actions
+}
+
+func matchesAction(pattern, action string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(action, strings.TrimSuffix(pattern, "*"))
+	}
+
+	return pattern == action
+}
+
+func TestEKSActionsMatchDocumentedPolicy(t *testing.T) {
+	documented := getDocumentedAWSActions(t)
+	actions := AWSActions[types.InfraEKS]
+
+	for _, pattern := range documented {
+		found := false
+
+		for _, action := range actions {
+			if matchesAction(pattern, action) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("documented action %s is not checked for eks", pattern)
+		}
+	}
+
+	for _, action := range actions {
+		found := false
+
+		for _, pattern := range documented {
+			if matchesAction(pattern, action) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("action %s is checked for eks, but is not in the documented policy", action)
+		}
+	}
+}
+
+func TestPermissionsAreValid(t *testing.T) {
+	for kind, actions := range AWSActions {
+		seen := make(map[string]bool)
+
+		for _, action := range actions {
+			if !awsActionRegex.MatchString(action) {
+				t.Errorf("invalid action %s for %s: actions must not contain wildcards", action, kind)
+			}
+
+			if seen[action] {
+				t.Errorf("duplicate action %s for %s", action, kind)
+			}
+
+			seen[action] = true
+		}
+	}
+
+	for kind, permissions := range GCPPermissions {
+		seen := make(map[string]bool)
+
+		for _, permission := range permissions {
+			if !gcpPermissionRegex.MatchString(permission) {
+				t.Errorf("invalid permission %s for %s", permission, kind)
+			}
+
+			if seen[permission] {
+				t.Errorf("duplicate permission %s for %s", permission, kind)
+			}
+
+			seen[permission] = true
+		}
+	}
+}
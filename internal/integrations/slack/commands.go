@@ -0,0 +1,161 @@
+package slack
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRequestAge is the maximum age of a Slack request, which protects against replays
+const maxRequestAge = 5 * time.Minute
+
+// VerifyRequest verifies the signature of a request from Slack, following
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func VerifyRequest(signingSecret, timestamp, signature string, body []byte) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+
+	if err != nil || math.Abs(float64(time.Now().Unix()-ts)) > maxRequestAge.Seconds() {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Message is a message that is sent in response to a slash command or an interaction
+type Message struct {
+	// ResponseType is either "ephemeral", which is only visible to the user who ran the
+	// command, or "in_channel"
+	ResponseType string `json:"response_type,omitempty"`
+	Text         string `json:"text"`
+
+	// Blocks are either a *SlackBlock or an *ActionsBlock
+	Blocks []interface{} `json:"blocks,omitempty"`
+}
+
+// ActionsBlock is a block of buttons in a message
+type ActionsBlock struct {
+	Type     string    `json:"type"`
+	Elements []*Button `json:"elements"`
+}
+
+// Button is a button of an actions block, whose value is sent back on click
+type Button struct {
+	Type     string     `json:"type"`
+	Text     *SlackText `json:"text"`
+	ActionID string     `json:"action_id"`
+	Value    string     `json:"value"`
+}
+
+// NewButton returns a button that sends its value to the interactions endpoint
+func NewButton(text, actionID, value string) *Button {
+	return &Button{
+		Type: "button",
+		Text: &SlackText{
+			Type: "plain_text",
+			Text: text,
+		},
+		ActionID: actionID,
+		Value:    value,
+	}
+}
+
+// InteractionPayload is the payload of a click on a button of a message
+type InteractionPayload struct {
+	Type string `json:"type"`
+	Team struct {
+		ID string `json:"id"`
+	} `json:"team"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// PostResponse posts a message to the response URL of a slash command or interaction,
+// which can be used up to 30 minutes after the command was run
+func PostResponse(responseURL string, msg *Message) error {
+	payload, err := json.Marshal(msg)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(responseURL, "application/json", bytes.NewReader(payload))
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not post slack response: status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type userInfoResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	User  struct {
+		Profile struct {
+			Email string `json:"email"`
+		} `json:"profile"`
+	} `json:"user"`
+}
+
+// GetUserEmail returns the email of a Slack user, which needs the users:read.email scope
+func GetUserEmail(accessToken, userID string) (string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("https://slack.com/api/users.info?user=%s", userID), nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	resp, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	userInfo := &userInfoResponse{}
+
+	if err := json.NewDecoder(resp.Body).Decode(userInfo); err != nil {
+		return "", err
+	}
+
+	if !userInfo.OK {
+		return "", fmt.Errorf("could not get slack user: %s", userInfo.Error)
+	}
+
+	if userInfo.User.Profile.Email == "" {
+		return "", fmt.Errorf("slack user %s does not have an email", userID)
+	}
+
+	return userInfo.User.Profile.Email, nil
+}
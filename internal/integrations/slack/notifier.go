@@ -22,6 +22,25 @@ const (
 	StatusHelmDeployed DeploymentStatus = "helm_deployed"
 	StatusPodCrashed   DeploymentStatus = "pod_crashed"
 	StatusHelmFailed   DeploymentStatus = "helm_failed"
+	StatusRolledBack   DeploymentStatus = "rolled_back"
+
+	// change request statuses are sent for protected releases. Info is set to the
+	// change that was requested.
+	StatusChangeRequested DeploymentStatus = "change_requested"
+	StatusChangeApproved  DeploymentStatus = "change_approved"
+	StatusChangeRejected  DeploymentStatus = "change_rejected"
+
+	// StatusAddonUpdateAvailable is sent when a newer version of the chart of an addon
+	// is found. Info is set to the current and latest versions.
+	StatusAddonUpdateAvailable DeploymentStatus = "addon_update_available"
+
+	// StatusReleaseStale is sent when a release is found to be stale. Info is set to the
+	// reasons that the release is stale.
+	StatusReleaseStale DeploymentStatus = "release_stale"
+
+	// StatusScheduledDeployFailed is sent when a scheduled deploy cannot be started, for
+	// example when the release was deleted. Info is set to the error.
+	StatusScheduledDeployFailed DeploymentStatus = "scheduled_deploy_failed"
 )
 
 type NotifyOpts struct {
@@ -52,6 +71,9 @@ type NotifyOpts struct {
 	Timestamp *time.Time
 
 	Version int
+
+	// Message describes the changes of a deployment, and is only shown for deploys
+	Message string
 }
 
 type SlackNotifier struct {
@@ -94,6 +116,12 @@ func (s *SlackNotifier) Notify(opts *NotifyOpts) error {
 		if opts.Status == StatusHelmFailed && !s.Config.Failure {
 			return nil
 		}
+		if opts.Status == StatusRolledBack && !s.Config.Failure {
+			return nil
+		}
+		if opts.Status == StatusScheduledDeployFailed && !s.Config.Failure {
+			return nil
+		}
 	}
 
 	// we create a basic payload as a fallback if the detailed payload with "info" fails, due to
@@ -140,10 +168,18 @@ func (s *SlackNotifier) Notify(opts *NotifyOpts) error {
 func getSlackBlocks(opts *NotifyOpts) ([]*SlackBlock, []*SlackBlock) {
 	res := []*SlackBlock{}
 
-	if opts.Status == StatusHelmDeployed || opts.Status == StatusHelmFailed {
+	if opts.Status == StatusHelmDeployed || opts.Status == StatusHelmFailed || opts.Status == StatusRolledBack {
 		res = append(res, getHelmMessageBlock(opts))
 	} else if opts.Status == StatusPodCrashed {
 		res = append(res, getPodCrashedMessageBlock(opts))
+	} else if isChangeRequestStatus(opts.Status) {
+		res = append(res, getChangeRequestMessageBlock(opts))
+	} else if opts.Status == StatusAddonUpdateAvailable {
+		res = append(res, getAddonUpdateMessageBlock(opts))
+	} else if opts.Status == StatusReleaseStale {
+		res = append(res, getReleaseStaleMessageBlock(opts))
+	} else if opts.Status == StatusScheduledDeployFailed {
+		res = append(res, getScheduledDeployFailedMessageBlock(opts))
 	}
 
 	res = append(
@@ -162,8 +198,12 @@ func getSlackBlocks(opts *NotifyOpts) ([]*SlackBlock, []*SlackBlock) {
 		)
 	}
 
-	if opts.Status == StatusHelmDeployed || opts.Status == StatusHelmFailed {
+	if opts.Status == StatusHelmDeployed || opts.Status == StatusHelmFailed || opts.Status == StatusRolledBack {
 		res = append(res, getMarkdownBlock(fmt.Sprintf("*Version:* %d", opts.Version)))
+
+		if opts.Message != "" {
+			res = append(res, getMarkdownBlock(fmt.Sprintf("*Message:* %s", opts.Message)))
+		}
 	}
 
 	basicRes := res
@@ -201,6 +241,8 @@ func getHelmMessageBlock(opts *NotifyOpts) *SlackBlock {
 		md = getHelmSuccessMessage(opts)
 	case StatusHelmFailed:
 		md = getHelmFailedMessage(opts)
+	case StatusRolledBack:
+		md = getRolledBackMessage(opts)
 	}
 
 	return getMarkdownBlock(md)
@@ -224,6 +266,18 @@ func getInfoBlock(opts *NotifyOpts) *SlackBlock {
 		md = getFailedInfoMessage(opts)
 	case StatusPodCrashed:
 		md = getFailedInfoMessage(opts)
+	case StatusRolledBack:
+		md = getFailedInfoMessage(opts)
+	case StatusScheduledDeployFailed:
+		md = getFailedInfoMessage(opts)
+	case StatusChangeRequested:
+		if opts.Info == "" {
+			return nil
+		}
+
+		md = fmt.Sprintf("```\n%s\n```", opts.Info)
+	case StatusAddonUpdateAvailable, StatusReleaseStale:
+		md = opts.Info
 	default:
 		return nil
 	}
@@ -247,6 +301,75 @@ func getHelmFailedMessage(opts *NotifyOpts) string {
 	)
 }
 
+func getRolledBackMessage(opts *NotifyOpts) string {
+	return fmt.Sprintf(
+		":leftwards_arrow_with_hook: Your application %s failed its health checks after deploying, and was rolled back on Porter. <%s|View the status here.>",
+		"`"+opts.Name+"`",
+		opts.URL,
+	)
+}
+
+func isChangeRequestStatus(status DeploymentStatus) bool {
+	return status == StatusChangeRequested || status == StatusChangeApproved || status == StatusChangeRejected
+}
+
+func getChangeRequestMessageBlock(opts *NotifyOpts) *SlackBlock {
+	var md string
+
+	switch opts.Status {
+	case StatusChangeRequested:
+		md = fmt.Sprintf(
+			":raised_hand: A change to the protected application %s is waiting for approval on Porter. <%s|Review the change.>",
+			"`"+opts.Name+"`",
+			opts.URL,
+		)
+	case StatusChangeApproved:
+		md = fmt.Sprintf(
+			":white_check_mark: A change to the protected application %s was approved on Porter. <%s|View the application.>",
+			"`"+opts.Name+"`",
+			opts.URL,
+		)
+	case StatusChangeRejected:
+		md = fmt.Sprintf(
+			":no_entry: A change to the protected application %s was rejected on Porter. <%s|View the application.>",
+			"`"+opts.Name+"`",
+			opts.URL,
+		)
+	}
+
+	return getMarkdownBlock(md)
+}
+
+func getAddonUpdateMessageBlock(opts *NotifyOpts) *SlackBlock {
+	md := fmt.Sprintf(
+		":arrow_up: A new version of the add-on %s is available on Porter. <%s|View the add-on.>",
+		"`"+opts.Name+"`",
+		opts.URL,
+	)
+
+	return getMarkdownBlock(md)
+}
+
+func getReleaseStaleMessageBlock(opts *NotifyOpts) *SlackBlock {
+	md := fmt.Sprintf(
+		":hourglass: Your application %s looks unused and may be safe to clean up. <%s|View the application.>",
+		"`"+opts.Name+"`",
+		opts.URL,
+	)
+
+	return getMarkdownBlock(md)
+}
+
+func getScheduledDeployFailedMessageBlock(opts *NotifyOpts) *SlackBlock {
+	md := fmt.Sprintf(
+		":x: A scheduled deploy of your application %s could not be started on Porter. <%s|View the application.>",
+		"`"+opts.Name+"`",
+		opts.URL,
+	)
+
+	return getMarkdownBlock(md)
+}
+
 func getFailedInfoMessage(opts *NotifyOpts) string {
 	info := opts.Info
 
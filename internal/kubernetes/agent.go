@@ -9,13 +9,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	goerrors "errors"
+	goruntime "runtime"
 
 	"github.com/porter-dev/porter/api/server/shared/websocket"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner"
@@ -31,7 +31,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
-	v1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -715,8 +715,8 @@ func (a *Agent) GetJobPods(namespace, jobName string) ([]v1.Pod, error) {
 }
 
 // GetIngress gets ingress given the name and namespace
-func (a *Agent) GetIngress(namespace string, name string) (*v1beta1.Ingress, error) {
-	resp, err := a.Clientset.ExtensionsV1beta1().Ingresses(namespace).Get(
+func (a *Agent) GetIngress(namespace string, name string) (*networkingv1.Ingress, error) {
+	resp, err := a.Clientset.NetworkingV1().Ingresses(namespace).Get(
 		context.TODO(),
 		name,
 		metav1.GetOptions{},
@@ -1314,37 +1314,100 @@ var b64 = base64.StdEncoding
 
 var magicGzip = []byte{0x1f, 0x8b, 0x08}
 
-func decodeRelease(data string) (*rspb.Release, error) {
-	// base64 decode string
-	b, err := b64.DecodeString(data)
+// releaseBufferPool and gzipReaderPool reuse the buffers and readers that decode release
+// secrets, since a cluster can have thousands of release secrets that are decoded on
+// every list
+var releaseBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+var gzipReaderPool sync.Pool
+
+// DecodeReleaseOpts are the options for decoding a release from its secret
+type DecodeReleaseOpts struct {
+	// SkipManifest leaves the manifest of the release empty, which is the largest field
+	// of a release and is not needed when listing releases
+	SkipManifest bool
+}
+
+// releaseWithoutManifest decodes a release without allocating its manifest, since the
+// manifest field shadows the manifest of the embedded release
+type releaseWithoutManifest struct {
+	*rspb.Release
+
+	Manifest skippedJSONField `json:"manifest,omitempty"`
+}
+
+type skippedJSONField struct{}
+
+func (skippedJSONField) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+func decodeRelease(data []byte, opts DecodeReleaseOpts) (*rspb.Release, error) {
+	decoded := releaseBufferPool.Get().(*bytes.Buffer)
+	defer releaseBufferPool.Put(decoded)
+
+	decoded.Reset()
+	decoded.Grow(b64.DecodedLen(len(data)))
+
+	// base64 decode string into the pooled buffer
+	b := decoded.Bytes()[:b64.DecodedLen(len(data))]
+	n, err := b64.Decode(b, data)
 	if err != nil {
 		return nil, err
 	}
+	b = b[:n]
 
 	// For backwards compatibility with releases that were stored before
 	// compression was introduced we skip decompression if the
 	// gzip magic header is not found
-	if bytes.Equal(b[0:3], magicGzip) {
-		r, err := gzip.NewReader(bytes.NewReader(b))
+	if len(b) >= 3 && bytes.Equal(b[0:3], magicGzip) {
+		r, err := getGzipReader(bytes.NewReader(b))
 		if err != nil {
 			return nil, err
 		}
-		defer r.Close()
-		b2, err := ioutil.ReadAll(r)
-		if err != nil {
+		defer gzipReaderPool.Put(r)
+
+		decompressed := releaseBufferPool.Get().(*bytes.Buffer)
+		defer releaseBufferPool.Put(decompressed)
+
+		decompressed.Reset()
+
+		if _, err := decompressed.ReadFrom(r); err != nil {
 			return nil, err
 		}
-		b = b2
+		b = decompressed.Bytes()
 	}
 
 	var rls rspb.Release
 	// unmarshal release object bytes
-	if err := json.Unmarshal(b, &rls); err != nil {
+	if opts.SkipManifest {
+		err = json.Unmarshal(b, &releaseWithoutManifest{Release: &rls})
+	} else {
+		err = json.Unmarshal(b, &rls)
+	}
+
+	if err != nil {
 		return nil, err
 	}
 	return &rls, nil
 }
 
+func getGzipReader(r io.Reader) (*gzip.Reader, error) {
+	if gr, ok := gzipReaderPool.Get().(*gzip.Reader); ok {
+		if err := gr.Reset(r); err != nil {
+			return nil, err
+		}
+
+		return gr, nil
+	}
+
+	return gzip.NewReader(r)
+}
+
 func contains(s []string, str string) bool {
 	for _, v := range s {
 		if v == str {
@@ -1356,6 +1419,13 @@ func contains(s []string, str string) bool {
 }
 
 func ParseSecretToHelmRelease(secret v1.Secret, chartList []string) (*rspb.Release, bool, error) {
+	return ParseSecretToHelmReleaseWithOpts(secret, chartList, DecodeReleaseOpts{})
+}
+
+// ParseSecretToHelmReleaseWithOpts decodes the release stored in a Helm release secret.
+// The returned bool is true if the secret is not a release secret, or if its release is
+// not in the chart list.
+func ParseSecretToHelmReleaseWithOpts(secret v1.Secret, chartList []string, opts DecodeReleaseOpts) (*rspb.Release, bool, error) {
 	if secret.Type != "helm.sh/release.v1" {
 		return nil, true, nil
 	}
@@ -1366,7 +1436,7 @@ func ParseSecretToHelmRelease(secret v1.Secret, chartList []string) (*rspb.Relea
 		return nil, true, fmt.Errorf("release field not found")
 	}
 
-	helm_object, err := decodeRelease(string(releaseData))
+	helm_object, err := decodeRelease(releaseData, opts)
 
 	if err != nil {
 		return nil, true, err
@@ -1379,6 +1449,56 @@ func ParseSecretToHelmRelease(secret v1.Secret, chartList []string) (*rspb.Relea
 	return helm_object, false, nil
 }
 
+// ParseSecretsToHelmReleases decodes release secrets with a pool of workers, and returns
+// the releases in the order of their secrets. Secrets that are not release secrets, that
+// are not in the chart list, or that cannot be decoded are skipped.
+func ParseSecretsToHelmReleases(secrets []v1.Secret, chartList []string, opts DecodeReleaseOpts) []*rspb.Release {
+	decoded := make([]*rspb.Release, len(secrets))
+
+	workers := goruntime.GOMAXPROCS(0)
+
+	if workers > len(secrets) {
+		workers = len(secrets)
+	}
+
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				rel, isNotHelmRelease, err := ParseSecretToHelmReleaseWithOpts(secrets[i], chartList, opts)
+
+				if !isNotHelmRelease && err == nil {
+					decoded[i] = rel
+				}
+			}
+		}()
+	}
+
+	for i := range secrets {
+		indices <- i
+	}
+
+	close(indices)
+	wg.Wait()
+
+	res := make([]*rspb.Release, 0, len(decoded))
+
+	for _, rel := range decoded {
+		if rel != nil {
+			res = append(res, rel)
+		}
+	}
+
+	return res
+}
+
 func (a *Agent) StreamHelmReleases(namespace string, chartList []string, selectors string, rw *websocket.WebsocketSafeReadWriter) error {
 	run := func() error {
 		tweakListOptionsFunc := func(options *metav1.ListOptions) {
@@ -1567,6 +1687,43 @@ func (a *Agent) Provision(
 	return err
 }
 
+// IsProvisionerJobRunning returns true if a provisioner job that holds the given
+// generation of the operation lease of the infra has not finished
+func (a *Agent) IsProvisionerJobRunning(namespace, workspaceID string, generation int64) (bool, error) {
+	jobs, err := a.Clientset.BatchV1().Jobs(namespace).List(
+		context.TODO(),
+		metav1.ListOptions{
+			LabelSelector: fmt.Sprintf(
+				"%s=%s,%s=%d",
+				provisioner.WorkspaceIDLabel,
+				workspaceID,
+				provisioner.LeaseGenerationLabel,
+				generation,
+			),
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, job := range jobs.Items {
+		finished := false
+
+		for _, cond := range job.Status.Conditions {
+			if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == v1.ConditionTrue {
+				finished = true
+			}
+		}
+
+		if !finished {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (a *Agent) clearExistingJobs(j *batchv1.Job) error {
 	// find if existingJob already exists
 	existingJob, err := a.Clientset.BatchV1().Jobs(j.Namespace).Get(
@@ -1,6 +1,12 @@
 package kubernetes_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/porter-dev/porter/internal/kubernetes"
@@ -10,6 +16,8 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"helm.sh/helm/v3/pkg/release"
+
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -63,3 +71,135 @@ func TestOutOfClusterConfig(t *testing.T) {
 		}
 	}
 }
+
+func newReleaseSecret(t testing.TB, name string, version int) v1.Secret {
+	t.Helper()
+
+	rel := &release.Release{
+		Name:      name,
+		Namespace: "default",
+		Version:   version,
+		Manifest:  strings.Repeat("apiVersion: v1\nkind: ConfigMap\n---\n", 100),
+		Config: map[string]interface{}{
+			"replicaCount": 1,
+		},
+	}
+
+	relBytes, err := json.Marshal(rel)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+
+	if _, err := w.Write(relBytes); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, version),
+			Namespace: "default",
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{
+			"release": []byte(base64.StdEncoding.EncodeToString(buf.Bytes())),
+		},
+	}
+}
+
+func TestParseSecretsToHelmReleases(t *testing.T) {
+	secrets := []v1.Secret{
+		newReleaseSecret(t, "web", 1),
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "not-a-release", Namespace: "default"},
+			Type:       v1.SecretTypeOpaque,
+		},
+		newReleaseSecret(t, "worker", 2),
+	}
+
+	releases := kubernetes.ParseSecretsToHelmReleases(secrets, []string{}, kubernetes.DecodeReleaseOpts{})
+
+	if len(releases) != 2 {
+		t.Fatalf("expected 2 releases, got %d", len(releases))
+	}
+
+	if releases[0].Name != "web" || releases[1].Name != "worker" || releases[1].Version != 2 {
+		t.Errorf("expected releases web and worker in order, got %s and %s", releases[0].Name, releases[1].Name)
+	}
+
+	if releases[0].Manifest == "" {
+		t.Errorf("expected the manifest to be decoded")
+	}
+
+	releases = kubernetes.ParseSecretsToHelmReleases(secrets, []string{"worker"}, kubernetes.DecodeReleaseOpts{
+		SkipManifest: true,
+	})
+
+	if len(releases) != 1 || releases[0].Name != "worker" {
+		t.Fatalf("expected only the worker release, got %d releases", len(releases))
+	}
+
+	if releases[0].Manifest != "" {
+		t.Errorf("expected the manifest to be skipped")
+	}
+
+	if releases[0].Config["replicaCount"] != float64(1) {
+		t.Errorf("expected the values to be decoded, got %v", releases[0].Config)
+	}
+}
+
+func newReleaseSecrets(b *testing.B, count int) []v1.Secret {
+	secrets := make([]v1.Secret, 0, count)
+
+	for i := 0; i < count; i++ {
+		secrets = append(secrets, newReleaseSecret(b, fmt.Sprintf("release-%d", i), 1))
+	}
+
+	return secrets
+}
+
+func BenchmarkParseSecretToHelmRelease(b *testing.B) {
+	secrets := newReleaseSecrets(b, 1000)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		for _, secret := range secrets {
+			if _, _, err := kubernetes.ParseSecretToHelmRelease(secret, []string{}); err != nil {
+				b.Fatalf("%v", err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseSecretsToHelmReleases(b *testing.B) {
+	secrets := newReleaseSecrets(b, 1000)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		kubernetes.ParseSecretsToHelmReleases(secrets, []string{}, kubernetes.DecodeReleaseOpts{})
+	}
+}
+
+func BenchmarkParseSecretsToHelmReleasesSkipManifest(b *testing.B) {
+	secrets := newReleaseSecrets(b, 1000)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		kubernetes.ParseSecretsToHelmReleases(secrets, []string{}, kubernetes.DecodeReleaseOpts{
+			SkipManifest: true,
+		})
+	}
+}
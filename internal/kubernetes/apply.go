@@ -0,0 +1,326 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FieldManager is the field manager of the fields that Porter sets with server-side apply
+const FieldManager = "porter"
+
+// ApplyObject creates or updates an object of any kind, including the kinds of CRDs,
+// with server-side apply. Porter takes ownership of the fields of the object if they
+// are managed by another field manager.
+func (a *Agent) ApplyObject(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	client, err := a.getResourceInterface(obj)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(obj)
+
+	if err != nil {
+		return nil, err
+	}
+
+	force := true
+
+	return client.Patch(
+		context.TODO(),
+		obj.GetName(),
+		types.ApplyPatchType,
+		data,
+		metav1.PatchOptions{
+			FieldManager: FieldManager,
+			Force:        &force,
+		},
+	)
+}
+
+// DeleteObject deletes an object of any kind. Objects that do not exist are ignored.
+func (a *Agent) DeleteObject(obj *unstructured.Unstructured) error {
+	client, err := a.getResourceInterface(obj)
+
+	if err != nil {
+		return err
+	}
+
+	err = client.Delete(context.TODO(), obj.GetName(), metav1.DeleteOptions{})
+
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// PatchObjectMetadata adds labels and annotations to an existing object of any kind, or
+// returns IsNotFoundError if the object does not exist
+func (a *Agent) PatchObjectMetadata(obj *unstructured.Unstructured, labels, annotations map[string]string) error {
+	client, err := a.getResourceInterface(obj)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      labels,
+			"annotations": annotations,
+		},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Patch(context.TODO(), obj.GetName(), types.MergePatchType, data, metav1.PatchOptions{})
+
+	return wrapNotFound(err)
+}
+
+// RestoreObjectMetadata reverts PatchObjectMetadata with the state of the object before it
+// was patched: the labels and annotations are set back to their previous values, or
+// removed if the object did not have them
+func (a *Agent) RestoreObjectMetadata(prev *unstructured.Unstructured, labels, annotations map[string]string) error {
+	client, err := a.getResourceInterface(prev)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      getPreviousValues(prev.GetLabels(), labels),
+			"annotations": getPreviousValues(prev.GetAnnotations(), annotations),
+		},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Patch(context.TODO(), prev.GetName(), types.MergePatchType, data, metav1.PatchOptions{})
+
+	return wrapNotFound(err)
+}
+
+// getPreviousValues returns the merge patch that sets the keys of values back to their
+// previous values. Keys without a previous value are set to null, which removes them.
+func getPreviousValues(prev, values map[string]string) map[string]interface{} {
+	res := make(map[string]interface{})
+
+	for key := range values {
+		if prevVal, ok := prev[key]; ok {
+			res[key] = prevVal
+		} else {
+			res[key] = nil
+		}
+	}
+
+	return res
+}
+
+// GetObject returns the live state of an object of any kind, or returns IsNotFoundError if
+// the object does not exist
+func (a *Agent) GetObject(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	client, err := a.getResourceInterface(obj)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	return res, nil
+}
+
+// getResourceInterface returns a dynamic client for the resource of an object, which is
+// found through the discovery of the cluster
+func (a *Agent) getResourceInterface(obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	if a.RESTClientGetter == nil {
+		return nil, fmt.Errorf("agent does not support dynamic clients")
+	}
+
+	restConf, err := a.RESTClientGetter.ToRESTConfig()
+
+	if err != nil {
+		return nil, err
+	}
+
+	mapper, err := a.RESTClientGetter.ToRESTMapper()
+
+	if err != nil {
+		return nil, err
+	}
+
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+
+	if err != nil {
+		return nil, fmt.Errorf("resource of kind %s is not served by the cluster: %w", gvk.String(), err)
+	}
+
+	dynClient, err := dynamic.NewForConfig(restConf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return dynClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+	}
+
+	return dynClient.Resource(mapping.Resource), nil
+}
+
+// IsResourceServed returns true if the cluster serves a resource, such as the resource
+// of a CRD that may not be installed
+func (a *Agent) IsResourceServed(gvr schema.GroupVersionResource) (bool, error) {
+	resources, err := a.Clientset.Discovery().ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+
+	if err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Name == gvr.Resource {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ApplyIngress creates an ingress, or replaces the existing ingress with the same name
+func (a *Agent) ApplyIngress(ingress *networkingv1.Ingress) (*networkingv1.Ingress, error) {
+	client := a.Clientset.NetworkingV1().Ingresses(ingress.Namespace)
+
+	prev, err := client.Get(context.TODO(), ingress.Name, metav1.GetOptions{})
+
+	if err != nil && errors.IsNotFound(err) {
+		return client.Create(context.TODO(), ingress, metav1.CreateOptions{})
+	} else if err != nil {
+		return nil, err
+	}
+
+	ingress.ObjectMeta.ResourceVersion = prev.ObjectMeta.ResourceVersion
+
+	return client.Update(context.TODO(), ingress, metav1.UpdateOptions{})
+}
+
+// DeleteIngress deletes an ingress. Ingresses that do not exist are ignored.
+func (a *Agent) DeleteIngress(namespace, name string) error {
+	err := a.Clientset.NetworkingV1().Ingresses(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// GetNetworkPolicy returns a network policy, or IsNotFoundError if it does not exist
+func (a *Agent) GetNetworkPolicy(namespace, name string) (*networkingv1.NetworkPolicy, error) {
+	policy, err := a.Clientset.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+
+	return policy, wrapNotFound(err)
+}
+
+// ApplyNetworkPolicy creates a network policy, or replaces the existing network policy
+// with the same name
+func (a *Agent) ApplyNetworkPolicy(policy *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, error) {
+	client := a.Clientset.NetworkingV1().NetworkPolicies(policy.Namespace)
+
+	prev, err := client.Get(context.TODO(), policy.Name, metav1.GetOptions{})
+
+	if err != nil && errors.IsNotFound(err) {
+		return client.Create(context.TODO(), policy, metav1.CreateOptions{})
+	} else if err != nil {
+		return nil, err
+	}
+
+	policy.ObjectMeta.ResourceVersion = prev.ObjectMeta.ResourceVersion
+
+	return client.Update(context.TODO(), policy, metav1.UpdateOptions{})
+}
+
+// DeleteNetworkPolicy deletes a network policy. Network policies that do not exist are
+// ignored.
+func (a *Agent) DeleteNetworkPolicy(namespace, name string) error {
+	err := a.Clientset.NetworkingV1().NetworkPolicies(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// GetHorizontalPodAutoscaler returns a horizontal pod autoscaler, or IsNotFoundError if
+// it does not exist
+func (a *Agent) GetHorizontalPodAutoscaler(namespace, name string) (*autoscalingv2beta2.HorizontalPodAutoscaler, error) {
+	hpa, err := a.Clientset.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).Get(
+		context.TODO(),
+		name,
+		metav1.GetOptions{},
+	)
+
+	return hpa, wrapNotFound(err)
+}
+
+// ApplyHorizontalPodAutoscaler creates a horizontal pod autoscaler, or replaces the
+// existing horizontal pod autoscaler with the same name
+func (a *Agent) ApplyHorizontalPodAutoscaler(
+	hpa *autoscalingv2beta2.HorizontalPodAutoscaler,
+) (*autoscalingv2beta2.HorizontalPodAutoscaler, error) {
+	client := a.Clientset.AutoscalingV2beta2().HorizontalPodAutoscalers(hpa.Namespace)
+
+	prev, err := client.Get(context.TODO(), hpa.Name, metav1.GetOptions{})
+
+	if err != nil && errors.IsNotFound(err) {
+		return client.Create(context.TODO(), hpa, metav1.CreateOptions{})
+	} else if err != nil {
+		return nil, err
+	}
+
+	hpa.ObjectMeta.ResourceVersion = prev.ObjectMeta.ResourceVersion
+
+	return client.Update(context.TODO(), hpa, metav1.UpdateOptions{})
+}
+
+// DeleteHorizontalPodAutoscaler deletes a horizontal pod autoscaler. Horizontal pod
+// autoscalers that do not exist are ignored.
+func (a *Agent) DeleteHorizontalPodAutoscaler(namespace, name string) error {
+	err := a.Clientset.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).Delete(
+		context.TODO(),
+		name,
+		metav1.DeleteOptions{},
+	)
+
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestApplyNetworkPolicy(t *testing.T) {
+	agent := newAgentFixture(t)
+
+	// applying twice replaces the existing network policy
+	for _, label := range []string{"web", "worker"} {
+		_, err := agent.ApplyNetworkPolicy(&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-policy",
+				Namespace: "default",
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": label},
+				},
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	policy, err := agent.GetNetworkPolicy("default", "test-policy")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if app := policy.Spec.PodSelector.MatchLabels["app"]; app != "worker" {
+		t.Errorf("expected pod selector app=worker, got app=%s", app)
+	}
+
+	if err := agent.DeleteNetworkPolicy("default", "test-policy"); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if _, err := agent.GetNetworkPolicy("default", "test-policy"); err != kubernetes.IsNotFoundError {
+		t.Errorf("expected the network policy to be deleted, got %v", err)
+	}
+
+	// deleting a network policy that does not exist is not an error
+	if err := agent.DeleteNetworkPolicy("default", "test-policy"); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestIsResourceServed(t *testing.T) {
+	agent := newAgentFixture(t)
+
+	agent.Clientset.(*fake.Clientset).Fake.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "snapshot.storage.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "volumesnapshots", Kind: "VolumeSnapshot", Namespaced: true},
+			},
+		},
+	}
+
+	served, err := agent.IsResourceServed(kubernetes.VolumeSnapshotResource)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if !served {
+		t.Errorf("expected volumesnapshots to be served")
+	}
+
+	served, err = agent.IsResourceServed(kubernetes.VolumeSnapshotResource.GroupVersion().WithResource("volumesnapshotclasses"))
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if served {
+		t.Errorf("expected volumesnapshotclasses not to be served")
+	}
+}
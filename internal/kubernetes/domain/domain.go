@@ -59,6 +59,8 @@ func GetNGINXIngressServiceIP(clientset kubernetes.Interface) (string, bool, err
 type DNSRecord models.DNSRecord
 
 type CreateDNSRecordConfig struct {
+	ClusterID   uint
+	Namespace   string
 	ReleaseName string
 	RootDomain  string
 	Endpoint    string
@@ -76,6 +78,9 @@ func (c *CreateDNSRecordConfig) NewDNSRecordForEndpoint() *models.DNSRecord {
 		RootDomain:      c.RootDomain,
 		Endpoint:        c.Endpoint,
 		Hostname:        fmt.Sprintf("%s.%s", subdomain, c.RootDomain),
+		ClusterID:       c.ClusterID,
+		Namespace:       c.Namespace,
+		ReleaseName:     c.ReleaseName,
 	}
 }
 
@@ -90,3 +95,15 @@ func (e *DNSRecord) CreateDomain(powerDNSClient *powerdns.Client) error {
 
 	return powerDNSClient.CreateCNAMERecord(e.Endpoint, domain)
 }
+
+// DeleteDomain deletes the record for the vanity domain
+func (e *DNSRecord) DeleteDomain(powerDNSClient *powerdns.Client) error {
+	isIPv4 := net.ParseIP(e.Endpoint) != nil
+	domain := fmt.Sprintf("%s.%s", e.SubdomainPrefix, e.RootDomain)
+
+	if isIPv4 {
+		return powerDNSClient.DeleteRecord("A", domain)
+	}
+
+	return powerDNSClient.DeleteRecord("CNAME", domain)
+}
@@ -0,0 +1,64 @@
+package envgroup
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	v1 "k8s.io/api/core/v1"
+)
+
+// CloneEnvGroup copies the latest version of an env group, including its secret
+// variables and linked applications, to a namespace that may be in a different cluster.
+// If the env group already exists in the target namespace, a new version is created.
+func CloneEnvGroup(
+	srcAgent, dstAgent *kubernetes.Agent,
+	name, srcNamespace, dstNamespace string,
+) (*v1.ConfigMap, error) {
+	envGroup, err := GetEnvGroup(srcAgent, name, srcNamespace, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	input := types.ConfigMapInput{
+		Name:            name,
+		Namespace:       dstNamespace,
+		Variables:       make(map[string]string),
+		SecretVariables: make(map[string]string),
+	}
+
+	secret, _, err := srcAgent.GetLatestVersionedSecret(name, srcNamespace)
+
+	if err != nil && !errors.Is(err, kubernetes.IsNotFoundError) {
+		return nil, err
+	}
+
+	for key, val := range envGroup.Variables {
+		if strings.Contains(val, "PORTERSECRET") && secret != nil {
+			if secretVal, ok := secret.Data[key]; ok {
+				input.SecretVariables[key] = string(secretVal)
+				continue
+			}
+		}
+
+		input.Variables[key] = val
+	}
+
+	cm, err := CreateEnvGroup(dstAgent, input)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range envGroup.Applications {
+		cm, err = dstAgent.AddApplicationToVersionedConfigMap(cm, app)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cm, nil
+}
@@ -0,0 +1,78 @@
+package envgroup
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// AddSyncedEnvGroup returns the values of a release with an env group synced to the
+// release under container.env.synced, so that the variables of the env group are
+// injected into the containers of the release. If an older version of the env group is
+// already synced, it is replaced.
+func AddSyncedEnvGroup(values map[string]interface{}, envGroup *types.EnvGroup) map[string]interface{} {
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+
+	container, ok := values["container"].(map[string]interface{})
+
+	if !ok {
+		container = make(map[string]interface{})
+		values["container"] = container
+	}
+
+	env, ok := container["env"].(map[string]interface{})
+
+	if !ok {
+		env = make(map[string]interface{})
+		container["env"] = env
+	}
+
+	keyNames := make([]string, 0, len(envGroup.Variables))
+
+	for key := range envGroup.Variables {
+		keyNames = append(keyNames, key)
+	}
+
+	sort.Strings(keyNames)
+
+	keys := make([]interface{}, 0, len(keyNames))
+
+	for _, key := range keyNames {
+		keys = append(keys, map[string]interface{}{
+			"name":   key,
+			"secret": strings.Contains(envGroup.Variables[key], "PORTERSECRET"),
+		})
+	}
+
+	section := map[string]interface{}{
+		"name":    envGroup.Name,
+		"version": envGroup.Version,
+		"keys":    keys,
+	}
+
+	synced := make([]interface{}, 0)
+	found := false
+
+	if curr, ok := env["synced"].([]interface{}); ok {
+		for _, s := range curr {
+			if currSection, ok := s.(map[string]interface{}); ok && currSection["name"] == envGroup.Name {
+				synced = append(synced, section)
+				found = true
+				continue
+			}
+
+			synced = append(synced, s)
+		}
+	}
+
+	if !found {
+		synced = append(synced, section)
+	}
+
+	env["synced"] = synced
+
+	return values
+}
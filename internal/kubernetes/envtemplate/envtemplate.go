@@ -0,0 +1,244 @@
+package envtemplate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// templateRegex matches values that call one of the template functions. Other values
+// are not treated as templates, so that values which happen to contain braces are left
+// untouched.
+var templateRegex = regexp.MustCompile(`\{\{-?\s*(svc|db)\s`)
+
+// IsTemplate returns true if an env variable value references another release or a
+// managed database
+func IsTemplate(value string) bool {
+	return templateRegex.MatchString(value)
+}
+
+// Validate checks that a template can be parsed, without resolving it
+func Validate(value string) error {
+	_, err := template.New("env").Funcs(template.FuncMap{
+		"svc": func(args ...string) (string, error) { return "", nil },
+		"db":  func(name, key string) (string, error) { return "", nil },
+	}).Parse(value)
+
+	return err
+}
+
+// Resolver resolves the templates in env variable values of releases in a namespace.
+// The following functions are supported:
+//
+//	{{ svc "backend" }} is the cluster DNS name of the service of the backend release
+//	{{ svc "backend" "other-namespace" }} looks for the release in another namespace
+//	{{ db "main" "DATABASE_URL" }} is a credential of the managed database named main
+//
+// The database keys are PGHOST, PGPORT, PGUSER, PGPASSWORD, PGDATABASE and DATABASE_URL.
+type Resolver struct {
+	agent     *kubernetes.Agent
+	repo      repository.Repository
+	cluster   *models.Cluster
+	namespace string
+
+	// databases caches the credentials of the managed databases of the cluster, which
+	// are read the first time a database is referenced
+	databases map[string]map[string]string
+}
+
+func NewResolver(
+	agent *kubernetes.Agent,
+	repo repository.Repository,
+	cluster *models.Cluster,
+	namespace string,
+) *Resolver {
+	return &Resolver{
+		agent:     agent,
+		repo:      repo,
+		cluster:   cluster,
+		namespace: namespace,
+	}
+}
+
+// Resolve resolves a template. Values that are not templates are returned as-is.
+func (r *Resolver) Resolve(value string) (string, error) {
+	if !IsTemplate(value) {
+		return value, nil
+	}
+
+	tmpl, err := template.New("env").Funcs(template.FuncMap{
+		"svc": r.svc,
+		"db":  r.db,
+	}).Parse(value)
+
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// svc returns the cluster DNS name of the service of a release. Services are matched by
+// the app.kubernetes.io/instance label that Helm charts set, or by name.
+func (r *Resolver) svc(args ...string) (string, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return "", fmt.Errorf("svc expects a release name and an optional namespace")
+	}
+
+	releaseName, namespace := args[0], r.namespace
+
+	if len(args) == 2 {
+		namespace = args[1]
+	}
+
+	services, err := r.agent.Clientset.CoreV1().Services(namespace).List(
+		context.TODO(),
+		metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("app.kubernetes.io/instance=%s", releaseName),
+		},
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0)
+
+	for _, service := range services.Items {
+		// a service named after the release is preferred over the other services of the
+		// release
+		if service.Name == releaseName {
+			names = []string{service.Name}
+			break
+		}
+
+		names = append(names, service.Name)
+	}
+
+	if len(names) == 0 {
+		service, err := r.agent.Clientset.CoreV1().Services(namespace).Get(
+			context.TODO(),
+			releaseName,
+			metav1.GetOptions{},
+		)
+
+		if err != nil {
+			return "", fmt.Errorf("no service found for release %s in namespace %s", releaseName, namespace)
+		}
+
+		names = append(names, service.Name)
+	}
+
+	sort.Strings(names)
+
+	return fmt.Sprintf("%s.%s.svc.cluster.local", names[0], namespace), nil
+}
+
+// db returns a credential of a managed database of the cluster
+func (r *Resolver) db(name, key string) (string, error) {
+	if r.databases == nil {
+		databases, err := r.getDatabaseCredentials()
+
+		if err != nil {
+			return "", err
+		}
+
+		r.databases = databases
+	}
+
+	creds, ok := r.databases[name]
+
+	if !ok {
+		return "", fmt.Errorf("no managed database named %s found in the cluster", name)
+	}
+
+	val, ok := creds[key]
+
+	if !ok {
+		return "", fmt.Errorf("unknown database key %s", key)
+	}
+
+	return val, nil
+}
+
+func (r *Resolver) getDatabaseCredentials() (map[string]map[string]string, error) {
+	res := make(map[string]map[string]string)
+
+	databases, err := r.repo.Database().ListDatabases(r.cluster.ProjectID, r.cluster.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, database := range databases {
+		infra, err := r.repo.Infra().ReadInfra(r.cluster.ProjectID, database.InfraID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		lastApplied := &types.RDSInfraLastApplied{}
+
+		if err := json.Unmarshal(infra.LastApplied, lastApplied); err != nil || lastApplied.CreateRDSInfraRequest == nil {
+			continue
+		}
+
+		res[lastApplied.DBName] = GetDatabaseCredentials(database, lastApplied)
+	}
+
+	return res, nil
+}
+
+// GetDatabaseCredentials returns the credentials of a managed database, keyed by the
+// names that can be used in templates
+func GetDatabaseCredentials(database *models.Database, lastApplied *types.RDSInfraLastApplied) map[string]string {
+	// split the instance endpoint on the port
+	port := "5432"
+	host := database.InstanceEndpoint
+
+	if strArr := strings.Split(database.InstanceEndpoint, ":"); len(strArr) == 2 {
+		host = strArr[0]
+		port = strArr[1]
+	}
+
+	scheme := "postgres"
+
+	if lastApplied.DBFamily == "mysql" {
+		scheme = "mysql"
+	}
+
+	dbURL := &url.URL{
+		Scheme: scheme,
+		User:   url.UserPassword(lastApplied.Username, lastApplied.Password),
+		Host:   fmt.Sprintf("%s:%s", host, port),
+		Path:   "/" + lastApplied.DBName,
+	}
+
+	return map[string]string{
+		"PGHOST":       host,
+		"PGPORT":       port,
+		"PGUSER":       lastApplied.Username,
+		"PGPASSWORD":   lastApplied.Password,
+		"PGDATABASE":   lastApplied.DBName,
+		"DATABASE_URL": dbURL.String(),
+	}
+}
@@ -0,0 +1,73 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ImagePolicyIncludeLabel is the namespace label that includes the pods of a namespace in
+// the image policies of the sigstore policy-controller
+const ImagePolicyIncludeLabel = "policy.sigstore.dev/include"
+
+// SetNamespaceImagePolicy includes a namespace in the image policies of the sigstore
+// policy-controller by labeling it, or excludes it by removing the label
+func (a *Agent) SetNamespaceImagePolicy(namespace string, include bool) error {
+	var value *string
+
+	if include {
+		trueStr := "true"
+		value = &trueStr
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]*string{
+				ImagePolicyIncludeLabel: value,
+			},
+		},
+	}
+
+	patchBytes, err := json.Marshal(patch)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = a.Clientset.CoreV1().Namespaces().Patch(
+		context.TODO(),
+		namespace,
+		k8sTypes.MergePatchType,
+		patchBytes,
+		metav1.PatchOptions{},
+	)
+
+	return wrapNotFound(err)
+}
+
+// ListImagePolicyNamespaces lists the names of the namespaces that are included in the
+// image policies of the sigstore policy-controller
+func (a *Agent) ListImagePolicyNamespaces() ([]string, error) {
+	list, err := a.Clientset.CoreV1().Namespaces().List(
+		context.TODO(),
+		metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=true", ImagePolicyIncludeLabel),
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0)
+
+	for _, ns := range list.Items {
+		res = append(res, ns.Name)
+	}
+
+	return res, nil
+}
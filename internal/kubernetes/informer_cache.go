@@ -0,0 +1,573 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	batchv1beta1listers "k8s.io/client-go/listers/batch/v1beta1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// StatusReader reads the status of pods and controllers, either from the api server
+// through an Agent or from the informer cache of a cluster
+type StatusReader interface {
+	GetPodsByLabel(selector string, namespace string) (*v1.PodList, error)
+	GetJobPods(namespace, jobName string) ([]v1.Pod, error)
+	ListJobsByLabel(namespace string, labels ...Label) ([]batchv1.Job, error)
+	GetDeployment(c grapher.Object) (*appsv1.Deployment, error)
+	GetStatefulSet(c grapher.Object) (*appsv1.StatefulSet, error)
+	GetReplicaSet(c grapher.Object) (*appsv1.ReplicaSet, error)
+	GetDaemonSet(c grapher.Object) (*appsv1.DaemonSet, error)
+	GetJob(c grapher.Object) (*batchv1.Job, error)
+	GetCronJob(c grapher.Object) (*batchv1beta1.CronJob, error)
+}
+
+// informerCacheSyncTimeout is how long a request waits for the informers of a cluster
+// to sync before it falls back to the api server
+const informerCacheSyncTimeout = 30 * time.Second
+
+// informerCacheExcludedNamespaces are the system namespaces whose objects are not cached,
+// so that the cache only holds the pods and controllers of applications. Reads of these
+// namespaces go to the api server.
+var informerCacheExcludedNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// InformerCache keeps shared informers for the pods and controllers of each connected
+// cluster, so that status endpoints read from a local cache instead of listing from the
+// api server on every request. The informers of a cluster are stopped once the cluster
+// has not been read from for the TTL.
+type InformerCache struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	clusters map[uint]*ClusterInformers
+}
+
+func NewInformerCache(ttl time.Duration) *InformerCache {
+	return &InformerCache{
+		ttl:      ttl,
+		clusters: make(map[uint]*ClusterInformers),
+	}
+}
+
+// ClusterInformers is the informer cache of a single cluster. It implements StatusReader.
+type ClusterInformers struct {
+	agent    *Agent
+	factory  informers.SharedInformerFactory
+	stopCh   chan struct{}
+	synced   chan struct{}
+	syncErr  error
+	lastUsed time.Time
+
+	pods         corelisters.PodLister
+	deployments  appslisters.DeploymentLister
+	statefulSets appslisters.StatefulSetLister
+	replicaSets  appslisters.ReplicaSetLister
+	daemonSets   appslisters.DaemonSetLister
+	jobs         batchlisters.JobLister
+
+	// only one of the CronJob listers is set, for the version of CronJobs that the
+	// cluster serves, and neither is set if the cluster serves no CronJobs
+	cronJobs     batchlisters.CronJobLister
+	betaCronJobs batchv1beta1listers.CronJobLister
+}
+
+// Get returns the informer cache of a cluster, and starts its informers with the
+// clientset of the agent if the cluster is not cached yet. It blocks until the informers
+// have synced, and returns an error if they do not sync in time.
+func (c *InformerCache) Get(clusterID uint, agent *Agent) (*ClusterInformers, error) {
+	c.mu.Lock()
+
+	ci, exists := c.clusters[clusterID]
+
+	if !exists {
+		ci = newClusterInformers(agent)
+		c.clusters[clusterID] = ci
+	}
+
+	ci.lastUsed = time.Now()
+
+	c.mu.Unlock()
+
+	if !exists {
+		go ci.start(informerCacheSyncTimeout)
+	}
+
+	<-ci.synced
+
+	if ci.syncErr != nil {
+		c.evict(clusterID, ci)
+
+		return nil, ci.syncErr
+	}
+
+	return ci, nil
+}
+
+// Evict stops the informers of a cluster, such as when the cluster is deleted
+func (c *InformerCache) Evict(clusterID uint) {
+	c.mu.Lock()
+	ci := c.clusters[clusterID]
+	c.mu.Unlock()
+
+	if ci != nil {
+		c.evict(clusterID, ci)
+	}
+}
+
+// Run stops the informers of idle clusters at the given interval until the context is
+// cancelled
+func (c *InformerCache) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.stopAll()
+			return
+		case <-ticker.C:
+			c.evictIdle()
+		}
+	}
+}
+
+func (c *InformerCache) evictIdle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for clusterID, ci := range c.clusters {
+		if time.Since(ci.lastUsed) > c.ttl {
+			delete(c.clusters, clusterID)
+			close(ci.stopCh)
+		}
+	}
+}
+
+// evict stops the informers of a cluster if they are still the cached informers of the
+// cluster, since they may have been replaced after a concurrent eviction
+func (c *InformerCache) evict(clusterID uint, ci *ClusterInformers) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.clusters[clusterID] == ci {
+		delete(c.clusters, clusterID)
+		close(ci.stopCh)
+	}
+}
+
+func (c *InformerCache) stopAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for clusterID, ci := range c.clusters {
+		delete(c.clusters, clusterID)
+		close(ci.stopCh)
+	}
+}
+
+func newClusterInformers(agent *Agent) *ClusterInformers {
+	return &ClusterInformers{
+		agent:  agent,
+		stopCh: make(chan struct{}),
+		synced: make(chan struct{}),
+	}
+}
+
+// start registers and starts the informers of the cluster, and waits until they have
+// synced or the timeout has passed
+func (ci *ClusterInformers) start(timeout time.Duration) {
+	defer close(ci.synced)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	go func() {
+		// stop waiting if the informers are stopped before they sync
+		select {
+		case <-ci.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := ci.register(ctx); err != nil {
+		ci.syncErr = err
+		return
+	}
+
+	ci.factory.Start(ci.stopCh)
+
+	for informerType, ok := range ci.factory.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			ci.syncErr = fmt.Errorf("informer cache for %s did not sync", informerType.String())
+			return
+		}
+	}
+}
+
+// register creates the informers of the cluster. Informers are only created if the
+// credentials of the cluster can list their resources in all namespaces, since informers
+// that cannot list retry until the sync timeout instead of failing.
+func (ci *ClusterInformers) register(ctx context.Context) error {
+	clientset := ci.agent.Clientset
+	fieldSelector := getInformerCacheFieldSelector()
+
+	cronJobVersion, err := ci.agent.getCronJobVersion()
+
+	if err != nil {
+		return err
+	}
+
+	checks := map[string]func(opts metav1.ListOptions) error{
+		"pods": func(opts metav1.ListOptions) error {
+			_, err := clientset.CoreV1().Pods("").List(ctx, opts)
+			return err
+		},
+		"deployments": func(opts metav1.ListOptions) error {
+			_, err := clientset.AppsV1().Deployments("").List(ctx, opts)
+			return err
+		},
+		"statefulsets": func(opts metav1.ListOptions) error {
+			_, err := clientset.AppsV1().StatefulSets("").List(ctx, opts)
+			return err
+		},
+		"replicasets": func(opts metav1.ListOptions) error {
+			_, err := clientset.AppsV1().ReplicaSets("").List(ctx, opts)
+			return err
+		},
+		"daemonsets": func(opts metav1.ListOptions) error {
+			_, err := clientset.AppsV1().DaemonSets("").List(ctx, opts)
+			return err
+		},
+		"jobs": func(opts metav1.ListOptions) error {
+			_, err := clientset.BatchV1().Jobs("").List(ctx, opts)
+			return err
+		},
+	}
+
+	switch cronJobVersion {
+	case "v1":
+		checks["cronjobs"] = func(opts metav1.ListOptions) error {
+			_, err := clientset.BatchV1().CronJobs("").List(ctx, opts)
+			return err
+		}
+	case "v1beta1":
+		checks["cronjobs"] = func(opts metav1.ListOptions) error {
+			_, err := clientset.BatchV1beta1().CronJobs("").List(ctx, opts)
+			return err
+		}
+	}
+
+	for resource, check := range checks {
+		err := check(metav1.ListOptions{FieldSelector: fieldSelector, Limit: 1})
+
+		if err != nil && errors.IsForbidden(err) {
+			return fmt.Errorf("informer cache cannot list %s in all namespaces: %w", resource, err)
+		} else if err != nil {
+			return fmt.Errorf("informer cache could not list %s: %w", resource, err)
+		}
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		clientset,
+		0,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fieldSelector
+		}),
+	)
+
+	ci.pods = factory.Core().V1().Pods().Lister()
+	ci.deployments = factory.Apps().V1().Deployments().Lister()
+	ci.statefulSets = factory.Apps().V1().StatefulSets().Lister()
+	ci.replicaSets = factory.Apps().V1().ReplicaSets().Lister()
+	ci.daemonSets = factory.Apps().V1().DaemonSets().Lister()
+	ci.jobs = factory.Batch().V1().Jobs().Lister()
+
+	switch cronJobVersion {
+	case "v1":
+		ci.cronJobs = factory.Batch().V1().CronJobs().Lister()
+	case "v1beta1":
+		ci.betaCronJobs = factory.Batch().V1beta1().CronJobs().Lister()
+	}
+
+	ci.factory = factory
+
+	return nil
+}
+
+// getCronJobVersion returns the version of the batch group that the cluster serves
+// CronJobs with, which is v1 from Kubernetes 1.21 and the only version from 1.25. An empty
+// version is returned if the cluster serves no CronJobs.
+func (a *Agent) getCronJobVersion() (string, error) {
+	for _, version := range []string{"v1", "v1beta1"} {
+		resources, err := a.Clientset.Discovery().ServerResourcesForGroupVersion("batch/" + version)
+
+		if err != nil && errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		for _, resource := range resources.APIResources {
+			if resource.Name == "cronjobs" {
+				return version, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func getInformerCacheFieldSelector() string {
+	selectors := make([]string, 0, len(informerCacheExcludedNamespaces))
+
+	for _, namespace := range informerCacheExcludedNamespaces {
+		selectors = append(selectors, fmt.Sprintf("metadata.namespace!=%s", namespace))
+	}
+
+	return strings.Join(selectors, ",")
+}
+
+// isCached returns true if the objects of the namespace are cached. Reads across all
+// namespaces are not cached, since the cache does not hold the system namespaces.
+func isCached(namespace string) bool {
+	if namespace == "" {
+		return false
+	}
+
+	for _, excluded := range informerCacheExcludedNamespaces {
+		if namespace == excluded {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetPodsByLabel lists the pods with matching labels from the cache
+func (ci *ClusterInformers) GetPodsByLabel(selector string, namespace string) (*v1.PodList, error) {
+	if !isCached(namespace) {
+		return ci.agent.GetPodsByLabel(selector, namespace)
+	}
+
+	parsedSelector, err := labels.Parse(selector)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := ci.pods.Pods(namespace).List(parsedSelector)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &v1.PodList{
+		Items: make([]v1.Pod, 0, len(pods)),
+	}
+
+	for _, pod := range pods {
+		res.Items = append(res.Items, *pod.DeepCopy())
+	}
+
+	return res, nil
+}
+
+// GetJobPods lists all pods belonging to a job in a namespace from the cache
+func (ci *ClusterInformers) GetJobPods(namespace, jobName string) ([]v1.Pod, error) {
+	res, err := ci.GetPodsByLabel(fmt.Sprintf("%s=%s", "job-name", jobName), namespace)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Items, nil
+}
+
+// ListJobsByLabel lists the jobs in a namespace with matching labels from the cache
+func (ci *ClusterInformers) ListJobsByLabel(namespace string, labelList ...Label) ([]batchv1.Job, error) {
+	if !isCached(namespace) {
+		return ci.agent.ListJobsByLabel(namespace, labelList...)
+	}
+
+	selectors := make([]string, 0)
+
+	for _, label := range labelList {
+		selectors = append(selectors, fmt.Sprintf("%s=%s", label.Key, label.Val))
+	}
+
+	parsedSelector, err := labels.Parse(strings.Join(selectors, ","))
+
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := ci.jobs.Jobs(namespace).List(parsedSelector)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]batchv1.Job, 0, len(jobs))
+
+	for _, job := range jobs {
+		res = append(res, *job.DeepCopy())
+	}
+
+	return res, nil
+}
+
+// GetDeployment gets the deployment given the name and namespace from the cache
+func (ci *ClusterInformers) GetDeployment(c grapher.Object) (*appsv1.Deployment, error) {
+	if !isCached(c.Namespace) {
+		return ci.agent.GetDeployment(c)
+	}
+
+	obj, err := ci.deployments.Deployments(c.Namespace).Get(c.Name)
+
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	res := obj.DeepCopy()
+	res.Kind = c.Kind
+
+	return res, nil
+}
+
+// GetStatefulSet gets the statefulset given the name and namespace from the cache
+func (ci *ClusterInformers) GetStatefulSet(c grapher.Object) (*appsv1.StatefulSet, error) {
+	if !isCached(c.Namespace) {
+		return ci.agent.GetStatefulSet(c)
+	}
+
+	obj, err := ci.statefulSets.StatefulSets(c.Namespace).Get(c.Name)
+
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	res := obj.DeepCopy()
+	res.Kind = c.Kind
+
+	return res, nil
+}
+
+// GetReplicaSet gets the replicaset given the name and namespace from the cache
+func (ci *ClusterInformers) GetReplicaSet(c grapher.Object) (*appsv1.ReplicaSet, error) {
+	if !isCached(c.Namespace) {
+		return ci.agent.GetReplicaSet(c)
+	}
+
+	obj, err := ci.replicaSets.ReplicaSets(c.Namespace).Get(c.Name)
+
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	res := obj.DeepCopy()
+	res.Kind = c.Kind
+
+	return res, nil
+}
+
+// GetDaemonSet gets the daemonset by name and namespace from the cache
+func (ci *ClusterInformers) GetDaemonSet(c grapher.Object) (*appsv1.DaemonSet, error) {
+	if !isCached(c.Namespace) {
+		return ci.agent.GetDaemonSet(c)
+	}
+
+	obj, err := ci.daemonSets.DaemonSets(c.Namespace).Get(c.Name)
+
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	res := obj.DeepCopy()
+	res.Kind = c.Kind
+
+	return res, nil
+}
+
+// GetJob gets the job by name and namespace from the cache
+func (ci *ClusterInformers) GetJob(c grapher.Object) (*batchv1.Job, error) {
+	if !isCached(c.Namespace) {
+		return ci.agent.GetJob(c)
+	}
+
+	obj, err := ci.jobs.Jobs(c.Namespace).Get(c.Name)
+
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	res := obj.DeepCopy()
+	res.Kind = c.Kind
+
+	return res, nil
+}
+
+// GetCronJob gets the CronJob by name and namespace from the cache. CronJobs of clusters
+// that serve batch/v1 CronJobs are returned as batch/v1beta1 CronJobs, which have the same
+// fields.
+func (ci *ClusterInformers) GetCronJob(c grapher.Object) (*batchv1beta1.CronJob, error) {
+	if !isCached(c.Namespace) {
+		return ci.agent.GetCronJob(c)
+	}
+
+	var res *batchv1beta1.CronJob
+
+	switch {
+	case ci.cronJobs != nil:
+		obj, err := ci.cronJobs.CronJobs(c.Namespace).Get(c.Name)
+
+		if err != nil {
+			return nil, wrapNotFound(err)
+		}
+
+		res = &batchv1beta1.CronJob{}
+
+		if err := convertCronJob(obj, res); err != nil {
+			return nil, err
+		}
+	case ci.betaCronJobs != nil:
+		obj, err := ci.betaCronJobs.CronJobs(c.Namespace).Get(c.Name)
+
+		if err != nil {
+			return nil, wrapNotFound(err)
+		}
+
+		res = obj.DeepCopy()
+	default:
+		return nil, IsNotFoundError
+	}
+
+	res.Kind = c.Kind
+
+	return res, nil
+}
+
+func convertCronJob(from *batchv1.CronJob, to *batchv1beta1.CronJob) error {
+	data, err := json.Marshal(from)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, to)
+}
@@ -0,0 +1,215 @@
+package kubernetes_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestInformerCache(t *testing.T) {
+	agent := newAgentFixture(
+		t,
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-1",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "web"},
+		}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "worker-1",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "worker"},
+		}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-1",
+			Namespace: "staging",
+			Labels:    map[string]string{"app": "web"},
+		}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+		}},
+	)
+
+	informerCache := kubernetes.NewInformerCache(time.Minute)
+	defer informerCache.Evict(1)
+
+	informers, err := informerCache.Get(1, agent)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pods, err := informers.GetPodsByLabel("app=web", "default")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(pods.Items) != 1 || pods.Items[0].Name != "web-1" || pods.Items[0].Namespace != "default" {
+		t.Errorf("expected pod default/web-1, got %v", pods.Items)
+	}
+
+	pods, err = informers.GetPodsByLabel("app=web", "")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(pods.Items) != 2 {
+		t.Errorf("expected 2 pods across namespaces, got %d", len(pods.Items))
+	}
+
+	depl, err := informers.GetDeployment(grapher.Object{Kind: "Deployment", Name: "web", Namespace: "default"})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if depl.Kind != "Deployment" {
+		t.Errorf("expected kind Deployment, got %s", depl.Kind)
+	}
+
+	_, err = informers.GetDeployment(grapher.Object{Kind: "Deployment", Name: "missing", Namespace: "default"})
+
+	if err != kubernetes.IsNotFoundError {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	// the informers of the cluster should be reused until they are evicted
+	cached, err := informerCache.Get(1, agent)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if cached != informers {
+		t.Errorf("expected the cached informers to be reused")
+	}
+
+	informerCache.Evict(1)
+
+	evicted, err := informerCache.Get(1, agent)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if evicted == informers {
+		t.Errorf("expected new informers after eviction")
+	}
+}
+
+func TestInformerCacheCronJobs(t *testing.T) {
+	agent := newAgentFixture(
+		t,
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{
+			Name:      "cleanup",
+			Namespace: "default",
+		}, Spec: batchv1.CronJobSpec{Schedule: "0 * * * *"}},
+	)
+
+	// clusters from Kubernetes 1.25 only serve batch/v1 CronJobs
+	agent.Clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+		GroupVersion: "batch/v1",
+		APIResources: []metav1.APIResource{{Name: "jobs"}, {Name: "cronjobs"}},
+	}}
+
+	informerCache := kubernetes.NewInformerCache(time.Minute)
+	defer informerCache.Evict(1)
+
+	informers, err := informerCache.Get(1, agent)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	cronJob, err := informers.GetCronJob(grapher.Object{Kind: "CronJob", Name: "cleanup", Namespace: "default"})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if cronJob.Spec.Schedule != "0 * * * *" {
+		t.Errorf("expected schedule of the batch/v1 CronJob, got %q", cronJob.Spec.Schedule)
+	}
+}
+
+func TestInformerCacheForbidden(t *testing.T) {
+	agent := newAgentFixture(t)
+
+	// credentials scoped to a namespace cannot list pods in all namespaces
+	agent.Clientset.(*fake.Clientset).PrependReactor(
+		"list",
+		"pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetNamespace() != "" {
+				return false, nil, nil
+			}
+
+			return true, nil, errors.NewForbidden(v1.Resource("pods"), "", fmt.Errorf("namespaced role"))
+		},
+	)
+
+	informerCache := kubernetes.NewInformerCache(time.Minute)
+
+	start := time.Now()
+
+	if _, err := informerCache.Get(1, agent); err == nil {
+		t.Fatalf("expected error for forbidden informers")
+	}
+
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("expected forbidden informers to fail without waiting for the sync timeout")
+	}
+}
+
+func TestInformerCacheSystemNamespaces(t *testing.T) {
+	agent := newAgentFixture(t)
+
+	informerCache := kubernetes.NewInformerCache(time.Minute)
+	defer informerCache.Evict(1)
+
+	informers, err := informerCache.Get(1, agent)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// objects of system namespaces are not cached, and are read from the api server
+	_, err = agent.Clientset.CoreV1().Pods("kube-system").Create(
+		context.Background(),
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "coredns-1",
+			Namespace: "kube-system",
+			Labels:    map[string]string{"k8s-app": "kube-dns"},
+		}},
+		metav1.CreateOptions{},
+	)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pods, err := informers.GetPodsByLabel("k8s-app=kube-dns", "kube-system")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(pods.Items) != 1 {
+		t.Errorf("expected pod of kube-system, got %d pods", len(pods.Items))
+	}
+}
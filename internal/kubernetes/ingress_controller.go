@@ -0,0 +1,137 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IngressControllerType is the implementation of the ingress controller of a cluster
+type IngressControllerType string
+
+const (
+	IngressControllerNGINX   IngressControllerType = "nginx"
+	IngressControllerTraefik IngressControllerType = "traefik"
+	IngressControllerALB     IngressControllerType = "alb"
+	IngressControllerGCE     IngressControllerType = "gce"
+	IngressControllerUnknown IngressControllerType = "unknown"
+)
+
+// ingressClassControllers maps the controller names of ingress classes to the ingress
+// controller types
+var ingressClassControllers = map[string]IngressControllerType{
+	"k8s.io/ingress-nginx":          IngressControllerNGINX,
+	"traefik.io/ingress-controller": IngressControllerTraefik,
+	"ingress.k8s.aws/alb":           IngressControllerALB,
+	"networking.gke.io/ingress-gce": IngressControllerGCE,
+}
+
+// LegacyIngressClassAnnotation sets the ingress class of ingresses that do not set
+// spec.ingressClassName, which may name a class that is not an IngressClass resource
+const LegacyIngressClassAnnotation = "kubernetes.io/ingress.class"
+
+// legacyIngressClasses maps the ingress classes of the legacy annotation that are not
+// IngressClass resources to the ingress controller types that serve them by default
+var legacyIngressClasses = map[string]IngressControllerType{
+	"nginx":   IngressControllerNGINX,
+	"traefik": IngressControllerTraefik,
+	"alb":     IngressControllerALB,
+	"gce":     IngressControllerGCE,
+}
+
+// GetIngressClassControllerType returns the ingress controller that serves an ingress
+// class. Ingresses without a class are served by the ingress controller of the cluster.
+func (a *Agent) GetIngressClassControllerType(className string) (IngressControllerType, error) {
+	if className == "" {
+		return a.GetIngressControllerType()
+	}
+
+	class, err := a.Clientset.NetworkingV1().IngressClasses().Get(
+		context.TODO(),
+		className,
+		metav1.GetOptions{},
+	)
+
+	if err == nil {
+		if controllerType, ok := ingressClassControllers[class.Spec.Controller]; ok {
+			return controllerType, nil
+		}
+
+		return IngressControllerUnknown, nil
+	} else if !errors.IsNotFound(err) {
+		return IngressControllerUnknown, err
+	}
+
+	if controllerType, ok := legacyIngressClasses[className]; ok {
+		return controllerType, nil
+	}
+
+	return IngressControllerUnknown, nil
+}
+
+// GetIngressControllerType detects the ingress controller of the cluster from its ingress
+// classes, preferring the default ingress class. Clusters without ingress classes are
+// detected from the namespace of the ingress-nginx controller, which is installed by
+// Porter.
+func (a *Agent) GetIngressControllerType() (IngressControllerType, error) {
+	classes, err := a.Clientset.NetworkingV1().IngressClasses().List(
+		context.TODO(),
+		metav1.ListOptions{},
+	)
+
+	// clusters that do not serve the networking.k8s.io/v1 api have no ingress classes
+	if err != nil && !errors.IsNotFound(err) {
+		return IngressControllerUnknown, err
+	}
+
+	res := IngressControllerUnknown
+
+	if classes != nil {
+		for _, class := range classes.Items {
+			controllerType, ok := ingressClassControllers[class.Spec.Controller]
+
+			if !ok {
+				continue
+			}
+
+			if strings.EqualFold(class.Annotations["ingressclass.kubernetes.io/is-default-class"], "true") {
+				return controllerType, nil
+			}
+
+			if res == IngressControllerUnknown {
+				res = controllerType
+			}
+		}
+	}
+
+	if res != IngressControllerUnknown {
+		return res, nil
+	}
+
+	_, err = a.Clientset.CoreV1().Namespaces().Get(context.TODO(), IngressNginxNamespace, metav1.GetOptions{})
+
+	if err == nil {
+		return IngressControllerNGINX, nil
+	} else if !errors.IsNotFound(err) {
+		return IngressControllerUnknown, err
+	}
+
+	return IngressControllerUnknown, nil
+}
+
+// GetIngressNginxService returns the service of the load balancer of the ingress-nginx
+// controller, or nil if the controller is not installed
+func (a *Agent) GetIngressNginxService() (*v1.Service, error) {
+	controller, err := a.getIngressNginxController()
+
+	if err != nil && errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return controller.service, nil
+}
@@ -0,0 +1,77 @@
+package kubernetes_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func getIngressClassTestAgent() *kubernetes.Agent {
+	return kubernetes.GetAgentTesting(
+		&networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "nginx",
+				Annotations: map[string]string{
+					"ingressclass.kubernetes.io/is-default-class": "true",
+				},
+			},
+			Spec: networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+		},
+		&networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "internal-alb"},
+			Spec:       networkingv1.IngressClassSpec{Controller: "ingress.k8s.aws/alb"},
+		},
+	)
+}
+
+func TestGetIngressClassControllerType(t *testing.T) {
+	agent := getIngressClassTestAgent()
+
+	tests := map[string]kubernetes.IngressControllerType{
+		// ingresses without a class are served by the default controller of the cluster
+		"":             kubernetes.IngressControllerNGINX,
+		"internal-alb": kubernetes.IngressControllerALB,
+		// classes of the legacy annotation that are not ingress classes
+		"traefik": kubernetes.IngressControllerTraefik,
+		"unknown": kubernetes.IngressControllerUnknown,
+	}
+
+	for className, expected := range tests {
+		controllerType, err := agent.GetIngressClassControllerType(className)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if controllerType != expected {
+			t.Errorf("expected class %q to be served by %s, got %s", className, expected, controllerType)
+		}
+	}
+}
+
+func TestGetIngressClassControllerTypeForbidden(t *testing.T) {
+	agent := getIngressClassTestAgent()
+
+	agent.Clientset.(*fake.Clientset).PrependReactor(
+		"*",
+		"ingressclasses",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.NewForbidden(networkingv1.Resource("ingressclasses"), "", fmt.Errorf("namespaced role"))
+		},
+	)
+
+	if _, err := agent.GetIngressClassControllerType("internal-alb"); err == nil {
+		t.Errorf("expected an error if ingress classes cannot be read")
+	}
+
+	if _, err := agent.GetIngressClassControllerType(""); err == nil {
+		t.Errorf("expected an error if ingress classes cannot be listed")
+	}
+}
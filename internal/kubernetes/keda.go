@@ -0,0 +1,24 @@
+package kubernetes
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// KEDAGroupVersion is the API group version of the KEDA ScaledObject resources
+const KEDAGroupVersion = "keda.sh/v1alpha1"
+
+// IsKEDAInstalled returns true if the KEDA resources are served by the cluster, which
+// is the case whether or not KEDA was installed through Porter
+func (a *Agent) IsKEDAInstalled() (bool, error) {
+	_, err := a.Clientset.Discovery().ServerResourcesForGroupVersion(KEDAGroupVersion)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,458 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LabeledResource is a resource that matched a label selector
+type LabeledResource struct {
+	Kind        string
+	Namespace   string
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+
+	// Owned is true if the resource is owned by another resource, such as the jobs of a
+	// cronjob
+	Owned bool
+
+	// OwnerKind and OwnerName identify the controller of an owned resource, or its first
+	// owner if it has no controller
+	OwnerKind string
+	OwnerName string
+}
+
+type resourceClient struct {
+	list   func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error)
+	delete func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error
+}
+
+// LabeledResourceKinds are the kinds of resources that are searched by
+// ListLabeledResources, and that can be deleted with DeleteLabeledResource
+var LabeledResourceKinds = []string{
+	"Deployment",
+	"StatefulSet",
+	"DaemonSet",
+	"CronJob",
+	"Job",
+	"Service",
+	"Ingress",
+	"ConfigMap",
+	"Secret",
+	"PersistentVolumeClaim",
+}
+
+func (a *Agent) getResourceClient(kind string) (*resourceClient, error) {
+	cs := a.Clientset
+
+	switch kind {
+	case "Deployment":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.AppsV1().Deployments("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.AppsV1().Deployments(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	case "StatefulSet":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.AppsV1().StatefulSets("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.AppsV1().StatefulSets(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	case "DaemonSet":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.AppsV1().DaemonSets("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.AppsV1().DaemonSets(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	case "CronJob":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.BatchV1beta1().CronJobs("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.BatchV1beta1().CronJobs(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	case "Job":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.BatchV1().Jobs("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.BatchV1().Jobs(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	case "Service":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.CoreV1().Services("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.CoreV1().Services(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	case "Ingress":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.NetworkingV1().Ingresses("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.NetworkingV1().Ingresses(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	case "ConfigMap":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.CoreV1().ConfigMaps("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.CoreV1().ConfigMaps(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	case "Secret":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.CoreV1().Secrets("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.CoreV1().Secrets(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	case "PersistentVolumeClaim":
+		return &resourceClient{
+			list: func(ctx context.Context, opts metav1.ListOptions) ([]metav1.Object, error) {
+				list, err := cs.CoreV1().PersistentVolumeClaims("").List(ctx, opts)
+
+				if err != nil {
+					return nil, err
+				}
+
+				res := make([]metav1.Object, 0, len(list.Items))
+
+				for i := range list.Items {
+					res = append(res, &list.Items[i])
+				}
+
+				return res, nil
+			},
+			delete: func(ctx context.Context, namespace, name string, opts metav1.DeleteOptions) error {
+				return cs.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, opts)
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported resource kind %s", kind)
+}
+
+// ListLabeledResources lists the resources of the kinds in LabeledResourceKinds that
+// match a label selector, across all namespaces. Kinds that are not served by the
+// cluster are skipped.
+func (a *Agent) ListLabeledResources(selector string) ([]*LabeledResource, error) {
+	return a.listResources(metav1.ListOptions{
+		LabelSelector: selector,
+	})
+}
+
+// ListNamespaceResources returns the resources that are in a namespace. Besides the
+// kinds in LabeledResourceKinds, every other namespaced kind that the cluster serves is
+// listed, including the kinds of CRDs, since deleting the namespace deletes them as well.
+// Events and metrics are not resources of the namespace, and are skipped.
+func (a *Agent) ListNamespaceResources(namespace string) ([]*LabeledResource, error) {
+	res, err := a.listResources(metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("metadata.namespace=%s", namespace),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	other, err := a.listOtherNamespaceResources(namespace)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(res, other...), nil
+}
+
+// listOtherNamespaceResources lists the resources of a namespace whose kinds are not in
+// LabeledResourceKinds with the dynamic client
+func (a *Agent) listOtherNamespaceResources(namespace string) ([]*LabeledResource, error) {
+	resourceLists, err := a.Clientset.Discovery().ServerPreferredNamespacedResources()
+
+	// the resources of groups that could not be discovered, such as the groups of
+	// unavailable API services, are skipped
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, err
+	}
+
+	listed := make(map[string]bool)
+
+	for _, kind := range LabeledResourceKinds {
+		listed[kind] = true
+	}
+
+	kinds := make(map[schema.GroupVersionResource]string)
+
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+
+		if err != nil || gv.Group == "metrics.k8s.io" {
+			continue
+		}
+
+		for _, resource := range resourceList.APIResources {
+			if listed[resource.Kind] || resource.Kind == "Event" || !hasVerb(resource.Verbs, "list") {
+				continue
+			}
+
+			// kinds that are served by several groups are only listed once
+			listed[resource.Kind] = true
+			kinds[gv.WithResource(resource.Name)] = resource.Kind
+		}
+	}
+
+	res := make([]*LabeledResource, 0)
+
+	if len(kinds) == 0 {
+		return res, nil
+	}
+
+	restConf, err := a.RESTClientGetter.ToRESTConfig()
+
+	if err != nil {
+		return nil, err
+	}
+
+	dynClient, err := dynamic.NewForConfig(restConf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for gvr, kind := range kinds {
+		list, err := dynClient.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+
+		if err != nil && (errors.IsNotFound(err) || errors.IsMethodNotSupported(err)) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			res = append(res, toLabeledResource(kind, &list.Items[i]))
+		}
+	}
+
+	return res, nil
+}
+
+func hasVerb(verbs metav1.Verbs, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toLabeledResource(kind string, obj metav1.Object) *LabeledResource {
+	res := &LabeledResource{
+		Kind:        kind,
+		Namespace:   obj.GetNamespace(),
+		Name:        obj.GetName(),
+		Labels:      obj.GetLabels(),
+		Annotations: obj.GetAnnotations(),
+	}
+
+	refs := obj.GetOwnerReferences()
+
+	if len(refs) == 0 {
+		return res
+	}
+
+	owner := refs[0]
+
+	for _, ref := range refs {
+		if ref.Controller != nil && *ref.Controller {
+			owner = ref
+			break
+		}
+	}
+
+	res.Owned = true
+	res.OwnerKind = owner.Kind
+	res.OwnerName = owner.Name
+
+	return res
+}
+
+func (a *Agent) listResources(opts metav1.ListOptions) ([]*LabeledResource, error) {
+	res := make([]*LabeledResource, 0)
+
+	for _, kind := range LabeledResourceKinds {
+		client, err := a.getResourceClient(kind)
+
+		if err != nil {
+			return nil, err
+		}
+
+		objs, err := client.list(context.TODO(), opts)
+
+		if err != nil && errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range objs {
+			res = append(res, toLabeledResource(kind, obj))
+		}
+	}
+
+	return res, nil
+}
+
+// DeleteLabeledResource deletes a resource of one of the kinds in LabeledResourceKinds,
+// along with the resources that it owns
+func (a *Agent) DeleteLabeledResource(kind, namespace, name string) error {
+	client, err := a.getResourceClient(kind)
+
+	if err != nil {
+		return err
+	}
+
+	propagation := metav1.DeletePropagationBackground
+
+	err = client.delete(context.TODO(), namespace, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+
+	return wrapNotFound(err)
+}
@@ -0,0 +1,59 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestListNamespaceResourcesOwners(t *testing.T) {
+	isController := true
+
+	agent := kubernetes.GetAgentTesting(
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cron-123",
+				Namespace: "pr-1",
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "Workflow", Name: "other"},
+					{Kind: "CronJob", Name: "cron", Controller: &isController},
+				},
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "default-token-abc",
+				Namespace:   "pr-1",
+				Annotations: map[string]string{"kubernetes.io/service-account.name": "default"},
+			},
+		},
+	)
+
+	resources, err := agent.ListNamespaceResources("pr-1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 resources, got %d", len(resources))
+	}
+
+	for _, resource := range resources {
+		switch resource.Kind {
+		case "Job":
+			if !resource.Owned || resource.OwnerKind != "CronJob" || resource.OwnerName != "cron" {
+				t.Errorf("expected job to be owned by its controller, got %s/%s", resource.OwnerKind, resource.OwnerName)
+			}
+		case "Secret":
+			if resource.Owned || resource.Annotations["kubernetes.io/service-account.name"] != "default" {
+				t.Errorf("expected secret to keep its annotations and have no owner, got %v", resource)
+			}
+		default:
+			t.Errorf("unexpected resource %s/%s", resource.Kind, resource.Name)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReleaseLabel is the Loki stream label that holds the name of the Helm release, as set
+// by the default Promtail relabeling of the app.kubernetes.io/instance pod label
+const ReleaseLabel = "instance"
+
+// DefaultLimit is the maximum number of log lines returned if no limit is specified
+const DefaultLimit = 1000
+
+// DefaultLookback is the time range that is searched if no start time is specified
+const DefaultLookback = time.Hour
+
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// GetLokiService returns the Loki service in the cluster, if Loki is installed
+func GetLokiService(clientset kubernetes.Interface) (*v1.Service, bool, error) {
+	services, err := clientset.CoreV1().Services("").List(context.TODO(), metav1.ListOptions{
+		LabelSelector: "app=loki",
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(services.Items) == 0 {
+		return nil, false, nil
+	}
+
+	// the loki chart also creates a headless service, so prefer the service with a
+	// cluster IP
+	for _, svc := range services.Items {
+		if svc.Spec.ClusterIP != "None" {
+			return &svc, true, nil
+		}
+	}
+
+	return &services.Items[0], true, nil
+}
+
+// SearchOpts are the options for a log search of a release
+type SearchOpts struct {
+	Namespace string
+	Release   string
+
+	// Query is a free-text string that every returned log line contains
+	Query string
+
+	// Labels are additional stream label filters of the form key=value
+	Labels []string
+
+	// Start and End are unix timestamps in seconds
+	Start uint
+	End   uint
+
+	Limit     uint
+	Direction string
+}
+
+// LogLine is a single log line returned by Loki
+type LogLine struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Line      string            `json:"line"`
+	Labels    map[string]string `json:"labels"`
+}
+
+type lokiRawQuery struct {
+	Data struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][]string        `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// BuildLogQLQuery builds the LogQL query for the search options, which selects the
+// streams of the release and filters the lines by the free-text query
+func BuildLogQLQuery(opts *SearchOpts) (string, error) {
+	selectors := []string{
+		fmt.Sprintf(`namespace=%s`, strconv.Quote(opts.Namespace)),
+		fmt.Sprintf(`%s=%s`, ReleaseLabel, strconv.Quote(opts.Release)),
+	}
+
+	for _, label := range opts.Labels {
+		key, val, err := parseLabel(label)
+
+		if err != nil {
+			return "", err
+		}
+
+		// the release and namespace selectors cannot be overwritten, so that the search
+		// stays scoped to the release
+		if key == "namespace" || key == ReleaseLabel {
+			continue
+		}
+
+		selectors = append(selectors, fmt.Sprintf(`%s=%s`, key, strconv.Quote(val)))
+	}
+
+	query := fmt.Sprintf("{%s}", strings.Join(selectors, ","))
+
+	if opts.Query != "" {
+		query = fmt.Sprintf("%s |= %s", query, strconv.Quote(opts.Query))
+	}
+
+	return query, nil
+}
+
+// SearchLogs queries Loki for the log lines of a release, sorted by timestamp in the
+// requested direction
+func SearchLogs(
+	clientset kubernetes.Interface,
+	service *v1.Service,
+	opts *SearchOpts,
+) ([]*LogLine, error) {
+	if len(service.Spec.Ports) == 0 {
+		return nil, fmt.Errorf("loki service has no exposed ports to query")
+	}
+
+	query, err := BuildLogQLQuery(opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	limit := opts.Limit
+
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	direction := opts.Direction
+
+	if direction == "" {
+		direction = "backward"
+	}
+
+	queryParams := map[string]string{
+		"query":     query,
+		"limit":     fmt.Sprintf("%d", limit),
+		"direction": direction,
+		// loki expects nanosecond timestamps
+		"start": fmt.Sprintf("%d", uint64(opts.Start)*uint64(time.Second)),
+		"end":   fmt.Sprintf("%d", uint64(opts.End)*uint64(time.Second)),
+	}
+
+	resp := clientset.CoreV1().Services(service.Namespace).ProxyGet(
+		"http",
+		service.Name,
+		fmt.Sprintf("%d", service.Spec.Ports[0].Port),
+		"/loki/api/v1/query_range",
+		queryParams,
+	)
+
+	rawQuery, err := resp.DoRaw(context.TODO())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseQuery(rawQuery, direction)
+}
+
+func parseQuery(rawQuery []byte, direction string) ([]*LogLine, error) {
+	rawQueryObj := &lokiRawQuery{}
+
+	if err := json.Unmarshal(rawQuery, rawQueryObj); err != nil {
+		return nil, err
+	}
+
+	res := make([]*LogLine, 0)
+
+	for _, result := range rawQueryObj.Data.Result {
+		for _, value := range result.Values {
+			if len(value) != 2 {
+				continue
+			}
+
+			nsec, err := strconv.ParseInt(value[0], 10, 64)
+
+			if err != nil {
+				continue
+			}
+
+			res = append(res, &LogLine{
+				Timestamp: time.Unix(0, nsec).UTC(),
+				Line:      value[1],
+				Labels:    result.Stream,
+			})
+		}
+	}
+
+	// loki returns lines grouped by stream, so merge the streams
+	sort.SliceStable(res, func(i, j int) bool {
+		if direction == "forward" {
+			return res[i].Timestamp.Before(res[j].Timestamp)
+		}
+
+		return res[i].Timestamp.After(res[j].Timestamp)
+	})
+
+	return res, nil
+}
+
+// ValidateLabels returns an error if any of the label filters of a search is not of the
+// form key=value
+func ValidateLabels(labels []string) error {
+	for _, label := range labels {
+		if _, _, err := parseLabel(label); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseLabel(label string) (string, string, error) {
+	spl := strings.SplitN(label, "=", 2)
+
+	if len(spl) != 2 || !labelNameRegex.MatchString(spl[0]) {
+		return "", "", fmt.Errorf("invalid label filter %q: must be of the form key=value", label)
+	}
+
+	return spl[0], spl[1], nil
+}
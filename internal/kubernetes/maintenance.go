@@ -0,0 +1,421 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// MaintenanceModeAnnotation is set on the ingresses of a release that is in
+	// maintenance mode
+	MaintenanceModeAnnotation = "porter.run/maintenance-mode"
+
+	// previousSpecAnnotation stores the spec of the ingress before maintenance mode was
+	// enabled, so that its backends can be restored
+	previousSpecAnnotation = "porter.run/maintenance-previous-spec"
+
+	// maintenanceBackendLabel is set on the pods of the maintenance backend of an ingress,
+	// and stores the name of the ingress
+	maintenanceBackendLabel = "porter.run/maintenance-backend"
+
+	// maintenancePageChecksumAnnotation is set on the pod template of the maintenance
+	// backend, so that the pods are restarted when the page changes
+	maintenancePageChecksumAnnotation = "porter.run/maintenance-page-checksum"
+
+	maintenanceImage = "nginx:1.21-alpine"
+	maintenancePort  = 8080
+)
+
+// maintenanceNginxConf answers every request with a 503 and the maintenance page
+const maintenanceNginxConf = `server {
+    listen 8080;
+    root /usr/share/nginx/html;
+    error_page 503 /index.html;
+
+    location = /index.html {
+        internal;
+    }
+
+    location / {
+        return 503;
+    }
+}
+`
+
+var defaultMaintenancePage = template.Must(template.New("maintenance").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Down for maintenance</title></head>
+<body style="font-family: sans-serif; text-align: center; padding-top: 100px;">
+<h1>Down for maintenance</h1>
+<p>{{ . }}</p>
+</body>
+</html>`))
+
+// DefaultMaintenanceMessage is shown on the default maintenance page if no message is set
+const DefaultMaintenanceMessage = "We'll be back shortly."
+
+// GetMaintenancePage returns the maintenance page to serve. If page is empty, the default
+// page is rendered with the (plain text) message.
+func GetMaintenancePage(page, message string) (string, error) {
+	if page != "" {
+		return page, nil
+	}
+
+	if message == "" {
+		message = DefaultMaintenanceMessage
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := defaultMaintenancePage.Execute(buf, message); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// GetMaintenanceBackendName returns the name of the deployment, service and configmap that
+// serve the maintenance page of an ingress
+func GetMaintenanceBackendName(ingressName string) string {
+	const suffix = "-maintenance"
+
+	// service names are limited to 63 characters
+	if len(ingressName) > 63-len(suffix) {
+		ingressName = strings.TrimRight(ingressName[:63-len(suffix)], "-")
+	}
+
+	return ingressName + suffix
+}
+
+// SetIngressMaintenanceMode puts an ingress into maintenance mode, in which every request
+// is answered with a 503 and the given HTML page, or takes it out of maintenance mode. The
+// page is served by a standby deployment in the namespace of the ingress, and the backends
+// of the ingress are pointed to its service, so that maintenance mode works with any
+// ingress controller and the release does not need to be redeployed.
+func (a *Agent) SetIngressMaintenanceMode(namespace, name string, enabled bool, page string) error {
+	ingress, err := a.GetIngress(namespace, name)
+
+	if err != nil {
+		return err
+	}
+
+	prevSpec, inMaintenance := ingress.Annotations[previousSpecAnnotation]
+
+	if !enabled {
+		if inMaintenance {
+			spec := networkingv1.IngressSpec{}
+
+			if err := json.Unmarshal([]byte(prevSpec), &spec); err != nil {
+				return fmt.Errorf("could not read the spec of ingress %s before maintenance mode: %w", name, err)
+			}
+
+			ingress.Spec = spec
+			delete(ingress.Annotations, previousSpecAnnotation)
+			delete(ingress.Annotations, MaintenanceModeAnnotation)
+
+			if _, err := a.Clientset.NetworkingV1().Ingresses(namespace).Update(context.TODO(), ingress, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+
+		return a.deleteMaintenanceBackend(namespace, name)
+	}
+
+	if err := a.applyMaintenanceBackend(ingress, page); err != nil {
+		return err
+	}
+
+	// the spec is only stored the first time, so that updating the page of an ingress that
+	// is already in maintenance mode does not overwrite it
+	if inMaintenance {
+		return nil
+	}
+
+	specBytes, err := json.Marshal(ingress.Spec)
+
+	if err != nil {
+		return err
+	}
+
+	if ingress.Annotations == nil {
+		ingress.Annotations = make(map[string]string)
+	}
+
+	ingress.Annotations[previousSpecAnnotation] = string(specBytes)
+	ingress.Annotations[MaintenanceModeAnnotation] = "true"
+
+	backend := networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: GetMaintenanceBackendName(name),
+			Port: networkingv1.ServiceBackendPort{
+				Number: 80,
+			},
+		},
+	}
+
+	if ingress.Spec.DefaultBackend != nil {
+		ingress.Spec.DefaultBackend = backend.DeepCopy()
+	}
+
+	for i := range ingress.Spec.Rules {
+		if ingress.Spec.Rules[i].HTTP == nil {
+			continue
+		}
+
+		for j := range ingress.Spec.Rules[i].HTTP.Paths {
+			ingress.Spec.Rules[i].HTTP.Paths[j].Backend = *backend.DeepCopy()
+		}
+	}
+
+	_, err = a.Clientset.NetworkingV1().Ingresses(namespace).Update(context.TODO(), ingress, metav1.UpdateOptions{})
+
+	return err
+}
+
+// IsIngressInMaintenanceMode returns true if the ingress is in maintenance mode
+func (a *Agent) IsIngressInMaintenanceMode(namespace, name string) (bool, error) {
+	ingress, err := a.GetIngress(namespace, name)
+
+	if err != nil {
+		return false, err
+	}
+
+	_, inMaintenance := ingress.ObjectMeta.Annotations[MaintenanceModeAnnotation]
+
+	return inMaintenance, nil
+}
+
+// applyMaintenanceBackend creates or updates the configmap, deployment and service that
+// serve the maintenance page of an ingress
+func (a *Agent) applyMaintenanceBackend(ingress *networkingv1.Ingress, page string) error {
+	namespace := ingress.Namespace
+	name := GetMaintenanceBackendName(ingress.Name)
+	checksum := sha256.Sum256([]byte(page))
+
+	labels := map[string]string{
+		maintenanceBackendLabel: ingress.Name,
+	}
+
+	// the pods are also labeled as pods of the release, so that the network policies of
+	// the release admit traffic from the ingress controller
+	podLabels := map[string]string{
+		maintenanceBackendLabel: ingress.Name,
+	}
+
+	if releaseName, ok := ingress.Labels[ReleaseInstanceLabel]; ok {
+		podLabels[ReleaseInstanceLabel] = releaseName
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: map[string]string{
+			"default.conf": maintenanceNginxConf,
+			"index.html":   page,
+		},
+	}
+
+	cmClient := a.Clientset.CoreV1().ConfigMaps(namespace)
+
+	if prev, err := cmClient.Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+		cm.ResourceVersion = prev.ResourceVersion
+		_, err = cmClient.Update(context.TODO(), cm, metav1.UpdateOptions{})
+
+		if err != nil {
+			return err
+		}
+	} else if errors.IsNotFound(err) {
+		if _, err := cmClient.Create(context.TODO(), cm, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+
+	replicas := int32(1)
+
+	depl := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+					Annotations: map[string]string{
+						maintenancePageChecksumAnnotation: hex.EncodeToString(checksum[:]),
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:  "maintenance",
+							Image: maintenanceImage,
+							Ports: []v1.ContainerPort{
+								{
+									ContainerPort: maintenancePort,
+								},
+							},
+							VolumeMounts: []v1.VolumeMount{
+								{
+									Name:      "maintenance",
+									MountPath: "/etc/nginx/conf.d/default.conf",
+									SubPath:   "default.conf",
+								},
+								{
+									Name:      "maintenance",
+									MountPath: "/usr/share/nginx/html/index.html",
+									SubPath:   "index.html",
+								},
+							},
+						},
+					},
+					Volumes: []v1.Volume{
+						{
+							Name: "maintenance",
+							VolumeSource: v1.VolumeSource{
+								ConfigMap: &v1.ConfigMapVolumeSource{
+									LocalObjectReference: v1.LocalObjectReference{
+										Name: name,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	deplClient := a.Clientset.AppsV1().Deployments(namespace)
+
+	if prev, err := deplClient.Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+		depl.ResourceVersion = prev.ResourceVersion
+		_, err = deplClient.Update(context.TODO(), depl, metav1.UpdateOptions{})
+
+		if err != nil {
+			return err
+		}
+	} else if errors.IsNotFound(err) {
+		if _, err := deplClient.Create(context.TODO(), depl, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+
+	svcType, err := a.getMaintenanceServiceType(ingress)
+
+	if err != nil {
+		return err
+	}
+
+	svcClient := a.Clientset.CoreV1().Services(namespace)
+
+	if _, err := svcClient.Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = svcClient.Create(context.TODO(), &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: v1.ServiceSpec{
+			Type:     svcType,
+			Selector: labels,
+			Ports: []v1.ServicePort{
+				{
+					Port:       80,
+					TargetPort: intstr.FromInt(maintenancePort),
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+
+	return err
+}
+
+// getMaintenanceServiceType returns the type of the service of the first backend of an
+// ingress, so that the maintenance backend can be reached by ingress controllers that
+// route to node ports
+func (a *Agent) getMaintenanceServiceType(ingress *networkingv1.Ingress) (v1.ServiceType, error) {
+	var backend *networkingv1.IngressServiceBackend
+
+	if ingress.Spec.DefaultBackend != nil {
+		backend = ingress.Spec.DefaultBackend.Service
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		if backend == nil && rule.HTTP != nil && len(rule.HTTP.Paths) > 0 {
+			backend = rule.HTTP.Paths[0].Backend.Service
+		}
+	}
+
+	if backend == nil {
+		return v1.ServiceTypeClusterIP, nil
+	}
+
+	svc, err := a.Clientset.CoreV1().Services(ingress.Namespace).Get(context.TODO(), backend.Name, metav1.GetOptions{})
+
+	if err != nil && errors.IsNotFound(err) {
+		return v1.ServiceTypeClusterIP, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if svc.Spec.Type == v1.ServiceTypeNodePort || svc.Spec.Type == v1.ServiceTypeLoadBalancer {
+		return v1.ServiceTypeNodePort, nil
+	}
+
+	return v1.ServiceTypeClusterIP, nil
+}
+
+// deleteMaintenanceBackend deletes the maintenance backend of an ingress. Resources that
+// do not exist are ignored.
+func (a *Agent) deleteMaintenanceBackend(namespace, ingressName string) error {
+	name := GetMaintenanceBackendName(ingressName)
+
+	for _, deleteFunc := range []func() error{
+		func() error {
+			return a.Clientset.AppsV1().Deployments(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		func() error {
+			return a.Clientset.CoreV1().Services(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		func() error {
+			return a.Clientset.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+	} {
+		if err := deleteFunc(); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
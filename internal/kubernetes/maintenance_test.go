@@ -0,0 +1,161 @@
+package kubernetes_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getMaintenanceTestIngress() *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-ingress",
+			Namespace: "default",
+			Labels: map[string]string{
+				kubernetes.ReleaseInstanceLabel: "web",
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "web.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "web-web",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getMaintenanceTestBackend(t *testing.T, agent *kubernetes.Agent) string {
+	t.Helper()
+
+	ingress, err := agent.Clientset.NetworkingV1().Ingresses("default").Get(context.TODO(), "web-ingress", metav1.GetOptions{})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name
+}
+
+func TestSetIngressMaintenanceMode(t *testing.T) {
+	agent := newAgentFixture(t, getMaintenanceTestIngress(), &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-web", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeNodePort},
+	})
+
+	backendName := kubernetes.GetMaintenanceBackendName("web-ingress")
+
+	// updating the page of an ingress in maintenance mode keeps its original backends
+	for _, message := range []string{"Back at 5pm", "Back at 6pm"} {
+		page, err := kubernetes.GetMaintenancePage("", message)
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		if err := agent.SetIngressMaintenanceMode("default", "web-ingress", true, page); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	if backend := getMaintenanceTestBackend(t, agent); backend != backendName {
+		t.Errorf("expected the ingress to route to %s, got %s", backendName, backend)
+	}
+
+	if inMaintenance, err := agent.IsIngressInMaintenanceMode("default", "web-ingress"); err != nil || !inMaintenance {
+		t.Errorf("expected the ingress to be in maintenance mode, got %v", err)
+	}
+
+	cm, err := agent.Clientset.CoreV1().ConfigMaps("default").Get(context.TODO(), backendName, metav1.GetOptions{})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if !strings.Contains(cm.Data["index.html"], "Back at 6pm") {
+		t.Errorf("expected the latest page to be served, got %s", cm.Data["index.html"])
+	}
+
+	depl, err := agent.Clientset.AppsV1().Deployments("default").Get(context.TODO(), backendName, metav1.GetOptions{})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if release := depl.Spec.Template.Labels[kubernetes.ReleaseInstanceLabel]; release != "web" {
+		t.Errorf("expected the pods to be labeled with the release, got %s", release)
+	}
+
+	svc, err := agent.Clientset.CoreV1().Services("default").Get(context.TODO(), backendName, metav1.GetOptions{})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if svc.Spec.Type != v1.ServiceTypeNodePort {
+		t.Errorf("expected the service type of the release, got %s", svc.Spec.Type)
+	}
+
+	if err := agent.SetIngressMaintenanceMode("default", "web-ingress", false, ""); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if backend := getMaintenanceTestBackend(t, agent); backend != "web-web" {
+		t.Errorf("expected the backend of the ingress to be restored, got %s", backend)
+	}
+
+	if inMaintenance, err := agent.IsIngressInMaintenanceMode("default", "web-ingress"); err != nil || inMaintenance {
+		t.Errorf("expected the ingress not to be in maintenance mode, got %v", err)
+	}
+
+	if _, err := agent.Clientset.AppsV1().Deployments("default").Get(context.TODO(), backendName, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the maintenance backend to be deleted, got %v", err)
+	}
+}
+
+func TestGetMaintenancePage(t *testing.T) {
+	page, err := kubernetes.GetMaintenancePage("", `<script>alert("down")</script>`)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if strings.Contains(page, "<script>") {
+		t.Errorf("expected the message to be escaped, got %s", page)
+	}
+
+	// the apostrophe of the default message is escaped too
+	if page, _ := kubernetes.GetMaintenancePage("", ""); !strings.Contains(page, "We&#39;ll be back shortly.") {
+		t.Errorf("expected the default message, got %s", page)
+	}
+
+	if page, _ := kubernetes.GetMaintenancePage("<h1>custom</h1>", "ignored"); page != "<h1>custom</h1>" {
+		t.Errorf("expected the custom page, got %s", page)
+	}
+
+	if name := kubernetes.GetMaintenanceBackendName(strings.Repeat("a", 70)); len(name) > 63 {
+		t.Errorf("expected the name to fit in 63 characters, got %d", len(name))
+	}
+}
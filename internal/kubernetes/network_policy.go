@@ -0,0 +1,133 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// NetworkPolicyPresetLabel is set on the network policies that are generated from a
+	// preset, and stores the name of the preset
+	NetworkPolicyPresetLabel = "porter.run/network-preset"
+
+	namespaceNetworkPolicyName = "porter-namespace-network-preset"
+)
+
+// ReleaseInstanceLabel is the label that Helm charts set to the name of the release on
+// the resources that they render
+const ReleaseInstanceLabel = "app.kubernetes.io/instance"
+
+// ingressControllerPeer matches the pods of the NGINX ingress controller in any
+// namespace, using the labels set by the upstream ingress-nginx chart
+var ingressControllerPeer = networkingv1.NetworkPolicyPeer{
+	NamespaceSelector: &metav1.LabelSelector{},
+	PodSelector: &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"app.kubernetes.io/name": "ingress-nginx",
+		},
+	},
+}
+
+// GetNetworkPolicyPresetName returns the name of the network policy generated for a
+// release, or for the whole namespace if the release name is empty
+func GetNetworkPolicyPresetName(releaseName string) string {
+	if releaseName == "" {
+		return namespaceNetworkPolicyName
+	}
+
+	return fmt.Sprintf("%s-network-preset", releaseName)
+}
+
+// GetNetworkPolicyForPreset generates the network policy of a preset. The policy selects
+// the pods of the release, or all pods in the namespace if the release name is empty.
+// The open preset does not have a network policy.
+func GetNetworkPolicyForPreset(namespace, releaseName string, preset types.NetworkPolicyPreset) (*networkingv1.NetworkPolicy, error) {
+	var from []networkingv1.NetworkPolicyPeer
+
+	switch preset {
+	case types.NetworkPolicyPresetIngressOnly:
+		from = []networkingv1.NetworkPolicyPeer{ingressControllerPeer}
+	case types.NetworkPolicyPresetNamespaceIsolated:
+		from = []networkingv1.NetworkPolicyPeer{
+			ingressControllerPeer,
+			{
+				// a pod selector without a namespace selector matches pods in the
+				// namespace of the policy
+				PodSelector: &metav1.LabelSelector{},
+			},
+		}
+	case types.NetworkPolicyPresetOpen:
+		return nil, fmt.Errorf("the %s preset does not have a network policy", preset)
+	default:
+		return nil, fmt.Errorf("unknown network policy preset %s", preset)
+	}
+
+	podSelector := metav1.LabelSelector{}
+
+	if releaseName != "" {
+		podSelector.MatchLabels = map[string]string{
+			ReleaseInstanceLabel: releaseName,
+		}
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetNetworkPolicyPresetName(releaseName),
+			Namespace: namespace,
+			Labels: map[string]string{
+				NetworkPolicyPresetLabel: string(preset),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: podSelector,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: from,
+				},
+			},
+		},
+	}, nil
+}
+
+// GetNetworkPolicyPreset returns the preset that is applied to a release, or to the
+// namespace if the release name is empty. The open preset is returned if no preset has
+// been applied.
+func (a *Agent) GetNetworkPolicyPreset(namespace, releaseName string) (types.NetworkPolicyPreset, error) {
+	policy, err := a.GetNetworkPolicy(namespace, GetNetworkPolicyPresetName(releaseName))
+
+	if err == IsNotFoundError {
+		return types.NetworkPolicyPresetOpen, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if preset, ok := policy.Labels[NetworkPolicyPresetLabel]; ok {
+		return types.NetworkPolicyPreset(preset), nil
+	}
+
+	return types.NetworkPolicyPresetOpen, nil
+}
+
+// ApplyNetworkPolicyPreset creates or updates the network policy of a preset for a
+// release, or for the namespace if the release name is empty. Applying the open preset
+// deletes the network policy. Since network policies are additive, a release with the
+// open preset is still restricted by the preset of its namespace.
+func (a *Agent) ApplyNetworkPolicyPreset(namespace, releaseName string, preset types.NetworkPolicyPreset) error {
+	if preset == types.NetworkPolicyPresetOpen {
+		return a.DeleteNetworkPolicy(namespace, GetNetworkPolicyPresetName(releaseName))
+	}
+
+	policy, err := GetNetworkPolicyForPreset(namespace, releaseName, preset)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = a.ApplyNetworkPolicy(policy)
+
+	return err
+}
@@ -0,0 +1,84 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyNetworkPolicyPreset(t *testing.T) {
+	agent := newAgentFixture(t)
+
+	preset, err := agent.GetNetworkPolicyPreset("default", "web")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if preset != types.NetworkPolicyPresetOpen {
+		t.Errorf("expected release without a network policy to be open, got %s", preset)
+	}
+
+	presets := []types.NetworkPolicyPreset{
+		types.NetworkPolicyPresetIngressOnly,
+		types.NetworkPolicyPresetNamespaceIsolated,
+	}
+
+	for _, expPreset := range presets {
+		if err := agent.ApplyNetworkPolicyPreset("default", "web", expPreset); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		preset, err := agent.GetNetworkPolicyPreset("default", "web")
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		if preset != expPreset {
+			t.Errorf("expected preset %s, got %s", expPreset, preset)
+		}
+	}
+
+	policy, err := agent.Clientset.NetworkingV1().NetworkPolicies("default").Get(
+		context.TODO(),
+		kubernetes.GetNetworkPolicyPresetName("web"),
+		metav1.GetOptions{},
+	)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expSelector := map[string]string{
+		"app.kubernetes.io/instance": "web",
+	}
+
+	if diff := deep.Equal(policy.Spec.PodSelector.MatchLabels, expSelector); diff != nil {
+		t.Errorf("incorrect pod selector")
+		t.Error(diff)
+	}
+
+	if numPeers := len(policy.Spec.Ingress[0].From); numPeers != 2 {
+		t.Errorf("expected namespace-isolated policy to have 2 peers, got %d", numPeers)
+	}
+
+	if err := agent.ApplyNetworkPolicyPreset("default", "web", types.NetworkPolicyPresetOpen); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	preset, err = agent.GetNetworkPolicyPreset("default", "web")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if preset != types.NetworkPolicyPresetOpen {
+		t.Errorf("expected open preset to delete the network policy, got %s", preset)
+	}
+}
@@ -11,6 +11,7 @@ import (
 )
 
 type TotalAllocatable struct {
+	Nodes  uint
 	CPU    uint
 	Memory uint
 }
@@ -33,11 +34,39 @@ func GetAllocatableResources(clientset kubernetes.Interface) (*TotalAllocatable,
 	}
 
 	return &TotalAllocatable{
+		Nodes:  uint(len(nodeList.Items)),
 		CPU:    uint(totCPU),
 		Memory: uint(totMem),
 	}, nil
 }
 
+// TotalRequested is the sum of the resource requests of the pods that are scheduled on
+// the nodes of a cluster, in millicores and bytes
+type TotalRequested struct {
+	CPU    uint
+	Memory uint
+}
+
+// GetRequestedResources returns the total resource requests of the pods in the cluster
+// that have not terminated
+func GetRequestedResources(clientset kubernetes.Interface) (*TotalRequested, error) {
+	podList, err := clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	reqs, _ := getPodsTotalRequestsAndLimits(podList)
+	cpuReqs, memoryReqs := reqs[v1.ResourceCPU], reqs[v1.ResourceMemory]
+
+	return &TotalRequested{
+		CPU:    uint(cpuReqs.MilliValue()),
+		Memory: uint(memoryReqs.Value()),
+	}, nil
+}
+
 type NodeUsage struct {
 	cpuReqs                        string
 	memoryReqs                     string
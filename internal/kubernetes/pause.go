@@ -0,0 +1,183 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PausedState is the state of the controllers of a release before it was paused, which
+// is restored when the release is resumed. Keys are of the form kind/name.
+type PausedState struct {
+	Replicas map[string]int32 `json:"replicas"`
+
+	// SuspendedCronJobs are the cronjobs that were suspended by the pause, which
+	// excludes cronjobs that were already suspended
+	SuspendedCronJobs []string `json:"suspended_cronjobs"`
+}
+
+// PauseControllers scales the deployments and statefulsets among the controllers to
+// zero replicas and suspends the cronjobs, and returns the state needed to resume them
+func (a *Agent) PauseControllers(controllers []grapher.Object) (*PausedState, error) {
+	res := &PausedState{
+		Replicas:          make(map[string]int32),
+		SuspendedCronJobs: make([]string, 0),
+	}
+
+	for _, controller := range controllers {
+		key := getControllerKey(controller)
+
+		switch strings.ToLower(controller.Kind) {
+		case "deployment", "statefulset":
+			prev, err := a.scaleController(controller, 0)
+
+			if err != nil {
+				return res, err
+			}
+
+			res.Replicas[key] = prev
+		case "cronjob":
+			cronJob, err := a.GetCronJob(controller)
+
+			if err != nil {
+				return res, err
+			}
+
+			if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+				continue
+			}
+
+			if err := a.setCronJobSuspended(controller, true); err != nil {
+				return res, err
+			}
+
+			res.SuspendedCronJobs = append(res.SuspendedCronJobs, key)
+		}
+	}
+
+	return res, nil
+}
+
+// ResumeControllers restores the replica counts and unsuspends the cronjobs that were
+// recorded when the controllers were paused. Controllers that no longer exist are skipped.
+func (a *Agent) ResumeControllers(controllers []grapher.Object, state *PausedState) error {
+	suspended := make(map[string]bool)
+
+	for _, key := range state.SuspendedCronJobs {
+		suspended[key] = true
+	}
+
+	for _, controller := range controllers {
+		key := getControllerKey(controller)
+
+		switch strings.ToLower(controller.Kind) {
+		case "deployment", "statefulset":
+			replicas, ok := state.Replicas[key]
+
+			if !ok {
+				continue
+			}
+
+			if _, err := a.scaleController(controller, replicas); err != nil && err != IsNotFoundError {
+				return err
+			}
+		case "cronjob":
+			if !suspended[key] {
+				continue
+			}
+
+			if err := a.setCronJobSuspended(controller, false); err != nil && err != IsNotFoundError {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// scaleController sets the replicas of a deployment or statefulset, and returns the
+// previous number of replicas
+func (a *Agent) scaleController(controller grapher.Object, replicas int32) (int32, error) {
+	var prev int32
+
+	switch strings.ToLower(controller.Kind) {
+	case "deployment":
+		scale, err := a.Clientset.AppsV1().Deployments(controller.Namespace).GetScale(
+			context.TODO(),
+			controller.Name,
+			metav1.GetOptions{},
+		)
+
+		if err != nil {
+			return 0, wrapNotFound(err)
+		}
+
+		prev = scale.Spec.Replicas
+		scale.Spec.Replicas = replicas
+
+		_, err = a.Clientset.AppsV1().Deployments(controller.Namespace).UpdateScale(
+			context.TODO(),
+			controller.Name,
+			scale,
+			metav1.UpdateOptions{},
+		)
+
+		return prev, err
+	case "statefulset":
+		scale, err := a.Clientset.AppsV1().StatefulSets(controller.Namespace).GetScale(
+			context.TODO(),
+			controller.Name,
+			metav1.GetOptions{},
+		)
+
+		if err != nil {
+			return 0, wrapNotFound(err)
+		}
+
+		prev = scale.Spec.Replicas
+		scale.Spec.Replicas = replicas
+
+		_, err = a.Clientset.AppsV1().StatefulSets(controller.Namespace).UpdateScale(
+			context.TODO(),
+			controller.Name,
+			scale,
+			metav1.UpdateOptions{},
+		)
+
+		return prev, err
+	}
+
+	return 0, fmt.Errorf("cannot scale controller of kind %s", controller.Kind)
+}
+
+func (a *Agent) setCronJobSuspended(controller grapher.Object, suspend bool) error {
+	patch := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)
+
+	_, err := a.Clientset.BatchV1beta1().CronJobs(controller.Namespace).Patch(
+		context.TODO(),
+		controller.Name,
+		types.MergePatchType,
+		[]byte(patch),
+		metav1.PatchOptions{},
+	)
+
+	return wrapNotFound(err)
+}
+
+func getControllerKey(controller grapher.Object) string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(controller.Kind), controller.Name)
+}
+
+func wrapNotFound(err error) error {
+	if err != nil && errors.IsNotFound(err) {
+		return IsNotFoundError
+	}
+
+	return err
+}
@@ -0,0 +1,348 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	v1 "k8s.io/api/core/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Namespace labels that configure the Pod Security admission controller
+const (
+	PodSecurityEnforceLabel        = "pod-security.kubernetes.io/enforce"
+	PodSecurityEnforceVersionLabel = "pod-security.kubernetes.io/enforce-version"
+)
+
+// baselineCapabilities are the capabilities that containers may add under the baseline
+// level
+var baselineCapabilities = map[v1.Capability]bool{
+	"AUDIT_WRITE":      true,
+	"CHOWN":            true,
+	"DAC_OVERRIDE":     true,
+	"FOWNER":           true,
+	"FSETID":           true,
+	"KILL":             true,
+	"MKNOD":            true,
+	"NET_BIND_SERVICE": true,
+	"SETFCAP":          true,
+	"SETGID":           true,
+	"SETPCAP":          true,
+	"SETUID":           true,
+	"SYS_CHROOT":       true,
+}
+
+// baselineSysctls are the sysctls that pods may set under the baseline level
+var baselineSysctls = map[string]bool{
+	"kernel.shm_rmid_forced":              true,
+	"net.ipv4.ip_local_port_range":        true,
+	"net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_syncookies":             true,
+	"net.ipv4.ping_group_range":           true,
+}
+
+// baselineSELinuxTypes are the SELinux types that pods may set under the baseline level
+var baselineSELinuxTypes = map[string]bool{
+	"":                 true,
+	"container_t":      true,
+	"container_init_t": true,
+	"container_kvm_t":  true,
+}
+
+// SetNamespacePodSecurityLevel enforces a Pod Security Standards level on a namespace
+// by labeling it. Setting the privileged level removes the labels, so that the default
+// level of the cluster applies.
+func (a *Agent) SetNamespacePodSecurityLevel(namespace string, level types.PodSecurityLevel) error {
+	labels := map[string]*string{
+		PodSecurityEnforceLabel:        nil,
+		PodSecurityEnforceVersionLabel: nil,
+	}
+
+	if level != types.PodSecurityLevelPrivileged {
+		levelStr := string(level)
+		version := "latest"
+
+		labels[PodSecurityEnforceLabel] = &levelStr
+		labels[PodSecurityEnforceVersionLabel] = &version
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labels,
+		},
+	}
+
+	patchBytes, err := json.Marshal(patch)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = a.Clientset.CoreV1().Namespaces().Patch(
+		context.TODO(),
+		namespace,
+		k8sTypes.MergePatchType,
+		patchBytes,
+		metav1.PatchOptions{},
+	)
+
+	return wrapNotFound(err)
+}
+
+// GetControllerPodSpec returns the pod spec of the pod template of a controller
+func GetControllerPodSpec(obj grapher.Object) (*v1.PodSpec, error) {
+	podSpecKeys := getPodSpecKeys(obj.Kind)
+
+	if podSpecKeys == nil {
+		return nil, fmt.Errorf("%s is not a controller", obj.Kind)
+	}
+
+	podSpecMap, _ := getNestedField(obj.RawYAML, podSpecKeys...).(map[string]interface{})
+
+	if podSpecMap == nil {
+		return nil, fmt.Errorf("%s %s does not have a pod template", obj.Kind, obj.Name)
+	}
+
+	podSpecBytes, err := json.Marshal(podSpecMap)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &v1.PodSpec{}
+
+	if err := json.Unmarshal(podSpecBytes, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CheckPodSecurity returns the reasons that pods with the given spec would be rejected
+// under a Pod Security Standards level, or an empty list if they would be admitted
+func CheckPodSecurity(level types.PodSecurityLevel, spec *v1.PodSpec) []string {
+	res := make([]string, 0)
+
+	if level != types.PodSecurityLevelBaseline && level != types.PodSecurityLevelRestricted {
+		return res
+	}
+
+	res = append(res, checkPodSecurityBaseline(spec)...)
+
+	if level == types.PodSecurityLevelRestricted {
+		res = append(res, checkPodSecurityRestricted(spec)...)
+	}
+
+	return res
+}
+
+func getAllContainers(spec *v1.PodSpec) []v1.Container {
+	res := make([]v1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	res = append(res, spec.InitContainers...)
+
+	return append(res, spec.Containers...)
+}
+
+func checkPodSecurityBaseline(spec *v1.PodSpec) []string {
+	res := make([]string, 0)
+
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		res = append(res, "pod must not share the host network, PID or IPC namespaces")
+	}
+
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			res = append(res, fmt.Sprintf("volume %s must not be a hostPath volume", volume.Name))
+		}
+	}
+
+	if podSecurityContext := spec.SecurityContext; podSecurityContext != nil {
+		if opts := podSecurityContext.SELinuxOptions; opts != nil && !isBaselineSELinuxOptions(opts) {
+			res = append(res, "pod must not set a custom SELinux user, role or type")
+		}
+
+		if profile := podSecurityContext.SeccompProfile; profile != nil && profile.Type == v1.SeccompProfileTypeUnconfined {
+			res = append(res, "pod must not set an unconfined seccomp profile")
+		}
+
+		for _, sysctl := range podSecurityContext.Sysctls {
+			if !baselineSysctls[sysctl.Name] {
+				res = append(res, fmt.Sprintf("pod must not set the unsafe sysctl %s", sysctl.Name))
+			}
+		}
+	}
+
+	for _, container := range getAllContainers(spec) {
+		for _, port := range container.Ports {
+			if port.HostPort != 0 {
+				res = append(res, fmt.Sprintf("container %s must not use host port %d", container.Name, port.HostPort))
+			}
+		}
+
+		securityContext := container.SecurityContext
+
+		if securityContext == nil {
+			continue
+		}
+
+		if securityContext.Privileged != nil && *securityContext.Privileged {
+			res = append(res, fmt.Sprintf("container %s must not be privileged", container.Name))
+		}
+
+		if securityContext.Capabilities != nil {
+			for _, capability := range securityContext.Capabilities.Add {
+				if !baselineCapabilities[capability] {
+					res = append(res, fmt.Sprintf("container %s must not add the capability %s", container.Name, capability))
+				}
+			}
+		}
+
+		if opts := securityContext.SELinuxOptions; opts != nil && !isBaselineSELinuxOptions(opts) {
+			res = append(res, fmt.Sprintf("container %s must not set a custom SELinux user, role or type", container.Name))
+		}
+
+		if securityContext.ProcMount != nil && *securityContext.ProcMount != v1.DefaultProcMount {
+			res = append(res, fmt.Sprintf("container %s must use the default proc mount", container.Name))
+		}
+
+		if profile := securityContext.SeccompProfile; profile != nil && profile.Type == v1.SeccompProfileTypeUnconfined {
+			res = append(res, fmt.Sprintf("container %s must not set an unconfined seccomp profile", container.Name))
+		}
+	}
+
+	return res
+}
+
+func isBaselineSELinuxOptions(opts *v1.SELinuxOptions) bool {
+	return opts.User == "" && opts.Role == "" && baselineSELinuxTypes[opts.Type]
+}
+
+func checkPodSecurityRestricted(spec *v1.PodSpec) []string {
+	res := make([]string, 0)
+
+	for _, volume := range spec.Volumes {
+		source := volume.VolumeSource
+
+		allowed := source.ConfigMap != nil ||
+			source.CSI != nil ||
+			source.DownwardAPI != nil ||
+			source.EmptyDir != nil ||
+			source.Ephemeral != nil ||
+			source.PersistentVolumeClaim != nil ||
+			source.Projected != nil ||
+			source.Secret != nil
+
+		// hostPath volumes are already reported by the baseline checks
+		if !allowed && source.HostPath == nil {
+			res = append(res, fmt.Sprintf("volume %s must use an allowed volume type", volume.Name))
+		}
+	}
+
+	podRunAsNonRoot := false
+	podSeccompSet := false
+
+	if podSecurityContext := spec.SecurityContext; podSecurityContext != nil {
+		podRunAsNonRoot = podSecurityContext.RunAsNonRoot != nil && *podSecurityContext.RunAsNonRoot
+
+		if podSecurityContext.RunAsUser != nil && *podSecurityContext.RunAsUser == 0 {
+			res = append(res, "pod must not run as the root user")
+		}
+
+		podSeccompSet = isRestrictedSeccompProfile(podSecurityContext.SeccompProfile)
+	}
+
+	for _, container := range getAllContainers(spec) {
+		securityContext := container.SecurityContext
+
+		if securityContext == nil {
+			securityContext = &v1.SecurityContext{}
+		}
+
+		if securityContext.AllowPrivilegeEscalation == nil || *securityContext.AllowPrivilegeEscalation {
+			res = append(res, fmt.Sprintf("container %s must set securityContext.allowPrivilegeEscalation to false", container.Name))
+		}
+
+		if securityContext.RunAsNonRoot != nil {
+			if !*securityContext.RunAsNonRoot {
+				res = append(res, fmt.Sprintf("container %s must not set securityContext.runAsNonRoot to false", container.Name))
+			}
+		} else if !podRunAsNonRoot {
+			res = append(res, fmt.Sprintf("container %s must set securityContext.runAsNonRoot to true", container.Name))
+		}
+
+		if securityContext.RunAsUser != nil && *securityContext.RunAsUser == 0 {
+			res = append(res, fmt.Sprintf("container %s must not run as the root user", container.Name))
+		}
+
+		if !podSeccompSet && !isRestrictedSeccompProfile(securityContext.SeccompProfile) {
+			res = append(res, fmt.Sprintf("container %s must set a RuntimeDefault or Localhost seccomp profile", container.Name))
+		}
+
+		dropsAll := false
+
+		if capabilities := securityContext.Capabilities; capabilities != nil {
+			for _, capability := range capabilities.Drop {
+				dropsAll = dropsAll || capability == "ALL"
+			}
+
+			for _, capability := range capabilities.Add {
+				// other capabilities that are not allowed by the baseline level are
+				// already reported by the baseline checks
+				if capability != "NET_BIND_SERVICE" && baselineCapabilities[capability] {
+					res = append(res, fmt.Sprintf("container %s must not add the capability %s", container.Name, capability))
+				}
+			}
+		}
+
+		if !dropsAll {
+			res = append(res, fmt.Sprintf("container %s must drop all capabilities", container.Name))
+		}
+	}
+
+	return res
+}
+
+func isRestrictedSeccompProfile(profile *v1.SeccompProfile) bool {
+	return profile != nil &&
+		(profile.Type == v1.SeccompProfileTypeRuntimeDefault || profile.Type == v1.SeccompProfileTypeLocalhost)
+}
+
+// GetPodSecurityViolations checks the pod templates of the controllers among the objects
+// of a release against a Pod Security Standards level, and returns the controllers
+// whose pods would be rejected
+func GetPodSecurityViolations(
+	level types.PodSecurityLevel,
+	namespace, releaseName string,
+	objs []grapher.Object,
+) ([]*types.PodSecurityViolation, error) {
+	res := make([]*types.PodSecurityViolation, 0)
+
+	for _, obj := range objs {
+		if getPodSpecKeys(obj.Kind) == nil {
+			continue
+		}
+
+		spec, err := GetControllerPodSpec(obj)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if reasons := CheckPodSecurity(level, spec); len(reasons) > 0 {
+			res = append(res, &types.PodSecurityViolation{
+				Namespace:   namespace,
+				ReleaseName: releaseName,
+				Kind:        obj.Kind,
+				Name:        obj.Name,
+				Reasons:     reasons,
+			})
+		}
+	}
+
+	return res, nil
+}
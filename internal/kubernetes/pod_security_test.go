@@ -0,0 +1,116 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+)
+
+const testPodSecurityManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hardened
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+        seccompProfile:
+          type: RuntimeDefault
+      containers:
+      - name: hardened
+        image: nginx
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      hostNetwork: true
+      containers:
+      - name: agent
+        image: agent
+        securityContext:
+          privileged: true
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+func TestGetPodSecurityViolations(t *testing.T) {
+	objs := grapher.ParseObjs(grapher.ImportMultiDocYAML([]byte(testPodSecurityManifest)), "default")
+
+	violations, err := kubernetes.GetPodSecurityViolations(types.PodSecurityLevelPrivileged, "default", "web", objs)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(violations) != 0 {
+		t.Errorf("expected no violations for the privileged level, got %d", len(violations))
+	}
+
+	violations, err = kubernetes.GetPodSecurityViolations(types.PodSecurityLevelBaseline, "default", "web", objs)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expBaseline := []*types.PodSecurityViolation{
+		{
+			Namespace:   "default",
+			ReleaseName: "web",
+			Kind:        "DaemonSet",
+			Name:        "agent",
+			Reasons: []string{
+				"pod must not share the host network, PID or IPC namespaces",
+				"container agent must not be privileged",
+			},
+		},
+	}
+
+	if diff := deep.Equal(violations, expBaseline); diff != nil {
+		t.Errorf("incorrect baseline violations")
+		t.Error(diff)
+	}
+
+	violations, err = kubernetes.GetPodSecurityViolations(types.PodSecurityLevelRestricted, "default", "web", objs)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	violatingNames := make([]string, 0)
+
+	for _, violation := range violations {
+		violatingNames = append(violatingNames, violation.Name)
+	}
+
+	if diff := deep.Equal(violatingNames, []string{"web", "agent"}); diff != nil {
+		t.Errorf("incorrect restricted violations")
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,78 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetPreviewIngressName returns the name of the ingress generated for a release of a
+// preview environment with path-based routing
+func GetPreviewIngressName(releaseName string) string {
+	return fmt.Sprintf("%s-preview-path", releaseName)
+}
+
+// GetPreviewPathPrefix returns the path prefix that a release of a preview environment is
+// served under on the shared host, such as /pr-123/web/
+func GetPreviewPathPrefix(prNumber uint, releaseName string) string {
+	return fmt.Sprintf("/pr-%d/%s/", prNumber, releaseName)
+}
+
+// GetPreviewIngress generates the ingress that routes a path prefix on a shared host to
+// the service of a web release. The prefix is stripped by the NGINX ingress controller, so
+// that the application is served from the root path.
+func GetPreviewIngress(namespace, releaseName, host, pathPrefix string) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypeImplementationSpecific
+
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetPreviewIngressName(releaseName),
+			Namespace: namespace,
+			Labels: map[string]string{
+				ReleaseInstanceLabel: releaseName,
+			},
+			Annotations: map[string]string{
+				"kubernetes.io/ingress.class":                "nginx",
+				"nginx.ingress.kubernetes.io/rewrite-target": "/$2",
+				"nginx.ingress.kubernetes.io/use-regex":      "true",
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									// the prefix without its trailing slash is matched too, and
+									// the second group is the path that is passed on
+									Path:     fmt.Sprintf("%s(/|$)(.*)", pathPrefix[:len(pathPrefix)-1]),
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											// the web chart names its service after the release
+											Name: fmt.Sprintf("%s-web", releaseName),
+											Port: networkingv1.ServiceBackendPort{
+												Number: 80,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ApplyPreviewIngress creates or updates the ingress that routes a path prefix on a
+// shared host to a web release of a preview environment
+func (a *Agent) ApplyPreviewIngress(namespace, releaseName, host, pathPrefix string) error {
+	_, err := a.ApplyIngress(GetPreviewIngress(namespace, releaseName, host, pathPrefix))
+
+	return err
+}
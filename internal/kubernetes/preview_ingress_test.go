@@ -0,0 +1,57 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyPreviewIngress(t *testing.T) {
+	agent := newAgentFixture(t)
+
+	prefix := kubernetes.GetPreviewPathPrefix(123, "web")
+
+	if prefix != "/pr-123/web/" {
+		t.Errorf("unexpected path prefix %s", prefix)
+	}
+
+	// applying twice updates the existing ingress
+	for _, host := range []string{"preview.example.com", "preview2.example.com"} {
+		if err := agent.ApplyPreviewIngress("pr-123-app", "web", host, prefix); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	ingress, err := agent.Clientset.NetworkingV1().Ingresses("pr-123-app").Get(
+		context.TODO(),
+		kubernetes.GetPreviewIngressName("web"),
+		metav1.GetOptions{},
+	)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(ingress.Spec.Rules) != 1 {
+		t.Fatalf("expected 1 rule, got %d", len(ingress.Spec.Rules))
+	}
+
+	rule := ingress.Spec.Rules[0]
+
+	if rule.Host != "preview2.example.com" {
+		t.Errorf("expected host preview2.example.com, got %s", rule.Host)
+	}
+
+	path := rule.HTTP.Paths[0]
+
+	if path.Path != "/pr-123/web(/|$)(.*)" {
+		t.Errorf("unexpected path %s", path.Path)
+	}
+
+	if path.Backend.Service.Name != "web-web" {
+		t.Errorf("expected service web-web, got %s", path.Backend.Service.Name)
+	}
+}
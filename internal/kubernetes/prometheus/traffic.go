@@ -0,0 +1,115 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// HasIngressRequestMetrics returns true if Prometheus has request metrics from the NGINX
+// ingress controller, so that the absence of requests to an ingress can be told apart
+// from the absence of metrics
+func HasIngressRequestMetrics(clientset kubernetes.Interface, service *v1.Service) (bool, error) {
+	values, err := queryInstant(clientset, service, "count(nginx_ingress_controller_requests)")
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(values) > 0 && values[0] > 0, nil
+}
+
+// GetIngressRequestCount returns the number of requests that the ingresses in a namespace
+// received over a period. Ingresses that never received a request have no metrics, so
+// they are counted as zero requests.
+func GetIngressRequestCount(
+	clientset kubernetes.Interface,
+	service *v1.Service,
+	namespace string,
+	ingressNames []string,
+	period time.Duration,
+) (float64, error) {
+	if len(ingressNames) == 0 {
+		return 0, nil
+	}
+
+	query := fmt.Sprintf(
+		`sum(increase(nginx_ingress_controller_requests{namespace="%s",ingress=~"%s"}[%ds]))`,
+		namespace,
+		strings.Join(ingressNames, "|"),
+		int64(period.Seconds()),
+	)
+
+	values, err := queryInstant(clientset, service, query)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var res float64
+
+	for _, val := range values {
+		res += val
+	}
+
+	return res, nil
+}
+
+// queryInstant runs an instant query and returns the value of each series of the result
+func queryInstant(clientset kubernetes.Interface, service *v1.Service, query string) ([]float64, error) {
+	if len(service.Spec.Ports) == 0 {
+		return nil, fmt.Errorf("prometheus service has no exposed ports to query")
+	}
+
+	resp := clientset.CoreV1().Services(service.Namespace).ProxyGet(
+		"http",
+		service.Name,
+		fmt.Sprintf("%d", service.Spec.Ports[0].Port),
+		"/api/v1/query",
+		map[string]string{
+			"query": query,
+		},
+	)
+
+	rawQuery, err := resp.DoRaw(context.TODO())
+
+	if err != nil {
+		return nil, err
+	}
+
+	rawQueryObj := &promRawInstantQuery{}
+
+	if err := json.Unmarshal(rawQuery, rawQueryObj); err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, 0)
+
+	for _, result := range rawQueryObj.Data.Result {
+		if len(result.Value) != 2 {
+			continue
+		}
+
+		valStr, ok := result.Value[1].(string)
+
+		if !ok {
+			continue
+		}
+
+		val, err := strconv.ParseFloat(valStr, 64)
+
+		if err != nil {
+			continue
+		}
+
+		res = append(res, val)
+	}
+
+	return res, nil
+}
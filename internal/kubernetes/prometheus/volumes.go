@@ -0,0 +1,94 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type promRawInstantQuery struct {
+	Data struct {
+		Result []struct {
+			Metric struct {
+				PersistentVolumeClaim string `json:"persistentvolumeclaim,omitempty"`
+			} `json:"metric,omitempty"`
+
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// GetVolumeUsage returns the number of bytes used on the volumes of persistent volume
+// claims in a namespace, as reported by the kubelet. Claims whose volumes are not
+// mounted have no usage.
+func GetVolumeUsage(
+	clientset kubernetes.Interface,
+	service *v1.Service,
+	namespace string,
+	claimNames []string,
+) (map[string]int64, error) {
+	if len(service.Spec.Ports) == 0 {
+		return nil, fmt.Errorf("prometheus service has no exposed ports to query")
+	}
+
+	res := make(map[string]int64)
+
+	if len(claimNames) == 0 {
+		return res, nil
+	}
+
+	query := fmt.Sprintf(
+		`max(kubelet_volume_stats_used_bytes{namespace="%s",persistentvolumeclaim=~"%s"}) by (persistentvolumeclaim)`,
+		namespace,
+		strings.Join(claimNames, "|"),
+	)
+
+	resp := clientset.CoreV1().Services(service.Namespace).ProxyGet(
+		"http",
+		service.Name,
+		fmt.Sprintf("%d", service.Spec.Ports[0].Port),
+		"/api/v1/query",
+		map[string]string{
+			"query": query,
+		},
+	)
+
+	rawQuery, err := resp.DoRaw(context.TODO())
+
+	if err != nil {
+		return nil, err
+	}
+
+	rawQueryObj := &promRawInstantQuery{}
+
+	if err := json.Unmarshal(rawQuery, rawQueryObj); err != nil {
+		return nil, err
+	}
+
+	for _, result := range rawQueryObj.Data.Result {
+		if len(result.Value) != 2 {
+			continue
+		}
+
+		valStr, ok := result.Value[1].(string)
+
+		if !ok {
+			continue
+		}
+
+		val, err := strconv.ParseFloat(valStr, 64)
+
+		if err != nil {
+			continue
+		}
+
+		res[result.Metric.PersistentVolumeClaim] = int64(val)
+	}
+
+	return res, nil
+}
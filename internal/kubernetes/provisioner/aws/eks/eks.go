@@ -1,6 +1,13 @@
 package eks
 
-import v1 "k8s.io/api/core/v1"
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+)
 
 // Conf is the EKS cluster config required for the provisioner
 type Conf struct {
@@ -8,6 +15,25 @@ type Conf struct {
 	ClusterName string
 	MachineType string
 	IssuerEmail string
+
+	NodeGroups              []*types.EKSNodeGroup
+	VPCID                   string
+	SubnetIDs               []string
+	EnableClusterAutoscaler bool
+}
+
+// NewConf creates a Conf from the (last-applied) EKS provisioning request
+func NewConf(awsRegion string, req *types.CreateEKSInfraRequest) *Conf {
+	return &Conf{
+		AWSRegion:               awsRegion,
+		ClusterName:             req.EKSName,
+		MachineType:             req.MachineType,
+		IssuerEmail:             req.IssuerEmail,
+		NodeGroups:              req.NodeGroups,
+		VPCID:                   req.VPCID,
+		SubnetIDs:               req.SubnetIDs,
+		EnableClusterAutoscaler: req.EnableClusterAutoscaler,
+	}
 }
 
 // AttachEKSEnv adds the relevant EKS env for the provisioner
@@ -32,5 +58,35 @@ func (conf *Conf) AttachEKSEnv(env []v1.EnvVar) []v1.EnvVar {
 		Value: conf.IssuerEmail,
 	})
 
+	// node groups are passed as a JSON-encoded list, which is decoded into a
+	// terraform variable by the provisioner
+	if len(conf.NodeGroups) > 0 {
+		nodeGroupBytes, err := json.Marshal(conf.NodeGroups)
+
+		if err == nil {
+			env = append(env, v1.EnvVar{
+				Name:  "EKS_NODE_GROUPS",
+				Value: string(nodeGroupBytes),
+			})
+		}
+	}
+
+	if conf.VPCID != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "EKS_VPC_ID",
+			Value: conf.VPCID,
+		})
+
+		env = append(env, v1.EnvVar{
+			Name:  "EKS_SUBNET_IDS",
+			Value: strings.Join(conf.SubnetIDs, ","),
+		})
+	}
+
+	env = append(env, v1.EnvVar{
+		Name:  "EKS_ENABLE_CLUSTER_AUTOSCALER",
+		Value: strconv.FormatBool(conf.EnableClusterAutoscaler),
+	})
+
 	return env
 }
@@ -0,0 +1,56 @@
+package rdsnetwork
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Conf is the config required for the provisioner to open the security group of an RDS
+// instance to the nodes of an EKS cluster in the same VPC
+type Conf struct {
+	AWSRegion      string
+	EKSName        string
+	VPCID          string
+	DBInstanceID   string
+	DBInstancePort string
+}
+
+func NewConf(lastApplied *types.PrivateNetworkInfraLastApplied) *Conf {
+	return &Conf{
+		AWSRegion:      lastApplied.AWSRegion,
+		EKSName:        lastApplied.EKSName,
+		VPCID:          lastApplied.VPCID,
+		DBInstanceID:   lastApplied.DBInstanceID,
+		DBInstancePort: lastApplied.DBInstancePort,
+	}
+}
+
+// AttachRDSNetworkEnv adds the relevant RDS network env for the provisioner
+func (conf *Conf) AttachRDSNetworkEnv(env []v1.EnvVar) []v1.EnvVar {
+	env = append(env, v1.EnvVar{
+		Name:  "AWS_REGION",
+		Value: conf.AWSRegion,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "EKS_CLUSTER_NAME",
+		Value: conf.EKSName,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "VPC_ID",
+		Value: conf.VPCID,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "DB_INSTANCE_ID",
+		Value: conf.DBInstanceID,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "DB_INSTANCE_PORT",
+		Value: conf.DBInstancePort,
+	})
+
+	return env
+}
@@ -0,0 +1,43 @@
+package s3
+
+import (
+	"strconv"
+
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Conf is the S3 bucket config required for the provisioner
+type Conf struct {
+	AWSRegion  string
+	BucketName string
+	Versioning string
+}
+
+func NewConf(lastApplied *types.BucketInfraLastApplied) *Conf {
+	return &Conf{
+		AWSRegion:  lastApplied.AWSRegion,
+		BucketName: lastApplied.BucketName,
+		Versioning: strconv.FormatBool(lastApplied.Versioning),
+	}
+}
+
+// AttachS3Env adds the relevant S3 env for the provisioner
+func (conf *Conf) AttachS3Env(env []v1.EnvVar) []v1.EnvVar {
+	env = append(env, v1.EnvVar{
+		Name:  "AWS_REGION",
+		Value: conf.AWSRegion,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "BUCKET_NAME",
+		Value: conf.BucketName,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "BUCKET_VERSIONING",
+		Value: conf.Versioning,
+	})
+
+	return env
+}
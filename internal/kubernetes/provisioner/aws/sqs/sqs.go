@@ -0,0 +1,70 @@
+package sqs
+
+import (
+	"strconv"
+
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Conf is the SQS queue config required for the provisioner
+type Conf struct {
+	AWSRegion         string
+	QueueName         string
+	FIFO              string
+	VisibilityTimeout string
+	MessageRetention  string
+}
+
+func NewConf(lastApplied *types.QueueInfraLastApplied) *Conf {
+	return &Conf{
+		AWSRegion:         lastApplied.AWSRegion,
+		QueueName:         lastApplied.QueueName,
+		FIFO:              strconv.FormatBool(lastApplied.FIFO),
+		VisibilityTimeout: formatSeconds(lastApplied.VisibilityTimeout),
+		MessageRetention:  formatSeconds(lastApplied.MessageRetention),
+	}
+}
+
+// AttachSQSEnv adds the relevant SQS env for the provisioner
+func (conf *Conf) AttachSQSEnv(env []v1.EnvVar) []v1.EnvVar {
+	env = append(env, v1.EnvVar{
+		Name:  "AWS_REGION",
+		Value: conf.AWSRegion,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "QUEUE_NAME",
+		Value: conf.QueueName,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "QUEUE_FIFO",
+		Value: conf.FIFO,
+	})
+
+	// unset durations use the defaults of the module
+	if conf.VisibilityTimeout != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "QUEUE_VISIBILITY_TIMEOUT",
+			Value: conf.VisibilityTimeout,
+		})
+	}
+
+	if conf.MessageRetention != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "QUEUE_MESSAGE_RETENTION",
+			Value: conf.MessageRetention,
+		})
+	}
+
+	return env
+}
+
+func formatSeconds(seconds uint) string {
+	if seconds == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(seconds), 10)
+}
@@ -0,0 +1,49 @@
+package cloudsqlnetwork
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Conf is the config required for the provisioner to connect a Cloud SQL instance to
+// the network of a GKE cluster through private services access
+type Conf struct {
+	GCPProjectID         string
+	GCPRegion            string
+	GKEName              string
+	CloudSQLInstanceName string
+}
+
+func NewConf(lastApplied *types.PrivateNetworkInfraLastApplied) *Conf {
+	return &Conf{
+		GCPProjectID:         lastApplied.GCPProjectID,
+		GCPRegion:            lastApplied.GCPRegion,
+		GKEName:              lastApplied.GKEName,
+		CloudSQLInstanceName: lastApplied.CloudSQLInstanceName,
+	}
+}
+
+// AttachCloudSQLNetworkEnv adds the relevant Cloud SQL network env for the provisioner
+func (conf *Conf) AttachCloudSQLNetworkEnv(env []v1.EnvVar) []v1.EnvVar {
+	env = append(env, v1.EnvVar{
+		Name:  "GCP_PROJECT_ID",
+		Value: conf.GCPProjectID,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "GCP_REGION",
+		Value: conf.GCPRegion,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "GKE_CLUSTER_NAME",
+		Value: conf.GKEName,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "CLOUDSQL_INSTANCE_NAME",
+		Value: conf.CloudSQLInstanceName,
+	})
+
+	return env
+}
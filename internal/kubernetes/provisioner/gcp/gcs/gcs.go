@@ -0,0 +1,50 @@
+package gcs
+
+import (
+	"strconv"
+
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Conf is the GCS bucket config required for the provisioner
+type Conf struct {
+	GCPProjectID string
+	GCPRegion    string
+	BucketName   string
+	Versioning   string
+}
+
+func NewConf(lastApplied *types.BucketInfraLastApplied) *Conf {
+	return &Conf{
+		GCPProjectID: lastApplied.GCPProjectID,
+		GCPRegion:    lastApplied.GCPRegion,
+		BucketName:   lastApplied.BucketName,
+		Versioning:   strconv.FormatBool(lastApplied.Versioning),
+	}
+}
+
+// AttachGCSEnv adds the relevant GCS env for the provisioner
+func (conf *Conf) AttachGCSEnv(env []v1.EnvVar) []v1.EnvVar {
+	env = append(env, v1.EnvVar{
+		Name:  "GCP_PROJECT_ID",
+		Value: conf.GCPProjectID,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "GCP_REGION",
+		Value: conf.GCPRegion,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "BUCKET_NAME",
+		Value: conf.BucketName,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "BUCKET_VERSIONING",
+		Value: conf.Versioning,
+	})
+
+	return env
+}
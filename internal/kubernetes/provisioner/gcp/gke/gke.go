@@ -1,10 +1,35 @@
 package gke
 
-import v1 "k8s.io/api/core/v1"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+)
 
 // Conf is the GKE cluster config required for the provisioner
 type Conf struct {
 	GCPRegion, GCPProjectID, ClusterName, IssuerEmail string
+
+	Autopilot   bool
+	Regional    bool
+	NodeZones   []string
+	MachineType string
+}
+
+// NewConf creates a Conf from the (last-applied) GKE provisioning request
+func NewConf(gcpProjectID string, req *types.CreateGKEInfraRequest) *Conf {
+	return &Conf{
+		GCPProjectID: gcpProjectID,
+		GCPRegion:    req.GCPRegion,
+		ClusterName:  req.GKEName,
+		IssuerEmail:  req.IssuerEmail,
+		Autopilot:    req.Autopilot,
+		Regional:     req.Regional,
+		NodeZones:    req.NodeZones,
+		MachineType:  req.MachineType,
+	}
 }
 
 // AttachGKEEnv adds the relevant GKE env for the provisioner
@@ -29,5 +54,29 @@ func (conf *Conf) AttachGKEEnv(env []v1.EnvVar) []v1.EnvVar {
 		Value: conf.IssuerEmail,
 	})
 
+	env = append(env, v1.EnvVar{
+		Name:  "GKE_AUTOPILOT",
+		Value: strconv.FormatBool(conf.Autopilot),
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "GKE_REGIONAL",
+		Value: strconv.FormatBool(conf.Regional),
+	})
+
+	if len(conf.NodeZones) > 0 {
+		env = append(env, v1.EnvVar{
+			Name:  "GKE_NODE_ZONES",
+			Value: strings.Join(conf.NodeZones, ","),
+		})
+	}
+
+	if conf.MachineType != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "GKE_MACHINE_TYPE",
+			Value: conf.MachineType,
+		})
+	}
+
 	return env
 }
@@ -0,0 +1,77 @@
+package pubsub
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ServiceAccountProjectRoles are the roles that the service account of a topic is granted
+// on the project, in addition to publishing to the topic and pulling from its
+// subscription. The KEDA scaler reads the backlog of the subscription from Cloud
+// Monitoring, which requires the monitoring.viewer role.
+var ServiceAccountProjectRoles = []string{"roles/monitoring.viewer"}
+
+// Conf is the Pub/Sub topic and subscription config required for the provisioner
+type Conf struct {
+	GCPProjectID     string
+	TopicName        string
+	AckDeadline      string
+	MessageRetention string
+	ProjectRoles     []string
+}
+
+func NewConf(lastApplied *types.QueueInfraLastApplied) *Conf {
+	return &Conf{
+		GCPProjectID:     lastApplied.GCPProjectID,
+		TopicName:        lastApplied.QueueName,
+		AckDeadline:      formatSeconds(lastApplied.VisibilityTimeout),
+		MessageRetention: formatSeconds(lastApplied.MessageRetention),
+		ProjectRoles:     ServiceAccountProjectRoles,
+	}
+}
+
+// AttachPubSubEnv adds the relevant Pub/Sub env for the provisioner
+func (conf *Conf) AttachPubSubEnv(env []v1.EnvVar) []v1.EnvVar {
+	env = append(env, v1.EnvVar{
+		Name:  "GCP_PROJECT_ID",
+		Value: conf.GCPProjectID,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "TOPIC_NAME",
+		Value: conf.TopicName,
+	})
+
+	env = append(env, v1.EnvVar{
+		Name:  "SERVICE_ACCOUNT_PROJECT_ROLES",
+		Value: strings.Join(conf.ProjectRoles, ","),
+	})
+
+	// unset durations use the defaults of the module
+	if conf.AckDeadline != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "SUBSCRIPTION_ACK_DEADLINE",
+			Value: conf.AckDeadline,
+		})
+	}
+
+	if conf.MessageRetention != "" {
+		env = append(env, v1.EnvVar{
+			Name:  "SUBSCRIPTION_MESSAGE_RETENTION",
+			Value: conf.MessageRetention,
+		})
+	}
+
+	return env
+}
+
+func formatSeconds(seconds uint) string {
+	if seconds == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(seconds), 10)
+}
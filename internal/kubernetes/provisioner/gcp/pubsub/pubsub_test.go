@@ -0,0 +1,46 @@
+package pubsub_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/pubsub"
+	v1 "k8s.io/api/core/v1"
+)
+
+func getEnvValue(env []v1.EnvVar, name string) (string, bool) {
+	for _, e := range env {
+		if e.Name == name {
+			return e.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func TestAttachPubSubEnv(t *testing.T) {
+	env := pubsub.NewConf(&types.QueueInfraLastApplied{
+		CreateQueueInfraRequest: &types.CreateQueueInfraRequest{
+			QueueName:         "jobs",
+			VisibilityTimeout: 60,
+		},
+		GCPProjectID: "project-1234",
+	}).AttachPubSubEnv([]v1.EnvVar{})
+
+	if val, _ := getEnvValue(env, "TOPIC_NAME"); val != "jobs" {
+		t.Errorf("expected topic jobs, got %q", val)
+	}
+
+	if val, _ := getEnvValue(env, "SUBSCRIPTION_ACK_DEADLINE"); val != "60" {
+		t.Errorf("expected an ack deadline of 60 seconds, got %q", val)
+	}
+
+	if _, ok := getEnvValue(env, "SUBSCRIPTION_MESSAGE_RETENTION"); ok {
+		t.Errorf("expected an unset message retention to use the default of the module")
+	}
+
+	// the scaler of the topic reads the backlog of the subscription from Cloud Monitoring
+	if val, _ := getEnvValue(env, "SERVICE_ACCOUNT_PROJECT_ROLES"); val != "roles/monitoring.viewer" {
+		t.Errorf("expected the service account to be granted roles/monitoring.viewer, got %q", val)
+	}
+}
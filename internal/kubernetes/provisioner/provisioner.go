@@ -2,15 +2,22 @@ package provisioner
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/aws/ecr"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/aws/eks"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/aws/rds"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/aws/rdsnetwork"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/aws/s3"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/aws/sqs"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/do/docr"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/do/doks"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/cloudsqlnetwork"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/gcr"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/gcs"
 	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/gke"
+	"github.com/porter-dev/porter/internal/kubernetes/provisioner/gcp/pubsub"
 	"github.com/porter-dev/porter/internal/models"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
@@ -22,6 +29,14 @@ type ProvisionerOperation string
 const (
 	Apply   ProvisionerOperation = "apply"
 	Destroy ProvisionerOperation = "destroy"
+	Plan    ProvisionerOperation = "plan"
+)
+
+const (
+	// WorkspaceIDLabel and LeaseGenerationLabel are set on provisioner jobs that hold the
+	// operation lease of an infra, so that the lease is renewed while the job runs
+	WorkspaceIDLabel     = "porter.run/workspace-id"
+	LeaseGenerationLabel = "porter.run/lease-generation"
 )
 
 type ProvisionCredentialExchange struct {
@@ -43,6 +58,11 @@ type ProvisionOpts struct {
 	OperationKind       ProvisionerOperation
 	ProvisionerTest     bool
 
+	// LeaseGeneration is the generation of the operation lease on the infra that was
+	// claimed for the operation, or 0 if the operation does not hold a lease. The
+	// provisioner returns it with the result of the operation.
+	LeaseGeneration int64
+
 	// resource-specific opts
 	ECR  *ecr.Conf
 	EKS  *eks.Conf
@@ -53,6 +73,18 @@ type ProvisionOpts struct {
 
 	// DB instance specific opts
 	RDS *rds.Conf
+
+	// bucket specific opts
+	S3  *s3.Conf
+	GCS *gcs.Conf
+
+	// queue specific opts
+	SQS    *sqs.Conf
+	PubSub *pubsub.Conf
+
+	// private network specific opts
+	RDSNetwork      *rdsnetwork.Conf
+	CloudSQLNetwork *cloudsqlnetwork.Conf
 }
 
 func GetProvisionerJobTemplate(opts *ProvisionOpts) (*batchv1.Job, error) {
@@ -72,6 +104,11 @@ func GetProvisionerJobTemplate(opts *ProvisionOpts) (*batchv1.Job, error) {
 		})
 	}
 
+	if opts.LeaseGeneration != 0 {
+		labels[WorkspaceIDLabel] = opts.Infra.GetUniqueName()
+		labels[LeaseGenerationLabel] = strconv.FormatInt(opts.LeaseGeneration, 10)
+	}
+
 	env := GetTFEnv(opts)
 
 	// add resource-specific env
@@ -90,6 +127,18 @@ func GetProvisionerJobTemplate(opts *ProvisionOpts) (*batchv1.Job, error) {
 		env = opts.DOKS.AttachDOKSEnv(env)
 	case types.InfraRDS:
 		env = opts.RDS.AttachRDSEnv(env)
+	case types.InfraS3:
+		env = opts.S3.AttachS3Env(env)
+	case types.InfraGCS:
+		env = opts.GCS.AttachGCSEnv(env)
+	case types.InfraSQS:
+		env = opts.SQS.AttachSQSEnv(env)
+	case types.InfraPubSub:
+		env = opts.PubSub.AttachPubSubEnv(env)
+	case types.InfraRDSNetwork:
+		env = opts.RDSNetwork.AttachRDSNetworkEnv(env)
+	case types.InfraCloudSQLNetwork:
+		env = opts.CloudSQLNetwork.AttachCloudSQLNetworkEnv(env)
 	}
 
 	job := &batchv1.Job{
@@ -186,5 +235,12 @@ func GetTFEnv(opts *ProvisionOpts) []v1.EnvVar {
 		Value: opts.CredentialExchange.VaultToken,
 	})
 
+	if opts.LeaseGeneration != 0 {
+		env = append(env, v1.EnvVar{
+			Name:  "LEASE_GENERATION",
+			Value: strconv.FormatInt(opts.LeaseGeneration, 10),
+		})
+	}
+
 	return env
 }
@@ -0,0 +1,48 @@
+package provisioner
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetProvisionerJobTemplateLeaseGeneration(t *testing.T) {
+	infra := &models.Infra{Kind: types.InfraKind("test"), ProjectID: 1, Suffix: "abc"}
+	infra.ID = 2
+
+	opts := &ProvisionOpts{
+		Infra:              infra,
+		OperationKind:      Apply,
+		CredentialExchange: &ProvisionCredentialExchange{},
+		LeaseGeneration:    7,
+	}
+
+	job, err := GetProvisionerJobTemplate(opts)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "test-1-2-abc", job.Labels[WorkspaceIDLabel])
+	assert.Equal(t, "7", job.Labels[LeaseGenerationLabel])
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{Name: "LEASE_GENERATION", Value: "7"})
+
+	// operations that do not hold a lease, such as plans, are not labeled
+	opts.OperationKind = Plan
+	opts.LeaseGeneration = 0
+
+	job, err = GetProvisionerJobTemplate(opts)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotContains(t, job.Labels, LeaseGenerationLabel)
+
+	for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+		assert.NotEqual(t, "LEASE_GENERATION", env.Name)
+	}
+}
@@ -0,0 +1,315 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fatalWaitingReasons are container waiting reasons that a rollout does not recover
+// from without a new deploy
+var fatalWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+}
+
+// deploymentRevisionAnnotation is the revision of a deployment, which is copied to the
+// replicaset of the revision
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// GetRolloutStatus returns the rollout status of the deployments and statefulsets among
+// the controllers, along with the warning events for the pods of their current revision
+// that occurred after the given time. Controllers of other kinds are ignored.
+func (a *Agent) GetRolloutStatus(controllers []grapher.Object, since time.Time) (*types.RolloutStatus, error) {
+	res := &types.RolloutStatus{
+		Phase:       types.RolloutSucceeded,
+		Controllers: make([]*types.ControllerRolloutStatus, 0),
+		Events:      make([]*types.RolloutEvent, 0),
+	}
+
+	podPrefixes := make(map[string][]string)
+
+	for _, controller := range controllers {
+		var status *types.ControllerRolloutStatus
+		var selector *metav1.LabelSelector
+
+		// only the pods of the current revision of the controller are checked, since the
+		// pods of earlier revisions are being replaced
+		podLabels := make(map[string]string)
+		podPrefix := controller.Name + "-"
+
+		switch strings.ToLower(controller.Kind) {
+		case "deployment":
+			depl, err := a.GetDeployment(controller)
+
+			if err != nil {
+				return nil, err
+			}
+
+			status = getDeploymentRolloutStatus(depl)
+			selector = depl.Spec.Selector
+
+			hash, err := a.getNewReplicaSetHash(depl)
+
+			if err != nil {
+				return nil, err
+			}
+
+			// the new replicaset may not have been created yet, in which case there are
+			// no pods of the current revision
+			if hash == "" {
+				selector = nil
+			} else {
+				podLabels[appsv1.DefaultDeploymentUniqueLabelKey] = hash
+				podPrefix = fmt.Sprintf("%s-%s-", controller.Name, hash)
+			}
+		case "statefulset":
+			statefulSet, err := a.GetStatefulSet(controller)
+
+			if err != nil {
+				return nil, err
+			}
+
+			status = getStatefulSetRolloutStatus(statefulSet)
+			selector = statefulSet.Spec.Selector
+
+			if statefulSet.Status.UpdateRevision != "" {
+				podLabels[appsv1.StatefulSetRevisionLabel] = statefulSet.Status.UpdateRevision
+			}
+		default:
+			continue
+		}
+
+		if status.FailureReason == "" && !status.Complete {
+			reason, err := a.getPodFailureReason(controller.Namespace, selector, podLabels)
+
+			if err != nil {
+				return nil, err
+			}
+
+			status.FailureReason = reason
+		}
+
+		if status.FailureReason != "" {
+			res.Phase = types.RolloutFailed
+		} else if !status.Complete && res.Phase != types.RolloutFailed {
+			res.Phase = types.RolloutProgressing
+		}
+
+		res.Controllers = append(res.Controllers, status)
+		podPrefixes[controller.Namespace] = append(podPrefixes[controller.Namespace], podPrefix)
+	}
+
+	for namespace, prefixes := range podPrefixes {
+		events, err := a.getWarningEvents(namespace, prefixes, since)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.Events = append(res.Events, events...)
+	}
+
+	return res, nil
+}
+
+func getDeploymentRolloutStatus(depl *appsv1.Deployment) *types.ControllerRolloutStatus {
+	desired := int32(1)
+
+	if depl.Spec.Replicas != nil {
+		desired = *depl.Spec.Replicas
+	}
+
+	res := &types.ControllerRolloutStatus{
+		Kind:            "Deployment",
+		Name:            depl.Name,
+		DesiredReplicas: desired,
+		UpdatedReplicas: depl.Status.UpdatedReplicas,
+		ReadyReplicas:   depl.Status.ReadyReplicas,
+	}
+
+	for _, cond := range depl.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			res.FailureReason = fmt.Sprintf("deployment exceeded its progress deadline: %s", cond.Message)
+			return res
+		}
+	}
+
+	// mirrors the checks of "kubectl rollout status"
+	res.Complete = depl.Generation <= depl.Status.ObservedGeneration &&
+		depl.Status.UpdatedReplicas >= desired &&
+		depl.Status.Replicas <= depl.Status.UpdatedReplicas &&
+		depl.Status.AvailableReplicas >= depl.Status.UpdatedReplicas
+
+	return res
+}
+
+func getStatefulSetRolloutStatus(statefulSet *appsv1.StatefulSet) *types.ControllerRolloutStatus {
+	desired := int32(1)
+
+	if statefulSet.Spec.Replicas != nil {
+		desired = *statefulSet.Spec.Replicas
+	}
+
+	res := &types.ControllerRolloutStatus{
+		Kind:            "StatefulSet",
+		Name:            statefulSet.Name,
+		DesiredReplicas: desired,
+		UpdatedReplicas: statefulSet.Status.UpdatedReplicas,
+		ReadyReplicas:   statefulSet.Status.ReadyReplicas,
+	}
+
+	res.Complete = statefulSet.Generation <= statefulSet.Status.ObservedGeneration &&
+		statefulSet.Status.ReadyReplicas >= desired &&
+		statefulSet.Status.UpdatedReplicas >= desired
+
+	return res
+}
+
+// getNewReplicaSetHash returns the pod template hash of the replicaset of the current
+// revision of the deployment, or an empty string if it does not exist yet
+func (a *Agent) getNewReplicaSetHash(depl *appsv1.Deployment) (string, error) {
+	if depl.Spec.Selector == nil {
+		return "", nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(depl.Spec.Selector)
+
+	if err != nil {
+		return "", err
+	}
+
+	replicaSets, err := a.Clientset.AppsV1().ReplicaSets(depl.Namespace).List(
+		context.TODO(),
+		metav1.ListOptions{
+			LabelSelector: labelSelector.String(),
+		},
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	revision := depl.Annotations[deploymentRevisionAnnotation]
+
+	for _, rs := range replicaSets.Items {
+		if metav1.IsControlledBy(&rs, depl) && revision != "" && rs.Annotations[deploymentRevisionAnnotation] == revision {
+			return rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey], nil
+		}
+	}
+
+	return "", nil
+}
+
+// getPodFailureReason returns a reason if a container of a pod matching the selector and
+// the given labels is waiting for a reason that it will not recover from
+func (a *Agent) getPodFailureReason(namespace string, selector *metav1.LabelSelector, podLabels map[string]string) (string, error) {
+	if selector == nil {
+		return "", nil
+	}
+
+	selector = selector.DeepCopy()
+
+	if selector.MatchLabels == nil {
+		selector.MatchLabels = make(map[string]string)
+	}
+
+	for key, val := range podLabels {
+		selector.MatchLabels[key] = val
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+
+	if err != nil {
+		return "", err
+	}
+
+	pods, err := a.GetPodsByLabel(labelSelector.String(), namespace)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		statuses := make([]v1.ContainerStatus, 0)
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+		for _, status := range statuses {
+			if status.State.Waiting != nil && fatalWaitingReasons[status.State.Waiting.Reason] {
+				return fmt.Sprintf(
+					"container %s of pod %s is in state %s: %s",
+					status.Name,
+					pod.Name,
+					status.State.Waiting.Reason,
+					status.State.Waiting.Message,
+				), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// getWarningEvents returns the warning events for pods whose name starts with one of the
+// prefixes, which occurred after the given time. This includes probe failures and
+// scheduling failures.
+func (a *Agent) getWarningEvents(namespace string, podPrefixes []string, since time.Time) ([]*types.RolloutEvent, error) {
+	events, err := a.Clientset.CoreV1().Events(namespace).List(
+		context.TODO(),
+		metav1.ListOptions{
+			FieldSelector: "type=Warning,involvedObject.kind=Pod",
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*types.RolloutEvent, 0)
+
+	for _, event := range events.Items {
+		timestamp := getEventTime(&event)
+
+		if !timestamp.After(since) {
+			continue
+		}
+
+		for _, prefix := range podPrefixes {
+			if strings.HasPrefix(event.InvolvedObject.Name, prefix) {
+				res = append(res, &types.RolloutEvent{
+					Timestamp: timestamp,
+					Object:    event.InvolvedObject.Name,
+					Reason:    event.Reason,
+					Message:   event.Message,
+				})
+
+				break
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func getEventTime(event *v1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+
+	return event.CreationTimestamp.Time
+}
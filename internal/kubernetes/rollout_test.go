@@ -0,0 +1,122 @@
+package kubernetes_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetRolloutStatusIgnoresOldPods(t *testing.T) {
+	tests := []struct {
+		name     string
+		newState string
+		expected types.RolloutPhase
+	}{
+		{
+			name:     "new pods starting",
+			newState: "ContainerCreating",
+			expected: types.RolloutProgressing,
+		},
+		{
+			name:     "new pods crashing",
+			newState: "CrashLoopBackOff",
+			expected: types.RolloutFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			agent := kubernetes.GetAgentTesting(getRolloutTestObjects(test.newState)...)
+
+			status, err := agent.GetRolloutStatus(
+				[]grapher.Object{{Kind: "Deployment", Name: "web", Namespace: "default"}},
+				time.Now().Add(-time.Minute),
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if status.Phase != test.expected {
+				t.Errorf("expected phase %s, got %s (%s)", test.expected, status.Phase, status.Controllers[0].FailureReason)
+			}
+		})
+	}
+}
+
+// getRolloutTestObjects returns a deployment in the middle of a rollout, whose pod of
+// the previous revision is crashing
+func getRolloutTestObjects(newState string) []runtime.Object {
+	replicas := int32(1)
+	selector := map[string]string{"app": "web"}
+
+	depl := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "default",
+			UID:         "web-uid",
+			Generation:  2,
+			Annotations: map[string]string{"deployment.kubernetes.io/revision": "2"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           2,
+			UpdatedReplicas:    1,
+		},
+	}
+
+	isController := true
+
+	getReplicaSet := func(revision, hash string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "web-" + hash,
+				Namespace:   "default",
+				Labels:      map[string]string{"app": "web", "pod-template-hash": hash},
+				Annotations: map[string]string{"deployment.kubernetes.io/revision": revision},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "web",
+					UID:        "web-uid",
+					Controller: &isController,
+				}},
+			},
+		}
+	}
+
+	getPod := func(hash, state string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-" + hash + "-abcde",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "web", "pod-template-hash": hash},
+			},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:  "web",
+					State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: state}},
+				}},
+			},
+		}
+	}
+
+	return []runtime.Object{
+		depl,
+		getReplicaSet("1", "old"),
+		getReplicaSet("2", "new"),
+		getPod("old", "CrashLoopBackOff"),
+		getPod("new", newState),
+	}
+}
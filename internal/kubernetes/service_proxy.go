@@ -0,0 +1,438 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// IngressNginxNamespace is the namespace of the ingress-nginx controller, which reads
+	// the tcp and udp services that it proxies from configmaps
+	IngressNginxNamespace = "ingress-nginx"
+
+	tcpServicesConfigMap = "tcp-services"
+	udpServicesConfigMap = "udp-services"
+
+	// ingressNginxControllerSelector selects the service and deployment of the controller,
+	// using the labels set by the upstream ingress-nginx chart
+	ingressNginxControllerSelector = "app.kubernetes.io/name=ingress-nginx,app.kubernetes.io/component=controller"
+)
+
+// ingressNginxController is the service and deployment of the ingress-nginx controller. The
+// ports of the service that proxy to releases are named by getServiceProxyPortName.
+type ingressNginxController struct {
+	service    *v1.Service
+	deployment *appsv1.Deployment
+}
+
+// CheckServiceProxyPort returns an error if a port of the ingress-nginx controller cannot
+// proxy to the services of a release, because the port is already used by the controller
+// or proxies to another service
+func (a *Agent) CheckServiceProxyPort(protocol string, proxyPort int, namespace string, services []string) error {
+	controller, err := a.getIngressNginxController()
+
+	if err != nil {
+		return err
+	}
+
+	return a.checkServiceProxyPort(controller, protocol, proxyPort, namespace, services)
+}
+
+// SetServiceProxy proxies a port of the ingress-nginx controller to a port of a service.
+// The port is added to the tcp or udp services configmap of the controller and to the
+// service of the controller, and the controller is configured to read the configmap if
+// it does not already. Other ports that proxy to the service are removed.
+func (a *Agent) SetServiceProxy(protocol string, proxyPort int, namespace, service string, servicePort int) error {
+	protocol = strings.ToLower(protocol)
+
+	controller, err := a.getIngressNginxController()
+
+	if err != nil {
+		return err
+	}
+
+	if err := a.checkServiceProxyPort(controller, protocol, proxyPort, namespace, []string{service}); err != nil {
+		return err
+	}
+
+	if err := a.removeServiceProxies(controller, namespace, service, protocol, proxyPort); err != nil {
+		return err
+	}
+
+	cmNamespace, cmName, configured, err := getServiceProxyConfigMap(controller, protocol)
+
+	if err != nil {
+		return err
+	}
+
+	cm, err := a.Clientset.CoreV1().ConfigMaps(cmNamespace).Get(
+		context.TODO(),
+		cmName,
+		metav1.GetOptions{},
+	)
+
+	exists := true
+
+	if err != nil && errors.IsNotFound(err) {
+		exists = false
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cmName,
+				Namespace: cmNamespace,
+			},
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+
+	cm.Data[strconv.Itoa(proxyPort)] = fmt.Sprintf("%s/%s:%d", namespace, service, servicePort)
+
+	if !exists {
+		_, err = a.Clientset.CoreV1().ConfigMaps(cmNamespace).Create(
+			context.TODO(),
+			cm,
+			metav1.CreateOptions{},
+		)
+	} else {
+		_, err = a.Clientset.CoreV1().ConfigMaps(cmNamespace).Update(
+			context.TODO(),
+			cm,
+			metav1.UpdateOptions{},
+		)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	portName := getServiceProxyPortName(protocol, proxyPort)
+	hasPort := false
+
+	for _, port := range controller.service.Spec.Ports {
+		if port.Name == portName {
+			hasPort = true
+			break
+		}
+	}
+
+	if !hasPort {
+		controller.service.Spec.Ports = append(controller.service.Spec.Ports, v1.ServicePort{
+			Name:       portName,
+			Port:       int32(proxyPort),
+			TargetPort: intstr.FromInt(proxyPort),
+			Protocol:   v1.Protocol(strings.ToUpper(protocol)),
+		})
+
+		_, err = a.Clientset.CoreV1().Services(controller.service.Namespace).Update(
+			context.TODO(),
+			controller.service,
+			metav1.UpdateOptions{},
+		)
+
+		if err != nil {
+			return fmt.Errorf("could not add port %d to the service of the ingress controller: %w", proxyPort, err)
+		}
+	}
+
+	// the controller only reads the configmap if it is started with its flag, which
+	// restarts the pods of the controller once
+	if !configured {
+		container := getIngressNginxControllerContainer(controller.deployment)
+
+		container.Args = append(
+			container.Args,
+			fmt.Sprintf("--%s-services-configmap=%s/%s", protocol, cmNamespace, cmName),
+		)
+
+		_, err = a.Clientset.AppsV1().Deployments(controller.deployment.Namespace).Update(
+			context.TODO(),
+			controller.deployment,
+			metav1.UpdateOptions{},
+		)
+
+		if err != nil {
+			return fmt.Errorf("could not configure the ingress controller to proxy %s services: %w", protocol, err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveServiceProxies removes the ports of the ingress-nginx controller that proxy to a
+// service, from the tcp and udp services configmaps and the service of the controller
+func (a *Agent) RemoveServiceProxies(namespace, service string) error {
+	controller, err := a.getIngressNginxController()
+
+	if err != nil {
+		// the controller was uninstalled along with its proxies
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	return a.removeServiceProxies(controller, namespace, service, "", 0)
+}
+
+func (a *Agent) getIngressNginxController() (*ingressNginxController, error) {
+	notFound := func(kind string) error {
+		return errors.NewNotFound(v1.Resource(kind), "ingress-nginx-controller")
+	}
+
+	services, err := a.Clientset.CoreV1().Services(IngressNginxNamespace).List(
+		context.TODO(),
+		metav1.ListOptions{LabelSelector: ingressNginxControllerSelector},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var service *v1.Service
+
+	// the chart may also create an internal service for the controller, so the service
+	// of the load balancer is preferred
+	for i := range services.Items {
+		if service == nil || services.Items[i].Spec.Type == v1.ServiceTypeLoadBalancer {
+			service = &services.Items[i]
+		}
+
+		if service.Spec.Type == v1.ServiceTypeLoadBalancer {
+			break
+		}
+	}
+
+	if service == nil {
+		return nil, fmt.Errorf("the ingress-nginx controller is not installed in namespace %s: %w", IngressNginxNamespace, notFound("services"))
+	}
+
+	deployments, err := a.Clientset.AppsV1().Deployments(IngressNginxNamespace).List(
+		context.TODO(),
+		metav1.ListOptions{LabelSelector: ingressNginxControllerSelector},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deployments.Items) == 0 {
+		return nil, fmt.Errorf("the ingress-nginx controller is not installed in namespace %s: %w", IngressNginxNamespace, notFound("deployments"))
+	}
+
+	return &ingressNginxController{
+		service:    service,
+		deployment: &deployments.Items[0],
+	}, nil
+}
+
+func (a *Agent) checkServiceProxyPort(
+	controller *ingressNginxController,
+	protocol string,
+	proxyPort int,
+	namespace string,
+	services []string,
+) error {
+	protocol = strings.ToLower(protocol)
+	portName := getServiceProxyPortName(protocol, proxyPort)
+
+	for _, port := range controller.service.Spec.Ports {
+		if int(port.Port) == proxyPort && strings.EqualFold(string(port.Protocol), protocol) && port.Name != portName {
+			return fmt.Errorf("port %d of the ingress controller is already in use", proxyPort)
+		}
+	}
+
+	cmNamespace, cmName, _, err := getServiceProxyConfigMap(controller, protocol)
+
+	if err != nil {
+		return err
+	}
+
+	cm, err := a.Clientset.CoreV1().ConfigMaps(cmNamespace).Get(
+		context.TODO(),
+		cmName,
+		metav1.GetOptions{},
+	)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	curr, ok := cm.Data[strconv.Itoa(proxyPort)]
+
+	if !ok {
+		return nil
+	}
+
+	for _, service := range services {
+		if strings.HasPrefix(curr, fmt.Sprintf("%s/%s:", namespace, service)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("port %d of the ingress controller already proxies to %s", proxyPort, curr)
+}
+
+// removeServiceProxies removes the ports of the controller that proxy to a service, except
+// for the port that is kept
+func (a *Agent) removeServiceProxies(
+	controller *ingressNginxController,
+	namespace, service string,
+	keepProtocol string,
+	keepPort int,
+) error {
+	prefix := fmt.Sprintf("%s/%s:", namespace, service)
+	removedPorts := make(map[string]bool)
+
+	for _, protocol := range []string{"tcp", "udp"} {
+		cmNamespace, cmName, _, err := getServiceProxyConfigMap(controller, protocol)
+
+		if err != nil {
+			return err
+		}
+
+		cm, err := a.Clientset.CoreV1().ConfigMaps(cmNamespace).Get(
+			context.TODO(),
+			cmName,
+			metav1.GetOptions{},
+		)
+
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+
+			return err
+		}
+
+		removed := false
+
+		for key, target := range cm.Data {
+			if !strings.HasPrefix(target, prefix) || (protocol == keepProtocol && key == strconv.Itoa(keepPort)) {
+				continue
+			}
+
+			delete(cm.Data, key)
+			removed = true
+
+			if port, err := strconv.Atoi(key); err == nil {
+				removedPorts[getServiceProxyPortName(protocol, port)] = true
+			}
+		}
+
+		if !removed {
+			continue
+		}
+
+		_, err = a.Clientset.CoreV1().ConfigMaps(cmNamespace).Update(
+			context.TODO(),
+			cm,
+			metav1.UpdateOptions{},
+		)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(removedPorts) == 0 {
+		return nil
+	}
+
+	ports := make([]v1.ServicePort, 0)
+
+	for _, port := range controller.service.Spec.Ports {
+		if !removedPorts[port.Name] {
+			ports = append(ports, port)
+		}
+	}
+
+	if len(ports) == len(controller.service.Spec.Ports) {
+		return nil
+	}
+
+	controller.service.Spec.Ports = ports
+
+	_, err := a.Clientset.CoreV1().Services(controller.service.Namespace).Update(
+		context.TODO(),
+		controller.service,
+		metav1.UpdateOptions{},
+	)
+
+	return err
+}
+
+// getServiceProxyConfigMap returns the configmap that the controller reads the proxied
+// services of a protocol from, and whether the controller is started with its flag. The
+// upstream chart names the configmap after the release of the chart, so the configmap of
+// the flag is used if it is set.
+func getServiceProxyConfigMap(controller *ingressNginxController, protocol string) (string, string, bool, error) {
+	var cmName string
+
+	switch strings.ToLower(protocol) {
+	case "tcp":
+		cmName = tcpServicesConfigMap
+	case "udp":
+		cmName = udpServicesConfigMap
+	default:
+		return "", "", false, fmt.Errorf("only tcp and udp services can be proxied, got %s", protocol)
+	}
+
+	flag := fmt.Sprintf("--%s-services-configmap=", strings.ToLower(protocol))
+
+	for _, arg := range getIngressNginxControllerContainer(controller.deployment).Args {
+		if !strings.HasPrefix(arg, flag) {
+			continue
+		}
+
+		ref := strings.ReplaceAll(strings.TrimPrefix(arg, flag), "$(POD_NAMESPACE)", controller.deployment.Namespace)
+
+		if parts := strings.SplitN(ref, "/", 2); len(parts) == 2 {
+			return parts[0], parts[1], true, nil
+		}
+
+		return controller.deployment.Namespace, ref, true, nil
+	}
+
+	return IngressNginxNamespace, cmName, false, nil
+}
+
+// getIngressNginxControllerContainer returns the controller container of the deployment of
+// the controller, which the upstream chart names controller
+func getIngressNginxControllerContainer(deployment *appsv1.Deployment) *v1.Container {
+	containers := deployment.Spec.Template.Spec.Containers
+
+	for i := range containers {
+		if containers[i].Name == "controller" {
+			return &containers[i]
+		}
+	}
+
+	if len(containers) == 0 {
+		deployment.Spec.Template.Spec.Containers = append(containers, v1.Container{Name: "controller"})
+	}
+
+	return &deployment.Spec.Template.Spec.Containers[0]
+}
+
+// getServiceProxyPortName returns the name of the port of the service of the controller
+// that proxies a port, which is at most 15 characters long
+func getServiceProxyPortName(protocol string, port int) string {
+	return fmt.Sprintf("proxy-%s-%d", strings.ToLower(protocol), port)
+}
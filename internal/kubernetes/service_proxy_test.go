@@ -0,0 +1,204 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var ingressNginxControllerLabels = map[string]string{
+	"app.kubernetes.io/name":      "ingress-nginx",
+	"app.kubernetes.io/component": "controller",
+}
+
+func getServiceProxyTestAgent(args ...string) *kubernetes.Agent {
+	return kubernetes.GetAgentTesting(
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ingress-nginx-controller",
+				Namespace: kubernetes.IngressNginxNamespace,
+				Labels:    ingressNginxControllerLabels,
+			},
+			Spec: v1.ServiceSpec{
+				Type: v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{
+					{Name: "http", Port: 80, Protocol: v1.ProtocolTCP},
+					{Name: "https", Port: 443, Protocol: v1.ProtocolTCP},
+				},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ingress-nginx-controller",
+				Namespace: kubernetes.IngressNginxNamespace,
+				Labels:    ingressNginxControllerLabels,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: v1.PodTemplateSpec{
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{
+							{Name: "controller", Args: append([]string{"/nginx-ingress-controller"}, args...)},
+						},
+					},
+				},
+			},
+		},
+	)
+}
+
+func getServiceProxyTestController(t *testing.T, agent *kubernetes.Agent) (*v1.Service, *appsv1.Deployment) {
+	svc, err := agent.Clientset.CoreV1().Services(kubernetes.IngressNginxNamespace).Get(
+		context.TODO(),
+		"ingress-nginx-controller",
+		metav1.GetOptions{},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deployment, err := agent.Clientset.AppsV1().Deployments(kubernetes.IngressNginxNamespace).Get(
+		context.TODO(),
+		"ingress-nginx-controller",
+		metav1.GetOptions{},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return svc, deployment
+}
+
+func getServiceProxyTestConfigMap(t *testing.T, agent *kubernetes.Agent, name string) map[string]string {
+	cm, err := agent.Clientset.CoreV1().ConfigMaps(kubernetes.IngressNginxNamespace).Get(
+		context.TODO(),
+		name,
+		metav1.GetOptions{},
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cm.Data
+}
+
+func hasServicePort(svc *v1.Service, port int32) bool {
+	for _, p := range svc.Spec.Ports {
+		if p.Port == port {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestSetServiceProxy(t *testing.T) {
+	agent := getServiceProxyTestAgent()
+
+	if err := agent.SetServiceProxy("tcp", 5432, "default", "db", 5432); err != nil {
+		t.Fatal(err)
+	}
+
+	if target := getServiceProxyTestConfigMap(t, agent, "tcp-services")["5432"]; target != "default/db:5432" {
+		t.Errorf("expected port 5432 to proxy to default/db:5432, got %q", target)
+	}
+
+	svc, deployment := getServiceProxyTestController(t, agent)
+
+	if !hasServicePort(svc, 5432) {
+		t.Errorf("expected port 5432 to be added to the service of the controller")
+	}
+
+	args := deployment.Spec.Template.Spec.Containers[0].Args
+
+	if len(args) != 2 || args[1] != "--tcp-services-configmap=ingress-nginx/tcp-services" {
+		t.Errorf("expected the controller to read the tcp services configmap, got args %v", args)
+	}
+
+	// changing the proxy port removes the previous port
+	if err := agent.SetServiceProxy("tcp", 6543, "default", "db", 5432); err != nil {
+		t.Fatal(err)
+	}
+
+	data := getServiceProxyTestConfigMap(t, agent, "tcp-services")
+
+	if _, ok := data["5432"]; ok || data["6543"] != "default/db:5432" {
+		t.Errorf("expected only port 6543 to proxy to the service, got %v", data)
+	}
+
+	svc, deployment = getServiceProxyTestController(t, agent)
+
+	if hasServicePort(svc, 5432) || !hasServicePort(svc, 6543) {
+		t.Errorf("expected only port 6543 on the service of the controller, got %v", svc.Spec.Ports)
+	}
+
+	if args := deployment.Spec.Template.Spec.Containers[0].Args; len(args) != 2 {
+		t.Errorf("expected the flag of the controller to be added once, got args %v", args)
+	}
+
+	if err := agent.RemoveServiceProxies("default", "db"); err != nil {
+		t.Fatal(err)
+	}
+
+	if data := getServiceProxyTestConfigMap(t, agent, "tcp-services"); len(data) != 0 {
+		t.Errorf("expected the proxies of the service to be removed, got %v", data)
+	}
+
+	if svc, _ := getServiceProxyTestController(t, agent); len(svc.Spec.Ports) != 2 {
+		t.Errorf("expected the proxy ports to be removed from the service of the controller, got %v", svc.Spec.Ports)
+	}
+}
+
+func TestSetServiceProxyConfiguredConfigMap(t *testing.T) {
+	agent := getServiceProxyTestAgent("--tcp-services-configmap=$(POD_NAMESPACE)/ingress-nginx-tcp")
+
+	if err := agent.SetServiceProxy("tcp", 5432, "default", "db", 5432); err != nil {
+		t.Fatal(err)
+	}
+
+	if target := getServiceProxyTestConfigMap(t, agent, "ingress-nginx-tcp")["5432"]; target != "default/db:5432" {
+		t.Errorf("expected the configmap of the flag of the controller to be used, got %q", target)
+	}
+
+	if _, deployment := getServiceProxyTestController(t, agent); len(deployment.Spec.Template.Spec.Containers[0].Args) != 2 {
+		t.Errorf("expected the flag of the controller not to be added again")
+	}
+}
+
+func TestSetServiceProxyPortInUse(t *testing.T) {
+	agent := getServiceProxyTestAgent()
+
+	if err := agent.SetServiceProxy("tcp", 443, "default", "db", 443); err == nil {
+		t.Errorf("expected an error for a port that the controller already uses")
+	}
+
+	if err := agent.SetServiceProxy("tcp", 5432, "default", "db", 5432); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := agent.SetServiceProxy("tcp", 5432, "other", "db", 5432); err == nil {
+		t.Errorf("expected an error for a port that proxies to another service")
+	}
+
+	if err := agent.CheckServiceProxyPort("tcp", 5432, "default", []string{"db"}); err != nil {
+		t.Errorf("expected the port to be available to the service that it proxies to, got %v", err)
+	}
+}
+
+func TestRemoveServiceProxiesWithoutController(t *testing.T) {
+	agent := kubernetes.GetAgentTesting()
+
+	if err := agent.RemoveServiceProxies("default", "db"); err != nil {
+		t.Errorf("expected no error without an ingress controller, got %v", err)
+	}
+
+	if err := agent.SetServiceProxy("tcp", 5432, "default", "db", 5432); err == nil {
+		t.Errorf("expected an error without an ingress controller")
+	}
+}
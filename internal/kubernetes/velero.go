@@ -0,0 +1,336 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VeleroNamespace is the namespace that Velero is installed into, which contains the
+// backups, schedules and restores of the cluster
+const VeleroNamespace = "velero"
+
+// VeleroScheduleNameLabel is set by Velero on the backups that a schedule creates
+const VeleroScheduleNameLabel = "velero.io/schedule-name"
+
+// VeleroCredentialsSecretName is the secret in the Velero namespace that stores the
+// credentials file of the bucket of Velero under the cloud key, which the Velero chart
+// reads as an existing secret
+const VeleroCredentialsSecretName = "velero-credentials"
+
+// VeleroBackupResource, VeleroScheduleResource and VeleroRestoreResource are the
+// resources of the Velero CRDs, which are installed along with Velero
+var (
+	VeleroBackupResource = schema.GroupVersionResource{
+		Group:    "velero.io",
+		Version:  "v1",
+		Resource: "backups",
+	}
+
+	VeleroScheduleResource = VeleroBackupResource.GroupVersion().WithResource("schedules")
+	VeleroRestoreResource  = VeleroBackupResource.GroupVersion().WithResource("restores")
+)
+
+// VeleroBackupScope is the set of resources that a backup, schedule or restore applies
+// to: the resources of a namespace, or the resources of the namespace that match a label
+// selector
+type VeleroBackupScope struct {
+	Namespace string
+	Selector  map[string]string
+
+	// AltSelectors are label selectors that resources can match instead of the selector,
+	// such as the instance label of resources that were deployed before they carried
+	// the labels of the selector
+	AltSelectors []map[string]string
+}
+
+// VeleroExistingResourcePolicy is what a restore does with resources that already exist
+// in the cluster: Velero skips them with none, and patches them with update
+type VeleroExistingResourcePolicy string
+
+const (
+	VeleroExistingResourcePolicyNone   VeleroExistingResourcePolicy = "none"
+	VeleroExistingResourcePolicyUpdate VeleroExistingResourcePolicy = "update"
+)
+
+// GetVeleroBackupScope reads the scope of the spec of a backup or schedule template
+func GetVeleroBackupScope(spec map[string]interface{}) *VeleroBackupScope {
+	res := &VeleroBackupScope{
+		Selector: make(map[string]string),
+	}
+
+	namespaces, _, _ := unstructured.NestedStringSlice(spec, "includedNamespaces")
+
+	if len(namespaces) > 0 {
+		res.Namespace = namespaces[0]
+	}
+
+	matchLabels, _, _ := unstructured.NestedStringMap(spec, "labelSelector", "matchLabels")
+
+	for key, val := range matchLabels {
+		res.Selector[key] = val
+	}
+
+	// Velero does not allow both a label selector and alternative label selectors, so
+	// the selector is the first of the alternative label selectors
+	orSelectors, _, _ := unstructured.NestedSlice(spec, "orLabelSelectors")
+
+	for i, orSelector := range orSelectors {
+		orSelectorMap, ok := orSelector.(map[string]interface{})
+
+		if !ok {
+			continue
+		}
+
+		matchLabels, _, _ := unstructured.NestedStringMap(orSelectorMap, "matchLabels")
+
+		if i == 0 {
+			for key, val := range matchLabels {
+				res.Selector[key] = val
+			}
+
+			continue
+		}
+
+		res.AltSelectors = append(res.AltSelectors, matchLabels)
+	}
+
+	return res
+}
+
+func (s *VeleroBackupScope) toSpec() map[string]interface{} {
+	spec := map[string]interface{}{
+		"includedNamespaces": []interface{}{s.Namespace},
+	}
+
+	s.setSelectorSpec(spec)
+
+	return spec
+}
+
+// setSelectorSpec sets the label selectors of the scope in the spec of a backup, schedule
+// template or restore
+func (s *VeleroBackupScope) setSelectorSpec(spec map[string]interface{}) {
+	if len(s.Selector) == 0 {
+		return
+	}
+
+	if len(s.AltSelectors) == 0 {
+		spec["labelSelector"] = toVeleroLabelSelector(s.Selector)
+		return
+	}
+
+	orSelectors := []interface{}{toVeleroLabelSelector(s.Selector)}
+
+	for _, selector := range s.AltSelectors {
+		orSelectors = append(orSelectors, toVeleroLabelSelector(selector))
+	}
+
+	spec["orLabelSelectors"] = orSelectors
+}
+
+func toVeleroLabelSelector(selector map[string]string) map[string]interface{} {
+	matchLabels := make(map[string]interface{})
+
+	for key, val := range selector {
+		matchLabels[key] = val
+	}
+
+	return map[string]interface{}{
+		"matchLabels": matchLabels,
+	}
+}
+
+// ApplyVeleroCredentials creates or updates the secret with the credentials file of the
+// bucket of Velero, so that the credentials are not stored in the values of the release
+func (a *Agent) ApplyVeleroCredentials(credentials string) error {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      VeleroCredentialsSecretName,
+			Namespace: VeleroNamespace,
+		},
+		Data: map[string][]byte{
+			"cloud": []byte(credentials),
+		},
+	}
+
+	_, err := a.Clientset.CoreV1().Secrets(VeleroNamespace).Create(
+		context.TODO(),
+		secret,
+		metav1.CreateOptions{},
+	)
+
+	if err != nil && errors.IsAlreadyExists(err) {
+		_, err = a.Clientset.CoreV1().Secrets(VeleroNamespace).Update(
+			context.TODO(),
+			secret,
+			metav1.UpdateOptions{},
+		)
+	}
+
+	return err
+}
+
+// CreateVeleroBackup creates an on-demand backup of a scope. The name of the backup is
+// generated from the namespace of the scope, and the TTL defaults to the TTL of Velero.
+func CreateVeleroBackup(
+	client dynamic.Interface,
+	scope *VeleroBackupScope,
+	ttl string,
+) (*unstructured.Unstructured, error) {
+	spec := scope.toSpec()
+
+	if ttl != "" {
+		spec["ttl"] = ttl
+	}
+
+	backup := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": VeleroBackupResource.GroupVersion().String(),
+			"kind":       "Backup",
+			"metadata": map[string]interface{}{
+				"generateName": scope.Namespace + "-",
+				"namespace":    VeleroNamespace,
+			},
+			"spec": spec,
+		},
+	}
+
+	return client.Resource(VeleroBackupResource).Namespace(VeleroNamespace).Create(
+		context.TODO(),
+		backup,
+		metav1.CreateOptions{},
+	)
+}
+
+// ListVeleroBackups lists the backups of the cluster
+func ListVeleroBackups(client dynamic.Interface) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(VeleroBackupResource).Namespace(VeleroNamespace).List(
+		context.TODO(),
+		metav1.ListOptions{},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// GetVeleroBackup reads a backup of the cluster
+func GetVeleroBackup(client dynamic.Interface, name string) (*unstructured.Unstructured, error) {
+	return client.Resource(VeleroBackupResource).Namespace(VeleroNamespace).Get(
+		context.TODO(),
+		name,
+		metav1.GetOptions{},
+	)
+}
+
+// CreateVeleroSchedule creates a schedule that backs up a scope on a cron schedule
+func CreateVeleroSchedule(
+	client dynamic.Interface,
+	name, cron string,
+	scope *VeleroBackupScope,
+	ttl string,
+) (*unstructured.Unstructured, error) {
+	template := scope.toSpec()
+
+	if ttl != "" {
+		template["ttl"] = ttl
+	}
+
+	schedule := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": VeleroScheduleResource.GroupVersion().String(),
+			"kind":       "Schedule",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": VeleroNamespace,
+			},
+			"spec": map[string]interface{}{
+				"schedule": cron,
+				"template": template,
+			},
+		},
+	}
+
+	return client.Resource(VeleroScheduleResource).Namespace(VeleroNamespace).Create(
+		context.TODO(),
+		schedule,
+		metav1.CreateOptions{},
+	)
+}
+
+// ListVeleroSchedules lists the backup schedules of the cluster
+func ListVeleroSchedules(client dynamic.Interface) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(VeleroScheduleResource).Namespace(VeleroNamespace).List(
+		context.TODO(),
+		metav1.ListOptions{},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// DeleteVeleroSchedule deletes a backup schedule. The backups that were created by the
+// schedule are kept until they expire.
+func DeleteVeleroSchedule(client dynamic.Interface, name string) error {
+	err := client.Resource(VeleroScheduleResource).Namespace(VeleroNamespace).Delete(
+		context.TODO(),
+		name,
+		metav1.DeleteOptions{},
+	)
+
+	if err != nil && errors.IsNotFound(err) {
+		return fmt.Errorf("backup schedule %s does not exist", name)
+	}
+
+	return err
+}
+
+// CreateVeleroRestore creates a restore of a backup into the namespace that it was taken
+// from. If the scope is set, only the resources of the backup that match its selectors
+// are restored.
+func CreateVeleroRestore(
+	client dynamic.Interface,
+	backupName string,
+	scope *VeleroBackupScope,
+	existingResourcePolicy VeleroExistingResourcePolicy,
+) (*unstructured.Unstructured, error) {
+	spec := map[string]interface{}{
+		"backupName":             backupName,
+		"existingResourcePolicy": string(existingResourcePolicy),
+	}
+
+	if scope != nil {
+		scope.setSelectorSpec(spec)
+	}
+
+	restore := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": VeleroRestoreResource.GroupVersion().String(),
+			"kind":       "Restore",
+			"metadata": map[string]interface{}{
+				"generateName": backupName + "-",
+				"namespace":    VeleroNamespace,
+			},
+			"spec": spec,
+		},
+	}
+
+	return client.Resource(VeleroRestoreResource).Namespace(VeleroNamespace).Create(
+		context.TODO(),
+		restore,
+		metav1.CreateOptions{},
+	)
+}
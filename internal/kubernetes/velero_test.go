@@ -0,0 +1,124 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestVeleroScheduleScope(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			kubernetes.VeleroScheduleResource: "ScheduleList",
+		},
+	)
+
+	scopes := map[string]*kubernetes.VeleroBackupScope{
+		"default": {
+			Namespace: "default",
+			Selector:  map[string]string{},
+		},
+		"default-web": {
+			Namespace: "default",
+			Selector: map[string]string{
+				"porter.run/release": "web",
+			},
+		},
+		"default-api": {
+			Namespace: "default",
+			Selector: map[string]string{
+				"porter.run/release": "api",
+			},
+			AltSelectors: []map[string]string{
+				{"app.kubernetes.io/instance": "api"},
+			},
+		},
+	}
+
+	for name, scope := range scopes {
+		_, err := kubernetes.CreateVeleroSchedule(client, name, "0 3 * * *", scope, "720h")
+
+		if err != nil {
+			t.Fatalf("error creating schedule %s: %v", name, err)
+		}
+	}
+
+	schedules, err := kubernetes.ListVeleroSchedules(client)
+
+	if err != nil {
+		t.Fatalf("error listing schedules: %v", err)
+	}
+
+	if len(schedules) != len(scopes) {
+		t.Fatalf("expected %d schedules, got %d", len(scopes), len(schedules))
+	}
+
+	for _, schedule := range schedules {
+		template, _, _ := unstructured.NestedMap(schedule.Object, "spec", "template")
+
+		if diff := deep.Equal(kubernetes.GetVeleroBackupScope(template), scopes[schedule.GetName()]); diff != nil {
+			t.Errorf("incorrect scope of schedule %s", schedule.GetName())
+			t.Error(diff)
+		}
+
+		if ttl, _, _ := unstructured.NestedString(template, "ttl"); ttl != "720h" {
+			t.Errorf("expected ttl of schedule %s to be 720h, got %q", schedule.GetName(), ttl)
+		}
+	}
+}
+
+func TestVeleroRestore(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	restore, err := kubernetes.CreateVeleroRestore(
+		client,
+		"default-abc12",
+		&kubernetes.VeleroBackupScope{
+			Namespace: "default",
+			Selector: map[string]string{
+				"porter.run/release": "web",
+			},
+			AltSelectors: []map[string]string{
+				{"app.kubernetes.io/instance": "web"},
+			},
+		},
+		kubernetes.VeleroExistingResourcePolicyUpdate,
+	)
+
+	if err != nil {
+		t.Fatalf("error creating restore: %v", err)
+	}
+
+	spec, _, _ := unstructured.NestedMap(restore.Object, "spec")
+
+	if policy, _, _ := unstructured.NestedString(spec, "existingResourcePolicy"); policy != "update" {
+		t.Errorf("expected existing resource policy to be update, got %q", policy)
+	}
+
+	// Velero does not allow both a label selector and alternative label selectors
+	if _, found, _ := unstructured.NestedMap(spec, "labelSelector"); found {
+		t.Errorf("expected restore to have no label selector")
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"matchLabels": map[string]interface{}{"porter.run/release": "web"},
+		},
+		map[string]interface{}{
+			"matchLabels": map[string]interface{}{"app.kubernetes.io/instance": "web"},
+		},
+	}
+
+	orSelectors, _, _ := unstructured.NestedSlice(spec, "orLabelSelectors")
+
+	if diff := deep.Equal(orSelectors, expected); diff != nil {
+		t.Errorf("incorrect label selectors of restore")
+		t.Error(diff)
+	}
+}
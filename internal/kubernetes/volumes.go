@@ -0,0 +1,249 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VolumeSnapshotResource is the resource of the VolumeSnapshot CRD, which is installed
+// along with the CSI snapshot controller
+var VolumeSnapshotResource = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+// GetReleaseVolumeClaims returns the persistent volume claims of a release: the claims
+// in the manifest of the release, the claims created from the volume claim templates of
+// its statefulsets, and the claims mounted by its controllers
+func (a *Agent) GetReleaseVolumeClaims(namespace string, objs []grapher.Object) ([]v1.PersistentVolumeClaim, error) {
+	names := make(map[string]bool)
+	templatePatterns := make([]*regexp.Regexp, 0)
+
+	for _, obj := range objs {
+		switch obj.Kind {
+		case "PersistentVolumeClaim":
+			names[obj.Name] = true
+		case "StatefulSet":
+			templates, _ := getNestedField(obj.RawYAML, "spec", "volumeClaimTemplates").([]interface{})
+
+			// claims created from a template are named <template>-<statefulset>-<ordinal>
+			for _, template := range templates {
+				templateMap, _ := template.(map[string]interface{})
+				templateName, _ := getNestedField(templateMap, "metadata", "name").(string)
+
+				if templateName == "" {
+					continue
+				}
+
+				templatePatterns = append(templatePatterns, regexp.MustCompile(
+					fmt.Sprintf("^%s-%s-[0-9]+$", regexp.QuoteMeta(templateName), regexp.QuoteMeta(obj.Name)),
+				))
+			}
+		}
+
+		for _, claimName := range getMountedClaimNames(obj) {
+			names[claimName] = true
+		}
+	}
+
+	res := make([]v1.PersistentVolumeClaim, 0)
+
+	if len(names) == 0 && len(templatePatterns) == 0 {
+		return res, nil
+	}
+
+	claims, err := a.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(
+		context.TODO(),
+		metav1.ListOptions{},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, claim := range claims.Items {
+		matches := names[claim.Name]
+
+		for _, pattern := range templatePatterns {
+			matches = matches || pattern.MatchString(claim.Name)
+		}
+
+		if matches {
+			res = append(res, claim)
+		}
+	}
+
+	return res, nil
+}
+
+// getMountedClaimNames returns the names of the claims that are mounted by the pod spec
+// of a controller
+func getMountedClaimNames(obj grapher.Object) []string {
+	podSpecKeys := getPodSpecKeys(obj.Kind)
+
+	if podSpecKeys == nil {
+		return nil
+	}
+
+	volumes, _ := getNestedField(obj.RawYAML, append(podSpecKeys, "volumes")...).([]interface{})
+	res := make([]string, 0)
+
+	for _, volume := range volumes {
+		volumeMap, _ := volume.(map[string]interface{})
+
+		if claimName, ok := getNestedField(volumeMap, "persistentVolumeClaim", "claimName").(string); ok {
+			res = append(res, claimName)
+		}
+	}
+
+	return res
+}
+
+// getPodSpecKeys returns the path to the pod spec in a controller of the given kind, or
+// nil if the kind is not a controller
+func getPodSpecKeys(kind string) []string {
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return []string{"spec", "template", "spec"}
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	}
+
+	return nil
+}
+
+func getNestedField(obj map[string]interface{}, keys ...string) interface{} {
+	var curr interface{} = obj
+
+	for _, key := range keys {
+		currMap, ok := curr.(map[string]interface{})
+
+		if !ok {
+			return nil
+		}
+
+		curr = currMap[key]
+	}
+
+	return curr
+}
+
+// CanResizeVolumeClaim returns true if the storage class of a claim allows volume
+// expansion
+func (a *Agent) CanResizeVolumeClaim(claim *v1.PersistentVolumeClaim) (bool, error) {
+	if claim.Spec.StorageClassName == nil || *claim.Spec.StorageClassName == "" {
+		return false, nil
+	}
+
+	storageClass, err := a.Clientset.StorageV1().StorageClasses().Get(
+		context.TODO(),
+		*claim.Spec.StorageClassName,
+		metav1.GetOptions{},
+	)
+
+	if err != nil {
+		return false, wrapNotFound(err)
+	}
+
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+// ResizeVolumeClaim expands a claim to a larger size. Volumes can only be expanded if
+// their storage class allows it, and cannot be shrunk.
+func (a *Agent) ResizeVolumeClaim(claim *v1.PersistentVolumeClaim, size resource.Quantity) (*v1.PersistentVolumeClaim, error) {
+	canResize, err := a.CanResizeVolumeClaim(claim)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !canResize {
+		return nil, fmt.Errorf("the storage class of volume claim %s does not allow volume expansion", claim.Name)
+	}
+
+	if curr, ok := claim.Spec.Resources.Requests[v1.ResourceStorage]; ok && size.Cmp(curr) <= 0 {
+		return nil, fmt.Errorf("the new size must be larger than the current size of %s", curr.String())
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"resources":{"requests":{"storage":"%s"}}}}`, size.String())
+
+	return a.Clientset.CoreV1().PersistentVolumeClaims(claim.Namespace).Patch(
+		context.TODO(),
+		claim.Name,
+		types.MergePatchType,
+		[]byte(patch),
+		metav1.PatchOptions{},
+	)
+}
+
+// DeleteVolumeClaim deletes a claim. Claims that were already deleted, such as claims
+// in the manifest of an uninstalled release, are ignored.
+func (a *Agent) DeleteVolumeClaim(namespace, name string) error {
+	err := a.Clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(
+		context.TODO(),
+		name,
+		metav1.DeleteOptions{},
+	)
+
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// CreateVolumeSnapshot creates a VolumeSnapshot of a claim. If the snapshot class is
+// empty, the default snapshot class of the cluster is used.
+func CreateVolumeSnapshot(
+	client dynamic.Interface,
+	claim *v1.PersistentVolumeClaim,
+	name, snapshotClassName string,
+) (*unstructured.Unstructured, error) {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": claim.Name,
+		},
+	}
+
+	if snapshotClassName != "" {
+		spec["volumeSnapshotClassName"] = snapshotClassName
+	}
+
+	metadata := map[string]interface{}{
+		"namespace": claim.Namespace,
+	}
+
+	if name != "" {
+		metadata["name"] = name
+	} else {
+		metadata["generateName"] = claim.Name + "-"
+	}
+
+	snapshot := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": fmt.Sprintf("%s/%s", VolumeSnapshotResource.Group, VolumeSnapshotResource.Version),
+			"kind":       "VolumeSnapshot",
+			"metadata":   metadata,
+			"spec":       spec,
+		},
+	}
+
+	return client.Resource(VolumeSnapshotResource).Namespace(claim.Namespace).Create(
+		context.TODO(),
+		snapshot,
+		metav1.CreateOptions{},
+	)
+}
@@ -0,0 +1,143 @@
+package kubernetes_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testVolumesManifest = `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: uploads
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  template:
+    spec:
+      containers:
+      - name: db
+        image: postgres
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+      volumes:
+      - name: shared
+        persistentVolumeClaim:
+          claimName: shared-cache
+`
+
+func newTestClaim(name, storageClass, size string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: resource.MustParse(size),
+				},
+			},
+		},
+	}
+}
+
+func TestGetReleaseVolumeClaims(t *testing.T) {
+	agent := newAgentFixture(
+		t,
+		newTestClaim("uploads", "standard", "1Gi"),
+		newTestClaim("data-db-0", "standard", "1Gi"),
+		newTestClaim("data-db-1", "standard", "1Gi"),
+		newTestClaim("data-db-other", "standard", "1Gi"),
+		newTestClaim("shared-cache", "standard", "1Gi"),
+		newTestClaim("unrelated", "standard", "1Gi"),
+	)
+
+	objs := grapher.ParseObjs(grapher.ImportMultiDocYAML([]byte(testVolumesManifest)), "default")
+
+	claims, err := agent.GetReleaseVolumeClaims("default", objs)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	names := make([]string, 0)
+
+	for _, claim := range claims {
+		names = append(names, claim.Name)
+	}
+
+	sort.Strings(names)
+
+	expNames := []string{"data-db-0", "data-db-1", "shared-cache", "uploads"}
+
+	if diff := deep.Equal(names, expNames); diff != nil {
+		t.Errorf("incorrect volume claims")
+		t.Error(diff)
+	}
+}
+
+func TestResizeVolumeClaim(t *testing.T) {
+	allowExpansion := true
+
+	claim := newTestClaim("data", "expandable", "1Gi")
+	fixedClaim := newTestClaim("fixed-data", "fixed", "1Gi")
+
+	agent := newAgentFixture(
+		t,
+		claim,
+		fixedClaim,
+		&storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "expandable",
+			},
+			AllowVolumeExpansion: &allowExpansion,
+		},
+		&storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "fixed",
+			},
+		},
+	)
+
+	if _, err := agent.ResizeVolumeClaim(fixedClaim, resource.MustParse("2Gi")); err == nil {
+		t.Errorf("expected resizing a claim whose storage class does not allow expansion to fail")
+	}
+
+	if _, err := agent.ResizeVolumeClaim(claim, resource.MustParse("512Mi")); err == nil {
+		t.Errorf("expected shrinking a claim to fail")
+	}
+
+	resized, err := agent.ResizeVolumeClaim(claim, resource.MustParse("2Gi"))
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if size := resized.Spec.Resources.Requests[v1.ResourceStorage]; size.String() != "2Gi" {
+		t.Errorf("expected claim to request 2Gi, got %s", size.String())
+	}
+}
@@ -145,6 +145,11 @@ func AddLoggingContextScopes(ctx context.Context, event *zerolog.Event) map[stri
 	res := make(map[string]interface{})
 
 	// case on the context values that exist, add them to event
+	if handlerName, ok := ctx.Value(types.HandlerNameCtxKey).(string); ok && handlerName != "" {
+		event.Str("handler", handlerName)
+		res["handler"] = handlerName
+	}
+
 	if userVal := ctx.Value(types.UserScope); userVal != nil {
 		if userModel, ok := userVal.(*models.User); ok {
 			event.Uint("user_id", userModel.ID)
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// Addon tracks the chart version of an addon installed in a cluster, so that the
+// addon can be checked for updates against the index of its repo
+type Addon struct {
+	gorm.Model
+
+	ProjectID uint
+	ClusterID uint
+	Namespace string
+	Name      string
+
+	ChartName    string
+	ChartVersion string
+	RepoURL      string
+
+	// LatestVersion is the latest version of the chart in the repo, as of the last
+	// update check
+	LatestVersion string
+	LastCheckedAt *time.Time
+}
+
+// IsOutdated returns true if a newer version of the chart was found in the repo
+func (a *Addon) IsOutdated() bool {
+	if a.LatestVersion == "" || a.LatestVersion == a.ChartVersion {
+		return false
+	}
+
+	latest, err := semver.NewVersion(a.LatestVersion)
+
+	if err != nil {
+		return false
+	}
+
+	current, err := semver.NewVersion(a.ChartVersion)
+
+	if err != nil {
+		return true
+	}
+
+	return latest.GreaterThan(current)
+}
+
+func (a *Addon) ToAddonType() *types.Addon {
+	return &types.Addon{
+		ID:              a.ID,
+		CreatedAt:       a.CreatedAt,
+		ClusterID:       a.ClusterID,
+		Namespace:       a.Namespace,
+		Name:            a.Name,
+		ChartName:       a.ChartName,
+		ChartVersion:    a.ChartVersion,
+		RepoURL:         a.RepoURL,
+		LatestVersion:   a.LatestVersion,
+		UpdateAvailable: a.IsOutdated(),
+		LastCheckedAt:   a.LastCheckedAt,
+	}
+}
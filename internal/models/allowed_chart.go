@@ -0,0 +1,41 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// AllowedChart is an entry of the chart allow-list of a project
+type AllowedChart struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+
+	// RepoURL is the Helm repository of the allowed charts, or empty to allow charts
+	// from any repository
+	RepoURL string
+
+	// ChartName is the name of the allowed chart, or empty to allow every chart of
+	// the repository
+	ChartName string
+}
+
+// Matches returns true if a chart from a Helm repository is allowed by the entry
+func (a *AllowedChart) Matches(repoURL, chartName string) bool {
+	if a.RepoURL != "" && strings.TrimSuffix(a.RepoURL, "/") != strings.TrimSuffix(repoURL, "/") {
+		return false
+	}
+
+	return a.ChartName == "" || a.ChartName == chartName
+}
+
+func (a *AllowedChart) ToAllowedChartType() *types.AllowedChart {
+	return &types.AllowedChart{
+		ID:        a.ID,
+		ProjectID: a.ProjectID,
+		RepoURL:   a.RepoURL,
+		ChartName: a.ChartName,
+	}
+}
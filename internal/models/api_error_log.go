@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// APIErrorLog stores a failed API operation of a project, so that users can see why
+// their requests failed
+type APIErrorLog struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+
+	Method     string
+	Path       string
+	Handler    string
+	StatusCode int
+	Error      string
+	ErrorCode  types.ErrorCode
+	Category   types.APIErrorCategory
+
+	ClusterID   uint
+	Namespace   string
+	ReleaseName string
+}
+
+func (a *APIErrorLog) ToAPIErrorLogType() *types.APIErrorLog {
+	return &types.APIErrorLog{
+		ID:          a.ID,
+		CreatedAt:   a.CreatedAt,
+		Method:      a.Method,
+		Path:        a.Path,
+		Handler:     a.Handler,
+		StatusCode:  a.StatusCode,
+		Error:       a.Error,
+		ErrorCode:   a.ErrorCode,
+		Category:    a.Category,
+		ClusterID:   a.ClusterID,
+		Namespace:   a.Namespace,
+		ReleaseName: a.ReleaseName,
+	}
+}
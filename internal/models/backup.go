@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// Backup tracks the status of a Velero backup of a cluster, which is synced from the
+// Backup resource in the cluster
+type Backup struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+	ClusterID uint `gorm:"index"`
+
+	// Name is the name of the Backup resource in the Velero namespace
+	Name string
+
+	// Namespace is the namespace that is backed up, and ReleaseName is the release in the
+	// namespace that is backed up, or empty if the whole namespace is backed up
+	Namespace   string
+	ReleaseName string
+
+	// ScheduleName is the Velero schedule that created the backup, or empty if the backup
+	// was created on demand
+	ScheduleName string
+
+	Phase    string
+	Errors   int
+	Warnings int
+
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+}
+
+func (b *Backup) ToBackupType() *types.Backup {
+	return &types.Backup{
+		Name:         b.Name,
+		Namespace:    b.Namespace,
+		ReleaseName:  b.ReleaseName,
+		ScheduleName: b.ScheduleName,
+		Phase:        types.BackupPhase(b.Phase),
+		Errors:       b.Errors,
+		Warnings:     b.Warnings,
+		StartedAt:    b.StartedAt,
+		CompletedAt:  b.CompletedAt,
+		ExpiresAt:    b.ExpiresAt,
+	}
+}
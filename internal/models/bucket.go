@@ -0,0 +1,24 @@
+package models
+
+import "github.com/porter-dev/porter/api/types"
+
+// Bucket is an object storage bucket provisioned through an infra, along with the
+// credentials that can access the bucket
+type Bucket struct {
+	ProvisionedResource
+}
+
+func (b *Bucket) ToBucketType() *types.Bucket {
+	return &types.Bucket{
+		ID:           b.ID,
+		ProjectID:    b.ProjectID,
+		ClusterID:    b.ClusterID,
+		InfraID:      b.InfraID,
+		Kind:         b.Kind,
+		Name:         b.Name,
+		Region:       b.Region,
+		Namespace:    b.Namespace,
+		EnvGroupName: b.EnvGroupName,
+		Status:       b.Status,
+	}
+}
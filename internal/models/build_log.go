@@ -0,0 +1,34 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// BuildLog stores the output of a build of a release, which is uploaded by the CLI
+// when the build runs in a Github workflow or from a remote source
+type BuildLog struct {
+	gorm.Model
+
+	ReleaseID uint `gorm:"index"`
+	CommitSHA string
+
+	// Status is either success or failed
+	Status types.EventStatus
+	Logs   []byte
+}
+
+func (b *BuildLog) ToBuildLogType(withLogs bool) *types.BuildLog {
+	res := &types.BuildLog{
+		ID:        b.ID,
+		CreatedAt: b.CreatedAt,
+		CommitSHA: b.CommitSHA,
+		Status:    b.Status,
+	}
+
+	if withLogs {
+		res.Logs = string(b.Logs)
+	}
+
+	return res
+}
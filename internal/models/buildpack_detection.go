@@ -0,0 +1,34 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// BuildpackDetection is the stored result of buildpack detection on a directory of a git
+// repo at a commit, so that the result is reused instead of detected again for the same
+// commit
+type BuildpackDetection struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+
+	// GitInstallationID is the id of the github app installation on github
+	GitInstallationID uint
+
+	GitRepoOwner string
+	GitRepoName  string
+	GitBranch    string
+	CommitSHA    string
+	FolderPath   string
+
+	// Runtime is the name of the first detected runtime, or empty if no runtime was
+	// detected
+	Runtime string
+
+	// SuggestedBuilder is the builder stack that is suggested for the detected buildpacks
+	SuggestedBuilder string
+
+	// Builders is the JSON-encoded detection output for each builder, including the
+	// package manager and env var hints of each detected buildpack
+	Builders []byte
+}
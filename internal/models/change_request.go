@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// ReleaseChangeRequest is a pending upgrade, rollback, deletion or kustomize patches change
+// of a protected release
+type ReleaseChangeRequest struct {
+	gorm.Model
+
+	ProjectID uint
+	ClusterID uint
+	Namespace string
+	Name      string
+
+	Operation types.ChangeRequestOperation
+	Status    types.ChangeRequestStatus
+
+	// Values and ChartVersion are the values and chart version of an upgrade. The
+	// sensitive values of an upgrade are replaced with placeholders, and are stored in
+	// SensitiveValues instead.
+	Values       string
+	ChartVersion string
+
+	// SensitiveValues are the sensitive values of an upgrade as JSON keyed by values path,
+	// which are encrypted before they are written to the DB
+	SensitiveValues []byte
+
+	// BaseRevision is the revision of the release that the change was requested for.
+	// Changes are not applied once the release was upgraded past this revision.
+	BaseRevision int
+
+	// RollbackRevision is the revision that a rollback rolls the release back to
+	RollbackRevision int
+
+	// DeleteCascade and DeleteVolumes are the options of a deletion, which are applied
+	// once the deletion is approved
+	DeleteCascade bool
+	DeleteVolumes bool
+
+	// KustomizePatches are the kustomize patches that a kustomize patches change sets,
+	// which were rendered against BaseRevision when the change was requested
+	KustomizePatches string
+
+	// ValuesDiff is the newline-separated diff of the values of an upgrade
+	ValuesDiff string
+
+	RequestedByUserID uint
+	ReviewedByUserID  uint
+
+	Error string
+}
+
+func (cr *ReleaseChangeRequest) ToReleaseChangeRequestType() *types.ReleaseChangeRequest {
+	diff := make([]string, 0)
+
+	if cr.ValuesDiff != "" {
+		diff = strings.Split(cr.ValuesDiff, "\n")
+	}
+
+	return &types.ReleaseChangeRequest{
+		ID:               cr.ID,
+		CreatedAt:        cr.CreatedAt,
+		UpdatedAt:        cr.UpdatedAt,
+		Namespace:        cr.Namespace,
+		Name:             cr.Name,
+		Operation:        cr.Operation,
+		Status:           cr.Status,
+		ChartVersion:     cr.ChartVersion,
+		ValuesDiff:       diff,
+		BaseRevision:     cr.BaseRevision,
+		Revision:         cr.RollbackRevision,
+		Cascade:          cr.DeleteCascade,
+		DeleteVolumes:    cr.DeleteVolumes,
+		KustomizePatches: cr.KustomizePatches,
+		RequestedBy:      cr.RequestedByUserID,
+		ReviewedBy:       cr.ReviewedByUserID,
+		Error:            cr.Error,
+	}
+}
+
+// GetSensitiveValues returns the unmarshaled sensitive values of an upgrade, keyed by
+// values path
+func (cr *ReleaseChangeRequest) GetSensitiveValues() (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+
+	if len(cr.SensitiveValues) == 0 {
+		return res, nil
+	}
+
+	if err := json.Unmarshal(cr.SensitiveValues, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ProtectedNamespace marks a namespace in which upgrades and deletions of all releases
+// require approval
+type ProtectedNamespace struct {
+	gorm.Model
+
+	ClusterID uint
+	Namespace string
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/porter-dev/porter/api/types"
 	"github.com/porter-dev/porter/internal/models/integrations"
@@ -53,6 +54,16 @@ type Cluster struct {
 
 	NotificationsDisabled bool `json:"notifications_disabled"`
 
+	// PodSecurityLevel is the Pod Security Standards level that is enforced on the
+	// namespaces of the cluster that Porter manages
+	PodSecurityLevel types.PodSecurityLevel `json:"pod_security_level"`
+
+	// The address that the load balancer of the ingress controller is pinned to. The
+	// EIP allocations are a comma-separated list.
+	IngressStaticIP       string `json:"ingress_static_ip"`
+	IngressEIPAllocations string `json:"ingress_eip_allocations"`
+	IngressLoadBalancerID string `json:"ingress_load_balancer_id"`
+
 	// ------------------------------------------------------------------
 	// All fields below this line are encrypted before storage
 	// ------------------------------------------------------------------
@@ -85,14 +96,55 @@ func (c *Cluster) ToClusterType() *types.Cluster {
 	}
 
 	return &types.Cluster{
-		ID:               c.ID,
-		ProjectID:        c.ProjectID,
-		Name:             c.Name,
-		Server:           c.Server,
-		Service:          serv,
-		InfraID:          c.InfraID,
-		AWSIntegrationID: c.AWSIntegrationID,
+		ID:                  c.ID,
+		ProjectID:           c.ProjectID,
+		Name:                c.Name,
+		Server:              c.Server,
+		Service:             serv,
+		InfraID:             c.InfraID,
+		AWSIntegrationID:    c.AWSIntegrationID,
+		PodSecurityLevel:    c.GetPodSecurityLevel(),
+		IngressLoadBalancer: c.GetIngressLoadBalancer(),
+	}
+}
+
+// GetIngressLoadBalancer returns the address that the load balancer of the ingress
+// controller is pinned to, or nil if it is not pinned
+func (c *Cluster) GetIngressLoadBalancer() *types.IngressLoadBalancer {
+	res := &types.IngressLoadBalancer{
+		StaticIP:       c.IngressStaticIP,
+		LoadBalancerID: c.IngressLoadBalancerID,
+	}
+
+	for _, alloc := range strings.Split(c.IngressEIPAllocations, ",") {
+		if alloc != "" {
+			res.EIPAllocations = append(res.EIPAllocations, alloc)
+		}
+	}
+
+	if res.IsEmpty() {
+		return nil
+	}
+
+	return res
+}
+
+// SetIngressLoadBalancer pins the load balancer of the ingress controller to an address,
+// or unpins it if the settings are empty
+func (c *Cluster) SetIngressLoadBalancer(lb *types.IngressLoadBalancer) {
+	c.IngressStaticIP = lb.StaticIP
+	c.IngressEIPAllocations = strings.Join(lb.EIPAllocations, ",")
+	c.IngressLoadBalancerID = lb.LoadBalancerID
+}
+
+// GetPodSecurityLevel returns the enforced Pod Security Standards level, which is
+// privileged if no level has been set
+func (c *Cluster) GetPodSecurityLevel() types.PodSecurityLevel {
+	if c.PodSecurityLevel == "" {
+		return types.PodSecurityLevelPrivileged
 	}
+
+	return c.PodSecurityLevel
 }
 
 // ClusterCandidate is a cluster integration that requires additional action
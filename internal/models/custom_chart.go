@@ -0,0 +1,48 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// CustomChart is a version of a Helm chart that was provided by a project. The packaged
+// chart is stored with each version, so that releases can be upgraded and rolled back to
+// any version of the chart.
+type CustomChart struct {
+	gorm.Model
+
+	ProjectID   uint
+	Name        string
+	Version     string
+	Description string
+	Source      types.CustomChartSource
+
+	GitRepoID uint
+	GitRepo   string
+	GitBranch string
+	ChartPath string
+	CommitSHA string
+
+	ImageValuesKey string
+
+	// Archive is the packaged chart
+	Archive []byte
+}
+
+func (c *CustomChart) ToCustomChartType() *types.CustomChart {
+	return &types.CustomChart{
+		ID:             c.ID,
+		CreatedAt:      c.CreatedAt,
+		ProjectID:      c.ProjectID,
+		Name:           c.Name,
+		Version:        c.Version,
+		Description:    c.Description,
+		Source:         c.Source,
+		GitRepoID:      c.GitRepoID,
+		GitRepo:        c.GitRepo,
+		GitBranch:      c.GitBranch,
+		ChartPath:      c.ChartPath,
+		CommitSHA:      c.CommitSHA,
+		ImageValuesKey: c.ImageValuesKey,
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// DefaultValues are values that are merged beneath the values of every release that is
+// created in a project, or in a single cluster if ClusterID is set
+type DefaultValues struct {
+	gorm.Model
+
+	ProjectID uint
+	ClusterID uint
+
+	// Values are stored as JSON
+	Values []byte
+}
+
+// GetValues returns the unmarshaled default values
+func (d *DefaultValues) GetValues() (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+
+	if len(d.Values) == 0 {
+		return res, nil
+	}
+
+	if err := json.Unmarshal(d.Values, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// DeployFreeze is a window during which deploys and infra changes in a project, or in a
+// single cluster of a project, are blocked. A freeze either recurs on a cron schedule
+// for a duration, or covers a single range of time.
+type DeployFreeze struct {
+	gorm.Model
+
+	ProjectID uint
+
+	// ClusterID is 0 for freezes of all clusters of the project
+	ClusterID uint
+
+	Name string
+
+	Schedule        string
+	DurationMinutes uint
+	Timezone        string
+
+	StartsAt *time.Time
+	EndsAt   *time.Time
+
+	CreatedByUserID uint
+}
+
+func (f *DeployFreeze) ToDeployFreezeType() *types.DeployFreeze {
+	return &types.DeployFreeze{
+		ID:              f.ID,
+		CreatedAt:       f.CreatedAt,
+		ClusterID:       f.ClusterID,
+		Name:            f.Name,
+		Schedule:        f.Schedule,
+		DurationMinutes: f.DurationMinutes,
+		Timezone:        f.Timezone,
+		StartsAt:        f.StartsAt,
+		EndsAt:          f.EndsAt,
+		CreatedBy:       f.CreatedByUserID,
+	}
+}
+
+// DeployFreezeOverride records a request that was allowed during a deploy freeze, and
+// the reason that the user gave for overriding the freeze
+type DeployFreezeOverride struct {
+	gorm.Model
+
+	ProjectID      uint
+	ClusterID      uint
+	DeployFreezeID uint
+	UserID         uint
+
+	Method string
+	Path   string
+	Reason string
+}
+
+func (o *DeployFreezeOverride) ToDeployFreezeOverrideType() *types.DeployFreezeOverride {
+	return &types.DeployFreezeOverride{
+		ID:             o.ID,
+		CreatedAt:      o.CreatedAt,
+		ClusterID:      o.ClusterID,
+		DeployFreezeID: o.DeployFreezeID,
+		UserID:         o.UserID,
+		Method:         o.Method,
+		Path:           o.Path,
+		Reason:         o.Reason,
+	}
+}
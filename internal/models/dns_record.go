@@ -18,6 +18,11 @@ type DNSRecord struct {
 	Hostname string `json:"hostname"`
 
 	ClusterID uint `json:"cluster_id"`
+
+	// The release that the record was created for, which is used to remove the record
+	// when the release is deleted
+	Namespace   string `json:"namespace"`
+	ReleaseName string `json:"release_name"`
 }
 
 func (p *DNSRecord) ToDNSRecordType() *types.DNSRecord {
@@ -15,6 +15,15 @@ type Environment struct {
 	GitRepoName       string
 
 	Name string
+
+	// ForkApprovalRequired determines if pull requests from forks are only deployed
+	// once a maintainer approves them
+	ForkApprovalRequired bool
+
+	// RoutingMode determines if applications are exposed on subdomains, or under path
+	// prefixes of the shared PathRoutingHost
+	RoutingMode     types.PreviewRoutingMode `gorm:"default:subdomain"`
+	PathRoutingHost string
 }
 
 func (e *Environment) ToEnvironmentType() *types.Environment {
@@ -26,6 +35,10 @@ func (e *Environment) ToEnvironmentType() *types.Environment {
 		GitRepoOwner:      e.GitRepoOwner,
 		GitRepoName:       e.GitRepoName,
 		Name:              e.Name,
+
+		ForkApprovalRequired: e.ForkApprovalRequired,
+		RoutingMode:          e.RoutingMode,
+		PathRoutingHost:      e.PathRoutingHost,
 	}
 }
 
@@ -42,6 +55,19 @@ type Deployment struct {
 	RepoName       string
 	RepoOwner      string
 	CommitSHA      string
+
+	// IsFork is set for deployments of pull requests from forks, which need the approval
+	// of a maintainer if the environment requires it
+	IsFork     bool
+	ApprovedBy string
+
+	// ApprovedCommitSHA is the commit that was approved. Commits that are pushed to the
+	// pull request after the approval need to be approved again.
+	ApprovedCommitSHA string
+
+	// GHActionID is the ID of the workflow run that created the deployment, which is
+	// re-run once a pending deployment is approved
+	GHActionID uint
 }
 
 func (d *Deployment) ToDeploymentType() *types.Deployment {
@@ -55,15 +81,18 @@ func (d *Deployment) ToDeploymentType() *types.Deployment {
 	}
 
 	return &types.Deployment{
-		CreatedAt:      d.CreatedAt,
-		UpdatedAt:      d.UpdatedAt,
-		ID:             d.Model.ID,
-		EnvironmentID:  d.EnvironmentID,
-		Namespace:      d.Namespace,
-		Status:         d.Status,
-		Subdomain:      d.Subdomain,
-		PullRequestID:  d.PullRequestID,
-		GitHubMetadata: ghMetadata,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+		ID:                d.Model.ID,
+		EnvironmentID:     d.EnvironmentID,
+		Namespace:         d.Namespace,
+		Status:            d.Status,
+		Subdomain:         d.Subdomain,
+		PullRequestID:     d.PullRequestID,
+		GitHubMetadata:    ghMetadata,
+		IsFork:            d.IsFork,
+		ApprovedBy:        d.ApprovedBy,
+		ApprovedCommitSHA: d.ApprovedCommitSHA,
 	}
 }
 
@@ -80,6 +109,19 @@ type DeploymentWithEnvironment struct {
 	RepoName       string
 	RepoOwner      string
 	CommitSHA      string
+
+	// IsFork is set for deployments of pull requests from forks, which need the approval
+	// of a maintainer if the environment requires it
+	IsFork     bool
+	ApprovedBy string
+
+	// ApprovedCommitSHA is the commit that was approved. Commits that are pushed to the
+	// pull request after the approval need to be approved again.
+	ApprovedCommitSHA string
+
+	// GHActionID is the ID of the workflow run that created the deployment, which is
+	// re-run once a pending deployment is approved
+	GHActionID uint
 }
 
 func (d *DeploymentWithEnvironment) ToDeploymentType() *types.Deployment {
@@ -103,5 +145,8 @@ func (d *DeploymentWithEnvironment) ToDeploymentType() *types.Deployment {
 		Subdomain:         d.Subdomain,
 		PullRequestID:     d.PullRequestID,
 		GitHubMetadata:    ghMetadata,
+		IsFork:            d.IsFork,
+		ApprovedBy:        d.ApprovedBy,
+		ApprovedCommitSHA: d.ApprovedCommitSHA,
 	}
 }
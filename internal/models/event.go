@@ -21,6 +21,15 @@ type SubEvent struct {
 	Index   int64 // priority of the event, used for sorting
 	Status  types.EventStatus
 	Info    string
+
+	// the following fields are only set for deploy events
+	Initiator     string
+	InitiatorKind types.DeployInitiatorKind
+	Source        types.DeploySource
+	CommitSHA     string
+	ImageTag      string
+	Revision      int
+	Message       string
 }
 
 func (event *SubEvent) ToSubEventType() types.SubEvent {
@@ -31,5 +40,13 @@ func (event *SubEvent) ToSubEventType() types.SubEvent {
 		Status:  event.Status,
 		Info:    event.Info,
 		Time:    event.UpdatedAt.Unix(),
+
+		Initiator:     event.Initiator,
+		InitiatorKind: event.InitiatorKind,
+		Source:        event.Source,
+		CommitSHA:     event.CommitSHA,
+		ImageTag:      event.ImageTag,
+		Revision:      event.Revision,
+		Message:       event.Message,
 	}
 }
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// GitOpsConfig is the git repository that the releases of a project are mirrored to
+type GitOpsConfig struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"unique"`
+
+	// GitInstallationID is the id of the Github App installation that is used to push
+	// to and read from the repository
+	GitInstallationID uint
+	GitRepoOwner      string
+	GitRepoName       string
+	Branch            string
+	Path              string
+
+	// ReconcileEnabled is true if changes that are pushed to the repository are applied
+	// to the releases of the project
+	ReconcileEnabled bool
+
+	// LastReconciledSHA is the commit of the branch that was last reconciled, so that
+	// a commit is only reconciled once. It is only set when every release of the commit
+	// was reconciled, so that failed commits are reconciled again.
+	LastReconciledSHA  string
+	LastReconciledAt   *time.Time
+	LastReconcileError string
+
+	// ReconcileLeaseExpiresAt is set while a server replica reconciles the repository,
+	// so that a commit is not reconciled by multiple replicas at the same time
+	ReconcileLeaseExpiresAt *time.Time
+}
+
+func (g *GitOpsConfig) ToGitOpsConfigType() *types.GitOpsConfig {
+	return &types.GitOpsConfig{
+		ID:                 g.ID,
+		ProjectID:          g.ProjectID,
+		GitInstallationID:  g.GitInstallationID,
+		GitRepoOwner:       g.GitRepoOwner,
+		GitRepoName:        g.GitRepoName,
+		Branch:             g.Branch,
+		Path:               g.Path,
+		ReconcileEnabled:   g.ReconcileEnabled,
+		LastReconciledSHA:  g.LastReconciledSHA,
+		LastReconciledAt:   g.LastReconciledAt,
+		LastReconcileError: g.LastReconcileError,
+	}
+}
@@ -53,6 +53,9 @@ type GitActionConfig struct {
 	IsInstallation bool `json:"is_installation"`
 
 	Version string `json:"version" gorm:"default:v0.0.1"`
+
+	// Determines if pull requests are commented on when their commits are deployed
+	PRComments bool `json:"pr_comments"`
 }
 
 // ToGitActionConfigType generates an external GitActionConfig to be shared over REST
@@ -64,5 +67,6 @@ func (r *GitActionConfig) ToGitActionConfigType() *types.GitActionConfig {
 		GitRepoID:      r.GithubInstallationID,
 		DockerfilePath: r.DockerfilePath,
 		FolderPath:     r.FolderPath,
+		PRComments:     r.PRComments,
 	}
 }
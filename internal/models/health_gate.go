@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// HealthGateConfig configures the verification of a release after it is upgraded. If the
+// new version fails verification, the release is rolled back to the previous version.
+type HealthGateConfig struct {
+	gorm.Model
+
+	Enabled bool
+
+	// VerificationMinutes is the duration that the new version is watched for
+	VerificationMinutes uint
+
+	// MaxErrorRate is the maximum percentage of 5xx responses through the ingress, and
+	// MaxLatency is the maximum average response latency in seconds. These are only
+	// checked if both are positive and Prometheus is installed in the cluster.
+	MaxErrorRate float64
+	MaxLatency   float64
+}
+
+func (conf *HealthGateConfig) ToHealthGateConfigType() *types.HealthGateConfig {
+	return &types.HealthGateConfig{
+		Enabled:             conf.Enabled,
+		VerificationMinutes: conf.VerificationMinutes,
+		MaxErrorRate:        conf.MaxErrorRate,
+		MaxLatency:          conf.MaxLatency,
+	}
+}
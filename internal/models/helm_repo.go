@@ -22,6 +22,11 @@ type HelmRepo struct {
 	// GCS it may be gs://
 	RepoURL string `json:"repo_url"`
 
+	// ClusterID is the cluster that the credentials of this Helm repository are used
+	// for. If it is 0, the credentials are used for every cluster of the project that
+	// does not have its own credentials for the repository.
+	ClusterID uint `json:"cluster_id"`
+
 	// ------------------------------------------------------------------
 	// All fields below this line are encrypted before storage
 	// ------------------------------------------------------------------
@@ -53,5 +58,6 @@ func (hr *HelmRepo) ToHelmRepoType() *types.HelmRepo {
 		ProjectID: hr.ProjectID,
 		Name:      hr.Name,
 		RepoURL:   hr.RepoURL,
+		ClusterID: hr.ClusterID,
 	}
 }
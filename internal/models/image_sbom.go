@@ -0,0 +1,60 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// ImageSBOM is the dependency inventory of an image of a project, which is parsed from an
+// SBOM that the CLI uploads after it pushes the image. Inventories are keyed by the
+// repository and tag of the image, which releases are deployed with, along with the
+// digest of the image, so that a tag that is pushed again gets a new inventory.
+type ImageSBOM struct {
+	gorm.Model
+
+	ProjectID uint   `gorm:"index"`
+	Digest    string `gorm:"index"`
+	ImageRepo string `gorm:"index"`
+	Tag       string
+	Format    types.SBOMFormat
+
+	Packages []SBOMPackage
+}
+
+func (s *ImageSBOM) ToImageSBOMType() *types.ImageSBOM {
+	pkgs := make([]*types.SBOMPackage, 0)
+
+	for _, pkg := range s.Packages {
+		pkgs = append(pkgs, pkg.ToSBOMPackageType())
+	}
+
+	return &types.ImageSBOM{
+		ID:        s.ID,
+		CreatedAt: s.CreatedAt,
+		ImageRepo: s.ImageRepo,
+		Tag:       s.Tag,
+		Digest:    s.Digest,
+		Format:    s.Format,
+		Packages:  pkgs,
+	}
+}
+
+// SBOMPackage is a package of the inventory of an image
+type SBOMPackage struct {
+	gorm.Model
+
+	ImageSBOMID uint   `gorm:"index"`
+	Name        string `gorm:"index"`
+	Version     string
+	Type        string
+	PURL        string
+}
+
+func (p *SBOMPackage) ToSBOMPackageType() *types.SBOMPackage {
+	return &types.SBOMPackage{
+		Name:    p.Name,
+		Version: p.Version,
+		Type:    p.Type,
+		PURL:    p.PURL,
+	}
+}
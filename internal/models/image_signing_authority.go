@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// ImageSigningAuthority is a cosign signer of the images of a project, which is either a
+// public key or a keyless identity
+type ImageSigningAuthority struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+
+	Name string
+
+	// PublicKey is the PEM-encoded public key of the authority, or empty for keyless
+	// identities
+	PublicKey string
+
+	// Issuer and Subject are the OIDC issuer and subject of the Fulcio certificates of a
+	// keyless identity
+	Issuer  string
+	Subject string
+}
+
+func (a *ImageSigningAuthority) ToImageSigningAuthorityType() *types.ImageSigningAuthority {
+	return &types.ImageSigningAuthority{
+		ID:        a.ID,
+		ProjectID: a.ProjectID,
+		Name:      a.Name,
+		PublicKey: a.PublicKey,
+		Issuer:    a.Issuer,
+		Subject:   a.Subject,
+	}
+}
@@ -44,6 +44,14 @@ type Infra struct {
 	// The database id for the infra, if this infra provisioned a database
 	DatabaseID uint
 
+	// The version of the Terraform modules (the provisioner image tag) that this
+	// infra was last applied with. If empty, the server default is used.
+	ModuleVersion string
+
+	// The module version of an upgrade that has not been applied yet, which becomes the
+	// ModuleVersion of the infra once the apply succeeds
+	PendingModuleVersion string
+
 	// ------------------------------------------------------------------
 	// All fields below this line are encrypted before storage
 	// ------------------------------------------------------------------
@@ -55,16 +63,18 @@ type Infra struct {
 // ToInfraType generates an external Infra to be shared over REST
 func (i *Infra) ToInfraType() *types.Infra {
 	return &types.Infra{
-		ID:               i.ID,
-		CreatedAt:        i.CreatedAt,
-		UpdatedAt:        i.UpdatedAt,
-		ProjectID:        i.ProjectID,
-		Kind:             i.Kind,
-		Status:           i.Status,
-		AWSIntegrationID: i.AWSIntegrationID,
-		DOIntegrationID:  i.DOIntegrationID,
-		GCPIntegrationID: i.GCPIntegrationID,
-		LastApplied:      i.SafelyGetLastApplied(),
+		ID:                   i.ID,
+		CreatedAt:            i.CreatedAt,
+		UpdatedAt:            i.UpdatedAt,
+		ProjectID:            i.ProjectID,
+		Kind:                 i.Kind,
+		Status:               i.Status,
+		AWSIntegrationID:     i.AWSIntegrationID,
+		DOIntegrationID:      i.DOIntegrationID,
+		GCPIntegrationID:     i.GCPIntegrationID,
+		ModuleVersion:        i.ModuleVersion,
+		PendingModuleVersion: i.PendingModuleVersion,
+		LastApplied:          i.SafelyGetLastApplied(),
 	}
 }
 
@@ -92,6 +102,9 @@ func (i *Infra) SafelyGetLastApplied() map[string]string {
 
 		resp["eks_name"] = lastApplied.EKSName
 		resp["machine_type"] = lastApplied.MachineType
+		resp["vpc_id"] = lastApplied.VPCID
+		resp["node_group_count"] = fmt.Sprintf("%d", len(lastApplied.NodeGroups))
+		resp["enable_cluster_autoscaler"] = strconv.FormatBool(lastApplied.EnableClusterAutoscaler)
 
 		return resp
 	case types.InfraGCR:
@@ -104,6 +117,9 @@ func (i *Infra) SafelyGetLastApplied() map[string]string {
 		}
 
 		resp["gke_name"] = lastApplied.GKEName
+		resp["gcp_region"] = lastApplied.GCPRegion
+		resp["autopilot"] = strconv.FormatBool(lastApplied.Autopilot)
+		resp["regional"] = strconv.FormatBool(lastApplied.Regional)
 
 		return resp
 	case types.InfraDOCR:
@@ -139,6 +155,62 @@ func (i *Infra) SafelyGetLastApplied() map[string]string {
 		resp["aws_region"] = lastApplied.AWSRegion
 		resp["db_name"] = lastApplied.DBName
 
+		return resp
+	case types.InfraS3, types.InfraGCS:
+		lastApplied := &types.BucketInfraLastApplied{}
+
+		if err := json.Unmarshal(i.LastApplied, lastApplied); err != nil || lastApplied.CreateBucketInfraRequest == nil {
+			return resp
+		}
+
+		resp["cluster_id"] = fmt.Sprintf("%d", lastApplied.ClusterID)
+		resp["bucket_name"] = lastApplied.BucketName
+		resp["versioning"] = strconv.FormatBool(lastApplied.Versioning)
+
+		if i.Kind == types.InfraS3 {
+			resp["aws_region"] = lastApplied.AWSRegion
+		} else {
+			resp["gcp_region"] = lastApplied.GCPRegion
+		}
+
+		return resp
+	case types.InfraSQS, types.InfraPubSub:
+		lastApplied := &types.QueueInfraLastApplied{}
+
+		if err := json.Unmarshal(i.LastApplied, lastApplied); err != nil || lastApplied.CreateQueueInfraRequest == nil {
+			return resp
+		}
+
+		resp["cluster_id"] = fmt.Sprintf("%d", lastApplied.ClusterID)
+		resp["queue_name"] = lastApplied.QueueName
+
+		if i.Kind == types.InfraSQS {
+			resp["aws_region"] = lastApplied.AWSRegion
+			resp["fifo"] = strconv.FormatBool(lastApplied.FIFO)
+		} else {
+			resp["gcp_project_id"] = lastApplied.GCPProjectID
+		}
+
+		return resp
+	case types.InfraRDSNetwork, types.InfraCloudSQLNetwork:
+		lastApplied := &types.PrivateNetworkInfraLastApplied{}
+
+		if err := json.Unmarshal(i.LastApplied, lastApplied); err != nil || lastApplied.CreatePrivateNetworkInfraRequest == nil {
+			return resp
+		}
+
+		resp["cluster_id"] = fmt.Sprintf("%d", lastApplied.ClusterID)
+		resp["cluster_infra_id"] = fmt.Sprintf("%d", lastApplied.ClusterInfraID)
+
+		if i.Kind == types.InfraRDSNetwork {
+			resp["database_infra_id"] = fmt.Sprintf("%d", lastApplied.DatabaseInfraID)
+			resp["db_instance_id"] = lastApplied.DBInstanceID
+			resp["aws_region"] = lastApplied.AWSRegion
+		} else {
+			resp["cloudsql_instance_name"] = lastApplied.CloudSQLInstanceName
+			resp["gcp_region"] = lastApplied.GCPRegion
+		}
+
 		return resp
 	}
 
@@ -172,3 +244,37 @@ func ParseUniqueName(workspaceID string) (string, uint, uint, error) {
 
 	return strArr[0], uint(projID), uint(infraID), nil
 }
+
+// InfraResource is a resource managed by the Terraform module of an infra. The set of
+// resources for an infra is materialized from the resource stream of the infra whenever
+// an operation finishes.
+type InfraResource struct {
+	gorm.Model
+
+	InfraID uint `gorm:"index"`
+
+	// The Terraform address of the resource
+	Addr string
+
+	ResourceType string
+	ResourceName string
+	Provider     string
+
+	Status types.InfraResourceStatus
+
+	// The error summary if the last operation on the resource failed
+	Error string
+}
+
+// ToInfraResourceType generates an external InfraResource to be shared over REST
+func (r *InfraResource) ToInfraResourceType() *types.InfraResource {
+	return &types.InfraResource{
+		Addr:         r.Addr,
+		ResourceType: r.ResourceType,
+		ResourceName: r.ResourceName,
+		Provider:     r.Provider,
+		Status:       r.Status,
+		Error:        r.Error,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
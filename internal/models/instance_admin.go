@@ -0,0 +1,58 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// InstanceQuota stores the quotas that instance admins set for every project and user
+// of the instance. Quotas of 0 are unlimited.
+type InstanceQuota struct {
+	gorm.Model
+
+	MaxProjectsPerUser    uint
+	MaxClustersPerProject uint
+	MaxUsersPerProject    uint
+}
+
+func (q *InstanceQuota) ToInstanceQuotaType() *types.InstanceQuota {
+	return &types.InstanceQuota{
+		MaxProjectsPerUser:    q.MaxProjectsPerUser,
+		MaxClustersPerProject: q.MaxClustersPerProject,
+		MaxUsersPerProject:    q.MaxUsersPerProject,
+	}
+}
+
+// WhitelistedUser is a user that does not count toward usage limits, in addition to the
+// users that are whitelisted through the environment
+type WhitelistedUser struct {
+	gorm.Model
+
+	UserID uint `gorm:"unique"`
+}
+
+// AdminAuditLog records an action of an instance admin, such as impersonating a user
+type AdminAuditLog struct {
+	gorm.Model
+
+	AdminUserID uint
+	Action      types.AdminAuditAction
+
+	// The user or project that the action targets, if any
+	TargetUserID    uint
+	TargetProjectID uint
+
+	Details string
+}
+
+func (a *AdminAuditLog) ToAdminAuditLogType() *types.AdminAuditLog {
+	return &types.AdminAuditLog{
+		ID:              a.ID,
+		CreatedAt:       a.CreatedAt,
+		AdminUserID:     a.AdminUserID,
+		Action:          a.Action,
+		TargetUserID:    a.TargetUserID,
+		TargetProjectID: a.TargetProjectID,
+		Details:         a.Details,
+	}
+}
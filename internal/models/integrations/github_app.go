@@ -1,6 +1,8 @@
 package integrations
 
 import (
+	"time"
+
 	"github.com/porter-dev/porter/api/types"
 	"gorm.io/gorm"
 )
@@ -15,6 +17,12 @@ type GithubAppInstallation struct {
 
 	// Installation ID (used for authentication)
 	InstallationID int64 `json:"installation_id"`
+
+	// The cached installation access token and its expiry, so that tokens are shared
+	// between instances of the server instead of requested from GitHub by each instance.
+	// The token is encrypted before it is written to the DB.
+	AccessToken       []byte     `json:"-"`
+	AccessTokenExpiry *time.Time `json:"-"`
 }
 
 func (r *GithubAppInstallation) ToGitInstallationType() *types.GitInstallation {
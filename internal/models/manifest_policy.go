@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// ManifestPolicy is a Rego policy that is evaluated against the rendered manifests of
+// every release of a project
+type ManifestPolicy struct {
+	gorm.Model
+
+	ProjectID uint `gorm:"index"`
+
+	Name        string
+	Description string
+
+	// Rego is the source of the policy, without a package declaration
+	Rego string
+}
+
+func (m *ManifestPolicy) ToManifestPolicyType() *types.ManifestPolicy {
+	return &types.ManifestPolicy{
+		ID:          m.ID,
+		ProjectID:   m.ProjectID,
+		Name:        m.Name,
+		Description: m.Description,
+		Rego:        m.Rego,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// Pipeline is an ordered list of environments that a release is promoted through
+type Pipeline struct {
+	gorm.Model
+
+	ProjectID         uint
+	Name              string
+	RequiredApprovals uint
+
+	Stages []PipelineStage
+}
+
+func (p *Pipeline) ToPipelineType() *types.Pipeline {
+	stages := make([]*types.PipelineStage, 0)
+
+	for _, stage := range p.Stages {
+		stages = append(stages, stage.ToPipelineStageType())
+	}
+
+	return &types.Pipeline{
+		ID:                p.ID,
+		ProjectID:         p.ProjectID,
+		Name:              p.Name,
+		RequiredApprovals: p.RequiredApprovals,
+		Stages:            stages,
+	}
+}
+
+// PipelineStage is a release in a namespace of a cluster. Stages are ordered by Position.
+type PipelineStage struct {
+	gorm.Model
+
+	PipelineID  uint
+	Position    uint
+	Name        string
+	ClusterID   uint
+	Namespace   string
+	ReleaseName string
+}
+
+func (s *PipelineStage) ToPipelineStageType() *types.PipelineStage {
+	return &types.PipelineStage{
+		ID:          s.ID,
+		Name:        s.Name,
+		ClusterID:   s.ClusterID,
+		Namespace:   s.Namespace,
+		ReleaseName: s.ReleaseName,
+	}
+}
+
+// PipelinePromotion is a request to promote a revision of the release in a stage to the
+// next stage
+type PipelinePromotion struct {
+	gorm.Model
+
+	PipelineID        uint
+	FromStageID       uint
+	ToStageID         uint
+	Status            types.PromotionStatus
+	RequestedByUserID uint
+
+	Revision     int
+	ChartVersion string
+	ImageTag     string
+	Error        string
+
+	Approvals []PipelinePromotionApproval `gorm:"foreignKey:PromotionID"`
+}
+
+func (p *PipelinePromotion) ToPipelinePromotionType() *types.PipelinePromotion {
+	approvals := make([]*types.PipelinePromotionApproval, 0)
+
+	for _, approval := range p.Approvals {
+		approvals = append(approvals, &types.PipelinePromotionApproval{
+			UserID:    approval.UserID,
+			CreatedAt: approval.CreatedAt,
+		})
+	}
+
+	return &types.PipelinePromotion{
+		ID:           p.ID,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+		PipelineID:   p.PipelineID,
+		FromStageID:  p.FromStageID,
+		ToStageID:    p.ToStageID,
+		Status:       p.Status,
+		RequestedBy:  p.RequestedByUserID,
+		Revision:     p.Revision,
+		ChartVersion: p.ChartVersion,
+		ImageTag:     p.ImageTag,
+		Error:        p.Error,
+		Approvals:    approvals,
+	}
+}
+
+// PipelinePromotionApproval records a user approving a promotion
+type PipelinePromotionApproval struct {
+	gorm.Model
+
+	PromotionID uint
+	UserID      uint
+}
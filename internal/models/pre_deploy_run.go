@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// PreDeployRun is a run of the pre-deploy command of a release, which is run as a job in
+// the background before the upgrade or rollback that started it is applied
+type PreDeployRun struct {
+	gorm.Model
+
+	ProjectID uint
+	ClusterID uint
+	Namespace string
+	Name      string
+
+	Image   string
+	Command string
+
+	Status  types.PreDeployRunStatus
+	JobName string
+
+	// Logs are the last lines of the logs of the command
+	Logs string
+
+	Error      string
+	FinishedAt *time.Time
+}
+
+func (run *PreDeployRun) ToPreDeployRunType() *types.PreDeployRun {
+	return &types.PreDeployRun{
+		ID:         run.ID,
+		CreatedAt:  run.CreatedAt,
+		Namespace:  run.Namespace,
+		Name:       run.Name,
+		Image:      run.Image,
+		Command:    run.Command,
+		Status:     run.Status,
+		JobName:    run.JobName,
+		Logs:       run.Logs,
+		Error:      run.Error,
+		FinishedAt: run.FinishedAt,
+	}
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"strings"
+
+	"github.com/docker/distribution/reference"
 	"gorm.io/gorm"
 
 	"github.com/porter-dev/porter/api/types"
@@ -58,6 +61,22 @@ type Project struct {
 
 	PreviewEnvsEnabled  bool
 	RDSDatabasesEnabled bool
+
+	// CLIVersion pins the version of the CLI that is used with the project. The CLI warns
+	// users of other versions, and installs the pinned version when updated.
+	CLIVersion string
+
+	// AllowedRegistries is a comma-separated list of the registries that the images of
+	// releases may be pulled from, or empty to allow every registry
+	AllowedRegistries string
+
+	// ImageSigningEnforced blocks the deploys of images that are not signed by an image
+	// signing authority of the project
+	ImageSigningEnforced bool
+
+	// SSOEnforced requires the members of the project to sign in through the SSO provider
+	// of the instance
+	SSOEnforced bool
 }
 
 // ToProjectType generates an external types.Project to be shared over REST
@@ -68,11 +87,61 @@ func (p *Project) ToProjectType() *types.Project {
 		roles = append(roles, role.ToRoleType())
 	}
 
-	return &types.Project{
+	res := &types.Project{
 		ID:                  p.ID,
 		Name:                p.Name,
 		Roles:               roles,
 		PreviewEnvsEnabled:  p.PreviewEnvsEnabled,
 		RDSDatabasesEnabled: p.RDSDatabasesEnabled,
+		CLIVersion:          p.CLIVersion,
+
+		ImageSigningEnforced: p.ImageSigningEnforced,
+		SSOEnforced:          p.SSOEnforced,
+	}
+
+	if allowed := p.GetAllowedRegistries(); len(allowed) > 0 {
+		res.AllowedRegistries = allowed
+	}
+
+	return res
+}
+
+// GetAllowedRegistries returns the registries that the images of releases may be pulled
+// from, or an empty list if every registry is allowed
+func (p *Project) GetAllowedRegistries() []string {
+	res := make([]string, 0)
+
+	for _, registry := range strings.Split(p.AllowedRegistries, ",") {
+		if registry != "" {
+			res = append(res, registry)
+		}
+	}
+
+	return res
+}
+
+// IsImageAllowed returns true if an image is pulled from an allowed registry of the
+// project. Allowed registries are a registry host, such as gcr.io, or a host with a path
+// prefix, such as gcr.io/my-project. Images without a host are Docker Hub images, which
+// are matched as docker.io/library/<image> for official images.
+func (p *Project) IsImageAllowed(image string) bool {
+	allowed := p.GetAllowedRegistries()
+
+	if len(allowed) == 0 {
+		return true
 	}
+
+	name := image
+
+	if named, err := reference.ParseNormalizedNamed(image); err == nil {
+		name = named.Name()
+	}
+
+	for _, registry := range allowed {
+		if name == registry || strings.HasPrefix(name, registry+"/") {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// ProvisionedResource holds the fields that are shared by the resources that are
+// provisioned through an infra and accessed with generated credentials, which are
+// synced to an env group of the namespace that the resource was provisioned from
+type ProvisionedResource struct {
+	gorm.Model
+
+	ProjectID uint `json:"project_id"`
+	ClusterID uint `json:"cluster_id"`
+	InfraID   uint `json:"infra_id"`
+
+	Kind   types.InfraKind `json:"kind"`
+	Name   string          `json:"name"`
+	Region string          `json:"region"`
+	Status string          `json:"status"`
+
+	Namespace    string `json:"namespace"`
+	EnvGroupName string `json:"env_group_name"`
+
+	// ------------------------------------------------------------------
+	// All fields encrypted before storage.
+	// ------------------------------------------------------------------
+
+	// The access keys of the IAM user of an AWS resource
+	AWSAccessKeyID     []byte `json:"aws_access_key_id"`
+	AWSSecretAccessKey []byte `json:"aws_secret_access_key"`
+
+	// The key data of the service account of a GCP resource
+	GCPKeyData []byte `json:"gcp_key_data"`
+}
+
+// GetProvisionedResource returns the shared fields of a resource, which lets buckets and
+// queues be read and written through the same repository helpers
+func (r *ProvisionedResource) GetProvisionedResource() *ProvisionedResource {
+	return r
+}
+
+// ProvisionedResourceModel is a model that embeds ProvisionedResource
+type ProvisionedResourceModel interface {
+	GetProvisionedResource() *ProvisionedResource
+}
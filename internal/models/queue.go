@@ -0,0 +1,62 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+)
+
+// Queue is a message queue provisioned through an infra, along with the credentials that
+// can consume from and publish to the queue
+type Queue struct {
+	ProvisionedResource
+
+	// URL is the URL of an SQS queue
+	URL string `json:"url"`
+
+	// GCPProjectID and SubscriptionName are the project and subscription of a Pub/Sub
+	// topic, where the name of the queue is the name of the topic
+	GCPProjectID     string `json:"gcp_project_id"`
+	SubscriptionName string `json:"subscription_name"`
+}
+
+func (q *Queue) ToQueueType() *types.Queue {
+	return &types.Queue{
+		ID:               q.ID,
+		ProjectID:        q.ProjectID,
+		ClusterID:        q.ClusterID,
+		InfraID:          q.InfraID,
+		Kind:             q.Kind,
+		Name:             q.Name,
+		Region:           q.Region,
+		URL:              q.URL,
+		SubscriptionName: q.SubscriptionName,
+		Namespace:        q.Namespace,
+		EnvGroupName:     q.EnvGroupName,
+		Status:           q.Status,
+		ScalerTrigger:    q.ToScalerTrigger(),
+	}
+}
+
+// ToScalerTrigger returns the trigger that scales a release on the depth of the queue,
+// which reads the credentials of the queue from the env group of the queue
+func (q *Queue) ToScalerTrigger() *types.ScalerTrigger {
+	switch q.Kind {
+	case types.InfraSQS:
+		return &types.ScalerTrigger{
+			Type:                   types.ScalerTriggerSQS,
+			Target:                 types.DefaultQueueScalerTarget,
+			QueueURL:               q.URL,
+			AWSRegion:              q.Region,
+			AccessKeyIDFromEnv:     types.SQSAccessKeyIDEnv,
+			SecretAccessKeyFromEnv: types.SQSSecretAccessKeyEnv,
+		}
+	case types.InfraPubSub:
+		return &types.ScalerTrigger{
+			Type:               types.ScalerTriggerPubSub,
+			Target:             types.DefaultQueueScalerTarget,
+			SubscriptionName:   q.SubscriptionName,
+			CredentialsFromEnv: types.PubSubCredentialsEnv,
+		}
+	}
+
+	return nil
+}
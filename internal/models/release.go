@@ -1,6 +1,8 @@
 package models
 
 import (
+	"strings"
+
 	"github.com/porter-dev/porter/api/types"
 	"gorm.io/gorm"
 )
@@ -25,13 +27,58 @@ type Release struct {
 	EventContainer     uint
 	NotificationConfig uint
 	BuildConfig        uint
+	HealthGateConfig   uint
+
+	// Paused is true if the release was scaled down by a pause. PausedState stores the
+	// replica counts and suspended cronjobs to restore when the release is resumed.
+	Paused      bool
+	PausedState []byte
+
+	// Protected releases require approval from a second user for upgrades and deletions
+	Protected bool
+
+	// DeletionProtection prevents the release from being deleted until it is disabled
+	DeletionProtection bool
+
+	// RestartOnEnvChange adds a checksum of the configmaps and secrets that the pods of
+	// the release read to their pod templates, so that pods are rolled when those change
+	RestartOnEnvChange bool
+
+	// Dependencies is a comma-separated list of the releases that the release depends on,
+	// of the form name for releases in the same namespace or namespace/name
+	Dependencies string
+
+	// PreDeployCommand is run as a job with the new image of the release before each
+	// upgrade that changes the image, such as to run database migrations
+	PreDeployCommand string
+
+	// LongLivedConnections applies the ingress timeouts and buffering settings of the
+	// ingress controller of the cluster for websockets and server-sent events
+	LongLivedConnections bool
+
+	// KustomizePatches are multi-document YAML strategic merge patches, which are applied
+	// to the rendered manifests of the release as a kustomize overlay
+	KustomizePatches string
+
+	// ImageSBOMID is the dependency inventory of the image that the release runs, which
+	// is updated after each upgrade or rollback of the release. It is 0 if the image has
+	// no inventory.
+	ImageSBOMID uint
 }
 
 func (r *Release) ToReleaseType() *types.PorterRelease {
 	res := &types.PorterRelease{
-		ID:           r.ID,
-		WebhookToken: r.WebhookToken,
-		ImageRepoURI: r.ImageRepoURI,
+		ID:                   r.ID,
+		WebhookToken:         r.WebhookToken,
+		ImageRepoURI:         r.ImageRepoURI,
+		Paused:               r.Paused,
+		Protected:            r.Protected,
+		DeletionProtection:   r.DeletionProtection,
+		RestartOnEnvChange:   r.RestartOnEnvChange,
+		Dependencies:         r.GetDependencies(),
+		PreDeployCommand:     r.PreDeployCommand,
+		LongLivedConnections: r.LongLivedConnections,
+		KustomizePatches:     r.KustomizePatches,
 	}
 
 	if r.GitActionConfig != nil {
@@ -40,3 +87,16 @@ func (r *Release) ToReleaseType() *types.PorterRelease {
 
 	return res
 }
+
+// GetDependencies returns the dependencies declared for the release
+func (r *Release) GetDependencies() []string {
+	res := make([]string, 0)
+
+	for _, dep := range strings.Split(r.Dependencies, ",") {
+		if dep != "" {
+			res = append(res, dep)
+		}
+	}
+
+	return res
+}
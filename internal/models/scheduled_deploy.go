@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// ScheduledDeploy is an upgrade of a release that is stored until its run time, and is
+// then run by the scheduled deploy runner
+type ScheduledDeploy struct {
+	gorm.Model
+
+	ProjectID uint
+	ClusterID uint
+	Namespace string
+	Name      string
+
+	Status types.ScheduledDeployStatus
+	RunAt  time.Time
+
+	Values       string
+	ChartVersion string
+	Patch        string
+	PatchType    types.ValuesPatchType
+	ImageTag     string
+	Message      string
+
+	ScheduledByUserID uint
+
+	// ClaimedAt is when a server replica claimed the deploy to run it. Deploys that are
+	// still running long after they were claimed are failed, since the replica that
+	// claimed them stopped.
+	ClaimedAt *time.Time
+
+	ExecutedAt *time.Time
+	Error      string
+}
+
+func (sd *ScheduledDeploy) ToScheduledDeployType() *types.ScheduledDeploy {
+	return &types.ScheduledDeploy{
+		ID:           sd.ID,
+		CreatedAt:    sd.CreatedAt,
+		UpdatedAt:    sd.UpdatedAt,
+		Namespace:    sd.Namespace,
+		Name:         sd.Name,
+		Status:       sd.Status,
+		RunAt:        sd.RunAt,
+		ChartVersion: sd.ChartVersion,
+		ImageTag:     sd.ImageTag,
+		HasValues:    sd.Values != "",
+		HasPatch:     sd.Patch != "",
+		Message:      sd.Message,
+		ScheduledBy:  sd.ScheduledByUserID,
+		ExecutedAt:   sd.ExecutedAt,
+		Error:        sd.Error,
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// SensitiveValues are the values of a revision of a release at the paths that its chart
+// marks as sensitive. The values of the release only contain placeholders for them, and
+// they are substituted when the release is rendered.
+type SensitiveValues struct {
+	gorm.Model
+
+	ProjectID uint
+	ClusterID uint
+	Namespace string
+	Name      string
+
+	// Revision is the revision of the release that the values were deployed with, so that
+	// the values of a revision are restored when the release is rolled back to it
+	Revision int
+
+	// Values are stored as JSON keyed by values path, and are encrypted before they are
+	// written to the DB
+	Values []byte
+}
+
+// GetValues returns the unmarshaled sensitive values, keyed by values path
+func (s *SensitiveValues) GetValues() (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+
+	if len(s.Values) == 0 {
+		return res, nil
+	}
+
+	if err := json.Unmarshal(s.Values, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// ServerSetting overrides a setting of the server from the environment at runtime
+type ServerSetting struct {
+	gorm.Model
+
+	Key   types.ServerSettingKey `gorm:"unique"`
+	Value string
+
+	// UpdatedBy is the id of the instance admin that last set the setting
+	UpdatedBy uint
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// StaleRelease tracks a release that has not been deployed for the stale period of the
+// stale release check. The release is stale once it has also been scaled down for the
+// stale period and, if its traffic is checked, received no traffic in the period. The
+// row is deleted when the release is deployed again or deleted.
+type StaleRelease struct {
+	gorm.Model
+
+	ProjectID uint
+	ClusterID uint
+	Namespace string
+	Name      string
+
+	LastDeployedAt time.Time
+
+	// ScaledDownSince is the first check at which all of the workloads of the release
+	// had no replicas, if they still have no replicas
+	ScaledDownSince *time.Time
+
+	// TrafficChecked is true if the cluster has metrics for the ingress traffic of the
+	// release, and NoTraffic is true if the release received no requests in the period
+	TrafficChecked bool
+	NoTraffic      bool
+
+	// Reasons is a comma-separated list of the reasons that the release is stale, which
+	// is empty if the release is not stale yet
+	Reasons    string
+	StaleSince *time.Time
+	NotifiedAt *time.Time
+}
+
+func (s *StaleRelease) IsStale() bool {
+	return s.StaleSince != nil
+}
+
+func (s *StaleRelease) ToStaleReleaseType() *types.StaleRelease {
+	res := &types.StaleRelease{
+		ClusterID:       s.ClusterID,
+		Namespace:       s.Namespace,
+		Name:            s.Name,
+		LastDeployedAt:  s.LastDeployedAt,
+		ScaledDownSince: s.ScaledDownSince,
+		TrafficChecked:  s.TrafficChecked,
+		Reasons:         make([]types.StaleReason, 0),
+		StaleSince:      s.StaleSince,
+		LastCheckedAt:   s.UpdatedAt,
+	}
+
+	if s.Reasons != "" {
+		for _, reason := range strings.Split(s.Reasons, ",") {
+			res.Reasons = append(res.Reasons, types.StaleReason(reason))
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"gorm.io/gorm"
+)
+
+// TemplateRepo is a Helm repo registered by a project, whose charts are only listed as
+// templates for that project
+type TemplateRepo struct {
+	gorm.Model
+
+	ProjectID uint
+	Name      string
+	RepoURL   string
+}
+
+func (t *TemplateRepo) ToTemplateRepoType() *types.TemplateRepo {
+	return &types.TemplateRepo{
+		ID:        t.ID,
+		CreatedAt: t.CreatedAt,
+		ProjectID: t.ProjectID,
+		Name:      t.Name,
+		RepoURL:   t.RepoURL,
+	}
+}
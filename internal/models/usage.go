@@ -25,6 +25,18 @@ type ProjectUsage struct {
 
 	// The number of users
 	Users uint
+
+	// Whether the plan includes preview environments and SSO
+	PreviewEnvironments bool
+	SSO                 bool
+
+	// When the trial of the plan ends, if the plan is a trial
+	TrialEndsAt *time.Time
+}
+
+// IsTrialExpired returns true if the plan is a trial that has ended
+func (p *ProjectUsage) IsTrialExpired() bool {
+	return p.TrialEndsAt != nil && p.TrialEndsAt.Before(time.Now())
 }
 
 // ToProjectUsageType converts the project usage model to a project usage API type
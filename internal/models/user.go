@@ -19,6 +19,9 @@ type User struct {
 	// The github user id used for login (optional)
 	GithubUserID int64
 	GoogleUserID string
+
+	// InstanceAdmin users can manage all projects and users of the instance
+	InstanceAdmin bool
 }
 
 // ToUserType generates an external types.User to be shared over REST
@@ -0,0 +1,248 @@
+// Package predeploy runs the pre-deploy command of a release, such as a database
+// migration, as a job with the new image of the release before the release is upgraded
+package predeploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/internal/helm/grapher"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"helm.sh/helm/v3/pkg/release"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultTimeout is how long the pre-deploy command can run before the job is failed
+	DefaultTimeout = 30 * time.Minute
+
+	// pollInterval is the interval at which the status of the job is checked
+	pollInterval = 2 * time.Second
+
+	// logTailLines is the number of log lines of the job that are returned
+	logTailLines = 100
+
+	// jobTTL is how long finished jobs are kept, so that their logs can be read
+	jobTTL = 24 * time.Hour
+
+	// LabelRelease is set to the name of the release on pre-deploy jobs
+	LabelRelease = "porter.run/pre-deploy"
+)
+
+// Runner runs the pre-deploy command of a release
+type Runner struct {
+	K8sAgent *kubernetes.Agent
+	Timeout  time.Duration
+}
+
+// Result is the outcome of a pre-deploy command
+type Result struct {
+	JobName string
+
+	// Logs are the last lines of the logs of the command
+	Logs string
+}
+
+// Error is returned when the pre-deploy command did not succeed
+type Error struct {
+	Result *Result
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Result == nil || e.Result.Logs == "" {
+		return fmt.Sprintf("pre-deploy command failed: %s", e.Reason)
+	}
+
+	return fmt.Sprintf("pre-deploy command failed: %s. Logs:\n%s", e.Reason, e.Result.Logs)
+}
+
+// Run runs the command as a job with the pod template of the current version of the
+// release and the given image, and blocks until the job has succeeded or failed. The
+// job gets the env, volumes and service account of the release, so the command runs
+// in the same environment as the release.
+func (r *Runner) Run(helmRelease *release.Release, image, command string) (*Result, error) {
+	template, err := r.getPodTemplate(helmRelease)
+
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := r.Timeout
+
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	job := newJob(helmRelease, template, image, command, timeout)
+
+	job, err = r.K8sAgent.Clientset.BatchV1().Jobs(helmRelease.Namespace).Create(
+		context.Background(),
+		job,
+		metav1.CreateOptions{},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create pre-deploy job: %w", err)
+	}
+
+	res := &Result{
+		JobName: job.Name,
+	}
+
+	reason := r.wait(job, timeout)
+
+	res.Logs = r.getLogs(job)
+
+	if reason != "" {
+		return res, &Error{Result: res, Reason: reason}
+	}
+
+	return res, nil
+}
+
+// getPodTemplate returns the pod template of the first controller of the release
+func (r *Runner) getPodTemplate(helmRelease *release.Release) (*v1.PodTemplateSpec, error) {
+	yamlArr := grapher.ImportMultiDocYAML([]byte(helmRelease.Manifest))
+
+	for _, c := range grapher.ParseControllers(yamlArr) {
+		c.Namespace = helmRelease.Namespace
+
+		switch c.Kind {
+		case "Deployment":
+			if obj, err := r.K8sAgent.GetDeployment(c); err == nil {
+				return &obj.Spec.Template, nil
+			}
+		case "StatefulSet":
+			if obj, err := r.K8sAgent.GetStatefulSet(c); err == nil {
+				return &obj.Spec.Template, nil
+			}
+		case "Job":
+			if obj, err := r.K8sAgent.GetJob(c); err == nil {
+				return &obj.Spec.Template, nil
+			}
+		case "CronJob":
+			if obj, err := r.K8sAgent.GetCronJob(c); err == nil {
+				return &obj.Spec.JobTemplate.Spec.Template, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no controller found for release %s to run the pre-deploy command with", helmRelease.Name)
+}
+
+func newJob(
+	helmRelease *release.Release,
+	template *v1.PodTemplateSpec,
+	image, command string,
+	timeout time.Duration,
+) *batchv1.Job {
+	spec := template.Spec.DeepCopy()
+
+	// the first container is the container of the app, and sidecars are not run for the
+	// command, as they would keep the job from completing
+	container := spec.Containers[0]
+	container.Image = image
+	container.Command = []string{"sh", "-c", command}
+	container.Args = nil
+	container.Ports = nil
+	container.LivenessProbe = nil
+	container.ReadinessProbe = nil
+	container.StartupProbe = nil
+	container.Lifecycle = nil
+
+	spec.Containers = []v1.Container{container}
+	spec.RestartPolicy = v1.RestartPolicyNever
+
+	backoffLimit := int32(0)
+	deadline := int64(timeout.Seconds())
+	ttl := int32(jobTTL.Seconds())
+
+	// the labels of the release are not copied, so that the pod of the job is not
+	// selected by the services and controllers of the release
+	labels := map[string]string{
+		LabelRelease: helmRelease.Name,
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-pre-deploy-", helmRelease.Name),
+			Namespace:    helmRelease.Namespace,
+			Labels:       labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   &deadline,
+			TTLSecondsAfterFinished: &ttl,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: template.Annotations,
+				},
+				Spec: *spec,
+			},
+		},
+	}
+}
+
+// wait blocks until the job has finished, and returns the reason that it failed if it did
+func (r *Runner) wait(job *batchv1.Job, timeout time.Duration) string {
+	// the job is failed by its deadline, so the wait only needs to outlast it
+	deadline := time.Now().Add(timeout + time.Minute)
+
+	for time.Now().Before(deadline) {
+		curr, err := r.K8sAgent.Clientset.BatchV1().Jobs(job.Namespace).Get(
+			context.Background(),
+			job.Name,
+			metav1.GetOptions{},
+		)
+
+		if err == nil {
+			if curr.Status.Succeeded > 0 {
+				return ""
+			}
+
+			for _, cond := range curr.Status.Conditions {
+				if cond.Type == batchv1.JobFailed && cond.Status == v1.ConditionTrue {
+					if cond.Message != "" {
+						return cond.Message
+					}
+
+					return cond.Reason
+				}
+			}
+		}
+
+		time.Sleep(pollInterval)
+	}
+
+	return fmt.Sprintf("job %s did not finish within %s", job.Name, timeout.String())
+}
+
+// getLogs returns the last lines of the logs of the pods of the job
+func (r *Runner) getLogs(job *batchv1.Job) string {
+	pods, err := r.K8sAgent.GetJobPods(job.Namespace, job.Name)
+
+	if err != nil || len(pods) == 0 {
+		return ""
+	}
+
+	tailLines := int64(logTailLines)
+	logs := make([]string, 0)
+
+	for _, pod := range pods {
+		raw, err := r.K8sAgent.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{
+			TailLines: &tailLines,
+		}).DoRaw(context.Background())
+
+		if err == nil {
+			logs = append(logs, strings.TrimRight(string(raw), "\n"))
+		}
+	}
+
+	return strings.Join(logs, "\n")
+}
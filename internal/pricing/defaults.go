@@ -0,0 +1,101 @@
+package pricing
+
+import "github.com/porter-dev/porter/api/types"
+
+// DefaultTables returns the built-in pricing tables, which are the on-demand list prices
+// of us-east-1 on AWS, us-central1 on GCP and all regions on DigitalOcean
+func DefaultTables() *Tables {
+	return &Tables{
+		AWS: &ProviderTable{
+			ClusterMonthly: 0.10 * HoursPerMonth,
+
+			// a NAT gateway for the private subnets of the cluster
+			NetworkingMonthly: 0.045 * HoursPerMonth,
+
+			DefaultMachineType: "t2.medium",
+			DefaultNodeCount:   3,
+			Instances: map[string]float64{
+				"t2.medium":  0.0464 * HoursPerMonth,
+				"t2.large":   0.0928 * HoursPerMonth,
+				"t2.xlarge":  0.1856 * HoursPerMonth,
+				"t2.2xlarge": 0.3712 * HoursPerMonth,
+				"t3.medium":  0.0416 * HoursPerMonth,
+				"t3.large":   0.0832 * HoursPerMonth,
+				"t3.xlarge":  0.1664 * HoursPerMonth,
+				"t3.2xlarge": 0.3328 * HoursPerMonth,
+				"m5.large":   0.096 * HoursPerMonth,
+				"m5.xlarge":  0.192 * HoursPerMonth,
+				"m5.2xlarge": 0.384 * HoursPerMonth,
+				"c5.large":   0.085 * HoursPerMonth,
+				"c5.xlarge":  0.17 * HoursPerMonth,
+				"c5.2xlarge": 0.34 * HoursPerMonth,
+				"r5.large":   0.126 * HoursPerMonth,
+				"r5.xlarge":  0.252 * HoursPerMonth,
+			},
+			SpotMultiplier: 0.3,
+			Databases: map[string]float64{
+				"db.t3.micro":   0.017 * HoursPerMonth,
+				"db.t3.small":   0.034 * HoursPerMonth,
+				"db.t3.medium":  0.068 * HoursPerMonth,
+				"db.t3.large":   0.136 * HoursPerMonth,
+				"db.m5.large":   0.171 * HoursPerMonth,
+				"db.m5.xlarge":  0.342 * HoursPerMonth,
+				"db.m5.2xlarge": 0.684 * HoursPerMonth,
+				"db.r5.large":   0.24 * HoursPerMonth,
+				"db.r5.xlarge":  0.48 * HoursPerMonth,
+			},
+			StorageGBMonthly: 0.115,
+		},
+		GCP: &ProviderTable{
+			ClusterMonthly: 0.10 * HoursPerMonth,
+
+			// the forwarding rule of the load balancer of the ingress controller
+			NetworkingMonthly: 0.025 * HoursPerMonth,
+
+			DefaultMachineType: types.DefaultGKEMachineType,
+			DefaultNodeCount:   3,
+			Instances: map[string]float64{
+				"e2-medium":      0.0335 * HoursPerMonth,
+				"e2-standard-2":  0.067 * HoursPerMonth,
+				"e2-standard-4":  0.134 * HoursPerMonth,
+				"e2-standard-8":  0.268 * HoursPerMonth,
+				"n1-standard-1":  0.0475 * HoursPerMonth,
+				"n1-standard-2":  0.095 * HoursPerMonth,
+				"n1-standard-4":  0.19 * HoursPerMonth,
+				"n2-standard-2":  0.0971 * HoursPerMonth,
+				"n2-standard-4":  0.1942 * HoursPerMonth,
+				"n2-standard-8":  0.3885 * HoursPerMonth,
+				"c2-standard-4":  0.2088 * HoursPerMonth,
+				"c2-standard-8":  0.4176 * HoursPerMonth,
+				"n2-highmem-2":   0.131 * HoursPerMonth,
+				"n2-highmem-4":   0.262 * HoursPerMonth,
+				"e2-highmem-2":   0.0904 * HoursPerMonth,
+				"e2-highcpu-4":   0.0989 * HoursPerMonth,
+				"e2-standard-16": 0.536 * HoursPerMonth,
+			},
+			SpotMultiplier: 0.3,
+		},
+		DO: &ProviderTable{
+			// the control plane of DOKS clusters is free
+			ClusterMonthly: 0,
+
+			// the load balancer of the ingress controller
+			NetworkingMonthly: 12,
+
+			DefaultMachineType: "s-2vcpu-4gb",
+			DefaultNodeCount:   3,
+			Instances: map[string]float64{
+				"s-1vcpu-2gb":  12,
+				"s-2vcpu-2gb":  18,
+				"s-2vcpu-4gb":  24,
+				"s-4vcpu-8gb":  48,
+				"s-8vcpu-16gb": 96,
+			},
+			RegistryTiers: map[string]float64{
+				"starter":      0,
+				"basic":        5,
+				"professional": 20,
+			},
+		},
+	}
+}
@@ -0,0 +1,242 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// Estimate returns the estimated monthly cost of an infra kind from the body of its
+// provisioning request. The last-applied config of an infra can be passed as well, since
+// it is a superset of the provisioning request.
+func (e *Estimator) Estimate(kind types.InfraKind, config []byte) (*types.CostEstimate, error) {
+	tables := e.Tables()
+	b := newEstimateBuilder()
+
+	var err error
+
+	switch kind {
+	case types.InfraEKS:
+		err = b.estimateEKS(tables.AWS, config)
+	case types.InfraGKE:
+		err = b.estimateGKE(tables.GCP, config)
+	case types.InfraDOKS:
+		b.estimateNodes(tables.DO, "DOKS", tables.DO.DefaultMachineType, tables.DO.DefaultNodeCount, false)
+	case types.InfraRDS:
+		err = b.estimateRDS(tables.AWS, config)
+	case types.InfraDOCR:
+		err = b.estimateDOCR(tables.DO, config)
+	case types.InfraECR, types.InfraGCR:
+		b.note("the registry is billed by the storage of images and by data transfer, which are not estimated")
+	case types.InfraS3, types.InfraGCS:
+		b.note("the bucket is billed by storage, requests and data transfer, which are not estimated")
+	case types.InfraSQS, types.InfraPubSub:
+		b.note("the queue is billed by the number of messages, which is not estimated")
+	case types.InfraRDSNetwork, types.InfraCloudSQLNetwork:
+		b.note("the private network is billed by data transfer, which is not estimated")
+	default:
+		return nil, fmt.Errorf("cost estimates are not supported for infra kind %s", kind)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return b.res, nil
+}
+
+type estimateBuilder struct {
+	res *types.CostEstimate
+}
+
+func newEstimateBuilder() *estimateBuilder {
+	return &estimateBuilder{
+		res: &types.CostEstimate{
+			Currency: "USD",
+			Items:    make([]*types.CostEstimateItem, 0),
+		},
+	}
+}
+
+func (b *estimateBuilder) add(name string, quantity uint, unitMonthly float64) {
+	monthly := roundCents(unitMonthly * float64(quantity))
+
+	b.res.Items = append(b.res.Items, &types.CostEstimateItem{
+		Name:        name,
+		Quantity:    quantity,
+		UnitMonthly: roundCents(unitMonthly),
+		Monthly:     monthly,
+	})
+
+	b.res.MonthlyTotal = roundCents(b.res.MonthlyTotal + monthly)
+}
+
+func (b *estimateBuilder) note(format string, a ...interface{}) {
+	b.res.Notes = append(b.res.Notes, fmt.Sprintf(format, a...))
+}
+
+// estimateNodes adds the control plane and networking of a cluster, and a node pool of
+// the given machine type and size
+func (b *estimateBuilder) estimateNodes(table *ProviderTable, clusterName, machineType string, count uint, spot bool) {
+	b.add(fmt.Sprintf("%s control plane", clusterName), 1, table.ClusterMonthly)
+	b.add("networking", 1, table.NetworkingMonthly)
+	b.estimateNodeGroup(table, "nodes", machineType, count, spot)
+}
+
+func (b *estimateBuilder) estimateNodeGroup(table *ProviderTable, name, machineType string, count uint, spot bool) {
+	price, ok := table.Instances[machineType]
+
+	if !ok {
+		b.note("no price is known for machine type %s, so %s are not estimated", machineType, name)
+		return
+	}
+
+	if spot {
+		price = price * table.SpotMultiplier
+		name = fmt.Sprintf("%s (spot)", name)
+	}
+
+	b.add(fmt.Sprintf("%s: %s", name, machineType), count, price)
+}
+
+func (b *estimateBuilder) estimateEKS(table *ProviderTable, config []byte) error {
+	req := &types.CreateEKSInfraRequest{}
+
+	if err := json.Unmarshal(config, req); err != nil {
+		return fmt.Errorf("invalid EKS config: %w", err)
+	}
+
+	if len(req.NodeGroups) == 0 {
+		machineType := req.MachineType
+
+		if machineType == "" {
+			machineType = table.DefaultMachineType
+		}
+
+		b.estimateNodes(table, "EKS", machineType, table.DefaultNodeCount, false)
+		return nil
+	}
+
+	b.add("EKS control plane", 1, table.ClusterMonthly)
+	b.add("networking", 1, table.NetworkingMonthly)
+
+	hasSpot := false
+
+	for _, ng := range req.NodeGroups {
+		count := ng.DesiredSize
+
+		if count == 0 {
+			count = ng.MinSize
+		}
+
+		// node groups with several instance types are estimated with the first type,
+		// since the mix of instances is chosen by EKS
+		if len(ng.InstanceTypes) == 0 {
+			continue
+		}
+
+		b.estimateNodeGroup(table, fmt.Sprintf("node group %s", ng.Name), ng.InstanceTypes[0], count, ng.Spot)
+
+		if req.EnableClusterAutoscaler && ng.MaxSize > count {
+			b.note("node group %s can scale up to %d nodes", ng.Name, ng.MaxSize)
+		}
+
+		hasSpot = hasSpot || ng.Spot
+	}
+
+	if hasSpot {
+		b.note("spot instances are estimated at %d%% of the on-demand price, and their actual price varies", int(table.SpotMultiplier*100))
+	}
+
+	return nil
+}
+
+func (b *estimateBuilder) estimateGKE(table *ProviderTable, config []byte) error {
+	req := &types.CreateGKEInfraRequest{}
+
+	if err := json.Unmarshal(config, req); err != nil {
+		return fmt.Errorf("invalid GKE config: %w", err)
+	}
+
+	req.SetDefaults()
+
+	if req.Autopilot {
+		b.add("GKE control plane", 1, table.ClusterMonthly)
+		b.add("networking", 1, table.NetworkingMonthly)
+		b.note("pods in autopilot clusters are billed by their resource requests, which are not estimated")
+
+		return nil
+	}
+
+	count := table.DefaultNodeCount
+
+	// the node pool of a regional cluster is replicated in each of its zones
+	if req.Regional {
+		zones := uint(len(req.NodeZones))
+
+		if zones == 0 {
+			zones = 3
+		}
+
+		count = count * zones
+	}
+
+	b.estimateNodes(table, "GKE", req.MachineType, count, false)
+
+	return nil
+}
+
+func (b *estimateBuilder) estimateRDS(table *ProviderTable, config []byte) error {
+	req := &types.CreateRDSInfraRequest{}
+
+	if err := json.Unmarshal(config, req); err != nil {
+		return fmt.Errorf("invalid RDS config: %w", err)
+	}
+
+	if price, ok := table.Databases[req.MachineType]; ok {
+		b.add(fmt.Sprintf("database instance: %s", req.MachineType), 1, price)
+	} else {
+		b.note("no price is known for database instance class %s, so the instance is not estimated", req.MachineType)
+	}
+
+	storage, err := strconv.ParseUint(req.DBStorage, 10, 64)
+
+	if err != nil {
+		b.note("the allocated storage of the database is not set, so storage is not estimated")
+		return nil
+	}
+
+	b.add("database storage (GB)", uint(storage), table.StorageGBMonthly)
+
+	if req.DBMaxStorage != "" && req.DBMaxStorage != req.DBStorage {
+		b.note("storage can grow up to %s GB", req.DBMaxStorage)
+	}
+
+	return nil
+}
+
+func (b *estimateBuilder) estimateDOCR(table *ProviderTable, config []byte) error {
+	req := &types.CreateDOCRInfraRequest{}
+
+	if err := json.Unmarshal(config, req); err != nil {
+		return fmt.Errorf("invalid DOCR config: %w", err)
+	}
+
+	price, ok := table.RegistryTiers[req.DOCRSubscriptionTier]
+
+	if !ok {
+		b.note("no price is known for subscription tier %s", req.DOCRSubscriptionTier)
+		return nil
+	}
+
+	b.add(fmt.Sprintf("registry subscription: %s", req.DOCRSubscriptionTier), 1, price)
+
+	return nil
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,113 @@
+package pricing_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/pricing"
+)
+
+func TestEstimateEKS(t *testing.T) {
+	estimator, err := pricing.NewEstimator("", 0)
+
+	if err != nil {
+		t.Fatalf("error creating estimator: %v", err)
+	}
+
+	config, _ := json.Marshal(&types.CreateEKSInfraRequest{
+		EKSName: "cluster",
+		NodeGroups: []*types.EKSNodeGroup{
+			{
+				Name:          "on-demand",
+				InstanceTypes: []string{"t3.medium"},
+				MinSize:       2,
+				MaxSize:       4,
+			},
+			{
+				Name:          "spot",
+				InstanceTypes: []string{"t3.medium"},
+				MaxSize:       4,
+				DesiredSize:   1,
+				Spot:          true,
+			},
+		},
+	})
+
+	res, err := estimator.Estimate(types.InfraEKS, config)
+
+	if err != nil {
+		t.Fatalf("error estimating cost: %v", err)
+	}
+
+	table := pricing.DefaultTables().AWS
+	nodePrice := table.Instances["t3.medium"]
+
+	expected := table.ClusterMonthly + table.NetworkingMonthly + 2*nodePrice + nodePrice*table.SpotMultiplier
+
+	if diff := res.MonthlyTotal - expected; diff > 0.05 || diff < -0.05 {
+		t.Errorf("expected monthly total of %.2f, got %.2f", expected, res.MonthlyTotal)
+	}
+
+	if len(res.Items) != 4 {
+		t.Errorf("expected 4 items, got %d", len(res.Items))
+	}
+}
+
+func TestEstimateUnknownMachineType(t *testing.T) {
+	estimator, _ := pricing.NewEstimator("", 0)
+
+	config, _ := json.Marshal(&types.CreateRDSInfraRequest{
+		MachineType: "db.unknown",
+		DBStorage:   "20",
+	})
+
+	res, err := estimator.Estimate(types.InfraRDS, config)
+
+	if err != nil {
+		t.Fatalf("error estimating cost: %v", err)
+	}
+
+	if len(res.Items) != 1 || len(res.Notes) != 1 {
+		t.Errorf("expected only storage to be estimated, got %d items and %d notes", len(res.Items), len(res.Notes))
+	}
+}
+
+func TestRefreshTables(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&pricing.Tables{
+			DO: &pricing.ProviderTable{
+				DefaultMachineType: "s-2vcpu-4gb",
+				DefaultNodeCount:   2,
+				Instances: map[string]float64{
+					"s-2vcpu-4gb": 10,
+				},
+			},
+		})
+	}))
+
+	defer server.Close()
+
+	estimator, err := pricing.NewEstimator(server.URL, 0)
+
+	if err != nil {
+		t.Fatalf("error creating estimator: %v", err)
+	}
+
+	res, err := estimator.Estimate(types.InfraDOKS, []byte("{}"))
+
+	if err != nil {
+		t.Fatalf("error estimating cost: %v", err)
+	}
+
+	if res.MonthlyTotal != 20 {
+		t.Errorf("expected monthly total of 20 from the fetched tables, got %.2f", res.MonthlyTotal)
+	}
+
+	// providers that are not in the fetched tables keep their default prices
+	if estimator.Tables().AWS == nil {
+		t.Errorf("expected default AWS table to be kept")
+	}
+}
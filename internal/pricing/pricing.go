@@ -0,0 +1,163 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HoursPerMonth is the number of hours that hourly list prices are multiplied by to get
+// a monthly price
+const HoursPerMonth = 730
+
+// Tables are the pricing tables of each cloud provider
+type Tables struct {
+	AWS *ProviderTable `json:"aws,omitempty"`
+	GCP *ProviderTable `json:"gcp,omitempty"`
+	DO  *ProviderTable `json:"do,omitempty"`
+}
+
+// ProviderTable contains the monthly list prices in USD of the resources of a cloud
+// provider that Porter provisions
+type ProviderTable struct {
+	// ClusterMonthly is the fee of the managed control plane of a cluster
+	ClusterMonthly float64 `json:"cluster_monthly"`
+
+	// NetworkingMonthly is the cost of the networking resources that are created along
+	// with a cluster, such as NAT gateways and load balancers
+	NetworkingMonthly float64 `json:"networking_monthly"`
+
+	// DefaultMachineType and DefaultNodeCount are the size of the node pool that is
+	// created when a request does not configure node pools
+	DefaultMachineType string `json:"default_machine_type"`
+	DefaultNodeCount   uint   `json:"default_node_count"`
+
+	// Instances are the prices of node instance types
+	Instances map[string]float64 `json:"instances"`
+
+	// SpotMultiplier is the fraction of the on-demand price that spot instances are
+	// estimated to cost
+	SpotMultiplier float64 `json:"spot_multiplier,omitempty"`
+
+	// Databases are the prices of database instance classes, and StorageGBMonthly is the
+	// price of a GB of database storage
+	Databases        map[string]float64 `json:"databases,omitempty"`
+	StorageGBMonthly float64            `json:"storage_gb_monthly,omitempty"`
+
+	// RegistryTiers are the prices of the subscription tiers of the container registry
+	RegistryTiers map[string]float64 `json:"registry_tiers,omitempty"`
+}
+
+// Estimator estimates the cost of provisioning requests from pricing tables. The tables
+// default to the tables that are built into Porter, and can be refreshed from a URL
+// that serves tables in the same JSON format. Providers that are missing from the
+// fetched tables keep their default prices.
+type Estimator struct {
+	url string
+	ttl time.Duration
+
+	mu         sync.RWMutex
+	tables     *Tables
+	expiresAt  time.Time
+	refreshing bool
+
+	client *http.Client
+}
+
+// NewEstimator creates an estimator with the default tables. If a URL is set, the
+// tables are fetched from the URL, and are refreshed in the background once the TTL has
+// passed. An error is returned if the tables cannot be fetched, in which case the
+// estimator still uses the default tables.
+func NewEstimator(url string, ttl time.Duration) (*Estimator, error) {
+	res := &Estimator{
+		url:    url,
+		ttl:    ttl,
+		tables: DefaultTables(),
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+
+	if url == "" {
+		return res, nil
+	}
+
+	return res, res.Refresh()
+}
+
+// Tables returns the current pricing tables, and starts refreshing the tables in the
+// background if they have expired
+func (e *Estimator) Tables() *Tables {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.url != "" && e.ttl > 0 && !e.refreshing && time.Now().After(e.expiresAt) {
+		e.refreshing = true
+
+		go e.Refresh()
+	}
+
+	return e.tables
+}
+
+// Refresh fetches the pricing tables from the URL of the estimator
+func (e *Estimator) Refresh() error {
+	tables, err := e.fetch()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.refreshing = false
+
+	// failed refreshes are retried after the TTL as well, so that an unavailable URL is
+	// not requested on every estimate
+	e.expiresAt = time.Now().Add(e.ttl)
+
+	if err != nil {
+		return err
+	}
+
+	e.tables = mergeTables(DefaultTables(), tables)
+
+	return nil
+}
+
+func (e *Estimator) fetch() (*Tables, error) {
+	resp, err := e.client.Get(e.url)
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching pricing tables: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching pricing tables: status code %d", resp.StatusCode)
+	}
+
+	tables := &Tables{}
+
+	if err := json.NewDecoder(resp.Body).Decode(tables); err != nil {
+		return nil, fmt.Errorf("error decoding pricing tables: %w", err)
+	}
+
+	return tables, nil
+}
+
+func mergeTables(base, override *Tables) *Tables {
+	if override.AWS != nil {
+		base.AWS = override.AWS
+	}
+
+	if override.GCP != nil {
+		base.GCP = override.GCP
+	}
+
+	if override.DO != nil {
+		base.DO = override.DO
+	}
+
+	return base
+}
@@ -0,0 +1,144 @@
+package redis_stream
+
+import (
+	"context"
+	"encoding/json"
+
+	redis "github.com/go-redis/redis/v8"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// tfLogLine is the subset of a Terraform JSON log line, as written to the resource
+// stream of an infra, that is needed for compaction
+type tfLogLine struct {
+	Message string `json:"@message"`
+	Type    string `json:"type"`
+	Hook    struct {
+		Resource tfResource `json:"resource"`
+		Action   string     `json:"action"`
+	} `json:"hook"`
+}
+
+type tfResource struct {
+	Addr         string `json:"addr"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	Provider     string `json:"implied_provider"`
+	Errored      struct {
+		ErrorSummary string `json:"error_context"`
+	} `json:"errored"`
+}
+
+// CompactResourceStream folds all messages on the resource stream of an infra into the
+// materialized resources of the infra, and then deletes the stream. This is called once
+// an operation on the infra has finished, so the stream only holds entries for live
+// operations.
+func CompactResourceStream(client *redis.Client, repo repository.Repository, infra *models.Infra) error {
+	streamName := infra.GetUniqueName()
+
+	msgs, err := client.XRange(context.Background(), streamName, "-", "+").Result()
+
+	if err != nil {
+		return err
+	}
+
+	existing, err := repo.Infra().ListInfraResources(infra.ID)
+
+	if err != nil {
+		return err
+	}
+
+	resources := make(map[string]*models.InfraResource)
+
+	for _, resource := range existing {
+		resources[resource.Addr] = resource
+	}
+
+	for _, msg := range msgs {
+		dataString, ok := msg.Values["data"].(string)
+
+		if !ok {
+			continue
+		}
+
+		logLine := &tfLogLine{}
+
+		if err := json.Unmarshal([]byte(dataString), logLine); err != nil {
+			continue
+		}
+
+		applyLogLine(resources, logLine)
+	}
+
+	snapshot := make([]*models.InfraResource, 0, len(resources))
+
+	for _, resource := range resources {
+		snapshot = append(snapshot, resource)
+	}
+
+	if err := repo.Infra().UpdateInfraResources(infra.ID, snapshot); err != nil {
+		return err
+	}
+
+	// only delete the entries that were compacted, in case a new operation has started
+	// writing to the stream in the meantime
+	if len(msgs) > 0 {
+		ids := make([]string, 0, len(msgs))
+
+		for _, msg := range msgs {
+			ids = append(ids, msg.ID)
+		}
+
+		return client.XDel(context.Background(), streamName, ids...).Err()
+	}
+
+	return nil
+}
+
+func applyLogLine(resources map[string]*models.InfraResource, logLine *tfLogLine) {
+	tfRes := logLine.Hook.Resource
+
+	if tfRes.Addr == "" {
+		return
+	}
+
+	switch logLine.Type {
+	case "apply_complete":
+		if logLine.Hook.Action == "delete" {
+			delete(resources, tfRes.Addr)
+			return
+		}
+
+		resource := getOrCreateResource(resources, tfRes)
+		resource.Status = types.InfraResourceCreated
+		resource.Error = ""
+	case "apply_errored":
+		resource := getOrCreateResource(resources, tfRes)
+		resource.Status = types.InfraResourceErrored
+		resource.Error = tfRes.Errored.ErrorSummary
+
+		if resource.Error == "" {
+			resource.Error = logLine.Message
+		}
+	}
+}
+
+func getOrCreateResource(resources map[string]*models.InfraResource, tfRes tfResource) *models.InfraResource {
+	resource, ok := resources[tfRes.Addr]
+
+	if !ok {
+		resource = &models.InfraResource{
+			Addr: tfRes.Addr,
+		}
+
+		resources[tfRes.Addr] = resource
+	}
+
+	resource.ResourceType = tfRes.ResourceType
+	resource.ResourceName = tfRes.ResourceName
+	resource.Provider = tfRes.Provider
+
+	return resource
+}
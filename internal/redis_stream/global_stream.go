@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/porter-dev/porter/internal/analytics"
@@ -29,6 +32,10 @@ const GlobalStreamName = "global"
 // is a part of
 const GlobalStreamGroupName = "portersvr"
 
+// GlobalStreamDefaultConsumer is the name of the consumer used when operation leases
+// are not enabled
+const GlobalStreamDefaultConsumer = "portersvr-0"
+
 // InitGlobalStream initializes the global stream if it does not exist, and the
 // global consumer group if it does not exist
 func InitGlobalStream(client *redis.Client) error {
@@ -90,15 +97,23 @@ func GlobalStreamListener(
 	client *redis.Client,
 	config *config.Config,
 	repo repository.Repository,
-	analyticsClient analytics.AnalyticsSegmentClient,
+	analyticsClient analytics.AnalyticsClient,
 	errorChan chan error,
 ) {
+	consumer := GlobalStreamDefaultConsumer
+
+	// when leases are enabled, each server replica reads from the global stream as its own
+	// consumer, so that messages delivered to dead replicas can be reclaimed
+	if config.ProvisionerLeases != nil {
+		consumer = config.ProvisionerLeases.WorkerID()
+	}
+
 	for {
 		xstreams, err := client.XReadGroup(
 			context.Background(),
 			&redis.XReadGroupArgs{
 				Group:    GlobalStreamGroupName,
-				Consumer: "portersvr-0", // just static consumer for now
+				Consumer: consumer,
 				Streams:  []string{GlobalStreamName, ">"},
 				Block:    0,
 			},
@@ -111,400 +126,555 @@ func GlobalStreamListener(
 
 		// parse messages from the global stream
 		for _, msg := range xstreams[0].Messages {
-			// parse the id to identify the infra
-			kind, projID, infraID, err := models.ParseUniqueName(fmt.Sprintf("%v", msg.Values["id"]))
+			processGlobalStreamMessage(client, config, repo, analyticsClient, msg)
+		}
+	}
+}
 
-			if fmt.Sprintf("%v", msg.Values["status"]) == "created" {
-				infra, err := repo.Infra().ReadInfra(projID, infraID)
+// ReclaimGlobalStreamMessages periodically claims messages on the global stream that
+// were delivered to workers that are no longer alive, and processes them. This requires
+// operation leases to be enabled.
+func ReclaimGlobalStreamMessages(
+	ctx context.Context,
+	client *redis.Client,
+	config *config.Config,
+	repo repository.Repository,
+	analyticsClient analytics.AnalyticsClient,
+) {
+	leases := config.ProvisionerLeases
 
-				if err != nil {
-					continue
-				}
+	if leases == nil {
+		return
+	}
 
-				infra.Status = types.StatusCreated
+	ticker := time.NewTicker(leases.TTL())
+	defer ticker.Stop()
 
-				infra, err = repo.Infra().UpdateInfra(infra)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 
-				if err != nil {
-					continue
-				}
+		pending, err := client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: GlobalStreamName,
+			Group:  GlobalStreamGroupName,
+			Start:  "-",
+			End:    "+",
+			Count:  100,
+		}).Result()
 
-				// create ECR/EKS
-				if kind == string(types.InfraECR) {
-					reg := &models.Registry{
-						ProjectID:        projID,
-						AWSIntegrationID: infra.AWSIntegrationID,
-						InfraID:          infra.ID,
-					}
+		if err != nil {
+			continue
+		}
 
-					// parse raw data into ECR type
-					dataString, ok := msg.Values["data"].(string)
+		for _, p := range pending {
+			if p.Consumer == leases.WorkerID() || p.Idle < leases.TTL() {
+				continue
+			}
 
-					if ok {
-						json.Unmarshal([]byte(dataString), reg)
-					}
+			if alive, err := leases.IsWorkerAlive(p.Consumer); err != nil || alive {
+				continue
+			}
 
-					awsInt, err := repo.AWSIntegration().ReadAWSIntegration(reg.ProjectID, reg.AWSIntegrationID)
+			// claiming is atomic, so only a single worker will receive the message
+			msgs, err := client.XClaim(ctx, &redis.XClaimArgs{
+				Stream:   GlobalStreamName,
+				Group:    GlobalStreamGroupName,
+				Consumer: leases.WorkerID(),
+				MinIdle:  leases.TTL(),
+				Messages: []string{p.ID},
+			}).Result()
+
+			if err != nil {
+				continue
+			}
 
-					if err != nil {
-						continue
-					}
+			for _, msg := range msgs {
+				processGlobalStreamMessage(client, config, repo, analyticsClient, msg)
+			}
+		}
+	}
+}
 
-					sess, err := awsInt.GetSession()
+// processGlobalStreamMessage updates models in the database for a single message on
+// the global stream, and acknowledges the message once it has been processed
+func processGlobalStreamMessage(
+	client *redis.Client,
+	config *config.Config,
+	repo repository.Repository,
+	analyticsClient analytics.AnalyticsClient,
+	msg redis.XMessage,
+) {
+	workspaceID := fmt.Sprintf("%v", msg.Values["id"])
 
-					if err != nil {
-						continue
-					}
+	// parse the id to identify the infra
+	kind, projID, infraID, err := models.ParseUniqueName(workspaceID)
 
-					ecrSvc := ecr.New(sess)
+	// messages with malformed ids can never be processed
+	if err != nil {
+		ackGlobalStreamMessage(client, msg)
+		return
+	}
 
-					output, err := ecrSvc.GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{})
+	if leases := config.ProvisionerLeases; leases != nil && isOperationResult(msg) {
+		if generation := getLeaseGeneration(msg); generation != 0 {
+			current, err := leases.Generation(workspaceID)
 
-					if err != nil {
-						continue
-					}
+			if err != nil {
+				return
+			}
 
-					reg.URL = *output.AuthorizationData[0].ProxyEndpoint
+			// results of operations whose lease was claimed again by a later operation
+			// are ignored, so that they do not overwrite the result of the later operation
+			if generation < current {
+				ackGlobalStreamMessage(client, msg)
+				return
+			}
 
-					reg, err = repo.Registry().CreateRegistry(reg)
+			// the operation has finished, so its lease on the infra is released. Results
+			// without a generation do not release the lease, which expires once the
+			// provisioner job has stopped.
+			if _, err := leases.Release(workspaceID, generation); err != nil {
+				return
+			}
+		}
+	}
 
-					if err != nil {
-						continue
-					}
-
-					analyticsClient.Track(analytics.RegistryProvisioningSuccessTrack(
-						&analytics.RegistryProvisioningSuccessTrackOpts{
-							RegistryScopedTrackOpts: analytics.GetRegistryScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, reg.ID),
-							RegistryType:            infra.Kind,
-							InfraID:                 infra.ID,
-						},
-					))
-				} else if kind == string(types.InfraRDS) {
-					// parse the last applied field to get the cluster id
-					rdsRequest := &types.RDSInfraLastApplied{}
-					err := json.Unmarshal(infra.LastApplied, rdsRequest)
+	infra, err := repo.Infra().ReadInfra(projID, infraID)
 
-					if err != nil {
-						continue
-					}
+	// messages of deleted infras can never be processed, while other messages are not
+	// acked if the infra cannot be read, so that they are processed again
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ackGlobalStreamMessage(client, msg)
+		return
+	} else if err != nil {
+		return
+	}
 
-					database := &models.Database{
-						Status: "running",
-					}
-
-					// parse raw data into ECR type
-					dataString, ok := msg.Values["data"].(string)
-
-					if ok {
-						err = json.Unmarshal([]byte(dataString), database)
-
-						if err != nil {
-						}
-					}
-
-					database.Model = gorm.Model{}
-					database.ProjectID = projID
-					database.ClusterID = rdsRequest.ClusterID
-					database.InfraID = infra.ID
-
-					database, err = repo.Database().CreateDatabase(database)
-
-					if err != nil {
-						continue
-					}
-
-					infra.DatabaseID = database.ID
-					infra, err = repo.Infra().UpdateInfra(infra)
-
-					if err != nil {
-						continue
-					}
-
-					err = createRDSEnvGroup(repo, config, infra, database, rdsRequest)
-
-					if err != nil {
-						continue
-					}
-				} else if kind == string(types.InfraEKS) {
-					cluster := &models.Cluster{
-						AuthMechanism:    models.AWS,
-						ProjectID:        projID,
-						AWSIntegrationID: infra.AWSIntegrationID,
-						InfraID:          infra.ID,
-					}
-
-					// parse raw data into ECR type
-					dataString, ok := msg.Values["data"].(string)
-
-					if ok {
-						json.Unmarshal([]byte(dataString), cluster)
-					}
-
-					re := regexp.MustCompile(`^([A-Za-z0-9+/]{4})*([A-Za-z0-9+/]{3}=|[A-Za-z0-9+/]{2}==)?$`)
-
-					// if it matches the base64 regex, decode it
-					caData := string(cluster.CertificateAuthorityData)
-					if re.MatchString(caData) {
-						decoded, err := base64.StdEncoding.DecodeString(caData)
-
-						if err != nil {
-							continue
-						}
-
-						cluster.CertificateAuthorityData = []byte(decoded)
-					}
-
-					cluster, err := repo.Cluster().CreateCluster(cluster)
-
-					if err != nil {
-						continue
-					}
-
-					analyticsClient.Track(analytics.ClusterProvisioningSuccessTrack(
-						&analytics.ClusterProvisioningSuccessTrackOpts{
-							ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, cluster.ID),
-							ClusterType:            infra.Kind,
-							InfraID:                infra.ID,
-						},
-					))
-				} else if kind == string(types.InfraGCR) {
-					reg := &models.Registry{
-						ProjectID:        projID,
-						GCPIntegrationID: infra.GCPIntegrationID,
-						InfraID:          infra.ID,
-						Name:             "gcr-registry",
-					}
-
-					// parse raw data into ECR type
-					dataString, ok := msg.Values["data"].(string)
-
-					if ok {
-						json.Unmarshal([]byte(dataString), reg)
-					}
-
-					reg, err = repo.Registry().CreateRegistry(reg)
-
-					if err != nil {
-						continue
-					}
-
-					analyticsClient.Track(analytics.RegistryProvisioningSuccessTrack(
-						&analytics.RegistryProvisioningSuccessTrackOpts{
-							RegistryScopedTrackOpts: analytics.GetRegistryScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, reg.ID),
-							RegistryType:            infra.Kind,
-							InfraID:                 infra.ID,
-						},
-					))
-				} else if kind == string(types.InfraGKE) {
-					cluster := &models.Cluster{
-						AuthMechanism:    models.GCP,
-						ProjectID:        projID,
-						GCPIntegrationID: infra.GCPIntegrationID,
-						InfraID:          infra.ID,
-					}
-
-					// parse raw data into GKE type
-					dataString, ok := msg.Values["data"].(string)
-
-					if ok {
-						json.Unmarshal([]byte(dataString), cluster)
-					}
-
-					re := regexp.MustCompile(`^([A-Za-z0-9+/]{4})*([A-Za-z0-9+/]{3}=|[A-Za-z0-9+/]{2}==)?$`)
-
-					// if it matches the base64 regex, decode it
-					caData := string(cluster.CertificateAuthorityData)
-					if re.MatchString(caData) {
-						decoded, err := base64.StdEncoding.DecodeString(caData)
-
-						if err != nil {
-							continue
-						}
-
-						cluster.CertificateAuthorityData = []byte(decoded)
-					}
-
-					cluster, err := repo.Cluster().CreateCluster(cluster)
-
-					if err != nil {
-						continue
-					}
-
-					analyticsClient.Track(analytics.ClusterProvisioningSuccessTrack(
-						&analytics.ClusterProvisioningSuccessTrackOpts{
-							ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, cluster.ID),
-							ClusterType:            infra.Kind,
-							InfraID:                infra.ID,
-						},
-					))
-				} else if kind == string(types.InfraDOCR) {
-					reg := &models.Registry{
-						ProjectID:       projID,
-						DOIntegrationID: infra.DOIntegrationID,
-						InfraID:         infra.ID,
-					}
-
-					// parse raw data into DOCR type
-					dataString, ok := msg.Values["data"].(string)
-
-					if ok {
-						json.Unmarshal([]byte(dataString), reg)
-					}
-
-					reg, err = repo.Registry().CreateRegistry(reg)
-
-					if err != nil {
-						continue
-					}
-
-					analyticsClient.Track(analytics.RegistryProvisioningSuccessTrack(
-						&analytics.RegistryProvisioningSuccessTrackOpts{
-							RegistryScopedTrackOpts: analytics.GetRegistryScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, reg.ID),
-							RegistryType:            infra.Kind,
-							InfraID:                 infra.ID,
-						},
-					))
-				} else if kind == string(types.InfraDOKS) {
-					cluster := &models.Cluster{
-						AuthMechanism:   models.DO,
-						ProjectID:       projID,
-						DOIntegrationID: infra.DOIntegrationID,
-						InfraID:         infra.ID,
-					}
-
-					// parse raw data into GKE type
-					dataString, ok := msg.Values["data"].(string)
-
-					if ok {
-						json.Unmarshal([]byte(dataString), cluster)
-					}
-
-					re := regexp.MustCompile(`^([A-Za-z0-9+/]{4})*([A-Za-z0-9+/]{3}=|[A-Za-z0-9+/]{2}==)?$`)
-
-					// if it matches the base64 regex, decode it
-					caData := string(cluster.CertificateAuthorityData)
-					if re.MatchString(caData) {
-						decoded, err := base64.StdEncoding.DecodeString(caData)
-
-						if err != nil {
-							continue
-						}
-
-						cluster.CertificateAuthorityData = []byte(decoded)
-					}
-
-					cluster, err := repo.Cluster().CreateCluster(cluster)
-
-					if err != nil {
-						continue
-					}
-
-					analyticsClient.Track(analytics.ClusterProvisioningSuccessTrack(
-						&analytics.ClusterProvisioningSuccessTrackOpts{
-							ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, cluster.ID),
-							ClusterType:            infra.Kind,
-							InfraID:                infra.ID,
-						},
-					))
-				}
-			} else if fmt.Sprintf("%v", msg.Values["status"]) == "error" {
-				infra, err := repo.Infra().ReadInfra(projID, infraID)
+	// compact the resource stream into the materialized resources of the infra, since
+	// the stream is only needed while the operation is live. The resources of the infra
+	// are compacted again after its next operation if this fails.
+	if err := CompactResourceStream(client, repo, infra); err != nil {
+		config.Logger.Error().Err(err).Msgf("error compacting the resource stream of infra %d", infra.ID)
+	}
+
+	if fmt.Sprintf("%v", msg.Values["status"]) == "created" {
+		// retries of failed upgrades still have the version of the upgrade pending
+		isUpgrade := infra.Status == types.StatusUpdating || infra.PendingModuleVersion != ""
+
+		infra.Status = types.StatusCreated
+
+		if infra.PendingModuleVersion != "" {
+			infra.ModuleVersion = infra.PendingModuleVersion
+			infra.PendingModuleVersion = ""
+		}
+
+		infra, err = repo.Infra().UpdateInfra(infra)
+
+		if err != nil {
+			return
+		}
+
+		// create ECR/EKS
+		if isUpgrade {
+			// upgrades re-apply an existing infra, so the registry, cluster or
+			// database linked to the infra already exists
+		} else if kind == string(types.InfraECR) {
+			reg := &models.Registry{
+				ProjectID:        projID,
+				AWSIntegrationID: infra.AWSIntegrationID,
+				InfraID:          infra.ID,
+			}
+
+			// parse raw data into ECR type
+			dataString, ok := msg.Values["data"].(string)
+
+			if ok {
+				json.Unmarshal([]byte(dataString), reg)
+			}
+
+			awsInt, err := repo.AWSIntegration().ReadAWSIntegration(reg.ProjectID, reg.AWSIntegrationID)
+
+			if err != nil {
+				return
+			}
+
+			sess, err := awsInt.GetSession()
+
+			if err != nil {
+				return
+			}
+
+			ecrSvc := ecr.New(sess)
+
+			output, err := ecrSvc.GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{})
+
+			if err != nil {
+				return
+			}
+
+			reg.URL = *output.AuthorizationData[0].ProxyEndpoint
+
+			reg, err = repo.Registry().CreateRegistry(reg)
+
+			if err != nil {
+				return
+			}
+
+			analyticsClient.Track(analytics.RegistryProvisioningSuccessTrack(
+				&analytics.RegistryProvisioningSuccessTrackOpts{
+					RegistryScopedTrackOpts: analytics.GetRegistryScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, reg.ID),
+					RegistryType:            infra.Kind,
+					InfraID:                 infra.ID,
+				},
+			))
+		} else if kind == string(types.InfraRDS) {
+			// parse the last applied field to get the cluster id
+			rdsRequest := &types.RDSInfraLastApplied{}
+			err := json.Unmarshal(infra.LastApplied, rdsRequest)
+
+			if err != nil {
+				return
+			}
+
+			database := &models.Database{
+				Status: "running",
+			}
+
+			// parse raw data into ECR type
+			dataString, ok := msg.Values["data"].(string)
+
+			if ok {
+				err = json.Unmarshal([]byte(dataString), database)
 
 				if err != nil {
-					continue
 				}
+			}
+
+			database.Model = gorm.Model{}
+			database.ProjectID = projID
+			database.ClusterID = rdsRequest.ClusterID
+			database.InfraID = infra.ID
+
+			database, err = repo.Database().CreateDatabase(database)
 
-				infra.Status = types.StatusError
+			if err != nil {
+				return
+			}
+
+			infra.DatabaseID = database.ID
+			infra, err = repo.Infra().UpdateInfra(infra)
+
+			if err != nil {
+				return
+			}
 
-				infra, err = repo.Infra().UpdateInfra(infra)
+			err = createRDSEnvGroup(repo, config, infra, database, rdsRequest)
+
+			if err != nil {
+				return
+			}
+		} else if _, ok := repository.GetProvisionedResourceRepository(repo, infra.Kind); ok {
+			dataString, _ := msg.Values["data"].(string)
+
+			if err := createProvisionedResource(repo, config, infra, dataString); err != nil {
+				setProvisionedResourceError(repo, config, infra, err)
+			}
+		} else if kind == string(types.InfraEKS) {
+			cluster := &models.Cluster{
+				AuthMechanism:    models.AWS,
+				ProjectID:        projID,
+				AWSIntegrationID: infra.AWSIntegrationID,
+				InfraID:          infra.ID,
+			}
+
+			// parse raw data into ECR type
+			dataString, ok := msg.Values["data"].(string)
+
+			if ok {
+				json.Unmarshal([]byte(dataString), cluster)
+			}
+
+			re := regexp.MustCompile(`^([A-Za-z0-9+/]{4})*([A-Za-z0-9+/]{3}=|[A-Za-z0-9+/]{2}==)?$`)
+
+			// if it matches the base64 regex, decode it
+			caData := string(cluster.CertificateAuthorityData)
+			if re.MatchString(caData) {
+				decoded, err := base64.StdEncoding.DecodeString(caData)
 
 				if err != nil {
-					continue
+					return
 				}
 
-				if infra.Kind == types.InfraDOKS || infra.Kind == types.InfraGKE || infra.Kind == types.InfraEKS {
-					analyticsClient.Track(analytics.ClusterProvisioningErrorTrack(
-						&analytics.ClusterProvisioningErrorTrackOpts{
-							ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID),
-							ClusterType:            infra.Kind,
-							InfraID:                infra.ID,
-						},
-					))
-				} else if infra.Kind == types.InfraDOCR || infra.Kind == types.InfraGCR || infra.Kind == types.InfraECR {
-					analyticsClient.Track(analytics.RegistryProvisioningErrorTrack(
-						&analytics.RegistryProvisioningErrorTrackOpts{
-							ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID),
-							RegistryType:           infra.Kind,
-							InfraID:                infra.ID,
-						},
-					))
-				}
-			} else if fmt.Sprintf("%v", msg.Values["status"]) == "destroyed" {
-				infra, err := repo.Infra().ReadInfra(projID, infraID)
+				cluster.CertificateAuthorityData = []byte(decoded)
+			}
+
+			cluster, err := repo.Cluster().CreateCluster(cluster)
+
+			if err != nil {
+				return
+			}
+
+			analyticsClient.Track(analytics.ClusterProvisioningSuccessTrack(
+				&analytics.ClusterProvisioningSuccessTrackOpts{
+					ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, cluster.ID),
+					ClusterType:            infra.Kind,
+					InfraID:                infra.ID,
+				},
+			))
+		} else if kind == string(types.InfraGCR) {
+			reg := &models.Registry{
+				ProjectID:        projID,
+				GCPIntegrationID: infra.GCPIntegrationID,
+				InfraID:          infra.ID,
+				Name:             "gcr-registry",
+			}
+
+			// parse raw data into ECR type
+			dataString, ok := msg.Values["data"].(string)
+
+			if ok {
+				json.Unmarshal([]byte(dataString), reg)
+			}
+
+			reg, err = repo.Registry().CreateRegistry(reg)
+
+			if err != nil {
+				return
+			}
+
+			analyticsClient.Track(analytics.RegistryProvisioningSuccessTrack(
+				&analytics.RegistryProvisioningSuccessTrackOpts{
+					RegistryScopedTrackOpts: analytics.GetRegistryScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, reg.ID),
+					RegistryType:            infra.Kind,
+					InfraID:                 infra.ID,
+				},
+			))
+		} else if kind == string(types.InfraGKE) {
+			cluster := &models.Cluster{
+				AuthMechanism:    models.GCP,
+				ProjectID:        projID,
+				GCPIntegrationID: infra.GCPIntegrationID,
+				InfraID:          infra.ID,
+			}
+
+			// parse raw data into GKE type
+			dataString, ok := msg.Values["data"].(string)
+
+			if ok {
+				json.Unmarshal([]byte(dataString), cluster)
+			}
+
+			re := regexp.MustCompile(`^([A-Za-z0-9+/]{4})*([A-Za-z0-9+/]{3}=|[A-Za-z0-9+/]{2}==)?$`)
+
+			// if it matches the base64 regex, decode it
+			caData := string(cluster.CertificateAuthorityData)
+			if re.MatchString(caData) {
+				decoded, err := base64.StdEncoding.DecodeString(caData)
 
 				if err != nil {
-					continue
+					return
 				}
 
-				infra.Status = types.StatusDestroyed
+				cluster.CertificateAuthorityData = []byte(decoded)
+			}
+
+			cluster, err := repo.Cluster().CreateCluster(cluster)
+
+			if err != nil {
+				return
+			}
+
+			analyticsClient.Track(analytics.ClusterProvisioningSuccessTrack(
+				&analytics.ClusterProvisioningSuccessTrackOpts{
+					ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, cluster.ID),
+					ClusterType:            infra.Kind,
+					InfraID:                infra.ID,
+				},
+			))
+		} else if kind == string(types.InfraDOCR) {
+			reg := &models.Registry{
+				ProjectID:       projID,
+				DOIntegrationID: infra.DOIntegrationID,
+				InfraID:         infra.ID,
+			}
+
+			// parse raw data into DOCR type
+			dataString, ok := msg.Values["data"].(string)
+
+			if ok {
+				json.Unmarshal([]byte(dataString), reg)
+			}
+
+			reg, err = repo.Registry().CreateRegistry(reg)
+
+			if err != nil {
+				return
+			}
+
+			analyticsClient.Track(analytics.RegistryProvisioningSuccessTrack(
+				&analytics.RegistryProvisioningSuccessTrackOpts{
+					RegistryScopedTrackOpts: analytics.GetRegistryScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, reg.ID),
+					RegistryType:            infra.Kind,
+					InfraID:                 infra.ID,
+				},
+			))
+		} else if kind == string(types.InfraDOKS) {
+			cluster := &models.Cluster{
+				AuthMechanism:   models.DO,
+				ProjectID:       projID,
+				DOIntegrationID: infra.DOIntegrationID,
+				InfraID:         infra.ID,
+			}
 
-				infra, err = repo.Infra().UpdateInfra(infra)
+			// parse raw data into GKE type
+			dataString, ok := msg.Values["data"].(string)
+
+			if ok {
+				json.Unmarshal([]byte(dataString), cluster)
+			}
+
+			re := regexp.MustCompile(`^([A-Za-z0-9+/]{4})*([A-Za-z0-9+/]{3}=|[A-Za-z0-9+/]{2}==)?$`)
+
+			// if it matches the base64 regex, decode it
+			caData := string(cluster.CertificateAuthorityData)
+			if re.MatchString(caData) {
+				decoded, err := base64.StdEncoding.DecodeString(caData)
 
 				if err != nil {
-					continue
+					return
 				}
 
-				if infra.Kind == types.InfraDOKS || infra.Kind == types.InfraGKE || infra.Kind == types.InfraEKS {
-					analyticsClient.Track(analytics.ClusterDestroyingSuccessTrack(
-						&analytics.ClusterDestroyingSuccessTrackOpts{
-							ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, 0),
-							ClusterType:            infra.Kind,
-							InfraID:                infra.ID,
-						},
-					))
-				} else if infra.Kind == types.InfraRDS && infra.DatabaseID != 0 {
-					rdsRequest := &types.RDSInfraLastApplied{}
-					err := json.Unmarshal(infra.LastApplied, rdsRequest)
+				cluster.CertificateAuthorityData = []byte(decoded)
+			}
+
+			cluster, err := repo.Cluster().CreateCluster(cluster)
 
-					if err != nil {
-						continue
-					}
+			if err != nil {
+				return
+			}
+
+			analyticsClient.Track(analytics.ClusterProvisioningSuccessTrack(
+				&analytics.ClusterProvisioningSuccessTrackOpts{
+					ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, cluster.ID),
+					ClusterType:            infra.Kind,
+					InfraID:                infra.ID,
+				},
+			))
+		}
+	} else if fmt.Sprintf("%v", msg.Values["status"]) == "error" {
+		infra.Status = types.StatusError
+
+		infra, err = repo.Infra().UpdateInfra(infra)
 
-					database, err := repo.Database().ReadDatabase(infra.ProjectID, rdsRequest.ClusterID, infra.DatabaseID)
+		if err != nil {
+			return
+		}
 
-					if err != nil {
-						continue
-					}
+		if infra.Kind == types.InfraDOKS || infra.Kind == types.InfraGKE || infra.Kind == types.InfraEKS {
+			analyticsClient.Track(analytics.ClusterProvisioningErrorTrack(
+				&analytics.ClusterProvisioningErrorTrackOpts{
+					ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID),
+					ClusterType:            infra.Kind,
+					InfraID:                infra.ID,
+				},
+			))
+		} else if infra.Kind == types.InfraDOCR || infra.Kind == types.InfraGCR || infra.Kind == types.InfraECR {
+			analyticsClient.Track(analytics.RegistryProvisioningErrorTrack(
+				&analytics.RegistryProvisioningErrorTrackOpts{
+					ProjectScopedTrackOpts: analytics.GetProjectScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID),
+					RegistryType:           infra.Kind,
+					InfraID:                infra.ID,
+				},
+			))
+		}
+	} else if fmt.Sprintf("%v", msg.Values["status"]) == "destroyed" {
+		infra.Status = types.StatusDestroyed
 
-					err = deleteRDSEnvGroup(repo, config, infra, database, rdsRequest)
+		infra, err = repo.Infra().UpdateInfra(infra)
 
-					if err != nil {
-						continue
-					}
+		if err != nil {
+			return
+		}
 
-					// delete the database
-					err = repo.Database().DeleteDatabase(infra.ProjectID, rdsRequest.ClusterID, infra.DatabaseID)
+		if infra.Kind == types.InfraDOKS || infra.Kind == types.InfraGKE || infra.Kind == types.InfraEKS {
+			analyticsClient.Track(analytics.ClusterDestroyingSuccessTrack(
+				&analytics.ClusterDestroyingSuccessTrackOpts{
+					ClusterScopedTrackOpts: analytics.GetClusterScopedTrackOpts(infra.CreatedByUserID, infra.ProjectID, 0),
+					ClusterType:            infra.Kind,
+					InfraID:                infra.ID,
+				},
+			))
+		} else if infra.Kind == types.InfraRDS && infra.DatabaseID != 0 {
+			rdsRequest := &types.RDSInfraLastApplied{}
+			err := json.Unmarshal(infra.LastApplied, rdsRequest)
 
-					if err != nil {
-						continue
-					}
-				}
+			if err != nil {
+				return
 			}
 
-			// acknowledge the message as read
-			_, err = client.XAck(
-				context.Background(),
-				GlobalStreamName,
-				GlobalStreamGroupName,
-				msg.ID,
-			).Result()
+			database, err := repo.Database().ReadDatabase(infra.ProjectID, rdsRequest.ClusterID, infra.DatabaseID)
 
-			// if error, continue for now
 			if err != nil {
-				continue
+				return
+			}
+
+			err = deleteRDSEnvGroup(repo, config, infra, database, rdsRequest)
+
+			if err != nil {
+				return
+			}
+
+			// delete the database
+			err = repo.Database().DeleteDatabase(infra.ProjectID, rdsRequest.ClusterID, infra.DatabaseID)
+
+			if err != nil {
+				return
+			}
+		} else if resourceRepo, ok := repository.GetProvisionedResourceRepository(repo, infra.Kind); ok {
+			if err := deleteProvisionedResource(repo, config, infra, resourceRepo); err != nil {
+				setProvisionedResourceError(repo, config, infra, err)
 			}
 		}
 	}
+
+	ackGlobalStreamMessage(client, msg)
+}
+
+// isOperationResult returns true if the message is the result of a finished operation
+func isOperationResult(msg redis.XMessage) bool {
+	switch fmt.Sprintf("%v", msg.Values["status"]) {
+	case "created", "error", "destroyed":
+		return true
+	default:
+		return false
+	}
+}
+
+// getLeaseGeneration returns the generation of the operation lease that was held by the
+// operation of the message, or 0 if the operation did not hold a lease
+func getLeaseGeneration(msg redis.XMessage) int64 {
+	generation, err := strconv.ParseInt(fmt.Sprintf("%v", msg.Values["lease_generation"]), 10, 64)
+
+	if err != nil {
+		return 0
+	}
+
+	return generation
+}
+
+// ackGlobalStreamMessage acknowledges the message as read
+func ackGlobalStreamMessage(client *redis.Client, msg redis.XMessage) error {
+	_, err := client.XAck(
+		context.Background(),
+		GlobalStreamName,
+		GlobalStreamGroupName,
+		msg.ID,
+	).Result()
+
+	return err
 }
 
 func createRDSEnvGroup(repo repository.Repository, config *config.Config, infra *models.Infra, database *models.Database, rdsConfig *types.RDSInfraLastApplied) error {
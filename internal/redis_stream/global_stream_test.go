@@ -0,0 +1,55 @@
+package redis_stream
+
+import (
+	"testing"
+
+	redis "github.com/go-redis/redis/v8"
+)
+
+func TestGetLeaseGeneration(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   map[string]interface{}
+		result   bool
+		expected int64
+	}{
+		{
+			name:     "result with generation",
+			values:   map[string]interface{}{"id": "s3-1-2-abc", "status": "created", "lease_generation": "4"},
+			result:   true,
+			expected: 4,
+		},
+		{
+			name:     "result without generation",
+			values:   map[string]interface{}{"id": "s3-1-2-abc", "status": "error"},
+			result:   true,
+			expected: 0,
+		},
+		{
+			name:     "malformed generation",
+			values:   map[string]interface{}{"id": "s3-1-2-abc", "status": "destroyed", "lease_generation": "latest"},
+			result:   true,
+			expected: 0,
+		},
+		{
+			name:     "not a result",
+			values:   map[string]interface{}{"id": "s3-1-2-abc", "status": "running", "lease_generation": "4"},
+			result:   false,
+			expected: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := redis.XMessage{ID: "1-0", Values: test.values}
+
+			if res := isOperationResult(msg); res != test.result {
+				t.Errorf("expected result %t, got %t", test.result, res)
+			}
+
+			if generation := getLeaseGeneration(msg); generation != test.expected {
+				t.Errorf("expected generation %d, got %d", test.expected, generation)
+			}
+		})
+	}
+}
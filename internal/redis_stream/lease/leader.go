@@ -0,0 +1,74 @@
+package lease
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+)
+
+// LeaderElector elects a single replica of the server to run a periodic task, such as
+// reporting usage, so that the task is not run by every replica
+type LeaderElector interface {
+	// IsLeader claims or renews the leadership of the task for this replica, and returns
+	// true if this replica is the leader
+	IsLeader(ctx context.Context) (bool, error)
+}
+
+// leaderScript renews the leadership if it is held by the given worker, or claims it if
+// it is not held by any worker. It returns 1 if the worker is the leader.
+var leaderScript = redis.NewScript(`
+local leader = redis.call("get", KEYS[1])
+if leader == ARGV[1] then
+	redis.call("pexpire", KEYS[1], ARGV[2])
+	return 1
+elseif not leader then
+	redis.call("set", KEYS[1], ARGV[1], "px", ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// Leader elects the leader of a periodic task with a Redis key that holds the id of the
+// leader. The leader renews the key each time that it runs the task, and another worker
+// becomes the leader once the key expires, so the TTL should be longer than the interval
+// of the task.
+type Leader struct {
+	client   *redis.Client
+	task     string
+	workerID string
+	ttl      time.Duration
+}
+
+// NewLeader returns the leader election of a task for the worker with the given unique id
+func NewLeader(client *redis.Client, task, workerID string, ttl time.Duration) *Leader {
+	return &Leader{
+		client:   client,
+		task:     task,
+		workerID: workerID,
+		ttl:      ttl,
+	}
+}
+
+// IsLeader claims or renews the leadership of the task, and returns true if this worker
+// is the leader
+func (l *Leader) IsLeader(ctx context.Context) (bool, error) {
+	res, err := leaderScript.Run(
+		ctx,
+		l.client,
+		[]string{leaderKey(l.task)},
+		l.workerID,
+		l.ttl.Milliseconds(),
+	).Int()
+
+	if err != nil {
+		return false, err
+	}
+
+	return res == 1, nil
+}
+
+func leaderKey(task string) string {
+	return fmt.Sprintf("leader:%s", task)
+}
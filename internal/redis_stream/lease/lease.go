@@ -0,0 +1,237 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+)
+
+// ErrLeaseHeld is returned when an operation lease is already held by a worker
+var ErrLeaseHeld = errors.New("an operation is already in progress for this infra")
+
+// leasesKey is the hash of all held leases, from the workspace id of the infra to the
+// generation of the lease, which every worker renews on its heartbeat
+const leasesKey = "provisioner-leases"
+
+// claimScript claims the lease with the next generation of the infra if the lease is not
+// held, and returns the generation. If the lease is held, 0 is returned.
+var claimScript = redis.NewScript(`
+if redis.call("exists", KEYS[1]) == 1 then
+	return 0
+end
+local generation = redis.call("incr", KEYS[2])
+redis.call("set", KEYS[1], generation, "px", ARGV[1])
+redis.call("hset", KEYS[3], ARGV[2], generation)
+return generation
+`)
+
+// renewScript extends the lease only if it is still held by the given generation
+var renewScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+else
+	return 0
+end
+`)
+
+// releaseScript releases the lease only if it is still held by the given generation.
+// The entry of the generation in the hash of leases is removed whether or not the lease
+// was still held.
+var releaseScript = redis.NewScript(`
+if redis.call("hget", KEYS[2], ARGV[2]) == ARGV[1] then
+	redis.call("hdel", KEYS[2], ARGV[2])
+end
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+else
+	return 0
+end
+`)
+
+// OperationChecker returns true if the operation that claimed the given generation of
+// the lease of an infra is still running
+type OperationChecker func(workspaceID string, generation int64) (bool, error)
+
+// Manager claims leases on provisioning operations, so that multiple server replicas
+// never run an operation on the same infra at the same time.
+//
+// Each claim of a lease has a new generation, which is passed to the operation and
+// returned with its result, so that only the result of the operation that holds the
+// lease releases it, and results of older operations can be ignored. Leases are renewed
+// on the heartbeat of every worker while their operation is running, so a lease does not
+// expire during a long operation when the worker that claimed it stops, and expires after
+// the lease TTL when its operation stopped without returning a result.
+type Manager struct {
+	client             *redis.Client
+	workerID           string
+	ttl                time.Duration
+	isOperationRunning OperationChecker
+}
+
+// NewManager returns a lease manager for the worker with the given unique id
+func NewManager(client *redis.Client, workerID string, ttl time.Duration, isOperationRunning OperationChecker) *Manager {
+	return &Manager{
+		client:             client,
+		workerID:           workerID,
+		ttl:                ttl,
+		isOperationRunning: isOperationRunning,
+	}
+}
+
+// WorkerID returns the unique id of this worker
+func (m *Manager) WorkerID() string {
+	return m.workerID
+}
+
+// TTL returns the duration after which a lease or worker heartbeat expires if it
+// is not renewed
+func (m *Manager) TTL() time.Duration {
+	return m.ttl
+}
+
+// Claim claims the operation lease for the infra with the given workspace id, and returns
+// the generation of the lease. If the lease is held by any worker, ErrLeaseHeld is
+// returned.
+func (m *Manager) Claim(workspaceID string) (int64, error) {
+	generation, err := claimScript.Run(
+		context.Background(),
+		m.client,
+		[]string{leaseKey(workspaceID), generationKey(workspaceID), leasesKey},
+		m.ttl.Milliseconds(),
+		workspaceID,
+	).Int64()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if generation == 0 {
+		return 0, ErrLeaseHeld
+	}
+
+	return generation, nil
+}
+
+// Release releases the operation lease for the infra if it is held by the given
+// generation, and returns true if the lease was released. This is called once the
+// operation that claimed the generation is finished.
+func (m *Manager) Release(workspaceID string, generation int64) (bool, error) {
+	released, err := releaseScript.Run(
+		context.Background(),
+		m.client,
+		[]string{leaseKey(workspaceID), leasesKey},
+		strconv.FormatInt(generation, 10),
+		workspaceID,
+	).Int()
+
+	if err != nil {
+		return false, err
+	}
+
+	return released > 0, nil
+}
+
+// Generation returns the generation of the last claim of the lease for the infra, or 0
+// if the lease was never claimed
+func (m *Manager) Generation(workspaceID string) (int64, error) {
+	generation, err := m.client.Get(context.Background(), generationKey(workspaceID)).Int64()
+
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	return generation, err
+}
+
+// IsWorkerAlive returns true if the worker with the given id has sent a heartbeat
+// within the lease TTL
+func (m *Manager) IsWorkerAlive(workerID string) (bool, error) {
+	if workerID == m.workerID {
+		return true, nil
+	}
+
+	n, err := m.client.Exists(context.Background(), workerKey(workerID)).Result()
+
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// Heartbeat marks this worker as alive and renews the leases of running operations at an
+// interval of a third of the lease TTL, until the context is canceled
+func (m *Manager) Heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(m.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		m.beat(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) beat(ctx context.Context) {
+	m.client.Set(ctx, workerKey(m.workerID), time.Now().Unix(), m.ttl)
+
+	leases, err := m.client.HGetAll(ctx, leasesKey).Result()
+
+	if err != nil {
+		return
+	}
+
+	for workspaceID, generationStr := range leases {
+		generation, err := strconv.ParseInt(generationStr, 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		// leases are renewed while it is unknown whether their operation is running,
+		// since an expired lease lets another operation run alongside it
+		running, err := m.isOperationRunning(workspaceID, generation)
+
+		if err == nil && !running {
+			// leases of stopped operations expire, after which they are removed
+			if n, err := m.client.Exists(ctx, leaseKey(workspaceID)).Result(); err == nil && n == 0 {
+				m.Release(workspaceID, generation)
+			}
+
+			continue
+		}
+
+		renewed, err := renewScript.Run(
+			ctx,
+			m.client,
+			[]string{leaseKey(workspaceID)},
+			generationStr,
+			m.ttl.Milliseconds(),
+		).Int()
+
+		// if the lease expired or was released, stop renewing it
+		if err == nil && renewed == 0 {
+			m.Release(workspaceID, generation)
+		}
+	}
+}
+
+func leaseKey(workspaceID string) string {
+	return fmt.Sprintf("provisioner-lease:%s", workspaceID)
+}
+
+func generationKey(workspaceID string) string {
+	return fmt.Sprintf("provisioner-lease-generation:%s", workspaceID)
+}
+
+func workerKey(workerID string) string {
+	return fmt.Sprintf("provisioner-worker:%s", workerID)
+}
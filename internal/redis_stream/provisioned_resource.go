@@ -0,0 +1,389 @@
+package redis_stream
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/porter-dev/porter/api/server/shared/config"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/envgroup"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// bucketOutputs are the outputs of the provisioner for S3 and GCS buckets
+type bucketOutputs struct {
+	BucketName   string `json:"bucket_name"`
+	BucketRegion string `json:"bucket_region"`
+
+	// outputs of S3 buckets, for the IAM user that can only access the bucket
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+
+	// output of GCS buckets, as the base64-encoded key of the service account that can
+	// only access the bucket
+	ServiceAccountKey string `json:"service_account_key"`
+}
+
+// queueOutputs are the outputs of the provisioner for SQS queues and Pub/Sub topics
+type queueOutputs struct {
+	QueueName string `json:"queue_name"`
+
+	// outputs of SQS queues, for the IAM user that can only access the queue
+	QueueURL        string `json:"queue_url"`
+	QueueRegion     string `json:"queue_region"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+
+	// outputs of Pub/Sub topics, with the base64-encoded key of the service account that
+	// can only publish to the topic and pull from the subscription
+	SubscriptionName  string `json:"subscription_name"`
+	ServiceAccountKey string `json:"service_account_key"`
+}
+
+// createProvisionedResource creates the bucket or the queue of an infra from the outputs
+// of the provisioner, along with the env group with the details and credentials of the
+// resource. A resource that was already created for the infra, such as by an earlier
+// attempt whose env group could not be created, is reused.
+func createProvisionedResource(
+	repo repository.Repository,
+	config *config.Config,
+	infra *models.Infra,
+	outputsData string,
+) error {
+	var resource models.ProvisionedResourceModel
+	var input types.ConfigMapInput
+
+	switch infra.Kind {
+	case types.InfraS3, types.InfraGCS:
+		bucket, err := createBucket(repo, infra, outputsData)
+
+		if err != nil {
+			return err
+		}
+
+		resource, input = bucket, getBucketEnvGroup(bucket)
+	case types.InfraSQS, types.InfraPubSub:
+		queue, err := createQueue(repo, infra, outputsData)
+
+		if err != nil {
+			return err
+		}
+
+		resource, input = queue, getQueueEnvGroup(queue)
+	default:
+		return fmt.Errorf("infra of kind %s is not a bucket or a queue", infra.Kind)
+	}
+
+	agent, err := getProvisionedResourceAgent(repo, config, infra, resource.GetProvisionedResource())
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := envgroup.CreateEnvGroup(agent, input); err != nil {
+		return fmt.Errorf("failed to create env group %s: %s", input.Name, err.Error())
+	}
+
+	return nil
+}
+
+// deleteProvisionedResource deletes the env group and the bucket or the queue of an
+// infra that was destroyed. A resource that failed to be created has no env group or
+// resource to delete.
+func deleteProvisionedResource(
+	repo repository.Repository,
+	config *config.Config,
+	infra *models.Infra,
+	resourceRepo repository.ProvisionedResourceRepository,
+) error {
+	resource, err := resourceRepo.ReadProvisionedResourceByInfraID(infra.ProjectID, infra.ID)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	res := resource.GetProvisionedResource()
+
+	agent, err := getProvisionedResourceAgent(repo, config, infra, res)
+
+	if err != nil {
+		return err
+	}
+
+	if err := envgroup.DeleteEnvGroup(agent, res.EnvGroupName, res.Namespace); err != nil {
+		return fmt.Errorf("failed to delete env group %s: %s", res.EnvGroupName, err.Error())
+	}
+
+	return resourceRepo.DeleteProvisionedResource(resource)
+}
+
+// setProvisionedResourceError logs an error with the bucket or the queue of an infra and
+// sets the status of the infra to errored, so that the operation can be retried
+func setProvisionedResourceError(repo repository.Repository, config *config.Config, infra *models.Infra, err error) {
+	config.Logger.Error().Err(err).Msgf("error processing the %s resource of infra %d", infra.Kind, infra.ID)
+
+	infra.Status = types.StatusError
+
+	if _, err := repo.Infra().UpdateInfra(infra); err != nil {
+		config.Logger.Error().Err(err).Msgf("error updating the status of infra %d", infra.ID)
+	}
+}
+
+func getProvisionedResourceAgent(
+	repo repository.Repository,
+	config *config.Config,
+	infra *models.Infra,
+	res *models.ProvisionedResource,
+) (*kubernetes.Agent, error) {
+	cluster, err := repo.Cluster().ReadCluster(infra.ProjectID, res.ClusterID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ooc := &kubernetes.OutOfClusterConfig{
+		Repo:              config.Repo,
+		DigitalOceanOAuth: config.DOConf,
+		Cluster:           cluster,
+	}
+
+	agent, err := kubernetes.GetAgentOutOfClusterConfig(ooc)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %s", err.Error())
+	}
+
+	return agent, nil
+}
+
+// newProvisionedResource returns the shared fields of the bucket or the queue of an infra
+func newProvisionedResource(infra *models.Infra, clusterID uint, namespace, name string) models.ProvisionedResource {
+	return models.ProvisionedResource{
+		ProjectID: infra.ProjectID,
+		ClusterID: clusterID,
+		InfraID:   infra.ID,
+		Kind:      infra.Kind,
+		Name:      name,
+		Namespace: namespace,
+		Status:    "running",
+	}
+}
+
+// decodeServiceAccountKey decodes the base64-encoded key of a GCP service account,
+// which is also accepted as the raw key
+func decodeServiceAccountKey(key string) []byte {
+	keyData, err := base64.StdEncoding.DecodeString(key)
+
+	if err != nil {
+		return []byte(key)
+	}
+
+	return keyData
+}
+
+func createBucket(repo repository.Repository, infra *models.Infra, outputsData string) (*models.Bucket, error) {
+	if resource, err := repo.Bucket().ReadProvisionedResourceByInfraID(infra.ProjectID, infra.ID); err == nil {
+		return resource.(*models.Bucket), nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	bucketRequest := &types.BucketInfraLastApplied{}
+
+	if err := json.Unmarshal(infra.LastApplied, bucketRequest); err != nil {
+		return nil, err
+	} else if bucketRequest.CreateBucketInfraRequest == nil {
+		return nil, fmt.Errorf("bucket infra %d has no last applied configuration", infra.ID)
+	}
+
+	outputs := &bucketOutputs{}
+
+	if err := json.Unmarshal([]byte(outputsData), outputs); err != nil {
+		return nil, fmt.Errorf("invalid outputs for bucket infra %d: %s", infra.ID, err.Error())
+	}
+
+	bucket, err := outputs.toBucket(infra, bucketRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.Bucket().CreateBucket(bucket)
+}
+
+func (o *bucketOutputs) toBucket(infra *models.Infra, bucketConfig *types.BucketInfraLastApplied) (*models.Bucket, error) {
+	bucket := &models.Bucket{
+		ProvisionedResource: newProvisionedResource(infra, bucketConfig.ClusterID, bucketConfig.Namespace, o.BucketName),
+	}
+
+	bucket.Region = o.BucketRegion
+
+	if bucket.Name == "" {
+		bucket.Name = bucketConfig.BucketName
+	}
+
+	bucket.EnvGroupName = fmt.Sprintf("bucket-credentials-%s", bucket.Name)
+
+	switch infra.Kind {
+	case types.InfraS3:
+		if o.AccessKeyID == "" || o.SecretAccessKey == "" {
+			return nil, fmt.Errorf("no access keys were returned for bucket %s", bucket.Name)
+		}
+
+		if bucket.Region == "" {
+			bucket.Region = bucketConfig.AWSRegion
+		}
+
+		bucket.AWSAccessKeyID = []byte(o.AccessKeyID)
+		bucket.AWSSecretAccessKey = []byte(o.SecretAccessKey)
+	case types.InfraGCS:
+		if o.ServiceAccountKey == "" {
+			return nil, fmt.Errorf("no service account key was returned for bucket %s", bucket.Name)
+		}
+
+		if bucket.Region == "" {
+			bucket.Region = bucketConfig.GCPRegion
+		}
+
+		bucket.GCPKeyData = decodeServiceAccountKey(o.ServiceAccountKey)
+	}
+
+	return bucket, nil
+}
+
+// getBucketEnvGroup returns the env group with the name and credentials of a bucket,
+// which is created in the namespace that the bucket was provisioned from so that the
+// bucket can be used by releases through the synced env groups of the release
+func getBucketEnvGroup(bucket *models.Bucket) types.ConfigMapInput {
+	input := types.ConfigMapInput{
+		Name:            bucket.EnvGroupName,
+		Namespace:       bucket.Namespace,
+		Variables:       map[string]string{},
+		SecretVariables: map[string]string{},
+	}
+
+	if bucket.Kind == types.InfraS3 {
+		input.Variables["S3_BUCKET"] = bucket.Name
+		input.Variables["AWS_REGION"] = bucket.Region
+		input.SecretVariables["AWS_ACCESS_KEY_ID"] = string(bucket.AWSAccessKeyID)
+		input.SecretVariables["AWS_SECRET_ACCESS_KEY"] = string(bucket.AWSSecretAccessKey)
+	} else {
+		input.Variables["GCS_BUCKET"] = bucket.Name
+		input.SecretVariables["GCS_CREDENTIALS_JSON"] = string(bucket.GCPKeyData)
+	}
+
+	return input
+}
+
+func createQueue(repo repository.Repository, infra *models.Infra, outputsData string) (*models.Queue, error) {
+	if resource, err := repo.Queue().ReadProvisionedResourceByInfraID(infra.ProjectID, infra.ID); err == nil {
+		return resource.(*models.Queue), nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	queueRequest := &types.QueueInfraLastApplied{}
+
+	if err := json.Unmarshal(infra.LastApplied, queueRequest); err != nil {
+		return nil, err
+	} else if queueRequest.CreateQueueInfraRequest == nil {
+		return nil, fmt.Errorf("queue infra %d has no last applied configuration", infra.ID)
+	}
+
+	outputs := &queueOutputs{}
+
+	if err := json.Unmarshal([]byte(outputsData), outputs); err != nil {
+		return nil, fmt.Errorf("invalid outputs for queue infra %d: %s", infra.ID, err.Error())
+	}
+
+	queue, err := outputs.toQueue(infra, queueRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.Queue().CreateQueue(queue)
+}
+
+func (o *queueOutputs) toQueue(infra *models.Infra, queueConfig *types.QueueInfraLastApplied) (*models.Queue, error) {
+	queue := &models.Queue{
+		ProvisionedResource: newProvisionedResource(infra, queueConfig.ClusterID, queueConfig.Namespace, o.QueueName),
+	}
+
+	if queue.Name == "" {
+		queue.Name = queueConfig.QueueName
+	}
+
+	queue.EnvGroupName = fmt.Sprintf("queue-credentials-%s", strings.TrimSuffix(queue.Name, ".fifo"))
+
+	switch infra.Kind {
+	case types.InfraSQS:
+		if o.QueueURL == "" {
+			return nil, fmt.Errorf("no url was returned for queue %s", queue.Name)
+		}
+
+		if o.AccessKeyID == "" || o.SecretAccessKey == "" {
+			return nil, fmt.Errorf("no access keys were returned for queue %s", queue.Name)
+		}
+
+		queue.URL = o.QueueURL
+		queue.Region = o.QueueRegion
+
+		if queue.Region == "" {
+			queue.Region = queueConfig.AWSRegion
+		}
+
+		queue.AWSAccessKeyID = []byte(o.AccessKeyID)
+		queue.AWSSecretAccessKey = []byte(o.SecretAccessKey)
+	case types.InfraPubSub:
+		if o.SubscriptionName == "" {
+			return nil, fmt.Errorf("no subscription was returned for topic %s", queue.Name)
+		}
+
+		if o.ServiceAccountKey == "" {
+			return nil, fmt.Errorf("no service account key was returned for topic %s", queue.Name)
+		}
+
+		queue.GCPProjectID = queueConfig.GCPProjectID
+		queue.SubscriptionName = o.SubscriptionName
+		queue.GCPKeyData = decodeServiceAccountKey(o.ServiceAccountKey)
+	}
+
+	return queue, nil
+}
+
+// getQueueEnvGroup returns the env group with the details and credentials of a queue,
+// which is created in the namespace that the queue was provisioned from. The credentials
+// are stored under the env vars that the scaler triggers of the queue read from, so
+// workers that sync the env group can be scaled on the queue.
+func getQueueEnvGroup(queue *models.Queue) types.ConfigMapInput {
+	input := types.ConfigMapInput{
+		Name:            queue.EnvGroupName,
+		Namespace:       queue.Namespace,
+		Variables:       map[string]string{},
+		SecretVariables: map[string]string{},
+	}
+
+	if queue.Kind == types.InfraSQS {
+		input.Variables["SQS_QUEUE_NAME"] = queue.Name
+		input.Variables["SQS_QUEUE_URL"] = queue.URL
+		input.Variables["AWS_REGION"] = queue.Region
+		input.SecretVariables[types.SQSAccessKeyIDEnv] = string(queue.AWSAccessKeyID)
+		input.SecretVariables[types.SQSSecretAccessKeyEnv] = string(queue.AWSSecretAccessKey)
+	} else {
+		input.Variables["PUBSUB_PROJECT_ID"] = queue.GCPProjectID
+		input.Variables["PUBSUB_TOPIC"] = queue.Name
+		input.Variables["PUBSUB_SUBSCRIPTION"] = queue.SubscriptionName
+		input.SecretVariables[types.PubSubCredentialsEnv] = string(queue.GCPKeyData)
+	}
+
+	return input
+}
@@ -0,0 +1,189 @@
+package redis_stream
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository/test"
+)
+
+func getBucketTestInfra(kind types.InfraKind) *models.Infra {
+	infra := &models.Infra{
+		ProjectID:   1,
+		Kind:        kind,
+		LastApplied: []byte(`{"bucket_name":"uploads","cluster_id":1,"namespace":"default","aws_region":"us-east-1","gcp_region":"us-central1"}`),
+	}
+
+	infra.ID = 2
+
+	return infra
+}
+
+func TestCreateBucket(t *testing.T) {
+	repo := test.NewRepository(true)
+	infra := getBucketTestInfra(types.InfraS3)
+
+	bucket, err := createBucket(repo, infra, `{"access_key_id":"AKIA","secret_access_key":"secret"}`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bucket.Name != "uploads" || bucket.Region != "us-east-1" || bucket.InfraID != 2 || bucket.EnvGroupName != "bucket-credentials-uploads" {
+		t.Errorf("expected the bucket to be created from the configuration of the infra, got %+v", bucket.ProvisionedResource)
+	}
+
+	input := getBucketEnvGroup(bucket)
+
+	if input.Variables["S3_BUCKET"] != "uploads" || input.SecretVariables["AWS_SECRET_ACCESS_KEY"] != "secret" {
+		t.Errorf("expected the env group to hold the name and credentials of the bucket, got %+v", input)
+	}
+
+	// retries of the message reuse the bucket that was already created for the infra
+	retried, err := createBucket(repo, infra, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if retried.ID != bucket.ID {
+		t.Errorf("expected the bucket of the infra to be reused, got bucket %d", retried.ID)
+	}
+
+	if buckets, _ := repo.Bucket().ListBuckets(1, 1); len(buckets) != 1 {
+		t.Errorf("expected a single bucket for the infra, got %d", len(buckets))
+	}
+}
+
+func TestCreateBucketInvalidOutputs(t *testing.T) {
+	repo := test.NewRepository(true)
+
+	if _, err := createBucket(repo, getBucketTestInfra(types.InfraS3), ""); err == nil {
+		t.Errorf("expected an error for a bucket without outputs")
+	}
+
+	if _, err := createBucket(repo, getBucketTestInfra(types.InfraS3), `{"access_key_id":"AKIA"}`); err == nil {
+		t.Errorf("expected an error for a bucket without a secret access key")
+	}
+
+	if buckets, _ := repo.Bucket().ListBuckets(1, 1); len(buckets) != 0 {
+		t.Errorf("expected no bucket to be created, got %d", len(buckets))
+	}
+}
+
+func TestCreateGCSBucket(t *testing.T) {
+	key := `{"type":"service_account"}`
+
+	bucket, err := createBucket(
+		test.NewRepository(true),
+		getBucketTestInfra(types.InfraGCS),
+		`{"bucket_name":"uploads-1234","service_account_key":"`+base64.StdEncoding.EncodeToString([]byte(key))+`"}`,
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bucket.Name != "uploads-1234" || bucket.Region != "us-central1" || string(bucket.GCPKeyData) != key {
+		t.Errorf("expected the bucket to be created from the outputs of the provisioner, got %+v", bucket.ProvisionedResource)
+	}
+
+	if input := getBucketEnvGroup(bucket); input.SecretVariables["GCS_CREDENTIALS_JSON"] != key {
+		t.Errorf("expected the env group to hold the decoded key of the service account, got %+v", input)
+	}
+}
+
+func getQueueTestInfra(kind types.InfraKind, lastApplied string) *models.Infra {
+	infra := &models.Infra{
+		ProjectID:   1,
+		Kind:        kind,
+		LastApplied: []byte(lastApplied),
+	}
+
+	infra.ID = 3
+
+	return infra
+}
+
+func TestCreateSQSQueue(t *testing.T) {
+	repo := test.NewRepository(true)
+	infra := getQueueTestInfra(
+		types.InfraSQS,
+		`{"queue_name":"jobs.fifo","fifo":true,"cluster_id":1,"namespace":"default","aws_region":"us-east-1"}`,
+	)
+
+	queue, err := createQueue(
+		repo,
+		infra,
+		`{"queue_url":"https://sqs.us-east-1.amazonaws.com/123/jobs.fifo","access_key_id":"AKIA","secret_access_key":"secret"}`,
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if queue.Name != "jobs.fifo" || queue.Region != "us-east-1" || queue.EnvGroupName != "queue-credentials-jobs" {
+		t.Errorf("expected the queue to be created from the configuration of the infra, got %+v", queue.ProvisionedResource)
+	}
+
+	input := getQueueEnvGroup(queue)
+
+	if input.Variables["SQS_QUEUE_URL"] != queue.URL || input.SecretVariables[types.SQSSecretAccessKeyEnv] != "secret" {
+		t.Errorf("expected the env group to hold the url and credentials of the queue, got %+v", input)
+	}
+
+	if retried, err := createQueue(repo, infra, ""); err != nil || retried.ID != queue.ID {
+		t.Errorf("expected the queue of the infra to be reused, got %v", err)
+	}
+}
+
+func TestCreateSQSQueueInvalidOutputs(t *testing.T) {
+	repo := test.NewRepository(true)
+	infra := getQueueTestInfra(types.InfraSQS, `{"queue_name":"jobs","cluster_id":1,"namespace":"default"}`)
+
+	if _, err := createQueue(repo, infra, `{"access_key_id":"AKIA","secret_access_key":"secret"}`); err == nil {
+		t.Errorf("expected an error for a queue without a url")
+	}
+
+	if _, err := createQueue(repo, getQueueTestInfra(types.InfraSQS, `{}`), `{}`); err == nil {
+		t.Errorf("expected an error for a queue without a last applied configuration")
+	}
+
+	if queues, _ := repo.Queue().ListQueues(1, 1); len(queues) != 0 {
+		t.Errorf("expected no queue to be created, got %d", len(queues))
+	}
+}
+
+func TestCreatePubSubQueue(t *testing.T) {
+	key := `{"type":"service_account"}`
+	infra := getQueueTestInfra(
+		types.InfraPubSub,
+		`{"queue_name":"jobs","cluster_id":1,"namespace":"default","gcp_project_id":"project-1234"}`,
+	)
+
+	if _, err := createQueue(test.NewRepository(true), infra, `{"service_account_key":"`+key+`"}`); err == nil {
+		t.Errorf("expected an error for a topic without a subscription")
+	}
+
+	queue, err := createQueue(
+		test.NewRepository(true),
+		infra,
+		`{"subscription_name":"jobs-sub","service_account_key":"`+base64.StdEncoding.EncodeToString([]byte(key))+`"}`,
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := getQueueEnvGroup(queue)
+
+	if input.Variables["PUBSUB_PROJECT_ID"] != "project-1234" || input.Variables["PUBSUB_SUBSCRIPTION"] != "jobs-sub" {
+		t.Errorf("expected the env group to hold the topic and subscription, got %+v", input)
+	}
+
+	if input.SecretVariables[types.PubSubCredentialsEnv] != key {
+		t.Errorf("expected the env group to hold the decoded key of the service account, got %+v", input)
+	}
+}
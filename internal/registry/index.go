@@ -0,0 +1,462 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	ptypes "github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/repository"
+	"golang.org/x/oauth2"
+)
+
+// indexRetryInterval is the time after which an entry of the index whose refresh failed
+// is refreshed again
+const indexRetryInterval = time.Minute
+
+// registryIndexIdleTTL is the time after which the index of a registry that has not been
+// listed is dropped
+const registryIndexIdleTTL = time.Hour
+
+// indexInvalidationsChannel is the Redis channel that invalidations of the index are
+// published to, so that they apply to the index of every replica
+const indexInvalidationsChannel = "registry-index-invalidations"
+
+// RepositoryIndex caches the repositories of each registry and the images of each
+// repository that has been listed, so that listing a large registry does not wait on the
+// registry. Expired entries are served while they are refreshed in the background, and
+// only the first listing of a registry or repository waits on the registry.
+//
+// Each replica of the server keeps its own index. If Redis is enabled, invalidations
+// are published to the other replicas, so that an image that is pushed through one
+// replica is listed by every replica.
+type RepositoryIndex struct {
+	ttl    time.Duration
+	repo   repository.Repository
+	doAuth *oauth2.Config
+	redis  *redis.Client
+
+	// listRepositories and listImages list the repositories and images of a registry,
+	// and are replaced in tests
+	listRepositories func(reg *Registry) ([]*ptypes.RegistryRepository, error)
+	listImages       func(reg *Registry, repoName string) ([]*ptypes.Image, error)
+
+	mu         sync.Mutex
+	registries map[uint]*registryIndex
+}
+
+type registryIndex struct {
+	reg          *Registry
+	repositories *indexEntry
+	images       map[string]*indexEntry
+	lastUsed     time.Time
+	syncing      bool
+}
+
+type indexEntry struct {
+	value      interface{}
+	err        error
+	loaded     bool
+	expiresAt  time.Time
+	refreshing bool
+	done       chan struct{}
+}
+
+// indexInvalidation is an invalidation of the index that is published to the other
+// replicas. Either the index of a registry is dropped, or the images of an image
+// repository of a project are dropped.
+type indexInvalidation struct {
+	RegistryID uint   `json:"registry_id,omitempty"`
+	ProjectID  uint   `json:"project_id,omitempty"`
+	ImageRepo  string `json:"image_repo,omitempty"`
+}
+
+// NewRepositoryIndex returns an index whose entries expire after the given ttl. The Redis
+// client is used to publish invalidations to the other replicas, and may be nil if Redis
+// is not enabled.
+func NewRepositoryIndex(
+	ttl time.Duration,
+	repo repository.Repository,
+	doAuth *oauth2.Config,
+	client *redis.Client,
+) *RepositoryIndex {
+	idx := &RepositoryIndex{
+		ttl:        ttl,
+		repo:       repo,
+		doAuth:     doAuth,
+		redis:      client,
+		registries: make(map[uint]*registryIndex),
+	}
+
+	idx.listRepositories = func(reg *Registry) ([]*ptypes.RegistryRepository, error) {
+		return reg.ListRepositories(idx.repo, idx.doAuth)
+	}
+
+	idx.listImages = func(reg *Registry, repoName string) ([]*ptypes.Image, error) {
+		return reg.ListImages(repoName, idx.repo, idx.doAuth)
+	}
+
+	return idx
+}
+
+// ListRepositories lists the repositories of a registry from the index
+func (idx *RepositoryIndex) ListRepositories(reg *Registry) ([]*ptypes.RegistryRepository, error) {
+	idx.mu.Lock()
+	ri := idx.getRegistryLocked(reg)
+	entry := ri.repositories
+	idx.mu.Unlock()
+
+	value, err := idx.get(entry, idx.repositoriesLoader(ri))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value.([]*ptypes.RegistryRepository), nil
+}
+
+// ListImages lists the images of a repository from the index. The images of the
+// repository are kept up to date by Run from then on.
+func (idx *RepositoryIndex) ListImages(reg *Registry, repoName string) ([]*ptypes.Image, error) {
+	idx.mu.Lock()
+	ri := idx.getRegistryLocked(reg)
+	entry, ok := ri.images[repoName]
+
+	if !ok {
+		entry = &indexEntry{}
+		ri.images[repoName] = entry
+	}
+
+	idx.mu.Unlock()
+
+	value, err := idx.get(entry, idx.imagesLoader(ri, repoName))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value.([]*ptypes.Image), nil
+}
+
+// Invalidate drops the index of a registry, such as when a repository is created or the
+// registry is updated or deleted
+func (idx *RepositoryIndex) Invalidate(registryID uint) {
+	idx.invalidate(&indexInvalidation{RegistryID: registryID})
+}
+
+// InvalidateImageRepository drops the images of an image repository of a project, such
+// as when an image is pushed to the repository, so that the next listing of the images
+// includes the new tag. If the image repository is not indexed yet, the repositories of
+// the registries of the project are refreshed instead, since the image may have been
+// pushed to a new repository.
+func (idx *RepositoryIndex) InvalidateImageRepository(projectID uint, imageRepo string) {
+	idx.invalidate(&indexInvalidation{ProjectID: projectID, ImageRepo: imageRepo})
+}
+
+func (idx *RepositoryIndex) invalidate(inv *indexInvalidation) {
+	idx.applyInvalidation(inv)
+
+	if idx.redis == nil {
+		return
+	}
+
+	// if the invalidation cannot be published, the other replicas list the new images
+	// once their entries expire
+	if data, err := json.Marshal(inv); err == nil {
+		idx.redis.Publish(context.Background(), indexInvalidationsChannel, data)
+	}
+}
+
+func (idx *RepositoryIndex) applyInvalidation(inv *indexInvalidation) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if inv.RegistryID != 0 {
+		delete(idx.registries, inv.RegistryID)
+		return
+	}
+
+	imageRepo := normalizeImageRepo(inv.ImageRepo)
+
+	for _, ri := range idx.registries {
+		if ri.reg.ProjectID != inv.ProjectID {
+			continue
+		}
+
+		found := false
+
+		if repos, ok := ri.repositories.value.([]*ptypes.RegistryRepository); ok {
+			for _, repo := range repos {
+				if normalizeImageRepo(repo.URI) == imageRepo {
+					delete(ri.images, repo.Name)
+					found = true
+				}
+			}
+		}
+
+		if !found {
+			ri.repositories.expiresAt = time.Time{}
+		}
+	}
+}
+
+// normalizeImageRepo removes the scheme and tag of an image repository, so that the URIs
+// of repositories can be compared to the image repositories of releases
+func normalizeImageRepo(imageRepo string) string {
+	imageRepo = strings.TrimPrefix(strings.TrimPrefix(imageRepo, "https://"), "http://")
+
+	if i := strings.LastIndex(imageRepo, ":"); i > strings.LastIndex(imageRepo, "/") {
+		imageRepo = imageRepo[:i]
+	}
+
+	return imageRepo
+}
+
+// Run syncs the expired entries of the index at the given interval until the context is
+// cancelled, and drops the indexes of registries that have not been listed recently. If
+// Redis is enabled, the invalidations of the other replicas are applied as well.
+func (idx *RepositoryIndex) Run(ctx context.Context, interval time.Duration) {
+	if idx.redis != nil {
+		go idx.subscribe(ctx)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, ri := range idx.pruneAndGetSyncable() {
+				go idx.sync(ri)
+			}
+		}
+	}
+}
+
+// subscribe applies the invalidations that are published by the replicas until the
+// context is cancelled. The client reconnects to Redis if the connection is lost.
+func (idx *RepositoryIndex) subscribe(ctx context.Context) {
+	pubsub := idx.redis.Subscribe(ctx, indexInvalidationsChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			inv := &indexInvalidation{}
+
+			if err := json.Unmarshal([]byte(msg.Payload), inv); err == nil {
+				idx.applyInvalidation(inv)
+			}
+		}
+	}
+}
+
+func (idx *RepositoryIndex) pruneAndGetSyncable() []*registryIndex {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	res := make([]*registryIndex, 0)
+
+	for registryID, ri := range idx.registries {
+		if time.Since(ri.lastUsed) > registryIndexIdleTTL {
+			delete(idx.registries, registryID)
+		} else if !ri.syncing {
+			ri.syncing = true
+			res = append(res, ri)
+		}
+	}
+
+	return res
+}
+
+// sync refreshes the repositories of a registry if they have expired, and then refreshes
+// the expired images of the repositories with a bounded pool of workers. The images of
+// repositories that no longer exist are dropped.
+func (idx *RepositoryIndex) sync(ri *registryIndex) {
+	defer func() {
+		idx.mu.Lock()
+		ri.syncing = false
+		idx.mu.Unlock()
+	}()
+
+	idx.mu.Lock()
+	repositoriesDone := idx.refreshIfExpiredLocked(ri.repositories, idx.repositoriesLoader(ri))
+	idx.mu.Unlock()
+
+	if repositoriesDone != nil {
+		<-repositoriesDone
+	}
+
+	idx.mu.Lock()
+
+	if repos, ok := ri.repositories.value.([]*ptypes.RegistryRepository); ok && ri.repositories.loaded {
+		exists := make(map[string]bool)
+
+		for _, repo := range repos {
+			exists[repo.Name] = true
+		}
+
+		for repoName := range ri.images {
+			if !exists[repoName] {
+				delete(ri.images, repoName)
+			}
+		}
+	}
+
+	repoNames := make([]string, 0, len(ri.images))
+
+	for repoName := range ri.images {
+		repoNames = append(repoNames, repoName)
+	}
+
+	idx.mu.Unlock()
+
+	runConcurrently(len(repoNames), func(i int) error {
+		idx.mu.Lock()
+
+		var done chan struct{}
+
+		if entry, ok := ri.images[repoNames[i]]; ok {
+			done = idx.refreshIfExpiredLocked(entry, idx.imagesLoader(ri, repoNames[i]))
+		}
+
+		idx.mu.Unlock()
+
+		if done != nil {
+			<-done
+		}
+
+		return nil
+	})
+}
+
+// getRegistryLocked returns the index of a registry, and creates it if the registry is
+// not indexed yet. It must be called with the lock held.
+func (idx *RepositoryIndex) getRegistryLocked(reg *Registry) *registryIndex {
+	ri, ok := idx.registries[reg.ID]
+
+	if !ok {
+		ri = &registryIndex{
+			repositories: &indexEntry{},
+			images:       make(map[string]*indexEntry),
+		}
+
+		idx.registries[reg.ID] = ri
+	}
+
+	// the registry is replaced on each listing, since its credentials may have changed
+	ri.reg = reg
+	ri.lastUsed = time.Now()
+
+	return ri
+}
+
+// get returns the value of an entry. An entry that has not been loaded is loaded before
+// get returns, while an expired entry is returned and refreshed in the background.
+func (idx *RepositoryIndex) get(entry *indexEntry, load func() (interface{}, error)) (interface{}, error) {
+	idx.mu.Lock()
+
+	if entry.loaded {
+		idx.refreshIfExpiredLocked(entry, load)
+		value := entry.value
+		idx.mu.Unlock()
+
+		return value, nil
+	}
+
+	done := idx.refreshLocked(entry, load)
+	idx.mu.Unlock()
+
+	<-done
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !entry.loaded {
+		return nil, entry.err
+	}
+
+	return entry.value, nil
+}
+
+func (idx *RepositoryIndex) refreshIfExpiredLocked(entry *indexEntry, load func() (interface{}, error)) chan struct{} {
+	if entry.refreshing {
+		return entry.done
+	}
+
+	if entry.loaded && time.Now().Before(entry.expiresAt) {
+		return nil
+	}
+
+	return idx.refreshLocked(entry, load)
+}
+
+// refreshLocked loads an entry in the background unless it is already being loaded, and
+// returns a channel that is closed once the entry is loaded. If the entry cannot be
+// loaded, the previous value is kept and the entry is retried after indexRetryInterval.
+// It must be called with the lock held.
+func (idx *RepositoryIndex) refreshLocked(entry *indexEntry, load func() (interface{}, error)) chan struct{} {
+	if entry.refreshing {
+		return entry.done
+	}
+
+	entry.refreshing = true
+	entry.done = make(chan struct{})
+
+	done := entry.done
+
+	go func() {
+		value, err := load()
+
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+
+		entry.refreshing = false
+		entry.err = err
+
+		if err != nil {
+			entry.expiresAt = time.Now().Add(indexRetryInterval)
+		} else {
+			entry.value = value
+			entry.loaded = true
+			entry.expiresAt = time.Now().Add(idx.ttl)
+		}
+
+		close(done)
+	}()
+
+	return done
+}
+
+func (idx *RepositoryIndex) repositoriesLoader(ri *registryIndex) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		idx.mu.Lock()
+		reg := ri.reg
+		idx.mu.Unlock()
+
+		return idx.listRepositories(reg)
+	}
+}
+
+func (idx *RepositoryIndex) imagesLoader(ri *registryIndex, repoName string) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		idx.mu.Lock()
+		reg := ri.reg
+		idx.mu.Unlock()
+
+		return idx.listImages(reg, repoName)
+	}
+}
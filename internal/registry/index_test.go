@@ -0,0 +1,226 @@
+package registry
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ptypes "github.com/porter-dev/porter/api/types"
+)
+
+// testRegistry serves the repositories and tags of a registry to the index, and counts
+// the listings that reach the registry
+type testRegistry struct {
+	mu           sync.Mutex
+	tags         map[string][]string
+	repoListings int
+	tagListings  int
+}
+
+func newTestIndex(ttl time.Duration, tags map[string][]string) (*RepositoryIndex, *testRegistry) {
+	idx := NewRepositoryIndex(ttl, nil, nil, nil)
+	reg := &testRegistry{tags: tags}
+
+	idx.listRepositories = func(_ *Registry) ([]*ptypes.RegistryRepository, error) {
+		reg.mu.Lock()
+		defer reg.mu.Unlock()
+
+		reg.repoListings++
+
+		res := make([]*ptypes.RegistryRepository, 0)
+
+		for name := range reg.tags {
+			res = append(res, &ptypes.RegistryRepository{
+				Name: name,
+				URI:  "123.dkr.ecr.us-east-1.amazonaws.com/" + name,
+			})
+		}
+
+		return res, nil
+	}
+
+	idx.listImages = func(_ *Registry, repoName string) ([]*ptypes.Image, error) {
+		reg.mu.Lock()
+		defer reg.mu.Unlock()
+
+		reg.tagListings++
+
+		res := make([]*ptypes.Image, 0)
+
+		for _, tag := range reg.tags[repoName] {
+			res = append(res, &ptypes.Image{RepositoryName: repoName, Tag: tag})
+		}
+
+		return res, nil
+	}
+
+	return idx, reg
+}
+
+func (reg *testRegistry) push(repoName, tag string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.tags[repoName] = append(reg.tags[repoName], tag)
+}
+
+func (reg *testRegistry) listings() (int, int) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	return reg.repoListings, reg.tagListings
+}
+
+func getTestIndexRegistry() *Registry {
+	reg := &Registry{ProjectID: 1}
+	reg.ID = 1
+
+	return reg
+}
+
+func listTestIndexTags(t *testing.T, idx *RepositoryIndex, repoName string) []string {
+	imgs, err := idx.ListImages(getTestIndexRegistry(), repoName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := make([]string, 0)
+
+	for _, img := range imgs {
+		res = append(res, img.Tag)
+	}
+
+	return res
+}
+
+func TestRepositoryIndexCachesImages(t *testing.T) {
+	idx, reg := newTestIndex(time.Hour, map[string][]string{"web": {"v1"}})
+
+	listTestIndexTags(t, idx, "web")
+
+	if tags := listTestIndexTags(t, idx, "web"); len(tags) != 1 || tags[0] != "v1" {
+		t.Errorf("expected tag v1, got %v", tags)
+	}
+
+	if _, tagListings := reg.listings(); tagListings != 1 {
+		t.Errorf("expected the images to be listed from the registry once, got %d listings", tagListings)
+	}
+}
+
+func TestRepositoryIndexInvalidateImageRepository(t *testing.T) {
+	idx, reg := newTestIndex(time.Hour, map[string][]string{"web": {"v1"}})
+
+	if _, err := idx.ListRepositories(getTestIndexRegistry()); err != nil {
+		t.Fatal(err)
+	}
+
+	listTestIndexTags(t, idx, "web")
+
+	reg.push("web", "v2")
+
+	// the tag is not listed until the entry expires or the image repository is invalidated
+	if tags := listTestIndexTags(t, idx, "web"); len(tags) != 1 {
+		t.Errorf("expected the cached tags to be listed, got %v", tags)
+	}
+
+	idx.InvalidateImageRepository(1, "123.dkr.ecr.us-east-1.amazonaws.com/web:v2")
+
+	if tags := listTestIndexTags(t, idx, "web"); len(tags) != 2 || tags[1] != "v2" {
+		t.Errorf("expected the pushed tag to be listed after the image repository is invalidated, got %v", tags)
+	}
+
+	// image repositories of other projects do not invalidate the index of the registry
+	reg.push("web", "v3")
+	idx.InvalidateImageRepository(2, "123.dkr.ecr.us-east-1.amazonaws.com/web")
+
+	if tags := listTestIndexTags(t, idx, "web"); len(tags) != 2 {
+		t.Errorf("expected the tags of the registry to be kept, got %v", tags)
+	}
+}
+
+func TestRepositoryIndexInvalidateNewImageRepository(t *testing.T) {
+	idx, reg := newTestIndex(time.Hour, map[string][]string{"web": {"v1"}})
+
+	if _, err := idx.ListRepositories(getTestIndexRegistry()); err != nil {
+		t.Fatal(err)
+	}
+
+	reg.push("worker", "v1")
+	idx.InvalidateImageRepository(1, "123.dkr.ecr.us-east-1.amazonaws.com/worker")
+
+	// the repositories are refreshed in the background, and listed once they are loaded
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		repos, err := idx.ListRepositories(getTestIndexRegistry())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(repos) == 2 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the new repository to be listed, got %d repositories", len(repos))
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRepositoryIndexRefreshesExpiredImages(t *testing.T) {
+	idx, reg := newTestIndex(time.Millisecond, map[string][]string{"web": {"v1"}})
+
+	listTestIndexTags(t, idx, "web")
+	reg.push("web", "v2")
+
+	time.Sleep(5 * time.Millisecond)
+
+	// the expired tags are served while they are refreshed in the background
+	deadline := time.Now().Add(5 * time.Second)
+
+	for len(listTestIndexTags(t, idx, "web")) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the pushed tag to be listed once the entry is refreshed")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRepositoryIndexApplyInvalidation(t *testing.T) {
+	idx, reg := newTestIndex(time.Hour, map[string][]string{"web": {"v1"}})
+
+	listTestIndexTags(t, idx, "web")
+	reg.push("web", "v2")
+
+	// invalidations of other replicas drop the index of the registry
+	idx.applyInvalidation(&indexInvalidation{RegistryID: 1})
+
+	if tags := listTestIndexTags(t, idx, "web"); len(tags) != 2 {
+		t.Errorf("expected the tags to be listed again after the registry is invalidated, got %v", tags)
+	}
+
+	if _, tagListings := reg.listings(); tagListings != 2 {
+		t.Errorf("expected the images to be listed from the registry twice, got %d listings", tagListings)
+	}
+}
+
+func TestNormalizeImageRepo(t *testing.T) {
+	tests := map[string]string{
+		"123.dkr.ecr.us-east-1.amazonaws.com/web":      "123.dkr.ecr.us-east-1.amazonaws.com/web",
+		"123.dkr.ecr.us-east-1.amazonaws.com/web:v1":   "123.dkr.ecr.us-east-1.amazonaws.com/web",
+		"https://registry.digitalocean.com/porter/web": "registry.digitalocean.com/porter/web",
+		"localhost:5000/web":                           "localhost:5000/web",
+		"localhost:5000/web:latest":                    "localhost:5000/web",
+	}
+
+	for imageRepo, expected := range tests {
+		if res := normalizeImageRepo(imageRepo); res != expected {
+			t.Errorf("expected %s to be normalized to %s, got %s", imageRepo, expected, res)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+)
+
+// registryPageSize is the number of repositories or tags that are requested per page from
+// registries that implement the Docker registry http api
+const registryPageSize = 100
+
+// maxConcurrentFetches bounds the number of concurrent requests that are made to a
+// registry, such as requests for the images of several repositories
+const maxConcurrentFetches = 8
+
+var nextLinkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="?next"?`)
+
+// getPaginated requests each page of a paginated Docker registry http api endpoint, and
+// calls readPage with the response of each page. Pages are followed through the Link
+// header of each response.
+func getPaginated(
+	client *http.Client,
+	reqURL string,
+	setAuth func(req *http.Request),
+	readPage func(resp *http.Response) error,
+) error {
+	parsedURL, err := url.Parse(reqURL)
+
+	if err != nil {
+		return err
+	}
+
+	query := parsedURL.Query()
+	query.Set("n", fmt.Sprintf("%d", registryPageSize))
+	parsedURL.RawQuery = query.Encode()
+
+	nextURL := parsedURL
+
+	// registries should not return more pages than this, so more pages are treated as
+	// a loop in the pagination of the registry
+	for page := 0; page < 10000; page++ {
+		req, err := http.NewRequest("GET", nextURL.String(), nil)
+
+		if err != nil {
+			return err
+		}
+
+		setAuth(req)
+
+		resp, err := client.Do(req)
+
+		if err != nil {
+			return err
+		}
+
+		err = readPage(resp)
+		resp.Body.Close()
+
+		if err != nil {
+			return err
+		}
+
+		match := nextLinkRegex.FindStringSubmatch(resp.Header.Get("Link"))
+
+		if match == nil {
+			return nil
+		}
+
+		// the next link is relative to the registry host
+		nextURL, err = parsedURL.Parse(match[1])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("registry returned too many pages for %s", parsedURL.Path)
+}
+
+// runConcurrently calls fn for each index from 0 to count with a bounded pool of workers,
+// and returns the first error
+func runConcurrently(count int, fn func(i int) error) error {
+	workers := maxConcurrentFetches
+
+	if workers > count {
+		workers = count
+	}
+
+	indices := make(chan int)
+	errs := make(chan error, count)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				if err := fn(i); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		indices <- i
+	}
+
+	close(indices)
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
@@ -129,37 +129,38 @@ func (r *Registry) listGCRRepositories(
 	// for oauth. This also prevents us from making more requests.
 	client := &http.Client{}
 
-	req, err := http.NewRequest(
-		"GET",
+	repoNames := make([]string, 0)
+
+	err = getPaginated(
+		client,
 		"https://gcr.io/v2/_catalog",
-		nil,
-	)
+		func(req *http.Request) {
+			req.SetBasicAuth("_json_key", string(gcp.GCPKeyData))
+		},
+		func(resp *http.Response) error {
+			gcrResp := gcrRepositoryResp{}
 
-	if err != nil {
-		return nil, err
-	}
+			if err := json.NewDecoder(resp.Body).Decode(&gcrResp); err != nil {
+				return fmt.Errorf("Could not read GCR repositories: %v", err)
+			}
 
-	req.SetBasicAuth("_json_key", string(gcp.GCPKeyData))
+			if len(gcrResp.Errors) > 0 {
+				errMsg := ""
+				for _, gcrErr := range gcrResp.Errors {
+					errMsg += fmt.Sprintf(": Code %s, message %s", gcrErr.Code, gcrErr.Message)
+				}
 
-	resp, err := client.Do(req)
+				return fmt.Errorf(errMsg)
+			}
 
-	if err != nil {
-		return nil, err
-	}
-
-	gcrResp := gcrRepositoryResp{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&gcrResp); err != nil {
-		return nil, fmt.Errorf("Could not read GCR repositories: %v", err)
-	}
+			repoNames = append(repoNames, gcrResp.Repositories...)
 
-	if len(gcrResp.Errors) > 0 {
-		errMsg := ""
-		for _, gcrErr := range gcrResp.Errors {
-			errMsg += fmt.Sprintf(": Code %s, message %s", gcrErr.Code, gcrErr.Message)
-		}
+			return nil
+		},
+	)
 
-		return nil, fmt.Errorf(errMsg)
+	if err != nil {
+		return nil, err
 	}
 
 	res := make([]*ptypes.RegistryRepository, 0)
@@ -170,7 +171,7 @@ func (r *Registry) listGCRRepositories(
 		return nil, err
 	}
 
-	for _, repo := range gcrResp.Repositories {
+	for _, repo := range repoNames {
 		res = append(res, &ptypes.RegistryRepository{
 			Name: repo,
 			URI:  parsedURL.Host + "/" + repo,
@@ -198,22 +199,27 @@ func (r *Registry) listECRRepositories(repo repository.Repository) ([]*ptypes.Re
 
 	svc := ecr.New(sess)
 
-	resp, err := svc.DescribeRepositories(&ecr.DescribeRepositoriesInput{})
+	res := make([]*ptypes.RegistryRepository, 0)
+
+	err = svc.DescribeRepositoriesPages(
+		&ecr.DescribeRepositoriesInput{},
+		func(page *ecr.DescribeRepositoriesOutput, lastPage bool) bool {
+			for _, repo := range page.Repositories {
+				res = append(res, &ptypes.RegistryRepository{
+					Name:      *repo.RepositoryName,
+					CreatedAt: *repo.CreatedAt,
+					URI:       *repo.RepositoryUri,
+				})
+			}
+
+			return true
+		},
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	res := make([]*ptypes.RegistryRepository, 0)
-
-	for _, repo := range resp.Repositories {
-		res = append(res, &ptypes.RegistryRepository{
-			Name:      *repo.RepositoryName,
-			CreatedAt: *repo.CreatedAt,
-			URI:       *repo.RepositoryUri,
-		})
-	}
-
 	return res, nil
 }
 
@@ -246,22 +252,35 @@ func (r *Registry) listDOCRRepositories(
 
 	name := urlArr[1]
 
-	repos, _, err := client.Registry.ListRepositories(context.TODO(), name, &godo.ListOptions{})
+	res := make([]*ptypes.RegistryRepository, 0)
+	opts := &godo.ListOptions{PerPage: registryPageSize}
 
-	if err != nil {
-		return nil, err
-	}
+	for {
+		repos, resp, err := client.Registry.ListRepositories(context.TODO(), name, opts)
 
-	res := make([]*ptypes.RegistryRepository, 0)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, repo := range repos {
-		res = append(res, &ptypes.RegistryRepository{
-			Name: repo.Name,
-			URI:  r.URL + "/" + repo.Name,
-		})
-	}
+		for _, repo := range repos {
+			res = append(res, &ptypes.RegistryRepository{
+				Name: repo.Name,
+				URI:  r.URL + "/" + repo.Name,
+			})
+		}
 
-	return res, nil
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			return res, nil
+		}
+
+		page, err := resp.Links.CurrentPage()
+
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Page = page + 1
+	}
 }
 
 func (r *Registry) listPrivateRegistryRepositories(
@@ -296,26 +315,45 @@ func (r *Registry) listPrivateRegistryRepositories(
 	// get the host and scheme to make the request
 	parsedURL, err := url.Parse(r.URL)
 
-	req, err := http.NewRequest(
-		"GET",
-		fmt.Sprintf("%s://%s/v2/_catalog", parsedURL.Scheme, parsedURL.Host),
-		nil,
-	)
-
 	if err != nil {
 		return nil, err
 	}
 
-	req.SetBasicAuth(string(basic.Username), string(basic.Password))
+	setAuth := func(req *http.Request) {
+		req.SetBasicAuth(string(basic.Username), string(basic.Password))
+	}
 
-	resp, err := client.Do(req)
+	repoNames := make([]string, 0)
+	notFound := false
+
+	err = getPaginated(
+		client,
+		fmt.Sprintf("%s://%s/v2/_catalog", parsedURL.Scheme, parsedURL.Host),
+		setAuth,
+		func(resp *http.Response) error {
+			if resp.StatusCode == 404 {
+				notFound = true
+				return nil
+			}
+
+			gcrResp := gcrRepositoryResp{}
+
+			if err := json.NewDecoder(resp.Body).Decode(&gcrResp); err != nil {
+				return fmt.Errorf("Could not read private registry repositories: %v", err)
+			}
+
+			repoNames = append(repoNames, gcrResp.Repositories...)
+
+			return nil
+		},
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
 	// if the status code is 404, fallback to the Docker Hub implementation
-	if resp.StatusCode == 404 {
+	if notFound {
 		req, err := http.NewRequest(
 			"GET",
 			fmt.Sprintf("%s/", r.URL),
@@ -326,28 +364,28 @@ func (r *Registry) listPrivateRegistryRepositories(
 			return nil, err
 		}
 
-		req.SetBasicAuth(string(basic.Username), string(basic.Password))
+		setAuth(req)
 
-		resp, err = client.Do(req)
+		resp, err := client.Do(req)
 
 		if err != nil {
 			return nil, err
 		}
-	}
 
-	gcrResp := gcrRepositoryResp{}
+		defer resp.Body.Close()
 
-	if err := json.NewDecoder(resp.Body).Decode(&gcrResp); err != nil {
-		return nil, fmt.Errorf("Could not read private registry repositories: %v", err)
-	}
+		gcrResp := gcrRepositoryResp{}
 
-	res := make([]*ptypes.RegistryRepository, 0)
+		if err := json.NewDecoder(resp.Body).Decode(&gcrResp); err != nil {
+			return nil, fmt.Errorf("Could not read private registry repositories: %v", err)
+		}
 
-	if err != nil {
-		return nil, err
+		repoNames = gcrResp.Repositories
 	}
 
-	for _, repo := range gcrResp.Repositories {
+	res := make([]*ptypes.RegistryRepository, 0)
+
+	for _, repo := range repoNames {
 		res = append(res, &ptypes.RegistryRepository{
 			Name: repo,
 			URI:  parsedURL.Host + "/" + repo,
@@ -488,39 +526,59 @@ func (r *Registry) listECRImages(repoName string, repo repository.Repository) ([
 
 	svc := ecr.New(sess)
 
-	resp, err := svc.ListImages(&ecr.ListImagesInput{
-		RepositoryName: &repoName,
-	})
+	imageIDs := make([]*ecr.ImageIdentifier, 0)
+
+	err = svc.ListImagesPages(
+		&ecr.ListImagesInput{
+			RepositoryName: &repoName,
+		},
+		func(page *ecr.ListImagesOutput, lastPage bool) bool {
+			imageIDs = append(imageIDs, page.ImageIds...)
+			return true
+		},
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	describeResp, err := svc.DescribeImages(&ecr.DescribeImagesInput{
-		RepositoryName: &repoName,
-		ImageIds:       resp.ImageIds,
-	})
+	// DescribeImages accepts at most 100 image ids per request, so the images are
+	// described in batches which are requested concurrently
+	batches := make([][]*ecr.ImageIdentifier, 0)
 
-	if err != nil {
-		return nil, err
+	for i := 0; i < len(imageIDs); i += ecrDescribeImagesBatchSize {
+		end := i + ecrDescribeImagesBatchSize
+
+		if end > len(imageIDs) {
+			end = len(imageIDs)
+		}
+
+		batches = append(batches, imageIDs[i:end])
 	}
 
-	imageDetails := describeResp.ImageDetails
+	batchDetails := make([][]*ecr.ImageDetail, len(batches))
 
-	nextToken := describeResp.NextToken
+	err = runConcurrently(len(batches), func(i int) error {
+		return svc.DescribeImagesPages(
+			&ecr.DescribeImagesInput{
+				RepositoryName: &repoName,
+				ImageIds:       batches[i],
+			},
+			func(page *ecr.DescribeImagesOutput, lastPage bool) bool {
+				batchDetails[i] = append(batchDetails[i], page.ImageDetails...)
+				return true
+			},
+		)
+	})
 
-	for nextToken != nil {
-		describeResp, err := svc.DescribeImages(&ecr.DescribeImagesInput{
-			RepositoryName: &repoName,
-			ImageIds:       resp.ImageIds,
-		})
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	imageDetails := make([]*ecr.ImageDetail, 0, len(imageIDs))
 
-		nextToken = describeResp.NextToken
-		imageDetails = append(imageDetails, describeResp.ImageDetails...)
+	for _, details := range batchDetails {
+		imageDetails = append(imageDetails, details...)
 	}
 
 	res := make([]*ptypes.Image, 0)
@@ -543,6 +601,10 @@ type gcrImageResp struct {
 	Tags []string `json:"tags"`
 }
 
+// ecrDescribeImagesBatchSize is the maximum number of image ids that can be described in
+// a single DescribeImages request
+const ecrDescribeImagesBatchSize = 100
+
 func (r *Registry) listGCRImages(repoName string, repo repository.Repository) ([]*ptypes.Image, error) {
 	gcp, err := repo.GCPIntegration().ReadGCPIntegration(
 		r.ProjectID,
@@ -564,39 +626,36 @@ func (r *Registry) listGCRImages(repoName string, repo repository.Repository) ([
 
 	trimmedPath := strings.Trim(parsedURL.Path, "/")
 
-	req, err := http.NewRequest(
-		"GET",
+	res := make([]*ptypes.Image, 0)
+
+	err = getPaginated(
+		client,
 		fmt.Sprintf("https://%s/v2/%s/%s/tags/list", parsedURL.Host, trimmedPath, repoName),
-		nil,
-	)
+		func(req *http.Request) {
+			req.SetBasicAuth("_json_key", string(gcp.GCPKeyData))
+		},
+		func(resp *http.Response) error {
+			gcrResp := gcrImageResp{}
 
-	if err != nil {
-		return nil, err
-	}
+			if err := json.NewDecoder(resp.Body).Decode(&gcrResp); err != nil {
+				return fmt.Errorf("Could not read GCR repositories: %v", err)
+			}
 
-	req.SetBasicAuth("_json_key", string(gcp.GCPKeyData))
+			for _, tag := range gcrResp.Tags {
+				res = append(res, &ptypes.Image{
+					RepositoryName: repoName,
+					Tag:            tag,
+				})
+			}
 
-	resp, err := client.Do(req)
+			return nil
+		},
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	gcrResp := gcrImageResp{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&gcrResp); err != nil {
-		return nil, fmt.Errorf("Could not read GCR repositories: %v", err)
-	}
-
-	res := make([]*ptypes.Image, 0)
-
-	for _, tag := range gcrResp.Tags {
-		res = append(res, &ptypes.Image{
-			RepositoryName: repoName,
-			Tag:            tag,
-		})
-	}
-
 	return res, nil
 }
 
@@ -630,22 +689,35 @@ func (r *Registry) listDOCRImages(
 
 	name := urlArr[1]
 
-	tags, _, err := client.Registry.ListRepositoryTags(context.TODO(), name, repoName, &godo.ListOptions{})
+	res := make([]*ptypes.Image, 0)
+	opts := &godo.ListOptions{PerPage: registryPageSize}
 
-	if err != nil {
-		return nil, err
-	}
+	for {
+		tags, resp, err := client.Registry.ListRepositoryTags(context.TODO(), name, repoName, opts)
 
-	res := make([]*ptypes.Image, 0)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, tag := range tags {
-		res = append(res, &ptypes.Image{
-			RepositoryName: repoName,
-			Tag:            tag.Tag,
-		})
-	}
+		for _, tag := range tags {
+			res = append(res, &ptypes.Image{
+				RepositoryName: repoName,
+				Tag:            tag.Tag,
+			})
+		}
 
-	return res, nil
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			return res, nil
+		}
+
+		page, err := resp.Links.CurrentPage()
+
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Page = page + 1
+	}
 }
 
 func (r *Registry) listPrivateRegistryImages(repoName string, repo repository.Repository) ([]*ptypes.Image, error) {
@@ -670,37 +742,38 @@ func (r *Registry) listPrivateRegistryImages(repoName string, repo repository.Re
 	// get the host and scheme to make the request
 	parsedURL, err := url.Parse(r.URL)
 
-	req, err := http.NewRequest(
-		"GET",
-		fmt.Sprintf("%s://%s/v2/%s/tags/list", parsedURL.Scheme, parsedURL.Host, repoName),
-		nil,
-	)
-
 	if err != nil {
 		return nil, err
 	}
 
-	req.SetBasicAuth(string(basic.Username), string(basic.Password))
-
-	resp, err := client.Do(req)
+	res := make([]*ptypes.Image, 0)
 
-	if err != nil {
-		return nil, err
-	}
+	err = getPaginated(
+		client,
+		fmt.Sprintf("%s://%s/v2/%s/tags/list", parsedURL.Scheme, parsedURL.Host, repoName),
+		func(req *http.Request) {
+			req.SetBasicAuth(string(basic.Username), string(basic.Password))
+		},
+		func(resp *http.Response) error {
+			gcrResp := gcrImageResp{}
 
-	gcrResp := gcrImageResp{}
+			if err := json.NewDecoder(resp.Body).Decode(&gcrResp); err != nil {
+				return fmt.Errorf("Could not read private registry repositories: %v", err)
+			}
 
-	if err := json.NewDecoder(resp.Body).Decode(&gcrResp); err != nil {
-		return nil, fmt.Errorf("Could not read private registry repositories: %v", err)
-	}
+			for _, tag := range gcrResp.Tags {
+				res = append(res, &ptypes.Image{
+					RepositoryName: repoName,
+					Tag:            tag,
+				})
+			}
 
-	res := make([]*ptypes.Image, 0)
+			return nil
+		},
+	)
 
-	for _, tag := range gcrResp.Tags {
-		res = append(res, &ptypes.Image{
-			RepositoryName: repoName,
-			Tag:            tag,
-		})
+	if err != nil {
+		return nil, err
 	}
 
 	return res, nil
@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// AddonRepository represents the set of queries on the Addon model
+type AddonRepository interface {
+	CreateAddon(addon *models.Addon) (*models.Addon, error)
+	ReadAddon(clusterID, id uint) (*models.Addon, error)
+	ReadAddonByName(clusterID uint, namespace, name string) (*models.Addon, error)
+	ListAddonsByClusterID(clusterID uint) ([]*models.Addon, error)
+	ListAddons() ([]*models.Addon, error)
+	UpdateAddon(addon *models.Addon) (*models.Addon, error)
+	DeleteAddon(addon *models.Addon) (*models.Addon, error)
+}
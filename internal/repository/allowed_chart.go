@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// AllowedChartRepository represents the set of queries on the chart allow-lists of
+// projects
+type AllowedChartRepository interface {
+	CreateAllowedChart(allowed *models.AllowedChart) (*models.AllowedChart, error)
+	ReadAllowedChart(projectID, id uint) (*models.AllowedChart, error)
+	ListAllowedChartsByProjectID(projectID uint) ([]*models.AllowedChart, error)
+	DeleteAllowedChart(allowed *models.AllowedChart) error
+}
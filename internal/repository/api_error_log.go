@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// APIErrorLogRepository represents the set of queries on the APIErrorLog model
+type APIErrorLogRepository interface {
+	CreateAPIErrorLog(log *models.APIErrorLog) (*models.APIErrorLog, error)
+	ListAPIErrorLogsByProjectID(projectID uint, opts *types.ListAPIErrorLogsRequest) ([]*models.APIErrorLog, error)
+	DeleteAPIErrorLogsBefore(projectID uint, before time.Time) error
+}
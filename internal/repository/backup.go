@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// BackupRepository represents the set of queries on the tracked Velero backups of a
+// cluster
+type BackupRepository interface {
+	CreateBackup(backup *models.Backup) (*models.Backup, error)
+	ReadBackupByName(clusterID uint, name string) (*models.Backup, error)
+	ListBackupsByClusterID(clusterID uint) ([]*models.Backup, error)
+	UpdateBackup(backup *models.Backup) (*models.Backup, error)
+	DeleteBackup(backup *models.Backup) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// BucketRepository represents the set of queries on the object storage buckets
+// provisioned by Porter
+type BucketRepository interface {
+	ProvisionedResourceRepository
+
+	CreateBucket(bucket *models.Bucket) (*models.Bucket, error)
+	ReadBucket(projectID, clusterID, bucketID uint) (*models.Bucket, error)
+	ListBuckets(projectID, clusterID uint) ([]*models.Bucket, error)
+}
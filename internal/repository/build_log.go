@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// BuildLogRepository represents the set of queries on the BuildLog model
+type BuildLogRepository interface {
+	CreateBuildLog(buildLog *models.BuildLog) (*models.BuildLog, error)
+	ReadBuildLog(releaseID uint, commitSHA string) (*models.BuildLog, error)
+	ListBuildLogsByReleaseID(releaseID uint) ([]*models.BuildLog, error)
+}
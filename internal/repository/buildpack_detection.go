@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// BuildpackDetectionRepository represents the set of queries on the stored results of
+// buildpack detection
+type BuildpackDetectionRepository interface {
+	CreateBuildpackDetection(detection *models.BuildpackDetection) (*models.BuildpackDetection, error)
+	ReadBuildpackDetectionByCommit(projectID, gitInstallationID uint, owner, name, folderPath, commitSHA string) (*models.BuildpackDetection, error)
+	ReadLatestBuildpackDetection(projectID, gitInstallationID uint, owner, name, branch, folderPath string) (*models.BuildpackDetection, error)
+}
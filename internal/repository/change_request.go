@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// ChangeRequestRepository represents the set of queries on release change requests and
+// protected namespaces
+type ChangeRequestRepository interface {
+	CreateChangeRequest(cr *models.ReleaseChangeRequest) (*models.ReleaseChangeRequest, error)
+	ReadChangeRequest(clusterID, id uint) (*models.ReleaseChangeRequest, error)
+	ListChangeRequests(clusterID uint, namespace, name string, statuses ...types.ChangeRequestStatus) ([]*models.ReleaseChangeRequest, error)
+	UpdateChangeRequest(cr *models.ReleaseChangeRequest) (*models.ReleaseChangeRequest, error)
+	IsNamespaceProtected(clusterID uint, namespace string) (bool, error)
+	SetNamespaceProtected(clusterID uint, namespace string, protected bool) error
+}
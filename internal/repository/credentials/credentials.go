@@ -1,6 +1,9 @@
 package credentials
 
-import "github.com/porter-dev/porter/internal/models/integrations"
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/models/integrations"
+)
 
 type OAuthCredential struct {
 	// The ID issued to the client
@@ -33,6 +36,22 @@ type AWSCredential struct {
 	AWSSessionToken []byte `json:"aws_session_token"`
 }
 
+type SensitiveValuesCredential struct {
+	// The encrypted sensitive values of a release
+	Values []byte `json:"values"`
+}
+
+type ProvisionedResourceCredential struct {
+	// The AWS access key of the IAM user of an S3 bucket or an SQS queue
+	AWSAccessKeyID []byte `json:"aws_access_key_id"`
+
+	// The AWS secret key of the IAM user of an S3 bucket or an SQS queue
+	AWSSecretAccessKey []byte `json:"aws_secret_access_key"`
+
+	// The key data of the service account of a GCS bucket or a Pub/Sub topic
+	GCPKeyData []byte `json:"gcp_key_data"`
+}
+
 type CredentialStorage interface {
 	WriteOAuthCredential(oauthIntegration *integrations.OAuthIntegration, data *OAuthCredential) error
 	GetOAuthCredential(oauthIntegration *integrations.OAuthIntegration) (*OAuthCredential, error)
@@ -43,4 +62,8 @@ type CredentialStorage interface {
 	WriteAWSCredential(awsIntegration *integrations.AWSIntegration, data *AWSCredential) error
 	GetAWSCredential(awsIntegration *integrations.AWSIntegration) (*AWSCredential, error)
 	CreateAWSToken(awsIntegration *integrations.AWSIntegration) (string, error)
+	WriteSensitiveValuesCredential(sensitiveValues *models.SensitiveValues, data *SensitiveValuesCredential) error
+	GetSensitiveValuesCredential(sensitiveValues *models.SensitiveValues) (*SensitiveValuesCredential, error)
+	WriteProvisionedResourceCredential(resource *models.ProvisionedResource, data *ProvisionedResourceCredential) error
+	GetProvisionedResourceCredential(resource *models.ProvisionedResource) (*ProvisionedResourceCredential, error)
 }
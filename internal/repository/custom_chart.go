@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// CustomChartRepository represents the set of queries on the custom charts of projects
+type CustomChartRepository interface {
+	CreateCustomChart(customChart *models.CustomChart) (*models.CustomChart, error)
+	ReadCustomChart(projectID, id uint) (*models.CustomChart, error)
+	ReadCustomChartByVersion(projectID uint, name, version string) (*models.CustomChart, error)
+	ListCustomChartsByProjectID(projectID uint) ([]*models.CustomChart, error)
+	DeleteCustomChart(customChart *models.CustomChart) (*models.CustomChart, error)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// DefaultValuesRepository represents the set of queries on the default values of
+// projects and clusters. Project-level default values have a cluster id of 0.
+type DefaultValuesRepository interface {
+	ReadDefaultValues(projectID, clusterID uint) (*models.DefaultValues, error)
+	UpdateDefaultValues(defaultValues *models.DefaultValues) (*models.DefaultValues, error)
+}
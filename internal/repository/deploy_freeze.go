@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// DeployFreezeRepository represents the set of queries on deploy freezes and the
+// overrides of deploy freezes
+type DeployFreezeRepository interface {
+	CreateDeployFreeze(freeze *models.DeployFreeze) (*models.DeployFreeze, error)
+	ReadDeployFreeze(projectID, freezeID uint) (*models.DeployFreeze, error)
+	ListDeployFreezesByProjectID(projectID uint) ([]*models.DeployFreeze, error)
+	DeleteDeployFreeze(freeze *models.DeployFreeze) (*models.DeployFreeze, error)
+	CreateDeployFreezeOverride(override *models.DeployFreezeOverride) (*models.DeployFreezeOverride, error)
+	ListDeployFreezeOverridesByProjectID(projectID uint) ([]*models.DeployFreezeOverride, error)
+}
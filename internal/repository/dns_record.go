@@ -8,4 +8,6 @@ import (
 // DNSRecord model
 type DNSRecordRepository interface {
 	CreateDNSRecord(record *models.DNSRecord) (*models.DNSRecord, error)
+	ListDNSRecordsByRelease(clusterID uint, namespace, releaseName string) ([]*models.DNSRecord, error)
+	DeleteDNSRecord(record *models.DNSRecord) error
 }
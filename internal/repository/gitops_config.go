@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// GitOpsConfigRepository represents the set of queries on the git repositories that the
+// releases of projects are mirrored to
+type GitOpsConfigRepository interface {
+	ReadGitOpsConfig(projectID uint) (*models.GitOpsConfig, error)
+	ListGitOpsConfigs() ([]*models.GitOpsConfig, error)
+	UpdateGitOpsConfig(conf *models.GitOpsConfig) (*models.GitOpsConfig, error)
+	ClaimGitOpsReconcile(conf *models.GitOpsConfig, expiresAt time.Time) (bool, error)
+	DeleteGitOpsConfig(conf *models.GitOpsConfig) error
+}
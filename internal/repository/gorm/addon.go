@@ -0,0 +1,94 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AddonRepository uses gorm.DB for querying the database
+type AddonRepository struct {
+	db *gorm.DB
+}
+
+// NewAddonRepository returns an AddonRepository which uses
+// gorm.DB for querying the database
+func NewAddonRepository(db *gorm.DB) repository.AddonRepository {
+	return &AddonRepository{db}
+}
+
+// CreateAddon creates a new addon
+func (repo *AddonRepository) CreateAddon(addon *models.Addon) (*models.Addon, error) {
+	if err := repo.db.Create(addon).Error; err != nil {
+		return nil, err
+	}
+
+	return addon, nil
+}
+
+// ReadAddon finds an addon by cluster id and id
+func (repo *AddonRepository) ReadAddon(clusterID, id uint) (*models.Addon, error) {
+	addon := &models.Addon{}
+
+	if err := repo.db.Where("cluster_id = ? AND id = ?", clusterID, id).First(addon).Error; err != nil {
+		return nil, err
+	}
+
+	return addon, nil
+}
+
+// ReadAddonByName finds an addon by cluster id, namespace and release name
+func (repo *AddonRepository) ReadAddonByName(clusterID uint, namespace, name string) (*models.Addon, error) {
+	addon := &models.Addon{}
+
+	if err := repo.db.Where(
+		"cluster_id = ? AND namespace = ? AND name = ?",
+		clusterID,
+		namespace,
+		name,
+	).First(addon).Error; err != nil {
+		return nil, err
+	}
+
+	return addon, nil
+}
+
+// ListAddonsByClusterID finds all addons installed in a cluster
+func (repo *AddonRepository) ListAddonsByClusterID(clusterID uint) ([]*models.Addon, error) {
+	addons := []*models.Addon{}
+
+	if err := repo.db.Where("cluster_id = ?", clusterID).Order("id asc").Find(&addons).Error; err != nil {
+		return nil, err
+	}
+
+	return addons, nil
+}
+
+// ListAddons finds all addons across clusters
+func (repo *AddonRepository) ListAddons() ([]*models.Addon, error) {
+	addons := []*models.Addon{}
+
+	if err := repo.db.Order("id asc").Find(&addons).Error; err != nil {
+		return nil, err
+	}
+
+	return addons, nil
+}
+
+// UpdateAddon modifies an existing addon in the database
+func (repo *AddonRepository) UpdateAddon(addon *models.Addon) (*models.Addon, error) {
+	if err := repo.db.Save(addon).Error; err != nil {
+		return nil, err
+	}
+
+	return addon, nil
+}
+
+// DeleteAddon deletes an addon
+func (repo *AddonRepository) DeleteAddon(addon *models.Addon) (*models.Addon, error) {
+	if err := repo.db.Delete(addon).Error; err != nil {
+		return nil, err
+	}
+
+	return addon, nil
+}
@@ -0,0 +1,54 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AllowedChartRepository uses gorm.DB for querying the database
+type AllowedChartRepository struct {
+	db *gorm.DB
+}
+
+// NewAllowedChartRepository returns an AllowedChartRepository which uses
+// gorm.DB for querying the database
+func NewAllowedChartRepository(db *gorm.DB) repository.AllowedChartRepository {
+	return &AllowedChartRepository{db}
+}
+
+// CreateAllowedChart adds an entry to the chart allow-list of a project
+func (repo *AllowedChartRepository) CreateAllowedChart(allowed *models.AllowedChart) (*models.AllowedChart, error) {
+	if err := repo.db.Create(allowed).Error; err != nil {
+		return nil, err
+	}
+
+	return allowed, nil
+}
+
+// ReadAllowedChart reads an entry of the chart allow-list of a project
+func (repo *AllowedChartRepository) ReadAllowedChart(projectID, id uint) (*models.AllowedChart, error) {
+	allowed := &models.AllowedChart{}
+
+	if err := repo.db.Where("project_id = ? AND id = ?", projectID, id).First(allowed).Error; err != nil {
+		return nil, err
+	}
+
+	return allowed, nil
+}
+
+// ListAllowedChartsByProjectID lists the entries of the chart allow-list of a project
+func (repo *AllowedChartRepository) ListAllowedChartsByProjectID(projectID uint) ([]*models.AllowedChart, error) {
+	allowed := []*models.AllowedChart{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Find(&allowed).Error; err != nil {
+		return nil, err
+	}
+
+	return allowed, nil
+}
+
+// DeleteAllowedChart removes an entry from the chart allow-list of a project
+func (repo *AllowedChartRepository) DeleteAllowedChart(allowed *models.AllowedChart) error {
+	return repo.db.Delete(allowed).Error
+}
@@ -0,0 +1,66 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// APIErrorLogRepository uses gorm.DB for querying the database
+type APIErrorLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIErrorLogRepository returns an APIErrorLogRepository which uses
+// gorm.DB for querying the database
+func NewAPIErrorLogRepository(db *gorm.DB) repository.APIErrorLogRepository {
+	return &APIErrorLogRepository{db}
+}
+
+// CreateAPIErrorLog creates a new API error log
+func (repo *APIErrorLogRepository) CreateAPIErrorLog(log *models.APIErrorLog) (*models.APIErrorLog, error) {
+	if err := repo.db.Create(log).Error; err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+// ListAPIErrorLogsByProjectID lists the most recent API error logs of a project,
+// optionally filtered by category
+func (repo *APIErrorLogRepository) ListAPIErrorLogsByProjectID(
+	projectID uint,
+	opts *types.ListAPIErrorLogsRequest,
+) ([]*models.APIErrorLog, error) {
+	logs := make([]*models.APIErrorLog, 0)
+
+	query := repo.db.Where("project_id = ?", projectID)
+
+	if opts.Category != "" {
+		query = query.Where("category = ?", opts.Category)
+	}
+
+	limit := opts.Limit
+
+	if limit <= 0 || limit > types.MaxListedAPIErrorLogs {
+		limit = types.MaxListedAPIErrorLogs
+	}
+
+	if err := query.Order("id desc").Limit(limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// DeleteAPIErrorLogsBefore deletes the API error logs of a project that were created
+// before the given time
+func (repo *APIErrorLogRepository) DeleteAPIErrorLogsBefore(projectID uint, before time.Time) error {
+	return repo.db.Unscoped().Where(
+		"project_id = ? AND created_at < ?",
+		projectID, before,
+	).Delete(&models.APIErrorLog{}).Error
+}
@@ -1,6 +1,8 @@
 package gorm
 
 import (
+	"time"
+
 	"github.com/porter-dev/porter/internal/models"
 	"github.com/porter-dev/porter/internal/repository"
 	"github.com/porter-dev/porter/internal/repository/credentials"
@@ -1213,12 +1215,14 @@ func (repo *AWSIntegrationRepository) DecryptAWSIntegrationData(
 
 // GithubAppInstallationRepository implements repository.GithubAppInstallationRepository
 type GithubAppInstallationRepository struct {
-	db *gorm.DB
+	db  *gorm.DB
+	key *[32]byte
 }
 
-// NewGithubAppInstallationRepository creates a new GithubAppInstallationRepository
-func NewGithubAppInstallationRepository(db *gorm.DB) repository.GithubAppInstallationRepository {
-	return &GithubAppInstallationRepository{db}
+// NewGithubAppInstallationRepository creates a new GithubAppInstallationRepository. It
+// accepts an encryption key to encrypt the cached access tokens of installations
+func NewGithubAppInstallationRepository(db *gorm.DB, key *[32]byte) repository.GithubAppInstallationRepository {
+	return &GithubAppInstallationRepository{db, key}
 }
 
 // CreateGithubAppInstallation creates a new GithubAppInstallation instance
@@ -1237,6 +1241,10 @@ func (repo *GithubAppInstallationRepository) ReadGithubAppInstallationByInstalla
 		return nil, err
 	}
 
+	if err := repo.DecryptGithubAppInstallationData(ret, repo.key); err != nil {
+		return nil, err
+	}
+
 	return ret, nil
 }
 
@@ -1249,6 +1257,10 @@ func (repo *GithubAppInstallationRepository) ReadGithubAppInstallationByAccountI
 		return nil, err
 	}
 
+	if err := repo.DecryptGithubAppInstallationData(ret, repo.key); err != nil {
+		return nil, err
+	}
+
 	return ret, nil
 }
 
@@ -1261,6 +1273,12 @@ func (repo *GithubAppInstallationRepository) ReadGithubAppInstallationByAccountI
 		return nil, err
 	}
 
+	for _, ga := range ret {
+		if err := repo.DecryptGithubAppInstallationData(ga, repo.key); err != nil {
+			return nil, err
+		}
+	}
+
 	return ret, nil
 }
 
@@ -1274,6 +1292,45 @@ func (repo *GithubAppInstallationRepository) DeleteGithubAppInstallationByAccoun
 	return nil
 }
 
+// UpdateGithubAppInstallationToken sets the cached access token of an installation
+func (repo *GithubAppInstallationRepository) UpdateGithubAppInstallationToken(
+	installationID int64,
+	token []byte,
+	expiry time.Time,
+) error {
+	cipherData, err := repository.Encrypt(token, repo.key)
+
+	if err != nil {
+		return err
+	}
+
+	return repo.db.Model(&ints.GithubAppInstallation{}).
+		Where("installation_id = ?", installationID).
+		Updates(map[string]interface{}{
+			"access_token":        cipherData,
+			"access_token_expiry": expiry,
+		}).Error
+}
+
+// DecryptGithubAppInstallationData will decrypt the cached access token of an
+// installation before returning it from the DB
+func (repo *GithubAppInstallationRepository) DecryptGithubAppInstallationData(
+	ga *ints.GithubAppInstallation,
+	key *[32]byte,
+) error {
+	if len(ga.AccessToken) > 0 {
+		plaintext, err := repository.Decrypt(ga.AccessToken, key)
+
+		if err != nil {
+			return err
+		}
+
+		ga.AccessToken = plaintext
+	}
+
+	return nil
+}
+
 // GithubAppOAuthIntegrationRepository implements repository.GithubAppOAuthIntegrationRepository
 type GithubAppOAuthIntegrationRepository struct {
 	db *gorm.DB
@@ -0,0 +1,63 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// BackupRepository uses gorm.DB for querying the database
+type BackupRepository struct {
+	db *gorm.DB
+}
+
+// NewBackupRepository returns a BackupRepository which uses gorm.DB for querying the
+// database
+func NewBackupRepository(db *gorm.DB) repository.BackupRepository {
+	return &BackupRepository{db}
+}
+
+// CreateBackup starts tracking a backup
+func (repo *BackupRepository) CreateBackup(backup *models.Backup) (*models.Backup, error) {
+	if err := repo.db.Create(backup).Error; err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// ReadBackupByName reads a tracked backup of a cluster by the name of its Backup resource
+func (repo *BackupRepository) ReadBackupByName(clusterID uint, name string) (*models.Backup, error) {
+	backup := &models.Backup{}
+
+	if err := repo.db.Where("cluster_id = ? AND name = ?", clusterID, name).First(backup).Error; err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// ListBackupsByClusterID lists the tracked backups of a cluster, newest first
+func (repo *BackupRepository) ListBackupsByClusterID(clusterID uint) ([]*models.Backup, error) {
+	backups := []*models.Backup{}
+
+	if err := repo.db.Where("cluster_id = ?", clusterID).Order("id desc").Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// UpdateBackup updates the status of a tracked backup
+func (repo *BackupRepository) UpdateBackup(backup *models.Backup) (*models.Backup, error) {
+	if err := repo.db.Save(backup).Error; err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// DeleteBackup stops tracking a backup
+func (repo *BackupRepository) DeleteBackup(backup *models.Backup) error {
+	return repo.db.Delete(backup).Error
+}
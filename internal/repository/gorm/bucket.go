@@ -0,0 +1,71 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/credentials"
+	"gorm.io/gorm"
+)
+
+// BucketRepository uses gorm.DB for querying the database
+type BucketRepository struct {
+	*provisionedResourceRepository
+}
+
+// NewBucketRepository returns a BucketRepository which uses gorm.DB for querying
+// the database. It accepts an encryption key to encrypt the credentials of the
+// buckets, which are written to the credential storage backend instead of the DB if
+// one is set.
+func NewBucketRepository(
+	db *gorm.DB,
+	key *[32]byte,
+	storageBackend credentials.CredentialStorage,
+) repository.BucketRepository {
+	return &BucketRepository{&provisionedResourceRepository{
+		db:             db,
+		key:            key,
+		storageBackend: storageBackend,
+		newResource: func() models.ProvisionedResourceModel {
+			return &models.Bucket{}
+		},
+	}}
+}
+
+// CreateBucket creates a new bucket
+func (repo *BucketRepository) CreateBucket(bucket *models.Bucket) (*models.Bucket, error) {
+	if err := repo.save(bucket); err != nil {
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+// ReadBucket reads a bucket by ID, along with its decrypted credentials
+func (repo *BucketRepository) ReadBucket(projectID, clusterID, bucketID uint) (*models.Bucket, error) {
+	bucket := &models.Bucket{}
+
+	if err := repo.read(
+		bucket,
+		"project_id = ? AND cluster_id = ? AND id = ?",
+		projectID, clusterID, bucketID,
+	); err != nil {
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+// ListBuckets lists the buckets of a cluster, without their credentials
+func (repo *BucketRepository) ListBuckets(projectID, clusterID uint) ([]*models.Bucket, error) {
+	buckets := []*models.Bucket{}
+
+	if err := repo.db.Where("project_id = ? AND cluster_id = ?", projectID, clusterID).Find(&buckets).Error; err != nil {
+		return nil, err
+	}
+
+	for _, bucket := range buckets {
+		stripProvisionedResourceCredentials(&bucket.ProvisionedResource)
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,60 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// maxListedBuildLogs is the number of most recent build logs that are listed for a
+// release
+const maxListedBuildLogs = 50
+
+// BuildLogRepository uses gorm.DB for querying the database
+type BuildLogRepository struct {
+	db *gorm.DB
+}
+
+// NewBuildLogRepository returns a BuildLogRepository which uses
+// gorm.DB for querying the database
+func NewBuildLogRepository(db *gorm.DB) repository.BuildLogRepository {
+	return &BuildLogRepository{db}
+}
+
+// CreateBuildLog creates a new build log
+func (repo *BuildLogRepository) CreateBuildLog(buildLog *models.BuildLog) (*models.BuildLog, error) {
+	if err := repo.db.Create(buildLog).Error; err != nil {
+		return nil, err
+	}
+
+	return buildLog, nil
+}
+
+// ReadBuildLog reads the most recent build log of a release for a commit
+func (repo *BuildLogRepository) ReadBuildLog(releaseID uint, commitSHA string) (*models.BuildLog, error) {
+	buildLog := &models.BuildLog{}
+
+	if err := repo.db.Where(
+		"release_id = ? AND commit_sha = ?",
+		releaseID, commitSHA,
+	).Order("id desc").First(buildLog).Error; err != nil {
+		return nil, err
+	}
+
+	return buildLog, nil
+}
+
+// ListBuildLogsByReleaseID lists the most recent build logs of a release, without the
+// logs themselves
+func (repo *BuildLogRepository) ListBuildLogsByReleaseID(releaseID uint) ([]*models.BuildLog, error) {
+	buildLogs := make([]*models.BuildLog, 0)
+
+	if err := repo.db.Omit("logs").Where(
+		"release_id = ?",
+		releaseID,
+	).Order("id desc").Limit(maxListedBuildLogs).Find(&buildLogs).Error; err != nil {
+		return nil, err
+	}
+
+	return buildLogs, nil
+}
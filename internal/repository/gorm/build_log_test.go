@@ -0,0 +1,82 @@
+package gorm_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestCreateAndReadBuildLogs(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_build_logs.db",
+	}
+
+	setupTestEnv(tester, t)
+	initRelease(tester, t)
+	defer cleanup(tester, t)
+
+	releaseID := tester.initReleases[0].ID
+
+	for _, buildLog := range []*models.BuildLog{
+		{
+			ReleaseID: releaseID,
+			CommitSHA: "abc1234",
+			Status:    types.EventStatusFailed,
+			Logs:      []byte("first attempt"),
+		},
+		{
+			ReleaseID: releaseID,
+			CommitSHA: "abc1234",
+			Status:    types.EventStatusSuccess,
+			Logs:      []byte("second attempt"),
+		},
+		{
+			ReleaseID: releaseID,
+			CommitSHA: "def5678",
+			Status:    types.EventStatusSuccess,
+			Logs:      []byte("other commit"),
+		},
+	} {
+		if _, err := tester.repo.BuildLog().CreateBuildLog(buildLog); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	// the most recent build log of a commit should be read
+	buildLog, err := tester.repo.BuildLog().ReadBuildLog(releaseID, "abc1234")
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if buildLog.Status != types.EventStatusSuccess || string(buildLog.Logs) != "second attempt" {
+		t.Errorf("incorrect build log: expected second attempt, got %s\n", string(buildLog.Logs))
+	}
+
+	if _, err := tester.repo.BuildLog().ReadBuildLog(releaseID, "0000000"); err != gorm.ErrRecordNotFound {
+		t.Errorf("expected record not found error, got %v\n", err)
+	}
+
+	// build logs should be listed from most recent, without the logs
+	buildLogs, err := tester.repo.BuildLog().ListBuildLogsByReleaseID(releaseID)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(buildLogs) != 3 {
+		t.Fatalf("incorrect number of build logs: expected %d, got %d\n", 3, len(buildLogs))
+	}
+
+	if buildLogs[0].CommitSHA != "def5678" {
+		t.Errorf("incorrect first build log: expected %s, got %s\n", "def5678", buildLogs[0].CommitSHA)
+	}
+
+	for _, buildLog := range buildLogs {
+		if len(buildLog.Logs) != 0 {
+			t.Errorf("expected logs to be omitted from list, got %s\n", string(buildLog.Logs))
+		}
+	}
+}
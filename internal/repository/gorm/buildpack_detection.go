@@ -0,0 +1,69 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// BuildpackDetectionRepository uses gorm.DB for querying the database
+type BuildpackDetectionRepository struct {
+	db *gorm.DB
+}
+
+// NewBuildpackDetectionRepository returns a BuildpackDetectionRepository which uses
+// gorm.DB for querying the database
+func NewBuildpackDetectionRepository(db *gorm.DB) repository.BuildpackDetectionRepository {
+	return &BuildpackDetectionRepository{db}
+}
+
+// CreateBuildpackDetection stores the result of buildpack detection
+func (repo *BuildpackDetectionRepository) CreateBuildpackDetection(
+	detection *models.BuildpackDetection,
+) (*models.BuildpackDetection, error) {
+	if err := repo.db.Create(detection).Error; err != nil {
+		return nil, err
+	}
+
+	return detection, nil
+}
+
+// ReadBuildpackDetectionByCommit reads the latest detection result of a directory of a
+// repo at a commit
+func (repo *BuildpackDetectionRepository) ReadBuildpackDetectionByCommit(
+	projectID, gitInstallationID uint,
+	owner, name, folderPath, commitSHA string,
+) (*models.BuildpackDetection, error) {
+	detection := &models.BuildpackDetection{}
+
+	query := repo.db.Where(
+		"project_id = ? AND git_installation_id = ? AND git_repo_owner = ? AND git_repo_name = ? AND folder_path = ? AND commit_sha = ?",
+		projectID, gitInstallationID, owner, name, folderPath, commitSHA,
+	)
+
+	if err := query.Order("id desc").First(detection).Error; err != nil {
+		return nil, err
+	}
+
+	return detection, nil
+}
+
+// ReadLatestBuildpackDetection reads the latest detection result of a directory of a
+// branch of a repo, at any commit
+func (repo *BuildpackDetectionRepository) ReadLatestBuildpackDetection(
+	projectID, gitInstallationID uint,
+	owner, name, branch, folderPath string,
+) (*models.BuildpackDetection, error) {
+	detection := &models.BuildpackDetection{}
+
+	query := repo.db.Where(
+		"project_id = ? AND git_installation_id = ? AND git_repo_owner = ? AND git_repo_name = ? AND git_branch = ? AND folder_path = ?",
+		projectID, gitInstallationID, owner, name, branch, folderPath,
+	)
+
+	if err := query.Order("id desc").First(detection).Error; err != nil {
+		return nil, err
+	}
+
+	return detection, nil
+}
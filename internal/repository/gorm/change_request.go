@@ -0,0 +1,165 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ChangeRequestRepository uses gorm.DB for querying the database
+type ChangeRequestRepository struct {
+	db  *gorm.DB
+	key *[32]byte
+}
+
+// NewChangeRequestRepository returns a ChangeRequestRepository which uses gorm.DB for
+// querying the database. It accepts an encryption key to encrypt the sensitive values
+// of upgrades.
+func NewChangeRequestRepository(db *gorm.DB, key *[32]byte) repository.ChangeRequestRepository {
+	return &ChangeRequestRepository{db, key}
+}
+
+func (repo *ChangeRequestRepository) CreateChangeRequest(cr *models.ReleaseChangeRequest) (*models.ReleaseChangeRequest, error) {
+	plaintext := cr.SensitiveValues
+
+	if err := repo.EncryptChangeRequestData(cr); err != nil {
+		return nil, err
+	}
+
+	if err := repo.db.Create(cr).Error; err != nil {
+		return nil, err
+	}
+
+	cr.SensitiveValues = plaintext
+
+	return cr, nil
+}
+
+func (repo *ChangeRequestRepository) ReadChangeRequest(clusterID, id uint) (*models.ReleaseChangeRequest, error) {
+	cr := &models.ReleaseChangeRequest{}
+
+	if err := repo.db.Where("cluster_id = ? AND id = ?", clusterID, id).First(&cr).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repo.DecryptChangeRequestData(cr); err != nil {
+		return nil, err
+	}
+
+	return cr, nil
+}
+
+func (repo *ChangeRequestRepository) ListChangeRequests(
+	clusterID uint,
+	namespace, name string,
+	statuses ...types.ChangeRequestStatus,
+) ([]*models.ReleaseChangeRequest, error) {
+	crs := make([]*models.ReleaseChangeRequest, 0)
+
+	query := repo.db.Order("id desc").Where(
+		"cluster_id = ? AND namespace = ? AND name = ?",
+		clusterID, namespace, name,
+	)
+
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+
+	if err := query.Find(&crs).Error; err != nil {
+		return nil, err
+	}
+
+	for _, cr := range crs {
+		if err := repo.DecryptChangeRequestData(cr); err != nil {
+			return nil, err
+		}
+	}
+
+	return crs, nil
+}
+
+func (repo *ChangeRequestRepository) UpdateChangeRequest(cr *models.ReleaseChangeRequest) (*models.ReleaseChangeRequest, error) {
+	plaintext := cr.SensitiveValues
+
+	if err := repo.EncryptChangeRequestData(cr); err != nil {
+		return nil, err
+	}
+
+	if err := repo.db.Save(cr).Error; err != nil {
+		return nil, err
+	}
+
+	cr.SensitiveValues = plaintext
+
+	return cr, nil
+}
+
+func (repo *ChangeRequestRepository) IsNamespaceProtected(clusterID uint, namespace string) (bool, error) {
+	var count int64
+
+	if err := repo.db.Model(&models.ProtectedNamespace{}).Where(
+		"cluster_id = ? AND namespace = ?",
+		clusterID, namespace,
+	).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (repo *ChangeRequestRepository) SetNamespaceProtected(clusterID uint, namespace string, protected bool) error {
+	if !protected {
+		return repo.db.Where(
+			"cluster_id = ? AND namespace = ?",
+			clusterID, namespace,
+		).Delete(&models.ProtectedNamespace{}).Error
+	}
+
+	isProtected, err := repo.IsNamespaceProtected(clusterID, namespace)
+
+	if err != nil || isProtected {
+		return err
+	}
+
+	return repo.db.Create(&models.ProtectedNamespace{
+		ClusterID: clusterID,
+		Namespace: namespace,
+	}).Error
+}
+
+// EncryptChangeRequestData encrypts the sensitive values of a change request before
+// they are written to the DB
+func (repo *ChangeRequestRepository) EncryptChangeRequestData(cr *models.ReleaseChangeRequest) error {
+	if len(cr.SensitiveValues) == 0 {
+		return nil
+	}
+
+	cipherData, err := repository.Encrypt(cr.SensitiveValues, repo.key)
+
+	if err != nil {
+		return err
+	}
+
+	cr.SensitiveValues = cipherData
+
+	return nil
+}
+
+// DecryptChangeRequestData decrypts the sensitive values of a change request after they
+// are read from the DB
+func (repo *ChangeRequestRepository) DecryptChangeRequestData(cr *models.ReleaseChangeRequest) error {
+	if len(cr.SensitiveValues) == 0 {
+		return nil
+	}
+
+	plaintext, err := repository.Decrypt(cr.SensitiveValues, repo.key)
+
+	if err != nil {
+		return err
+	}
+
+	cr.SensitiveValues = plaintext
+
+	return nil
+}
@@ -217,6 +217,23 @@ func (repo *ClusterRepository) ListClustersByProjectID(
 	return clusters, nil
 }
 
+// ListClusters finds all clusters across projects
+func (repo *ClusterRepository) ListClusters() ([]*models.Cluster, error) {
+	ctxDB := repo.db.WithContext(context.Background())
+
+	clusters := []*models.Cluster{}
+
+	if err := ctxDB.Order("id asc").Find(&clusters).Error; err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range clusters {
+		repo.DecryptClusterData(cluster, repo.key)
+	}
+
+	return clusters, nil
+}
+
 // UpdateCluster modifies an existing Cluster in the database
 func (repo *ClusterRepository) UpdateCluster(
 	cluster *models.Cluster,
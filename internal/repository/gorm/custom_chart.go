@@ -0,0 +1,73 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// CustomChartRepository uses gorm.DB for querying the database
+type CustomChartRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomChartRepository returns a CustomChartRepository which uses
+// gorm.DB for querying the database
+func NewCustomChartRepository(db *gorm.DB) repository.CustomChartRepository {
+	return &CustomChartRepository{db}
+}
+
+// CreateCustomChart creates a new version of a custom chart
+func (repo *CustomChartRepository) CreateCustomChart(customChart *models.CustomChart) (*models.CustomChart, error) {
+	if err := repo.db.Create(customChart).Error; err != nil {
+		return nil, err
+	}
+
+	return customChart, nil
+}
+
+// ReadCustomChart finds a version of a custom chart by project id and id
+func (repo *CustomChartRepository) ReadCustomChart(projectID, id uint) (*models.CustomChart, error) {
+	customChart := &models.CustomChart{}
+
+	if err := repo.db.Where("project_id = ? AND id = ?", projectID, id).First(customChart).Error; err != nil {
+		return nil, err
+	}
+
+	return customChart, nil
+}
+
+// ReadCustomChartByVersion finds a version of a custom chart by project id, name and version
+func (repo *CustomChartRepository) ReadCustomChartByVersion(projectID uint, name, version string) (*models.CustomChart, error) {
+	customChart := &models.CustomChart{}
+
+	if err := repo.db.Where(
+		"project_id = ? AND name = ? AND version = ?",
+		projectID, name, version,
+	).First(customChart).Error; err != nil {
+		return nil, err
+	}
+
+	return customChart, nil
+}
+
+// ListCustomChartsByProjectID finds all versions of the custom charts of a project,
+// without their packaged charts
+func (repo *CustomChartRepository) ListCustomChartsByProjectID(projectID uint) ([]*models.CustomChart, error) {
+	customCharts := []*models.CustomChart{}
+
+	if err := repo.db.Omit("archive").Where("project_id = ?", projectID).Order("name asc, id desc").Find(&customCharts).Error; err != nil {
+		return nil, err
+	}
+
+	return customCharts, nil
+}
+
+// DeleteCustomChart deletes a version of a custom chart
+func (repo *CustomChartRepository) DeleteCustomChart(customChart *models.CustomChart) (*models.CustomChart, error) {
+	if err := repo.db.Delete(customChart).Error; err != nil {
+		return nil, err
+	}
+
+	return customChart, nil
+}
@@ -0,0 +1,42 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// DefaultValuesRepository uses gorm.DB for querying the database
+type DefaultValuesRepository struct {
+	db *gorm.DB
+}
+
+// NewDefaultValuesRepository returns a DefaultValuesRepository which uses
+// gorm.DB for querying the database
+func NewDefaultValuesRepository(db *gorm.DB) repository.DefaultValuesRepository {
+	return &DefaultValuesRepository{db}
+}
+
+// ReadDefaultValues reads the default values of a project, or of a cluster if clusterID
+// is not 0
+func (repo *DefaultValuesRepository) ReadDefaultValues(projectID, clusterID uint) (*models.DefaultValues, error) {
+	defaultValues := &models.DefaultValues{}
+
+	if err := repo.db.Where(
+		"project_id = ? AND cluster_id = ?",
+		projectID, clusterID,
+	).First(defaultValues).Error; err != nil {
+		return nil, err
+	}
+
+	return defaultValues, nil
+}
+
+// UpdateDefaultValues creates or updates default values
+func (repo *DefaultValuesRepository) UpdateDefaultValues(defaultValues *models.DefaultValues) (*models.DefaultValues, error) {
+	if err := repo.db.Save(defaultValues).Error; err != nil {
+		return nil, err
+	}
+
+	return defaultValues, nil
+}
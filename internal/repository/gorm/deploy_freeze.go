@@ -0,0 +1,82 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// DeployFreezeRepository uses gorm.DB for querying the database
+type DeployFreezeRepository struct {
+	db *gorm.DB
+}
+
+// NewDeployFreezeRepository returns a DeployFreezeRepository which uses
+// gorm.DB for querying the database
+func NewDeployFreezeRepository(db *gorm.DB) repository.DeployFreezeRepository {
+	return &DeployFreezeRepository{db}
+}
+
+// CreateDeployFreeze creates a new deploy freeze
+func (repo *DeployFreezeRepository) CreateDeployFreeze(freeze *models.DeployFreeze) (*models.DeployFreeze, error) {
+	if err := repo.db.Create(freeze).Error; err != nil {
+		return nil, err
+	}
+
+	return freeze, nil
+}
+
+// ReadDeployFreeze finds a deploy freeze of a project by its id
+func (repo *DeployFreezeRepository) ReadDeployFreeze(projectID, freezeID uint) (*models.DeployFreeze, error) {
+	freeze := &models.DeployFreeze{}
+
+	if err := repo.db.Where("project_id = ? AND id = ?", projectID, freezeID).First(freeze).Error; err != nil {
+		return nil, err
+	}
+
+	return freeze, nil
+}
+
+// ListDeployFreezesByProjectID finds all deploy freezes of a project
+func (repo *DeployFreezeRepository) ListDeployFreezesByProjectID(projectID uint) ([]*models.DeployFreeze, error) {
+	freezes := []*models.DeployFreeze{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Order("id asc").Find(&freezes).Error; err != nil {
+		return nil, err
+	}
+
+	return freezes, nil
+}
+
+// DeleteDeployFreeze deletes a deploy freeze. Its overrides are kept, since they are
+// the audit log of the freeze.
+func (repo *DeployFreezeRepository) DeleteDeployFreeze(freeze *models.DeployFreeze) (*models.DeployFreeze, error) {
+	if err := repo.db.Delete(freeze).Error; err != nil {
+		return nil, err
+	}
+
+	return freeze, nil
+}
+
+// CreateDeployFreezeOverride records an override of a deploy freeze
+func (repo *DeployFreezeRepository) CreateDeployFreezeOverride(
+	override *models.DeployFreezeOverride,
+) (*models.DeployFreezeOverride, error) {
+	if err := repo.db.Create(override).Error; err != nil {
+		return nil, err
+	}
+
+	return override, nil
+}
+
+// ListDeployFreezeOverridesByProjectID finds all overrides of the deploy freezes of a
+// project, from most recent
+func (repo *DeployFreezeRepository) ListDeployFreezeOverridesByProjectID(projectID uint) ([]*models.DeployFreezeOverride, error) {
+	overrides := []*models.DeployFreezeOverride{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Order("id desc").Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
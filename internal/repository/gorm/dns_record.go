@@ -25,3 +25,24 @@ func (repo *DNSRecordRepository) CreateDNSRecord(record *models.DNSRecord) (*mod
 
 	return record, nil
 }
+
+// ListDNSRecordsByRelease lists the records that were created for a release
+func (repo *DNSRecordRepository) ListDNSRecordsByRelease(
+	clusterID uint,
+	namespace, releaseName string,
+) ([]*models.DNSRecord, error) {
+	records := []*models.DNSRecord{}
+
+	if err := repo.db.Where(
+		"cluster_id = ? AND namespace = ? AND release_name = ?", clusterID, namespace, releaseName,
+	).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// DeleteDNSRecord deletes a record
+func (repo *DNSRecordRepository) DeleteDNSRecord(record *models.DNSRecord) error {
+	return repo.db.Delete(record).Error
+}
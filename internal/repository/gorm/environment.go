@@ -61,6 +61,27 @@ func (repo *EnvironmentRepository) ListEnvironments(projectID, clusterID uint) (
 	return envs, nil
 }
 
+func (repo *EnvironmentRepository) ListEnvironmentsByRepo(gitInstallationID uint, gitRepoOwner, gitRepoName string) ([]*models.Environment, error) {
+	envs := make([]*models.Environment, 0)
+
+	if err := repo.db.Order("id asc").Where(
+		"git_installation_id = ? AND git_repo_owner = ? AND git_repo_name = ?",
+		gitInstallationID, gitRepoOwner, gitRepoName,
+	).Find(&envs).Error; err != nil {
+		return nil, err
+	}
+
+	return envs, nil
+}
+
+func (repo *EnvironmentRepository) UpdateEnvironment(env *models.Environment) (*models.Environment, error) {
+	if err := repo.db.Save(env).Error; err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
 func (repo *EnvironmentRepository) DeleteEnvironment(env *models.Environment) (*models.Environment, error) {
 	if err := repo.db.Delete(&env).Error; err != nil {
 		return nil, err
@@ -95,10 +116,10 @@ func (repo *EnvironmentRepository) ReadDeploymentByCluster(projectID, clusterID
 	depl := &models.Deployment{}
 
 	if err := repo.db.
-		Order("deployments.id asc").
+		Order("deployments.id desc").
 		Joins("INNER JOIN environments ON environments.id = deployments.environment_id").
-		Where("environments.project_id = ? AND environments.cluster_id = ? AND environments.deleted_at IS NULL AND namespace = ?", projectID, clusterID, depl.Namespace).
-		Find(&depl).Error; err != nil {
+		Where("environments.project_id = ? AND environments.cluster_id = ? AND environments.deleted_at IS NULL AND namespace = ?", projectID, clusterID, namespace).
+		First(&depl).Error; err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,77 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// GitOpsConfigRepository uses gorm.DB for querying the database
+type GitOpsConfigRepository struct {
+	db *gorm.DB
+}
+
+// NewGitOpsConfigRepository returns a GitOpsConfigRepository which uses
+// gorm.DB for querying the database
+func NewGitOpsConfigRepository(db *gorm.DB) repository.GitOpsConfigRepository {
+	return &GitOpsConfigRepository{db}
+}
+
+// ReadGitOpsConfig reads the gitops config of a project
+func (repo *GitOpsConfigRepository) ReadGitOpsConfig(projectID uint) (*models.GitOpsConfig, error) {
+	conf := &models.GitOpsConfig{}
+
+	if err := repo.db.Where("project_id = ?", projectID).First(conf).Error; err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// ListGitOpsConfigs lists the gitops configs of all projects
+func (repo *GitOpsConfigRepository) ListGitOpsConfigs() ([]*models.GitOpsConfig, error) {
+	confs := []*models.GitOpsConfig{}
+
+	if err := repo.db.Find(&confs).Error; err != nil {
+		return nil, err
+	}
+
+	return confs, nil
+}
+
+// UpdateGitOpsConfig creates or updates the gitops config of a project
+func (repo *GitOpsConfigRepository) UpdateGitOpsConfig(conf *models.GitOpsConfig) (*models.GitOpsConfig, error) {
+	if err := repo.db.Save(conf).Error; err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// ClaimGitOpsReconcile claims the reconcile lease of a gitops config until expiresAt, and
+// returns false if another server replica holds an unexpired lease
+func (repo *GitOpsConfigRepository) ClaimGitOpsReconcile(conf *models.GitOpsConfig, expiresAt time.Time) (bool, error) {
+	res := repo.db.Model(&models.GitOpsConfig{}).Where(
+		"id = ? AND (reconcile_lease_expires_at IS NULL OR reconcile_lease_expires_at < ?)",
+		conf.ID, time.Now(),
+	).Update("reconcile_lease_expires_at", expiresAt)
+
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	conf.ReconcileLeaseExpiresAt = &expiresAt
+
+	return true, nil
+}
+
+// DeleteGitOpsConfig deletes the gitops config of a project
+func (repo *GitOpsConfigRepository) DeleteGitOpsConfig(conf *models.GitOpsConfig) error {
+	return repo.db.Delete(conf).Error
+}
@@ -0,0 +1,45 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type HealthGateConfigRepository struct {
+	db *gorm.DB
+}
+
+// NewHealthGateConfigRepository creates a new HealthGateConfigRepository
+func NewHealthGateConfigRepository(db *gorm.DB) repository.HealthGateConfigRepository {
+	return HealthGateConfigRepository{db: db}
+}
+
+// CreateHealthGateConfig creates a new HealthGateConfig
+func (repo HealthGateConfigRepository) CreateHealthGateConfig(conf *models.HealthGateConfig) (*models.HealthGateConfig, error) {
+	if err := repo.db.Create(conf).Error; err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// ReadHealthGateConfig reads a HealthGateConfig by ID
+func (repo HealthGateConfigRepository) ReadHealthGateConfig(id uint) (*models.HealthGateConfig, error) {
+	ret := &models.HealthGateConfig{}
+
+	if err := repo.db.Where("id = ?", id).First(&ret).Error; err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// UpdateHealthGateConfig updates a given HealthGateConfig
+func (repo HealthGateConfigRepository) UpdateHealthGateConfig(conf *models.HealthGateConfig) (*models.HealthGateConfig, error) {
+	if err := repo.db.Save(conf).Error; err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
@@ -70,12 +70,22 @@ func setupTestEnv(tester *tester, t *testing.T) {
 		&models.ClusterCandidate{},
 		&models.ClusterResolver{},
 		&models.Infra{},
+		&models.InfraResource{},
 		&models.GitActionConfig{},
 		&models.Invite{},
 		&models.KubeEvent{},
 		&models.KubeSubEvent{},
+		&models.BuildLog{},
 		&models.Onboarding{},
 		&models.Allowlist{},
+		&models.ImageSBOM{},
+		&models.SBOMPackage{},
+		&models.Bucket{},
+		&models.Queue{},
+		&models.Pipeline{},
+		&models.PipelineStage{},
+		&models.PipelinePromotion{},
+		&models.PipelinePromotionApproval{},
 		&ints.KubeIntegration{},
 		&ints.BasicIntegration{},
 		&ints.OIDCIntegration{},
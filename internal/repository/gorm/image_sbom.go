@@ -0,0 +1,89 @@
+package gorm
+
+import (
+	"strings"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ImageSBOMRepository uses gorm.DB for querying the database
+type ImageSBOMRepository struct {
+	db *gorm.DB
+}
+
+// NewImageSBOMRepository returns an ImageSBOMRepository which uses gorm.DB for querying
+// the database
+func NewImageSBOMRepository(db *gorm.DB) repository.ImageSBOMRepository {
+	return &ImageSBOMRepository{db}
+}
+
+// CreateImageSBOM stores the dependency inventory of an image along with its packages
+func (repo *ImageSBOMRepository) CreateImageSBOM(sbom *models.ImageSBOM) (*models.ImageSBOM, error) {
+	if err := repo.db.Create(sbom).Error; err != nil {
+		return nil, err
+	}
+
+	return sbom, nil
+}
+
+// ReadImageSBOM reads the dependency inventory of an image along with its packages
+func (repo *ImageSBOMRepository) ReadImageSBOM(projectID, id uint) (*models.ImageSBOM, error) {
+	sbom := &models.ImageSBOM{}
+
+	if err := repo.db.Preload("Packages", orderSBOMPackages).Where(
+		"project_id = ? AND id = ?",
+		projectID, id,
+	).First(sbom).Error; err != nil {
+		return nil, err
+	}
+
+	return sbom, nil
+}
+
+// ReadImageSBOMByImage reads the most recent dependency inventory of an image by the
+// repository and tag of the image, without its packages
+func (repo *ImageSBOMRepository) ReadImageSBOMByImage(projectID uint, imageRepo, tag string) (*models.ImageSBOM, error) {
+	sbom := &models.ImageSBOM{}
+
+	if err := repo.db.Where(
+		"project_id = ? AND image_repo = ? AND tag = ?",
+		projectID, imageRepo, tag,
+	).Order("id desc").First(sbom).Error; err != nil {
+		return nil, err
+	}
+
+	return sbom, nil
+}
+
+// ListImageSBOMsByPackageName lists the dependency inventories of a project that contain
+// packages whose name contains a string, ignoring case. Only the matching packages of
+// each inventory are read.
+func (repo *ImageSBOMRepository) ListImageSBOMsByPackageName(projectID uint, name string) ([]*models.ImageSBOM, error) {
+	sboms := make([]*models.ImageSBOM, 0)
+	query := "LOWER(name) LIKE ? ESCAPE '\\'"
+	pattern := "%" + escapeLike(strings.ToLower(name)) + "%"
+
+	matching := repo.db.Model(&models.SBOMPackage{}).Select("image_sbom_id").Where(query, pattern)
+
+	if err := repo.db.Preload("Packages", func(db *gorm.DB) *gorm.DB {
+		return orderSBOMPackages(db.Where(query, pattern))
+	}).Where(
+		"project_id = ? AND id IN (?)",
+		projectID, matching,
+	).Order("id asc").Find(&sboms).Error; err != nil {
+		return nil, err
+	}
+
+	return sboms, nil
+}
+
+func orderSBOMPackages(db *gorm.DB) *gorm.DB {
+	return db.Order("name asc, version asc")
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
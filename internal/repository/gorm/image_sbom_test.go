@@ -0,0 +1,123 @@
+package gorm_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func TestListImageSBOMsByPackageName(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_image_sboms.db",
+	}
+
+	setupTestEnv(tester, t)
+	initRelease(tester, t)
+	defer cleanup(tester, t)
+
+	for _, sbom := range []*models.ImageSBOM{
+		{
+			ProjectID: 1,
+			Digest:    "sha256:aaaa",
+			ImageRepo: "gcr.io/project/api",
+			Tag:       "v1",
+			Format:    types.SBOMFormatCycloneDXJSON,
+			Packages: []models.SBOMPackage{
+				{Name: "log4j-core", Version: "2.14.1", Type: "maven"},
+				{Name: "log4j-api", Version: "2.14.1", Type: "maven"},
+				{Name: "jackson-databind", Version: "2.12.3", Type: "maven"},
+			},
+		},
+		{
+			ProjectID: 1,
+			Digest:    "sha256:bbbb",
+			ImageRepo: "gcr.io/project/web",
+			Tag:       "v1",
+			Format:    types.SBOMFormatBuildpacks,
+			Packages: []models.SBOMPackage{
+				{Name: "node", Version: "16.13.0"},
+			},
+		},
+		{
+			ProjectID: 2,
+			Digest:    "sha256:cccc",
+			ImageRepo: "gcr.io/other/api",
+			Tag:       "v1",
+			Format:    types.SBOMFormatCycloneDXJSON,
+			Packages: []models.SBOMPackage{
+				{Name: "log4j-core", Version: "2.17.1", Type: "maven"},
+			},
+		},
+	} {
+		if _, err := tester.repo.ImageSBOM().CreateImageSBOM(sbom); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	// only the inventories of the project with matching packages should be listed, with
+	// only their matching packages
+	sboms, err := tester.repo.ImageSBOM().ListImageSBOMsByPackageName(1, "LOG4J")
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(sboms) != 1 || sboms[0].Digest != "sha256:aaaa" {
+		t.Fatalf("incorrect image SBOMs: expected sha256:aaaa, got %v\n", sboms)
+	}
+
+	if len(sboms[0].Packages) != 2 || sboms[0].Packages[0].Name != "log4j-api" || sboms[0].Packages[1].Name != "log4j-core" {
+		t.Errorf("incorrect packages: expected log4j-api and log4j-core, got %v\n", sboms[0].Packages)
+	}
+
+	// wildcards of LIKE patterns should be matched literally
+	sboms, err = tester.repo.ImageSBOM().ListImageSBOMsByPackageName(1, "log4j_")
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(sboms) != 0 {
+		t.Errorf("expected no image SBOMs for log4j_, got %d\n", len(sboms))
+	}
+
+	// a tag that is pushed again gets a new inventory, which is read from then on
+	if _, err := tester.repo.ImageSBOM().CreateImageSBOM(&models.ImageSBOM{
+		ProjectID: 1,
+		Digest:    "sha256:dddd",
+		ImageRepo: "gcr.io/project/web",
+		Tag:       "v1",
+		Format:    types.SBOMFormatBuildpacks,
+	}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	sbom, err := tester.repo.ImageSBOM().ReadImageSBOMByImage(1, "gcr.io/project/web", "v1")
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if sbom.Digest != "sha256:dddd" {
+		t.Errorf("incorrect image SBOM: expected sha256:dddd, got %s\n", sbom.Digest)
+	}
+
+	// releases should be found by the inventory of the image that they run
+	release := tester.initReleases[0]
+	release.ImageSBOMID = sbom.ID
+
+	if _, err := tester.repo.Release().UpdateRelease(release); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	releases, err := tester.repo.Release().ListReleasesByImageSBOMIDs(1, []uint{sbom.ID})
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(releases) != 1 || releases[0].Name != release.Name {
+		t.Errorf("incorrect releases: expected %s, got %v\n", release.Name, releases)
+	}
+}
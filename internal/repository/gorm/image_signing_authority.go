@@ -0,0 +1,58 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ImageSigningAuthorityRepository uses gorm.DB for querying the database
+type ImageSigningAuthorityRepository struct {
+	db *gorm.DB
+}
+
+// NewImageSigningAuthorityRepository returns an ImageSigningAuthorityRepository which
+// uses gorm.DB for querying the database
+func NewImageSigningAuthorityRepository(db *gorm.DB) repository.ImageSigningAuthorityRepository {
+	return &ImageSigningAuthorityRepository{db}
+}
+
+// CreateImageSigningAuthority adds an image signing authority to a project
+func (repo *ImageSigningAuthorityRepository) CreateImageSigningAuthority(
+	authority *models.ImageSigningAuthority,
+) (*models.ImageSigningAuthority, error) {
+	if err := repo.db.Create(authority).Error; err != nil {
+		return nil, err
+	}
+
+	return authority, nil
+}
+
+// ReadImageSigningAuthority reads an image signing authority of a project
+func (repo *ImageSigningAuthorityRepository) ReadImageSigningAuthority(projectID, id uint) (*models.ImageSigningAuthority, error) {
+	authority := &models.ImageSigningAuthority{}
+
+	if err := repo.db.Where("project_id = ? AND id = ?", projectID, id).First(authority).Error; err != nil {
+		return nil, err
+	}
+
+	return authority, nil
+}
+
+// ListImageSigningAuthoritiesByProjectID lists the image signing authorities of a project
+func (repo *ImageSigningAuthorityRepository) ListImageSigningAuthoritiesByProjectID(
+	projectID uint,
+) ([]*models.ImageSigningAuthority, error) {
+	authorities := []*models.ImageSigningAuthority{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Find(&authorities).Error; err != nil {
+		return nil, err
+	}
+
+	return authorities, nil
+}
+
+// DeleteImageSigningAuthority removes an image signing authority from a project
+func (repo *ImageSigningAuthorityRepository) DeleteImageSigningAuthority(authority *models.ImageSigningAuthority) error {
+	return repo.db.Delete(authority).Error
+}
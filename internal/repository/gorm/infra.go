@@ -109,6 +109,38 @@ func (repo *InfraRepository) UpdateInfra(
 	return ai, nil
 }
 
+// ListInfraResources lists the materialized resources of an infra
+func (repo *InfraRepository) ListInfraResources(infraID uint) ([]*models.InfraResource, error) {
+	resources := []*models.InfraResource{}
+
+	if err := repo.db.Where("infra_id = ?", infraID).Order("addr").Find(&resources).Error; err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+// UpdateInfraResources replaces the materialized resources of an infra with the
+// given set of resources
+func (repo *InfraRepository) UpdateInfraResources(infraID uint, resources []*models.InfraResource) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("infra_id = ?", infraID).Delete(&models.InfraResource{}).Error; err != nil {
+			return err
+		}
+
+		if len(resources) == 0 {
+			return nil
+		}
+
+		for _, resource := range resources {
+			resource.Model = gorm.Model{}
+			resource.InfraID = infraID
+		}
+
+		return tx.Create(&resources).Error
+	})
+}
+
 // EncryptInfraData will encrypt the infra data before
 // writing to the DB
 func (repo *InfraRepository) EncryptInfraData(
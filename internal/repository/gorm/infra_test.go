@@ -90,3 +90,68 @@ func TestListInfrasByProjectID(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestUpdateInfraResources(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_update_infra_resources.db",
+	}
+
+	setupTestEnv(tester, t)
+	initProject(tester, t)
+	initInfra(tester, t)
+	defer cleanup(tester, t)
+
+	infraID := tester.initInfras[0].Model.ID
+
+	err := tester.repo.Infra().UpdateInfraResources(infraID, []*models.InfraResource{
+		{
+			Addr:         "aws_ecr_repository.repo",
+			ResourceType: "aws_ecr_repository",
+			ResourceName: "repo",
+			Status:       types.InfraResourceCreated,
+		},
+		{
+			Addr:         "aws_iam_policy.policy",
+			ResourceType: "aws_iam_policy",
+			ResourceName: "policy",
+			Status:       types.InfraResourceErrored,
+			Error:        "access denied",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// updating again should replace the previous snapshot
+	err = tester.repo.Infra().UpdateInfraResources(infraID, []*models.InfraResource{
+		{
+			Addr:         "aws_ecr_repository.repo",
+			ResourceType: "aws_ecr_repository",
+			ResourceName: "repo",
+			Status:       types.InfraResourceCreated,
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	resources, err := tester.repo.Infra().ListInfraResources(infraID)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(resources) != 1 {
+		t.Fatalf("length of infra resources incorrect: expected %d, got %d\n", 1, len(resources))
+	}
+
+	if resources[0].Addr != "aws_ecr_repository.repo" {
+		t.Errorf("incorrect infra resource addr: expected %s, got %s\n", "aws_ecr_repository.repo", resources[0].Addr)
+	}
+
+	if resources[0].InfraID != infraID {
+		t.Errorf("incorrect infra resource infra id: expected %d, got %d\n", infraID, resources[0].InfraID)
+	}
+}
@@ -0,0 +1,152 @@
+package gorm
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+const defaultAdminListLimit = 50
+
+// InstanceAdminRepository uses gorm.DB for querying the database
+type InstanceAdminRepository struct {
+	db *gorm.DB
+}
+
+// NewInstanceAdminRepository returns an InstanceAdminRepository which uses
+// gorm.DB for querying the database
+func NewInstanceAdminRepository(db *gorm.DB) repository.InstanceAdminRepository {
+	return &InstanceAdminRepository{db}
+}
+
+// ListProjects lists all projects of the instance with the given options
+func (repo *InstanceAdminRepository) ListProjects(opts *types.AdminListRequest) ([]*models.Project, int64, error) {
+	projects := []*models.Project{}
+
+	var count int64
+
+	if err := repo.db.Model(&models.Project{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := repo.db.Preload("Roles").Order("id asc").Limit(adminListLimit(opts)).Offset(opts.Skip)
+
+	if err := query.Find(&projects).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return projects, count, nil
+}
+
+// ListUsers lists all users of the instance with the given options
+func (repo *InstanceAdminRepository) ListUsers(opts *types.AdminListRequest) ([]*models.User, int64, error) {
+	users := []*models.User{}
+
+	var count int64
+
+	if err := repo.db.Model(&models.User{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := repo.db.Order("id asc").Limit(adminListLimit(opts)).Offset(opts.Skip)
+
+	if err := query.Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, count, nil
+}
+
+// ReadInstanceQuota returns the quota of the instance, which is unlimited if it was
+// never set
+func (repo *InstanceAdminRepository) ReadInstanceQuota() (*models.InstanceQuota, error) {
+	quota := &models.InstanceQuota{}
+
+	if err := repo.db.Order("id asc").First(quota).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.InstanceQuota{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return quota, nil
+}
+
+// UpdateInstanceQuota creates or updates the single quota of the instance
+func (repo *InstanceAdminRepository) UpdateInstanceQuota(quota *models.InstanceQuota) (*models.InstanceQuota, error) {
+	existing, err := repo.ReadInstanceQuota()
+
+	if err != nil {
+		return nil, err
+	}
+
+	quota.ID = existing.ID
+
+	if err := repo.db.Save(quota).Error; err != nil {
+		return nil, err
+	}
+
+	return quota, nil
+}
+
+// ListWhitelistedUsers lists the users that were whitelisted by instance admins
+func (repo *InstanceAdminRepository) ListWhitelistedUsers() ([]*models.WhitelistedUser, error) {
+	whitelisted := []*models.WhitelistedUser{}
+
+	if err := repo.db.Find(&whitelisted).Error; err != nil {
+		return nil, err
+	}
+
+	return whitelisted, nil
+}
+
+func (repo *InstanceAdminRepository) CreateWhitelistedUser(whitelisted *models.WhitelistedUser) (*models.WhitelistedUser, error) {
+	if err := repo.db.Create(whitelisted).Error; err != nil {
+		return nil, err
+	}
+
+	return whitelisted, nil
+}
+
+// DeleteWhitelistedUser removes a user from the whitelist. The row is deleted rather
+// than soft-deleted, so that the user can be whitelisted again.
+func (repo *InstanceAdminRepository) DeleteWhitelistedUser(userID uint) error {
+	return repo.db.Unscoped().Where("user_id = ?", userID).Delete(&models.WhitelistedUser{}).Error
+}
+
+func (repo *InstanceAdminRepository) CreateAuditLog(log *models.AdminAuditLog) (*models.AdminAuditLog, error) {
+	if err := repo.db.Create(log).Error; err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+// ListAuditLogs lists the audit logs of instance admins, starting with the most recent
+func (repo *InstanceAdminRepository) ListAuditLogs(opts *types.AdminListRequest) ([]*models.AdminAuditLog, int64, error) {
+	logs := []*models.AdminAuditLog{}
+
+	var count int64
+
+	if err := repo.db.Model(&models.AdminAuditLog{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := repo.db.Order("id desc").Limit(adminListLimit(opts)).Offset(opts.Skip)
+
+	if err := query.Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, count, nil
+}
+
+func adminListLimit(opts *types.AdminListRequest) int {
+	if opts.Limit <= 0 {
+		return defaultAdminListLimit
+	}
+
+	return opts.Limit
+}
@@ -0,0 +1,63 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ManifestPolicyRepository uses gorm.DB for querying the database
+type ManifestPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewManifestPolicyRepository returns a ManifestPolicyRepository which uses
+// gorm.DB for querying the database
+func NewManifestPolicyRepository(db *gorm.DB) repository.ManifestPolicyRepository {
+	return &ManifestPolicyRepository{db}
+}
+
+// CreateManifestPolicy adds a manifest policy to a project
+func (repo *ManifestPolicyRepository) CreateManifestPolicy(policy *models.ManifestPolicy) (*models.ManifestPolicy, error) {
+	if err := repo.db.Create(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// ReadManifestPolicy reads a manifest policy of a project
+func (repo *ManifestPolicyRepository) ReadManifestPolicy(projectID, id uint) (*models.ManifestPolicy, error) {
+	policy := &models.ManifestPolicy{}
+
+	if err := repo.db.Where("project_id = ? AND id = ?", projectID, id).First(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// ListManifestPoliciesByProjectID lists the manifest policies of a project
+func (repo *ManifestPolicyRepository) ListManifestPoliciesByProjectID(projectID uint) ([]*models.ManifestPolicy, error) {
+	policies := []*models.ManifestPolicy{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Order("id asc").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// UpdateManifestPolicy modifies an existing manifest policy in the database
+func (repo *ManifestPolicyRepository) UpdateManifestPolicy(policy *models.ManifestPolicy) (*models.ManifestPolicy, error) {
+	if err := repo.db.Save(policy).Error; err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// DeleteManifestPolicy removes a manifest policy from a project
+func (repo *ManifestPolicyRepository) DeleteManifestPolicy(policy *models.ManifestPolicy) error {
+	return repo.db.Delete(policy).Error
+}
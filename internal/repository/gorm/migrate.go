@@ -8,13 +8,27 @@ import (
 )
 
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	// preview environments were available to every project before they were part of the
+	// plan of a project, so the plans that existed before are backfilled to include them
+	backfillPreviewEnvironments := db.Migrator().HasTable(&models.ProjectUsage{}) &&
+		!db.Migrator().HasColumn(&models.ProjectUsage{}, "PreviewEnvironments")
+
+	err := db.AutoMigrate(
 		&models.Project{},
 		&models.Role{},
 		&models.User{},
 		&models.Release{},
 		&models.Environment{},
 		&models.Deployment{},
+		&models.Pipeline{},
+		&models.PipelineStage{},
+		&models.PipelinePromotion{},
+		&models.PipelinePromotionApproval{},
+		&models.ReleaseChangeRequest{},
+		&models.ProtectedNamespace{},
+		&models.DefaultValues{},
+		&models.TemplateRepo{},
+		&models.Addon{},
 		&models.Session{},
 		&models.GitRepo{},
 		&models.Registry{},
@@ -24,6 +38,7 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.ClusterResolver{},
 		&models.Database{},
 		&models.Infra{},
+		&models.InfraResource{},
 		&models.GitActionConfig{},
 		&models.Invite{},
 		&models.AuthCode{},
@@ -31,8 +46,10 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.PWResetToken{},
 		&models.NotificationConfig{},
 		&models.JobNotificationConfig{},
+		&models.HealthGateConfig{},
 		&models.EventContainer{},
 		&models.SubEvent{},
+		&models.BuildLog{},
 		&models.KubeEvent{},
 		&models.KubeSubEvent{},
 		&models.ProjectUsage{},
@@ -41,6 +58,28 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.CredentialsExchangeToken{},
 		&models.BuildConfig{},
 		&models.Allowlist{},
+		&models.InstanceQuota{},
+		&models.WhitelistedUser{},
+		&models.AdminAuditLog{},
+		&models.ServerSetting{},
+		&models.APIErrorLog{},
+		&models.SensitiveValues{},
+		&models.GitOpsConfig{},
+		&models.AllowedChart{},
+		&models.BuildpackDetection{},
+		&models.Backup{},
+		&models.Bucket{},
+		&models.Queue{},
+		&models.StaleRelease{},
+		&models.DeployFreeze{},
+		&models.DeployFreezeOverride{},
+		&models.ScheduledDeploy{},
+		&models.PreDeployRun{},
+		&models.CustomChart{},
+		&models.ManifestPolicy{},
+		&models.ImageSigningAuthority{},
+		&models.ImageSBOM{},
+		&models.SBOMPackage{},
 		&ints.KubeIntegration{},
 		&ints.BasicIntegration{},
 		&ints.OIDCIntegration{},
@@ -55,4 +94,16 @@ func AutoMigrate(db *gorm.DB) error {
 		&ints.GithubAppOAuthIntegration{},
 		&ints.SlackIntegration{},
 	)
+
+	if err != nil {
+		return err
+	}
+
+	if backfillPreviewEnvironments {
+		return db.Model(&models.ProjectUsage{}).
+			Where("preview_environments = ?", false).
+			Update("preview_environments", true).Error
+	}
+
+	return nil
 }
@@ -0,0 +1,158 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// PipelineRepository uses gorm.DB for querying the database
+type PipelineRepository struct {
+	db *gorm.DB
+}
+
+// NewPipelineRepository returns a PipelineRepository which uses gorm.DB for querying
+// the database
+func NewPipelineRepository(db *gorm.DB) repository.PipelineRepository {
+	return &PipelineRepository{db}
+}
+
+func (repo *PipelineRepository) CreatePipeline(pipeline *models.Pipeline) (*models.Pipeline, error) {
+	if err := repo.db.Create(pipeline).Error; err != nil {
+		return nil, err
+	}
+
+	return pipeline, nil
+}
+
+func (repo *PipelineRepository) ReadPipeline(projectID, pipelineID uint) (*models.Pipeline, error) {
+	pipeline := &models.Pipeline{}
+
+	if err := repo.db.Preload("Stages", orderStages).Where(
+		"project_id = ? AND id = ?",
+		projectID, pipelineID,
+	).First(&pipeline).Error; err != nil {
+		return nil, err
+	}
+
+	return pipeline, nil
+}
+
+func (repo *PipelineRepository) ListPipelines(projectID uint) ([]*models.Pipeline, error) {
+	pipelines := make([]*models.Pipeline, 0)
+
+	if err := repo.db.Preload("Stages", orderStages).Order("id asc").Where(
+		"project_id = ?",
+		projectID,
+	).Find(&pipelines).Error; err != nil {
+		return nil, err
+	}
+
+	return pipelines, nil
+}
+
+// DeletePipeline deletes a pipeline along with its stages, and its promotions and their
+// approvals
+func (repo *PipelineRepository) DeletePipeline(pipeline *models.Pipeline) (*models.Pipeline, error) {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		promotionIDs := tx.Model(&models.PipelinePromotion{}).Select("id").Where("pipeline_id = ?", pipeline.ID)
+
+		if err := tx.Where("promotion_id IN (?)", promotionIDs).Delete(&models.PipelinePromotionApproval{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("pipeline_id = ?", pipeline.ID).Delete(&models.PipelinePromotion{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("pipeline_id = ?", pipeline.ID).Delete(&models.PipelineStage{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&pipeline).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pipeline, nil
+}
+
+func (repo *PipelineRepository) CreatePromotion(promotion *models.PipelinePromotion) (*models.PipelinePromotion, error) {
+	if err := repo.db.Create(promotion).Error; err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (repo *PipelineRepository) ReadPromotion(pipelineID, promotionID uint) (*models.PipelinePromotion, error) {
+	promotion := &models.PipelinePromotion{}
+
+	if err := repo.db.Preload("Approvals").Where(
+		"pipeline_id = ? AND id = ?",
+		pipelineID, promotionID,
+	).First(&promotion).Error; err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (repo *PipelineRepository) ListPromotions(pipelineID uint) ([]*models.PipelinePromotion, error) {
+	promotions := make([]*models.PipelinePromotion, 0)
+
+	if err := repo.db.Preload("Approvals").Order("id desc").Where(
+		"pipeline_id = ?",
+		pipelineID,
+	).Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+func (repo *PipelineRepository) UpdatePromotion(promotion *models.PipelinePromotion) (*models.PipelinePromotion, error) {
+	if err := repo.db.Save(promotion).Error; err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// ClaimPromotion marks a pending promotion as running, and returns false if it is no
+// longer pending, such as when another request started the same promotion first
+func (repo *PipelineRepository) ClaimPromotion(promotion *models.PipelinePromotion) (bool, error) {
+	res := repo.db.Model(&models.PipelinePromotion{}).Where(
+		"id = ? AND status = ?",
+		promotion.ID, types.PromotionStatusPending,
+	).Update("status", types.PromotionStatusRunning)
+
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	promotion.Status = types.PromotionStatusRunning
+
+	return true, nil
+}
+
+func (repo *PipelineRepository) CreatePromotionApproval(
+	approval *models.PipelinePromotionApproval,
+) (*models.PipelinePromotionApproval, error) {
+	if err := repo.db.Create(approval).Error; err != nil {
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+func orderStages(db *gorm.DB) *gorm.DB {
+	return db.Order("pipeline_stages.position asc")
+}
@@ -0,0 +1,116 @@
+package gorm_test
+
+import (
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+func initPipelinePromotion(tester *tester, t *testing.T) (*models.Pipeline, *models.PipelinePromotion) {
+	t.Helper()
+
+	pipeline, err := tester.repo.Pipeline().CreatePipeline(&models.Pipeline{
+		ProjectID: 1,
+		Name:      "pipeline",
+		Stages: []models.PipelineStage{
+			{Position: 0, Name: "staging", ClusterID: 1, Namespace: "staging", ReleaseName: "web"},
+			{Position: 1, Name: "production", ClusterID: 1, Namespace: "production", ReleaseName: "web"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	promotion, err := tester.repo.Pipeline().CreatePromotion(&models.PipelinePromotion{
+		PipelineID:  pipeline.ID,
+		FromStageID: pipeline.Stages[0].ID,
+		ToStageID:   pipeline.Stages[1].ID,
+		Status:      types.PromotionStatusPending,
+		Revision:    1,
+	})
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	return pipeline, promotion
+}
+
+func TestClaimPromotion(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_claim_promotion.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	_, promotion := initPipelinePromotion(tester, t)
+
+	claimed, err := tester.repo.Pipeline().ClaimPromotion(promotion)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if !claimed || promotion.Status != types.PromotionStatusRunning {
+		t.Fatalf("expected the promotion to be claimed, got %t with status %s\n", claimed, promotion.Status)
+	}
+
+	// a promotion that is already running is not claimed again
+	stale := *promotion
+	stale.Status = types.PromotionStatusPending
+
+	claimed, err = tester.repo.Pipeline().ClaimPromotion(&stale)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if claimed {
+		t.Errorf("expected the running promotion not to be claimed again\n")
+	}
+}
+
+func TestDeletePipelineDeletesPromotions(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_delete_pipeline.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	pipeline, promotion := initPipelinePromotion(tester, t)
+
+	_, err := tester.repo.Pipeline().CreatePromotionApproval(&models.PipelinePromotionApproval{
+		PromotionID: promotion.ID,
+		UserID:      1,
+	})
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if _, err := tester.repo.Pipeline().DeletePipeline(pipeline); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	var count int64
+
+	if err := tester.db.Model(&models.PipelinePromotion{}).Where("pipeline_id = ?", pipeline.ID).Count(&count).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if count != 0 {
+		t.Errorf("expected the promotions of the pipeline to be deleted, got %d\n", count)
+	}
+
+	if err := tester.db.Model(&models.PipelinePromotionApproval{}).Where("promotion_id = ?", promotion.ID).Count(&count).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if count != 0 {
+		t.Errorf("expected the approvals of the promotions to be deleted, got %d\n", count)
+	}
+}
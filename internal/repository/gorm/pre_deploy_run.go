@@ -0,0 +1,51 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// PreDeployRunRepository uses gorm.DB for querying the database
+type PreDeployRunRepository struct {
+	db *gorm.DB
+}
+
+// NewPreDeployRunRepository returns a PreDeployRunRepository which uses gorm.DB for
+// querying the database
+func NewPreDeployRunRepository(db *gorm.DB) repository.PreDeployRunRepository {
+	return &PreDeployRunRepository{db}
+}
+
+func (repo *PreDeployRunRepository) CreatePreDeployRun(run *models.PreDeployRun) (*models.PreDeployRun, error) {
+	if err := repo.db.Create(run).Error; err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// ReadLatestPreDeployRun finds the most recent run of the pre-deploy command of a release
+func (repo *PreDeployRunRepository) ReadLatestPreDeployRun(
+	clusterID uint,
+	namespace, name string,
+) (*models.PreDeployRun, error) {
+	run := &models.PreDeployRun{}
+
+	if err := repo.db.Order("id desc").Where(
+		"cluster_id = ? AND namespace = ? AND name = ?",
+		clusterID, namespace, name,
+	).First(&run).Error; err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+func (repo *PreDeployRunRepository) UpdatePreDeployRun(run *models.PreDeployRun) (*models.PreDeployRun, error) {
+	if err := repo.db.Save(run).Error; err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
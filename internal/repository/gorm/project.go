@@ -57,6 +57,15 @@ func (repo *ProjectRepository) UpdateProjectRole(projID uint, role *models.Role)
 	return role, nil
 }
 
+// UpdateProject updates the fields of a project
+func (repo *ProjectRepository) UpdateProject(project *models.Project) (*models.Project, error) {
+	if err := repo.db.Save(project).Error; err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
 // ReadProject gets a projects specified by a unique id
 func (repo *ProjectRepository) ReadProject(id uint) (*models.Project, error) {
 	project := &models.Project{}
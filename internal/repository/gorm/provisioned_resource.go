@@ -0,0 +1,175 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/credentials"
+	"gorm.io/gorm"
+)
+
+// provisionedResourceRepository implements the queries that are shared by the resources
+// that are provisioned through an infra, such as buckets and queues. The credentials of
+// the resources are encrypted, and are written to the credential storage backend
+// instead of the DB if one is set.
+type provisionedResourceRepository struct {
+	db             *gorm.DB
+	key            *[32]byte
+	storageBackend credentials.CredentialStorage
+
+	// newResource returns an empty model of the resources of the repository
+	newResource func() models.ProvisionedResourceModel
+}
+
+// ReadProvisionedResourceByInfraID reads the resource that was provisioned by an infra,
+// along with its decrypted credentials
+func (repo *provisionedResourceRepository) ReadProvisionedResourceByInfraID(
+	projectID, infraID uint,
+) (models.ProvisionedResourceModel, error) {
+	resource := repo.newResource()
+
+	if err := repo.read(resource, "project_id = ? AND infra_id = ?", projectID, infraID); err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// UpdateProvisionedResource modifies an existing resource in the database
+func (repo *provisionedResourceRepository) UpdateProvisionedResource(
+	resource models.ProvisionedResourceModel,
+) (models.ProvisionedResourceModel, error) {
+	if err := repo.save(resource); err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// DeleteProvisionedResource deletes a resource
+func (repo *provisionedResourceRepository) DeleteProvisionedResource(resource models.ProvisionedResourceModel) error {
+	return repo.db.Delete(resource).Error
+}
+
+// read reads the first resource that matches a query into a model, along with its
+// decrypted credentials
+func (repo *provisionedResourceRepository) read(
+	resource models.ProvisionedResourceModel,
+	query string,
+	args ...interface{},
+) error {
+	if err := repo.db.Where(query, args...).First(resource).Error; err != nil {
+		return err
+	}
+
+	return repo.decryptCredentials(resource.GetProvisionedResource())
+}
+
+func (repo *provisionedResourceRepository) save(resource models.ProvisionedResourceModel) error {
+	res := resource.GetProvisionedResource()
+
+	plaintext := getProvisionedResourceCredential(res)
+
+	if err := repo.encryptCredentials(res); err != nil {
+		return err
+	}
+
+	// if storage backend is not nil, strip out the credentials, which will be stored in
+	// the credential storage backend after write to DB
+	credentialData := &credentials.ProvisionedResourceCredential{}
+
+	if repo.storageBackend != nil {
+		credentialData = getProvisionedResourceCredential(res)
+		stripProvisionedResourceCredentials(res)
+	}
+
+	if err := repo.db.Save(resource).Error; err != nil {
+		return err
+	}
+
+	if repo.storageBackend != nil {
+		if err := repo.storageBackend.WriteProvisionedResourceCredential(res, credentialData); err != nil {
+			return err
+		}
+	}
+
+	setProvisionedResourceCredential(res, plaintext)
+
+	return nil
+}
+
+// encryptCredentials will encrypt the credentials of a resource before writing to the DB
+func (repo *provisionedResourceRepository) encryptCredentials(res *models.ProvisionedResource) error {
+	for _, field := range getProvisionedResourceCredentialFields(res) {
+		if len(*field) == 0 {
+			continue
+		}
+
+		cipherData, err := repository.Encrypt(*field, repo.key)
+
+		if err != nil {
+			return err
+		}
+
+		*field = cipherData
+	}
+
+	return nil
+}
+
+// decryptCredentials will decrypt the credentials of a resource, reading them from the
+// credential storage backend if one is set
+func (repo *provisionedResourceRepository) decryptCredentials(res *models.ProvisionedResource) error {
+	if repo.storageBackend != nil {
+		credentialData, err := repo.storageBackend.GetProvisionedResourceCredential(res)
+
+		if err != nil {
+			return err
+		}
+
+		setProvisionedResourceCredential(res, credentialData)
+	}
+
+	for _, field := range getProvisionedResourceCredentialFields(res) {
+		if len(*field) == 0 {
+			continue
+		}
+
+		plaintext, err := repository.Decrypt(*field, repo.key)
+
+		if err != nil {
+			return err
+		}
+
+		*field = plaintext
+	}
+
+	return nil
+}
+
+func getProvisionedResourceCredentialFields(res *models.ProvisionedResource) []*[]byte {
+	return []*[]byte{&res.AWSAccessKeyID, &res.AWSSecretAccessKey, &res.GCPKeyData}
+}
+
+func getProvisionedResourceCredential(res *models.ProvisionedResource) *credentials.ProvisionedResourceCredential {
+	return &credentials.ProvisionedResourceCredential{
+		AWSAccessKeyID:     res.AWSAccessKeyID,
+		AWSSecretAccessKey: res.AWSSecretAccessKey,
+		GCPKeyData:         res.GCPKeyData,
+	}
+}
+
+func setProvisionedResourceCredential(res *models.ProvisionedResource, data *credentials.ProvisionedResourceCredential) {
+	res.AWSAccessKeyID = data.AWSAccessKeyID
+	res.AWSSecretAccessKey = data.AWSSecretAccessKey
+	res.GCPKeyData = data.GCPKeyData
+}
+
+// stripProvisionedResourceCredentials removes the credentials of a resource, such as
+// before the resource is listed
+func stripProvisionedResourceCredentials(res *models.ProvisionedResource) {
+	setProvisionedResourceCredential(res, &credentials.ProvisionedResourceCredential{
+		AWSAccessKeyID:     []byte{},
+		AWSSecretAccessKey: []byte{},
+		GCPKeyData:         []byte{},
+	})
+}
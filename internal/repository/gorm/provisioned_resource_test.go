@@ -0,0 +1,203 @@
+package gorm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	_gorm "gorm.io/gorm"
+)
+
+func TestCreateBucket(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_create_bucket.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	bucket := &models.Bucket{
+		ProvisionedResource: models.ProvisionedResource{
+			ProjectID:          1,
+			ClusterID:          1,
+			InfraID:            1,
+			Kind:               types.InfraS3,
+			Name:               "uploads",
+			AWSAccessKeyID:     []byte("AKIA"),
+			AWSSecretAccessKey: []byte("secret"),
+		},
+	}
+
+	bucket, err := tester.repo.Bucket().CreateBucket(bucket)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	// the credentials should be returned in plaintext, but encrypted in the database
+	if string(bucket.AWSSecretAccessKey) != "secret" {
+		t.Errorf("expected the plaintext credentials to be returned, got %q", bucket.AWSSecretAccessKey)
+	}
+
+	stored := &models.Bucket{}
+
+	if err := tester.db.First(stored, bucket.ID).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if string(stored.AWSSecretAccessKey) == "secret" {
+		t.Errorf("expected the credentials to be encrypted in the database")
+	}
+
+	bucket, err = tester.repo.Bucket().ReadBucket(1, 1, bucket.ID)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if string(bucket.AWSAccessKeyID) != "AKIA" || string(bucket.AWSSecretAccessKey) != "secret" {
+		t.Errorf("expected the credentials to be decrypted, got %q and %q", bucket.AWSAccessKeyID, bucket.AWSSecretAccessKey)
+	}
+
+	buckets, err := tester.repo.Bucket().ListBuckets(1, 1)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(buckets) != 1 || len(buckets[0].AWSSecretAccessKey) != 0 {
+		t.Errorf("expected one bucket to be listed without its credentials, got %v", buckets)
+	}
+}
+
+func TestCreateQueue(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_create_queue.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	queue := &models.Queue{
+		ProvisionedResource: models.ProvisionedResource{
+			ProjectID:          1,
+			ClusterID:          1,
+			InfraID:            1,
+			Kind:               types.InfraSQS,
+			Name:               "jobs",
+			AWSAccessKeyID:     []byte("AKIA"),
+			AWSSecretAccessKey: []byte("secret"),
+		},
+		URL: "https://sqs.us-east-1.amazonaws.com/123/jobs",
+	}
+
+	queue, err := tester.repo.Queue().CreateQueue(queue)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	stored := &models.Queue{}
+
+	if err := tester.db.First(stored, queue.ID).Error; err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if string(stored.AWSSecretAccessKey) == "secret" {
+		t.Errorf("expected the credentials to be encrypted in the database")
+	}
+
+	queue, err = tester.repo.Queue().ReadQueue(1, 1, queue.ID)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if queue.URL != "https://sqs.us-east-1.amazonaws.com/123/jobs" || string(queue.AWSSecretAccessKey) != "secret" {
+		t.Errorf("expected the queue to be read with its decrypted credentials, got %q and %q", queue.URL, queue.AWSSecretAccessKey)
+	}
+
+	queues, err := tester.repo.Queue().ListQueues(1, 1)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if len(queues) != 1 || len(queues[0].AWSSecretAccessKey) != 0 {
+		t.Errorf("expected one queue to be listed without its credentials, got %v", queues)
+	}
+}
+
+func TestProvisionedResourceByInfraID(t *testing.T) {
+	tester := &tester{
+		dbFileName: "./porter_provisioned_resource.db",
+	}
+
+	setupTestEnv(tester, t)
+	defer cleanup(tester, t)
+
+	queue := &models.Queue{
+		ProvisionedResource: models.ProvisionedResource{
+			ProjectID:  1,
+			ClusterID:  1,
+			InfraID:    2,
+			Kind:       types.InfraPubSub,
+			Name:       "jobs",
+			GCPKeyData: []byte(`{"type":"service_account"}`),
+		},
+		SubscriptionName: "jobs-sub",
+	}
+
+	if _, err := tester.repo.Queue().CreateQueue(queue); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	resourceRepo, ok := repository.GetProvisionedResourceRepository(tester.repo, types.InfraPubSub)
+
+	if !ok {
+		t.Fatalf("expected a repository for Pub/Sub infras")
+	}
+
+	// buckets and queues are stored separately, so the queue is not read as a bucket
+	if _, err := tester.repo.Bucket().ReadProvisionedResourceByInfraID(1, 2); !errors.Is(err, _gorm.ErrRecordNotFound) {
+		t.Errorf("expected no bucket for the infra of the queue, got %v", err)
+	}
+
+	resource, err := resourceRepo.ReadProvisionedResourceByInfraID(1, 2)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	read, ok := resource.(*models.Queue)
+
+	if !ok || read.SubscriptionName != "jobs-sub" || string(read.GCPKeyData) != `{"type":"service_account"}` {
+		t.Fatalf("expected the decrypted queue of the infra, got %v", resource)
+	}
+
+	read.Status = "destroying"
+
+	if _, err := resourceRepo.UpdateProvisionedResource(read); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	queue, err = tester.repo.Queue().ReadQueue(1, 1, read.ID)
+
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if queue.Status != "destroying" || string(queue.GCPKeyData) != `{"type":"service_account"}` {
+		t.Errorf("expected the status to be updated and the credentials to be kept, got %q and %q", queue.Status, queue.GCPKeyData)
+	}
+
+	if err := resourceRepo.DeleteProvisionedResource(queue); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if _, err := resourceRepo.ReadProvisionedResourceByInfraID(1, 2); !errors.Is(err, _gorm.ErrRecordNotFound) {
+		t.Errorf("expected the queue to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/credentials"
+	"gorm.io/gorm"
+)
+
+// QueueRepository uses gorm.DB for querying the database
+type QueueRepository struct {
+	*provisionedResourceRepository
+}
+
+// NewQueueRepository returns a QueueRepository which uses gorm.DB for querying
+// the database. It accepts an encryption key to encrypt the credentials of the
+// queues, which are written to the credential storage backend instead of the DB if
+// one is set.
+func NewQueueRepository(
+	db *gorm.DB,
+	key *[32]byte,
+	storageBackend credentials.CredentialStorage,
+) repository.QueueRepository {
+	return &QueueRepository{&provisionedResourceRepository{
+		db:             db,
+		key:            key,
+		storageBackend: storageBackend,
+		newResource: func() models.ProvisionedResourceModel {
+			return &models.Queue{}
+		},
+	}}
+}
+
+// CreateQueue creates a new queue
+func (repo *QueueRepository) CreateQueue(queue *models.Queue) (*models.Queue, error) {
+	if err := repo.save(queue); err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+// ReadQueue reads a queue by ID, along with its decrypted credentials
+func (repo *QueueRepository) ReadQueue(projectID, clusterID, queueID uint) (*models.Queue, error) {
+	queue := &models.Queue{}
+
+	if err := repo.read(
+		queue,
+		"project_id = ? AND cluster_id = ? AND id = ?",
+		projectID, clusterID, queueID,
+	); err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+// ListQueues lists the queues of a cluster, without their credentials
+func (repo *QueueRepository) ListQueues(projectID, clusterID uint) ([]*models.Queue, error) {
+	queues := []*models.Queue{}
+
+	if err := repo.db.Where("project_id = ? AND cluster_id = ?", projectID, clusterID).Find(&queues).Error; err != nil {
+		return nil, err
+	}
+
+	for _, queue := range queues {
+		stripProvisionedResourceCredentials(&queue.ProvisionedResource)
+	}
+
+	return queues, nil
+}
@@ -49,6 +49,53 @@ func (repo *ReleaseRepository) ListReleasesByImageRepoURI(clusterID uint, imageR
 	return releases, nil
 }
 
+// ListReleasesByImageSBOMIDs finds the releases of a project that run the images of
+// dependency inventories
+func (repo *ReleaseRepository) ListReleasesByImageSBOMIDs(projectID uint, imageSBOMIDs []uint) ([]*models.Release, error) {
+	releases := make([]*models.Release, 0)
+
+	if len(imageSBOMIDs) == 0 {
+		return releases, nil
+	}
+
+	if err := repo.db.Where(
+		"project_id = ? AND image_sbom_id IN ?",
+		projectID, imageSBOMIDs,
+	).Order("id asc").Find(&releases).Error; err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+// ListDeletionProtectedReleases finds the releases of a cluster with deletion protection
+func (repo *ReleaseRepository) ListDeletionProtectedReleases(clusterID uint) ([]*models.Release, error) {
+	releases := make([]*models.Release, 0)
+
+	if err := repo.db.Where(
+		"cluster_id = ? AND deletion_protection = ?",
+		clusterID, true,
+	).Order("id asc").Find(&releases).Error; err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+// ListReleasesByNamespace finds the releases in a namespace of a cluster
+func (repo *ReleaseRepository) ListReleasesByNamespace(clusterID uint, namespace string) ([]*models.Release, error) {
+	releases := make([]*models.Release, 0)
+
+	if err := repo.db.Where(
+		"cluster_id = ? AND namespace = ?",
+		clusterID, namespace,
+	).Order("id asc").Find(&releases).Error; err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
 // ReadReleaseByWebhookToken finds a single release based on their unique webhook token.
 func (repo *ReleaseRepository) ReadReleaseByWebhookToken(token string) (*models.Release, error) {
 	release := &models.Release{}
@@ -19,6 +19,11 @@ type GormRepository struct {
 	invite                    repository.InviteRepository
 	release                   repository.ReleaseRepository
 	environment               repository.EnvironmentRepository
+	pipeline                  repository.PipelineRepository
+	changeRequest             repository.ChangeRequestRepository
+	defaultValues             repository.DefaultValuesRepository
+	templateRepo              repository.TemplateRepoRepository
+	addon                     repository.AddonRepository
 	authCode                  repository.AuthCodeRepository
 	dnsRecord                 repository.DNSRecordRepository
 	pwResetToken              repository.PWResetTokenRepository
@@ -34,13 +39,33 @@ type GormRepository struct {
 	slackIntegration          repository.SlackIntegrationRepository
 	notificationConfig        repository.NotificationConfigRepository
 	jobNotificationConfig     repository.JobNotificationConfigRepository
+	healthGateConfig          repository.HealthGateConfigRepository
 	buildEvent                repository.BuildEventRepository
+	buildLog                  repository.BuildLogRepository
 	kubeEvent                 repository.KubeEventRepository
 	projectUsage              repository.ProjectUsageRepository
 	onboarding                repository.ProjectOnboardingRepository
 	ceToken                   repository.CredentialsExchangeTokenRepository
 	buildConfig               repository.BuildConfigRepository
 	allowlist                 repository.AllowlistRepository
+	instanceAdmin             repository.InstanceAdminRepository
+	serverSetting             repository.ServerSettingRepository
+	apiErrorLog               repository.APIErrorLogRepository
+	sensitiveValues           repository.SensitiveValuesRepository
+	gitOpsConfig              repository.GitOpsConfigRepository
+	allowedChart              repository.AllowedChartRepository
+	buildpackDetection        repository.BuildpackDetectionRepository
+	backup                    repository.BackupRepository
+	bucket                    repository.BucketRepository
+	queue                     repository.QueueRepository
+	staleRelease              repository.StaleReleaseRepository
+	deployFreeze              repository.DeployFreezeRepository
+	scheduledDeploy           repository.ScheduledDeployRepository
+	preDeployRun              repository.PreDeployRunRepository
+	customChart               repository.CustomChartRepository
+	manifestPolicy            repository.ManifestPolicyRepository
+	imageSigningAuthority     repository.ImageSigningAuthorityRepository
+	imageSBOM                 repository.ImageSBOMRepository
 }
 
 func (t *GormRepository) User() repository.UserRepository {
@@ -91,6 +116,26 @@ func (t *GormRepository) Environment() repository.EnvironmentRepository {
 	return t.environment
 }
 
+func (t *GormRepository) Pipeline() repository.PipelineRepository {
+	return t.pipeline
+}
+
+func (t *GormRepository) ChangeRequest() repository.ChangeRequestRepository {
+	return t.changeRequest
+}
+
+func (t *GormRepository) DefaultValues() repository.DefaultValuesRepository {
+	return t.defaultValues
+}
+
+func (t *GormRepository) TemplateRepo() repository.TemplateRepoRepository {
+	return t.templateRepo
+}
+
+func (t *GormRepository) Addon() repository.AddonRepository {
+	return t.addon
+}
+
 func (t *GormRepository) AuthCode() repository.AuthCodeRepository {
 	return t.authCode
 }
@@ -151,10 +196,18 @@ func (t *GormRepository) JobNotificationConfig() repository.JobNotificationConfi
 	return t.jobNotificationConfig
 }
 
+func (t *GormRepository) HealthGateConfig() repository.HealthGateConfigRepository {
+	return t.healthGateConfig
+}
+
 func (t *GormRepository) BuildEvent() repository.BuildEventRepository {
 	return t.buildEvent
 }
 
+func (t *GormRepository) BuildLog() repository.BuildLogRepository {
+	return t.buildLog
+}
+
 func (t *GormRepository) KubeEvent() repository.KubeEventRepository {
 	return t.kubeEvent
 }
@@ -179,6 +232,78 @@ func (t *GormRepository) Allowlist() repository.AllowlistRepository {
 	return t.allowlist
 }
 
+func (t *GormRepository) InstanceAdmin() repository.InstanceAdminRepository {
+	return t.instanceAdmin
+}
+
+func (t *GormRepository) ServerSetting() repository.ServerSettingRepository {
+	return t.serverSetting
+}
+
+func (t *GormRepository) APIErrorLog() repository.APIErrorLogRepository {
+	return t.apiErrorLog
+}
+
+func (t *GormRepository) SensitiveValues() repository.SensitiveValuesRepository {
+	return t.sensitiveValues
+}
+
+func (t *GormRepository) GitOpsConfig() repository.GitOpsConfigRepository {
+	return t.gitOpsConfig
+}
+
+func (t *GormRepository) AllowedChart() repository.AllowedChartRepository {
+	return t.allowedChart
+}
+
+func (t *GormRepository) BuildpackDetection() repository.BuildpackDetectionRepository {
+	return t.buildpackDetection
+}
+
+func (t *GormRepository) Backup() repository.BackupRepository {
+	return t.backup
+}
+
+func (t *GormRepository) Bucket() repository.BucketRepository {
+	return t.bucket
+}
+
+func (t *GormRepository) Queue() repository.QueueRepository {
+	return t.queue
+}
+
+func (t *GormRepository) StaleRelease() repository.StaleReleaseRepository {
+	return t.staleRelease
+}
+
+func (t *GormRepository) DeployFreeze() repository.DeployFreezeRepository {
+	return t.deployFreeze
+}
+
+func (t *GormRepository) ScheduledDeploy() repository.ScheduledDeployRepository {
+	return t.scheduledDeploy
+}
+
+func (t *GormRepository) PreDeployRun() repository.PreDeployRunRepository {
+	return t.preDeployRun
+}
+
+func (t *GormRepository) CustomChart() repository.CustomChartRepository {
+	return t.customChart
+}
+
+func (t *GormRepository) ManifestPolicy() repository.ManifestPolicyRepository {
+	return t.manifestPolicy
+}
+
+func (t *GormRepository) ImageSigningAuthority() repository.ImageSigningAuthorityRepository {
+	return t.imageSigningAuthority
+}
+
+func (t *GormRepository) ImageSBOM() repository.ImageSBOMRepository {
+	return t.imageSBOM
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.CredentialStorage) repository.Repository {
@@ -195,6 +320,11 @@ func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.Creden
 		invite:                    NewInviteRepository(db),
 		release:                   NewReleaseRepository(db),
 		environment:               NewEnvironmentRepository(db),
+		pipeline:                  NewPipelineRepository(db),
+		changeRequest:             NewChangeRequestRepository(db, key),
+		defaultValues:             NewDefaultValuesRepository(db),
+		templateRepo:              NewTemplateRepoRepository(db),
+		addon:                     NewAddonRepository(db),
 		authCode:                  NewAuthCodeRepository(db),
 		dnsRecord:                 NewDNSRecordRepository(db),
 		pwResetToken:              NewPWResetTokenRepository(db),
@@ -205,17 +335,37 @@ func NewRepository(db *gorm.DB, key *[32]byte, storageBackend credentials.Creden
 		oauthIntegration:          NewOAuthIntegrationRepository(db, key, storageBackend),
 		gcpIntegration:            NewGCPIntegrationRepository(db, key, storageBackend),
 		awsIntegration:            NewAWSIntegrationRepository(db, key, storageBackend),
-		githubAppInstallation:     NewGithubAppInstallationRepository(db),
+		githubAppInstallation:     NewGithubAppInstallationRepository(db, key),
 		githubAppOAuthIntegration: NewGithubAppOAuthIntegrationRepository(db),
 		slackIntegration:          NewSlackIntegrationRepository(db, key),
 		notificationConfig:        NewNotificationConfigRepository(db),
 		jobNotificationConfig:     NewJobNotificationConfigRepository(db),
+		healthGateConfig:          NewHealthGateConfigRepository(db),
 		buildEvent:                NewBuildEventRepository(db),
+		buildLog:                  NewBuildLogRepository(db),
 		kubeEvent:                 NewKubeEventRepository(db, key),
 		projectUsage:              NewProjectUsageRepository(db),
 		onboarding:                NewProjectOnboardingRepository(db),
 		ceToken:                   NewCredentialsExchangeTokenRepository(db),
 		buildConfig:               NewBuildConfigRepository(db),
 		allowlist:                 NewAllowlistRepository(db),
+		instanceAdmin:             NewInstanceAdminRepository(db),
+		serverSetting:             NewServerSettingRepository(db),
+		apiErrorLog:               NewAPIErrorLogRepository(db),
+		sensitiveValues:           NewSensitiveValuesRepository(db, key, storageBackend),
+		gitOpsConfig:              NewGitOpsConfigRepository(db),
+		allowedChart:              NewAllowedChartRepository(db),
+		buildpackDetection:        NewBuildpackDetectionRepository(db),
+		backup:                    NewBackupRepository(db),
+		bucket:                    NewBucketRepository(db, key, storageBackend),
+		queue:                     NewQueueRepository(db, key, storageBackend),
+		staleRelease:              NewStaleReleaseRepository(db),
+		deployFreeze:              NewDeployFreezeRepository(db),
+		scheduledDeploy:           NewScheduledDeployRepository(db),
+		preDeployRun:              NewPreDeployRunRepository(db),
+		customChart:               NewCustomChartRepository(db),
+		manifestPolicy:            NewManifestPolicyRepository(db),
+		imageSigningAuthority:     NewImageSigningAuthorityRepository(db),
+		imageSBOM:                 NewImageSBOMRepository(db),
 	}
 }
@@ -0,0 +1,127 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ScheduledDeployRepository uses gorm.DB for querying the database
+type ScheduledDeployRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduledDeployRepository returns a ScheduledDeployRepository which uses gorm.DB for
+// querying the database
+func NewScheduledDeployRepository(db *gorm.DB) repository.ScheduledDeployRepository {
+	return &ScheduledDeployRepository{db}
+}
+
+func (repo *ScheduledDeployRepository) CreateScheduledDeploy(sd *models.ScheduledDeploy) (*models.ScheduledDeploy, error) {
+	if err := repo.db.Create(sd).Error; err != nil {
+		return nil, err
+	}
+
+	return sd, nil
+}
+
+func (repo *ScheduledDeployRepository) ReadScheduledDeploy(clusterID, id uint) (*models.ScheduledDeploy, error) {
+	sd := &models.ScheduledDeploy{}
+
+	if err := repo.db.Where("cluster_id = ? AND id = ?", clusterID, id).First(&sd).Error; err != nil {
+		return nil, err
+	}
+
+	return sd, nil
+}
+
+func (repo *ScheduledDeployRepository) ListScheduledDeploys(
+	clusterID uint,
+	namespace, name string,
+	statuses ...types.ScheduledDeployStatus,
+) ([]*models.ScheduledDeploy, error) {
+	sds := make([]*models.ScheduledDeploy, 0)
+
+	query := repo.db.Order("run_at desc").Where(
+		"cluster_id = ? AND namespace = ? AND name = ?",
+		clusterID, namespace, name,
+	)
+
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+
+	if err := query.Find(&sds).Error; err != nil {
+		return nil, err
+	}
+
+	return sds, nil
+}
+
+// ListDueScheduledDeploys finds the pending scheduled deploys of all clusters whose run
+// time has passed, from earliest
+func (repo *ScheduledDeployRepository) ListDueScheduledDeploys(now time.Time) ([]*models.ScheduledDeploy, error) {
+	sds := make([]*models.ScheduledDeploy, 0)
+
+	if err := repo.db.Order("run_at asc").Where(
+		"status = ? AND run_at <= ?",
+		types.ScheduledDeployPending, now,
+	).Find(&sds).Error; err != nil {
+		return nil, err
+	}
+
+	return sds, nil
+}
+
+// ClaimScheduledDeploy marks a pending scheduled deploy as running, and returns false if
+// it is no longer pending, such as when another server replica claimed it first
+func (repo *ScheduledDeployRepository) ClaimScheduledDeploy(sd *models.ScheduledDeploy) (bool, error) {
+	now := time.Now()
+
+	res := repo.db.Model(&models.ScheduledDeploy{}).Where(
+		"id = ? AND status = ?",
+		sd.ID, types.ScheduledDeployPending,
+	).Updates(map[string]interface{}{
+		"status":     types.ScheduledDeployRunning,
+		"claimed_at": now,
+	})
+
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	sd.Status = types.ScheduledDeployRunning
+	sd.ClaimedAt = &now
+
+	return true, nil
+}
+
+// ListStaleScheduledDeploys finds the running scheduled deploys of all clusters that were
+// claimed before the given time
+func (repo *ScheduledDeployRepository) ListStaleScheduledDeploys(claimedBefore time.Time) ([]*models.ScheduledDeploy, error) {
+	sds := make([]*models.ScheduledDeploy, 0)
+
+	if err := repo.db.Where(
+		"status = ? AND (claimed_at IS NULL OR claimed_at < ?)",
+		types.ScheduledDeployRunning, claimedBefore,
+	).Find(&sds).Error; err != nil {
+		return nil, err
+	}
+
+	return sds, nil
+}
+
+func (repo *ScheduledDeployRepository) UpdateScheduledDeploy(sd *models.ScheduledDeploy) (*models.ScheduledDeploy, error) {
+	if err := repo.db.Save(sd).Error; err != nil {
+		return nil, err
+	}
+
+	return sd, nil
+}
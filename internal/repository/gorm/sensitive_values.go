@@ -0,0 +1,136 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"github.com/porter-dev/porter/internal/repository/credentials"
+	"gorm.io/gorm"
+)
+
+// SensitiveValuesRepository uses gorm.DB for querying the database
+type SensitiveValuesRepository struct {
+	db             *gorm.DB
+	key            *[32]byte
+	storageBackend credentials.CredentialStorage
+}
+
+// NewSensitiveValuesRepository returns a SensitiveValuesRepository which uses
+// gorm.DB for querying the database. It accepts an encryption key to encrypt
+// the sensitive values, which are written to the credential storage backend
+// instead of the DB if one is set.
+func NewSensitiveValuesRepository(
+	db *gorm.DB,
+	key *[32]byte,
+	storageBackend credentials.CredentialStorage,
+) repository.SensitiveValuesRepository {
+	return &SensitiveValuesRepository{db, key, storageBackend}
+}
+
+// ReadSensitiveValues reads the sensitive values of the latest revision of a release
+func (repo *SensitiveValuesRepository) ReadSensitiveValues(
+	clusterID uint,
+	namespace, name string,
+) (*models.SensitiveValues, error) {
+	sensitiveValues := &models.SensitiveValues{}
+
+	if err := repo.db.Order("revision desc, id desc").Where(
+		"cluster_id = ? AND namespace = ? AND name = ?",
+		clusterID, namespace, name,
+	).First(sensitiveValues).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.decryptSensitiveValues(sensitiveValues)
+}
+
+// ReadSensitiveValuesRevision reads the sensitive values of a revision of a release
+func (repo *SensitiveValuesRepository) ReadSensitiveValuesRevision(
+	clusterID uint,
+	namespace, name string,
+	revision int,
+) (*models.SensitiveValues, error) {
+	sensitiveValues := &models.SensitiveValues{}
+
+	if err := repo.db.Order("id desc").Where(
+		"cluster_id = ? AND namespace = ? AND name = ? AND revision = ?",
+		clusterID, namespace, name, revision,
+	).First(sensitiveValues).Error; err != nil {
+		return nil, err
+	}
+
+	return repo.decryptSensitiveValues(sensitiveValues)
+}
+
+func (repo *SensitiveValuesRepository) decryptSensitiveValues(
+	sensitiveValues *models.SensitiveValues,
+) (*models.SensitiveValues, error) {
+	if repo.storageBackend != nil {
+		credentialData, err := repo.storageBackend.GetSensitiveValuesCredential(sensitiveValues)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sensitiveValues.Values = credentialData.Values
+	}
+
+	if len(sensitiveValues.Values) > 0 {
+		plaintext, err := repository.Decrypt(sensitiveValues.Values, repo.key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sensitiveValues.Values = plaintext
+	}
+
+	return sensitiveValues, nil
+}
+
+// UpdateSensitiveValues creates or updates the sensitive values of a release
+func (repo *SensitiveValuesRepository) UpdateSensitiveValues(
+	sensitiveValues *models.SensitiveValues,
+) (*models.SensitiveValues, error) {
+	plaintext := sensitiveValues.Values
+
+	if len(plaintext) > 0 {
+		cipherData, err := repository.Encrypt(plaintext, repo.key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sensitiveValues.Values = cipherData
+	}
+
+	// if storage backend is not nil, strip out the values, which will be stored in the
+	// credential storage backend after write to DB
+	var credentialData = &credentials.SensitiveValuesCredential{}
+
+	if repo.storageBackend != nil {
+		credentialData.Values = sensitiveValues.Values
+		sensitiveValues.Values = []byte{}
+	}
+
+	if err := repo.db.Save(sensitiveValues).Error; err != nil {
+		return nil, err
+	}
+
+	if repo.storageBackend != nil {
+		if err := repo.storageBackend.WriteSensitiveValuesCredential(sensitiveValues, credentialData); err != nil {
+			return nil, err
+		}
+	}
+
+	sensitiveValues.Values = plaintext
+
+	return sensitiveValues, nil
+}
+
+// DeleteSensitiveValues deletes the sensitive values of every revision of a release
+func (repo *SensitiveValuesRepository) DeleteSensitiveValues(clusterID uint, namespace, name string) error {
+	return repo.db.Where(
+		"cluster_id = ? AND namespace = ? AND name = ?",
+		clusterID, namespace, name,
+	).Delete(&models.SensitiveValues{}).Error
+}
@@ -0,0 +1,58 @@
+package gorm
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ServerSettingRepository uses gorm.DB for querying the database
+type ServerSettingRepository struct {
+	db *gorm.DB
+}
+
+// NewServerSettingRepository returns a ServerSettingRepository which uses
+// gorm.DB for querying the database
+func NewServerSettingRepository(db *gorm.DB) repository.ServerSettingRepository {
+	return &ServerSettingRepository{db}
+}
+
+func (repo *ServerSettingRepository) ListServerSettings() ([]*models.ServerSetting, error) {
+	settings := []*models.ServerSetting{}
+
+	if err := repo.db.Find(&settings).Error; err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// UpsertServerSetting creates the setting, or updates the value of the setting if it
+// already exists
+func (repo *ServerSettingRepository) UpsertServerSetting(setting *models.ServerSetting) (*models.ServerSetting, error) {
+	existing := &models.ServerSetting{}
+
+	err := repo.db.Where("key = ?", setting.Key).First(existing).Error
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	} else if err == nil {
+		setting.ID = existing.ID
+		setting.CreatedAt = existing.CreatedAt
+	}
+
+	if err := repo.db.Save(setting).Error; err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+// DeleteServerSetting removes the override of a setting. The row is deleted rather than
+// soft-deleted, so that the setting can be overridden again.
+func (repo *ServerSettingRepository) DeleteServerSetting(key types.ServerSettingKey) error {
+	return repo.db.Unscoped().Where("key = ?", key).Delete(&models.ServerSetting{}).Error
+}
@@ -58,6 +58,24 @@ func (repo *SlackIntegrationRepository) ListSlackIntegrationsByProjectID(
 	return slackInts, nil
 }
 
+// ListSlackIntegrationsByTeamID finds all slack integrations for a given Slack team
+// id, which may belong to multiple projects
+func (repo *SlackIntegrationRepository) ListSlackIntegrationsByTeamID(
+	teamID string,
+) ([]*ints.SlackIntegration, error) {
+	slackInts := []*ints.SlackIntegration{}
+
+	if err := repo.db.Where("team_id = ?", teamID).Find(&slackInts).Error; err != nil {
+		return nil, err
+	}
+
+	for _, slackInt := range slackInts {
+		repo.DecryptSlackIntegrationData(slackInt, repo.key)
+	}
+
+	return slackInts, nil
+}
+
 // DeleteSlackIntegration deletes a slack integration by ID
 func (repo *SlackIntegrationRepository) DeleteSlackIntegration(
 	integrationID uint,
@@ -0,0 +1,67 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// StaleReleaseRepository uses gorm.DB for querying the database
+type StaleReleaseRepository struct {
+	db *gorm.DB
+}
+
+// NewStaleReleaseRepository returns a StaleReleaseRepository which uses
+// gorm.DB for querying the database
+func NewStaleReleaseRepository(db *gorm.DB) repository.StaleReleaseRepository {
+	return &StaleReleaseRepository{db}
+}
+
+// CreateStaleRelease starts tracking a release that has not been deployed recently
+func (repo *StaleReleaseRepository) CreateStaleRelease(staleRelease *models.StaleRelease) (*models.StaleRelease, error) {
+	if err := repo.db.Create(staleRelease).Error; err != nil {
+		return nil, err
+	}
+
+	return staleRelease, nil
+}
+
+// ListStaleReleasesByClusterID finds all tracked releases of a cluster
+func (repo *StaleReleaseRepository) ListStaleReleasesByClusterID(clusterID uint) ([]*models.StaleRelease, error) {
+	staleReleases := []*models.StaleRelease{}
+
+	if err := repo.db.Where("cluster_id = ?", clusterID).Order("id asc").Find(&staleReleases).Error; err != nil {
+		return nil, err
+	}
+
+	return staleReleases, nil
+}
+
+// ListStaleReleasesByProjectID finds all tracked releases of the clusters of a project
+func (repo *StaleReleaseRepository) ListStaleReleasesByProjectID(projectID uint) ([]*models.StaleRelease, error) {
+	staleReleases := []*models.StaleRelease{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Order("id asc").Find(&staleReleases).Error; err != nil {
+		return nil, err
+	}
+
+	return staleReleases, nil
+}
+
+// UpdateStaleRelease modifies an existing tracked release in the database
+func (repo *StaleReleaseRepository) UpdateStaleRelease(staleRelease *models.StaleRelease) (*models.StaleRelease, error) {
+	if err := repo.db.Save(staleRelease).Error; err != nil {
+		return nil, err
+	}
+
+	return staleRelease, nil
+}
+
+// DeleteStaleRelease stops tracking a release
+func (repo *StaleReleaseRepository) DeleteStaleRelease(staleRelease *models.StaleRelease) (*models.StaleRelease, error) {
+	if err := repo.db.Delete(staleRelease).Error; err != nil {
+		return nil, err
+	}
+
+	return staleRelease, nil
+}
@@ -0,0 +1,58 @@
+package gorm
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// TemplateRepoRepository uses gorm.DB for querying the database
+type TemplateRepoRepository struct {
+	db *gorm.DB
+}
+
+// NewTemplateRepoRepository returns a TemplateRepoRepository which uses
+// gorm.DB for querying the database
+func NewTemplateRepoRepository(db *gorm.DB) repository.TemplateRepoRepository {
+	return &TemplateRepoRepository{db}
+}
+
+// CreateTemplateRepo creates a new template repo
+func (repo *TemplateRepoRepository) CreateTemplateRepo(templateRepo *models.TemplateRepo) (*models.TemplateRepo, error) {
+	if err := repo.db.Create(templateRepo).Error; err != nil {
+		return nil, err
+	}
+
+	return templateRepo, nil
+}
+
+// ReadTemplateRepo finds a template repo by project id and id
+func (repo *TemplateRepoRepository) ReadTemplateRepo(projectID, id uint) (*models.TemplateRepo, error) {
+	templateRepo := &models.TemplateRepo{}
+
+	if err := repo.db.Where("project_id = ? AND id = ?", projectID, id).First(templateRepo).Error; err != nil {
+		return nil, err
+	}
+
+	return templateRepo, nil
+}
+
+// ListTemplateReposByProjectID finds all template repos registered by a project
+func (repo *TemplateRepoRepository) ListTemplateReposByProjectID(projectID uint) ([]*models.TemplateRepo, error) {
+	templateRepos := []*models.TemplateRepo{}
+
+	if err := repo.db.Where("project_id = ?", projectID).Order("id asc").Find(&templateRepos).Error; err != nil {
+		return nil, err
+	}
+
+	return templateRepos, nil
+}
+
+// DeleteTemplateRepo deletes a template repo
+func (repo *TemplateRepoRepository) DeleteTemplateRepo(templateRepo *models.TemplateRepo) (*models.TemplateRepo, error) {
+	if err := repo.db.Delete(templateRepo).Error; err != nil {
+		return nil, err
+	}
+
+	return templateRepo, nil
+}
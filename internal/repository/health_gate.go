@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+)
+
+type HealthGateConfigRepository interface {
+	CreateHealthGateConfig(conf *models.HealthGateConfig) (*models.HealthGateConfig, error)
+	ReadHealthGateConfig(id uint) (*models.HealthGateConfig, error)
+	UpdateHealthGateConfig(conf *models.HealthGateConfig) (*models.HealthGateConfig, error)
+}
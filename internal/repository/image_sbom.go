@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// ImageSBOMRepository represents the set of queries on the dependency inventories of images
+type ImageSBOMRepository interface {
+	CreateImageSBOM(sbom *models.ImageSBOM) (*models.ImageSBOM, error)
+	ReadImageSBOM(projectID, id uint) (*models.ImageSBOM, error)
+	ReadImageSBOMByImage(projectID uint, imageRepo, tag string) (*models.ImageSBOM, error)
+	ListImageSBOMsByPackageName(projectID uint, name string) ([]*models.ImageSBOM, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// ImageSigningAuthorityRepository represents the set of queries on the image signing
+// authorities of projects
+type ImageSigningAuthorityRepository interface {
+	CreateImageSigningAuthority(authority *models.ImageSigningAuthority) (*models.ImageSigningAuthority, error)
+	ReadImageSigningAuthority(projectID, id uint) (*models.ImageSigningAuthority, error)
+	ListImageSigningAuthoritiesByProjectID(projectID uint) ([]*models.ImageSigningAuthority, error)
+	DeleteImageSigningAuthority(authority *models.ImageSigningAuthority) error
+}
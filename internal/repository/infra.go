@@ -10,4 +10,6 @@ type InfraRepository interface {
 	ReadInfra(projectID, infraID uint) (*models.Infra, error)
 	ListInfrasByProjectID(projectID uint) ([]*models.Infra, error)
 	UpdateInfra(repo *models.Infra) (*models.Infra, error)
+	ListInfraResources(infraID uint) ([]*models.InfraResource, error)
+	UpdateInfraResources(infraID uint, resources []*models.InfraResource) error
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// InstanceAdminRepository represents the set of instance-wide queries of instance
+// admins, which are not scoped to a project or user
+type InstanceAdminRepository interface {
+	ListProjects(opts *types.AdminListRequest) ([]*models.Project, int64, error)
+	ListUsers(opts *types.AdminListRequest) ([]*models.User, int64, error)
+	ReadInstanceQuota() (*models.InstanceQuota, error)
+	UpdateInstanceQuota(quota *models.InstanceQuota) (*models.InstanceQuota, error)
+	ListWhitelistedUsers() ([]*models.WhitelistedUser, error)
+	CreateWhitelistedUser(whitelisted *models.WhitelistedUser) (*models.WhitelistedUser, error)
+	DeleteWhitelistedUser(userID uint) error
+	CreateAuditLog(log *models.AdminAuditLog) (*models.AdminAuditLog, error)
+	ListAuditLogs(opts *types.AdminListRequest) ([]*models.AdminAuditLog, int64, error)
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	ints "github.com/porter-dev/porter/internal/models/integrations"
 )
 
@@ -49,6 +51,7 @@ type GithubAppOAuthIntegrationRepository interface {
 type SlackIntegrationRepository interface {
 	CreateSlackIntegration(slackInt *ints.SlackIntegration) (*ints.SlackIntegration, error)
 	ListSlackIntegrationsByProjectID(projectID uint) ([]*ints.SlackIntegration, error)
+	ListSlackIntegrationsByTeamID(teamID string) ([]*ints.SlackIntegration, error)
 	DeleteSlackIntegration(integrationID uint) error
 }
 
@@ -76,4 +79,5 @@ type GithubAppInstallationRepository interface {
 	ReadGithubAppInstallationByAccountID(accountID int64) (*ints.GithubAppInstallation, error)
 	ReadGithubAppInstallationByAccountIDs(accountIDs []int64) ([]*ints.GithubAppInstallation, error)
 	DeleteGithubAppInstallationByAccountID(accountID int64) error
+	UpdateGithubAppInstallationToken(installationID int64, token []byte, expiry time.Time) error
 }
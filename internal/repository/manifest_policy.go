@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// ManifestPolicyRepository represents the set of queries on the manifest policies of
+// projects
+type ManifestPolicyRepository interface {
+	CreateManifestPolicy(policy *models.ManifestPolicy) (*models.ManifestPolicy, error)
+	ReadManifestPolicy(projectID, id uint) (*models.ManifestPolicy, error)
+	ListManifestPoliciesByProjectID(projectID uint) ([]*models.ManifestPolicy, error)
+	UpdateManifestPolicy(policy *models.ManifestPolicy) (*models.ManifestPolicy, error)
+	DeleteManifestPolicy(policy *models.ManifestPolicy) error
+}
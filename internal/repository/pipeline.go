@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// PipelineRepository represents the set of queries on the Pipeline model and its
+// promotions
+type PipelineRepository interface {
+	CreatePipeline(pipeline *models.Pipeline) (*models.Pipeline, error)
+	ReadPipeline(projectID, pipelineID uint) (*models.Pipeline, error)
+	ListPipelines(projectID uint) ([]*models.Pipeline, error)
+	DeletePipeline(pipeline *models.Pipeline) (*models.Pipeline, error)
+	CreatePromotion(promotion *models.PipelinePromotion) (*models.PipelinePromotion, error)
+	ReadPromotion(pipelineID, promotionID uint) (*models.PipelinePromotion, error)
+	ListPromotions(pipelineID uint) ([]*models.PipelinePromotion, error)
+	UpdatePromotion(promotion *models.PipelinePromotion) (*models.PipelinePromotion, error)
+	ClaimPromotion(promotion *models.PipelinePromotion) (bool, error)
+	CreatePromotionApproval(approval *models.PipelinePromotionApproval) (*models.PipelinePromotionApproval, error)
+}
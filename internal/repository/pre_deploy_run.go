@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// PreDeployRunRepository represents the set of queries on runs of pre-deploy commands
+type PreDeployRunRepository interface {
+	CreatePreDeployRun(run *models.PreDeployRun) (*models.PreDeployRun, error)
+	ReadLatestPreDeployRun(clusterID uint, namespace, name string) (*models.PreDeployRun, error)
+	UpdatePreDeployRun(run *models.PreDeployRun) (*models.PreDeployRun, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// ProvisionedResourceRepository represents the set of queries that are shared by the
+// resources that are provisioned through an infra, such as buckets and queues
+type ProvisionedResourceRepository interface {
+	ReadProvisionedResourceByInfraID(projectID, infraID uint) (models.ProvisionedResourceModel, error)
+	UpdateProvisionedResource(resource models.ProvisionedResourceModel) (models.ProvisionedResourceModel, error)
+	DeleteProvisionedResource(resource models.ProvisionedResourceModel) error
+}
+
+// GetProvisionedResourceRepository returns the repository of the resources that are
+// provisioned by infras of a kind, or false if the infras of the kind do not provision
+// such a resource
+func GetProvisionedResourceRepository(repo Repository, kind types.InfraKind) (ProvisionedResourceRepository, bool) {
+	switch kind {
+	case types.InfraS3, types.InfraGCS:
+		return repo.Bucket(), true
+	case types.InfraSQS, types.InfraPubSub:
+		return repo.Queue(), true
+	}
+
+	return nil, false
+}
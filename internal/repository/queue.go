@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// QueueRepository represents the set of queries on the message queues
+// provisioned by Porter
+type QueueRepository interface {
+	ProvisionedResourceRepository
+
+	CreateQueue(queue *models.Queue) (*models.Queue, error)
+	ReadQueue(projectID, clusterID, queueID uint) (*models.Queue, error)
+	ListQueues(projectID, clusterID uint) ([]*models.Queue, error)
+}
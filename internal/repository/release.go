@@ -13,6 +13,9 @@ type ReleaseRepository interface {
 	ReadRelease(clusterID uint, name, namespace string) (*models.Release, error)
 	ReadReleaseByWebhookToken(token string) (*models.Release, error)
 	ListReleasesByImageRepoURI(clusterID uint, imageRepoURI string) ([]*models.Release, error)
+	ListReleasesByImageSBOMIDs(projectID uint, imageSBOMIDs []uint) ([]*models.Release, error)
+	ListDeletionProtectedReleases(clusterID uint) ([]*models.Release, error)
+	ListReleasesByNamespace(clusterID uint, namespace string) ([]*models.Release, error)
 	UpdateRelease(release *models.Release) (*models.Release, error)
 	DeleteRelease(release *models.Release) (*models.Release, error)
 }
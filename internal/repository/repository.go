@@ -5,6 +5,11 @@ type Repository interface {
 	Project() ProjectRepository
 	Release() ReleaseRepository
 	Environment() EnvironmentRepository
+	Pipeline() PipelineRepository
+	ChangeRequest() ChangeRequestRepository
+	DefaultValues() DefaultValuesRepository
+	TemplateRepo() TemplateRepoRepository
+	Addon() AddonRepository
 	Session() SessionRepository
 	GitRepo() GitRepoRepository
 	Cluster() ClusterRepository
@@ -28,11 +33,31 @@ type Repository interface {
 	SlackIntegration() SlackIntegrationRepository
 	NotificationConfig() NotificationConfigRepository
 	JobNotificationConfig() JobNotificationConfigRepository
+	HealthGateConfig() HealthGateConfigRepository
 	BuildEvent() BuildEventRepository
+	BuildLog() BuildLogRepository
 	KubeEvent() KubeEventRepository
 	ProjectUsage() ProjectUsageRepository
 	Onboarding() ProjectOnboardingRepository
 	CredentialsExchangeToken() CredentialsExchangeTokenRepository
 	BuildConfig() BuildConfigRepository
 	Allowlist() AllowlistRepository
+	InstanceAdmin() InstanceAdminRepository
+	ServerSetting() ServerSettingRepository
+	APIErrorLog() APIErrorLogRepository
+	SensitiveValues() SensitiveValuesRepository
+	GitOpsConfig() GitOpsConfigRepository
+	AllowedChart() AllowedChartRepository
+	BuildpackDetection() BuildpackDetectionRepository
+	Backup() BackupRepository
+	Bucket() BucketRepository
+	Queue() QueueRepository
+	StaleRelease() StaleReleaseRepository
+	DeployFreeze() DeployFreezeRepository
+	ScheduledDeploy() ScheduledDeployRepository
+	PreDeployRun() PreDeployRunRepository
+	CustomChart() CustomChartRepository
+	ManifestPolicy() ManifestPolicyRepository
+	ImageSigningAuthority() ImageSigningAuthorityRepository
+	ImageSBOM() ImageSBOMRepository
 }
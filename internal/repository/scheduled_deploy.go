@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// ScheduledDeployRepository represents the set of queries on scheduled deploys
+type ScheduledDeployRepository interface {
+	CreateScheduledDeploy(sd *models.ScheduledDeploy) (*models.ScheduledDeploy, error)
+	ReadScheduledDeploy(clusterID, id uint) (*models.ScheduledDeploy, error)
+	ListScheduledDeploys(clusterID uint, namespace, name string, statuses ...types.ScheduledDeployStatus) ([]*models.ScheduledDeploy, error)
+	ListDueScheduledDeploys(now time.Time) ([]*models.ScheduledDeploy, error)
+	ClaimScheduledDeploy(sd *models.ScheduledDeploy) (bool, error)
+	ListStaleScheduledDeploys(claimedBefore time.Time) ([]*models.ScheduledDeploy, error)
+	UpdateScheduledDeploy(sd *models.ScheduledDeploy) (*models.ScheduledDeploy, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// SensitiveValuesRepository represents the set of queries on the sensitive values of
+// releases
+type SensitiveValuesRepository interface {
+	ReadSensitiveValues(clusterID uint, namespace, name string) (*models.SensitiveValues, error)
+	ReadSensitiveValuesRevision(clusterID uint, namespace, name string, revision int) (*models.SensitiveValues, error)
+	UpdateSensitiveValues(sensitiveValues *models.SensitiveValues) (*models.SensitiveValues, error)
+	DeleteSensitiveValues(clusterID uint, namespace, name string) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+)
+
+// ServerSettingRepository represents the set of queries on the ServerSetting model
+type ServerSettingRepository interface {
+	ListServerSettings() ([]*models.ServerSetting, error)
+	UpsertServerSetting(setting *models.ServerSetting) (*models.ServerSetting, error)
+	DeleteServerSetting(key types.ServerSettingKey) error
+}
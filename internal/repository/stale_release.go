@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// StaleReleaseRepository represents the set of queries on the StaleRelease model
+type StaleReleaseRepository interface {
+	CreateStaleRelease(staleRelease *models.StaleRelease) (*models.StaleRelease, error)
+	ListStaleReleasesByClusterID(clusterID uint) ([]*models.StaleRelease, error)
+	ListStaleReleasesByProjectID(projectID uint) ([]*models.StaleRelease, error)
+	UpdateStaleRelease(staleRelease *models.StaleRelease) (*models.StaleRelease, error)
+	DeleteStaleRelease(staleRelease *models.StaleRelease) (*models.StaleRelease, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/porter-dev/porter/internal/models"
+
+// TemplateRepoRepository represents the set of queries on the TemplateRepo model
+type TemplateRepoRepository interface {
+	CreateTemplateRepo(repo *models.TemplateRepo) (*models.TemplateRepo, error)
+	ReadTemplateRepo(projectID, id uint) (*models.TemplateRepo, error)
+	ListTemplateReposByProjectID(projectID uint) ([]*models.TemplateRepo, error)
+	DeleteTemplateRepo(repo *models.TemplateRepo) (*models.TemplateRepo, error)
+}
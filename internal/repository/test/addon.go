@@ -0,0 +1,115 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type AddonRepository struct {
+	canQuery bool
+	addons   []*models.Addon
+}
+
+func NewAddonRepository(canQuery bool) repository.AddonRepository {
+	return &AddonRepository{canQuery, []*models.Addon{}}
+}
+
+func (repo *AddonRepository) CreateAddon(addon *models.Addon) (*models.Addon, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.addons = append(repo.addons, addon)
+	addon.ID = uint(len(repo.addons))
+
+	return addon, nil
+}
+
+func (repo *AddonRepository) ReadAddon(clusterID, id uint) (*models.Addon, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	if int(id-1) >= len(repo.addons) || repo.addons[id-1] == nil || repo.addons[id-1].ClusterID != clusterID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.addons[id-1], nil
+}
+
+func (repo *AddonRepository) ReadAddonByName(clusterID uint, namespace, name string) (*models.Addon, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, addon := range repo.addons {
+		if addon != nil && addon.ClusterID == clusterID && addon.Namespace == namespace && addon.Name == name {
+			return addon, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *AddonRepository) ListAddonsByClusterID(clusterID uint) ([]*models.Addon, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Addon, 0)
+
+	for _, addon := range repo.addons {
+		if addon != nil && addon.ClusterID == clusterID {
+			res = append(res, addon)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *AddonRepository) ListAddons() ([]*models.Addon, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Addon, 0)
+
+	for _, addon := range repo.addons {
+		if addon != nil {
+			res = append(res, addon)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *AddonRepository) UpdateAddon(addon *models.Addon) (*models.Addon, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(addon.ID-1) >= len(repo.addons) || repo.addons[addon.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.addons[addon.ID-1] = addon
+
+	return addon, nil
+}
+
+func (repo *AddonRepository) DeleteAddon(addon *models.Addon) (*models.Addon, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(addon.ID-1) >= len(repo.addons) || repo.addons[addon.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.addons[addon.ID-1] = nil
+
+	return addon, nil
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type AllowedChartRepository struct {
+	canQuery bool
+	allowed  []*models.AllowedChart
+}
+
+func NewAllowedChartRepository(canQuery bool) repository.AllowedChartRepository {
+	return &AllowedChartRepository{canQuery, []*models.AllowedChart{}}
+}
+
+func (repo *AllowedChartRepository) CreateAllowedChart(allowed *models.AllowedChart) (*models.AllowedChart, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.allowed = append(repo.allowed, allowed)
+	allowed.ID = uint(len(repo.allowed))
+
+	return allowed, nil
+}
+
+func (repo *AllowedChartRepository) ReadAllowedChart(projectID, id uint) (*models.AllowedChart, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	if int(id-1) >= len(repo.allowed) || repo.allowed[id-1] == nil || repo.allowed[id-1].ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.allowed[id-1], nil
+}
+
+func (repo *AllowedChartRepository) ListAllowedChartsByProjectID(projectID uint) ([]*models.AllowedChart, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.AllowedChart, 0)
+
+	for _, allowed := range repo.allowed {
+		if allowed != nil && allowed.ProjectID == projectID {
+			res = append(res, allowed)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *AllowedChartRepository) DeleteAllowedChart(allowed *models.AllowedChart) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(allowed.ID-1) >= len(repo.allowed) || repo.allowed[allowed.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.allowed[allowed.ID-1] = nil
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// APIErrorLogRepository is guarded by a mutex, since API errors are logged outside
+// of the request
+type APIErrorLogRepository struct {
+	canQuery bool
+
+	mu   sync.Mutex
+	logs []*models.APIErrorLog
+}
+
+func NewAPIErrorLogRepository(canQuery bool) repository.APIErrorLogRepository {
+	return &APIErrorLogRepository{canQuery: canQuery, logs: []*models.APIErrorLog{}}
+}
+
+func (repo *APIErrorLogRepository) CreateAPIErrorLog(log *models.APIErrorLog) (*models.APIErrorLog, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.logs = append(repo.logs, log)
+	log.ID = uint(len(repo.logs))
+
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+
+	return log, nil
+}
+
+func (repo *APIErrorLogRepository) ListAPIErrorLogsByProjectID(
+	projectID uint,
+	opts *types.ListAPIErrorLogsRequest,
+) ([]*models.APIErrorLog, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	res := make([]*models.APIErrorLog, 0)
+
+	for i := len(repo.logs) - 1; i >= 0; i-- {
+		log := repo.logs[i]
+
+		if log == nil || log.ProjectID != projectID || (opts.Category != "" && log.Category != opts.Category) {
+			continue
+		}
+
+		res = append(res, log)
+	}
+
+	return res, nil
+}
+
+func (repo *APIErrorLogRepository) DeleteAPIErrorLogsBefore(projectID uint, before time.Time) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, log := range repo.logs {
+		if log != nil && log.ProjectID == projectID && log.CreatedAt.Before(before) {
+			repo.logs[i] = nil
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/porter-dev/porter/internal/repository"
 	"gorm.io/gorm"
@@ -465,7 +466,17 @@ func (repo *GithubAppInstallationRepository) ReadGithubAppInstallation(projectID
 }
 
 func (repo *GithubAppInstallationRepository) ReadGithubAppInstallationByInstallationID(gaID uint) (*ints.GithubAppInstallation, error) {
-	panic("unimplemented")
+	if !repo.canQuery {
+		return nil, errors.New("cannot write database")
+	}
+
+	for _, installation := range repo.githubAppInstallations {
+		if installation != nil && installation.InstallationID == int64(gaID) {
+			return installation, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
 }
 
 func (repo *GithubAppInstallationRepository) ReadGithubAppInstallationByAccountID(accountID int64) (*ints.GithubAppInstallation, error) {
@@ -517,6 +528,25 @@ func (repo *GithubAppInstallationRepository) DeleteGithubAppInstallationByAccoun
 	return nil
 }
 
+func (repo *GithubAppInstallationRepository) UpdateGithubAppInstallationToken(
+	installationID int64,
+	token []byte,
+	expiry time.Time,
+) error {
+	if !repo.canQuery {
+		return errors.New("cannot write database")
+	}
+
+	for _, installation := range repo.githubAppInstallations {
+		if installation != nil && installation.InstallationID == installationID {
+			installation.AccessToken = token
+			installation.AccessTokenExpiry = &expiry
+		}
+	}
+
+	return nil
+}
+
 type GithubAppOAuthIntegrationRepository struct {
 	canQuery                   bool
 	githubAppOauthIntegrations []*ints.GithubAppOAuthIntegration
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type BackupRepository struct {
+	canQuery bool
+	backups  []*models.Backup
+}
+
+func NewBackupRepository(canQuery bool) repository.BackupRepository {
+	return &BackupRepository{canQuery, []*models.Backup{}}
+}
+
+func (repo *BackupRepository) CreateBackup(backup *models.Backup) (*models.Backup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.backups = append(repo.backups, backup)
+	backup.ID = uint(len(repo.backups))
+
+	return backup, nil
+}
+
+func (repo *BackupRepository) ReadBackupByName(clusterID uint, name string) (*models.Backup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, backup := range repo.backups {
+		if backup != nil && backup.ClusterID == clusterID && backup.Name == name {
+			return backup, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *BackupRepository) ListBackupsByClusterID(clusterID uint) ([]*models.Backup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Backup, 0)
+
+	for i := len(repo.backups) - 1; i >= 0; i-- {
+		if repo.backups[i] != nil && repo.backups[i].ClusterID == clusterID {
+			res = append(res, repo.backups[i])
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *BackupRepository) UpdateBackup(backup *models.Backup) (*models.Backup, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(backup.ID-1) >= len(repo.backups) || repo.backups[backup.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.backups[int(backup.ID-1)] = backup
+
+	return backup, nil
+}
+
+func (repo *BackupRepository) DeleteBackup(backup *models.Backup) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(backup.ID-1) >= len(repo.backups) || repo.backups[backup.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.backups[int(backup.ID-1)] = nil
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package test
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+type BucketRepository struct {
+	*provisionedResourceRepository
+}
+
+func NewBucketRepository(canQuery bool) repository.BucketRepository {
+	return &BucketRepository{&provisionedResourceRepository{canQuery: canQuery}}
+}
+
+func (repo *BucketRepository) CreateBucket(bucket *models.Bucket) (*models.Bucket, error) {
+	if err := repo.create(bucket); err != nil {
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+func (repo *BucketRepository) ReadBucket(projectID, clusterID, bucketID uint) (*models.Bucket, error) {
+	bucket, err := repo.read(func(res *models.ProvisionedResource) bool {
+		return res.ProjectID == projectID && res.ClusterID == clusterID && res.ID == bucketID
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bucket.(*models.Bucket), nil
+}
+
+func (repo *BucketRepository) ListBuckets(projectID, clusterID uint) ([]*models.Bucket, error) {
+	resources, err := repo.list(func(res *models.ProvisionedResource) bool {
+		return res.ProjectID == projectID && res.ClusterID == clusterID
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*models.Bucket, 0)
+
+	for _, bucket := range resources {
+		res = append(res, bucket.(*models.Bucket))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type BuildLogRepository struct {
+	canQuery  bool
+	buildLogs []*models.BuildLog
+}
+
+func NewBuildLogRepository(canQuery bool) repository.BuildLogRepository {
+	return &BuildLogRepository{canQuery, []*models.BuildLog{}}
+}
+
+func (repo *BuildLogRepository) CreateBuildLog(buildLog *models.BuildLog) (*models.BuildLog, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.buildLogs = append(repo.buildLogs, buildLog)
+	buildLog.ID = uint(len(repo.buildLogs))
+
+	return buildLog, nil
+}
+
+func (repo *BuildLogRepository) ReadBuildLog(releaseID uint, commitSHA string) (*models.BuildLog, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for i := len(repo.buildLogs) - 1; i >= 0; i-- {
+		if buildLog := repo.buildLogs[i]; buildLog.ReleaseID == releaseID && buildLog.CommitSHA == commitSHA {
+			return buildLog, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *BuildLogRepository) ListBuildLogsByReleaseID(releaseID uint) ([]*models.BuildLog, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.BuildLog, 0)
+
+	for i := len(repo.buildLogs) - 1; i >= 0; i-- {
+		if buildLog := repo.buildLogs[i]; buildLog.ReleaseID == releaseID {
+			res = append(res, buildLog)
+		}
+	}
+
+	return res, nil
+}
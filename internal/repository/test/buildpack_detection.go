@@ -0,0 +1,69 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type BuildpackDetectionRepository struct {
+	canQuery   bool
+	detections []*models.BuildpackDetection
+}
+
+func NewBuildpackDetectionRepository(canQuery bool) repository.BuildpackDetectionRepository {
+	return &BuildpackDetectionRepository{canQuery, []*models.BuildpackDetection{}}
+}
+
+func (repo *BuildpackDetectionRepository) CreateBuildpackDetection(
+	detection *models.BuildpackDetection,
+) (*models.BuildpackDetection, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.detections = append(repo.detections, detection)
+	detection.ID = uint(len(repo.detections))
+
+	return detection, nil
+}
+
+func (repo *BuildpackDetectionRepository) ReadBuildpackDetectionByCommit(
+	projectID, gitInstallationID uint,
+	owner, name, folderPath, commitSHA string,
+) (*models.BuildpackDetection, error) {
+	return repo.readLatest(func(d *models.BuildpackDetection) bool {
+		return d.ProjectID == projectID && d.GitInstallationID == gitInstallationID &&
+			d.GitRepoOwner == owner && d.GitRepoName == name &&
+			d.FolderPath == folderPath && d.CommitSHA == commitSHA
+	})
+}
+
+func (repo *BuildpackDetectionRepository) ReadLatestBuildpackDetection(
+	projectID, gitInstallationID uint,
+	owner, name, branch, folderPath string,
+) (*models.BuildpackDetection, error) {
+	return repo.readLatest(func(d *models.BuildpackDetection) bool {
+		return d.ProjectID == projectID && d.GitInstallationID == gitInstallationID &&
+			d.GitRepoOwner == owner && d.GitRepoName == name &&
+			d.GitBranch == branch && d.FolderPath == folderPath
+	})
+}
+
+func (repo *BuildpackDetectionRepository) readLatest(
+	matches func(d *models.BuildpackDetection) bool,
+) (*models.BuildpackDetection, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for i := len(repo.detections) - 1; i >= 0; i-- {
+		if matches(repo.detections[i]) {
+			return repo.detections[i], nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ChangeRequestRepository implements repository.ChangeRequestRepository
+type ChangeRequestRepository struct {
+	canQuery            bool
+	changeRequests      []*models.ReleaseChangeRequest
+	protectedNamespaces []*models.ProtectedNamespace
+}
+
+// NewChangeRequestRepository will return errors if canQuery is false
+func NewChangeRequestRepository(canQuery bool) repository.ChangeRequestRepository {
+	return &ChangeRequestRepository{
+		canQuery,
+		[]*models.ReleaseChangeRequest{},
+		[]*models.ProtectedNamespace{},
+	}
+}
+
+func (repo *ChangeRequestRepository) CreateChangeRequest(cr *models.ReleaseChangeRequest) (*models.ReleaseChangeRequest, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.changeRequests = append(repo.changeRequests, cr)
+	cr.ID = uint(len(repo.changeRequests))
+
+	return cr, nil
+}
+
+func (repo *ChangeRequestRepository) ReadChangeRequest(clusterID, id uint) (*models.ReleaseChangeRequest, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	if int(id-1) >= len(repo.changeRequests) || id == 0 || repo.changeRequests[id-1].ClusterID != clusterID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.changeRequests[id-1], nil
+}
+
+func (repo *ChangeRequestRepository) ListChangeRequests(
+	clusterID uint,
+	namespace, name string,
+	statuses ...types.ChangeRequestStatus,
+) ([]*models.ReleaseChangeRequest, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.ReleaseChangeRequest, 0)
+
+	for _, cr := range repo.changeRequests {
+		if cr.ClusterID != clusterID || cr.Namespace != namespace || cr.Name != name {
+			continue
+		}
+
+		matches := len(statuses) == 0
+
+		for _, status := range statuses {
+			if cr.Status == status {
+				matches = true
+			}
+		}
+
+		if matches {
+			res = append(res, cr)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *ChangeRequestRepository) UpdateChangeRequest(cr *models.ReleaseChangeRequest) (*models.ReleaseChangeRequest, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(cr.ID-1) >= len(repo.changeRequests) || cr.ID == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.changeRequests[cr.ID-1] = cr
+
+	return cr, nil
+}
+
+func (repo *ChangeRequestRepository) IsNamespaceProtected(clusterID uint, namespace string) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("Cannot read from database")
+	}
+
+	for _, ns := range repo.protectedNamespaces {
+		if ns.ClusterID == clusterID && ns.Namespace == namespace {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (repo *ChangeRequestRepository) SetNamespaceProtected(clusterID uint, namespace string, protected bool) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	res := make([]*models.ProtectedNamespace, 0)
+
+	for _, ns := range repo.protectedNamespaces {
+		if ns.ClusterID != clusterID || ns.Namespace != namespace {
+			res = append(res, ns)
+		}
+	}
+
+	if protected {
+		res = append(res, &models.ProtectedNamespace{ClusterID: clusterID, Namespace: namespace})
+	}
+
+	repo.protectedNamespaces = res
+
+	return nil
+}
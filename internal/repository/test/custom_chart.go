@@ -0,0 +1,33 @@
+package test
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+type CustomChartRepository struct {
+}
+
+func NewCustomChartRepository() repository.CustomChartRepository {
+	return &CustomChartRepository{}
+}
+
+func (repo *CustomChartRepository) CreateCustomChart(customChart *models.CustomChart) (*models.CustomChart, error) {
+	panic("unimplemented")
+}
+
+func (repo *CustomChartRepository) ReadCustomChart(projectID, id uint) (*models.CustomChart, error) {
+	panic("unimplemented")
+}
+
+func (repo *CustomChartRepository) ReadCustomChartByVersion(projectID uint, name, version string) (*models.CustomChart, error) {
+	panic("unimplemented")
+}
+
+func (repo *CustomChartRepository) ListCustomChartsByProjectID(projectID uint) ([]*models.CustomChart, error) {
+	panic("unimplemented")
+}
+
+func (repo *CustomChartRepository) DeleteCustomChart(customChart *models.CustomChart) (*models.CustomChart, error) {
+	panic("unimplemented")
+}
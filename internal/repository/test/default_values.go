@@ -0,0 +1,53 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type DefaultValuesRepository struct {
+	canQuery      bool
+	defaultValues []*models.DefaultValues
+}
+
+func NewDefaultValuesRepository(canQuery bool) repository.DefaultValuesRepository {
+	return &DefaultValuesRepository{canQuery, []*models.DefaultValues{}}
+}
+
+func (repo *DefaultValuesRepository) ReadDefaultValues(projectID, clusterID uint) (*models.DefaultValues, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, defaultValues := range repo.defaultValues {
+		if defaultValues.ProjectID == projectID && defaultValues.ClusterID == clusterID {
+			return defaultValues, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *DefaultValuesRepository) UpdateDefaultValues(defaultValues *models.DefaultValues) (*models.DefaultValues, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if defaultValues.ID == 0 {
+		repo.defaultValues = append(repo.defaultValues, defaultValues)
+		defaultValues.ID = uint(len(repo.defaultValues))
+
+		return defaultValues, nil
+	}
+
+	if int(defaultValues.ID-1) >= len(repo.defaultValues) || repo.defaultValues[defaultValues.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.defaultValues[defaultValues.ID-1] = defaultValues
+
+	return defaultValues, nil
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type DeployFreezeRepository struct {
+	canQuery  bool
+	freezes   []*models.DeployFreeze
+	overrides []*models.DeployFreezeOverride
+}
+
+func NewDeployFreezeRepository(canQuery bool) repository.DeployFreezeRepository {
+	return &DeployFreezeRepository{canQuery, []*models.DeployFreeze{}, []*models.DeployFreezeOverride{}}
+}
+
+func (repo *DeployFreezeRepository) CreateDeployFreeze(freeze *models.DeployFreeze) (*models.DeployFreeze, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.freezes = append(repo.freezes, freeze)
+	freeze.ID = uint(len(repo.freezes))
+
+	return freeze, nil
+}
+
+func (repo *DeployFreezeRepository) ReadDeployFreeze(projectID, freezeID uint) (*models.DeployFreeze, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	if int(freezeID-1) >= len(repo.freezes) || repo.freezes[freezeID-1] == nil || repo.freezes[freezeID-1].ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.freezes[freezeID-1], nil
+}
+
+func (repo *DeployFreezeRepository) ListDeployFreezesByProjectID(projectID uint) ([]*models.DeployFreeze, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.DeployFreeze, 0)
+
+	for _, freeze := range repo.freezes {
+		if freeze != nil && freeze.ProjectID == projectID {
+			res = append(res, freeze)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *DeployFreezeRepository) DeleteDeployFreeze(freeze *models.DeployFreeze) (*models.DeployFreeze, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(freeze.ID-1) >= len(repo.freezes) || repo.freezes[freeze.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.freezes[freeze.ID-1] = nil
+
+	return freeze, nil
+}
+
+func (repo *DeployFreezeRepository) CreateDeployFreezeOverride(
+	override *models.DeployFreezeOverride,
+) (*models.DeployFreezeOverride, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.overrides = append(repo.overrides, override)
+	override.ID = uint(len(repo.overrides))
+
+	return override, nil
+}
+
+func (repo *DeployFreezeRepository) ListDeployFreezeOverridesByProjectID(projectID uint) ([]*models.DeployFreezeOverride, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.DeployFreezeOverride, 0)
+
+	// overrides are listed from most recent
+	for i := len(repo.overrides) - 1; i >= 0; i-- {
+		if repo.overrides[i].ProjectID == projectID {
+			res = append(res, repo.overrides[i])
+		}
+	}
+
+	return res, nil
+}
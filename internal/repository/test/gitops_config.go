@@ -0,0 +1,105 @@
+package test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type GitOpsConfigRepository struct {
+	canQuery bool
+	confs    []*models.GitOpsConfig
+}
+
+func NewGitOpsConfigRepository(canQuery bool) repository.GitOpsConfigRepository {
+	return &GitOpsConfigRepository{canQuery, []*models.GitOpsConfig{}}
+}
+
+func (repo *GitOpsConfigRepository) ReadGitOpsConfig(projectID uint) (*models.GitOpsConfig, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for _, conf := range repo.confs {
+		if conf != nil && conf.ProjectID == projectID {
+			return conf, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *GitOpsConfigRepository) ListGitOpsConfigs() ([]*models.GitOpsConfig, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.GitOpsConfig, 0)
+
+	for _, conf := range repo.confs {
+		if conf != nil {
+			res = append(res, conf)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *GitOpsConfigRepository) UpdateGitOpsConfig(conf *models.GitOpsConfig) (*models.GitOpsConfig, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if conf.ID == 0 {
+		repo.confs = append(repo.confs, conf)
+		conf.ID = uint(len(repo.confs))
+
+		return conf, nil
+	}
+
+	if int(conf.ID-1) >= len(repo.confs) || repo.confs[conf.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.confs[conf.ID-1] = conf
+
+	return conf, nil
+}
+
+func (repo *GitOpsConfigRepository) ClaimGitOpsReconcile(conf *models.GitOpsConfig, expiresAt time.Time) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("Cannot write database")
+	}
+
+	if int(conf.ID-1) >= len(repo.confs) || repo.confs[conf.ID-1] == nil {
+		return false, gorm.ErrRecordNotFound
+	}
+
+	stored := repo.confs[conf.ID-1]
+
+	if stored.ReconcileLeaseExpiresAt != nil && stored.ReconcileLeaseExpiresAt.After(time.Now()) {
+		return false, nil
+	}
+
+	stored.ReconcileLeaseExpiresAt = &expiresAt
+	conf.ReconcileLeaseExpiresAt = &expiresAt
+
+	return true, nil
+}
+
+func (repo *GitOpsConfigRepository) DeleteGitOpsConfig(conf *models.GitOpsConfig) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(conf.ID-1) >= len(repo.confs) || repo.confs[conf.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.confs[conf.ID-1] = nil
+
+	return nil
+}
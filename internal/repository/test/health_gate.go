@@ -0,0 +1,60 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+// HealthGateConfigRepository implements repository.HealthGateConfigRepository
+type HealthGateConfigRepository struct {
+	canQuery bool
+	configs  []*models.HealthGateConfig
+}
+
+// NewHealthGateConfigRepository will return errors if canQuery is false
+func NewHealthGateConfigRepository(canQuery bool) repository.HealthGateConfigRepository {
+	return &HealthGateConfigRepository{
+		canQuery,
+		[]*models.HealthGateConfig{},
+	}
+}
+
+func (repo *HealthGateConfigRepository) CreateHealthGateConfig(conf *models.HealthGateConfig) (*models.HealthGateConfig, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.configs = append(repo.configs, conf)
+	conf.ID = uint(len(repo.configs))
+
+	return conf, nil
+}
+
+func (repo *HealthGateConfigRepository) ReadHealthGateConfig(id uint) (*models.HealthGateConfig, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	if int(id-1) >= len(repo.configs) || repo.configs[id-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.configs[id-1], nil
+}
+
+func (repo *HealthGateConfigRepository) UpdateHealthGateConfig(conf *models.HealthGateConfig) (*models.HealthGateConfig, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(conf.ID-1) >= len(repo.configs) || repo.configs[conf.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.configs[conf.ID-1] = conf
+
+	return conf, nil
+}
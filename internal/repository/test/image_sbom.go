@@ -0,0 +1,87 @@
+package test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ImageSBOMRepository struct {
+	canQuery bool
+	sboms    []*models.ImageSBOM
+}
+
+func NewImageSBOMRepository(canQuery bool) repository.ImageSBOMRepository {
+	return &ImageSBOMRepository{canQuery, []*models.ImageSBOM{}}
+}
+
+func (repo *ImageSBOMRepository) CreateImageSBOM(sbom *models.ImageSBOM) (*models.ImageSBOM, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.sboms = append(repo.sboms, sbom)
+	sbom.ID = uint(len(repo.sboms))
+
+	return sbom, nil
+}
+
+func (repo *ImageSBOMRepository) ReadImageSBOM(projectID, id uint) (*models.ImageSBOM, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	if int(id-1) >= len(repo.sboms) || repo.sboms[id-1] == nil || repo.sboms[id-1].ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.sboms[id-1], nil
+}
+
+func (repo *ImageSBOMRepository) ReadImageSBOMByImage(projectID uint, imageRepo, tag string) (*models.ImageSBOM, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for i := len(repo.sboms) - 1; i >= 0; i-- {
+		if sbom := repo.sboms[i]; sbom != nil && sbom.ProjectID == projectID && sbom.ImageRepo == imageRepo && sbom.Tag == tag {
+			return sbom, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *ImageSBOMRepository) ListImageSBOMsByPackageName(projectID uint, name string) ([]*models.ImageSBOM, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.ImageSBOM, 0)
+
+	for _, sbom := range repo.sboms {
+		if sbom == nil || sbom.ProjectID != projectID {
+			continue
+		}
+
+		pkgs := make([]models.SBOMPackage, 0)
+
+		for _, pkg := range sbom.Packages {
+			if strings.Contains(strings.ToLower(pkg.Name), strings.ToLower(name)) {
+				pkgs = append(pkgs, pkg)
+			}
+		}
+
+		if len(pkgs) > 0 {
+			match := *sbom
+			match.Packages = pkgs
+
+			res = append(res, &match)
+		}
+	}
+
+	return res, nil
+}
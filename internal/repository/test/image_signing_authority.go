@@ -0,0 +1,75 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ImageSigningAuthorityRepository struct {
+	canQuery    bool
+	authorities []*models.ImageSigningAuthority
+}
+
+func NewImageSigningAuthorityRepository(canQuery bool) repository.ImageSigningAuthorityRepository {
+	return &ImageSigningAuthorityRepository{canQuery, []*models.ImageSigningAuthority{}}
+}
+
+func (repo *ImageSigningAuthorityRepository) CreateImageSigningAuthority(
+	authority *models.ImageSigningAuthority,
+) (*models.ImageSigningAuthority, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.authorities = append(repo.authorities, authority)
+	authority.ID = uint(len(repo.authorities))
+
+	return authority, nil
+}
+
+func (repo *ImageSigningAuthorityRepository) ReadImageSigningAuthority(projectID, id uint) (*models.ImageSigningAuthority, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	if int(id-1) >= len(repo.authorities) || repo.authorities[id-1] == nil || repo.authorities[id-1].ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.authorities[id-1], nil
+}
+
+func (repo *ImageSigningAuthorityRepository) ListImageSigningAuthoritiesByProjectID(
+	projectID uint,
+) ([]*models.ImageSigningAuthority, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.ImageSigningAuthority, 0)
+
+	for _, authority := range repo.authorities {
+		if authority != nil && authority.ProjectID == projectID {
+			res = append(res, authority)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *ImageSigningAuthorityRepository) DeleteImageSigningAuthority(authority *models.ImageSigningAuthority) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(authority.ID-1) >= len(repo.authorities) || repo.authorities[authority.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.authorities[authority.ID-1] = nil
+
+	return nil
+}
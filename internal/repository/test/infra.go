@@ -10,8 +10,9 @@ import (
 
 // InfraRepository implements repository.InfraRepository
 type InfraRepository struct {
-	canQuery bool
-	infras   []*models.Infra
+	canQuery  bool
+	infras    []*models.Infra
+	resources map[uint][]*models.InfraResource
 }
 
 // NewInfraRepository will return errors if canQuery is false
@@ -19,6 +20,7 @@ func NewInfraRepository(canQuery bool) repository.InfraRepository {
 	return &InfraRepository{
 		canQuery,
 		[]*models.Infra{},
+		make(map[uint][]*models.InfraResource),
 	}
 }
 
@@ -90,3 +92,27 @@ func (repo *InfraRepository) UpdateInfra(
 
 	return ai, nil
 }
+
+// ListInfraResources lists the materialized resources of an infra
+func (repo *InfraRepository) ListInfraResources(infraID uint) ([]*models.InfraResource, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	return repo.resources[infraID], nil
+}
+
+// UpdateInfraResources replaces the materialized resources of an infra
+func (repo *InfraRepository) UpdateInfraResources(infraID uint, resources []*models.InfraResource) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	for _, resource := range resources {
+		resource.InfraID = infraID
+	}
+
+	repo.resources[infraID] = resources
+
+	return nil
+}
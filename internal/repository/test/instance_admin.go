@@ -0,0 +1,109 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// InstanceAdminRepository implements repository.InstanceAdminRepository
+type InstanceAdminRepository struct {
+	canQuery    bool
+	quota       *models.InstanceQuota
+	whitelisted []*models.WhitelistedUser
+	auditLogs   []*models.AdminAuditLog
+}
+
+// NewInstanceAdminRepository will return errors if canQuery is false
+func NewInstanceAdminRepository(canQuery bool) repository.InstanceAdminRepository {
+	return &InstanceAdminRepository{
+		canQuery,
+		&models.InstanceQuota{},
+		[]*models.WhitelistedUser{},
+		[]*models.AdminAuditLog{},
+	}
+}
+
+func (repo *InstanceAdminRepository) ListProjects(opts *types.AdminListRequest) ([]*models.Project, int64, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (repo *InstanceAdminRepository) ListUsers(opts *types.AdminListRequest) ([]*models.User, int64, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (repo *InstanceAdminRepository) ReadInstanceQuota() (*models.InstanceQuota, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	return repo.quota, nil
+}
+
+func (repo *InstanceAdminRepository) UpdateInstanceQuota(quota *models.InstanceQuota) (*models.InstanceQuota, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.quota = quota
+
+	return quota, nil
+}
+
+func (repo *InstanceAdminRepository) ListWhitelistedUsers() ([]*models.WhitelistedUser, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	return repo.whitelisted, nil
+}
+
+func (repo *InstanceAdminRepository) CreateWhitelistedUser(whitelisted *models.WhitelistedUser) (*models.WhitelistedUser, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.whitelisted = append(repo.whitelisted, whitelisted)
+	whitelisted.ID = uint(len(repo.whitelisted))
+
+	return whitelisted, nil
+}
+
+func (repo *InstanceAdminRepository) DeleteWhitelistedUser(userID uint) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	res := []*models.WhitelistedUser{}
+
+	for _, whitelisted := range repo.whitelisted {
+		if whitelisted.UserID != userID {
+			res = append(res, whitelisted)
+		}
+	}
+
+	repo.whitelisted = res
+
+	return nil
+}
+
+func (repo *InstanceAdminRepository) CreateAuditLog(log *models.AdminAuditLog) (*models.AdminAuditLog, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.auditLogs = append(repo.auditLogs, log)
+	log.ID = uint(len(repo.auditLogs))
+
+	return log, nil
+}
+
+func (repo *InstanceAdminRepository) ListAuditLogs(opts *types.AdminListRequest) ([]*models.AdminAuditLog, int64, error) {
+	if !repo.canQuery {
+		return nil, 0, errors.New("Cannot read from database")
+	}
+
+	return repo.auditLogs, int64(len(repo.auditLogs)), nil
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ManifestPolicyRepository struct {
+	canQuery bool
+	policies []*models.ManifestPolicy
+}
+
+func NewManifestPolicyRepository(canQuery bool) repository.ManifestPolicyRepository {
+	return &ManifestPolicyRepository{canQuery, []*models.ManifestPolicy{}}
+}
+
+func (repo *ManifestPolicyRepository) CreateManifestPolicy(policy *models.ManifestPolicy) (*models.ManifestPolicy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.policies = append(repo.policies, policy)
+	policy.ID = uint(len(repo.policies))
+
+	return policy, nil
+}
+
+func (repo *ManifestPolicyRepository) ReadManifestPolicy(projectID, id uint) (*models.ManifestPolicy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	if int(id-1) >= len(repo.policies) || repo.policies[id-1] == nil || repo.policies[id-1].ProjectID != projectID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.policies[id-1], nil
+}
+
+func (repo *ManifestPolicyRepository) ListManifestPoliciesByProjectID(projectID uint) ([]*models.ManifestPolicy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.ManifestPolicy, 0)
+
+	for _, policy := range repo.policies {
+		if policy != nil && policy.ProjectID == projectID {
+			res = append(res, policy)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *ManifestPolicyRepository) UpdateManifestPolicy(policy *models.ManifestPolicy) (*models.ManifestPolicy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(policy.ID-1) >= len(repo.policies) || repo.policies[policy.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.policies[policy.ID-1] = policy
+
+	return policy, nil
+}
+
+func (repo *ManifestPolicyRepository) DeleteManifestPolicy(policy *models.ManifestPolicy) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	if int(policy.ID-1) >= len(repo.policies) || repo.policies[policy.ID-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.policies[policy.ID-1] = nil
+
+	return nil
+}
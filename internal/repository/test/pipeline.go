@@ -0,0 +1,55 @@
+package test
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+type PipelineRepository struct {
+}
+
+func NewPipelineRepository() repository.PipelineRepository {
+	return &PipelineRepository{}
+}
+
+func (repo *PipelineRepository) CreatePipeline(pipeline *models.Pipeline) (*models.Pipeline, error) {
+	panic("unimplemented")
+}
+
+func (repo *PipelineRepository) ReadPipeline(projectID, pipelineID uint) (*models.Pipeline, error) {
+	panic("unimplemented")
+}
+
+func (repo *PipelineRepository) ListPipelines(projectID uint) ([]*models.Pipeline, error) {
+	panic("unimplemented")
+}
+
+func (repo *PipelineRepository) DeletePipeline(pipeline *models.Pipeline) (*models.Pipeline, error) {
+	panic("unimplemented")
+}
+
+func (repo *PipelineRepository) CreatePromotion(promotion *models.PipelinePromotion) (*models.PipelinePromotion, error) {
+	panic("unimplemented")
+}
+
+func (repo *PipelineRepository) ReadPromotion(pipelineID, promotionID uint) (*models.PipelinePromotion, error) {
+	panic("unimplemented")
+}
+
+func (repo *PipelineRepository) ListPromotions(pipelineID uint) ([]*models.PipelinePromotion, error) {
+	panic("unimplemented")
+}
+
+func (repo *PipelineRepository) UpdatePromotion(promotion *models.PipelinePromotion) (*models.PipelinePromotion, error) {
+	panic("unimplemented")
+}
+
+func (repo *PipelineRepository) ClaimPromotion(promotion *models.PipelinePromotion) (bool, error) {
+	panic("unimplemented")
+}
+
+func (repo *PipelineRepository) CreatePromotionApproval(
+	approval *models.PipelinePromotionApproval,
+) (*models.PipelinePromotionApproval, error) {
+	panic("unimplemented")
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type PreDeployRunRepository struct {
+	canQuery bool
+	runs     []*models.PreDeployRun
+}
+
+func NewPreDeployRunRepository(canQuery bool) repository.PreDeployRunRepository {
+	return &PreDeployRunRepository{canQuery, []*models.PreDeployRun{}}
+}
+
+func (repo *PreDeployRunRepository) CreatePreDeployRun(run *models.PreDeployRun) (*models.PreDeployRun, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+
+	repo.runs = append(repo.runs, run)
+	run.ID = uint(len(repo.runs))
+
+	return run, nil
+}
+
+func (repo *PreDeployRunRepository) ReadLatestPreDeployRun(
+	clusterID uint,
+	namespace, name string,
+) (*models.PreDeployRun, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	for i := len(repo.runs) - 1; i >= 0; i-- {
+		run := repo.runs[i]
+
+		if run != nil && run.ClusterID == clusterID && run.Namespace == namespace && run.Name == name {
+			return run, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (repo *PreDeployRunRepository) UpdatePreDeployRun(run *models.PreDeployRun) (*models.PreDeployRun, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(run.ID-1) >= len(repo.runs) || repo.runs[run.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.runs[run.ID-1] = run
+
+	return run, nil
+}
@@ -13,6 +13,7 @@ const (
 	CreateProjectMethod        string = "create_project_0"
 	CreateProjectRoleMethod    string = "create_project_role_0"
 	ReadProjectMethod          string = "read_project_0"
+	UpdateProjectMethod        string = "update_project_0"
 	ListProjectsByUserIDMethod string = "list_projects_by_user_id_0"
 )
 
@@ -95,6 +96,22 @@ func (repo *ProjectRepository) UpdateProjectRole(projID uint, role *models.Role)
 	return role, nil
 }
 
+// UpdateProject replaces a project in the in-memory projects array
+func (repo *ProjectRepository) UpdateProject(project *models.Project) (*models.Project, error) {
+	if !repo.canQuery || strings.Contains(repo.failingMethods, UpdateProjectMethod) {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(project.ID-1) >= len(repo.projects) || repo.projects[project.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	index := int(project.ID - 1)
+	repo.projects[index] = project
+
+	return project, nil
+}
+
 // ReadProject gets a projects specified by a unique id
 func (repo *ProjectRepository) ReadProjectRole(userID, projID uint) (*models.Role, error) {
 	if !repo.canQuery {
@@ -161,10 +178,7 @@ func (repo *ProjectRepository) ListProjectRoles(projID uint) ([]models.Role, err
 		return nil, gorm.ErrRecordNotFound
 	}
 
-	index := int(projID - 1)
-	repo.projects[index] = nil
-
-	return repo.projects[index].Roles, nil
+	return repo.projects[projID-1].Roles, nil
 }
 
 // DeleteProject removes a project
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"gorm.io/gorm"
+)
+
+// provisionedResourceRepository implements the queries that are shared by the resources
+// that are provisioned through an infra, such as buckets and queues
+type provisionedResourceRepository struct {
+	canQuery  bool
+	resources []models.ProvisionedResourceModel
+}
+
+func (repo *provisionedResourceRepository) ReadProvisionedResourceByInfraID(
+	projectID, infraID uint,
+) (models.ProvisionedResourceModel, error) {
+	return repo.read(func(res *models.ProvisionedResource) bool {
+		return res.ProjectID == projectID && res.InfraID == infraID
+	})
+}
+
+func (repo *provisionedResourceRepository) UpdateProvisionedResource(
+	resource models.ProvisionedResourceModel,
+) (models.ProvisionedResourceModel, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	id := resource.GetProvisionedResource().ID
+
+	if int(id-1) >= len(repo.resources) || repo.resources[id-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.resources[id-1] = resource
+
+	return resource, nil
+}
+
+func (repo *provisionedResourceRepository) DeleteProvisionedResource(resource models.ProvisionedResourceModel) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	id := resource.GetProvisionedResource().ID
+
+	if int(id-1) >= len(repo.resources) || repo.resources[id-1] == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	repo.resources[id-1] = nil
+
+	return nil
+}
+
+func (repo *provisionedResourceRepository) create(resource models.ProvisionedResourceModel) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	repo.resources = append(repo.resources, resource)
+	resource.GetProvisionedResource().ID = uint(len(repo.resources))
+
+	return nil
+}
+
+func (repo *provisionedResourceRepository) read(
+	match func(res *models.ProvisionedResource) bool,
+) (models.ProvisionedResourceModel, error) {
+	res, err := repo.list(match)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return res[0], nil
+}
+
+func (repo *provisionedResourceRepository) list(
+	match func(res *models.ProvisionedResource) bool,
+) ([]models.ProvisionedResourceModel, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]models.ProvisionedResourceModel, 0)
+
+	for _, resource := range repo.resources {
+		if resource != nil && match(resource.GetProvisionedResource()) {
+			res = append(res, resource)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,52 @@
+package test
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+type QueueRepository struct {
+	*provisionedResourceRepository
+}
+
+func NewQueueRepository(canQuery bool) repository.QueueRepository {
+	return &QueueRepository{&provisionedResourceRepository{canQuery: canQuery}}
+}
+
+func (repo *QueueRepository) CreateQueue(queue *models.Queue) (*models.Queue, error) {
+	if err := repo.create(queue); err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+func (repo *QueueRepository) ReadQueue(projectID, clusterID, queueID uint) (*models.Queue, error) {
+	queue, err := repo.read(func(res *models.ProvisionedResource) bool {
+		return res.ProjectID == projectID && res.ClusterID == clusterID && res.ID == queueID
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return queue.(*models.Queue), nil
+}
+
+func (repo *QueueRepository) ListQueues(projectID, clusterID uint) ([]*models.Queue, error) {
+	resources, err := repo.list(func(res *models.ProvisionedResource) bool {
+		return res.ProjectID == projectID && res.ClusterID == clusterID
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*models.Queue, 0)
+
+	for _, queue := range resources {
+		res = append(res, queue.(*models.Queue))
+	}
+
+	return res, nil
+}
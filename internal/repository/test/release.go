@@ -93,6 +93,68 @@ func (repo *ReleaseRepository) ListReleasesByImageRepoURI(
 	return res, nil
 }
 
+func (repo *ReleaseRepository) ListReleasesByImageSBOMIDs(
+	projectID uint, imageSBOMIDs []uint,
+) ([]*models.Release, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Release, 0)
+
+	for _, release := range repo.releases {
+		if release == nil || release.ProjectID != projectID {
+			continue
+		}
+
+		for _, id := range imageSBOMIDs {
+			if release.ImageSBOMID == id {
+				res = append(res, release)
+				break
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// ListDeletionProtectedReleases finds the releases of a cluster with deletion protection
+func (repo *ReleaseRepository) ListDeletionProtectedReleases(
+	clusterID uint,
+) ([]*models.Release, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Release, 0)
+
+	for _, release := range repo.releases {
+		if release != nil && release.ClusterID == clusterID && release.DeletionProtection {
+			res = append(res, release)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *ReleaseRepository) ListReleasesByNamespace(
+	clusterID uint, namespace string,
+) ([]*models.Release, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.Release, 0)
+
+	for _, release := range repo.releases {
+		if release != nil && release.ClusterID == clusterID && release.Namespace == namespace {
+			res = append(res, release)
+		}
+	}
+
+	return res, nil
+}
+
 // UpdateRelease modifies an existing Release in the database
 func (repo *ReleaseRepository) UpdateRelease(
 	release *models.Release,
@@ -16,6 +16,11 @@ type TestRepository struct {
 	invite                    repository.InviteRepository
 	release                   repository.ReleaseRepository
 	environment               repository.EnvironmentRepository
+	pipeline                  repository.PipelineRepository
+	changeRequest             repository.ChangeRequestRepository
+	defaultValues             repository.DefaultValuesRepository
+	templateRepo              repository.TemplateRepoRepository
+	addon                     repository.AddonRepository
 	authCode                  repository.AuthCodeRepository
 	dnsRecord                 repository.DNSRecordRepository
 	pwResetToken              repository.PWResetTokenRepository
@@ -31,7 +36,9 @@ type TestRepository struct {
 	slackIntegration          repository.SlackIntegrationRepository
 	notificationConfig        repository.NotificationConfigRepository
 	jobNotificationConfig     repository.JobNotificationConfigRepository
+	healthGateConfig          repository.HealthGateConfigRepository
 	buildEvent                repository.BuildEventRepository
+	buildLog                  repository.BuildLogRepository
 	kubeEvent                 repository.KubeEventRepository
 	projectUsage              repository.ProjectUsageRepository
 	onboarding                repository.ProjectOnboardingRepository
@@ -39,6 +46,24 @@ type TestRepository struct {
 	buildConfig               repository.BuildConfigRepository
 	database                  repository.DatabaseRepository
 	allowlist                 repository.AllowlistRepository
+	instanceAdmin             repository.InstanceAdminRepository
+	serverSetting             repository.ServerSettingRepository
+	apiErrorLog               repository.APIErrorLogRepository
+	sensitiveValues           repository.SensitiveValuesRepository
+	gitOpsConfig              repository.GitOpsConfigRepository
+	allowedChart              repository.AllowedChartRepository
+	buildpackDetection        repository.BuildpackDetectionRepository
+	backup                    repository.BackupRepository
+	bucket                    repository.BucketRepository
+	queue                     repository.QueueRepository
+	staleRelease              repository.StaleReleaseRepository
+	deployFreeze              repository.DeployFreezeRepository
+	scheduledDeploy           repository.ScheduledDeployRepository
+	preDeployRun              repository.PreDeployRunRepository
+	customChart               repository.CustomChartRepository
+	manifestPolicy            repository.ManifestPolicyRepository
+	imageSigningAuthority     repository.ImageSigningAuthorityRepository
+	imageSBOM                 repository.ImageSBOMRepository
 }
 
 func (t *TestRepository) User() repository.UserRepository {
@@ -85,6 +110,26 @@ func (t *TestRepository) Environment() repository.EnvironmentRepository {
 	return t.environment
 }
 
+func (t *TestRepository) Pipeline() repository.PipelineRepository {
+	return t.pipeline
+}
+
+func (t *TestRepository) ChangeRequest() repository.ChangeRequestRepository {
+	return t.changeRequest
+}
+
+func (t *TestRepository) DefaultValues() repository.DefaultValuesRepository {
+	return t.defaultValues
+}
+
+func (t *TestRepository) TemplateRepo() repository.TemplateRepoRepository {
+	return t.templateRepo
+}
+
+func (t *TestRepository) Addon() repository.AddonRepository {
+	return t.addon
+}
+
 func (t *TestRepository) AuthCode() repository.AuthCodeRepository {
 	return t.authCode
 }
@@ -145,10 +190,18 @@ func (t *TestRepository) JobNotificationConfig() repository.JobNotificationConfi
 	return t.jobNotificationConfig
 }
 
+func (t *TestRepository) HealthGateConfig() repository.HealthGateConfigRepository {
+	return t.healthGateConfig
+}
+
 func (t *TestRepository) BuildEvent() repository.BuildEventRepository {
 	return t.buildEvent
 }
 
+func (t *TestRepository) BuildLog() repository.BuildLogRepository {
+	return t.buildLog
+}
+
 func (t *TestRepository) KubeEvent() repository.KubeEventRepository {
 	return t.kubeEvent
 }
@@ -177,6 +230,78 @@ func (t *TestRepository) Allowlist() repository.AllowlistRepository {
 	return t.allowlist
 }
 
+func (t *TestRepository) InstanceAdmin() repository.InstanceAdminRepository {
+	return t.instanceAdmin
+}
+
+func (t *TestRepository) ServerSetting() repository.ServerSettingRepository {
+	return t.serverSetting
+}
+
+func (t *TestRepository) APIErrorLog() repository.APIErrorLogRepository {
+	return t.apiErrorLog
+}
+
+func (t *TestRepository) SensitiveValues() repository.SensitiveValuesRepository {
+	return t.sensitiveValues
+}
+
+func (t *TestRepository) GitOpsConfig() repository.GitOpsConfigRepository {
+	return t.gitOpsConfig
+}
+
+func (t *TestRepository) AllowedChart() repository.AllowedChartRepository {
+	return t.allowedChart
+}
+
+func (t *TestRepository) BuildpackDetection() repository.BuildpackDetectionRepository {
+	return t.buildpackDetection
+}
+
+func (t *TestRepository) Backup() repository.BackupRepository {
+	return t.backup
+}
+
+func (t *TestRepository) Bucket() repository.BucketRepository {
+	return t.bucket
+}
+
+func (t *TestRepository) Queue() repository.QueueRepository {
+	return t.queue
+}
+
+func (t *TestRepository) StaleRelease() repository.StaleReleaseRepository {
+	return t.staleRelease
+}
+
+func (t *TestRepository) DeployFreeze() repository.DeployFreezeRepository {
+	return t.deployFreeze
+}
+
+func (t *TestRepository) ScheduledDeploy() repository.ScheduledDeployRepository {
+	return t.scheduledDeploy
+}
+
+func (t *TestRepository) PreDeployRun() repository.PreDeployRunRepository {
+	return t.preDeployRun
+}
+
+func (t *TestRepository) CustomChart() repository.CustomChartRepository {
+	return t.customChart
+}
+
+func (t *TestRepository) ManifestPolicy() repository.ManifestPolicyRepository {
+	return t.manifestPolicy
+}
+
+func (t *TestRepository) ImageSigningAuthority() repository.ImageSigningAuthorityRepository {
+	return t.imageSigningAuthority
+}
+
+func (t *TestRepository) ImageSBOM() repository.ImageSBOMRepository {
+	return t.imageSBOM
+}
+
 // NewRepository returns a Repository which persists users in memory
 // and accepts a parameter that can trigger read/write errors
 func NewRepository(canQuery bool, failingMethods ...string) repository.Repository {
@@ -192,6 +317,11 @@ func NewRepository(canQuery bool, failingMethods ...string) repository.Repositor
 		invite:                    NewInviteRepository(canQuery),
 		release:                   NewReleaseRepository(canQuery),
 		environment:               NewEnvironmentRepository(),
+		pipeline:                  NewPipelineRepository(),
+		changeRequest:             NewChangeRequestRepository(canQuery),
+		defaultValues:             NewDefaultValuesRepository(canQuery),
+		templateRepo:              NewTemplateRepoRepository(),
+		addon:                     NewAddonRepository(canQuery),
 		authCode:                  NewAuthCodeRepository(canQuery),
 		dnsRecord:                 NewDNSRecordRepository(canQuery),
 		pwResetToken:              NewPWResetTokenRepository(canQuery),
@@ -207,7 +337,9 @@ func NewRepository(canQuery bool, failingMethods ...string) repository.Repositor
 		slackIntegration:          NewSlackIntegrationRepository(canQuery),
 		notificationConfig:        NewNotificationConfigRepository(canQuery),
 		jobNotificationConfig:     NewJobNotificationConfigRepository(canQuery),
+		healthGateConfig:          NewHealthGateConfigRepository(canQuery),
 		buildEvent:                NewBuildEventRepository(canQuery),
+		buildLog:                  NewBuildLogRepository(canQuery),
 		kubeEvent:                 NewKubeEventRepository(canQuery),
 		projectUsage:              NewProjectUsageRepository(canQuery),
 		onboarding:                NewProjectOnboardingRepository(canQuery),
@@ -215,5 +347,23 @@ func NewRepository(canQuery bool, failingMethods ...string) repository.Repositor
 		buildConfig:               NewBuildConfigRepository(canQuery),
 		database:                  NewDatabaseRepository(),
 		allowlist:                 NewAllowlistRepository(canQuery),
+		instanceAdmin:             NewInstanceAdminRepository(canQuery),
+		serverSetting:             NewServerSettingRepository(canQuery),
+		apiErrorLog:               NewAPIErrorLogRepository(canQuery),
+		sensitiveValues:           NewSensitiveValuesRepository(canQuery),
+		gitOpsConfig:              NewGitOpsConfigRepository(canQuery),
+		allowedChart:              NewAllowedChartRepository(canQuery),
+		buildpackDetection:        NewBuildpackDetectionRepository(canQuery),
+		backup:                    NewBackupRepository(canQuery),
+		bucket:                    NewBucketRepository(canQuery),
+		queue:                     NewQueueRepository(canQuery),
+		staleRelease:              NewStaleReleaseRepository(),
+		deployFreeze:              NewDeployFreezeRepository(canQuery),
+		scheduledDeploy:           NewScheduledDeployRepository(canQuery),
+		preDeployRun:              NewPreDeployRunRepository(canQuery),
+		customChart:               NewCustomChartRepository(),
+		manifestPolicy:            NewManifestPolicyRepository(canQuery),
+		imageSigningAuthority:     NewImageSigningAuthorityRepository(canQuery),
+		imageSBOM:                 NewImageSBOMRepository(canQuery),
 	}
 }
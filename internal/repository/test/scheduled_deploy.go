@@ -0,0 +1,155 @@
+package test
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ScheduledDeployRepository struct {
+	canQuery bool
+	sds      []*models.ScheduledDeploy
+}
+
+func NewScheduledDeployRepository(canQuery bool) repository.ScheduledDeployRepository {
+	return &ScheduledDeployRepository{canQuery, []*models.ScheduledDeploy{}}
+}
+
+func (repo *ScheduledDeployRepository) CreateScheduledDeploy(sd *models.ScheduledDeploy) (*models.ScheduledDeploy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.sds = append(repo.sds, sd)
+	sd.ID = uint(len(repo.sds))
+
+	return sd, nil
+}
+
+func (repo *ScheduledDeployRepository) ReadScheduledDeploy(clusterID, id uint) (*models.ScheduledDeploy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	if int(id-1) >= len(repo.sds) || repo.sds[id-1] == nil || repo.sds[id-1].ClusterID != clusterID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return repo.sds[id-1], nil
+}
+
+func (repo *ScheduledDeployRepository) ListScheduledDeploys(
+	clusterID uint,
+	namespace, name string,
+	statuses ...types.ScheduledDeployStatus,
+) ([]*models.ScheduledDeploy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.ScheduledDeploy, 0)
+
+	for _, sd := range repo.sds {
+		if sd != nil && sd.ClusterID == clusterID && sd.Namespace == namespace && sd.Name == name &&
+			(len(statuses) == 0 || hasScheduledDeployStatus(sd, statuses)) {
+			res = append(res, sd)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].RunAt.After(res[j].RunAt)
+	})
+
+	return res, nil
+}
+
+func (repo *ScheduledDeployRepository) ListDueScheduledDeploys(now time.Time) ([]*models.ScheduledDeploy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.ScheduledDeploy, 0)
+
+	for _, sd := range repo.sds {
+		if sd != nil && sd.Status == types.ScheduledDeployPending && !sd.RunAt.After(now) {
+			res = append(res, sd)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].RunAt.Before(res[j].RunAt)
+	})
+
+	return res, nil
+}
+
+func (repo *ScheduledDeployRepository) ClaimScheduledDeploy(sd *models.ScheduledDeploy) (bool, error) {
+	if !repo.canQuery {
+		return false, errors.New("Cannot write database")
+	}
+
+	if int(sd.ID-1) >= len(repo.sds) || repo.sds[sd.ID-1] == nil {
+		return false, gorm.ErrRecordNotFound
+	}
+
+	stored := repo.sds[sd.ID-1]
+
+	if stored.Status != types.ScheduledDeployPending {
+		return false, nil
+	}
+
+	now := time.Now()
+
+	stored.Status = types.ScheduledDeployRunning
+	stored.ClaimedAt = &now
+	sd.Status = types.ScheduledDeployRunning
+	sd.ClaimedAt = &now
+
+	return true, nil
+}
+
+func (repo *ScheduledDeployRepository) ListStaleScheduledDeploys(claimedBefore time.Time) ([]*models.ScheduledDeploy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.ScheduledDeploy, 0)
+
+	for _, sd := range repo.sds {
+		if sd != nil && sd.Status == types.ScheduledDeployRunning &&
+			(sd.ClaimedAt == nil || sd.ClaimedAt.Before(claimedBefore)) {
+			res = append(res, sd)
+		}
+	}
+
+	return res, nil
+}
+
+func (repo *ScheduledDeployRepository) UpdateScheduledDeploy(sd *models.ScheduledDeploy) (*models.ScheduledDeploy, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if int(sd.ID-1) >= len(repo.sds) || repo.sds[sd.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.sds[sd.ID-1] = sd
+
+	return sd, nil
+}
+
+func hasScheduledDeployStatus(sd *models.ScheduledDeploy, statuses []types.ScheduledDeployStatus) bool {
+	for _, status := range statuses {
+		if sd.Status == status {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"gorm.io/gorm"
+)
+
+type SensitiveValuesRepository struct {
+	canQuery        bool
+	sensitiveValues []*models.SensitiveValues
+}
+
+func NewSensitiveValuesRepository(canQuery bool) repository.SensitiveValuesRepository {
+	return &SensitiveValuesRepository{canQuery, []*models.SensitiveValues{}}
+}
+
+func (repo *SensitiveValuesRepository) ReadSensitiveValues(
+	clusterID uint,
+	namespace, name string,
+) (*models.SensitiveValues, error) {
+	return repo.readSensitiveValues(clusterID, namespace, name, -1)
+}
+
+func (repo *SensitiveValuesRepository) ReadSensitiveValuesRevision(
+	clusterID uint,
+	namespace, name string,
+	revision int,
+) (*models.SensitiveValues, error) {
+	return repo.readSensitiveValues(clusterID, namespace, name, revision)
+}
+
+// readSensitiveValues returns the values of a revision of a release, or of the latest
+// revision if the revision is negative
+func (repo *SensitiveValuesRepository) readSensitiveValues(
+	clusterID uint,
+	namespace, name string,
+	revision int,
+) (*models.SensitiveValues, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	var res *models.SensitiveValues
+
+	for _, sensitiveValues := range repo.sensitiveValues {
+		if sensitiveValues == nil || sensitiveValues.ClusterID != clusterID ||
+			sensitiveValues.Namespace != namespace || sensitiveValues.Name != name {
+			continue
+		}
+
+		if revision >= 0 && sensitiveValues.Revision == revision {
+			res = sensitiveValues
+		} else if revision < 0 && (res == nil || sensitiveValues.Revision >= res.Revision) {
+			res = sensitiveValues
+		}
+	}
+
+	if res == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return res, nil
+}
+
+func (repo *SensitiveValuesRepository) UpdateSensitiveValues(
+	sensitiveValues *models.SensitiveValues,
+) (*models.SensitiveValues, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	if sensitiveValues.ID == 0 {
+		repo.sensitiveValues = append(repo.sensitiveValues, sensitiveValues)
+		sensitiveValues.ID = uint(len(repo.sensitiveValues))
+
+		return sensitiveValues, nil
+	}
+
+	if int(sensitiveValues.ID-1) >= len(repo.sensitiveValues) || repo.sensitiveValues[sensitiveValues.ID-1] == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	repo.sensitiveValues[sensitiveValues.ID-1] = sensitiveValues
+
+	return sensitiveValues, nil
+}
+
+func (repo *SensitiveValuesRepository) DeleteSensitiveValues(clusterID uint, namespace, name string) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	for i, sensitiveValues := range repo.sensitiveValues {
+		if sensitiveValues != nil && sensitiveValues.ClusterID == clusterID &&
+			sensitiveValues.Namespace == namespace && sensitiveValues.Name == name {
+			repo.sensitiveValues[i] = nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"errors"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// ServerSettingRepository implements repository.ServerSettingRepository
+type ServerSettingRepository struct {
+	canQuery bool
+	settings map[types.ServerSettingKey]*models.ServerSetting
+}
+
+// NewServerSettingRepository will return errors if canQuery is false
+func NewServerSettingRepository(canQuery bool) repository.ServerSettingRepository {
+	return &ServerSettingRepository{
+		canQuery,
+		make(map[types.ServerSettingKey]*models.ServerSetting),
+	}
+}
+
+func (repo *ServerSettingRepository) ListServerSettings() ([]*models.ServerSetting, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot read from database")
+	}
+
+	res := make([]*models.ServerSetting, 0)
+
+	for _, setting := range repo.settings {
+		res = append(res, setting)
+	}
+
+	return res, nil
+}
+
+func (repo *ServerSettingRepository) UpsertServerSetting(setting *models.ServerSetting) (*models.ServerSetting, error) {
+	if !repo.canQuery {
+		return nil, errors.New("Cannot write database")
+	}
+
+	repo.settings[setting.Key] = setting
+
+	return setting, nil
+}
+
+func (repo *ServerSettingRepository) DeleteServerSetting(key types.ServerSettingKey) error {
+	if !repo.canQuery {
+		return errors.New("Cannot write database")
+	}
+
+	delete(repo.settings, key)
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package test
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+type StaleReleaseRepository struct {
+}
+
+func NewStaleReleaseRepository() repository.StaleReleaseRepository {
+	return &StaleReleaseRepository{}
+}
+
+func (repo *StaleReleaseRepository) CreateStaleRelease(staleRelease *models.StaleRelease) (*models.StaleRelease, error) {
+	panic("unimplemented")
+}
+
+func (repo *StaleReleaseRepository) ListStaleReleasesByClusterID(clusterID uint) ([]*models.StaleRelease, error) {
+	panic("unimplemented")
+}
+
+func (repo *StaleReleaseRepository) ListStaleReleasesByProjectID(projectID uint) ([]*models.StaleRelease, error) {
+	panic("unimplemented")
+}
+
+func (repo *StaleReleaseRepository) UpdateStaleRelease(staleRelease *models.StaleRelease) (*models.StaleRelease, error) {
+	panic("unimplemented")
+}
+
+func (repo *StaleReleaseRepository) DeleteStaleRelease(staleRelease *models.StaleRelease) (*models.StaleRelease, error) {
+	panic("unimplemented")
+}
@@ -0,0 +1,29 @@
+package test
+
+import (
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+type TemplateRepoRepository struct {
+}
+
+func NewTemplateRepoRepository() repository.TemplateRepoRepository {
+	return &TemplateRepoRepository{}
+}
+
+func (repo *TemplateRepoRepository) CreateTemplateRepo(templateRepo *models.TemplateRepo) (*models.TemplateRepo, error) {
+	panic("unimplemented")
+}
+
+func (repo *TemplateRepoRepository) ReadTemplateRepo(projectID, id uint) (*models.TemplateRepo, error) {
+	panic("unimplemented")
+}
+
+func (repo *TemplateRepoRepository) ListTemplateReposByProjectID(projectID uint) ([]*models.TemplateRepo, error) {
+	panic("unimplemented")
+}
+
+func (repo *TemplateRepoRepository) DeleteTemplateRepo(templateRepo *models.TemplateRepo) (*models.TemplateRepo, error) {
+	panic("unimplemented")
+}
@@ -0,0 +1,278 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/porter-dev/porter/api/types"
+)
+
+// BuildpacksMetadataLabel is the label of the images built by buildpacks that contains the
+// bill of materials of the build. Only older buildpacks write their bill of materials to
+// the label, while current buildpacks write SBOMs to the SBOM layer of the image.
+const BuildpacksMetadataLabel = "io.buildpacks.build.metadata"
+
+// LifecycleMetadataLabel is the label of the images built by buildpacks that contains the
+// layers of the image, including the SBOM layer
+const LifecycleMetadataLabel = "io.buildpacks.lifecycle.metadata"
+
+// HasSBOMLayer returns true if the lifecycle metadata label of an image lists an SBOM
+// layer
+func HasSBOMLayer(lifecycleMetadata string) bool {
+	metadata := &struct {
+		SBOM *struct {
+			SHA string `json:"sha"`
+		} `json:"sbom"`
+	}{}
+
+	if err := json.Unmarshal([]byte(lifecycleMetadata), metadata); err != nil {
+		return false
+	}
+
+	return metadata.SBOM != nil && metadata.SBOM.SHA != ""
+}
+
+// MergeCycloneDX merges CycloneDX SBOMs, such as the SBOMs that buildpacks write for each
+// layer of an image, into a single SBOM with the components of all of them
+func MergeCycloneDX(documents [][]byte) ([]byte, error) {
+	res := &struct {
+		BOMFormat   string            `json:"bomFormat"`
+		SpecVersion string            `json:"specVersion"`
+		Components  []json.RawMessage `json:"components"`
+	}{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.3",
+		Components:  make([]json.RawMessage, 0),
+	}
+
+	for _, document := range documents {
+		bom := &struct {
+			BOMFormat  string            `json:"bomFormat"`
+			Components []json.RawMessage `json:"components"`
+		}{}
+
+		if err := json.Unmarshal(document, bom); err != nil {
+			return nil, err
+		}
+
+		if bom.BOMFormat != "CycloneDX" {
+			return nil, fmt.Errorf("document is not a CycloneDX BOM")
+		}
+
+		res.Components = append(res.Components, bom.Components...)
+	}
+
+	return json.Marshal(res)
+}
+
+// Parse returns the packages of an SBOM document, sorted by name and version. Packages
+// without a name are skipped, and packages that are listed more than once are only
+// returned once.
+func Parse(format types.SBOMFormat, document []byte) ([]*types.SBOMPackage, error) {
+	var pkgs []*types.SBOMPackage
+	var err error
+
+	switch format {
+	case types.SBOMFormatBuildpacks:
+		pkgs, err = parseBuildpacks(document)
+	case types.SBOMFormatCycloneDXJSON:
+		pkgs, err = parseCycloneDX(document)
+	case types.SBOMFormatSPDXJSON:
+		pkgs, err = parseSPDX(document)
+	default:
+		return nil, fmt.Errorf("unsupported SBOM format %s", format)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s SBOM: %w", format, err)
+	}
+
+	res := make([]*types.SBOMPackage, 0, len(pkgs))
+	seen := make(map[types.SBOMPackage]bool)
+
+	for _, pkg := range pkgs {
+		if pkg.Name == "" || seen[*pkg] {
+			continue
+		}
+
+		seen[*pkg] = true
+
+		if pkg.Type == "" {
+			pkg.Type = getPURLType(pkg.PURL)
+		}
+
+		res = append(res, pkg)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+
+		return res[i].Version < res[j].Version
+	})
+
+	return res, nil
+}
+
+// MatchesVersion returns true if the version of a package satisfies a semantic version
+// constraint. Versions that are not semantic versions, such as the versions of some OS
+// packages, never match a constraint.
+func MatchesVersion(version string, constraint *semver.Constraints) bool {
+	if constraint == nil {
+		return true
+	}
+
+	v, err := semver.NewVersion(version)
+
+	if err != nil {
+		return false
+	}
+
+	return constraint.Check(v)
+}
+
+// parseBuildpacks parses the value of the build metadata label of an image built by
+// buildpacks. The versions of entries are either set on the entry or in its metadata,
+// depending on the buildpack.
+func parseBuildpacks(document []byte) ([]*types.SBOMPackage, error) {
+	metadata := &struct {
+		BOM []struct {
+			Name     string `json:"name"`
+			Version  string `json:"version"`
+			Metadata struct {
+				Version string `json:"version"`
+				PURL    string `json:"purl"`
+			} `json:"metadata"`
+		} `json:"bom"`
+	}{}
+
+	if err := json.Unmarshal(document, metadata); err != nil {
+		return nil, err
+	}
+
+	res := make([]*types.SBOMPackage, 0)
+
+	for _, entry := range metadata.BOM {
+		version := entry.Version
+
+		if version == "" {
+			version = entry.Metadata.Version
+		}
+
+		res = append(res, &types.SBOMPackage{
+			Name:    entry.Name,
+			Version: version,
+			PURL:    entry.Metadata.PURL,
+		})
+	}
+
+	return res, nil
+}
+
+type cycloneDXComponent struct {
+	Type       string                `json:"type"`
+	Name       string                `json:"name"`
+	Version    string                `json:"version"`
+	PURL       string                `json:"purl"`
+	Components []*cycloneDXComponent `json:"components"`
+}
+
+func parseCycloneDX(document []byte) ([]*types.SBOMPackage, error) {
+	bom := &struct {
+		BOMFormat  string                `json:"bomFormat"`
+		Components []*cycloneDXComponent `json:"components"`
+	}{}
+
+	if err := json.Unmarshal(document, bom); err != nil {
+		return nil, err
+	}
+
+	if bom.BOMFormat != "CycloneDX" {
+		return nil, fmt.Errorf("document is not a CycloneDX BOM")
+	}
+
+	res := make([]*types.SBOMPackage, 0)
+
+	// components may contain the components that they bundle, such as the dependencies
+	// that are shaded into a jar
+	var addComponents func(components []*cycloneDXComponent)
+
+	addComponents = func(components []*cycloneDXComponent) {
+		for _, component := range components {
+			// the operating system and the files of the image are also components
+			if component.Type != "operating-system" && component.Type != "file" {
+				res = append(res, &types.SBOMPackage{
+					Name:    component.Name,
+					Version: component.Version,
+					PURL:    component.PURL,
+				})
+			}
+
+			addComponents(component.Components)
+		}
+	}
+
+	addComponents(bom.Components)
+
+	return res, nil
+}
+
+func parseSPDX(document []byte) ([]*types.SBOMPackage, error) {
+	doc := &struct {
+		SPDXVersion string `json:"spdxVersion"`
+		Packages    []struct {
+			Name         string `json:"name"`
+			VersionInfo  string `json:"versionInfo"`
+			ExternalRefs []struct {
+				ReferenceType    string `json:"referenceType"`
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}{}
+
+	if err := json.Unmarshal(document, doc); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(doc.SPDXVersion, "SPDX-") {
+		return nil, fmt.Errorf("document is not an SPDX document")
+	}
+
+	res := make([]*types.SBOMPackage, 0)
+
+	for _, pkg := range doc.Packages {
+		purl := ""
+
+		for _, ref := range pkg.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				purl = ref.ReferenceLocator
+				break
+			}
+		}
+
+		res = append(res, &types.SBOMPackage{
+			Name:    pkg.Name,
+			Version: pkg.VersionInfo,
+			PURL:    purl,
+		})
+	}
+
+	return res, nil
+}
+
+// getPURLType returns the type of a package URL, such as maven or npm
+func getPURLType(purl string) string {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return ""
+	}
+
+	if i := strings.Index(purl, "/"); i > len("pkg:") {
+		return purl[len("pkg:"):i]
+	}
+
+	return ""
+}
@@ -0,0 +1,200 @@
+package sbom_test
+
+import (
+	"reflect"
+	"testing"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/sbom"
+)
+
+const testBuildpacksMetadata = `{
+  "bom": [
+    {
+      "name": "jre",
+      "metadata": {"version": "11.0.13", "purl": "pkg:generic/bellsoft-jre@11.0.13"},
+      "buildpack": {"id": "paketo-buildpacks/bellsoft-liberica", "version": "9.0.1"}
+    },
+    {
+      "name": "node",
+      "version": "16.13.0",
+      "buildpack": {"id": "paketo-buildpacks/node-engine", "version": "0.11.2"}
+    },
+    {
+      "name": "",
+      "version": "1.0.0"
+    }
+  ],
+  "buildpacks": [{"id": "paketo-buildpacks/node-engine", "version": "0.11.2"}]
+}`
+
+const testCycloneDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "components": [
+    {"type": "operating-system", "name": "debian", "version": "11"},
+    {
+      "type": "library",
+      "group": "org.apache.logging.log4j",
+      "name": "log4j-core",
+      "version": "2.14.1",
+      "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+      "components": [
+        {"type": "library", "name": "log4j-api", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-api@2.14.1"}
+      ]
+    },
+    {"type": "library", "name": "log4j-core", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+    {"type": "library", "name": "libc6", "version": "2.31-13+deb11u2", "purl": "pkg:deb/debian/libc6@2.31-13+deb11u2"}
+  ]
+}`
+
+const testSPDX = `{
+  "spdxVersion": "SPDX-2.2",
+  "packages": [
+    {
+      "name": "express",
+      "versionInfo": "4.17.1",
+      "externalRefs": [
+        {"referenceCategory": "SECURITY", "referenceType": "cpe23Type", "referenceLocator": "cpe:2.3:a:express:express:4.17.1:*:*:*:*:*:*:*"},
+        {"referenceCategory": "PACKAGE_MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/express@4.17.1"}
+      ]
+    }
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		format   types.SBOMFormat
+		document string
+		expected []*types.SBOMPackage
+	}{
+		{
+			format:   types.SBOMFormatBuildpacks,
+			document: testBuildpacksMetadata,
+			expected: []*types.SBOMPackage{
+				{Name: "jre", Version: "11.0.13", Type: "generic", PURL: "pkg:generic/bellsoft-jre@11.0.13"},
+				{Name: "node", Version: "16.13.0"},
+			},
+		},
+		{
+			format:   types.SBOMFormatCycloneDXJSON,
+			document: testCycloneDX,
+			expected: []*types.SBOMPackage{
+				{Name: "libc6", Version: "2.31-13+deb11u2", Type: "deb", PURL: "pkg:deb/debian/libc6@2.31-13+deb11u2"},
+				{Name: "log4j-api", Version: "2.14.1", Type: "maven", PURL: "pkg:maven/org.apache.logging.log4j/log4j-api@2.14.1"},
+				{Name: "log4j-core", Version: "2.14.1", Type: "maven", PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+			},
+		},
+		{
+			format:   types.SBOMFormatSPDXJSON,
+			document: testSPDX,
+			expected: []*types.SBOMPackage{
+				{Name: "express", Version: "4.17.1", Type: "npm", PURL: "pkg:npm/express@4.17.1"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		pkgs, err := sbom.Parse(test.format, []byte(test.document))
+
+		if err != nil {
+			t.Fatalf("%s: %v", test.format, err)
+		}
+
+		if !reflect.DeepEqual(pkgs, test.expected) {
+			t.Errorf("%s: incorrect packages", test.format)
+
+			for _, pkg := range pkgs {
+				t.Logf("%+v", *pkg)
+			}
+		}
+	}
+}
+
+func TestParseWrongFormat(t *testing.T) {
+	if _, err := sbom.Parse(types.SBOMFormatCycloneDXJSON, []byte(testSPDX)); err == nil {
+		t.Errorf("expected an SPDX document to not be parsed as a CycloneDX BOM")
+	}
+
+	if _, err := sbom.Parse(types.SBOMFormat("syft-json"), []byte("{}")); err == nil {
+		t.Errorf("expected an unsupported format to return an error")
+	}
+}
+
+func TestMatchesVersion(t *testing.T) {
+	constraint, err := semver.NewConstraint("2.x")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		"2.14.1":          true,
+		"2.0":             true,
+		"1.2.17":          false,
+		"3.0.0":           false,
+		"2.31-13+deb11u2": false,
+		"":                false,
+	}
+
+	for version, expected := range tests {
+		if res := sbom.MatchesVersion(version, constraint); res != expected {
+			t.Errorf("expected %s to match 2.x to be %t, got %t", version, expected, res)
+		}
+	}
+
+	if !sbom.MatchesVersion("not-semver", nil) {
+		t.Errorf("expected every version to match without a constraint")
+	}
+}
+
+func TestMergeCycloneDX(t *testing.T) {
+	layerSBOM := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.3",
+  "components": [
+    {"type": "library", "name": "express", "version": "4.17.1", "purl": "pkg:npm/express@4.17.1"}
+  ]
+}`
+
+	document, err := sbom.MergeCycloneDX([][]byte{[]byte(testCycloneDX), []byte(layerSBOM)})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkgs, err := sbom.Parse(types.SBOMFormatCycloneDXJSON, document)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0)
+
+	for _, pkg := range pkgs {
+		names = append(names, pkg.Name)
+	}
+
+	if expected := []string{"express", "libc6", "log4j-api", "log4j-core"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected the packages of every layer %v, got %v", expected, names)
+	}
+
+	if _, err := sbom.MergeCycloneDX([][]byte{[]byte(testSPDX)}); err == nil {
+		t.Errorf("expected an SPDX document to not be merged into a CycloneDX BOM")
+	}
+}
+
+func TestHasSBOMLayer(t *testing.T) {
+	tests := map[string]bool{
+		`{"app": [{"sha": "sha256:aaaa"}], "sbom": {"sha": "sha256:bbbb"}}`: true,
+		`{"app": [{"sha": "sha256:aaaa"}]}`:                                 false,
+		``:                                                                  false,
+	}
+
+	for metadata, expected := range tests {
+		if res := sbom.HasSBOMLayer(metadata); res != expected {
+			t.Errorf("expected %q to have an SBOM layer to be %t, got %t", metadata, expected, res)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// Manager reads the settings of the server, which are the settings of the environment
+// overridden by the settings that instance admins stored in the database. Overrides
+// are cached, and reloaded from the database when the cache is older than the TTL, so
+// that changes made on one replica of the server reach every replica.
+type Manager struct {
+	repo repository.ServerSettingRepository
+
+	// envValues are the values of the settings in the environment
+	envValues map[types.ServerSettingKey]string
+
+	ttl time.Duration
+
+	mu        sync.RWMutex
+	overrides map[types.ServerSettingKey]*models.ServerSetting
+	loadedAt  time.Time
+	listeners []func(m *Manager)
+}
+
+func NewManager(
+	repo repository.ServerSettingRepository,
+	envValues map[types.ServerSettingKey]string,
+	ttl time.Duration,
+) (*Manager, error) {
+	m := &Manager{
+		repo:      repo,
+		envValues: envValues,
+		ttl:       ttl,
+		overrides: make(map[types.ServerSettingKey]*models.ServerSetting),
+	}
+
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// OnChange registers a function that is called after the value of any setting changes
+func (m *Manager) OnChange(fn func(m *Manager)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.listeners = append(m.listeners, fn)
+}
+
+// Get returns the current value of a setting
+func (m *Manager) Get(key types.ServerSettingKey) string {
+	m.refreshIfStale()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if override, ok := m.overrides[key]; ok {
+		return override.Value
+	}
+
+	return m.envValues[key]
+}
+
+// GetBool returns the current value of a boolean setting, which is false if the value
+// cannot be parsed
+func (m *Manager) GetBool(key types.ServerSettingKey) bool {
+	res, _ := strconv.ParseBool(m.Get(key))
+
+	return res
+}
+
+// List returns every setting with its current and environment value
+func (m *Manager) List() []*types.ServerSetting {
+	m.refreshIfStale()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]*types.ServerSetting, 0)
+
+	for _, key := range types.ServerSettingKeys {
+		setting := &types.ServerSetting{
+			Key:      key,
+			Value:    m.envValues[key],
+			EnvValue: m.envValues[key],
+		}
+
+		if override, ok := m.overrides[key]; ok {
+			updatedAt := override.UpdatedAt
+
+			setting.Value = override.Value
+			setting.Overridden = true
+			setting.UpdatedAt = &updatedAt
+			setting.UpdatedBy = override.UpdatedBy
+		}
+
+		res = append(res, setting)
+	}
+
+	return res
+}
+
+// Set overrides the value of a setting
+func (m *Manager) Set(key types.ServerSettingKey, value string, userID uint) error {
+	if err := Validate(key, value); err != nil {
+		return err
+	}
+
+	_, err := m.repo.UpsertServerSetting(&models.ServerSetting{
+		Key:       key,
+		Value:     value,
+		UpdatedBy: userID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return m.Reload()
+}
+
+// Unset removes the override of a setting, so that the value of the environment is used
+func (m *Manager) Unset(key types.ServerSettingKey) error {
+	if err := m.repo.DeleteServerSetting(key); err != nil {
+		return err
+	}
+
+	return m.Reload()
+}
+
+// Reload reads the overrides from the database, and calls the listeners if any value
+// has changed
+func (m *Manager) Reload() error {
+	settings, err := m.repo.ListServerSettings()
+
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[types.ServerSettingKey]*models.ServerSetting)
+
+	for _, setting := range settings {
+		overrides[setting.Key] = setting
+	}
+
+	m.mu.Lock()
+
+	changed := !sameValues(m.overrides, overrides)
+
+	m.overrides = overrides
+	m.loadedAt = time.Now()
+	listeners := m.listeners
+
+	m.mu.Unlock()
+
+	if changed {
+		for _, fn := range listeners {
+			fn(m)
+		}
+	}
+
+	return nil
+}
+
+// refreshIfStale reloads the overrides if the cache is older than the TTL. If the
+// overrides cannot be reloaded, the cached overrides are used until the next TTL.
+func (m *Manager) refreshIfStale() {
+	m.mu.Lock()
+
+	if m.ttl <= 0 || time.Since(m.loadedAt) < m.ttl {
+		m.mu.Unlock()
+		return
+	}
+
+	m.loadedAt = time.Now()
+
+	m.mu.Unlock()
+
+	m.Reload()
+}
+
+func sameValues(a, b map[types.ServerSettingKey]*models.ServerSetting) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, setting := range a {
+		if other, ok := b[key]; !ok || other.Value != setting.Value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate returns an error if the value is not valid for the setting
+func Validate(key types.ServerSettingKey, value string) error {
+	switch key {
+	case types.ServerSettingAppRootDomain:
+		if value == "" {
+			return fmt.Errorf("%s cannot be empty", key)
+		}
+	case types.ServerSettingDefaultApplicationHelmRepoURL, types.ServerSettingDefaultAddonHelmRepoURL:
+		parsed, err := url.Parse(value)
+
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s must be an http or https URL", key)
+		}
+	case types.ServerSettingDisableAllowlist, types.ServerSettingAnalyticsOptOut:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be true or false", key)
+		}
+	default:
+		return fmt.Errorf("unknown setting %s", key)
+	}
+
+	return nil
+}
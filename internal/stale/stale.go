@@ -0,0 +1,429 @@
+package stale
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/helm"
+	"github.com/porter-dev/porter/internal/integrations/slack"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/prometheus"
+	"github.com/porter-dev/porter/internal/logger"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/redis_stream/lease"
+	"github.com/porter-dev/porter/internal/repository"
+	"golang.org/x/oauth2"
+	"helm.sh/helm/v3/pkg/release"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// releaseNameAnnotation is set by Helm on every resource of a release
+const releaseNameAnnotation = "meta.helm.sh/release-name"
+
+// Detector flags releases that have not been deployed for the stale period, have been
+// scaled down to no replicas for the period and have received no traffic in the period.
+// Traffic is only checked in clusters where Prometheus has metrics from the NGINX
+// ingress controller, and is not a condition for releases whose traffic is unknown.
+type Detector struct {
+	Repo      repository.Repository
+	DOConf    *oauth2.Config
+	ServerURL string
+	Logger    *logger.Logger
+
+	// Period is the time after which a release without deploys, traffic or replicas is
+	// stale
+	Period time.Duration
+
+	// Notify sends a Slack notification the first time that a release is found to be
+	// stale, unless notifications are disabled for the cluster
+	Notify bool
+
+	// Leader elects the replica that checks the releases, so that clusters are not
+	// checked and notifications are not sent by every replica. If Leader is nil, the
+	// releases are checked by this replica.
+	Leader lease.LeaderElector
+}
+
+func NewDetector(
+	repo repository.Repository,
+	doConf *oauth2.Config,
+	serverURL string,
+	logger *logger.Logger,
+	period time.Duration,
+	notify bool,
+) *Detector {
+	return &Detector{
+		Repo:      repo,
+		DOConf:    doConf,
+		ServerURL: serverURL,
+		Logger:    logger,
+		Period:    period,
+		Notify:    notify,
+	}
+}
+
+// Run checks the releases of all clusters at the given interval until the context is
+// cancelled
+func (d *Detector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if d.isLeader(ctx) {
+			d.CheckAll()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Detector) isLeader(ctx context.Context) bool {
+	if d.Leader == nil {
+		return true
+	}
+
+	isLeader, err := d.Leader.IsLeader(ctx)
+
+	if err != nil {
+		d.Logger.Error().Err(err).Msg("error electing the replica that checks for stale releases")
+		return false
+	}
+
+	return isLeader
+}
+
+// CheckAll checks the releases of all clusters. A cluster that cannot be reached is
+// skipped, and keeps the result of its previous check.
+func (d *Detector) CheckAll() {
+	clusters, err := d.Repo.Cluster().ListClusters()
+
+	if err != nil {
+		d.Logger.Error().Err(err).Msg("error listing clusters for stale release check")
+		return
+	}
+
+	for _, cluster := range clusters {
+		if err := d.Check(cluster); err != nil {
+			d.Logger.Error().Err(err).Uint("cluster_id", cluster.ID).Msg("error checking cluster for stale releases")
+		}
+	}
+}
+
+// Check updates the tracked releases of a cluster. Releases that have not been deployed
+// for the stale period are tracked, and tracked releases that were deployed again or
+// deleted are no longer tracked.
+func (d *Detector) Check(cluster *models.Cluster) error {
+	helmAgent, err := helm.GetAgentOutOfClusterConfig(&helm.Form{
+		Cluster:           cluster,
+		Repo:              d.Repo,
+		DigitalOceanOAuth: d.DOConf,
+		Storage:           "secret",
+	}, d.Logger)
+
+	if err != nil {
+		return err
+	}
+
+	releases, err := helmAgent.ListReleases("", &types.ReleaseListFilter{
+		StatusFilter: []string{"deployed"},
+		SkipManifest: true,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	tracked, err := d.Repo.StaleRelease().ListStaleReleasesByClusterID(cluster.ID)
+
+	if err != nil {
+		return err
+	}
+
+	trackedByName := make(map[string]*models.StaleRelease)
+
+	for _, staleRelease := range tracked {
+		trackedByName[staleRelease.Namespace+"/"+staleRelease.Name] = staleRelease
+	}
+
+	promSvc := d.getTrafficMetricsService(helmAgent.K8sAgent)
+	now := time.Now()
+	seen := make(map[string]bool)
+
+	for _, rel := range releases {
+		if rel.Info == nil {
+			continue
+		}
+
+		id := rel.Namespace + "/" + rel.Name
+		seen[id] = true
+		staleRelease, isTracked := trackedByName[id]
+		lastDeployed := rel.Info.LastDeployed.Time
+
+		if now.Sub(lastDeployed) < d.Period {
+			if isTracked {
+				if _, err := d.Repo.StaleRelease().DeleteStaleRelease(staleRelease); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if !isTracked {
+			staleRelease = &models.StaleRelease{
+				ProjectID: cluster.ProjectID,
+				ClusterID: cluster.ID,
+				Namespace: rel.Namespace,
+				Name:      rel.Name,
+			}
+		}
+
+		staleRelease.LastDeployedAt = lastDeployed
+
+		if err := d.checkRelease(helmAgent.K8sAgent, promSvc, rel, staleRelease, now); err != nil {
+			d.Logger.Warn().Err(err).Uint("cluster_id", cluster.ID).Str("release", id).Msg("error checking stale release")
+			continue
+		}
+
+		wasStale := staleRelease.IsStale()
+
+		d.updateReasons(staleRelease, now)
+
+		if isTracked {
+			staleRelease, err = d.Repo.StaleRelease().UpdateStaleRelease(staleRelease)
+		} else {
+			staleRelease, err = d.Repo.StaleRelease().CreateStaleRelease(staleRelease)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !wasStale && staleRelease.IsStale() && d.Notify && staleRelease.NotifiedAt == nil {
+			d.notify(cluster, staleRelease)
+		}
+	}
+
+	for id, staleRelease := range trackedByName {
+		if !seen[id] {
+			if _, err := d.Repo.StaleRelease().DeleteStaleRelease(staleRelease); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getTrafficMetricsService returns the Prometheus service of the cluster if it has
+// request metrics from the ingress controller, or nil otherwise
+func (d *Detector) getTrafficMetricsService(agent *kubernetes.Agent) *v1.Service {
+	promSvc, found, err := prometheus.GetPrometheusService(agent.Clientset)
+
+	if err != nil || !found {
+		return nil
+	}
+
+	if hasMetrics, err := prometheus.HasIngressRequestMetrics(agent.Clientset, promSvc); err != nil || !hasMetrics {
+		return nil
+	}
+
+	return promSvc
+}
+
+// checkRelease updates whether the workloads of a release are scaled down, and whether
+// its ingresses received traffic in the stale period
+func (d *Detector) checkRelease(
+	agent *kubernetes.Agent,
+	promSvc *v1.Service,
+	rel *release.Release,
+	staleRelease *models.StaleRelease,
+	now time.Time,
+) error {
+	scaledDown, err := isScaledDown(agent, rel)
+
+	if err != nil {
+		return err
+	}
+
+	if !scaledDown {
+		staleRelease.ScaledDownSince = nil
+	} else if staleRelease.ScaledDownSince == nil {
+		staleRelease.ScaledDownSince = &now
+	}
+
+	staleRelease.TrafficChecked = false
+	staleRelease.NoTraffic = false
+
+	if promSvc == nil {
+		return nil
+	}
+
+	ingressNames, err := getIngressNames(agent, rel)
+
+	if err != nil {
+		return err
+	}
+
+	// releases without ingresses do not receive traffic through the ingress controller,
+	// so their traffic is unknown
+	if len(ingressNames) == 0 {
+		return nil
+	}
+
+	count, err := prometheus.GetIngressRequestCount(agent.Clientset, promSvc, rel.Namespace, ingressNames, d.Period)
+
+	if err != nil {
+		return err
+	}
+
+	staleRelease.TrafficChecked = true
+	staleRelease.NoTraffic = count == 0
+
+	return nil
+}
+
+// updateReasons sets the reasons that a release is stale. A release is stale if it has
+// not been deployed for the stale period, has been scaled down for the period and, if
+// its traffic is known, has received no traffic in the period.
+func (d *Detector) updateReasons(staleRelease *models.StaleRelease, now time.Time) {
+	scaledDown := staleRelease.ScaledDownSince != nil && now.Sub(*staleRelease.ScaledDownSince) >= d.Period
+	noTraffic := !staleRelease.TrafficChecked || staleRelease.NoTraffic
+
+	if !scaledDown || !noTraffic {
+		staleRelease.Reasons = ""
+		staleRelease.StaleSince = nil
+		staleRelease.NotifiedAt = nil
+
+		return
+	}
+
+	reasons := []string{string(types.StaleReasonNoDeploys), string(types.StaleReasonScaledDown)}
+
+	if staleRelease.TrafficChecked {
+		reasons = append(reasons, string(types.StaleReasonNoTraffic))
+	}
+
+	staleRelease.Reasons = strings.Join(reasons, ",")
+
+	if staleRelease.StaleSince == nil {
+		staleRelease.StaleSince = &now
+	}
+}
+
+// isScaledDown returns true if the release has deployments or statefulsets, and all of
+// them are scaled to no replicas
+func isScaledDown(agent *kubernetes.Agent, rel *release.Release) (bool, error) {
+	workloads := 0
+
+	deployments, err := agent.Clientset.AppsV1().Deployments(rel.Namespace).List(context.TODO(), metav1.ListOptions{})
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, depl := range deployments.Items {
+		if depl.Annotations[releaseNameAnnotation] != rel.Name {
+			continue
+		}
+
+		workloads++
+
+		if depl.Spec.Replicas == nil || *depl.Spec.Replicas > 0 {
+			return false, nil
+		}
+	}
+
+	statefulSets, err := agent.Clientset.AppsV1().StatefulSets(rel.Namespace).List(context.TODO(), metav1.ListOptions{})
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, ss := range statefulSets.Items {
+		if ss.Annotations[releaseNameAnnotation] != rel.Name {
+			continue
+		}
+
+		workloads++
+
+		if ss.Spec.Replicas == nil || *ss.Spec.Replicas > 0 {
+			return false, nil
+		}
+	}
+
+	return workloads > 0, nil
+}
+
+func getIngressNames(agent *kubernetes.Agent, rel *release.Release) ([]string, error) {
+	ingresses, err := agent.Clientset.NetworkingV1().Ingresses(rel.Namespace).List(context.TODO(), metav1.ListOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0)
+
+	for _, ing := range ingresses.Items {
+		if ing.Annotations[releaseNameAnnotation] == rel.Name {
+			res = append(res, ing.Name)
+		}
+	}
+
+	return res, nil
+}
+
+func (d *Detector) notify(cluster *models.Cluster, staleRelease *models.StaleRelease) {
+	if cluster.NotificationsDisabled {
+		return
+	}
+
+	slackInts, err := d.Repo.SlackIntegration().ListSlackIntegrationsByProjectID(cluster.ProjectID)
+
+	if err != nil || len(slackInts) == 0 {
+		return
+	}
+
+	notifier := slack.NewSlackNotifier(nil, slackInts...)
+
+	err = notifier.Notify(&slack.NotifyOpts{
+		ProjectID:   cluster.ProjectID,
+		ClusterID:   cluster.ID,
+		ClusterName: cluster.Name,
+		Status:      slack.StatusReleaseStale,
+		Info: fmt.Sprintf(
+			"*Last deployed:* %s\n*Reasons:* `%s`",
+			staleRelease.LastDeployedAt.Format("2006-01-02"),
+			strings.ReplaceAll(staleRelease.Reasons, ",", "`, `"),
+		),
+		Name:      staleRelease.Name,
+		Namespace: staleRelease.Namespace,
+		URL: fmt.Sprintf(
+			"%s/applications/%s/%s/%s?project_id=%d",
+			d.ServerURL,
+			url.PathEscape(cluster.Name),
+			staleRelease.Namespace,
+			staleRelease.Name,
+			cluster.ProjectID,
+		),
+	})
+
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	staleRelease.NotifiedAt = &now
+
+	d.Repo.StaleRelease().UpdateStaleRelease(staleRelease)
+}
@@ -0,0 +1,217 @@
+package stale
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/logger"
+	"github.com/porter-dev/porter/internal/models"
+	"helm.sh/helm/v3/pkg/release"
+
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateReasons(t *testing.T) {
+	now := time.Now()
+	scaledDownSince := now.Add(-48 * time.Hour)
+	recentlyScaledDown := now.Add(-time.Hour)
+
+	tests := []struct {
+		name            string
+		scaledDownSince *time.Time
+		trafficChecked  bool
+		noTraffic       bool
+		expected        string
+	}{
+		{"running", nil, false, false, ""},
+		{"running without traffic", nil, true, true, ""},
+		{"recently scaled down", &recentlyScaledDown, true, true, ""},
+		{"scaled down with traffic", &scaledDownSince, true, false, ""},
+		{"scaled down with unknown traffic", &scaledDownSince, false, false, "no_deploys,scaled_down"},
+		{"scaled down without traffic", &scaledDownSince, true, true, "no_deploys,scaled_down,no_traffic"},
+	}
+
+	d := &Detector{Period: 24 * time.Hour}
+
+	for _, tt := range tests {
+		staleRelease := &models.StaleRelease{
+			ScaledDownSince: tt.scaledDownSince,
+			TrafficChecked:  tt.trafficChecked,
+			NoTraffic:       tt.noTraffic,
+		}
+
+		d.updateReasons(staleRelease, now)
+
+		if staleRelease.Reasons != tt.expected {
+			t.Errorf("%s: expected reasons %q, got %q", tt.name, tt.expected, staleRelease.Reasons)
+		}
+
+		if staleRelease.IsStale() != (tt.expected != "") {
+			t.Errorf("%s: expected stale to be %t, got %t", tt.name, tt.expected != "", staleRelease.IsStale())
+		}
+	}
+}
+
+func TestUpdateReasonsClearsStaleRelease(t *testing.T) {
+	now := time.Now()
+	scaledDownSince := now.Add(-48 * time.Hour)
+
+	d := &Detector{Period: 24 * time.Hour}
+
+	staleRelease := &models.StaleRelease{ScaledDownSince: &scaledDownSince}
+
+	d.updateReasons(staleRelease, now)
+
+	staleRelease.NotifiedAt = &now
+
+	// the release is scaled up again, so it is no longer stale and is notified again
+	// once it becomes stale again
+	staleRelease.ScaledDownSince = nil
+
+	d.updateReasons(staleRelease, now)
+
+	if staleRelease.IsStale() || staleRelease.Reasons != "" || staleRelease.NotifiedAt != nil {
+		t.Errorf("expected the release to no longer be stale, got %v", staleRelease)
+	}
+
+	if res := staleRelease.ToStaleReleaseType(); len(res.Reasons) != 0 {
+		t.Errorf("expected no reasons, got %v", res.Reasons)
+	}
+}
+
+func getTestDeployment(name, releaseName string, replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{releaseNameAnnotation: releaseName},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+	}
+}
+
+func TestIsScaledDown(t *testing.T) {
+	agent := kubernetes.GetAgentTesting(
+		getTestDeployment("web", "web", 0),
+		getTestDeployment("worker", "worker", 0),
+		getTestDeployment("worker-consumer", "worker", 2),
+		getTestDeployment("other", "other", 1),
+	)
+
+	tests := map[string]bool{
+		// all workloads of the release have no replicas
+		"web": true,
+		// a workload of the release still has replicas
+		"worker": false,
+		// releases without workloads are not scaled down
+		"job": false,
+	}
+
+	for name, expected := range tests {
+		scaledDown, err := isScaledDown(agent, &release.Release{Name: name, Namespace: "default"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if scaledDown != expected {
+			t.Errorf("%s: expected scaled down to be %t, got %t", name, expected, scaledDown)
+		}
+	}
+}
+
+func TestGetIngressNames(t *testing.T) {
+	agent := kubernetes.GetAgentTesting(
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "web-ingress",
+				Namespace:   "default",
+				Annotations: map[string]string{releaseNameAnnotation: "web"},
+			},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "other-ingress",
+				Namespace:   "default",
+				Annotations: map[string]string{releaseNameAnnotation: "other"},
+			},
+		},
+	)
+
+	names, err := getIngressNames(agent, &release.Release{Name: "web", Namespace: "default"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != 1 || names[0] != "web-ingress" {
+		t.Errorf("expected the ingress of the release, got %v", names)
+	}
+}
+
+func TestCheckReleaseWithoutTrafficMetrics(t *testing.T) {
+	agent := kubernetes.GetAgentTesting(getTestDeployment("web", "web", 0))
+	d := &Detector{Period: 24 * time.Hour}
+	now := time.Now()
+	scaledDownSince := now.Add(-48 * time.Hour)
+
+	staleRelease := &models.StaleRelease{
+		ScaledDownSince: &scaledDownSince,
+		TrafficChecked:  true,
+		NoTraffic:       true,
+	}
+
+	if err := d.checkRelease(agent, nil, &release.Release{Name: "web", Namespace: "default"}, staleRelease, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// the time since the release was scaled down is kept, and its traffic is unknown
+	if staleRelease.ScaledDownSince == nil || !staleRelease.ScaledDownSince.Equal(scaledDownSince) {
+		t.Errorf("expected the release to be scaled down since %s, got %v", scaledDownSince, staleRelease.ScaledDownSince)
+	}
+
+	if staleRelease.TrafficChecked || staleRelease.NoTraffic {
+		t.Errorf("expected the traffic of the release to be unknown")
+	}
+}
+
+type testLeader struct {
+	isLeader bool
+	err      error
+}
+
+func (l *testLeader) IsLeader(ctx context.Context) (bool, error) {
+	return l.isLeader, l.err
+}
+
+func TestIsLeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		leader   *testLeader
+		expected bool
+	}{
+		{"leader", &testLeader{isLeader: true}, true},
+		{"other replica", &testLeader{isLeader: false}, false},
+		{"election error", &testLeader{err: errors.New("connection refused")}, false},
+	}
+
+	for _, tt := range tests {
+		d := &Detector{Logger: logger.NewConsole(false), Leader: tt.leader}
+
+		if res := d.isLeader(context.Background()); res != tt.expected {
+			t.Errorf("%s: expected leader to be %t, got %t", tt.name, tt.expected, res)
+		}
+	}
+
+	// releases are checked by every replica without a leader election
+	if d := (&Detector{}); !d.isLeader(context.Background()) {
+		t.Errorf("expected the releases to be checked without a leader election")
+	}
+}
@@ -0,0 +1,352 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/porter-dev/porter/api/types"
+)
+
+// FormDataToValues validates form data submitted for a form, and converts it to values
+// in the same way as the dashboard: defaults are applied to fields that were not set,
+// units are appended to inputs, and variables are expanded into nested values. Fields
+// in sections that are hidden by show_if are dropped, even if they were submitted, unless
+// the form includes hidden fields. Form data that does not belong to a field is passed
+// through.
+//
+// The form should be parsed with FormYAMLFromBytes, so that the values of the fields
+// are populated.
+func FormDataToValues(
+	form *types.FormYAML,
+	data map[string]interface{},
+) (map[string]interface{}, []*types.FormValidationError) {
+	vars := make(map[string]interface{})
+
+	for key, val := range data {
+		vars[key] = val
+	}
+
+	// variable fields are hidden, and set their default unless the variable was submitted
+	forEachField(form, func(section *types.FormSection, content *types.FormContent) {
+		if _, exists := vars[content.Variable]; content.Type == "variable" && !exists {
+			vars[content.Variable] = content.Settings.Default
+		}
+	})
+
+	final := make(map[string]interface{})
+
+	for key, val := range vars {
+		final[key] = val
+	}
+
+	errs := make([]*types.FormValidationError, 0)
+	includeHidden := form.IncludeHiddenFields == "true"
+
+	// a variable can be set by fields in more than one section, so it is only dropped if
+	// all of its fields are hidden
+	hidden := make(map[string]bool)
+	shown := make(map[string]bool)
+
+	forEachField(form, func(section *types.FormSection, content *types.FormContent) {
+		if content.Variable == "" || content.Type == "variable" {
+			return
+		}
+
+		if !includeHidden && section.ShowIf != nil && !evalShowIf(section.ShowIf, vars) {
+			hidden[content.Variable] = true
+			return
+		}
+
+		shown[content.Variable] = true
+
+		val, err := getFinalValue(content, vars[content.Variable])
+
+		if err == nil && content.Required && isEmptyValue(val) {
+			err = fmt.Errorf("%s is required", getFieldName(content))
+		}
+
+		if err != nil {
+			errs = append(errs, &types.FormValidationError{
+				Variable: content.Variable,
+				Error:    err.Error(),
+			})
+
+			return
+		}
+
+		if val != nil {
+			final[content.Variable] = val
+		}
+	})
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	for variable := range hidden {
+		if !shown[variable] {
+			delete(final, variable)
+		}
+	}
+
+	return expandVariables(final), nil
+}
+
+func forEachField(form *types.FormYAML, fn func(section *types.FormSection, content *types.FormContent)) {
+	for _, tab := range form.Tabs {
+		for _, section := range tab.Sections {
+			for _, content := range section.Contents {
+				if content != nil {
+					fn(section, content)
+				}
+			}
+		}
+	}
+}
+
+// getFinalValue returns the value of a field: the submitted value if it was set,
+// otherwise the value read into the form, otherwise the default of the field
+func getFinalValue(content *types.FormContent, submitted interface{}) (interface{}, error) {
+	val := submitted
+	fromForm := false
+
+	if val == nil {
+		val, fromForm = getFormValue(content)
+	}
+
+	switch content.Type {
+	case "input":
+		if val == nil {
+			val = content.Settings.Default
+		}
+
+		if val == nil {
+			return nil, nil
+		}
+
+		unit, _ := content.Settings.Unit.(string)
+
+		// values read into the form already contain the unit
+		if fromForm && unit != "" {
+			val = strings.TrimSuffix(fmt.Sprintf("%v", val), unit)
+		}
+
+		if content.Settings.Type == "number" {
+			num, err := toNumber(val)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a number", getFieldName(content))
+			}
+
+			val = num
+		}
+
+		if unit != "" && !content.Settings.OmitUnitFromValue {
+			val = fmt.Sprintf("%v%s", val, unit)
+		}
+
+		return val, nil
+	case "checkbox":
+		if val == nil {
+			return false, nil
+		}
+
+		if _, ok := val.(bool); !ok {
+			return nil, fmt.Errorf("%s must be true or false", getFieldName(content))
+		}
+
+		return val, nil
+	case "select":
+		options := getSelectOptions(content)
+
+		if val == nil && len(options) > 0 {
+			val = options[0]
+		}
+
+		if val == nil || len(options) == 0 {
+			return val, nil
+		}
+
+		for _, option := range options {
+			if fmt.Sprintf("%v", option) == fmt.Sprintf("%v", val) {
+				return val, nil
+			}
+		}
+
+		return nil, fmt.Errorf("%v is not a valid option for %s", val, getFieldName(content))
+	case "array-input":
+		if val == nil {
+			return nil, nil
+		}
+
+		if _, ok := val.([]interface{}); !ok {
+			return nil, fmt.Errorf("%s must be a list", getFieldName(content))
+		}
+
+		return val, nil
+	case "key-value-array":
+		if val == nil {
+			return nil, nil
+		}
+
+		if _, ok := val.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("%s must be a map", getFieldName(content))
+		}
+
+		return val, nil
+	}
+
+	return val, nil
+}
+
+// getFormValue returns the value that was read into the form for a field. The form
+// parser stores the value as a list of query results.
+func getFormValue(content *types.FormContent) (interface{}, bool) {
+	if results, ok := content.Value.([]interface{}); ok && len(results) > 0 && results[0] != nil {
+		return results[0], true
+	}
+
+	return nil, false
+}
+
+// getSelectOptions returns the values of the options of a normal select field
+func getSelectOptions(content *types.FormContent) []interface{} {
+	res := make([]interface{}, 0)
+
+	if content.Settings.Type == "provider" {
+		return res
+	}
+
+	options, _ := content.Settings.Options.([]interface{})
+
+	for _, option := range options {
+		if optionMap, ok := option.(map[string]interface{}); ok {
+			res = append(res, optionMap["value"])
+		}
+	}
+
+	return res
+}
+
+func getFieldName(content *types.FormContent) string {
+	if content.Label != "" {
+		return content.Label
+	}
+
+	return content.Variable
+}
+
+func isEmptyValue(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+
+	str, ok := val.(string)
+
+	return ok && str == ""
+}
+
+func toNumber(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case int, int32, int64, float32, float64:
+		return v, nil
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i, nil
+		}
+
+		return strconv.ParseFloat(v, 64)
+	}
+
+	return nil, fmt.Errorf("%v is not a number", val)
+}
+
+// evalShowIf evaluates the show_if condition of a section, which is either a variable
+// that must be truthy, or an "and", "or" or "not" of conditions
+func evalShowIf(showIf interface{}, vars map[string]interface{}) bool {
+	switch cond := showIf.(type) {
+	case string:
+		return isTruthy(vars[cond])
+	case map[string]interface{}:
+		if or, ok := cond["or"].([]interface{}); ok {
+			for _, subCond := range or {
+				if evalShowIf(subCond, vars) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if and, ok := cond["and"].([]interface{}); ok {
+			for _, subCond := range and {
+				if !evalShowIf(subCond, vars) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		if not, ok := cond["not"]; ok {
+			return !evalShowIf(not, vars)
+		}
+	}
+
+	return false
+}
+
+func isTruthy(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case int:
+		return v != 0
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	}
+
+	return true
+}
+
+// expandVariables expands variables of the form a.b.c into nested values
+func expandVariables(vars map[string]interface{}) map[string]interface{} {
+	keys := make([]string, 0, len(vars))
+
+	for key := range vars {
+		keys = append(keys, key)
+	}
+
+	// parents are set before their children, so that a child is not overwritten
+	sort.Strings(keys)
+
+	res := make(map[string]interface{})
+
+	for _, key := range keys {
+		path := strings.Split(key, ".")
+		curr := res
+
+		for _, part := range path[:len(path)-1] {
+			next, ok := curr[part].(map[string]interface{})
+
+			if !ok {
+				next = make(map[string]interface{})
+				curr[part] = next
+			}
+
+			curr = next
+		}
+
+		curr[path[len(path)-1]] = vars[key]
+	}
+
+	return res
+}
@@ -0,0 +1,168 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/templater/parser"
+)
+
+const testForm = `
+name: web
+tabs:
+- name: main
+  sections:
+  - name: container
+    contents:
+    - type: input
+      label: Port
+      variable: container.port
+      required: true
+      settings:
+        type: number
+        default: 80
+    - type: input
+      label: Memory
+      variable: resources.requests.memory
+      settings:
+        unit: Mi
+        default: 256
+    - type: select
+      label: Pull Policy
+      variable: image.pullPolicy
+      settings:
+        options:
+        - label: Always
+          value: Always
+        - label: If Not Present
+          value: IfNotPresent
+    - type: checkbox
+      label: Expose
+      variable: ingress.enabled
+    - type: variable
+      variable: showStartCommand
+      settings:
+        default: true
+  - name: ingress
+    show_if: ingress.enabled
+    contents:
+    - type: input
+      label: Domain
+      variable: ingress.host
+      required: true
+  - name: start
+    show_if:
+      and:
+      - showStartCommand
+      - not: ingress.enabled
+    contents:
+    - type: input
+      label: Start Command
+      variable: container.command
+`
+
+type formDataToValuesTest struct {
+	name      string
+	data      map[string]interface{}
+	expValues map[string]interface{}
+	expErrors []*types.FormValidationError
+}
+
+var formDataToValuesTests = []formDataToValuesTest{
+	{
+		name: "defaults are applied",
+		data: map[string]interface{}{},
+		expValues: map[string]interface{}{
+			"container": map[string]interface{}{
+				"port": float64(80),
+			},
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{
+					"memory": "256Mi",
+				},
+			},
+			"image": map[string]interface{}{
+				"pullPolicy": "Always",
+			},
+			"ingress": map[string]interface{}{
+				"enabled": false,
+			},
+			"showStartCommand": true,
+		},
+	},
+	{
+		name: "submitted values are converted",
+		data: map[string]interface{}{
+			"container.port":            "8080",
+			"resources.requests.memory": "512",
+			"image.pullPolicy":          "IfNotPresent",
+			"ingress.enabled":           true,
+			"ingress.host":              "example.com",
+			"container.command":         "hidden",
+		},
+		// the start command is hidden when the ingress is enabled, so it is dropped
+		expValues: map[string]interface{}{
+			"container": map[string]interface{}{
+				"port": int64(8080),
+			},
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{
+					"memory": "512Mi",
+				},
+			},
+			"image": map[string]interface{}{
+				"pullPolicy": "IfNotPresent",
+			},
+			"ingress": map[string]interface{}{
+				"enabled": true,
+				"host":    "example.com",
+			},
+			"showStartCommand": true,
+		},
+	},
+	{
+		name: "invalid values are rejected",
+		data: map[string]interface{}{
+			"container.port":   "http",
+			"image.pullPolicy": "Never",
+			"ingress.enabled":  true,
+		},
+		expErrors: []*types.FormValidationError{
+			{
+				Variable: "container.port",
+				Error:    "Port must be a number",
+			},
+			{
+				Variable: "image.pullPolicy",
+				Error:    "Never is not a valid option for Pull Policy",
+			},
+			{
+				Variable: "ingress.host",
+				Error:    "Domain is required",
+			},
+		},
+	},
+}
+
+func TestFormDataToValues(t *testing.T) {
+	for _, test := range formDataToValuesTests {
+		form, err := parser.FormYAMLFromBytes(&parser.ClientConfigDefault{}, []byte(testForm), "declared")
+
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		values, errs := parser.FormDataToValues(form, test.data)
+
+		if diff := deep.Equal(errs, test.expErrors); diff != nil {
+			t.Errorf("%s: incorrect errors", test.name)
+			t.Error(diff)
+		}
+
+		if diff := deep.Equal(values, test.expValues); diff != nil {
+			t.Errorf("%s: incorrect values", test.name)
+			t.Error(diff)
+		}
+	}
+}
@@ -22,6 +22,30 @@ func MergeYAML(base, override []byte) (map[string]interface{}, error) {
 	return CoalesceValues(baseVals, overrideVals), nil
 }
 
+// MergeValues merges override into base in the same way as CoalesceValues, except that
+// null values of override are kept, so that they still unset the values beneath them when
+// the merged values are coalesced with the values of a chart. Neither map is modified.
+func MergeValues(base, override map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(base)+len(override))
+
+	for key, val := range base {
+		res[key] = val
+	}
+
+	for key, oVal := range override {
+		oMapVal, oIsMap := oVal.(map[string]interface{})
+		bMapVal, bIsMap := res[key].(map[string]interface{})
+
+		if oIsMap && bIsMap {
+			res[key] = MergeValues(bMapVal, oMapVal)
+		} else {
+			res[key] = oVal
+		}
+	}
+
+	return res
+}
+
 // CoalesceValues replaces arrays and scalar values, merges maps
 func CoalesceValues(base, override map[string]interface{}) map[string]interface{} {
 	if base == nil && override != nil {
@@ -0,0 +1,62 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/porter-dev/porter/internal/templater/utils"
+)
+
+func TestMergeValuesKeepsNulls(t *testing.T) {
+	chart := map[string]interface{}{
+		"replicaCount": 1,
+		"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"memory": "256Mi", "cpu": "100m"},
+		},
+	}
+
+	defaults := map[string]interface{}{
+		"replicaCount": 2,
+		"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"memory": "512Mi", "cpu": "500m"},
+		},
+	}
+
+	override := map[string]interface{}{
+		"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"cpu": nil},
+		},
+	}
+
+	values := utils.MergeValues(utils.MergeValues(chart, defaults), override)
+
+	// merging the defaults again does not undo the null override
+	values = utils.MergeValues(defaults, values)
+
+	expected := map[string]interface{}{
+		"replicaCount": 2,
+		"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"memory": "512Mi", "cpu": nil},
+		},
+	}
+
+	if diff := deep.Equal(values, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	// the null override unsets the value of the chart when the values are coalesced
+	// with the chart
+	final := utils.CoalesceValues(chart, values)
+
+	limits := final["resources"].(map[string]interface{})["limits"].(map[string]interface{})
+
+	if _, ok := limits["cpu"]; ok {
+		t.Errorf("expected cpu limit to be unset, got %v", limits)
+	}
+
+	if diff := deep.Equal(defaults["resources"], map[string]interface{}{
+		"limits": map[string]interface{}{"memory": "512Mi", "cpu": "500m"},
+	}); diff != nil {
+		t.Errorf("expected defaults not to be modified: %v", diff)
+	}
+}
@@ -0,0 +1,293 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/kubernetes"
+	"github.com/porter-dev/porter/internal/kubernetes/nodes"
+	"github.com/porter-dev/porter/internal/models"
+	"github.com/porter-dev/porter/internal/repository"
+	"golang.org/x/oauth2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+)
+
+// inventoryIdleTTL is the time after which the inventory of a cluster that has not been
+// read is dropped
+const inventoryIdleTTL = time.Hour
+
+// InventoryCache caches the inventory of each cluster, so that the inventory endpoint and
+// the usage of a project do not each query all of the clusters of the project. The
+// inventory of a cluster is collected again once it is older than the TTL.
+//
+// If Redis is enabled, collected inventories are stored in Redis as well, so that each
+// cluster is collected once per TTL by all of the replicas of the server instead of once
+// by each replica.
+type InventoryCache struct {
+	ttl    time.Duration
+	repo   repository.Repository
+	doConf *oauth2.Config
+	redis  *redis.Client
+
+	// collect collects the inventory of a cluster, and is replaced in tests
+	collect func(cluster *models.Cluster) *types.ClusterInventory
+
+	mu      sync.Mutex
+	entries map[uint]*inventoryEntry
+}
+
+type inventoryEntry struct {
+	mu        sync.Mutex
+	inventory *types.ClusterInventory
+	lastUsed  time.Time
+}
+
+// NewInventoryCache returns a cache whose inventories expire after the given ttl. The
+// Redis client is used to share inventories with the other replicas, and may be nil if
+// Redis is not enabled.
+func NewInventoryCache(
+	ttl time.Duration,
+	repo repository.Repository,
+	doConf *oauth2.Config,
+	client *redis.Client,
+) *InventoryCache {
+	c := &InventoryCache{
+		ttl:     ttl,
+		repo:    repo,
+		doConf:  doConf,
+		redis:   client,
+		entries: make(map[uint]*inventoryEntry),
+	}
+
+	c.collect = func(cluster *models.Cluster) *types.ClusterInventory {
+		return CollectClusterInventory(c.repo, c.doConf, cluster)
+	}
+
+	return c
+}
+
+// GetProjectInventory returns the inventory of each cluster of a project, collecting
+// the inventories of the clusters whose cached inventory has expired in parallel
+func (c *InventoryCache) GetProjectInventory(clusters []*models.Cluster) []*types.ClusterInventory {
+	res := make([]*types.ClusterInventory, len(clusters))
+
+	var wg sync.WaitGroup
+
+	for i, cluster := range clusters {
+		wg.Add(1)
+
+		go func(i int, cluster *models.Cluster) {
+			defer wg.Done()
+
+			res[i] = c.GetClusterInventory(cluster)
+		}(i, cluster)
+	}
+
+	wg.Wait()
+
+	return res
+}
+
+// GetClusterInventory returns the cached inventory of a cluster, or collects the
+// inventory if it has expired
+func (c *InventoryCache) GetClusterInventory(cluster *models.Cluster) *types.ClusterInventory {
+	c.mu.Lock()
+	entry, ok := c.entries[cluster.ID]
+
+	if !ok {
+		entry = &inventoryEntry{}
+		c.entries[cluster.ID] = entry
+	}
+
+	entry.lastUsed = time.Now()
+	c.mu.Unlock()
+
+	// concurrent reads of an expired entry wait on a single collection
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if c.isExpired(entry.inventory) {
+		if shared := c.readShared(cluster.ID); !c.isExpired(shared) {
+			entry.inventory = shared
+		} else {
+			entry.inventory = c.collect(cluster)
+			c.writeShared(entry.inventory)
+		}
+	}
+
+	return entry.inventory
+}
+
+// Invalidate drops the cached inventory of a cluster, such as when the cluster is deleted
+func (c *InventoryCache) Invalidate(clusterID uint) {
+	c.mu.Lock()
+	delete(c.entries, clusterID)
+	c.mu.Unlock()
+
+	if c.redis != nil {
+		c.redis.Del(context.Background(), getInventoryKey(clusterID))
+	}
+}
+
+func (c *InventoryCache) isExpired(inventory *types.ClusterInventory) bool {
+	return inventory == nil || time.Since(inventory.CollectedAt) > c.ttl
+}
+
+func getInventoryKey(clusterID uint) string {
+	return fmt.Sprintf("cluster-inventory:%d", clusterID)
+}
+
+// readShared reads the inventory of a cluster that was collected by any replica, or
+// returns nil if Redis is not enabled or the inventory cannot be read
+func (c *InventoryCache) readShared(clusterID uint) *types.ClusterInventory {
+	if c.redis == nil {
+		return nil
+	}
+
+	data, err := c.redis.Get(context.Background(), getInventoryKey(clusterID)).Bytes()
+
+	if err != nil {
+		return nil
+	}
+
+	inventory := &types.ClusterInventory{}
+
+	if err := json.Unmarshal(data, inventory); err != nil {
+		return nil
+	}
+
+	return inventory
+}
+
+// writeShared stores a collected inventory for the other replicas. If the inventory
+// cannot be stored, the other replicas collect the inventory themselves.
+func (c *InventoryCache) writeShared(inventory *types.ClusterInventory) {
+	if c.redis == nil {
+		return
+	}
+
+	if data, err := json.Marshal(inventory); err == nil {
+		c.redis.Set(context.Background(), getInventoryKey(inventory.ClusterID), data, c.ttl)
+	}
+}
+
+// Run drops the inventories of clusters that have not been read recently at the given
+// interval until the context is cancelled
+func (c *InventoryCache) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+
+			for clusterID, entry := range c.entries {
+				if time.Since(entry.lastUsed) > inventoryIdleTTL {
+					delete(c.entries, clusterID)
+				}
+			}
+
+			c.mu.Unlock()
+		}
+	}
+}
+
+// CollectClusterInventory collects the inventory of a cluster. Errors are stored on the
+// inventory instead of being returned, so that an unreachable cluster does not prevent
+// the inventory of the other clusters of a project from being read.
+func CollectClusterInventory(
+	repo repository.Repository,
+	doConf *oauth2.Config,
+	cluster *models.Cluster,
+) *types.ClusterInventory {
+	res := &types.ClusterInventory{
+		ClusterID:   cluster.ID,
+		ClusterName: cluster.Name,
+		CollectedAt: time.Now(),
+	}
+
+	if dbs, err := repo.Database().ListDatabases(cluster.ProjectID, cluster.ID); err == nil {
+		res.Databases = uint(len(dbs))
+	}
+
+	agent, err := kubernetes.GetAgentOutOfClusterConfig(&kubernetes.OutOfClusterConfig{
+		Cluster:           cluster,
+		Repo:              repo,
+		DigitalOceanOAuth: doConf,
+	})
+
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	restConf, err := agent.RESTClientGetter.ToRESTConfig()
+
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	metadataClient, err := metadata.NewForConfig(restConf)
+
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	if err := collectResources(agent.Clientset, metadataClient, res); err != nil {
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+func collectResources(clientset k8s.Interface, metadataClient metadata.Interface, res *types.ClusterInventory) error {
+	alloc, err := nodes.GetAllocatableResources(clientset)
+
+	if err != nil {
+		return err
+	}
+
+	res.Nodes = alloc.Nodes
+	res.CPUAllocatable = alloc.CPU
+	res.MemoryAllocatable = alloc.Memory
+
+	requested, err := nodes.GetRequestedResources(clientset)
+
+	if err != nil {
+		return err
+	}
+
+	res.CPURequested = requested.CPU
+	res.MemoryRequested = requested.Memory
+
+	// helm stores the deployed revision of each release as a secret with the deployed
+	// status, so there is one such secret per release. Only the metadata of the secrets
+	// is listed, since their data is the whole manifest of the release.
+	releaseSecrets, err := metadataClient.Resource(v1.SchemeGroupVersion.WithResource("secrets")).
+		Namespace("").
+		List(context.TODO(), metav1.ListOptions{
+			LabelSelector: "owner=helm,status=deployed",
+		})
+
+	if err != nil {
+		return err
+	}
+
+	res.Releases = uint(len(releaseSecrets.Items))
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package usage
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/porter-dev/porter/api/types"
+	"github.com/porter-dev/porter/internal/models"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+)
+
+func getInventoryTestSecret(namespace, name string, labels map[string]string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+}
+
+func TestCollectResources(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: v1.NodeStatus{
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("2"),
+					v1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: v1.PodSpec{
+				NodeName: "node-1",
+				Containers: []v1.Container{
+					{
+						Name: "web",
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{
+								v1.ResourceCPU:    resource.MustParse("500m"),
+								v1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		},
+	)
+
+	scheme := runtime.NewScheme()
+	metav1.AddMetaToScheme(scheme)
+
+	// only the deployed revision of each release is counted
+	metadataClient := metadatafake.NewSimpleMetadataClient(
+		scheme,
+		getInventoryTestSecret("default", "sh.helm.release.v1.web.v1", map[string]string{"owner": "helm", "status": "superseded"}),
+		getInventoryTestSecret("default", "sh.helm.release.v1.web.v2", map[string]string{"owner": "helm", "status": "deployed"}),
+		getInventoryTestSecret("jobs", "sh.helm.release.v1.cron.v1", map[string]string{"owner": "helm", "status": "deployed"}),
+		getInventoryTestSecret("default", "web-env", map[string]string{"status": "deployed"}),
+	)
+
+	res := &types.ClusterInventory{}
+
+	if err := collectResources(clientset, metadataClient, res); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if res.Releases != 2 {
+		t.Errorf("expected 2 releases, got %d", res.Releases)
+	}
+
+	if res.Nodes != 1 || res.CPUAllocatable != 2000 || res.MemoryAllocatable != 4*1024*1024*1024 {
+		t.Errorf("expected the allocatable resources of the node, got %+v", res)
+	}
+
+	if res.CPURequested != 500 || res.MemoryRequested != 1024*1024*1024 {
+		t.Errorf("expected the requested resources of the pod, got %+v", res)
+	}
+}
+
+// newTestInventoryCache returns a cache without Redis, along with the number of times
+// that the inventories of its clusters were collected
+func newTestInventoryCache(ttl time.Duration) (*InventoryCache, func() int) {
+	var mu sync.Mutex
+	collected := 0
+
+	cache := NewInventoryCache(ttl, nil, nil, nil)
+
+	cache.collect = func(cluster *models.Cluster) *types.ClusterInventory {
+		mu.Lock()
+		defer mu.Unlock()
+
+		collected++
+
+		return &types.ClusterInventory{
+			ClusterID:   cluster.ID,
+			CollectedAt: time.Now(),
+		}
+	}
+
+	return cache, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return collected
+	}
+}
+
+func TestInventoryIsCached(t *testing.T) {
+	cache, collected := newTestInventoryCache(time.Hour)
+
+	clusters := []*models.Cluster{{}, {}}
+	clusters[0].ID = 1
+	clusters[1].ID = 2
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			inventories := cache.GetProjectInventory(clusters)
+
+			if len(inventories) != 2 || inventories[0].ClusterID != 1 || inventories[1].ClusterID != 2 {
+				t.Errorf("expected the inventory of each cluster, got %v", inventories)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if collected() != 2 {
+		t.Errorf("expected each cluster to be collected once, got %d collections", collected())
+	}
+
+	cache.Invalidate(1)
+	cache.GetClusterInventory(clusters[0])
+	cache.GetClusterInventory(clusters[1])
+
+	if collected() != 3 {
+		t.Errorf("expected only the invalidated cluster to be collected again, got %d collections", collected())
+	}
+}
+
+func TestInventoryExpires(t *testing.T) {
+	cache, collected := newTestInventoryCache(time.Nanosecond)
+
+	cluster := &models.Cluster{}
+	cluster.ID = 1
+
+	cache.GetClusterInventory(cluster)
+	time.Sleep(time.Millisecond)
+	cache.GetClusterInventory(cluster)
+
+	if collected() != 2 {
+		t.Errorf("expected the expired inventory to be collected again, got %d collections", collected())
+	}
+}
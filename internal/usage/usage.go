@@ -18,6 +18,10 @@ type GetUsageOpts struct {
 	DOConf           *oauth2.Config
 	Project          *models.Project
 	WhitelistedUsers map[uint]uint
+
+	// Inventory is the cache of cluster inventories that CPU and memory are read from. If
+	// it is nil, the clusters are queried directly.
+	Inventory *InventoryCache
 }
 
 // GetUsage gets a project's current usage and usage limit
@@ -134,6 +138,15 @@ func isUsageChanged(oldUsageCache, currUsageCache *models.ProjectUsageCache) boo
 func getResourceUsage(opts *GetUsageOpts, clusters []*models.Cluster) (uint, uint, error) {
 	var totCPU, totMem uint = 0, 0
 
+	if opts.Inventory != nil {
+		for _, inventory := range opts.Inventory.GetProjectInventory(clusters) {
+			totCPU += inventory.CPUAllocatable
+			totMem += inventory.MemoryAllocatable
+		}
+
+		return totCPU / 1000, totMem / (1000 * 1000), nil
+	}
+
 	for _, cluster := range clusters {
 		ooc := &kubernetes.OutOfClusterConfig{
 			Cluster:           cluster,
@@ -0,0 +1,109 @@
+package usage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/porter-dev/porter/internal/repository"
+)
+
+// WhitelistedUsers is the set of users that do not count toward usage limits. Users
+// are whitelisted either through the environment, or at runtime by instance admins.
+// The users whitelisted at runtime are reloaded from the database when the set is older
+// than the TTL, so that changes made on one replica of the server reach every replica.
+type WhitelistedUsers struct {
+	repo repository.InstanceAdminRepository
+	ttl  time.Duration
+
+	mu       sync.RWMutex
+	loadedAt time.Time
+
+	envUsers map[uint]uint
+	users    map[uint]uint
+}
+
+func NewWhitelistedUsers(
+	envUserIDs []uint,
+	repo repository.InstanceAdminRepository,
+	ttl time.Duration,
+) *WhitelistedUsers {
+	envUsers := make(map[uint]uint)
+
+	for _, userID := range envUserIDs {
+		envUsers[userID] = userID
+	}
+
+	return &WhitelistedUsers{
+		repo:     repo,
+		ttl:      ttl,
+		envUsers: envUsers,
+		users:    envUsers,
+	}
+}
+
+// Map returns the whitelisted users. The map is replaced rather than modified when the
+// whitelist changes, so it is safe to read after the call.
+func (w *WhitelistedUsers) Map() map[uint]uint {
+	w.refreshIfStale()
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.users
+}
+
+// IsFromEnv returns true if the user was whitelisted through the environment, and can
+// therefore not be removed at runtime
+func (w *WhitelistedUsers) IsFromEnv(userID uint) bool {
+	_, exists := w.envUsers[userID]
+	return exists
+}
+
+// Reload sets the whitelist to the users from the environment and the users that were
+// whitelisted by instance admins
+func (w *WhitelistedUsers) Reload() error {
+	if w.repo == nil {
+		return nil
+	}
+
+	whitelisted, err := w.repo.ListWhitelistedUsers()
+
+	if err != nil {
+		return err
+	}
+
+	users := make(map[uint]uint)
+
+	for userID := range w.envUsers {
+		users[userID] = userID
+	}
+
+	for _, wl := range whitelisted {
+		users[wl.UserID] = wl.UserID
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.users = users
+	w.loadedAt = time.Now()
+
+	return nil
+}
+
+// refreshIfStale reloads the whitelist if it is older than the TTL. The whitelist is kept
+// if it cannot be reloaded, and the reload is tried again after the TTL.
+func (w *WhitelistedUsers) refreshIfStale() {
+	w.mu.Lock()
+
+	if w.repo == nil || w.ttl <= 0 || time.Since(w.loadedAt) < w.ttl {
+		w.mu.Unlock()
+		return
+	}
+
+	w.loadedAt = time.Now()
+
+	w.mu.Unlock()
+
+	w.Reload()
+}